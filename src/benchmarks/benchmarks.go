@@ -0,0 +1,151 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+// Package benchmarks measures the performance of the VM on a suite of standard Java
+// microbenchmarks, so that changes to the interpreter can be checked for regressions.
+// The workloads are in testdata/benchmarks: each is a class that runs the number of
+// operations given as its argument. The Go benchmarks in benchmarks_test.go run them
+// with the Jacobin executable given in JACOBIN_EXE:
+//
+//	JACOBIN_EXE=/path/to/jacobin go test ./src/benchmarks -bench .
+//
+// The time per operation excludes the VM's startup, which is measured by running the
+// workload with no operations. To track the results over time, set JACOBIN_BENCH_HISTORY
+// to a file, to which each run's results are appended. The run fails if a workload is
+// slower than the median of its recent results by more than JACOBIN_BENCH_TOLERANCE
+// percent (10 by default), which makes the suite usable as a gate on interpreter changes.
+package benchmarks
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Workloads are the names of the classes of the suite
+var Workloads = []string{
+	"Fibonacci",      // recursive invocation and integer arithmetic
+	"Sieve",          // array access and loops
+	"StringBuilding", // StringBuilder appends and allocation
+	"MapChurn",       // HashMap operations, boxing, and allocation
+	"MethodDispatch", // interface method dispatch
+}
+
+// Run runs a workload in dir for the given number of operations with the JVM command
+// jvm (the executable and any options that precede the classpath), and returns the
+// time it took, including the VM's startup
+func Run(jvm []string, dir, workload string, ops int) (time.Duration, error) {
+	args := append(append(append([]string{}, jvm[1:]...), "-cp", dir), workload, strconv.Itoa(ops))
+	cmd := exec.Command(jvm[0], args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	if err := cmd.Run(); err != nil {
+		return 0, fmt.Errorf("%s failed: %v\n%s", workload, err, stderr.String())
+	}
+	return time.Since(start), nil
+}
+
+// Measure returns the time per operation of a workload run for the given number of
+// operations, less the time the VM takes to start up and run it for none
+func Measure(jvm []string, dir, workload string, ops int) (time.Duration, error) {
+	startup, err := Run(jvm, dir, workload, 0)
+	if err != nil {
+		return 0, err
+	}
+	elapsed, err := Run(jvm, dir, workload, ops)
+	if err != nil {
+		return 0, err
+	}
+	return max(elapsed-startup, 0) / time.Duration(ops), nil
+}
+
+// Record is the result of one workload in one run of the suite
+type Record struct {
+	Time     time.Time
+	Workload string
+	NsPerOp  float64
+}
+
+// AppendHistory appends the records to the history file at path, creating it if need
+// be. Each record is a line: the time (RFC 3339), the workload, and the ns/op.
+func AppendHistory(path string, records []Record) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot open the benchmark history %s: %w", path, err)
+	}
+	defer file.Close()
+	for _, r := range records {
+		if _, err = fmt.Fprintf(file, "%s %s %.1f\n", r.Time.UTC().Format(time.RFC3339), r.Workload, r.NsPerOp); err != nil {
+			return fmt.Errorf("cannot write the benchmark history %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// ReadHistory reads the records in the history file at path, in the order they were
+// written. A missing file is an empty history.
+func ReadHistory(path string) ([]Record, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot open the benchmark history %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid entry at line %d of the benchmark history %s", line, path)
+		}
+		when, err := time.Parse(time.RFC3339, fields[0])
+		nsPerOp, err2 := strconv.ParseFloat(fields[2], 64)
+		if err != nil || err2 != nil {
+			return nil, fmt.Errorf("invalid entry at line %d of the benchmark history %s", line, path)
+		}
+		records = append(records, Record{Time: when, Workload: fields[1], NsPerOp: nsPerOp})
+	}
+	return records, scanner.Err()
+}
+
+// the number of recent results of a workload whose median a new result is compared to
+const regressionWindow = 5
+
+// Regressions compares the current results with the history, and describes each
+// workload that is slower than the median of its last few results by more than
+// tolerance percent. Workloads with no history are not regressions.
+func Regressions(history, current []Record, tolerance float64) []string {
+	var regressions []string
+	for _, c := range current {
+		var recent []float64
+		for _, h := range history {
+			if h.Workload == c.Workload {
+				recent = append(recent, h.NsPerOp)
+			}
+		}
+		if len(recent) == 0 {
+			continue
+		}
+		recent = recent[max(len(recent)-regressionWindow, 0):]
+		sort.Float64s(recent)
+		median := recent[len(recent)/2]
+		if c.NsPerOp > median*(1+tolerance/100) {
+			regressions = append(regressions, fmt.Sprintf("%s: %.1f ns/op is %.1f%% slower than the recent median of %.1f ns/op",
+				c.Workload, c.NsPerOp, (c.NsPerOp/median-1)*100, median))
+		}
+	}
+	return regressions
+}
@@ -0,0 +1,149 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package benchmarks
+
+import (
+	"fmt"
+	"jacobin/src/selftest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// the results of the benchmarks in this run, by workload. A benchmark function is
+// called with increasing b.N, so the last result, for the largest b.N, is kept.
+var results sync.Map
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if path := os.Getenv("JACOBIN_BENCH_HISTORY"); path != "" && code == 0 {
+		code = recordResults(path)
+	}
+	os.Exit(code)
+}
+
+// checks the results of this run against the history and appends them to it. Returns
+// the exit code of the run: 1 if there are regressions or the history can't be used.
+func recordResults(path string) int {
+	var current []Record
+	for _, workload := range Workloads {
+		if nsPerOp, ok := results.Load(workload); ok {
+			current = append(current, Record{Time: time.Now(), Workload: workload, NsPerOp: nsPerOp.(float64)})
+		}
+	}
+	if len(current) == 0 {
+		return 0
+	}
+
+	history, err := ReadHistory(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	tolerance := 10.0
+	if value := os.Getenv("JACOBIN_BENCH_TOLERANCE"); value != "" {
+		if tolerance, err = strconv.ParseFloat(value, 64); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid JACOBIN_BENCH_TOLERANCE: %s\n", value)
+			return 1
+		}
+	}
+	regressions := Regressions(history, current, tolerance)
+	if err = AppendHistory(path, current); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(regressions) > 0 {
+		fmt.Fprintln(os.Stderr, "performance regressions:\n  "+strings.Join(regressions, "\n  "))
+		return 1
+	}
+	return 0
+}
+
+// runs a workload for b.N operations with the Jacobin executable in JACOBIN_EXE and
+// reports its time per operation, without the VM's startup
+func benchmarkWorkload(b *testing.B, workload string) {
+	jacobin := os.Getenv("JACOBIN_EXE")
+	if jacobin == "" {
+		b.Skip("the benchmarks require the Jacobin executable to be specified in JACOBIN_EXE")
+	}
+	dir := filepath.Join("..", "..", "testdata", "benchmarks")
+	if err := selftest.CompileIfNeeded(dir, workload); err != nil {
+		b.Skip(err.Error())
+	}
+
+	perOp, err := Measure([]string{jacobin}, dir, workload, b.N)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportMetric(float64(perOp.Nanoseconds()), "ns/op")
+	results.Store(workload, float64(perOp.Nanoseconds()))
+}
+
+func BenchmarkFibonacci(b *testing.B)      { benchmarkWorkload(b, "Fibonacci") }
+func BenchmarkSieve(b *testing.B)          { benchmarkWorkload(b, "Sieve") }
+func BenchmarkStringBuilding(b *testing.B) { benchmarkWorkload(b, "StringBuilding") }
+func BenchmarkMapChurn(b *testing.B)       { benchmarkWorkload(b, "MapChurn") }
+func BenchmarkMethodDispatch(b *testing.B) { benchmarkWorkload(b, "MethodDispatch") }
+
+func TestWorkloadsHaveSources(t *testing.T) {
+	for _, workload := range Workloads {
+		source := filepath.Join("..", "..", "testdata", "benchmarks", workload+".java")
+		if _, err := os.Stat(source); err != nil {
+			t.Errorf("Expected the source of the %s workload at %s", workload, source)
+		}
+	}
+}
+
+func TestHistoryRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	when := time.Date(2025, 10, 16, 12, 0, 0, 0, time.UTC)
+	written := []Record{{when, "Fibonacci", 1234.5}, {when, "Sieve", 99}}
+	if err := AppendHistory(path, written[:1]); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := AppendHistory(path, written[1:]); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	read, err := ReadHistory(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(read) != 2 || read[0] != written[0] || read[1] != written[1] {
+		t.Errorf("Expected the records written, %v, got: %v", written, read)
+	}
+}
+
+func TestReadMissingHistory(t *testing.T) {
+	records, err := ReadHistory(filepath.Join(t.TempDir(), "none"))
+	if err != nil || len(records) != 0 {
+		t.Errorf("Expected a missing history to be empty, got: %v, %v", records, err)
+	}
+}
+
+func TestRegressions(t *testing.T) {
+	var history []Record
+	for _, nsPerOp := range []float64{5000, 100, 100, 110, 90, 105} { // the first is outside the window
+		history = append(history, Record{Workload: "Sieve", NsPerOp: nsPerOp})
+	}
+	current := []Record{
+		{Workload: "Sieve", NsPerOp: 120},     // 14% slower than the median of 105
+		{Workload: "Fibonacci", NsPerOp: 1e6}, // no history
+	}
+
+	regressions := Regressions(history, current, 10)
+	if len(regressions) != 1 || !strings.HasPrefix(regressions[0], "Sieve: 120.0 ns/op") {
+		t.Errorf("Expected a regression of Sieve only, got: %v", regressions)
+	}
+	if regressions = Regressions(history, current, 20); len(regressions) != 0 {
+		t.Errorf("Expected no regressions within a tolerance of 20%%, got: %v", regressions)
+	}
+}
@@ -0,0 +1,346 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+// Package charset converts between the characters of Java strings and the bytes of the
+// charsets that Jacobin supports: UTF-8, UTF-16 (big-endian with a byte-order mark, and
+// the explicit UTF-16BE and UTF-16LE), ISO-8859-1, US-ASCII, and windows-1252.
+//
+// The default charset, which the methods of String and the readers and writers use when
+// no charset is specified, is selected at startup from the OS and the locale (the
+// LC_ALL, LC_CTYPE, and LANG environment variables) and can be overridden with
+// -Dfile.encoding. Default() returns it.
+//
+// As in the JDK, bytes that aren't a valid sequence in the charset are decoded as the
+// replacement character, U+FFFD, and characters that the charset can't represent are
+// encoded as '?'.
+package charset
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Charset is a charset: its canonical name, as Charset.name() returns it, and the
+// functions that encode and decode one character
+type Charset struct {
+	Name    string
+	aliases []string
+	// appends the bytes of the rune to dst, or '?' if the charset can't represent it
+	encodeRune func(dst []byte, r rune) []byte
+	// decodes the first rune of a non-empty slice holding at least one full rune,
+	// returning utf8.RuneError and 1 for an invalid sequence
+	decodeRune func(b []byte) (rune, int)
+	// reports whether the slice begins with a full rune, and so can be decoded
+	fullRune func(b []byte) bool
+	bom      bool // is a byte-order mark written and, when decoding, honored?
+}
+
+const replacementByte = '?'
+
+var UTF8 = &Charset{
+	Name:    "UTF-8",
+	aliases: []string{"UTF8", "unicode-1-1-utf-8"},
+	encodeRune: func(dst []byte, r rune) []byte {
+		if utf16.IsSurrogate(r) || !utf8.ValidRune(r) {
+			return append(dst, replacementByte)
+		}
+		return utf8.AppendRune(dst, r)
+	},
+	decodeRune: utf8.DecodeRune,
+	fullRune:   utf8.FullRune,
+}
+
+var ISO88591 = &Charset{
+	Name:    "ISO-8859-1",
+	aliases: []string{"ISO8859_1", "ISO8859-1", "ISO_8859_1", "ISO_8859-1", "latin1", "l1", "8859_1", "cp819", "IBM819"},
+	encodeRune: func(dst []byte, r rune) []byte {
+		if r > 0xFF || r < 0 {
+			return append(dst, replacementByte)
+		}
+		return append(dst, byte(r))
+	},
+	decodeRune: func(b []byte) (rune, int) { return rune(b[0]), 1 },
+	fullRune:   singleByte,
+}
+
+var USASCII = &Charset{
+	Name:    "US-ASCII",
+	aliases: []string{"ASCII", "ANSI_X3.4-1968", "ISO646-US", "646", "cp367", "IBM367", "default"},
+	encodeRune: func(dst []byte, r rune) []byte {
+		if r >= utf8.RuneSelf || r < 0 {
+			return append(dst, replacementByte)
+		}
+		return append(dst, byte(r))
+	},
+	decodeRune: func(b []byte) (rune, int) {
+		if b[0] >= utf8.RuneSelf {
+			return utf8.RuneError, 1
+		}
+		return rune(b[0]), 1
+	},
+	fullRune: singleByte,
+}
+
+// the characters of bytes 0x80 through 0x9F in windows-1252; the other bytes are the
+// characters of ISO-8859-1. The five bytes that are undefined decode as U+FFFD.
+var windows1252High = [32]rune{
+	0x20AC, 0xFFFD, 0x201A, 0x0192, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x02C6, 0x2030, 0x0160, 0x2039, 0x0152, 0xFFFD, 0x017D, 0xFFFD,
+	0xFFFD, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0x02DC, 0x2122, 0x0161, 0x203A, 0x0153, 0xFFFD, 0x017E, 0x0178,
+}
+
+var Windows1252 = &Charset{
+	Name:    "windows-1252",
+	aliases: []string{"cp1252", "cp5348"},
+	encodeRune: func(dst []byte, r rune) []byte {
+		if (r >= 0 && r < 0x80) || (r >= 0xA0 && r <= 0xFF) {
+			return append(dst, byte(r))
+		}
+		for i, c := range windows1252High {
+			if c == r && c != utf8.RuneError {
+				return append(dst, byte(0x80+i))
+			}
+		}
+		return append(dst, replacementByte)
+	},
+	decodeRune: func(b []byte) (rune, int) {
+		if b[0] >= 0x80 && b[0] < 0xA0 {
+			return windows1252High[b[0]-0x80], 1
+		}
+		return rune(b[0]), 1
+	},
+	fullRune: singleByte,
+}
+
+var UTF16BE = utf16Charset("UTF-16BE", []string{"UnicodeBigUnmarked", "X-UTF-16BE", "ISO-10646-UCS-2"}, true, false)
+var UTF16LE = utf16Charset("UTF-16LE", []string{"UnicodeLittleUnmarked", "X-UTF-16LE"}, false, false)
+var UTF16 = utf16Charset("UTF-16", []string{"UTF_16", "utf16", "unicode", "UnicodeBig"}, true, true)
+
+var charsets = []*Charset{UTF8, ISO88591, USASCII, Windows1252, UTF16, UTF16BE, UTF16LE}
+
+func singleByte(b []byte) bool { return len(b) > 0 }
+
+// returns a UTF-16 charset of the byte order given
+func utf16Charset(name string, aliases []string, bigEndian, bom bool) *Charset {
+	unit := func(b []byte) rune {
+		if bigEndian {
+			return rune(b[0])<<8 | rune(b[1])
+		}
+		return rune(b[1])<<8 | rune(b[0])
+	}
+	appendUnit := func(dst []byte, u rune) []byte {
+		if bigEndian {
+			return append(dst, byte(u>>8), byte(u))
+		}
+		return append(dst, byte(u), byte(u>>8))
+	}
+	return &Charset{
+		Name:    name,
+		aliases: aliases,
+		bom:     bom,
+		encodeRune: func(dst []byte, r rune) []byte {
+			switch {
+			case utf16.IsSurrogate(r) || !utf8.ValidRune(r):
+				return appendUnit(dst, replacementByte)
+			case r >= 0x10000:
+				high, low := utf16.EncodeRune(r)
+				return appendUnit(appendUnit(dst, high), low)
+			default:
+				return appendUnit(dst, r)
+			}
+		},
+		decodeRune: func(b []byte) (rune, int) {
+			if len(b) < 2 {
+				return utf8.RuneError, len(b)
+			}
+			high := unit(b)
+			if !utf16.IsSurrogate(high) {
+				return high, 2
+			}
+			if high >= 0xDC00 || len(b) < 4 { // a low surrogate alone, or a truncated pair
+				return utf8.RuneError, 2
+			}
+			if r := utf16.DecodeRune(high, unit(b[2:])); r != utf8.RuneError {
+				return r, 4
+			}
+			return utf8.RuneError, 2
+		},
+		fullRune: func(b []byte) bool {
+			if len(b) < 2 {
+				return false
+			}
+			high := unit(b)
+			return high < 0xD800 || high >= 0xDC00 || len(b) >= 4
+		},
+	}
+}
+
+// Lookup returns the charset with the name or alias passed, which is not case-sensitive,
+// or nil if Jacobin doesn't support it
+func Lookup(name string) *Charset {
+	for _, cs := range charsets {
+		if strings.EqualFold(cs.Name, name) {
+			return cs
+		}
+		for _, alias := range cs.aliases {
+			if strings.EqualFold(alias, name) {
+				return cs
+			}
+		}
+	}
+	return nil
+}
+
+var defaultCharset atomic.Pointer[Charset]
+
+// Default returns the default charset, which is UTF-8 until it's set at startup
+func Default() *Charset {
+	if cs := defaultCharset.Load(); cs != nil {
+		return cs
+	}
+	return UTF8
+}
+
+// SetDefault makes the charset passed the default charset
+func SetDefault(cs *Charset) {
+	defaultCharset.Store(cs)
+}
+
+// Native returns the charset of the OS and locale, which is reported as the system
+// property native.encoding: windows-1252 on Windows and otherwise the codeset of the
+// locale, such as UTF-8 for en_US.UTF-8, or US-ASCII for the C and POSIX locales. If no
+// locale is set, or its codeset isn't supported, it's UTF-8.
+func Native() *Charset {
+	if runtime.GOOS == "windows" {
+		return Windows1252
+	}
+	for _, envVar := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		locale := os.Getenv(envVar)
+		if locale == "" {
+			continue
+		}
+		if locale == "C" || locale == "POSIX" {
+			return USASCII
+		}
+		_, codeset, found := strings.Cut(locale, ".")
+		codeset, _, _ = strings.Cut(codeset, "@") // such as de_DE.ISO-8859-1@euro
+		if cs := Lookup(codeset); found && cs != nil {
+			return cs
+		}
+		return UTF8
+	}
+	return UTF8
+}
+
+// Encode returns the bytes of a Go string in the charset
+func (cs *Charset) Encode(s string) []byte {
+	b := make([]byte, 0, len(s))
+	if cs.bom && len(s) > 0 {
+		b = cs.encodeRune(b, 0xFEFF)
+	}
+	for _, r := range s {
+		b = cs.encodeRune(b, r)
+	}
+	return b
+}
+
+// Decode returns the Go string of the bytes of the charset passed
+func (cs *Charset) Decode(b []byte) string {
+	d := cs.NewDecoder()
+	return string(utf16.Decode(append(d.Decode(b), d.Flush()...)))
+}
+
+// Decoder decodes bytes that arrive in pieces, as from a stream, into the UTF-16 code
+// units of Java chars. A character whose bytes are split between pieces is decoded when
+// its last byte arrives.
+type Decoder struct {
+	cs      *Charset
+	pending []byte
+	started bool // has the byte-order mark, if any, been looked for?
+}
+
+func (cs *Charset) NewDecoder() *Decoder {
+	return &Decoder{cs: cs}
+}
+
+// Decode returns the chars of the bytes passed and of those left over from the previous
+// call, keeping the bytes of an incomplete character for the next call
+func (d *Decoder) Decode(b []byte) []uint16 {
+	d.pending = append(d.pending, b...)
+	if d.cs.bom && !d.started {
+		if len(d.pending) < 2 {
+			return nil
+		}
+		d.started = true
+		switch {
+		case d.pending[0] == 0xFE && d.pending[1] == 0xFF:
+			d.pending = d.pending[2:]
+		case d.pending[0] == 0xFF && d.pending[1] == 0xFE:
+			d.cs = UTF16LE
+			d.pending = d.pending[2:]
+		}
+	}
+
+	var chars []uint16
+	for len(d.pending) > 0 && d.cs.fullRune(d.pending) {
+		r, size := d.cs.decodeRune(d.pending)
+		chars = utf16.AppendRune(chars, r)
+		d.pending = d.pending[size:]
+	}
+	return chars
+}
+
+// Flush returns the replacement character if the bytes ended within a character
+func (d *Decoder) Flush() []uint16 {
+	if len(d.pending) == 0 {
+		return nil
+	}
+	d.pending = nil
+	return []uint16{utf8.RuneError}
+}
+
+// Encoder encodes the UTF-16 code units of Java chars, which may arrive in pieces, into
+// bytes. A surrogate pair split between pieces is encoded when its low surrogate arrives.
+type Encoder struct {
+	cs      *Charset
+	high    uint16 // a high surrogate awaiting its low surrogate, or zero
+	started bool   // has the byte-order mark, if any, been written?
+}
+
+func (cs *Charset) NewEncoder() *Encoder {
+	return &Encoder{cs: cs}
+}
+
+// Encode returns the bytes of the chars passed
+func (e *Encoder) Encode(chars []uint16) []byte {
+	var b []byte
+	if e.cs.bom && !e.started && len(chars) > 0 {
+		b = e.cs.encodeRune(b, 0xFEFF)
+	}
+	e.started = true
+	for _, c := range chars {
+		switch {
+		case e.high != 0 && c >= 0xDC00 && c < 0xE000:
+			b = e.cs.encodeRune(b, utf16.DecodeRune(rune(e.high), rune(c)))
+			e.high = 0
+			continue
+		case e.high != 0: // the high surrogate isn't followed by a low one
+			b = e.cs.encodeRune(b, rune(e.high))
+			e.high = 0
+		}
+		if c >= 0xD800 && c < 0xDC00 {
+			e.high = c
+			continue
+		}
+		b = e.cs.encodeRune(b, rune(c))
+	}
+	return b
+}
@@ -0,0 +1,143 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package charset
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestLookup(t *testing.T) {
+	tests := map[string]*Charset{
+		"UTF-8": UTF8, "utf8": UTF8, "latin1": ISO88591, "ISO8859_1": ISO88591,
+		"ascii": USASCII, "Cp1252": Windows1252, "UTF-16": UTF16, "utf-16le": UTF16LE,
+	}
+	for name, expected := range tests {
+		if cs := Lookup(name); cs != expected {
+			t.Errorf("Lookup(%q): expected %s, got %v", name, expected.Name, cs)
+		}
+	}
+	if cs := Lookup("EBCDIC-XYZ"); cs != nil {
+		t.Errorf("Expected an unsupported charset to be nil, got %s", cs.Name)
+	}
+}
+
+func TestEncodeAndDecode(t *testing.T) {
+	tests := []struct {
+		cs      *Charset
+		str     string
+		encoded []byte
+	}{
+		{UTF8, "hé€", []byte{'h', 0xC3, 0xA9, 0xE2, 0x82, 0xAC}},
+		{ISO88591, "hé", []byte{'h', 0xE9}},
+		{USASCII, "hi", []byte{'h', 'i'}},
+		{Windows1252, "€é", []byte{0x80, 0xE9}},
+		{UTF16BE, "h\U0001F600", []byte{0, 'h', 0xD8, 0x3D, 0xDE, 0x00}},
+		{UTF16LE, "h", []byte{'h', 0}},
+		{UTF16, "h", []byte{0xFE, 0xFF, 0, 'h'}},
+	}
+	for _, test := range tests {
+		if encoded := test.cs.Encode(test.str); !bytes.Equal(encoded, test.encoded) {
+			t.Errorf("%s: expected %q to encode as % X, got % X", test.cs.Name, test.str, test.encoded, encoded)
+		}
+		if decoded := test.cs.Decode(test.encoded); decoded != test.str {
+			t.Errorf("%s: expected % X to decode as %q, got %q", test.cs.Name, test.encoded, test.str, decoded)
+		}
+	}
+}
+
+func TestUnmappableAndMalformed(t *testing.T) {
+	if encoded := ISO88591.Encode("a€b"); string(encoded) != "a?b" {
+		t.Errorf("Expected an unmappable character to be encoded as '?', got %q", encoded)
+	}
+	if encoded := USASCII.Encode("é"); string(encoded) != "?" {
+		t.Errorf("Expected an unmappable character to be encoded as '?', got %q", encoded)
+	}
+	if decoded := UTF8.Decode([]byte{'a', 0xFF, 'b'}); decoded != "a�b" {
+		t.Errorf("Expected an invalid byte to decode as U+FFFD, got %q", decoded)
+	}
+	if decoded := UTF8.Decode([]byte{'a', 0xE2, 0x82}); decoded != "a�" {
+		t.Errorf("Expected a truncated sequence to decode as U+FFFD, got %q", decoded)
+	}
+	if decoded := USASCII.Decode([]byte{0xE9}); decoded != "�" {
+		t.Errorf("Expected a byte above 0x7F to decode as U+FFFD in US-ASCII, got %q", decoded)
+	}
+	if decoded := UTF16.Decode([]byte{0xFF, 0xFE, 'h', 0}); decoded != "h" {
+		t.Errorf("Expected a little-endian byte-order mark to be honored, got %q", decoded)
+	}
+}
+
+func TestDecoderAcrossPieces(t *testing.T) {
+	d := UTF8.NewDecoder()
+	euro := []byte{0xE2, 0x82, 0xAC}
+	if chars := d.Decode(euro[:1]); len(chars) != 0 {
+		t.Errorf("Expected no chars from an incomplete character, got %v", chars)
+	}
+	if chars := d.Decode(euro[1:]); !slices.Equal(chars, []uint16{0x20AC}) {
+		t.Errorf("Expected the euro sign once the character is complete, got %v", chars)
+	}
+
+	// a supplementary character is decoded as a surrogate pair
+	if chars := d.Decode([]byte{0xF0, 0x9F, 0x98, 0x80}); !slices.Equal(chars, utf16.Encode([]rune{0x1F600})) {
+		t.Errorf("Expected a surrogate pair, got %v", chars)
+	}
+
+	d.Decode([]byte{0xE2})
+	if chars := d.Flush(); !slices.Equal(chars, []uint16{0xFFFD}) {
+		t.Errorf("Expected U+FFFD for the bytes left at the end, got %v", chars)
+	}
+}
+
+func TestEncoderAcrossPieces(t *testing.T) {
+	e := UTF8.NewEncoder()
+	pair := utf16.Encode([]rune{0x1F600})
+	if b := e.Encode(pair[:1]); len(b) != 0 {
+		t.Errorf("Expected no bytes from a high surrogate alone, got % X", b)
+	}
+	if b := e.Encode(pair[1:]); !bytes.Equal(b, []byte{0xF0, 0x9F, 0x98, 0x80}) {
+		t.Errorf("Expected the bytes of the surrogate pair, got % X", b)
+	}
+	if b := e.Encode([]uint16{0xD800, 'a'}); string(b) != "?a" {
+		t.Errorf("Expected an unpaired surrogate to be encoded as '?', got %q", b)
+	}
+
+	// the byte-order mark is written once
+	e = UTF16.NewEncoder()
+	if b := append(e.Encode([]uint16{'a'}), e.Encode([]uint16{'b'})...); !bytes.Equal(b, []byte{0xFE, 0xFF, 0, 'a', 0, 'b'}) {
+		t.Errorf("Expected one byte-order mark, got % X", b)
+	}
+}
+
+func TestNative(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_CTYPE", "")
+
+	t.Setenv("LANG", "de_DE.ISO-8859-1@euro")
+	if cs := Native(); cs != ISO88591 && cs != Windows1252 { // windows-1252 on Windows
+		t.Errorf("Expected ISO-8859-1, got %s", cs.Name)
+	}
+	t.Setenv("LANG", "C")
+	if cs := Native(); cs != USASCII && cs != Windows1252 {
+		t.Errorf("Expected US-ASCII, got %s", cs.Name)
+	}
+	t.Setenv("LANG", "")
+	if cs := Native(); cs != UTF8 && cs != Windows1252 {
+		t.Errorf("Expected UTF-8, got %s", cs.Name)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	saved := Default()
+	t.Cleanup(func() { SetDefault(saved) })
+
+	SetDefault(ISO88591)
+	if Default() != ISO88591 {
+		t.Errorf("Expected the default charset to be ISO-8859-1, got %s", Default().Name)
+	}
+}
@@ -17,6 +17,9 @@ import (
 	"jacobin/src/object"
 	"jacobin/src/stringPool"
 	"jacobin/src/types"
+	"jacobin/src/util"
+	"strings"
+	"sync"
 )
 
 type JavaLangClass struct {
@@ -134,3 +137,65 @@ func ClassFromInstance(obj *object.Object) *JavaLangClass {
 
 	return &cl
 }
+
+// == The java/lang/Class object cache ==
+// Java code expects there to be exactly one java/lang/Class object per loaded class,
+// so that, for example, x.getClass() == Foo.class. The objects are created on first
+// request and are cached here, keyed by the string-pool index of the class name.
+// The cache belongs to the method area and is cleared when it is (re)initialized.
+// All code that hands a Class object to Java code should obtain it from here.
+
+var classObjects = make(map[uint32]*object.Object)
+var classObjectsMutex sync.Mutex
+
+// GetClassObject returns the java/lang/Class object for the class whose name
+// (in java/lang/String format, or an array descriptor such as [Ljava/lang/String;)
+// is found at the given string-pool index, creating it if necessary.
+func GetClassObject(classNameIndex uint32) *object.Object {
+	classObjectsMutex.Lock()
+	defer classObjectsMutex.Unlock()
+
+	if cl, ok := classObjects[classNameIndex]; ok {
+		return cl
+	}
+
+	className := *stringPool.GetStringPointer(classNameIndex)
+	cl := object.MakeEmptyObjectWithClassName(&types.ClassClassName)
+	binaryName := util.ConvertInternalClassNameToUserFormat(className)
+	cl.FieldTable["name"] = object.Field{
+		Ftype: types.Ref, Fvalue: object.StringObjectFromGoString(binaryName)}
+	cl.FieldTable["$klass"] = object.Field{Ftype: types.StringIndex, Fvalue: classNameIndex}
+
+	classObjects[classNameIndex] = cl
+	return cl
+}
+
+// MethAreaClassObject returns the java/lang/Class object for the named class, or
+// nil if the class is not in the method area. Array classes need not be loaded.
+func MethAreaClassObject(className string) *object.Object {
+	if !strings.HasPrefix(className, types.Array) && MethAreaFetch(className) == nil {
+		return nil
+	}
+	return GetClassObject(stringPool.GetStringIndex(&className))
+}
+
+// resets the cache of Class objects. Called when the method area is initialized.
+func resetClassObjects() {
+	classObjectsMutex.Lock()
+	classObjects = make(map[uint32]*object.Object)
+	classObjectsMutex.Unlock()
+}
+
+// ClassNameFromClassObject returns the name of the class (in java/lang/String format)
+// that a java/lang/Class object created by GetClassObject represents, or an empty
+// string if the object is not such a Class object.
+func ClassNameFromClassObject(cl *object.Object) string {
+	if object.IsNull(cl) {
+		return ""
+	}
+	fld, ok := cl.FieldTable["$klass"]
+	if !ok {
+		return ""
+	}
+	return *stringPool.GetStringPointer(fld.Fvalue.(uint32))
+}
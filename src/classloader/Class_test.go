@@ -0,0 +1,85 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package classloader
+
+import (
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"testing"
+)
+
+func TestGetClassObjectIsSingleton(t *testing.T) {
+	globals.InitGlobals("test")
+
+	name := "pkg/Foo"
+	index := stringPool.GetStringIndex(&name)
+	cl1 := GetClassObject(index)
+	cl2 := GetClassObject(index)
+
+	if cl1 != cl2 {
+		t.Error("Expected the same Class object for repeated requests")
+	}
+
+	if *stringPool.GetStringPointer(cl1.KlassName) != types.ClassClassName {
+		t.Errorf("Expected object of class java/lang/Class, got: %s", *stringPool.GetStringPointer(cl1.KlassName))
+	}
+
+	if ClassNameFromClassObject(cl1) != name {
+		t.Errorf("Expected class name %s, got: %s", name, ClassNameFromClassObject(cl1))
+	}
+
+	binaryName := object.GoStringFromStringObject(cl1.FieldTable["name"].Fvalue.(*object.Object))
+	if binaryName != "pkg.Foo" {
+		t.Errorf("Expected name field pkg.Foo, got: %s", binaryName)
+	}
+}
+
+func TestClassNameFromNonClassObject(t *testing.T) {
+	globals.InitGlobals("test")
+
+	if ClassNameFromClassObject(object.Null) != "" {
+		t.Error("Expected empty class name for null object")
+	}
+
+	str := object.StringObjectFromGoString("not a class")
+	if ClassNameFromClassObject(str) != "" {
+		t.Error("Expected empty class name for a non-Class object")
+	}
+}
+
+func TestMethAreaClassObject(t *testing.T) {
+	globals.InitGlobals("test")
+	InitMethodArea()
+
+	if MethAreaClassObject("pkg/NotLoaded") != nil {
+		t.Error("Expected no Class object for a class that is not loaded")
+	}
+
+	k := Klass{Status: 'F', Loader: "testloader", Data: &ClData{
+		Name:            "pkg/Loaded",
+		SuperclassIndex: types.ObjectPoolStringIndex,
+	}}
+	MethAreaInsert("pkg/Loaded", &k)
+
+	cl := MethAreaClassObject("pkg/Loaded")
+	if cl == nil || ClassNameFromClassObject(cl) != "pkg/Loaded" {
+		t.Errorf("Expected Class object for pkg/Loaded, got: %v", cl)
+	}
+
+	if MethAreaClassObject("[Lpkg/Loaded;") == nil {
+		t.Error("Expected a Class object for an array class")
+	}
+
+	// reinitializing the method area discards the cached Class objects
+	InitMethodArea()
+	MethAreaInsert("pkg/Loaded", &k)
+	if MethAreaClassObject("pkg/Loaded") == cl {
+		t.Error("Expected a new Class object after the method area was reinitialized")
+	}
+}
@@ -0,0 +1,292 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package classloader
+
+import (
+	"fmt"
+	"jacobin/src/globals"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"strings"
+)
+
+// This file contains the access-control checks (JVMS 5.4.4) performed when a
+// symbolic reference to a class, field, or method is resolved. For fields and
+// methods, the checks are done once per CP entry: after a reference has passed,
+// the CP entry is flagged as checked and subsequent executions of the same
+// bytecode skip the check. The checks can be disabled with -XX:-EnforceAccess.
+
+// access flags for fields and methods, see:
+// https://docs.oracle.com/javase/specs/jvms/se17/html/jvms-4.html#jvms-4.5
+const (
+	AccPublic    = 0x0001
+	AccPrivate   = 0x0002
+	AccProtected = 0x0004
+	AccStatic    = 0x0008
+	AccFinal     = 0x0010
+	AccAbstract  = 0x0400
+	AccSynthetic = 0x1000
+)
+
+// NestHostOf returns the name of the nest host of the named class. A class
+// that has no NestHost attribute is the host of its own nest. Classes that are
+// not in the method area are treated as their own nest hosts.
+func NestHostOf(className string) string {
+	k := MethAreaFetch(className)
+	if k == nil || k.Data == nil || k.Data.NestHost == 0 {
+		return className
+	}
+	return *stringPool.GetStringPointer(k.Data.NestHost)
+}
+
+// AreNestmates determines whether two classes belong to the same nest, which
+// is what allows javac (since Java 11) to have inner and outer classes access
+// each other's private members directly. Per JVMS 5.4.4, the nest host must
+// list the member in its NestMembers attribute for the membership to be valid.
+func AreNestmates(class1, class2 string) bool {
+	if class1 == class2 {
+		return true
+	}
+
+	host1 := NestHostOf(class1)
+	host2 := NestHostOf(class2)
+	if host1 != host2 {
+		return false
+	}
+
+	return isValidNestMember(host1, class1) && isValidNestMember(host1, class2)
+}
+
+// isValidNestMember confirms that the nest host lists the class as a member
+func isValidNestMember(host, member string) bool {
+	if host == member {
+		return true
+	}
+
+	k := MethAreaFetch(host)
+	if k == nil || k.Data == nil {
+		return false
+	}
+
+	memberIndex := stringPool.GetStringIndex(&member)
+	for _, m := range k.Data.NestMembers {
+		if m == memberIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// isAccessible applies the access rules of JVMS 5.4.4 to a member (field or
+// method) having the given access flags that is declared in the class owner,
+// when accessed from code in the class accessor. It returns an empty string if
+// the access is permitted, otherwise a description of why it was refused.
+func isAccessible(accessor, owner string, accessFlags int) string {
+	if accessor == owner {
+		return ""
+	}
+
+	switch {
+	case accessFlags&AccPublic > 0:
+		return ""
+	case accessFlags&AccPrivate > 0:
+		if !AreNestmates(accessor, owner) {
+			return fmt.Sprintf("private member of %s is not accessible from %s (not nestmates)", owner, accessor)
+		}
+	case accessFlags&AccProtected > 0:
+		if !IsSamePackage(accessor, owner) && !IsSubclassOf(accessor, owner) {
+			return fmt.Sprintf("protected member of %s is not accessible from %s "+
+				"(not in the same package nor a subclass)", owner, accessor)
+		}
+	default: // package-private
+		if !IsSamePackage(accessor, owner) {
+			return fmt.Sprintf("package-private member of %s is not accessible from %s "+
+				"(not in the same package)", owner, accessor)
+		}
+	}
+	return ""
+}
+
+// isClassAccessible applies the class access rules of JVMS 5.4.4: a class is
+// accessible if it is public or if it is in the same runtime package as the
+// accessing class. Array classes are accessible if their element class is.
+// Classes that are not in the method area are not checked.
+func isClassAccessible(accessor, className string) string {
+	className = strings.TrimLeft(className, "[")
+	if strings.HasPrefix(className, "L") && strings.HasSuffix(className, ";") {
+		className = className[1 : len(className)-1]
+	}
+	if accessor == className || IsSamePackage(accessor, className) {
+		return ""
+	}
+
+	k := MethAreaFetch(className)
+	if k == nil || k.Data == nil || k.Data.Access.ClassIsPublic {
+		return ""
+	}
+	return fmt.Sprintf("class %s is not public and is not accessible from %s (not in the same package)",
+		className, accessor)
+}
+
+// IsSamePackage determines whether two classes are in the same package. Jacobin
+// loads user classes with a single application classloader, so the runtime package
+// is determined by the package name alone.
+func IsSamePackage(class1, class2 string) bool {
+	return packageOf(class1) == packageOf(class2)
+}
+
+// returns the package portion of a class name in java/lang/String format
+func packageOf(className string) string {
+	lastSlash := strings.LastIndex(className, "/")
+	if lastSlash == -1 {
+		return ""
+	}
+	return className[:lastSlash]
+}
+
+// FindFieldOwner searches the named class and then its superclasses for the
+// named field. It returns the name of the declaring class and the field's
+// access flags. If the field cannot be located, found is false.
+func FindFieldOwner(className, fieldName string) (owner string, accessFlags int, found bool) {
+	owner, fld := findField(className, fieldName)
+	if fld == nil {
+		return "", 0, false
+	}
+	return owner, fld.AccessFlags, true
+}
+
+// findField searches the named class and then its superclasses for the named
+// field. It returns the name of the declaring class and a pointer to the field,
+// or nil if the field cannot be located.
+func findField(className, fieldName string) (string, *Field) {
+	clName := className
+	for clName != "" {
+		k := MethAreaFetch(clName)
+		if k == nil || k.Data == nil {
+			return "", nil
+		}
+		for i := range k.Data.Fields {
+			if k.Data.Fields[i].NameStr == fieldName {
+				return clName, &k.Data.Fields[i]
+			}
+		}
+		if clName == types.ObjectClassName {
+			break
+		}
+		clName = *stringPool.GetStringPointer(k.Data.SuperclassIndex)
+	}
+	return "", nil
+}
+
+// FindMethodOwner searches the named class and then its superclasses for the method
+// whose name and type are given, such as toString()Ljava/lang/String;. It returns the
+// name of the declaring class and the method's access flags. If the method cannot be
+// located, found is false.
+func FindMethodOwner(className, methNameAndType string) (owner string, accessFlags int, found bool) {
+	clName := className
+	for clName != "" {
+		k := MethAreaFetch(clName)
+		if k == nil || k.Data == nil {
+			return "", 0, false
+		}
+		if m, ok := k.Data.MethodTable[methNameAndType]; ok {
+			return clName, m.AccessFlags, true
+		}
+		if clName == types.ObjectClassName {
+			break
+		}
+		clName = *stringPool.GetStringPointer(k.Data.SuperclassIndex)
+	}
+	return "", 0, false
+}
+
+// CheckFieldRefAccess checks whether the class accessor may access the field
+// referred to by the FieldRef at slot in the CP's FieldRefs. A non-nil error
+// contains the message for the IllegalAccessError the caller should throw.
+// Fields that cannot be located (e.g., those of classes implemented as
+// gfunctions) are not checked.
+func CheckFieldRefAccess(accessor string, cp *CPool, slot uint16) error {
+	if cp == nil || int(slot) >= len(cp.FieldRefs) {
+		return nil
+	}
+
+	fieldRef := &cp.FieldRefs[slot]
+	if fieldRef.AccessChecked || !globals.GetGlobalRef().EnforceAccess {
+		return nil
+	}
+
+	if msg := isClassAccessible(accessor, fieldRef.ClName); msg != "" {
+		return fmt.Errorf("tried to access field %s.%s from class %s: %s",
+			fieldRef.ClName, fieldRef.FldName, accessor, msg)
+	}
+
+	owner, flags, found := FindFieldOwner(fieldRef.ClName, fieldRef.FldName)
+	if found {
+		if msg := isAccessible(accessor, owner, flags); msg != "" {
+			return fmt.Errorf("tried to access field %s.%s from class %s: %s",
+				owner, fieldRef.FldName, accessor, msg)
+		}
+	}
+
+	fieldRef.AccessChecked = true
+	return nil
+}
+
+// CheckClassAccess checks whether the class accessor may refer to the named
+// class, as when the class is resolved by NEW and ANEWARRAY. A non-nil error contains the message for the IllegalAccessError
+// the caller should throw.
+func CheckClassAccess(accessor, className string) error {
+	if !globals.GetGlobalRef().EnforceAccess {
+		return nil
+	}
+	if msg := isClassAccessible(accessor, className); msg != "" {
+		return fmt.Errorf("failed to access class %s from class %s: %s", className, accessor, msg)
+	}
+	return nil
+}
+
+// CheckMethodRefAccess checks whether the class accessor may invoke the method
+// with the given access flags that is referred to by the MethodRef at cpIndex
+// in the CP. As for fields, the member checks apply to the class that declares
+// the method, which is a superclass of the class the MethodRef names if the method
+// is inherited; the given access flags are used only if the declaring class can't
+// be located. A non-nil error contains the message for the IllegalAccessError
+// the caller should throw.
+func CheckMethodRefAccess(accessor string, cp *CPool, cpIndex int, accessFlags int) error {
+	if cp == nil || cpIndex < 1 || cpIndex >= len(cp.CpIndex) || cp.CpIndex[cpIndex].Type != MethodRef {
+		return nil
+	}
+
+	slot := cp.CpIndex[cpIndex].Slot
+	if int(slot) >= len(cp.ResolvedMethodRefs) {
+		return nil
+	}
+
+	methRef := &cp.ResolvedMethodRefs[slot]
+	if methRef.AccessChecked || !globals.GetGlobalRef().EnforceAccess {
+		return nil
+	}
+
+	refClass := *stringPool.GetStringPointer(methRef.ClassIndex)
+	if msg := isClassAccessible(accessor, refClass); msg != "" {
+		return fmt.Errorf("tried to access method %s from class %s: %s",
+			*stringPool.GetStringPointer(methRef.FQNameIndex), accessor, msg)
+	}
+
+	owner, flags := refClass, accessFlags
+	methNameAndType := *stringPool.GetStringPointer(methRef.NameIndex) + *stringPool.GetStringPointer(methRef.TypeIndex)
+	if declarer, declFlags, found := FindMethodOwner(refClass, methNameAndType); found {
+		owner, flags = declarer, declFlags
+	}
+	if msg := isAccessible(accessor, owner, flags); msg != "" {
+		return fmt.Errorf("tried to access method %s from class %s: %s",
+			*stringPool.GetStringPointer(methRef.FQNameIndex), accessor, msg)
+	}
+
+	methRef.AccessChecked = true
+	return nil
+}
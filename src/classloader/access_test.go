@@ -0,0 +1,296 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package classloader
+
+import (
+	"jacobin/src/globals"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"testing"
+)
+
+// sets up a nest consisting of pkg/Outer (the host) and pkg/Outer$Inner,
+// plus pkg/Other, which is not a member of the nest.
+func setupNestTestClasses() {
+	globals.InitGlobals("test")
+	InitMethodArea()
+
+	outer := "pkg/Outer"
+	inner := "pkg/Outer$Inner"
+	other := "pkg/Other"
+
+	outerKlass := Klass{Status: 'F', Loader: "testloader", Data: &ClData{
+		Name:            outer,
+		SuperclassIndex: types.ObjectPoolStringIndex,
+		NestMembers:     []uint32{stringPool.GetStringIndex(&inner)},
+		Fields: []Field{
+			{AccessFlags: AccPrivate, NameStr: "secret"},
+			{AccessFlags: AccPublic, NameStr: "open"},
+		},
+	}}
+	innerKlass := Klass{Status: 'F', Loader: "testloader", Data: &ClData{
+		Name:            inner,
+		SuperclassIndex: types.ObjectPoolStringIndex,
+		NestHost:        stringPool.GetStringIndex(&outer),
+	}}
+	otherKlass := Klass{Status: 'F', Loader: "testloader", Data: &ClData{
+		Name:            other,
+		SuperclassIndex: stringPool.GetStringIndex(&outer),
+	}}
+
+	MethAreaInsert(outer, &outerKlass)
+	MethAreaInsert(inner, &innerKlass)
+	MethAreaInsert(other, &otherKlass)
+}
+
+func TestAreNestmates(t *testing.T) {
+	setupNestTestClasses()
+
+	if !AreNestmates("pkg/Outer", "pkg/Outer$Inner") {
+		t.Error("Expected pkg/Outer and pkg/Outer$Inner to be nestmates")
+	}
+
+	if !AreNestmates("pkg/Outer$Inner", "pkg/Outer") {
+		t.Error("Expected pkg/Outer$Inner and pkg/Outer to be nestmates")
+	}
+
+	if AreNestmates("pkg/Other", "pkg/Outer") {
+		t.Error("Expected pkg/Other and pkg/Outer not to be nestmates")
+	}
+
+	if NestHostOf("pkg/Outer$Inner") != "pkg/Outer" {
+		t.Errorf("Expected nest host of pkg/Outer$Inner to be pkg/Outer, got: %s", NestHostOf("pkg/Outer$Inner"))
+	}
+}
+
+func TestNestmateClaimNotConfirmedByHost(t *testing.T) {
+	setupNestTestClasses()
+
+	// a class that claims pkg/Outer as its host, but is not listed in pkg/Outer's NestMembers
+	outer := "pkg/Outer"
+	impostor := "pkg/Impostor"
+	k := Klass{Status: 'F', Loader: "testloader", Data: &ClData{
+		Name:            impostor,
+		SuperclassIndex: types.ObjectPoolStringIndex,
+		NestHost:        stringPool.GetStringIndex(&outer),
+	}}
+	MethAreaInsert(impostor, &k)
+
+	if AreNestmates(impostor, outer) {
+		t.Error("Expected pkg/Impostor not to be a nestmate of pkg/Outer")
+	}
+}
+
+func TestFindFieldOwnerInSuperclass(t *testing.T) {
+	setupNestTestClasses()
+
+	owner, flags, found := FindFieldOwner("pkg/Other", "secret")
+	if !found {
+		t.Fatal("Expected to find field secret in superclass of pkg/Other")
+	}
+	if owner != "pkg/Outer" || flags&AccPrivate == 0 {
+		t.Errorf("Expected private field in pkg/Outer, got owner: %s, flags: %X", owner, flags)
+	}
+
+	_, _, found = FindFieldOwner("pkg/Other", "nonexistent")
+	if found {
+		t.Error("Did not expect to find field nonexistent")
+	}
+}
+
+func TestCheckFieldRefAccessPrivate(t *testing.T) {
+	setupNestTestClasses()
+
+	cp := CPool{}
+	cp.FieldRefs = append(cp.FieldRefs, ResolvedFieldEntry{ClName: "pkg/Outer", FldName: "secret"})
+
+	if err := CheckFieldRefAccess("pkg/Outer$Inner", &cp, 0); err != nil {
+		t.Errorf("Expected nestmate access to private field to be permitted, got: %v", err)
+	}
+	if !cp.FieldRefs[0].AccessChecked {
+		t.Error("Expected the field ref to be flagged as access-checked")
+	}
+
+	cp.FieldRefs[0].AccessChecked = false
+	if err := CheckFieldRefAccess("pkg/Other", &cp, 0); err == nil {
+		t.Error("Expected access to private field from a non-nestmate to be refused")
+	}
+	if cp.FieldRefs[0].AccessChecked {
+		t.Error("A refused field ref should not be flagged as access-checked")
+	}
+}
+
+func TestIsSamePackageAndSubclass(t *testing.T) {
+	setupNestTestClasses()
+
+	if !IsSamePackage("pkg/Outer", "pkg/Other") {
+		t.Error("Expected pkg/Outer and pkg/Other to be in the same package")
+	}
+	if IsSamePackage("pkg/Outer", "pkg/sub/Outer") {
+		t.Error("Expected pkg/Outer and pkg/sub/Outer to be in different packages")
+	}
+	if !IsSamePackage("Main", "Other") {
+		t.Error("Expected classes in the unnamed package to be in the same package")
+	}
+
+	if !IsSubclassOf("pkg/Other", "pkg/Outer") {
+		t.Error("Expected pkg/Other to be a subclass of pkg/Outer")
+	}
+	if !IsSubclassOf("pkg/Other", types.ObjectClassName) {
+		t.Error("Expected pkg/Other to be a subclass of java/lang/Object")
+	}
+	if IsSubclassOf("pkg/Outer", "pkg/Other") {
+		t.Error("Did not expect pkg/Outer to be a subclass of pkg/Other")
+	}
+}
+
+func TestIsAccessibleProtectedAndPackagePrivate(t *testing.T) {
+	setupNestTestClasses()
+
+	// a subclass in a different package
+	sub := "other/Sub"
+	outer := "pkg/Outer"
+	k := Klass{Status: 'F', Loader: "testloader", Data: &ClData{
+		Name:            sub,
+		SuperclassIndex: stringPool.GetStringIndex(&outer),
+	}}
+	MethAreaInsert(sub, &k)
+
+	if msg := isAccessible(sub, outer, AccProtected); msg != "" {
+		t.Errorf("Expected subclass access to protected member to be permitted, got: %s", msg)
+	}
+	if msg := isAccessible("other/Stranger", outer, AccProtected); msg == "" {
+		t.Error("Expected access to protected member from unrelated class in another package to be refused")
+	}
+	if msg := isAccessible("pkg/Stranger", outer, AccProtected); msg != "" {
+		t.Errorf("Expected same-package access to protected member to be permitted, got: %s", msg)
+	}
+	if msg := isAccessible(sub, outer, 0); msg == "" {
+		t.Error("Expected access to package-private member from another package to be refused")
+	}
+	if msg := isAccessible("pkg/Stranger", outer, 0); msg != "" {
+		t.Errorf("Expected same-package access to package-private member to be permitted, got: %s", msg)
+	}
+}
+
+func TestCheckClassAccess(t *testing.T) {
+	setupNestTestClasses()
+
+	hidden := "pkg/Hidden"
+	k := Klass{Status: 'F', Loader: "testloader", Data: &ClData{
+		Name:            hidden,
+		SuperclassIndex: types.ObjectPoolStringIndex,
+	}}
+	k.Data.Access.ClassIsPublic = false
+	MethAreaInsert(hidden, &k)
+
+	if err := CheckClassAccess("pkg/Outer", hidden); err != nil {
+		t.Errorf("Expected same-package access to non-public class to be permitted, got: %v", err)
+	}
+	if err := CheckClassAccess("other/Main", hidden); err == nil {
+		t.Error("Expected access to non-public class from another package to be refused")
+	}
+	if err := CheckClassAccess("other/Main", "[Lpkg/Hidden;"); err == nil {
+		t.Error("Expected access to array of non-public class from another package to be refused")
+	}
+
+	globals.GetGlobalRef().EnforceAccess = false
+	if err := CheckClassAccess("other/Main", hidden); err != nil {
+		t.Errorf("Expected no access check with -XX:-EnforceAccess, got: %v", err)
+	}
+}
+
+func TestImplementsInterface(t *testing.T) {
+	setupNestTestClasses()
+
+	// pkg/Base implements pkg/Child, which extends pkg/Parent; pkg/Derived extends pkg/Base
+	parent := "pkg/Parent"
+	child := "pkg/Child"
+	base := "pkg/Base"
+	childKlass := Klass{Status: 'F', Loader: "testloader", Data: &ClData{
+		Name:            child,
+		SuperclassIndex: types.ObjectPoolStringIndex,
+		Interfaces:      []uint16{uint16(stringPool.GetStringIndex(&parent))},
+	}}
+	baseKlass := Klass{Status: 'F', Loader: "testloader", Data: &ClData{
+		Name:            base,
+		SuperclassIndex: types.ObjectPoolStringIndex,
+		Interfaces:      []uint16{uint16(stringPool.GetStringIndex(&child))},
+	}}
+	derivedKlass := Klass{Status: 'F', Loader: "testloader", Data: &ClData{
+		Name:            "pkg/Derived",
+		SuperclassIndex: stringPool.GetStringIndex(&base),
+	}}
+	MethAreaInsert(child, &childKlass)
+	MethAreaInsert(base, &baseKlass)
+	MethAreaInsert("pkg/Derived", &derivedKlass)
+
+	if !ImplementsInterface("pkg/Derived", parent) {
+		t.Error("Expected pkg/Derived to implement pkg/Parent via its superclass and a superinterface")
+	}
+	if ImplementsInterface("pkg/Outer", parent) {
+		t.Error("Did not expect pkg/Outer to implement pkg/Parent")
+	}
+}
+
+// returns a CP whose entry 1 is a MethodRef to the method of the class
+func methodRefCP(className, methName, methType string) *CPool {
+	fqn := className + "." + methName + methType
+	cp := CPool{}
+	cp.CpIndex = []CpEntry{{Type: 0, Slot: 0}, {Type: MethodRef, Slot: 0}}
+	cp.ResolvedMethodRefs = []ResolvedMethodRefEntry{{
+		ClassIndex:  stringPool.GetStringIndex(&className),
+		NameIndex:   stringPool.GetStringIndex(&methName),
+		TypeIndex:   stringPool.GetStringIndex(&methType),
+		FQNameIndex: stringPool.GetStringIndex(&fqn),
+	}}
+	return &cp
+}
+
+func TestCheckMethodRefAccessInheritedAcrossPackages(t *testing.T) {
+	globals.InitGlobals("test")
+	InitMethodArea()
+
+	// pkg/a/Base declares a protected and a package-private method, which
+	// pkg/b/Derived inherits
+	base := "pkg/a/Base"
+	MethAreaInsert(base, &Klass{Status: 'F', Loader: "testloader", Data: &ClData{
+		Name:            base,
+		SuperclassIndex: types.ObjectPoolStringIndex,
+		Access:          AccessFlags{ClassIsPublic: true},
+		MethodTable: map[string]*Method{
+			"guarded()V":  {AccessFlags: AccProtected},
+			"internal()V": {AccessFlags: 0},
+		},
+	}})
+	derived := "pkg/b/Derived"
+	MethAreaInsert(derived, &Klass{Status: 'F', Loader: "testloader", Data: &ClData{
+		Name:            derived,
+		SuperclassIndex: stringPool.GetStringIndex(&base),
+		Access:          AccessFlags{ClassIsPublic: true},
+		MethodTable:     map[string]*Method{},
+	}})
+
+	// the protected method is accessible from Base's package, though Derived is elsewhere
+	cp := methodRefCP(derived, "guarded", "()V")
+	if err := CheckMethodRefAccess("pkg/a/Peer", cp, 1, AccProtected); err != nil {
+		t.Errorf("Expected a protected method to be accessible from the package that declares it, got: %v", err)
+	}
+
+	// the package-private method isn't accessible from Derived's package
+	cp = methodRefCP(derived, "internal", "()V")
+	if err := CheckMethodRefAccess("pkg/b/Helper", cp, 1, 0); err == nil {
+		t.Error("Expected a package-private method of another package to be refused, though it's inherited")
+	}
+	if err := CheckMethodRefAccess("pkg/a/Peer", cp, 1, 0); err != nil {
+		t.Errorf("Expected a package-private method to be accessible from its own package, got: %v", err)
+	}
+
+	if owner, flags, found := FindMethodOwner(derived, "guarded()V"); !found || owner != base || flags != AccProtected {
+		t.Errorf("Expected to find guarded() in %s, got: %s, %X, %v", base, owner, flags, found)
+	}
+}
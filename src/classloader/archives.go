@@ -10,8 +10,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"jacobin/src/trace"
 	"strings"
+	"time"
 )
 
 // This file contains the code for loading and managing JAR files.
@@ -34,9 +36,22 @@ type ResourceEntry struct {
 type Archive struct {
 	Filename   string
 	entryCache map[string]ResourceEntry
+	entries    []ArchiveEntry
 	manifest   map[string]string
 }
 
+// ArchiveEntry describes a file in an archive, with the details that
+// java.util.zip.ZipEntry reports about it
+type ArchiveEntry struct {
+	Name           string
+	Comment        string
+	Method         uint16
+	CRC32          uint32
+	Size           int64
+	CompressedSize int64
+	Modified       time.Time
+}
+
 type LoadResult struct {
 	Success       bool
 	Data          *[]byte
@@ -68,7 +83,17 @@ func (archive *Archive) scanArchive() error {
 		return err
 	}
 
+	archive.entries = nil
 	for _, file := range reader.File {
+		archive.entries = append(archive.entries, ArchiveEntry{
+			Name:           file.Name,
+			Comment:        file.Comment,
+			Method:         file.Method,
+			CRC32:          file.CRC32,
+			Size:           int64(file.UncompressedSize64),
+			CompressedSize: int64(file.CompressedSize64),
+			Modified:       file.Modified,
+		})
 		entry := archive.recordFile(file)
 		if entry.Type == Manifest {
 			if archive.parseManifest(file); err != nil {
@@ -175,6 +200,33 @@ func (archive *Archive) loadClass(className string) (*LoadResult, error) {
 	return &LoadResult{Data: &bytes, Success: true, ResourceEntry: item}, nil
 }
 
+// Entries returns the entries in the archive, in the order they appear in it
+func (archive *Archive) Entries() []ArchiveEntry {
+	return archive.entries
+}
+
+// ReadEntry returns the uncompressed contents of the named entry. If there's no
+// such entry, the error is fs.ErrNotExist.
+func (archive *Archive) ReadEntry(name string) ([]byte, error) {
+	reader, err := zip.OpenReader(archive.Filename)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	for _, file := range reader.File {
+		if file.Name == name {
+			rc, err := file.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		}
+	}
+	return nil, fs.ErrNotExist
+}
+
 func (archive *Archive) getMainClass() string {
 	mainClass, exists := archive.manifest["Main-Class"]
 
@@ -102,3 +102,37 @@ func TestLoadClassDoesNotExist(t *testing.T) {
 		t.Error("Expected error loading class, but didn't get one.")
 	}
 }
+
+func TestArchiveEntries(t *testing.T) {
+	jar, err := getJar(GOOD_JAR_NAME, t)
+
+	if err != nil {
+		return
+	}
+
+	entries := jar.Entries()
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(entries))
+	}
+
+	if entries[0].Name != "META-INF/MANIFEST.MF" || entries[3].Name != "jacobin/HelloWorld.class" {
+		t.Errorf("Entries are not in archive order: %s ... %s", entries[0].Name, entries[3].Name)
+	}
+
+	if entries[3].Size != 562 {
+		t.Errorf("Expected jacobin/HelloWorld.class to be 562 bytes, got %d", entries[3].Size)
+	}
+
+	data, err := jar.ReadEntry("jacobin/HelloWorld.class")
+	if err != nil {
+		t.Fatal("Error reading entry", err)
+	}
+
+	if len(data) != 562 || data[0] != 0xCA || data[1] != 0xFE {
+		t.Errorf("Entry contents are not the class file: %d bytes", len(data))
+	}
+
+	if _, err = jar.ReadEntry("no/such/entry"); err == nil {
+		t.Error("Expected error reading an entry that doesn't exist, but didn't get one.")
+	}
+}
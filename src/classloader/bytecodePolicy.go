@@ -0,0 +1,159 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package classloader
+
+import (
+	"fmt"
+	"jacobin/src/globals"
+	"strings"
+)
+
+// The bytecode policy decides, for a method that has both a gfunction and bytecode in
+// the JDK's classes, which of the two runs. By default, the gfunction always wins,
+// because the gfunctions are loaded into the MTable before any class is. The
+// -XX:PreferBytecode flag names the classes and methods for which the JDK's own code
+// is run instead, so that gfunctions can be retired gradually and a discrepancy can be
+// tracked down by running the same program both ways.
+//
+// The flag's value is a list of patterns separated by commas. Each is one of:
+//
+//	java/util/*               every class in the package and its subpackages
+//	java/util/ArrayList       every method of the class (java.util.ArrayList also works)
+//	java/lang/Math.abs        every method of the class with that name
+//	java/lang/Math.abs(I)I    that method only
+//
+// A pattern prefixed with - excludes what it matches, and when patterns overlap the last
+// one that matches decides. So java/util/*,-java/util/HashMap runs all of java.util
+// from bytecode except HashMap.
+//
+// The gfunctions that the policy displaces are not put in the MTable, but are held aside
+// in heldGfunctions. When FetchMethodAndCP finds that the class has no bytecode for the
+// method--because the method is native or isn't in the class at all--the held gfunction
+// is used after all.
+
+// a pattern of -XX:PreferBytecode, parsed
+type bytecodePattern struct {
+	text    string // the pattern in slash form, without the * or the -
+	prefix  bool   // the pattern ends in *, so it matches every class whose name starts with text
+	exclude bool   // the pattern starts with -
+}
+
+// the parsed patterns, and the value of -XX:PreferBytecode they were parsed from
+var bytecodePatterns []bytecodePattern
+var bytecodePolicySpec string
+
+// the gfunctions displaced by the policy, by method FQN. Guarded by MTmutex.
+var heldGfunctions = make(map[string]MTentry)
+
+// CheckBytecodePolicy checks the syntax of a value of -XX:PreferBytecode, returning an
+// error that identifies the first malformed pattern
+func CheckBytecodePolicy(spec string) error {
+	_, err := parseBytecodePolicy(spec)
+	return err
+}
+
+// parses a value of -XX:PreferBytecode
+func parseBytecodePolicy(spec string) ([]bytecodePattern, error) {
+	var patterns []bytecodePattern
+	if spec == "" {
+		return patterns, nil
+	}
+	for _, text := range strings.Split(spec, ",") {
+		p := bytecodePattern{text: strings.TrimSpace(text)}
+		if strings.HasPrefix(p.text, "-") {
+			p.exclude = true
+			p.text = p.text[1:]
+		}
+		if strings.HasSuffix(p.text, "*") {
+			p.prefix = true
+			p.text = p.text[:len(p.text)-1]
+			if strings.Contains(p.text, "(") {
+				return nil, fmt.Errorf("a pattern with a method can't end in *: %s", text)
+			}
+		}
+		if !strings.Contains(p.text, "/") && !strings.Contains(p.text, "(") {
+			p.text = strings.ReplaceAll(p.text, ".", "/") // a class or package in dot form
+		}
+		if p.text == "" && !p.prefix {
+			return nil, fmt.Errorf("empty pattern in %s", spec)
+		}
+		if paren := strings.Index(p.text, "("); paren >= 0 &&
+			(!strings.Contains(p.text[:paren], ".") || !strings.Contains(p.text[paren:], ")")) {
+			return nil, fmt.Errorf("not a class, package, or method: %s", text)
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+// PrefersBytecode determines whether -XX:PreferBytecode calls for the JDK's bytecode to
+// run in place of the gfunction for the method. methFQN is the fully qualified name of
+// the method, such as java/lang/Math.abs(I)I, as used for the keys of the MTable.
+func PrefersBytecode(methFQN string) bool {
+	spec := globals.GetGlobalRef().PreferBytecode
+	if spec != bytecodePolicySpec {
+		patterns, err := parseBytecodePolicy(spec)
+		if err != nil { // the flag is checked when it's set, so this is unlikely
+			patterns = nil
+		}
+		bytecodePatterns, bytecodePolicySpec = patterns, spec
+	}
+	if len(bytecodePatterns) == 0 {
+		return false
+	}
+
+	paren := strings.Index(methFQN, "(")
+	if paren < 0 {
+		return false
+	}
+	dot := strings.LastIndex(methFQN[:paren], ".")
+	if dot < 0 {
+		return false
+	}
+	className := methFQN[:dot]
+
+	prefer := false
+	for _, p := range bytecodePatterns {
+		var matches bool
+		switch {
+		case p.prefix:
+			matches = strings.HasPrefix(className, p.text)
+		case strings.Contains(p.text, "("):
+			matches = methFQN == p.text
+		case strings.Contains(p.text, "."):
+			matches = methFQN[:paren] == p.text
+		default:
+			matches = className == p.text
+		}
+		if matches {
+			prefer = !p.exclude
+		}
+	}
+	return prefer
+}
+
+// HoldGfunction sets aside the gfunction for the method, which the bytecode policy
+// displaces, so that it's available if the class turns out to have no bytecode for it
+func HoldGfunction(methFQN string, mte MTentry) {
+	MTmutex.Lock()
+	heldGfunctions[methFQN] = mte
+	MTmutex.Unlock()
+}
+
+// heldGfunction returns the gfunction held aside for the method, if there is one
+func heldGfunction(methFQN string) (MTentry, bool) {
+	MTmutex.Lock()
+	defer MTmutex.Unlock()
+	mte, ok := heldGfunctions[methFQN]
+	return mte, ok
+}
+
+// hasBytecode determines whether the method in a class has code to run, as opposed
+// to being native or abstract
+func hasBytecode(m *Method) bool {
+	return len(m.CodeAttr.Code) > 0
+}
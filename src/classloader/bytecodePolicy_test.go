@@ -0,0 +1,89 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package classloader
+
+import (
+	"jacobin/src/globals"
+	"testing"
+)
+
+func TestPrefersBytecode_Patterns(t *testing.T) {
+	globals.InitGlobals("test")
+	gl := globals.GetGlobalRef()
+
+	if PrefersBytecode("java/util/ArrayList.size()I") {
+		t.Error("Expected the gfunctions to be preferred by default")
+	}
+
+	gl.PreferBytecode = "java.util.*,-java/util/HashMap,java/lang/Math.abs,java/lang/String.length()I"
+	tests := []struct {
+		methFQN string
+		want    bool
+	}{
+		{"java/util/ArrayList.size()I", true},
+		{"java/util/concurrent/ConcurrentHashMap.size()I", true},
+		{"java/util/HashMap.size()I", false},
+		{"java/util/HashMap$Node.getKey()Ljava/lang/Object;", true},
+		{"java/lang/Math.abs(I)I", true},
+		{"java/lang/Math.abs(J)J", true},
+		{"java/lang/Math.max(II)I", false},
+		{"java/lang/String.length()I", true},
+		{"java/lang/String.isEmpty()Z", false},
+	}
+	for _, test := range tests {
+		if got := PrefersBytecode(test.methFQN); got != test.want {
+			t.Errorf("PrefersBytecode(%s) = %v, expected %v", test.methFQN, got, test.want)
+		}
+	}
+}
+
+func TestCheckBytecodePolicy(t *testing.T) {
+	for _, spec := range []string{"", "*", "java/util/*", "java.lang.Math", "java/lang/Math.abs(I)I", "-java/util/*"} {
+		if err := CheckBytecodePolicy(spec); err != nil {
+			t.Errorf("Unexpected error for %q: %v", spec, err)
+		}
+	}
+	for _, spec := range []string{"java/util/*,,java/io/*", "-", "java/lang/Math.abs(I*", "abs(I)I"} {
+		if err := CheckBytecodePolicy(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+func TestFetchMethodAndCP_HeldGfunction(t *testing.T) {
+	globals.InitGlobals("test")
+	resetState()
+
+	// the bytecode of run() replaces its gfunction, but the native poll() has no bytecode
+	klassName := "com/example/Policy"
+	kData := &ClData{Name: klassName, MethodTable: make(map[string]*Method)}
+	kData.MethodTable["run()V"] = &Method{CodeAttr: CodeAttrib{MaxStack: 1, MaxLocals: 1, Code: []byte{0xb1}}}
+	kData.MethodTable["poll()I"] = &Method{AccessFlags: 0x0100}
+	MethAreaInsert(klassName, &Klass{Status: 'F', Data: kData})
+
+	gm := Function(func(args []interface{}) interface{} { return nil })
+	HoldGfunction(klassName+".run()V", MTentry{Meth: gm, MType: 'G'})
+	HoldGfunction(klassName+".poll()I", MTentry{Meth: gm, MType: 'G'})
+	HoldGfunction(klassName+".extra()V", MTentry{Meth: gm, MType: 'G'})
+
+	for _, test := range []struct {
+		methName, methType string
+		want               byte
+	}{
+		{"run", "()V", 'J'},
+		{"poll", "()I", 'G'},
+		{"extra", "()V", 'G'},
+	} {
+		res, err := FetchMethodAndCP(klassName, test.methName, test.methType)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", test.methName, err)
+		}
+		if res.MType != test.want {
+			t.Errorf("expected %q for %s%s, got %q", test.want, test.methName, test.methType, res.MType)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"jacobin/src/stringPool"
 	"jacobin/src/trace"
 	"jacobin/src/types"
+	"sync/atomic"
 )
 
 // the definition of the class as it's stored in the method area
@@ -38,9 +39,12 @@ type ClData struct {
 	Attributes      []Attr
 	SourceFile      string
 	Bootstraps      []BootstrapMethod
+	NestHost        uint32   // index into StringPool of the nest host; 0 if the class is its own nest host
+	NestMembers     []uint32 // indices into StringPool of the nest members (present only in a nest host)
 	CP              CPool
 	Access          AccessFlags
 	ClInit          byte // 0 = no clinit, 1 = clinit not run, 2 clinit run
+	Deprecated      bool // does the class have a Deprecated attribute?
 }
 
 // the CP of the loaded class (see above)
@@ -63,6 +67,7 @@ type CPool struct {
 	Utf8Refs              []string
 	ResolvedInterfaceRefs []ResolvedInterfaceRefEntry // resolved interface references
 	ResolvedMethodRefs    []ResolvedMethodRefEntry    // resolved method references
+	ResolvedClasses       []*Klass                    // ClassRefs linked to their classes (see cpResolution.go)
 }
 
 type AccessFlags struct {
@@ -112,6 +117,7 @@ type CodeAttrib struct {
 	Exceptions        []CodeException // exception entries for this method
 	Attributes        []Attr          // the code attributes has its own sub-attributes(!)
 	BytecodeSourceMap []BytecodeToSourceLine
+	LocalVariables    []LocalVariable // the named local variables, if compiled with -g (see localVariables.go)
 }
 
 // ParamAttrib is the MethodParameters method attribute
@@ -158,10 +164,12 @@ type MethodRefEntry struct { // type: 10 (method reference)
 }
 
 type ResolvedMethodRefEntry struct { // type: 10 (method reference, resolved)
-	ClassIndex  uint32 // all of these are indices into the StringPool
-	NameIndex   uint32
-	TypeIndex   uint32
-	FQNameIndex uint32 // the three previous strings appended into one entry (the most common usage)
+	ClassIndex    uint32 // all of these are indices into the StringPool
+	NameIndex     uint32
+	TypeIndex     uint32
+	FQNameIndex   uint32                  // the three previous strings appended into one entry (the most common usage)
+	AccessChecked bool                    // has access to this method been checked? (see access.go)
+	Method        atomic.Pointer[MTentry] // the MTable entry of the method, once linked (see cpResolution.go)
 }
 
 type InterfaceRefEntry struct { // type: 11 (interface reference)
@@ -173,7 +181,8 @@ type ResolvedInterfaceRefEntry struct { // type: 11 (interface reference, resolv
 	ClassIndex  uint32 // all of these are indices into the StringPool
 	NameIndex   uint32
 	TypeIndex   uint32
-	FQNameIndex uint32 // the three previous strings appended into one entry (the most common usage)
+	FQNameIndex uint32                  // the three previous strings appended into one entry (the most common usage)
+	Method      atomic.Pointer[MTentry] // the MTable entry of the method, once linked (see cpResolution.go)
 }
 
 type NameAndTypeEntry struct { // type 12 (name and type reference)
@@ -301,6 +310,11 @@ func FetchMethodAndCP(className, methName, methType string) (MTentry, error) {
 	var m Method
 	searchName := methName + methType
 	methRef, ok := k.Data.MethodTable[searchName]
+	if held, found := heldGfunction(methFQN); found && (!ok || !hasBytecode(methRef)) {
+		// -XX:PreferBytecode displaced the gfunction, but the class has no bytecode to run instead
+		AddEntry(&MTable, methFQN, held)
+		return held, nil
+	}
 	if ok {
 		m = *methRef
 
@@ -318,6 +332,7 @@ func FetchMethodAndCP(className, methName, methType string) (MTentry, error) {
 			params:      m.Parameters,
 			deprecated:  m.Deprecated,
 			Cp:          &k.Data.CP,
+			Class:       k.Data.Name,
 		}
 
 		// add the method to the MTable and return it
@@ -344,7 +359,17 @@ func FetchMethodAndCP(className, methName, methType string) (MTentry, error) {
 			shutdown.Exit(shutdown.JVM_EXCEPTION)
 			return MTentry{}, errors.New(errMsg) // dummy return needed for tests
 		}
+		// the superclass method might be implemented as a G-function (e.g., Object.clone())
+		if superEntry := MTable[className+"."+searchName]; superEntry.Meth != nil {
+			AddEntry(&MTable, methFQN, superEntry)
+			return superEntry, nil
+		}
+
 		methRef, ok = k.Data.MethodTable[searchName]
+		if held, found := heldGfunction(className + "." + searchName); found && (!ok || !hasBytecode(methRef)) {
+			AddEntry(&MTable, methFQN, held)
+			return held, nil
+		}
 		if ok {
 			m = *methRef
 
@@ -362,6 +387,7 @@ func FetchMethodAndCP(className, methName, methType string) (MTentry, error) {
 				params:      m.Parameters,
 				deprecated:  m.Deprecated,
 				Cp:          &k.Data.CP,
+				Class:       k.Data.Name,
 			}
 
 			// add the method to the MTable and return it
@@ -409,3 +435,42 @@ func FetchUTF8stringFromCPEntryNumber(cp *CPool, entry uint16) string {
 
 	return cp.Utf8Refs[u.Slot]
 }
+
+// IsClassDeprecated reports whether the named class has a Deprecated attribute.
+// Classes that are not in the method area are reported as not deprecated.
+func IsClassDeprecated(className string) bool {
+	k := MethAreaFetch(className)
+	return k != nil && k.Data != nil && k.Data.Deprecated
+}
+
+// IsClassSynthetic reports whether the named class was generated by the compiler
+// rather than declared in the source code (its ACC_SYNTHETIC flag is set).
+func IsClassSynthetic(className string) bool {
+	k := MethAreaFetch(className)
+	return k != nil && k.Data != nil && k.Data.Access.ClassIsSynthetic
+}
+
+// IsMethodDeprecated reports whether the method, which must be declared in the
+// named class, has a Deprecated attribute. These and IsMethodSynthetic() are the
+// basis of the reflection API's Method.isSynthetic() and @Deprecated checks.
+func IsMethodDeprecated(className, methName, methType string) bool {
+	m := fetchDeclaredMethod(className, methName, methType)
+	return m != nil && m.Deprecated
+}
+
+// IsMethodSynthetic reports whether the method, which must be declared in the named
+// class, was generated by the compiler (its ACC_SYNTHETIC flag is set), as are
+// bridge methods and the accessors of lambdas.
+func IsMethodSynthetic(className, methName, methType string) bool {
+	m := fetchDeclaredMethod(className, methName, methType)
+	return m != nil && m.AccessFlags&AccSynthetic != 0
+}
+
+// returns the method declared in the named class, or nil if it's not there
+func fetchDeclaredMethod(className, methName, methType string) *Method {
+	k := MethAreaFetch(className)
+	if k == nil || k.Data == nil {
+		return nil
+	}
+	return k.Data.MethodTable[methName+methType]
+}
@@ -14,6 +14,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestFetchUTF8stringFromCPEntryNumber(t *testing.T) {
@@ -212,6 +213,10 @@ func TestFetchMethodAndCP_WaitForClassStatusError(t *testing.T) {
 	resetState()
 
 	klassName := "com/example/Unstable"
+	savedTimeout := ClassLoadTimeout
+	ClassLoadTimeout = 10 * time.Millisecond
+	defer func() { ClassLoadTimeout = savedTimeout }()
+
 	// Insert class with Status 'I' so WaitForClassStatus() times out
 	MethAreaInsert(klassName, &Klass{Status: 'I', Data: &ClData{}})
 
@@ -402,3 +407,33 @@ func TestFetchUTF8stringFromCPEntryNumber_UpperBoundOutOfRange(t *testing.T) {
 }
 
 // === End of tests generated by Junie ===
+
+func TestDeprecatedAndSyntheticQueries(t *testing.T) {
+	globals.InitGlobals("test")
+	InitMethodArea()
+	defer InitMethodArea()
+
+	name := "flags/Old"
+	MethAreaInsert(name, &Klass{Status: 'F', Data: &ClData{
+		Name:       name,
+		Deprecated: true,
+		Access:     AccessFlags{ClassIsSynthetic: true},
+		MethodTable: map[string]*Method{
+			"old()V":          {Deprecated: true},
+			"lambda$run$0()V": {AccessFlags: AccPrivate | AccStatic | AccSynthetic},
+		},
+	}})
+
+	if !IsClassDeprecated(name) || !IsClassSynthetic(name) {
+		t.Errorf("Expected %s to be deprecated and synthetic", name)
+	}
+	if !IsMethodDeprecated(name, "old", "()V") || IsMethodSynthetic(name, "old", "()V") {
+		t.Error("Expected old()V to be deprecated and not synthetic")
+	}
+	if IsMethodDeprecated(name, "lambda$run$0", "()V") || !IsMethodSynthetic(name, "lambda$run$0", "()V") {
+		t.Error("Expected lambda$run$0()V to be synthetic and not deprecated")
+	}
+	if IsClassDeprecated("flags/Missing") || IsMethodSynthetic(name, "missing", "()V") {
+		t.Error("Expected classes and methods that are not loaded to be neither deprecated nor synthetic")
+	}
+}
@@ -49,6 +49,7 @@ var ExtensionCL Classloader
 // ParsedClass contains all the parsed fields
 type ParsedClass struct {
 	javaVersion    int
+	minorVersion   int    // 65535 (0xFFFF) for classes that use preview features
 	className      string // name of class without path and without .class TODO: eventually remove
 	classNameIndex uint32 // index into StringPool
 	// superClass      string // name of superclass for this class TODO: eventually remove in favor of stringPool
@@ -66,6 +67,8 @@ type ParsedClass struct {
 	sourceFile      string
 	bootstrapCount  int // the number of bootstrap methods
 	bootstraps      []bootstrapMethod
+	nestHost        uint32   // string pool index of the NestHost class, if any (0 = no NestHost attribute)
+	nestMembers     []uint32 // string pool indices of the classes in the NestMembers attribute, if any
 
 	deprecated bool
 
@@ -115,12 +118,16 @@ type field struct {
 }
 
 type ResolvedFieldEntry struct {
-	AccessFlags int
-	IsStatic    bool
-	IsFinal     bool
-	ClName      string
-	FldName     string
-	FldType     string
+	AccessFlags   int
+	IsStatic      bool
+	IsFinal       bool
+	ClName        string
+	FldName       string
+	FldType       string
+	AccessChecked bool        // has access to this field been checked? (see access.go)
+	ConstValue    interface{} // value of a static final constant, once resolved (see cpUtils.go)
+	Class         *Klass      // the class that declares the field, once linked (see cpResolution.go)
+	Slot          int         // index of the field in the declaring class's Fields, once linked
 }
 
 // the methods of the class, including the constructors
@@ -143,6 +150,7 @@ type codeAttrib struct {
 	exceptions      []exception // exception entries for this method
 	attributes      []attr      // the code attributes has its own sub-attributes(!)
 	sourceLineTable *[]BytecodeToSourceLine
+	localVarTable   []LocalVariable // from the LocalVariableTable and LocalVariableTypeTable
 }
 
 // the MethodParameters method attribute
@@ -176,16 +184,29 @@ var ClassesLock = sync.RWMutex{}
 
 // cfe = class format error, which is the error thrown by the parser for most
 // of the errors arising from malformed bytecode. Prints out file and line# where
-// the call to cfe() occurred.
+// the call to cfe() occurred. The error has no code in the error catalog; errors
+// that do are reported with cfeCode().
 func cfe(msg string) error {
+	return classFormatError(CfeUnclassified, msg)
+}
+
+// cfeCode reports the class format error with the given code in the error catalog
+// (see errorCatalog.go), formatting its message with args. Prints out file and line#
+// where the call to cfeCode() occurred.
+func cfeCode(code ErrorCode, args ...any) error {
+	return classFormatError(code, catalogMessage(code, args...))
+}
+
+// classFormatError does the work of cfe() and cfeCode(), which must be its only callers.
+func classFormatError(code ErrorCode, msg string) error {
 	errMsg := "Class Format Error: " + msg
 
 	// get the filename and line# of the function where the error occurred
-	// implementation note: Caller(0) would be this function. (1) is the
-	// previous function on the stack (so, the one calling this error routine)
+	// implementation note: Caller(0) would be this function. (1) is cfe() or
+	// cfeCode(), and (2) is the function that called them.
 	// To traverse all the way back to the start of the program, set up a loop
 	// and exit when ok is no longer true.
-	pc, _, _, ok := runtime.Caller(1)
+	pc, _, _, ok := runtime.Caller(2)
 	if ok {
 		fn := runtime.FuncForPC(pc)
 		fileName, fileLine := fn.FileLine(pc)
@@ -193,7 +214,7 @@ func cfe(msg string) error {
 			", line: " + strconv.Itoa(fileLine)
 	}
 	trace.Error(errMsg)
-	return errors.New(errMsg)
+	return &CatalogError{Code: code, Msg: errMsg}
 }
 
 func CFE(msg string) error { return cfe(msg) }
@@ -204,7 +225,7 @@ func CFE(msg string) error { return cfe(msg) }
 // Based on the lib/classlist member in java.base.jmod, only 1402 class files are actually loaded by this function.
 func LoadBaseClasses() {
 	global := globals.GetGlobalRef()
-	jmodFilePath := global.JavaHome + string(os.PathSeparator) + "jmods" + string(os.PathSeparator) + "java.base.jmod"
+	jmodFilePath := util.GetPlatform().JoinPath(global.JavaHome, "jmods", "java.base.jmod")
 
 	err := WalkBaseJmod()
 	if err != nil {
@@ -254,6 +275,7 @@ func LoadFromLoaderChannel(LoaderChannel <-chan string) {
 		MethAreaInsert(name, &eKI)
 		err := LoadClassFromNameOnly(util.ConvertToPlatformPathSeparators(name))
 		if err != nil {
+			MethAreaDelete(name) // wake up any threads waiting for the class
 			shutdown.Exit(shutdown.JVM_EXCEPTION)
 		}
 	}
@@ -264,6 +286,7 @@ func LoadFromLoaderChannel(LoaderChannel <-chan string) {
 // It also loads the superclasses of any class it loads.
 func LoadClassFromNameOnly(name string) error {
 	var err error
+	var superclassChain []string // the classes loaded so far in this call, to detect circularity
 	className := name
 
 	// we loop here in order to load the class and all its superclasses.
@@ -275,6 +298,12 @@ loadAclass:
 		return errors.New(errMsg)
 	}
 
+	if err = checkCircularity(superclassChain, className); err != nil {
+		globals.GetGlobalRef().FuncThrowException(excNames.ClassCircularityError, err.Error())
+		return err // return for tests only
+	}
+	superclassChain = append(superclassChain, className)
+
 	// get the jmod file name for this class. We'll use the jmod file to
 	// get the .class file for this class.
 	jmodFileName := JmodMapFetch(className)
@@ -337,6 +366,20 @@ loadAclass:
 	return err
 }
 
+// checkCircularity returns an error if className already appears in the chain of
+// superclasses being loaded, which means that the class is its own superclass
+// (e.g., A extends B extends A). Such hierarchies can only arise from class files
+// that were compiled separately, and JVMS 5.3.5 requires a ClassCircularityError.
+func checkCircularity(superclassChain []string, className string) error {
+	for i, clName := range superclassChain {
+		if clName == className {
+			return fmt.Errorf("LoadClassFromNameOnly: class %s is its own superclass: %s extends %s",
+				className, strings.Join(superclassChain[i:], " extends "), className)
+		}
+	}
+	return nil
+}
+
 // LoadClassFromFile first canonicalizes the filename, and reads the file from the classpath,
 // and class the classloader to load it.
 func LoadClassFromFile(cl Classloader, fname string) (uint32, uint32, error) {
@@ -452,6 +495,12 @@ func ParseAndPostClass(cl *Classloader, filename string, rawBytes []byte) (uint3
 	fullyParsedClass, err := parse(rawBytes)
 	if err != nil {
 		trace.Error("ParseAndPostClass: file " + filename + ", err: " + err.Error())
+		if IsClassVersionError(err) {
+			className := strings.TrimSuffix(filename, ".class")
+			globals.GetGlobalRef().FuncThrowException(excNames.UnsupportedClassVersionError,
+				classVersionMessage(className, &fullyParsedClass, err))
+			return types.InvalidStringIndex, types.InvalidStringIndex, err
+		}
 		return types.InvalidStringIndex, types.InvalidStringIndex, fmt.Errorf("parsing error")
 	}
 
@@ -505,6 +554,7 @@ func convertToPostableClass(fullyParsedClass *ParsedClass) ClData {
 	if len(fullyParsedClass.fields) > 0 {
 		for i := 0; i < len(fullyParsedClass.fields); i++ {
 			kdf := Field{}
+			kdf.AccessFlags = fullyParsedClass.fields[i].accessFlags
 			kdf.Name = uint16(fullyParsedClass.fields[i].name)
 			kdf.NameStr = fullyParsedClass.utf8Refs[kdf.Name].content // temporarily include field name. JACOBIN-611
 			kdf.Desc = uint16(fullyParsedClass.fields[i].description)
@@ -592,6 +642,9 @@ func convertToPostableClass(fullyParsedClass *ParsedClass) ClData {
 				fullyParsedClass.methods[i].codeAttr.sourceLineTable = nil
 			}
 
+			kdm.CodeAttr.LocalVariables = fullyParsedClass.methods[i].codeAttr.localVarTable
+			jmeth.CodeAttr.LocalVariables = fullyParsedClass.methods[i].codeAttr.localVarTable
+
 			if len(fullyParsedClass.methods[i].attributes) > 0 {
 				for n := 0; n < len(fullyParsedClass.methods[i].attributes); n++ {
 					kdma := Attr{
@@ -650,6 +703,8 @@ func convertToPostableClass(fullyParsedClass *ParsedClass) ClData {
 		}
 	}
 	kd.SourceFile = fullyParsedClass.sourceFile
+	kd.NestHost = fullyParsedClass.nestHost
+	kd.NestMembers = fullyParsedClass.nestMembers
 	if len(fullyParsedClass.bootstraps) > 0 {
 		for j := 0; j < len(fullyParsedClass.bootstraps); j++ {
 			kdbs := BootstrapMethod{
@@ -670,6 +725,7 @@ func convertToPostableClass(fullyParsedClass *ParsedClass) ClData {
 	kd.Access.ClassIsInterface = fullyParsedClass.classIsInterface
 	kd.Access.ClassIsAbstract = fullyParsedClass.classIsAbstract
 	kd.Access.ClassIsSynthetic = fullyParsedClass.classIsSynthetic
+	kd.Deprecated = fullyParsedClass.deprecated
 	kd.Access.ClassIsAnnotation = fullyParsedClass.classIsAnnotation
 	kd.Access.ClassIsEnum = fullyParsedClass.classIsEnum
 	kd.Access.ClassIsModule = fullyParsedClass.classIsModule
@@ -700,6 +756,9 @@ func convertToPostableClass(fullyParsedClass *ParsedClass) ClData {
 		for i := 0; i < len(fullyParsedClass.utf8Refs); i++ {
 			kd.CP.Utf8Refs = append(kd.CP.Utf8Refs, fullyParsedClass.utf8Refs[i].content)
 		}
+		if globals.GetGlobalRef().CompactCPs {
+			CompactCP(&kd.CP)
+		}
 	}
 
 	if len(fullyParsedClass.classRefs) > 0 {
@@ -637,3 +637,19 @@ func TestLoadClassFromNameOnly_SuperclassRecursion(t *testing.T) {
 }
 
 // === end of tests generated by Jetbrains Junie ===
+
+func TestCheckCircularity(t *testing.T) {
+	chain := []string{"pkg/Main", "pkg/A", "pkg/B"}
+
+	if err := checkCircularity(chain, "pkg/C"); err != nil {
+		t.Errorf("Unexpected error for a class not in the chain: %v", err)
+	}
+
+	err := checkCircularity(chain, "pkg/A")
+	if err == nil {
+		t.Fatal("Expected an error for a class that is its own superclass")
+	}
+	if !strings.Contains(err.Error(), "pkg/A extends pkg/B extends pkg/A") {
+		t.Errorf("Unexpected error message: %s", err.Error())
+	}
+}
@@ -8,7 +8,6 @@ package classloader
 
 import (
 	"encoding/binary"
-	"errors"
 	"fmt"
 	"jacobin/src/excNames"
 	"jacobin/src/globals"
@@ -231,6 +230,51 @@ var bytecodeSkipTable = map[byte]int{
 	0xCA: 1, // BREAKPOINT
 }
 
+// BytecodeLength returns the length in bytes of the instruction at code[pc],
+// including its operands, or 0 if pc is out of range or the instruction is
+// truncated. Unlike the checking functions below, it uses no package state, so
+// it can be used to walk the bytecode of methods after the class is loaded.
+func BytecodeLength(code []byte, pc int) int {
+	if pc < 0 || pc >= len(code) {
+		return 0
+	}
+
+	length, ok := bytecodeSkipTable[code[pc]]
+	if !ok {
+		return 0
+	}
+	switch code[pc] {
+	case 0xAA, 0xAB: // TABLESWITCH, LOOKUPSWITCH: operands start on a 4-byte boundary
+		base := (pc + 4) &^ 3 // skip the padding to the default offset
+		if base+12 > len(code) {
+			return 0
+		}
+		if code[pc] == 0xAA {
+			low := int32(binary.BigEndian.Uint32(code[base+4:]))
+			high := int32(binary.BigEndian.Uint32(code[base+8:]))
+			if low > high {
+				return 0
+			}
+			length = base + 12 + int(int64(high)-int64(low)+1)*4 - pc
+		} else {
+			npairs := int(binary.BigEndian.Uint32(code[base+4:]))
+			length = base + 8 + npairs*8 - pc
+		}
+	case 0xC4: // WIDE: a wide IINC has a 2-byte index and 2-byte constant, others a 2-byte index
+		if pc+1 >= len(code) {
+			return 0
+		}
+		length = 4
+		if code[pc+1] == 0x84 {
+			length = 6
+		}
+	}
+	if pc+length > len(code) {
+		return 0
+	}
+	return length
+}
+
 type BytecodeFunc func() int
 
 var ERROR_OCCURRED = math.MaxInt32
@@ -328,10 +372,10 @@ var CheckTable = [203]BytecodeFunc{
 	CheckPop2,            // POP2            0x58
 	CheckDup1,            // DUP             0x59
 	CheckDup1,            // DUP_X1          0x5A
-	CheckDup1,            // DUP_X2          0x5B
+	CheckDupx2,           // DUP_X2          0x5B
 	CheckDup2,            // DUP2            0x5C
-	CheckDup2,            // DUP2_X1         0x5D
-	CheckDup2,            // DUP2_X2         0x5E
+	CheckDup2x1,          // DUP2_X1         0x5D
+	CheckDup2x2,          // DUP2_X2         0x5E
 	Return1,              // SWAP            0x5F
 	Arith,                // IADD            0x60
 	Arith,                // LADD            0x61
@@ -433,7 +477,7 @@ var CheckTable = [203]BytecodeFunc{
 	Return3,              // INSTANCEOF      0xC1
 	Return1,              // MONITORENTER    0xC2
 	Return1,              // MONITOREXIT     0xC3
-	Return1,              // WIDE            0xC4
+	CheckWide,            // WIDE            0xC4
 	CheckMultianewarray,  // MULTIANEWARRAY  0xC5
 	Return3,              // IFNULL          0xC6
 	Return3,              // IFNONNULL       0xC7
@@ -449,10 +493,19 @@ var Code []byte
 var StackEntries int
 var MaxStack int
 
+// codeError returns the error with the given code in the error catalog
+func codeError(code ErrorCode, args ...any) error {
+	return &CatalogError{Code: code, Msg: catalogMessage(code, args...)}
+}
+
+// verifyErrorMsg returns the message for a VerifyError with the given code in the error catalog
+func verifyErrorMsg(code ErrorCode, args ...any) string {
+	return fmt.Sprintf("%s:\n %s", excNames.JVMexceptionNames[excNames.VerifyError], catalogMessage(code, args...))
+}
+
 func CheckCodeValidity(codePtr *[]byte, cp *CPool, maxStack int, access AccessFlags) error {
 	if codePtr == nil {
-		errMsg := "CheckCodeValidity: ptr to code segment is nil"
-		return errors.New(errMsg)
+		return codeError(VfyNilCode)
 	}
 	code := *codePtr
 	// check that the code is valid
@@ -460,20 +513,17 @@ func CheckCodeValidity(codePtr *[]byte, cp *CPool, maxStack int, access AccessFl
 		if access.ClassIsAbstract {
 			return nil
 		} else {
-			errMsg := "CheckCodeValidity: Empty code segment"
-			return errors.New(errMsg)
+			return codeError(VfyEmptyCode)
 		}
 	}
 
 	if cp == nil {
-		errMsg := "CheckCodeValidity: ptr to constant pool is nil"
-		return errors.New(errMsg)
+		return codeError(VfyNilCP)
 	}
 
 	CP = cp
 	if len(CP.CpIndex) == 0 {
-		errMsg := "CheckCodeValidity: empty constant pool"
-		return errors.New(errMsg)
+		return codeError(VfyEmptyCP)
 	}
 
 	Code = code
@@ -481,27 +531,30 @@ func CheckCodeValidity(codePtr *[]byte, cp *CPool, maxStack int, access AccessFl
 	PrevPC = -1 // -1 means no previous PC
 	MaxStack = maxStack
 	StackEntries = 0
+	checkedPCs = checkedPCs[:0]
 
 	for PC < len(code) {
 		opcode := code[PC]
-		ret := CheckTable[opcode]()
+		ret := ERROR_OCCURRED // for opcodes beyond the table, such as IMPDEP1, which can't appear in a class file
+		if int(opcode) < len(CheckTable) && CheckTable[opcode] != nil {
+			ret = CheckTable[opcode]()
+		}
 		if ret == ERROR_OCCURRED {
-			errMsg := fmt.Sprintf("Invalid bytecode or argument at location %d", PC)
-			status := globals.GetGlobalRef().FuncThrowException(excNames.ClassFormatError, errMsg)
-			if status != true { // will only happen in test
-				globals.InitGlobals("test")
-				return errors.New(errMsg)
+			err := codeError(VfyInvalidBytecode, PC)
+			status := globals.GetGlobalRef().FuncThrowException(excNames.ClassFormatError, err.Error())
+			if status != true { // will only happen in test, or when validating (see jvm/validate.go)
+				return err
 			}
 		} else {
 			if ret+PC > len(code) {
-				errMsg := fmt.Sprintf("Invalid bytecode or argument at location %d", PC)
-				status := globals.GetGlobalRef().FuncThrowException(excNames.ClassFormatError, errMsg)
-				if status != true { // will only happen in test
-					globals.InitGlobals("test")
-					return errors.New(errMsg)
+				err := codeError(VfyInvalidBytecode, PC)
+				status := globals.GetGlobalRef().FuncThrowException(excNames.ClassFormatError, err.Error())
+				if status != true { // will only happen in test, or when validating
+					return err
 				}
 			}
 			PrevPC = PC
+			checkedPCs = append(checkedPCs, PC)
 			PC += ret
 		}
 	}
@@ -537,25 +590,78 @@ func CheckDup1() int {
 	return 1
 }
 
+// DUP_X2 0x5B duplicates the top stack value and inserts it three values down. When the
+// second value is a long or double, which take up 2 stack entries on HotSpot and other OpenJDK
+// JVMs but 1 on Jacobin, it's inserted two values down, so DUP_X2 is converted to DUP_X1.
+// (See codeCheckCategories.go.)
+func CheckDupx2() int {
+	if operandCategory(1) == cat2 {
+		Code[PC] = 0x5A // change DUP_X2 to DUP_X1
+	}
+	StackEntries += 1
+	return 1
+}
+
 // DUP2 is like DUP, but duplicates the top 2 stack values-- frequenly generated for longs and doubles,
 // which take up 2 stack entries on HotSpot and other OpenJDK JVMs. On Jacobin, doubles and longs
 // take up 1 stack entry, so we need to be check whether the operation is on a double or long. If
 // it is, then we convert DUP2 to DUP, which duplicates only the top stack entry.
 func CheckDup2() int {
-	if BytecodePushes32BitValue(Code[PrevPC]) { // check if the previous bytecode is a 32-bit load bytecode
-		goto dup2 // if so, we can safely use DUP2
+	if topIsLongOrDouble() {
+		Code[PC] = 0x59 // change DUP2 to DUP
+		StackEntries += 1
+		return 1
 	}
+	StackEntries += 2
+	return 1
+}
 
-	if BytecodeIsForLongOrDouble(Code[PC+1]) { // check if the next bytecode is for a long or double
-		Code[PC] = 0x59 // change DUP2 to DUP
+// DUP2_X1 0x5D duplicates the top 2 stack values and inserts them three values down. When the
+// top value is a long or double, it's duplicated alone and inserted two values down, so DUP2_X1
+// is converted to DUP_X1.
+func CheckDup2x1() int {
+	if topIsLongOrDouble() {
+		Code[PC] = 0x5A // change DUP2_X1 to DUP_X1
+		StackEntries += 1
+		return 1
+	}
+	StackEntries += 2
+	return 1
+}
+
+// DUP2_X2 0x5E duplicates the top 2 stack values and inserts them four values down. Each long
+// or double among the values it operates on counts as 2 of them, so depending on which are
+// longs or doubles, it's converted to DUP_X2, DUP2_X1, or DUP_X1.
+func CheckDup2x2() int {
+	if topIsLongOrDouble() {
+		if operandCategory(1) == cat2 {
+			Code[PC] = 0x5A // change DUP2_X2 to DUP_X1
+		} else {
+			Code[PC] = 0x5B // change DUP2_X2 to DUP_X2
+		}
 		StackEntries += 1
 		return 1
 	}
-dup2:
+	if operandCategory(2) == cat2 {
+		Code[PC] = 0x5D // change DUP2_X2 to DUP2_X1
+	}
 	StackEntries += 2
 	return 1
 }
 
+// reports whether the value at the top of the stack is a long or double. If the bytecodes that
+// precede the present one don't show this, then whether the next bytecode is for longs or doubles
+// decides.
+func topIsLongOrDouble() bool {
+	switch operandCategory(0) {
+	case cat2:
+		return true
+	case cat1:
+		return false
+	}
+	return PC+1 < len(Code) && BytecodeIsForLongOrDouble(Code[PC+1])
+}
+
 // FCONST and DCONST Push a float onto the op stack
 func PushFloat() int {
 	StackEntries += 1
@@ -607,8 +713,7 @@ func CheckGetfield() int {
 
 	CPentry := CP.CpIndex[CPslot]
 	if CPentry.Type != FieldRef {
-		errMsg := fmt.Sprintf("%s:\n GETFIELD at %d: CP entry (%d) is not a field reference",
-			excNames.JVMexceptionNames[excNames.VerifyError], PC, CPentry.Type)
+		errMsg := verifyErrorMsg(VfyNotFieldRef, "GETFIELD", PC, CPentry.Type)
 		trace.Error(errMsg)
 		return ERROR_OCCURRED
 	}
@@ -620,8 +725,7 @@ func CheckGetfield() int {
 func CheckGoto() int {
 	jumpTo := int(int16(Code[PC+1])*256 + int16(Code[PC+2]))
 	if PC+jumpTo < 0 || PC+jumpTo >= len(Code) {
-		errMsg := fmt.Sprintf("%s:\n GOTO at %d: illegal jump to %d",
-			excNames.JVMexceptionNames[excNames.VerifyError], PC, PC+jumpTo)
+		errMsg := verifyErrorMsg(VfyIllegalJump, "GOTO", PC, PC+jumpTo)
 		trace.Error(errMsg)
 		return ERROR_OCCURRED
 	}
@@ -632,8 +736,7 @@ func CheckGoto() int {
 func CheckGotow() int {
 	jumpTo := int(types.FourBytesToInt64(Code[PC+1], Code[PC+2], Code[PC+3], Code[PC+4]))
 	if PC+jumpTo < 0 || PC+jumpTo >= len(Code) {
-		errMsg := fmt.Sprintf("%s:\n GOTO_W at %d: illegal jump to %d",
-			excNames.JVMexceptionNames[excNames.VerifyError], PC, PC+jumpTo)
+		errMsg := verifyErrorMsg(VfyIllegalJump, "GOTO_W", PC, PC+jumpTo)
 		trace.Error(errMsg)
 		return ERROR_OCCURRED
 	}
@@ -644,8 +747,7 @@ func CheckGotow() int {
 func CheckIf() int { // most IF* bytecodes come here. Jump if condition is met
 	jumpSize := int(int16(Code[PC+1])*256 + int16(Code[PC+2]))
 	if PC+jumpSize < 0 || PC+jumpSize >= len(Code) {
-		errMsg := fmt.Sprintf("%s:\n IF_ACMPEQ at %d: illegal jump to %d",
-			excNames.JVMexceptionNames[excNames.VerifyError], PC, PC+jumpSize)
+		errMsg := verifyErrorMsg(VfyIllegalJump, "IF_ACMPEQ", PC, PC+jumpSize)
 		trace.Error(errMsg)
 		return ERROR_OCCURRED
 	}
@@ -655,8 +757,7 @@ func CheckIf() int { // most IF* bytecodes come here. Jump if condition is met
 func CheckIfzero() int { // Jump if condition w.r.t 0 is met
 	jumpSize := int(int16(Code[PC+1])*256 + int16(Code[PC+2]))
 	if PC+jumpSize < 0 || PC+jumpSize >= len(Code) {
-		errMsg := fmt.Sprintf("%s:\n IF* test at %d: illegal jump to %d",
-			excNames.JVMexceptionNames[excNames.VerifyError], PC, PC+jumpSize)
+		errMsg := verifyErrorMsg(VfyIllegalJump, "IF* test", PC, PC+jumpSize)
 		trace.Error(errMsg)
 		return ERROR_OCCURRED
 	}
@@ -684,8 +785,7 @@ func CheckInvokeinterface() int {
 	CPentry := CP.CpIndex[CPslot]
 	if CPentry.Type != Interface {
 		// because this is not a ClassFormatError, we output a trace error message here
-		errMsg := fmt.Sprintf("%s:\n INVOKEINTERFACE at %d: CP entry (%d) is not an interface reference",
-			excNames.JVMexceptionNames[excNames.VerifyError], PC, CPentry.Type)
+		errMsg := verifyErrorMsg(VfyNotInterfaceRef, "INVOKEINTERFACE", PC, CPentry.Type)
 		trace.Error(errMsg)
 		return ERROR_OCCURRED
 	}
@@ -703,8 +803,7 @@ func checkInvokespecial() int {
 	CPentry := CP.CpIndex[CPslot]
 	if CPentry.Type != MethodRef && CPentry.Type != Interface {
 		// because this is not a ClassFormatError, we output a trace error message here
-		errMsg := fmt.Sprintf("%s:\n INVOKESPECIAL at %d: CP entry (%d) is not a method or interface reference",
-			excNames.JVMexceptionNames[excNames.VerifyError], PC, CPentry.Type)
+		errMsg := verifyErrorMsg(VfyNotMethodOrInterface, "INVOKESPECIAL", PC, CPentry.Type)
 		trace.Error(errMsg)
 		return ERROR_OCCURRED
 	}
@@ -722,8 +821,7 @@ func checkInvokestatic() int {
 	CPentry := CP.CpIndex[CPslot]
 	if CPentry.Type != MethodRef && CPentry.Type != Interface {
 		// because this is not a ClassFormatError, we output a trace message here
-		errMsg := fmt.Sprintf("%s:\n INVOKESTATIC at %d: CP entry (%d) is not a method or interface reference",
-			excNames.JVMexceptionNames[excNames.VerifyError], PC, CPentry.Type)
+		errMsg := verifyErrorMsg(VfyNotMethodOrInterface, "INVOKESTATIC", PC, CPentry.Type)
 		trace.Error(errMsg)
 		return ERROR_OCCURRED
 	}
@@ -741,8 +839,7 @@ func CheckInvokevirtual() int {
 	CPentry := CP.CpIndex[CPslot]
 	if CPentry.Type != MethodRef {
 		// because this is not a ClassFormatError, we emit a trace message here
-		errMsg := fmt.Sprintf("%s:\n INVOKEVIRTUAL at %d: CP entry (%d) is not a method reference",
-			excNames.JVMexceptionNames[excNames.VerifyError], PC, CPentry.Type)
+		errMsg := verifyErrorMsg(VfyNotMethodRef, "INVOKEVIRTUAL", PC, CPentry.Type)
 		trace.Error(errMsg)
 		return ERROR_OCCURRED
 	}
@@ -785,11 +882,40 @@ func CheckPop() int {
 	return 1
 }
 
+// POP2 0x58 pops the top 2 stack values, or the top value if it's a long or double, in which case
+// it's converted to POP. (See codeCheckCategories.go.)
 func CheckPop2() int {
+	if operandCategory(0) == cat2 {
+		Code[PC] = 0x57 // change POP2 to POP
+		StackEntries -= 1
+		return 1
+	}
 	StackEntries -= 2
 	return 1
 }
 
+// WIDE 0xC4 widens the local variable index of the bytecode that follows to 2 bytes, as well as
+// the increment of IINC, so the two are checked as a single bytecode
+func CheckWide() int {
+	if PC+1 >= len(Code) {
+		return ERROR_OCCURRED
+	}
+	switch opcode := Code[PC+1]; {
+	case opcode >= 0x15 && opcode <= 0x19: // ILOAD, LLOAD, FLOAD, DLOAD, ALOAD
+		StackEntries += 1
+		return 4
+	case opcode >= 0x36 && opcode <= 0x3A: // ISTORE, LSTORE, FSTORE, DSTORE, ASTORE
+		StackEntries -= 1
+		return 4
+	case opcode == 0xA9: // RET
+		return 4
+	case opcode == 0x84: // IINC
+		return 6
+	default:
+		return ERROR_OCCURRED
+	}
+}
+
 // TABLESWITCH 0xAA
 func CheckTableSwitch() int {
 	basePC := PC
@@ -823,8 +949,7 @@ func CheckMultianewarray() int {
 	CPentry := CP.CpIndex[CPslot]
 	if CPentry.Type != ClassRef {
 		// because this is not a ClassFormatError, we emit a trace message here
-		errMsg := fmt.Sprintf("%s:\n MULTIANEWARRAY at %d: CP entry (%d) is not a class reference",
-			excNames.JVMexceptionNames[excNames.VerifyError], PC, CPentry.Type)
+		errMsg := verifyErrorMsg(VfyNotClassRef, "MULTIANEWARRAY", PC, CPentry.Type)
 		trace.Error(errMsg)
 		return ERROR_OCCURRED
 	}
@@ -0,0 +1,278 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package classloader
+
+import (
+	"jacobin/src/util"
+	"strings"
+)
+
+// The JVM spec counts longs and doubles as two values on the operand stack (they are of
+// category 2), while ints, floats, and references are one (category 1). Jacobin keeps
+// every value, including longs and doubles, in a single slot. So the stack-manipulation
+// bytecodes whose behavior depends on the categories of the values they operate on are
+// rewritten by the code checker into the bytecodes that do the same to single-slot values:
+//
+//	POP2     form 2: value1 is category 2                         -> POP
+//	DUP_X2   form 2: value2 is category 2                         -> DUP_X1
+//	DUP2     form 2: value1 is category 2                         -> DUP
+//	DUP2_X1  form 2: value1 is category 2                         -> DUP_X1
+//	DUP2_X2  form 2: value1 is category 2, value2 and 3 are not   -> DUP_X2
+//	DUP2_X2  form 3: value3 is category 2, value1 and 2 are not   -> DUP2_X1
+//	DUP2_X2  form 4: value1 and value2 are category 2             -> DUP_X1
+//
+// The other forms operate on category 1 values only, so they're left as they are, except
+// POP2 of two category 1 values, which has no single-byte equivalent. The interpreter
+// implements POP2 as a single pop, as javac emits it only to discard a long or a double.
+//
+// The categories are worked out by walking back from the bytecode through the bytecodes
+// checked before it in the method, using their effect on the operand stack, until the
+// bytecode that pushed the value is found. This follows the bytecodes in the order they
+// appear in the method, not the order in which they execute, so the walk gives up at an
+// unconditional jump or a return. When the category can't be worked out, the checkers
+// fall back on whether the bytecode that follows is for longs or doubles.
+
+const (
+	catUnknown = 0
+	cat1       = 1 // ints, floats, and references
+	cat2       = 2 // longs and doubles
+)
+
+// the PCs of the bytecodes checked so far in the method being checked, in order
+var checkedPCs []int
+
+// the effect of a bytecode on the operand stack
+type stackEffect struct {
+	pops   int   // the number of values popped
+	pushes []int // the categories of the values pushed, the last pushed first
+	copies []int // for DUP* and SWAP, the depth before the bytecode of each value pushed, last pushed first
+}
+
+// the layouts of the values pushed by the stack-manipulation bytecodes, in the forms that
+// operate on single-slot values
+var stackCopies = map[byte]stackEffect{
+	0x59: {pops: 1, copies: []int{0, 0}},             // DUP
+	0x5A: {pops: 2, copies: []int{0, 1, 0}},          // DUP_X1
+	0x5B: {pops: 3, copies: []int{0, 1, 2, 0}},       // DUP_X2
+	0x5C: {pops: 2, copies: []int{0, 1, 0, 1}},       // DUP2
+	0x5D: {pops: 3, copies: []int{0, 1, 2, 0, 1}},    // DUP2_X1
+	0x5E: {pops: 4, copies: []int{0, 1, 2, 3, 0, 1}}, // DUP2_X2
+	0x5F: {pops: 2, copies: []int{1, 0}},             // SWAP
+}
+
+// operandCategory returns the category of the value at the given depth of the operand
+// stack (0 is the top) before the bytecode at PC, or catUnknown if it can't be worked out
+// from the bytecodes checked before it
+func operandCategory(depth int) int {
+	next := PC
+	for i := len(checkedPCs) - 1; i >= 0; i-- {
+		pc := checkedPCs[i]
+		if pc < 0 || pc >= next || pc >= len(Code) { // not a bytecode of this method before PC
+			return catUnknown
+		}
+		next = pc
+
+		effect, ok := bytecodeStackEffect(pc)
+		if !ok {
+			return catUnknown
+		}
+		if effect.copies != nil {
+			if depth < len(effect.copies) {
+				depth = effect.copies[depth]
+			} else {
+				depth += effect.pops - len(effect.copies)
+			}
+			continue
+		}
+		if depth < len(effect.pushes) {
+			return effect.pushes[depth]
+		}
+		depth += effect.pops - len(effect.pushes)
+	}
+	return catUnknown
+}
+
+// returns the effect on the operand stack of the bytecode at pc. Returns false for the
+// bytecodes after which the values on the stack don't come from the bytecodes before them,
+// such as GOTO and the returns, and for those whose effect isn't known.
+func bytecodeStackEffect(pc int) (stackEffect, bool) {
+	opcode := Code[pc]
+	if effect, ok := stackCopies[opcode]; ok {
+		return effect, true
+	}
+
+	push := func(pops, category int) (stackEffect, bool) {
+		return stackEffect{pops: pops, pushes: []int{category}}, true
+	}
+	pop := func(pops int) (stackEffect, bool) {
+		return stackEffect{pops: pops}, true
+	}
+
+	switch {
+	case opcode == 0x00 || opcode == 0x84: // NOP, IINC
+		return pop(0)
+	case opcode <= 0x08, opcode >= 0x0B && opcode <= 0x0D: // ACONST_NULL, ICONST_*, FCONST_*
+		return push(0, cat1)
+	case opcode <= 0x0A, opcode <= 0x0F, opcode == 0x14: // LCONST_*, DCONST_*, LDC2_W
+		return push(0, cat2)
+	case opcode <= 0x13: // BIPUSH, SIPUSH, LDC, LDC_W
+		return push(0, cat1)
+	case opcode <= 0x2D: // the loads
+		if opcode == 0x16 || opcode == 0x18 || (opcode >= 0x1E && opcode <= 0x21) ||
+			(opcode >= 0x26 && opcode <= 0x29) { // LLOAD*, DLOAD*
+			return push(0, cat2)
+		}
+		return push(0, cat1)
+	case opcode <= 0x35: // the array loads
+		if opcode == 0x2F || opcode == 0x31 { // LALOAD, DALOAD
+			return push(2, cat2)
+		}
+		return push(2, cat1)
+	case opcode <= 0x4E: // the stores
+		return pop(1)
+	case opcode <= 0x56: // the array stores
+		return pop(3)
+	case opcode == 0x57: // POP
+		return pop(1)
+	case opcode == 0x58: // POP2, which pops one or two values
+		return stackEffect{}, false
+	case opcode <= 0x73: // IADD through DREM, in the order int, long, float, double
+		return push(2, categoryOfOrder(opcode-0x60))
+	case opcode <= 0x77: // INEG, LNEG, FNEG, DNEG
+		return push(1, categoryOfOrder(opcode-0x74))
+	case opcode <= 0x83: // the shifts and logical operations, alternating between int and long
+		if opcode%2 == 1 {
+			return push(2, cat2)
+		}
+		return push(2, cat1)
+	case opcode <= 0x93: // the conversions
+		switch opcode {
+		case 0x85, 0x87, 0x8A, 0x8C, 0x8D, 0x8F: // I2L, I2D, L2D, F2L, F2D, D2L
+			return push(1, cat2)
+		default:
+			return push(1, cat1)
+		}
+	case opcode <= 0x98: // LCMP, FCMPL, FCMPG, DCMPL, DCMPG
+		return push(2, cat1)
+	case opcode <= 0x9E, opcode == 0xC6 || opcode == 0xC7: // IFEQ through IFLE, IFNULL, IFNONNULL
+		return pop(1)
+	case opcode <= 0xA6: // IF_ICMP*, IF_ACMP*
+		return pop(2)
+	case opcode == 0xB2 || opcode == 0xB4: // GETSTATIC, GETFIELD
+		category := descriptorCategory(memberDescriptor(pc))
+		if category == catUnknown {
+			return stackEffect{}, false
+		}
+		if opcode == 0xB4 {
+			return push(1, category)
+		}
+		return push(0, category)
+	case opcode == 0xB3: // PUTSTATIC
+		return pop(1)
+	case opcode == 0xB5: // PUTFIELD
+		return pop(2)
+	case opcode >= 0xB6 && opcode <= 0xB9: // INVOKEVIRTUAL, INVOKESPECIAL, INVOKESTATIC, INVOKEINTERFACE
+		desc := memberDescriptor(pc)
+		if desc == "" {
+			return stackEffect{}, false
+		}
+		pops := len(util.ParseIncomingParamsFromMethTypeString(desc))
+		if opcode != 0xB8 { // all but INVOKESTATIC pop the object reference
+			pops += 1
+		}
+		if strings.HasSuffix(desc, ")V") {
+			return pop(pops)
+		}
+		return push(pops, descriptorCategory(desc))
+	case opcode == 0xBB: // NEW
+		return push(0, cat1)
+	case opcode >= 0xBC && opcode <= 0xBE, opcode == 0xC0 || opcode == 0xC1:
+		// NEWARRAY, ANEWARRAY, ARRAYLENGTH, CHECKCAST, INSTANCEOF
+		return push(1, cat1)
+	case opcode == 0xC2 || opcode == 0xC3: // MONITORENTER, MONITOREXIT
+		return pop(1)
+	case opcode == 0xC4: // WIDE, which has the effect of the bytecode it widens
+		if pc+1 >= len(Code) || Code[pc+1] == 0xA9 { // RET
+			return stackEffect{}, false
+		}
+		return bytecodeStackEffect(pc + 1)
+	case opcode == 0xC5: // MULTIANEWARRAY
+		if pc+3 >= len(Code) {
+			return stackEffect{}, false
+		}
+		return push(int(Code[pc+3]), cat1)
+	}
+	// the jumps, switches, returns, ATHROW, POP2 (whose form may not be known), and INVOKEDYNAMIC
+	return stackEffect{}, false
+}
+
+// returns the category of the result of the arithmetic bytecodes, which come in the order
+// int, long, float, double
+func categoryOfOrder(offset byte) int {
+	if offset%2 == 1 {
+		return cat2
+	}
+	return cat1
+}
+
+// returns the category of a value of the type in a field descriptor, or of the return
+// type of a method descriptor, which is catUnknown for void
+func descriptorCategory(desc string) int {
+	if strings.HasPrefix(desc, "(") {
+		desc = desc[strings.LastIndexByte(desc, ')')+1:]
+	}
+	if desc == "" {
+		return catUnknown
+	}
+	switch desc[0] {
+	case 'J', 'D':
+		return cat2
+	case 'V':
+		return catUnknown
+	default:
+		return cat1
+	}
+}
+
+// returns the descriptor of the field or method referred to by the CP index that follows
+// the bytecode at pc, or the empty string if there's no such field or method
+func memberDescriptor(pc int) string {
+	if CP == nil || pc+2 >= len(Code) {
+		return ""
+	}
+	CPslot := (int(Code[pc+1]) * 256) + int(Code[pc+2])
+	if CPslot < 1 || CPslot >= len(CP.CpIndex) {
+		return ""
+	}
+
+	entry := CP.CpIndex[CPslot]
+	var nameAndType uint16
+	switch entry.Type {
+	case FieldRef:
+		if int(entry.Slot) >= len(CP.FieldRefs) {
+			return ""
+		}
+		return CP.FieldRefs[entry.Slot].FldType
+	case MethodRef:
+		if int(entry.Slot) >= len(CP.MethodRefs) {
+			return ""
+		}
+		nameAndType = CP.MethodRefs[entry.Slot].NameAndType
+	case Interface:
+		if int(entry.Slot) >= len(CP.InterfaceRefs) {
+			return ""
+		}
+		nameAndType = CP.InterfaceRefs[entry.Slot].NameAndType
+	default:
+		return ""
+	}
+	_, desc, ok := cpNameAndType(CP, nameAndType)
+	if !ok {
+		return ""
+	}
+	return desc
+}
@@ -44,11 +44,23 @@ func TestCheckCodeValidity_NilCodePointer(t *testing.T) {
 	if err == nil {
 		t.Errorf("Expected error for nil codePtr, but got none")
 	}
-	if !strings.Contains(err.Error(), "ptr to code segment is nil") {
+	if ErrorCodeOf(err) != VfyNilCode {
 		t.Errorf("Expected specific error message, got: %s", err.Error())
 	}
 }
 
+func TestCheckCodeValidity_OpcodeBeyondTable(t *testing.T) {
+	globals.InitGlobals("test")
+
+	code := []byte{0xFE} // IMPDEP1, which is reserved and can't appear in a class file
+	cp := createBasicCP()
+
+	err := CheckCodeValidity(&code, &cp, 5, AccessFlags{})
+	if ErrorCodeOf(err) != VfyInvalidBytecode {
+		t.Errorf("Expected an invalid bytecode error, got: %v", err)
+	}
+}
+
 func TestCheckCodeValidity_EmptyCodeNonAbstract(t *testing.T) {
 	globals.InitGlobals("test")
 
@@ -60,7 +72,7 @@ func TestCheckCodeValidity_EmptyCodeNonAbstract(t *testing.T) {
 	if err == nil {
 		t.Errorf("Expected error for empty code in non-abstract class, but got none")
 	}
-	if !strings.Contains(err.Error(), "Empty code segment") {
+	if ErrorCodeOf(err) != VfyEmptyCode {
 		t.Errorf("Expected specific error message, got: %s", err.Error())
 	}
 }
@@ -87,7 +99,7 @@ func TestCheckCodeValidity_NilConstantPool(t *testing.T) {
 	if err == nil {
 		t.Errorf("Expected error for nil constant pool, but got none")
 	}
-	if !strings.Contains(err.Error(), "ptr to constant pool is nil") {
+	if ErrorCodeOf(err) != VfyNilCP {
 		t.Errorf("Expected specific error message, got: %s", err.Error())
 	}
 }
@@ -103,7 +115,7 @@ func TestCheckCodeValidity_EmptyConstantPool(t *testing.T) {
 	if err == nil {
 		t.Errorf("Expected error for empty constant pool, but got none")
 	}
-	if !strings.Contains(err.Error(), "empty constant pool") {
+	if ErrorCodeOf(err) != VfyEmptyCP {
 		t.Errorf("Expected specific error message, got: %s", err.Error())
 	}
 }
@@ -133,7 +145,7 @@ func TestCheckCodeValidity_InvalidBytecodeLength(t *testing.T) {
 	if err == nil {
 		t.Errorf("Expected error for invalid bytecode length, but got none")
 	}
-	if !strings.Contains(err.Error(), "Invalid bytecode or argument") {
+	if ErrorCodeOf(err) != VfyInvalidBytecode {
 		t.Errorf("Expected specific error message, got: %s", err.Error())
 	}
 }
@@ -388,6 +400,94 @@ func TestDup2_RegularOperation(t *testing.T) {
 	}
 }
 
+// the category 2 forms of the stack-manipulation bytecodes, which are converted to the bytecodes
+// that do the same to Jacobin's single-slot longs and doubles (see codeCheckCategories.go)
+func TestStackBytecodeForms(t *testing.T) {
+	globals.InitGlobals("test")
+
+	tests := []struct {
+		name     string
+		code     []byte
+		at       int  // the PC of the bytecode that's checked
+		expected byte // what it's converted to
+	}{
+		{"DUP2 of a long", []byte{opcodes.LLOAD_0, opcodes.DUP2}, 1, opcodes.DUP},
+		{"DUP2 of two ints", []byte{opcodes.ILOAD_0, opcodes.ILOAD_1, opcodes.DUP2}, 2, opcodes.DUP2},
+		{"DUP2 of a computed long", []byte{opcodes.LCONST_1, opcodes.LCONST_1, opcodes.LADD, opcodes.DUP2}, 3, opcodes.DUP},
+		{"DUP2 of a double after WIDE IINC",
+			[]byte{opcodes.WIDE, opcodes.IINC, 0x00, 0x04, 0xFF, 0xFF, opcodes.DLOAD_0, opcodes.DUP2}, 7, opcodes.DUP},
+		{"DUP_X2 over a long", []byte{opcodes.LLOAD_0, opcodes.ILOAD_2, opcodes.DUP_X2}, 2, opcodes.DUP_X1},
+		{"DUP_X2 over two ints", []byte{opcodes.ILOAD_0, opcodes.ILOAD_1, opcodes.ILOAD_2, opcodes.DUP_X2}, 3, opcodes.DUP_X2},
+		{"DUP2_X1 of a long", []byte{opcodes.ILOAD_0, opcodes.LLOAD_1, opcodes.DUP2_X1}, 2, opcodes.DUP_X1},
+		{"DUP2_X1 of two ints", []byte{opcodes.ILOAD_0, opcodes.ILOAD_1, opcodes.ILOAD_2, opcodes.DUP2_X1}, 3, opcodes.DUP2_X1},
+		{"DUP2_X2 of a long over two ints", // as in long[] a; a[i]++
+			[]byte{opcodes.ALOAD_0, opcodes.ILOAD_1, opcodes.DUP2, opcodes.LALOAD, opcodes.DUP2_X2}, 4, opcodes.DUP_X2},
+		{"DUP2_X2 of two ints over a long",
+			[]byte{opcodes.LLOAD_0, opcodes.ILOAD_2, opcodes.ILOAD_3, opcodes.DUP2_X2}, 3, opcodes.DUP2_X1},
+		{"DUP2_X2 of a long over a long", []byte{opcodes.LLOAD_0, opcodes.DLOAD_2, opcodes.DUP2_X2}, 2, opcodes.DUP_X1},
+		{"DUP2_X2 of four ints",
+			[]byte{opcodes.ILOAD_0, opcodes.ILOAD_1, opcodes.ILOAD_2, opcodes.ILOAD_3, opcodes.DUP2_X2}, 4, opcodes.DUP2_X2},
+		{"DUP2 of two ints after a long was duplicated and popped",
+			[]byte{opcodes.ILOAD_0, opcodes.LLOAD_1, opcodes.DUP2_X1, opcodes.POP2, opcodes.DUP2}, 4, opcodes.DUP2},
+		{"POP2 of a long", []byte{opcodes.LLOAD_0, opcodes.POP2}, 1, opcodes.POP},
+		{"POP2 of two ints", []byte{opcodes.ILOAD_0, opcodes.ILOAD_1, opcodes.POP2}, 2, opcodes.POP2},
+	}
+
+	for _, test := range tests {
+		code := append([]byte{}, test.code...)
+		cp := createBasicCP()
+		err := CheckCodeValidity(&code, &cp, 10, AccessFlags{})
+		if err != nil {
+			t.Errorf("%s: CheckCodeValidity failed: %v", test.name, err)
+			continue
+		}
+		if code[test.at] != test.expected {
+			t.Errorf("%s: expected 0x%X at PC %d, got: 0x%X", test.name, test.expected, test.at, code[test.at])
+		}
+	}
+}
+
+// DUP2_X1 of a long field, as in obj.l++, whose category comes from the field's type
+func TestDup2x1_LongField(t *testing.T) {
+	globals.InitGlobals("test")
+
+	cp := createBasicCP()
+	cp.CpIndex[1] = CpEntry{Type: FieldRef, Slot: 0}
+	cp.FieldRefs = []ResolvedFieldEntry{{ClName: "T", FldName: "l", FldType: "J"}}
+	code := []byte{opcodes.ALOAD_0, opcodes.DUP, opcodes.GETFIELD, 0x00, 0x01, opcodes.DUP2_X1}
+
+	err := CheckCodeValidity(&code, &cp, 5, AccessFlags{})
+	if err != nil {
+		t.Fatalf("CheckCodeValidity failed: %v", err)
+	}
+	if code[5] != opcodes.DUP_X1 {
+		t.Errorf("Expected DUP2_X1 to be converted to DUP_X1, got: 0x%X", code[5])
+	}
+}
+
+// WIDE is checked together with the bytecode it widens
+func TestCheckWide(t *testing.T) {
+	globals.InitGlobals("test")
+
+	tests := []struct {
+		code     []byte
+		expected int
+	}{
+		{[]byte{opcodes.WIDE, opcodes.ILOAD, 0x01, 0x00}, 4},
+		{[]byte{opcodes.WIDE, opcodes.DSTORE, 0x01, 0x00}, 4},
+		{[]byte{opcodes.WIDE, opcodes.IINC, 0x01, 0x00, 0xFF, 0xFE}, 6},
+		{[]byte{opcodes.WIDE, opcodes.IADD}, ERROR_OCCURRED},
+		{[]byte{opcodes.WIDE}, ERROR_OCCURRED},
+	}
+	for _, test := range tests {
+		Code = test.code
+		PC = 0
+		if result := CheckWide(); result != test.expected {
+			t.Errorf("WIDE 0x%X: expected %d, got: %d", test.code[1:], test.expected, result)
+		}
+	}
+}
+
 // FCONST_0
 
 func TestPushFloat0_HighLevel(t *testing.T) {
@@ -536,7 +636,7 @@ func TestCodeCheckGetfield(t *testing.T) {
 
 	errMsg := string(msg)
 
-	if !strings.Contains(errMsg, "java.lang.VerifyError") || !strings.Contains(errMsg, "not a field reference") {
+	if !strings.Contains(errMsg, "java.lang.VerifyError") || !strings.Contains(errMsg, string(VfyNotFieldRef)) {
 		t.Errorf("GETFIELD: Did not get expected error message, got: %s", errMsg)
 	}
 }
@@ -946,7 +1046,7 @@ func TestNewInvokevirtualInvalidMethRef(t *testing.T) {
 		t.Errorf("INVOKEVIRTUAL: Expected error but did not get one.")
 	}
 
-	if !strings.Contains(errMsg, "java.lang.VerifyError") || !strings.Contains(errMsg, "not a method reference") {
+	if !strings.Contains(errMsg, "java.lang.VerifyError") || !strings.Contains(errMsg, string(VfyNotMethodRef)) {
 		t.Errorf("INVOKEVIRTUAL: Did not get expected error message, got:\n %s", errMsg)
 	}
 }
@@ -1431,3 +1531,34 @@ func TestCheckTableswitch_InvalidRange(t *testing.T) {
 		t.Errorf("Expected error for invalid TABLESWITCH range, but got none")
 	}
 }
+
+func TestBytecodeLength(t *testing.T) {
+	tableswitch := []byte{opcodes.NOP, opcodes.TABLESWITCH, 0, 0, // padding to offset 4
+		0, 0, 0, 20, 0, 0, 0, 1, 0, 0, 0, 2, // default, low = 1, high = 2
+		0, 0, 0, 24, 0, 0, 0, 28} // two jump offsets
+	lookupswitch := []byte{opcodes.LOOKUPSWITCH, 0, 0, 0, // padding to offset 4
+		0, 0, 0, 20, 0, 0, 0, 1, // default, npairs = 1
+		0, 0, 0, 9, 0, 0, 0, 24} // one match-offset pair
+
+	tests := []struct {
+		name     string
+		code     []byte
+		pc       int
+		expected int
+	}{
+		{"ALOAD_0", []byte{opcodes.ALOAD_0}, 0, 1},
+		{"GETFIELD", []byte{opcodes.GETFIELD, 0, 1}, 0, 3},
+		{"INVOKEINTERFACE", []byte{opcodes.INVOKEINTERFACE, 0, 1, 1, 0}, 0, 5},
+		{"TABLESWITCH", tableswitch, 1, 23},
+		{"LOOKUPSWITCH", lookupswitch, 0, 20},
+		{"WIDE ILOAD", []byte{opcodes.WIDE, opcodes.ILOAD, 1, 0}, 0, 4},
+		{"WIDE IINC", []byte{opcodes.WIDE, opcodes.IINC, 1, 0, 0, 5}, 0, 6},
+		{"truncated GETFIELD", []byte{opcodes.GETFIELD, 0}, 0, 0},
+		{"PC past the end", []byte{opcodes.NOP}, 1, 0},
+	}
+	for _, test := range tests {
+		if length := BytecodeLength(test.code, test.pc); length != test.expected {
+			t.Errorf("%s: expected length %d, got %d", test.name, test.expected, length)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package classloader
+
+import (
+	"jacobin/src/stringPool"
+)
+
+// Constant pool compaction. Most of the UTF-8 entries in a class's CP are names and
+// descriptors that other classes' CPs repeat: "<init>", "()V", "java/lang/Object",
+// "Code", "LineNumberTable", and so on. By default, each loaded class keeps its own copy
+// of these strings. When -XX:+CompactConstantPools is specified, each UTF-8 entry is
+// replaced by the copy of the string in the string pool, so that all the classes share
+// a single copy of each string, and the slice of entries is trimmed to its length.
+// The entries keep their values, so the code that reads Utf8Refs is unaffected.
+
+// CompactCP replaces the UTF-8 strings of the CP with the string pool's copies of them
+func CompactCP(cp *CPool) {
+	if len(cp.Utf8Refs) == 0 {
+		return
+	}
+	indices := stringPool.GetStringIndexBatch(cp.Utf8Refs)
+	utf8Refs := make([]string, len(cp.Utf8Refs))
+	for i, index := range indices {
+		utf8Refs[i] = *stringPool.GetStringPointer(index)
+	}
+	cp.Utf8Refs = utf8Refs
+}
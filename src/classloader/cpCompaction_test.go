@@ -0,0 +1,53 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package classloader
+
+import (
+	"jacobin/src/globals"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+// two CPs with the same strings, built separately so that they don't share them
+func makeCPsWithSameStrings() (CPool, CPool) {
+	var cp1, cp2 CPool
+	for _, s := range []string{"<init>", "()V", "java/lang/Object", "Code"} {
+		cp1.Utf8Refs = append(cp1.Utf8Refs, strings.Clone(s))
+		cp2.Utf8Refs = append(cp2.Utf8Refs, strings.Clone(s))
+	}
+	return cp1, cp2
+}
+
+func TestCompactCPSharesStrings(t *testing.T) {
+	globals.InitGlobals("test")
+	cp1, cp2 := makeCPsWithSameStrings()
+	if unsafe.StringData(cp1.Utf8Refs[0]) == unsafe.StringData(cp2.Utf8Refs[0]) {
+		t.Fatalf("the test CPs share their strings before compaction")
+	}
+
+	CompactCP(&cp1)
+	CompactCP(&cp2)
+	for i := range cp1.Utf8Refs {
+		if cp1.Utf8Refs[i] != cp2.Utf8Refs[i] {
+			t.Errorf("entry %d changed: %q vs. %q", i, cp1.Utf8Refs[i], cp2.Utf8Refs[i])
+		}
+		if unsafe.StringData(cp1.Utf8Refs[i]) != unsafe.StringData(cp2.Utf8Refs[i]) {
+			t.Errorf("entry %d (%s) is not shared after compaction", i, cp1.Utf8Refs[i])
+		}
+	}
+	if len(cp1.Utf8Refs) != 4 || cap(cp1.Utf8Refs) != 4 {
+		t.Errorf("expected the compacted entries to have length and capacity 4, got %d and %d",
+			len(cp1.Utf8Refs), cap(cp1.Utf8Refs))
+	}
+
+	var empty CPool
+	CompactCP(&empty)
+	if empty.Utf8Refs != nil {
+		t.Errorf("compacting an empty CP added entries")
+	}
+}
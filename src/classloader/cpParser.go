@@ -259,6 +259,7 @@ func parseConstantPool(rawBytes []byte, klass *ParsedClass) (int, error) {
 	}
 
 	// resolve the classRefs to use indices into the string pool
+	classNames := make([]string, len(tempClassRefs))
 	for i = 0; i < len(tempClassRefs); i++ {
 		index := tempClassRefs[i]
 		if index == 0 || index > (len(klass.cpIndex)-1) {
@@ -266,10 +267,9 @@ func parseConstantPool(rawBytes []byte, klass *ParsedClass) (int, error) {
 		}
 
 		h := klass.cpIndex[index].slot
-		str := klass.utf8Refs[h].content
-		strIndex := stringPool.GetStringIndex(&str)
-		klass.classRefs = append(klass.classRefs, strIndex)
+		classNames[i] = klass.utf8Refs[h].content
 	}
+	klass.classRefs = append(klass.classRefs, stringPool.GetStringIndexBatch(classNames)...)
 
 	return pos, nil
 }
@@ -12,6 +12,9 @@ import (
 	"jacobin/src/globals"
 	"jacobin/src/stringPool"
 	"jacobin/src/trace"
+	"jacobin/src/types"
+	"strings"
+	"sync/atomic"
 )
 
 // ResolveCPmethRefs resolves the method references in the constant pool of a class
@@ -22,7 +25,9 @@ func ResolveCPinterfaceRefs(cpp *CPool) error {
 	}
 
 	for _, interfaceEntry := range cp.InterfaceRefs {
-		resEntry := ResolvedInterfaceRefEntry{}
+		// filled in place: the entry holds an atomic memo, so it must not be copied
+		cpp.ResolvedInterfaceRefs = append(cpp.ResolvedInterfaceRefs, ResolvedInterfaceRefEntry{})
+		resEntry := &cpp.ResolvedInterfaceRefs[len(cpp.ResolvedInterfaceRefs)-1]
 		// get the class name as an index into the string pool
 		classIndex := interfaceEntry.ClassIndex
 		classRefIdx := cp.CpIndex[classIndex].Slot
@@ -48,7 +53,6 @@ func ResolveCPinterfaceRefs(cpp *CPool) error {
 		fqn := *stringPool.GetStringPointer(resEntry.ClassIndex) + "." + methName + methSig
 		resEntry.FQNameIndex = stringPool.GetStringIndex(&fqn)
 
-		cpp.ResolvedInterfaceRefs = append(cpp.ResolvedInterfaceRefs, resEntry)
 		if globals.TraceClass {
 			msg := fmt.Sprintf("ResolveCPinterfaceRefs: Resolved interface ref: %s\n", fqn)
 			trace.Trace(msg)
@@ -65,7 +69,9 @@ func ResolveCPmethRefs(cpp *CPool) error {
 	}
 
 	for _, methEntry := range cp.MethodRefs {
-		resEntry := ResolvedMethodRefEntry{}
+		// filled in place: the entry holds an atomic memo, so it must not be copied
+		cpp.ResolvedMethodRefs = append(cpp.ResolvedMethodRefs, ResolvedMethodRefEntry{})
+		resEntry := &cpp.ResolvedMethodRefs[len(cpp.ResolvedMethodRefs)-1]
 		// get the class name as an index into the string pool
 		classIndex := methEntry.ClassIndex
 		classRefIdx := cp.CpIndex[classIndex].Slot
@@ -91,7 +97,6 @@ func ResolveCPmethRefs(cpp *CPool) error {
 		fqn := *stringPool.GetStringPointer(resEntry.ClassIndex) + "." + methName + methSig
 		resEntry.FQNameIndex = stringPool.GetStringIndex(&fqn)
 
-		cpp.ResolvedMethodRefs = append(cpp.ResolvedMethodRefs, resEntry)
 		if globals.TraceClass {
 			msg := fmt.Sprintf("ResolveCPmethRefs: Resolved method ref: %s\n", fqn)
 			trace.Trace(msg)
@@ -100,6 +105,146 @@ func ResolveCPmethRefs(cpp *CPool) error {
 	return nil
 }
 
+// The following functions link CP entries to the runtime items they refer to: ClassRefs to
+// their classes, FieldRefs to the declaring class and the field's slot in it, and MethodRefs
+// and InterfaceRefs to their MTable entries. Linking is done lazily, the first time an entry
+// is used, and the result is memoized in the CP, so that subsequent executions of the same
+// bytecode avoid the string-based lookups in the method area and the MTable.
+
+// ResolveClassRef returns the class referred to by the ClassRef at the CP index, loading the
+// class if need be. Array classes have no entry in the method area and so return an error.
+func ResolveClassRef(cp *CPool, cpIndex int) (*Klass, error) {
+	if cp == nil || cpIndex < 1 || cpIndex >= len(cp.CpIndex) {
+		return nil, fmt.Errorf("ResolveClassRef: invalid CP index %d", cpIndex)
+	}
+	entry := cp.CpIndex[cpIndex]
+	if entry.Type != ClassRef || int(entry.Slot) >= len(cp.ClassRefs) {
+		return nil, fmt.Errorf("ResolveClassRef: CP entry %d is not a class reference", cpIndex)
+	}
+
+	if int(entry.Slot) < len(cp.ResolvedClasses) && cp.ResolvedClasses[entry.Slot] != nil {
+		return cp.ResolvedClasses[entry.Slot], nil
+	}
+
+	className := *stringPool.GetStringPointer(cp.ClassRefs[entry.Slot])
+	if strings.HasPrefix(className, types.Array) {
+		return nil, fmt.Errorf("ResolveClassRef: %s is an array class", className)
+	}
+
+	k := MethAreaFetch(className)
+	if k == nil {
+		if err := LoadClassFromNameOnly(className); err != nil {
+			return nil, fmt.Errorf("ResolveClassRef: could not load class %s: %s", className, err.Error())
+		}
+		k = MethAreaFetch(className)
+		if k == nil {
+			return nil, fmt.Errorf("ResolveClassRef: class %s not found in the method area", className)
+		}
+	}
+
+	if len(cp.ResolvedClasses) < len(cp.ClassRefs) {
+		cp.ResolvedClasses = append(cp.ResolvedClasses,
+			make([]*Klass, len(cp.ClassRefs)-len(cp.ResolvedClasses))...)
+	}
+	cp.ResolvedClasses[entry.Slot] = k
+	return k, nil
+}
+
+// ResolveFieldRef links the FieldRef at the CP index to the class that declares the field,
+// which might be a superclass of the class named in the FieldRef, and to the field's slot
+// in that class's Fields. It returns the FieldRef, whose Class and Slot are then set.
+func ResolveFieldRef(cp *CPool, cpIndex int) (*ResolvedFieldEntry, error) {
+	if cp == nil || cpIndex < 1 || cpIndex >= len(cp.CpIndex) {
+		return nil, fmt.Errorf("ResolveFieldRef: invalid CP index %d", cpIndex)
+	}
+	entry := cp.CpIndex[cpIndex]
+	if entry.Type != FieldRef || int(entry.Slot) >= len(cp.FieldRefs) {
+		return nil, fmt.Errorf("ResolveFieldRef: CP entry %d is not a field reference", cpIndex)
+	}
+
+	fieldRef := &cp.FieldRefs[entry.Slot]
+	if fieldRef.Class != nil {
+		return fieldRef, nil
+	}
+
+	if MethAreaFetch(fieldRef.ClName) == nil {
+		if err := LoadClassFromNameOnly(fieldRef.ClName); err != nil {
+			return nil, fmt.Errorf("ResolveFieldRef: could not load class %s: %s", fieldRef.ClName, err.Error())
+		}
+	}
+
+	owner, fld := findField(fieldRef.ClName, fieldRef.FldName)
+	if fld == nil {
+		return nil, fmt.Errorf("ResolveFieldRef: field %s.%s not found", fieldRef.ClName, fieldRef.FldName)
+	}
+	k := MethAreaFetch(owner)
+	for i := range k.Data.Fields {
+		if &k.Data.Fields[i] == fld {
+			fieldRef.Slot = i
+			break
+		}
+	}
+	fieldRef.AccessFlags = fld.AccessFlags
+	fieldRef.IsStatic = fld.IsStatic
+	fieldRef.Class = k
+	return fieldRef, nil
+}
+
+// ResolveMethodRef returns the MTable entry of the method referred to by the MethodRef or
+// InterfaceRef at the CP index, searching the superclasses, if need be, as FetchMethodAndCP()
+// does. An entry is memoized only if the method is found. As arrays inherit their methods,
+// such as clone(), from java/lang/Object, methods of array classes are looked up there.
+func ResolveMethodRef(cp *CPool, cpIndex int) (MTentry, error) {
+	if cp == nil || cpIndex < 1 || cpIndex >= len(cp.CpIndex) {
+		return MTentry{}, fmt.Errorf("ResolveMethodRef: invalid CP index %d", cpIndex)
+	}
+
+	var className, methName, methType string
+	var memo *atomic.Pointer[MTentry]
+	entry := cp.CpIndex[cpIndex]
+	switch entry.Type {
+	case MethodRef:
+		if int(entry.Slot) >= len(cp.ResolvedMethodRefs) {
+			return MTentry{}, fmt.Errorf("ResolveMethodRef: CP entry %d is not a resolved method reference", cpIndex)
+		}
+		memo = &cp.ResolvedMethodRefs[entry.Slot].Method
+		if m := memo.Load(); m != nil {
+			return *m, nil
+		}
+		className, methName, methType, _ = GetMethInfoFromCPmethref(cp, cpIndex)
+	case Interface:
+		var err error
+		className, methName, methType, _, err = GetIfaceMethInfoFromCPref(cp, cpIndex)
+		if err != nil {
+			return MTentry{}, err
+		}
+		memo = &cp.ResolvedInterfaceRefs[entry.Slot].Method
+		if m := memo.Load(); m != nil {
+			return *m, nil
+		}
+	default:
+		return MTentry{}, fmt.Errorf("ResolveMethodRef: CP entry %d is not a method reference", cpIndex)
+	}
+
+	if strings.HasPrefix(className, types.Array) {
+		className = types.ObjectClassName
+	}
+
+	mtEntry := MTable[className+"."+methName+methType]
+	if mtEntry.Meth == nil {
+		var err error
+		mtEntry, err = FetchMethodAndCP(className, methName, methType)
+		if err != nil {
+			return MTentry{}, err
+		}
+	}
+
+	if mtEntry.Meth != nil {
+		memo.Store(&mtEntry)
+	}
+	return mtEntry, nil
+}
+
 /*
 methodRef := CP.CpIndex[cpIndex].Slot
 	classIndex := CP.MethodRefs[methodRef].ClassIndex
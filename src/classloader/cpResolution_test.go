@@ -0,0 +1,167 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package classloader
+
+import (
+	"fmt"
+	"jacobin/src/globals"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"sync"
+	"testing"
+)
+
+// loads link/Base, which declares the field count, and link/Sub, which extends it,
+// into the method area, and returns a CP whose entries refer to them:
+//
+//	1: ClassRef link/Sub    2: ClassRef [I          3: FieldRef link/Sub.count
+//	4: FieldRef link/Sub.missing    5: MethodRef link/Sub.run()V    6: IntConst
+func setupLinkTestCP() *CPool {
+	globals.InitGlobals("test")
+	InitMethodArea()
+
+	base := "link/Base"
+	sub := "link/Sub"
+	intArray := "[I"
+	object := types.ObjectClassName
+	MethAreaInsert(base, &Klass{Status: 'F', Loader: "testloader", Data: &ClData{
+		Name:            base,
+		SuperclassIndex: stringPool.GetStringIndex(&object),
+		Fields: []Field{
+			{NameStr: "other", DescStr: "J"},
+			{AccessFlags: AccPublic, NameStr: "count", DescStr: "I"},
+		},
+	}})
+	MethAreaInsert(sub, &Klass{Status: 'F', Loader: "testloader", Data: &ClData{
+		Name:            sub,
+		SuperclassIndex: stringPool.GetStringIndex(&base),
+	}})
+
+	run, runType := "run", "()V"
+	fqn := sub + "." + run + runType
+	cp := CPool{}
+	cp.CpIndex = []CpEntry{
+		{Type: 0, Slot: 0}, // mandatory dummy entry
+		{Type: ClassRef, Slot: 0},
+		{Type: ClassRef, Slot: 1},
+		{Type: FieldRef, Slot: 0},
+		{Type: FieldRef, Slot: 1},
+		{Type: MethodRef, Slot: 0},
+		{Type: IntConst, Slot: 0},
+	}
+	cp.ClassRefs = []uint32{stringPool.GetStringIndex(&sub), stringPool.GetStringIndex(&intArray)}
+	cp.FieldRefs = []ResolvedFieldEntry{
+		{ClName: sub, FldName: "count", FldType: "I"},
+		{ClName: sub, FldName: "missing", FldType: "I"},
+	}
+	cp.MethodRefs = []MethodRefEntry{{}}
+	cp.ResolvedMethodRefs = []ResolvedMethodRefEntry{{
+		ClassIndex:  stringPool.GetStringIndex(&sub),
+		NameIndex:   stringPool.GetStringIndex(&run),
+		TypeIndex:   stringPool.GetStringIndex(&runType),
+		FQNameIndex: stringPool.GetStringIndex(&fqn),
+	}}
+	cp.IntConsts = []int32{1}
+	return &cp
+}
+
+func TestResolveClassRef(t *testing.T) {
+	cp := setupLinkTestCP()
+
+	k, err := ResolveClassRef(cp, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if k != MethAreaFetch("link/Sub") {
+		t.Errorf("Expected the class link/Sub, got: %v", k)
+	}
+	if len(cp.ResolvedClasses) != 2 || cp.ResolvedClasses[0] != k {
+		t.Errorf("Expected the class to be memoized in the CP, got: %v", cp.ResolvedClasses)
+	}
+
+	for _, index := range []int{0, 2, 6, 7} {
+		if _, err = ResolveClassRef(cp, index); err == nil {
+			t.Errorf("Expected an error for CP index %d, got none", index)
+		}
+	}
+}
+
+func TestResolveFieldRef(t *testing.T) {
+	cp := setupLinkTestCP()
+
+	fieldRef, err := ResolveFieldRef(cp, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fieldRef.Class != MethAreaFetch("link/Base") || fieldRef.Slot != 1 {
+		t.Errorf("Expected field count in slot 1 of link/Base, got slot %d of %v", fieldRef.Slot, fieldRef.Class)
+	}
+	if fieldRef.AccessFlags != AccPublic || fieldRef.FldType != "I" || fieldRef != &cp.FieldRefs[0] {
+		t.Errorf("Unexpected resolved field: %v", *fieldRef)
+	}
+
+	for _, index := range []int{4, 5, 7} {
+		if _, err = ResolveFieldRef(cp, index); err == nil {
+			t.Errorf("Expected an error for CP index %d, got none", index)
+		}
+	}
+}
+
+func TestResolveMethodRef(t *testing.T) {
+	cp := setupLinkTestCP()
+	AddEntry(&MTable, "link/Sub.run()V", MTentry{Meth: JmEntry{MaxStack: 3}, MType: 'J'})
+
+	mt, err := ResolveMethodRef(cp, 5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if mt.MType != 'J' || mt.Meth.(JmEntry).MaxStack != 3 {
+		t.Errorf("Unexpected MTable entry: %v", mt)
+	}
+
+	// once memoized, the entry no longer depends on the MTable
+	delete(MTable, "link/Sub.run()V")
+	mt, err = ResolveMethodRef(cp, 5)
+	if err != nil || mt.Meth == nil || mt.Meth.(JmEntry).MaxStack != 3 {
+		t.Errorf("Expected the memoized MTable entry, got: %v, %v", mt, err)
+	}
+
+	for _, index := range []int{0, 1, 3, 7} {
+		if _, err = ResolveMethodRef(cp, index); err == nil {
+			t.Errorf("Expected an error for CP index %d, got none", index)
+		}
+	}
+}
+
+func TestResolveMethodRefConcurrently(t *testing.T) {
+	cp := setupLinkTestCP()
+	AddEntry(&MTable, "link/Sub.run()V", MTentry{Meth: JmEntry{MaxStack: 3}, MType: 'J'})
+	defer delete(MTable, "link/Sub.run()V")
+
+	// run with -race: every goroutine resolves, and so memoizes, the same entry
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mt, err := ResolveMethodRef(cp, 5)
+			if err == nil && (mt.MType != 'J' || mt.Meth.(JmEntry).MaxStack != 3) {
+				err = fmt.Errorf("unexpected MTable entry: %v", mt)
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
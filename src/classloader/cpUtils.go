@@ -11,6 +11,7 @@ package classloader
 // here to avoid circular dependencies.
 
 import (
+	"fmt"
 	"jacobin/src/stringPool"
 )
 
@@ -19,17 +20,10 @@ type CpType struct {
 	RetType   int
 	IntVal    int64
 	FloatVal  float64
-	AddrVal   *CPuint16s
+	StructPtr interface{} // a typed pointer to the entry in the CP, such as *MethodRefEntry
 	StringVal *string
 }
 
-// all the multi-value structs use this layout. Depending on the type of
-// the CPentry, the fields entry1 and entry2 will have different meanings.
-type CPuint16s struct {
-	entry1 uint16
-	entry2 uint16
-}
-
 var IS_ERROR = 0
 var IS_STRUCT_ADDR = 1
 var IS_FLOAT64 = 2
@@ -51,9 +45,13 @@ var IS_STRING_ADDR = 4
 //     2 = float64
 //     3 = int64
 //     4 = address of string
-//  3. three fields that hold an int64, float64, or 64-bit address, respectively.
-//     The calling function checks the RetType field to determine which
-//     of these three fields holds the returned value.
+//  3. four fields that hold an int64, float64, typed pointer to a struct, or
+//     the address of a string, respectively. The calling function checks the
+//     RetType field to determine which of these four fields holds the returned
+//     value. Struct entries are returned as pointers to the entries in the CP,
+//     such as *DynamicEntry or *NameAndTypeEntry, never as raw addresses, so that
+//     the garbage collector always sees them. Entries that must be linked to
+//     classes, fields, or methods are resolved by the functions in cpResolution.go.
 func FetchCPentry(cpp *CPool, index int) CpType {
 	if cpp == nil {
 		return CpType{EntryType: 0, RetType: IS_ERROR}
@@ -118,52 +116,28 @@ func FetchCPentry(cpp *CPool, index int) CpType {
 
 	// addresses of structures or other elements
 	case Dynamic:
-		dyn := cp.Dynamics[entry.Slot]
-		cpe := CPuint16s{
-			entry1: dyn.BootstrapIndex,
-			entry2: dyn.NameAndType,
-		}
-		return CpType{EntryType: int(entry.Type), RetType: IS_STRUCT_ADDR, AddrVal: &cpe}
+		return CpType{EntryType: int(entry.Type), RetType: IS_STRUCT_ADDR,
+			StructPtr: &cpp.Dynamics[entry.Slot]}
 
 	case Interface:
-		iface := cp.InterfaceRefs[entry.Slot]
-		cpe := CPuint16s{
-			entry1: iface.ClassIndex,
-			entry2: iface.NameAndType,
-		}
-		return CpType{EntryType: int(entry.Type), RetType: IS_STRUCT_ADDR, AddrVal: &cpe}
+		return CpType{EntryType: int(entry.Type), RetType: IS_STRUCT_ADDR,
+			StructPtr: &cpp.InterfaceRefs[entry.Slot]}
 
 	case InvokeDynamic:
-		idyn := cp.InvokeDynamics[entry.Slot]
-		cpe := CPuint16s{
-			entry1: idyn.BootstrapIndex,
-			entry2: idyn.NameAndType,
-		}
-		return CpType{EntryType: int(entry.Type), RetType: IS_STRUCT_ADDR, AddrVal: &cpe}
+		return CpType{EntryType: int(entry.Type), RetType: IS_STRUCT_ADDR,
+			StructPtr: &cpp.InvokeDynamics[entry.Slot]}
 
 	case MethodHandle:
-		mh := cp.MethodHandles[entry.Slot]
-		cpe := CPuint16s{
-			entry1: mh.RefKind,
-			entry2: mh.RefIndex,
-		}
-		return CpType{EntryType: int(entry.Type), RetType: IS_STRUCT_ADDR, AddrVal: &cpe}
+		return CpType{EntryType: int(entry.Type), RetType: IS_STRUCT_ADDR,
+			StructPtr: &cpp.MethodHandles[entry.Slot]}
 
 	case MethodRef:
-		mr := cp.MethodRefs[entry.Slot]
-		cpe := CPuint16s{
-			entry1: mr.ClassIndex,
-			entry2: mr.NameAndType,
-		}
-		return CpType{EntryType: int(entry.Type), RetType: IS_STRUCT_ADDR, AddrVal: &cpe}
+		return CpType{EntryType: int(entry.Type), RetType: IS_STRUCT_ADDR,
+			StructPtr: &cpp.MethodRefs[entry.Slot]}
 
 	case NameAndType:
-		nat := cp.NameAndTypes[entry.Slot]
-		cpe := CPuint16s{
-			entry1: nat.NameIndex,
-			entry2: nat.DescIndex,
-		}
-		return CpType{EntryType: int(entry.Type), RetType: IS_STRUCT_ADDR, AddrVal: &cpe}
+		return CpType{EntryType: int(entry.Type), RetType: IS_STRUCT_ADDR,
+			StructPtr: &cpp.NameAndTypes[entry.Slot]}
 
 	// error: name of module or package would
 	// not normally be retrieved here
@@ -181,7 +155,7 @@ func FetchCPentry(cpp *CPool, index int) CpType {
 // in codeCheck.go.
 func GetMethInfoFromCPmethref(CP *CPool, cpIndex int) (string, string, string, string) {
 	cp := *CP
-	meth := cp.ResolvedMethodRefs[cp.CpIndex[cpIndex].Slot]
+	meth := &cp.ResolvedMethodRefs[cp.CpIndex[cpIndex].Slot]
 	cls := *stringPool.GetStringPointer(meth.ClassIndex)
 	mth := *stringPool.GetStringPointer(meth.NameIndex)
 	typ := *stringPool.GetStringPointer(meth.TypeIndex)
@@ -189,31 +163,109 @@ func GetMethInfoFromCPmethref(CP *CPool, cpIndex int) (string, string, string, s
 	return cls, mth, typ, fqn
 }
 
+// GetMethInfoFromCPinterfaceRef receives a CP entry index that points to an interface
+// method reference and returns the class name, method name, and method signature. If
+// the entry can't be resolved, the strings are empty. See GetIfaceMethInfoFromCPref().
 func GetMethInfoFromCPinterfaceRef(CP *CPool, cpIndex int) (string, string, string) {
+	className, methName, methSig, _, _ := GetIfaceMethInfoFromCPref(CP, cpIndex)
+	return className, methName, methSig
+}
 
-	methodRef := CP.CpIndex[cpIndex].Slot
-	classIndex := CP.InterfaceRefs[methodRef].ClassIndex
+// GetIfaceMethInfoFromCPref receives a CP entry index that points to an interface method
+// reference and returns the class name, method name, method signature, and the three
+// combined as a fully qualified name (FQN). The entry is resolved the first time it's
+// requested, if the class loader has not already resolved it, and the resolved form is
+// kept in the CP's ResolvedInterfaceRefs. Every index is checked, and an error is returned
+// if the entry is not an interface method reference or any part of it is invalid.
+func GetIfaceMethInfoFromCPref(CP *CPool, cpIndex int) (string, string, string, string, error) {
+	if CP == nil || cpIndex < 1 || cpIndex >= len(CP.CpIndex) {
+		return "", "", "", "", fmt.Errorf("GetIfaceMethInfoFromCPref: invalid CP index %d", cpIndex)
+	}
+	entry := CP.CpIndex[cpIndex]
+	if entry.Type != Interface || int(entry.Slot) >= len(CP.InterfaceRefs) {
+		return "", "", "", "", fmt.Errorf(
+			"GetIfaceMethInfoFromCPref: CP entry %d is not an interface method reference", cpIndex)
+	}
+
+	// the FQN is never the empty string (index 0), so an FQNameIndex of 0 marks an unresolved entry
+	if int(entry.Slot) >= len(CP.ResolvedInterfaceRefs) {
+		CP.ResolvedInterfaceRefs = append(CP.ResolvedInterfaceRefs,
+			make([]ResolvedInterfaceRefEntry, len(CP.InterfaceRefs)-len(CP.ResolvedInterfaceRefs))...)
+	}
+	resolved := &CP.ResolvedInterfaceRefs[entry.Slot]
+	if resolved.FQNameIndex == 0 {
+		ifaceRef := CP.InterfaceRefs[entry.Slot]
+		className, ok := cpClassName(CP, ifaceRef.ClassIndex)
+		if !ok {
+			return "", "", "", "", fmt.Errorf(
+				"GetIfaceMethInfoFromCPref: invalid class of interface method reference at CP entry %d", cpIndex)
+		}
+		methName, methSig, ok := cpNameAndType(CP, ifaceRef.NameAndType)
+		if !ok {
+			return "", "", "", "", fmt.Errorf(
+				"GetIfaceMethInfoFromCPref: invalid name and type of interface method reference at CP entry %d", cpIndex)
+		}
+		fqn := className + "." + methName + methSig
+		indices := stringPool.GetStringIndexBatch([]string{className, methName, methSig, fqn})
+		*resolved = ResolvedInterfaceRefEntry{
+			ClassIndex: indices[0], NameIndex: indices[1], TypeIndex: indices[2], FQNameIndex: indices[3]}
+	}
+
+	return *stringPool.GetStringPointer(resolved.ClassIndex), *stringPool.GetStringPointer(resolved.NameIndex),
+		*stringPool.GetStringPointer(resolved.TypeIndex), *stringPool.GetStringPointer(resolved.FQNameIndex), nil
+}
 
-	classRefIdx := CP.CpIndex[classIndex].Slot
-	classIdx := CP.ClassRefs[classRefIdx]
-	classNamePtr := stringPool.GetStringPointer(uint32(classIdx))
-	className := *classNamePtr
+// GetFieldInfoFromCPfieldref receives a CP entry index that points to a field reference
+// and returns the name of the class, the name of the field, and its type. The class loader
+// resolves field references when it loads the class, so the CP's FieldRefs hold these.
+// Every index is checked, and an error is returned if the entry is not a field reference.
+func GetFieldInfoFromCPfieldref(CP *CPool, cpIndex int) (string, string, string, error) {
+	if CP == nil || cpIndex < 1 || cpIndex >= len(CP.CpIndex) {
+		return "", "", "", fmt.Errorf("GetFieldInfoFromCPfieldref: invalid CP index %d", cpIndex)
+	}
+	entry := CP.CpIndex[cpIndex]
+	if entry.Type != FieldRef || int(entry.Slot) >= len(CP.FieldRefs) {
+		return "", "", "", fmt.Errorf("GetFieldInfoFromCPfieldref: CP entry %d is not a field reference", cpIndex)
+	}
+	field := CP.FieldRefs[entry.Slot]
+	return field.ClName, field.FldName, field.FldType, nil
+}
 
-	// now get the method signature
-	nameAndTypeCPindex := CP.InterfaceRefs[methodRef].NameAndType
-	nameAndTypeIndex := CP.CpIndex[nameAndTypeCPindex].Slot
-	nameAndType := CP.NameAndTypes[nameAndTypeIndex]
-	methNameCPindex := nameAndType.NameIndex
-	methNameUTF8index := CP.CpIndex[methNameCPindex].Slot
-	methName := CP.Utf8Refs[methNameUTF8index]
+// returns the name of the class in the ClassRef at the CP index, checking every index
+func cpClassName(CP *CPool, cpIndex uint16) (string, bool) {
+	if int(cpIndex) >= len(CP.CpIndex) || CP.CpIndex[cpIndex].Type != ClassRef ||
+		int(CP.CpIndex[cpIndex].Slot) >= len(CP.ClassRefs) {
+		return "", false
+	}
+	namePtr := stringPool.GetStringPointer(CP.ClassRefs[CP.CpIndex[cpIndex].Slot])
+	if namePtr == nil {
+		return "", false
+	}
+	return *namePtr, true
+}
 
-	// and get the method signature/description
-	methSigCPindex := nameAndType.DescIndex
-	methSigUTF8index := CP.CpIndex[methSigCPindex].Slot
-	methSig := CP.Utf8Refs[methSigUTF8index]
+// returns the name and descriptor in the NameAndType at the CP index, checking every index
+func cpNameAndType(CP *CPool, cpIndex uint16) (string, string, bool) {
+	if int(cpIndex) >= len(CP.CpIndex) || CP.CpIndex[cpIndex].Type != NameAndType ||
+		int(CP.CpIndex[cpIndex].Slot) >= len(CP.NameAndTypes) {
+		return "", "", false
+	}
+	nameAndType := CP.NameAndTypes[CP.CpIndex[cpIndex].Slot]
+	name, ok := cpUTF8(CP, nameAndType.NameIndex)
+	if !ok {
+		return "", "", false
+	}
+	desc, ok := cpUTF8(CP, nameAndType.DescIndex)
+	return name, desc, ok
+}
 
-	// className, methName, methSig, _ := GetMethInfoFromCPmethref(CP, cpIndex)
-	return className, methName, methSig
+// returns the string in the UTF8 entry at the CP index, checking every index
+func cpUTF8(CP *CPool, cpIndex uint16) (string, bool) {
+	if int(cpIndex) >= len(CP.CpIndex) || CP.CpIndex[cpIndex].Type != UTF8 ||
+		int(CP.CpIndex[cpIndex].Slot) >= len(CP.Utf8Refs) {
+		return "", false
+	}
+	return CP.Utf8Refs[CP.CpIndex[cpIndex].Slot], true
 }
 
 // accepts the index of a CP entry, which should point to a classref
@@ -227,3 +279,29 @@ func GetClassNameFromCPclassref(CP *CPool, cpIndex uint16) string {
 		return *entry.StringVal
 	}
 }
+
+// CacheConstantStatic checks whether the FieldRef at slot in the CP's FieldRefs
+// refers to a static final field whose value is set by a ConstantValue attribute.
+// Such a field can never change, so its value is cached in the FieldRef, which
+// allows GETSTATIC to push it directly on subsequent executions without looking
+// it up in the statics table. Returns true if the value was cached.
+func CacheConstantStatic(cp *CPool, slot uint16) bool {
+	if cp == nil || int(slot) >= len(cp.FieldRefs) {
+		return false
+	}
+
+	fieldRef := &cp.FieldRefs[slot]
+	if fieldRef.ConstValue != nil {
+		return true
+	}
+
+	_, fld := findField(fieldRef.ClName, fieldRef.FldName)
+	if fld == nil || fld.ConstValue == nil || !fld.IsStatic || fld.AccessFlags&AccFinal == 0 {
+		return false
+	}
+
+	fieldRef.IsStatic = true
+	fieldRef.IsFinal = true
+	fieldRef.ConstValue = fld.ConstValue
+	return true
+}
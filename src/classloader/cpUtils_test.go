@@ -9,8 +9,10 @@ package classloader
 import (
 	"jacobin/src/frames"
 	"jacobin/src/globals"
+	"jacobin/src/stringPool"
 	"jacobin/src/types"
 	"math"
+	"reflect"
 	"testing"
 )
 
@@ -251,10 +253,13 @@ func TestFetchCPentriesThatAreStructAddresses(t *testing.T) {
 		t.Errorf("Expected IS_STRUCT_ADDR, got %d", cp.RetType)
 	}
 
-	struc := *cp.AddrVal
-	if struc.entry1 != uint16(4) || struc.entry2 != 2 {
+	irfPtr, ok := cp.StructPtr.(*InterfaceRefEntry)
+	if !ok || irfPtr != &CP.InterfaceRefs[0] {
+		t.Fatalf("Expected a pointer to the InterfaceRefEntry in the CP, got %T", cp.StructPtr)
+	}
+	if irfPtr.ClassIndex != 4 || irfPtr.NameAndType != 2 {
 		t.Errorf("Expected returned struc to contain 4 and 2, got %d and %d",
-			struc.entry1, struc.entry2)
+			irfPtr.ClassIndex, irfPtr.NameAndType)
 	}
 
 	// Dynamic
@@ -269,10 +274,13 @@ func TestFetchCPentriesThatAreStructAddresses(t *testing.T) {
 		t.Errorf("Expected IS_STRUCT_ADDR, got %d", cp.RetType)
 	}
 
-	struc = *cp.AddrVal
-	if struc.entry1 != uint16(5) || struc.entry2 != uint16(3) {
+	dePtr, ok := cp.StructPtr.(*DynamicEntry)
+	if !ok || dePtr != &CP.Dynamics[0] {
+		t.Fatalf("Expected a pointer to the DynamicEntry in the CP, got %T", cp.StructPtr)
+	}
+	if dePtr.BootstrapIndex != 5 || dePtr.NameAndType != 3 {
 		t.Errorf("Expected returned struc to contain 5 and 3, got %d and %d",
-			struc.entry1, struc.entry2)
+			dePtr.BootstrapIndex, dePtr.NameAndType)
 	}
 
 	// InvokeDynamic
@@ -287,10 +295,13 @@ func TestFetchCPentriesThatAreStructAddresses(t *testing.T) {
 		t.Errorf("Expected IS_STRUCT_ADDR, got %d", cp.RetType)
 	}
 
-	struc = *cp.AddrVal
-	if struc.entry1 != uint16(20) || struc.entry2 != uint16(21) {
+	idPtr, ok := cp.StructPtr.(*InvokeDynamicEntry)
+	if !ok || idPtr != &CP.InvokeDynamics[0] {
+		t.Fatalf("Expected a pointer to the InvokeDynamicEntry in the CP, got %T", cp.StructPtr)
+	}
+	if idPtr.BootstrapIndex != 20 || idPtr.NameAndType != 21 {
 		t.Errorf("Expected returned struc to contain 20 and 21, got %d and %d",
-			struc.entry1, struc.entry2)
+			idPtr.BootstrapIndex, idPtr.NameAndType)
 	}
 
 	// Method Handle
@@ -305,10 +316,13 @@ func TestFetchCPentriesThatAreStructAddresses(t *testing.T) {
 		t.Errorf("Expected IS_STRUCT_ADDR, got %d", cp.RetType)
 	}
 
-	struc = *cp.AddrVal
-	if struc.entry1 != uint16(8) || struc.entry2 != uint16(9) {
+	mhPtr, ok := cp.StructPtr.(*MethodHandleEntry)
+	if !ok || mhPtr != &CP.MethodHandles[0] {
+		t.Fatalf("Expected a pointer to the MethodHandleEntry in the CP, got %T", cp.StructPtr)
+	}
+	if mhPtr.RefKind != 8 || mhPtr.RefIndex != 9 {
 		t.Errorf("Expected returned struc to contain 8 and 9, got %d and %d",
-			struc.entry1, struc.entry2)
+			mhPtr.RefKind, mhPtr.RefIndex)
 	}
 
 	// Method Ref
@@ -323,10 +337,13 @@ func TestFetchCPentriesThatAreStructAddresses(t *testing.T) {
 		t.Errorf("Expected IS_STRUCT_ADDR, got %d", cp.RetType)
 	}
 
-	struc = *cp.AddrVal
-	if struc.entry1 != uint16(10) || struc.entry2 != uint16(11) {
+	mrPtr, ok := cp.StructPtr.(*MethodRefEntry)
+	if !ok || mrPtr != &CP.MethodRefs[0] {
+		t.Fatalf("Expected a pointer to the MethodRefEntry in the CP, got %T", cp.StructPtr)
+	}
+	if mrPtr.ClassIndex != 10 || mrPtr.NameAndType != 11 {
 		t.Errorf("Expected returned struc to contain 10 and 11, got %d and %d",
-			struc.entry1, struc.entry2)
+			mrPtr.ClassIndex, mrPtr.NameAndType)
 	}
 
 	// NameAndType
@@ -341,9 +358,151 @@ func TestFetchCPentriesThatAreStructAddresses(t *testing.T) {
 		t.Errorf("Expected IS_STRUCT_ADDR, got %d", cp.RetType)
 	}
 
-	struc = *cp.AddrVal
-	if struc.entry1 != uint16(12) || struc.entry2 != uint16(13) {
+	ntPtr, ok := cp.StructPtr.(*NameAndTypeEntry)
+	if !ok || ntPtr != &CP.NameAndTypes[0] {
+		t.Fatalf("Expected a pointer to the NameAndTypeEntry in the CP, got %T", cp.StructPtr)
+	}
+	if ntPtr.NameIndex != 12 || ntPtr.DescIndex != 13 {
 		t.Errorf("Expected returned struc to contain 12 and 13, got %d and %d",
-			struc.entry1, struc.entry2)
+			ntPtr.NameIndex, ntPtr.DescIndex)
+	}
+}
+
+func TestCacheConstantStatic(t *testing.T) {
+	globals.InitGlobals("test")
+	InitMethodArea()
+
+	k := Klass{Status: 'F', Loader: "testloader", Data: &ClData{
+		Name:            "pkg/Consts",
+		SuperclassIndex: types.ObjectPoolStringIndex,
+		Fields: []Field{
+			{AccessFlags: AccPublic | AccStatic | AccFinal, NameStr: "MAX", IsStatic: true, ConstValue: int64(42)},
+			{AccessFlags: AccPublic | AccStatic, NameStr: "counter", IsStatic: true, ConstValue: int64(1)},
+		},
+	}}
+	MethAreaInsert("pkg/Consts", &k)
+
+	cp := CPool{}
+	cp.FieldRefs = append(cp.FieldRefs,
+		ResolvedFieldEntry{ClName: "pkg/Consts", FldName: "MAX"},
+		ResolvedFieldEntry{ClName: "pkg/Consts", FldName: "counter"})
+
+	if !CacheConstantStatic(&cp, 0) {
+		t.Error("Expected static final field with ConstantValue to be cached")
+	}
+	if cp.FieldRefs[0].ConstValue != int64(42) || !cp.FieldRefs[0].IsFinal {
+		t.Errorf("Expected cached value 42, got: %v", cp.FieldRefs[0].ConstValue)
+	}
+
+	if CacheConstantStatic(&cp, 1) {
+		t.Error("Did not expect a non-final static field to be cached")
+	}
+	if cp.FieldRefs[1].ConstValue != nil {
+		t.Errorf("Expected no cached value, got: %v", cp.FieldRefs[1].ConstValue)
+	}
+}
+
+func TestGetIfaceMethInfoFromCPref(t *testing.T) {
+	globals.InitGlobals("test")
+
+	className := "java/util/List"
+	CP := CPool{}
+	CP.CpIndex = []CpEntry{
+		{Type: 0, Slot: 0}, // mandatory dummy entry
+		{Type: Interface, Slot: 0},
+		{Type: ClassRef, Slot: 0},
+		{Type: NameAndType, Slot: 0},
+		{Type: UTF8, Slot: 0},
+		{Type: UTF8, Slot: 1},
+		{Type: IntConst, Slot: 0},
+	}
+	CP.ClassRefs = []uint32{stringPool.GetStringIndex(&className)}
+	CP.NameAndTypes = []NameAndTypeEntry{{NameIndex: 4, DescIndex: 5}}
+	CP.Utf8Refs = []string{"size", "()I"}
+	CP.InterfaceRefs = []InterfaceRefEntry{{ClassIndex: 2, NameAndType: 3}}
+	CP.IntConsts = []int32{1}
+
+	cls, meth, sig, fqn, err := GetIfaceMethInfoFromCPref(&CP, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cls != "java/util/List" || meth != "size" || sig != "()I" || fqn != "java/util/List.size()I" {
+		t.Errorf("Unexpected interface method info: %s, %s, %s, %s", cls, meth, sig, fqn)
+	}
+
+	// the resolved form should now be cached in the CP
+	if len(CP.ResolvedInterfaceRefs) != 1 ||
+		*stringPool.GetStringPointer(CP.ResolvedInterfaceRefs[0].FQNameIndex) != "java/util/List.size()I" {
+		t.Errorf("Expected the resolved interface ref to be cached, got: %v", CP.ResolvedInterfaceRefs)
+	}
+
+	cls, meth, sig = GetMethInfoFromCPinterfaceRef(&CP, 1)
+	if cls != "java/util/List" || meth != "size" || sig != "()I" {
+		t.Errorf("Unexpected interface method info: %s, %s, %s", cls, meth, sig)
+	}
+
+	for _, index := range []int{0, 6, 7, -1} {
+		if _, _, _, _, err = GetIfaceMethInfoFromCPref(&CP, index); err == nil {
+			t.Errorf("Expected an error for CP index %d, got none", index)
+		}
+	}
+
+	// an interface ref whose class index points to a non-ClassRef entry
+	CP.InterfaceRefs = append(CP.InterfaceRefs, InterfaceRefEntry{ClassIndex: 6, NameAndType: 3})
+	CP.CpIndex = append(CP.CpIndex, CpEntry{Type: Interface, Slot: 1})
+	if _, _, _, _, err = GetIfaceMethInfoFromCPref(&CP, 7); err == nil {
+		t.Error("Expected an error for an invalid class index, got none")
+	}
+}
+
+func TestGetFieldInfoFromCPfieldref(t *testing.T) {
+	CP := CPool{}
+	CP.CpIndex = []CpEntry{
+		{Type: 0, Slot: 0}, // mandatory dummy entry
+		{Type: FieldRef, Slot: 0},
+		{Type: FieldRef, Slot: 1}, // points past the end of FieldRefs
+		{Type: IntConst, Slot: 0},
+	}
+	CP.FieldRefs = []ResolvedFieldEntry{{ClName: "pkg/Point", FldName: "x", FldType: "I"}}
+	CP.IntConsts = []int32{1}
+
+	cls, fld, typ, err := GetFieldInfoFromCPfieldref(&CP, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cls != "pkg/Point" || fld != "x" || typ != "I" {
+		t.Errorf("Unexpected field info: %s, %s, %s", cls, fld, typ)
+	}
+
+	for _, index := range []int{0, 2, 3, 4} {
+		if _, _, _, err = GetFieldInfoFromCPfieldref(&CP, index); err == nil {
+			t.Errorf("Expected an error for CP index %d, got none", index)
+		}
+	}
+
+	if _, _, _, err = GetFieldInfoFromCPfieldref(nil, 1); err == nil {
+		t.Error("Expected an error for a nil CP, got none")
+	}
+}
+
+// CpType must not hold uintptr or unsafe.Pointer values: the garbage collector does not
+// track objects referred to only by such values, so they can be moved or freed while
+// the CP entry is still in use. This test guards against reintroducing them.
+func TestCpTypeHoldsNoRawAddresses(t *testing.T) {
+	cpType := reflect.TypeOf(CpType{})
+	for i := 0; i < cpType.NumField(); i++ {
+		field := cpType.Field(i)
+		switch field.Type.Kind() {
+		case reflect.Uintptr, reflect.UnsafePointer:
+			t.Errorf("CpType.%s is a raw address of type %s", field.Name, field.Type)
+		}
+	}
+
+	CP := CPool{}
+	CP.CpIndex = []CpEntry{{Type: 0, Slot: 0}, {Type: MethodRef, Slot: 0}}
+	CP.MethodRefs = []MethodRefEntry{{ClassIndex: 1, NameAndType: 2}}
+	entry := FetchCPentry(&CP, 1)
+	if kind := reflect.TypeOf(entry.StructPtr).Kind(); kind != reflect.Ptr {
+		t.Errorf("Expected StructPtr to hold a typed pointer, got a %s", kind)
 	}
 }
@@ -0,0 +1,253 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package classloader
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The catalog of the errors detected in class format checking and code checking.
+// Each error has a stable code, such as JVM-CFE-0003, which is shown with the
+// message. The code identifies the error regardless of the wording of the message
+// (which may change), so tests check the code rather than the text, and users
+// can look up the error by its code here. Codes are never reused or renumbered:
+// when an error is retired, its code is simply no longer emitted.
+//
+// The codes are in two series:
+//
+//	JVM-CFE-nnnn - class format errors (JVMS 4.8), see formatCheck.go
+//	JVM-VFY-nnnn - errors in a method's bytecode, see codeCheck.go
+
+// ErrorCode is the stable identifier of an error in the catalog
+type ErrorCode string
+
+const (
+	// a class format error that has no entry of its own in the catalog
+	CfeUnclassified ErrorCode = "JVM-CFE-0000"
+
+	// ---- the constant pool (CP) ----
+	CfeCPSize                  ErrorCode = "JVM-CFE-0001"
+	CfeCPMissingFirstDummy     ErrorCode = "JVM-CFE-0002"
+	CfeUTF8Index               ErrorCode = "JVM-CFE-0003"
+	CfeUTF8InvalidChar         ErrorCode = "JVM-CFE-0004"
+	CfeIntConstIndex           ErrorCode = "JVM-CFE-0005"
+	CfeFloatConstIndex         ErrorCode = "JVM-CFE-0006"
+	CfeLongConstIndex          ErrorCode = "JVM-CFE-0007"
+	CfeLongMissingDummy        ErrorCode = "JVM-CFE-0008"
+	CfeDoubleConstIndex        ErrorCode = "JVM-CFE-0009"
+	CfeDoubleMissingDummy      ErrorCode = "JVM-CFE-0010"
+	CfeClassRefIndex           ErrorCode = "JVM-CFE-0011"
+	CfeStringConstIndex        ErrorCode = "JVM-CFE-0012"
+	CfeFieldRefIndex           ErrorCode = "JVM-CFE-0013"
+	CfeFieldRefClass           ErrorCode = "JVM-CFE-0014"
+	CfeFieldRefNameAndType     ErrorCode = "JVM-CFE-0015"
+	CfeMethodRefClass          ErrorCode = "JVM-CFE-0016"
+	CfeMethodRefNameAndType    ErrorCode = "JVM-CFE-0017"
+	CfeMethodRefName           ErrorCode = "JVM-CFE-0018"
+	CfeMethodRefInvalidName    ErrorCode = "JVM-CFE-0019"
+	CfeInterfaceRefClass       ErrorCode = "JVM-CFE-0020"
+	CfeInterfaceRefName        ErrorCode = "JVM-CFE-0021"
+	CfeInterfaceRefNameAndType ErrorCode = "JVM-CFE-0022"
+	CfeNameAndTypeIndex        ErrorCode = "JVM-CFE-0023"
+	CfeNameAndTypeName         ErrorCode = "JVM-CFE-0024"
+	CfeNameAndTypeDescIndex    ErrorCode = "JVM-CFE-0025"
+	CfeNameAndTypeDesc         ErrorCode = "JVM-CFE-0026"
+	CfeMethodHandleKind        ErrorCode = "JVM-CFE-0027"
+	CfeMethodHandleFieldRef    ErrorCode = "JVM-CFE-0028"
+	CfeMethodHandleMethodRef   ErrorCode = "JVM-CFE-0029"
+	CfeMethodHandleInvoke      ErrorCode = "JVM-CFE-0030"
+	CfeMethodHandleInterface   ErrorCode = "JVM-CFE-0031"
+	CfeMethodHandleRefIndex    ErrorCode = "JVM-CFE-0032"
+	CfeMethodHandleInit        ErrorCode = "JVM-CFE-0033"
+	CfeMethodTypeDescIndex     ErrorCode = "JVM-CFE-0034"
+	CfeMethodTypeDesc          ErrorCode = "JVM-CFE-0035"
+	CfeDynamicIndex            ErrorCode = "JVM-CFE-0036"
+	CfeDynamicBootstrap        ErrorCode = "JVM-CFE-0037"
+	CfeBootstrapMethodRef      ErrorCode = "JVM-CFE-0038"
+	CfeDynamicNameAndTypeIndex ErrorCode = "JVM-CFE-0039"
+	CfeDynamicNameAndType      ErrorCode = "JVM-CFE-0040"
+	CfeDynamicDescIndex        ErrorCode = "JVM-CFE-0041"
+	CfeDynamicDesc             ErrorCode = "JVM-CFE-0042"
+	CfeInvokeDynamicIndex      ErrorCode = "JVM-CFE-0043"
+	CfeInvokeDynamicBootstrap  ErrorCode = "JVM-CFE-0044"
+	CfeInvokeDynamicNATIndex   ErrorCode = "JVM-CFE-0045"
+	CfeInvokeDynamicNAT        ErrorCode = "JVM-CFE-0046"
+	CfeInvokeDynamicDescIndex  ErrorCode = "JVM-CFE-0047"
+	CfeInvokeDynamicDesc       ErrorCode = "JVM-CFE-0048"
+	CfeModuleNotInModule       ErrorCode = "JVM-CFE-0049"
+	CfePackageNotInModule      ErrorCode = "JVM-CFE-0050"
+
+	// ---- fields ----
+	CfeFieldNameIndex      ErrorCode = "JVM-CFE-0051"
+	CfeFieldDescIndex      ErrorCode = "JVM-CFE-0052"
+	CfeFieldNameDigit      ErrorCode = "JVM-CFE-0053"
+	CfeFieldNameWhitespace ErrorCode = "JVM-CFE-0054"
+	CfeFieldDesc           ErrorCode = "JVM-CFE-0055"
+
+	// ---- module and package names ----
+	CfeModuleNameMissing  ErrorCode = "JVM-CFE-0056"
+	CfeModuleNameStart    ErrorCode = "JVM-CFE-0057"
+	CfeModuleNameChar     ErrorCode = "JVM-CFE-0058"
+	CfePackageNameMissing ErrorCode = "JVM-CFE-0059"
+	CfePackageNameChar    ErrorCode = "JVM-CFE-0060"
+
+	// ---- class attributes ----
+	CfeBootstrapNotHandle   ErrorCode = "JVM-CFE-0061"
+	CfeBootstrapArgLoadable ErrorCode = "JVM-CFE-0062"
+
+	// ---- structure ----
+	CfeCPCount        ErrorCode = "JVM-CFE-0063"
+	CfeInterfaceCount ErrorCode = "JVM-CFE-0064"
+	CfeMethodCount    ErrorCode = "JVM-CFE-0065"
+	CfeAttributeCount ErrorCode = "JVM-CFE-0066"
+	CfeBootstrapCount ErrorCode = "JVM-CFE-0067"
+
+	// ---- class file version, reported as UnsupportedClassVersionError ----
+	CfeVersionTooNew     ErrorCode = "JVM-CFE-0068"
+	CfeVersionTooOld     ErrorCode = "JVM-CFE-0069"
+	CfeVersionMinor      ErrorCode = "JVM-CFE-0070"
+	CfePreviewNotEnabled ErrorCode = "JVM-CFE-0071"
+	CfePreviewVersion    ErrorCode = "JVM-CFE-0072"
+
+	// ---- bytecode ----
+	VfyNilCode              ErrorCode = "JVM-VFY-0001"
+	VfyEmptyCode            ErrorCode = "JVM-VFY-0002"
+	VfyNilCP                ErrorCode = "JVM-VFY-0003"
+	VfyEmptyCP              ErrorCode = "JVM-VFY-0004"
+	VfyInvalidBytecode      ErrorCode = "JVM-VFY-0005"
+	VfyNotFieldRef          ErrorCode = "JVM-VFY-0006"
+	VfyIllegalJump          ErrorCode = "JVM-VFY-0007"
+	VfyNotInterfaceRef      ErrorCode = "JVM-VFY-0008"
+	VfyNotMethodOrInterface ErrorCode = "JVM-VFY-0009"
+	VfyNotMethodRef         ErrorCode = "JVM-VFY-0010"
+	VfyNotClassRef          ErrorCode = "JVM-VFY-0011"
+)
+
+// the message templates, which are formatted with fmt.Sprintf
+var errorCatalog = map[ErrorCode]string{
+	CfeUnclassified: "%s",
+
+	CfeCPSize:                  "Error in size of constant pool discovered in format check. Expected: %d, got: %d",
+	CfeCPMissingFirstDummy:     "Missing dummy entry in first slot of constant pool",
+	CfeUTF8Index:               "CP entry #%d points to invalid UTF8 entry: %d",
+	CfeUTF8InvalidChar:         "UTF8 string for CP entry #%d contains an invalid character",
+	CfeIntConstIndex:           "Integer at CP entry #%d points to an invalid entry in CP intConsts",
+	CfeFloatConstIndex:         "Float at CP entry #%d points to an invalid entry in CP floats",
+	CfeLongConstIndex:          "Long constant at CP entry #%d points to an invalid entry in CP longConsts",
+	CfeLongMissingDummy:        "Missing dummy entry after long constant at CP entry #%d",
+	CfeDoubleConstIndex:        "Double constant at CP entry #%d points to an invalid entry in CP doubles",
+	CfeDoubleMissingDummy:      "Missing dummy entry after double constant at CP entry #%d",
+	CfeClassRefIndex:           "ClassRef at CP entry #%d points to an invalid entry in the string pool",
+	CfeStringConstIndex:        "Constant String at CP entry #%d points to an invalid entry in CP utf8Refs",
+	CfeFieldRefIndex:           "Field Ref at CP entry #%d points to an invalid entry in CP fieldRefs",
+	CfeFieldRefClass:           "Field Ref at CP entry #%d has a class index that points to an invalid entry in ClassRefs: %d",
+	CfeFieldRefNameAndType:     "Field Ref at CP entry #%d has a nameAndType index that points to an invalid entry in nameAndTypes: %d",
+	CfeMethodRefClass:          "Method Ref at CP entry #%d holds an invalid class index: %d",
+	CfeMethodRefNameAndType:    "Method Ref at CP entry #%d holds an invalid NameAndType index: %d",
+	CfeMethodRefName:           "Method Ref at CP entry #%d has a Name and Type entry whose name is not a valid UTF8 entry",
+	CfeMethodRefInvalidName:    "Method Ref at CP entry #%d holds a NameAndType index to an entry with an invalid method name: %s",
+	CfeInterfaceRefClass:       "Interface Ref at CP entry #%d holds an invalid class index: %d",
+	CfeInterfaceRefName:        "Interface Ref at CP entry #%d holds an invalid stringPool index for interface: %d",
+	CfeInterfaceRefNameAndType: "Interface Ref at CP entry #%d holds an invalid NameAndType index: %d",
+	CfeNameAndTypeIndex:        "Name and Type at CP entry #%d points to an invalid entry in CP nameAndTypes",
+	CfeNameAndTypeName:         "Name and Type at CP entry #%d has a name index that points to an invalid UTF8 entry: %d",
+	CfeNameAndTypeDescIndex:    "Name and Type at CP entry #%d has a description index that points to an invalid UTF8 entry: %d",
+	CfeNameAndTypeDesc:         "Name and Type at CP entry #%d has an invalid description string: %s",
+	CfeMethodHandleKind:        "MethodHandle at CP entry #%d has an invalid reference kind: %d",
+	CfeMethodHandleFieldRef:    "MethodHandle at CP entry #%d has a reference kind between 1-4 (%d) which does not point to a FieldRef",
+	CfeMethodHandleMethodRef:   "MethodHandle at CP entry #%d has a reference kind of 5 or 8 (%d) which does not point to a MethodRef",
+	CfeMethodHandleInvoke: "MethodHandle at CP entry #%d has a reference kind of 6 or 7 (%d) which does not point to a " +
+		"MethodRef or, in Java version 52 or later, to an Interface",
+	CfeMethodHandleInterface:   "MethodHandle at CP entry #%d has a reference kind of 9 which does not point to an interface",
+	CfeMethodHandleRefIndex:    "Reference index for MethodHandle at CP entry #%d points to an invalid MethodRef: %d",
+	CfeMethodHandleInit:        "MethodHandle at CP entry #%d has an invalid method name: %s",
+	CfeMethodTypeDescIndex:     "MethodType at CP entry #%d has an invalid description index: %d",
+	CfeMethodTypeDesc:          "MethodType at CP entry #%d does not point to a type that starts with an open parenthesis. Got: %s",
+	CfeDynamicIndex:            "The dynamic entry at CP[%d] points to a non-existent dynamic slot: %d",
+	CfeDynamicBootstrap:        "The bootstrap index in dynamic at CP[%d] is invalid: %d",
+	CfeBootstrapMethodRef:      "Invalid methodRef in bootstrap method[%d]",
+	CfeDynamicNameAndTypeIndex: "The entry number into klass.dynamics[] at CP entry #%d is invalid: %d",
+	CfeDynamicNameAndType:      "NameAndType index at CP entry #%d (dynamic) points to an entry that's not NameAndType: %d",
+	CfeDynamicDescIndex:        "Descriptor in nameAndType entry of dynamic CP entry #%d is invalid: %d",
+	CfeDynamicDesc:             "Descriptor in nameAndType entry of dynamic CP entry #%d is an invalid field descriptor: %s",
+	CfeInvokeDynamicIndex:      "The invokeDynamic entry at CP[%d] points to a non-existent invokeDynamic slot: %d",
+	CfeInvokeDynamicBootstrap:  "The bootstrap index in InvokeDynamic at CP[%d] is invalid: %d",
+	CfeInvokeDynamicNATIndex:   "The entry number into klass.InvokeDynamics[] at CP entry #%d is invalid: %d",
+	CfeInvokeDynamicNAT:        "NameAndType index at CP entry #%d (InvokeDynamic) points to an entry that's not NameAndType: %d",
+	CfeInvokeDynamicDescIndex:  "Descriptor in nameAndType entry of InvokeDynamic CP entry #%d is invalid: %d",
+	CfeInvokeDynamicDesc:       "Descriptor in nameAndType entry of InvokeDynamic CP entry #%d is an invalid method descriptor: %s",
+	CfeModuleNotInModule:       "Module CP entry must appear only in class with ACC_MODULE set.",
+	CfePackageNotInModule:      "Package CP entry must appear only in class with ACC_MODULE set.",
+
+	CfeFieldNameIndex:      "Invalid index to UTF8 string for field name in field #%d",
+	CfeFieldDescIndex:      "Invalid index for UTF8 string containing description of field %s",
+	CfeFieldNameDigit:      "Invalid field name in format check (starts with a digit): %s",
+	CfeFieldNameWhitespace: "Invalid field name in format check (contains whitespace): %s",
+	CfeFieldDesc:           "Field %s has an invalid description string: %s",
+
+	CfeModuleNameMissing:  "Expected a module/package name, but none was found.",
+	CfeModuleNameStart:    "Module/Package name %s contains an illegal character",
+	CfeModuleNameChar:     "Module name %s contains an illegal character",
+	CfePackageNameMissing: "Expected a package name, but none was found.",
+	CfePackageNameChar:    "Package name %s contains an illegal character",
+
+	CfeBootstrapNotHandle:   "MethodRef in bootstrapMethod[%d] in class %s should but does not point to a MethodHandle",
+	CfeBootstrapArgLoadable: "Bootstrap method argument[%d] in class %s bootstrap method #[%d] should be but is not a loadable constant",
+
+	CfeCPCount:        "CP count: %d is not equal to actual size of CP: %d",
+	CfeInterfaceCount: "Expected %d interfaces. Got: %d",
+	CfeMethodCount:    "Expected %d methods. Got: %d",
+	CfeAttributeCount: "Expected %d class attributes. Got: %d",
+	CfeBootstrapCount: "Expected %d bootstrap methods. Got: %d",
+
+	CfeVersionTooNew: "Class file version %d.%d is newer than %d.0: " +
+		"Jacobin supports only Java versions through Java %d",
+	CfeVersionTooOld:     "Class file version %d.%d is older than the earliest valid version, 45.0",
+	CfeVersionMinor:      "Class file version %d.%d has an invalid minor version: it must be 0, or 65535 for preview features",
+	CfePreviewNotEnabled: "Class file version %d.65535 requires preview features, which are enabled only by --enable-preview",
+	CfePreviewVersion:    "Class file version %d.65535 requires preview features, which Jacobin supports only in version %d.65535",
+
+	VfyNilCode:              "CheckCodeValidity: ptr to code segment is nil",
+	VfyEmptyCode:            "CheckCodeValidity: Empty code segment",
+	VfyNilCP:                "CheckCodeValidity: ptr to constant pool is nil",
+	VfyEmptyCP:              "CheckCodeValidity: empty constant pool",
+	VfyInvalidBytecode:      "Invalid bytecode or argument at location %d",
+	VfyNotFieldRef:          "%s at %d: CP entry (%d) is not a field reference",
+	VfyIllegalJump:          "%s at %d: illegal jump to %d",
+	VfyNotInterfaceRef:      "%s at %d: CP entry (%d) is not an interface reference",
+	VfyNotMethodOrInterface: "%s at %d: CP entry (%d) is not a method or interface reference",
+	VfyNotMethodRef:         "%s at %d: CP entry (%d) is not a method reference",
+	VfyNotClassRef:          "%s at %d: CP entry (%d) is not a class reference",
+}
+
+// CatalogError is an error whose message comes from the catalog
+type CatalogError struct {
+	Code ErrorCode
+	Msg  string // the complete message, including the code
+}
+
+func (e *CatalogError) Error() string { return e.Msg }
+
+// catalogMessage formats the catalog's message for code with args, prefixed by the code
+func catalogMessage(code ErrorCode, args ...any) string {
+	template, ok := errorCatalog[code]
+	if !ok {
+		return fmt.Sprintf("[%s] (no catalog entry) %v", code, args)
+	}
+	return fmt.Sprintf("[%s] %s", code, fmt.Sprintf(template, args...))
+}
+
+// ErrorCodeOf returns the catalog code of err, or "" if err is not (or does not
+// wrap) a CatalogError.
+func ErrorCodeOf(err error) ErrorCode {
+	var catErr *CatalogError
+	if errors.As(err, &catErr) {
+		return catErr.Code
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package classloader
+
+import (
+	"fmt"
+	"io"
+	"jacobin/src/globals"
+	"jacobin/src/trace"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// every code in the catalog must be well formed and have a message
+func TestErrorCatalogEntries(t *testing.T) {
+	wellFormed := regexp.MustCompile(`^JVM-(CFE|VFY)-\d{4}$`)
+	for code, template := range errorCatalog {
+		if !wellFormed.MatchString(string(code)) {
+			t.Errorf("Malformed error code: %s", code)
+		}
+		if template == "" {
+			t.Errorf("Error code %s has an empty message", code)
+		}
+	}
+}
+
+func TestCatalogMessage(t *testing.T) {
+	msg := catalogMessage(CfeUTF8Index, 4, 17)
+	expected := "[JVM-CFE-0003] CP entry #4 points to invalid UTF8 entry: 17"
+	if msg != expected {
+		t.Errorf("Expected %q, got %q", expected, msg)
+	}
+
+	msg = catalogMessage(ErrorCode("JVM-CFE-9999"), 4)
+	if !strings.HasPrefix(msg, "[JVM-CFE-9999] (no catalog entry)") {
+		t.Errorf("Got unexpected message for an unknown code: %s", msg)
+	}
+}
+
+// the errors reported by cfeCode() carry their code, including when wrapped
+func TestErrorCodeOf(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	globals.SetTraceWriter(io.Discard)
+
+	err := cfeCode(CfeMethodCount, 3, 2)
+	if ErrorCodeOf(err) != CfeMethodCount {
+		t.Errorf("Expected code %s, got %s", CfeMethodCount, ErrorCodeOf(err))
+	}
+	if !strings.Contains(err.Error(), "Class Format Error: [JVM-CFE-0065] Expected 3 methods. Got: 2") {
+		t.Errorf("Got unexpected error message: %s", err.Error())
+	}
+	if !strings.Contains(err.Error(), "detected by file: errorCatalog_test.go") {
+		t.Errorf("Expected the error to identify the file that reported it, got: %s", err.Error())
+	}
+
+	wrapped := fmt.Errorf("loading class: %w", err)
+	if ErrorCodeOf(wrapped) != CfeMethodCount {
+		t.Errorf("Expected code %s from wrapped error, got %s", CfeMethodCount, ErrorCodeOf(wrapped))
+	}
+
+	if ErrorCodeOf(cfe("not in the catalog")) != CfeUnclassified {
+		t.Error("Expected an error reported by cfe() to be unclassified")
+	}
+	if ErrorCodeOf(fmt.Errorf("plain error")) != "" {
+		t.Error("Expected no code for an error that's not from the catalog")
+	}
+}
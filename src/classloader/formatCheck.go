@@ -8,9 +8,7 @@ package classloader
 
 import (
 	"errors"
-	"jacobin/src/globals"
 	"jacobin/src/stringPool"
-	"strconv"
 	"strings"
 )
 
@@ -51,12 +49,11 @@ func formatCheckClass(klass *ParsedClass) error {
 func formatCheckConstantPool(klass *ParsedClass) error {
 	cpSize := klass.cpCount
 	if len(klass.cpIndex) != cpSize {
-		return cfe("Error in size of constant pool discovered in format check." +
-			"Expected: " + strconv.Itoa(cpSize) + ", got: " + strconv.Itoa(len(klass.cpIndex)))
+		return cfeCode(CfeCPSize, cpSize, len(klass.cpIndex))
 	}
 
 	if klass.cpIndex[0].entryType != Dummy {
-		return cfe("Missing dummy entry in first slot of constant pool")
+		return cfeCode(CfeCPMissingFirstDummy)
 	}
 
 	for j := 1; j < cpSize; j++ {
@@ -68,15 +65,13 @@ func formatCheckConstantPool(klass *ParsedClass) error {
 			// * No byte may lie in the range (byte)0xf0 to (byte)0xff
 			whichUtf8 := entry.slot
 			if whichUtf8 < 0 || whichUtf8 >= len(klass.utf8Refs) {
-				return cfe("CP entry #" + strconv.Itoa(j) + "points to invalid UTF8 entry: " +
-					strconv.Itoa(whichUtf8))
+				return cfeCode(CfeUTF8Index, j, whichUtf8)
 			}
 			utf8string := klass.utf8Refs[whichUtf8].content
 			utf8bytes := []byte(utf8string)
 			for _, char := range utf8bytes {
 				if char == 0x00 || (char >= 0xf0 && char <= 0xff) {
-					return cfe("UTF8 string for CP entry #" + strconv.Itoa(j) +
-						" contains an invalid character")
+					return cfeCode(CfeUTF8InvalidChar, j)
 				}
 			}
 		case IntConst:
@@ -84,16 +79,14 @@ func formatCheckConstantPool(klass *ParsedClass) error {
 			// that there is a valid entry pointed to in intConsts
 			whichInt := entry.slot
 			if whichInt < 0 || whichInt >= len(klass.intConsts) {
-				return cfe("Integer at CP entry #" + strconv.Itoa(j) +
-					" points to an invalid entry in CP intConsts")
+				return cfeCode(CfeIntConstIndex, j)
 			}
 		case FloatConst:
 			// there are complex bit patterns that can be enforced for floats, but
 			// for the nonce, we'll just make sure that the float index points to an actual value
 			whichFloat := entry.slot
 			if whichFloat < 0 || whichFloat >= len(klass.floats) {
-				return cfe("Float at CP entry #" + strconv.Itoa(j) +
-					" points to an invalid entry in CP floats")
+				return cfeCode(CfeFloatConstIndex, j)
 			}
 		case LongConst:
 			// there are complex bit patterns that can be enforced for longs, but for the
@@ -102,44 +95,38 @@ func formatCheckConstantPool(klass *ParsedClass) error {
 			// https://docs.oracle.com/javase/specs/jvms/se11/html/jvms-4.html#jvms-4.4.5
 			whichLong := entry.slot
 			if whichLong < 0 || whichLong >= len(klass.longConsts) {
-				return cfe("Long constant at CP entry #" + strconv.Itoa(j) +
-					" points to an invalid entry in CP longConsts")
+				return cfeCode(CfeLongConstIndex, j)
 			}
 
 			nextEntry := klass.cpIndex[j+1]
 			if nextEntry.entryType != Dummy {
-				return cfe("Missing dummy entry after long constant at CP entry#" +
-					strconv.Itoa(j))
+				return cfeCode(CfeLongMissingDummy, j)
 			}
 			j += 1
 		case DoubleConst:
 			// see the comments on the LongConst. They apply exactly to the following code.
 			whichDouble := entry.slot
 			if whichDouble < 0 || whichDouble >= len(klass.doubles) {
-				return cfe("Double constant at CP entry #" + strconv.Itoa(j) +
-					" points to an invalid entry in CP doubless")
+				return cfeCode(CfeDoubleConstIndex, j)
 			}
 
 			nextEntry := klass.cpIndex[j+1]
 			if nextEntry.entryType != Dummy {
-				return cfe("Missing dummy entry after double constant at CP entry#" +
-					strconv.Itoa(j))
+				return cfeCode(CfeDoubleMissingDummy, j)
 			}
 			j += 1
 		case ClassRef:
 			// the only field of a ClassRef is a uint32 index into the StringPoolTable
 			whichClassRef := entry.slot
-			if whichClassRef < 0 || whichClassRef >= len(globals.StringPoolTable) {
-				return cfe("ClassRef at CP entry #" + strconv.Itoa(j) +
-					" points to an invalid entry in CP the string pool")
+			if whichClassRef < 0 || whichClassRef >= int(stringPool.GetStringPoolSize()) {
+				return cfeCode(CfeClassRefIndex, j)
 			}
 		case StringConst:
 			// a StringConst holds only an index into the utf8Refs. so we check this.
 			// https://docs.oracle.com/javase/specs/jvms/se11/html/jvms-4.html#jvms-4.4.3
 			whichString := entry.slot
 			if whichString < 0 || whichString >= len(klass.utf8Refs) {
-				return cfe("Constant String at CP entry #" + strconv.Itoa(j) +
-					" points to an invalid entry in CP utf8Refs")
+				return cfeCode(CfeStringConstIndex, j)
 			}
 		case FieldRef:
 			// the requirements are that the class index points to a valid Class entry
@@ -150,25 +137,20 @@ func formatCheckConstantPool(klass *ParsedClass) error {
 			// picks them up going through the CP.
 			whichFieldRef := entry.slot
 			if whichFieldRef < 0 || whichFieldRef >= len(klass.fieldRefs) {
-				return cfe("Field Ref at CP entry #" + strconv.Itoa(j) +
-					" points to an invalid entry in CP fieldRefs")
+				return cfeCode(CfeFieldRefIndex, j)
 			}
 			fieldRef := klass.fieldRefs[whichFieldRef]
 			classIndex := fieldRef.classIndex
 			class := klass.cpIndex[classIndex]
 			if class.entryType != ClassRef ||
 				class.slot < 0 || class.slot >= len(klass.classRefs) {
-				return cfe("Field Ref at CP entry #" + strconv.Itoa(j) +
-					" has a class index that points to an invalid entry in ClassRefs. " +
-					strconv.Itoa(classIndex))
+				return cfeCode(CfeFieldRefClass, j, classIndex)
 			}
 
 			nameAndType := klass.cpIndex[fieldRef.nameAndTypeIndex]
 			if nameAndType.entryType != NameAndType ||
 				nameAndType.slot < 0 || nameAndType.slot >= len(klass.nameAndTypes) {
-				return cfe("Field Ref at CP entry #" + strconv.Itoa(j) +
-					" has a nameAndType index that points to an invalid entry in nameAndTypes. " +
-					strconv.Itoa(fieldRef.nameAndTypeIndex))
+				return cfeCode(CfeFieldRefNameAndType, j, fieldRef.nameAndTypeIndex)
 			}
 		case MethodRef:
 			// the MethodRef must have a class index that points to a Class_info entry
@@ -182,34 +164,27 @@ func formatCheckConstantPool(klass *ParsedClass) error {
 			classIndex := methodRef.classIndex
 			class := klass.cpIndex[classIndex]
 			if class.entryType != ClassRef ||
-				class.slot < 0 || class.slot >= len(globals.StringPoolTable) {
-				return cfe("Method Ref at CP entry #" + strconv.Itoa(j) +
-					" holds an invalid class index: " +
-					strconv.Itoa(class.slot))
+				class.slot < 0 || class.slot >= int(stringPool.GetStringPoolSize()) {
+				return cfeCode(CfeMethodRefClass, j, class.slot)
 			}
 
 			nAndTIndex := methodRef.nameAndTypeIndex
 			nAndT := klass.cpIndex[nAndTIndex]
 			if nAndT.entryType != NameAndType ||
 				nAndT.slot < 0 || nAndT.slot >= len(klass.nameAndTypes) {
-				return cfe("Method Ref at CP entry #" + strconv.Itoa(j) +
-					" holds an invalid NameAndType index: " +
-					strconv.Itoa(nAndT.slot))
+				return cfeCode(CfeMethodRefNameAndType, j, nAndT.slot)
 			}
 
 			nAndTentry := klass.nameAndTypes[nAndT.slot]
 			methodNameIndex := nAndTentry.nameIndex
 			name, err := FetchUTF8string(klass, methodNameIndex)
 			if err != nil {
-				return cfe("Method Ref (at CP entry #" + strconv.Itoa(j) +
-					") has a Name and Type entry does not have a name that is a valid UTF8 entry")
+				return cfeCode(CfeMethodRefName, j)
 			}
 
 			nameBytes := []byte(name)
 			if nameBytes[0] == '<' && name != "<init>" {
-				return cfe("Method Ref at CP entry #" + strconv.Itoa(j) +
-					" holds an NameAndType index to an entry with an invalid method name " +
-					name)
+				return cfeCode(CfeMethodRefInvalidName, j, name)
 			}
 		case Interface:
 			// the Interface entries are almost identical to the class entries (see above),
@@ -222,17 +197,13 @@ func formatCheckConstantPool(klass *ParsedClass) error {
 			class := klass.cpIndex[classIndex]
 			if class.entryType != ClassRef ||
 				class.slot < 0 || class.slot >= len(klass.classRefs) {
-				return cfe("Interface Ref at CP entry #" + strconv.Itoa(j) +
-					" holds an invalid class index: " + strconv.Itoa(class.slot))
+				return cfeCode(CfeInterfaceRefClass, j, class.slot)
 			}
 
 			clRef := klass.classRefs[class.slot]
 			clName := stringPool.GetStringPointer(clRef)
 			if clName == nil {
-				return cfe("Interface Ref at CP entry #" + strconv.Itoa(j) +
-					// " holds an invalid UTF8 index to the interface name: " +
-					"holds an invalid stringPool index for interface: " +
-					strconv.FormatUint(uint64(clRef), 10))
+				return cfeCode(CfeInterfaceRefName, j, clRef)
 			}
 
 			/* TODO: REVISIT: with java.lang.String the following code works OK
@@ -260,9 +231,7 @@ func formatCheckConstantPool(klass *ParsedClass) error {
 			nAndT := klass.cpIndex[nAndTIndex]
 			if nAndT.entryType != NameAndType ||
 				nAndT.slot < 0 || nAndT.slot >= len(klass.nameAndTypes) {
-				return cfe("Method Ref at CP entry #" + strconv.Itoa(j) +
-					" holds an invalid NameAndType index: " +
-					strconv.Itoa(nAndT.slot))
+				return cfeCode(CfeInterfaceRefNameAndType, j, nAndT.slot)
 			}
 		case NameAndType:
 			// a NameAndType entry points to two UTF8 entries: name and description. Consult
@@ -272,29 +241,23 @@ func formatCheckConstantPool(klass *ParsedClass) error {
 			// https://docs.oracle.com/javase/specs/jvms/se11/html/jvms-4.html#jvms-4.3.2-200
 			whichNandT := entry.slot
 			if whichNandT < 0 || whichNandT >= len(klass.nameAndTypes) {
-				return cfe("Name and Type at CP entry #" + strconv.Itoa(j) +
-					" points to an invalid entry in CP nameAndTypes")
+				return cfeCode(CfeNameAndTypeIndex, j)
 			}
 
 			nAndTentry := klass.nameAndTypes[whichNandT]
 			_, err := FetchUTF8string(klass, nAndTentry.nameIndex)
 			if err != nil {
-				return cfe("Name and Type at CP entry #" + strconv.Itoa(j) +
-					" has a name index that points to an invalid UTF8 entry: " +
-					strconv.Itoa(nAndTentry.nameIndex))
+				return cfeCode(CfeNameAndTypeName, j, nAndTentry.nameIndex)
 			}
 
 			desc, err2 := FetchUTF8string(klass, nAndTentry.descriptorIndex)
 			if err2 != nil {
-				return cfe("Name and Type at CP entry #" + strconv.Itoa(j) +
-					" has a description index that points to an invalid UTF8 entry: " +
-					strconv.Itoa(nAndTentry.nameIndex))
+				return cfeCode(CfeNameAndTypeDescIndex, j, nAndTentry.descriptorIndex)
 			}
 
 			err = validateFieldDesc(desc)
 			if err != nil {
-				return cfe("Name and Type at CP entry #" + strconv.Itoa(j) +
-					" has an invalid description string: " + desc)
+				return cfeCode(CfeNameAndTypeDesc, j, desc)
 			}
 		case MethodHandle:
 			// Method handles have complex validation logic. It's entirely enforced here. See:
@@ -307,8 +270,7 @@ func formatCheckConstantPool(klass *ParsedClass) error {
 			mhe := klass.methodHandles[whichMethHandle]
 			refKind := mhe.referenceKind
 			if refKind < 1 || refKind > 9 {
-				return cfe("MethodHandle at CP entry #" + strconv.Itoa(j) +
-					" has an invalid reference kind: " + strconv.Itoa(refKind))
+				return cfeCode(CfeMethodHandleKind, j, refKind)
 			}
 			refIndex := mhe.referenceIndex
 
@@ -316,16 +278,12 @@ func formatCheckConstantPool(klass *ParsedClass) error {
 			// if refKind is 1-4, the reference_index must point to a fieldRef
 			case 1, 2, 3, 4:
 				if klass.cpIndex[refIndex].entryType != FieldRef {
-					return cfe("MethodHandle at CP entry #" + strconv.Itoa(j) +
-						" has an reference kind between 1-4 ( " + strconv.Itoa(refKind) +
-						") which does not point to a FieldRef")
+					return cfeCode(CfeMethodHandleFieldRef, j, refKind)
 				}
 			// if refKind is 5 or 8, the reference_index must point to a methodRef
 			case 5, 8:
 				if klass.cpIndex[refIndex].entryType != MethodRef {
-					return cfe("MethodHandle at CP entry #" + strconv.Itoa(j) +
-						" has an reference kind between of 5 or 8 ( " + strconv.Itoa(refKind) +
-						") which does not point to a MethodRef")
+					return cfeCode(CfeMethodHandleMethodRef, j, refKind)
 				}
 			case 6, 7:
 				// if refKind is 6 or 7, the reference_index must point to a methodRef or if the
@@ -335,15 +293,11 @@ func formatCheckConstantPool(klass *ParsedClass) error {
 					(klass.javaVersion >= 52 && klass.cpIndex[refIndex].entryType == Interface) {
 					break
 				} else {
-					return cfe("MethodHandle at CP entry #" + strconv.Itoa(j) +
-						" has an reference kind between of 6 or 7 ( " + strconv.Itoa(refKind) +
-						") which does not point to a MethodRef or in Java version 52 or later " +
-						"does not point to an Interface.")
+					return cfeCode(CfeMethodHandleInvoke, j, refKind)
 				}
 			case 9:
 				if klass.cpIndex[refIndex].entryType != Interface {
-					return cfe("MethodHandle at CP entry #" + strconv.Itoa(j) +
-						" has an reference kind  of 9 which does not point to an interface")
+					return cfeCode(CfeMethodHandleInterface, j)
 				}
 			}
 
@@ -362,13 +316,11 @@ func formatCheckConstantPool(klass *ParsedClass) error {
 			if refKind >= 5 && refKind <= 7 && klass.cpIndex[refIndex].entryType == MethodRef {
 				methRefIndex := klass.cpIndex[refIndex].slot
 				if methRefIndex < 0 || methRefIndex >= len(klass.methodRefs) {
-					return cfe("Reference index for MethodHandle at CP entry #" + strconv.Itoa(j) +
-						" points to an invalid MethodRef: " + strconv.Itoa(methRefIndex))
+					return cfeCode(CfeMethodHandleRefIndex, j, methRefIndex)
 				}
 
 				if methodName == "<init>" || methodName == "<clinit>" {
-					return cfe("Invalid class name for MethodHandle at CP entry #" + strconv.Itoa(j) +
-						" : " + methodName)
+					return cfeCode(CfeMethodHandleInit, j, methodName)
 				}
 			}
 
@@ -383,14 +335,11 @@ func formatCheckConstantPool(klass *ParsedClass) error {
 			mte := klass.methodTypes[whichMethType]
 			utf8 := klass.cpIndex[mte]
 			if utf8.entryType != UTF8 || utf8.slot < 0 || utf8.slot > len(klass.utf8Refs)-1 {
-				return cfe("MethodType at CP entry #" + strconv.Itoa(j) +
-					" has an invalid description index: " + strconv.Itoa(utf8.slot))
+				return cfeCode(CfeMethodTypeDescIndex, j, utf8.slot)
 			}
 			methType := klass.utf8Refs[utf8.slot]
 			if !strings.HasPrefix(methType.content, "(") {
-				return cfe("MethodType at CP entry #" + strconv.Itoa(j) +
-					" does not point to a type that starts with an open parenthesis. Got: " +
-					methType.content)
+				return cfeCode(CfeMethodTypeDesc, j, methType.content)
 			}
 		case Dynamic:
 			// Like InvokeDynamic, Dynamic is a unique kind of entry. The first field,
@@ -403,45 +352,38 @@ func formatCheckConstantPool(klass *ParsedClass) error {
 			// the descriptor in the nameAndType points to a field.
 			whichDyn := entry.slot
 			if whichDyn >= len(klass.dynamics) {
-				return cfe("The dynamic entry at CP[" + strconv.Itoa(j) + "] " +
-					"points to a non-existent dynamic slot: " + strconv.Itoa(entry.slot))
+				return cfeCode(CfeDynamicIndex, j, entry.slot)
 			}
 			dyn := klass.dynamics[whichDyn]
 
 			bootstrap := dyn.bootstrapIndex
 			if bootstrap >= klass.bootstrapCount {
-				return cfe("The bootstrap index in dynamic at CP[" + strconv.Itoa(j) +
-					"] is invalid: " + strconv.Itoa(bootstrap))
+				return cfeCode(CfeDynamicBootstrap, j, bootstrap)
 			}
 
 			// just trying to access it to make sure it's actually there and accessible.
 			bse := klass.bootstraps[bootstrap]
 			if !(bse.methodRef > 0) {
-				return cfe("Invalid methodRef in bootstrap method[" + strconv.Itoa(bootstrap) + "]")
+				return cfeCode(CfeBootstrapMethodRef, bootstrap)
 			}
 
 			nAndT := dyn.nameAndType
 			if nAndT < 1 || nAndT > len(klass.cpIndex)-1 {
-				return cfe("The entry number into klass.dynamics[] at CP entry #" +
-					strconv.Itoa(j) + " is invalid: " + strconv.Itoa(nAndT))
+				return cfeCode(CfeDynamicNameAndTypeIndex, j, nAndT)
 			}
 			if klass.cpIndex[nAndT].entryType != NameAndType {
-				return cfe("NameAndType index at CP entry #" + strconv.Itoa(j) +
-					" (dynamic) points to an entry that's not NameAndType: " +
-					strconv.Itoa(klass.cpIndex[nAndT].entryType))
+				return cfeCode(CfeDynamicNameAndType, j, klass.cpIndex[nAndT].entryType)
 			}
 
 			natSlot := klass.cpIndex[nAndT].slot
 			nat := klass.nameAndTypes[natSlot] // gets the actual nameAndType entry
 			desc, err := FetchUTF8string(klass, nat.descriptorIndex)
 			if err != nil {
-				return cfe("Descriptor in nameAndType entry of dynamic CP entry #" +
-					strconv.Itoa(j) + " is invalid: " + strconv.Itoa(nat.descriptorIndex))
+				return cfeCode(CfeDynamicDescIndex, j, nat.descriptorIndex)
 			}
 
 			if validateFieldDesc(desc) != nil {
-				return cfe("Descriptor in nameAndType entry of dynamic CP entry #" +
-					strconv.Itoa(j) + " is an invalid field descriptor: " + desc)
+				return cfeCode(CfeDynamicDesc, j, desc)
 			}
 
 		case InvokeDynamic:
@@ -454,45 +396,38 @@ func formatCheckConstantPool(klass *ParsedClass) error {
 			// will be checked later/earlier in this format check.
 			whichInvDyn := entry.slot
 			if whichInvDyn >= len(klass.invokeDynamics) {
-				return cfe("The invokeDynamic entry at CP[" + strconv.Itoa(j) + "] " +
-					"points to a non-existent invokeDynamic slot: " + strconv.Itoa(entry.slot))
+				return cfeCode(CfeInvokeDynamicIndex, j, entry.slot)
 			}
 			invDyn := klass.invokeDynamics[whichInvDyn]
 
 			bootstrap := invDyn.bootstrapIndex
 			if bootstrap >= klass.bootstrapCount {
-				return cfe("The bootstrap index in InvokeDynamic at CP[" + strconv.Itoa(j) +
-					"] is invalid: " + strconv.Itoa(bootstrap))
+				return cfeCode(CfeInvokeDynamicBootstrap, j, bootstrap)
 			}
 
 			// just trying to access it to make sure it's actually there and accessible.
 			bse := klass.bootstraps[bootstrap]
 			if !(bse.methodRef > 0) {
-				return cfe("Invalid methodRef in bootstrap method[" + strconv.Itoa(bootstrap) + "]")
+				return cfeCode(CfeBootstrapMethodRef, bootstrap)
 			}
 
 			nAndTslot := invDyn.nameAndType
 			if nAndTslot < 1 || nAndTslot > len(klass.cpIndex)-1 {
-				return cfe("The entry number into klass.InvokeDynamics[] at CP entry #" +
-					strconv.Itoa(j) + " is invalid: " + strconv.Itoa(nAndTslot))
+				return cfeCode(CfeInvokeDynamicNATIndex, j, nAndTslot)
 			}
 			if klass.cpIndex[nAndTslot].entryType != NameAndType {
-				return cfe("NameAndType index at CP entry #" + strconv.Itoa(j) +
-					" (InvokeDynamic) points to an entry that's not NameAndType: " +
-					strconv.Itoa(klass.cpIndex[nAndTslot].entryType))
+				return cfeCode(CfeInvokeDynamicNAT, j, klass.cpIndex[nAndTslot].entryType)
 			}
 
 			natSlot := klass.cpIndex[nAndTslot].slot
 			nat := klass.nameAndTypes[natSlot] // gets the actual nameAndType entry
 			desc, err := FetchUTF8string(klass, nat.descriptorIndex)
 			if err != nil {
-				return cfe("Descriptor in nameAndType entry of dynamic CP entry #" +
-					strconv.Itoa(j) + " is invalid: " + strconv.Itoa(nat.descriptorIndex))
+				return cfeCode(CfeInvokeDynamicDescIndex, j, nat.descriptorIndex)
 			}
 
 			if validateMethodDesc(desc) != nil {
-				return cfe("Descriptor in nameAndType entry of dynamic CP entry #" +
-					strconv.Itoa(j) + " is an invalid method descriptor: " + desc)
+				return cfeCode(CfeInvokeDynamicDesc, j, desc)
 			}
 		case Module:
 			// if there's a module entry, the module name has already been fetched and
@@ -502,10 +437,10 @@ func formatCheckConstantPool(klass *ParsedClass) error {
 			// Note: the test for minimum Java 9 version and the limit of at most one
 			// Module entry is enforced in the original CP parsing (see cpParser.go)
 			if !klass.classIsModule {
-				return cfe("Module CP entry must appear only in class with ACC_MODULE set.")
+				return cfeCode(CfeModuleNotInModule)
 			}
-			if checkModuleName(klass.moduleName) != nil {
-				return errors.New("") // the error message will already have been displayed
+			if err := checkModuleName(klass.moduleName); err != nil {
+				return err // the error message will already have been displayed
 			}
 		case Package:
 			// if there's a package entry, the package name has already been fetched and
@@ -515,12 +450,12 @@ func formatCheckConstantPool(klass *ParsedClass) error {
 			// Note: the test for minimum Java 9 version and the limit of at most one
 			// Package entry is enforced in the original CP parsing (see cpParser.go)
 			if !klass.classIsModule {
-				return cfe("Package CP entry must appear only in class with ACC_MODULE set.")
+				return cfeCode(CfePackageNotInModule)
 			}
 
 			// packages have the same restrictions on the names as modules.
-			if checkPackageName(klass.packageName) != nil {
-				return errors.New("") // the error message will already have been displayed
+			if err := checkPackageName(klass.packageName); err != nil {
+				return err // the error message will already have been displayed
 			}
 		default:
 			continue
@@ -538,19 +473,19 @@ func formatCheckFields(klass *ParsedClass) error {
 	for i, f := range klass.fields {
 		// f.name points to a UTF8 entry in klass.utf8refs, so check it's in a valid range
 		if f.name < 0 || f.name >= len(klass.utf8Refs) {
-			return cfe("Invalid index to UTF8 string for field name in field #" + strconv.Itoa(i))
+			return cfeCode(CfeFieldNameIndex, i)
 		}
 		fName := klass.utf8Refs[f.name].content
 
 		// f.description points to a UTF8 entry in klass.utf8refs, so check it's in a valid range
 		if f.description < 0 || f.description >= len(klass.utf8Refs) {
-			return cfe("Invalid index for UTF8 string containing description of field " + fName)
+			return cfeCode(CfeFieldDescIndex, fName)
 		}
 		fDesc := klass.utf8Refs[f.description].content
 
 		fNameBytes := []byte(fName)
 		if fNameBytes[0] >= '0' && fNameBytes[0] <= '9' {
-			return cfe("Invalid field name in format check (starts with a digit): " + fName)
+			return cfeCode(CfeFieldNameDigit, fName)
 		}
 
 		// check that there is no leading, trailing, or embedded whitespace
@@ -565,14 +500,14 @@ func formatCheckFields(klass *ParsedClass) error {
 				'\u0020', // space
 				'\u0085', // next line
 				'\u00A0': // no-break space
-				return cfe("Invalid field name in format check (contains whitespace): " + fName)
+				return cfeCode(CfeFieldNameWhitespace, fName)
 			default:
 				continue
 			}
 		}
 
 		if validateFieldDesc(fDesc) != nil {
-			return cfe("Field " + fName + " has an invalid description string: " + fDesc)
+			return cfeCode(CfeFieldDesc, fName, fDesc)
 		}
 	}
 	return nil
@@ -647,12 +582,12 @@ func validateUnqualifiedName(name string, method bool) bool {
 // see checkPackageName() for the same check (but different error messages)
 func checkModuleName(name string) error {
 	if name == "" {
-		return cfe("Expected a module/package name, but none was found.")
+		return cfeCode(CfeModuleNameMissing)
 	}
 
 	bArr := []byte(name)
 	if bArr[0] == '@' || bArr[0] == ':' { // a @ or : must be escaped, so can't start name
-		return cfe("Module/Package name " + name + " contains an illegal character")
+		return cfeCode(CfeModuleNameStart, name)
 	}
 
 	invalidName := false
@@ -676,7 +611,7 @@ func checkModuleName(name string) error {
 			}
 		}
 		if invalidName {
-			return cfe("Module name " + name + " contains an illegal character")
+			return cfeCode(CfeModuleNameChar, name)
 		}
 	}
 	return nil
@@ -688,12 +623,12 @@ func checkModuleName(name string) error {
 // see checkModuleName() for the same check (but different error messages)
 func checkPackageName(name string) error {
 	if name == "" {
-		return cfe("Expected a package name, but none was found.")
+		return cfeCode(CfePackageNameMissing)
 	}
 
 	bArr := []byte(name)
 	if bArr[0] == '@' || bArr[0] == ':' { // a @ or : must be escaped, so can't start name
-		return cfe("Package name " + name + " contains an illegal character")
+		return cfeCode(CfePackageNameChar, name)
 	}
 
 	invalidName := false
@@ -717,7 +652,7 @@ func checkPackageName(name string) error {
 			}
 		}
 		if invalidName {
-			return cfe("Package name " + name + " contains an illegal character")
+			return cfeCode(CfePackageNameChar, name)
 		}
 	}
 	return nil
@@ -733,16 +668,13 @@ func formatCheckClassAttributes(klass *ParsedClass) error {
 		for i := 0; i < len(klass.bootstraps); i++ {
 			bsm := klass.bootstraps[i]
 			if klass.cpIndex[bsm.methodRef].entryType != MethodHandle {
-				return cfe("MethodRef in bootstrapMethod[" + strconv.Itoa(i) + "] in class " +
-					klass.className + "should but does not point to a MethodHandle")
+				return cfeCode(CfeBootstrapNotHandle, i, klass.className)
 			}
 
 			if len(bsm.args) > 0 {
 				for j := 0; j < len(bsm.args); j++ {
 					if !validateItemIsLodable(klass, bsm.args[j]) {
-						return cfe("Bootstrap method argument[" + strconv.Itoa(j) + "] in class " +
-							klass.className + " bootstrap method #[" + strconv.Itoa(i) + "] " +
-							"should be but is not a loadable constant")
+						return cfeCode(CfeBootstrapArgLoadable, j, klass.className, i)
 					}
 				}
 			}
@@ -773,28 +705,23 @@ func validateItemIsLodable(klass *ParsedClass, arg int) bool {
 // checking that a count field holds the correct number, etc.
 func formatCheckStructure(klass *ParsedClass) error {
 	if klass.cpCount != len(klass.cpIndex) {
-		return cfe("CP count: " + strconv.Itoa(klass.cpCount) +
-			" is not equal to actual size of CP: " + strconv.Itoa(len(klass.cpIndex)))
+		return cfeCode(CfeCPCount, klass.cpCount, len(klass.cpIndex))
 	}
 
 	if klass.interfaceCount != len(klass.interfaces) {
-		return cfe("Expected " + strconv.Itoa(klass.interfaceCount) + " interfaces. Got: " +
-			strconv.Itoa(len(klass.interfaces)))
+		return cfeCode(CfeInterfaceCount, klass.interfaceCount, len(klass.interfaces))
 	}
 
 	if klass.methodCount != len(klass.methods) {
-		return cfe("Expected " + strconv.Itoa(klass.methodCount) + " methods. Got: " +
-			strconv.Itoa(len(klass.methods)))
+		return cfeCode(CfeMethodCount, klass.methodCount, len(klass.methods))
 	}
 
 	if klass.attribCount != len(klass.attributes) {
-		return cfe("Expected " + strconv.Itoa(klass.attribCount) + " class attributes. Got: " +
-			strconv.Itoa(len(klass.attributes)))
+		return cfeCode(CfeAttributeCount, klass.attribCount, len(klass.attributes))
 	}
 
 	if klass.bootstrapCount != len(klass.bootstraps) {
-		return cfe("Expected " + strconv.Itoa(klass.bootstrapCount) + " bootstrap methods. Got: " +
-			strconv.Itoa(len(klass.bootstraps)))
+		return cfeCode(CfeBootstrapCount, klass.bootstrapCount, len(klass.bootstraps))
 	}
 
 	return nil
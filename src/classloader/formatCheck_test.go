@@ -11,7 +11,6 @@ import (
 	"jacobin/src/globals"
 	"jacobin/src/stringPool"
 	"jacobin/src/trace"
-	"os"
 	"strings"
 	"testing"
 )
@@ -64,14 +63,9 @@ func TestInvalidCPsize(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -88,32 +82,20 @@ func TestInvalidCPsize(t *testing.T) {
 		t.Error("Did not get error for mismatch between CP count field and actual number of CP entries")
 	}
 
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
+	msg := errOut.String()
 
-	if !strings.Contains(msg, "Error in size of constant pool") {
+	if !strings.Contains(msg, string(CfeCPSize)) {
 		t.Error("Did not get expected error msg for invalid CP count. Got: " + msg)
 	}
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 func TestMissingInitialDummyEntry(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -128,32 +110,20 @@ func TestMissingInitialDummyEntry(t *testing.T) {
 		t.Error("Did not get error for missing initial dummy entry")
 	}
 
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
+	msg := errOut.String()
 
-	if !strings.Contains(msg, "Missing dummy entry in first slot of constant pool") {
+	if !strings.Contains(msg, string(CfeCPMissingFirstDummy)) {
 		t.Error("Did not get expected error msg for missing initial CP dummy entry. Got: " + msg)
 	}
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 func TestInvalidIndexInUTF8Entry(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -170,32 +140,20 @@ func TestInvalidIndexInUTF8Entry(t *testing.T) {
 		t.Error("Expected error for incorrect ut8Refs index, but got none.")
 	}
 
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
+	msg := errOut.String()
 
-	if !strings.Contains(msg, "points to invalid UTF8 entry") {
+	if !strings.Contains(msg, string(CfeUTF8Index)) {
 		t.Error("Did not get expected error msg. Got: " + msg)
 	}
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 func TestInvalidStringInUTF8Entry(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -213,32 +171,20 @@ func TestInvalidStringInUTF8Entry(t *testing.T) {
 		t.Error("Expected error for invalid UTF8 string, but got none.")
 	}
 
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
+	msg := errOut.String()
 
-	if !strings.Contains(msg, "contains an invalid character") {
+	if !strings.Contains(msg, string(CfeUTF8InvalidChar)) {
 		t.Error("Did not get expected error msg. Got: " + msg)
 	}
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 func TestIntConsts(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -263,32 +209,21 @@ func TestIntConsts(t *testing.T) {
 	if err != nil {
 		t.Error("Got unexpected error for valid IntConst")
 	}
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
+	msg := errOut.String()
 
 	// this is the error message left over from the first test of the invalid entry
-	if !strings.Contains(msg, "invalid entry in CP intConsts") {
+	if !strings.Contains(msg, string(CfeIntConstIndex)) {
 		t.Error("Did not get expected error msg. Got: " + msg)
 	}
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 func TestFloatConsts(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -313,18 +248,12 @@ func TestFloatConsts(t *testing.T) {
 	if err != nil {
 		t.Error("Got unexpected error for valid FloatConst")
 	}
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
+	msg := errOut.String()
 
 	// this is the error message left over from the first test of the invalid entry
-	if !strings.Contains(msg, "invalid entry in CP floats") {
+	if !strings.Contains(msg, string(CfeFloatConstIndex)) {
 		t.Error("Did not get expected error msg. Got: " + msg)
 	}
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 // tests LongConst and the entry afterwards (which should be a dummy entry)
@@ -332,14 +261,9 @@ func TestMissingDummyEntryAfterLongConst(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -363,33 +287,21 @@ func TestMissingDummyEntryAfterLongConst(t *testing.T) {
 		t.Error("Got unexpected error with dummy entry after LongConst.")
 	}
 
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
+	msg := errOut.String()
 
 	// tests the remaining error string from the failed test.
-	if !strings.Contains(msg, "Missing dummy entry") {
+	if !strings.Contains(msg, string(CfeLongMissingDummy)) {
 		t.Error("Did not get expected error msg. Got: " + msg)
 	}
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 func TestDoubleConst(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -414,19 +326,12 @@ func TestDoubleConst(t *testing.T) {
 		t.Error("Got unexpected error with dummy entry after DoubleConst.")
 	}
 
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
+	msg := errOut.String()
 
 	// tests the remaining error string from the failed test.
-	if !strings.Contains(msg, "Missing dummy entry") {
+	if !strings.Contains(msg, string(CfeDoubleMissingDummy)) {
 		t.Error("Did not get expected error msg. Got: " + msg)
 	}
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 // StringConsts are just indices into the UTF8 entries. So, we just make
@@ -435,14 +340,9 @@ func TestStringConsts(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -467,32 +367,21 @@ func TestStringConsts(t *testing.T) {
 	if err != nil {
 		t.Error("Got unexpected error for valid StringConst")
 	}
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
+	msg := errOut.String()
 
 	// this is the error message left over from the first test of the invalid entry
-	if !strings.Contains(msg, "invalid entry in CP utf8Refs") {
+	if !strings.Contains(msg, string(CfeStringConstIndex)) {
 		t.Error("Did not get expected error msg. Got: " + msg)
 	}
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 func TestInvalidFieldRef(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -512,32 +401,20 @@ func TestInvalidFieldRef(t *testing.T) {
 		t.Error("Expected error for invalid class index in FieldRef entry, but got none.")
 	}
 
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
+	msg := errOut.String()
 
-	if !strings.Contains(msg, "points to an invalid entry in ClassRefs") {
+	if !strings.Contains(msg, string(CfeFieldRefClass)) {
 		t.Error("Did not get expected error msg. Got: " + msg)
 	}
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 func TestFieldRefWithInvalidNameAndTypeIndex(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -558,18 +435,11 @@ func TestFieldRefWithInvalidNameAndTypeIndex(t *testing.T) {
 		t.Error("Expected error for invalid nameAndType index in FieldRef entry, but got none.")
 	}
 
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
+	msg := errOut.String()
 
-	if !strings.Contains(msg, "points to an invalid entry in nameAndType") {
+	if !strings.Contains(msg, string(CfeFieldRefNameAndType)) {
 		t.Error("Did not get expected error msg. Got: " + msg)
 	}
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 // a MethodRef points to a class index and a nameAndType index. The name in
@@ -579,14 +449,9 @@ func TestMethodRefWithInvalidMethodName(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -617,18 +482,11 @@ func TestMethodRefWithInvalidMethodName(t *testing.T) {
 		t.Error("Expected error for invalid method name in MethodRef's nameAndType entry, but got none.")
 	}
 
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
+	msg := errOut.String()
 
-	if !strings.Contains(msg, "an entry with an invalid method name") {
+	if !strings.Contains(msg, string(CfeMethodRefInvalidName)) {
 		t.Error("Did not get expected error msg. Got: " + msg)
 	}
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 // this test validates both InterfaceRefs and NameAndType refs.
@@ -636,14 +494,9 @@ func TestValidInterfaceRefEntry(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -678,18 +531,11 @@ func TestValidInterfaceRefEntry(t *testing.T) {
 		t.Error("Got but did not expect error in test of valid InterfaceRef.")
 	}
 
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
+	msg := errOut.String()
 
 	if len(msg) != 0 {
 		t.Error("Got unexpected output to stderr: " + msg)
 	}
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 // Make sure that all the intricacies of MethodHandles pass the format check
@@ -698,14 +544,9 @@ func TestValidMethodHandleEntry(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -749,18 +590,11 @@ func TestValidMethodHandleEntry(t *testing.T) {
 		t.Error("Got but did not expect error in test of valid MethodHandle with.")
 	}
 
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
+	msg := errOut.String()
 
 	if len(msg) != 0 {
 		t.Error("Got unexpected output to stderr: " + msg)
 	}
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 // MethodHandles with reference kind 1-4 need to point to a FieldRef
@@ -769,14 +603,9 @@ func TestMethodHandle4PointsToFieldRef(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -818,18 +647,11 @@ func TestMethodHandle4PointsToFieldRef(t *testing.T) {
 		t.Error("Expected error in test of invalid MethodHandle but got none.")
 	}
 
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
+	msg := errOut.String()
 
-	if !strings.Contains(msg, "which does not point to a FieldRef") {
+	if !strings.Contains(msg, string(CfeMethodHandleFieldRef)) {
 		t.Error("Got unexpected output to stderr: " + msg)
 	}
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 // method handles with refKind == 6, can point to an interface if
@@ -839,14 +661,9 @@ func TestValidMethodHandlePointingToInterface(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -908,18 +725,11 @@ func TestValidMethodHandlePointingToInterface(t *testing.T) {
 			" pointint to an interface and Java version of 50, but did not get one")
 	}
 
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
+	msg := errOut.String()
 
-	if !strings.Contains(msg, "or in Java version 52 or later") {
+	if !strings.Contains(msg, string(CfeMethodHandleInvoke)) {
 		t.Error("Got unexpected output error message: " + msg)
 	}
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 // MethodHandles refKind = 8 must have a method name of "<init>"
@@ -998,14 +808,9 @@ func TestInvalidMethodHandleRefKind9(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -1047,32 +852,20 @@ func TestInvalidMethodHandleRefKind9(t *testing.T) {
 		t.Error("Expected error for ReferenceIndex not pointing to Interface, but got none. ")
 	}
 
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
+	msg := errOut.String()
 
-	if !strings.Contains(msg, "reference kind  of 9 which does not point to an interface") {
+	if !strings.Contains(msg, string(CfeMethodHandleInterface)) {
 		t.Error("Got unexpected error message: " + msg)
 	}
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 func TestValidMethodType(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -1103,32 +896,20 @@ func TestValidMethodType(t *testing.T) {
 			" string that did not begin with '('")
 	}
 
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
+	msg := errOut.String()
 
-	if !strings.Contains(msg, "not point to a type that starts with an open parenthesis") {
+	if !strings.Contains(msg, string(CfeMethodTypeDesc)) {
 		t.Error("Got unexpected output error message: " + msg)
 	}
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 func TestDynamics(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// first create the Bootstrap Method we'll need to point to
 	klass := ParsedClass{}
@@ -1211,13 +992,7 @@ func TestDynamics(t *testing.T) {
 		t.Error("Expected error for invalid dynamic CP entry, but got none.")
 	}
 
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
-	_ = wout.Close()
-	os.Stdout = normalStdout
+	msg := errOut.String()
 
 	if err != nil {
 		println(msg)
@@ -1228,14 +1003,9 @@ func TestValidInvokeDynamic(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// first create the Bootstrap Method we'll need to point to
 	klass := ParsedClass{}
@@ -1309,13 +1079,7 @@ func TestValidInvokeDynamic(t *testing.T) {
 		t.Error("Unexpected error in testing InvokeDynamic CP entry")
 	}
 
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
-	_ = wout.Close()
-	os.Stdout = normalStdout
+	msg := errOut.String()
 
 	if err != nil {
 		println(msg)
@@ -1328,14 +1092,9 @@ func TestInvalidInvokeDynamic(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -1349,32 +1108,20 @@ func TestInvalidInvokeDynamic(t *testing.T) {
 		t.Error("Did not get expected error for missing InvokeDynamic.")
 	}
 
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stderr = normalStderr
-	msg := string(out[:])
+	msg := errOut.String()
 
-	if !strings.Contains(msg, "points to a non-existent invokeDynamic slot") {
+	if !strings.Contains(msg, string(CfeInvokeDynamicIndex)) {
 		t.Error("Did not get the expected error message for missing InvokeDynamic. Got: " + msg)
 	}
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 func TestModuleNames_Test0(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -1385,33 +1132,18 @@ func TestModuleNames_Test0(t *testing.T) {
 
 	klass.moduleName = "\\@valid"
 	if checkModuleName(klass.moduleName) != nil {
-		_ = w.Close()
-		out, _ := io.ReadAll(r)
-		msg := string(out[:])
+		msg := errOut.String()
 		t.Error("Unexpected error occurred with valid module name: \\@valid\n" +
 			"Error message: " + msg)
-	} else {
-		_ = w.Close()
 	}
-
-	os.Stderr = normalStderr
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 func TestModuleNames_Test1(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	_, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// keep the error messages out of the test output
+	globals.SetTraceWriter(io.Discard)
 
 	if checkModuleName("") == nil {
 		t.Error("Expected error on test of empty module name, but got none")
@@ -1440,26 +1172,15 @@ func TestModuleNames_Test1(t *testing.T) {
 	if checkModuleName("goodname") != nil {
 		t.Error("Expected no error in module name 'goodname', but got one")
 	}
-
-	os.Stderr = normalStderr
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 func TestCPModuleNames(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	// _, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -1472,34 +1193,19 @@ func TestCPModuleNames(t *testing.T) {
 	klass.classIsModule = true
 
 	if formatCheckConstantPool(&klass) != nil {
-		_ = w.Close()
-		out, _ := io.ReadAll(r)
-		msg := string(out[:])
+		msg := errOut.String()
 		t.Error("Unexpected error occurred with valid module name: \\@valid\n" +
 			"Error message: " + msg)
-	} else {
-		_ = w.Close()
 	}
-
-	os.Stderr = normalStderr
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 func TestCPPackageNames(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	// _, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// capture the error messages
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -1512,34 +1218,18 @@ func TestCPPackageNames(t *testing.T) {
 	klass.classIsModule = true
 
 	if formatCheckConstantPool(&klass) != nil {
-		_ = w.Close()
-		out, _ := io.ReadAll(r)
-		msg := string(out[:])
+		msg := errOut.String()
 		t.Error("Unexpected error occurred with valid package name: \\@valid\n" +
 			"Error message: " + msg)
-	} else {
-		_ = w.Close()
 	}
-
-	os.Stderr = normalStderr
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 func TestPackageName(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	_, w, _ := os.Pipe()
-	// _, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// keep the error messages out of the test output
+	globals.SetTraceWriter(io.Discard)
 
 	if checkPackageName("") == nil {
 		t.Error("Expected error on test of empty package name, but got none")
@@ -1568,12 +1258,6 @@ func TestPackageName(t *testing.T) {
 	if checkPackageName("goodname") != nil {
 		t.Error("Expected no error in package name 'goodname', but got one")
 	}
-
-	_ = w.Close()
-	os.Stderr = normalStderr
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 // Tests module name without using CP records. Identical logic to TestPackageName(), except
@@ -1582,15 +1266,8 @@ func TestModuleName(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to capture results from stderr
-	normalStderr := os.Stderr
-	_, w, _ := os.Pipe()
-	// _, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// keep the error messages out of the test output
+	globals.SetTraceWriter(io.Discard)
 
 	if checkModuleName("") == nil {
 		t.Error("Expected error on test of empty module name, but got none")
@@ -1611,12 +1288,6 @@ func TestModuleName(t *testing.T) {
 	if checkModuleName("goodname") != nil {
 		t.Error("Expected no error in module name 'goodnae', but got one")
 	}
-
-	_ = w.Close()
-	os.Stderr = normalStderr
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 // field names in Java cannot begin with a digit and they cannot contain
@@ -1626,14 +1297,8 @@ func TestInvalidFieldNames(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to avoid noisy output
-	normalStderr := os.Stderr
-	_, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// keep the error messages out of the test output
+	globals.SetTraceWriter(io.Discard)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -1665,13 +1330,6 @@ func TestInvalidFieldNames(t *testing.T) {
 	if err == nil {
 		t.Error("Did not get expected error for field name starting with digit")
 	}
-
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	os.Stderr = normalStderr
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 // the field description must start with one only a few characters, of which
@@ -1681,14 +1339,8 @@ func TestInvalidFieldDescription(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to avoid noisy output
-	normalStderr := os.Stderr
-	_, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// keep the error messages out of the test output
+	globals.SetTraceWriter(io.Discard)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -1722,13 +1374,6 @@ func TestInvalidFieldDescription(t *testing.T) {
 		t.Error("Did not get expected error for empty field description for " +
 			"field: validName")
 	}
-
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	os.Stderr = normalStderr
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 func TestMethodDescription(t *testing.T) {
@@ -1749,14 +1394,8 @@ func TestCheckStructure(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to avoid noisy output
-	normalStderr := os.Stderr
-	_, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// keep the error messages out of the test output
+	globals.SetTraceWriter(io.Discard)
 
 	pClass := ParsedClass{}
 
@@ -1794,13 +1433,6 @@ func TestCheckStructure(t *testing.T) {
 		t.Error("Expecting error in mismatch of bootstrapCount and bootstraps.len, but got none")
 	}
 	pClass.bootstrapCount = 0
-
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	os.Stderr = normalStderr
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 // unqualified names in Java have a set of restrictions on the syntax, which
@@ -1839,14 +1471,8 @@ func TestStructuralValidation(t *testing.T) {
 	globals.InitGlobals("test")
 	trace.Init()
 
-	// redirect stderr & stdout to avoid noisy output
-	normalStderr := os.Stderr
-	_, w, _ := os.Pipe()
-	os.Stderr = w
-
-	normalStdout := os.Stdout
-	_, wout, _ := os.Pipe()
-	os.Stdout = wout
+	// keep the error messages out of the test output
+	globals.SetTraceWriter(io.Discard)
 
 	// variables we'll need.
 	klass := ParsedClass{}
@@ -1889,13 +1515,6 @@ func TestStructuralValidation(t *testing.T) {
 		t.Error("Did not get expected error for mistmatch between attribCount and " +
 			"total number of class attributes")
 	}
-
-	// restore stderr and stdout to what they were before
-	_ = w.Close()
-	os.Stderr = normalStderr
-
-	_ = wout.Close()
-	os.Stdout = normalStdout
 }
 
 func TestLoadableItem(t *testing.T) {
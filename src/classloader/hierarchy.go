@@ -0,0 +1,213 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package classloader
+
+import (
+	"fmt"
+	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"sort"
+	"strings"
+)
+
+// This file contains the queries on the hierarchy of loaded classes: whether one class
+// is a subclass of another or implements an interface, whether a value of one type can
+// be assigned to another (used by CHECKCAST and INSTANCEOF) or stored in an array (used
+// by AASTORE and System.arraycopy()), and which loaded classes extend a class or
+// implement an interface (class hierarchy analysis, used by tooling and diagnostics).
+// All the queries look only at the classes in the method area: a class that has not been
+// loaded is not found, and it does not make any other class its subclass.
+
+// IsSubclassOf determines whether the class sub is the same as the class super
+// or is a (direct or indirect) subclass of it. Only loaded classes are examined.
+func IsSubclassOf(sub, super string) bool {
+	clName := sub
+	for {
+		if clName == super {
+			return true
+		}
+		if clName == types.ObjectClassName {
+			return false
+		}
+		k := MethAreaFetch(clName)
+		if k == nil || k.Data == nil {
+			return false
+		}
+		clName = *stringPool.GetStringPointer(k.Data.SuperclassIndex)
+	}
+}
+
+// ImplementsInterface determines whether the named class implements the named
+// interface, either directly, through a superinterface, or through a superclass.
+// Only loaded classes and interfaces are examined.
+func ImplementsInterface(className, iface string) bool {
+	k := MethAreaFetch(className)
+	if k == nil || k.Data == nil {
+		return false
+	}
+	for _, index := range k.Data.Interfaces {
+		name := *stringPool.GetStringPointer(uint32(index))
+		if name == iface || ImplementsInterface(name, iface) {
+			return true
+		}
+	}
+	if className == types.ObjectClassName {
+		return false
+	}
+	return ImplementsInterface(*stringPool.GetStringPointer(k.Data.SuperclassIndex), iface)
+}
+
+// IsInterface determines whether the named class is a loaded interface
+func IsInterface(className string) bool {
+	k := MethAreaFetch(className)
+	return k != nil && k.Data != nil && k.Data.Access.ClassIsInterface
+}
+
+// IsAssignableTo determines whether a reference to an instance of the class from can
+// be assigned to a variable of the type to, that is, whether to is the same class,
+// a superclass, or an interface that from implements or extends. Array types are
+// not handled here.
+func IsAssignableTo(from, to string) bool {
+	if from == to || to == types.ObjectClassName {
+		return true
+	}
+	if IsInterface(to) {
+		return ImplementsInterface(from, to)
+	}
+	return IsSubclassOf(from, to)
+}
+
+// ArrayComponentType returns the type of the elements of an array of references:
+// "[Ljava/lang/String;" yields "java/lang/String" and "[[I" yields "[I". The arrays made
+// without an element type ("[L") yield "".
+func ArrayComponentType(arrayType string) string {
+	if strings.HasPrefix(arrayType, types.RefArray) {
+		return strings.TrimSuffix(arrayType[len(types.RefArray):], ";")
+	}
+	return strings.TrimPrefix(arrayType, types.Array)
+}
+
+// TypeNameOf returns the class name of an object or, for an array, its descriptor. (The
+// class name of an array made by object.Make2DimArray() is that of its innermost arrays,
+// so the descriptor is taken from its value field.)
+func TypeNameOf(obj *object.Object) string {
+	className := *stringPool.GetStringPointer(obj.KlassName)
+	if strings.HasPrefix(className, types.Array) {
+		return obj.FieldTable["value"].Ftype
+	}
+	return className
+}
+
+// IsAssignableToComponent determines whether a value of the type typeName, a class name or
+// an array descriptor, can be stored in an array whose elements are of the type component,
+// as AASTORE and System.arraycopy() require. Arrays can be stored as Objects, Cloneables,
+// and Serializables, and in arrays of arrays whose components they can be assigned to. A
+// class can be checked only if it and the component type have been loaded; if either
+// hasn't (as for the classes implemented in Go), the value is taken to be assignable.
+func IsAssignableToComponent(typeName, component string) bool {
+	if component == "" || typeName == component || component == types.ObjectClassName {
+		return true
+	}
+	if strings.HasPrefix(typeName, types.Array) {
+		if component == "java/lang/Cloneable" || component == "java/io/Serializable" {
+			return true
+		}
+		if isRefArrayType(typeName) && isRefArrayType(component) {
+			return IsAssignableToComponent(ArrayComponentType(typeName), ArrayComponentType(component))
+		}
+		return false
+	}
+	if strings.HasPrefix(component, types.Array) {
+		return false
+	}
+	if MethAreaFetch(typeName) == nil || MethAreaFetch(component) == nil {
+		return true
+	}
+	return IsAssignableTo(typeName, component)
+}
+
+// determines whether the descriptor is that of an array of references, including arrays
+// of arrays
+func isRefArrayType(arrayType string) bool {
+	return strings.HasPrefix(arrayType, types.RefArray) || strings.HasPrefix(arrayType, types.MultiArray)
+}
+
+// loadedClasses returns the loaded classes and interfaces in the method area, leaving
+// out the synthetic array classes and classes that are still being loaded
+func loadedClasses() map[string]*Klass {
+	classes := make(map[string]*Klass)
+	MethAreaMutex.RLock()
+	MethArea.Range(func(key, value any) bool {
+		name := key.(string)
+		k := value.(*Klass)
+		if !strings.HasPrefix(name, types.Array) && k.Status != 'I' && k.Data != nil {
+			classes[name] = k
+		}
+		return true
+	})
+	MethAreaMutex.RUnlock()
+	return classes
+}
+
+// Subclasses returns the names of the loaded classes that are direct or indirect
+// subclasses of the named class, in alphabetical order
+func Subclasses(className string) []string {
+	subclasses := []string{}
+	for name, k := range loadedClasses() {
+		if name != className && !k.Data.Access.ClassIsInterface && IsSubclassOf(name, className) {
+			subclasses = append(subclasses, name)
+		}
+	}
+	sort.Strings(subclasses)
+	return subclasses
+}
+
+// ImplementorsOf returns the names of the loaded classes that implement the named
+// interface, whether directly, through a superinterface, or through a superclass,
+// in alphabetical order. Interfaces that extend it are not included.
+func ImplementorsOf(interfaceName string) []string {
+	implementors := []string{}
+	for name, k := range loadedClasses() {
+		if !k.Data.Access.ClassIsInterface && ImplementsInterface(name, interfaceName) {
+			implementors = append(implementors, name)
+		}
+	}
+	sort.Strings(implementors)
+	return implementors
+}
+
+// ClassHierarchy returns a diagram of the loaded subclasses of the named class, one class
+// per line, indented under its superclass, in the manner of jcmd's VM.class_hierarchy
+func ClassHierarchy(className string) string {
+	classes := loadedClasses()
+	children := make(map[string][]string)
+	for name, k := range classes {
+		if name == types.ObjectClassName || k.Data.Access.ClassIsInterface {
+			continue
+		}
+		super := *stringPool.GetStringPointer(k.Data.SuperclassIndex)
+		children[super] = append(children[super], name)
+	}
+
+	var sb strings.Builder
+	var addClass func(name string, depth int)
+	addClass = func(name string, depth int) {
+		sb.WriteString(strings.Repeat("|--", depth))
+		if k, ok := classes[name]; ok {
+			sb.WriteString(fmt.Sprintf("%s/%s\n", strings.ReplaceAll(name, "/", "."), k.Loader))
+		} else {
+			sb.WriteString(strings.ReplaceAll(name, "/", ".") + " (not loaded)\n")
+		}
+		sort.Strings(children[name])
+		for _, child := range children[name] {
+			addClass(child, depth+1)
+		}
+	}
+	addClass(className, 0)
+	return sb.String()
+}
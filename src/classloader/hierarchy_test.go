@@ -0,0 +1,107 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package classloader
+
+import (
+	"jacobin/src/globals"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// loads this hierarchy into the method area:
+//
+//	interface hier/Shape; interface hier/Solid extends hier/Shape
+//	hier/Base implements hier/Shape
+//	hier/Middle extends hier/Base; hier/Leaf extends hier/Middle implements hier/Solid
+//	hier/Other
+func setupHierarchyTestClasses() {
+	globals.InitGlobals("test")
+	InitMethodArea()
+
+	addClass := func(name, super string, isInterface bool, interfaces ...string) {
+		k := Klass{Status: 'F', Loader: "testloader", Data: &ClData{
+			Name:            name,
+			SuperclassIndex: stringPool.GetStringIndex(&super),
+		}}
+		k.Data.Access.ClassIsInterface = isInterface
+		for _, iface := range interfaces {
+			k.Data.Interfaces = append(k.Data.Interfaces, uint16(stringPool.GetStringIndex(&iface)))
+		}
+		MethAreaInsert(name, &k)
+	}
+	addClass("hier/Shape", types.ObjectClassName, true)
+	addClass("hier/Solid", types.ObjectClassName, true, "hier/Shape")
+	addClass("hier/Base", types.ObjectClassName, false, "hier/Shape")
+	addClass("hier/Middle", "hier/Base", false)
+	addClass("hier/Leaf", "hier/Middle", false, "hier/Solid")
+	addClass("hier/Other", types.ObjectClassName, false)
+}
+
+func TestSubclasses(t *testing.T) {
+	setupHierarchyTestClasses()
+
+	if got := Subclasses("hier/Base"); !reflect.DeepEqual(got, []string{"hier/Leaf", "hier/Middle"}) {
+		t.Errorf("Subclasses(hier/Base) = %v", got)
+	}
+	if got := Subclasses("hier/Leaf"); len(got) != 0 {
+		t.Errorf("Subclasses(hier/Leaf) = %v, want none", got)
+	}
+	for _, name := range Subclasses(types.ObjectClassName) {
+		if strings.HasPrefix(name, types.Array) || name == "hier/Shape" {
+			t.Errorf("Subclasses(java/lang/Object) includes %s", name)
+		}
+	}
+}
+
+func TestImplementorsOf(t *testing.T) {
+	setupHierarchyTestClasses()
+
+	want := []string{"hier/Base", "hier/Leaf", "hier/Middle"}
+	if got := ImplementorsOf("hier/Shape"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ImplementorsOf(hier/Shape) = %v, want %v", got, want)
+	}
+	if got := ImplementorsOf("hier/Solid"); !reflect.DeepEqual(got, []string{"hier/Leaf"}) {
+		t.Errorf("ImplementorsOf(hier/Solid) = %v", got)
+	}
+}
+
+func TestIsAssignableTo(t *testing.T) {
+	setupHierarchyTestClasses()
+
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{"hier/Leaf", "hier/Base", true},
+		{"hier/Leaf", "hier/Shape", true},
+		{"hier/Middle", "hier/Solid", false},
+		{"hier/Solid", "hier/Shape", true},
+		{"hier/Base", "hier/Middle", false},
+		{"hier/Other", types.ObjectClassName, true},
+		{"hier/Other", "hier/Shape", false},
+	}
+	for _, test := range tests {
+		if got := IsAssignableTo(test.from, test.to); got != test.want {
+			t.Errorf("IsAssignableTo(%s, %s) = %v, want %v", test.from, test.to, got, test.want)
+		}
+	}
+}
+
+func TestClassHierarchy(t *testing.T) {
+	setupHierarchyTestClasses()
+
+	want := "hier.Base/testloader\n|--hier.Middle/testloader\n|--|--hier.Leaf/testloader\n"
+	if got := ClassHierarchy("hier/Base"); got != want {
+		t.Errorf("ClassHierarchy(hier/Base) =\n%s\nwant:\n%s", got, want)
+	}
+	if got := ClassHierarchy("hier/Missing"); got != "hier.Missing (not loaded)\n" {
+		t.Errorf("ClassHierarchy of a class that isn't loaded = %q", got)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package classloader
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"jacobin/src/globals"
+	"jacobin/src/trace"
+	"jacobin/src/util"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// reads the classes in java.base.jmod, skipping the benchmark if there's no JDK
+func readJavaBaseClasses(b *testing.B) map[string][]byte {
+	globals.InitGlobals("test")
+	trace.Init()
+	jmodPath := util.GetPlatform().JoinPath(globals.GetGlobalRef().JavaHome, "jmods", "java.base.jmod")
+	jmodBytes, err := os.ReadFile(jmodPath)
+	if err != nil || len(jmodBytes) < 4 {
+		b.Skipf("java.base.jmod is not available: %v", err)
+	}
+	zipReader, err := zip.NewReader(bytes.NewReader(jmodBytes[4:]), int64(len(jmodBytes)-4))
+	if err != nil {
+		b.Fatalf("could not read %s: %v", jmodPath, err)
+	}
+
+	classes := make(map[string][]byte)
+	for _, file := range zipReader.File {
+		if !strings.HasPrefix(file.Name, "classes/java/lang/") || !strings.HasSuffix(file.Name, ".class") {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			b.Fatalf("could not open %s: %v", file.Name, err)
+		}
+		classBytes, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		classes[file.Name] = classBytes
+	}
+	return classes
+}
+
+// parses and posts the java/lang classes of java.base on one thread per CPU, with a fresh
+// string pool and method area each time, as the threads of a starting JVM do
+func BenchmarkLoadJavaBaseParallel(b *testing.B) {
+	classes := readJavaBaseClasses(b)
+	_ = Init()
+
+	b.ResetTimer()
+	for range b.N {
+		globals.InitStringPool()
+		InitMethodArea()
+
+		work := make(chan string, len(classes))
+		for name := range classes {
+			work <- name
+		}
+		close(work)
+
+		var wg sync.WaitGroup
+		for range runtime.GOMAXPROCS(0) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for name := range work {
+					_, _, _ = ParseAndPostClass(&BootstrapCL, name, classes[name])
+				}
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+// the memory in use after the java/lang classes of java.base are loaded, with and
+// without -XX:+CompactConstantPools. Reported as the live heap and, where the OS
+// provides it, the resident set size.
+func BenchmarkLoadJavaBaseFootprint(b *testing.B) {
+	classes := readJavaBaseClasses(b)
+	_ = Init()
+
+	for _, compact := range []bool{false, true} {
+		name := "shared-strings"
+		if !compact {
+			name = "per-class-strings"
+		}
+		b.Run(name, func(b *testing.B) {
+			globals.GetGlobalRef().CompactCPs = compact
+			defer func() { globals.GetGlobalRef().CompactCPs = false }()
+			var heap, rss uint64
+			for range b.N {
+				globals.InitStringPool()
+				InitMethodArea()
+				runtime.GC()
+				var before runtime.MemStats
+				runtime.ReadMemStats(&before)
+
+				for name, classBytes := range classes {
+					_, _, _ = ParseAndPostClass(&BootstrapCL, name, classBytes)
+				}
+				runtime.GC()
+				var after runtime.MemStats
+				runtime.ReadMemStats(&after)
+				heap += after.HeapAlloc - min(before.HeapAlloc, after.HeapAlloc)
+				rss += residentSetSize()
+			}
+			b.ReportMetric(float64(heap)/float64(b.N), "heap-bytes")
+			if rss > 0 {
+				b.ReportMetric(float64(rss)/float64(b.N), "rss-bytes")
+			}
+		})
+	}
+}
+
+// the resident set size of this process, or 0 if /proc/self/statm can't be read
+func residentSetSize() uint64 {
+	statm, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(statm))
+	if len(fields) < 2 {
+		return 0
+	}
+	pages, _ := strconv.ParseUint(fields[1], 10, 64)
+	return pages * uint64(os.Getpagesize())
+}
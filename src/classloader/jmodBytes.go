@@ -15,7 +15,9 @@ import (
 	"jacobin/src/globals"
 	"jacobin/src/shutdown"
 	"jacobin/src/trace"
+	"jacobin/src/util"
 	"os"
+	"sort"
 )
 
 const ExpectedMagicNumber = 0x4A4D
@@ -28,7 +30,7 @@ func GetBaseJmodBytes() {
 
 	var err error
 	global := globals.GetGlobalRef()
-	jmodBasePath := global.JavaHome + string(os.PathSeparator) + "jmods" + string(os.PathSeparator) + BaseJmodFileName
+	jmodBasePath := util.GetPlatform().JoinPath(global.JavaHome, "jmods", BaseJmodFileName)
 
 	// Read the entire base jmod file contents (huge!)
 	global.JmodBaseBytes, err = os.ReadFile(jmodBasePath)
@@ -62,7 +64,7 @@ func GetClassBytes(jmodFileName string, className string) ([]byte, error) {
 	var newReaderLength int64
 
 	global := globals.GetGlobalRef()
-	jmodPath := global.JavaHome + string(os.PathSeparator) + "jmods" + string(os.PathSeparator) + jmodFileName
+	jmodPath := util.GetPlatform().JoinPath(global.JavaHome, "jmods", jmodFileName)
 	classFileName := "classes/" + className + ".class"
 
 	//fmt.Printf("DEBUG GetClassBytes: jmod=%s, class=%s\n", jmodFileName, className)
@@ -124,3 +126,40 @@ func GetClassBytes(jmodFileName string, className string) ([]byte, error) {
 	return classBytes, nil
 
 }
+
+// JDKMethod is a public or protected method of a JDK class
+type JDKMethod struct {
+	Method string // the name and descriptor, e.g., length()I
+	Native bool
+}
+
+// JDKClassMethods returns the public and protected methods of a JDK class, as read
+// from its jmod file, ordered by name. Used by --api-coverage.
+func JDKClassMethods(className string) ([]JDKMethod, error) {
+	jmodFileName := JmodMapFetch(className)
+	if jmodFileName == "" {
+		return nil, fmt.Errorf("%s is not in the JDK's jmod files", className)
+	}
+	classBytes, err := GetClassBytes(jmodFileName, className)
+	if err != nil {
+		return nil, err
+	}
+	klass, err := parse(classBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	const accPublic, accProtected, accNative = 0x0001, 0x0004, 0x0100
+	var methods []JDKMethod
+	for _, m := range klass.methods {
+		if m.accessFlags&(accPublic|accProtected) == 0 {
+			continue
+		}
+		methods = append(methods, JDKMethod{
+			Method: klass.utf8Refs[m.name].content + klass.utf8Refs[m.description].content,
+			Native: m.accessFlags&accNative != 0,
+		})
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i].Method < methods[j].Method })
+	return methods, nil
+}
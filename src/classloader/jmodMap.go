@@ -15,6 +15,7 @@ import (
 	"jacobin/src/globals"
 	"jacobin/src/shutdown"
 	"jacobin/src/trace"
+	"jacobin/src/util"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -106,7 +107,7 @@ func JmodMapInit() {
 			version := strings.TrimSuffix(name, ".gob") // get rid of trailing .gob
 			if version == global.JavaVersion {
 				// Got a match!  Build map from it.
-				gobFullPath := global.JacobinHome + string(os.PathSeparator) + name
+				gobFullPath := util.GetPlatform().JoinPath(global.JacobinHome, name)
 				if !buildMapFromGob(gobFullPath) {
 					// Gob file trouble
 					// Force re-creation
@@ -186,7 +187,7 @@ func buildMapFromJmods() {
 	jmodMapSize = 0
 
 	// Get path of jmods directory
-	dirPath := global.JavaHome + string(os.PathSeparator) + "jmods"
+	dirPath := util.GetPlatform().JoinPath(global.JavaHome, "jmods")
 
 	// Open jmods directory
 	dirOpened, err := os.Open(dirPath)
@@ -300,7 +301,7 @@ func processJmodFile(jmodFileName string, jmodFullPath string) bool {
 func saveMapToGob() {
 
 	global := globals.GetGlobalRef()
-	gobFile := global.JacobinHome + string(os.PathSeparator) + global.JavaVersion + ".gob"
+	gobFile := util.GetPlatform().JoinPath(global.JacobinHome, global.JavaVersion+".gob")
 	// Open output gob file
 	_ = os.Remove(gobFile)
 	outFile, err := os.Create(gobFile)
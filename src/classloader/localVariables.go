@@ -0,0 +1,159 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package classloader
+
+import (
+	"fmt"
+	"jacobin/src/types"
+	"jacobin/src/util"
+	"strconv"
+)
+
+// LocalVariable is an entry in a method's LocalVariableTable, along with the generic
+// signature from the matching entry in its LocalVariableTypeTable, if there is one.
+// The tables are emitted by javac when a class is compiled with -g (or -g:vars).
+// See: https://docs.oracle.com/javase/specs/jvms/se17/html/jvms-4.html#jvms-4.7.13
+type LocalVariable struct {
+	StartPc   uint16 // the variable has a value from StartPc up to, but not including,
+	Length    uint16 // StartPc + Length
+	Name      string
+	Desc      string // the field descriptor of the variable's type, e.g., Ljava/lang/String;
+	Signature string // the generic signature of the type, e.g., Ljava/util/List<Ljava/lang/String;>;
+	Slot      uint16 // the index of the variable in the frame's local variables
+}
+
+// parses the LocalVariableTable and LocalVariableTypeTable sub-attributes of the Code
+// attribute. Both consist of a count followed by entries of this layout:
+//
+//	{   u2 start_pc;
+//	    u2 length;
+//	    u2 name_index;
+//	    u2 descriptor_index; (signature_index in the LocalVariableTypeTable)
+//	    u2 index;
+//	} local_variable_table[local_variable_table_length];
+//
+// Entries in the LocalVariableTypeTable add the signature to the matching entry in the
+// LocalVariableTable, so the latter must be parsed first, as javac emits them.
+func parseLocalVariableTable(codeAttr *codeAttrib, thisAttr *attr, isTypeTable bool,
+	methodName string, klass *ParsedClass) error {
+	tableName := "LocalVariableTable"
+	if isTypeTable {
+		tableName = "LocalVariableTypeTable"
+	}
+
+	entryCount, err := intFrom2Bytes(thisAttr.attrContent, 0)
+	if err != nil || len(thisAttr.attrContent) < 2+entryCount*10 {
+		return cfe("Invalid " + tableName + " in method " + methodName + "() of " + klass.className)
+	}
+
+	loc := 2 // we're two bytes into the attr.Content byte array
+	for i := 0; i < entryCount; i++ {
+		content := thisAttr.attrContent
+		startPc := uint16(content[loc])<<8 + uint16(content[loc+1])
+		length := uint16(content[loc+2])<<8 + uint16(content[loc+3])
+		nameIndex := int(content[loc+4])<<8 + int(content[loc+5])
+		descIndex := int(content[loc+6])<<8 + int(content[loc+7])
+		slot := uint16(content[loc+8])<<8 + uint16(content[loc+9])
+		loc += 10
+
+		name, err := FetchUTF8string(klass, nameIndex)
+		if err != nil {
+			return cfe("Invalid name of " + tableName + " entry #" + strconv.Itoa(i+1) +
+				" in method " + methodName + "() of " + klass.className)
+		}
+		desc, err := FetchUTF8string(klass, descIndex)
+		if err != nil {
+			return cfe("Invalid type of " + tableName + " entry #" + strconv.Itoa(i+1) +
+				" in method " + methodName + "() of " + klass.className)
+		}
+
+		if !isTypeTable {
+			codeAttr.localVarTable = append(codeAttr.localVarTable,
+				LocalVariable{StartPc: startPc, Length: length, Name: name, Desc: desc, Slot: slot})
+			continue
+		}
+
+		for j := range codeAttr.localVarTable {
+			lv := &codeAttr.localVarTable[j]
+			if lv.StartPc == startPc && lv.Length == length && lv.Slot == slot && lv.Name == name {
+				lv.Signature = desc
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// LocalVariableAt returns the entry in a method's LocalVariableTable for the local
+// variable in the given slot at the given PC. A slot can hold different variables at
+// different points in a method, so the PC is needed to identify the variable. Returns
+// false if the table is empty or the slot holds no named variable at that PC.
+func LocalVariableAt(table []LocalVariable, slot, pc int) (LocalVariable, bool) {
+	for _, lv := range table {
+		if int(lv.Slot) == slot && pc >= int(lv.StartPc) && pc < int(lv.StartPc)+int(lv.Length) {
+			return lv, true
+		}
+	}
+	return LocalVariable{}, false
+}
+
+// FetchLocalVariables returns the LocalVariableTable of the named method, or nil if
+// the class is not loaded, the method is not found, or it has no such table.
+func FetchLocalVariables(className, methName, methType string) []LocalVariable {
+	if MethArea == nil { // as when an exception is thrown before the method area is set up
+		return nil
+	}
+	k := MethAreaFetch(className)
+	if k == nil || k.Data == nil {
+		return nil
+	}
+	m, ok := k.Data.MethodTable[methName+methType]
+	if !ok {
+		return nil
+	}
+	return m.CodeAttr.LocalVariables
+}
+
+// MethodParameterNames returns the names of the parameters of the named method, for
+// use by reflection (Method.getParameters()) and in diagnostic messages. The names
+// come from the MethodParameters attribute if the class was compiled with -parameters,
+// or else from the LocalVariableTable if it was compiled with -g. If neither is present,
+// the names are synthesized as arg0, arg1, etc., as the JDK does.
+func MethodParameterNames(className, methName, methType string) []string {
+	paramTypes := util.ParseIncomingParamsFromMethTypeString(methType)
+	names := make([]string, len(paramTypes))
+
+	var meth *Method
+	if MethArea != nil { // if it's nil, no class is loaded, so the names are synthesized
+		if k := MethAreaFetch(className); k != nil && k.Data != nil {
+			meth = k.Data.MethodTable[methName+methType]
+		}
+	}
+
+	// the slot of the first parameter: 0 for static methods, else 1 (after this)
+	slot := 1
+	if meth != nil && meth.AccessFlags&AccStatic != 0 {
+		slot = 0
+	}
+
+	for i, paramType := range paramTypes {
+		names[i] = fmt.Sprintf("arg%d", i)
+		if meth != nil && len(meth.Parameters) == len(paramTypes) && meth.Parameters[i].Name != "" {
+			names[i] = meth.Parameters[i].Name
+		} else if meth != nil {
+			if lv, ok := LocalVariableAt(meth.CodeAttr.LocalVariables, slot, 0); ok {
+				names[i] = lv.Name
+			}
+		}
+		if paramType == types.Long || paramType == types.Double { // these take two slots
+			slot += 2
+		} else {
+			slot++
+		}
+	}
+	return names
+}
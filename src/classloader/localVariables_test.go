@@ -0,0 +1,139 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package classloader
+
+import (
+	"jacobin/src/globals"
+	"jacobin/src/trace"
+	"reflect"
+	"testing"
+)
+
+// a parsed class whose CP holds the names and types of the local variables in
+// static void m(int count, List<String> names) { String s = ...; }
+func localVarTestClass() *ParsedClass {
+	klass := ParsedClass{className: "pkg/Locals"}
+	klass.utf8Refs = []utf8Entry{{"count"}, {"I"}, {"names"}, {"Ljava/util/List;"},
+		{"Ljava/util/List<Ljava/lang/String;>;"}, {"s"}, {"Ljava/lang/String;"}}
+	klass.cpIndex = []cpEntry{{}}
+	for i := range klass.utf8Refs {
+		klass.cpIndex = append(klass.cpIndex, cpEntry{UTF8, i})
+	}
+	klass.cpCount = len(klass.cpIndex)
+	return &klass
+}
+
+func TestParseLocalVariableTables(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	klass := localVarTestClass()
+
+	lvt := attr{attrContent: []byte{
+		0x00, 0x03, // 3 entries
+		0x00, 0x00, 0x00, 0x0A, 0x00, 0x01, 0x00, 0x02, 0x00, 0x00, // count: I in slot 0
+		0x00, 0x00, 0x00, 0x0A, 0x00, 0x03, 0x00, 0x04, 0x00, 0x01, // names: List in slot 1
+		0x00, 0x04, 0x00, 0x06, 0x00, 0x06, 0x00, 0x07, 0x00, 0x02, // s: String in slot 2
+	}}
+	lvtt := attr{attrContent: []byte{
+		0x00, 0x01, // 1 entry
+		0x00, 0x00, 0x00, 0x0A, 0x00, 0x03, 0x00, 0x05, 0x00, 0x01, // names: List<String> in slot 1
+	}}
+
+	ca := codeAttrib{}
+	if err := parseLocalVariableTable(&ca, &lvt, false, "m", klass); err != nil {
+		t.Fatalf("Unexpected error parsing LocalVariableTable: %v", err)
+	}
+	if err := parseLocalVariableTable(&ca, &lvtt, true, "m", klass); err != nil {
+		t.Fatalf("Unexpected error parsing LocalVariableTypeTable: %v", err)
+	}
+
+	expected := []LocalVariable{
+		{StartPc: 0, Length: 10, Name: "count", Desc: "I", Slot: 0},
+		{StartPc: 0, Length: 10, Name: "names", Desc: "Ljava/util/List;",
+			Signature: "Ljava/util/List<Ljava/lang/String;>;", Slot: 1},
+		{StartPc: 4, Length: 6, Name: "s", Desc: "Ljava/lang/String;", Slot: 2},
+	}
+	if !reflect.DeepEqual(ca.localVarTable, expected) {
+		t.Errorf("Expected %v, got %v", expected, ca.localVarTable)
+	}
+
+	// a table that's shorter than its count says, and one with an invalid name index
+	short := attr{attrContent: []byte{0x00, 0x01, 0x00, 0x00}}
+	if parseLocalVariableTable(&codeAttrib{}, &short, false, "m", klass) == nil {
+		t.Error("Expected an error for a truncated LocalVariableTable")
+	}
+	badName := attr{attrContent: []byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x0A, 0x00, 0x20, 0x00, 0x02, 0x00, 0x00}}
+	if parseLocalVariableTable(&codeAttrib{}, &badName, false, "m", klass) == nil {
+		t.Error("Expected an error for an invalid name index")
+	}
+}
+
+func TestLocalVariableAt(t *testing.T) {
+	table := []LocalVariable{
+		{StartPc: 2, Length: 4, Name: "a", Slot: 1},
+		{StartPc: 8, Length: 4, Name: "b", Slot: 1}, // slot 1 is reused for another variable
+	}
+
+	tests := []struct {
+		slot, pc int
+		name     string
+		found    bool
+	}{
+		{1, 2, "a", true}, {1, 5, "a", true}, {1, 6, "", false},
+		{1, 8, "b", true}, {1, 11, "b", true}, {0, 2, "", false},
+	}
+	for _, test := range tests {
+		lv, ok := LocalVariableAt(table, test.slot, test.pc)
+		if ok != test.found || lv.Name != test.name {
+			t.Errorf("slot %d at PC %d: expected %q (%v), got %q (%v)",
+				test.slot, test.pc, test.name, test.found, lv.Name, ok)
+		}
+	}
+}
+
+func TestMethodParameterNames(t *testing.T) {
+	globals.InitGlobals("test")
+	InitMethodArea()
+
+	MethAreaInsert("pkg/Params", &Klass{Status: 'F', Loader: "testloader", Data: &ClData{
+		Name: "pkg/Params",
+		MethodTable: map[string]*Method{
+			// compiled with -parameters
+			"a(ILjava/lang/String;)V": {AccessFlags: AccStatic,
+				Parameters: []ParamAttrib{{Name: "count"}, {Name: "label"}}},
+			// compiled with -g: an instance method, so this is in slot 0 and the long takes two slots
+			"b(JI)V": {CodeAttr: CodeAttrib{LocalVariables: []LocalVariable{
+				{Length: 5, Name: "this", Slot: 0}, {Length: 5, Name: "when", Slot: 1},
+				{Length: 5, Name: "times", Slot: 3}}}},
+			// compiled with neither
+			"c(IZ)V": {AccessFlags: AccStatic},
+		},
+	}})
+
+	tests := []struct {
+		methName, methType string
+		expected           []string
+	}{
+		{"a", "(ILjava/lang/String;)V", []string{"count", "label"}},
+		{"b", "(JI)V", []string{"when", "times"}},
+		{"c", "(IZ)V", []string{"arg0", "arg1"}},
+		{"d", "(D)V", []string{"arg0"}}, // not in the class
+	}
+	for _, test := range tests {
+		names := MethodParameterNames("pkg/Params", test.methName, test.methType)
+		if !reflect.DeepEqual(names, test.expected) {
+			t.Errorf("%s%s: expected %v, got %v", test.methName, test.methType, test.expected, names)
+		}
+	}
+
+	if names := FetchLocalVariables("pkg/Params", "b", "(JI)V"); len(names) != 3 {
+		t.Errorf("Expected 3 local variables, got %v", names)
+	}
+	if names := FetchLocalVariables("pkg/Missing", "b", "(JI)V"); names != nil {
+		t.Errorf("Expected no local variables for a missing class, got %v", names)
+	}
+}
@@ -8,8 +8,13 @@ package classloader
 
 import (
 	"fmt"
+	"io"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"jacobin/src/util"
 	"os"
 	"sort"
+	"strings"
 	"sync"
 )
 
@@ -55,6 +60,7 @@ type JmEntry struct {
 	CodeAttr   CodeAttrib
 	deprecated bool
 	Cp         *CPool
+	Class      string // the class that defines the method, which an inherited entry's key doesn't name
 }
 
 // Function is the generic-style function used for Go entries: a function that accepts a
@@ -95,3 +101,130 @@ func DumpMTable() {
 	}
 	_, _ = fmt.Fprintln(os.Stderr, "===== DumpMTable END")
 }
+
+// MTableEntry describes an entry in the MTable, as reported by MTableDump()
+type MTableEntry struct {
+	Signature  string // the fully qualified name of the method, which is the key of the entry
+	MType      byte   // G = Go method, J = Java method
+	Class      string // the class that defines the method: a superclass, if the method is inherited
+	ParamSlots int    // the number of parameters the method takes, not counting this
+	Shadows    bool   // a gfunction that's run in place of the bytecode of a loaded class
+}
+
+// the gfunctions' entries report the parameter slots they declare
+type paramSlotter interface {
+	NumParamSlots() int
+}
+
+// MTableDump returns the entries of the MTable whose signatures begin with the filter,
+// sorted by signature. An empty filter returns all the entries. The filter can be in
+// java/lang/Object or java.lang.Object format.
+//
+// Since JACOBIN-575, the MTable is where every method invocation is resolved, so this
+// is the record of which implementation of each method the program actually runs. A
+// gfunction takes precedence over any bytecode for the method (unless -XX:PreferBytecode
+// says otherwise), so the entries whose gfunction shadows bytecode in a class that's
+// been loaded are marked, as they are where a discrepancy with the JDK can arise.
+func MTableDump(filter string) []MTableEntry {
+	filter = strings.ReplaceAll(filter, ".", "/")
+	snapshot := make(map[string]MTentry)
+	MTmutex.Lock()
+	for key, entry := range MTable {
+		if strings.HasPrefix(key, filter) {
+			snapshot[key] = entry
+		}
+	}
+	MTmutex.Unlock()
+
+	entries := make([]MTableEntry, 0, len(snapshot))
+	for key, entry := range snapshot {
+		className, methSig := splitMethodFQN(key)
+		info := MTableEntry{Signature: key, MType: entry.MType, Class: className}
+		if paren := strings.Index(methSig, "("); paren >= 0 {
+			info.ParamSlots = len(util.ParseIncomingParamsFromMethTypeString(methSig[paren:]))
+		}
+		switch meth := entry.Meth.(type) {
+		case JmEntry:
+			if meth.Class != "" {
+				info.Class = meth.Class
+			}
+		case paramSlotter:
+			info.ParamSlots = meth.NumParamSlots()
+		}
+		if entry.MType == 'G' {
+			info.Class = gfunctionClass(className, methSig, snapshot)
+			if k := MethAreaFetch(info.Class); k != nil && k.Data != nil {
+				if m, ok := k.Data.MethodTable[methSig]; ok && hasBytecode(m) {
+					info.Shadows = true
+				}
+			}
+		}
+		entries = append(entries, info)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Signature < entries[j].Signature })
+	return entries
+}
+
+// splits a method FQN, such as java/lang/Math.abs(I)I, into the class name and the
+// method's name and type. The keys of the MTable always contain a . and a (, as
+// loadlib() checks when it loads the gfunctions.
+func splitMethodFQN(methFQN string) (string, string) {
+	paren := strings.Index(methFQN, "(")
+	if paren < 0 {
+		paren = len(methFQN)
+	}
+	dot := strings.LastIndex(methFQN[:paren], ".")
+	if dot < 0 {
+		return "", methFQN
+	}
+	return methFQN[:dot], methFQN[dot+1:]
+}
+
+// finds the class that a gfunction entry for the class comes from. FetchMethodAndCP()
+// copies the gfunction of a superclass, such as Object.clone(), to the MTable entry of
+// the subclass that inherits it, so the superclasses are ascended for as long as they
+// have the same gfunction and the class doesn't define the method itself.
+func gfunctionClass(className, methSig string, entries map[string]MTentry) string {
+	for className != types.ObjectClassName {
+		k := MethAreaFetch(className)
+		if k == nil || k.Data == nil {
+			break
+		}
+		if _, ok := k.Data.MethodTable[methSig]; ok {
+			break
+		}
+		super := *stringPool.GetStringPointer(k.Data.SuperclassIndex)
+		if entry, ok := entries[super+"."+methSig]; !ok || entry.MType != 'G' {
+			if entry = MTable[super+"."+methSig]; entry.MType != 'G' {
+				break
+			}
+		}
+		className = super
+	}
+	return className
+}
+
+// PrintMTable writes the entries of the MTable whose signatures begin with the filter
+// to out, one per line, followed by the gfunctions among them that shadow the bytecode
+// of a loaded class. It's used by -XX:+PrintMTableAtExit and jj._dumpMTable().
+func PrintMTable(out io.Writer, filter string) {
+	entries := MTableDump(filter)
+	var shadows []MTableEntry
+	_, _ = fmt.Fprintln(out, "---- start of MTable dump ----")
+	_, _ = fmt.Fprintf(out, "%-4s %6s  %-40s %s\n", "type", "params", "defined in", "signature")
+	for _, e := range entries {
+		_, _ = fmt.Fprintf(out, "%-4c %6d  %-40s %s\n", e.MType, e.ParamSlots, e.Class, e.Signature)
+		if e.Shadows {
+			shadows = append(shadows, e)
+		}
+	}
+	_, _ = fmt.Fprintf(out, "---- end of MTable dump: %d methods ----\n", len(entries))
+
+	if len(shadows) > 0 {
+		_, _ = fmt.Fprintf(out, "gfunctions that shadow loaded bytecode (%d):\n", len(shadows))
+		for _, e := range shadows {
+			_, _ = fmt.Fprintf(out, "    %s\n", e.Signature)
+		}
+	}
+}
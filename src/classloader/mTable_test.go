@@ -7,8 +7,10 @@
 package classloader
 
 import (
+	"bytes"
 	"io"
 	"jacobin/src/globals"
+	"jacobin/src/stringPool"
 	"os"
 	"strings"
 	"testing"
@@ -63,3 +65,55 @@ func TestMtableDump(t *testing.T) {
 		t.Errorf("Expecting different content in dump of Mtable, got: %s", msg)
 	}
 }
+
+// a stand-in for a gfunction's GMeth
+type testGmeth struct{ slots int }
+
+func (gm testGmeth) NumParamSlots() int { return gm.slots }
+
+func TestMTableDump_ClassesAndShadows(t *testing.T) {
+	globals.InitGlobals("test")
+	resetState()
+
+	// com/example/Base has bytecode for run() and poll(), but a gfunction replaces run(),
+	// which com/example/Derived inherits
+	baseName := "com/example/Base"
+	baseData := &ClData{Name: baseName, MethodTable: make(map[string]*Method)}
+	baseData.MethodTable["run(IJ)V"] = &Method{CodeAttr: CodeAttrib{MaxStack: 1, MaxLocals: 4, Code: []byte{0xb1}}}
+	baseData.MethodTable["poll()I"] = &Method{CodeAttr: CodeAttrib{MaxStack: 1, MaxLocals: 1, Code: []byte{0x03, 0xac}}}
+	MethAreaInsert(baseName, &Klass{Status: 'F', Data: baseData})
+	derivedName := "com/example/Derived"
+	MethAreaInsert(derivedName, &Klass{Status: 'F', Data: &ClData{Name: derivedName,
+		SuperclassIndex: stringPool.GetStringIndex(&baseName), MethodTable: make(map[string]*Method)}})
+
+	AddEntry(&MTable, baseName+".run(IJ)V", MTentry{Meth: testGmeth{slots: 2}, MType: 'G'})
+	AddEntry(&MTable, derivedName+".run(IJ)V", MTentry{Meth: testGmeth{slots: 2}, MType: 'G'})
+	AddEntry(&MTable, derivedName+".poll()I", MTentry{Meth: JmEntry{Class: baseName}, MType: 'J'})
+	AddEntry(&MTable, "com/other/Native.now()J", MTentry{Meth: testGmeth{slots: 0}, MType: 'G'})
+
+	entries := MTableDump("com.example")
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries for com.example, got %d: %v", len(entries), entries)
+	}
+	want := []MTableEntry{
+		{Signature: baseName + ".run(IJ)V", MType: 'G', Class: baseName, ParamSlots: 2, Shadows: true},
+		{Signature: derivedName + ".poll()I", MType: 'J', Class: baseName, ParamSlots: 0},
+		{Signature: derivedName + ".run(IJ)V", MType: 'G', Class: baseName, ParamSlots: 2, Shadows: true},
+	}
+	for i, entry := range entries {
+		if entry != want[i] {
+			t.Errorf("Expected entry %d to be %+v, got %+v", i, want[i], entry)
+		}
+	}
+
+	var out bytes.Buffer
+	PrintMTable(&out, "")
+	msg := out.String()
+	if !strings.Contains(msg, "end of MTable dump: 4 methods") {
+		t.Errorf("Expected a count of 4 methods in the dump of the MTable, got: %s", msg)
+	}
+	_, shadows, _ := strings.Cut(msg, "gfunctions that shadow loaded bytecode (2):")
+	if !strings.Contains(shadows, baseName+".run(IJ)V") || strings.Contains(shadows, "com/other/Native") {
+		t.Errorf("Expected the two run() gfunctions to be reported as shadowing bytecode, got: %s", msg)
+	}
+}
@@ -9,12 +9,13 @@ package classloader
 import (
 	"errors"
 	"fmt"
+	"io"
 	"jacobin/src/globals"
 	"jacobin/src/stringPool"
 	"jacobin/src/trace"
 	"jacobin/src/types"
-	"os"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -24,8 +25,15 @@ var MethArea *sync.Map
 var methAreaSize = 0
 var MethAreaMutex sync.RWMutex // All additions or updates to MethArea map come through this mutex
 
+// classes being loaded have the status 'I' in the method area. Threads that need such a
+// class wait on a channel in classLoadSignals, which is closed when the load finishes or
+// fails. They wait no longer than ClassLoadTimeout.
+var classLoadSignals sync.Map // class name -> chan struct{}
+var ClassLoadTimeout = 2 * time.Second
+
 // InitMethodArea initializes MethArea (the method area table of loaded classes),
-// initializes the counter of classes, and preloads the synthetic array classes.
+// initializes the counter of classes, clears the cache of java/lang/Class objects,
+// and preloads the synthetic array classes.
 func InitMethodArea() {
 	MethAreaMutex.Lock()
 	ma := sync.Map{}
@@ -33,6 +41,8 @@ func InitMethodArea() {
 	methAreaSize = 0
 	MethAreaMutex.Unlock()
 
+	resetClassObjects()
+
 	// preload the synthetic classes for arrays
 	MethAreaPreload()
 }
@@ -86,6 +96,10 @@ func MethAreaInsert(name string, klass *Klass) {
 	methAreaSize++
 	MethAreaMutex.Unlock()
 
+	if klass.Status != 'I' {
+		signalClassLoadDone(name)
+	}
+
 	if globals.TraceClass {
 		if klass.Status == 'F' || klass.Status == 'V' || klass.Status == 'L' {
 			trace.Trace("Method area insert: " + klass.Data.Name + ", loader: " + klass.Loader)
@@ -100,6 +114,10 @@ func MethAreaUpdate(name string, klass *Klass) {
 	MethArea.Store(name, klass)
 	MethAreaMutex.Unlock()
 
+	if klass.Status != 'I' {
+		signalClassLoadDone(name)
+	}
+
 	if globals.TraceClass {
 		trace.Trace("Method area update: " + klass.Data.Name + ", loader: " + klass.Loader)
 	}
@@ -115,8 +133,8 @@ func MethAreaSize() int {
 	return size
 }
 
-// MethAreaDelete deletes an entry in the method area
-// **at present, it is used only in testing **
+// MethAreaDelete deletes an entry in the method area. It is used in testing
+// and to remove the placeholder entry of a class whose load failed.
 func MethAreaDelete(key string) {
 	if MethAreaFetch(key) != nil {
 		MethAreaMutex.Lock()
@@ -124,44 +142,115 @@ func MethAreaDelete(key string) {
 		methAreaSize--
 		MethAreaMutex.Unlock()
 	}
+	signalClassLoadDone(key)
+}
+
+// wakes up any threads waiting for the named class to finish loading
+func signalClassLoadDone(className string) {
+	if ch, ok := classLoadSignals.LoadAndDelete(className); ok {
+		close(ch.(chan struct{}))
+	}
 }
 
-// Wait for klass.Status to no longer be 'I' (I = initializing)
-// TODO: must be a better way to do this!
+// WaitForClassStatus waits until the named class is in the method area and its
+// status is no longer 'I' (I = initializing the load). An error is returned if
+// the load fails or does not complete within ClassLoadTimeout.
 func WaitForClassStatus(className string) error {
 	klass := MethAreaFetch(className)
-	if klass == nil { // class not there yet
-		time.Sleep(globals.SleepMsecs * time.Millisecond) // sleep awhile
-		klass = MethAreaFetch(className)
-		if klass == nil {
-			errMsg := fmt.Sprintf("WaitClassStatus: Timeout waiting for class %s to load", className)
-			return errors.New(errMsg)
-		}
+	if klass != nil && klass.Status != 'I' {
+		return nil
 	}
-	if klass.Status == 'I' { // class is being initialized by a loader, so wait
-		time.Sleep(globals.SleepMsecs * time.Millisecond) // sleep awhile
-		klass = MethAreaFetch(className)
-		if klass.Status == 'I' {
+
+	ch, _ := classLoadSignals.LoadOrStore(className, make(chan struct{}))
+
+	// check again, in case the load finished before the channel was in place
+	klass = MethAreaFetch(className)
+	if klass == nil || klass.Status == 'I' {
+		select {
+		case <-ch.(chan struct{}):
+			klass = MethAreaFetch(className)
+		case <-time.After(ClassLoadTimeout):
+			if klass == nil {
+				errMsg := fmt.Sprintf("WaitClassStatus: Timeout waiting for class %s to load", className)
+				return errors.New(errMsg)
+			}
 			errMsg := fmt.Sprintf("WaitClassStatus: Timeout waiting for class %s to be initialized", className)
 			return errors.New(errMsg)
 		}
 	}
+
+	if klass == nil || klass.Status == 'I' {
+		errMsg := fmt.Sprintf("WaitClassStatus: Load of class %s failed", className)
+		return errors.New(errMsg)
+	}
 	return nil
 }
 
-// MethAreaDump dumps the contents of the method area in a sorted list to stderr
-// used only for testing/debugging
-func MethAreaDump() {
-	var entries []string
+// MethAreaEntry describes a class in the method area, as reported by MethAreaDump()
+type MethAreaEntry struct {
+	Name    string
+	Loader  string
+	Status  byte // I=Initializing,F=formatChecked,V=verified,L=linked,N=instantiated
+	Methods int  // the number of methods the class defines
+	ClInit  byte // the state of the class's initializer: types.NoClInit, types.ClInitRun, etc.
+}
+
+// MethAreaDump returns the entries of the method area whose class names begin with
+// the filter, sorted by class name. An empty filter returns all the entries. The filter
+// can be in java/lang/Object or java.lang.Object format.
+func MethAreaDump(filter string) []MethAreaEntry {
+	filter = strings.ReplaceAll(filter, ".", "/")
+	var entries []MethAreaEntry
 
+	MethAreaMutex.RLock()
 	MethArea.Range(func(key, value interface{}) bool {
-		entries = append(entries, key.(string))
+		name := key.(string)
+		if !strings.HasPrefix(name, filter) {
+			return true
+		}
+		k := value.(*Klass)
+		entry := MethAreaEntry{Name: name, Loader: k.Loader, Status: k.Status}
+		if k.Data != nil {
+			entry.Methods = len(k.Data.MethodTable)
+			entry.ClInit = k.Data.ClInit
+		}
+		entries = append(entries, entry)
 		return true
 	})
-	sort.Strings(entries)
-	fmt.Fprintln(os.Stderr, "---- start of method area dump ----")
-	for _, str := range entries {
-		fmt.Fprintln(os.Stderr, str)
+	MethAreaMutex.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
+// PrintMethArea writes the entries of the method area whose class names begin with
+// the filter to out, one per line, followed by the number of classes shown.
+// It's used by -XX:+PrintMethodAreaAtExit and jj._dumpMethodArea().
+func PrintMethArea(out io.Writer, filter string) {
+	entries := MethAreaDump(filter)
+	_, _ = fmt.Fprintln(out, "---- start of method area dump ----")
+	_, _ = fmt.Fprintf(out, "%-50s %-10s %-6s %7s  %s\n", "class", "loader", "status", "methods", "initializer")
+	for _, e := range entries {
+		_, _ = fmt.Fprintf(out, "%-50s %-10s %-6c %7d  %s\n",
+			e.Name, e.Loader, e.Status, e.Methods, clInitDescription(e.ClInit))
+	}
+	_, _ = fmt.Fprintf(out, "---- end of method area dump: %d classes ----\n", len(entries))
+}
+
+// describes the state of a class's initializer (<clinit>)
+func clInitDescription(clInit byte) string {
+	switch clInit {
+	case types.NoClInit:
+		return "none"
+	case types.ClInitNotRun:
+		return "not run"
+	case types.ClInitInProgress:
+		return "running"
+	case types.ClInitRun:
+		return "run"
+	case types.ClInitError:
+		return "failed"
+	default:
+		return fmt.Sprintf("unknown (%d)", clInit)
 	}
-	fmt.Fprintln(os.Stderr, "---- end of method area dump ----")
 }
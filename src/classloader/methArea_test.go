@@ -15,6 +15,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 // Note: many MethArea functions are tested in classes_test,go
@@ -129,14 +130,89 @@ func TestMethAreadDump(t *testing.T) {
 
 	InitMethodArea()
 	MethAreaPreload()
-	MethAreaDump()
+	PrintMethArea(os.Stderr, "")
 
 	_ = w.Close()
 	out, _ := io.ReadAll(r)
 	os.Stderr = normalStderr
 
 	msg := string(out[:])
-	if !strings.Contains(msg, "[B\n[D") {
+	if !strings.Contains(msg, "[B ") || strings.Index(msg, "[B ") > strings.Index(msg, "[D ") {
 		t.Errorf("Expecting different content in dump of MethArea, got: %s", msg)
 	}
+	if !strings.Contains(msg, "end of method area dump: 8 classes") {
+		t.Errorf("Expecting a count of 8 classes in dump of MethArea, got: %s", msg)
+	}
+}
+
+func TestMethAreaDumpFilter(t *testing.T) {
+	globals.InitGlobals("test")
+	InitMethodArea()
+
+	klass := Klass{Status: 'N', Loader: "app", Data: &ClData{
+		Name:        "pkg/Dumped",
+		MethodTable: map[string]*Method{"<clinit>()V": {}, "run()V": {}},
+		ClInit:      types.ClInitRun,
+	}}
+	MethAreaInsert("pkg/Dumped", &klass)
+	MethAreaInsert("pkg/Loading", &Klass{Status: 'I'})
+
+	entries := MethAreaDump("pkg.")
+	if len(entries) != 2 || entries[0].Name != "pkg/Dumped" || entries[1].Name != "pkg/Loading" {
+		t.Fatalf("MethAreaDump(\"pkg.\") = %v", entries)
+	}
+	want := MethAreaEntry{Name: "pkg/Dumped", Loader: "app", Status: 'N', Methods: 2, ClInit: types.ClInitRun}
+	if entries[0] != want {
+		t.Errorf("got %+v, want %+v", entries[0], want)
+	}
+	if entries[1].Methods != 0 || entries[1].Status != 'I' {
+		t.Errorf("unexpected entry for a class being loaded: %+v", entries[1])
+	}
+	if len(MethAreaDump("")) != MethAreaSize() {
+		t.Errorf("MethAreaDump(\"\") returned %d entries, the method area has %d", len(MethAreaDump("")), MethAreaSize())
+	}
+}
+
+func TestWaitForClassStatusWakesWhenLoadFinishes(t *testing.T) {
+	MethArea = &sync.Map{}
+	methAreaSize = 0
+
+	MethAreaInsert("pkg/Loading", &Klass{Status: 'I', Data: &ClData{}})
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		MethAreaUpdate("pkg/Loading", &Klass{Status: 'F', Loader: "testloader", Data: &ClData{Name: "pkg/Loading"}})
+	}()
+
+	if err := WaitForClassStatus("pkg/Loading"); err != nil {
+		t.Errorf("Unexpected error waiting for class to load: %v", err)
+	}
+}
+
+func TestWaitForClassStatusLoadFails(t *testing.T) {
+	MethArea = &sync.Map{}
+	methAreaSize = 0
+
+	MethAreaInsert("pkg/Failing", &Klass{Status: 'I', Data: &ClData{}})
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		MethAreaDelete("pkg/Failing")
+	}()
+
+	err := WaitForClassStatus("pkg/Failing")
+	if err == nil || !strings.Contains(err.Error(), "Load of class pkg/Failing failed") {
+		t.Errorf("Expected a load failure error, got: %v", err)
+	}
+}
+
+func TestWaitForClassStatusTimeout(t *testing.T) {
+	MethArea = &sync.Map{}
+	methAreaSize = 0
+	savedTimeout := ClassLoadTimeout
+	ClassLoadTimeout = 10 * time.Millisecond
+	defer func() { ClassLoadTimeout = savedTimeout }()
+
+	err := WaitForClassStatus("pkg/NeverLoaded")
+	if err == nil || !strings.Contains(err.Error(), "Timeout waiting for class pkg/NeverLoaded to load") {
+		t.Errorf("Expected a timeout error, got: %v", err)
+	}
 }
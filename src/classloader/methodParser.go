@@ -100,15 +100,13 @@ func parseMethods(bytes []byte, loc int, klass *ParsedClass) (int, error) {
 						return pos, cfe("") // error msg will already have been shown to user
 					}
 				case "MethodParameters":
-					// JACOBIN-577: Removed because JDK 21 causes something in the parsing
-					// of this attribute to become unhinged. As this attribute is not needed in
-					// the execution of a class, we've temporarily chosen to block this call.
-					// When time permits or if the MethodParameters attribute assumes a new importance,
-					// we'll return here and figure out what needs to be done.
-					//
-					// if parseMethodParametersAttribute(attrib, &meth, klass) != nil {
-					// 	return pos, cfe("") // error msg will already have been shown to user
-					// }
+					// JACOBIN-577: this was blocked because parsing became unhinged with JDK 21,
+					// which emits this attribute for many methods. The parser failed to step past
+					// the access flags of each parameter, so all parameters after the first were
+					// misread. The parameter names are now used by reflection and diagnostics.
+					if parseMethodParametersAttribute(attrib, &meth, klass) != nil {
+						return pos, cfe("") // error msg will already have been shown to user
+					}
 				}
 
 			} else {
@@ -206,6 +204,16 @@ func parseCodeAttribute(att attr, meth *method, klass *ParsedClass) error {
 				!util.IsFilePartOfJDK(&klass.className) {
 				buildLineNumberTable(&ca, &subAttr, methodName)
 			}
+			switch klass.utf8Refs[subAttr.attrName].content {
+			case "LocalVariableTable":
+				if parseLocalVariableTable(&ca, &subAttr, false, methodName, klass) != nil {
+					return cfe("") // error msg will already have been shown to user
+				}
+			case "LocalVariableTypeTable":
+				if parseLocalVariableTable(&ca, &subAttr, true, methodName, klass) != nil {
+					return cfe("") // error msg will already have been shown to user
+				}
+			}
 			ca.attributes = append(ca.attributes, subAttr)
 		}
 	}
@@ -363,15 +371,14 @@ func parseMethodParametersAttribute(att attr, meth *method, klass *ParsedClass)
 		}
 
 		accessFlags, err := intFrom2Bytes(att.attrContent, pos)
+		pos += 2
 		if err != nil {
 			return cfe("Error getting access flags of MethodParameters attribute #" +
 				strconv.Itoa(k+1) + " in " + klass.utf8Refs[meth.name].content)
 		}
-		// do format check on the access flags here
-		switch accessFlags {
-		case 0x00, 0x10, 0x1000, 0x1010, 0x8000, 0x8010:
-			break
-		default:
+		// do format check on the access flags here: only ACC_FINAL (0x0010),
+		// ACC_SYNTHETIC (0x1000), and ACC_MANDATED (0x8000) are valid, in any combination
+		if accessFlags&^(0x0010|0x1000|0x8000) != 0 {
 			errMsg := fmt.Sprintf(
 				"Invalid access flags of MethodParameters attribute #%s in method %s: %X",
 				strconv.Itoa(k+1), klass.utf8Refs[meth.name].content, accessFlags)
@@ -914,3 +914,35 @@ func TestParseMethodParametersAttribute_MultipleParameters(t *testing.T) {
 }
 */
 // === end of tests generated by JetBrains Junie ===
+
+// JACOBIN-577: every parameter after the first was misread, because the parser
+// did not step past the access flags of each parameter
+func TestParseMethodParametersAttribute_TwoParameters(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+
+	klass := ParsedClass{}
+	klass.utf8Refs = []utf8Entry{{"testMethod"}, {"count"}, {"name"}}
+	klass.cpIndex = []cpEntry{{}, {UTF8, 1}, {UTF8, 2}}
+	klass.cpCount = 3
+
+	meth := method{name: 0}
+	attrib := attr{
+		attrContent: []byte{
+			0x02,       // parameters count = 2
+			0x00, 0x01, // name index = 1 (count)
+			0x00, 0x10, // access flags = final
+			0x00, 0x02, // name index = 2 (name)
+			0x90, 0x00, // access flags = mandated and synthetic
+		},
+	}
+
+	if err := parseMethodParametersAttribute(attrib, &meth, &klass); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(meth.parameters) != 2 ||
+		meth.parameters[0] != (paramAttrib{name: "count", accessFlags: 0x10}) ||
+		meth.parameters[1] != (paramAttrib{name: "name", accessFlags: 0x9000}) {
+		t.Errorf("Unexpected parameters: %v", meth.parameters)
+	}
+}
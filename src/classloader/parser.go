@@ -8,6 +8,7 @@ package classloader
 
 import (
 	"errors"
+	"fmt"
 	"jacobin/src/globals"
 	"jacobin/src/object"
 	"jacobin/src/stringPool"
@@ -126,22 +127,76 @@ func parseMagicNumber(bytes []byte) error {
 	}
 }
 
-// get the Java version number used in creating this class file. If it's higher than the
-// version Jacobin presently supports, report an error.
+// get the Java version number used in creating this class file and check that Jacobin
+// supports it (JVMS 4.1): the major version must be from 45 through the latest version
+// Jacobin supports, and from version 56 on, the minor version must be 0 or 65535. The
+// latter marks a class that uses preview features, which can be run only with
+// --enable-preview and only if the major version is the latest supported version.
+// The errors are reported as UnsupportedClassVersionError (see ParseAndPostClass()).
 func parseJavaVersionNumber(bytes []byte, klass *ParsedClass) error {
+	minor, err := intFrom2Bytes(bytes, 4)
+	if err != nil {
+		return err
+	}
 	version, err := intFrom2Bytes(bytes, 6)
 	if err != nil {
 		return err
 	}
+	klass.javaVersion = version
+	klass.minorVersion = minor
+
+	global := globals.GetGlobalRef()
+	switch {
+	case version > global.MaxJavaVersionRaw:
+		return cfeCode(CfeVersionTooNew, version, minor, global.MaxJavaVersionRaw, global.MaxJavaVersion)
+	case version < 45:
+		return cfeCode(CfeVersionTooOld, version, minor)
+	case version >= 56 && minor == previewMinorVersion:
+		if version != global.MaxJavaVersionRaw {
+			return cfeCode(CfePreviewVersion, version, global.MaxJavaVersionRaw)
+		}
+		if !global.EnablePreview {
+			return cfeCode(CfePreviewNotEnabled, version)
+		}
+	case version >= 56 && minor != 0:
+		return cfeCode(CfeVersionMinor, version, minor)
+	}
+	return nil
+}
 
-	if version > globals.GetGlobalRef().MaxJavaVersionRaw {
-		errMsg := "Jacobin supports only Java versions through Java " +
-			strconv.Itoa(globals.GetGlobalRef().MaxJavaVersion)
-		return cfe(errMsg)
+// the minor version of classes that use preview features (JVMS 4.1)
+const previewMinorVersion = 0xFFFF
+
+// IsClassVersionError reports whether err is (or wraps) an error in the version of a
+// class file, which is thrown as an UnsupportedClassVersionError
+func IsClassVersionError(err error) bool {
+	switch ErrorCodeOf(err) {
+	case CfeVersionTooNew, CfeVersionTooOld, CfeVersionMinor, CfePreviewNotEnabled, CfePreviewVersion:
+		return true
 	}
+	return false
+}
 
-	klass.javaVersion = version
-	return nil
+// returns the message of the UnsupportedClassVersionError for the class, in the words
+// of the JDK, given the error in its version reported by parseJavaVersionNumber()
+func classVersionMessage(className string, klass *ParsedClass, err error) string {
+	global := globals.GetGlobalRef()
+	major, minor := klass.javaVersion, klass.minorVersion
+	switch ErrorCodeOf(err) {
+	case CfeVersionTooNew:
+		return fmt.Sprintf("%s has been compiled by a more recent version of the Java Runtime "+
+			"(class file version %d.%d), this version of the Java Runtime only recognizes "+
+			"class file versions up to %d.0", className, major, minor, global.MaxJavaVersionRaw)
+	case CfePreviewNotEnabled:
+		return fmt.Sprintf("Preview features are not enabled for %s (class file version %d.%d). "+
+			"Try running with '--enable-preview'", className, major, minor)
+	case CfePreviewVersion:
+		return fmt.Sprintf("%s (class file version %d.%d) was compiled with preview features that are "+
+			"unsupported. This version of the Java Runtime only recognizes preview features for "+
+			"class file version %d.%d", className, major, minor, global.MaxJavaVersionRaw, previewMinorVersion)
+	default:
+		return fmt.Sprintf("%s has an unsupported class file version %d.%d", className, major, minor)
+	}
 }
 
 // get the number of entries in the constant pool. This number will
@@ -656,6 +711,33 @@ func parseClassAttributes(bytes []byte, loc int, klass *ParsedClass) (int, error
 		case "Deprecated":
 			klass.deprecated = true
 
+		case "NestHost":
+			// see: https://docs.oracle.com/javase/specs/jvms/se17/html/jvms-4.html#jvms-4.7.28
+			hostIndex, err1 := intFrom2Bytes(attrib.attrContent, 0)
+			if err1 != nil || hostIndex < 1 || hostIndex >= len(klass.cpIndex) ||
+				klass.cpIndex[hostIndex].entryType != ClassRef {
+				return pos, cfe("Invalid NestHost attribute in class: " + klass.className)
+			}
+			klass.nestHost = klass.classRefs[klass.cpIndex[hostIndex].slot]
+
+		case "NestMembers":
+			// see: https://docs.oracle.com/javase/specs/jvms/se17/html/jvms-4.html#jvms-4.7.29
+			memberCount, err1 := intFrom2Bytes(attrib.attrContent, 0)
+			if err1 != nil {
+				return pos, cfe("Invalid NestMembers attribute in class: " + klass.className)
+			}
+			loc = 2
+			for m := 0; m < memberCount; m++ {
+				memberIndex, err2 := intFrom2Bytes(attrib.attrContent, loc)
+				loc += 2
+				if err2 != nil || memberIndex < 1 || memberIndex >= len(klass.cpIndex) ||
+					klass.cpIndex[memberIndex].entryType != ClassRef {
+					return pos, cfe("Invalid NestMembers entry #" + strconv.Itoa(m) +
+						" in class: " + klass.className)
+				}
+				klass.nestMembers = append(klass.nestMembers, klass.classRefs[klass.cpIndex[memberIndex].slot])
+			}
+
 		case "SourceFile":
 			sourceNameIndex, _ := intFrom2Bytes(attrib.attrContent, 0)
 			utf8slot := klass.cpIndex[sourceNameIndex].slot
@@ -7,6 +7,7 @@
 package classloader
 
 import (
+	"fmt"
 	"io"
 	"jacobin/src/globals"
 	"jacobin/src/stringPool"
@@ -1289,3 +1290,144 @@ func TestDeprecatedClassAttribute(t *testing.T) {
 	_ = wout.Close()
 	os.Stdout = normalStdout
 }
+
+func TestNestHostAndNestMembersClassAttributes(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+
+	outerName := "pkg/Outer"
+	innerName := "pkg/Outer$Inner"
+	outerIndex := stringPool.GetStringIndex(&outerName)
+	innerIndex := stringPool.GetStringIndex(&innerName)
+
+	klass := ParsedClass{}
+	klass.cpIndex = append(klass.cpIndex, cpEntry{})
+	klass.cpIndex = append(klass.cpIndex, cpEntry{UTF8, 0})     // "NestHost"
+	klass.cpIndex = append(klass.cpIndex, cpEntry{UTF8, 1})     // "NestMembers"
+	klass.cpIndex = append(klass.cpIndex, cpEntry{ClassRef, 0}) // -> pkg/Outer
+	klass.cpIndex = append(klass.cpIndex, cpEntry{ClassRef, 1}) // -> pkg/Outer$Inner
+	klass.utf8Refs = append(klass.utf8Refs, utf8Entry{"NestHost"})
+	klass.utf8Refs = append(klass.utf8Refs, utf8Entry{"NestMembers"})
+	klass.classRefs = append(klass.classRefs, outerIndex, innerIndex)
+	klass.cpCount = 5
+	klass.attribCount = 2
+
+	// the attribute bytes. There's a leading dummy byte b/c the fetch routine starts
+	// at 1 byte after the passed-in position.
+	bytes := []byte{00, // dummy byte
+		00, 01, // CP[1] -> UTF8[0] -> "NestHost"
+		00, 00, 00, 02, // length of attribute
+		00, 03, // CP[3] -> ClassRef -> pkg/Outer
+		00, 02, // CP[2] -> UTF8[1] -> "NestMembers"
+		00, 00, 00, 04, // length of attribute
+		00, 01, // number of classes
+		00, 04, // CP[4] -> ClassRef -> pkg/Outer$Inner
+	}
+
+	_, err := parseClassAttributes(bytes, 0, &klass)
+	if err != nil {
+		t.Errorf("Unexpected error in test of parseClassAttributes(): %v", err)
+	}
+
+	if klass.nestHost != outerIndex {
+		t.Errorf("Expected nest host to be %s, got: %s", outerName, *stringPool.GetStringPointer(klass.nestHost))
+	}
+
+	if len(klass.nestMembers) != 1 || klass.nestMembers[0] != innerIndex {
+		t.Errorf("Expected nest members to contain only %s, got: %v", innerName, klass.nestMembers)
+	}
+}
+
+func TestNestHostClassAttributeInvalidCPentry(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+
+	// redirect stderr to avoid printing error messages to console
+	normalStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stderr = w
+
+	klass := ParsedClass{}
+	klass.cpIndex = append(klass.cpIndex, cpEntry{})
+	klass.cpIndex = append(klass.cpIndex, cpEntry{UTF8, 0}) // "NestHost"
+	klass.utf8Refs = append(klass.utf8Refs, utf8Entry{"NestHost"})
+	klass.cpCount = 2
+	klass.attribCount = 1
+
+	bytes := []byte{00, // dummy byte
+		00, 01, // CP[1] -> UTF8[0] -> "NestHost"
+		00, 00, 00, 02, // length of attribute
+		00, 01, // CP[1] is a UTF8 entry, not a ClassRef, so this is invalid
+	}
+
+	_, err := parseClassAttributes(bytes, 0, &klass)
+	if err == nil {
+		t.Error("Expected an error for NestHost pointing to a non-ClassRef entry, but got none")
+	}
+
+	_ = w.Close()
+	os.Stderr = normalStderr
+}
+
+// the major and minor versions of class files are gated per JVMS 4.1, and the
+// errors carry the codes for which UnsupportedClassVersionError is thrown
+func TestParseJavaVersionGating(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	globals.SetTraceWriter(io.Discard)
+	defer trace.Init()
+
+	latest := globals.GetGlobalRef().MaxJavaVersionRaw
+	tests := []struct {
+		major, minor int
+		preview      bool
+		code         ErrorCode
+	}{
+		{52, 3, false, ""}, // minor versions are unrestricted before version 56
+		{latest, 0, false, ""},
+		{latest + 1, 0, false, CfeVersionTooNew},
+		{44, 0, false, CfeVersionTooOld},
+		{latest, 1, false, CfeVersionMinor},
+		{latest, 0xFFFF, false, CfePreviewNotEnabled},
+		{latest, 0xFFFF, true, ""},
+		{latest - 1, 0xFFFF, true, CfePreviewVersion},
+	}
+	for _, test := range tests {
+		globals.GetGlobalRef().EnablePreview = test.preview
+		bytes := []byte{0xCA, 0xFE, 0xBA, 0xBE,
+			byte(test.minor >> 8), byte(test.minor), byte(test.major >> 8), byte(test.major)}
+		klass := ParsedClass{}
+		err := parseJavaVersionNumber(bytes, &klass)
+		if ErrorCodeOf(err) != test.code || (err != nil) != IsClassVersionError(err) {
+			t.Errorf("Version %d.%d (preview: %v): expected code %q, got: %v",
+				test.major, test.minor, test.preview, test.code, err)
+		}
+		if klass.javaVersion != test.major || klass.minorVersion != test.minor {
+			t.Errorf("Expected version %d.%d to be recorded, got %d.%d",
+				test.major, test.minor, klass.javaVersion, klass.minorVersion)
+		}
+	}
+}
+
+func TestClassVersionMessage(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	globals.SetTraceWriter(io.Discard)
+	defer trace.Init()
+
+	latest := globals.GetGlobalRef().MaxJavaVersionRaw
+	klass := ParsedClass{javaVersion: latest + 1}
+	msg := classVersionMessage("Foo", &klass, cfeCode(CfeVersionTooNew, latest+1, 0, latest, 21))
+	expected := fmt.Sprintf("Foo has been compiled by a more recent version of the Java Runtime "+
+		"(class file version %d.0), this version of the Java Runtime only recognizes "+
+		"class file versions up to %d.0", latest+1, latest)
+	if msg != expected {
+		t.Errorf("Expected %q, got %q", expected, msg)
+	}
+
+	klass = ParsedClass{javaVersion: latest, minorVersion: 0xFFFF}
+	msg = classVersionMessage("Foo", &klass, cfeCode(CfePreviewNotEnabled, latest))
+	if !strings.Contains(msg, "Try running with '--enable-preview'") {
+		t.Errorf("Expected the message to suggest --enable-preview, got %q", msg)
+	}
+}
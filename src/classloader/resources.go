@@ -0,0 +1,55 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package classloader
+
+import (
+	"io/fs"
+	"jacobin/src/globals"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FindResource returns the contents of a resource, such as a .properties file, found
+// the way the application classloader finds classes: in the starting JAR if there is
+// one, and otherwise in the JARs and directories on the classpath, in order. The name
+// is a /-separated path relative to the root of the JAR or directory, with no leading /.
+// If the resource can't be found, the error is fs.ErrNotExist.
+func FindResource(name string) ([]byte, error) {
+	name = strings.TrimPrefix(name, "/")
+	glob := globals.GetGlobalRef()
+
+	if glob.StartingJar != "" {
+		return readJarResource(glob.StartingJar, name)
+	}
+
+	for _, entry := range glob.Classpath {
+		var data []byte
+		var err error
+		if strings.HasSuffix(entry, ".jar") {
+			data, err = readJarResource(entry, name)
+		} else {
+			data, err = os.ReadFile(filepath.Join(entry, filepath.FromSlash(name)))
+		}
+		if err == nil {
+			return data, nil
+		}
+	}
+	return nil, fs.ErrNotExist
+}
+
+// readJarResource reads an entry from a JAR file, caching the archive in the app classloader
+func readJarResource(jarFileName, name string) ([]byte, error) {
+	if AppCL.Archives == nil {
+		AppCL.Archives = make(map[string]*Archive)
+	}
+	jar, err := getJarFile(AppCL, jarFileName)
+	if err != nil {
+		return nil, err
+	}
+	return jar.ReadEntry(name)
+}
@@ -0,0 +1,67 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package classloader
+
+import (
+	"errors"
+	"io/fs"
+	"jacobin/src/globals"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindResourceOnClasspath(t *testing.T) {
+	globals.InitGlobals("test")
+	glob := globals.GetGlobalRef()
+
+	empty := t.TempDir()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "msgs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "msgs", "app.properties"), []byte("k=v\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	glob.StartingJar = ""
+	glob.Classpath = []string{empty, dir}
+
+	data, err := FindResource("msgs/app.properties")
+	if err != nil || string(data) != "k=v\n" {
+		t.Errorf("FindResource: got %q, %v", data, err)
+	}
+	data, err = FindResource("/msgs/app.properties")
+	if err != nil || string(data) != "k=v\n" {
+		t.Errorf("FindResource with leading /: got %q, %v", data, err)
+	}
+
+	_, err = FindResource("msgs/missing.properties")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("FindResource of missing resource: expected fs.ErrNotExist, got %v", err)
+	}
+}
+
+func TestFindResourceInStartingJar(t *testing.T) {
+	globals.InitGlobals("test")
+	glob := globals.GetGlobalRef()
+
+	jarName, err := getJarFileName(GOOD_JAR_NAME)
+	if err != nil {
+		t.Fatal(err)
+	}
+	glob.StartingJar = jarName
+	defer func() { glob.StartingJar = "" }()
+
+	data, err := FindResource("META-INF/MANIFEST.MF")
+	if err != nil || len(data) == 0 {
+		t.Errorf("FindResource of manifest: got %d bytes, %v", len(data), err)
+	}
+	_, err = FindResource("no/such/resource.txt")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("FindResource of missing entry: expected fs.ErrNotExist, got %v", err)
+	}
+}
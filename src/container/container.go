@@ -0,0 +1,104 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+// Package container detects the limits on memory and CPU that Linux control groups
+// (cgroups), as used by Docker, Kubernetes, and the like, impose on the process, so
+// that Jacobin can size itself to its container rather than to the host, as the JDK
+// has done since Java 10. Both versions of cgroups are supported: v2, the unified
+// hierarchy, and v1, with a hierarchy for each controller. Since a container has its
+// own cgroup namespace, the limits are read from the root of the hierarchy.
+//
+// On other operating systems, and on Linux without limits, no limits are reported.
+package container
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Limits are the limits on the resources the process may use. A value of 0 means
+// there is no limit.
+type Limits struct {
+	Memory int64 // in bytes
+	CPUs   int   // the CPU quota, rounded up to whole CPUs
+}
+
+// where the cgroup hierarchy is mounted
+var cgroupRoot = "/sys/fs/cgroup"
+
+// cgroups v1 reports no limit on memory as a huge number, rounded down to a page
+const v1NoMemoryLimit = math.MaxInt64 / 2
+
+// Detect returns the limits of the process's cgroup, if any
+func Detect() Limits {
+	if runtime.GOOS != "linux" {
+		return Limits{}
+	}
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err == nil {
+		return detectV2()
+	}
+	return detectV1()
+}
+
+// reads the limits of cgroups v2: memory.max holds the limit or "max", and cpu.max
+// holds the quota, or "max", and the period, both in microseconds
+func detectV2() Limits {
+	var limits Limits
+	if memory, ok := readValue("memory.max"); ok && memory != "max" {
+		limits.Memory, _ = strconv.ParseInt(memory, 10, 64)
+	}
+	if cpu, ok := readValue("cpu.max"); ok {
+		fields := strings.Fields(cpu)
+		if len(fields) == 2 && fields[0] != "max" {
+			limits.CPUs = cpusForQuota(fields[0], fields[1])
+		}
+	}
+	return limits
+}
+
+// reads the limits of cgroups v1, in which the memory and CPU controllers have
+// hierarchies of their own. A quota of -1 means there is none.
+func detectV1() Limits {
+	var limits Limits
+	if memory, ok := readValue("memory", "memory.limit_in_bytes"); ok {
+		if n, err := strconv.ParseInt(memory, 10, 64); err == nil && n < v1NoMemoryLimit {
+			limits.Memory = n
+		}
+	}
+	for _, controller := range []string{"cpu", "cpu,cpuacct"} {
+		quota, ok := readValue(controller, "cpu.cfs_quota_us")
+		period, ok2 := readValue(controller, "cpu.cfs_period_us")
+		if ok && ok2 {
+			limits.CPUs = cpusForQuota(quota, period)
+			break
+		}
+	}
+	return limits
+}
+
+// returns the number of CPUs a quota of CPU time per period amounts to, rounded up,
+// or 0 if there is no quota
+func cpusForQuota(quota, period string) int {
+	q, err := strconv.ParseInt(quota, 10, 64)
+	p, err2 := strconv.ParseInt(period, 10, 64)
+	if err != nil || err2 != nil || q <= 0 || p <= 0 {
+		return 0
+	}
+	return int((q + p - 1) / p)
+}
+
+// reads the value in a file of the cgroup hierarchy, given its path from the root
+func readValue(path ...string) (string, bool) {
+	contents, err := os.ReadFile(filepath.Join(append([]string{cgroupRoot}, path...)...))
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(contents)), true
+}
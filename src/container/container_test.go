@@ -0,0 +1,88 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// creates a cgroup hierarchy with the given files and their contents, and makes it
+// the one Detect() reads
+func fakeCgroup(t *testing.T, files map[string]string) {
+	if runtime.GOOS != "linux" {
+		t.Skip("cgroups exist only on Linux")
+	}
+	root := t.TempDir()
+	for name, contents := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	saved := cgroupRoot
+	cgroupRoot = root
+	t.Cleanup(func() { cgroupRoot = saved })
+}
+
+func TestDetectV2Limits(t *testing.T) {
+	fakeCgroup(t, map[string]string{
+		"cgroup.controllers": "cpuset cpu io memory pids",
+		"memory.max":         "536870912",
+		"cpu.max":            "150000 100000",
+	})
+	limits := Detect()
+	if limits.Memory != 512*1024*1024 || limits.CPUs != 2 {
+		t.Errorf("Expected 512m of memory and 2 CPUs, got: %+v", limits)
+	}
+}
+
+func TestDetectV2NoLimits(t *testing.T) {
+	fakeCgroup(t, map[string]string{
+		"cgroup.controllers": "cpuset cpu io memory pids",
+		"memory.max":         "max",
+		"cpu.max":            "max 100000",
+	})
+	if limits := Detect(); limits != (Limits{}) {
+		t.Errorf("Expected no limits, got: %+v", limits)
+	}
+}
+
+func TestDetectV1Limits(t *testing.T) {
+	fakeCgroup(t, map[string]string{
+		"memory/memory.limit_in_bytes":  "1073741824",
+		"cpu,cpuacct/cpu.cfs_quota_us":  "400000",
+		"cpu,cpuacct/cpu.cfs_period_us": "100000",
+	})
+	limits := Detect()
+	if limits.Memory != 1024*1024*1024 || limits.CPUs != 4 {
+		t.Errorf("Expected 1g of memory and 4 CPUs, got: %+v", limits)
+	}
+}
+
+func TestDetectV1NoLimits(t *testing.T) {
+	fakeCgroup(t, map[string]string{
+		"memory/memory.limit_in_bytes": "9223372036854771712",
+		"cpu/cpu.cfs_quota_us":         "-1",
+		"cpu/cpu.cfs_period_us":        "100000",
+	})
+	if limits := Detect(); limits != (Limits{}) {
+		t.Errorf("Expected no limits, got: %+v", limits)
+	}
+}
+
+func TestDetectWithoutCgroups(t *testing.T) {
+	fakeCgroup(t, nil)
+	if limits := Detect(); limits != (Limits{}) {
+		t.Errorf("Expected no limits without a cgroup hierarchy, got: %+v", limits)
+	}
+}
@@ -24,12 +24,14 @@ const (
 	AtomicMoveNotSupportedException
 	BufferOverflowException
 	BufferUnderflowException
+	CancellationException
 	CannotRedoException
 	CannotUndoException
 	CatalogException
 	ClassCastException
 	ClassNotFoundException
 	ClassNotPreparedException
+	ClosedWatchServiceException
 	CMMException
 	CompletionException
 	ConcurrentModificationException
@@ -76,9 +78,12 @@ const (
 	MalformedParameterizedTypeException
 	MalformedParametersException // for HotSpot reflection: param count wrong, CP index invalid, illegal flag combo
 	MirroredTypesException
+	MissingFormatArgumentException
 	MissingResourceException
 	NativeMethodException
 	NegativeArraySizeException
+	NonReadableChannelException
+	NonWritableChannelException
 	NoSuchDynamicMethodException
 	NoSuchElementException
 	NoSuchMechanismException
@@ -99,6 +104,7 @@ const (
 	UncheckedIOException
 	UndeclaredThrowableException
 	UnknownEntityException
+	UnknownFormatConversionException
 	UnmodifiableModuleException
 	UnmodifiableSetException
 	UnsupportedOperationException
@@ -111,6 +117,7 @@ const (
 
 	// non-runtime exceptions
 	AbsentInformationException
+	AEADBadTagException
 	AclNotFoundException
 	ActivationException
 	AgentInitializationException
@@ -128,9 +135,11 @@ const (
 	CertificateException
 	ClassNotLoadedException
 	CloneNotSupportedException
+	ClosedChannelException
 	DataFormatException
 	DatatypeConfigurationException
 	DestroyFailedException
+	EOFException
 	ExecutionControlException
 	ExecutionException
 	ExpandVetoException
@@ -143,7 +152,9 @@ const (
 	IncompatibleThreadStateException
 	InterruptedException
 	IntrospectionException
+	InvalidAlgorithmParameterException
 	InvalidApplicationException // MBean exception in JMX, rarely shown to user
+	InvalidKeyException
 	InvalidMidiDataException
 	InvalidPreferencesFormatException
 	InvalidTypeException
@@ -159,9 +170,13 @@ const (
 	MimeTypeParseException
 	NamingException
 	NoninvertibleTransformException
+	NoSuchAlgorithmException
 	NoSuchFieldException
+	NoSuchFileException
 	NoSuchMethodException
+	NoSuchPaddingException
 	NotBoundException
+	NotDirectoryException
 	ParseException
 	ParserConfigurationException
 	PrinterException
@@ -194,6 +209,7 @@ const (
 	XMLParseException
 	XMLSignatureException
 	XMLStreamException
+	ZipException
 
 	// Java errors
 	AnnotationFormatError
@@ -205,6 +221,7 @@ const (
 	CoderMalfunctionError
 	ExceptionInInitializerError // for exceptions in static initalizers
 	FactoryConfigurationError
+	IllegalAccessError
 	IncompatibleClassChangeError // if class has changed unexpectedly
 	InternalError
 	IOError
@@ -247,12 +264,14 @@ var JVMexceptionNamesJacobin = []string{
 	"java.nio.file.AtomicMoveNotSupportedException",          // VERIFIED
 	"java.nio.BufferOverflowException",                       // VERIFIED
 	"java.nio.BufferUnderflowException",                      // VERIFIED
+	"java.util.concurrent.CancellationException",             // VERIFIED
 	"javax.swing.undo.CannotRedoException",                   // VERIFIED
 	"javax.swing.undo.CannotUndoException",                   // VERIFIED
 	"javax.xml.catalog.CatalogException",                     // VERIFIED
 	"java.lang.ClassCastException",                           // VERIFIED
 	"java.lang.ClassNotFoundException",                       // VERIFIED
 	"org.jacobin.ClassNotPreparedException",                  // VERIFIED
+	"java.nio.file.ClosedWatchServiceException",              // VERIFIED
 	"java.awt.color.CMMException",                            // VERIFIED
 	"java.util.concurrent.CompletionException",               // VERIFIED
 	"java.util.ConcurrentModificationException",              // VERIFIED
@@ -299,9 +318,12 @@ var JVMexceptionNamesJacobin = []string{
 	"java.lang.reflect.MalformedParameterizedTypeException",  // VERIFIED
 	"java.lang.reflect.MalformedParametersException",         // VERIFIED
 	"javax.lang.model.type.MirroredTypesException",           // VERIFIED
+	"java.util.MissingFormatArgumentException",               // VERIFIED
 	"java.util.MissingResourceException",                     // VERIFIED
 	"org.jacobin.NativeMethodException",                      // VERIFIED
 	"java.lang.NegativeArraySizeException",                   // VERIFIED
+	"java.nio.channels.NonReadableChannelException",          // VERIFIED
+	"java.nio.channels.NonWritableChannelException",          // VERIFIED
 	"jdk.dynalink.NoSuchDynamicMethodException",              // VERIFIED
 	"java.util.NoSuchElementException",                       // VERIFIED
 	"javax.xml.crypto.NoSuchMechanismException",              // VERIFIED
@@ -322,6 +344,7 @@ var JVMexceptionNamesJacobin = []string{
 	"java.io.UncheckedIOException",                           // VERIFIED
 	"java.lang.reflect.UndeclaredThrowableException",         // VERIFIED
 	"javax.lang.model.UnknownEntityException",                // VERIFIED
+	"java.util.UnknownFormatConversionException",             // VERIFIED
 	"java.lang.instrument.UnmodifiableModuleException",       // VERIFIED
 	"javax.print.attribute.UnmodifiableSetException",         // VERIFIED
 	"java.lang.UnsupportedOperationException",                // VERIFIED
@@ -334,6 +357,7 @@ var JVMexceptionNamesJacobin = []string{
 
 	// non-runtime exceptions
 	"org.jacobin.AbsentInformationException",                    // VERIFIED
+	"javax.crypto.AEADBadTagException",                          // VERIFIED
 	"java.security.acl.AclNotFoundException",                    // VERIFIED might not be part of JDK 17
 	"java.rmi.activation.ActivationException",                   // VERIFIED might not be part of JDK 17
 	"org.jacobin.tools.attach.AgentInitializationException",     // VERIFIED
@@ -351,9 +375,11 @@ var JVMexceptionNamesJacobin = []string{
 	"java.security.cert.CertificateException",                   // VERIFIED
 	"org.jacobin.ClassNotLoadedException",                       // VERIFIED
 	"java.lang.CloneNotSupportedException",                      // VERIFIED
+	"java.nio.channels.ClosedChannelException",                  // VERIFIED
 	"java.util.zip.DataFormatException",                         // VERIFIED
 	"java.lang.DatatypeConfigurationException",                  // VERIFIED
 	"javax.security.auth.DestroyFailedException",                // VERIFIED
+	"java.io.EOFException",                                      // VERIFIED
 	"jdk.jshell.spi.ExecutionControl.ExecutionControlException", // VERIFIED
 	"java.util.concurrent.ExecutionException",                   // VERIFIED
 	"javax.swing.tree.ExpandVetoException",                      // VERIFIED
@@ -366,7 +392,9 @@ var JVMexceptionNamesJacobin = []string{
 	"org.jacobin.IncompatibleThreadStateException",              // VERIFIED
 	"java.lang.InterruptedException",                            // VERIFIED
 	"javax.management.IntrospectionException",                   // VERIFIED
+	"java.security.InvalidAlgorithmParameterException",          // VERIFIED
 	"javax.management.InvalidApplicationException",              // VERIFIED
+	"java.security.InvalidKeyException",                         // VERIFIED
 	"javax.sound.InvalidMidiDataException",                      // VERIFIED
 	"java.util.prefs.InvalidPreferencesFormatException",         // VERIFIED
 	"org.jacobin.InvalidTypeException",                          // VERIFIED
@@ -382,9 +410,13 @@ var JVMexceptionNamesJacobin = []string{
 	"java.awt.datatransfer.MimeTypeParseException",              // VERIFIED
 	"javax.naming.NamingException",                              // VERIFIED
 	"java.awt.geom.NoninvertibleTransformException",             // VERIFIED
+	"java.security.NoSuchAlgorithmException",                    // VERIFIED
 	"java.lang.NoSuchFieldException",                            // VERIFIED
+	"java.nio.file.NoSuchFileException",                         // VERIFIED
 	"java.lang.NoSuchMethodException",                           // VERIFIED
+	"javax.crypto.NoSuchPaddingException",                       // VERIFIED
 	"java.rmi.NotBoundException",                                // VERIFIED
+	"java.nio.file.NotDirectoryException",                       // VERIFIED
 	"java.text.ParseException",                                  // VERIFIED
 	"javax.xml.parsers.ParserConfigurationException",            // VERIFIED
 	"java.awt.print.PrinterException",                           // VERIFIED
@@ -417,6 +449,7 @@ var JVMexceptionNamesJacobin = []string{
 	"javax.management.modelmbean.XMLParseException",             // VERIFIED
 	"javax.xml.crypto.dsig.XMLSignatureException",               // VERIFIED
 	"javax.xml.stream.XMLStreamException",                       // VERIFIED
+	"java.util.zip.ZipException",                                // VERIFIED
 
 	// Java errors
 	"java.lang.annotation.AnnotationFormatError",               // VERIFIED
@@ -428,6 +461,7 @@ var JVMexceptionNamesJacobin = []string{
 	"java.nio.charset.CoderMalfunctionError",                   // VERIFIED
 	"java.lang.ExceptionInInitializerError",                    // VERIFIED
 	"javax.xml.parsers.FactoryConfigurationError",              // VERIFIED
+	"java.lang.IllegalAccessError",                             // access-control violations at resolution time
 	"java.lang.IncompatibleClassChangeError",                   // VERIFIED used in interface lookups, among otherd
 	"java.lang.InternalError",                                  // VERIFIED
 	"java.io.IOError",                                          // VERIFIED
@@ -469,12 +503,14 @@ var JVMexceptionNames = []string{
 	"java.nio.file.AtomicMoveNotSupportedException",          // VERIFIED
 	"java.nio.BufferOverflowException",                       // VERIFIED
 	"java.nio.BufferUnderflowException",                      // VERIFIED
+	"java.util.concurrent.CancellationException",             // VERIFIED
 	"javax.swing.undo.CannotRedoException",                   // VERIFIED
 	"javax.swing.undo.CannotUndoException",                   // VERIFIED
 	"javax.xml.catalog.CatalogException",                     // VERIFIED
 	"java.lang.ClassCastException",                           // VERIFIED
 	"java.lang.ClassNotFoundException",                       // VERIFIED
 	"com.sun.jdi.ClassNotPreparedException",                  // VERIFIED
+	"java.nio.file.ClosedWatchServiceException",              // VERIFIED
 	"java.awt.color.CMMException",                            // VERIFIED
 	"java.util.concurrent.CompletionException",               // VERIFIED
 	"java.util.ConcurrentModificationException",              // VERIFIED
@@ -521,9 +557,12 @@ var JVMexceptionNames = []string{
 	"java.lang.reflect.MalformedParameterizedTypeException",  // VERIFIED
 	"java.lang.reflect.MalformedParametersException",         // VERIFIED
 	"javax.lang.model.type.MirroredTypesException",           // VERIFIED
+	"java.util.MissingFormatArgumentException",               // VERIFIED
 	"java.util.MissingResourceException",                     // VERIFIED
 	"com.sun.jdi.NativeMethodException",                      // VERIFIED
 	"java.lang.NegativeArraySizeException",                   // VERIFIED
+	"java.nio.channels.NonReadableChannelException",          // VERIFIED
+	"java.nio.channels.NonWritableChannelException",          // VERIFIED
 	"jdk.dynalink.NoSuchDynamicMethodException",              // VERIFIED
 	"java.util.NoSuchElementException",                       // VERIFIED
 	"javax.xml.crypto.NoSuchMechanismException",              // VERIFIED
@@ -544,6 +583,7 @@ var JVMexceptionNames = []string{
 	"java.io.UncheckedIOException",                           // VERIFIED
 	"java.lang.reflect.UndeclaredThrowableException",         // VERIFIED
 	"javax.lang.model.UnknownEntityException",                // VERIFIED
+	"java.util.UnknownFormatConversionException",             // VERIFIED
 	"java.lang.instrument.UnmodifiableModuleException",       // VERIFIED
 	"javax.print.attribute.UnmodifiableSetException",         // VERIFIED
 	"java.lang.UnsupportedOperationException",                // VERIFIED
@@ -556,6 +596,7 @@ var JVMexceptionNames = []string{
 
 	// non-runtime exceptions
 	"com.sun.jdi.AbsentInformationException",                    // VERIFIED
+	"javax.crypto.AEADBadTagException",                          // VERIFIED
 	"java.security.acl.AclNotFoundException",                    // VERIFIED might not be part of JDK 17
 	"java.rmi.activation.ActivationException",                   // VERIFIED might not be part of JDK 17
 	"com.sun.tools.attach.AgentInitializationException",         // VERIFIED
@@ -573,9 +614,11 @@ var JVMexceptionNames = []string{
 	"java.security.cert.CertificateException",                   // VERIFIED
 	"com.sun.jdi.ClassNotLoadedException",                       // VERIFIED
 	"java.lang.CloneNotSupportedException",                      // VERIFIED
+	"java.nio.channels.ClosedChannelException",                  // VERIFIED
 	"java.util.zip.DataFormatException",                         // VERIFIED
 	"java.lang.DatatypeConfigurationException",                  // VERIFIED
 	"javax.security.auth.DestroyFailedException",                // VERIFIED
+	"java.io.EOFException",                                      // VERIFIED
 	"dk.jshell.spi.ExecutionControl.ExecutionControlException",  // VERIFIED
 	"java.util.concurrent.ExecutionException",                   // VERIFIED
 	"javax.swing.tree.ExpandVetoException",                      // VERIFIED
@@ -588,7 +631,9 @@ var JVMexceptionNames = []string{
 	"com.sun.jdi.IncompatibleThreadStateException",              // VERIFIED
 	"java.lang.InterruptedException",                            // VERIFIED
 	"javax.management.IntrospectionException",                   // VERIFIED
+	"java.security.InvalidAlgorithmParameterException",          // VERIFIED
 	"javax.management.InvalidApplicationException",              // VERIFIED
+	"java.security.InvalidKeyException",                         // VERIFIED
 	"javax.sound.InvalidMidiDataException",                      // VERIFIED
 	"java.util.prefs.InvalidPreferencesFormatException",         // VERIFIED
 	"com.sun.jdi.InvalidTypeException",                          // VERIFIED
@@ -604,9 +649,13 @@ var JVMexceptionNames = []string{
 	"java.awt.datatransfer.MimeTypeParseException",              // VERIFIED
 	"javax.naming.NamingException",                              // VERIFIED
 	"java.awt.geom.NoninvertibleTransformException",             // VERIFIED
+	"java.security.NoSuchAlgorithmException",                    // VERIFIED
 	"java.lang.NoSuchFieldException",                            // VERIFIED
+	"java.nio.file.NoSuchFileException",                         // VERIFIED
 	"java.lang.NoSuchMethodException",                           // VERIFIED
+	"javax.crypto.NoSuchPaddingException",                       // VERIFIED
 	"java.rmi.NotBoundException",                                // VERIFIED
+	"java.nio.file.NotDirectoryException",                       // VERIFIED
 	"java.text.ParseException",                                  // VERIFIED
 	"javax.xml.parsers.ParserConfigurationException",            // VERIFIED
 	"java.awt.print.PrinterException",                           // VERIFIED
@@ -639,6 +688,7 @@ var JVMexceptionNames = []string{
 	"javax.management.modelmbean.XMLParseException",             // VERIFIED
 	"javax.xml.crypto.dsig.XMLSignatureException",               // VERIFIED
 	"javax.xml.stream.XMLStreamException",                       // VERIFIED
+	"java.util.zip.ZipException",                                // VERIFIED
 
 	// Java errors
 	"java.lang.annotation.AnnotationFormatError",               // VERIFIED
@@ -650,6 +700,7 @@ var JVMexceptionNames = []string{
 	"java.nio.charset.CoderMalfunctionError",                   // VERIFIED
 	"java.lang.ExceptionInInitializerError",                    // VERIFIED
 	"javax.xml.parsers.FactoryConfigurationError",              // VERIFIED
+	"java.lang.IllegalAccessError",                             // access-control violations at resolution time
 	"java.lang.IncompatibleClassChangeError",                   // VERIFIED used in interface lookups, among otherd
 	"java.lang.InternalError",                                  // VERIFIED
 	"java.io.IOError",                                          // VERIFIED
@@ -676,3 +727,16 @@ var JVMexceptionNames = []string{
 	"java.io.UnsupportedEncodingException",      // VERIFIED
 	"java.io.UTFDataFormatException",            // VERIFIED
 }
+
+// ExceptionIndex returns the index (that is, the constant defined above) of the
+// exception whose Java name (e.g., java.lang.ArithmeticException) is passed in.
+// Both the JDK names and the Jacobin-specific names are recognized. Returns
+// Unknown if the exception is not in either list.
+func ExceptionIndex(javaName string) int {
+	for i := 1; i < len(JVMexceptionNames); i++ {
+		if JVMexceptionNames[i] == javaName || JVMexceptionNamesJacobin[i] == javaName {
+			return i
+		}
+	}
+	return Unknown
+}
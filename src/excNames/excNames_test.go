@@ -23,6 +23,12 @@ func TestExceptionTableAlignment(t *testing.T) {
 	details(t, XMLParseException, "javax.management.modelmbean.XMLParseException")
 	details(t, VirtualMachineError, "java.lang.VirtualMachineError")
 	details(t, UTFDataFormatException, "java.io.UTFDataFormatException")
+	details(t, EOFException, "java.io.EOFException")
+	details(t, ClosedChannelException, "java.nio.channels.ClosedChannelException")
+	details(t, NonWritableChannelException, "java.nio.channels.NonWritableChannelException")
+	details(t, ClosedWatchServiceException, "java.nio.file.ClosedWatchServiceException")
+	details(t, NotDirectoryException, "java.nio.file.NotDirectoryException")
+	details(t, NoSuchFileException, "java.nio.file.NoSuchFileException")
 }
 
 // Make sure that the enum for the exception correctly matches the string. Exceptions tested here
@@ -55,3 +61,15 @@ func detailsJacobin(t *testing.T, index int, expected string) {
 		t.Errorf("JVMexceptionNamesJacobin[%d]: expected %s, but observed %s\n", index, expected, observed)
 	}
 }
+
+func TestExceptionIndex(t *testing.T) {
+	if ExceptionIndex("java.lang.IllegalStateException") != IllegalStateException {
+		t.Errorf("Expected index of IllegalStateException, got %d", ExceptionIndex("java.lang.IllegalStateException"))
+	}
+	if ExceptionIndex("org.jacobin.ClassNotLoadedException") != ClassNotLoadedException {
+		t.Error("Expected the Jacobin-specific name of ClassNotLoadedException to be recognized")
+	}
+	if ExceptionIndex("com.example.NoSuchException") != Unknown {
+		t.Error("Expected an unlisted exception to return Unknown")
+	}
+}
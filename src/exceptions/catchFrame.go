@@ -13,6 +13,7 @@ import (
 	"jacobin/src/excNames"
 	"jacobin/src/frames"
 	"jacobin/src/globals"
+	"jacobin/src/object"
 	"jacobin/src/stringPool"
 	"jacobin/src/trace"
 	"jacobin/src/util"
@@ -27,6 +28,10 @@ var javaLangThrowable = "java/lang/Throwable"
 // current frame stack working its way up the frame stack (fs). If one is found,
 // it returns a pointer to that frame, otherwise it returns nil. Param pc is the
 // program counter in the current frame where the execption was thrown.
+// The search does not go past a frame running a <clinit> method, because an
+// exception that escapes a static initializer is not propagated to the code
+// that triggered the initialization, nor past an upcall frame, because the
+// exception is then handed back to the Go code that called Java (see AbortToBoundary).
 func FindCatchFrame(fs *list.List, exceptName string, pc int) (*frames.Frame, int) {
 	excName := util.ConvertClassFilenameToInternalFormat(exceptName)
 
@@ -36,6 +41,9 @@ func FindCatchFrame(fs *list.List, exceptName string, pc int) (*frames.Frame, in
 	firstTimeThrough := true
 	for fr := fs.Front(); fr != nil; {
 		var f = fr.Value.(*frames.Frame)
+		if f.MethName == frames.UpcallMethName { // the frame has no code to search
+			return nil, -1
+		}
 		var searchPC int
 
 		if f.ExceptionPC == -1 {
@@ -55,8 +63,8 @@ func FindCatchFrame(fs *list.List, exceptName string, pc int) (*frames.Frame, in
 		if excFrame != nil {
 			break
 		} else { // if the exception was not found in this frame, we delete the current frame
-			// unless it's the last frame
-			if fr.Next() == nil {
+			// unless it's the last frame or the frame of a static initializer
+			if fr.Next() == nil || isBoundaryFrame(f) {
 				return nil, -1
 			}
 			firstTimeThrough = false
@@ -66,6 +74,63 @@ func FindCatchFrame(fs *list.List, exceptName string, pc int) (*frames.Frame, in
 	return excFrame, excPC
 }
 
+// AbortToBoundary handles an exception that is not caught before it reaches a
+// boundary frame: a <clinit> frame or an upcall frame. If such a frame is on the
+// frame stack, the frames above it are removed, the exception (described by cause,
+// and its Throwable, if it has been created) is recorded in the boundary frame, and that frame's PC is set past the end of its
+// bytecode so that the interpreter stops executing it. The code that pushed the
+// boundary frame then deals with the exception: for a static initializer, the class
+// is marked as erroneous and an ExceptionInInitializerError is thrown in the
+// triggering code; for an upcall, the exception is returned to the Go caller.
+// Returns false if there is no boundary frame, in which case the exception is
+// truly uncaught.
+func AbortToBoundary(fs *list.List, cause string, throwable *object.Object) bool {
+	var boundary *frames.Frame
+	for fr := fs.Front(); fr != nil; fr = fr.Next() {
+		f := fr.Value.(*frames.Frame)
+		if isBoundaryFrame(f) {
+			boundary = f
+			break
+		}
+	}
+	if boundary == nil {
+		return false
+	}
+
+	for fs.Front().Value.(*frames.Frame) != boundary {
+		fs.Remove(fs.Front())
+	}
+
+	if globals.TraceVerbose {
+		trace.Trace(fmt.Sprintf("AbortToBoundary: %s thrown in %s", cause, frames.FormatFQN(boundary)))
+	}
+	boundary.Uncaught = cause
+	boundary.UncaughtObj = throwable
+	boundary.PC = len(boundary.Meth)
+	boundary.ExceptionPC = -1
+	return true
+}
+
+// UpcallError is returned to Go code that calls a Java method (see
+// jvm.InvokeJavaMethod) when the method throws an exception that it does not
+// catch. Cause holds the Java name of the exception followed by its detail
+// message, if any, as recorded by AbortToBoundary, and Throwable holds the
+// exception itself, if it was created.
+type UpcallError struct {
+	Method    string
+	Cause     string
+	Throwable *object.Object
+}
+
+func (e *UpcallError) Error() string {
+	return fmt.Sprintf("exception in %s: %s", e.Method, e.Cause)
+}
+
+// isBoundaryFrame reports whether uncaught exceptions stop at the frame
+func isBoundaryFrame(f *frames.Frame) bool {
+	return f.MethName == "<clinit>" || f.MethName == frames.UpcallMethName
+}
+
 // locateExceptionFrame (private to package exceptions) is a helper function for FindCatchFrame
 func locateExceptionFrame(f *frames.Frame, excName string, pc int) (*frames.Frame, int) {
 	// get the method and check for an exception catch table
@@ -96,14 +161,25 @@ func locateExceptionFrame(f *frames.Frame, excName string, pc int) (*frames.Fram
 		// per https://docs.oracle.com/javase/specs/jvms/se21/html/jvms-4.html#jvms-4.7.3
 		// the StartPC value is inclusive, the EndPC value is exclusive
 		if pc >= entry.StartPc && pc < entry.EndPc {
-			// found a handler, now check that it's for the right exception
+			// found a handler, now check that it's for the right exception.
+			// A catch type of 0 catches everything (it's used for finally blocks).
+			if entry.CatchType == 0 {
+				return f, entry.HandlerPc
+			}
 			CP := f.CP.(*classloader.CPool)
 			catchName :=
 				classloader.GetClassNameFromCPclassref(CP, uint16(entry.CatchType))
 
-			// TODO: add support for checking for subclasses
-			// In the meantime, check for a direct match or one of the typical
-			// superclasses.
+			// if the exception's class is loaded, its superclasses are too, so
+			// the handler applies if it catches the class or one of its superclasses
+			if classloader.MethAreaFetch(excName) != nil {
+				if classloader.IsSubclassOf(excName, catchName) {
+					return f, entry.HandlerPc
+				}
+				continue
+			}
+
+			// otherwise, check for a direct match or one of the typical superclasses.
 			if catchName == excName ||
 				catchName == "java/lang/Throwable" ||
 				catchName == "java/lang/Exception" ||
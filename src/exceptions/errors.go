@@ -127,7 +127,8 @@ func ShowGoStackTrace(stackInfo any) {
 		if i < len(entries) {
 			if strings.HasPrefix(entries[i], "runtime") ||
 				strings.HasPrefix(entries[i], "jacobin/src/exceptions.ShowGoStackTrace") ||
-				strings.HasPrefix(entries[i], "jacobin/src/exceptions.ThrowEx") {
+				strings.HasPrefix(entries[i], "jacobin/src/exceptions.ThrowEx") ||
+				strings.HasPrefix(entries[i], "jacobin/src/exceptions.throwEx") {
 				i += 2 // skip over runtime traces, we just want app data
 				continue
 			}
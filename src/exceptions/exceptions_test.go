@@ -9,7 +9,9 @@ package exceptions
 import (
 	"io"
 	"jacobin/src/excNames"
+	"jacobin/src/frames"
 	"jacobin/src/globals"
+	"jacobin/src/object"
 	"os"
 	"strings"
 	"testing"
@@ -74,3 +76,68 @@ func TestMinimalThrow(t *testing.T) {
 		t.Errorf("Got unexpected output: %s", msg)
 	}
 }
+
+func TestAbortToBoundary(t *testing.T) {
+	globals.InitGlobals("test")
+
+	fs := frames.CreateFrameStack()
+	caller := frames.CreateFrame(2)
+	caller.MethName = "main"
+	clinit := frames.CreateFrame(2)
+	clinit.MethName = "<clinit>"
+	clinit.Meth = []byte{0x00, 0x00, 0xB1}
+	callee := frames.CreateFrame(2)
+	callee.MethName = "compute"
+	_ = frames.PushFrame(fs, caller)
+	_ = frames.PushFrame(fs, clinit)
+	_ = frames.PushFrame(fs, callee)
+
+	excClass := "java/lang/ArithmeticException"
+	throwable := object.MakeEmptyObjectWithClassName(&excClass)
+	if !AbortToBoundary(fs, "java.lang.ArithmeticException: / by zero", throwable) {
+		t.Fatal("Expected AbortToBoundary to find the <clinit> frame")
+	}
+	if fs.Len() != 2 || fs.Front().Value.(*frames.Frame) != clinit {
+		t.Errorf("Expected the <clinit> frame to be at the top of a 2-frame stack, got %d frames", fs.Len())
+	}
+	if clinit.Uncaught != "java.lang.ArithmeticException: / by zero" {
+		t.Errorf("Unexpected uncaught exception: %s", clinit.Uncaught)
+	}
+	if clinit.UncaughtObj != throwable {
+		t.Error("Expected the Throwable to be recorded in the <clinit> frame")
+	}
+	if clinit.PC != len(clinit.Meth) {
+		t.Errorf("Expected PC to be past the end of <clinit>, got: %d", clinit.PC)
+	}
+
+	// without a <clinit> on the stack, the exception is not handled
+	fs = frames.CreateFrameStack()
+	_ = frames.PushFrame(fs, caller)
+	if AbortToBoundary(fs, "java.lang.ArithmeticException", nil) {
+		t.Error("Did not expect AbortToBoundary to succeed without a <clinit> frame")
+	}
+}
+
+func TestFindCatchFrameStopsAtUpcall(t *testing.T) {
+	globals.InitGlobals("test")
+
+	fs := frames.CreateFrameStack()
+	upcall := frames.CreateFrame(2)
+	upcall.MethName = frames.UpcallMethName
+	_ = frames.PushFrame(fs, upcall)
+
+	// the upcall frame has no code, so it must not be searched for a handler
+	if f, pc := FindCatchFrame(fs, "java/lang/RuntimeException", 0); f != nil || pc != -1 {
+		t.Errorf("Expected no catch frame at an upcall frame, got PC %d", pc)
+	}
+
+	callee := frames.CreateFrame(2)
+	callee.MethName = "get"
+	_ = frames.PushFrame(fs, callee)
+	if !AbortToBoundary(fs, "java.lang.RuntimeException: oops", nil) {
+		t.Fatal("Expected AbortToBoundary to find the upcall frame")
+	}
+	if fs.Len() != 1 || upcall.Uncaught != "java.lang.RuntimeException: oops" {
+		t.Errorf("Expected the exception to be recorded in the upcall frame, got: %q", upcall.Uncaught)
+	}
+}
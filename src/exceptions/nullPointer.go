@@ -0,0 +1,502 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package exceptions
+
+import (
+	"encoding/binary"
+	"fmt"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/opcodes"
+	"jacobin/src/stringPool"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// Helpful NullPointerException messages, as introduced in HotSpot by JEP 358. The
+// message says what the failing bytecode could not do and, when it can be worked
+// out from the bytecode, where the null came from, e.g.:
+//
+//	Cannot read field "next" because "<local1>" is null
+//	Cannot invoke "String.length()" because the return value of "Foo.name()" is null
+//	Cannot load from object array because "this.items" is null
+//
+// The source of the null is found by walking back from the failing bytecode through
+// the straight-line code before it, tracking which instruction pushed the operand
+// stack entry that held the null. If the walk reaches a jump target (where the value
+// could have come from more than one place) or a bytecode whose stack effect isn't
+// tracked, the because-clause is left out, as HotSpot does. Locals are named from the
+// method's local variable table, if the class was compiled with -g; otherwise, they're
+// named as HotSpot names them: this, <parameterN>, and <localN>.
+
+// ThrowNPE throws a NullPointerException for the bytecode at f.PC, which found a
+// null reference where it needed an object or array. jacobinMsg is Jacobin's own
+// description of the problem: with -strictJDK, the exception's message is the helpful
+// message alone, as in HotSpot; otherwise it's jacobinMsg followed by the helpful message.
+// Returns the value the bytecode function should return to the interpreter.
+func ThrowNPE(f *frames.Frame, jacobinMsg string) int {
+	glob := globals.GetGlobalRef()
+	glob.ErrorGoStack = string(debug.Stack())
+
+	errMsg := HelpfulNPEMessage(f, f.PC)
+	if !glob.StrictJDK {
+		if errMsg == "" {
+			errMsg = jacobinMsg
+		} else {
+			errMsg = jacobinMsg + ": " + errMsg
+		}
+	}
+	status := ThrowEx(excNames.NullPointerException, errMsg, f)
+	if status != Caught {
+		return ERROR_OCCURRED // applies only if in test
+	}
+	return RESUME_HERE // caught
+}
+
+// NPEMessage formats a helpful NullPointerException message for Go code, such as
+// gfunctions, that has no bytecode to examine: for example, NPEMessage("invoke
+// \"Thread.start()\"", "task") returns Cannot invoke "Thread.start()" because "task"
+// is null. If nullValue is empty, the because-clause is omitted.
+func NPEMessage(action, nullValue string) string {
+	if nullValue == "" {
+		return "Cannot " + action
+	}
+	return fmt.Sprintf("Cannot %s because \"%s\" is null", action, nullValue)
+}
+
+// HelpfulNPEMessage returns the helpful NullPointerException message for the bytecode
+// at pc in the method executing in frame f, or an empty string if the bytecode does
+// not dereference a reference.
+func HelpfulNPEMessage(f *frames.Frame, pc int) string {
+	if f == nil || pc < 0 || pc >= len(f.Meth) {
+		return ""
+	}
+	a := newNPEAnalyzer(f)
+	code := f.Meth
+	cp, _ := f.CP.(*classloader.CPool)
+
+	var action string
+	var depth int // how far below the top of the operand stack the null was
+	switch op := code[pc]; op {
+	case opcodes.GETFIELD:
+		action = fmt.Sprintf("read field \"%s\"", fieldName(cp, code, pc))
+	case opcodes.PUTFIELD:
+		action = fmt.Sprintf("assign field \"%s\"", fieldName(cp, code, pc))
+		depth = 1
+	case opcodes.INVOKEVIRTUAL, opcodes.INVOKESPECIAL, opcodes.INVOKEINTERFACE:
+		className, methName, methType, ok := methodRef(cp, code, pc)
+		if !ok {
+			return "Cannot invoke a method"
+		}
+		action = fmt.Sprintf("invoke \"%s\"", formatMethod(className, methName, methType))
+		depth = len(parseParamTypes(methType))
+	case opcodes.IALOAD, opcodes.LALOAD, opcodes.FALOAD, opcodes.DALOAD,
+		opcodes.AALOAD, opcodes.BALOAD, opcodes.CALOAD, opcodes.SALOAD:
+		action = fmt.Sprintf("load from %s array", arrayKind(op-opcodes.IALOAD))
+		depth = 1
+	case opcodes.IASTORE, opcodes.LASTORE, opcodes.FASTORE, opcodes.DASTORE,
+		opcodes.AASTORE, opcodes.BASTORE, opcodes.CASTORE, opcodes.SASTORE:
+		action = fmt.Sprintf("store to %s array", arrayKind(op-opcodes.IASTORE))
+		depth = 2
+	case opcodes.ARRAYLENGTH:
+		action = "read the array length"
+	case opcodes.ATHROW:
+		action = "throw exception"
+	case opcodes.MONITORENTER:
+		action = "enter synchronized block"
+	case opcodes.MONITOREXIT:
+		action = "exit synchronized block"
+	default:
+		return ""
+	}
+
+	msg := "Cannot " + action
+	if src, ok := a.sourceOf(pc, depth); ok {
+		if code[src] >= opcodes.INVOKEVIRTUAL && code[src] <= opcodes.INVOKEINTERFACE {
+			if className, methName, methType, ok := methodRef(cp, code, src); ok {
+				msg += fmt.Sprintf(" because the return value of \"%s\" is null",
+					formatMethod(className, methName, methType))
+			}
+		} else if desc, ok := a.describe(src, 0); ok {
+			msg += fmt.Sprintf(" because \"%s\" is null", desc)
+		}
+	}
+	return msg
+}
+
+// the element types of the array load and store bytecodes, in opcode order
+func arrayKind(index byte) string {
+	kinds := []string{"int", "long", "float", "double", "object", "byte/boolean", "char", "short"}
+	if int(index) >= len(kinds) {
+		return "an"
+	}
+	return kinds[index]
+}
+
+// npeAnalyzer holds what's needed to walk the bytecode of a method
+type npeAnalyzer struct {
+	f       *frames.Frame
+	cp      *classloader.CPool
+	starts  []int                       // the PCs at which instructions begin, in order
+	index   map[int]int                 // the position in starts of each instruction's PC
+	targets map[int]bool                // PCs that are jumped to or are exception handlers
+	static  bool                        // whether the method is static
+	locals  []classloader.LocalVariable // the method's LocalVariableTable, if it has one
+}
+
+func newNPEAnalyzer(f *frames.Frame) *npeAnalyzer {
+	a := npeAnalyzer{f: f, index: make(map[int]int), targets: make(map[int]bool)}
+	a.cp, _ = f.CP.(*classloader.CPool)
+	a.locals = classloader.FetchLocalVariables(f.ClName, f.MethName, f.MethType)
+
+	code := f.Meth
+	for pc := 0; pc < len(code); {
+		length := classloader.BytecodeLength(code, pc)
+		if length == 0 {
+			break
+		}
+		a.index[pc] = len(a.starts)
+		a.starts = append(a.starts, pc)
+		a.addJumpTargets(pc)
+		pc += length
+	}
+
+	if mte, ok := classloader.MTable[f.ClName+"."+f.MethName+f.MethType]; ok {
+		if meth, ok := mte.Meth.(classloader.JmEntry); ok {
+			a.static = meth.AccessFlags&classloader.AccStatic != 0
+			for _, exc := range meth.Exceptions {
+				a.targets[exc.HandlerPc] = true
+			}
+		}
+	}
+	return &a
+}
+
+// records the targets of a jump instruction at pc
+func (a *npeAnalyzer) addJumpTargets(pc int) {
+	code := a.f.Meth
+	op := code[pc]
+	switch {
+	case (op >= opcodes.IFEQ && op <= opcodes.JSR) || op == opcodes.IFNULL || op == opcodes.IFNONNULL:
+		a.targets[pc+int(int16(binary.BigEndian.Uint16(code[pc+1:])))] = true
+	case op == opcodes.GOTO_W || op == opcodes.JSR_W:
+		a.targets[pc+int(int32(binary.BigEndian.Uint32(code[pc+1:])))] = true
+	case op == opcodes.TABLESWITCH || op == opcodes.LOOKUPSWITCH:
+		base := (pc + 4) &^ 3
+		offset := func(at int) int { return pc + int(int32(binary.BigEndian.Uint32(code[at:]))) }
+		a.targets[offset(base)] = true // the default
+		if op == opcodes.TABLESWITCH {
+			count := int(int32(binary.BigEndian.Uint32(code[base+8:]))) -
+				int(int32(binary.BigEndian.Uint32(code[base+4:]))) + 1
+			for i := 0; i < count; i++ {
+				a.targets[offset(base+12+i*4)] = true
+			}
+		} else {
+			npairs := int(binary.BigEndian.Uint32(code[base+4:]))
+			for i := 0; i < npairs; i++ {
+				a.targets[offset(base+12+i*8)] = true
+			}
+		}
+	}
+}
+
+// sourceOf finds the instruction that pushed the operand stack entry that was depth
+// entries below the top of the stack when the instruction at pc began. Returns the
+// PC of that instruction and whether it could be determined.
+func (a *npeAnalyzer) sourceOf(pc, depth int) (int, bool) {
+	pos, ok := a.index[pc]
+	if !ok {
+		return -1, false
+	}
+	for ; pos > 0; pos-- {
+		if a.targets[a.starts[pos]] { // the value might have come from elsewhere
+			return -1, false
+		}
+		prev := a.starts[pos-1]
+		if a.f.Meth[prev] == opcodes.DUP {
+			if depth <= 1 { // the entry is one of the copies, so find the original
+				depth = 0
+			} else {
+				depth -= 1
+			}
+			continue
+		}
+		pops, pushes, ok := a.stackEffect(prev)
+		if !ok {
+			return -1, false
+		}
+		if depth < pushes {
+			return prev, true
+		}
+		depth += pops - pushes
+	}
+	return -1, false
+}
+
+// describe returns the Java expression for the value pushed by the instruction at pc,
+// such as <local1>, this.name, or Foo.table[2]. nesting limits how deeply expressions
+// are built up.
+func (a *npeAnalyzer) describe(pc, nesting int) (string, bool) {
+	if nesting > 4 {
+		return "", false
+	}
+	code := a.f.Meth
+	switch op := code[pc]; {
+	case op == opcodes.ACONST_NULL:
+		return "null", true
+	case op == opcodes.ALOAD || op == opcodes.ILOAD:
+		return a.localName(int(code[pc+1]), pc), true
+	case op >= opcodes.ALOAD_0 && op <= opcodes.ALOAD_3:
+		return a.localName(int(op-opcodes.ALOAD_0), pc), true
+	case op >= opcodes.ILOAD_0 && op <= opcodes.ILOAD_3:
+		return a.localName(int(op-opcodes.ILOAD_0), pc), true
+	case op >= opcodes.ICONST_M1 && op <= opcodes.ICONST_5:
+		return strconv.Itoa(int(op) - opcodes.ICONST_0), true
+	case op == opcodes.BIPUSH:
+		return strconv.Itoa(int(int8(code[pc+1]))), true
+	case op == opcodes.SIPUSH:
+		return strconv.Itoa(int(int16(binary.BigEndian.Uint16(code[pc+1:])))), true
+	case op == opcodes.GETSTATIC:
+		className, name, ok := fieldRef(a.cp, code, pc)
+		if !ok {
+			return "", false
+		}
+		return externalClassName(className) + "." + name, true
+	case op == opcodes.GETFIELD:
+		name := fieldName(a.cp, code, pc)
+		if src, ok := a.sourceOf(pc, 0); ok {
+			if obj, ok := a.describe(src, nesting+1); ok {
+				return obj + "." + name, true
+			}
+		}
+		return name, true
+	case op == opcodes.AALOAD:
+		arraySrc, ok1 := a.sourceOf(pc, 1)
+		indexSrc, ok2 := a.sourceOf(pc, 0)
+		if !ok1 || !ok2 {
+			return "", false
+		}
+		array, ok1 := a.describe(arraySrc, nesting+1)
+		index, ok2 := a.describe(indexSrc, nesting+1)
+		if !ok1 {
+			return "", false
+		}
+		if !ok2 {
+			index = "..."
+		}
+		return array + "[" + index + "]", true
+	case op == opcodes.CHECKCAST: // a cast doesn't change the value
+		if src, ok := a.sourceOf(pc, 0); ok {
+			return a.describe(src, nesting)
+		}
+	case op >= opcodes.INVOKEVIRTUAL && op <= opcodes.INVOKEINTERFACE:
+		if className, methName, methType, ok := methodRef(a.cp, code, pc); ok {
+			return formatMethod(className, methName, methType), true
+		}
+	}
+	return "", false
+}
+
+// localName returns the name of the local variable in slot at pc. If the method was
+// compiled with a local variable table (javac -g), that's the name in the table;
+// otherwise, it's HotSpot's name: this, <parameterN> (numbered from 1), or <localN> (by slot)
+func (a *npeAnalyzer) localName(slot, pc int) string {
+	if lv, ok := classloader.LocalVariableAt(a.locals, slot, pc); ok {
+		return lv.Name
+	}
+
+	var names []string // the names of the slots that hold this and the parameters
+	if !a.static {
+		names = append(names, "this")
+	}
+	for i, param := range parseParamTypes(a.f.MethType) {
+		name := fmt.Sprintf("<parameter%d>", i+1)
+		names = append(names, name)
+		if param == "long" || param == "double" { // these take two slots
+			names = append(names, name)
+		}
+	}
+	if slot < len(names) {
+		return names[slot]
+	}
+	return fmt.Sprintf("<local%d>", slot)
+}
+
+// stackEffect returns the number of operand stack entries that the instruction at pc
+// pops and pushes, counting longs and doubles as single entries, as Jacobin does.
+// Returns false for instructions whose effect isn't tracked.
+func (a *npeAnalyzer) stackEffect(pc int) (pops, pushes int, ok bool) {
+	code := a.f.Meth
+	op := code[pc]
+	switch {
+	case op == opcodes.NOP || op == opcodes.IINC || op == opcodes.GOTO || op == opcodes.GOTO_W:
+		return 0, 0, true
+	case op <= opcodes.ALOAD_3: // constants and loads
+		return 0, 1, true
+	case op <= opcodes.SALOAD: // array loads
+		return 2, 1, true
+	case op <= opcodes.ASTORE_3: // stores
+		return 1, 0, true
+	case op <= opcodes.SASTORE: // array stores
+		return 3, 0, true
+	case op == opcodes.POP:
+		return 1, 0, true
+	case op >= opcodes.IADD && op <= opcodes.LXOR:
+		if op >= opcodes.INEG && op <= opcodes.DNEG {
+			return 1, 1, true
+		}
+		return 2, 1, true
+	case op >= opcodes.I2L && op <= opcodes.I2S: // conversions
+		return 1, 1, true
+	case op >= opcodes.LCMP && op <= opcodes.DCMPG:
+		return 2, 1, true
+	case op >= opcodes.IFEQ && op <= opcodes.IFLE, op == opcodes.IFNULL, op == opcodes.IFNONNULL,
+		op == opcodes.TABLESWITCH, op == opcodes.LOOKUPSWITCH,
+		op == opcodes.PUTSTATIC, op == opcodes.MONITORENTER, op == opcodes.MONITOREXIT:
+		return 1, 0, true
+	case op >= opcodes.IF_ICMPEQ && op <= opcodes.IF_ACMPNE:
+		return 2, 0, true
+	case op == opcodes.GETSTATIC || op == opcodes.NEW:
+		return 0, 1, true
+	case op == opcodes.GETFIELD || op == opcodes.NEWARRAY || op == opcodes.ANEWARRAY ||
+		op == opcodes.ARRAYLENGTH || op == opcodes.CHECKCAST || op == opcodes.INSTANCEOF:
+		return 1, 1, true
+	case op == opcodes.PUTFIELD:
+		return 2, 0, true
+	case op >= opcodes.INVOKEVIRTUAL && op <= opcodes.INVOKEINTERFACE:
+		_, _, methType, ok := methodRef(a.cp, code, pc)
+		if !ok {
+			return 0, 0, false
+		}
+		pops = len(parseParamTypes(methType))
+		if op != opcodes.INVOKESTATIC {
+			pops += 1 // the object whose method is invoked
+		}
+		if !strings.HasSuffix(methType, ")V") {
+			pushes = 1
+		}
+		return pops, pushes, true
+	case op == opcodes.MULTIANEWARRAY:
+		return int(code[pc+3]), 1, true
+	}
+	return 0, 0, false
+}
+
+// fieldRef returns the class and name of the field referred to by the instruction at pc
+func fieldRef(cp *classloader.CPool, code []byte, pc int) (string, string, bool) {
+	if cp == nil || pc+2 >= len(code) {
+		return "", "", false
+	}
+	slot := int(binary.BigEndian.Uint16(code[pc+1:]))
+	className, fieldName, _, err := classloader.GetFieldInfoFromCPfieldref(cp, slot)
+	return className, fieldName, err == nil
+}
+
+// fieldName returns the name of the field referred to by the instruction at pc
+func fieldName(cp *classloader.CPool, code []byte, pc int) string {
+	if _, name, ok := fieldRef(cp, code, pc); ok {
+		return name
+	}
+	return "<unknown>"
+}
+
+// methodRef returns the class, name, and descriptor of the method referred to by
+// the invoke instruction at pc
+func methodRef(cp *classloader.CPool, code []byte, pc int) (string, string, string, bool) {
+	if cp == nil || pc+2 >= len(code) {
+		return "", "", "", false
+	}
+	slot := int(binary.BigEndian.Uint16(code[pc+1:]))
+	if slot >= len(cp.CpIndex) {
+		return "", "", "", false
+	}
+	entry := cp.CpIndex[slot]
+	switch entry.Type {
+	case classloader.MethodRef:
+		if int(entry.Slot) >= len(cp.ResolvedMethodRefs) {
+			return "", "", "", false
+		}
+		meth := &cp.ResolvedMethodRefs[entry.Slot]
+		return *stringPool.GetStringPointer(meth.ClassIndex), *stringPool.GetStringPointer(meth.NameIndex),
+			*stringPool.GetStringPointer(meth.TypeIndex), true
+	case classloader.Interface:
+		className, methName, methType, _, err := classloader.GetIfaceMethInfoFromCPref(cp, slot)
+		return className, methName, methType, err == nil
+	}
+	return "", "", "", false
+}
+
+// formatMethod formats a method as HotSpot does in NullPointerException messages,
+// e.g., java.util.List.add(int, Object)
+func formatMethod(className, methName, methType string) string {
+	return fmt.Sprintf("%s.%s(%s)", externalClassName(className), methName,
+		strings.Join(parseParamTypes(methType), ", "))
+}
+
+// converts a class name in internal format (java/lang/String) to the format HotSpot
+// uses in NullPointerException messages, in which String and Object are abbreviated
+func externalClassName(className string) string {
+	switch className {
+	case "java/lang/String":
+		return "String"
+	case "java/lang/Object":
+		return "Object"
+	}
+	return strings.ReplaceAll(className, "/", ".")
+}
+
+// parseParamTypes returns the Java names of the parameter types in a method
+// descriptor, such as int, String, and long[]
+func parseParamTypes(methType string) []string {
+	var params []string
+	end := strings.IndexByte(methType, ')')
+	if !strings.HasPrefix(methType, "(") || end < 0 {
+		return params
+	}
+	desc := methType[1:end]
+	for i := 0; i < len(desc); i++ {
+		dims := 0
+		for i < len(desc) && desc[i] == '[' {
+			dims++
+			i++
+		}
+		if i >= len(desc) {
+			break
+		}
+		var name string
+		switch desc[i] {
+		case 'B':
+			name = "byte"
+		case 'C':
+			name = "char"
+		case 'D':
+			name = "double"
+		case 'F':
+			name = "float"
+		case 'I':
+			name = "int"
+		case 'J':
+			name = "long"
+		case 'S':
+			name = "short"
+		case 'Z':
+			name = "boolean"
+		case 'L':
+			semicolon := strings.IndexByte(desc[i:], ';')
+			if semicolon < 0 {
+				return params
+			}
+			name = externalClassName(desc[i+1 : i+semicolon])
+			i += semicolon
+		}
+		params = append(params, name+strings.Repeat("[]", dims))
+	}
+	return params
+}
@@ -0,0 +1,171 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package exceptions
+
+import (
+	"io"
+	"jacobin/src/classloader"
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/opcodes"
+	"jacobin/src/stringPool"
+	"os"
+	"strings"
+	"testing"
+)
+
+// builds a constant pool with these entries:
+//
+//	1: Field pkg/Node.next
+//	2: Method pkg/Foo.name()Ljava/lang/String;
+//	3: Method java/lang/String.length()I
+//	4: Field pkg/Node.table
+//	5: Method java/util/List.add(ILjava/lang/Object;)V
+func npeTestCP() *classloader.CPool {
+	cp := classloader.CPool{}
+	cp.CpIndex = make([]classloader.CpEntry, 6)
+	addField := func(cpIndex int, className, fieldName string) {
+		cp.CpIndex[cpIndex] = classloader.CpEntry{Type: classloader.FieldRef, Slot: uint16(len(cp.FieldRefs))}
+		cp.FieldRefs = append(cp.FieldRefs, classloader.ResolvedFieldEntry{ClName: className, FldName: fieldName})
+	}
+	addMethod := func(cpIndex int, className, methName, methType string) {
+		cp.CpIndex[cpIndex] = classloader.CpEntry{Type: classloader.MethodRef, Slot: uint16(len(cp.ResolvedMethodRefs))}
+		cp.ResolvedMethodRefs = append(cp.ResolvedMethodRefs, classloader.ResolvedMethodRefEntry{
+			ClassIndex: stringPool.GetStringIndex(&className),
+			NameIndex:  stringPool.GetStringIndex(&methName),
+			TypeIndex:  stringPool.GetStringIndex(&methType),
+		})
+	}
+	addField(1, "pkg/Node", "next")
+	addMethod(2, "pkg/Foo", "name", "()Ljava/lang/String;")
+	addMethod(3, "java/lang/String", "length", "()I")
+	addField(4, "pkg/Node", "table")
+	addMethod(5, "java/util/List", "add", "(ILjava/lang/Object;)V")
+	return &cp
+}
+
+// returns a frame executing the given code as method pkg/Node.m with the given type
+func npeTestFrame(methType string, static bool, code []byte) *frames.Frame {
+	globals.InitGlobals("test")
+	f := frames.CreateFrame(4)
+	f.ClName, f.MethName, f.MethType = "pkg/Node", "m", methType
+	f.Meth = code
+	f.CP = npeTestCP()
+
+	flags := 0
+	if static {
+		flags = classloader.AccStatic
+	}
+	classloader.MTable[f.ClName+"."+f.MethName+f.MethType] = classloader.MTentry{
+		MType: 'J', Meth: classloader.JmEntry{AccessFlags: flags, Code: code, Cp: f.CP.(*classloader.CPool)}}
+	return f
+}
+
+func TestHelpfulNPEMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		methType string
+		static   bool
+		code     []byte
+		pc       int
+		expected string
+	}{
+		{"field of a local", "()V", true,
+			[]byte{opcodes.ALOAD_1, opcodes.GETFIELD, 0, 1}, 1,
+			`Cannot read field "next" because "<local1>" is null`},
+		{"field of a field", "()V", false,
+			[]byte{opcodes.ALOAD_0, opcodes.GETFIELD, 0, 1, opcodes.GETFIELD, 0, 1}, 4,
+			`Cannot read field "next" because "this.next" is null`},
+		{"method on a return value", "()V", true,
+			[]byte{opcodes.INVOKESTATIC, 0, 2, opcodes.INVOKEVIRTUAL, 0, 3}, 3,
+			`Cannot invoke "String.length()" because the return value of "pkg.Foo.name()" is null`},
+		{"method with arguments on a parameter", "(JLjava/util/List;)V", false,
+			[]byte{opcodes.ALOAD_3, opcodes.ICONST_2, opcodes.ALOAD_0, opcodes.INVOKEINTERFACE, 0, 5, 3, 0}, 3,
+			`Cannot invoke "java.util.List.add(int, Object)" because "<parameter2>" is null`},
+		{"array element of a static", "()V", true,
+			[]byte{opcodes.GETSTATIC, 0, 4, opcodes.ICONST_3, opcodes.AALOAD, opcodes.ARRAYLENGTH}, 5,
+			`Cannot read the array length because "pkg.Node.table[3]" is null`},
+		{"store to a new object's field", "()V", true,
+			[]byte{opcodes.ALOAD, 5, opcodes.DUP, opcodes.ICONST_1, opcodes.PUTFIELD, 0, 1}, 4,
+			`Cannot assign field "next" because "<local5>" is null`},
+		{"store to an array through a cast", "()V", true,
+			[]byte{opcodes.ACONST_NULL, opcodes.CHECKCAST, 0, 1, opcodes.ICONST_0, opcodes.ICONST_0, opcodes.IASTORE}, 6,
+			`Cannot store to int array because "null" is null`},
+		{"value that might come from a jump", "()V", true,
+			[]byte{opcodes.ALOAD_1, opcodes.IFNONNULL, 0, 4, opcodes.ALOAD_2, opcodes.ATHROW}, 5,
+			`Cannot throw exception`},
+		{"not a dereference", "()V", true,
+			[]byte{opcodes.ICONST_0, opcodes.IRETURN}, 1, ``},
+	}
+
+	for _, test := range tests {
+		f := npeTestFrame(test.methType, test.static, test.code)
+		if msg := HelpfulNPEMessage(f, test.pc); msg != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.name, test.expected, msg)
+		}
+	}
+}
+
+func TestHelpfulNPEMessageWithLocalVariableTable(t *testing.T) {
+	code := []byte{opcodes.ALOAD_1, opcodes.GETFIELD, 0, 1, opcodes.ALOAD_1, opcodes.GETFIELD, 0, 1}
+	f := npeTestFrame("()V", true, code)
+
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea() // so pkg/Node isn't seen by other tests
+	classloader.MethAreaInsert("pkg/Node", &classloader.Klass{Status: 'F', Loader: "testloader",
+		Data: &classloader.ClData{Name: "pkg/Node", MethodTable: map[string]*classloader.Method{
+			"m()V": {CodeAttr: classloader.CodeAttrib{LocalVariables: []classloader.LocalVariable{
+				{StartPc: 0, Length: 4, Name: "node", Desc: "Lpkg/Node;", Slot: 1}}}},
+		}}})
+
+	if msg := HelpfulNPEMessage(f, 1); msg != `Cannot read field "next" because "node" is null` {
+		t.Errorf("Expected the local's name from the table, got %q", msg)
+	}
+	// past the end of the variable's range, the slot has no name in the table
+	if msg := HelpfulNPEMessage(f, 5); msg != `Cannot read field "next" because "<local1>" is null` {
+		t.Errorf("Expected HotSpot's name for the local, got %q", msg)
+	}
+}
+
+func TestThrowNPE(t *testing.T) {
+	f := npeTestFrame("()V", true, []byte{opcodes.ALOAD_1, opcodes.GETFIELD, 0, 1})
+	f.PC = 1
+
+	normalStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+	ret := ThrowNPE(f, "GETFIELD: Null object reference")
+	_ = w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stderr = normalStderr
+
+	if ret != ERROR_OCCURRED {
+		t.Errorf("Expected ERROR_OCCURRED in test mode, got: %d", ret)
+	}
+	msg := string(out)
+	if !strings.Contains(msg, "java.lang.NullPointerException") ||
+		!strings.Contains(msg, `GETFIELD: Null object reference: Cannot read field "next" because "<local1>" is null`) {
+		t.Errorf("Got unexpected output: %s", msg)
+	}
+}
+
+func TestNPEMessage(t *testing.T) {
+	if msg := NPEMessage(`invoke "Runnable.run()"`, "task"); msg != `Cannot invoke "Runnable.run()" because "task" is null` {
+		t.Errorf("Unexpected message: %s", msg)
+	}
+	if msg := NPEMessage("enter synchronized block", ""); msg != "Cannot enter synchronized block" {
+		t.Errorf("Unexpected message: %s", msg)
+	}
+}
+
+func TestParseParamTypes(t *testing.T) {
+	params := parseParamTypes("([[IJLjava/lang/String;Ljava/util/Map;[Ljava/lang/Object;Z)V")
+	expected := []string{"int[][]", "long", "String", "java.util.Map", "Object[]", "boolean"}
+	if strings.Join(params, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, params)
+	}
+}
@@ -7,6 +7,7 @@
 package exceptions
 
 import (
+	"container/list"
 	"fmt"
 	"jacobin/src/excNames"
 	"jacobin/src/frames"
@@ -15,6 +16,7 @@ import (
 	"jacobin/src/shutdown"
 	"jacobin/src/thread"
 	"jacobin/src/trace"
+	"jacobin/src/types"
 	"jacobin/src/util"
 	"os"
 	"runtime/debug"
@@ -40,9 +42,28 @@ func ThrowExNil(which int, msg string) bool {
 // errors thrown by Jacobin, rather than by the application. (The latter
 // would generally use the ATHROW bytecode.)
 //
-// Important: if you change the name of this function, you need to update
-// exceptions.ShowGoStackTrace(), which explicitly tests for this function name.
+// Important: if you change the name of this function or of throwEx(), you need
+// to update exceptions.ShowGoStackTrace(), which explicitly tests for these names.
 func ThrowEx(which int, msg string, f *frames.Frame) bool {
+	return throwEx(which, msg, nil, f, false)
+}
+
+// ThrowExWithCause throws an exception just as ThrowEx does, with the Throwable
+// cause as the exception's cause, which getCause() returns
+func ThrowExWithCause(which int, msg string, cause *object.Object, f *frames.Frame) bool {
+	return throwEx(which, msg, cause, f, false)
+}
+
+// ThrowExEndingThread throws an exception just as ThrowEx does, except that if
+// the exception is not caught, it ends only the thread it was thrown on: the
+// stack trace is printed and the thread's frames are removed, so that the
+// thread's run loop finds an empty frame stack and exits. Other threads, and
+// the JVM, keep running.
+func ThrowExEndingThread(which int, msg string, f *frames.Frame) bool {
+	return throwEx(which, msg, nil, f, true)
+}
+
+func throwEx(which int, msg string, cause *object.Object, f *frames.Frame, endThread bool) bool {
 	if globals.TraceVerbose {
 		infoMsg := fmt.Sprintf("[ThrowEx] %s, msg: %s", excNames.JVMexceptionNames[which], msg)
 		trace.Trace(infoMsg)
@@ -109,6 +130,10 @@ func ThrowEx(which int, msg string, f *frames.Frame) bool {
 			trace.Trace(infoMsg)
 		}
 
+		// create the exception object while the frames above the catch frame
+		// are still on the stack, so that they appear in its stack trace
+		objRef, _ := makeThrowable(exceptionCPname, msg, cause, fs)
+
 		th = glob.Threads[f.Thread].(*thread.ExecThread)
 		fs = th.Stack
 		for fs.Len() > 0 { // remove the frames we examined that did not have the catch logic
@@ -120,7 +145,6 @@ func ThrowEx(which int, msg string, f *frames.Frame) bool {
 			}
 		}
 
-		objRef, _ := glob.FuncInstantiateClass(exceptionCPname, fs)
 		catchFrame.TOS = 0
 		catchFrame.OpStack[0] = objRef // push the objRef
 		catchFrame.PC = catchPC
@@ -135,18 +159,35 @@ func ThrowEx(which int, msg string, f *frames.Frame) bool {
 
 	// ---- if exception is not caught ----
 
-	throwObject, err := glob.FuncInstantiateClass(exceptionCPname, fs)
+	throwObj, err := makeThrowable(exceptionCPname, msg, cause, fs)
+
+	// an exception that escapes a static initializer or an upcall ends it, but not the program
+	if AbortToBoundary(fs, exceptionNameForUser+": "+msg, throwObj) {
+		return Caught
+	}
+
+	if err != nil && endThread {
+		excInfo := fmt.Sprintf("%s: FQN: %s, %s", exceptionNameForUser, frames.FormatFQN(f), msg)
+		_, _ = fmt.Fprintln(os.Stderr, excInfo)
+		clearFrameStack(fs)
+		return NotCaught
+	}
 	if err != nil {
 		fmt.Printf("InstantiateClass failed, FQN: %s, %s", frames.FormatFQN(f), err.Error())
-		// if throwObject != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "throwObject: %v\n", throwObject)
+		_, _ = fmt.Fprintf(os.Stderr, "throwObject: %v\n", throwObj)
 		_ = shutdown.Exit(shutdown.JVM_EXCEPTION)
-		// }
+		return NotCaught // only applies to tests
 	}
 
-	throwObj := throwObject.(*object.Object)
-	params := []any{fs, throwObj}
-	glob.FuncFillInStackTrace(params)
+	// the thread's uncaught exception handler, if it has one, deals with the exception
+	if glob.FuncUncaughtHandler != nil && glob.FuncUncaughtHandler(fs, throwObj) {
+		if endThread {
+			clearFrameStack(fs)
+			return NotCaught
+		}
+		_ = shutdown.Exit(shutdown.UNCAUGHT_EXCEPTION)
+		return NotCaught // only applies to tests
+	}
 
 	excInfo := fmt.Sprintf("%s: FQN: %s, %s", exceptionNameForUser, frames.FormatFQN(f), msg)
 	_, _ = fmt.Fprintln(os.Stderr, excInfo)
@@ -184,8 +225,42 @@ func ThrowEx(which int, msg string, f *frames.Frame) bool {
 		ShowGoStackTrace("")
 	}
 
-	_ = shutdown.Exit(shutdown.JVM_EXCEPTION) // in test mode, this call returns
-	return NotCaught                          // only applies to tests
+	if endThread {
+		clearFrameStack(fs)
+		return NotCaught
+	}
+
+	_ = shutdown.Exit(shutdown.UNCAUGHT_EXCEPTION) // in test mode, this call returns
+	return NotCaught                               // only applies to tests
+}
+
+// makeThrowable creates an instance of the named exception class, as Java code would
+// with new: its detail message is set to msg (so getMessage() returns it), its cause
+// to cause, if that isn't nil, and its stack trace is filled in from the frame stack fs. Exceptions thrown by Jacobin,
+// including those returned by gfunctions, are thus ordinary Throwables that catch
+// blocks can inspect and rethrow.
+func makeThrowable(exceptionCPname, msg string, cause *object.Object, fs *list.List) (*object.Object, error) {
+	glob := globals.GetGlobalRef()
+	throwObject, err := glob.FuncInstantiateClass(exceptionCPname, fs)
+	if err != nil {
+		return nil, err
+	}
+	throwObj, ok := throwObject.(*object.Object)
+	if !ok || object.IsNull(throwObj) {
+		return nil, fmt.Errorf("makeThrowable: could not instantiate %s", exceptionCPname)
+	}
+
+	if msg != "" {
+		throwObj.FieldTable["detailMessage"] = object.Field{
+			Ftype: types.StringClassRef, Fvalue: object.StringObjectFromGoString(msg)}
+	}
+	if cause != nil {
+		throwObj.FieldTable["cause"] = object.Field{Ftype: "Ljava/lang/Throwable;", Fvalue: cause}
+	}
+	if glob.FuncFillInStackTrace != nil {
+		glob.FuncFillInStackTrace([]any{fs, throwObj})
+	}
+	return throwObj, nil
 }
 
 /* This code is not called. However, before deleting it, we want to make sure it won't be
@@ -303,3 +378,11 @@ func MinimalAbort(whichException int, msg string) {
 	ShowGoStackTrace(nil)
 	_ = shutdown.Exit(shutdown.APP_EXCEPTION)
 }
+
+// clearFrameStack removes all the frames from a thread's frame stack, which ends
+// the thread once control returns to its run loop.
+func clearFrameStack(fs *list.List) {
+	for fs.Len() > 0 {
+		fs.Remove(fs.Front())
+	}
+}
@@ -0,0 +1,138 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package frames
+
+import "jacobin/src/object"
+
+// Frame pooling. Every method invocation needs a frame, an operand stack, and
+// an array of locals, so call-heavy code (recursion especially) allocates a great
+// deal of short-lived memory. To reduce the load on the garbage collector, each
+// execution thread has a FramePool, a free list of frames that have been popped
+// and can be reused along with their slices. The frames are kept in size classes
+// by the capacity of their operand stacks, so that a frame taken from the pool
+// never needs its operand stack reallocated. Locals, and the copy of the method's
+// bytecode, are grown in place as needed.
+//
+// A pool belongs to a single thread, which is the only one that pushes and pops
+// its frames, so the pool needs no locking. Frames created by a pooled frame (via
+// Frame.Pool) share its pool. Only frames that came from a pool are returned to it,
+// so frames created by CreateFrame() are never reused behind the back of code that
+// holds onto them.
+
+const (
+	minPooledOpStack   = 4  // capacity of the operand stacks in the smallest size class
+	frameSizeClasses   = 8  // so, the largest pooled operand stack holds 4 << 7 = 512 items
+	maxFramesPerClass  = 64 // the most frames kept in each size class
+	oversizeFrameClass = -1 // the size class of frames too large to pool
+)
+
+// FramePool holds the frames of a thread that are available for reuse
+type FramePool struct {
+	free    [frameSizeClasses][]*Frame
+	Nursery *object.Nursery // the thread's reusable objects, if -XX:+UseAllocationNursery. See object/nursery.go
+}
+
+// NewFramePool creates an empty frame pool, for use by a single thread
+func NewFramePool() *FramePool {
+	return &FramePool{}
+}
+
+// returns the size class for an operand stack of the given size
+func frameSizeClass(opStackSize int) int {
+	capacity := minPooledOpStack
+	for class := 0; class < frameSizeClasses; class++ {
+		if opStackSize <= capacity {
+			return class
+		}
+		capacity <<= 1
+	}
+	return oversizeFrameClass
+}
+
+// CreateFrame returns a frame with an operand stack of the passed-in size, in the
+// same initial state as one from frames.CreateFrame(), reusing a pooled frame if
+// one is available. The frame belongs to the pool, so the frames it creates in turn
+// use the pool. If p is nil, a new, unpooled frame is created.
+func (p *FramePool) CreateFrame(opStackSize int) *Frame {
+	if p == nil {
+		return CreateFrame(opStackSize)
+	}
+	if opStackSize < 0 {
+		opStackSize = 0
+	}
+
+	class := frameSizeClass(opStackSize)
+	if class == oversizeFrameClass {
+		fram := CreateFrame(opStackSize)
+		fram.Pool = p
+		return fram
+	}
+
+	var fram *Frame
+	if n := len(p.free[class]); n > 0 {
+		fram = p.free[class][n-1]
+		p.free[class][n-1] = nil
+		p.free[class] = p.free[class][:n-1]
+	} else {
+		fram = &Frame{
+			OpStack: make([]interface{}, 0, minPooledOpStack<<class),
+			Pool:    p,
+			pooled:  true,
+		}
+	}
+
+	fram.OpStack = fram.OpStack[:opStackSize]
+	for j := range fram.OpStack {
+		fram.OpStack[j] = 0
+	}
+	fram.TOS = -1
+	fram.PC = 0
+	fram.ExceptionPC = -1
+	return fram
+}
+
+// ReleaseFrame returns a frame that has been popped off its frame stack to its
+// pool for reuse. Frames that did not come from a pool are left to the garbage
+// collector. The caller must not use the frame afterwards.
+func ReleaseFrame(f *Frame) {
+	if f == nil || !f.pooled {
+		return
+	}
+	p := f.Pool
+	// the objects the method took from the nursery never left it, so they can be reused
+	for slot, obj := range f.NurseryObjs {
+		p.Nursery.Release(obj)
+		delete(f.NurseryObjs, slot)
+	}
+
+	class := frameSizeClass(cap(f.OpStack))
+	if len(p.free[class]) >= maxFramesPerClass {
+		return
+	}
+
+	// clear the references the frame holds, so it doesn't keep objects alive
+	clear(f.OpStack[:cap(f.OpStack)])
+	clear(f.Locals)
+	*f = Frame{
+		OpStack:     f.OpStack[:0],
+		Locals:      f.Locals[:0],
+		Meth:        f.Meth[:0],
+		Pool:        p,
+		pooled:      true,
+		NurseryObjs: f.NurseryObjs,
+	}
+	p.free[class] = append(p.free[class], f)
+}
+
+// Len returns the number of frames in the pool, for diagnostics and testing
+func (p *FramePool) Len() int {
+	n := 0
+	for class := range p.free {
+		n += len(p.free[class])
+	}
+	return n
+}
@@ -0,0 +1,114 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package frames
+
+import (
+	"jacobin/src/object"
+	"testing"
+)
+
+func TestFrameSizeClass(t *testing.T) {
+	tests := []struct {
+		size, class int
+	}{{0, 0}, {4, 0}, {5, 1}, {8, 1}, {9, 2}, {512, 7}, {513, oversizeFrameClass}}
+	for _, test := range tests {
+		if class := frameSizeClass(test.size); class != test.class {
+			t.Errorf("Size %d: expected class %d, got %d", test.size, test.class, class)
+		}
+	}
+}
+
+func TestFramePoolReusesFrames(t *testing.T) {
+	pool := NewFramePool()
+	f := pool.CreateFrame(6)
+	if len(f.OpStack) != 6 || f.TOS != -1 || f.ExceptionPC != -1 || f.Pool != pool {
+		t.Fatalf("Unexpected initial state of pooled frame: %+v", f)
+	}
+
+	f.OpStack[0] = "a reference"
+	f.Locals = append(f.Locals, "another reference", int64(3))
+	f.TOS = 0
+	f.PC = 12
+	f.MethName = "fib"
+	ReleaseFrame(f)
+	if pool.Len() != 1 {
+		t.Fatalf("Expected 1 frame in the pool, got %d", pool.Len())
+	}
+
+	// a frame of the same size class is reused, in its initial state
+	g := pool.CreateFrame(7)
+	if g != f {
+		t.Error("Expected the released frame to be reused")
+	}
+	if len(g.OpStack) != 7 || g.OpStack[0] != 0 || g.TOS != -1 || g.PC != 0 ||
+		g.MethName != "" || len(g.Locals) != 0 {
+		t.Errorf("Expected the reused frame to be reset, got: %+v", g)
+	}
+	if cap(g.Locals) < 2 {
+		t.Error("Expected the reused frame to keep the capacity of its locals")
+	}
+	if pool.Len() != 0 {
+		t.Errorf("Expected the pool to be empty, got %d frames", pool.Len())
+	}
+
+	// a frame of a different size class is not
+	ReleaseFrame(g)
+	if h := pool.CreateFrame(20); h == g {
+		t.Error("Expected a frame of a different size class not to be reused")
+	}
+}
+
+func TestReleaseUnpooledFrame(t *testing.T) {
+	pool := NewFramePool()
+	f := CreateFrame(4)
+	f.Pool = pool
+	f.MethName = "main"
+	ReleaseFrame(f)
+	if pool.Len() != 0 || f.MethName != "main" {
+		t.Error("Expected a frame not created by the pool to be left alone")
+	}
+
+	big := pool.CreateFrame(1000)
+	ReleaseFrame(big)
+	if pool.Len() != 0 {
+		t.Error("Expected an oversize frame not to be pooled")
+	}
+
+	var nilPool *FramePool
+	if f = nilPool.CreateFrame(3); f == nil || len(f.OpStack) != 3 || f.Pool != nil {
+		t.Error("Expected a nil pool to create an unpooled frame")
+	}
+}
+
+func TestFramePoolIsBounded(t *testing.T) {
+	pool := NewFramePool()
+	var fs []*Frame
+	for i := 0; i < maxFramesPerClass+10; i++ {
+		fs = append(fs, pool.CreateFrame(2))
+	}
+	for _, f := range fs {
+		ReleaseFrame(f)
+	}
+	if pool.Len() != maxFramesPerClass {
+		t.Errorf("Expected the pool to hold %d frames, got %d", maxFramesPerClass, pool.Len())
+	}
+}
+
+func TestReleaseFrameReturnsNurseryObjects(t *testing.T) {
+	pool := NewFramePool()
+	pool.Nursery = object.NewNursery()
+	f := pool.CreateFrame(4)
+	f.NurseryObjs = map[int]*object.Object{2: object.MakeEmptyObject(), 3: object.MakeEmptyObject()}
+
+	ReleaseFrame(f)
+	if pool.Nursery.Len() != 2 {
+		t.Errorf("Expected the frame's 2 objects in the nursery, got %d", pool.Nursery.Len())
+	}
+	if g := pool.CreateFrame(4); len(g.NurseryObjs) != 0 {
+		t.Errorf("Expected the reused frame to have no nursery objects, got %d", len(g.NurseryObjs))
+	}
+}
@@ -8,7 +8,10 @@ package frames
 
 import (
 	"container/list"
+	"errors"
 	"fmt"
+	"jacobin/src/globals"
+	"jacobin/src/object"
 	"strings"
 	"unsafe"
 )
@@ -20,6 +23,12 @@ type StackValue interface {
 
 var debugging bool = false
 
+// UpcallMethName is the method name of the placeholder frame that Go code pushes
+// when it calls a Java method (see jvm.InvokeJavaMethod). The frame has no
+// bytecode; it receives the method's arguments and return value, and it stops
+// the search for exception handlers just as a <clinit> frame does.
+const UpcallMethName = "<upcall>"
+
 type Number interface {
 	int32 | int64 | float64
 }
@@ -51,6 +60,24 @@ type Frame struct {
 	Ftype        byte          // type of method in frame: 'J' = java, 'G' = Golang, 'N' = native
 	ExceptionPC  int           // program counter at the moment the PC threw an exception
 	WideInEffect bool          // WideInEffect indicates if the wide instruction is in effect in the current frame
+	Uncaught     string        // in a <clinit> or upcall frame, the exception that escaped the code it ran, if any
+	Pool         *FramePool    // the pool of the thread's reusable frames, if it has one. See framePool.go
+	pooled       bool          // true if the frame came from Pool and can be returned to it
+
+	// the Throwable of the exception in Uncaught, if it was created. See exceptions.AbortToBoundary()
+	UncaughtObj *object.Object
+
+	// the objects from the thread's nursery, by the local they're stored in. See jvm/nursery.go
+	NurseryObjs map[int]*object.Object
+
+	// the method compiled by the JIT, if it has been: the function that executes the
+	// instruction at each PC, with its operands bound. See jvm/jit.go
+	Compiled []func(*Frame, int64) int
+
+	// the JIT's record of the method, which counts its backward branches so that the
+	// frame can switch to the compiled code. Holds a *jvm.jitMethod; due to circularity
+	// it must be done this way
+	JitMethod interface{}
 }
 
 // CreateFrameStack creates a stack of frames. Implemented as a list in which
@@ -82,8 +109,16 @@ func CreateFrame(opStackSize int) *Frame {
 	return &fram
 }
 
-// PushFrame pushes a frame. This simply adds a frame to the head of the list.
+// ErrStackOverflow is returned by PushFrame when the frame stack already holds the
+// maximum number of frames (see -Xss). The caller should throw a StackOverflowError.
+var ErrStackOverflow = errors.New("frame stack is at its maximum depth")
+
+// PushFrame pushes a frame. This simply adds a frame to the head of the list,
+// unless the list is already at its maximum depth.
 func PushFrame(fs *list.List, f *Frame) error {
+	if maxDepth := globals.GetGlobalRef().MaxFrameDepth; maxDepth > 0 && fs.Len() >= maxDepth {
+		return ErrStackOverflow
+	}
 	if debugging {
 		fmt.Printf("DEBUG PushFrame %s ClName=%s, MethName=%s TOS=%d, PC=%d\n", ftag(f), f.ClName, f.MethName, f.TOS, f.PC)
 	}
@@ -6,7 +6,11 @@
 
 package frames
 
-import "testing"
+import (
+	"errors"
+	"jacobin/src/globals"
+	"testing"
+)
 
 func TestNewFrame(t *testing.T) {
 	f := CreateFrame(6)
@@ -52,3 +56,26 @@ func TestFramePeek(t *testing.T) {
 		t.Errorf("Peeked at prior frame. Expected size of opstack to be 1, got: %d", len(peek.OpStack))
 	}
 }
+
+func TestPushFrameAtMaximumDepth(t *testing.T) {
+	globals.InitGlobals("test")
+	globals.GetGlobalRef().MaxFrameDepth = 3
+
+	fs := CreateFrameStack()
+	for i := 0; i < 3; i++ {
+		if err := PushFrame(fs, CreateFrame(1)); err != nil {
+			t.Fatalf("Unexpected error pushing frame %d: %v", i, err)
+		}
+	}
+	if err := PushFrame(fs, CreateFrame(1)); !errors.Is(err, ErrStackOverflow) {
+		t.Errorf("Expected ErrStackOverflow, got: %v", err)
+	}
+	if fs.Len() != 3 {
+		t.Errorf("Expected the frame stack to hold 3 frames, got %d", fs.Len())
+	}
+
+	globals.GetGlobalRef().MaxFrameDepth = 0 // no limit
+	if err := PushFrame(fs, CreateFrame(1)); err != nil {
+		t.Errorf("Expected no limit when MaxFrameDepth is 0, got: %v", err)
+	}
+}
@@ -7,7 +7,13 @@
 package gfunction
 
 import (
+	"fmt"
 	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/shutdown"
+	"jacobin/src/trace"
+	"reflect"
+	"sync"
 )
 
 func Load_Traps() {
@@ -18,12 +24,6 @@ func Load_Traps() {
 			GFunction:  trapClass,
 		}
 
-	MethodSignatures["java/io/BufferedWriter.<clinit>()V"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  trapClass,
-		}
-
 	MethodSignatures["java/io/ByteArrayOutputStream.<clinit>()V"] =
 		GMeth{
 			ParamSlots: 0,
@@ -108,12 +108,6 @@ func Load_Traps() {
 			GFunction:  trapClass,
 		}
 
-	MethodSignatures["java/nio/ByteBuffer.<clinit>()V"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  trapClass,
-		}
-
 	MethodSignatures["java/nio/charset/StandardCharsets.<clinit>()V"] =
 		GMeth{
 			ParamSlots: 0,
@@ -126,12 +120,6 @@ func Load_Traps() {
 			GFunction:  trapClass,
 		}
 
-	MethodSignatures["java/nio/channels/FileChannel.<clinit>()V"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  trapClass,
-		}
-
 	MethodSignatures["java/rmi/RMISecurityManager.<clinit>()V"] =
 		GMeth{
 			ParamSlots: 0,
@@ -158,6 +146,60 @@ func trapDeprecated([]interface{}) interface{} {
 	return getGErrBlk(excNames.UnsupportedOperationException, errMsg)
 }
 
+// IsTrapDeprecated reports whether the method with the given fully qualified name
+// (such as java/lang/String.getBytes(II[BI)V) is a deprecated method that Jacobin
+// traps with trapDeprecated(). Used by -Xlint:deprecation.
+func IsTrapDeprecated(methFQN string) bool {
+	gmeth, ok := MethodSignatures[methFQN]
+	return ok && gmeth.GFunction != nil &&
+		reflect.ValueOf(gmeth.GFunction).Pointer() == reflect.ValueOf(trapDeprecated).Pointer()
+}
+
+// IsTrapped reports whether the method with the given fully qualified name is a
+// gfunction that traps, i.e., one that Jacobin does not support. Used by --validate-only.
+func IsTrapped(methFQN string) bool {
+	gmeth, ok := MethodSignatures[methFQN]
+	return ok && isTrap(gmeth.GFunction)
+}
+
+// isTrap reports whether the gfunction is one of the generic traps above and below
+func isTrap(gfunc func([]interface{}) interface{}) bool {
+	if gfunc == nil {
+		return false
+	}
+	ptr := reflect.ValueOf(gfunc).Pointer()
+	for _, trap := range []func([]interface{}) interface{}{
+		trapClass, trapDeprecated, trapUndocumented, trapFunction, trapProtected} {
+		if ptr == reflect.ValueOf(trap).Pointer() {
+			return true
+		}
+	}
+	return false
+}
+
+// the trapped methods that have been reported under -XX:TrapPolicy=warn
+var trapsReported sync.Map
+
+// applyTrapPolicy carries out -XX:TrapPolicy when the trapped method methFQN is called.
+// Under the warn policy, the first call to each trapped method is logged, so that one
+// run reveals all the unsupported methods the program uses, not just the first. Under
+// the abort policy, the program ends. Returns true if the program is to continue, in
+// which case the trap's UnsupportedOperationException is thrown, so the program can
+// catch it and carry on.
+func applyTrapPolicy(methFQN string, errBlk *GErrBlk) bool {
+	switch globals.GetGlobalRef().TrapPolicy {
+	case globals.TrapWarn:
+		if _, reported := trapsReported.LoadOrStore(methFQN, true); !reported {
+			trace.Warning(fmt.Sprintf("TrapPolicy=warn: %s is not supported (%s)", methFQN, errBlk.ErrMsg))
+		}
+	case globals.TrapAbort:
+		trace.Error(fmt.Sprintf("TrapPolicy=abort: %s is not supported (%s)", methFQN, errBlk.ErrMsg))
+		shutdown.Exit(shutdown.APP_EXCEPTION)
+		return false // reached only in tests, as Exit() ends the program
+	}
+	return true
+}
+
 // Generic trap for deprecated classes and functions
 func trapUndocumented([]interface{}) interface{} {
 	errMsg := "TRAP: The requested class or function is undocumented and, therefore, not supported"
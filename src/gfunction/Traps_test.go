@@ -12,6 +12,8 @@ import (
     "testing"
 
     "jacobin/src/excNames"
+    "jacobin/src/globals"
+    "jacobin/src/trace"
 )
 
 func TestLoad_Traps_RegistersSomeMethods(t *testing.T) {
@@ -73,3 +75,50 @@ func TestTrapFunctions_ReturnUnsupported(t *testing.T) {
         t.Fatalf("trapProtected expected UnsupportedOperationException with TRAP: message, got %+v", blk)
     }
 }
+
+func TestIsTrapDeprecated(t *testing.T) {
+    saved := MethodSignatures
+    defer func() { MethodSignatures = saved }()
+    MethodSignatures = make(map[string]GMeth)
+
+    Load_Traps()
+
+    if !IsTrapDeprecated("java/rmi/RMISecurityManager.<init>()V") {
+        t.Error("expected RMISecurityManager.<init>()V to be a deprecated trap")
+    }
+    if IsTrapDeprecated("java/io/BufferedOutputStream.<clinit>()V") {
+        t.Error("expected BufferedOutputStream.<clinit>()V, a class trap, not to be a deprecated trap")
+    }
+    if IsTrapDeprecated("pkg/NoSuchClass.noSuchMethod()V") {
+        t.Error("expected a method with no gfunction not to be a deprecated trap")
+    }
+}
+
+func TestApplyTrapPolicy(t *testing.T) {
+    globals.InitGlobals("test")
+    trace.Init()
+
+    if !isTrap(trapFunction) || !isTrap(trapProtected) || isTrap(clinitGeneric) || isTrap(nil) {
+        t.Fatal("isTrap did not distinguish the trap functions from other gfunctions")
+    }
+
+    errBlk := trapFunction(nil).(*GErrBlk)
+    if !applyTrapPolicy("pkg/Trapped.throw()V", errBlk) {
+        t.Error("expected the throw policy to continue the program")
+    }
+
+    globals.GetGlobalRef().TrapPolicy = globals.TrapWarn
+    for i := 0; i < 2; i++ {
+        if !applyTrapPolicy("pkg/Trapped.warn()V", errBlk) {
+            t.Error("expected the warn policy to continue the program")
+        }
+    }
+    if _, reported := trapsReported.Load("pkg/Trapped.warn()V"); !reported {
+        t.Error("expected the warn policy to record the trapped method")
+    }
+
+    globals.GetGlobalRef().TrapPolicy = globals.TrapAbort
+    if applyTrapPolicy("pkg/Trapped.abort()V", errBlk) {
+        t.Error("expected the abort policy to end the program")
+    }
+}
@@ -0,0 +1,215 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+// Package args extracts the parameters passed to G functions. A G function
+// receives its parameters as a slice of interface{}; for an instance method,
+// params[0] is the object the method is invoked on and the method's arguments
+// follow it. Asserting the type of a parameter directly, as in
+// params[1].(*object.Object), panics the VM if the parameter is null or of an
+// unexpected type. The functions here instead return an *Error, which the G
+// function returns as a Java exception. A typical use:
+//
+//	str, err := args.GetGoString(params, 1)
+//	if err != nil {
+//		return getArgsGErrBlk("stringConcat", err)
+//	}
+package args
+
+import (
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/types"
+)
+
+// Error describes a G function parameter that is missing, null, or of the wrong
+// type. Its fields are those of gfunction.GErrBlk: the exception to throw (an
+// index into excNames) and its message.
+type Error struct {
+	ExceptionType int
+	ErrMsg        string
+}
+
+func (e *Error) Error() string {
+	return e.ErrMsg
+}
+
+func newError(exceptionType int, format string, a ...any) *Error {
+	return &Error{ExceptionType: exceptionType, ErrMsg: fmt.Sprintf(format, a...)}
+}
+
+// returns the parameter at the given index, or an error if there is none
+func get(params []any, index int) (any, error) {
+	if index < 0 || index >= len(params) {
+		return nil, newError(excNames.IllegalArgumentException,
+			"missing parameter %d (got %d parameters)", index, len(params))
+	}
+	return params[index], nil
+}
+
+// GetObjectOrNull returns the object at the given index, which may be null, in
+// which case it returns object.Null.
+func GetObjectOrNull(params []any, index int) (*object.Object, error) {
+	param, err := get(params, index)
+	if err != nil {
+		return nil, err
+	}
+	if object.IsNull(param) {
+		return object.Null, nil
+	}
+	obj, ok := param.(*object.Object)
+	if !ok {
+		return nil, newError(excNames.IllegalArgumentException,
+			"parameter %d: expected an object, got %T", index, param)
+	}
+	return obj, nil
+}
+
+// GetObject returns the object at the given index. A null object is an error
+// that throws a NullPointerException.
+func GetObject(params []any, index int) (*object.Object, error) {
+	obj, err := GetObjectOrNull(params, index)
+	if err != nil {
+		return nil, err
+	}
+	if obj == object.Null {
+		return nil, newError(excNames.NullPointerException, "parameter %d is null", index)
+	}
+	return obj, nil
+}
+
+// GetStringObject returns the String object at the given index, which must not be null
+func GetStringObject(params []any, index int) (*object.Object, error) {
+	obj, err := GetObject(params, index)
+	if err != nil {
+		return nil, err
+	}
+	if !object.IsStringObject(obj) {
+		return nil, newError(excNames.IllegalArgumentException,
+			"parameter %d: expected a String, got an object of class %s",
+			index, object.GoStringFromStringPoolIndex(obj.KlassName))
+	}
+	return obj, nil
+}
+
+// GetGoString returns the value of the String object at the given index as a Go string
+func GetGoString(params []any, index int) (string, error) {
+	obj, err := GetStringObject(params, index)
+	if err != nil {
+		return "", err
+	}
+	return object.GoStringFromStringObject(obj), nil
+}
+
+// GetInt64 returns the integral value at the given index. Java's int, long, short,
+// byte, char, and boolean are all passed to G functions as int64, but a few G
+// functions are called with other Go integer types, so these are accepted as well.
+func GetInt64(params []any, index int) (int64, error) {
+	param, err := get(params, index)
+	if err != nil {
+		return 0, err
+	}
+	switch value := param.(type) {
+	case int64:
+		return value, nil
+	case int:
+		return int64(value), nil
+	case int32:
+		return int64(value), nil
+	case types.JavaByte:
+		return int64(value), nil
+	case uint8:
+		return int64(value), nil
+	}
+	return 0, newError(excNames.IllegalArgumentException,
+		"parameter %d: expected an integer, got %T", index, param)
+}
+
+// GetBoolean returns the Java boolean at the given index as a Go bool
+func GetBoolean(params []any, index int) (bool, error) {
+	value, err := GetInt64(params, index)
+	if err != nil {
+		return false, err
+	}
+	return value != types.JavaBoolFalse, nil
+}
+
+// GetFloat64 returns the float or double at the given index
+func GetFloat64(params []any, index int) (float64, error) {
+	param, err := get(params, index)
+	if err != nil {
+		return 0, err
+	}
+	switch value := param.(type) {
+	case float64:
+		return value, nil
+	case float32:
+		return float64(value), nil
+	}
+	return 0, newError(excNames.IllegalArgumentException,
+		"parameter %d: expected a floating-point number, got %T", index, param)
+}
+
+// returns the value field of the non-null array object at the given index
+func getArrayValue(params []any, index int) (any, error) {
+	obj, err := GetObject(params, index)
+	if err != nil {
+		return nil, err
+	}
+	fld, ok := obj.FieldTable["value"]
+	if !ok {
+		return nil, newError(excNames.IllegalArgumentException,
+			"parameter %d: expected an array, got an object without a value field", index)
+	}
+	return fld.Fvalue, nil
+}
+
+// GetByteArray returns the contents of the byte array at the given index. The
+// bytes of some arrays (and of Strings) are held as Go bytes; for these, the
+// returned slice is a copy, so changes to it are not seen in the Java array.
+func GetByteArray(params []any, index int) ([]types.JavaByte, error) {
+	value, err := getArrayValue(params, index)
+	if err != nil {
+		return nil, err
+	}
+	switch bytes := value.(type) {
+	case []types.JavaByte:
+		return bytes, nil
+	case []byte:
+		return object.JavaByteArrayFromGoByteArray(bytes), nil
+	}
+	return nil, newError(excNames.IllegalArgumentException,
+		"parameter %d: expected a byte array, got %T", index, value)
+}
+
+// GetInt64Array returns the contents of the array of int64 at the given index:
+// a Java int, long, short, or char array.
+func GetInt64Array(params []any, index int) ([]int64, error) {
+	value, err := getArrayValue(params, index)
+	if err != nil {
+		return nil, err
+	}
+	ints, ok := value.([]int64)
+	if !ok {
+		return nil, newError(excNames.IllegalArgumentException,
+			"parameter %d: expected an int, long, short, or char array, got %T", index, value)
+	}
+	return ints, nil
+}
+
+// GetObjectArray returns the contents of the reference array at the given index
+func GetObjectArray(params []any, index int) ([]*object.Object, error) {
+	value, err := getArrayValue(params, index)
+	if err != nil {
+		return nil, err
+	}
+	objects, ok := value.([]*object.Object)
+	if !ok {
+		return nil, newError(excNames.IllegalArgumentException,
+			"parameter %d: expected an array of objects, got %T", index, value)
+	}
+	return objects, nil
+}
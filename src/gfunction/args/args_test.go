@@ -0,0 +1,113 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package args
+
+import (
+	"errors"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"strings"
+	"testing"
+)
+
+// checks that err is an *Error that throws the given exception
+func checkError(t *testing.T, err error, exceptionType int, msgFragment string) {
+	t.Helper()
+	var argErr *Error
+	if !errors.As(err, &argErr) {
+		t.Fatalf("Expected an *args.Error, got: %v", err)
+	}
+	if argErr.ExceptionType != exceptionType {
+		t.Errorf("Expected exception %s, got %s",
+			excNames.JVMexceptionNames[exceptionType], excNames.JVMexceptionNames[argErr.ExceptionType])
+	}
+	if !strings.Contains(argErr.ErrMsg, msgFragment) {
+		t.Errorf("Expected message containing %q, got %q", msgFragment, argErr.ErrMsg)
+	}
+}
+
+func TestGetObject(t *testing.T) {
+	globals.InitGlobals("test")
+	obj := object.MakeEmptyObject()
+	params := []any{obj, object.Null, nil, int64(3)}
+
+	if got, err := GetObject(params, 0); err != nil || got != obj {
+		t.Errorf("Expected the object, got %v (err: %v)", got, err)
+	}
+	_, err := GetObject(params, 1)
+	checkError(t, err, excNames.NullPointerException, "parameter 1 is null")
+	_, err = GetObject(params, 2)
+	checkError(t, err, excNames.NullPointerException, "parameter 2 is null")
+	_, err = GetObject(params, 3)
+	checkError(t, err, excNames.IllegalArgumentException, "expected an object, got int64")
+	_, err = GetObject(params, 4)
+	checkError(t, err, excNames.IllegalArgumentException, "missing parameter 4")
+
+	if got, err := GetObjectOrNull(params, 2); err != nil || got != object.Null {
+		t.Errorf("Expected null, got %v (err: %v)", got, err)
+	}
+}
+
+func TestGetGoString(t *testing.T) {
+	globals.InitGlobals("test")
+	params := []any{object.StringObjectFromGoString("hello"), object.MakeEmptyObject()}
+
+	if str, err := GetGoString(params, 0); err != nil || str != "hello" {
+		t.Errorf("Expected \"hello\", got %q (err: %v)", str, err)
+	}
+	_, err := GetGoString(params, 1)
+	checkError(t, err, excNames.IllegalArgumentException, "expected a String")
+}
+
+func TestGetPrimitives(t *testing.T) {
+	params := []any{int64(-7), types.JavaByte(-2), types.JavaBoolTrue, 2.5, "text"}
+
+	if n, err := GetInt64(params, 0); err != nil || n != -7 {
+		t.Errorf("Expected -7, got %d (err: %v)", n, err)
+	}
+	if n, err := GetInt64(params, 1); err != nil || n != -2 {
+		t.Errorf("Expected -2 from a byte, got %d (err: %v)", n, err)
+	}
+	if b, err := GetBoolean(params, 2); err != nil || !b {
+		t.Errorf("Expected true, got %v (err: %v)", b, err)
+	}
+	if f, err := GetFloat64(params, 3); err != nil || f != 2.5 {
+		t.Errorf("Expected 2.5, got %f (err: %v)", f, err)
+	}
+	_, err := GetInt64(params, 3)
+	checkError(t, err, excNames.IllegalArgumentException, "expected an integer, got float64")
+	_, err = GetFloat64(params, 4)
+	checkError(t, err, excNames.IllegalArgumentException, "expected a floating-point number, got string")
+}
+
+func TestGetArrays(t *testing.T) {
+	globals.InitGlobals("test")
+	goBytes := object.MakePrimitiveObject("[B", types.ByteArray, []byte("ab"))
+	javaBytes := object.MakePrimitiveObject("[B", types.ByteArray, []types.JavaByte{1, 2, 3})
+	chars := object.MakePrimitiveObject("[C", types.CharArray, []int64{'x', 'y'})
+	refs := object.MakePrimitiveObject("[Ljava/lang/Object;", types.RefArray, []*object.Object{object.Null})
+	params := []any{goBytes, javaBytes, chars, refs, object.MakeEmptyObject()}
+
+	if bytes, err := GetByteArray(params, 0); err != nil || len(bytes) != 2 || bytes[0] != 'a' {
+		t.Errorf("Expected the bytes of \"ab\", got %v (err: %v)", bytes, err)
+	}
+	if bytes, err := GetByteArray(params, 1); err != nil || len(bytes) != 3 {
+		t.Errorf("Expected 3 bytes, got %v (err: %v)", bytes, err)
+	}
+	if ints, err := GetInt64Array(params, 2); err != nil || len(ints) != 2 || ints[1] != 'y' {
+		t.Errorf("Expected the chars of \"xy\", got %v (err: %v)", ints, err)
+	}
+	if objs, err := GetObjectArray(params, 3); err != nil || len(objs) != 1 {
+		t.Errorf("Expected 1 object, got %v (err: %v)", objs, err)
+	}
+	_, err := GetInt64Array(params, 1)
+	checkError(t, err, excNames.IllegalArgumentException, "got []int8")
+	_, err = GetByteArray(params, 4)
+	checkError(t, err, excNames.IllegalArgumentException, "without a value field")
+}
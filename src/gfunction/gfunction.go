@@ -8,10 +8,13 @@ package gfunction
 
 import (
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"jacobin/src/charset"
 	"jacobin/src/classloader"
 	"jacobin/src/excNames"
 	"jacobin/src/exceptions"
+	"jacobin/src/gfunction/args"
 	"jacobin/src/globals"
 	"jacobin/src/object"
 	"jacobin/src/trace"
@@ -27,11 +30,15 @@ var TestMethodSignatures = make(map[string]GMeth) // used only for the test gfun
 var TestGfunctionsLoaded = false
 
 // File I/O and stream Field keys:
-var FileStatus string = "status"     // using this value in case some member function is looking at it
-var FilePath string = "FilePath"     // full absolute path of a file aka canonical path
-var FileHandle string = "FileHandle" // *os.File
-var FileMark string = "FileMark"     // file position relative to beginning (0)
-var FileAtEOF string = "FileAtEOF"   // file at EOF
+var FileStatus string = "status"       // using this value in case some member function is looking at it
+var FilePath string = "FilePath"       // full absolute path of a file aka canonical path
+var FileHandle string = "FileHandle"   // *os.File
+var FileMark string = "FileMark"       // file position relative to beginning (0)
+var FileAtEOF string = "FileAtEOF"     // file at EOF
+var FileCharset string = "FileCharset" // the charset of a Reader or Writer: *readerCharset or *writerCharset
+var FileChannel string = "FileChannel" // the java/nio/channels/FileChannel of a stream or a RandomAccessFile
+var FileMode string = "FileMode"       // the mode in which a RandomAccessFile was opened: "r", "rw", "rws", or "rwd"
+var FileStream string = "FileStream"   // the io.Reader of a Reader on a stream rather than a file: System.in's *os.File or a *javaStream
 
 // File I/O constants:
 var CreateFilePermissions os.FileMode = 0664 // When creating, read and write for user and group, others read-only
@@ -56,10 +63,18 @@ type GMeth struct {
 	ThreadSafe   bool
 }
 
+// NumParamSlots returns the number of parameters the gfunction takes, for the MTable's
+// diagnostic dump (see classloader.MTableDump)
+func (gm GMeth) NumParamSlots() int {
+	return gm.ParamSlots
+}
+
 // G function error block.
 type GErrBlk struct {
 	ExceptionType int
 	ErrMsg        string
+	Cause         *object.Object // the Throwable that caused the exception, if any
+	Thrown        *object.Object // the Throwable that escaped an upcall, which the GErrBlk stands for
 }
 
 // Construct a G function error block. Return a ptr to it.
@@ -70,6 +85,16 @@ func getGErrBlk(exceptionType int, errMsg string) *GErrBlk {
 	return &gErrBlk
 }
 
+// Construct a G function error block from an error returned by one of the args
+// functions, naming the G function whose parameter was in error.
+func getArgsGErrBlk(funcName string, err error) *GErrBlk {
+	var argErr *args.Error
+	if errors.As(err, &argErr) {
+		return getGErrBlk(argErr.ExceptionType, funcName+": "+argErr.ErrMsg)
+	}
+	return getGErrBlk(excNames.IllegalArgumentException, funcName+": "+err.Error())
+}
+
 // do-nothing Go function shared by several source files
 func clinitGeneric([]interface{}) interface{} {
 	return nil
@@ -89,6 +114,16 @@ func returnCharsetName([]interface{}) interface{} {
 	return object.StringObjectFromGoString(globals.GetCharsetName())
 }
 
+// returns the charset with the name passed, or an UnsupportedEncodingException if
+// Jacobin doesn't support it
+func charsetNamed(funcName, name string) (*charset.Charset, *GErrBlk) {
+	cs := charset.Lookup(name)
+	if cs == nil {
+		return nil, getGErrBlk(excNames.UnsupportedEncodingException, funcName+": unsupported charset: "+name)
+	}
+	return cs, nil
+}
+
 // MTableLoadGFunctions loads the Go methods from files that contain them. It does this
 // by calling the Load_* function in each of those files to load whatever Go functions
 // they make available.
@@ -103,6 +138,7 @@ func MTableLoadGFunctions(MTable *classloader.MT) {
 
 		// java/io/*
 		Load_Io_BufferedReader()
+		Load_Io_BufferedWriter()
 		Load_Io_Console()
 		Load_Io_File()
 		Load_Io_FileInputStream()
@@ -130,6 +166,9 @@ func MTableLoadGFunctions(MTable *classloader.MT) {
 		Load_Lang_Process()
 		Load_Lang_Process_Builder()
 		Load_Lang_Process_Handle_Impl()
+		Load_Lang_Reflect_Array()
+		Load_Lang_Reflect_Method()
+		Load_Lang_Reflect_Proxy()
 		Load_Lang_Runtime()
 		Load_Lang_SecurityManager()
 		Load_Lang_Short()
@@ -139,36 +178,84 @@ func MTableLoadGFunctions(MTable *classloader.MT) {
 		Load_Lang_StringBuilder()
 		Load_Lang_System()
 		Load_Lang_Thread()
+		Load_Lang_ThreadLocal()
 		Load_Lang_Throwable()
 		Load_Lang_UTF16()
 
 		// java/math/*
 		Load_Math_Big_Integer()
 		Load_Math_Big_Decimal()
+		Load_Math_Math_Context()
+		Load_Math_Rounding_Mode()
+
+		// java/nio/*
+		Load_Nio_ByteBuffer()
+		Load_Nio_Channels_FileChannel()
+		Load_Nio_File_Files()
+		Load_Nio_File_Path()
+		Load_Nio_File_WatchService()
 
 		// java/security/*
+		Load_Security_AccessController()
+		Load_Security_MessageDigest()
 		Load_Security_SecureRandom()
 
+		// java/text/*
+		Load_Text_DecimalFormat()
+
+		// java/time/*
+		Load_Time_Clock()
+		Load_Time_Instant()
+
 		// java/util/*
+		Load_Util_ArrayDeque()
 		Load_Util_Arrays()
 		Load_Util_Base64()
+		Load_Util_Collections()
+		Load_Util_Collections_Unmodifiable()
 		Load_Util_Concurrent_Atomic_AtomicInteger()
 		Load_Util_Concurrent_Atomic_Atomic_Long()
+		Load_Util_Concurrent_CountDownLatch()
+		Load_Util_Concurrent_Locks_ReentrantLock()
+		Load_Util_Concurrent_Locks_ReentrantReadWriteLock()
+		Load_Util_Concurrent_ScheduledThreadPoolExecutor()
+		Load_Util_Concurrent_Semaphore()
+		Load_Util_Concurrent_ThreadPoolExecutor()
+		Load_Util_Formatter()
 		Load_Util_Hash_Map()
 		Load_Util_Hash_Set()
 		Load_Util_HexFormat()
+		Load_Util_ImmutableCollections()
+		Load_Util_LinkedHashMap()
+		Load_Util_LinkedHashSet()
 		Load_Util_LinkedList()
+		Load_Util_Logging()
 		Load_Util_Locale()
 		Load_Util_Properties()
+		Load_Util_ResourceBundle()
 		Load_Util_Objects()
 		Load_Util_Optional()
 		Load_Util_Random()
+		Load_Util_StringJoiner()
+		Load_Util_Timer()
+		Load_Util_TreeMap()
+		Load_Util_TreeSet()
+		Load_Util_UUID()
 		Load_Util_Zip_Adler32()
 		Load_Util_Zip_Crc32_Crc32c()
+		Load_Util_Zip_Deflater()
+		Load_Util_Zip_Inflater()
+		Load_Util_Zip_Inflater_Input_Stream()
+		Load_Util_Zip_Zip_File()
+
+		// javax/crypto/*
+		Load_Crypto_Cipher()
+		Load_Crypto_Spec()
 
-		// jdk/internal/misc/*
+		// jdk/internal/*
 		Load_Jdk_Internal_Misc_Unsafe()
 		Load_Jdk_Internal_Misc_ScopedMemoryAccess()
+		Load_Jdk_Internal_Shims()
 
 		// Sun
 		Load_Sun_Security_Action_GetPropertyAction()
@@ -225,6 +312,10 @@ func loadlib(tbl *classloader.MT, libMeths map[string]GMeth) {
 			Meth:  gme,
 		}
 
+		if classloader.PrefersBytecode(key) { // -XX:PreferBytecode runs the JDK's code instead, if there is any
+			classloader.HoldGfunction(key, tableEntry)
+			continue
+		}
 		classloader.AddEntry(tbl, key, tableEntry)
 		classloader.GmtAddEntry(key, classloader.GmtEntry{ // JACOBIN-575 adding to GMT in parallel
 			MethData: &gme,
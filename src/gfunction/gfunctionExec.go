@@ -17,7 +17,9 @@ import (
 	"jacobin/src/globals"
 	"jacobin/src/object"
 	"jacobin/src/trace"
+	"runtime/debug"
 	"slices"
+	"strings"
 	"sync"
 	"time"
 )
@@ -68,10 +70,15 @@ func RunGfunction(mt classloader.MTentry, fs *list.List,
 	if tracing {
 		infoMsg := fmt.Sprintf("RunGfunction: %s, objectRef: %v, paramSlots: %d",
 			fullMethName, objRef, paramCount)
-		trace.Trace(infoMsg)
+		trace.Log(trace.Gfunction, trace.LevelTrace, f.Thread, infoMsg)
 		// TODO jvm.LogTraceStack(f)
 	}
 
+	// With -XX:+PrintGfunctionUsageAtExit, count the call for the report at exit.
+	if globals.GetGlobalRef().PrintGfuncUsage {
+		recordGfunctionCall(fullMethName, isTrap(entry.GFunction))
+	}
+
 	// Reverse the parameter order. Last appended will be fetched first.
 	if paramCount > 1 {
 		slices.Reverse(*params)
@@ -99,26 +106,22 @@ func RunGfunction(mt classloader.MTentry, fs *list.List,
 		}
 		// The key is locked to me.
 		// Call the G function, passing it a pointer to the slice of arguments.
-		if paramCount == 0 {
-			ret = gmeth.GFunction(nil)
-		} else {
-			ret = gmeth.GFunction(*params)
-		}
+		ret = callGfunction(gmeth, params, paramCount)
 		// Unlock thw key.
 		thSafeMap.Delete(key)
 	} else {
 		// Call the function, passing it a pointer to the slice of arguments.
-		if paramCount == 0 {
-			ret = gmeth.GFunction(nil)
-		} else {
-			ret = gmeth.GFunction(*params)
-		}
+		ret = callGfunction(gmeth, params, paramCount)
 	}
 
 	// if an error occured
 	switch ret.(type) {
 	case *GErrBlk:
-		// var errorDetails string
+		// The error becomes a genuine Throwable of the named class, whose message is
+		// the gfunction's (so that getMessage() returns what Java code expects) and
+		// whose stack trace is that of the calling Java code. It's thrown from the
+		// calling frame, so the caller's catch blocks handle it as they would an
+		// exception thrown by ATHROW.
 		errBlk := *ret.(*GErrBlk)
 
 		var threadName string
@@ -128,7 +131,14 @@ func RunGfunction(mt classloader.MTentry, fs *list.List,
 			threadName = fmt.Sprintf("%d", f.Thread)
 		}
 		errMsg := fmt.Sprintf("%s in thread: %s, G-function: %s", errBlk.ErrMsg, threadName, fullMethName)
-		status := exceptions.ThrowEx(errBlk.ExceptionType, errMsg, f)
+		if isTrap(gmeth.GFunction) && !applyTrapPolicy(fullMethName, &errBlk) {
+			return errors.New(errMsg) // the program was ended by -XX:TrapPolicy=abort
+		}
+		if tracing || globals.TraceVerbose {
+			trace.Log(trace.Gfunction, trace.LevelTrace, f.Thread,
+				"RunGfunction: "+excNames.JVMexceptionNames[errBlk.ExceptionType]+": "+errMsg)
+		}
+		status := exceptions.ThrowExWithCause(errBlk.ExceptionType, errBlk.ErrMsg, errBlk.Cause, f)
 		if status != exceptions.Caught {
 			return errors.New(errMsg + " " + errBlk.ErrMsg) // applies only if in test
 		} else {
@@ -150,3 +160,64 @@ func RunGfunction(mt classloader.MTentry, fs *list.List,
 	// return value, so return it.
 	return ret
 }
+
+// callGfunction calls the G function, passing it the slice of arguments. A Go panic
+// in the G function is recovered and returned as a GErrBlk for an InternalError,
+// so that it's thrown in the calling Java code rather than ending the program.
+func callGfunction(gmeth GMeth, params *[]interface{}, paramCount int) (ret any) {
+	defer func() {
+		if r := recover(); r != nil {
+			if globals.TraceVerbose {
+				trace.Trace("callGfunction: recovered from panic: " + fmt.Sprint(r) + "\n" + string(debug.Stack()))
+			}
+			ret = getGErrBlk(excNames.InternalError, fmt.Sprintf("Go panic in G function: %v", r))
+		}
+	}()
+
+	if paramCount == 0 {
+		return gmeth.GFunction(nil)
+	}
+	return gmeth.GFunction(*params)
+}
+
+// invokeJavaMethod lets a gfunction call a Java method (for example, the get()
+// method of a Supplier passed in by the user) through the upcall bridge in the
+// jvm package. An exception that escapes the Java method is returned as a GErrBlk
+// for the same exception, so that the gfunction can simply return it to rethrow it
+// in the calling Java code. Exceptions that have no entry in excNames are
+// rethrown as RuntimeExceptions.
+func invokeJavaMethod(fs *list.List, className, methName, methType string,
+	receiver any, args []any) (any, *GErrBlk) {
+
+	glob := globals.GetGlobalRef()
+	if glob.FuncInvokeJavaMethod == nil {
+		errMsg := fmt.Sprintf("invokeJavaMethod: no upcall bridge to call %s.%s%s", className, methName, methType)
+		return nil, getGErrBlk(excNames.InternalException, errMsg)
+	}
+
+	ret, err := glob.FuncInvokeJavaMethod(fs, className, methName, methType, receiver, args)
+	if err == nil {
+		return ret, nil
+	}
+	return nil, upcallErrBlk(err)
+}
+
+// upcallErrBlk converts an error from the upcall bridge into the GErrBlk that rethrows
+// the exception that escaped the Java method, and which holds that exception's Throwable
+// so that it can be made the cause of another. Other errors become InternalExceptions.
+func upcallErrBlk(err error) *GErrBlk {
+	var upcallErr *exceptions.UpcallError
+	if !errors.As(err, &upcallErr) {
+		return getGErrBlk(excNames.InternalException, err.Error())
+	}
+	var gerr *GErrBlk
+	excName, msg, _ := strings.Cut(upcallErr.Cause, ": ")
+	excType := excNames.ExceptionIndex(excName)
+	if excType == excNames.Unknown {
+		gerr = getGErrBlk(excNames.RuntimeException, upcallErr.Cause)
+	} else {
+		gerr = getGErrBlk(excType, msg)
+	}
+	gerr.Thrown = upcallErr.Throwable
+	return gerr
+}
@@ -14,6 +14,8 @@ import (
 	"jacobin/src/frames"
 	"jacobin/src/globals"
 	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
 	"testing"
 )
 
@@ -137,6 +139,81 @@ func TestRunGfunction_GErrBlk_ReturnsErrorInTestMode(t *testing.T) {
 	}
 }
 
+// adds a loaded class with the given superclass to the method area
+func addTestExceptionClass(name, superclass string) {
+	k := classloader.Klass{Status: 'F', Loader: "testloader", Data: &classloader.ClData{
+		Name:            name,
+		SuperclassIndex: stringPool.GetStringIndex(&superclass),
+	}}
+	classloader.MethAreaInsert(name, &k)
+}
+
+func TestRunGfunction_GErrBlk_CaughtByJavaHandler(t *testing.T) {
+	globals.InitGlobals("test")
+	glob := globals.GetGlobalRef()
+	glob.JacobinName = "testWithoutShutdown" // so that ThrowEx looks for a handler
+	classloader.InitMethodArea()
+	addTestExceptionClass("java/io/IOException", "java/lang/Exception")
+	addTestExceptionClass("java/io/FileNotFoundException", "java/io/IOException")
+	addTestExceptionClass("pkg/UnrelatedException", "java/lang/Exception")
+
+	glob.FuncInstantiateClass = func(className string, _ *list.List) (any, error) {
+		return object.MakeEmptyObjectWithClassName(&className), nil
+	}
+	traceDepth := 0
+	glob.FuncFillInStackTrace = func(params []any) any {
+		traceDepth = params[0].(*list.List).Len()
+		params[1].(*object.Object).FieldTable["stackTrace"] = object.Field{Ftype: types.Ref, Fvalue: object.Null}
+		return nil
+	}
+
+	// the calling method, pkg/Reader.read(), calls the gfunction at PC 0 and has two
+	// handlers for it: one for UnrelatedException (at 10) and one for IOException (at 20)
+	unrelated, ioException := "pkg/UnrelatedException", "java/io/IOException"
+	CP := classloader.CPool{}
+	CP.CpIndex = []classloader.CpEntry{{}, {Type: classloader.ClassRef, Slot: 0}, {Type: classloader.ClassRef, Slot: 1}}
+	CP.ClassRefs = []uint32{stringPool.GetStringIndex(&unrelated), stringPool.GetStringIndex(&ioException)}
+	classloader.MTable["pkg/Reader.read()V"] = classloader.MTentry{MType: 'J', Meth: classloader.JmEntry{
+		Code: make([]byte, 30),
+		Cp:   &CP,
+		Exceptions: []classloader.CodeException{
+			{StartPc: 0, EndPc: 3, HandlerPc: 10, CatchType: 1},
+			{StartPc: 0, EndPc: 3, HandlerPc: 20, CatchType: 2},
+		},
+	}}
+
+	th, fs := addTestExecThread()
+	th.Stack = fs
+	f := fs.Front().Value.(*frames.Frame)
+	f.ClName, f.MethName, f.MethType = "pkg/Reader", "read", "()V"
+	f.CP = &CP
+
+	gm := GMeth{GFunction: func([]interface{}) interface{} {
+		return getGErrBlk(excNames.FileNotFoundException, "data.txt (No such file or directory)")
+	}}
+	params := []interface{}{}
+	ret := RunGfunction(classloader.MTentry{Meth: gm, MType: 'G'}, fs,
+		"java/io/FileInputStream", "open0", "(Ljava/lang/String;)V", &params, false, false)
+
+	if !errors.Is(ret.(error), CaughtGfunctionException) {
+		t.Fatalf("Expected the exception to be caught, got: %v", ret)
+	}
+	if f.PC != 20 {
+		t.Errorf("Expected the IOException handler at PC 20 to catch the exception, got PC %d", f.PC)
+	}
+	exc, ok := f.OpStack[0].(*object.Object)
+	if !ok || object.GoStringFromStringPoolIndex(exc.KlassName) != "java/io/FileNotFoundException" {
+		t.Fatalf("Expected a FileNotFoundException at TOS, got: %v", f.OpStack[0])
+	}
+	msg, ok := exc.FieldTable["detailMessage"].Fvalue.(*object.Object)
+	if !ok || object.GoStringFromStringObject(msg) != "data.txt (No such file or directory)" {
+		t.Errorf("Expected the gfunction's message as the detail message, got: %v", exc.FieldTable["detailMessage"])
+	}
+	if _, ok = exc.FieldTable["stackTrace"]; !ok || traceDepth != 1 {
+		t.Errorf("Expected the stack trace to be filled in from the caller's frame stack")
+	}
+}
+
 func TestRunGfunction_ThreadSafe_WithObjRef(t *testing.T) {
 	globals.InitGlobals("test")
 
@@ -168,6 +245,36 @@ func TestRunGfunction_ThreadSafe_WithObjRef(t *testing.T) {
 	}
 }
 
+func TestRunGfunction_PanicBecomesInternalError(t *testing.T) {
+	globals.InitGlobals("test")
+
+	fs := makeFrameStack()
+
+	gm := GMeth{
+		ThreadSafe: true,
+		GFunction: func(in []interface{}) interface{} {
+			var arr []int64
+			return arr[len(in)] // index out of range
+		},
+	}
+	mt := classloader.MTentry{Meth: gm, MType: 'G'}
+
+	obj := object.MakeEmptyObject()
+	params := []interface{}{obj}
+	ret := RunGfunction(mt, fs, "P/Q", "r", "()V", &params, true, false)
+
+	err, ok := ret.(error)
+	if !ok {
+		t.Fatalf("expected an error return for a panicking GFunction in test mode, got %T: %v", ret, ret)
+	}
+	if !contains(err.Error(), "Go panic in G function") || !contains(err.Error(), "P/Q.r()V") {
+		t.Errorf("error message missing expected content: %q", err.Error())
+	}
+	if _, locked := thSafeMap.Load(obj); locked {
+		t.Errorf("expected the thread-safe lock on the object to be released after the panic")
+	}
+}
+
 // contains is a tiny helper to avoid importing strings just for Contains
 func contains(haystack, needle string) bool {
 	return len(needle) == 0 || (len(haystack) >= len(needle) && indexOf(haystack, needle) >= 0)
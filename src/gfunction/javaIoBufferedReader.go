@@ -10,9 +10,11 @@ import (
 	"fmt"
 	"io"
 	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
 	"jacobin/src/object"
 	"jacobin/src/types"
 	"os"
+	"unicode/utf16"
 )
 
 func Load_Io_BufferedReader() {
@@ -37,8 +39,9 @@ func Load_Io_BufferedReader() {
 
 	MethodSignatures["java/io/BufferedReader.close()V"] =
 		GMeth{
-			ParamSlots: 0,
-			GFunction:  isrClose,
+			ParamSlots:   0,
+			GFunction:    readerWithFrameStack(isrClose),
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/io/BufferedReader.lines()Ljava/util/stream/Stream;"] =
@@ -61,20 +64,23 @@ func Load_Io_BufferedReader() {
 
 	MethodSignatures["java/io/BufferedReader.read()I"] =
 		GMeth{
-			ParamSlots: 0,
-			GFunction:  isrReadOneChar,
+			ParamSlots:   0,
+			GFunction:    readerWithFrameStack(isrReadOneChar),
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/io/BufferedReader.read([CII)I"] =
 		GMeth{
-			ParamSlots: 3,
-			GFunction:  isrReadCharBufferSubset,
+			ParamSlots:   3,
+			GFunction:    readerWithFrameStack(isrReadCharBufferSubset),
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/io/BufferedReader.readLine()Ljava/lang/String;"] =
 		GMeth{
-			ParamSlots: 0,
-			GFunction:  bufferedReaderReadLine,
+			ParamSlots:   0,
+			GFunction:    readerWithFrameStack(bufferedReaderReadLine),
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/io/BufferedReader.ready()Z"] =
@@ -93,7 +99,22 @@ func Load_Io_BufferedReader() {
 
 // "java/io/BufferedReader.<init>(Ljava/io/Reader;])V"
 func bufferedReaderInit(params []interface{}) interface{} {
-	fld1, ok := params[1].(*object.Object).FieldTable[FilePath]
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("bufferedReaderInit", err)
+	}
+	readerObj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("bufferedReaderInit", err)
+	}
+
+	// A Reader on a stream, such as System.in, shares the stream and its decoded chars.
+	if fld, ok := readerObj.FieldTable[FileStream]; ok {
+		obj.FieldTable[FileStream] = fld
+		obj.FieldTable[FileCharset] = object.Field{Ftype: types.Ref, Fvalue: readerCharsetOf(readerObj)}
+		return nil
+	}
+	fld1, ok := readerObj.FieldTable[FilePath]
 	if !ok {
 		errMsg := "Reader object lacks a FilePath field"
 		return getGErrBlk(excNames.InvalidTypeException, errMsg)
@@ -107,11 +128,15 @@ func bufferedReaderInit(params []interface{}) interface{} {
 
 	// Copy java/io/File path
 	fld := fld1
-	params[0].(*object.Object).FieldTable[FilePath] = fld
+	obj.FieldTable[FilePath] = fld
 
 	// Field FileHandle = Golang *os.File from os.Open
 	fld = object.Field{Ftype: types.Ref, Fvalue: osFile}
-	params[0].(*object.Object).FieldTable[FileHandle] = fld
+	obj.FieldTable[FileHandle] = fld
+
+	// Decode the file in the charset of the Reader.
+	fld = object.Field{Ftype: types.Ref, Fvalue: newReaderCharset(readerCharsetOf(readerObj).cs)}
+	obj.FieldTable[FileCharset] = fld
 
 	return nil
 }
@@ -124,7 +149,10 @@ func bufferedReaderMarkSupported([]interface{}) interface{} {
 // "java/io/BufferedReader.readLine()Ljava/lang/String;"
 func bufferedReaderReadLine(params []interface{}) interface{} {
 	// Get BufferedReader object.
-	obj := params[0].(*object.Object)
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("bufferedReaderReadLine", err)
+	}
 
 	// Already at EOF?
 	if eofGet(obj) {
@@ -132,18 +160,16 @@ func bufferedReaderReadLine(params []interface{}) interface{} {
 	}
 
 	// Get file handle.
-	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
+	osFile, ok := readerSource(obj)
 	if !ok {
 		errMsg := "Reader object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
-	// Need a one-byte buffer.
-	byteBuf := make([]byte, 1)
-	var buffer []byte
-	var err error
+	// Read a char at a time.
+	var buffer []uint16
 	for {
-		_, err = osFile.Read(byteBuf)
+		chars, err := readChars(obj, osFile, 1)
 		if err == io.EOF {
 			eofSet(obj, true)
 			if len(buffer) > 0 {
@@ -155,15 +181,15 @@ func bufferedReaderReadLine(params []interface{}) interface{} {
 			errMsg := fmt.Sprintf("osFile.Read failed, reason: %s", err.Error())
 			return getGErrBlk(excNames.IOException, errMsg)
 		}
-		if byteBuf[0] == '\r' {
+		if chars[0] == '\r' {
 			continue
 		}
-		if byteBuf[0] == '\n' {
+		if chars[0] == '\n' {
 			break
 		}
-		buffer = append(buffer, byteBuf[0])
+		buffer = append(buffer, chars[0])
 	}
 
 	// Return the string.
-	return object.StringObjectFromByteArray(buffer)
+	return object.StringObjectFromGoString(string(utf16.Decode(buffer)))
 }
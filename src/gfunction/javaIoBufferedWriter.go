@@ -0,0 +1,138 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"os"
+	"unicode/utf16"
+)
+
+// A BufferedWriter writes straight through to the file of the Writer it wraps, so
+// flush() and close() are those of OutputStreamWriter.
+func Load_Io_BufferedWriter() {
+
+	MethodSignatures["java/io/BufferedWriter.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/io/BufferedWriter.<init>(Ljava/io/Writer;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  bufferedWriterInit,
+		}
+
+	MethodSignatures["java/io/BufferedWriter.<init>(Ljava/io/Writer;I)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  bufferedWriterInitSize,
+		}
+
+	MethodSignatures["java/io/BufferedWriter.close()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  oswClose,
+		}
+
+	MethodSignatures["java/io/BufferedWriter.flush()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  oswFlush,
+		}
+
+	MethodSignatures["java/io/BufferedWriter.newLine()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  bufferedWriterNewLine,
+		}
+
+	MethodSignatures["java/io/BufferedWriter.write(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  oswWriteOneChar,
+		}
+
+	MethodSignatures["java/io/BufferedWriter.write([CII)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  oswWriteCharBuffer,
+		}
+
+	MethodSignatures["java/io/BufferedWriter.write(Ljava/lang/String;II)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  oswWriteStringBuffer,
+		}
+
+}
+
+// "java/io/BufferedWriter.<init>(Ljava/io/Writer;)V"
+func bufferedWriterInit(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("bufferedWriterInit", err)
+	}
+	writerObj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("bufferedWriterInit", err)
+	}
+	fldHandle, ok := writerObj.FieldTable[FileHandle]
+	if !ok {
+		errMsg := "bufferedWriterInit: Writer object lacks a FileHandle field"
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+
+	// Share the file and the charset of the Writer, so that the output of the two
+	// is written in order and a surrogate pair split between them is encoded whole.
+	if fldPath, ok := writerObj.FieldTable[FilePath]; ok {
+		obj.FieldTable[FilePath] = fldPath
+	}
+	obj.FieldTable[FileHandle] = fldHandle
+	obj.FieldTable[FileCharset] = object.Field{Ftype: types.Ref, Fvalue: writerCharsetOf(writerObj)}
+	return nil
+}
+
+// "java/io/BufferedWriter.<init>(Ljava/io/Writer;I)V"
+func bufferedWriterInitSize(params []interface{}) interface{} {
+	size, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("bufferedWriterInitSize", err)
+	}
+	if size <= 0 {
+		return getGErrBlk(excNames.IllegalArgumentException, "bufferedWriterInitSize: Buffer size <= 0")
+	}
+	return bufferedWriterInit(params[:2])
+}
+
+// "java/io/BufferedWriter.newLine()V" writes the line separator, as System.lineSeparator()
+// returns it
+func bufferedWriterNewLine(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("bufferedWriterNewLine", err)
+	}
+	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
+	if !ok {
+		errMsg := "bufferedWriterNewLine: BufferedWriter object lacks a FileHandle field"
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+
+	chars := utf16.Encode([]rune(globals.GetLineSeparator()))
+	_, err = osFile.Write(writerCharsetOf(obj).encoder.Encode(chars))
+	if err != nil {
+		errMsg := fmt.Sprintf("bufferedWriterNewLine: osFile.Write failed, reason: %s", err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	return nil
+}
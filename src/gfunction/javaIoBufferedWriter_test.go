@@ -0,0 +1,82 @@
+package gfunction
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"jacobin/src/charset"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+)
+
+// returns a Writer object, as OutputStreamWriter makes it, that writes to a new file
+func makeWriterObjForFile(t *testing.T, filePath string) *object.Object {
+	t.Helper()
+	fh, err := os.Create(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	t.Cleanup(func() { _ = fh.Close() })
+	return &object.Object{FieldTable: map[string]object.Field{
+		FilePath:    {Ftype: types.ByteArray, Fvalue: object.JavaByteArrayFromGoString(filePath)},
+		FileHandle:  {Ftype: types.FileHandle, Fvalue: fh},
+		FileCharset: {Ftype: types.Ref, Fvalue: newWriterCharset(charset.UTF8)},
+	}}
+}
+
+func TestBufferedWriterWriteAndNewLine(t *testing.T) {
+	globals.InitStringPool()
+	filePath := filepath.Join(t.TempDir(), "test.txt")
+	writerObj := makeWriterObjForFile(t, filePath)
+
+	bwObj := object.MakeEmptyObject()
+	if res := bufferedWriterInit([]interface{}{bwObj, writerObj}); res != nil {
+		t.Fatalf("Expected success, got error: %v", res)
+	}
+	if writerCharsetOf(bwObj) != writerCharsetOf(writerObj) {
+		t.Errorf("Expected the BufferedWriter to share the charset of the Writer")
+	}
+
+	saved := globals.GetLineSeparator()
+	t.Cleanup(func() { globals.SetLineSeparator(saved) })
+	globals.SetLineSeparator("\r\n")
+
+	if res := oswWriteStringBuffer([]interface{}{bwObj, object.StringObjectFromGoString("héllo"), int64(0), int64(5)}); res != nil {
+		t.Fatalf("Unexpected error: %v", res)
+	}
+	if res := bufferedWriterNewLine([]interface{}{bwObj}); res != nil {
+		t.Fatalf("Unexpected error: %v", res)
+	}
+	if res := oswWriteOneChar([]interface{}{bwObj, int64('!')}); res != nil {
+		t.Fatalf("Unexpected error: %v", res)
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(content) != "héllo\r\n!" {
+		t.Errorf("Expected %q, got %q", "héllo\r\n!", content)
+	}
+}
+
+func TestBufferedWriterInit_Errors(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "test.txt")
+	writerObj := makeWriterObjForFile(t, filePath)
+
+	res := bufferedWriterInitSize([]interface{}{object.MakeEmptyObject(), writerObj, int64(0)})
+	if gErr, ok := res.(*GErrBlk); !ok || gErr.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("Expected IllegalArgumentException for a buffer size of 0, got %v", res)
+	}
+	if res := bufferedWriterInitSize([]interface{}{object.MakeEmptyObject(), writerObj, int64(8192)}); res != nil {
+		t.Errorf("Expected success, got error: %v", res)
+	}
+
+	res = bufferedWriterInit([]interface{}{object.MakeEmptyObject(), object.MakeEmptyObject()})
+	if gErr, ok := res.(*GErrBlk); !ok || gErr.ExceptionType != excNames.IOException {
+		t.Errorf("Expected IOException for a Writer without a file, got %v", res)
+	}
+}
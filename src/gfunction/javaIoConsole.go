@@ -11,6 +11,7 @@ import (
 	"jacobin/src/classloader"
 	"jacobin/src/excNames"
 	"jacobin/src/object"
+	"jacobin/src/replay"
 	"jacobin/src/statics"
 	"jacobin/src/types"
 	"os"
@@ -113,12 +114,25 @@ func consoleClinit([]interface{}) interface{} {
 	return nil
 }
 
+// consoleFile returns System.in or System.out, if it's a Go file, as it is unless the
+// program has replaced it. The console doesn't use a stream the program has set, just
+// as in the JDK, so for one of those it returns the process's own stdin or stdout.
+func consoleFile(name string) *os.File {
+	if osFile, ok := statics.GetStaticValue("java/lang/System", name).(*os.File); ok {
+		return osFile
+	}
+	if name == "in" {
+		return os.Stdin
+	}
+	return os.Stdout
+}
+
 // Flush java/lang/System.in/out/err.
 // "java/io/Console.flush()V"
 func consoleFlush([]interface{}) interface{} {
-	stdinout := statics.GetStaticValue("java/lang/System", "in").(*os.File)
+	stdinout := consoleFile("in")
 	_ = stdinout.Sync()
-	stdinout = statics.GetStaticValue("java/lang/System", "out").(*os.File)
+	stdinout = consoleFile("out")
 	_ = stdinout.Sync()
 	// Note: java/lang/System.err is not associated with the system console.
 	return nil
@@ -138,7 +152,7 @@ func consolePrintf(params []interface{}) interface{} {
 	}
 	objPtr := retval.(*object.Object)
 	str := object.GoStringFromStringObject(objPtr)
-	stdout := statics.GetStaticValue("java/lang/System", "out").(*os.File)
+	stdout := consoleFile("out")
 	_, _ = fmt.Fprint(stdout, str)
 	return stdout // Return the *os.File
 
@@ -151,9 +165,9 @@ func consoleReadLine([]interface{}) interface{} {
 	var bb = []byte{0x00}
 	var nbytes int
 	var err error
-	stdin := statics.GetStaticValue("java/lang/System", "in").(*os.File)
+	stdin := consoleFile("in")
 	for {
-		nbytes, err = stdin.Read(bb)
+		nbytes, err = replay.Read(stdin, bb)
 		if nbytes == 0 {
 			break
 		}
@@ -186,7 +200,7 @@ func consoleReadPassword([]interface{}) interface{} {
 		errMsg := fmt.Sprintf("consoleReadPassword: stdin.ReadPassword failed, reason: %s", err.Error())
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
-	stdout := statics.GetStaticValue("java/lang/System", "out").(*os.File)
+	stdout := consoleFile("out")
 	_, _ = fmt.Fprint(stdout, "\n")
 
 	// Convert password to int64 array, insert into an object, and return to caller
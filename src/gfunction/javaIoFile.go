@@ -7,13 +7,22 @@
 package gfunction
 
 import (
+	"crypto/rand"
+	"encoding/binary"
 	"fmt"
 	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
+	"jacobin/src/globals"
 	"jacobin/src/object"
+	"jacobin/src/shutdown"
 	"jacobin/src/trace"
 	"jacobin/src/types"
+	"jacobin/src/util"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 func Load_Io_File() {
@@ -66,14 +75,49 @@ func Load_Io_File() {
 			GFunction:  fileIsInvalid,
 		}
 
+	MethodSignatures["java/io/File.toPath()Ljava/nio/file/Path;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  fileToPath,
+		}
+
+	MethodSignatures["java/io/File.createTempFile(Ljava/lang/String;Ljava/lang/String;)Ljava/io/File;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  fileCreateTempFile,
+		}
+
+	MethodSignatures["java/io/File.createTempFile(Ljava/lang/String;Ljava/lang/String;Ljava/io/File;)Ljava/io/File;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  fileCreateTempFile,
+		}
+
+	MethodSignatures["java/io/File.deleteOnExit()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  fileDeleteOnExit,
+		}
+
 }
 
+// the paths that deleteOnExit() was called on, in the order of the first call for each
+var deleteOnExitPaths []string
+var deleteOnExitLock sync.Mutex
+var deleteOnExitHook sync.Once
+
+// the maximum number of names createTempEntry() tries before it gives up
+const tempEntryAttempts = 100
+
 // "java/io/File.<init>(Ljava/lang/String;)V"
 // File file = new File(path);
 func fileInit(params []interface{}) interface{} {
 
 	// Get File object. Initialise the field map if required.
-	objFile := params[0].(*object.Object)
+	objFile, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("fileInit", err)
+	}
 	if objFile.FieldTable == nil {
 		objFile.FieldTable = make(map[string]object.Field)
 	}
@@ -106,16 +150,17 @@ func fileInit(params []interface{}) interface{} {
 	fld = object.Field{Ftype: types.ByteArray, Fvalue: object.JavaByteArrayFromGoString(absPathStr)}
 	objFile.FieldTable[FilePath] = fld
 
-	fld = object.Field{Ftype: types.Int, Fvalue: os.PathSeparator}
+	platform := util.GetPlatform()
+	fld = object.Field{Ftype: types.Int, Fvalue: int64(platform.PathSeparator)}
 	objFile.FieldTable["separatorChar"] = fld
 
-	fld = object.Field{Ftype: types.ByteArray, Fvalue: object.JavaByteArrayFromGoByteArray([]byte{os.PathSeparator})}
+	fld = object.Field{Ftype: types.ByteArray, Fvalue: object.JavaByteArrayFromGoByteArray([]byte{platform.PathSeparator})}
 	objFile.FieldTable["separator"] = fld
 
-	fld = object.Field{Ftype: types.Int, Fvalue: os.PathListSeparator}
+	fld = object.Field{Ftype: types.Int, Fvalue: int64(platform.PathListSeparator)}
 	objFile.FieldTable["pathSeparatorChar"] = fld
 
-	fld = object.Field{Ftype: types.ByteArray, Fvalue: object.JavaByteArrayFromGoByteArray([]byte{os.PathListSeparator})}
+	fld = object.Field{Ftype: types.ByteArray, Fvalue: object.JavaByteArrayFromGoByteArray([]byte{platform.PathListSeparator})}
 	objFile.FieldTable["pathSeparator"] = fld
 
 	// Set status to "checked" (=1).
@@ -127,7 +172,11 @@ func fileInit(params []interface{}) interface{} {
 
 // "java/io/File.getPath()Ljava/lang/String;"
 func fileGetPath(params []interface{}) interface{} {
-	fld, ok := params[0].(*object.Object).FieldTable[FilePath]
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("fileGetPath", err)
+	}
+	fld, ok := obj.FieldTable[FilePath]
 	if !ok {
 		errMsg := "fileGetPath: File object lacks a FilePath field"
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -137,7 +186,11 @@ func fileGetPath(params []interface{}) interface{} {
 
 // "java/io/File.isInvalid()Z"
 func fileIsInvalid(params []interface{}) interface{} {
-	status, ok := params[0].(*object.Object).FieldTable[FileStatus].Fvalue.(int64)
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("fileIsInvalid", err)
+	}
+	status, ok := obj.FieldTable[FileStatus].Fvalue.(int64)
 	if !ok {
 		errMsg := "fileIsInvalid: File object lacks a FileStatus field"
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -151,21 +204,26 @@ func fileIsInvalid(params []interface{}) interface{} {
 
 // "java/io/File.delete()Z"
 func fileDelete(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("fileDelete", err)
+	}
+
 	// Close the file if it is open (Windows).
-	osFile, ok := params[0].(*object.Object).FieldTable[FileHandle].Fvalue.(*os.File)
+	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
 	if ok {
 		_ = osFile.Close()
 	}
 
 	// Get file path string.
-	fld, ok := params[0].(*object.Object).FieldTable[FilePath]
+	fld, ok := obj.FieldTable[FilePath]
 	if !ok {
 		errMsg := "fileDelete: File object lacks a FilePath field"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 	pathStr := object.GoStringFromJavaByteArray(fld.Fvalue.([]types.JavaByte))
 
-	err := os.Remove(pathStr)
+	err = os.Remove(pathStr)
 	if err != nil {
 		errMsg := fmt.Sprintf("fileDelete: Failed to remove file %s, reason: %s", pathStr, err.Error())
 		trace.Error(errMsg)
@@ -176,8 +234,13 @@ func fileDelete(params []interface{}) interface{} {
 
 // "java/io/File.createNewFile()Z"
 func fileCreate(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("fileCreate", err)
+	}
+
 	// Get file path string.
-	fld, ok := params[0].(*object.Object).FieldTable[FilePath]
+	fld, ok := obj.FieldTable[FilePath]
 	if !ok {
 		errMsg := "fileCreate: File object lacks a FilePath field"
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -194,7 +257,120 @@ func fileCreate(params []interface{}) interface{} {
 
 	// Copy the file handle into the FileOutputStream object.
 	fld = object.Field{Ftype: types.FileHandle, Fvalue: osFile}
-	params[0].(*object.Object).FieldTable[FileHandle] = fld
+	obj.FieldTable[FileHandle] = fld
 
 	return int64(1)
 }
+
+// makeFileObject returns a java/io/File of the path, or the GErrBlk from fileInit()
+func makeFileObject(pathStr string) interface{} {
+	className := "java/io/File"
+	fileObj := object.MakeEmptyObjectWithClassName(&className)
+	if ret := fileInit([]interface{}{fileObj, object.StringObjectFromGoString(pathStr)}); ret != nil {
+		return ret
+	}
+	return fileObj
+}
+
+// createTempEntry creates a new, empty file or directory in dir, named by the prefix, an
+// unsigned long, and the suffix, as the JDK names them. The long comes from crypto/rand,
+// and a name that already exists is never reused, so another process can neither predict
+// the name nor create the entry first. Only the owner may use the new file or directory.
+func createTempEntry(dir, prefix, suffix string, isDir bool) (string, error) {
+	var err error
+	for attempt := 0; attempt < tempEntryAttempts; attempt++ {
+		var random [8]byte
+		if _, err = rand.Read(random[:]); err != nil {
+			return "", err
+		}
+		name := prefix + strconv.FormatUint(binary.BigEndian.Uint64(random[:]), 10) + suffix
+		pathStr := filepath.Join(dir, name)
+		if isDir {
+			err = os.Mkdir(pathStr, 0700)
+		} else {
+			var osFile *os.File
+			if osFile, err = os.OpenFile(pathStr, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600); err == nil {
+				err = osFile.Close()
+			}
+		}
+		if !os.IsExist(err) {
+			return pathStr, err
+		}
+	}
+	return "", err
+}
+
+// "java/io/File.createTempFile(Ljava/lang/String;Ljava/lang/String;Ljava/io/File;)Ljava/io/File;"
+// and the form without a directory. The prefix must be at least three characters; a null
+// suffix is ".tmp", and a null directory is the one named by java.io.tmpdir.
+func fileCreateTempFile(params []interface{}) interface{} {
+	prefixObj, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(prefixObj) {
+		return getGErrBlk(excNames.NullPointerException, "fileCreateTempFile: prefix is null")
+	}
+	prefix := object.GoStringFromStringObject(prefixObj)
+	if len(prefix) < 3 {
+		errMsg := fmt.Sprintf("Prefix string \"%s\" too short: length must be at least 3", prefix)
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	suffix := ".tmp"
+	if suffixObj, ok := params[1].(*object.Object); ok && !object.IsNull(suffixObj) {
+		suffix = object.GoStringFromStringObject(suffixObj)
+	}
+	dir := globals.GetSystemProperty("java.io.tmpdir")
+	if len(params) > 2 {
+		if dirObj, ok := params[2].(*object.Object); ok && !object.IsNull(dirObj) {
+			var gErr *GErrBlk
+			if dir, gErr = pathString("fileCreateTempFile", params, 2); gErr != nil {
+				return gErr
+			}
+		}
+	}
+	if strings.ContainsAny(prefix+suffix, "/"+string(os.PathSeparator)) {
+		return getGErrBlk(excNames.IOException, "fileCreateTempFile: Unable to create temporary file")
+	}
+
+	pathStr, err := createTempEntry(dir, prefix, suffix, false)
+	if err != nil {
+		errMsg := fmt.Sprintf("fileCreateTempFile: Unable to create temporary file in %s, reason: %s", dir, err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	return makeFileObject(pathStr)
+}
+
+// "java/io/File.deleteOnExit()V" -- the file or directory is deleted when Jacobin exits,
+// after any registered later, as in the JDK
+func fileDeleteOnExit(params []interface{}) interface{} {
+	pathStr, gErr := pathString("fileDeleteOnExit", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	addDeleteOnExit(pathStr)
+	return nil
+}
+
+// addDeleteOnExit records the path for deleteFilesOnExit(), which the first call hooks to
+// Jacobin's exit. A path already recorded keeps its place.
+func addDeleteOnExit(pathStr string) {
+	deleteOnExitHook.Do(func() { shutdown.AddExitHook(deleteFilesOnExit) })
+	deleteOnExitLock.Lock()
+	defer deleteOnExitLock.Unlock()
+	for _, recorded := range deleteOnExitPaths {
+		if recorded == pathStr {
+			return
+		}
+	}
+	deleteOnExitPaths = append(deleteOnExitPaths, pathStr)
+}
+
+// deleteFilesOnExit deletes the recorded paths in the reverse of the order they were
+// recorded, so that a directory's entries, recorded after it, go before it
+func deleteFilesOnExit() {
+	deleteOnExitLock.Lock()
+	paths := deleteOnExitPaths
+	deleteOnExitPaths = nil
+	deleteOnExitLock.Unlock()
+	for i := len(paths) - 1; i >= 0; i-- {
+		_ = os.Remove(paths[i])
+	}
+}
@@ -10,7 +10,9 @@ import (
 	"fmt"
 	"io"
 	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
 	"jacobin/src/object"
+	"jacobin/src/replay"
 	"jacobin/src/types"
 	"os"
 )
@@ -47,6 +49,12 @@ func Load_Io_FileInputStream() {
 			GFunction:  fisClose,
 		}
 
+	MethodSignatures["java/io/FileInputStream.getChannel()Ljava/nio/channels/FileChannel;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  fisGetChannel,
+		}
+
 	MethodSignatures["java/io/FileInputStream.read()I"] =
 		GMeth{
 			ParamSlots: 0,
@@ -104,12 +112,6 @@ func Load_Io_FileInputStream() {
 			GFunction:  trapFunction,
 		}
 
-	MethodSignatures["java/io/FileInputStream.getChannel()Ljava/nio/channels/FileChannel;"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  trapFunction,
-		}
-
 	MethodSignatures["java/io/FileInputStream.getFD()Ljava/io/FileDescriptor;"] =
 		GMeth{
 			ParamSlots: 0,
@@ -120,9 +122,17 @@ func Load_Io_FileInputStream() {
 
 // "java/io/FileInputStream.<init>(Ljava/io/File;])V"
 func initFileInputStreamFile(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("initFileInputStreamFile", err)
+	}
 
 	// Get file path field from the File argument.
-	fld, ok := params[1].(*object.Object).FieldTable[FilePath]
+	fileObj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("initFileInputStreamFile", err)
+	}
+	fld, ok := fileObj.FieldTable[FilePath]
 	if !ok {
 		errMsg := "initFileInputStreamFile: File object argument lacks a FilePath field"
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -139,20 +149,27 @@ func initFileInputStreamFile(params []interface{}) interface{} {
 	}
 
 	// Copy the file path field into the FileInputStream object.
-	params[0].(*object.Object).FieldTable[FilePath] = fld
+	obj.FieldTable[FilePath] = fld
 
 	// Copy the file handle into the FileInputStream object.
 	fld = object.Field{Ftype: types.FileHandle, Fvalue: osFile}
-	params[0].(*object.Object).FieldTable[FileHandle] = fld
+	obj.FieldTable[FileHandle] = fld
 
 	return nil
 }
 
 // "java/io/FileInputStream.<init>(Ljava/lang/String;])V"
 func initFileInputStreamString(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("initFileInputStreamString", err)
+	}
 
 	// Using the argument path string, open the file for read-only.
-	pathStr := object.GoStringFromStringObject(params[1].(*object.Object))
+	pathStr, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("initFileInputStreamString", err)
+	}
 	osFile, err := os.Open(pathStr)
 	if err != nil {
 		errMsg := fmt.Sprintf("initFileInputStreamString: os.Open(%s) failed, reason: %s", pathStr, err.Error())
@@ -161,20 +178,24 @@ func initFileInputStreamString(params []interface{}) interface{} {
 
 	// Copy the file path field into the FileInputStream object.
 	fld := object.Field{Ftype: types.ByteArray, Fvalue: object.JavaByteArrayFromGoString(pathStr)}
-	params[0].(*object.Object).FieldTable[FilePath] = fld
+	obj.FieldTable[FilePath] = fld
 
 	// Copy the file handle into the FileInputStream object.
 	fld = object.Field{Ftype: types.FileHandle, Fvalue: osFile}
-	params[0].(*object.Object).FieldTable[FileHandle] = fld
+	obj.FieldTable[FileHandle] = fld
 
 	return nil
 }
 
 // "java/io/FileInputStream.available()I"
 func fisAvailable(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("fisAvailable", err)
+	}
 
 	// Get the file handle.
-	osFile, ok := params[0].(*object.Object).FieldTable[FileHandle].Fvalue.(*os.File)
+	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
 	if !ok {
 		errMsg := "fisAvailable: FileInputStream object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -183,7 +204,7 @@ func fisAvailable(params []interface{}) interface{} {
 	// Compute total file size.
 	fileInfo, err := osFile.Stat()
 	if err != nil {
-		path := object.GoStringFromJavaByteArray(params[0].(*object.Object).FieldTable["path"].Fvalue.([]types.JavaByte))
+		path := object.GoStringFromJavaByteArray(obj.FieldTable["path"].Fvalue.([]types.JavaByte))
 		errMsg := fmt.Sprintf("fisAvailable: osFile.Stat(%s) failed, reason: %s", path, err.Error())
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
@@ -202,9 +223,13 @@ func fisAvailable(params []interface{}) interface{} {
 
 // "java/io/FileInputStream.read()I"
 func fisReadOne(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("fisReadOne", err)
+	}
 
 	// Get file handle.
-	osFile, ok := params[0].(*object.Object).FieldTable[FileHandle].Fvalue.(*os.File)
+	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
 	if !ok {
 		errMsg := "fisReadOne: FileInputStream object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -214,7 +239,7 @@ func fisReadOne(params []interface{}) interface{} {
 	buffer := make([]byte, 1)
 
 	// Read one byte.
-	_, err := osFile.Read(buffer)
+	_, err = replay.Read(osFile, buffer)
 	if err == io.EOF {
 		return int64(-1) // return -1 on EOF
 	}
@@ -229,16 +254,24 @@ func fisReadOne(params []interface{}) interface{} {
 
 // "java/io/FileInputStream.read([B)I"
 func fisReadByteArray(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("fisReadByteArray", err)
+	}
 
 	// Get file handle.
-	osFile, ok := params[0].(*object.Object).FieldTable[FileHandle].Fvalue.(*os.File)
+	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
 	if !ok {
 		errMsg := "fisReadByteArray: FileInputStream object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
 	// Set buffer to the byte array parameter.
-	javaBytes, ok := params[1].(*object.Object).FieldTable["value"].Fvalue.([]types.JavaByte)
+	arrayObj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("fisReadByteArray", err)
+	}
+	javaBytes, ok := arrayObj.FieldTable["value"].Fvalue.([]types.JavaByte)
 	if !ok {
 		errMsg := "fisReadByteArray: Byte array parameter lacks a \"value\" field"
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -246,7 +279,7 @@ func fisReadByteArray(params []interface{}) interface{} {
 	buffer := object.GoByteArrayFromJavaByteArray(javaBytes)
 
 	// Fill the buffer.
-	nbytes, err := osFile.Read(buffer)
+	nbytes, err := replay.Read(osFile, buffer)
 	if err == io.EOF {
 		return int64(-1) // return -1 on EOF
 	}
@@ -258,7 +291,7 @@ func fisReadByteArray(params []interface{}) interface{} {
 	// All is well - update the supplied buffer.
 	javaBytes = object.JavaByteArrayFromGoByteArray(buffer[:nbytes])
 	fld := object.Field{Ftype: types.ByteArray, Fvalue: javaBytes}
-	params[1].(*object.Object).FieldTable["value"] = fld
+	arrayObj.FieldTable["value"] = fld
 
 	// Return the number of bytes.
 	return int64(nbytes)
@@ -266,16 +299,24 @@ func fisReadByteArray(params []interface{}) interface{} {
 
 // "java/io/FileInputStream.read([BII)I"
 func fisReadByteArrayOffset(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("fisReadByteArrayOffset", err)
+	}
 
 	// Get the file handle.
-	osFile, ok := params[0].(*object.Object).FieldTable[FileHandle].Fvalue.(*os.File)
+	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
 	if !ok {
 		errMsg := "fisReadByteArrayOffset: FileInputStream object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
 	// Set buffer (buf1) to the byte array parameter.
-	javaBytes, ok := params[1].(*object.Object).FieldTable["value"].Fvalue.([]types.JavaByte)
+	arrayObj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("fisReadByteArrayOffset", err)
+	}
+	javaBytes, ok := arrayObj.FieldTable["value"].Fvalue.([]types.JavaByte)
 	if !ok {
 		errMsg := "fisReadByteArrayOffset: Byte array parameter lacks a \"value\" field"
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -283,8 +324,14 @@ func fisReadByteArrayOffset(params []interface{}) interface{} {
 	buf1 := object.GoByteArrayFromJavaByteArray(javaBytes)
 
 	// Collect the offset and length parameter values.
-	offset := params[2].(int64)
-	length := params[3].(int64)
+	offset, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("fisReadByteArrayOffset", err)
+	}
+	length, err := args.GetInt64(params, 3)
+	if err != nil {
+		return getArgsGErrBlk("fisReadByteArrayOffset", err)
+	}
 
 	// Check the parameters.
 	if length == 0 {
@@ -298,7 +345,7 @@ func fisReadByteArrayOffset(params []interface{}) interface{} {
 
 	// Try read with a second buffer.
 	buf2 := make([]byte, length)
-	nbytes, err := osFile.Read(buf2)
+	nbytes, err := replay.Read(osFile, buf2)
 	if err == io.EOF {
 		return int64(-1) // return -1 on EOF
 	}
@@ -313,7 +360,7 @@ func fisReadByteArrayOffset(params []interface{}) interface{} {
 	// Update the parameter buffer.
 	javaBytes = object.JavaByteArrayFromGoByteArray(buf1)
 	fld := object.Field{Ftype: types.ByteArray, Fvalue: javaBytes}
-	params[1].(*object.Object).FieldTable["value"] = fld
+	arrayObj.FieldTable["value"] = fld
 
 	// Return the number of bytes.
 	return int64(nbytes)
@@ -321,19 +368,26 @@ func fisReadByteArrayOffset(params []interface{}) interface{} {
 
 // "java/io/FileInputStream.skip(J)J"
 func fisSkip(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("fisSkip", err)
+	}
 
 	// Get file handle.
-	osFile, ok := params[0].(*object.Object).FieldTable[FileHandle].Fvalue.(*os.File)
+	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
 	if !ok {
 		errMsg := "fisSkip: FileInputStream object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
 	// Get skip count.
-	count := params[1].(int64)
+	count, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("fisSkip", err)
+	}
 
 	// Skip.
-	_, err := osFile.Seek(count, 1)
+	_, err = osFile.Seek(count, 1)
 	if err != nil {
 		errMsg := fmt.Sprintf("fisSkip: osFile.Seek(%d) failed, reason: %s", count, err.Error())
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -345,16 +399,20 @@ func fisSkip(params []interface{}) interface{} {
 
 // "java/io/FileInputStream.close()V"
 func fisClose(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("fisClose", err)
+	}
 
 	// Get file handle.
-	osFile, ok := params[0].(*object.Object).FieldTable[FileHandle].Fvalue.(*os.File)
+	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
 	if !ok {
 		errMsg := "fisClose: FileInputStream object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
 	// Close the file.
-	err := osFile.Close()
+	err = osFile.Close()
 	if err != nil {
 		errMsg := fmt.Sprintf("fisClose: osFile.Close() failed, reason: %s", err.Error())
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -9,6 +9,7 @@ package gfunction
 import (
 	"fmt"
 	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
 	"jacobin/src/object"
 	"jacobin/src/types"
 	"os"
@@ -52,6 +53,12 @@ func Load_Io_FileOutputStream() {
 			GFunction:  fosClose,
 		}
 
+	MethodSignatures["java/io/FileOutputStream.getChannel()Ljava/nio/channels/FileChannel;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  fosGetChannel,
+		}
+
 	MethodSignatures["java/io/FileOutputStream.write(I)V"] =
 		GMeth{
 			ParamSlots: 1,
@@ -86,9 +93,17 @@ func Load_Io_FileOutputStream() {
 
 // "java/io/FileOutputStream.<init>(Ljava/io/File;])V"
 func initFileOutputStreamFile(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("initFileOutputStreamFile", err)
+	}
 
 	// Get the file path.
-	fld, ok := params[1].(*object.Object).FieldTable[FilePath]
+	fileObj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("initFileOutputStreamFile", err)
+	}
+	fld, ok := fileObj.FieldTable[FilePath]
 	if !ok {
 		errMsg := "initFileOutputStreamFile: File object lacks a FilePath field"
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -103,20 +118,28 @@ func initFileOutputStreamFile(params []interface{}) interface{} {
 	}
 
 	// Copy the file path field into the FileOutputStream object.
-	params[0].(*object.Object).FieldTable[FilePath] = fld
+	obj.FieldTable[FilePath] = fld
 
 	// Copy the file handle into the FileOutputStream object.
 	fld = object.Field{Ftype: types.FileHandle, Fvalue: osFile}
-	params[0].(*object.Object).FieldTable[FileHandle] = fld
+	obj.FieldTable[FileHandle] = fld
 
 	return nil
 }
 
 // "java/io/FileOutputStream.<init>(Ljava/io/File;Z])V"
 func initFileOutputStreamFileBoolean(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("initFileOutputStreamFileBoolean", err)
+	}
 
 	// Get file path field from the File argument.
-	fld, ok := params[1].(*object.Object).FieldTable[FilePath]
+	fileObj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("initFileOutputStreamFileBoolean", err)
+	}
+	fld, ok := fileObj.FieldTable[FilePath]
 	if !ok {
 		errMsg := "initFileOutputStreamFileBoolean: File object lacks a FilePath field"
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -134,7 +157,6 @@ func initFileOutputStreamFileBoolean(params []interface{}) interface{} {
 
 	// Open the file for write-only, yielding a file handle.
 	var osFile *os.File
-	var err error
 	if boolarg != 0 { // append: true
 		osFile, err = os.OpenFile(pathStr, os.O_APPEND|os.O_CREATE|os.O_WRONLY, CreateFilePermissions)
 	} else {
@@ -147,20 +169,27 @@ func initFileOutputStreamFileBoolean(params []interface{}) interface{} {
 	}
 
 	// Copy the file path field into the FileOutputStream object.
-	params[0].(*object.Object).FieldTable[FilePath] = fld
+	obj.FieldTable[FilePath] = fld
 
 	// Copy the file handle into the FileOutputStream object.
 	fld = object.Field{Ftype: types.FileHandle, Fvalue: osFile}
-	params[0].(*object.Object).FieldTable[FileHandle] = fld
+	obj.FieldTable[FileHandle] = fld
 
 	return nil
 }
 
 // "java/io/FileOutputStream.<init>(Ljava/lang/String;])V"
 func initFileOutputStreamString(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("initFileOutputStreamString", err)
+	}
 
 	// Using the argument path string, open the file for write-only.
-	pathStr := object.GoStringFromStringObject(params[1].(*object.Object))
+	pathStr, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("initFileOutputStreamString", err)
+	}
 
 	// Open the file for write-only, yielding a file handle.
 	osFile, err := os.Create(pathStr)
@@ -171,20 +200,27 @@ func initFileOutputStreamString(params []interface{}) interface{} {
 
 	// Copy the file path field into the FileOutputStream object.
 	fld := object.Field{Ftype: types.ByteArray, Fvalue: []byte(pathStr)}
-	params[0].(*object.Object).FieldTable[FilePath] = fld
+	obj.FieldTable[FilePath] = fld
 
 	// Copy the file handle into the FileOutputStream object.
 	fld = object.Field{Ftype: types.FileHandle, Fvalue: osFile}
-	params[0].(*object.Object).FieldTable[FileHandle] = fld
+	obj.FieldTable[FileHandle] = fld
 
 	return nil
 }
 
 // "java/io/FileOutputStream.<init>(Ljava/lang/String;])V"
 func initFileOutputStreamStringBoolean(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("initFileOutputStreamStringBoolean", err)
+	}
 
 	// Using the argument path string, open the file for write-only.
-	pathStr := object.GoStringFromStringObject(params[1].(*object.Object))
+	pathStr, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("initFileOutputStreamStringBoolean", err)
+	}
 
 	// Get the boolean argument.
 	boolarg, ok := params[2].(int64)
@@ -195,7 +231,6 @@ func initFileOutputStreamStringBoolean(params []interface{}) interface{} {
 
 	// Open the file for write-only, yielding a file handle.
 	var osFile *os.File
-	var err error
 	if boolarg != 0 { // append: true
 		osFile, err = os.OpenFile(pathStr, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	} else {
@@ -209,20 +244,24 @@ func initFileOutputStreamStringBoolean(params []interface{}) interface{} {
 
 	// Copy the file path field into the FileOutputStream object.
 	fld := object.Field{Ftype: types.ByteArray, Fvalue: []byte(pathStr)}
-	params[0].(*object.Object).FieldTable[FilePath] = fld
+	obj.FieldTable[FilePath] = fld
 
 	// Copy the file handle into the FileOutputStream object.
 	fld = object.Field{Ftype: types.FileHandle, Fvalue: osFile}
-	params[0].(*object.Object).FieldTable[FileHandle] = fld
+	obj.FieldTable[FileHandle] = fld
 
 	return nil
 }
 
 // "java/io/FileOutputStream.write(I)"
 func fosWriteOne(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("fosWriteOne", err)
+	}
 
 	// Get file handle.
-	osFile, ok := params[0].(*object.Object).FieldTable[FileHandle].Fvalue.(*os.File)
+	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
 	if !ok {
 		errMsg := "fosWriteOne: FileOutputStream object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -240,7 +279,7 @@ func fosWriteOne(params []interface{}) interface{} {
 	buffer[0] = byte(wint % 256)
 
 	// Write one byte.
-	_, err := osFile.Write(buffer)
+	_, err = osFile.Write(buffer)
 	if err != nil {
 		errMsg := fmt.Sprintf("fosWriteOne: osFile.Write failed, reason: %s", err.Error())
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -251,16 +290,24 @@ func fosWriteOne(params []interface{}) interface{} {
 
 // "java/io/FileOutputStream.write([B)I"
 func fosWriteByteArray(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("fosWriteByteArray", err)
+	}
 
 	// Get file handle.
-	osFile, ok := params[0].(*object.Object).FieldTable[FileHandle].Fvalue.(*os.File)
+	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
 	if !ok {
 		errMsg := "fosWriteByteArray: FileOutputStream object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
 	// Set buffer to the byte array parameter.
-	javaBytes, ok := params[1].(*object.Object).FieldTable["value"].Fvalue.([]types.JavaByte)
+	arrayObj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("fosWriteByteArray", err)
+	}
+	javaBytes, ok := arrayObj.FieldTable["value"].Fvalue.([]types.JavaByte)
 	if !ok {
 		errMsg := "fosWriteByteArray: Byte array parameter lacks a \"value\" field"
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -268,7 +315,7 @@ func fosWriteByteArray(params []interface{}) interface{} {
 	buffer := object.GoByteArrayFromJavaByteArray(javaBytes)
 
 	// Write the buffer.
-	_, err := osFile.Write(buffer)
+	_, err = osFile.Write(buffer)
 	if err != nil {
 		errMsg := fmt.Sprintf("fosWriteByteArray: osFile.Write failed, reason: %s", err.Error())
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -279,16 +326,24 @@ func fosWriteByteArray(params []interface{}) interface{} {
 
 // "java/io/FileOutputStream.write([BII)I"
 func fosWriteByteArrayOffset(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("fosWriteByteArrayOffset", err)
+	}
 
 	// Get the file handle.
-	osFile, ok := params[0].(*object.Object).FieldTable[FileHandle].Fvalue.(*os.File)
+	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
 	if !ok {
 		errMsg := "fosWriteByteArrayOffset: FileOutputStream object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
 	// Set buffer (buf1) to the byte array parameter.
-	javaBytes, ok := params[1].(*object.Object).FieldTable["value"].Fvalue.([]types.JavaByte)
+	arrayObj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("fosWriteByteArrayOffset", err)
+	}
+	javaBytes, ok := arrayObj.FieldTable["value"].Fvalue.([]types.JavaByte)
 	if !ok {
 		errMsg := "fosWriteByteArrayOffset: Byte array parameter lacks a \"value\" field"
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -296,8 +351,14 @@ func fosWriteByteArrayOffset(params []interface{}) interface{} {
 	buf1 := object.GoByteArrayFromJavaByteArray(javaBytes)
 
 	// Collect the offset and length parameter values.
-	offset := params[2].(int64)
-	length := params[3].(int64)
+	offset, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("fosWriteByteArrayOffset", err)
+	}
+	length, err := args.GetInt64(params, 3)
+	if err != nil {
+		return getArgsGErrBlk("fosWriteByteArrayOffset", err)
+	}
 
 	// Check the parameters.
 	if length == 0 {
@@ -310,7 +371,7 @@ func fosWriteByteArrayOffset(params []interface{}) interface{} {
 	}
 
 	// Write the byte buffer.
-	_, err := osFile.Write(buf1[offset : offset+length])
+	_, err = osFile.Write(buf1[offset : offset+length])
 	if err != nil {
 		errMsg := fmt.Sprintf("fosWriteByteArrayOffset: osFile.Write failed, reason: %s", err.Error())
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -321,16 +382,20 @@ func fosWriteByteArrayOffset(params []interface{}) interface{} {
 
 // "java/io/FileOutputStream.close()V"
 func fosClose(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("fosClose", err)
+	}
 
 	// Get file handle.
-	osFile, ok := params[0].(*object.Object).FieldTable[FileHandle].Fvalue.(*os.File)
+	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
 	if !ok {
 		errMsg := "fosClose: FileOutputStream object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
 	// Close the file.
-	err := osFile.Close()
+	err = osFile.Close()
 	if err != nil {
 		errMsg := fmt.Sprintf("fosClose: osFile.Close() failed, reason: %s", err.Error())
 		return getGErrBlk(excNames.IOException, errMsg)
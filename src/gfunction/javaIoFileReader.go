@@ -9,6 +9,7 @@ package gfunction
 import (
 	"fmt"
 	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
 	"jacobin/src/object"
 	"jacobin/src/types"
 	"os"
@@ -60,7 +61,15 @@ func Load_Io_FileReader() {
 
 // "java/io/FileReader.<init>(Ljava/io/File;])V"
 func initFileReader(params []interface{}) interface{} {
-	fld1, ok := params[1].(*object.Object).FieldTable[FilePath]
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("initFileReader", err)
+	}
+	fileObj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("initFileReader", err)
+	}
+	fld1, ok := fileObj.FieldTable[FilePath]
 	if !ok {
 		errMsg := "initFileReader: File object lacks a FilePath field"
 		return getGErrBlk(excNames.InvalidTypeException, errMsg)
@@ -74,18 +83,25 @@ func initFileReader(params []interface{}) interface{} {
 
 	// Copy java/io/File path
 	fld := fld1
-	params[0].(*object.Object).FieldTable[FilePath] = fld
+	obj.FieldTable[FilePath] = fld
 
 	// Field FileHandle = Golang *os.File from os.Open
 	fld = object.Field{Ftype: types.Ref, Fvalue: osFile}
-	params[0].(*object.Object).FieldTable[FileHandle] = fld
+	obj.FieldTable[FileHandle] = fld
 
 	return nil
 }
 
 // "java/io/FileReader.<init>(Ljava/lang/String;])V"
 func initFileReaderString(params []interface{}) interface{} {
-	pathStr := object.GoStringFromStringObject(params[1].(*object.Object))
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("initFileReaderString", err)
+	}
+	pathStr, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("initFileReaderString", err)
+	}
 	osFile, err := os.Open(pathStr)
 	if err != nil {
 		errMsg := fmt.Sprintf("initFileReaderString: os.Open(%s) failed, reason: %s", pathStr, err.Error())
@@ -94,11 +110,11 @@ func initFileReaderString(params []interface{}) interface{} {
 
 	// Copy java/io/File path
 	fld := object.Field{Ftype: types.ByteArray, Fvalue: object.JavaByteArrayFromGoString(pathStr)}
-	params[0].(*object.Object).FieldTable[FilePath] = fld
+	obj.FieldTable[FilePath] = fld
 
 	// Field FileHandle = Golang *os.File
 	fld = object.Field{Ftype: types.FileHandle, Fvalue: osFile}
-	params[0].(*object.Object).FieldTable[FileHandle] = fld
+	obj.FieldTable[FileHandle] = fld
 
 	return nil
 }
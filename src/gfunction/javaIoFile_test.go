@@ -3,9 +3,11 @@ package gfunction
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"jacobin/src/excNames"
+	"jacobin/src/globals"
 	"jacobin/src/object"
 	"jacobin/src/types"
 )
@@ -315,3 +317,50 @@ func TestFileCreate_Failure(t *testing.T) {
 		t.Errorf("Expected JavaBoolFalse (0) on failure to create file, got %#v", res)
 	}
 }
+
+func TestFileCreateTempFile_InDirectory(t *testing.T) {
+	globals.InitStringPool()
+	dir := t.TempDir()
+	dirFile := makeFileObject(dir)
+	res := fileCreateTempFile([]interface{}{object.StringObjectFromGoString("jac"), object.Null, dirFile})
+	fileObj, ok := res.(*object.Object)
+	if !ok {
+		t.Fatalf("Expected a File, got %v", res)
+	}
+	pathStr := object.GoStringFromJavaByteArray(fileObj.FieldTable[FilePath].Fvalue.([]types.JavaByte))
+	name := filepath.Base(pathStr)
+	if filepath.Dir(pathStr) != dir || !strings.HasPrefix(name, "jac") || !strings.HasSuffix(name, ".tmp") {
+		t.Errorf("Unexpected temp file path %q", pathStr)
+	}
+	if info, err := os.Stat(pathStr); err != nil || info.Size() != 0 || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected an empty file only its owner can use, got %v, %v", info, err)
+	}
+
+	res = fileCreateTempFile([]interface{}{object.StringObjectFromGoString("ab"), object.Null})
+	if gErr, ok := res.(*GErrBlk); !ok || gErr.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("Expected IllegalArgumentException for a short prefix, got %v", res)
+	}
+	res = fileCreateTempFile([]interface{}{object.StringObjectFromGoString("abc"), object.Null,
+		makeFileObject(filepath.Join(dir, "missing"))})
+	if gErr, ok := res.(*GErrBlk); !ok || gErr.ExceptionType != excNames.IOException {
+		t.Errorf("Expected IOException for a missing directory, got %v", res)
+	}
+}
+
+func TestFileDeleteOnExit(t *testing.T) {
+	globals.InitStringPool()
+	dir := filepath.Join(t.TempDir(), "work")
+	_ = os.Mkdir(dir, 0755)
+	file := filepath.Join(dir, "out.txt")
+	_ = os.WriteFile(file, nil, 0644)
+
+	// the directory is recorded first, so it must be deleted last
+	fileDeleteOnExit([]interface{}{makeFileObject(dir)})
+	fileDeleteOnExit([]interface{}{makeFileObject(file)})
+	fileDeleteOnExit([]interface{}{makeFileObject(dir)})
+	deleteFilesOnExit()
+
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected the directory and its file to be deleted, got %v", err)
+	}
+}
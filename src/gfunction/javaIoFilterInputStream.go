@@ -9,6 +9,7 @@ package gfunction
 import (
 	"fmt"
 	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
 	"jacobin/src/object"
 	"jacobin/src/types"
 	"os"
@@ -92,9 +93,17 @@ func Load_Io_FilterInputStream() {
 
 // "java/io/FilterInputStream.<init>(Ljava/io/File;])V"
 func initFilterInputStreamFile(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("initFilterInputStreamFile", err)
+	}
 
 	// Get file path field from the File argument.
-	fld, ok := params[1].(*object.Object).FieldTable[FilePath]
+	fileObj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("initFilterInputStreamFile", err)
+	}
+	fld, ok := fileObj.FieldTable[FilePath]
 	if !ok {
 		errMsg := "initFilterInputStreamFile: File object argument lacks a FilePath field"
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -111,20 +120,27 @@ func initFilterInputStreamFile(params []interface{}) interface{} {
 	}
 
 	// Copy the file path field into the FilterInputStream object.
-	params[0].(*object.Object).FieldTable[FilePath] = fld
+	obj.FieldTable[FilePath] = fld
 
 	// Copy the file handle into the FilterInputStream object.
 	fld = object.Field{Ftype: types.FileHandle, Fvalue: osFile}
-	params[0].(*object.Object).FieldTable[FileHandle] = fld
+	obj.FieldTable[FileHandle] = fld
 
 	return nil
 }
 
 // "java/io/FilterInputStream.<init>(Ljava/lang/String;])V"
 func initFilterInputStreamString(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("initFilterInputStreamString", err)
+	}
 
 	// Using the argument path string, open the file for read-only.
-	pathStr := object.GoStringFromStringObject(params[1].(*object.Object))
+	pathStr, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("initFilterInputStreamString", err)
+	}
 	osFile, err := os.Open(pathStr)
 	if err != nil {
 		errMsg := fmt.Sprintf("initFilterInputStreamString: os.Open(%s) failed, reason: %s", pathStr, err.Error())
@@ -133,11 +149,11 @@ func initFilterInputStreamString(params []interface{}) interface{} {
 
 	// Copy the file path field into the FilterInputStream object.
 	fld := object.Field{Ftype: types.ByteArray, Fvalue: []byte(pathStr)}
-	params[0].(*object.Object).FieldTable[FilePath] = fld
+	obj.FieldTable[FilePath] = fld
 
 	// Copy the file handle into the FilterInputStream object.
 	fld = object.Field{Ftype: types.FileHandle, Fvalue: osFile}
-	params[0].(*object.Object).FieldTable[FileHandle] = fld
+	obj.FieldTable[FileHandle] = fld
 
 	return nil
 }
@@ -7,10 +7,14 @@
 package gfunction
 
 import (
+	"container/list"
 	"fmt"
 	"io"
+	"jacobin/src/charset"
 	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
 	"jacobin/src/object"
+	"jacobin/src/replay"
 	"jacobin/src/types"
 	"os"
 )
@@ -32,7 +36,7 @@ func Load_Io_InputStreamReader() {
 	MethodSignatures["java/io/InputStreamReader.<init>(Ljava/io/InputStream;Ljava/lang/String;)V"] =
 		GMeth{
 			ParamSlots: 2,
-			GFunction:  trapFunction,
+			GFunction:  inputStreamReaderInitCharsetName,
 		}
 
 	MethodSignatures["java/io/InputStreamReader.<init>(Ljava/io/InputStream;Ljava/nio/charset/Charset;)V"] =
@@ -49,26 +53,29 @@ func Load_Io_InputStreamReader() {
 
 	MethodSignatures["java/io/InputStreamReader.close()V"] =
 		GMeth{
-			ParamSlots: 0,
-			GFunction:  isrClose,
+			ParamSlots:   0,
+			GFunction:    readerWithFrameStack(isrClose),
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/io/InputStreamReader.getEncoding()Ljava/lang/String;"] =
 		GMeth{
 			ParamSlots: 0,
-			GFunction:  returnCharsetName,
+			GFunction:  isrGetEncoding,
 		}
 
 	MethodSignatures["java/io/InputStreamReader.read()I"] =
 		GMeth{
-			ParamSlots: 0,
-			GFunction:  isrReadOneChar,
+			ParamSlots:   0,
+			GFunction:    readerWithFrameStack(isrReadOneChar),
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/io/InputStreamReader.read([CII)I"] =
 		GMeth{
-			ParamSlots: 3,
-			GFunction:  isrReadCharBufferSubset,
+			ParamSlots:   3,
+			GFunction:    readerWithFrameStack(isrReadCharBufferSubset),
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/io/InputStreamReader.ready()Z"] =
@@ -81,16 +88,35 @@ func Load_Io_InputStreamReader() {
 
 // "java/io/InputStreamReader.<init>(Ljava/io/InputStream;)V"
 func inputStreamReaderInit(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("inputStreamReaderInit", err)
+	}
+
+	// A Go handle, such as System.in's, or a stream written in Java, such as one passed
+	// to System.setIn(), is read as a stream rather than reopened as a file.
+	if osFile, ok := params[1].(*os.File); ok {
+		obj.FieldTable[FileStream] = object.Field{Ftype: types.Ref, Fvalue: osFile}
+		return nil
+	}
 
 	// Get file path field.
-	fldPath, ok := params[1].(*object.Object).FieldTable[FilePath]
+	streamObj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("inputStreamReaderInit", err)
+	}
+	if _, ok := streamObj.FieldTable[FileHandle]; !ok {
+		obj.FieldTable[FileStream] = object.Field{Ftype: types.Ref, Fvalue: &javaStream{obj: streamObj}}
+		return nil
+	}
+	fldPath, ok := streamObj.FieldTable[FilePath]
 	if !ok {
 		errMsg := "inputStreamReaderInit: InputStream object lacks a FilePath field"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
 	// Get file handle field.
-	fldHandle, ok := params[1].(*object.Object).FieldTable[FileHandle]
+	fldHandle, ok := streamObj.FieldTable[FileHandle]
 	if !ok {
 		errMsg := "inputStreamReaderInit: InputStream object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -98,7 +124,7 @@ func inputStreamReaderInit(params []interface{}) interface{} {
 	osFile := fldHandle.Fvalue.(*os.File)
 
 	// Get file statistics.
-	_, err := osFile.Stat()
+	_, err = osFile.Stat()
 	if err != nil {
 		pathStr := string(fldPath.Fvalue.([]byte))
 		errMsg := fmt.Sprintf("inputStreamReaderInit: os.Stat(%s) failed, reason: %s", pathStr, err.Error())
@@ -106,26 +132,134 @@ func inputStreamReaderInit(params []interface{}) interface{} {
 	}
 
 	// Copy file path into the InputStreamReader object.
-	params[0].(*object.Object).FieldTable[FilePath] = fldPath
+	obj.FieldTable[FilePath] = fldPath
 
 	// Copy file handle into the InputStreamReader object.
-	params[0].(*object.Object).FieldTable[FileHandle] = fldHandle
+	obj.FieldTable[FileHandle] = fldHandle
 
 	return nil
 }
 
+// "java/io/InputStreamReader.<init>(Ljava/io/InputStream;Ljava/lang/String;)V"
+func inputStreamReaderInitCharsetName(params []interface{}) interface{} {
+	charsetName, err := args.GetGoString(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("inputStreamReaderInitCharsetName", err)
+	}
+	cs, gErr := charsetNamed("inputStreamReaderInitCharsetName", charsetName)
+	if gErr != nil {
+		return gErr
+	}
+	if ret := inputStreamReaderInit(params[:2]); ret != nil {
+		return ret
+	}
+	params[0].(*object.Object).FieldTable[FileCharset] = object.Field{Ftype: types.Ref, Fvalue: newReaderCharset(cs)}
+	return nil
+}
+
+// the charset of a Reader, with the chars it has decoded but that haven't been read yet
+type readerCharset struct {
+	cs      *charset.Charset
+	decoder *charset.Decoder
+	chars   []uint16
+}
+
+func newReaderCharset(cs *charset.Charset) *readerCharset {
+	return &readerCharset{cs: cs, decoder: cs.NewDecoder()}
+}
+
+// returns the charset of the Reader, which is the default charset unless one was
+// specified when the Reader was created
+func readerCharsetOf(obj *object.Object) *readerCharset {
+	if rc, ok := obj.FieldTable[FileCharset].Fvalue.(*readerCharset); ok {
+		return rc
+	}
+	rc := newReaderCharset(charset.Default())
+	obj.FieldTable[FileCharset] = object.Field{Ftype: types.Ref, Fvalue: rc}
+	return rc
+}
+
+// readerWithFrameStack returns a G function, which needs context, that calls the Reader G
+// function fn without the frame stack, on which the upcalls of a Reader on a Java stream
+// are made. An exception thrown by an upcall is thrown in place of what fn returns.
+func readerWithFrameStack(fn func([]interface{}) interface{}) func([]interface{}) interface{} {
+	return func(params []interface{}) interface{} {
+		if obj, ok := params[1].(*object.Object); ok && !object.IsNull(obj) {
+			if js, ok := obj.FieldTable[FileStream].Fvalue.(*javaStream); ok {
+				js.fs, _ = params[0].(*list.List)
+				ret := fn(params[1:])
+				if gErr := js.takeException(); gErr != nil {
+					return gErr
+				}
+				return ret
+			}
+		}
+		return fn(params[1:])
+	}
+}
+
+// readerSource returns what a Reader reads: its file, or the stream it was made on
+func readerSource(obj *object.Object) (io.Reader, bool) {
+	if osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File); ok {
+		return osFile, true
+	}
+	reader, ok := obj.FieldTable[FileStream].Fvalue.(io.Reader)
+	return reader, ok
+}
+
+// reads up to max chars from the file or stream of the Reader, decoding its bytes in the
+// Reader's charset. Returns io.EOF if there are no more chars.
+func readChars(obj *object.Object, osFile io.Reader, max int64) ([]uint16, error) {
+	rc := readerCharsetOf(obj)
+	for len(rc.chars) == 0 {
+		// a char has at least one byte, so reading no more bytes than the chars wanted
+		// never reads past them
+		inBytes := make([]byte, max)
+		nbytes, err := replay.Read(osFile, inBytes)
+		rc.chars = append(rc.chars, rc.decoder.Decode(inBytes[:nbytes])...)
+		if err == io.EOF {
+			rc.chars = append(rc.chars, rc.decoder.Flush()...)
+			if len(rc.chars) == 0 {
+				return nil, io.EOF
+			}
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	n := min(max, int64(len(rc.chars)))
+	chars := rc.chars[:n]
+	rc.chars = rc.chars[n:]
+	return chars, nil
+}
+
+// "java/io/InputStreamReader.getEncoding()Ljava/lang/String;"
+func isrGetEncoding(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("isrGetEncoding", err)
+	}
+	return object.StringObjectFromGoString(readerCharsetOf(obj).cs.Name)
+}
+
 // "java/io/InputStreamReader.close()V"
 func isrClose(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("isrClose", err)
+	}
 
 	// Get file handle.
-	osFile, ok := params[0].(*object.Object).FieldTable[FileHandle].Fvalue.(*os.File)
+	source, _ := readerSource(obj)
+	closer, ok := source.(io.Closer)
 	if !ok {
 		errMsg := "isrClose: InputStreamReader object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
 	// Close the file.
-	err := osFile.Close()
+	err = closer.Close()
 	if err != nil {
 		errMsg := fmt.Sprintf("isrClose: osFile.Close() failed, reason: %s", err.Error())
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -138,20 +272,20 @@ func isrClose(params []interface{}) interface{} {
 func isrReadOneChar(params []interface{}) interface{} {
 
 	// Get InputStream object.
-	obj := params[0].(*object.Object)
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("isrReadOneChar", err)
+	}
 
 	// Get file handle.
-	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
+	osFile, ok := readerSource(obj)
 	if !ok {
 		errMsg := "isrReadOneChar: InputStreamReader object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
-	// Need a one-byte buffer.
-	buffer := make([]byte, 1)
-
-	// Read one byte.
-	_, err := osFile.Read(buffer)
+	// Read one char.
+	chars, err := readChars(obj, osFile, 1)
 	if err == io.EOF {
 		eofSet(obj, true)
 		return int64(-1) // return -1 on EOF
@@ -161,31 +295,44 @@ func isrReadOneChar(params []interface{}) interface{} {
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
-	// Return the byte as an integer.
-	return int64(buffer[0])
+	// Return the char as an integer.
+	return int64(chars[0])
 }
 
 // "java/io/InputStreamReader.read([CII)I"
 func isrReadCharBufferSubset(params []interface{}) interface{} {
 
 	// Get InputStream object.
-	obj := params[0].(*object.Object)
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("isrReadCharBufferSubset", err)
+	}
 
 	// Get file handle.
-	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
+	osFile, ok := readerSource(obj)
 	if !ok {
 		errMsg := "isrReadCharBufferSubset: InputStreamReader object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
 	// Get the parameter buffer, offset, and length.
-	intArray, ok := params[1].(*object.Object).FieldTable["value"].Fvalue.([]int64)
+	arrayObj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("isrReadCharBufferSubset", err)
+	}
+	intArray, ok := arrayObj.FieldTable["value"].Fvalue.([]int64)
 	if !ok {
 		errMsg := "isrReadCharBufferSubset: InputStreamReader trouble with character array buffer"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
-	offset := params[2].(int64)
-	length := params[3].(int64)
+	offset, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("isrReadCharBufferSubset", err)
+	}
+	length, err := args.GetInt64(params, 3)
+	if err != nil {
+		return getArgsGErrBlk("isrReadCharBufferSubset", err)
+	}
 
 	// Check parameters.
 	if length == 0 {
@@ -197,9 +344,8 @@ func isrReadCharBufferSubset(params []interface{}) interface{} {
 		return getGErrBlk(excNames.IndexOutOfBoundsException, errMsg)
 	}
 
-	// Fill the replacement byte buffer.
-	inBytes := make([]byte, length)
-	nbytes, err := osFile.Read(inBytes)
+	// Read the chars.
+	chars, err := readChars(obj, osFile, length)
 	if err == io.EOF {
 		eofSet(obj, true)
 		return int64(-1) // return -1 on EOF
@@ -210,31 +356,39 @@ func isrReadCharBufferSubset(params []interface{}) interface{} {
 	}
 
 	// Update the parameter buffer, beginning at the offset.
-	for ii := int64(0); ii < int64(nbytes); ii++ {
-		intArray[offset+ii] = int64(inBytes[ii])
+	for ii, char := range chars {
+		intArray[offset+int64(ii)] = int64(char)
 	}
 
 	// Update the parameter buffer.
 	fld := object.Field{Ftype: types.IntArray, Fvalue: intArray}
-	params[1].(*object.Object).FieldTable["value"] = fld
+	arrayObj.FieldTable["value"] = fld
 
-	// Return the number of bytes.
-	return int64(nbytes)
+	// Return the number of chars.
+	return int64(len(chars))
 
 }
 
 // "java/io/InputStreamReader.ready()Z"
 func isrReady(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("isrReady", err)
+	}
 
 	// Get file path.
-	fldPath, ok := params[1].(*object.Object).FieldTable[FilePath]
+	streamObj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("isrReady", err)
+	}
+	fldPath, ok := streamObj.FieldTable[FilePath]
 	if !ok {
 		errMsg := "isrReady: InputStreamReader object lacks a FilePath field"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
 	// Get file handle.
-	fldHandle, ok := params[1].(*object.Object).FieldTable[FileHandle]
+	fldHandle, ok := streamObj.FieldTable[FileHandle]
 	if !ok {
 		errMsg := "isrReady: InputStreamReader object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -242,11 +396,11 @@ func isrReady(params []interface{}) interface{} {
 
 	// Copy java/io/File path field into the InputStreamReader object.
 	fld := fldPath
-	params[0].(*object.Object).FieldTable[FilePath] = fld
+	obj.FieldTable[FilePath] = fld
 
 	// Get file handle and get file statistics.
 	osFile := fldHandle.Fvalue.(*os.File)
-	_, err := osFile.Stat()
+	_, err = osFile.Stat()
 	if err != nil {
 		return int64(0) // Ready: false
 	}
@@ -1,6 +1,7 @@
 package gfunction
 
 import (
+    "jacobin/src/charset"
     "jacobin/src/excNames"
     "jacobin/src/globals"
     "jacobin/src/object"
@@ -8,6 +9,7 @@ import (
     "os"
     "path/filepath"
     "testing"
+    "unicode/utf16"
 )
 
 // helper: make an InputStream-like object that carries FilePath and FileHandle for reading
@@ -171,3 +173,68 @@ func TestInputStreamReader_Ready_And_Close(t *testing.T) {
         t.Fatalf("expected error on closing already closed file, got nil")
     }
 }
+
+func TestInputStreamReader_DecodesMultibyteChars(t *testing.T) {
+    globals.InitStringPool()
+    saved := charset.Default()
+    charset.SetDefault(charset.UTF8)
+    defer charset.SetDefault(saved)
+
+    tmpDir := os.TempDir()
+    filePath := filepath.Join(tmpDir, "isr_test5.txt")
+    if err := os.WriteFile(filePath, []byte("é€\U0001F600"), 0o644); err != nil {
+        t.Fatalf("failed to write test file: %v", err)
+    }
+    defer os.Remove(filePath)
+
+    inStreamObj := makeInputStreamObjForFile(t, filePath)
+    target := object.MakeEmptyObject()
+    if res := inputStreamReaderInit([]interface{}{target, inStreamObj}); res != nil {
+        t.Fatalf("inputStreamReaderInit returned error: %v", res)
+    }
+
+    // the supplementary character is read as a surrogate pair
+    pair := utf16.Encode([]rune{0x1F600})
+    for _, want := range []int64{0xE9, 0x20AC, int64(pair[0]), int64(pair[1]), -1} {
+        if v := isrReadOneChar([]interface{}{target}); v.(int64) != want {
+            t.Fatalf("got %X want %X", v.(int64), want)
+        }
+    }
+}
+
+func TestInputStreamReader_CharsetName(t *testing.T) {
+    globals.InitStringPool()
+    tmpDir := os.TempDir()
+    filePath := filepath.Join(tmpDir, "isr_test6.txt")
+    if err := os.WriteFile(filePath, []byte{'c', 'a', 'f', 0xE9}, 0o644); err != nil {
+        t.Fatalf("failed to write test file: %v", err)
+    }
+    defer os.Remove(filePath)
+
+    inStreamObj := makeInputStreamObjForFile(t, filePath)
+    target := object.MakeEmptyObject()
+    charsetName := object.StringObjectFromGoString("ISO-8859-1")
+    if res := inputStreamReaderInitCharsetName([]interface{}{target, inStreamObj, charsetName}); res != nil {
+        t.Fatalf("inputStreamReaderInitCharsetName returned error: %v", res)
+    }
+    if enc := isrGetEncoding([]interface{}{target}); object.GoStringFromStringObject(enc.(*object.Object)) != "ISO-8859-1" {
+        t.Fatalf("expected the encoding ISO-8859-1, got %v", enc)
+    }
+
+    dest := make([]int64, 8)
+    bufObj := &object.Object{FieldTable: map[string]object.Field{
+        "value": {Ftype: types.IntArray, Fvalue: dest},
+    }}
+    if n := isrReadCharBufferSubset([]interface{}{target, bufObj, int64(0), int64(8)}); n.(int64) != 4 {
+        t.Fatalf("expected 4 chars read, got %v", n)
+    }
+    if dest[3] != 0xE9 {
+        t.Fatalf("expected é, got %X", dest[3])
+    }
+
+    res := inputStreamReaderInitCharsetName([]interface{}{object.MakeEmptyObject(), inStreamObj,
+        object.StringObjectFromGoString("no-such-charset")})
+    if gErr, ok := res.(*GErrBlk); !ok || gErr.ExceptionType != excNames.UnsupportedEncodingException {
+        t.Fatalf("expected UnsupportedEncodingException, got %v", res)
+    }
+}
@@ -8,10 +8,13 @@ package gfunction
 
 import (
 	"fmt"
+	"jacobin/src/charset"
 	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
 	"jacobin/src/object"
 	"jacobin/src/types"
 	"os"
+	"unicode/utf16"
 )
 
 func Load_Io_OutputStreamWriter() {
@@ -28,6 +31,12 @@ func Load_Io_OutputStreamWriter() {
 			GFunction:  initOutputStreamWriter,
 		}
 
+	MethodSignatures["java/io/OutputStreamWriter.<init>(Ljava/io/OutputStream;Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  initOutputStreamWriterCharsetName,
+		}
+
 	MethodSignatures["java/io/OutputStreamWriter.close()V"] =
 		GMeth{
 			ParamSlots: 0,
@@ -40,6 +49,12 @@ func Load_Io_OutputStreamWriter() {
 			GFunction:  oswFlush,
 		}
 
+	MethodSignatures["java/io/OutputStreamWriter.getEncoding()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  oswGetEncoding,
+		}
+
 	MethodSignatures["java/io/OutputStreamWriter.write(I)V"] =
 		GMeth{
 			ParamSlots: 1,
@@ -62,12 +77,6 @@ func Load_Io_OutputStreamWriter() {
 	// Traps that do nothing but return an error
 	// -----------------------------------------
 
-	MethodSignatures["java/io/OutputStreamWriter.<init>(Ljava/io/OutputStream;Ljava/lang.String;)V"] =
-		GMeth{
-			ParamSlots: 2,
-			GFunction:  trapFunction,
-		}
-
 	MethodSignatures["java/io/OutputStreamWriter.<init>(Ljava/io/OutputStream;Ljava/nio/charset/Charset;)V"] =
 		GMeth{
 			ParamSlots: 2,
@@ -80,26 +89,28 @@ func Load_Io_OutputStreamWriter() {
 			GFunction:  trapFunction,
 		}
 
-	MethodSignatures["java/io/OutputStreamWriter.getEncoding()V"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  trapFunction,
-		}
-
 }
 
 // "java/io/OutputStreamWriter.<init>(Ljava/io/OutputStream;)V"
 func initOutputStreamWriter(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("initOutputStreamWriter", err)
+	}
 
 	// Get file path field.
-	fldPath, ok := params[1].(*object.Object).FieldTable[FilePath]
+	streamObj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("initOutputStreamWriter", err)
+	}
+	fldPath, ok := streamObj.FieldTable[FilePath]
 	if !ok {
 		errMsg := "initOutputStreamWriter: OutputStream object lacks a FilePath field"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
 	// Get file handle field.
-	fldHandle, ok := params[1].(*object.Object).FieldTable[FileHandle]
+	fldHandle, ok := streamObj.FieldTable[FileHandle]
 	if !ok {
 		errMsg := "initOutputStreamWriter: OutputStream object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -107,7 +118,7 @@ func initOutputStreamWriter(params []interface{}) interface{} {
 	osFile := fldHandle.Fvalue.(*os.File)
 
 	// Get file statistics.
-	_, err := osFile.Stat()
+	_, err = osFile.Stat()
 	if err != nil {
 		pathStr := string(fldPath.Fvalue.([]byte))
 		errMsg := fmt.Sprintf("initOutputStreamWriter: os.Stat(%s) failed, reason: %s", pathStr, err.Error())
@@ -115,25 +126,76 @@ func initOutputStreamWriter(params []interface{}) interface{} {
 	}
 
 	// Copy file path into the OutputStreamWriter object.
-	params[0].(*object.Object).FieldTable[FilePath] = fldPath
+	obj.FieldTable[FilePath] = fldPath
 
 	// Copy file handle into the OutputStreamWriter object.
-	params[0].(*object.Object).FieldTable[FileHandle] = fldHandle
+	obj.FieldTable[FileHandle] = fldHandle
 
 	return nil
 }
 
+// "java/io/OutputStreamWriter.<init>(Ljava/io/OutputStream;Ljava/lang/String;)V"
+func initOutputStreamWriterCharsetName(params []interface{}) interface{} {
+	charsetName, err := args.GetGoString(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("initOutputStreamWriterCharsetName", err)
+	}
+	cs, gErr := charsetNamed("initOutputStreamWriterCharsetName", charsetName)
+	if gErr != nil {
+		return gErr
+	}
+	if ret := initOutputStreamWriter(params[:2]); ret != nil {
+		return ret
+	}
+	params[0].(*object.Object).FieldTable[FileCharset] = object.Field{Ftype: types.Ref, Fvalue: newWriterCharset(cs)}
+	return nil
+}
+
+// the charset of a Writer
+type writerCharset struct {
+	cs      *charset.Charset
+	encoder *charset.Encoder
+}
+
+func newWriterCharset(cs *charset.Charset) *writerCharset {
+	return &writerCharset{cs: cs, encoder: cs.NewEncoder()}
+}
+
+// returns the charset of the Writer, which is the default charset unless one was
+// specified when the Writer was created
+func writerCharsetOf(obj *object.Object) *writerCharset {
+	if wc, ok := obj.FieldTable[FileCharset].Fvalue.(*writerCharset); ok {
+		return wc
+	}
+	wc := newWriterCharset(charset.Default())
+	obj.FieldTable[FileCharset] = object.Field{Ftype: types.Ref, Fvalue: wc}
+	return wc
+}
+
+// "java/io/OutputStreamWriter.getEncoding()Ljava/lang/String;"
+func oswGetEncoding(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("oswGetEncoding", err)
+	}
+	return object.StringObjectFromGoString(writerCharsetOf(obj).cs.Name)
+}
+
 func oswClose(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("oswClose", err)
+	}
 
 	// Get file handle.
-	osFile, ok := params[0].(*object.Object).FieldTable[FileHandle].Fvalue.(*os.File)
+	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
 	if !ok {
 		errMsg := "oswClose: OutputStreamWriter object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
 	// Close the file.
-	err := osFile.Close()
+	err = osFile.Close()
 	if err != nil {
 		errMsg := fmt.Sprintf("oswClose: osFile.Close() failed, reason: %s", err.Error())
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -142,16 +204,20 @@ func oswClose(params []interface{}) interface{} {
 }
 
 func oswFlush(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("oswFlush", err)
+	}
 
 	// Get file handle.
-	osFile, ok := params[0].(*object.Object).FieldTable[FileHandle].Fvalue.(*os.File)
+	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
 	if !ok {
 		errMsg := "oswFlush: OutputStreamWriter object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
 	// Flush the file's buffers.
-	err := osFile.Sync()
+	err = osFile.Sync()
 	if err != nil {
 		errMsg := fmt.Sprintf("oswFlush: osFile.Sync() failed, reason: %s", err.Error())
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -163,7 +229,10 @@ func oswFlush(params []interface{}) interface{} {
 func oswWriteOneChar(params []interface{}) interface{} {
 
 	// Get OutputStream object.
-	obj := params[0].(*object.Object)
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("oswWriteOneChar", err)
+	}
 
 	// Get file handle.
 	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
@@ -179,12 +248,11 @@ func oswWriteOneChar(params []interface{}) interface{} {
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
-	// Create a one-byte buffer.
-	buffer := make([]byte, 1)
-	buffer[0] = byte(wint % 256)
+	// Encode the char, which is the low 16 bits of the integer.
+	buffer := writerCharsetOf(obj).encoder.Encode([]uint16{uint16(wint)})
 
-	// Write one byte.
-	_, err := osFile.Write(buffer)
+	// Write its bytes.
+	_, err = osFile.Write(buffer)
 	if err != nil {
 		errMsg := fmt.Sprintf("oswWriteOneChar: osFile.Write failed, reason: %s", err.Error())
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -195,22 +263,36 @@ func oswWriteOneChar(params []interface{}) interface{} {
 
 // "java/io/OutputStreamWriter.write([CII)I"
 func oswWriteCharBuffer(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("oswWriteCharBuffer", err)
+	}
 
 	// Get file handle.
-	osFile, ok := params[0].(*object.Object).FieldTable[FileHandle].Fvalue.(*os.File)
+	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
 	if !ok {
 		errMsg := "oswWriteCharBuffer: OutputStreamWriter object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
 	// Get the parameter buffer, offset, and length.
-	intArray, ok := params[1].(*object.Object).FieldTable["value"].Fvalue.([]int64)
+	arrayObj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("oswWriteCharBuffer", err)
+	}
+	intArray, ok := arrayObj.FieldTable["value"].Fvalue.([]int64)
 	if !ok {
 		errMsg := "oswWriteCharBuffer: Trouble with value field ([]int64)"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
-	offset := params[2].(int64)
-	length := params[3].(int64)
+	offset, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("oswWriteCharBuffer", err)
+	}
+	length, err := args.GetInt64(params, 3)
+	if err != nil {
+		return getArgsGErrBlk("oswWriteCharBuffer", err)
+	}
 
 	// Check parameters.
 	if length == 0 {
@@ -222,14 +304,15 @@ func oswWriteCharBuffer(params []interface{}) interface{} {
 		return getGErrBlk(excNames.IndexOutOfBoundsException, errMsg)
 	}
 
-	// Create and fill a byte buffer.
-	outBytes := make([]byte, length)
+	// Encode the chars.
+	chars := make([]uint16, length)
 	for ii := int64(0); ii < length; ii++ {
-		outBytes[ii] = byte(intArray[offset+ii])
+		chars[ii] = uint16(intArray[offset+ii])
 	}
+	outBytes := writerCharsetOf(obj).encoder.Encode(chars)
 
 	// Write the byte buffer.
-	_, err := osFile.Write(outBytes)
+	_, err = osFile.Write(outBytes)
 	if err != nil {
 		errMsg := fmt.Sprintf("oswWriteCharBuffer: osFile.Write failed, reason: %s", err.Error())
 		return getGErrBlk(excNames.IOException, errMsg)
@@ -240,42 +323,48 @@ func oswWriteCharBuffer(params []interface{}) interface{} {
 
 // "java/io/OutputStreamWriter.write(Ljava/lang/String;II)I"
 func oswWriteStringBuffer(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("oswWriteStringBuffer", err)
+	}
 
 	// Get file handle.
-	osFile, ok := params[0].(*object.Object).FieldTable[FileHandle].Fvalue.(*os.File)
+	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
 	if !ok {
 		errMsg := "oswWriteStringBuffer: OutputStreamWriter object lacks a FileHandle field"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
 
-	// Get the parameter string byte array, offset, and length.
-	javaBytes, ok := params[1].(*object.Object).FieldTable["value"].Fvalue.([]types.JavaByte)
-	if !ok {
-		errMsg := "oswWriteStringBuffer: Trouble with value field"
-		return getGErrBlk(excNames.IOException, errMsg)
+	// Get the chars of the parameter string, offset, and length.
+	str, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("oswWriteStringBuffer", err)
+	}
+	chars := utf16.Encode([]rune(str))
+	offset, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("oswWriteStringBuffer", err)
+	}
+	length, err := args.GetInt64(params, 3)
+	if err != nil {
+		return getArgsGErrBlk("oswWriteStringBuffer", err)
 	}
-	paramBytes := object.GoByteArrayFromJavaByteArray(javaBytes)
-	offset := params[2].(int64)
-	length := params[3].(int64)
 
 	// Check parameters.
 	if length == 0 {
 		return int64(0)
 	}
-	if length < 0 || offset < 0 || length > (int64(len(paramBytes))-offset) {
+	if length < 0 || offset < 0 || length > (int64(len(chars))-offset) {
 		errMsg := fmt.Sprintf("oswWriteStringBuffer: Error in parameters: offset=%d, length=%d, char.array.length=%d",
-			offset, length, len(paramBytes))
+			offset, length, len(chars))
 		return getGErrBlk(excNames.IndexOutOfBoundsException, errMsg)
 	}
 
-	// Create and fill a byte buffer.
-	outBytes := make([]byte, length)
-	for ii := int64(0); ii < length; ii++ {
-		outBytes[ii] = paramBytes[offset+ii]
-	}
+	// Encode the chars.
+	outBytes := writerCharsetOf(obj).encoder.Encode(chars[offset : offset+length])
 
 	// Write the byte buffer.
-	_, err := osFile.Write(outBytes)
+	_, err = osFile.Write(outBytes)
 	if err != nil {
 		errMsg := fmt.Sprintf("oswWriteStringBuffer: osFile.Write failed, reason: %s", err.Error())
 		return getGErrBlk(excNames.IOException, errMsg)
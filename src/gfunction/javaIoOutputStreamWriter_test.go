@@ -1,6 +1,7 @@
 package gfunction
 
 import (
+    "jacobin/src/charset"
     "jacobin/src/excNames"
     "jacobin/src/globals"
     "jacobin/src/object"
@@ -8,6 +9,7 @@ import (
     "os"
     "path/filepath"
     "testing"
+    "unicode/utf16"
 )
 
 // helper: make an OutputStream-like object that carries FilePath and FileHandle
@@ -158,3 +160,79 @@ func TestOutputStreamWriter_WriteStringBuffer(t *testing.T) {
 
     _ = oswClose([]interface{}{target})
 }
+
+func TestOutputStreamWriter_EncodesInCharset(t *testing.T) {
+    globals.InitStringPool()
+    tmpDir := os.TempDir()
+    filePath := filepath.Join(tmpDir, "osw_test5.txt")
+    defer os.Remove(filePath)
+
+    outStreamObj := makeOutputStreamObjForFile(t, filePath)
+    target := object.MakeEmptyObject()
+    charsetName := object.StringObjectFromGoString("ISO-8859-1")
+    if res := initOutputStreamWriterCharsetName([]interface{}{target, outStreamObj, charsetName}); res != nil {
+        t.Fatalf("initOutputStreamWriterCharsetName returned error: %v", res)
+    }
+    if enc := oswGetEncoding([]interface{}{target}); object.GoStringFromStringObject(enc.(*object.Object)) != "ISO-8859-1" {
+        t.Fatalf("expected the encoding ISO-8859-1, got %v", enc)
+    }
+
+    // "é€" as a String, then as chars: the euro sign isn't in ISO-8859-1
+    if res := oswWriteStringBuffer([]interface{}{target, object.StringObjectFromGoString("é€"), int64(0), int64(2)}); res != nil {
+        t.Fatalf("oswWriteStringBuffer error: %v", res)
+    }
+    charArr := &object.Object{FieldTable: map[string]object.Field{
+        "value": {Ftype: types.IntArray, Fvalue: []int64{int64('ü')}},
+    }}
+    if res := oswWriteCharBuffer([]interface{}{target, charArr, int64(0), int64(1)}); res != nil {
+        t.Fatalf("oswWriteCharBuffer error: %v", res)
+    }
+
+    bytes, err := os.ReadFile(filePath)
+    if err != nil {
+        t.Fatalf("ReadFile failed: %v", err)
+    }
+    if string(bytes) != "\xE9?\xFC" {
+        t.Fatalf("content mismatch: got % X want E9 3F FC", bytes)
+    }
+    _ = oswClose([]interface{}{target})
+
+    res := initOutputStreamWriterCharsetName([]interface{}{object.MakeEmptyObject(), outStreamObj,
+        object.StringObjectFromGoString("no-such-charset")})
+    if gErr, ok := res.(*GErrBlk); !ok || gErr.ExceptionType != excNames.UnsupportedEncodingException {
+        t.Fatalf("expected UnsupportedEncodingException, got %v", res)
+    }
+}
+
+func TestOutputStreamWriter_DefaultCharsetUTF8(t *testing.T) {
+    globals.InitStringPool()
+    tmpDir := os.TempDir()
+    filePath := filepath.Join(tmpDir, "osw_test6.txt")
+    defer os.Remove(filePath)
+
+    saved := charset.Default()
+    charset.SetDefault(charset.UTF8)
+    defer charset.SetDefault(saved)
+
+    outStreamObj := makeOutputStreamObjForFile(t, filePath)
+    target := object.MakeEmptyObject()
+    if res := initOutputStreamWriter([]interface{}{target, outStreamObj}); res != nil {
+        t.Fatalf("initOutputStreamWriter returned error: %v", res)
+    }
+
+    // a surrogate pair written a char at a time
+    for _, char := range utf16.Encode([]rune{0x1F600}) {
+        if res := oswWriteOneChar([]interface{}{target, int64(char)}); res != nil {
+            t.Fatalf("oswWriteOneChar error: %v", res)
+        }
+    }
+
+    bytes, err := os.ReadFile(filePath)
+    if err != nil {
+        t.Fatalf("ReadFile failed: %v", err)
+    }
+    if string(bytes) != "\U0001F600" {
+        t.Fatalf("content mismatch: got % X want F0 9F 98 80", bytes)
+    }
+    _ = oswClose([]interface{}{target})
+}
@@ -7,12 +7,15 @@
 package gfunction
 
 import (
+	"container/list"
 	"fmt"
 	"io"
 	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
+	"jacobin/src/globals"
 	"jacobin/src/object"
 	"jacobin/src/types"
-	"strconv"
+	"strings"
 )
 
 /*
@@ -249,6 +252,66 @@ func Load_Io_PrintStream() {
 			GFunction:  Printf,
 		}
 
+	// Each method prints to whatever stream it's invoked on, which needn't be a Go handle:
+	// it can be a PrintStream made in Java, such as one passed to System.setOut().
+	for key, gmeth := range MethodSignatures {
+		if strings.HasPrefix(key, "java/io/PrintStream.") && !gmeth.NeedsContext {
+			gmeth.GFunction = printStreamTarget(gmeth.GFunction)
+			gmeth.NeedsContext = true
+			MethodSignatures[key] = gmeth
+		}
+	}
+}
+
+// printStreamTarget returns a G function, which needs context, that calls the PrintStream
+// G function fn with the io.Writer of the stream in place of the frame stack and the stream
+func printStreamTarget(fn func([]interface{}) interface{}) func([]interface{}) interface{} {
+	return func(params []interface{}) interface{} {
+		fs, _ := params[0].(*list.List)
+		stream := params[1]
+		var target interface{} = stream
+		if writer := printStreamWriter(fs, stream); writer != nil {
+			target = writer
+		}
+
+		ret := fn(append([]interface{}{target}, params[2:]...))
+		if js, ok := target.(*javaStream); ok && js.gErr != nil {
+			return js.takeException()
+		}
+		if ret == target { // printf() returns the stream
+			return stream
+		}
+		return ret
+	}
+}
+
+// printStreamWriter returns the io.Writer to which a PrintStream G function prints: a Go
+// handle, such as the *os.File that System.out and System.err start as, is its own
+// writer, and so is the one that a java/io/PrintStream was made on. Any other PrintStream
+// is written to by upcalls of its write([BII)V method, which a subclass may override.
+// Returns nil if the stream is neither.
+func printStreamWriter(fs *list.List, stream interface{}) io.Writer {
+	switch stream := stream.(type) {
+	case io.Writer:
+		return stream
+	case *object.Object:
+		if object.IsNull(stream) {
+			return nil
+		}
+		if object.GoStringFromStringPoolIndex(stream.KlassName) == "java/io/PrintStream" {
+			if out, ok := stream.FieldTable["out"].Fvalue.(io.Writer); ok {
+				return out
+			}
+		}
+		return &javaStream{fs: fs, obj: stream}
+	}
+	return nil
+}
+
+// writes the value and then the line separator, which is "\r\n" on Windows unless
+// -Dline.separator sets another
+func printLine(writer io.Writer, value any) {
+	fmt.Fprint(writer, value, globals.GetLineSeparator())
 }
 
 // PrintlnV = java/io/Prinstream.println() -- println() prints a newline (V = void)
@@ -259,7 +322,7 @@ func PrintlnV(params []interface{}) interface{} {
 		errMsg := fmt.Sprintf("PrintlnV: Expected io.Writer, observed %T", params[0])
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
-	fmt.Fprintln(writer, "")
+	printLine(writer, "")
 	return nil
 }
 
@@ -270,8 +333,12 @@ func PrintlnChar(params []interface{}) interface{} {
 		errMsg := fmt.Sprintf("PrintlnChar: Expected io.Writer, observed %T", params[0])
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
-	bb := byte(params[1].(int64))
-	fmt.Fprintln(writer, string(bb))
+	ch, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("PrintlnChar", err)
+	}
+	bb := byte(ch)
+	printLine(writer, string(bb))
 	return nil
 }
 
@@ -284,11 +351,11 @@ func PrintlnBIS(params []interface{}) interface{} {
 		errMsg := fmt.Sprintf("PrintlnBIS: Expected io.Writer, observed %T", params[0])
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
-	intToPrint, ok := params[1].(int64) // contains an int
-	if !ok {
-		intToPrint = int64(params[1].(int8))
+	intToPrint, err := args.GetInt64(params, 1) // contains an int, or a byte as an int8
+	if err != nil {
+		return getArgsGErrBlk("PrintlnBIS", err)
 	}
-	fmt.Fprintln(writer, intToPrint)
+	printLine(writer, intToPrint)
 	return nil
 }
 
@@ -300,13 +367,16 @@ func PrintlnBoolean(params []interface{}) interface{} {
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
 	var boolToPrint bool
-	boolAsInt64 := params[1].(int64) // contains an int64
+	boolAsInt64, err := args.GetInt64(params, 1) // contains an int64
+	if err != nil {
+		return getArgsGErrBlk("PrintlnBoolean", err)
+	}
 	if boolAsInt64 > 0 {
 		boolToPrint = true
 	} else {
 		boolToPrint = false
 	}
-	fmt.Fprintln(writer, boolToPrint)
+	printLine(writer, boolToPrint)
 	return nil
 }
 
@@ -317,8 +387,11 @@ func PrintlnLong(params []interface{}) interface{} {
 		errMsg := fmt.Sprintf("PrintlnLong: Expected io.Writer, observed %T", params[0])
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
-	longToPrint := params[1].(int64) // contains to an int64--the equivalent of a Java long
-	fmt.Fprintln(writer, longToPrint)
+	longToPrint, err := args.GetInt64(params, 1) // contains to an int64--the equivalent of a Java long
+	if err != nil {
+		return getArgsGErrBlk("PrintlnLong", err)
+	}
+	printLine(writer, longToPrint)
 	return nil
 }
 
@@ -329,8 +402,11 @@ func PrintlnDouble(params []interface{}) interface{} {
 		errMsg := fmt.Sprintf("PrintlnDouble: Expected io.Writer, observed %T", params[0])
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
-	xx := params[1].(float64) // contains to a float64--the equivalent of a Java double
-	fmt.Fprintln(writer, strconv.FormatFloat(xx, 'g', -1, 64))
+	xx, err := args.GetFloat64(params, 1) // contains to a float64--the equivalent of a Java double
+	if err != nil {
+		return getArgsGErrBlk("PrintlnDouble", err)
+	}
+	printLine(writer, object.JavaDoubleString(xx))
 	return nil
 }
 
@@ -341,8 +417,11 @@ func PrintlnFloat(params []interface{}) interface{} {
 		errMsg := fmt.Sprintf("PrintlnFloat: Expected io.Writer, observed %T", params[0])
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
-	xx := params[1].(float64) // contains to a float64--the equivalent of a Java double
-	fmt.Fprintln(writer, strconv.FormatFloat(xx, 'g', -1, 32))
+	xx, err := args.GetFloat64(params, 1) // contains to a float64--the equivalent of a Java double
+	if err != nil {
+		return getArgsGErrBlk("PrintlnFloat", err)
+	}
+	printLine(writer, object.JavaFloatString(xx))
 	return nil
 }
 
@@ -353,7 +432,11 @@ func PrintChar(params []interface{}) interface{} {
 		errMsg := fmt.Sprintf("PrintChar: Expected io.Writer, observed %T", params[0])
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
-	bb := byte(params[1].(int64))
+	ch, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("PrintChar", err)
+	}
+	bb := byte(ch)
 	fmt.Fprint(writer, string(bb))
 	return nil
 }
@@ -367,9 +450,9 @@ func PrintBIS(params []interface{}) interface{} {
 		errMsg := fmt.Sprintf("PrintBIS: Expected io.Writer, observed %T", params[0])
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
-	intToPrint, ok := params[1].(int64) // contains an int
-	if !ok {
-		intToPrint = int64(params[1].(int8))
+	intToPrint, err := args.GetInt64(params, 1) // contains an int, or a byte as an int8
+	if err != nil {
+		return getArgsGErrBlk("PrintBIS", err)
 	}
 	fmt.Fprint(writer, intToPrint)
 	return nil
@@ -384,7 +467,10 @@ func PrintBoolean(params []interface{}) interface{} {
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
 	var boolToPrint bool
-	boolAsInt64 := params[1].(int64) // contains an int64
+	boolAsInt64, err := args.GetInt64(params, 1) // contains an int64
+	if err != nil {
+		return getArgsGErrBlk("PrintBoolean", err)
+	}
 	if boolAsInt64 > 0 {
 		boolToPrint = true
 	} else {
@@ -403,7 +489,10 @@ func PrintLong(params []interface{}) interface{} {
 		errMsg := fmt.Sprintf("PrintLong: Expected io.Writer, observed %T", params[0])
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
-	longToPrint := params[1].(int64) // contains to an int64--the equivalent of a Java long
+	longToPrint, err := args.GetInt64(params, 1) // contains to an int64--the equivalent of a Java long
+	if err != nil {
+		return getArgsGErrBlk("PrintLong", err)
+	}
 	fmt.Fprint(writer, longToPrint)
 	return nil
 }
@@ -415,8 +504,11 @@ func PrintDouble(params []interface{}) interface{} {
 		errMsg := fmt.Sprintf("PrintDouble: Expected io.Writer, observed %T", params[0])
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
-	xx := params[1].(float64) // contains to a float64--the equivalent of a Java double
-	fmt.Fprint(writer, strconv.FormatFloat(xx, 'g', -1, 64))
+	xx, err := args.GetFloat64(params, 1) // contains to a float64--the equivalent of a Java double
+	if err != nil {
+		return getArgsGErrBlk("PrintDouble", err)
+	}
+	fmt.Fprint(writer, object.JavaDoubleString(xx))
 	return nil
 }
 
@@ -427,8 +519,11 @@ func PrintFloat(params []interface{}) interface{} {
 		errMsg := fmt.Sprintf("PrintFloat: Expected io.Writer, observed %T", params[0])
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
-	xx := params[1].(float64) // contains to a float64--the equivalent of a Java double
-	fmt.Fprint(writer, strconv.FormatFloat(xx, 'g', -1, 32))
+	xx, err := args.GetFloat64(params, 1) // contains to a float64--the equivalent of a Java double
+	if err != nil {
+		return getArgsGErrBlk("PrintFloat", err)
+	}
+	fmt.Fprint(writer, object.JavaFloatString(xx))
 	return nil
 }
 
@@ -467,10 +562,9 @@ func _printString(params []interface{}, newLine bool) interface{} {
 	}
 
 	var str string
-	param1, ok := params[1].(*object.Object)
-	if !ok {
-		errMsg := fmt.Sprintf("_printString: Expected params[1] of type *object.Object but observed type %T\n", params[1])
-		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	param1, err := args.GetObjectOrNull(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("_printString", err)
 	}
 
 	// Handle null strings.
@@ -499,7 +593,7 @@ func _printString(params []interface{}, newLine bool) interface{} {
 	}
 
 	if newLine {
-		fmt.Fprintln(writer, str)
+		printLine(writer, str)
 	} else {
 		fmt.Fprint(writer, str)
 	}
@@ -546,7 +640,7 @@ func _printObject(params []interface{}, newLine bool) interface{} {
 			}
 			strBuffer = strBuffer[:len(strBuffer)-2] + "}"
 			if newLine {
-				fmt.Fprintln(writer, strBuffer)
+				printLine(writer, strBuffer)
 				return nil
 			} else {
 				fmt.Fprint(writer, strBuffer)
@@ -559,7 +653,7 @@ func _printObject(params []interface{}, newLine bool) interface{} {
 	}
 
 	if newLine {
-		fmt.Fprintln(writer, strBuffer)
+		printLine(writer, strBuffer)
 	} else {
 		fmt.Fprint(writer, strBuffer)
 	}
@@ -582,7 +676,11 @@ func PrintObject(params []interface{}) interface{} {
 	}
 
 	// Check for linked list object.
-	if object.GoStringFromStringPoolIndex(params[1].(*object.Object).KlassName) == classNameLinkedList {
+	obj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("PrintObject", err)
+	}
+	if object.GoStringFromStringPoolIndex(obj.KlassName) == classNameLinkedList {
 		return _printLinkedList(params, false)
 	}
 
@@ -600,12 +698,16 @@ func PrintlnObject(params []interface{}) interface{} {
 			errMsg := fmt.Sprintf("PrintlnObject: Expected io.Writer, observed %T", params[0])
 			return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 		}
-		fmt.Fprintln(writer, types.NullString)
+		printLine(writer, types.NullString)
 		return nil
 	}
 
 	// Check for linked list object.
-	if object.GoStringFromStringPoolIndex(params[1].(*object.Object).KlassName) == classNameLinkedList {
+	obj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("PrintlnObject", err)
+	}
+	if object.GoStringFromStringPoolIndex(obj.KlassName) == classNameLinkedList {
 		return _printLinkedList(params, true)
 	}
 
@@ -624,10 +726,9 @@ func _printLinkedList(params []interface{}, newLine bool) interface{} {
 	var strBuffer string
 
 	// Get linked list object.
-	param1, ok := params[1].(*object.Object)
-	if !ok {
-		errMsg := fmt.Sprintf("_printLinkedList: Expected params[1] of type *object.Object but observed type %T\n", params[1])
-		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	param1, err := args.GetObjectOrNull(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("_printLinkedList", err)
 	}
 
 	// Handle null LinkedList objects.
@@ -663,7 +764,7 @@ func _printLinkedList(params []interface{}, newLine bool) interface{} {
 	strBuffer = strBuffer[:len(strBuffer)-2] + "]"
 
 	if newLine {
-		fmt.Fprintln(writer, strBuffer)
+		printLine(writer, strBuffer)
 	} else {
 		fmt.Fprint(writer, strBuffer)
 	}
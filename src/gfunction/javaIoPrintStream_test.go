@@ -203,3 +203,17 @@ func TestPrintStreamPrintObjectNull(t *testing.T) {
 		t.Errorf("PrintlnObject(nil) output = %q; want 'null\\n'", got)
 	}
 }
+
+func TestPrintStreamPrintlnLineSeparator(t *testing.T) {
+	saved := globals.GetLineSeparator()
+	t.Cleanup(func() { globals.SetLineSeparator(saved) })
+	globals.SetLineSeparator("\r\n")
+
+	buf := new(bytes.Buffer)
+	gfunction.PrintlnString([]interface{}{buf, makeStringObject("hello")})
+	gfunction.PrintlnBIS([]interface{}{buf, int64(42)})
+	gfunction.PrintlnV([]interface{}{buf})
+	if got := buf.String(); got != "hello\r\n42\r\n\r\n" {
+		t.Errorf("println() wrote %q; want each line ended by \\r\\n", got)
+	}
+}
@@ -7,13 +7,23 @@
 package gfunction
 
 import (
+	"encoding/binary"
 	"fmt"
+	"io"
 	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
 	"jacobin/src/object"
+	"jacobin/src/replay"
 	"jacobin/src/types"
+	"math"
 	"os"
+	"unicode/utf16"
 )
 
+// A RandomAccessFile reads and writes an os.File at its file pointer, which is the
+// offset of the os.File. As in java.io.DataInput and DataOutput, the primitives are
+// big-endian and the strings of readUTF() and writeUTF() are in modified UTF-8.
+
 func Load_Io_RandomAccessFile() {
 
 	MethodSignatures["java/io/RandomAccessFile.<clinit>()V"] =
@@ -40,12 +50,24 @@ func Load_Io_RandomAccessFile() {
 			GFunction:  fisClose,
 		}
 
+	MethodSignatures["java/io/RandomAccessFile.getChannel()Ljava/nio/channels/FileChannel;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  rafGetChannel,
+		}
+
 	MethodSignatures["java/io/RandomAccessFile.getFilePointer()J"] =
 		GMeth{
 			ParamSlots: 0,
 			GFunction:  rafGetFilePointer,
 		}
 
+	MethodSignatures["java/io/RandomAccessFile.length()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  rafLength,
+		}
+
 	MethodSignatures["java/io/RandomAccessFile.read()I"] =
 		GMeth{
 			ParamSlots: 0,
@@ -55,13 +77,199 @@ func Load_Io_RandomAccessFile() {
 	MethodSignatures["java/io/RandomAccessFile.read([B)I"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  fisReadByteArray,
+			GFunction:  rafReadByteArray,
 		}
 
 	MethodSignatures["java/io/RandomAccessFile.read([BII)I"] =
 		GMeth{
 			ParamSlots: 3,
-			GFunction:  fisReadByteArrayOffset,
+			GFunction:  rafReadByteArrayOffset,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.readBoolean()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  rafReadBoolean,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.readByte()B"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  rafReadByte,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.readChar()C"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  rafReadChar,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.readDouble()D"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  rafReadDouble,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.readFloat()F"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  rafReadFloat,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.readFully([B)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  rafReadFully,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.readFully([BII)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  rafReadFullyOffset,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.readInt()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  rafReadInt,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.readLine()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  rafReadLine,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.readLong()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  rafReadLong,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.readShort()S"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  rafReadShort,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.readUnsignedByte()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  rafReadUnsignedByte,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.readUnsignedShort()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  rafReadUnsignedShort,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.readUTF()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  rafReadUTF,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.seek(J)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  rafSeek,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.setLength(J)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  rafSetLength,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.skipBytes(I)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  rafSkipBytes,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.write(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  fosWriteOne,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.write([B)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  fosWriteByteArray,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.write([BII)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  fosWriteByteArrayOffset,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.writeBoolean(Z)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  rafWriteByte,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.writeByte(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  rafWriteByte,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.writeBytes(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  rafWriteBytes,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.writeChar(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  rafWriteShort,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.writeChars(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  rafWriteChars,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.writeDouble(D)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  rafWriteDouble,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.writeFloat(F)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  rafWriteFloat,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.writeInt(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  rafWriteInt,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.writeLong(J)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  rafWriteLong,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.writeShort(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  rafWriteShort,
+		}
+
+	MethodSignatures["java/io/RandomAccessFile.writeUTF(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  rafWriteUTF,
 		}
 
 	// ----------------------------------------------------------
@@ -80,48 +288,39 @@ func Load_Io_RandomAccessFile() {
 // "java/io/RandomAccessFile.<init>(Ljava/lang/String;Ljava/lang/String;)V"
 // RandomAccessFile raf = new RandomAccessFile(Stringname, Stringmode);
 func rafInitString(params []interface{}) interface{} {
-
-	// Using the argument path string, open the file for read-only.
-	pathStr := object.GoStringFromStringObject(params[1].(*object.Object))
-
-	// Mode.
-	var modeInt int
-	modeStr := object.GoStringFromStringObject(params[2].(*object.Object))
-	switch modeStr {
-	case "r":
-		modeInt = os.O_RDONLY
-	case "rw", "rws", "rwd":
-		modeInt = os.O_RDWR | os.O_CREATE | os.O_APPEND
-	default:
-		errMsg := fmt.Sprintf("rafInitString: mode string (%s) invalid", modeStr)
-		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("rafInitString", err)
 	}
 
-	// Open the file in the specified mode.
-	osFile, err := os.OpenFile(pathStr, modeInt, CreateFilePermissions)
+	// Get the path string.
+	pathStr, err := args.GetGoString(params, 1)
 	if err != nil {
-		errMsg := fmt.Sprintf("rafInitString: os.OpenFile(%s) failed, reason: %s", pathStr, err.Error())
-		return getGErrBlk(excNames.IOException, errMsg)
+		return getArgsGErrBlk("rafInitString", err)
 	}
 
-	// Copy the file path field into the RandomAccessFile object.
-	fld := object.Field{Ftype: types.ByteArray, Fvalue: []byte(pathStr)}
-	params[0].(*object.Object).FieldTable[FilePath] = fld
-
-	// Copy the file handle into the RandomAccessFile object.
-	fld = object.Field{Ftype: types.FileHandle, Fvalue: osFile}
-	params[0].(*object.Object).FieldTable[FileHandle] = fld
-
-	return nil
+	// Mode.
+	modeStr, err := args.GetGoString(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("rafInitString", err)
+	}
 
+	return rafOpen("rafInitString", obj, pathStr, modeStr)
 }
 
 // "java/io/RandomAccessFile.<init>(Ljava/io/File;Ljava/lang/String;)V"
 // RandomAccessFile raf = new RandomAccessFile(Fileobject, Stringmode);
 func rafInitFile(params []interface{}) interface{} {
+	self, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("rafInitFile", err)
+	}
 
-	// Using the argument path string, open the file for read-only.
-	obj := params[1].(*object.Object)
+	// Get the path string from the File object.
+	obj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("rafInitFile", err)
+	}
 	fld, ok := obj.FieldTable[FilePath]
 	if !ok {
 		errMsg := "rafInitFile: java/io/File object is missing the FilePath field"
@@ -130,35 +329,65 @@ func rafInitFile(params []interface{}) interface{} {
 	pathStr := object.GoStringFromJavaByteArray(fld.Fvalue.([]types.JavaByte))
 
 	// Mode.
+	modeStr, err := args.GetGoString(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("rafInitFile", err)
+	}
+
+	return rafOpen("rafInitFile", self, pathStr, modeStr)
+}
+
+// Opens the file in the mode given: "r" to read an existing file, and "rw" to read and
+// write it, creating it if need be. "rws" and "rwd" are "rw" with every write synchronized
+// to the device.
+func rafOpen(funcName string, obj *object.Object, pathStr string, modeStr string) interface{} {
 	var modeInt int
-	modeStr := object.GoStringFromStringObject(params[2].(*object.Object))
 	switch modeStr {
 	case "r":
 		modeInt = os.O_RDONLY
-	case "rw", "rws", "rwd":
-		modeInt = os.O_RDWR | os.O_CREATE | os.O_APPEND
+	case "rw":
+		modeInt = os.O_RDWR | os.O_CREATE
+	case "rws", "rwd":
+		modeInt = os.O_RDWR | os.O_CREATE | os.O_SYNC
 	default:
-		errMsg := fmt.Sprintf("rafInitFile: mode string (%s) invalid", modeStr)
+		errMsg := fmt.Sprintf("%s: Illegal mode \"%s\" must be one of \"r\", \"rw\", \"rws\", or \"rwd\"",
+			funcName, modeStr)
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
 
 	// Open the file in the specified mode.
 	osFile, err := os.OpenFile(pathStr, modeInt, CreateFilePermissions)
 	if err != nil {
-		errMsg := fmt.Sprintf("rafInitFile: os.Open(%s) failed, reason: %s", pathStr, err.Error())
-		return getGErrBlk(excNames.IOException, errMsg)
+		errMsg := fmt.Sprintf("%s: os.OpenFile(%s) failed, reason: %s", funcName, pathStr, err.Error())
+		return getGErrBlk(excNames.FileNotFoundException, errMsg)
 	}
 
 	// Copy the file path field into the RandomAccessFile object.
-	fld = object.Field{Ftype: types.ByteArray, Fvalue: []byte(pathStr)}
-	params[0].(*object.Object).FieldTable[FilePath] = fld
+	fld := object.Field{Ftype: types.ByteArray, Fvalue: []byte(pathStr)}
+	obj.FieldTable[FilePath] = fld
 
 	// Copy the file handle into the RandomAccessFile object.
 	fld = object.Field{Ftype: types.FileHandle, Fvalue: osFile}
-	params[0].(*object.Object).FieldTable[FileHandle] = fld
+	obj.FieldTable[FileHandle] = fld
+
+	// Record the mode, which decides whether the file's channel may be written.
+	obj.FieldTable[FileMode] = object.Field{Ftype: types.GolangString, Fvalue: modeStr}
 
 	return nil
+}
 
+// Returns the file handle of the RandomAccessFile object in params[0].
+func rafFile(funcName string, params []interface{}) (*os.File, *GErrBlk) {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return nil, getArgsGErrBlk(funcName, err)
+	}
+	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
+	if !ok {
+		errMsg := fmt.Sprintf("%s: java/io/RandomAccessFile object is missing the FileHandle field", funcName)
+		return nil, getGErrBlk(excNames.IOException, errMsg)
+	}
+	return osFile, nil
 }
 
 // "java/io/RandomAccessFile.getFilePointer()J"
@@ -166,7 +395,10 @@ func rafInitFile(params []interface{}) interface{} {
 func rafGetFilePointer(params []interface{}) interface{} {
 
 	// Get the open file handle.
-	obj := params[0].(*object.Object)
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("rafGetFilePointer", err)
+	}
 	fld, ok := obj.FieldTable[FileHandle]
 	if !ok {
 		errMsg := "rafGetFilePointer: java/io/RandomAccessFile object is missing the FileHandle field"
@@ -184,3 +416,537 @@ func rafGetFilePointer(params []interface{}) interface{} {
 	return posn
 
 }
+
+// "java/io/RandomAccessFile.seek(J)V"
+// Set the file position, which may be beyond the end of the file: a write there
+// extends the file.
+func rafSeek(params []interface{}) interface{} {
+	osFile, gErr := rafFile("rafSeek", params)
+	if gErr != nil {
+		return gErr
+	}
+	posn, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("rafSeek", err)
+	}
+	if posn < 0 {
+		return getGErrBlk(excNames.IOException, "rafSeek: Negative seek offset")
+	}
+
+	_, err = osFile.Seek(posn, io.SeekStart)
+	if err != nil {
+		errMsg := fmt.Sprintf("rafSeek: osFile.Seek(%d) failed, reason: %s", posn, err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	return nil
+}
+
+// "java/io/RandomAccessFile.length()J"
+func rafLength(params []interface{}) interface{} {
+	osFile, gErr := rafFile("rafLength", params)
+	if gErr != nil {
+		return gErr
+	}
+	info, err := osFile.Stat()
+	if err != nil {
+		errMsg := fmt.Sprintf("rafLength: osFile.Stat() failed, reason: %s", err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	return info.Size()
+}
+
+// "java/io/RandomAccessFile.setLength(J)V"
+// Truncate or extend the file. If it is truncated to before the file position, the
+// position becomes the new end of the file.
+func rafSetLength(params []interface{}) interface{} {
+	osFile, gErr := rafFile("rafSetLength", params)
+	if gErr != nil {
+		return gErr
+	}
+	newLength, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("rafSetLength", err)
+	}
+	if newLength < 0 {
+		return getGErrBlk(excNames.IOException, "rafSetLength: Negative file length")
+	}
+
+	err = osFile.Truncate(newLength)
+	if err != nil {
+		errMsg := fmt.Sprintf("rafSetLength: osFile.Truncate(%d) failed, reason: %s", newLength, err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	posn, err := osFile.Seek(0, io.SeekCurrent)
+	if err == nil && posn > newLength {
+		_, err = osFile.Seek(newLength, io.SeekStart)
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("rafSetLength: osFile.Seek failed, reason: %s", err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	return nil
+}
+
+// "java/io/RandomAccessFile.skipBytes(I)I"
+// Skip up to n bytes, stopping at the end of the file. Returns the number skipped.
+func rafSkipBytes(params []interface{}) interface{} {
+	osFile, gErr := rafFile("rafSkipBytes", params)
+	if gErr != nil {
+		return gErr
+	}
+	count, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("rafSkipBytes", err)
+	}
+	if count <= 0 {
+		return int64(0)
+	}
+
+	posn, err := osFile.Seek(0, io.SeekCurrent)
+	if err != nil {
+		errMsg := fmt.Sprintf("rafSkipBytes: osFile.Seek failed, reason: %s", err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	info, err := osFile.Stat()
+	if err != nil {
+		errMsg := fmt.Sprintf("rafSkipBytes: osFile.Stat() failed, reason: %s", err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	count = max(min(count, info.Size()-posn), 0)
+	_, err = osFile.Seek(posn+count, io.SeekStart)
+	if err != nil {
+		errMsg := fmt.Sprintf("rafSkipBytes: osFile.Seek failed, reason: %s", err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	return count
+}
+
+// Reads into the byte array at the offset given, in place, and returns the number
+// of bytes read, or -1 at the end of the file. Unlike the read() of an InputStream, it
+// doesn't replace the array.
+func rafReadInto(funcName string, params []interface{}, javaBytes []types.JavaByte, offset, length int64) interface{} {
+	osFile, gErr := rafFile(funcName, params)
+	if gErr != nil {
+		return gErr
+	}
+	if length < 0 || offset < 0 || length > int64(len(javaBytes))-offset {
+		errMsg := fmt.Sprintf("%s: Error in parameters offset=%d length=%d bytes.length=%d",
+			funcName, offset, length, len(javaBytes))
+		return getGErrBlk(excNames.IndexOutOfBoundsException, errMsg)
+	}
+	if length == 0 {
+		return int64(0)
+	}
+
+	buffer := make([]byte, length)
+	nbytes, err := replay.Read(osFile, buffer)
+	if err == io.EOF {
+		return int64(-1) // return -1 on EOF
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("%s: osFile.Read failed, reason: %s", funcName, err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	copy(javaBytes[offset:], object.JavaByteArrayFromGoByteArray(buffer[:nbytes]))
+	return int64(nbytes)
+}
+
+// "java/io/RandomAccessFile.read([B)I"
+func rafReadByteArray(params []interface{}) interface{} {
+	javaBytes, err := args.GetByteArray(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("rafReadByteArray", err)
+	}
+	return rafReadInto("rafReadByteArray", params, javaBytes, 0, int64(len(javaBytes)))
+}
+
+// "java/io/RandomAccessFile.read([BII)I"
+func rafReadByteArrayOffset(params []interface{}) interface{} {
+	javaBytes, err := args.GetByteArray(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("rafReadByteArrayOffset", err)
+	}
+	offset, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("rafReadByteArrayOffset", err)
+	}
+	length, err := args.GetInt64(params, 3)
+	if err != nil {
+		return getArgsGErrBlk("rafReadByteArrayOffset", err)
+	}
+	return rafReadInto("rafReadByteArrayOffset", params, javaBytes, offset, length)
+}
+
+// Reads exactly n bytes, or returns an EOFException if the file ends first.
+func rafReadExactly(funcName string, params []interface{}, n int) ([]byte, *GErrBlk) {
+	osFile, gErr := rafFile(funcName, params)
+	if gErr != nil {
+		return nil, gErr
+	}
+	buffer := make([]byte, n)
+	for nread := 0; nread < n; {
+		nbytes, err := replay.Read(osFile, buffer[nread:])
+		nread += nbytes
+		if err == io.EOF {
+			return nil, getGErrBlk(excNames.EOFException, funcName+": End of file")
+		}
+		if err != nil {
+			errMsg := fmt.Sprintf("%s: osFile.Read failed, reason: %s", funcName, err.Error())
+			return nil, getGErrBlk(excNames.IOException, errMsg)
+		}
+	}
+	return buffer, nil
+}
+
+// "java/io/RandomAccessFile.readFully([B)V"
+func rafReadFully(params []interface{}) interface{} {
+	javaBytes, err := args.GetByteArray(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("rafReadFully", err)
+	}
+	buffer, gErr := rafReadExactly("rafReadFully", params, len(javaBytes))
+	if gErr != nil {
+		return gErr
+	}
+	copy(javaBytes, object.JavaByteArrayFromGoByteArray(buffer))
+	return nil
+}
+
+// "java/io/RandomAccessFile.readFully([BII)V"
+func rafReadFullyOffset(params []interface{}) interface{} {
+	javaBytes, err := args.GetByteArray(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("rafReadFullyOffset", err)
+	}
+	offset, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("rafReadFullyOffset", err)
+	}
+	length, err := args.GetInt64(params, 3)
+	if err != nil {
+		return getArgsGErrBlk("rafReadFullyOffset", err)
+	}
+	if length < 0 || offset < 0 || length > int64(len(javaBytes))-offset {
+		errMsg := fmt.Sprintf("rafReadFullyOffset: Error in parameters offset=%d length=%d bytes.length=%d",
+			offset, length, len(javaBytes))
+		return getGErrBlk(excNames.IndexOutOfBoundsException, errMsg)
+	}
+	buffer, gErr := rafReadExactly("rafReadFullyOffset", params, int(length))
+	if gErr != nil {
+		return gErr
+	}
+	copy(javaBytes[offset:], object.JavaByteArrayFromGoByteArray(buffer))
+	return nil
+}
+
+// "java/io/RandomAccessFile.readBoolean()Z"
+func rafReadBoolean(params []interface{}) interface{} {
+	buffer, gErr := rafReadExactly("rafReadBoolean", params, 1)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(buffer[0] != 0)
+}
+
+// "java/io/RandomAccessFile.readByte()B"
+func rafReadByte(params []interface{}) interface{} {
+	buffer, gErr := rafReadExactly("rafReadByte", params, 1)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(int8(buffer[0]))
+}
+
+// "java/io/RandomAccessFile.readUnsignedByte()I"
+func rafReadUnsignedByte(params []interface{}) interface{} {
+	buffer, gErr := rafReadExactly("rafReadUnsignedByte", params, 1)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(buffer[0])
+}
+
+// "java/io/RandomAccessFile.readShort()S"
+func rafReadShort(params []interface{}) interface{} {
+	buffer, gErr := rafReadExactly("rafReadShort", params, 2)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(int16(binary.BigEndian.Uint16(buffer)))
+}
+
+// "java/io/RandomAccessFile.readUnsignedShort()I"
+func rafReadUnsignedShort(params []interface{}) interface{} {
+	buffer, gErr := rafReadExactly("rafReadUnsignedShort", params, 2)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(binary.BigEndian.Uint16(buffer))
+}
+
+// "java/io/RandomAccessFile.readChar()C"
+func rafReadChar(params []interface{}) interface{} {
+	buffer, gErr := rafReadExactly("rafReadChar", params, 2)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(binary.BigEndian.Uint16(buffer))
+}
+
+// "java/io/RandomAccessFile.readInt()I"
+func rafReadInt(params []interface{}) interface{} {
+	buffer, gErr := rafReadExactly("rafReadInt", params, 4)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(int32(binary.BigEndian.Uint32(buffer)))
+}
+
+// "java/io/RandomAccessFile.readLong()J"
+func rafReadLong(params []interface{}) interface{} {
+	buffer, gErr := rafReadExactly("rafReadLong", params, 8)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(binary.BigEndian.Uint64(buffer))
+}
+
+// "java/io/RandomAccessFile.readFloat()F"
+func rafReadFloat(params []interface{}) interface{} {
+	buffer, gErr := rafReadExactly("rafReadFloat", params, 4)
+	if gErr != nil {
+		return gErr
+	}
+	return float64(math.Float32frombits(binary.BigEndian.Uint32(buffer)))
+}
+
+// "java/io/RandomAccessFile.readDouble()D"
+func rafReadDouble(params []interface{}) interface{} {
+	buffer, gErr := rafReadExactly("rafReadDouble", params, 8)
+	if gErr != nil {
+		return gErr
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(buffer))
+}
+
+// "java/io/RandomAccessFile.readLine()Ljava/lang/String;"
+// Reads a line ended by "\n", "\r", or "\r\n", taking each byte as a char, as the JDK
+// does. Returns null at the end of the file.
+func rafReadLine(params []interface{}) interface{} {
+	osFile, gErr := rafFile("rafReadLine", params)
+	if gErr != nil {
+		return gErr
+	}
+
+	var chars []rune
+	buffer := make([]byte, 1)
+	sawEOF := true
+	for {
+		_, err := replay.Read(osFile, buffer)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errMsg := fmt.Sprintf("rafReadLine: osFile.Read failed, reason: %s", err.Error())
+			return getGErrBlk(excNames.IOException, errMsg)
+		}
+		sawEOF = false
+		if buffer[0] == '\n' {
+			break
+		}
+		if buffer[0] == '\r' {
+			// Consume a '\n' that follows, but nothing else.
+			_, err = replay.Read(osFile, buffer)
+			if err == nil && buffer[0] != '\n' {
+				_, err = osFile.Seek(-1, io.SeekCurrent)
+			}
+			if err != nil && err != io.EOF {
+				errMsg := fmt.Sprintf("rafReadLine: osFile.Read failed, reason: %s", err.Error())
+				return getGErrBlk(excNames.IOException, errMsg)
+			}
+			break
+		}
+		chars = append(chars, rune(buffer[0]))
+	}
+
+	if sawEOF {
+		return object.Null
+	}
+	return object.StringObjectFromGoString(string(chars))
+}
+
+// "java/io/RandomAccessFile.readUTF()Ljava/lang/String;"
+// Reads a string of modified UTF-8 preceded by its length in bytes as an unsigned short.
+func rafReadUTF(params []interface{}) interface{} {
+	buffer, gErr := rafReadExactly("rafReadUTF", params, 2)
+	if gErr != nil {
+		return gErr
+	}
+	buffer, gErr = rafReadExactly("rafReadUTF", params, int(binary.BigEndian.Uint16(buffer)))
+	if gErr != nil {
+		return gErr
+	}
+	str, ok := decodeModifiedUTF8(buffer)
+	if !ok {
+		return getGErrBlk(excNames.UTFDataFormatException, "rafReadUTF: Malformed input")
+	}
+	return object.StringObjectFromGoString(str)
+}
+
+// Decodes modified UTF-8, in which a NUL is the two bytes C0 80 and each surrogate of a
+// supplementary character is encoded by itself in three bytes.
+func decodeModifiedUTF8(b []byte) (string, bool) {
+	chars := make([]uint16, 0, len(b))
+	for i := 0; i < len(b); {
+		c := b[i]
+		switch {
+		case c < 0x80:
+			chars = append(chars, uint16(c))
+			i++
+		case c&0xE0 == 0xC0 && i+1 < len(b) && b[i+1]&0xC0 == 0x80:
+			chars = append(chars, uint16(c&0x1F)<<6|uint16(b[i+1]&0x3F))
+			i += 2
+		case c&0xF0 == 0xE0 && i+2 < len(b) && b[i+1]&0xC0 == 0x80 && b[i+2]&0xC0 == 0x80:
+			chars = append(chars, uint16(c&0x0F)<<12|uint16(b[i+1]&0x3F)<<6|uint16(b[i+2]&0x3F))
+			i += 3
+		default:
+			return "", false
+		}
+	}
+	return string(utf16.Decode(chars)), true
+}
+
+// Encodes the chars of a string in modified UTF-8.
+func encodeModifiedUTF8(str string) []byte {
+	chars := utf16.Encode([]rune(str))
+	b := make([]byte, 0, len(chars))
+	for _, c := range chars {
+		switch {
+		case c != 0 && c < 0x80:
+			b = append(b, byte(c))
+		case c < 0x800:
+			b = append(b, byte(0xC0|c>>6), byte(0x80|c&0x3F))
+		default:
+			b = append(b, byte(0xE0|c>>12), byte(0x80|(c>>6)&0x3F), byte(0x80|c&0x3F))
+		}
+	}
+	return b
+}
+
+// Writes the bytes at the file position.
+func rafWrite(funcName string, params []interface{}, buffer []byte) interface{} {
+	osFile, gErr := rafFile(funcName, params)
+	if gErr != nil {
+		return gErr
+	}
+	_, err := osFile.Write(buffer)
+	if err != nil {
+		errMsg := fmt.Sprintf("%s: osFile.Write failed, reason: %s", funcName, err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	return nil
+}
+
+// "java/io/RandomAccessFile.writeBoolean(Z)V"
+// "java/io/RandomAccessFile.writeByte(I)V"
+func rafWriteByte(params []interface{}) interface{} {
+	value, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("rafWriteByte", err)
+	}
+	return rafWrite("rafWriteByte", params, []byte{byte(value)})
+}
+
+// "java/io/RandomAccessFile.writeChar(I)V"
+// "java/io/RandomAccessFile.writeShort(I)V"
+func rafWriteShort(params []interface{}) interface{} {
+	value, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("rafWriteShort", err)
+	}
+	return rafWrite("rafWriteShort", params, binary.BigEndian.AppendUint16(nil, uint16(value)))
+}
+
+// "java/io/RandomAccessFile.writeInt(I)V"
+func rafWriteInt(params []interface{}) interface{} {
+	value, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("rafWriteInt", err)
+	}
+	return rafWrite("rafWriteInt", params, binary.BigEndian.AppendUint32(nil, uint32(value)))
+}
+
+// "java/io/RandomAccessFile.writeLong(J)V"
+func rafWriteLong(params []interface{}) interface{} {
+	value, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("rafWriteLong", err)
+	}
+	return rafWrite("rafWriteLong", params, binary.BigEndian.AppendUint64(nil, uint64(value)))
+}
+
+// "java/io/RandomAccessFile.writeFloat(F)V"
+func rafWriteFloat(params []interface{}) interface{} {
+	value, err := args.GetFloat64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("rafWriteFloat", err)
+	}
+	bits := math.Float32bits(float32(value))
+	return rafWrite("rafWriteFloat", params, binary.BigEndian.AppendUint32(nil, bits))
+}
+
+// "java/io/RandomAccessFile.writeDouble(D)V"
+func rafWriteDouble(params []interface{}) interface{} {
+	value, err := args.GetFloat64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("rafWriteDouble", err)
+	}
+	bits := math.Float64bits(value)
+	return rafWrite("rafWriteDouble", params, binary.BigEndian.AppendUint64(nil, bits))
+}
+
+// "java/io/RandomAccessFile.writeBytes(Ljava/lang/String;)V"
+// Writes the low byte of each char.
+func rafWriteBytes(params []interface{}) interface{} {
+	str, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("rafWriteBytes", err)
+	}
+	chars := utf16.Encode([]rune(str))
+	buffer := make([]byte, len(chars))
+	for i, c := range chars {
+		buffer[i] = byte(c)
+	}
+	return rafWrite("rafWriteBytes", params, buffer)
+}
+
+// "java/io/RandomAccessFile.writeChars(Ljava/lang/String;)V"
+// Writes each char as two bytes.
+func rafWriteChars(params []interface{}) interface{} {
+	str, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("rafWriteChars", err)
+	}
+	chars := utf16.Encode([]rune(str))
+	buffer := make([]byte, 0, 2*len(chars))
+	for _, c := range chars {
+		buffer = binary.BigEndian.AppendUint16(buffer, c)
+	}
+	return rafWrite("rafWriteChars", params, buffer)
+}
+
+// "java/io/RandomAccessFile.writeUTF(Ljava/lang/String;)V"
+// Writes the length in bytes as an unsigned short and then the string in modified UTF-8.
+func rafWriteUTF(params []interface{}) interface{} {
+	str, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("rafWriteUTF", err)
+	}
+	encoded := encodeModifiedUTF8(str)
+	if len(encoded) > math.MaxUint16 {
+		errMsg := fmt.Sprintf("rafWriteUTF: Encoded string too long: %d bytes", len(encoded))
+		return getGErrBlk(excNames.UTFDataFormatException, errMsg)
+	}
+	buffer := binary.BigEndian.AppendUint16(nil, uint16(len(encoded)))
+	return rafWrite("rafWriteUTF", params, append(buffer, encoded...))
+}
@@ -2,8 +2,11 @@ package gfunction
 
 import (
 	"io"
+	"jacobin/src/excNames"
 	"jacobin/src/globals"
+	"math"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"jacobin/src/object"
@@ -247,3 +250,162 @@ func TestFisReadByteArrayOffset(t *testing.T) {
 		t.Errorf("fisReadByteArrayOffset expected read %d bytes, got %d", length, numRead)
 	}
 }
+
+// opens a RandomAccessFile on a new file in the mode given
+func openRAF(t *testing.T, mode string) (*object.Object, string) {
+	t.Helper()
+	globals.InitStringPool()
+	filePath := filepath.Join(t.TempDir(), "raf.bin")
+	rafObj := newRAFObject()
+	ret := rafInitString([]interface{}{rafObj, object.StringObjectFromGoString(filePath), object.StringObjectFromGoString(mode)})
+	if ret != nil {
+		t.Fatalf("rafInitString(%s) returned error: %v", mode, ret)
+	}
+	t.Cleanup(func() { fisClose([]interface{}{rafObj}) })
+	return rafObj, filePath
+}
+
+func TestRafModes(t *testing.T) {
+	globals.InitStringPool()
+	missing := filepath.Join(t.TempDir(), "missing.bin")
+	ret := rafInitString([]interface{}{newRAFObject(), object.StringObjectFromGoString(missing), object.StringObjectFromGoString("r")})
+	if gErr, ok := ret.(*GErrBlk); !ok || gErr.ExceptionType != excNames.FileNotFoundException {
+		t.Errorf("Expected FileNotFoundException for mode r on a missing file, got %v", ret)
+	}
+	ret = rafInitString([]interface{}{newRAFObject(), object.StringObjectFromGoString(missing), object.StringObjectFromGoString("w")})
+	if gErr, ok := ret.(*GErrBlk); !ok || gErr.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("Expected IllegalArgumentException for mode w, got %v", ret)
+	}
+
+	// rw creates the file, and r opens it read-only
+	_, filePath := openRAF(t, "rws")
+	rafObj := newRAFObject()
+	ret = rafInitString([]interface{}{rafObj, object.StringObjectFromGoString(filePath), object.StringObjectFromGoString("r")})
+	if ret != nil {
+		t.Fatalf("rafInitString returned error: %v", ret)
+	}
+	defer fisClose([]interface{}{rafObj})
+	if ret := rafWriteInt([]interface{}{rafObj, int64(1)}); ret == nil {
+		t.Errorf("Expected an error writing a file opened in mode r")
+	}
+}
+
+func TestRafPrimitivesRoundTrip(t *testing.T) {
+	rafObj, _ := openRAF(t, "rw")
+
+	writes := []struct {
+		fn    func([]interface{}) interface{}
+		value interface{}
+	}{
+		{rafWriteByte, int64(1)},
+		{rafWriteByte, int64(-2)},
+		{rafWriteShort, int64(-300)},
+		{rafWriteShort, int64(0x20AC)},
+		{rafWriteInt, int64(-70000)},
+		{rafWriteLong, int64(-1) << 40},
+		{rafWriteFloat, float64(1.5)},
+		{rafWriteDouble, math.Pi},
+		{rafWriteUTF, object.StringObjectFromGoString("a\x00é\U0001F600")},
+	}
+	for _, w := range writes {
+		if ret := w.fn([]interface{}{rafObj, w.value}); ret != nil {
+			t.Fatalf("write returned error: %v", ret)
+		}
+	}
+	// 1 + 1 + 2 + 2 + 4 + 8 + 4 + 8 + (2 + 1 + 2 + 2 + 6)
+	if length := rafLength([]interface{}{rafObj}); length != int64(43) {
+		t.Fatalf("Expected a length of 43, got %v", length)
+	}
+
+	if ret := rafSeek([]interface{}{rafObj, int64(0)}); ret != nil {
+		t.Fatalf("rafSeek returned error: %v", ret)
+	}
+	reads := []struct {
+		fn       func([]interface{}) interface{}
+		expected interface{}
+	}{
+		{rafReadBoolean, types.JavaBoolTrue},
+		{rafReadByte, int64(-2)},
+		{rafReadShort, int64(-300)},
+		{rafReadChar, int64(0x20AC)},
+		{rafReadInt, int64(-70000)},
+		{rafReadLong, int64(-1) << 40},
+		{rafReadFloat, float64(1.5)},
+		{rafReadDouble, math.Pi},
+	}
+	for i, r := range reads {
+		if got := r.fn([]interface{}{rafObj}); got != r.expected {
+			t.Errorf("read %d: expected %v, got %v", i, r.expected, got)
+		}
+	}
+	str := rafReadUTF([]interface{}{rafObj})
+	if got := object.GoStringFromStringObject(str.(*object.Object)); got != "a\x00é\U0001F600" {
+		t.Errorf("Expected the string written by writeUTF, got %q", got)
+	}
+
+	ret := rafReadInt([]interface{}{rafObj})
+	if gErr, ok := ret.(*GErrBlk); !ok || gErr.ExceptionType != excNames.EOFException {
+		t.Errorf("Expected EOFException reading past the end, got %v", ret)
+	}
+}
+
+func TestRafSeekAndSetLength(t *testing.T) {
+	rafObj, filePath := openRAF(t, "rw")
+	if ret := rafWriteBytes([]interface{}{rafObj, object.StringObjectFromGoString("0123456789")}); ret != nil {
+		t.Fatalf("rafWriteBytes returned error: %v", ret)
+	}
+
+	// overwrite in the middle, which appending would not
+	rafSeek([]interface{}{rafObj, int64(4)})
+	rafWriteByte([]interface{}{rafObj, int64('x')})
+	if posn := rafGetFilePointer([]interface{}{rafObj}); posn != int64(5) {
+		t.Errorf("Expected the file pointer at 5, got %v", posn)
+	}
+	if skipped := rafSkipBytes([]interface{}{rafObj, int64(100)}); skipped != int64(5) {
+		t.Errorf("Expected to skip the 5 bytes left, got %v", skipped)
+	}
+
+	// truncating moves the file pointer back to the new end
+	if ret := rafSetLength([]interface{}{rafObj, int64(6)}); ret != nil {
+		t.Fatalf("rafSetLength returned error: %v", ret)
+	}
+	if posn := rafGetFilePointer([]interface{}{rafObj}); posn != int64(6) {
+		t.Errorf("Expected the file pointer at 6, got %v", posn)
+	}
+	content, _ := os.ReadFile(filePath)
+	if string(content) != "0123x5" {
+		t.Errorf("Expected %q, got %q", "0123x5", content)
+	}
+
+	ret := rafSeek([]interface{}{rafObj, int64(-1)})
+	if gErr, ok := ret.(*GErrBlk); !ok || gErr.ExceptionType != excNames.IOException {
+		t.Errorf("Expected IOException for a negative seek, got %v", ret)
+	}
+}
+
+func TestRafReadIntoArrayAndReadLine(t *testing.T) {
+	rafObj, _ := openRAF(t, "rw")
+	rafWriteBytes([]interface{}{rafObj, object.StringObjectFromGoString("one\r\ntwo\rthree\nfour")})
+	rafSeek([]interface{}{rafObj, int64(0)})
+
+	// read([BII) fills the array in place and leaves the rest alone
+	javaBytes := make([]types.JavaByte, 6)
+	arrayObj := object.MakePrimitiveObject(types.ByteArray, types.ByteArray, javaBytes)
+	if n := rafReadByteArrayOffset([]interface{}{rafObj, arrayObj, int64(1), int64(3)}); n != int64(3) {
+		t.Fatalf("Expected 3 bytes read, got %v", n)
+	}
+	if got := arrayObj.FieldTable["value"].Fvalue.([]types.JavaByte); len(got) != 6 || got[1] != 'o' || got[3] != 'e' {
+		t.Errorf("Expected \"one\" at offset 1 of the array, got %v", got)
+	}
+
+	rafSeek([]interface{}{rafObj, int64(0)})
+	for _, expected := range []string{"one", "two", "three", "four"} {
+		line := rafReadLine([]interface{}{rafObj})
+		if got := object.GoStringFromStringObject(line.(*object.Object)); got != expected {
+			t.Errorf("Expected the line %q, got %q", expected, got)
+		}
+	}
+	if line := rafReadLine([]interface{}{rafObj}); !object.IsNull(line) {
+		t.Errorf("Expected null at the end of the file, got %v", line)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"errors"
+	"io"
+	"jacobin/src/object"
+	"jacobin/src/types"
+)
+
+// A javaStream lets G functions use a stream written in Java--such as the PrintStream or
+// InputStream a program passes to System.setOut(), setErr(), or setIn()--as a Go
+// io.Writer or io.Reader. Each Write() is an upcall to the object's write([BII)V method
+// and each Read() one to its read([BII)I method, on the frame stack of the thread running
+// the G function. A stream that's a Go handle, such as the *os.File that System.out
+// starts as, is used directly instead.
+
+type javaStream struct {
+	fs   *list.List
+	obj  *object.Object
+	gErr *GErrBlk // the exception thrown by the last upcall, if it threw one
+}
+
+// the error that Read() and Write() return when the upcall throws an exception, which
+// is in gErr
+var errJavaStreamException = errors.New("the Java stream threw an exception")
+
+// Write writes p by calling the stream's write(byte[], int, int)
+func (js *javaStream) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	arr := object.MakeArrayFromRawArray(append([]byte(nil), p...))
+	_, gErr := invokeJavaMethod(js.fs, "java/io/OutputStream", "write", "([BII)V",
+		js.obj, []any{arr, int64(0), int64(len(p))})
+	if gErr != nil {
+		js.gErr = gErr
+		return 0, errJavaStreamException
+	}
+	return len(p), nil
+}
+
+// Read reads into p by calling the stream's read(byte[], int, int), which returns -1 at
+// the end of the stream
+func (js *javaStream) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	arr := object.Make1DimArray(object.BYTE, int64(len(p)))
+	ret, gErr := invokeJavaMethod(js.fs, "java/io/InputStream", "read", "([BII)I",
+		js.obj, []any{arr, int64(0), int64(len(p))})
+	if gErr != nil {
+		js.gErr = gErr
+		return 0, errJavaStreamException
+	}
+	n, _ := ret.(int64)
+	if n < 0 {
+		return 0, io.EOF
+	}
+	for i, b := range arr.FieldTable["value"].Fvalue.([]types.JavaByte)[:n] {
+		p[i] = byte(b)
+	}
+	return int(n), nil
+}
+
+// Close calls the stream's close()
+func (js *javaStream) Close() error {
+	if _, gErr := invokeJavaMethod(js.fs, "java/io/Closeable", "close", "()V", js.obj, nil); gErr != nil {
+		js.gErr = gErr
+		return errJavaStreamException
+	}
+	return nil
+}
+
+// takeException returns the exception thrown by the last upcall, if one did, and clears it
+func (js *javaStream) takeException() *GErrBlk {
+	gErr := js.gErr
+	js.gErr = nil
+	return gErr
+}
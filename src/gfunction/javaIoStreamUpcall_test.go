@@ -0,0 +1,170 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"bytes"
+	"container/list"
+	"jacobin/src/excNames"
+	"jacobin/src/exceptions"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/statics"
+	"jacobin/src/types"
+	"os"
+	"testing"
+)
+
+// installs an upcall bridge that serves the read([BII)I and write([BII)V upcalls of a
+// stream written in Java from input and into output, and returns that stream. An upcall
+// of write() with fail set throws an IOException.
+func fakeJavaStream(t *testing.T, input []byte, output *bytes.Buffer, fail *bool) *object.Object {
+	t.Helper()
+	className := "com/example/CaptureStream"
+	stream := object.MakeEmptyObjectWithClassName(&className)
+
+	glob := globals.GetGlobalRef()
+	saved := glob.FuncInvokeJavaMethod
+	glob.FuncInvokeJavaMethod = func(_ *list.List, _, methName, methType string, receiver any, args []any) (any, error) {
+		if receiver != stream {
+			t.Fatalf("unexpected upcall of %s%s on %v", methName, methType, receiver)
+		}
+		buf := args[0].(*object.Object).FieldTable["value"].Fvalue.([]types.JavaByte)
+		off, length := args[1].(int64), args[2].(int64)
+		switch methName + methType {
+		case "write([BII)V":
+			if fail != nil && *fail {
+				return nil, &exceptions.UpcallError{Method: className + ".write", Cause: "java.io.IOException: stream closed"}
+			}
+			for _, b := range buf[off : off+length] {
+				output.WriteByte(byte(b))
+			}
+			return nil, nil
+		case "read([BII)I":
+			if len(input) == 0 {
+				return int64(-1), nil
+			}
+			n := copy(buf[off:off+length], object.JavaByteArrayFromGoString(string(input)))
+			input = input[n:]
+			return int64(n), nil
+		}
+		t.Fatalf("unexpected upcall of %s%s", methName, methType)
+		return nil, nil
+	}
+	t.Cleanup(func() { glob.FuncInvokeJavaMethod = saved })
+	return stream
+}
+
+// calls the G function registered for the method as the interpreter does, with the frame
+// stack first if it needs context
+func callGMethod(t *testing.T, method string, params ...interface{}) interface{} {
+	t.Helper()
+	gmeth, ok := MethodSignatures[method]
+	if !ok {
+		t.Fatalf("%s is not registered", method)
+	}
+	if gmeth.NeedsContext {
+		params = append([]interface{}{list.New()}, params...)
+	}
+	return gmeth.GFunction(params)
+}
+
+func TestPrintStream_PrintsToJavaStream(t *testing.T) {
+	globals.InitGlobals("test")
+	Load_Io_PrintStream()
+	var output bytes.Buffer
+	fail := false
+	stream := fakeJavaStream(t, nil, &output, &fail)
+
+	callGMethod(t, "java/io/PrintStream.print(I)V", stream, int64(42))
+	callGMethod(t, "java/io/PrintStream.println(Ljava/lang/String;)V", stream, object.StringObjectFromGoString(" is the answer"))
+	if want := "42 is the answer" + globals.GetLineSeparator(); output.String() != want {
+		t.Errorf("Expected the stream to get %q, got %q", want, output.String())
+	}
+
+	format := object.StringObjectFromGoString("!")
+	noArgs := object.MakeArrayFromRawArray([]*object.Object{})
+	if ret := callGMethod(t, "java/io/PrintStream.printf(Ljava/lang/String;[Ljava/lang/Object;)Ljava/io/PrintStream;",
+		stream, format, noArgs); ret != stream {
+		t.Errorf("Expected printf() to return the stream, got %v", ret)
+	}
+
+	fail = true
+	ret := callGMethod(t, "java/io/PrintStream.println()V", stream)
+	expectException(t, ret, excNames.IOException, "println() to a stream that throws")
+}
+
+func TestPrintStream_PrintsToGoHandle(t *testing.T) {
+	globals.InitGlobals("test")
+	Load_Io_PrintStream()
+	var output bytes.Buffer
+
+	// a java/io/PrintStream made on a Go handle writes to the handle
+	className := "java/io/PrintStream"
+	stream := object.MakeEmptyObjectWithClassName(&className)
+	stream.FieldTable["out"] = object.Field{Ftype: "Ljava/io/OutputStream;", Fvalue: &output}
+
+	callGMethod(t, "java/io/PrintStream.print(Ljava/lang/String;)V", &output, object.StringObjectFromGoString("a"))
+	callGMethod(t, "java/io/PrintStream.print(Z)V", stream, types.JavaBoolTrue)
+	if output.String() != "atrue" {
+		t.Errorf("Expected %q, got %q", "atrue", output.String())
+	}
+}
+
+func TestSystem_SetStandardStreams(t *testing.T) {
+	globals.InitGlobals("test")
+	stream := fakeJavaStream(t, nil, &bytes.Buffer{}, nil)
+	t.Cleanup(func() {
+		_ = statics.AddStatic("java/lang/System.out", statics.Static{Type: "GS", Value: os.Stdout})
+		_ = statics.AddStatic("java/lang/System.in", statics.Static{Type: "GS", Value: os.Stdin})
+	})
+
+	systemSetOut([]interface{}{stream})
+	if static := statics.Statics["java/lang/System.out"]; static.Value != stream || static.Type != "Ljava/io/PrintStream;" {
+		t.Errorf("Expected System.out to be the Java stream, got %v", static)
+	}
+	if consoleFile("out") != os.Stdout {
+		t.Errorf("Expected the console to keep writing to stdout")
+	}
+
+	systemSetOut([]interface{}{os.Stderr})
+	if static := statics.Statics["java/lang/System.out"]; static.Value != os.Stderr || static.Type != "GS" {
+		t.Errorf("Expected System.out to be restored to a Go handle, got %v", static)
+	}
+
+	systemSetIn([]interface{}{object.Null})
+	if static := statics.Statics["java/lang/System.in"]; !object.IsNull(static.Value) {
+		t.Errorf("Expected System.in to be null, got %v", static.Value)
+	}
+}
+
+func TestBufferedReader_ReadsJavaStream(t *testing.T) {
+	globals.InitGlobals("test")
+	Load_Io_InputStreamReader()
+	Load_Io_BufferedReader()
+	stream := fakeJavaStream(t, []byte("first\r\nsecond"), nil, nil)
+
+	isrClass, brClass := "java/io/InputStreamReader", "java/io/BufferedReader"
+	isr := object.MakeEmptyObjectWithClassName(&isrClass)
+	if ret := inputStreamReaderInit([]interface{}{isr, stream}); ret != nil {
+		t.Fatalf("InputStreamReader(stream) failed: %v", ret)
+	}
+	reader := object.MakeEmptyObjectWithClassName(&brClass)
+	if ret := bufferedReaderInit([]interface{}{reader, isr}); ret != nil {
+		t.Fatalf("BufferedReader(reader) failed: %v", ret)
+	}
+
+	for _, want := range []string{"first", "second"} {
+		line, ok := callGMethod(t, "java/io/BufferedReader.readLine()Ljava/lang/String;", reader).(*object.Object)
+		if !ok || object.GoStringFromStringObject(line) != want {
+			t.Errorf("Expected readLine() to return %q, got %v", want, line)
+		}
+	}
+	if line := callGMethod(t, "java/io/BufferedReader.readLine()Ljava/lang/String;", reader); line != object.Null {
+		t.Errorf("Expected readLine() to return null at the end of the stream, got %v", line)
+	}
+}
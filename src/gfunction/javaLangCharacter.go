@@ -7,11 +7,29 @@
 package gfunction
 
 import (
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
 	"jacobin/src/object"
 	"jacobin/src/types"
 	"unicode"
 )
 
+// Character's static methods come in two forms: one takes a char, the other an int code
+// point, which may be a supplementary character (above U+FFFF). Both are passed to the G
+// function as an int64, so both forms share a G function. The character properties come
+// from Go's Unicode tables, using the Java definitions of the properties where these
+// differ from Go's, as for isWhitespace().
+
+const (
+	minHighSurrogate     = 0xD800
+	maxHighSurrogate     = 0xDBFF
+	minLowSurrogate      = 0xDC00
+	maxLowSurrogate      = 0xDFFF
+	minSupplementaryCode = 0x10000
+	maxCodePoint         = 0x10FFFF
+)
+
 func Load_Lang_Character() {
 
 	MethodSignatures["java/lang/Character.<clinit>()V"] =
@@ -20,22 +38,238 @@ func Load_Lang_Character() {
 			GFunction:  clinitGeneric,
 		}
 
+	MethodSignatures["java/lang/Character.charCount(I)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charCharCount,
+		}
+
+	MethodSignatures["java/lang/Character.charValue()C"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  charValue,
+		}
+
+	MethodSignatures["java/lang/Character.codePointAt([CI)I"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  charCodePointAt,
+		}
+
+	MethodSignatures["java/lang/Character.codePointBefore([CI)I"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  charCodePointBefore,
+		}
+
+	MethodSignatures["java/lang/Character.digit(CI)I"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  charDigit,
+		}
+
+	MethodSignatures["java/lang/Character.digit(II)I"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  charDigit,
+		}
+
+	MethodSignatures["java/lang/Character.forDigit(II)C"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  charForDigit,
+		}
+
+	MethodSignatures["java/lang/Character.getNumericValue(C)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charGetNumericValue,
+		}
+
+	MethodSignatures["java/lang/Character.getNumericValue(I)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charGetNumericValue,
+		}
+
+	MethodSignatures["java/lang/Character.highSurrogate(I)C"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charHighSurrogate,
+		}
+
+	MethodSignatures["java/lang/Character.isAlphabetic(I)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsAlphabetic,
+		}
+
+	MethodSignatures["java/lang/Character.isBmpCodePoint(I)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsBmpCodePoint,
+		}
+
 	MethodSignatures["java/lang/Character.isDigit(C)Z"] =
 		GMeth{
 			ParamSlots: 1,
 			GFunction:  charIsDigit,
 		}
 
+	MethodSignatures["java/lang/Character.isDigit(I)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsDigit,
+		}
+
+	MethodSignatures["java/lang/Character.isHighSurrogate(C)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsHighSurrogate,
+		}
+
+	MethodSignatures["java/lang/Character.isISOControl(C)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsISOControl,
+		}
+
+	MethodSignatures["java/lang/Character.isISOControl(I)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsISOControl,
+		}
+
 	MethodSignatures["java/lang/Character.isLetter(C)Z"] =
 		GMeth{
 			ParamSlots: 1,
 			GFunction:  charIsLetter,
 		}
 
-	MethodSignatures["java/lang/Character.charValue()C"] =
+	MethodSignatures["java/lang/Character.isLetter(I)Z"] =
 		GMeth{
-			ParamSlots: 0,
-			GFunction:  charValue,
+			ParamSlots: 1,
+			GFunction:  charIsLetter,
+		}
+
+	MethodSignatures["java/lang/Character.isLetterOrDigit(C)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsLetterOrDigit,
+		}
+
+	MethodSignatures["java/lang/Character.isLetterOrDigit(I)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsLetterOrDigit,
+		}
+
+	MethodSignatures["java/lang/Character.isLowSurrogate(C)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsLowSurrogate,
+		}
+
+	MethodSignatures["java/lang/Character.isLowerCase(C)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsLowerCase,
+		}
+
+	MethodSignatures["java/lang/Character.isLowerCase(I)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsLowerCase,
+		}
+
+	MethodSignatures["java/lang/Character.isSpaceChar(C)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsSpaceChar,
+		}
+
+	MethodSignatures["java/lang/Character.isSpaceChar(I)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsSpaceChar,
+		}
+
+	MethodSignatures["java/lang/Character.isSupplementaryCodePoint(I)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsSupplementaryCodePoint,
+		}
+
+	MethodSignatures["java/lang/Character.isSurrogate(C)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsSurrogate,
+		}
+
+	MethodSignatures["java/lang/Character.isSurrogatePair(CC)Z"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  charIsSurrogatePair,
+		}
+
+	MethodSignatures["java/lang/Character.isTitleCase(C)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsTitleCase,
+		}
+
+	MethodSignatures["java/lang/Character.isTitleCase(I)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsTitleCase,
+		}
+
+	MethodSignatures["java/lang/Character.isUpperCase(C)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsUpperCase,
+		}
+
+	MethodSignatures["java/lang/Character.isUpperCase(I)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsUpperCase,
+		}
+
+	MethodSignatures["java/lang/Character.isValidCodePoint(I)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsValidCodePoint,
+		}
+
+	MethodSignatures["java/lang/Character.isWhitespace(C)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsWhitespace,
+		}
+
+	MethodSignatures["java/lang/Character.isWhitespace(I)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charIsWhitespace,
+		}
+
+	MethodSignatures["java/lang/Character.lowSurrogate(I)C"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charLowSurrogate,
+		}
+
+	MethodSignatures["java/lang/Character.toChars(I)[C"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charToChars,
+		}
+
+	MethodSignatures["java/lang/Character.toCodePoint(CC)I"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  charToCodePoint,
 		}
 
 	MethodSignatures["java/lang/Character.toLowerCase(C)C"] =
@@ -44,12 +278,36 @@ func Load_Lang_Character() {
 			GFunction:  charToLowerCase,
 		}
 
+	MethodSignatures["java/lang/Character.toLowerCase(I)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charToLowerCase,
+		}
+
+	MethodSignatures["java/lang/Character.toTitleCase(C)C"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charToTitleCase,
+		}
+
+	MethodSignatures["java/lang/Character.toTitleCase(I)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charToTitleCase,
+		}
+
 	MethodSignatures["java/lang/Character.toUpperCase(C)C"] =
 		GMeth{
 			ParamSlots: 1,
 			GFunction:  charToUpperCase,
 		}
 
+	MethodSignatures["java/lang/Character.toUpperCase(I)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  charToUpperCase,
+		}
+
 	MethodSignatures["java/lang/Character.valueOf(C)Ljava/lang/Character;"] =
 		GMeth{
 			ParamSlots: 1,
@@ -58,36 +316,329 @@ func Load_Lang_Character() {
 
 }
 
-// "java/lang/Character.isDigit(C)Z"
-func charIsDigit(params []interface{}) interface{} {
-	ii := params[0].(int64)
-	if unicode.IsDigit(rune(ii)) {
+// returns the char or code point in params[0] as a rune, and whether it's a valid code point
+func charCodePoint(params []interface{}) (rune, bool) {
+	cp := params[0].(int64)
+	if cp < 0 || cp > maxCodePoint {
+		return 0, false
+	}
+	return rune(cp), true
+}
+
+// returns the Java boolean for whether the char or code point in params[0] has the property
+func charHasProperty(params []interface{}, property func(rune) bool) interface{} {
+	if r, ok := charCodePoint(params); ok && property(r) {
+		return types.JavaBoolTrue
+	}
+	return types.JavaBoolFalse
+}
+
+// returns the char or code point in params[0] mapped by the case mapping, or unchanged if
+// it's not a valid code point
+func charMapCase(params []interface{}, mapping func(rune) rune) interface{} {
+	r, ok := charCodePoint(params)
+	if !ok {
+		return params[0].(int64)
+	}
+	return int64(mapping(r))
+}
+
+// returns the value of r as a digit: the value of a decimal digit in any script, or 10-35
+// for the Latin letters a-z and A-Z, including their fullwidth forms. Returns -1 for any
+// other character.
+func charDigitValue(r rune) int64 {
+	switch {
+	case r >= '0' && r <= '9':
+		return int64(r - '0')
+	case r >= 'a' && r <= 'z':
+		return int64(r-'a') + 10
+	case r >= 'A' && r <= 'Z':
+		return int64(r-'A') + 10
+	case r >= 0xFF41 && r <= 0xFF5A: // fullwidth a-z
+		return int64(r-0xFF41) + 10
+	case r >= 0xFF21 && r <= 0xFF3A: // fullwidth A-Z
+		return int64(r-0xFF21) + 10
+	case unicode.IsDigit(r):
+		// Unicode's decimal digits come in runs that start at zero, so the value is the
+		// number of digits that precede r in its run, mod 10
+		value := int64(0)
+		for prev := r - 1; unicode.IsDigit(prev); prev-- {
+			value++
+		}
+		return value % 10
+	}
+	return -1
+}
+
+// "java/lang/Character.charCount(I)I"
+func charCharCount(params []interface{}) interface{} {
+	if params[0].(int64) >= minSupplementaryCode {
+		return int64(2)
+	}
+	return int64(1)
+}
+
+// "java/lang/Character.codePointAt([CI)I"
+func charCodePointAt(params []interface{}) interface{} {
+	chars, err := args.GetInt64Array(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("charCodePointAt", err)
+	}
+	index := params[1].(int64)
+	if index < 0 || index >= int64(len(chars)) {
+		errMsg := fmt.Sprintf("Index %d out of bounds for length %d", index, len(chars))
+		return getGErrBlk(excNames.ArrayIndexOutOfBoundsException, errMsg)
+	}
+	high := chars[index]
+	if high >= minHighSurrogate && high <= maxHighSurrogate && index+1 < int64(len(chars)) {
+		low := chars[index+1]
+		if low >= minLowSurrogate && low <= maxLowSurrogate {
+			return charToCodePoint([]interface{}{high, low})
+		}
+	}
+	return high
+}
+
+// "java/lang/Character.codePointBefore([CI)I"
+func charCodePointBefore(params []interface{}) interface{} {
+	chars, err := args.GetInt64Array(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("charCodePointBefore", err)
+	}
+	index := params[1].(int64) - 1
+	if index < 0 || index >= int64(len(chars)) {
+		errMsg := fmt.Sprintf("Index %d out of bounds for length %d", index, len(chars))
+		return getGErrBlk(excNames.ArrayIndexOutOfBoundsException, errMsg)
+	}
+	low := chars[index]
+	if low >= minLowSurrogate && low <= maxLowSurrogate && index > 0 {
+		high := chars[index-1]
+		if high >= minHighSurrogate && high <= maxHighSurrogate {
+			return charToCodePoint([]interface{}{high, low})
+		}
+	}
+	return low
+}
+
+// "java/lang/Character.digit(CI)I" and "java/lang/Character.digit(II)I"
+func charDigit(params []interface{}) interface{} {
+	radix := params[1].(int64)
+	r, ok := charCodePoint(params)
+	if !ok || radix < 2 || radix > 36 {
+		return int64(-1)
+	}
+	value := charDigitValue(r)
+	if value >= radix {
+		return int64(-1)
+	}
+	return value
+}
+
+// "java/lang/Character.forDigit(II)C"
+func charForDigit(params []interface{}) interface{} {
+	digit := params[0].(int64)
+	radix := params[1].(int64)
+	if radix < 2 || radix > 36 || digit < 0 || digit >= radix {
+		return int64(0)
+	}
+	if digit < 10 {
+		return '0' + digit
+	}
+	return 'a' + digit - 10
+}
+
+// "java/lang/Character.getNumericValue(C)I" and "java/lang/Character.getNumericValue(I)I"
+// Only the decimal digits and the Latin letters have numeric values here, so the numeric
+// characters that aren't decimal digits, such as Roman numerals, return -1.
+func charGetNumericValue(params []interface{}) interface{} {
+	r, ok := charCodePoint(params)
+	if !ok {
+		return int64(-1)
+	}
+	return charDigitValue(r)
+}
+
+// "java/lang/Character.highSurrogate(I)C"
+func charHighSurrogate(params []interface{}) interface{} {
+	cp := params[0].(int64)
+	return int64(uint16((cp >> 10) + (minHighSurrogate - (minSupplementaryCode >> 10))))
+}
+
+// "java/lang/Character.isAlphabetic(I)Z"
+func charIsAlphabetic(params []interface{}) interface{} {
+	return charHasProperty(params, func(r rune) bool {
+		return unicode.In(r, unicode.L, unicode.Nl, unicode.Other_Alphabetic)
+	})
+}
+
+// "java/lang/Character.isBmpCodePoint(I)Z"
+func charIsBmpCodePoint(params []interface{}) interface{} {
+	cp := params[0].(int64)
+	if cp >= 0 && cp < minSupplementaryCode {
 		return types.JavaBoolTrue
 	}
 	return types.JavaBoolFalse
 }
 
-// "java/lang/Character.isLetter(C)Z"
+// "java/lang/Character.isDigit(C)Z" and "java/lang/Character.isDigit(I)Z"
+func charIsDigit(params []interface{}) interface{} {
+	return charHasProperty(params, unicode.IsDigit)
+}
+
+// "java/lang/Character.isHighSurrogate(C)Z"
+func charIsHighSurrogate(params []interface{}) interface{} {
+	return charHasProperty(params, func(r rune) bool {
+		return r >= minHighSurrogate && r <= maxHighSurrogate
+	})
+}
+
+// "java/lang/Character.isISOControl(C)Z" and "java/lang/Character.isISOControl(I)Z"
+func charIsISOControl(params []interface{}) interface{} {
+	return charHasProperty(params, func(r rune) bool {
+		return r <= 0x1F || (r >= 0x7F && r <= 0x9F)
+	})
+}
+
+// "java/lang/Character.isLetter(C)Z" and "java/lang/Character.isLetter(I)Z"
 func charIsLetter(params []interface{}) interface{} {
-	ii := params[0].(int64)
-	if unicode.IsLetter(rune(ii)) {
+	return charHasProperty(params, unicode.IsLetter)
+}
+
+// "java/lang/Character.isLetterOrDigit(C)Z" and "java/lang/Character.isLetterOrDigit(I)Z"
+func charIsLetterOrDigit(params []interface{}) interface{} {
+	return charHasProperty(params, func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	})
+}
+
+// "java/lang/Character.isLowSurrogate(C)Z"
+func charIsLowSurrogate(params []interface{}) interface{} {
+	return charHasProperty(params, func(r rune) bool {
+		return r >= minLowSurrogate && r <= maxLowSurrogate
+	})
+}
+
+// "java/lang/Character.isLowerCase(C)Z" and "java/lang/Character.isLowerCase(I)Z"
+// Java counts the characters with the Other_Lowercase property, such as ª, as lowercase.
+func charIsLowerCase(params []interface{}) interface{} {
+	return charHasProperty(params, func(r rune) bool {
+		return unicode.In(r, unicode.Ll, unicode.Other_Lowercase)
+	})
+}
+
+// "java/lang/Character.isSpaceChar(C)Z" and "java/lang/Character.isSpaceChar(I)Z"
+func charIsSpaceChar(params []interface{}) interface{} {
+	return charHasProperty(params, func(r rune) bool {
+		return unicode.In(r, unicode.Zs, unicode.Zl, unicode.Zp)
+	})
+}
+
+// "java/lang/Character.isSupplementaryCodePoint(I)Z"
+func charIsSupplementaryCodePoint(params []interface{}) interface{} {
+	cp := params[0].(int64)
+	if cp >= minSupplementaryCode && cp <= maxCodePoint {
+		return types.JavaBoolTrue
+	}
+	return types.JavaBoolFalse
+}
+
+// "java/lang/Character.isSurrogate(C)Z"
+func charIsSurrogate(params []interface{}) interface{} {
+	return charHasProperty(params, func(r rune) bool {
+		return r >= minHighSurrogate && r <= maxLowSurrogate
+	})
+}
+
+// "java/lang/Character.isSurrogatePair(CC)Z"
+func charIsSurrogatePair(params []interface{}) interface{} {
+	high := params[0].(int64)
+	low := params[1].(int64)
+	if high >= minHighSurrogate && high <= maxHighSurrogate &&
+		low >= minLowSurrogate && low <= maxLowSurrogate {
+		return types.JavaBoolTrue
+	}
+	return types.JavaBoolFalse
+}
+
+// "java/lang/Character.isTitleCase(C)Z" and "java/lang/Character.isTitleCase(I)Z"
+func charIsTitleCase(params []interface{}) interface{} {
+	return charHasProperty(params, unicode.IsTitle)
+}
+
+// "java/lang/Character.isUpperCase(C)Z" and "java/lang/Character.isUpperCase(I)Z"
+// Java counts the characters with the Other_Uppercase property, such as Ⓐ, as uppercase.
+func charIsUpperCase(params []interface{}) interface{} {
+	return charHasProperty(params, func(r rune) bool {
+		return unicode.In(r, unicode.Lu, unicode.Other_Uppercase)
+	})
+}
+
+// "java/lang/Character.isValidCodePoint(I)Z"
+func charIsValidCodePoint(params []interface{}) interface{} {
+	if _, ok := charCodePoint(params); ok {
 		return types.JavaBoolTrue
 	}
 	return types.JavaBoolFalse
 }
 
-// "java/lang/Character.toLowerCase(C)C"
+// "java/lang/Character.isWhitespace(C)Z" and "java/lang/Character.isWhitespace(I)Z"
+// Java's whitespace is the Unicode space, line, and paragraph separators other than the
+// non-breaking spaces, plus the ASCII tab, line feed, vertical tab, form feed, carriage
+// return, and file, group, record, and unit separators.
+func charIsWhitespace(params []interface{}) interface{} {
+	return charHasProperty(params, func(r rune) bool {
+		switch {
+		case r >= '\t' && r <= '\r', r >= 0x1C && r <= 0x1F:
+			return true
+		case r == 0x00A0 || r == 0x2007 || r == 0x202F: // the non-breaking spaces
+			return false
+		}
+		return unicode.In(r, unicode.Zs, unicode.Zl, unicode.Zp)
+	})
+}
+
+// "java/lang/Character.lowSurrogate(I)C"
+func charLowSurrogate(params []interface{}) interface{} {
+	cp := params[0].(int64)
+	return int64(uint16((cp & 0x3FF) + minLowSurrogate))
+}
+
+// "java/lang/Character.toChars(I)[C"
+func charToChars(params []interface{}) interface{} {
+	cp := params[0].(int64)
+	var chars []int64
+	switch {
+	case cp >= 0 && cp < minSupplementaryCode:
+		chars = []int64{cp}
+	case cp >= minSupplementaryCode && cp <= maxCodePoint:
+		chars = []int64{charHighSurrogate(params).(int64), charLowSurrogate(params).(int64)}
+	default:
+		errMsg := fmt.Sprintf("Not a valid Unicode code point: 0x%X", uint32(cp))
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	return Populator("[C", types.CharArray, chars)
+}
+
+// "java/lang/Character.toCodePoint(CC)I"
+func charToCodePoint(params []interface{}) interface{} {
+	high := params[0].(int64)
+	low := params[1].(int64)
+	return ((high - minHighSurrogate) << 10) + (low - minLowSurrogate) + minSupplementaryCode
+}
+
+// "java/lang/Character.toLowerCase(C)C" and "java/lang/Character.toLowerCase(I)I"
 func charToLowerCase(params []interface{}) interface{} {
-	ii := params[0].(int64)
-	rr := unicode.ToLower(rune(ii))
-	return int64(rr)
+	return charMapCase(params, unicode.ToLower)
+}
+
+// "java/lang/Character.toTitleCase(C)C" and "java/lang/Character.toTitleCase(I)I"
+func charToTitleCase(params []interface{}) interface{} {
+	return charMapCase(params, unicode.ToTitle)
 }
 
-// "java/lang/Character.toUpperCase(C)C"
+// "java/lang/Character.toUpperCase(C)C" and "java/lang/Character.toUpperCase(I)I"
 func charToUpperCase(params []interface{}) interface{} {
-	ii := params[0].(int64)
-	rr := unicode.ToUpper(rune(ii))
-	return int64(rr)
+	return charMapCase(params, unicode.ToUpper)
 }
 
 // "java/lang/Character.valueOf(C)Ljava/lang/Character;"
@@ -10,6 +10,7 @@ import (
     "reflect"
     "testing"
 
+    "jacobin/src/excNames"
     "jacobin/src/globals"
     "jacobin/src/object"
     "jacobin/src/types"
@@ -34,6 +35,14 @@ func TestLoad_Lang_Character_RegistersMethods(t *testing.T) {
         {"java/lang/Character.toLowerCase(C)C", 1, charToLowerCase},
         {"java/lang/Character.toUpperCase(C)C", 1, charToUpperCase},
         {"java/lang/Character.valueOf(C)Ljava/lang/Character;", 1, characterValueOf},
+        {"java/lang/Character.isDigit(I)Z", 1, charIsDigit},
+        {"java/lang/Character.isWhitespace(C)Z", 1, charIsWhitespace},
+        {"java/lang/Character.toUpperCase(I)I", 1, charToUpperCase},
+        {"java/lang/Character.getNumericValue(C)I", 1, charGetNumericValue},
+        {"java/lang/Character.digit(CI)I", 2, charDigit},
+        {"java/lang/Character.isSurrogate(C)Z", 1, charIsSurrogate},
+        {"java/lang/Character.toChars(I)[C", 1, charToChars},
+        {"java/lang/Character.codePointAt([CI)I", 2, charCodePointAt},
     }
 
     for _, c := range checks {
@@ -90,3 +99,196 @@ func TestCharacter_ToLower_ToUpper_ValueOf_CharValue(t *testing.T) {
         t.Fatalf("charValue expected 'Q', got %v", cv)
     }
 }
+
+func TestCharacter_Properties_BeyondLatin1(t *testing.T) {
+    globals.InitGlobals("test")
+
+    isTrue := func(fn func([]interface{}) interface{}, cp rune) bool {
+        return fn([]interface{}{int64(cp)}).(int64) == types.JavaBoolTrue
+    }
+
+    checks := []struct {
+        name     string
+        fn       func([]interface{}) interface{}
+        cp       rune
+        expected bool
+    }{
+        {"isDigit(Arabic-Indic 3)", charIsDigit, 0x0663, true},
+        {"isDigit(Roman numeral one)", charIsDigit, 0x2160, false},
+        {"isLetter(Greek alpha)", charIsLetter, 'α', true},
+        {"isLetter(Deseret long I)", charIsLetter, 0x10400, true},
+        {"isLetterOrDigit('_')", charIsLetterOrDigit, '_', false},
+        {"isLetterOrDigit(Devanagari 7)", charIsLetterOrDigit, 0x096D, true},
+        {"isAlphabetic(Roman numeral one)", charIsAlphabetic, 0x2160, true},
+        {"isWhitespace(tab)", charIsWhitespace, '\t', true},
+        {"isWhitespace(unit separator)", charIsWhitespace, 0x1F, true},
+        {"isWhitespace(em space)", charIsWhitespace, 0x2003, true},
+        {"isWhitespace(no-break space)", charIsWhitespace, 0x00A0, false},
+        {"isSpaceChar(no-break space)", charIsSpaceChar, 0x00A0, true},
+        {"isSpaceChar(tab)", charIsSpaceChar, '\t', false},
+        {"isUpperCase(Cyrillic Zhe)", charIsUpperCase, 'Ж', true},
+        {"isUpperCase(circled A)", charIsUpperCase, 0x24B6, true},
+        {"isLowerCase(feminine ordinal)", charIsLowerCase, 0x00AA, true},
+        {"isLowerCase('A')", charIsLowerCase, 'A', false},
+        {"isTitleCase(Dz digraph)", charIsTitleCase, 0x01C5, true},
+        {"isISOControl(0x85)", charIsISOControl, 0x85, true},
+        {"isISOControl(' ')", charIsISOControl, ' ', false},
+        {"isSurrogate(0xDC00)", charIsSurrogate, 0xDC00, true},
+        {"isHighSurrogate(0xDBFF)", charIsHighSurrogate, 0xDBFF, true},
+        {"isHighSurrogate(0xDC00)", charIsHighSurrogate, 0xDC00, false},
+        {"isLowSurrogate(0xDFFF)", charIsLowSurrogate, 0xDFFF, true},
+        {"isValidCodePoint(0x10FFFF)", charIsValidCodePoint, 0x10FFFF, true},
+        {"isValidCodePoint(0x110000)", charIsValidCodePoint, 0x110000, false},
+        {"isValidCodePoint(-1)", charIsValidCodePoint, -1, false},
+        {"isBmpCodePoint(0xFFFF)", charIsBmpCodePoint, 0xFFFF, true},
+        {"isSupplementaryCodePoint(0xFFFF)", charIsSupplementaryCodePoint, 0xFFFF, false},
+        {"isSupplementaryCodePoint(0x1F600)", charIsSupplementaryCodePoint, 0x1F600, true},
+    }
+
+    for _, c := range checks {
+        if got := isTrue(c.fn, c.cp); got != c.expected {
+            t.Errorf("%s: expected %v, got %v", c.name, c.expected, got)
+        }
+    }
+}
+
+func TestCharacter_CaseMappings_BeyondLatin1(t *testing.T) {
+    globals.InitGlobals("test")
+
+    checks := []struct {
+        name     string
+        fn       func([]interface{}) interface{}
+        cp       int64
+        expected int64
+    }{
+        {"toUpperCase(ÿ)", charToUpperCase, 0xFF, 0x178},
+        {"toUpperCase(ß)", charToUpperCase, 0xDF, 0xDF},
+        {"toUpperCase(Deseret long i)", charToUpperCase, 0x10428, 0x10400},
+        {"toLowerCase(Cyrillic Zhe)", charToLowerCase, 'Ж', 'ж'},
+        {"toLowerCase(I with dot)", charToLowerCase, 0x130, 'i'},
+        {"toTitleCase(dz digraph)", charToTitleCase, 0x01C6, 0x01C5},
+        {"toUpperCase(invalid)", charToUpperCase, -5, -5},
+    }
+
+    for _, c := range checks {
+        if got := c.fn([]interface{}{c.cp}).(int64); got != c.expected {
+            t.Errorf("%s: expected 0x%X, got 0x%X", c.name, c.expected, got)
+        }
+    }
+}
+
+func TestCharacter_NumericValues(t *testing.T) {
+    globals.InitGlobals("test")
+
+    numeric := []struct {
+        cp       rune
+        expected int64
+    }{
+        {'7', 7},
+        {'a', 10},
+        {'Z', 35},
+        {0xFF41, 10}, // fullwidth a
+        {0x0669, 9},  // Arabic-Indic 9
+        {0x1D7D8, 0}, // mathematical double-struck 0, in a run of 50 digits
+        {0x1D7E3, 1}, // mathematical sans-serif 1
+        {'$', -1},
+    }
+    for _, n := range numeric {
+        if got := charGetNumericValue([]interface{}{int64(n.cp)}).(int64); got != n.expected {
+            t.Errorf("getNumericValue(0x%X): expected %d, got %d", n.cp, n.expected, got)
+        }
+    }
+
+    digits := []struct {
+        cp       rune
+        radix    int64
+        expected int64
+    }{
+        {'7', 10, 7},
+        {'7', 7, -1},
+        {'f', 16, 15},
+        {'F', 16, 15},
+        {'g', 16, -1},
+        {'z', 36, 35},
+        {'1', 37, -1},
+        {'1', 1, -1},
+        {0x0966, 10, 0}, // Devanagari 0
+    }
+    for _, d := range digits {
+        if got := charDigit([]interface{}{int64(d.cp), d.radix}).(int64); got != d.expected {
+            t.Errorf("digit(0x%X, %d): expected %d, got %d", d.cp, d.radix, d.expected, got)
+        }
+    }
+
+    if got := charForDigit([]interface{}{int64(11), int64(16)}).(int64); got != 'b' {
+        t.Errorf("forDigit(11, 16): expected 'b', got %c", rune(got))
+    }
+    if got := charForDigit([]interface{}{int64(16), int64(16)}).(int64); got != 0 {
+        t.Errorf("forDigit(16, 16): expected 0, got %d", got)
+    }
+}
+
+func TestCharacter_CodePoints(t *testing.T) {
+    globals.InitGlobals("test")
+
+    const emoji = int64(0x1F600) // encoded as 😀
+    high := charHighSurrogate([]interface{}{emoji}).(int64)
+    low := charLowSurrogate([]interface{}{emoji}).(int64)
+    if high != 0xD83D || low != 0xDE00 {
+        t.Fatalf("surrogates of 0x1F600: expected 0xD83D 0xDE00, got 0x%X 0x%X", high, low)
+    }
+    if cp := charToCodePoint([]interface{}{high, low}).(int64); cp != emoji {
+        t.Errorf("toCodePoint: expected 0x%X, got 0x%X", emoji, cp)
+    }
+    if charIsSurrogatePair([]interface{}{high, low}).(int64) != types.JavaBoolTrue {
+        t.Errorf("isSurrogatePair(0xD83D, 0xDE00): expected true")
+    }
+    if charIsSurrogatePair([]interface{}{low, high}).(int64) != types.JavaBoolFalse {
+        t.Errorf("isSurrogatePair(0xDE00, 0xD83D): expected false")
+    }
+    if n := charCharCount([]interface{}{emoji}).(int64); n != 2 {
+        t.Errorf("charCount(0x1F600): expected 2, got %d", n)
+    }
+    if n := charCharCount([]interface{}{int64('A')}).(int64); n != 1 {
+        t.Errorf("charCount('A'): expected 1, got %d", n)
+    }
+
+    arr := charToChars([]interface{}{emoji}).(*object.Object)
+    chars := arr.FieldTable["value"].Fvalue.([]int64)
+    if len(chars) != 2 || chars[0] != high || chars[1] != low {
+        t.Fatalf("toChars(0x1F600): expected [0xD83D 0xDE00], got %v", chars)
+    }
+    arr = charToChars([]interface{}{int64('é')}).(*object.Object)
+    if bmp := arr.FieldTable["value"].Fvalue.([]int64); len(bmp) != 1 || bmp[0] != 'é' {
+        t.Errorf("toChars('é'): expected ['é'], got %v", bmp)
+    }
+    if errBlk, ok := charToChars([]interface{}{int64(0x110000)}).(*GErrBlk); !ok {
+        t.Errorf("toChars(0x110000): expected an error block")
+    } else if errBlk.ErrMsg != "Not a valid Unicode code point: 0x110000" {
+        t.Errorf("toChars(0x110000): unexpected message: %s", errBlk.ErrMsg)
+    }
+
+    // the chars 'x', \uD83D, \uDE00, 'y'
+    charArr := Populator("[C", types.CharArray, []int64{'x', high, low, 'y'})
+    at := func(index int64) interface{} { return charCodePointAt([]interface{}{charArr, index}) }
+    before := func(index int64) interface{} { return charCodePointBefore([]interface{}{charArr, index}) }
+
+    if cp := at(1).(int64); cp != emoji {
+        t.Errorf("codePointAt(1): expected 0x%X, got 0x%X", emoji, cp)
+    }
+    if cp := at(2).(int64); cp != low {
+        t.Errorf("codePointAt(2): expected the unpaired low surrogate, got 0x%X", cp)
+    }
+    if cp := before(3).(int64); cp != emoji {
+        t.Errorf("codePointBefore(3): expected 0x%X, got 0x%X", emoji, cp)
+    }
+    if cp := before(2).(int64); cp != high {
+        t.Errorf("codePointBefore(2): expected the unpaired high surrogate, got 0x%X", cp)
+    }
+    if errBlk, ok := at(4).(*GErrBlk); !ok || errBlk.ExceptionType != excNames.ArrayIndexOutOfBoundsException {
+        t.Errorf("codePointAt(4): expected ArrayIndexOutOfBoundsException, got %v", at(4))
+    }
+    if errBlk, ok := before(0).(*GErrBlk); !ok || errBlk.ErrMsg != "Index -1 out of bounds for length 4" {
+        t.Errorf("codePointBefore(0): expected ArrayIndexOutOfBoundsException, got %v", before(0))
+    }
+}
@@ -7,10 +7,12 @@
 package gfunction
 
 import (
+	"container/list"
 	"errors"
 	"fmt"
 	"jacobin/src/classloader"
 	"jacobin/src/excNames"
+	"jacobin/src/globals"
 	"jacobin/src/object"
 	"jacobin/src/shutdown"
 	"jacobin/src/statics"
@@ -28,6 +30,18 @@ func Load_Lang_Class() {
 	// There is no <clinit> for java/lang/Class.
 	// The <clinit> type of code is executed in gfunction.go classClinitIsh().
 
+	MethodSignatures["java/lang/Class.arrayType()Ljava/lang/Class;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  classArrayType,
+		}
+
+	MethodSignatures["java/lang/Class.componentType()Ljava/lang/Class;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  getComponentType,
+		}
+
 	MethodSignatures["java/lang/Class.desiredAssertionStatus()Z"] =
 		GMeth{
 			ParamSlots: 0,
@@ -40,6 +54,20 @@ func Load_Lang_Class() {
 			GFunction:  getAssertionsEnabledStatus,
 		}
 
+	MethodSignatures["java/lang/Class.forName(Ljava/lang/String;)Ljava/lang/Class;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    classForName,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/Class.forName(Ljava/lang/String;ZLjava/lang/ClassLoader;)Ljava/lang/Class;"] =
+		GMeth{
+			ParamSlots:   3,
+			GFunction:    classForNameWithInit,
+			NeedsContext: true,
+		}
+
 	MethodSignatures["java/lang/Class.getComponentType()Ljava/lang/Class;"] =
 		GMeth{
 			ParamSlots: 0,
@@ -70,6 +98,18 @@ func Load_Lang_Class() {
 			GFunction:  classIsArray,
 		}
 
+	MethodSignatures["java/lang/Class.isPrimitive()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  classIsPrimitive,
+		}
+
+	MethodSignatures["java/lang/Class.isSynthetic()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  classIsSynthetic,
+		}
+
 	MethodSignatures["java/lang/Class.registerNatives()V"] =
 		GMeth{
 			ParamSlots: 0,
@@ -78,104 +118,215 @@ func Load_Lang_Class() {
 
 }
 
-// getComponentType() returns a pointer to class of the type of an array.
-// primitive arrays return the boxed class type, e.g. int[] returns Integer.class.
+// classForName() loads and initializes the named class and returns its Class object.
+// "java/lang/Class.forName(Ljava/lang/String;)Ljava/lang/Class;"
+func classForName(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	return forName(params[1], true, fs)
+}
+
+// classForNameWithInit() loads the named class, initializing it only if the
+// boolean parameter is true, and returns its Class object. Jacobin has a single
+// application classloader, so the ClassLoader parameter is ignored.
+// "java/lang/Class.forName(Ljava/lang/String;ZLjava/lang/ClassLoader;)Ljava/lang/Class;"
+func classForNameWithInit(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	initialize := params[2].(int64) == types.JavaBoolTrue
+	return forName(params[1], initialize, fs)
+}
+
+// forName() does the work for the Class.forName() methods. The name is in the
+// format returned by Class.getName(): java.lang.String for classes and interfaces,
+// and descriptors such as [I or [Ljava.lang.String; for arrays. Loading an array
+// class loads its element class, but does not initialize it.
+func forName(nameParam interface{}, initialize bool, fs *list.List) interface{} {
+	if object.IsNull(nameParam) {
+		return getGErrBlk(excNames.NullPointerException, "Class.forName: null class name")
+	}
+
+	name := object.GoStringFromStringObject(nameParam.(*object.Object))
+	if name == "" || strings.Contains(name, "/") {
+		errMsg := fmt.Sprintf("Class.forName: invalid class name: %s", name)
+		return getGErrBlk(excNames.ClassNotFoundException, errMsg)
+	}
+	className := strings.ReplaceAll(name, ".", "/")
+
+	// for arrays, validate the descriptor and find the element class, if any
+	elementName := className
+	if strings.HasPrefix(className, types.Array) {
+		elementType := strings.TrimLeft(className, types.Array)
+		switch {
+		case len(elementType) == 1 && types.IsPrimitive(elementType):
+			elementName = "" // no class to load
+		case strings.HasPrefix(elementType, types.Ref) && strings.HasSuffix(elementType, ";") &&
+			len(elementType) > 2:
+			elementName = elementType[1 : len(elementType)-1]
+		default:
+			errMsg := fmt.Sprintf("Class.forName: invalid array class name: %s", name)
+			return getGErrBlk(excNames.ClassNotFoundException, errMsg)
+		}
+		initialize = false
+	}
+
+	if elementName != "" && classloader.MethAreaFetch(elementName) == nil {
+		if err := classloader.LoadClassFromNameOnly(elementName); err != nil {
+			errMsg := fmt.Sprintf("Class.forName: could not load class %s", name)
+			return getGErrBlk(excNames.ClassNotFoundException, errMsg)
+		}
+	}
+
+	// initialization runs the class's <clinit>, if it has not been run yet
+	if initialize {
+		k := classloader.MethAreaFetch(className)
+		if k != nil && k.Data != nil && k.Data.ClInit == types.ClInitError {
+			errMsg := fmt.Sprintf("Class.forName: could not initialize class %s", name)
+			return getGErrBlk(excNames.NoClassDefFoundError, errMsg)
+		}
+		if k != nil && k.Data != nil && k.Data.ClInit == types.ClInitNotRun {
+			if _, err := globals.GetGlobalRef().FuncInstantiateClass(className, fs); err != nil {
+				errMsg := fmt.Sprintf("Class.forName: could not initialize class %s", name)
+				return getGErrBlk(excNames.ExceptionInInitializerError, errMsg)
+			}
+		}
+	}
+
+	return classloader.GetClassObject(stringPool.GetStringIndex(&className))
+}
+
+// getComponentType() returns the Class object of the type of an array's elements.
+// primitive arrays return the Class object of the primitive, e.g. int[] returns int.class.
 // multidimensional arrays return an array one dimension less, e.g. int[][] returns int[].class.
-// Note: at present, this function returns a pointer to the loaded (but not instantiated) class.
+// The receiver is normally a Class object, but an array object is also accepted.
+// Classes that are not arrays return null.
+// "java/lang/Class.getComponentType()Ljava/lang/Class;" and componentType()
 func getComponentType(params []interface{}) interface{} {
 	objPtr := params[0].(*object.Object)
 
-	field := (*objPtr).FieldTable["value"]
+	arrayType := classloader.ClassNameFromClassObject(objPtr)
+	if arrayType == "" { // not a Class object, so see whether it's an array itself
+		arrayType = (*objPtr).FieldTable["value"].Ftype
+	}
+
 	// If the object is not an array, return null.
-	if !types.IsArray(field.Ftype) {
+	if !types.IsArray(arrayType) {
 		return object.Null
 	}
 
-	componentType := field.Ftype[1:] // remove the leading '['
+	componentType := arrayType[1:] // remove the leading '['
 	if types.IsArray(componentType) {
-		// If it's a multidimensional array, we return the pointer to the next dimension.
-		return field.Fvalue
+		// If it's a multidimensional array, we return the class of the next dimension.
+		return classloader.GetClassObject(stringPool.GetStringIndex(&componentType))
 	}
 
-	// If it's a primitive array, we return the boxed class type.
+	// If it's a primitive array, we return the primitive's class.
 	if types.IsPrimitive(componentType) {
-		// Convert the primitive type to its boxed class type.
-		switch componentType {
-		case types.Byte:
-			componentType = "java/lang/Byte"
-		case types.Char:
-			componentType = "java/lang/Character"
-		case types.Double:
-			componentType = "java/lang/Double"
-		case types.Float:
-			componentType = "java/lang/Float"
-		case types.Int:
-			componentType = "java/lang/Integer"
-		case types.Long:
-			componentType = "java/lang/Long"
-		case types.Short:
-			componentType = "java/lang/Short"
-		case types.Bool:
-			componentType = "java/lang/Boolean"
-		default:
+		cl := primitiveClassObject(componentType)
+		if cl == nil {
 			errMsg := fmt.Sprintf("getComponentType: unrecognized primitive type %s", componentType)
 			return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 		}
-	} else {
-		componentType = strings.TrimPrefix(componentType, types.Ref) // remove the leading 'L'
-		componentType = strings.TrimSuffix(componentType, ";")       // remove the trailing ';'
+		return cl
 	}
 
+	componentType = strings.TrimPrefix(componentType, types.Ref) // remove the leading 'L'
+	componentType = strings.TrimSuffix(componentType, ";")       // remove the trailing ';'
+
 	// Load the class for the component type.
-	cl, err := simpleClassLoadByName(componentType)
+	_, err := simpleClassLoadByName(componentType)
 	if err != nil {
 		errMsg := fmt.Sprintf("getComponentType: failed to load class %s: %s", componentType, err.Error())
 		return getGErrBlk(excNames.ClassNotFoundException, errMsg)
 	}
 
-	return cl
+	return classloader.GetClassObject(stringPool.GetStringIndex(&componentType))
+}
+
+// The primitive types have Class objects, such as int.class, whose names are the Java
+// keywords. They're used as the component types of arrays of primitives.
+var primitiveClassNames = map[string]string{
+	types.Bool:   "boolean",
+	types.Byte:   "byte",
+	types.Char:   "char",
+	types.Rune:   "char",
+	types.Double: "double",
+	types.Float:  "float",
+	types.Int:    "int",
+	types.Long:   "long",
+	types.Short:  "short",
+}
+
+// returns the Class object of the primitive whose descriptor is passed, such as "I" for
+// int.class, or nil if the descriptor is not that of a primitive
+func primitiveClassObject(descriptor string) *object.Object {
+	name, ok := primitiveClassNames[descriptor]
+	if !ok {
+		return nil
+	}
+	return classloader.GetClassObject(stringPool.GetStringIndex(&name))
+}
+
+// classDescriptor returns the field descriptor of the type of the Class object, such as
+// "I" for int.class, "Ljava/lang/String;" for String.class, and "[I" for int[].class. It
+// returns "" for void.class and for objects that aren't Class objects.
+func classDescriptor(cl *object.Object) string {
+	name := classloader.ClassNameFromClassObject(cl)
+	switch {
+	case name == "" || name == "void":
+		return ""
+	case strings.HasPrefix(name, types.Array):
+		return name
+	}
+	for descriptor, keyword := range primitiveClassNames {
+		if name == keyword && descriptor != types.Rune {
+			return descriptor
+		}
+	}
+	return types.Ref + name + ";"
 }
 
-// getPrimitiveClass() takes a one-word descriptor of a primitive and
-// returns  apointer to the native primitive class that corresponds to it.
-// This duplicates the behavior of OpenJDK JVMs.
+// "java/lang/Class.arrayType()Ljava/lang/Class;" -- the Class object of the arrays whose
+// elements are of this class, e.g. int[].class for int.class
+func classArrayType(params []interface{}) interface{} {
+	descriptor := classDescriptor(params[0].(*object.Object))
+	if descriptor == "" {
+		return object.Null // void.class has no array type
+	}
+	arrayType := types.Array + descriptor
+	return classloader.GetClassObject(stringPool.GetStringIndex(&arrayType))
+}
+
+// "java/lang/Class.isPrimitive()Z"
+func classIsPrimitive(params []interface{}) interface{} {
+	name := classloader.ClassNameFromClassObject(params[0].(*object.Object))
+	if name == "void" {
+		return types.JavaBoolTrue
+	}
+	for _, keyword := range primitiveClassNames {
+		if name == keyword {
+			return types.JavaBoolTrue
+		}
+	}
+	return types.JavaBoolFalse
+}
+
+// getPrimitiveClass() takes the name of a primitive type, such as "int", and returns
+// the cached Class object for it, such as int.class (which is Integer.TYPE). It's the
+// same object that getComponentType() returns for int[].class.
 // "java/lang/Class.getPrimitiveClass(Ljava/lang/String;)Ljava/lang/Class;"
 func getPrimitiveClass(params []interface{}) interface{} {
 	primitive := params[0].(*object.Object)
 	str := object.GoStringFromStringObject(primitive)
 
-	var k *classloader.Klass
-	var err error
-	switch str {
-	case "boolean":
-		k, err = simpleClassLoadByName("java/lang/Boolean")
-	case "byte":
-		k, err = simpleClassLoadByName("java/lang/Byte")
-	case "char":
-		k, err = simpleClassLoadByName("java/lang/Character")
-	case "double":
-		k, err = simpleClassLoadByName("java/lang/Double")
-	case "float":
-		k, err = simpleClassLoadByName("java/lang/Float")
-	case "int":
-		k, err = simpleClassLoadByName("java/lang/Integer")
-	case "long":
-		k, err = simpleClassLoadByName("java/lang/Long")
-	case "short":
-		k, err = simpleClassLoadByName("java/lang/Short")
-	case "void":
-		k, err = simpleClassLoadByName("java/lang/Void")
-	default:
-		k = nil
-		err = errors.New("getPrimitiveClass: unrecognized primitive")
-	}
-
-	if err == nil {
-		return k
-	} else {
-		errMsg := fmt.Sprintf("getPrimitiveClass: %s: %s", err.Error(), str)
-		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	if str == "void" {
+		return classloader.GetClassObject(stringPool.GetStringIndex(&str))
 	}
+	for descriptor, keyword := range primitiveClassNames {
+		if str == keyword {
+			return primitiveClassObject(descriptor)
+		}
+	}
+	errMsg := fmt.Sprintf("getPrimitiveClass: unrecognized primitive: %s", str)
+	return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 }
 
 // simpleClassLoadByName() just checks the MethodArea cache for the loaded
@@ -203,12 +354,23 @@ func simpleClassLoadByName(className string) (*classloader.Klass, error) {
 	}
 }
 
-// returns boolean indicating whether assertions are enabled or not.
+// returns boolean indicating whether assertions are enabled in the class, as set by
+// -ea, -da, -esa, and -dsa (see globals/assertions.go). The static initializer that
+// javac generates for a class with assert statements sets its $assertionsDisabled
+// from this.
 // "java/lang/Class.desiredAssertionStatus()Z"
 // "java/lang/Class.desiredAssertionStatus0()Z"
-func getAssertionsEnabledStatus([]interface{}) interface{} {
-	// note that statics have been preloaded before this function
-	// can be called, and CLI processing has also occurred. So, we
+func getAssertionsEnabledStatus(params []interface{}) interface{} {
+	if len(params) > 0 {
+		if clazz, ok := params[0].(*object.Object); ok {
+			if className := classloader.ClassNameFromClassObject(clazz); className != "" {
+				return object.JavaBooleanFromGoBoolean(globals.DesiredAssertionStatus(className))
+			}
+		}
+	}
+
+	// without a class, the default status applies. Note that statics have been preloaded
+	// before this function can be called, and CLI processing has also occurred. So, we
 	// know we have the latest assertion-enabled status.
 	x := statics.Statics["main.$assertionsDisabled"].Value.(int64)
 	if x == 1 {
@@ -216,19 +378,24 @@ func getAssertionsEnabledStatus([]interface{}) interface{} {
 	} else {
 		return types.JavaBoolTrue
 	}
-	// return 1 - x // return the 0 if disabled, 1 if not.
 }
 
 // "java/lang/Class.getName()Ljava/lang/String;"
 func getName(params []interface{}) interface{} {
 	primitive := params[0].(*object.Object)
+	if name, ok := primitive.FieldTable["name"]; ok && classloader.ClassNameFromClassObject(primitive) != "" {
+		return name.Fvalue // a Class object from the cache holds its name as a String object
+	}
 	str := object.GoStringFromStringObject(primitive)
 	return str
 }
 
-// "java/lang/Class.getName()Ljava/lang/String;"
+// "java/lang/Class.isArray()Z"
 func classIsArray(params []interface{}) interface{} {
 	obj := params[0].(*object.Object)
+	if strings.HasPrefix(classloader.ClassNameFromClassObject(obj), types.Array) {
+		return types.JavaBoolTrue
+	}
 	fldType := obj.FieldTable["value"].Ftype
 	if strings.HasPrefix(fldType, types.Array) {
 		return types.JavaBoolTrue
@@ -236,6 +403,17 @@ func classIsArray(params []interface{}) interface{} {
 	return types.JavaBoolFalse
 }
 
+// classIsSynthetic reports whether the class was generated by the compiler, as
+// lambda and nested-class helpers are, rather than declared in the source.
+// "java/lang/Class.isSynthetic()Z"
+func classIsSynthetic(params []interface{}) interface{} {
+	obj := params[0].(*object.Object)
+	if classloader.IsClassSynthetic(classloader.ClassNameFromClassObject(obj)) {
+		return types.JavaBoolTrue
+	}
+	return types.JavaBoolFalse
+}
+
 // classgetModule returns the unnamed module for any Class object
 func classGetModule(params []interface{}) interface{} {
 	if unnamedModule == nil {
@@ -7,6 +7,7 @@
 package gfunction
 
 import (
+	"container/list"
 	"io"
 	"jacobin/src/classloader"
 	"jacobin/src/excNames"
@@ -14,6 +15,7 @@ import (
 	"jacobin/src/globals"
 	"jacobin/src/object"
 	"jacobin/src/statics"
+	"jacobin/src/stringPool"
 	"jacobin/src/types"
 	"os"
 	"strings"
@@ -33,19 +35,32 @@ func setup() {
 	}
 }
 
-func TestGetPrimitiveClass_Boolean(t *testing.T) {
-	setup()
+// checks that getPrimitiveClass() returns the cached Class object of the primitive
+func checkPrimitiveClass(t *testing.T, keyword string) {
+	t.Helper()
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
 
-	obj := object.StringObjectFromGoString("boolean")
-	params := []interface{}{obj}
-	result := getPrimitiveClass(params)
-	if _, ok := result.(*classloader.Klass); !ok {
-		t.Errorf("Expected *classloader.Klass, got %T", result)
+	result := getPrimitiveClass([]interface{}{object.StringObjectFromGoString(keyword)})
+	cl, ok := result.(*object.Object)
+	if !ok {
+		t.Fatalf("Expected a Class object, got %T", result)
+	}
+	if name := classloader.ClassNameFromClassObject(cl); name != keyword {
+		t.Errorf("Expected %s.class, got %s", keyword, name)
+	}
+	if classloader.GetClassObject(stringPool.GetStringIndex(&keyword)) != cl {
+		t.Errorf("Expected the cached Class object for %s", keyword)
 	}
 }
 
+func TestGetPrimitiveClass_Boolean(t *testing.T) {
+	checkPrimitiveClass(t, "boolean")
+}
+
 func TestGetPrimitiveClass_UnrecognizedPrimitive(t *testing.T) {
-	setup()
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
 
 	obj := object.StringObjectFromGoString("unknown")
 	params := []interface{}{obj}
@@ -56,91 +71,35 @@ func TestGetPrimitiveClass_UnrecognizedPrimitive(t *testing.T) {
 }
 
 func TestGetPrimitiveClass_Byte(t *testing.T) {
-	setup()
-
-	obj := object.StringObjectFromGoString("byte")
-	params := []interface{}{obj}
-	result := getPrimitiveClass(params)
-	if _, ok := result.(*classloader.Klass); !ok {
-		t.Errorf("Expected *classloader.Klass, got %T", result)
-	}
+	checkPrimitiveClass(t, "byte")
 }
 
 func TestGetPrimitiveClass_Char(t *testing.T) {
-	setup()
-
-	obj := object.StringObjectFromGoString("char")
-	params := []interface{}{obj}
-	result := getPrimitiveClass(params)
-	if _, ok := result.(*classloader.Klass); !ok {
-		t.Errorf("Expected *classloader.Klass, got %T", result)
-	}
+	checkPrimitiveClass(t, "char")
 }
 
 func TestGetPrimitiveClass_Double(t *testing.T) {
-	setup()
-
-	obj := object.StringObjectFromGoString("double")
-	params := []interface{}{obj}
-	result := getPrimitiveClass(params)
-	if _, ok := result.(*classloader.Klass); !ok {
-		t.Errorf("Expected *classloader.Klass, got %T", result)
-	}
+	checkPrimitiveClass(t, "double")
 }
 
 func TestGetPrimitiveClass_Float(t *testing.T) {
-	setup()
-
-	obj := object.StringObjectFromGoString("float")
-	params := []interface{}{obj}
-	result := getPrimitiveClass(params)
-	if _, ok := result.(*classloader.Klass); !ok {
-		t.Errorf("Expected *classloader.Klass, got %T", result)
-	}
+	checkPrimitiveClass(t, "float")
 }
 
 func TestGetPrimitiveClass_Int(t *testing.T) {
-	setup()
-
-	obj := object.StringObjectFromGoString("int")
-	params := []interface{}{obj}
-	result := getPrimitiveClass(params)
-	if _, ok := result.(*classloader.Klass); !ok {
-		t.Errorf("Expected *classloader.Klass, got %T", result)
-	}
+	checkPrimitiveClass(t, "int")
 }
 
 func TestGetPrimitiveClass_Long(t *testing.T) {
-	setup()
-
-	obj := object.StringObjectFromGoString("long")
-	params := []interface{}{obj}
-	result := getPrimitiveClass(params)
-	if _, ok := result.(*classloader.Klass); !ok {
-		t.Errorf("Expected *classloader.Klass, got %T", result)
-	}
+	checkPrimitiveClass(t, "long")
 }
 
 func TestGetPrimitiveClass_Short(t *testing.T) {
-	setup()
-
-	obj := object.StringObjectFromGoString("short")
-	params := []interface{}{obj}
-	result := getPrimitiveClass(params)
-	if _, ok := result.(*classloader.Klass); !ok {
-		t.Errorf("Expected *classloader.Klass, got %T", result)
-	}
+	checkPrimitiveClass(t, "short")
 }
 
 func TestGetPrimitiveClass_Void(t *testing.T) {
-	setup()
-
-	obj := object.StringObjectFromGoString("void")
-	params := []interface{}{obj}
-	result := getPrimitiveClass(params)
-	if _, ok := result.(*classloader.Klass); !ok {
-		t.Errorf("Expected *classloader.Klass, got %T", result)
-	}
+	checkPrimitiveClass(t, "void")
 }
 
 func TestSimpleClassLoadByName(t *testing.T) {
@@ -200,6 +159,25 @@ func TestAssertionsEnabledStatus_Enabled(t *testing.T) {
 	}
 }
 
+func TestAssertionsEnabledStatus_ForClass(t *testing.T) {
+	globals.InitGlobals("test")
+	statics.LoadProgramStatics()
+	defer globals.ResetAssertionStatus()
+	globals.SetAssertionStatus("com.acme...", true) // -ea:com.acme...
+
+	classFor := func(name string) *object.Object {
+		clazz := object.MakeEmptyObject()
+		clazz.FieldTable["$klass"] = object.Field{Ftype: types.Ref, Fvalue: stringPool.GetStringIndex(&name)}
+		return clazz
+	}
+	if getAssertionsEnabledStatus([]interface{}{classFor("com/acme/Tool")}) != types.JavaBoolTrue {
+		t.Errorf("Expected assertions to be enabled in com/acme/Tool")
+	}
+	if getAssertionsEnabledStatus([]interface{}{classFor("org/other/Main")}) != types.JavaBoolFalse {
+		t.Errorf("Expected assertions to be disabled in org/other/Main")
+	}
+}
+
 func TestGetNameWithAStringObject(t *testing.T) {
 	setup()
 	obj := object.StringObjectFromGoString("java/lang/String")
@@ -209,3 +187,171 @@ func TestGetNameWithAStringObject(t *testing.T) {
 		t.Errorf("Expected java/lang/String, got %s", result)
 	}
 }
+
+func TestGetNameWithAClassObject(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	name := "[Ljava/lang/String;"
+	cl := classloader.GetClassObject(stringPool.GetStringIndex(&name))
+	result := getName([]interface{}{cl})
+	str := object.GoStringFromStringObject(result.(*object.Object))
+	if str != "[Ljava.lang.String;" {
+		t.Errorf("Expected [Ljava.lang.String;, got %s", str)
+	}
+}
+
+func TestGetComponentTypeOfMultiDimArrayClass(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	name := "[[I"
+	cl := classloader.GetClassObject(stringPool.GetStringIndex(&name))
+	result := getComponentType([]interface{}{cl})
+
+	component := "[I"
+	expected := classloader.GetClassObject(stringPool.GetStringIndex(&component))
+	if result != expected {
+		t.Errorf("Expected the cached Class object for [I, got %v", result)
+	}
+
+	if classIsArray([]interface{}{cl}) != types.JavaBoolTrue {
+		t.Error("Expected isArray() to be true for [[I")
+	}
+}
+
+func TestGetComponentTypeOfPrimitiveArrayClass(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	name := "[J"
+	cl := classloader.GetClassObject(stringPool.GetStringIndex(&name))
+	result := getComponentType([]interface{}{cl}).(*object.Object)
+	if classloader.ClassNameFromClassObject(result) != "long" {
+		t.Errorf("Expected long.class, got %s", classloader.ClassNameFromClassObject(result))
+	}
+	if classIsPrimitive([]interface{}{result}) != types.JavaBoolTrue {
+		t.Error("Expected isPrimitive() to be true for long.class")
+	}
+	if classIsPrimitive([]interface{}{cl}) != types.JavaBoolFalse {
+		t.Error("Expected isPrimitive() to be false for long[].class")
+	}
+
+	// arrayType() is the inverse of getComponentType()
+	if classArrayType([]interface{}{result}) != cl {
+		t.Error("Expected arrayType() of long.class to be long[].class")
+	}
+	str := types.StringClassName
+	strClass := classloader.GetClassObject(stringPool.GetStringIndex(&str))
+	arrayClass := classArrayType([]interface{}{strClass}).(*object.Object)
+	if classloader.ClassNameFromClassObject(arrayClass) != "[Ljava/lang/String;" {
+		t.Errorf("Expected String[].class, got %s", classloader.ClassNameFromClassObject(arrayClass))
+	}
+}
+
+// int[].class.getComponentType() == Integer.TYPE
+func TestGetPrimitiveClassIsComponentType(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	name := "[I"
+	arrayClass := classloader.GetClassObject(stringPool.GetStringIndex(&name))
+	component := getComponentType([]interface{}{arrayClass})
+	primitive := getPrimitiveClass([]interface{}{object.StringObjectFromGoString("int")})
+	if component != primitive {
+		t.Errorf("Expected getComponentType() of [I and getPrimitiveClass(\"int\") to be the same object, got %v and %v",
+			component, primitive)
+	}
+}
+
+func TestGetComponentTypeOfNonArrayClass(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	name := "pkg/NotAnArray"
+	cl := classloader.GetClassObject(stringPool.GetStringIndex(&name))
+	if result := getComponentType([]interface{}{cl}); result != object.Null {
+		t.Errorf("Expected null for a class that is not an array, got %v", result)
+	}
+
+	if classIsArray([]interface{}{cl}) != types.JavaBoolFalse {
+		t.Error("Expected isArray() to be false for pkg/NotAnArray")
+	}
+}
+
+func TestClassIsSynthetic(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	synth := "pkg/Outer$1"
+	classloader.MethAreaInsert(synth, &classloader.Klass{Status: 'F', Data: &classloader.ClData{
+		Name:   synth,
+		Access: classloader.AccessFlags{ClassIsSynthetic: true},
+	}})
+	cl := classloader.GetClassObject(stringPool.GetStringIndex(&synth))
+	if classIsSynthetic([]interface{}{cl}) != types.JavaBoolTrue {
+		t.Error("Expected isSynthetic() to be true for pkg/Outer$1")
+	}
+
+	plain := "pkg/NotSynthetic"
+	cl = classloader.GetClassObject(stringPool.GetStringIndex(&plain))
+	if classIsSynthetic([]interface{}{cl}) != types.JavaBoolFalse {
+		t.Error("Expected isSynthetic() to be false for pkg/NotSynthetic")
+	}
+}
+
+func TestForNameOfPrimitiveArray(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	params := []interface{}{list.New(), object.StringObjectFromGoString("[I")}
+	result := classForName(params)
+
+	name := "[I"
+	if result != classloader.GetClassObject(stringPool.GetStringIndex(&name)) {
+		t.Errorf("Expected the cached Class object for [I, got %v", result)
+	}
+}
+
+func TestForNameOfLoadedClass(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	k := classloader.Klass{Status: 'F', Loader: "testloader", Data: &classloader.ClData{
+		Name:            "pkg/Loaded",
+		SuperclassIndex: types.ObjectPoolStringIndex,
+		ClInit:          types.ClInitRun,
+	}}
+	classloader.MethAreaInsert("pkg/Loaded", &k)
+
+	params := []interface{}{list.New(), object.StringObjectFromGoString("pkg.Loaded"),
+		types.JavaBoolFalse, object.Null}
+	result := classForNameWithInit(params)
+	if classloader.ClassNameFromClassObject(result.(*object.Object)) != "pkg/Loaded" {
+		t.Errorf("Expected Class object for pkg/Loaded, got %v", result)
+	}
+
+	// the same Class object should be returned when obtained via the one-parameter forName()
+	params = []interface{}{list.New(), object.StringObjectFromGoString("pkg.Loaded")}
+	if classForName(params) != result {
+		t.Error("Expected the same Class object from both forms of forName()")
+	}
+}
+
+func TestForNameInvalidNames(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	result := classForName([]interface{}{list.New(), object.Null})
+	if errBlk, ok := result.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("Expected NullPointerException for a null name, got %v", result)
+	}
+
+	for _, name := range []string{"java/lang/String", "[Q", "[Ljava.lang.String", ""} {
+		params := []interface{}{list.New(), object.StringObjectFromGoString(name)}
+		result = classForName(params)
+		if errBlk, ok := result.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.ClassNotFoundException {
+			t.Errorf("Expected ClassNotFoundException for name %q, got %v", name, result)
+		}
+	}
+}
@@ -605,9 +605,7 @@ func doubleToString(params []interface{}) interface{} {
 		return getGErrBlk(excNames.IllegalArgumentException, "doubleToString: Failed to retrieve value from self Double object")
 	}
 
-	// Convert the double value to string
-	strValue := fmt.Sprintf("%g", selfValue) // %g is the format for general floating-point notation
-	return object.StringObjectFromGoString(strValue)
+	return object.StringObjectFromGoString(object.JavaDoubleString(selfValue))
 }
 
 // Method: toString (D)Ljava/lang/String;
@@ -619,8 +617,7 @@ func doubleToStringStatic(params []interface{}) interface{} {
 	if !ok {
 		return getGErrBlk(excNames.IllegalArgumentException, "doubleToStringStatic: Invalid argument type")
 	}
-	// Return string representation of the double (mocked for illustration)
-	return object.StringObjectFromGoString(fmt.Sprintf("%f", dd))
+	return object.StringObjectFromGoString(object.JavaDoubleString(dd))
 }
 
 // Method: valueOf (D)Ljava/lang/Double;
@@ -92,14 +92,13 @@ func TestDouble_ToString_Variants(t *testing.T) {
     obj := makeDouble(123.25)
     out := doubleToString([]interface{}{obj}).(*object.Object)
     got := object.GoStringFromStringObject(out)
-    // %g formatting should produce "123.25" for this value
     if got != "123.25" {
         t.Fatalf("toString got %q", got)
     }
-    // static variant uses %f default precision (6)
+    // the static variant formats the same way
     out2 := doubleToStringStatic([]interface{}{123.25}).(*object.Object)
     got2 := object.GoStringFromStringObject(out2)
-    if got2 != "123.250000" {
+    if got2 != "123.25" {
         t.Fatalf("toStringStatic got %q", got2)
     }
 }
@@ -604,9 +604,7 @@ func floatToString(params []interface{}) interface{} {
 		return getGErrBlk(excNames.IllegalArgumentException, "floatToString: Failed to retrieve value from self Double object")
 	}
 
-	// Convert the float value to string
-	strValue := fmt.Sprintf("%g", selfValue) // %g is the format for general floating-point notation
-	return object.StringObjectFromGoString(strValue)
+	return object.StringObjectFromGoString(object.JavaFloatString(selfValue))
 }
 
 // Method: toString (F)Ljava/lang/String;
@@ -618,8 +616,7 @@ func floatToStringStatic(params []interface{}) interface{} {
 	if !ok {
 		return getGErrBlk(excNames.IllegalArgumentException, "floatToStringStatic: Invalid argument type")
 	}
-	// Return string representation of the double.
-	return object.StringObjectFromGoString(fmt.Sprintf("%f", ff))
+	return object.StringObjectFromGoString(object.JavaFloatString(ff))
 }
 
 // Method: valueOf (F)Ljava/lang/Float;
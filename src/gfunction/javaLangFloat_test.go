@@ -94,17 +94,17 @@ func TestFloat_ToHexString_And_ToString(t *testing.T) {
         t.Fatalf("toHexString got %q want %q", gotHex, expectedHex)
     }
 
-    // instance toString -> %g
+    // instance toString
     sObj2 := floatToString([]interface{}{obj}).(*object.Object)
     gotStr := object.GoStringFromStringObject(sObj2)
     if gotStr != "123.25" {
         t.Fatalf("toString got %q", gotStr)
     }
 
-    // static toString(F) -> %f default precision (6)
+    // static toString(F) formats the same way
     sObj3 := floatToStringStatic([]interface{}{v}).(*object.Object)
     gotStr2 := object.GoStringFromStringObject(sObj3)
-    if gotStr2 != "123.250000" {
+    if gotStr2 != "123.25" {
         t.Fatalf("toStringStatic got %q", gotStr2)
     }
 }
@@ -10,6 +10,7 @@ import (
 	"jacobin/src/classloader"
 	"jacobin/src/excNames"
 	"jacobin/src/object"
+	"jacobin/src/replay"
 	"math"
 	"math/big"
 	"math/rand"
@@ -529,7 +530,8 @@ func powFloat64(params []interface{}) interface{} {
 
 // Generate a random number >= 0.0 and < 1.0
 func randomFloat64(params []interface{}) interface{} {
-	return rand.Float64()
+	bits := replay.Int64(replay.KindRandom, func() int64 { return int64(math.Float64bits(rand.Float64())) })
+	return math.Float64frombits(uint64(bits)) // see replay.go
 }
 
 // Computes a double-valued number that is closest in value to the argument and is equal to a mathematical integer.
@@ -7,12 +7,15 @@
 package gfunction
 
 import (
+	"container/list"
 	"fmt"
 	"jacobin/src/classloader"
 	"jacobin/src/excNames"
 	"jacobin/src/object"
 	"jacobin/src/types"
-	"unsafe"
+	"strings"
+	"sync"
+	"time"
 )
 
 // Implementation of some of the functions in Java/lang/Class.
@@ -31,7 +34,11 @@ func Load_Lang_Object() {
 			GFunction:  justReturn,
 		}
 
-	// "java/lang/Object.clone(Ljava/lang/Object;)Ljava/lang/Object;" is PROTECTED
+	MethodSignatures["java/lang/Object.clone()Ljava/lang/Object;"] = // protected, but public for arrays
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  objectClone,
+		}
 
 	MethodSignatures["java/lang/Object.equals(Ljava/lang/Object;)Z"] =
 		GMeth{
@@ -60,13 +67,13 @@ func Load_Lang_Object() {
 	MethodSignatures["java/lang/Object.notify()V"] =
 		GMeth{
 			ParamSlots: 0,
-			GFunction:  trapFunction,
+			GFunction:  objectNotify,
 		}
 
 	MethodSignatures["java/lang/Object.notifyAll()V"] =
 		GMeth{
 			ParamSlots: 0,
-			GFunction:  trapFunction,
+			GFunction:  objectNotifyAll,
 		}
 
 	MethodSignatures["java/lang/Object.toString()Ljava/lang/String;"] =
@@ -77,44 +84,30 @@ func Load_Lang_Object() {
 
 	MethodSignatures["java/lang/Object.wait()V"] = // wait until awakened, typically by being notified or interrupted
 		GMeth{
-			ParamSlots: 0,
-			GFunction:  trapFunction,
+			ParamSlots:   0,
+			GFunction:    objectWait,
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/lang/Object.wait(J)V"] = // wait(long timeoutMillis)
 		GMeth{
-			ParamSlots: 1,
-			GFunction:  trapFunction,
+			ParamSlots:   1,
+			GFunction:    objectWait,
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/lang/Object.wait(JI)V"] = // wait(long timeoutMillis, int nanos)
 		GMeth{
-			ParamSlots: 2,
-			GFunction:  trapFunction,
+			ParamSlots:   2,
+			GFunction:    objectWait,
+			NeedsContext: true,
 		}
 
 }
 
-// === the internal representation of a java.lang.Class() instance ===
-// this is not a faithful reproduction of the OpenJDK version, but rather
-// the one we use in Jacobin
-type javaLangClass struct {
-	accessFlags    classloader.AccessFlags
-	name           string
-	superClassName string
-	interfaceNames []string
-	constantPool   classloader.CPool
-	fields         []classloader.Field
-	methods        map[string]*classloader.Method
-	loader         string
-	superClass     string
-	interfaces     []uint16 // indices into UTF8Refs
-	// instanceSlotCount uint
-	// staticSlotCount   uint
-	// staticVars        Slots
-}
-
 // "java/lang/Object.getClass()Ljava/lang/Class;"
+// returns the cached java/lang/Class object for the object's class,
+// so that x.getClass() == Foo.class holds.
 func objectGetClass(params []interface{}) interface{} {
 	objPtr := params[0].(*object.Object)
 	if objPtr == nil || objPtr.KlassName == types.InvalidStringIndex {
@@ -122,31 +115,40 @@ func objectGetClass(params []interface{}) interface{} {
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
 
-	jlc := javaLangClass{}
-	jlc.name = object.GoStringFromStringPoolIndex(objPtr.KlassName)
-
-	// get a pointer to the class contents from the method area
-	o := classloader.MethAreaFetch(jlc.name)
-	if o == nil {
-		errMsg := fmt.Sprintf("objectGetClass: Class %s not loaded", jlc.name)
+	className := object.GoStringFromStringPoolIndex(objPtr.KlassName)
+	cl := classloader.MethAreaClassObject(className)
+	if cl == nil {
+		errMsg := fmt.Sprintf("objectGetClass: Class %s not loaded", className)
 		return getGErrBlk(excNames.ClassNotLoadedException, errMsg)
 	}
+	return cl
+}
 
-	// syntactic sugar
-	obj := *o
+// "java/lang/Object.clone()Ljava/lang/Object;"
+// returns a shallow copy of the object: the fields of the copy hold the same values
+// as those of the original. Arrays can always be cloned; other objects only if their
+// class implements java/lang/Cloneable.
+func objectClone(params []interface{}) interface{} {
+	objPtr, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(objPtr) {
+		errMsg := fmt.Sprintf("objectClone: Invalid object: %T", params[0])
+		return getGErrBlk(excNames.NullPointerException, errMsg)
+	}
 
-	// create the empty java.lang.Class structure
-	jlc.loader = obj.Loader
+	className := object.GoStringFromStringPoolIndex(objPtr.KlassName)
+	if strings.HasPrefix(className, types.Array) {
+		clone := object.CopyArray(objPtr)
+		if clone == nil {
+			errMsg := fmt.Sprintf("objectClone: Unsupported array type: %s", className)
+			return getGErrBlk(excNames.InternalException, errMsg)
+		}
+		return clone
+	}
 
-	// fill in the jlc
-	objData := *obj.Data
-	jlc.constantPool = objData.CP
-	jlc.superClass = object.GoStringFromStringPoolIndex(objData.SuperclassIndex)
-	jlc.fields = objData.Fields
-	jlc.interfaces = objData.Interfaces
-	jlc.methods = objData.MethodTable
-	jlc.accessFlags = objData.Access
-	return &jlc
+	if !classloader.ImplementsInterface(className, "java/lang/Cloneable") {
+		return getGErrBlk(excNames.CloneNotSupportedException, strings.ReplaceAll(className, "/", "."))
+	}
+	return object.CloneObject(objPtr)
 }
 
 // "java/lang/Object.toString()Ljava/lang/String;"
@@ -172,9 +174,7 @@ func objectHashCode(params []interface{}) interface{} {
 	// params[0]: input Object
 	switch params[0].(type) {
 	case *object.Object:
-		ptr := uintptr(unsafe.Pointer(params[0].(*object.Object)))
-		hashCode := int64(ptr ^ (ptr >> 32))
-		return hashCode
+		return int64(object.IdentityHashCode(params[0].(*object.Object)))
 	}
 
 	errMsg := fmt.Sprintf("objectHashCode: Unsupported parameter type: %T", params[0])
@@ -199,3 +199,93 @@ func objectEquals(params []interface{}) interface{} {
 	// Not the same object.
 	return types.JavaBoolFalse
 }
+
+// Jacobin does not yet implement monitors (MONITORENTER and MONITOREXIT are
+// no-ops), so wait() and notify() do not check that the calling thread owns the
+// object's monitor. Each object that has waiting threads has a wait set, which
+// holds one channel per waiting thread. notify() signals the longest-waiting
+// thread's channel; notifyAll() signals them all.
+var waitSets = struct {
+	sync.Mutex
+	m map[*object.Object][]chan struct{}
+}{m: make(map[*object.Object][]chan struct{})}
+
+// "java/lang/Object.wait()V", "java/lang/Object.wait(J)V", "java/lang/Object.wait(JI)V"
+// Waits until the object is notified, the timeout (if any) expires, or the
+// current thread is interrupted, in which case an InterruptedException is
+// thrown and the interrupt status is cleared.
+func objectWait(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	this := params[1].(*object.Object)
+
+	var millis, nanos int64
+	if len(params) > 2 {
+		millis = params[2].(int64)
+	}
+	if len(params) > 3 {
+		nanos = params[3].(int64)
+	}
+	if millis < 0 {
+		return getGErrBlk(excNames.IllegalArgumentException, "objectWait: timeout value is negative")
+	}
+	if nanos < 0 || nanos > 999999 {
+		return getGErrBlk(excNames.IllegalArgumentException, "objectWait: nanosecond timeout value out of range")
+	}
+
+	th := currentExecThread(fs)
+	if th == nil {
+		errMsg := "objectWait: could not find the current thread"
+		return getGErrBlk(excNames.IllegalStateException, errMsg)
+	}
+
+	ch := make(chan struct{}, 1)
+	waitSets.Lock()
+	waitSets.m[this] = append(waitSets.m[this], ch)
+	waitSets.Unlock()
+
+	timeout := time.Duration(millis)*time.Millisecond + time.Duration(nanos)
+	interrupted := th.WaitInterruptibly(ch, timeout)
+
+	// if we were not notified, we are still in the wait set
+	waitSets.Lock()
+	waiters := waitSets.m[this]
+	for i, c := range waiters {
+		if c == ch {
+			waitSets.m[this] = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(waitSets.m[this]) == 0 {
+		delete(waitSets.m, this)
+	}
+	waitSets.Unlock()
+
+	if interrupted {
+		return getGErrBlk(excNames.InterruptedException, "wait interrupted")
+	}
+	return nil
+}
+
+// "java/lang/Object.notify()V"
+func objectNotify(params []interface{}) interface{} {
+	this := params[0].(*object.Object)
+	waitSets.Lock()
+	defer waitSets.Unlock()
+	if waiters := waitSets.m[this]; len(waiters) > 0 {
+		waiters[0] <- struct{}{}
+		waitSets.m[this] = waiters[1:]
+	}
+	return nil
+}
+
+// "java/lang/Object.notifyAll()V"
+func objectNotifyAll(params []interface{}) interface{} {
+	this := params[0].(*object.Object)
+	waitSets.Lock()
+	defer waitSets.Unlock()
+	for _, ch := range waitSets.m[this] {
+		ch <- struct{}{}
+	}
+	delete(waitSets.m, this)
+	return nil
+}
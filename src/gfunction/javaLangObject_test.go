@@ -0,0 +1,163 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"testing"
+	"time"
+)
+
+// adds a class to the method area that implements the given interfaces
+func addCloneTestClass(name string, interfaces ...string) {
+	k := classloader.Klass{Status: 'F', Loader: "testloader", Data: &classloader.ClData{
+		Name:            name,
+		SuperclassIndex: types.ObjectPoolStringIndex,
+	}}
+	for _, iface := range interfaces {
+		k.Data.Interfaces = append(k.Data.Interfaces, uint16(stringPool.GetStringIndex(&iface)))
+	}
+	classloader.MethAreaInsert(name, &k)
+}
+
+func TestObjectCloneOfCloneableObject(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	addCloneTestClass("pkg/Sheep", "java/lang/Cloneable")
+
+	className := "pkg/Sheep"
+	orig := object.MakeEmptyObjectWithClassName(&className)
+	orig.FieldTable["weight"] = object.Field{Ftype: types.Int, Fvalue: int64(42)}
+
+	ret := objectClone([]interface{}{orig})
+	clone, ok := ret.(*object.Object)
+	if !ok {
+		t.Fatalf("Expected an object, got: %T", ret)
+	}
+	if clone == orig {
+		t.Error("Expected the clone to be a distinct object")
+	}
+	if clone.KlassName != orig.KlassName || clone.FieldTable["weight"].Fvalue != int64(42) {
+		t.Errorf("Expected the clone to have the same class and fields, got: %v", clone.FieldTable)
+	}
+}
+
+func TestObjectCloneOfNonCloneableObject(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	addCloneTestClass("pkg/Goat")
+
+	className := "pkg/Goat"
+	orig := object.MakeEmptyObjectWithClassName(&className)
+
+	ret := objectClone([]interface{}{orig})
+	errBlk, ok := ret.(*GErrBlk)
+	if !ok || errBlk.ExceptionType != excNames.CloneNotSupportedException {
+		t.Errorf("Expected CloneNotSupportedException, got: %v", ret)
+	}
+}
+
+func TestObjectCloneOfArray(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	arr := object.Make1DimArray(object.INT, 3)
+	arr.FieldTable["value"].Fvalue.([]int64)[1] = 7
+
+	ret := objectClone([]interface{}{arr})
+	clone, ok := ret.(*object.Object)
+	if !ok {
+		t.Fatalf("Expected an array object, got: %T", ret)
+	}
+	cloneValues := clone.FieldTable["value"].Fvalue.([]int64)
+	if len(cloneValues) != 3 || cloneValues[1] != 7 {
+		t.Errorf("Expected a copy of the array, got: %v", cloneValues)
+	}
+
+	// the clone is a distinct array
+	cloneValues[1] = 8
+	if arr.FieldTable["value"].Fvalue.([]int64)[1] != 7 {
+		t.Error("Expected changes to the clone not to affect the original array")
+	}
+}
+
+func TestObjectHashCodeMatchesIdentityHashCode(t *testing.T) {
+	globals.InitGlobals("test")
+
+	obj := object.MakeEmptyObject()
+	hash := objectHashCode([]interface{}{obj})
+	if hash != systemIdentityHashCode([]interface{}{obj}) {
+		t.Errorf("Expected Object.hashCode() to equal System.identityHashCode(), got %v", hash)
+	}
+	if hash != objectHashCode([]interface{}{obj}) {
+		t.Error("Expected Object.hashCode() to be stable")
+	}
+	if systemIdentityHashCode([]interface{}{object.Null}) != int64(0) {
+		t.Error("Expected the identity hash code of null to be 0")
+	}
+}
+
+func TestObjectWaitAndNotify(t *testing.T) {
+	globals.InitGlobals("test")
+	_, fs := addTestExecThread()
+	className := "pkg/Lock"
+	lock := object.MakeEmptyObjectWithClassName(&className)
+
+	done := make(chan interface{})
+	go func() {
+		done <- objectWait([]interface{}{fs, lock})
+	}()
+
+	// notify once the waiter is in the wait set
+	for {
+		waitSets.Lock()
+		waiting := len(waitSets.m[lock])
+		waitSets.Unlock()
+		if waiting > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	objectNotify([]interface{}{lock})
+
+	select {
+	case ret := <-done:
+		if ret != nil {
+			t.Errorf("Expected wait() to return nil when notified, got: %v", ret)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("notify() did not wake the waiting thread")
+	}
+}
+
+func TestObjectWaitInterruptedAndTimedOut(t *testing.T) {
+	globals.InitGlobals("test")
+	th, fs := addTestExecThread()
+	className := "pkg/Lock"
+	lock := object.MakeEmptyObjectWithClassName(&className)
+
+	if ret := objectWait([]interface{}{fs, lock, int64(1), int64(0)}); ret != nil {
+		t.Errorf("Expected a timed-out wait() to return nil, got: %v", ret)
+	}
+
+	th.Interrupt()
+	errBlk, ok := objectWait([]interface{}{fs, lock}).(*GErrBlk)
+	if !ok || errBlk.ExceptionType != excNames.InterruptedException {
+		t.Fatal("Expected an InterruptedException from an interrupted wait()")
+	}
+	if th.IsInterrupted() {
+		t.Error("Expected the interrupt status to be cleared")
+	}
+	if len(waitSets.m[lock]) != 0 {
+		t.Error("Expected the interrupted thread to be removed from the wait set")
+	}
+}
@@ -6,71 +6,374 @@
 
 package gfunction
 
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"io/fs"
+	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Implementation of the processes that Runtime.exec() starts. startProcess() runs the
+// command as a Go exec.Cmd whose standard input, output, and error are OS pipes, and
+// returns a java/lang/ProcessImpl--the class of the processes the JDK starts--whose
+// "process" field holds the Go state. Runtime.exec() calls it with the command it has
+// split into words, and ProcessBuilder.start() is to call it the same way. The parent's
+// ends of the pipes are the file handles of a FileOutputStream and two FileInputStreams,
+// so the program reads and writes them with the G functions of those classes.
+//
+// A goroutine waits for the process to end and records its exit code. Its state is guarded
+// by the mutex of a synchronizer, on which waitFor() waits, interruptibly.
+
+var classNameProcessImpl = "java/lang/ProcessImpl"
+
+const fieldNameProcess = "process"
+
+// process is the state of a Process
+type process struct {
+	sync     *synchronizer
+	cmd      *exec.Cmd
+	stdin    *os.File // the parent's ends of the pipes
+	stdout   *os.File
+	stderr   *os.File
+	exited   bool
+	exitCode int64
+}
+
 func Load_Lang_Process() {
 
-	MethodSignatures["javaLangProcess.<clinit>()V"] =
+	MethodSignatures["java/lang/Process.<clinit>()V"] =
 		GMeth{
 			ParamSlots: 0,
-			GFunction:  trapFunction,
+			GFunction:  clinitGeneric,
 		}
 
-	MethodSignatures["javaLangProcess.<init>()V"] =
+	MethodSignatures[classNameProcessImpl+".<clinit>()V"] =
 		GMeth{
 			ParamSlots: 0,
-			GFunction:  trapFunction,
+			GFunction:  clinitGeneric,
 		}
 
-	MethodSignatures["javaLangProcess.destroy()V"] =
+	MethodSignatures[classNameProcessImpl+".destroy()V"] =
 		GMeth{
 			ParamSlots: 0,
-			GFunction:  trapFunction,
+			GFunction:  processDestroy,
 		}
 
-	MethodSignatures["javaLangProcess.destroyForcibly()Ljava/lang/Process;"] =
+	MethodSignatures[classNameProcessImpl+".destroyForcibly()Ljava/lang/Process;"] =
 		GMeth{
 			ParamSlots: 0,
-			GFunction:  trapFunction,
+			GFunction:  processDestroyForcibly,
 		}
 
-	MethodSignatures["javaLangProcess.exitValue()I"] =
+	MethodSignatures[classNameProcessImpl+".exitValue()I"] =
 		GMeth{
 			ParamSlots: 0,
-			GFunction:  trapFunction,
+			GFunction:  processExitValue,
 		}
 
-	MethodSignatures["javaLangProcess.getErrorStream()Ljava/io/InputStream;"] =
+	MethodSignatures[classNameProcessImpl+".getErrorStream()Ljava/io/InputStream;"] =
 		GMeth{
 			ParamSlots: 0,
-			GFunction:  trapFunction,
+			GFunction:  processGetErrorStream,
 		}
 
-	MethodSignatures["javaLangProcess.getInputStream()Ljava/io/InputStream;"] =
+	MethodSignatures[classNameProcessImpl+".getInputStream()Ljava/io/InputStream;"] =
 		GMeth{
 			ParamSlots: 0,
-			GFunction:  trapFunction,
+			GFunction:  processGetInputStream,
 		}
 
-	MethodSignatures["javaLangProcess.getOutputStream()Ljava/io/OutputStream;"] =
+	MethodSignatures[classNameProcessImpl+".getOutputStream()Ljava/io/OutputStream;"] =
 		GMeth{
 			ParamSlots: 0,
-			GFunction:  trapFunction,
+			GFunction:  processGetOutputStream,
 		}
 
-	MethodSignatures["javaLangProcess.isAlive()Z"] =
+	MethodSignatures[classNameProcessImpl+".isAlive()Z"] =
 		GMeth{
 			ParamSlots: 0,
-			GFunction:  trapFunction,
+			GFunction:  processIsAlive,
 		}
 
-	MethodSignatures["javaLangProcess.waitFor()I"] =
+	MethodSignatures[classNameProcessImpl+".pid()J"] =
 		GMeth{
 			ParamSlots: 0,
-			GFunction:  trapFunction,
+			GFunction:  processPid,
 		}
 
-	MethodSignatures["javaLangProcess.waitFor(JLjava/util/concurrent/TimeUnit;)Z"] =
+	MethodSignatures[classNameProcessImpl+".waitFor()I"] =
 		GMeth{
-			ParamSlots: 2,
-			GFunction:  trapFunction,
+			ParamSlots:   0,
+			GFunction:    processWaitFor,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[classNameProcessImpl+".waitFor(JLjava/util/concurrent/TimeUnit;)Z"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    processWaitFor,
+			NeedsContext: true,
+		}
+}
+
+// startProcess starts the command, whose first word is the program, with the environment
+// env ("name=value" strings, or nil to inherit Jacobin's) in the directory dir ("" to
+// inherit Jacobin's), and returns its Process
+func startProcess(funcName string, command []string, env []string, dir string) interface{} {
+	if len(command) == 0 {
+		return getGErrBlk(excNames.IndexOutOfBoundsException, funcName+": the command is empty")
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Env = env
+	cmd.Dir = dir
+	setCommandLine(cmd, command)
+
+	// the child's ends of the pipes are the read end of its stdin and the write ends
+	// of its stdout and stderr; the parent's are the others
+	var childEnds, parentEnds []*os.File
+	var err error
+	for i := 0; i < 3 && err == nil; i++ {
+		var r, w *os.File
+		if r, w, err = os.Pipe(); err == nil && i == 0 {
+			childEnds, parentEnds = append(childEnds, r), append(parentEnds, w)
+		} else if err == nil {
+			childEnds, parentEnds = append(childEnds, w), append(parentEnds, r)
+		}
+	}
+	if err == nil {
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = childEnds[0], childEnds[1], childEnds[2]
+		err = cmd.Start()
+	}
+	closeFiles(childEnds)
+	if err != nil {
+		closeFiles(parentEnds)
+		where := ""
+		if dir != "" {
+			where = fmt.Sprintf(" (in directory \"%s\")", dir)
+		}
+		errMsg := fmt.Sprintf("Cannot run program \"%s\"%s: %s", command[0], where, startFailure(err))
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+
+	proc := &process{
+		sync:   newSynchronizer(false),
+		cmd:    cmd,
+		stdin:  parentEnds[0],
+		stdout: parentEnds[1],
+		stderr: parentEnds[2],
+	}
+	go proc.wait()
+	return object.MakeOneFieldObject(classNameProcessImpl, fieldNameProcess, types.ProcessState, proc)
+}
+
+// closes the files, as after a failure, when there is nothing to do about an error
+func closeFiles(files []*os.File) {
+	for _, file := range files {
+		_ = file.Close()
+	}
+}
+
+// startFailure returns the reason that a command couldn't be started, without the name of
+// the program that Go's errors repeat
+func startFailure(err error) error {
+	var execErr *exec.Error
+	if errors.As(err, &execErr) {
+		return execErr.Err
+	}
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err
+	}
+	return err
+}
+
+// wait waits for the process to end and records its exit code. As in the JDK, a process
+// ended by a signal exits with 128 plus the number of the signal.
+func (proc *process) wait() {
+	_ = proc.cmd.Wait()
+	exitCode := int64(proc.cmd.ProcessState.ExitCode())
+	if status, ok := proc.cmd.ProcessState.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		exitCode = 128 + int64(status.Signal())
+	}
+
+	proc.sync.mutex.Lock()
+	proc.exited = true
+	proc.exitCode = exitCode
+	proc.sync.notify()
+	proc.sync.mutex.Unlock()
+}
+
+// windowsCommandLine returns the command line with which Windows starts the command. As in
+// the JDK, a word that contains a space or a tab, or that's empty, is put in quotes, unless
+// it's in quotes already, and a backslash that ends a quoted word is doubled so that it
+// doesn't escape the closing quote.
+func windowsCommandLine(command []string) string {
+	var sb strings.Builder
+	for i, word := range command {
+		if i > 0 {
+			sb.WriteByte(' ')
 		}
+		switch {
+		case len(word) >= 2 && strings.HasPrefix(word, `"`) && strings.HasSuffix(word, `"`):
+			sb.WriteString(word)
+		case word == "" || strings.ContainsAny(word, " \t"):
+			sb.WriteByte('"')
+			sb.WriteString(word)
+			if strings.HasSuffix(word, `\`) {
+				sb.WriteByte('\\')
+			}
+			sb.WriteByte('"')
+		default:
+			sb.WriteString(word)
+		}
+	}
+	return sb.String()
+}
+
+// returns the state of the Process in params[index]
+func getProcess(funcName string, params []interface{}, index int) (*process, *GErrBlk) {
+	obj, err := args.GetObject(params, index)
+	if err != nil {
+		return nil, getArgsGErrBlk(funcName, err)
+	}
+	proc, ok := obj.FieldTable[fieldNameProcess].Fvalue.(*process)
+	if !ok {
+		return nil, getGErrBlk(excNames.IllegalArgumentException, funcName+": the object is not a Process")
+	}
+	return proc, nil
+}
+
+// "java/lang/Process.destroy()V" -- asks the process to end: on Windows, where there is no
+// such request, it ends the process at once, as the JDK does
+func processDestroy(params []interface{}) interface{} {
+	proc, gErr := getProcess("processDestroy", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	if err := proc.cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		_ = proc.cmd.Process.Kill()
+	}
+	return nil
+}
+
+// "java/lang/Process.destroyForcibly()Ljava/lang/Process;"
+func processDestroyForcibly(params []interface{}) interface{} {
+	proc, gErr := getProcess("processDestroyForcibly", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	_ = proc.cmd.Process.Kill()
+	return params[0]
+}
+
+// "java/lang/Process.exitValue()I"
+func processExitValue(params []interface{}) interface{} {
+	proc, gErr := getProcess("processExitValue", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	proc.sync.mutex.Lock()
+	defer proc.sync.mutex.Unlock()
+	if !proc.exited {
+		return getGErrBlk(excNames.IllegalThreadStateException, "processExitValue: process hasn't exited")
+	}
+	return proc.exitCode
+}
+
+// "java/lang/Process.getErrorStream()Ljava/io/InputStream;"
+func processGetErrorStream(params []interface{}) interface{} {
+	proc, gErr := getProcess("processGetErrorStream", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	return makePipeStream("java/io/FileInputStream", proc.stderr)
+}
+
+// "java/lang/Process.getInputStream()Ljava/io/InputStream;", which reads the standard output
+// of the process
+func processGetInputStream(params []interface{}) interface{} {
+	proc, gErr := getProcess("processGetInputStream", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	return makePipeStream("java/io/FileInputStream", proc.stdout)
+}
+
+// "java/lang/Process.getOutputStream()Ljava/io/OutputStream;", which writes the standard
+// input of the process
+func processGetOutputStream(params []interface{}) interface{} {
+	proc, gErr := getProcess("processGetOutputStream", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	return makePipeStream("java/io/FileOutputStream", proc.stdin)
+}
+
+// makePipeStream returns a FileInputStream or FileOutputStream on the parent's end of a pipe
+func makePipeStream(className string, pipe *os.File) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&className)
+	obj.FieldTable[FilePath] = object.Field{Ftype: types.ByteArray, Fvalue: object.JavaByteArrayFromGoString(pipe.Name())}
+	obj.FieldTable[FileHandle] = object.Field{Ftype: types.FileHandle, Fvalue: pipe}
+	return obj
+}
+
+// "java/lang/Process.isAlive()Z"
+func processIsAlive(params []interface{}) interface{} {
+	proc, gErr := getProcess("processIsAlive", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	proc.sync.mutex.Lock()
+	defer proc.sync.mutex.Unlock()
+	if proc.exited {
+		return types.JavaBoolFalse
+	}
+	return types.JavaBoolTrue
+}
+
+// "java/lang/Process.pid()J"
+func processPid(params []interface{}) interface{} {
+	proc, gErr := getProcess("processPid", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(proc.cmd.Process.Pid)
+}
+
+// "java/lang/Process.waitFor()I", which waits for the process to end and returns its exit
+// code, and waitFor(long, TimeUnit), which returns whether it ended before the time ran out
+func processWaitFor(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	proc, gErr := getProcess("processWaitFor", params, 1)
+	if gErr != nil {
+		return gErr
+	}
+	timed := len(params) > 2
+	var timeout time.Duration
+	if timed {
+		if timeout, gErr = timeUnitDuration("processWaitFor", params[2].(int64), params[3]); gErr != nil {
+			return gErr
+		}
+	}
+
+	exited, interrupted := proc.sync.await(fs, func() bool { return proc.exited }, timed, timeout, true, "")
+	switch {
+	case interrupted:
+		return getGErrBlk(excNames.InterruptedException, "processWaitFor: interrupted")
+	case timed:
+		return types.ConvertGoBoolToJavaBool(exited)
+	}
+	proc.sync.mutex.Lock()
+	defer proc.sync.mutex.Unlock()
+	return proc.exitCode
 }
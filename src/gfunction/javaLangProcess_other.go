@@ -0,0 +1,15 @@
+//go:build !windows
+
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import "os/exec"
+
+// setCommandLine does nothing: on other systems, the program gets the words of the
+// command as its arguments, with no command line to parse
+func setCommandLine(*exec.Cmd, []string) {}
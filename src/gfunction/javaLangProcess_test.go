@@ -0,0 +1,167 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"io"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// skips the test where there's no POSIX shell to run
+func needShell(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the test runs /bin/sh")
+	}
+}
+
+// returns a String[] of the words
+func stringArray(words ...string) *object.Object {
+	return object.MakePrimitiveObject("[Ljava/lang/String;", types.RefArray, object.StringObjectArrayFromGoStringArray(words))
+}
+
+// waits for the process to end and returns its exit code and what it wrote to its stdout
+func waitForOutput(t *testing.T, proc interface{}) (int64, string) {
+	t.Helper()
+	procObj, ok := proc.(*object.Object)
+	if !ok {
+		t.Fatalf("Expected a Process, got %v", proc)
+	}
+	stdout := processGetInputStream([]interface{}{procObj}).(*object.Object)
+	output, err := io.ReadAll(stdout.FieldTable[FileHandle].Fvalue.(*os.File))
+	if err != nil {
+		t.Fatalf("Reading the output of the process failed: %v", err)
+	}
+	exitCode, ok := processWaitFor([]interface{}{list.New(), procObj}).(int64)
+	if !ok {
+		t.Fatalf("waitFor() failed")
+	}
+	return exitCode, string(output)
+}
+
+func TestRuntimeExec_String(t *testing.T) {
+	needShell(t)
+	globals.InitGlobals("test")
+
+	// the words are "sh", "-c", "'exit", and "3'", as quotes aren't special
+	proc := runtimeExecString([]interface{}{nil, object.StringObjectFromGoString("sh -c\t'exit 3'")})
+	if _, ok := proc.(*GErrBlk); ok {
+		t.Fatalf("Expected the command to start, got %v", proc)
+	}
+	if exitCode, _ := waitForOutput(t, proc); exitCode != 2 {
+		t.Errorf("Expected the shell to fail on the unterminated quote with exit code 2, got %d", exitCode)
+	}
+
+	ret := runtimeExecString([]interface{}{nil, object.StringObjectFromGoString(" \t")})
+	expectException(t, ret, excNames.IllegalArgumentException, "exec() of an empty command")
+}
+
+func TestRuntimeExec_ArrayWithEnvAndDir(t *testing.T) {
+	needShell(t)
+	globals.InitGlobals("test")
+	dir := t.TempDir()
+	dirObj := makeFileObject(dir)
+
+	command := stringArray("sh", "-c", `echo "$GREETING, $0" from $(pwd); read line; echo "$line" >&2; exit 7`, "world")
+	env := stringArray("GREETING=hello", "IGNORED")
+	proc := runtimeExecArray([]interface{}{nil, command, env, dirObj})
+	procObj, ok := proc.(*object.Object)
+	if !ok {
+		t.Fatalf("Expected the command to start, got %v", proc)
+	}
+
+	stdin := processGetOutputStream([]interface{}{procObj}).(*object.Object).FieldTable[FileHandle].Fvalue.(*os.File)
+	_, _ = stdin.WriteString("to stderr\n")
+	_ = stdin.Close()
+	stderr := processGetErrorStream([]interface{}{procObj}).(*object.Object).FieldTable[FileHandle].Fvalue.(*os.File)
+	errOutput, _ := io.ReadAll(stderr)
+
+	exitCode, output := waitForOutput(t, proc)
+	realDir, _ := filepath.EvalSymlinks(dir)
+	if want := "hello, world from " + realDir + "\n"; output != want && output != "hello, world from "+dir+"\n" {
+		t.Errorf("Expected the output %q, got %q", want, output)
+	}
+	if string(errOutput) != "to stderr\n" {
+		t.Errorf("Expected the error output %q, got %q", "to stderr\n", errOutput)
+	}
+	if exitCode != 7 {
+		t.Errorf("Expected exit code 7, got %d", exitCode)
+	}
+	if ret := processExitValue([]interface{}{procObj}); ret != int64(7) {
+		t.Errorf("Expected exitValue() to be 7, got %v", ret)
+	}
+	if processIsAlive([]interface{}{procObj}) != types.JavaBoolFalse {
+		t.Errorf("Expected the process not to be alive after it exited")
+	}
+}
+
+func TestRuntimeExec_Failures(t *testing.T) {
+	globals.InitGlobals("test")
+
+	ret := runtimeExecArray([]interface{}{nil, stringArray()})
+	expectException(t, ret, excNames.IndexOutOfBoundsException, "exec() of an empty array")
+
+	ret = runtimeExecArray([]interface{}{nil, object.Null})
+	expectException(t, ret, excNames.NullPointerException, "exec() of a null array")
+
+	ret = runtimeExecArray([]interface{}{nil, stringArray("no-such-program-for-jacobin")})
+	expectException(t, ret, excNames.IOException, "exec() of a program that doesn't exist")
+	if gErr, ok := ret.(*GErrBlk); ok && !strings.HasPrefix(gErr.ErrMsg, `Cannot run program "no-such-program-for-jacobin": `) {
+		t.Errorf("Unexpected message %q", gErr.ErrMsg)
+	}
+}
+
+func TestProcess_DestroyAndWaitFor(t *testing.T) {
+	needShell(t)
+	globals.InitGlobals("test")
+
+	proc := runtimeExecArray([]interface{}{nil, stringArray("sleep", "30")})
+	procObj, ok := proc.(*object.Object)
+	if !ok {
+		t.Fatalf("Expected the command to start, got %v", proc)
+	}
+	millis := object.StringObjectFromGoString("MILLISECONDS")
+	if ret := processWaitFor([]interface{}{list.New(), procObj, int64(10), millis}); ret != types.JavaBoolFalse {
+		t.Errorf("Expected waitFor(10ms) to time out, got %v", ret)
+	}
+	expectException(t, processExitValue([]interface{}{procObj}), excNames.IllegalThreadStateException,
+		"exitValue() of a running process")
+	if processIsAlive([]interface{}{procObj}) != types.JavaBoolTrue {
+		t.Errorf("Expected the process to be alive")
+	}
+
+	processDestroy([]interface{}{procObj})
+	if exitCode := processWaitFor([]interface{}{list.New(), procObj}); exitCode != int64(128+15) {
+		t.Errorf("Expected the process ended by SIGTERM to exit with 143, got %v", exitCode)
+	}
+}
+
+func TestWindowsCommandLine(t *testing.T) {
+	tests := []struct {
+		command []string
+		want    string
+	}{
+		{[]string{"cmd", "/c", "dir"}, `cmd /c dir`},
+		{[]string{`C:\Program Files\app.exe`, "a b", ""}, `"C:\Program Files\app.exe" "a b" ""`},
+		{[]string{"app", `"already quoted"`}, `app "already quoted"`},
+		{[]string{"app", `C:\a dir\`}, `app "C:\a dir\\"`},
+	}
+	for _, test := range tests {
+		if got := windowsCommandLine(test.command); got != test.want {
+			t.Errorf("windowsCommandLine(%q) = %s, expected %s", test.command, got, test.want)
+		}
+	}
+}
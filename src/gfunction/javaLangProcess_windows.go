@@ -0,0 +1,18 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setCommandLine gives Windows the command line that the JDK would, rather than the one
+// Go would make from the words of the command, which escapes words already in quotes
+func setCommandLine(cmd *exec.Cmd, command []string) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: windowsCommandLine(command)}
+}
@@ -0,0 +1,367 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"fmt"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"strings"
+)
+
+// Implementation of java/lang/reflect/Array, which creates arrays of types known only at
+// run time and reads and writes their elements. The elements of arrays of primitives are
+// boxed by get() and unboxed by set(); the typed getters and setters, such as getInt() and
+// setInt(), take and return primitives. Both allow the widening conversions of the JLS.
+
+// the largest number of dimensions an array can have
+const maxArrayDimensions = 255
+
+// the wrapper classes of the primitives, by their descriptors
+var wrapperClassNames = map[byte]string{
+	'Z': "java/lang/Boolean",
+	'B': "java/lang/Byte",
+	'C': "java/lang/Character",
+	'S': "java/lang/Short",
+	'I': "java/lang/Integer",
+	'J': "java/lang/Long",
+	'F': "java/lang/Float",
+	'D': "java/lang/Double",
+}
+
+// the primitives each primitive can be widened to (JLS 5.1.2), including itself
+var wideningTargets = map[byte]string{
+	'Z': "Z",
+	'B': "BSIJFD",
+	'S': "SIJFD",
+	'C': "CIJFD",
+	'I': "IJFD",
+	'J': "JFD",
+	'F': "FD",
+	'D': "D",
+}
+
+func Load_Lang_Reflect_Array() {
+
+	MethodSignatures["java/lang/reflect/Array.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/lang/reflect/Array.get(Ljava/lang/Object;I)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  reflectArrayGet,
+		}
+
+	MethodSignatures["java/lang/reflect/Array.getLength(Ljava/lang/Object;)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  reflectArrayGetLength,
+		}
+
+	MethodSignatures["java/lang/reflect/Array.newInstance(Ljava/lang/Class;I)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  reflectArrayNewInstance,
+		}
+
+	MethodSignatures["java/lang/reflect/Array.newInstance(Ljava/lang/Class;[I)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  reflectArrayNewMultiInstance,
+		}
+
+	MethodSignatures["java/lang/reflect/Array.set(Ljava/lang/Object;ILjava/lang/Object;)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  reflectArraySet,
+		}
+
+	// the typed getters and setters, such as getInt(Ljava/lang/Object;I)I and
+	// setInt(Ljava/lang/Object;II)V
+	for _, primitive := range []struct{ name, descriptor string }{
+		{"Boolean", types.Bool}, {"Byte", types.Byte}, {"Char", types.Char}, {"Short", types.Short},
+		{"Int", types.Int}, {"Long", types.Long}, {"Float", types.Float}, {"Double", types.Double},
+	} {
+		MethodSignatures["java/lang/reflect/Array.get"+primitive.name+"(Ljava/lang/Object;I)"+primitive.descriptor] =
+			GMeth{
+				ParamSlots: 2,
+				GFunction:  reflectArrayGetPrimitive(primitive.descriptor[0]),
+			}
+
+		MethodSignatures["java/lang/reflect/Array.set"+primitive.name+"(Ljava/lang/Object;I"+primitive.descriptor+")V"] =
+			GMeth{
+				ParamSlots: 3,
+				GFunction:  reflectArraySetPrimitive(primitive.descriptor[0]),
+			}
+	}
+}
+
+// returns the array in params[0] and checks the index in params[1], if there is one
+func reflectArrayParam(funcName string, params []interface{}) (*object.Object, *GErrBlk) {
+	arr, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(arr) {
+		return nil, getGErrBlk(excNames.NullPointerException, funcName+": the array is null")
+	}
+	// Strings hold their characters in a [B field too
+	if !strings.HasPrefix(arr.FieldTable["value"].Ftype, types.Array) || object.IsStringObject(arr) {
+		return nil, getGErrBlk(excNames.IllegalArgumentException, funcName+": Argument is not an array")
+	}
+	if len(params) > 1 {
+		index := params[1].(int64)
+		if length := reflectArrayLength(arr); index < 0 || index >= length {
+			errMsg := fmt.Sprintf("%s: Index %d out of bounds for length %d", funcName, index, length)
+			return nil, getGErrBlk(excNames.ArrayIndexOutOfBoundsException, errMsg)
+		}
+	}
+	return arr, nil
+}
+
+// the number of elements in the array, whatever its storage
+func reflectArrayLength(arr *object.Object) int64 {
+	switch array := arr.FieldTable["value"].Fvalue.(type) {
+	case []types.JavaByte:
+		return int64(len(array))
+	case []byte:
+		return int64(len(array))
+	case []int64:
+		return int64(len(array))
+	case []float64:
+		return int64(len(array))
+	case []*object.Object:
+		return int64(len(array))
+	}
+	return 0
+}
+
+// reflectElementTypes returns the descriptors of the primitives that the elements of the
+// array may be, the first being the one assumed when they're boxed, or "" for arrays of
+// references. NEWARRAY makes the arrays of char, short, int, and long as [I arrays, those
+// of float and double as [F arrays, and those of boolean and byte as [B arrays, so these
+// stand for all the types stored in the same way.
+func reflectElementTypes(arrayType string) string {
+	switch arrayType {
+	case types.IntArray:
+		return "IJCS"
+	case types.FloatArray:
+		return "FD"
+	case types.ByteArray:
+		return "BZ"
+	case types.CharArray:
+		return "C"
+	}
+	if types.IsPrimitive(arrayType[1:]) {
+		return arrayType[1:]
+	}
+	return ""
+}
+
+// reads the element at the index of an array of primitives, as an int64 or a float64
+func reflectPrimitiveElement(arr *object.Object, index int64) any {
+	switch array := arr.FieldTable["value"].Fvalue.(type) {
+	case []types.JavaByte:
+		return int64(array[index])
+	case []byte:
+		return int64(int8(array[index]))
+	case []int64:
+		return array[index]
+	case []float64:
+		return array[index]
+	}
+	return nil
+}
+
+// stores an int64 or a float64, already converted to the type of the elements, at the
+// index of an array of primitives
+func reflectStorePrimitive(arr *object.Object, index int64, value any) {
+	switch array := arr.FieldTable["value"].Fvalue.(type) {
+	case []types.JavaByte:
+		array[index] = types.JavaByte(value.(int64))
+	case []byte:
+		array[index] = byte(value.(int64))
+	case []int64:
+		array[index] = value.(int64)
+	case []float64:
+		array[index] = value.(float64)
+	}
+}
+
+// widenPrimitive converts a primitive value, an int64 or a float64, from one primitive
+// type to another as a widening conversion does, returning false if none is allowed
+func widenPrimitive(value any, from, to byte) (any, bool) {
+	if !strings.ContainsRune(wideningTargets[from], rune(to)) {
+		return nil, false
+	}
+	if i, ok := value.(int64); ok {
+		switch to {
+		case 'F':
+			return float64(float32(i)), true
+		case 'D':
+			return float64(i), true
+		}
+	}
+	return value, true
+}
+
+// converts a primitive value of the type passed to the type of the elements of the array,
+// or returns an IllegalArgumentException if the array can't hold it
+func reflectConvertForStore(funcName string, arr *object.Object, value any, from byte) (any, *GErrBlk) {
+	for _, elementType := range []byte(reflectElementTypes(arr.FieldTable["value"].Ftype)) {
+		if converted, ok := widenPrimitive(value, from, elementType); ok {
+			return converted, nil
+		}
+	}
+	return nil, getGErrBlk(excNames.IllegalArgumentException, funcName+": argument type mismatch")
+}
+
+// "java/lang/reflect/Array.newInstance(Ljava/lang/Class;I)Ljava/lang/Object;"
+func reflectArrayNewInstance(params []interface{}) interface{} {
+	return newReflectArray(params[0], []int64{params[1].(int64)})
+}
+
+// "java/lang/reflect/Array.newInstance(Ljava/lang/Class;[I)Ljava/lang/Object;"
+func reflectArrayNewMultiInstance(params []interface{}) interface{} {
+	dimsObj, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(dimsObj) {
+		return getGErrBlk(excNames.NullPointerException, "reflectArrayNewMultiInstance: the dimensions are null")
+	}
+	dimSizes, _ := dimsObj.FieldTable["value"].Fvalue.([]int64)
+	if len(dimSizes) == 0 {
+		return getGErrBlk(excNames.IllegalArgumentException, "reflectArrayNewMultiInstance: Empty dimensions array")
+	}
+	return newReflectArray(params[0], dimSizes)
+}
+
+// newReflectArray creates an array with as many dimensions as there are sizes, plus those
+// of the component type if it's an array class: newInstance(int[].class, 2, 3) returns an
+// int[2][3][]
+func newReflectArray(componentParam any, dimSizes []int64) interface{} {
+	componentClass, ok := componentParam.(*object.Object)
+	if !ok || object.IsNull(componentClass) {
+		return getGErrBlk(excNames.NullPointerException, "newReflectArray: the component type is null")
+	}
+	componentType := classDescriptor(componentClass)
+	if componentType == "" {
+		return getGErrBlk(excNames.IllegalArgumentException, "newReflectArray: the component type is void")
+	}
+	arrayType := strings.Repeat(types.Array, len(dimSizes)) + componentType
+	if dims := len(arrayType) - len(strings.TrimLeft(arrayType, types.Array)); dims > maxArrayDimensions {
+		errMsg := fmt.Sprintf("newReflectArray: %d dimensions exceed the limit of %d", dims, maxArrayDimensions)
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	for _, size := range dimSizes {
+		if size < 0 {
+			return getGErrBlk(excNames.NegativeArraySizeException, fmt.Sprintf("newReflectArray: %d", size))
+		}
+	}
+	return object.MakeMultiDimArray(arrayType, dimSizes)
+}
+
+// "java/lang/reflect/Array.getLength(Ljava/lang/Object;)I"
+func reflectArrayGetLength(params []interface{}) interface{} {
+	arr, gErr := reflectArrayParam("reflectArrayGetLength", params)
+	if gErr != nil {
+		return gErr
+	}
+	return reflectArrayLength(arr)
+}
+
+// "java/lang/reflect/Array.get(Ljava/lang/Object;I)Ljava/lang/Object;" -- the elements of
+// arrays of primitives are returned boxed
+func reflectArrayGet(params []interface{}) interface{} {
+	arr, gErr := reflectArrayParam("reflectArrayGet", params)
+	if gErr != nil {
+		return gErr
+	}
+	index := params[1].(int64)
+	elementTypes := reflectElementTypes(arr.FieldTable["value"].Ftype)
+	if elementTypes == "" {
+		return arr.FieldTable["value"].Fvalue.([]*object.Object)[index]
+	}
+	elementType := elementTypes[:1]
+	return Populator(wrapperClassNames[elementType[0]], elementType, reflectPrimitiveElement(arr, index))
+}
+
+// "java/lang/reflect/Array.set(Ljava/lang/Object;ILjava/lang/Object;)V" -- the value is
+// unboxed to be stored in an array of primitives
+func reflectArraySet(params []interface{}) interface{} {
+	arr, gErr := reflectArrayParam("reflectArraySet", params)
+	if gErr != nil {
+		return gErr
+	}
+	index := params[1].(int64)
+	value, _ := params[2].(*object.Object)
+	arrayType := arr.FieldTable["value"].Ftype
+
+	if reflectElementTypes(arrayType) == "" {
+		if !object.IsNull(value) && !classloader.IsAssignableToComponent(
+			classloader.TypeNameOf(value), classloader.ArrayComponentType(arrayType)) {
+			return getGErrBlk(excNames.IllegalArgumentException, "reflectArraySet: array element type mismatch")
+		}
+		arr.FieldTable["value"].Fvalue.([]*object.Object)[index] = value
+		return nil
+	}
+
+	if object.IsNull(value) {
+		return getGErrBlk(excNames.IllegalArgumentException, "reflectArraySet: a null can't be stored in an array of primitives")
+	}
+	from := ""
+	className := object.GoStringFromStringPoolIndex(value.KlassName)
+	for descriptor, wrapper := range wrapperClassNames {
+		if className == wrapper {
+			from = string(descriptor)
+		}
+	}
+	if from == "" {
+		return getGErrBlk(excNames.IllegalArgumentException, "reflectArraySet: argument type mismatch")
+	}
+	converted, gErr := reflectConvertForStore("reflectArraySet", arr, value.FieldTable["value"].Fvalue, from[0])
+	if gErr != nil {
+		return gErr
+	}
+	reflectStorePrimitive(arr, index, converted)
+	return nil
+}
+
+// returns the gfunction of the typed getter of the primitive, such as getInt(), which
+// widens the element to the primitive's type
+func reflectArrayGetPrimitive(primitive byte) func([]interface{}) interface{} {
+	return func(params []interface{}) interface{} {
+		arr, gErr := reflectArrayParam("reflectArrayGetPrimitive", params)
+		if gErr != nil {
+			return gErr
+		}
+		value := reflectPrimitiveElement(arr, params[1].(int64))
+		for _, elementType := range []byte(reflectElementTypes(arr.FieldTable["value"].Ftype)) {
+			if converted, ok := widenPrimitive(value, elementType, primitive); ok {
+				return converted
+			}
+		}
+		return getGErrBlk(excNames.IllegalArgumentException, "reflectArrayGetPrimitive: argument type mismatch")
+	}
+}
+
+// returns the gfunction of the typed setter of the primitive, such as setInt(), which
+// widens the value to the type of the array's elements
+func reflectArraySetPrimitive(primitive byte) func([]interface{}) interface{} {
+	return func(params []interface{}) interface{} {
+		arr, gErr := reflectArrayParam("reflectArraySetPrimitive", params)
+		if gErr != nil {
+			return gErr
+		}
+		converted, gErr := reflectConvertForStore("reflectArraySetPrimitive", arr, params[2], primitive)
+		if gErr != nil {
+			return gErr
+		}
+		reflectStorePrimitive(arr, params[1].(int64), converted)
+		return nil
+	}
+}
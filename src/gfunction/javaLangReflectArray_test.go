@@ -0,0 +1,189 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"testing"
+)
+
+// returns the Class object of the class or array class named
+func classObjectNamed(name string) *object.Object {
+	return classloader.GetClassObject(stringPool.GetStringIndex(&name))
+}
+
+// checks that the gfunction returned an exception of the type expected
+func checkReflectArrayError(t *testing.T, ret interface{}, excType int) {
+	t.Helper()
+	gErr, ok := ret.(*GErrBlk)
+	if !ok {
+		t.Fatalf("Expected an exception, got %T", ret)
+	}
+	if gErr.ExceptionType != excType {
+		t.Errorf("Expected %s, got %s: %s", excNames.JVMexceptionNames[excType],
+			excNames.JVMexceptionNames[gErr.ExceptionType], gErr.ErrMsg)
+	}
+}
+
+func TestReflectArrayNewInstanceOfPrimitives(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	arr := reflectArrayNewInstance([]interface{}{primitiveClassObject(types.Int), int64(5)}).(*object.Object)
+	if arr.FieldTable["value"].Ftype != types.IntArray {
+		t.Errorf("Expected an int array, got %s", arr.FieldTable["value"].Ftype)
+	}
+	if reflectArrayGetLength([]interface{}{arr}) != int64(5) {
+		t.Errorf("Expected a length of 5")
+	}
+
+	arr = reflectArrayNewInstance([]interface{}{primitiveClassObject(types.Double), int64(2)}).(*object.Object)
+	if _, ok := arr.FieldTable["value"].Fvalue.([]float64); !ok {
+		t.Errorf("Expected a double array, got %T", arr.FieldTable["value"].Fvalue)
+	}
+}
+
+func TestReflectArrayNewInstanceOfReferences(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	arr := reflectArrayNewInstance([]interface{}{classObjectNamed(types.StringClassName), int64(3)}).(*object.Object)
+	if arr.FieldTable["value"].Ftype != "[Ljava/lang/String;" {
+		t.Errorf("Expected a String array, got %s", arr.FieldTable["value"].Ftype)
+	}
+
+	// the component type is an array class, so the array has one more dimension
+	arr = reflectArrayNewInstance([]interface{}{classObjectNamed("[I"), int64(3)}).(*object.Object)
+	if arr.FieldTable["value"].Ftype != "[[I" || len(arr.FieldTable["value"].Fvalue.([]*object.Object)) != 3 {
+		t.Errorf("Expected an int[3][], got %s", arr.FieldTable["value"].Ftype)
+	}
+
+	// getComponentType() of the array's class returns the component type passed
+	component := getComponentType([]interface{}{classObjectNamed("[[I")})
+	if component != classObjectNamed("[I") {
+		t.Errorf("Expected the component type int[], got %v", component)
+	}
+}
+
+func TestReflectArrayNewMultiInstance(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	dims := object.MakeArrayFromRawArray([]int64{2, 3})
+	arr := reflectArrayNewMultiInstance([]interface{}{classObjectNamed(types.StringClassName), dims}).(*object.Object)
+	if arr.FieldTable["value"].Ftype != "[[Ljava/lang/String;" {
+		t.Errorf("Expected a String[][], got %s", arr.FieldTable["value"].Ftype)
+	}
+	row := arr.FieldTable["value"].Fvalue.([]*object.Object)[1]
+	if row.FieldTable["value"].Ftype != "[Ljava/lang/String;" || reflectArrayGetLength([]interface{}{row}) != int64(3) {
+		t.Errorf("Expected rows of 3 Strings, got %s", row.FieldTable["value"].Ftype)
+	}
+
+	empty := object.MakeArrayFromRawArray([]int64{})
+	checkReflectArrayError(t, reflectArrayNewMultiInstance([]interface{}{primitiveClassObject(types.Int), empty}),
+		excNames.IllegalArgumentException)
+	negative := object.MakeArrayFromRawArray([]int64{2, -1})
+	checkReflectArrayError(t, reflectArrayNewMultiInstance([]interface{}{primitiveClassObject(types.Int), negative}),
+		excNames.NegativeArraySizeException)
+}
+
+func TestReflectArrayNewInstanceInvalid(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	checkReflectArrayError(t, reflectArrayNewInstance([]interface{}{object.Null, int64(1)}),
+		excNames.NullPointerException)
+	checkReflectArrayError(t, reflectArrayNewInstance([]interface{}{classObjectNamed("void"), int64(1)}),
+		excNames.IllegalArgumentException)
+	checkReflectArrayError(t, reflectArrayNewInstance([]interface{}{primitiveClassObject(types.Int), int64(-1)}),
+		excNames.NegativeArraySizeException)
+}
+
+func TestReflectArrayGetAndSetPrimitives(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	arr := object.Make1DimArray(object.INT, 3)
+	if ret := reflectArraySet([]interface{}{arr, int64(1), Populator("java/lang/Integer", types.Int, int64(42))}); ret != nil {
+		t.Fatalf("Expected set() to succeed, got %v", ret)
+	}
+	boxed := reflectArrayGet([]interface{}{arr, int64(1)}).(*object.Object)
+	if object.GoStringFromStringPoolIndex(boxed.KlassName) != "java/lang/Integer" || boxed.FieldTable["value"].Fvalue != int64(42) {
+		t.Errorf("Expected an Integer of 42, got %v", boxed.FieldTable["value"].Fvalue)
+	}
+
+	// a char widens to an int; a double doesn't narrow to one
+	if ret := reflectArraySet([]interface{}{arr, int64(2), Populator("java/lang/Character", types.Char, int64('A'))}); ret != nil {
+		t.Errorf("Expected a Character to be stored in an int array, got %v", ret)
+	}
+	checkReflectArrayError(t, reflectArraySet([]interface{}{arr, int64(0), Populator("java/lang/Double", types.Double, 1.5)}),
+		excNames.IllegalArgumentException)
+	checkReflectArrayError(t, reflectArraySet([]interface{}{arr, int64(0), object.Null}),
+		excNames.IllegalArgumentException)
+
+	// the typed getters widen the elements
+	if ret := reflectArrayGetPrimitive('D')([]interface{}{arr, int64(2)}); ret != float64('A') {
+		t.Errorf("Expected getDouble() to return 65.0, got %v", ret)
+	}
+	checkReflectArrayError(t, reflectArrayGetPrimitive('B')([]interface{}{arr, int64(2)}), excNames.IllegalArgumentException)
+
+	doubles := object.Make1DimArray(object.FLOAT, 2)
+	if ret := reflectArraySetPrimitive('J')([]interface{}{doubles, int64(0), int64(7)}); ret != nil {
+		t.Fatalf("Expected setLong() on a double array to succeed, got %v", ret)
+	}
+	if doubles.FieldTable["value"].Fvalue.([]float64)[0] != 7.0 {
+		t.Errorf("Expected 7.0, got %v", doubles.FieldTable["value"].Fvalue.([]float64)[0])
+	}
+
+	bytes := object.Make1DimArray(object.BYTE, 2)
+	if ret := reflectArraySetPrimitive('B')([]interface{}{bytes, int64(1), int64(-3)}); ret != nil {
+		t.Fatalf("Expected setByte() to succeed, got %v", ret)
+	}
+	if ret := reflectArrayGetPrimitive('I')([]interface{}{bytes, int64(1)}); ret != int64(-3) {
+		t.Errorf("Expected getInt() on a byte array to return -3, got %v", ret)
+	}
+}
+
+func TestReflectArrayGetAndSetReferences(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	arr := object.Make1DimRefArray("java/lang/String;", 2)
+	str := object.StringObjectFromGoString("hello")
+	if ret := reflectArraySet([]interface{}{arr, int64(0), str}); ret != nil {
+		t.Fatalf("Expected set() to succeed, got %v", ret)
+	}
+	if reflectArrayGet([]interface{}{arr, int64(0)}) != str {
+		t.Errorf("Expected get() to return the String stored")
+	}
+	if ret := reflectArrayGet([]interface{}{arr, int64(1)}); !object.IsNull(ret) {
+		t.Errorf("Expected get() of an unset element to return null, got %v", ret)
+	}
+
+	// an int[] isn't a String
+	checkReflectArrayError(t, reflectArraySet([]interface{}{arr, int64(1), object.Make1DimArray(object.INT, 1)}),
+		excNames.IllegalArgumentException)
+	checkReflectArrayError(t, reflectArrayGetPrimitive('I')([]interface{}{arr, int64(0)}),
+		excNames.IllegalArgumentException)
+}
+
+func TestReflectArrayInvalidArguments(t *testing.T) {
+	globals.InitGlobals("test")
+
+	checkReflectArrayError(t, reflectArrayGetLength([]interface{}{object.Null}), excNames.NullPointerException)
+	checkReflectArrayError(t, reflectArrayGetLength([]interface{}{object.StringObjectFromGoString("x")}),
+		excNames.IllegalArgumentException)
+
+	arr := object.Make1DimArray(object.INT, 2)
+	checkReflectArrayError(t, reflectArrayGet([]interface{}{arr, int64(2)}), excNames.ArrayIndexOutOfBoundsException)
+	checkReflectArrayError(t, reflectArrayGet([]interface{}{arr, int64(-1)}), excNames.ArrayIndexOutOfBoundsException)
+}
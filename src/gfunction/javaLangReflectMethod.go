@@ -0,0 +1,345 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"jacobin/src/util"
+	"strings"
+)
+
+// A partial implementation of java/lang/reflect/Method. A Method object holds the Class
+// object of its declaring class, its name, its modifiers, and, in a field that Java code
+// can't see, its descriptor. Method objects are presently created for the methods that a
+// proxy dispatches to its InvocationHandler; see javaLangReflectProxy.go.
+
+var classNameMethod = "java/lang/reflect/Method"
+
+// the field of a Method object that holds the string-pool index of its descriptor
+var fieldNameMethodDesc = "$desc"
+
+func Load_Lang_Reflect_Method() {
+
+	MethodSignatures["java/lang/reflect/Method.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/lang/reflect/Method.getDeclaringClass()Ljava/lang/Class;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  methodGetDeclaringClass,
+		}
+
+	MethodSignatures["java/lang/reflect/Method.getModifiers()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  methodGetModifiers,
+		}
+
+	MethodSignatures["java/lang/reflect/Method.getName()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  methodGetName,
+		}
+
+	MethodSignatures["java/lang/reflect/Method.getParameterCount()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  methodGetParameterCount,
+		}
+
+	MethodSignatures["java/lang/reflect/Method.getParameterTypes()[Ljava/lang/Class;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  methodGetParameterTypes,
+		}
+
+	MethodSignatures["java/lang/reflect/Method.getReturnType()Ljava/lang/Class;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  methodGetReturnType,
+		}
+
+	MethodSignatures["java/lang/reflect/Method.invoke(Ljava/lang/Object;[Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    methodInvoke,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/reflect/Method.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  methodToString,
+		}
+}
+
+// reflectMethod is the Go view of a Method object
+type reflectMethod struct {
+	className string
+	name      string
+	desc      string
+	modifiers int64
+}
+
+// newMethodObject returns a Method object for the method of the class with the name,
+// descriptor, and access flags passed
+func newMethodObject(className, name, desc string, accessFlags int) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&classNameMethod)
+	obj.FieldTable["clazz"] = object.Field{Ftype: types.Ref,
+		Fvalue: classloader.GetClassObject(stringPool.GetStringIndex(&className))}
+	obj.FieldTable["name"] = object.Field{Ftype: types.Ref, Fvalue: object.StringObjectFromGoString(name)}
+	obj.FieldTable["modifiers"] = object.Field{Ftype: types.Int, Fvalue: int64(accessFlags)}
+	obj.FieldTable[fieldNameMethodDesc] = object.Field{Ftype: types.StringIndex, Fvalue: stringPool.GetStringIndex(&desc)}
+	return obj
+}
+
+// returns the Go view of the Method object in params[0]
+func getReflectMethod(funcName string, params []interface{}) (*reflectMethod, *GErrBlk) {
+	obj, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return nil, getGErrBlk(excNames.NullPointerException, funcName+": the Method is null")
+	}
+	descIndex, ok := obj.FieldTable[fieldNameMethodDesc].Fvalue.(uint32)
+	if !ok {
+		return nil, getGErrBlk(excNames.IllegalStateException, funcName+": the Method is not initialized")
+	}
+	modifiers, _ := obj.FieldTable["modifiers"].Fvalue.(int64)
+	return &reflectMethod{
+		className: classloader.ClassNameFromClassObject(obj.FieldTable["clazz"].Fvalue.(*object.Object)),
+		name:      object.GoStringFromStringObject(obj.FieldTable["name"].Fvalue.(*object.Object)),
+		desc:      *stringPool.GetStringPointer(descIndex),
+		modifiers: modifiers,
+	}, nil
+}
+
+// splitMethodDescriptor returns the descriptors of the parameters and of the return type
+// of a method descriptor: "(I[Ljava/lang/String;)V" yields ["I", "[Ljava/lang/String;"]
+// and "V"
+func splitMethodDescriptor(desc string) ([]string, string) {
+	var paramTypes []string
+	i := 1 // skip the '('
+	for i < len(desc) && desc[i] != ')' {
+		start := i
+		for desc[i] == '[' {
+			i++
+		}
+		if desc[i] == 'L' {
+			i += strings.IndexByte(desc[i:], ';')
+		}
+		i++
+		paramTypes = append(paramTypes, desc[start:i])
+	}
+	return paramTypes, desc[i+1:]
+}
+
+// returns the Class object of the type with the descriptor passed, such as int.class for
+// "I" and String.class for "Ljava/lang/String;"
+func classObjectOfDescriptor(descriptor string) *object.Object {
+	if cl := primitiveClassObject(descriptor); cl != nil {
+		return cl
+	}
+	name := descriptor
+	switch {
+	case descriptor == "V":
+		name = "void"
+	case strings.HasPrefix(descriptor, types.Ref):
+		name = descriptor[1 : len(descriptor)-1]
+	}
+	return classloader.GetClassObject(stringPool.GetStringIndex(&name))
+}
+
+// boxValue returns the value of the primitive type passed, an int64 or a float64, in an
+// object of its wrapper class. References are returned unchanged.
+func boxValue(descriptor string, value any) any {
+	if wrapper, ok := wrapperClassNames[descriptor[0]]; ok && len(descriptor) == 1 {
+		return Populator(wrapper, descriptor, value)
+	}
+	return value
+}
+
+// unboxValue returns the primitive held by an object of the wrapper class of the primitive
+// type passed, or the object itself for reference types. A null or a value of the wrong
+// wrapper class can't be unboxed.
+func unboxValue(funcName, descriptor string, value any) (any, *GErrBlk) {
+	wrapper, ok := wrapperClassNames[descriptor[0]]
+	if !ok || len(descriptor) != 1 {
+		return value, nil
+	}
+	obj, ok := value.(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return nil, getGErrBlk(excNames.NullPointerException, funcName+": a null can't be converted to a "+primitiveClassNames[descriptor])
+	}
+	if className := object.GoStringFromStringPoolIndex(obj.KlassName); className != wrapper {
+		errMsg := fmt.Sprintf("%s: %s cannot be converted to %s", funcName,
+			util.ConvertInternalClassNameToUserFormat(className), primitiveClassNames[descriptor])
+		return nil, getGErrBlk(excNames.ClassCastException, errMsg)
+	}
+	return obj.FieldTable["value"].Fvalue, nil
+}
+
+// "java/lang/reflect/Method.getDeclaringClass()Ljava/lang/Class;"
+func methodGetDeclaringClass(params []interface{}) interface{} {
+	obj, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return getGErrBlk(excNames.NullPointerException, "methodGetDeclaringClass: the Method is null")
+	}
+	return obj.FieldTable["clazz"].Fvalue
+}
+
+// "java/lang/reflect/Method.getModifiers()I"
+func methodGetModifiers(params []interface{}) interface{} {
+	m, gErr := getReflectMethod("methodGetModifiers", params)
+	if gErr != nil {
+		return gErr
+	}
+	return m.modifiers
+}
+
+// "java/lang/reflect/Method.getName()Ljava/lang/String;"
+func methodGetName(params []interface{}) interface{} {
+	m, gErr := getReflectMethod("methodGetName", params)
+	if gErr != nil {
+		return gErr
+	}
+	return object.StringObjectFromGoString(m.name)
+}
+
+// "java/lang/reflect/Method.getParameterCount()I"
+func methodGetParameterCount(params []interface{}) interface{} {
+	m, gErr := getReflectMethod("methodGetParameterCount", params)
+	if gErr != nil {
+		return gErr
+	}
+	paramTypes, _ := splitMethodDescriptor(m.desc)
+	return int64(len(paramTypes))
+}
+
+// "java/lang/reflect/Method.getParameterTypes()[Ljava/lang/Class;"
+func methodGetParameterTypes(params []interface{}) interface{} {
+	m, gErr := getReflectMethod("methodGetParameterTypes", params)
+	if gErr != nil {
+		return gErr
+	}
+	paramTypes, _ := splitMethodDescriptor(m.desc)
+	arr := object.Make1DimRefArray("java/lang/Class;", int64(len(paramTypes)))
+	classes := arr.FieldTable["value"].Fvalue.([]*object.Object)
+	for i, paramType := range paramTypes {
+		classes[i] = classObjectOfDescriptor(paramType)
+	}
+	return arr
+}
+
+// "java/lang/reflect/Method.getReturnType()Ljava/lang/Class;"
+func methodGetReturnType(params []interface{}) interface{} {
+	m, gErr := getReflectMethod("methodGetReturnType", params)
+	if gErr != nil {
+		return gErr
+	}
+	_, returnType := splitMethodDescriptor(m.desc)
+	return classObjectOfDescriptor(returnType)
+}
+
+// "java/lang/reflect/Method.invoke(Ljava/lang/Object;[Ljava/lang/Object;)Ljava/lang/Object;"
+// -- the arguments are unboxed for the method's primitive parameters, and a primitive
+// return value is boxed. An exception thrown by the method is rethrown as is, rather than
+// wrapped in an InvocationTargetException.
+func methodInvoke(params []interface{}) interface{} {
+	fs, _ := params[0].(*list.List)
+	m, gErr := getReflectMethod("methodInvoke", params[1:])
+	if gErr != nil {
+		return gErr
+	}
+
+	var receiver any
+	if m.modifiers&0x0008 == 0 { // not static
+		obj, ok := params[2].(*object.Object)
+		if !ok || object.IsNull(obj) {
+			return getGErrBlk(excNames.NullPointerException, "methodInvoke: the target of an instance method is null")
+		}
+		receiver = obj
+	}
+
+	var argObjs []*object.Object
+	if argsArr, ok := params[3].(*object.Object); ok && !object.IsNull(argsArr) {
+		argObjs, _ = argsArr.FieldTable["value"].Fvalue.([]*object.Object)
+	}
+	paramTypes, returnType := splitMethodDescriptor(m.desc)
+	if len(argObjs) != len(paramTypes) {
+		errMsg := fmt.Sprintf("methodInvoke: wrong number of arguments: %d expected: %d", len(argObjs), len(paramTypes))
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	args := make([]any, len(paramTypes))
+	for i, paramType := range paramTypes {
+		arg, gErr := unboxValue("methodInvoke", paramType, argObjs[i])
+		if gErr != nil {
+			return getGErrBlk(excNames.IllegalArgumentException, gErr.ErrMsg)
+		}
+		args[i] = arg
+	}
+
+	ret, gErr := invokeJavaMethod(fs, m.className, m.name, m.desc, receiver, args)
+	if gErr != nil {
+		return gErr
+	}
+	if returnType == "V" {
+		return object.Null
+	}
+	return boxValue(returnType, ret)
+}
+
+// "java/lang/reflect/Method.toString()Ljava/lang/String;" -- such as
+// "public abstract int java.lang.Comparable.compareTo(java.lang.Object)"
+func methodToString(params []interface{}) interface{} {
+	m, gErr := getReflectMethod("methodToString", params)
+	if gErr != nil {
+		return gErr
+	}
+	var sb strings.Builder
+	for _, modifier := range []struct {
+		flag int64
+		name string
+	}{{0x0001, "public "}, {0x0004, "protected "}, {0x0002, "private "}, {0x0400, "abstract "},
+		{0x0008, "static "}, {0x0010, "final "}, {0x0020, "synchronized "}, {0x0100, "native "}} {
+		if m.modifiers&modifier.flag != 0 {
+			sb.WriteString(modifier.name)
+		}
+	}
+	paramTypes, returnType := splitMethodDescriptor(m.desc)
+	sb.WriteString(typeNameOfDescriptor(returnType) + " ")
+	sb.WriteString(util.ConvertInternalClassNameToUserFormat(m.className) + "." + m.name + "(")
+	for i, paramType := range paramTypes {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(typeNameOfDescriptor(paramType))
+	}
+	sb.WriteString(")")
+	return object.StringObjectFromGoString(sb.String())
+}
+
+// returns the name of a type in Java source form, such as "int[]" for "[I"
+func typeNameOfDescriptor(descriptor string) string {
+	dims := len(descriptor) - len(strings.TrimLeft(descriptor, types.Array))
+	elementType := descriptor[dims:]
+	name, ok := primitiveClassNames[elementType]
+	switch {
+	case elementType == "V":
+		name = "void"
+	case !ok:
+		name = util.ConvertInternalClassNameToUserFormat(elementType[1 : len(elementType)-1])
+	}
+	return name + strings.Repeat("[]", dims)
+}
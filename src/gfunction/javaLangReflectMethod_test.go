@@ -0,0 +1,83 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"slices"
+	"testing"
+)
+
+func TestSplitMethodDescriptor(t *testing.T) {
+	paramTypes, returnType := splitMethodDescriptor("(I[[Ljava/lang/String;JLjava/lang/Object;)[D")
+	expected := []string{"I", "[[Ljava/lang/String;", "J", "Ljava/lang/Object;"}
+	if !slices.Equal(paramTypes, expected) || returnType != "[D" {
+		t.Errorf("Expected %v and [D, got %v and %s", expected, paramTypes, returnType)
+	}
+
+	paramTypes, returnType = splitMethodDescriptor("()V")
+	if len(paramTypes) != 0 || returnType != "V" {
+		t.Errorf("Expected no parameters and V, got %v and %s", paramTypes, returnType)
+	}
+}
+
+func TestMethodToString(t *testing.T) {
+	globals.InitGlobals("test")
+	method := newMethodObject("java/lang/Comparable", "compareTo", "(Ljava/lang/Object;)I", 0x0401)
+	ret := methodToString([]interface{}{method}).(*object.Object)
+	expected := "public abstract int java.lang.Comparable.compareTo(java.lang.Object)"
+	if object.GoStringFromStringObject(ret) != expected {
+		t.Errorf("Expected %s, got %s", expected, object.GoStringFromStringObject(ret))
+	}
+
+	if methodGetParameterCount([]interface{}{method}) != int64(1) {
+		t.Errorf("Expected 1 parameter")
+	}
+	if methodGetReturnType([]interface{}{method}) != primitiveClassObject(types.Int) {
+		t.Errorf("Expected the return type int.class")
+	}
+}
+
+func TestMethodInvoke(t *testing.T) {
+	globals.InitGlobals("test")
+	glob := globals.GetGlobalRef()
+	saved := glob.FuncInvokeJavaMethod
+	glob.FuncInvokeJavaMethod = func(_ *list.List, className, methName, _ string, receiver any, args []any) (any, error) {
+		if className != "test/Adder" || methName != "add" || receiver != nil {
+			t.Fatalf("unexpected upcall of %s.%s", className, methName)
+		}
+		return args[0].(int64) + args[1].(int64), nil
+	}
+	t.Cleanup(func() { glob.FuncInvokeJavaMethod = saved })
+
+	method := newMethodObject("test/Adder", "add", "(IJ)J", 0x0009) // public static
+	args := object.Make1DimRefArray("java/lang/Object;", 2)
+	elems := args.FieldTable["value"].Fvalue.([]*object.Object)
+	elems[0] = Populator("java/lang/Integer", types.Int, int64(2))
+	elems[1] = Populator("java/lang/Long", types.Long, int64(40))
+
+	ret := methodInvoke([]interface{}{list.New(), method, object.Null, args}).(*object.Object)
+	if object.GoStringFromStringPoolIndex(ret.KlassName) != "java/lang/Long" || ret.FieldTable["value"].Fvalue != int64(42) {
+		t.Errorf("Expected a Long of 42, got %v", ret.FieldTable["value"].Fvalue)
+	}
+
+	// an argument of the wrong wrapper class, or the wrong number of arguments
+	elems[1] = object.StringObjectFromGoString("40")
+	checkReflectArrayError(t, methodInvoke([]interface{}{list.New(), method, object.Null, args}),
+		excNames.IllegalArgumentException)
+	checkReflectArrayError(t, methodInvoke([]interface{}{list.New(), method, object.Null, object.Null}),
+		excNames.IllegalArgumentException)
+
+	// an instance method needs a target
+	instanceMethod := newMethodObject("test/Adder", "add", "(IJ)J", 0x0001)
+	checkReflectArrayError(t, methodInvoke([]interface{}{list.New(), instanceMethod, object.Null, args}),
+		excNames.NullPointerException)
+}
@@ -0,0 +1,296 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"jacobin/src/util"
+	"strings"
+	"sync"
+)
+
+// Implementation of java/lang/reflect/Proxy. A proxy class is synthesized in memory rather
+// than generated as a class file: it's a class in the method area, a subclass of Proxy that
+// implements the interfaces passed to newProxyInstance() and that has no bytecode. Instead,
+// each of the methods of the interfaces, and equals(), hashCode(), and toString(), is
+// entered in the MTable as a gfunction that passes the call to the proxy's
+// InvocationHandler, whose invoke() method is called with the proxy, the Method object of
+// the method called, and the arguments, boxed, in an Object[]. INVOKEINTERFACE finds these
+// gfunctions in the MTable when it looks up the methods of the proxy's class.
+
+var classNameProxy = "java/lang/reflect/Proxy"
+
+// the field of a proxy that holds its InvocationHandler, as in the JDK
+var fieldNameProxyHandler = "h"
+
+var invocationHandlerInvokeDesc = "(Ljava/lang/Object;Ljava/lang/reflect/Method;[Ljava/lang/Object;)Ljava/lang/Object;"
+
+// the proxy classes, by the names of the interfaces they implement, joined by commas
+var proxyClasses = make(map[string]string)
+var proxyClassesMutex sync.Mutex
+
+func Load_Lang_Reflect_Proxy() {
+
+	MethodSignatures["java/lang/reflect/Proxy.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/lang/reflect/Proxy.getInvocationHandler(Ljava/lang/Object;)Ljava/lang/reflect/InvocationHandler;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  proxyGetInvocationHandler,
+		}
+
+	MethodSignatures["java/lang/reflect/Proxy.getProxyClass(Ljava/lang/ClassLoader;[Ljava/lang/Class;)Ljava/lang/Class;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  proxyGetProxyClass,
+		}
+
+	MethodSignatures["java/lang/reflect/Proxy.isProxyClass(Ljava/lang/Class;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  proxyIsProxyClass,
+		}
+
+	MethodSignatures["java/lang/reflect/Proxy.newProxyInstance(Ljava/lang/ClassLoader;[Ljava/lang/Class;Ljava/lang/reflect/InvocationHandler;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  proxyNewProxyInstance,
+		}
+}
+
+// "java/lang/reflect/Proxy.newProxyInstance(Ljava/lang/ClassLoader;[Ljava/lang/Class;Ljava/lang/reflect/InvocationHandler;)Ljava/lang/Object;"
+// -- Jacobin has a single application classloader, so the ClassLoader is ignored
+func proxyNewProxyInstance(params []interface{}) interface{} {
+	if object.IsNull(params[2]) {
+		return getGErrBlk(excNames.NullPointerException, "proxyNewProxyInstance: the InvocationHandler is null")
+	}
+	className, gErr := proxyClassFor("proxyNewProxyInstance", params[1])
+	if gErr != nil {
+		return gErr
+	}
+	proxy := object.MakeEmptyObjectWithClassName(&className)
+	proxy.FieldTable[fieldNameProxyHandler] = object.Field{
+		Ftype: "Ljava/lang/reflect/InvocationHandler;", Fvalue: params[2]}
+	return proxy
+}
+
+// "java/lang/reflect/Proxy.getProxyClass(Ljava/lang/ClassLoader;[Ljava/lang/Class;)Ljava/lang/Class;"
+func proxyGetProxyClass(params []interface{}) interface{} {
+	className, gErr := proxyClassFor("proxyGetProxyClass", params[1])
+	if gErr != nil {
+		return gErr
+	}
+	return classloader.GetClassObject(stringPool.GetStringIndex(&className))
+}
+
+// "java/lang/reflect/Proxy.isProxyClass(Ljava/lang/Class;)Z"
+func proxyIsProxyClass(params []interface{}) interface{} {
+	if object.IsNull(params[0]) {
+		return getGErrBlk(excNames.NullPointerException, "proxyIsProxyClass: the class is null")
+	}
+	className := classloader.ClassNameFromClassObject(params[0].(*object.Object))
+	proxyClassesMutex.Lock()
+	defer proxyClassesMutex.Unlock()
+	for _, proxyClass := range proxyClasses {
+		if className == proxyClass {
+			return types.JavaBoolTrue
+		}
+	}
+	return types.JavaBoolFalse
+}
+
+// "java/lang/reflect/Proxy.getInvocationHandler(Ljava/lang/Object;)Ljava/lang/reflect/InvocationHandler;"
+func proxyGetInvocationHandler(params []interface{}) interface{} {
+	proxy, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(proxy) {
+		return getGErrBlk(excNames.NullPointerException, "proxyGetInvocationHandler: the proxy is null")
+	}
+	handler, ok := proxy.FieldTable[fieldNameProxyHandler]
+	if !ok {
+		return getGErrBlk(excNames.IllegalArgumentException, "proxyGetInvocationHandler: not a proxy instance")
+	}
+	return handler.Fvalue
+}
+
+// proxyClassFor returns the name of the proxy class that implements the interfaces whose
+// Class objects are in the array passed, synthesizing the class the first time these
+// interfaces are requested
+func proxyClassFor(funcName string, interfacesParam any) (string, *GErrBlk) {
+	interfacesArr, ok := interfacesParam.(*object.Object)
+	if !ok || object.IsNull(interfacesArr) {
+		return "", getGErrBlk(excNames.NullPointerException, funcName+": the interfaces are null")
+	}
+	classes, _ := interfacesArr.FieldTable["value"].Fvalue.([]*object.Object)
+	if len(classes) > 65535 {
+		return "", getGErrBlk(excNames.IllegalArgumentException, funcName+": interface limit exceeded")
+	}
+
+	var interfaces []string
+	for _, cl := range classes {
+		if object.IsNull(cl) {
+			return "", getGErrBlk(excNames.NullPointerException, funcName+": an interface is null")
+		}
+		name := classloader.ClassNameFromClassObject(cl)
+		if classloader.MethAreaFetch(name) == nil && classloader.LoadClassFromNameOnly(name) != nil {
+			errMsg := fmt.Sprintf("%s: %s referenced from a method is not visible from class loader",
+				funcName, util.ConvertInternalClassNameToUserFormat(name))
+			return "", getGErrBlk(excNames.IllegalArgumentException, errMsg)
+		}
+		if !classloader.IsInterface(name) {
+			errMsg := fmt.Sprintf("%s: %s is not an interface", funcName, util.ConvertInternalClassNameToUserFormat(name))
+			return "", getGErrBlk(excNames.IllegalArgumentException, errMsg)
+		}
+		for _, iface := range interfaces {
+			if iface == name {
+				errMsg := fmt.Sprintf("%s: repeated interface: %s", funcName, util.ConvertInternalClassNameToUserFormat(name))
+				return "", getGErrBlk(excNames.IllegalArgumentException, errMsg)
+			}
+		}
+		interfaces = append(interfaces, name)
+	}
+
+	key := strings.Join(interfaces, ",")
+	proxyClassesMutex.Lock()
+	defer proxyClassesMutex.Unlock()
+	if className, ok := proxyClasses[key]; ok {
+		return className, nil
+	}
+	className := fmt.Sprintf("jdk/proxy1/$Proxy%d", len(proxyClasses))
+	defineProxyClass(className, interfaces)
+	proxyClasses[key] = className
+	return className, nil
+}
+
+// defineProxyClass puts the proxy class in the method area and its methods in the MTable
+func defineProxyClass(className string, interfaces []string) {
+	clData := classloader.ClData{
+		Name:            className,
+		NameIndex:       stringPool.GetStringIndex(&className),
+		SuperclassIndex: stringPool.GetStringIndex(&classNameProxy),
+		Pkg:             "jdk/proxy1",
+		MethodList:      make(map[string]string),
+		MethodTable:     make(map[string]*classloader.Method),
+		Access:          classloader.AccessFlags{ClassIsPublic: true, ClassIsFinal: true},
+		ClInit:          types.NoClInit,
+	}
+	for _, iface := range interfaces {
+		clData.Interfaces = append(clData.Interfaces, uint16(stringPool.GetStringIndex(&iface)))
+	}
+	classloader.MethAreaInsert(className, &classloader.Klass{Status: 'L', Loader: "bootstrap", Data: &clData})
+
+	// equals(), hashCode(), and toString() are dispatched as methods of Object, as in the
+	// JDK; the methods of the interfaces, by the first interface that declares them
+	methods := map[string]*object.Object{
+		"equals(Ljava/lang/Object;)Z":  newMethodObject(types.ObjectClassName, "equals", "(Ljava/lang/Object;)Z", 0x0001),
+		"hashCode()I":                  newMethodObject(types.ObjectClassName, "hashCode", "()I", 0x0101),
+		"toString()Ljava/lang/String;": newMethodObject(types.ObjectClassName, "toString", "()Ljava/lang/String;", 0x0001),
+	}
+	for _, iface := range interfaces {
+		addProxyMethods(iface, methods, make(map[string]bool))
+	}
+
+	for nameAndDesc, method := range methods {
+		name, desc, _ := strings.Cut(nameAndDesc, "(")
+		desc = "(" + desc
+		classloader.AddEntry(&classloader.MTable, className+"."+nameAndDesc, classloader.MTentry{
+			MType: 'G',
+			Meth: GMeth{
+				ParamSlots:   len(util.ParseIncomingParamsFromMethTypeString(desc)),
+				GFunction:    proxyDispatch(name, desc, method),
+				NeedsContext: true,
+			},
+		})
+	}
+}
+
+// adds the Method objects of the instance methods of the interface and its
+// superinterfaces that aren't yet among the methods
+func addProxyMethods(iface string, methods map[string]*object.Object, visited map[string]bool) {
+	if visited[iface] {
+		return
+	}
+	visited[iface] = true
+	k := classloader.MethAreaFetch(iface)
+	if k == nil && classloader.LoadClassFromNameOnly(iface) == nil {
+		k = classloader.MethAreaFetch(iface)
+	}
+	if k == nil || k.Data == nil {
+		return
+	}
+	for nameAndDesc, m := range k.Data.MethodTable {
+		if _, ok := methods[nameAndDesc]; ok || strings.HasPrefix(nameAndDesc, "<") ||
+			m.AccessFlags&0x000A != 0 { // private or static
+			continue
+		}
+		name, desc, _ := strings.Cut(nameAndDesc, "(")
+		methods[nameAndDesc] = newMethodObject(iface, name, "("+desc, m.AccessFlags)
+	}
+	for _, index := range k.Data.Interfaces {
+		addProxyMethods(*stringPool.GetStringPointer(uint32(index)), methods, visited)
+	}
+}
+
+// returns the gfunction of a proxy method, which calls the invoke() method of the proxy's
+// InvocationHandler and returns what it returns, unboxed if the method returns a primitive
+func proxyDispatch(name, desc string, method *object.Object) func([]interface{}) interface{} {
+	paramTypes, returnType := splitMethodDescriptor(desc)
+	return func(params []interface{}) interface{} {
+		fs, _ := params[0].(*list.List)
+		proxy, ok := params[1].(*object.Object)
+		if !ok || object.IsNull(proxy) {
+			return getGErrBlk(excNames.NullPointerException, "proxyDispatch: the proxy is null")
+		}
+		handler := proxy.FieldTable[fieldNameProxyHandler].Fvalue
+
+		// the arguments are passed in an Object[], or as null if there are none
+		var args any = object.Null
+		if len(paramTypes) > 0 {
+			arr := object.Make1DimRefArray("java/lang/Object;", int64(len(paramTypes)))
+			elems := arr.FieldTable["value"].Fvalue.([]*object.Object)
+			for i, paramType := range paramTypes {
+				elems[i], _ = boxValue(paramType, params[2+i]).(*object.Object)
+			}
+			args = arr
+		}
+
+		ret, gErr := invokeJavaMethod(fs, "java/lang/reflect/InvocationHandler", "invoke",
+			invocationHandlerInvokeDesc, handler, []any{proxy, method, args})
+		if gErr != nil {
+			return gErr
+		}
+		if returnType == "V" {
+			return nil
+		}
+		value, gErr := unboxValue("proxyDispatch", returnType, ret)
+		if gErr != nil {
+			return gErr
+		}
+		if obj, ok := value.(*object.Object); ok && !object.IsNull(obj) && len(returnType) > 1 {
+			returnClass := returnType
+			if strings.HasPrefix(returnType, types.Ref) {
+				returnClass = returnType[1 : len(returnType)-1]
+			}
+			if !classloader.IsAssignableToComponent(classloader.TypeNameOf(obj), returnClass) {
+				errMsg := fmt.Sprintf("proxyDispatch: %s.%s cannot return %s", util.ConvertInternalClassNameToUserFormat(
+					classloader.ClassNameFromClassObject(method.FieldTable["clazz"].Fvalue.(*object.Object))), name,
+					util.ConvertInternalClassNameToUserFormat(classloader.TypeNameOf(obj)))
+				return getGErrBlk(excNames.ClassCastException, errMsg)
+			}
+		}
+		return value
+	}
+}
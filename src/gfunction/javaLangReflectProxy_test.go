@@ -0,0 +1,202 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"testing"
+)
+
+// puts an interface with the abstract methods passed in the method area, and returns
+// its Class object
+func defineTestInterface(name string, superinterfaces []string, methods ...string) *object.Object {
+	clData := classloader.ClData{
+		Name:        name,
+		MethodTable: make(map[string]*classloader.Method),
+		Access:      classloader.AccessFlags{ClassIsPublic: true, ClassIsInterface: true, ClassIsAbstract: true},
+	}
+	for _, method := range methods {
+		clData.MethodTable[method] = &classloader.Method{AccessFlags: 0x0401} // public abstract
+	}
+	for _, superinterface := range superinterfaces {
+		clData.Interfaces = append(clData.Interfaces, uint16(stringPool.GetStringIndex(&superinterface)))
+	}
+	classloader.MethAreaInsert(name, &classloader.Klass{Status: 'F', Loader: "bootstrap", Data: &clData})
+	return classObjectNamed(name)
+}
+
+// returns a Class[] holding the Class objects passed
+func classArray(classes ...*object.Object) *object.Object {
+	arr := object.Make1DimRefArray("java/lang/Class;", int64(len(classes)))
+	copy(arr.FieldTable["value"].Fvalue.([]*object.Object), classes)
+	return arr
+}
+
+// replaces the upcall bridge with an InvocationHandler whose invoke() method calls the
+// function passed with the Method object and the arguments
+func fakeInvocationHandler(t *testing.T, invoke func(proxy, method *object.Object, args []*object.Object) any) *object.Object {
+	globals.InitGlobals("test")
+	handlerClass := "test/Handler"
+	handler := object.MakeEmptyObjectWithClassName(&handlerClass)
+
+	glob := globals.GetGlobalRef()
+	saved := glob.FuncInvokeJavaMethod
+	glob.FuncInvokeJavaMethod = func(_ *list.List, className, methName, methType string, receiver any, args []any) (any, error) {
+		if className != "java/lang/reflect/InvocationHandler" || methName != "invoke" ||
+			methType != invocationHandlerInvokeDesc || receiver != handler {
+			t.Fatalf("unexpected upcall of %s.%s%s", className, methName, methType)
+		}
+		var elems []*object.Object
+		if arr := args[2].(*object.Object); !object.IsNull(arr) {
+			elems = arr.FieldTable["value"].Fvalue.([]*object.Object)
+		}
+		return invoke(args[0].(*object.Object), args[1].(*object.Object), elems), nil
+	}
+	t.Cleanup(func() { glob.FuncInvokeJavaMethod = saved })
+	return handler
+}
+
+// calls the method of the proxy as INVOKEINTERFACE does, through the MTable entry of the
+// proxy's class
+func callProxy(t *testing.T, proxy *object.Object, method string, args ...interface{}) interface{} {
+	t.Helper()
+	className := object.GoStringFromStringPoolIndex(proxy.KlassName)
+	entry, ok := classloader.MTable[className+"."+method]
+	if !ok {
+		t.Fatalf("%s has no method %s", className, method)
+	}
+	params := append([]interface{}{list.New(), proxy}, args...)
+	return entry.Meth.(GMeth).GFunction(params)
+}
+
+func TestProxyDispatchesToInvocationHandler(t *testing.T) {
+	var calledMethod string
+	var calledArgs []*object.Object
+	handler := fakeInvocationHandler(t, func(_, method *object.Object, args []*object.Object) any {
+		calledMethod = object.GoStringFromStringObject(method.FieldTable["name"].Fvalue.(*object.Object))
+		calledArgs = args
+		return object.StringObjectFromGoString("hi")
+	})
+	classloader.InitMethodArea()
+	greeter := defineTestInterface("test/ProxyGreeter", nil, "greet(Ljava/lang/String;I)Ljava/lang/String;")
+
+	proxy := proxyNewProxyInstance([]interface{}{object.Null, classArray(greeter), handler}).(*object.Object)
+	ret := callProxy(t, proxy, "greet(Ljava/lang/String;I)Ljava/lang/String;",
+		object.StringObjectFromGoString("Bob"), int64(3))
+
+	if object.GoStringFromStringObject(ret.(*object.Object)) != "hi" {
+		t.Errorf("Expected the handler's return value, got %v", ret)
+	}
+	if calledMethod != "greet" || len(calledArgs) != 2 {
+		t.Fatalf("Expected greet() with 2 arguments, got %s() with %d", calledMethod, len(calledArgs))
+	}
+	if object.GoStringFromStringObject(calledArgs[0]) != "Bob" || calledArgs[1].FieldTable["value"].Fvalue != int64(3) {
+		t.Errorf("Expected the arguments \"Bob\" and an Integer of 3")
+	}
+
+	// equals(), hashCode(), and toString() go to the handler, too
+	if _, ok := classloader.MTable[object.GoStringFromStringPoolIndex(proxy.KlassName)+".toString()Ljava/lang/String;"]; !ok {
+		t.Errorf("Expected the proxy class to dispatch toString()")
+	}
+}
+
+func TestProxyUnboxesReturnValues(t *testing.T) {
+	var result any
+	handler := fakeInvocationHandler(t, func(_, _ *object.Object, args []*object.Object) any {
+		if args != nil {
+			t.Errorf("Expected null arguments for a method without parameters")
+		}
+		return result
+	})
+	classloader.InitMethodArea()
+	counter := defineTestInterface("test/ProxyCounter", nil, "count()I")
+	proxy := proxyNewProxyInstance([]interface{}{object.Null, classArray(counter), handler}).(*object.Object)
+
+	result = Populator("java/lang/Integer", types.Int, int64(42))
+	if ret := callProxy(t, proxy, "count()I"); ret != int64(42) {
+		t.Errorf("Expected 42, got %v", ret)
+	}
+
+	result = object.Null
+	checkReflectArrayError(t, callProxy(t, proxy, "count()I"), excNames.NullPointerException)
+
+	result = object.StringObjectFromGoString("42")
+	checkReflectArrayError(t, callProxy(t, proxy, "count()I"), excNames.ClassCastException)
+}
+
+func TestProxyInheritsSuperinterfaceMethods(t *testing.T) {
+	handler := fakeInvocationHandler(t, func(_, method *object.Object, _ []*object.Object) any {
+		return method
+	})
+	classloader.InitMethodArea()
+	defineTestInterface("test/ProxyBase", nil, "run()Ljava/lang/Object;")
+	derived := defineTestInterface("test/ProxyDerived", []string{"test/ProxyBase"}, "stop()V")
+
+	proxy := proxyNewProxyInstance([]interface{}{object.Null, classArray(derived), handler}).(*object.Object)
+	method := callProxy(t, proxy, "run()Ljava/lang/Object;").(*object.Object)
+	declaringClass := classloader.ClassNameFromClassObject(method.FieldTable["clazz"].Fvalue.(*object.Object))
+	if declaringClass != "test/ProxyBase" {
+		t.Errorf("Expected run() to be declared by test.ProxyBase, got %s", declaringClass)
+	}
+	if ret := callProxy(t, proxy, "stop()V"); ret != nil {
+		t.Errorf("Expected a void method to return nothing, got %v", ret)
+	}
+}
+
+func TestProxyClassIsShared(t *testing.T) {
+	handler := fakeInvocationHandler(t, nil)
+	classloader.InitMethodArea()
+	shared := defineTestInterface("test/ProxyShared", nil, "run()V")
+
+	proxy1 := proxyNewProxyInstance([]interface{}{object.Null, classArray(shared), handler}).(*object.Object)
+	proxy2 := proxyNewProxyInstance([]interface{}{object.Null, classArray(shared), handler}).(*object.Object)
+	if proxy1 == proxy2 || proxy1.KlassName != proxy2.KlassName {
+		t.Errorf("Expected two proxies of the same class")
+	}
+
+	proxyClass := proxyGetProxyClass([]interface{}{object.Null, classArray(shared)}).(*object.Object)
+	if classloader.ClassNameFromClassObject(proxyClass) != object.GoStringFromStringPoolIndex(proxy1.KlassName) {
+		t.Errorf("Expected getProxyClass() to return the class of the proxies")
+	}
+	if proxyIsProxyClass([]interface{}{proxyClass}) != types.JavaBoolTrue {
+		t.Errorf("Expected isProxyClass() to be true for the proxy class")
+	}
+	if proxyIsProxyClass([]interface{}{shared}) != types.JavaBoolFalse {
+		t.Errorf("Expected isProxyClass() to be false for an interface")
+	}
+	if proxyGetInvocationHandler([]interface{}{proxy1}) != handler {
+		t.Errorf("Expected getInvocationHandler() to return the handler")
+	}
+	if !classloader.ImplementsInterface(object.GoStringFromStringPoolIndex(proxy1.KlassName), "test/ProxyShared") {
+		t.Errorf("Expected the proxy class to implement the interface")
+	}
+}
+
+func TestProxyInvalidArguments(t *testing.T) {
+	handler := fakeInvocationHandler(t, nil)
+	classloader.InitMethodArea()
+	iface := defineTestInterface("test/ProxyInvalid", nil, "run()V")
+	notInterface := defineTestInterface("test/ProxyNotInterface", nil)
+	classloader.MethAreaFetch("test/ProxyNotInterface").Data.Access.ClassIsInterface = false
+
+	checkReflectArrayError(t, proxyNewProxyInstance([]interface{}{object.Null, classArray(iface), object.Null}),
+		excNames.NullPointerException)
+	checkReflectArrayError(t, proxyNewProxyInstance([]interface{}{object.Null, object.Null, handler}),
+		excNames.NullPointerException)
+	checkReflectArrayError(t, proxyNewProxyInstance([]interface{}{object.Null, classArray(notInterface), handler}),
+		excNames.IllegalArgumentException)
+	checkReflectArrayError(t, proxyNewProxyInstance([]interface{}{object.Null, classArray(iface, iface), handler}),
+		excNames.IllegalArgumentException)
+	checkReflectArrayError(t, proxyGetInvocationHandler([]interface{}{object.StringObjectFromGoString("x")}),
+		excNames.IllegalArgumentException)
+}
@@ -7,11 +7,15 @@
 package gfunction
 
 import (
+	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
+	"jacobin/src/globals"
 	"jacobin/src/object"
 	"jacobin/src/statics"
 	"jacobin/src/types"
 	"math"
 	"runtime"
+	"strings"
 )
 
 func Load_Lang_Runtime() {
@@ -43,37 +47,37 @@ func Load_Lang_Runtime() {
 	MethodSignatures["java/lang/Runtime.exec(Ljava/lang/String;)Ljava/lang/Process;"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  trapDeprecated,
+			GFunction:  runtimeExecString,
 		}
 
 	MethodSignatures["java/lang/Runtime.exec([Ljava/lang/String;)Ljava/lang/Process;"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  trapFunction,
+			GFunction:  runtimeExecArray,
 		}
 
 	MethodSignatures["java/lang/Runtime.exec([Ljava/lang/String;[Ljava/lang/String;)Ljava/lang/Process;"] =
 		GMeth{
 			ParamSlots: 2,
-			GFunction:  trapFunction,
+			GFunction:  runtimeExecArray,
 		}
 
 	MethodSignatures["java/lang/Runtime.exec([Ljava/lang/String;[Ljava/lang/String;Ljava/io/File;)Ljava/lang/Process;"] =
 		GMeth{
 			ParamSlots: 3,
-			GFunction:  trapFunction,
+			GFunction:  runtimeExecArray,
 		}
 
 	MethodSignatures["java/lang/Runtime.exec(Ljava/lang/String;[Ljava/lang/String;)Ljava/lang/Process;"] =
 		GMeth{
 			ParamSlots: 2,
-			GFunction:  trapDeprecated,
+			GFunction:  runtimeExecString,
 		}
 
 	MethodSignatures["java/lang/Runtime.exec(Ljava/lang/String;[Ljava/lang/String;Ljava/io/File;)Ljava/lang/Process;"] =
 		GMeth{
 			ParamSlots: 3,
-			GFunction:  trapDeprecated,
+			GFunction:  runtimeExecString,
 		}
 
 	MethodSignatures["java/lang/Runtime.exit(I)V"] =
@@ -108,26 +112,30 @@ func Load_Lang_Runtime() {
 
 	MethodSignatures["java/lang/Runtime.load(Ljava/lang/String;)V"] =
 		GMeth{
-			ParamSlots: 1,
-			GFunction:  trapFunction,
+			ParamSlots:   1,
+			GFunction:    runtimeLoad,
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/lang/Runtime.load0(Ljava/lang/Class;Ljava/lang/String;)V"] =
 		GMeth{
-			ParamSlots: 2,
-			GFunction:  trapFunction,
+			ParamSlots:   2,
+			GFunction:    runtimeLoad,
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/lang/Runtime.loadLibrary(Ljava/lang/String;)V"] =
 		GMeth{
-			ParamSlots: 1,
-			GFunction:  trapFunction,
+			ParamSlots:   1,
+			GFunction:    runtimeLoadLibrary,
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/lang/Runtime.loadLibrary0(Ljava/lang/Class;Ljava/lang/String;)V"] =
 		GMeth{
-			ParamSlots: 2,
-			GFunction:  trapFunction,
+			ParamSlots:   2,
+			GFunction:    runtimeLoadLibrary,
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/lang/Runtime.maxMemory()J"] =
@@ -179,14 +187,92 @@ func runtimeGetRuntime([]interface{}) interface{} {
 	return statics.GetStaticValue(stringClassnameRuntime, stringFieldCurrentRuntime)
 }
 
-// runtimeAvailableProcessors: Get the number of CPU cores.
+// runtimeAvailableProcessors: Get the number of CPU cores, or the number set by -XX:ActiveProcessorCount.
 func runtimeAvailableProcessors([]interface{}) interface{} {
+	if activeCPUs := globals.GetGlobalRef().ActiveCPUs; activeCPUs > 0 {
+		return int64(activeCPUs)
+	}
 	return int64(runtime.NumCPU())
 }
 
-// maxMemory: Get the maximum amount of memory that the max Jacobin will attempt to use. If there is no limit,
-// Java return Long.MAX_VALUE, which is what we do here
+// runtimeExecString: Start a command given as one string, which is split into words at white space, as Java's
+// StringTokenizer splits it: quotes are not treated specially. The forms that take the environment and the working
+// directory of the process are handled by runtimeExec().
+func runtimeExecString(params []interface{}) interface{} {
+	command, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("runtimeExecString", err)
+	}
+	words := strings.FieldsFunc(command, func(r rune) bool { return strings.ContainsRune(" \t\n\r\f", r) })
+	if len(words) == 0 {
+		return getGErrBlk(excNames.IllegalArgumentException, "runtimeExecString: Empty command")
+	}
+	return runtimeExec("runtimeExecString", words, params)
+}
+
+// runtimeExecArray: Start a command given as an array of words, the first of which is the program.
+func runtimeExecArray(params []interface{}) interface{} {
+	wordObjs, err := args.GetObjectArray(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("runtimeExecArray", err)
+	}
+	words, gErr := goStrings("runtimeExecArray", wordObjs)
+	if gErr != nil {
+		return gErr
+	}
+	return runtimeExec("runtimeExecArray", words, params)
+}
+
+// runtimeExec: Start the command with the environment in params[2] and in the working directory in params[3], if
+// these are present and not null. The environment is an array of "name=value" strings that replaces Jacobin's own;
+// as in the JDK, a string without an = is ignored.
+func runtimeExec(funcName string, command []string, params []interface{}) interface{} {
+	var env []string
+	if len(params) > 2 && !object.IsNull(params[2]) {
+		envObjs, err := args.GetObjectArray(params, 2)
+		if err != nil {
+			return getArgsGErrBlk(funcName, err)
+		}
+		envStrings, gErr := goStrings(funcName, envObjs)
+		if gErr != nil {
+			return gErr
+		}
+		env = []string{}
+		for _, envString := range envStrings {
+			if strings.Contains(envString, "=") {
+				env = append(env, envString)
+			}
+		}
+	}
+
+	dir := ""
+	if len(params) > 3 && !object.IsNull(params[3]) {
+		var gErr *GErrBlk
+		if dir, gErr = pathString(funcName, params, 3); gErr != nil {
+			return gErr
+		}
+	}
+	return startProcess(funcName, command, env, dir)
+}
+
+// goStrings: Get the Go strings of an array of Strings, none of which may be null.
+func goStrings(funcName string, strObjs []*object.Object) ([]string, *GErrBlk) {
+	strs := make([]string, len(strObjs))
+	for i, strObj := range strObjs {
+		if object.IsNull(strObj) {
+			return nil, getGErrBlk(excNames.NullPointerException, funcName+": null string in the array")
+		}
+		strs[i] = object.GoStringFromStringObject(strObj)
+	}
+	return strs, nil
+}
+
+// maxMemory: Get the maximum amount of memory that the max Jacobin will attempt to use: the heap limit set by
+// -Xmx or, in a container, by its memory limit. If there is no limit, Java returns Long.MAX_VALUE, as we do here
 func maxMemory([]interface{}) interface{} {
+	if limit := globals.GetGlobalRef().MaxHeapSize; limit > 0 {
+		return limit
+	}
 	return int64(math.MaxInt64)
 }
 
@@ -6,7 +6,10 @@
 
 package gfunction
 
-import "testing"
+import (
+	"jacobin/src/globals"
+	"testing"
+)
 
 func TestMaxMemory(t *testing.T) {
 	mem := maxMemory(nil)
@@ -28,3 +31,23 @@ func TestRuntimeCPUs(t *testing.T) {
 		t.Errorf("runtimeCPUs() = %d; expected > 1", cpus)
 	}
 }
+
+func TestRuntimeCPUsSetByActiveProcessorCount(t *testing.T) {
+	globals.InitGlobals("test")
+	globals.GetGlobalRef().ActiveCPUs = 3
+	defer globals.InitGlobals("test")
+
+	if cpus := runtimeAvailableProcessors(nil); cpus.(int64) != 3 {
+		t.Errorf("runtimeCPUs() = %d; expected 3, as set by -XX:ActiveProcessorCount", cpus)
+	}
+}
+
+func TestMaxMemoryIsHeapLimit(t *testing.T) {
+	globals.InitGlobals("test")
+	globals.GetGlobalRef().MaxHeapSize = 256 * 1024 * 1024
+	defer globals.InitGlobals("test")
+
+	if mem := maxMemory(nil); mem.(int64) != 256*1024*1024 {
+		t.Errorf("maxMemory() = %d bytes; expected the heap limit of 256m", mem)
+	}
+}
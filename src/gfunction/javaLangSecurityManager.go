@@ -6,7 +6,11 @@
 
 package gfunction
 
-// TODO: The entire SecurityManager class is deprecated and will be removed in the future.
+// The entire SecurityManager class is deprecated and will be removed in the future. Jacobin
+// runs without a security manager, as the JDK does by default since Java 18:
+// System.getSecurityManager() returns null and System.setSecurityManager() refuses one. A
+// SecurityManager that a program creates for itself permits everything, just as
+// AccessController (javaSecurityAccessController.go) does: its check methods just return.
 
 var classNameSecurityManager = "java/lang/SecurityManager"
 
@@ -7,14 +7,16 @@
 package gfunction
 
 import (
+	"container/list"
 	"fmt"
+	"jacobin/src/charset"
 	"jacobin/src/classloader"
 	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
 	"jacobin/src/object"
 	"jacobin/src/types"
 	"os"
 	"regexp"
-	"strconv"
 	"strings"
 	"unicode"
 )
@@ -69,11 +71,11 @@ func Load_Lang_String() {
 			GFunction:  trapDeprecated,
 		}
 
-	// TODO: String(byte[] bytes, int offset, int length, String charsetName) *********** CHARSET
+	// String(byte[] bytes, int offset, int length, String charsetName)
 	MethodSignatures["java/lang/String.<init>([BIILjava/lang/String;)V"] =
 		GMeth{
 			ParamSlots: 4,
-			GFunction:  trapFunction,
+			GFunction:  newStringFromBytesSubsetCharsetName,
 		}
 
 	// TODO: String(byte[] bytes, int offset, int length, Charset charset) ************** CHARSET
@@ -83,11 +85,11 @@ func Load_Lang_String() {
 			GFunction:  trapFunction,
 		}
 
-	// TODO: String(byte[] bytes, String charsetName) *********************************** CHARSET
+	// String(byte[] bytes, String charsetName)
 	MethodSignatures["java/lang/String.<init>([BLjava/lang/String;)V"] =
 		GMeth{
 			ParamSlots: 2,
-			GFunction:  trapFunction,
+			GFunction:  newStringFromBytesCharsetName,
 		}
 
 	// TODO: String(byte[] bytes, Charset charset) ************************************** CHARSET
@@ -299,11 +301,11 @@ func Load_Lang_String() {
 			GFunction:  sprintf,
 		}
 
-	// TODO: Return a formatted string using the specified locale, format string, and arguments.
+	// Return a formatted string using the specified locale, format string, and arguments.
 	MethodSignatures["java/lang/String.format(Ljava/util/Locale;Ljava/lang/String;[Ljava/lang/Object;)Ljava/lang/String;"] =
 		GMeth{
 			ParamSlots: 3,
-			GFunction:  trapFunction,
+			GFunction:  sprintfLocale,
 		}
 
 	// This method is equivalent to String.format(this, args).
@@ -334,11 +336,11 @@ func Load_Lang_String() {
 			GFunction:  trapFunction,
 		}
 
-	// Encodes this String into a sequence of bytes using the named charset, storing the result into a new byte array. ************************ CHARSET
+	// Encodes this String into a sequence of bytes using the named charset, storing the result into a new byte array.
 	MethodSignatures["java/lang/String.getBytes(Ljava/lang/String;)[B"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  trapFunction,
+			GFunction:  getBytesFromStringCharsetName,
 		}
 
 	// Not in API: getBytes([BIIBI)V
@@ -440,18 +442,20 @@ func Load_Lang_String() {
 			GFunction:  stringIsEmpty,
 		}
 
-	// TODO: Returns a new String composed of copies of the CharSequence elements joined together with a copy of the specified delimiter.
+	// Returns a new String composed of copies of the CharSequence elements joined together with a copy of the specified delimiter.
 	MethodSignatures["java/lang/String.join(Ljava/lang/CharSequence;[Ljava/lang/CharSequence;)Ljava/lang/String;"] =
 		GMeth{
-			ParamSlots: 2,
-			GFunction:  trapFunction,
+			ParamSlots:   2,
+			GFunction:    stringJoin,
+			NeedsContext: true,
 		}
 
-	// TODO: Returns a new String composed of copies of the CharSequence elements joined together with a copy of the specified delimiter.
-	MethodSignatures["java/lang/String.join(Ljava/lang/CharSequence;[Ljava/lang/Iterable;)Ljava/lang/String;"] =
+	// Returns a new String composed of copies of the CharSequence elements joined together with a copy of the specified delimiter.
+	MethodSignatures["java/lang/String.join(Ljava/lang/CharSequence;Ljava/lang/Iterable;)Ljava/lang/String;"] =
 		GMeth{
-			ParamSlots: 2,
-			GFunction:  trapFunction,
+			ParamSlots:   2,
+			GFunction:    stringJoinIterable,
+			NeedsContext: true,
 		}
 
 	// Returns the index within this string of the last occurrence of the specified character.
@@ -790,62 +794,108 @@ func stringClinit([]interface{}) interface{} {
 // Instantiate a new empty string - "java/lang/String.<init>()V"
 func newEmptyString(params []interface{}) interface{} {
 	// params[0] = target object for string (updated)
-	obj := params[0].(*object.Object)
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("newEmptyString", err)
+	}
 	bytes := make([]types.JavaByte, 0)
 	object.UpdateValueFieldFromJavaBytes(obj, bytes)
 	return nil
 }
 
-// Instantiate a new string object from a Go byte array.
+// Instantiate a new string object from a Go byte array, decoded in the default charset.
 // "java/lang/String.<init>([B)V"
 func newStringFromBytes(params []interface{}) interface{} {
+	return stringFromBytes("newStringFromBytes", params, charset.Default())
+}
+
+// Instantiate a new string object from a Go byte array, decoded in the named charset.
+// "java/lang/String.<init>([BLjava/lang/String;)V"
+func newStringFromBytesCharsetName(params []interface{}) interface{} {
+	charsetName, err := args.GetGoString(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("newStringFromBytesCharsetName", err)
+	}
+	cs, gErr := charsetNamed("newStringFromBytesCharsetName", charsetName)
+	if gErr != nil {
+		return gErr
+	}
+	return stringFromBytes("newStringFromBytesCharsetName", params, cs)
+}
+
+func stringFromBytes(funcName string, params []interface{}, cs *charset.Charset) interface{} {
 	// params[0] = reference string (to be updated with byte array)
 	// params[1] = byte array object
-	obj := params[0].(*object.Object)
-	switch params[1].(*object.Object).FieldTable["value"].Fvalue.(type) {
-	case []byte:
-		bytes := object.JavaByteArrayFromGoByteArray(
-			params[1].(*object.Object).FieldTable["value"].Fvalue.([]byte))
-		object.UpdateValueFieldFromJavaBytes(obj, bytes)
-	case []types.JavaByte:
-		bytes := params[1].(*object.Object).FieldTable["value"].Fvalue.([]types.JavaByte)
-		object.UpdateValueFieldFromJavaBytes(obj, bytes)
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk(funcName, err)
 	}
+	bytes, err := args.GetByteArray(params, 1)
+	if err != nil {
+		return getArgsGErrBlk(funcName, err)
+	}
+	str := cs.Decode(object.GoByteArrayFromJavaByteArray(bytes))
+	object.UpdateValueFieldFromJavaBytes(obj, object.JavaByteArrayFromGoString(str))
 	return nil
 }
 
-// Construct a string object from a subset of a JavaByte array.
+// Construct a string object from a subset of a JavaByte array, decoded in the default charset.
 // "java/lang/String.<init>([BII)V"
 func newStringFromBytesSubset(params []interface{}) interface{} {
+	return stringFromBytesSubset("newStringFromBytesSubset", params, charset.Default())
+}
+
+// Construct a string object from a subset of a JavaByte array, decoded in the named charset.
+// "java/lang/String.<init>([BIILjava/lang/String;)V"
+func newStringFromBytesSubsetCharsetName(params []interface{}) interface{} {
+	charsetName, err := args.GetGoString(params, 4)
+	if err != nil {
+		return getArgsGErrBlk("newStringFromBytesSubsetCharsetName", err)
+	}
+	cs, gErr := charsetNamed("newStringFromBytesSubsetCharsetName", charsetName)
+	if gErr != nil {
+		return gErr
+	}
+	return stringFromBytesSubset("newStringFromBytesSubsetCharsetName", params, cs)
+}
+
+func stringFromBytesSubset(funcName string, params []interface{}, cs *charset.Charset) interface{} {
 	// params[0] = reference string (to be updated with byte array)
 	// params[1] = byte array object
 	// params[2] = start offset
 	// params[3] = end offset
-	obj := params[0].(*object.Object)
-	var bytes []types.JavaByte
-	switch params[1].(*object.Object).FieldTable["value"].Fvalue.(type) {
-	case []byte:
-		bytes = object.JavaByteArrayFromGoByteArray(params[1].(*object.Object).FieldTable["value"].Fvalue.([]byte))
-	case []types.JavaByte:
-		bytes = params[1].(*object.Object).FieldTable["value"].Fvalue.([]types.JavaByte)
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk(funcName, err)
+	}
+	bytes, err := args.GetByteArray(params, 1)
+	if err != nil {
+		return getArgsGErrBlk(funcName, err)
 	}
 
 	// Get substring start and end offset
-	ssStart := params[2].(int64)
-	ssEnd := params[3].(int64)
+	ssStart, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk(funcName, err)
+	}
+	ssEnd, err := args.GetInt64(params, 3)
+	if err != nil {
+		return getArgsGErrBlk(funcName, err)
+	}
 
 	// Validate boundaries.
 	totalLength := int64(len(bytes))
 	if totalLength < 1 || ssStart < 0 || ssEnd < 1 || ssStart > (totalLength-1) || (ssStart+ssEnd) > totalLength {
-		errMsg1 := "newStringFromBytesSubset: Either nil input byte array, invalid substring offset, or invalid substring length"
+		errMsg1 := funcName + ": Either nil input byte array, invalid substring offset, or invalid substring length"
 		errMsg2 := fmt.Sprintf("\n\twhole='%s' wholelen=%d, offset=%d, sslen=%d\n\n",
 			object.GoStringFromJavaByteArray(bytes), totalLength, ssStart, ssEnd)
 		return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg1+errMsg2)
 	}
 
-	// Compute subarray and update params[0].
+	// Compute subarray, decode it, and update params[0].
 	bytes = bytes[ssStart : ssStart+ssEnd]
-	object.UpdateValueFieldFromJavaBytes(obj, bytes)
+	str := cs.Decode(object.GoByteArrayFromJavaByteArray(bytes))
+	object.UpdateValueFieldFromJavaBytes(obj, object.JavaByteArrayFromGoString(str))
 	return nil
 }
 
@@ -854,8 +904,14 @@ func newStringFromBytesSubset(params []interface{}) interface{} {
 func newStringFromChars(params []interface{}) interface{} {
 	// params[0] = reference string (to be updated with byte array)
 	// params[1] = byte array object
-	obj := params[0].(*object.Object)
-	ints := params[1].(*object.Object).FieldTable["value"].Fvalue.([]int64)
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("newStringFromChars", err)
+	}
+	ints, err := args.GetInt64Array(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("newStringFromChars", err)
+	}
 
 	var bytes []types.JavaByte
 	for _, ii := range ints {
@@ -872,20 +928,20 @@ func newStringFromCharsSubset(params []interface{}) interface{} {
 	// params[1] = start offset
 	// params[2] = end offset
 	// Return the string.
-	fld, ok := params[0].(*object.Object).FieldTable["value"]
-	if !ok {
-		errMsg := fmt.Sprintf("newStringFromCharsSubset: Missing value field in character array object")
-		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
-	}
-	iarray, ok := fld.Fvalue.([]int64)
-	if !ok {
-		errMsg := fmt.Sprintf("newStringFromCharsSubset: Invalid value field type (%s : %T) in character array object", fld.Ftype, fld.Fvalue)
-		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	iarray, err := args.GetInt64Array(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("newStringFromCharsSubset", err)
 	}
 
 	// Get substring start and end offset
-	ssStart := params[1].(int64)
-	ssEnd := params[2].(int64)
+	ssStart, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("newStringFromCharsSubset", err)
+	}
+	ssEnd, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("newStringFromCharsSubset", err)
+	}
 
 	// Validate boundaries.
 	totalLength := int64(len(iarray))
@@ -910,16 +966,16 @@ func newStringFromCharsSubset(params []interface{}) interface{} {
 func newStringFromString(params []interface{}) interface{} {
 	// params[0] = reference string (to be updated with byte array)
 	// params[1] = String, StringBuilder, or StringBuffer object
-	var javaBytes []types.JavaByte
-	switch params[1].(*object.Object).FieldTable["value"].Fvalue.(type) {
-	case []byte:
-		bytes := params[1].(*object.Object).FieldTable["value"].Fvalue.([]byte)
-		javaBytes = object.JavaByteArrayFromGoByteArray(bytes)
-	case []types.JavaByte:
-		javaBytes = params[1].(*object.Object).FieldTable["value"].Fvalue.([]types.JavaByte)
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("newStringFromString", err)
+	}
+	javaBytes, err := args.GetByteArray(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("newStringFromString", err)
 	}
 
-	object.UpdateValueFieldFromJavaBytes(params[0].(*object.Object), javaBytes)
+	object.UpdateValueFieldFromJavaBytes(obj, javaBytes)
 	return nil
 }
 
@@ -929,12 +985,21 @@ func newStringFromString(params []interface{}) interface{} {
 // "java/lang/String.charAt(I)C"
 func stringCharAt(params []interface{}) interface{} {
 	// Unpack the reference string and convert it to a rune array.
-	ptrObj := params[0].(*object.Object)
-	str := object.GoStringFromStringObject(ptrObj)
+	str, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringCharAt", err)
+	}
 	runeArray := []rune(str)
 
 	// Get index.
-	index := params[1].(int64)
+	index, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringCharAt", err)
+	}
+	if index < 0 || index >= int64(len(runeArray)) {
+		errMsg := fmt.Sprintf("stringCharAt: Index %d out of bounds for length %d", index, len(runeArray))
+		return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg)
+	}
 
 	// Return indexed character.
 	runeValue := runeArray[index]
@@ -943,10 +1008,14 @@ func stringCharAt(params []interface{}) interface{} {
 
 // "java/lang/String.compareTo(Ljava/lang/String;)I"
 func stringCompareToCaseSensitive(params []interface{}) interface{} {
-	obj := params[0].(*object.Object)
-	str1 := object.GoStringFromStringObject(obj)
-	obj = params[1].(*object.Object)
-	str2 := object.GoStringFromStringObject(obj)
+	str1, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringCompareToCaseSensitive", err)
+	}
+	str2, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringCompareToCaseSensitive", err)
+	}
 	if str2 == str1 {
 		return types.JavaBoolFalse
 	}
@@ -958,10 +1027,15 @@ func stringCompareToCaseSensitive(params []interface{}) interface{} {
 
 // "java/lang/String.compareToIgnoreCase(Ljava/lang/String;)I"
 func stringCompareToIgnoreCase(params []interface{}) interface{} {
-	obj := params[0].(*object.Object)
-	str1 := strings.ToLower(object.GoStringFromStringObject(obj))
-	obj = params[1].(*object.Object)
-	str2 := strings.ToLower(object.GoStringFromStringObject(obj))
+	str1, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringCompareToIgnoreCase", err)
+	}
+	str2, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringCompareToIgnoreCase", err)
+	}
+	str1, str2 = strings.ToLower(str1), strings.ToLower(str2)
 	if str2 == str1 {
 		return int64(0)
 	}
@@ -973,22 +1047,13 @@ func stringCompareToIgnoreCase(params []interface{}) interface{} {
 
 // "java/lang/String.concat(Ljava/lang/String;)Ljava/lang/String;"
 func stringConcat(params []interface{}) interface{} {
-	var str1, str2 string
-
-	fld := params[0].(*object.Object).FieldTable["value"]
-	switch fld.Fvalue.(type) {
-	case []byte:
-		str1 = string(fld.Fvalue.([]byte))
-	case []types.JavaByte:
-		str1 = object.GoStringFromJavaByteArray(fld.Fvalue.([]types.JavaByte))
+	str1, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringConcat", err)
 	}
-
-	fld = params[1].(*object.Object).FieldTable["value"]
-	switch fld.Fvalue.(type) {
-	case []byte:
-		str2 = string(fld.Fvalue.([]byte))
-	case []types.JavaByte:
-		str2 = object.GoStringFromJavaByteArray(fld.Fvalue.([]types.JavaByte))
+	str2, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringConcat", err)
 	}
 
 	str := str1 + str2
@@ -1001,7 +1066,10 @@ func stringConcat(params []interface{}) interface{} {
 // Here, we assume one of those two options.
 func stringContains(params []interface{}) interface{} {
 	// get the search string (the string we're searching for, i.e., "foo" in "seafood")
-	searchFor := params[1].(*object.Object)
+	searchFor, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringContains", err)
+	}
 	var searchString string
 	switch searchFor.FieldTable["value"].Fvalue.(type) {
 	case []types.JavaByte:
@@ -1012,7 +1080,10 @@ func stringContains(params []interface{}) interface{} {
 	case string:
 		searchString = searchFor.FieldTable["value"].Fvalue.(string)
 	}
-	searchIn := params[0].(*object.Object)
+	searchIn, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringContains", err)
+	}
 
 	// now get the target string (the string being searched)
 	var targetString string
@@ -1034,7 +1105,10 @@ func stringContains(params []interface{}) interface{} {
 
 func javaLangStringContentEquals(params []interface{}) interface{} {
 	var str1, str2 string
-	obj := params[0].(*object.Object)
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("javaLangStringContentEquals", err)
+	}
 	switch obj.FieldTable["value"].Fvalue.(type) {
 	case []byte:
 		str1 = string(obj.FieldTable["value"].Fvalue.([]byte))
@@ -1042,7 +1116,10 @@ func javaLangStringContentEquals(params []interface{}) interface{} {
 		str1 = object.GoStringFromJavaByteArray(obj.FieldTable["value"].Fvalue.([]types.JavaByte))
 	}
 
-	obj = params[1].(*object.Object)
+	obj, err = args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("javaLangStringContentEquals", err)
+	}
 	switch obj.FieldTable["value"].Fvalue.(type) {
 	case []byte:
 		str2 = string(obj.FieldTable["value"].Fvalue.([]byte))
@@ -1062,9 +1139,17 @@ func javaLangStringContentEquals(params []interface{}) interface{} {
 func stringEquals(params []interface{}) interface{} {
 	// params[0]: reference string object
 	// params[1]: compare-to string Object
-	obj := params[0].(*object.Object)
-	str1 := object.GoStringFromStringObject(obj)
-	obj = params[1].(*object.Object)
+	str1, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringEquals", err)
+	}
+	obj, err := args.GetObjectOrNull(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringEquals", err)
+	}
+	if obj == object.Null || !object.IsStringObject(obj) { // a String never equals null or an object of another class
+		return types.JavaBoolFalse
+	}
 	str2 := object.GoStringFromStringObject(obj)
 
 	// Are they equal in value?
@@ -1077,24 +1162,20 @@ func stringEquals(params []interface{}) interface{} {
 // Are 2 strings equal, ignoring case?
 // "java/lang/String.equalsIgnoreCase(Ljava/lang/String;)Z"
 func stringEqualsIgnoreCase(params []interface{}) interface{} {
-	var str1, str2 string
 	// params[0]: reference string object
 	// params[1]: compare-to string Object
-	obj := params[0].(*object.Object)
-	switch obj.FieldTable["value"].Fvalue.(type) {
-	case []byte:
-		str1 = string(obj.FieldTable["value"].Fvalue.([]byte))
-	case []types.JavaByte:
-		str1 = object.GoStringFromJavaByteArray(obj.FieldTable["value"].Fvalue.([]types.JavaByte))
+	str1, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringEqualsIgnoreCase", err)
 	}
-
-	obj = params[1].(*object.Object)
-	switch obj.FieldTable["value"].Fvalue.(type) {
-	case []byte:
-		str2 = string(obj.FieldTable["value"].Fvalue.([]byte))
-	case []types.JavaByte:
-		str2 = object.GoStringFromJavaByteArray(obj.FieldTable["value"].Fvalue.([]types.JavaByte))
+	obj, err := args.GetObjectOrNull(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringEqualsIgnoreCase", err)
 	}
+	if obj == object.Null {
+		return types.JavaBoolFalse
+	}
+	str2 := object.GoStringFromStringObject(obj)
 
 	// Are they equal in value?
 	upstr1 := strings.ToUpper(str1)
@@ -1113,10 +1194,45 @@ func sprintf(params []interface{}) interface{} {
 	return StringFormatter(params)
 }
 
+// "java/lang/String.format(Ljava/util/Locale;Ljava/lang/String;[Ljava/lang/Object;)Ljava/lang/String;"
+// A null locale formats without localization, as the root locale does.
+func sprintfLocale(params []interface{}) interface{} {
+	// params[0]: locale
+	// params[1]: format string
+	// params[2]: argument slice (array of object pointers)
+	symbols := rootFormatSymbols
+	if locale, ok := params[0].(*object.Object); ok && !object.IsNull(locale) {
+		symbols = formatSymbolsForLocale(localeName(locale))
+	}
+	return stringFormatterWithSymbols(params[1:], symbols)
+}
+
 // java/lang/String.getBytes()[B
 func getBytesFromString(params []interface{}) interface{} {
 	// params[0] = reference string with byte array to be returned
-	bytes := object.JavaByteArrayFromStringObject(params[0].(*object.Object))
+	str, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("getBytesFromString", err)
+	}
+	bytes := object.JavaByteArrayFromGoByteArray(charset.Default().Encode(str))
+	return Populator("[B", types.ByteArray, bytes)
+}
+
+// java/lang/String.getBytes(Ljava/lang/String;)[B
+func getBytesFromStringCharsetName(params []interface{}) interface{} {
+	str, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("getBytesFromStringCharsetName", err)
+	}
+	charsetName, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("getBytesFromStringCharsetName", err)
+	}
+	cs, gErr := charsetNamed("getBytesFromStringCharsetName", charsetName)
+	if gErr != nil {
+		return gErr
+	}
+	bytes := object.JavaByteArrayFromGoByteArray(cs.Encode(str))
 	return Populator("[B", types.ByteArray, bytes)
 }
 
@@ -1133,18 +1249,31 @@ func stringGetBytesBIIBI(params []any) any {
 // Returns an index if the character is found or -1 if the character is not found
 func lastIndexOfCharacter(params []any) any {
 	// Get base string.
-	baseStringObject := params[0].(*object.Object)
-	baseString := object.GoStringFromStringObject(baseStringObject)
+	baseString, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("lastIndexOfCharacter", err)
+	}
 
 	// Get search string argument.
-	searchByte := byte(params[1].(int64))
+	searchChar, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("lastIndexOfCharacter", err)
+	}
+	searchByte := byte(searchChar)
 
 	// Get index starting point.
-	var beginIndex int64
+	beginIndex := int64(len(baseString))
 	if len(params) > 2 {
-		beginIndex = params[2].(int64)
-	} else {
-		beginIndex = int64(len(baseString))
+		beginIndex, err = args.GetInt64(params, 2)
+		if err != nil {
+			return getArgsGErrBlk("lastIndexOfCharacter", err)
+		}
+		if beginIndex > int64(len(baseString)) {
+			beginIndex = int64(len(baseString))
+		}
+		if beginIndex < 0 {
+			return int64(-1)
+		}
 	}
 
 	// Find search argument in base string if it is there.
@@ -1160,18 +1289,29 @@ func lastIndexOfCharacter(params []any) any {
 // index to the first character if the string is found, -1 if the string is not found
 func lastIndexOfString(params []any) any {
 	// Get base string.
-	baseStringObject := params[0].(*object.Object)
-	baseString := object.GoStringFromStringObject(baseStringObject)
+	baseString, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("lastIndexOfString", err)
+	}
 
 	// Get search string argument.
-	searchStringObject := params[1].(*object.Object)
-	searchString := object.GoStringFromStringObject(searchStringObject)
+	searchString, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("lastIndexOfString", err)
+	}
 
-	// Get indes starting point.
-	var beginIndex int64
+	// Get index starting point.
+	beginIndex := int64(len(baseString))
 	if len(params) > 2 {
-		beginIndex = params[2].(int64)
-	} else {
+		beginIndex, err = args.GetInt64(params, 2)
+		if err != nil {
+			return getArgsGErrBlk("lastIndexOfString", err)
+		}
+		if beginIndex < 0 {
+			return int64(-1)
+		}
+	}
+	if beginIndex > int64(len(baseString)) {
 		beginIndex = int64(len(baseString))
 	}
 
@@ -1188,10 +1328,55 @@ func stringIsLatin1(params []interface{}) interface{} {
 	return types.JavaBoolTrue // true
 }
 
+// "java/lang/String.join(Ljava/lang/CharSequence;[Ljava/lang/CharSequence;)Ljava/lang/String;"
+// joins the elements with a StringJoiner; null elements are joined as "null"
+func stringJoin(params []interface{}) interface{} {
+	arr, ok := params[2].(*object.Object)
+	if !ok || object.IsNull(arr) {
+		return getGErrBlk(excNames.NullPointerException, "stringJoin: the elements are null")
+	}
+	elems, _ := arr.FieldTable["value"].Fvalue.([]*object.Object)
+	return joinCharSequences(params[0].(*list.List), "stringJoin", params[1], elems)
+}
+
+// "java/lang/String.join(Ljava/lang/CharSequence;Ljava/lang/Iterable;)Ljava/lang/String;"
+func stringJoinIterable(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	iterable, ok := params[2].(*object.Object)
+	if !ok || object.IsNull(iterable) {
+		return getGErrBlk(excNames.NullPointerException, "stringJoinIterable: the elements are null")
+	}
+	elems, gErr := iterableElements(fs, iterable)
+	if gErr != nil {
+		return gErr
+	}
+	return joinCharSequences(fs, "stringJoinIterable", params[1], elems)
+}
+
+// returns a String of the CharSequences joined by the delimiter
+func joinCharSequences(fs *list.List, funcName string, delimiter any, elems []*object.Object) interface{} {
+	delim, gErr := charSequenceParams(fs, funcName, []interface{}{delimiter})
+	if gErr != nil {
+		return gErr
+	}
+	sj := stringJoiner{delimiter: delim[0]}
+	for _, elem := range elems {
+		str, gErr := charSequenceString(fs, elem)
+		if gErr != nil {
+			return gErr
+		}
+		sj.elems = append(sj.elems, str)
+	}
+	return object.StringObjectFromGoString(sj.String())
+}
+
 // "java/lang/String.length()I"
 func stringLength(params []interface{}) interface{} {
 	// params[0] = string object whose string length is to be measured
-	obj := params[0].(*object.Object)
+	obj, err := args.GetStringObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringLength", err)
+	}
 	bytes := object.JavaByteArrayFromStringObject(obj)
 	return int64(len(bytes))
 }
@@ -1203,11 +1388,15 @@ func stringMatches(params []any) any {
 		errMsg := fmt.Sprintf("stringMatches: Expected a string and a regular expression")
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
-	baseStringObject := params[0].(*object.Object)
-	baseString := object.GoStringFromStringObject(baseStringObject)
+	baseString, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringMatches", err)
+	}
 
-	regexStringObject := params[1].(*object.Object)
-	regexString := object.GoStringFromStringObject(regexStringObject)
+	regexString, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringMatches", err)
+	}
 
 	regex, err := regexp.Compile(regexString)
 	if err != nil {
@@ -1228,28 +1417,46 @@ func stringRegionMatches(params []any) any {
 	// param[2] pointer to second string
 	// param[3] offset in second string
 	// param[4] length of region to compare
-	baseStringObject := params[0].(*object.Object)
+	baseStringObject, err := args.GetStringObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringRegionMatches", err)
+	}
 	baseByteArray := object.JavaByteArrayFromStringObject(baseStringObject)
 
 	// If this call includes boolean ignoreCase, then the parameters are shifted in the params array.
 	ignoreCase := false
 	pix := 1 // Assume no boolean ignoreCase parameter is present.
 	if len(params) > 5 {
-		pix = 2                                                // The boolean ignoreCase parameter is present.
-		ignoreCase = (params[1].(int64) == types.JavaBoolTrue) // Get the flag value.
+		pix = 2 // The boolean ignoreCase parameter is present.
+		ignoreCase, err = args.GetBoolean(params, 1)
+		if err != nil {
+			return getArgsGErrBlk("stringRegionMatches", err)
+		}
 	}
 
-	baseOffset := params[pix].(int64)
+	baseOffset, err := args.GetInt64(params, pix)
+	if err != nil {
+		return getArgsGErrBlk("stringRegionMatches", err)
+	}
 
-	compareStringObject := params[pix+1].(*object.Object)
+	compareStringObject, err := args.GetStringObject(params, pix+1)
+	if err != nil {
+		return getArgsGErrBlk("stringRegionMatches", err)
+	}
 	compareByteArray := object.JavaByteArrayFromStringObject(compareStringObject)
-	compareOffset := params[pix+2].(int64)
+	compareOffset, err := args.GetInt64(params, pix+2)
+	if err != nil {
+		return getArgsGErrBlk("stringRegionMatches", err)
+	}
 
 	if baseOffset < 0 || compareOffset < 0 { // in the JDK, this is the indicated response, rather than an exception(!)
 		return types.JavaBoolFalse
 	}
 
-	regionLength := params[pix+3].(int64)
+	regionLength, err := args.GetInt64(params, pix+3)
+	if err != nil {
+		return getArgsGErrBlk("stringRegionMatches", err)
+	}
 	if baseOffset+regionLength > int64(len(baseByteArray)) || // again, erroneous values simply return false
 		compareOffset+regionLength > int64(len(compareByteArray)) {
 		return types.JavaBoolFalse
@@ -1273,9 +1480,19 @@ func stringRegionMatches(params []any) any {
 func stringRepeat(params []interface{}) interface{} {
 	// params[0] = base string
 	// params[1] = int64 repetition factor
-	oldStr := object.GoStringFromStringObject(params[0].(*object.Object))
+	oldStr, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringRepeat", err)
+	}
+	count, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringRepeat", err)
+	}
+	if count < 0 {
+		errMsg := fmt.Sprintf("stringRepeat: count is negative: %d", count)
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
 	var newStr string
-	count := params[1].(int64)
 	for ii := int64(0); ii < count; ii++ {
 		newStr = newStr + oldStr
 	}
@@ -1291,9 +1508,20 @@ func stringReplaceCC(params []interface{}) interface{} {
 	// params[0] = base string
 	// params[1] = character to be replaced
 	// params[2] = replacement character
-	str := object.GoStringFromStringObject(params[0].(*object.Object))
-	oldChar := byte((params[1].(int64)) & 0xFF)
-	newChar := byte((params[2].(int64)) & 0xFF)
+	str, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringReplaceCC", err)
+	}
+	oldValue, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringReplaceCC", err)
+	}
+	newValue, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("stringReplaceCC", err)
+	}
+	oldChar := byte(oldValue & 0xFF)
+	newChar := byte(newValue & 0xFF)
 	newStr := strings.ReplaceAll(str, string(oldChar), string(newChar))
 
 	// Return final string in an object.
@@ -1305,10 +1533,16 @@ func stringReplaceCC(params []interface{}) interface{} {
 func substringToTheEnd(params []interface{}) interface{} {
 	// params[0] = base string
 	// params[1] = start offset
-	str := object.GoStringFromStringObject(params[0].(*object.Object))
+	str, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("substringToTheEnd", err)
+	}
 
 	// Get substring start offset and compute end offset
-	ssStart := params[1].(int64)
+	ssStart, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("substringToTheEnd", err)
+	}
 	ssEnd := int64(len(str))
 
 	// Validate boundaries.
@@ -1332,11 +1566,20 @@ func substringStartEnd(params []interface{}) interface{} {
 	// params[0] = base string
 	// params[1] = start offset
 	// params[2] = end offset
-	str := object.GoStringFromStringObject(params[0].(*object.Object))
+	str, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("substringStartEnd", err)
+	}
 
 	// Get substring start and end offset
-	ssStart := params[1].(int64)
-	ssEnd := params[2].(int64)
+	ssStart, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("substringStartEnd", err)
+	}
+	ssEnd, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("substringStartEnd", err)
+	}
 
 	// Validate boundaries.
 	totalLength := int64(len(str))
@@ -1357,8 +1600,10 @@ func substringStartEnd(params []interface{}) interface{} {
 // "java/lang/String.toCharArray()[C"
 func toCharArray(params []interface{}) interface{} {
 	// params[0]: input string
-	obj := params[0].(*object.Object)
-	bytes := obj.FieldTable["value"].Fvalue.([]types.JavaByte)
+	bytes, err := args.GetByteArray(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("toCharArray", err)
+	}
 	var iArray []int64
 	for _, bb := range bytes {
 		iArray = append(iArray, int64(bb))
@@ -1369,7 +1614,11 @@ func toCharArray(params []interface{}) interface{} {
 // "java/lang/String.toLowerCase()Ljava/lang/String;"
 func toLowerCase(params []interface{}) interface{} {
 	// params[0]: input string
-	str := strings.ToLower(object.GoStringFromStringObject(params[0].(*object.Object)))
+	str, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("toLowerCase", err)
+	}
+	str = strings.ToLower(str)
 	obj := object.StringObjectFromGoString(str)
 	return obj
 }
@@ -1377,7 +1626,11 @@ func toLowerCase(params []interface{}) interface{} {
 // "java/lang/String.toUpperCase()Ljava/lang/String;"
 func toUpperCase(params []interface{}) interface{} {
 	// params[0]: input string
-	str := strings.ToUpper(object.GoStringFromStringObject(params[0].(*object.Object)))
+	str, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("toUpperCase", err)
+	}
+	str = strings.ToUpper(str)
 	obj := object.StringObjectFromGoString(str)
 	return obj
 }
@@ -1385,7 +1638,11 @@ func toUpperCase(params []interface{}) interface{} {
 // "java/lang/String.trim()Ljava/lang/String;"
 func trimString(params []interface{}) interface{} {
 	// params[0]: input string
-	str := strings.Trim(object.GoStringFromStringObject(params[0].(*object.Object)), " ")
+	str, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("trimString", err)
+	}
+	str = strings.Trim(str, " ")
 	obj := object.StringObjectFromGoString(str)
 	return obj
 }
@@ -1393,7 +1650,10 @@ func trimString(params []interface{}) interface{} {
 // "java/lang/String.valueOf(Z)Ljava/lang/String;"
 func valueOfBoolean(params []interface{}) interface{} {
 	// params[0]: input boolean
-	value := params[0].(int64)
+	value, err := args.GetInt64(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("valueOfBoolean", err)
+	}
 	var str string
 	if value != 0 {
 		str = "true"
@@ -1407,7 +1667,10 @@ func valueOfBoolean(params []interface{}) interface{} {
 // "java/lang/String.valueOf(C)Ljava/lang/String;"
 func valueOfChar(params []interface{}) interface{} {
 	// params[0]: input char
-	value := params[0].(int64)
+	value, err := args.GetInt64(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("valueOfChar", err)
+	}
 	str := fmt.Sprintf("%c", value)
 	obj := object.StringObjectFromGoString(str)
 	return obj
@@ -1416,8 +1679,10 @@ func valueOfChar(params []interface{}) interface{} {
 // "java/lang/String.valueOf([C)Ljava/lang/String;"
 func valueOfCharArray(params []interface{}) interface{} {
 	// params[0]: input char array
-	propObj := params[0].(*object.Object)
-	intArray := propObj.FieldTable["value"].Fvalue.([]int64)
+	intArray, err := args.GetInt64Array(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("valueOfCharArray", err)
+	}
 	var str string
 	for _, ch := range intArray {
 		str += fmt.Sprintf("%c", ch)
@@ -1431,15 +1696,23 @@ func valueOfCharSubarray(params []interface{}) interface{} {
 	// params[0]: input char array
 	// params[1]: input offset
 	// params[2]: input count
-	propObj := params[0].(*object.Object)
-	intArray := propObj.FieldTable["value"].Fvalue.([]int64)
+	intArray, err := args.GetInt64Array(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("valueOfCharSubarray", err)
+	}
 	var wholeString string
 	for _, ch := range intArray {
 		wholeString += fmt.Sprintf("%c", ch)
 	}
 	// Get substring offset and count
-	ssOffset := params[1].(int64)
-	ssCount := params[2].(int64)
+	ssOffset, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("valueOfCharSubarray", err)
+	}
+	ssCount, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("valueOfCharSubarray", err)
+	}
 
 	// Validate boundaries.
 	wholeLength := int64(len(wholeString))
@@ -1458,31 +1731,32 @@ func valueOfCharSubarray(params []interface{}) interface{} {
 // "java/lang/String.valueOf(D)Ljava/lang/String;"
 func valueOfDouble(params []interface{}) interface{} {
 	// params[0]: input double
-	value := params[0].(float64)
-	str := strconv.FormatFloat(value, 'f', -1, 64)
-	if !strings.Contains(str, ".") {
-		str += ".0"
+	value, err := args.GetFloat64(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("valueOfDouble", err)
 	}
-	obj := object.StringObjectFromGoString(str)
+	obj := object.StringObjectFromGoString(object.JavaDoubleString(value))
 	return obj
 }
 
 // "java/lang/String.valueOf(F)Ljava/lang/String;"
 func valueOfFloat(params []interface{}) interface{} {
 	// params[0]: input float
-	value := params[0].(float64)
-	str := strconv.FormatFloat(value, 'f', -1, 64)
-	if !strings.Contains(str, ".") {
-		str += ".0"
+	value, err := args.GetFloat64(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("valueOfFloat", err)
 	}
-	obj := object.StringObjectFromGoString(str)
+	obj := object.StringObjectFromGoString(object.JavaFloatString(value))
 	return obj
 }
 
 // "java/lang/String.valueOf(I)Ljava/lang/String;"
 func valueOfInt(params []interface{}) interface{} {
 	// params[0]: input int
-	value := params[0].(int64)
+	value, err := args.GetInt64(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("valueOfInt", err)
+	}
 	str := fmt.Sprintf("%d", value)
 	obj := object.StringObjectFromGoString(str)
 	return obj
@@ -1491,7 +1765,10 @@ func valueOfInt(params []interface{}) interface{} {
 // "java/lang/String.valueOf(J)Ljava/lang/String;"
 func valueOfLong(params []interface{}) interface{} {
 	// params[0]: input long
-	value := params[0].(int64)
+	value, err := args.GetInt64(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("valueOfLong", err)
+	}
 	str := fmt.Sprintf("%d", value)
 	obj := object.StringObjectFromGoString(str)
 	return obj
@@ -1500,18 +1777,13 @@ func valueOfLong(params []interface{}) interface{} {
 // "java/lang/String.valueOf(Ljava/lang/Object;)Ljava/lang/String;"
 func valueOfObject(params []interface{}) interface{} {
 	// params[0]: input Object or primitive
-	var str string
-
-	switch params[0].(type) {
-	case *object.Object:
-		inObj := params[0].(*object.Object)
-		str = object.ObjectFieldToString(inObj, "value")
-		if str == types.NullString {
-			str = object.ObjectFieldToString(inObj, "name")
-		}
-	default:
-		errMsg := fmt.Sprintf("valueOfObject: Unsupported parameter type: %T", params[0])
-		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	inObj, err := args.GetObjectOrNull(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("valueOfObject", err)
+	}
+	str := object.ObjectFieldToString(inObj, "value")
+	if str == types.NullString && inObj != object.Null {
+		str = object.ObjectFieldToString(inObj, "name")
 	}
 
 	outObj := object.StringObjectFromGoString(str)
@@ -1547,15 +1819,27 @@ public native String intern();
 func stringIntern(params []interface{}) interface{} {
 	// params[0]: String object
 	// TODO: Need to add this to the String pool?
-	obj := params[0].(*object.Object)
+	obj, err := args.GetStringObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringIntern", err)
+	}
 	return obj
 }
 
 // "java/lang/String.checkBoundsBeginEnd(III)V"
 func stringCheckBoundsBeginEnd(params []interface{}) interface{} {
-	begin := params[0].(int64)
-	end := params[1].(int64)
-	length := params[2].(int64)
+	begin, err := args.GetInt64(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringCheckBoundsBeginEnd", err)
+	}
+	end, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringCheckBoundsBeginEnd", err)
+	}
+	length, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("stringCheckBoundsBeginEnd", err)
+	}
 
 	if begin < 0 || begin > end || end > length {
 		errMsg := fmt.Sprintf("stringCheckBoundsBeginEnd: begin: %d, end: %d, length: %d", begin, end, length)
@@ -1567,9 +1851,18 @@ func stringCheckBoundsBeginEnd(params []interface{}) interface{} {
 
 // "java/lang/String.checkBoundsOffCount(III)I"
 func stringCheckBoundsOffCount(params []interface{}) interface{} {
-	offset := params[0].(int64)
-	count := params[1].(int64)
-	length := params[2].(int64)
+	offset, err := args.GetInt64(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringCheckBoundsOffCount", err)
+	}
+	count, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringCheckBoundsOffCount", err)
+	}
+	length, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("stringCheckBoundsOffCount", err)
+	}
 
 	if offset < 0 || count < 0 || offset > count || offset > (length-count) {
 		errMsg := fmt.Sprintf("stringCheckBoundsOffCount: offset: %d, count: %d, length: %d", offset, count, length)
@@ -1581,8 +1874,10 @@ func stringCheckBoundsOffCount(params []interface{}) interface{} {
 
 // "java/lang/String.hashCode()I"
 func stringHashCode(params []interface{}) interface{} {
-	obj := params[0].(*object.Object)
-	str := object.GoStringFromStringObject(obj)
+	str, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringHashCode", err)
+	}
 	hash := int32(0)
 	for _, wint32 := range str {
 		hash = 31*hash + int32(wint32)
@@ -1593,13 +1888,20 @@ func stringHashCode(params []interface{}) interface{} {
 // "java/lang/String.startsWith(Ljava/lang/String;)Z"
 // "java/lang/String.startsWith(Ljava/lang/String;I)Z"
 func stringStartsWith(params []interface{}) interface{} {
-	baseObj := params[0].(*object.Object)
-	baseStr := object.GoStringFromStringObject(baseObj)
-	argObj := params[1].(*object.Object)
-	prefix := object.GoStringFromStringObject(argObj)
+	baseStr, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringStartsWith", err)
+	}
+	prefix, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringStartsWith", err)
+	}
 	if len(params) == 3 {
-		offset := int(params[2].(int64))
-		if offset < 0 || offset > len(baseStr) {
+		offset, err := args.GetInt64(params, 2)
+		if err != nil {
+			return getArgsGErrBlk("stringStartsWith", err)
+		}
+		if offset < 0 || offset > int64(len(baseStr)) {
 			errMsg := fmt.Sprintf("stringStartsWith: base: %s, prefix: %s, offset: %d", baseStr, prefix, offset)
 			return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg)
 		}
@@ -1616,10 +1918,14 @@ func stringStartsWith(params []interface{}) interface{} {
 
 // "java/lang/String.endsWith(Ljava/lang/String;)Z"
 func stringEndsWith(params []interface{}) interface{} {
-	baseObj := params[0].(*object.Object)
-	baseStr := object.GoStringFromStringObject(baseObj)
-	argObj := params[1].(*object.Object)
-	prefix := object.GoStringFromStringObject(argObj)
+	baseStr, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringEndsWith", err)
+	}
+	prefix, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringEndsWith", err)
+	}
 	if strings.HasSuffix(baseStr, prefix) {
 		return types.JavaBoolTrue
 	}
@@ -1646,46 +1952,35 @@ func stringGetChars(params []interface{}) interface{} {
 	// params[3] = object holding the char array
 	// params[4] = dstBegin
 	// Return nil
-	srcFld, ok := params[0].(*object.Object).FieldTable["value"]
-	if !ok {
-		errMsg := fmt.Sprintf("stringGetChars: Missing value field in base object")
-		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
-	}
-
-	var srcBytes []types.JavaByte
-	switch srcFld.Fvalue.(type) {
-	case []byte:
-		srcBytes = object.JavaByteArrayFromGoByteArray(srcFld.Fvalue.([]byte))
-	case []types.JavaByte:
-		srcBytes = srcFld.Fvalue.([]types.JavaByte)
-	default:
-		errMsg := fmt.Sprintf("stringGetChars: Invalid value field type (%s : %T) in base object", srcFld.Ftype, srcFld.Fvalue)
-		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	srcBytes, err := args.GetByteArray(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringGetChars", err)
 	}
 
 	// Get source substring start offset and end offset.
-	srcBegin := params[1].(int64)
-	srcEnd := params[2].(int64)
+	srcBegin, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringGetChars", err)
+	}
+	srcEnd, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("stringGetChars", err)
+	}
 
 	// Compute total length of base byte array.
 	srcLength := int64(len(srcBytes))
 
 	// Get destination char array.
-	dstObj := params[3].(*object.Object)
-	dstFld, ok := dstObj.FieldTable["value"]
-	if !ok {
-		errMsg := fmt.Sprintf("stringGetChars: Missing value field in char array object")
-		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
-	}
-	dstChars, ok := dstFld.Fvalue.([]int64)
-	if !ok {
-		errMsg := fmt.Sprintf("stringGetChars: Invalid value field type (%s : %T) in char array object",
-			dstFld.Ftype, dstFld.Fvalue)
-		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	dstChars, err := args.GetInt64Array(params, 3)
+	if err != nil {
+		return getArgsGErrBlk("stringGetChars", err)
 	}
 
 	// Get char array start offset.
-	dstBegin := params[4].(int64)
+	dstBegin, err := args.GetInt64(params, 4)
+	if err != nil {
+		return getArgsGErrBlk("stringGetChars", err)
+	}
 
 	// Compute chara array length.
 	dstLength := int64(len(dstChars))
@@ -1703,8 +1998,6 @@ func stringGetChars(params []interface{}) interface{} {
 		dstChars[ix] = int64(xbyte)
 		ix += 1
 	}
-	dstFld.Fvalue = dstChars
-	dstObj.FieldTable["value"] = dstFld
 
 	return nil
 
@@ -1716,27 +2009,18 @@ starting the search at beginIndex if specified else 0,
 and stopping before endIndex if specified else the length of the base string.
 */
 func stringIndexOfCh(params []interface{}) interface{} {
-	// Get field of base object.
-	srcFld, ok := params[0].(*object.Object).FieldTable["value"]
-	if !ok {
-		errMsg := fmt.Sprintf("stringIndexOfCh: Missing value field in base object")
-		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
-	}
-
 	// Get base object byte array.
-	var srcBytes []types.JavaByte
-	switch srcFld.Fvalue.(type) {
-	case []byte:
-		srcBytes = object.JavaByteArrayFromGoByteArray(srcFld.Fvalue.([]byte))
-	case []types.JavaByte:
-		srcBytes = srcFld.Fvalue.([]types.JavaByte)
-	default:
-		errMsg := fmt.Sprintf("stringIndexOfCh: Invalid value field type (%s : %T) in base object", srcFld.Ftype, srcFld.Fvalue)
-		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	srcBytes, err := args.GetByteArray(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringIndexOfCh", err)
 	}
 
 	// Get search argument and set up switch.
-	arg := types.JavaByte(params[1].(int64))
+	ch, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringIndexOfCh", err)
+	}
+	arg := types.JavaByte(ch)
 	var beginIndex int64
 	var endIndex int64
 	lenSrcBytes := int64(len(srcBytes))
@@ -1747,7 +2031,10 @@ func stringIndexOfCh(params []interface{}) interface{} {
 		beginIndex = 0
 		endIndex = lenSrcBytes
 	case 2: // int indexOf(int ch, int fromIndex)
-		beginIndex = params[2].(int64)
+		beginIndex, err = args.GetInt64(params, 2)
+		if err != nil {
+			return getArgsGErrBlk("stringIndexOfCh", err)
+		}
 		if beginIndex < 0 {
 			beginIndex = 0
 		}
@@ -1756,12 +2043,18 @@ func stringIndexOfCh(params []interface{}) interface{} {
 		}
 		endIndex = lenSrcBytes
 	case 3: // int indexOf(int ch, int beginIndex, int endIndex)
-		beginIndex = params[2].(int64)
+		beginIndex, err = args.GetInt64(params, 2)
+		if err != nil {
+			return getArgsGErrBlk("stringIndexOfCh", err)
+		}
 		if beginIndex < 0 || beginIndex >= lenSrcBytes {
 			errMsg := fmt.Sprintf("stringIndexOfCh: Base string len: %d, begin index: %d", lenSrcBytes, beginIndex)
 			return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg)
 		}
-		endIndex = params[3].(int64)
+		endIndex, err = args.GetInt64(params, 3)
+		if err != nil {
+			return getArgsGErrBlk("stringIndexOfCh", err)
+		}
 		if endIndex > lenSrcBytes || beginIndex > endIndex {
 			errMsg := fmt.Sprintf("stringIndexOfCh: Base string len: %d, end index: %d", lenSrcBytes, endIndex)
 			return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg)
@@ -1779,10 +2072,16 @@ func stringIndexOfCh(params []interface{}) interface{} {
 
 func stringIndexOfString(params []interface{}) interface{} {
 	// Get field of base object.
-	baseString := object.GoStringFromStringObject(params[0].(*object.Object))
+	baseString, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringIndexOfString", err)
+	}
 
 	// Get base object byte array.
-	argString := object.GoStringFromStringObject(params[1].(*object.Object))
+	argString, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringIndexOfString", err)
+	}
 
 	// Set up for switch.
 	lenOrigBaseString := int64(len(baseString))
@@ -1795,7 +2094,10 @@ func stringIndexOfString(params []interface{}) interface{} {
 		beginIndex = 0
 		endIndex = lenOrigBaseString
 	case 2: // int indexOf(String str, int fromIndex)
-		beginIndex = params[2].(int64)
+		beginIndex, err = args.GetInt64(params, 2)
+		if err != nil {
+			return getArgsGErrBlk("stringIndexOfString", err)
+		}
 		if beginIndex < 0 {
 			beginIndex = 0
 		}
@@ -1804,12 +2106,18 @@ func stringIndexOfString(params []interface{}) interface{} {
 		}
 		endIndex = lenOrigBaseString
 	case 3: // int indexOf(String str, int beginIndex, int endIndex)
-		beginIndex = params[2].(int64)
+		beginIndex, err = args.GetInt64(params, 2)
+		if err != nil {
+			return getArgsGErrBlk("stringIndexOfString", err)
+		}
 		if beginIndex < 0 || beginIndex >= lenOrigBaseString {
 			errMsg := fmt.Sprintf("stringIndexOfString: Base string len: %d, begin index: %d", lenOrigBaseString, beginIndex)
 			return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg)
 		}
-		endIndex = params[3].(int64)
+		endIndex, err = args.GetInt64(params, 3)
+		if err != nil {
+			return getArgsGErrBlk("stringIndexOfString", err)
+		}
 		if endIndex > lenOrigBaseString || beginIndex > endIndex {
 			errMsg := fmt.Sprintf("stringIndexOfString: Base string len: %d, end index: %d", lenOrigBaseString, endIndex)
 			return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg)
@@ -1825,7 +2133,10 @@ func stringIndexOfString(params []interface{}) interface{} {
 }
 
 func stringIsBlank(params []interface{}) interface{} {
-	baseString := object.GoStringFromStringObject(params[0].(*object.Object))
+	baseString, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringIsBlank", err)
+	}
 	if len(strings.TrimSpace(baseString)) == 0 {
 		return types.JavaBoolTrue
 	} else {
@@ -1834,7 +2145,10 @@ func stringIsBlank(params []interface{}) interface{} {
 }
 
 func stringIsEmpty(params []interface{}) interface{} {
-	baseString := object.GoStringFromStringObject(params[0].(*object.Object))
+	baseString, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringIsEmpty", err)
+	}
 	if len(baseString) == 0 {
 		return types.JavaBoolTrue
 	} else {
@@ -1848,9 +2162,18 @@ func stringToString(params []interface{}) interface{} {
 
 func stringReplaceAllRegex(params []interface{}) interface{} {
 	// Get 3 string arguments.
-	input := object.GoStringFromStringObject(params[0].(*object.Object))
-	pattern := object.GoStringFromStringObject(params[1].(*object.Object))
-	replacement := object.GoStringFromStringObject(params[2].(*object.Object))
+	input, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringReplaceAllRegex", err)
+	}
+	pattern, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringReplaceAllRegex", err)
+	}
+	replacement, err := args.GetGoString(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("stringReplaceAllRegex", err)
+	}
 
 	// Compile the regular expression.
 	re, err := regexp.Compile(pattern)
@@ -1868,9 +2191,18 @@ func stringReplaceAllRegex(params []interface{}) interface{} {
 
 func stringReplaceFirstRegex(params []interface{}) interface{} {
 	// Get 3 string arguments.
-	input := object.GoStringFromStringObject(params[0].(*object.Object))
-	pattern := object.GoStringFromStringObject(params[1].(*object.Object))
-	replacement := object.GoStringFromStringObject(params[2].(*object.Object))
+	input, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringReplaceFirstRegex", err)
+	}
+	pattern, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringReplaceFirstRegex", err)
+	}
+	replacement, err := args.GetGoString(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("stringReplaceFirstRegex", err)
+	}
 
 	// Compile the regular expression.
 	re, err := regexp.Compile(pattern)
@@ -1894,8 +2226,14 @@ func stringSplit(params []interface{}) interface{} {
 	// params[0] = base string
 	// params[1] = regular expression in a string
 
-	input := object.GoStringFromStringObject(params[0].(*object.Object))
-	pattern := object.GoStringFromStringObject(params[1].(*object.Object))
+	input, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringSplit", err)
+	}
+	pattern, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringSplit", err)
+	}
 
 	// Compile the regular expression.
 	re, err := regexp.Compile(pattern)
@@ -1921,9 +2259,18 @@ func stringSplitLimit(params []interface{}) interface{} {
 	// params[1] = regular expression in a string
 	// params[2] = split limit
 
-	input := object.GoStringFromStringObject(params[0].(*object.Object))
-	pattern := object.GoStringFromStringObject(params[1].(*object.Object))
-	limit := params[2].(int64)
+	input, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringSplitLimit", err)
+	}
+	pattern, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("stringSplitLimit", err)
+	}
+	limit, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("stringSplitLimit", err)
+	}
 	if limit == 0 {
 		limit = -1
 	}
@@ -1947,19 +2294,28 @@ func stringSplitLimit(params []interface{}) interface{} {
 }
 
 func stringStrip(params []interface{}) interface{} {
-	input := object.GoStringFromStringObject(params[0].(*object.Object))
+	input, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringStrip", err)
+	}
 	result := strings.TrimSpace(input)
 	return object.StringObjectFromGoString(result)
 }
 
 func stringStripLeading(params []interface{}) interface{} {
-	input := object.GoStringFromStringObject(params[0].(*object.Object))
+	input, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringStripLeading", err)
+	}
 	result := strings.TrimLeftFunc(input, unicode.IsSpace)
 	return object.StringObjectFromGoString(result)
 }
 
 func stringStripTrailing(params []interface{}) interface{} {
-	input := object.GoStringFromStringObject(params[0].(*object.Object))
+	input, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("stringStripTrailing", err)
+	}
 	result := strings.TrimRightFunc(input, unicode.IsSpace)
 	return object.StringObjectFromGoString(result)
 }
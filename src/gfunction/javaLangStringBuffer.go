@@ -93,7 +93,7 @@ func Load_Lang_StringBuffer() {
 	MethodSignatures["java/lang/StringBuffer.append(F)Ljava/lang/StringBuffer;"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  stringBuilderAppend,
+			GFunction:  stringBuilderAppendFloat,
 			ThreadSafe: true,
 		}
 
@@ -289,7 +289,7 @@ func Load_Lang_StringBuffer() {
 	MethodSignatures["java/lang/StringBuffer.insert(IF)Ljava/lang/StringBuffer;"] =
 		GMeth{
 			ParamSlots: 2,
-			GFunction:  stringBuilderInsert,
+			GFunction:  stringBuilderInsertFloat,
 			ThreadSafe: true,
 		}
 
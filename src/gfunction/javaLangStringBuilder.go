@@ -11,7 +11,6 @@ import (
 	"jacobin/src/excNames"
 	"jacobin/src/object"
 	"jacobin/src/types"
-	"strconv"
 )
 
 // Implementation of some of the functions in Java/lang/Class.
@@ -85,7 +84,7 @@ func Load_Lang_StringBuilder() {
 	MethodSignatures["java/lang/StringBuilder.append(F)Ljava/lang/StringBuilder;"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  stringBuilderAppend,
+			GFunction:  stringBuilderAppendFloat,
 		}
 
 	MethodSignatures["java/lang/StringBuilder.append(I)Ljava/lang/StringBuilder;"] =
@@ -253,7 +252,7 @@ func Load_Lang_StringBuilder() {
 	MethodSignatures["java/lang/StringBuilder.insert(IF)Ljava/lang/StringBuilder;"] =
 		GMeth{
 			ParamSlots: 2,
-			GFunction:  stringBuilderInsert,
+			GFunction:  stringBuilderInsertFloat,
 		}
 
 	MethodSignatures["java/lang/StringBuilder.insert(II)Ljava/lang/StringBuilder;"] =
@@ -501,8 +500,7 @@ func stringBuilderAppend(params []any) any {
 		str := fmt.Sprintf("%d", params[1].(int64))
 		parmArray = object.JavaByteArrayFromGoString(str)
 	case float64: // float, double
-		ff := params[1].(float64)
-		str := strconv.FormatFloat(ff, 'f', -1, 64)
+		str := object.JavaDoubleString(params[1].(float64))
 		parmArray = object.JavaByteArrayFromGoString(str)
 	default:
 		str := object.StringifyAnythingGo(params[1])
@@ -644,6 +642,17 @@ func stringBuilderDelete(params []any) any {
 	return objBase
 }
 
+// append(F): floats are held in float64s like doubles, so they're formatted here, as
+// Float.toString() does, and appended as a string
+func stringBuilderAppendFloat(params []any) any {
+	ff, ok := params[1].(float64)
+	if !ok {
+		errMsg := fmt.Sprintf("stringBuilderAppendFloat: Parameter type (%T) is illegal", params[1])
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	return stringBuilderAppend([]any{params[0], object.StringObjectFromGoString(object.JavaFloatString(ff))})
+}
+
 // Insert the second parameter to the bytes into the StringBuilder
 // at the given index.
 func stringBuilderInsert(params []any) any {
@@ -696,8 +705,7 @@ func stringBuilderInsert(params []any) any {
 		str := fmt.Sprintf("%d", params[2].(int64))
 		parmArray = object.JavaByteArrayFromGoString(str)
 	case float64: // float, double
-		ff := params[2].(float64)
-		str := strconv.FormatFloat(ff, 'f', -1, 64)
+		str := object.JavaDoubleString(params[2].(float64))
 		parmArray = object.JavaByteArrayFromGoString(str)
 	default:
 		errMsg := fmt.Sprintf("stringBuilderInsert: Parameter type (%T) is illegal", params[1])
@@ -957,3 +965,13 @@ func expandCapacity(obj *object.Object, count int64) {
 	capField.Fvalue = capacity
 	obj.FieldTable["capacity"] = capField
 }
+
+// insert(IF): floats are formatted as Float.toString() does and inserted as a string
+func stringBuilderInsertFloat(params []any) any {
+	ff, ok := params[2].(float64)
+	if !ok {
+		errMsg := fmt.Sprintf("stringBuilderInsertFloat: Parameter type (%T) is illegal", params[2])
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	return stringBuilderInsert([]any{params[0], params[1], object.StringObjectFromGoString(object.JavaFloatString(ff))})
+}
@@ -8,6 +8,7 @@ package gfunction
 
 import (
 	"jacobin/src/excNames"
+	"jacobin/src/globals"
 	"jacobin/src/object"
 	"jacobin/src/types"
 	"testing"
@@ -94,3 +95,19 @@ func TestStringBuilderLength(t *testing.T) {
 		t.Errorf("Expected 5, got %v", result)
 	}
 }
+
+func TestStringBuilderAppendDoubleAndFloat(t *testing.T) {
+	globals.InitGlobals("test")
+
+	obj := object.MakeEmptyObject()
+	_ = stringBuilderInit([]any{obj})
+	stringBuilderAppend([]any{obj, 1e10})
+	stringBuilderAppend([]any{obj, object.StringObjectFromGoString(" ")})
+	stringBuilderAppendFloat([]any{obj, float64(float32(0.1))})
+	stringBuilderInsertFloat([]any{obj, int64(0), 2.0})
+
+	str := object.GoStringFromJavaByteArray(obj.FieldTable["value"].Fvalue.([]types.JavaByte))
+	if str != "2.01.0E10 0.1" {
+		t.Errorf("Expected '2.01.0E10 0.1', got '%s'", str)
+	}
+}
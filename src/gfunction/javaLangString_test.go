@@ -7,6 +7,7 @@
 package gfunction
 
 import (
+	"jacobin/src/charset"
 	"jacobin/src/classloader"
 	"jacobin/src/excNames"
 	"jacobin/src/globals"
@@ -127,15 +128,51 @@ func TestStringLength_2(t *testing.T) {
 	}
 }
 
+// a %s with no argument for it is a MissingFormatArgumentException, as in the JDK
 func TestSprintf_1(t *testing.T) {
 	globals.InitGlobals("test")
 	aString := "Mary had a %s little lamb"
 	aObj := object.StringObjectFromGoString(aString)
 	params := []interface{}{aObj}
-	resultObj := (sprintf(params)).(*object.Object)
-	str := object.GoStringFromStringObject(resultObj)
-	if str != aString {
-		t.Errorf("TestSprintf_1: expected: %s, observed: %s", aString, str)
+	result := sprintf(params)
+
+	switch result.(type) {
+	case *GErrBlk:
+		errBlk := result.(*GErrBlk)
+		if errBlk.ExceptionType != excNames.MissingFormatArgumentException {
+			t.Errorf("TestSprintf_1: expected MissingFormatArgumentException, observed: %s",
+				excNames.JVMexceptionNames[errBlk.ExceptionType])
+		}
+	case *object.Object:
+		str := object.GoStringFromStringObject(result.(*object.Object))
+		t.Errorf("TestSprintf_1: expected MissingFormatArgumentException, observed: %s", str)
+	default:
+		t.Errorf("TestSprintf_1: result type %T makes no sense", result)
+	}
+}
+
+func TestSprintf_1WithArgument(t *testing.T) {
+	globals.InitGlobals("test")
+	aString := "Mary had a %s little lamb"
+	expected := "Mary had a very little lamb"
+	aObj := object.StringObjectFromGoString(aString)
+
+	classStr := "[Ljava/lang/Object"
+	argsObj := object.MakeEmptyObjectWithClassName(&classStr)
+	argsObj.FieldTable["value"] = object.Field{Ftype: classStr,
+		Fvalue: []*object.Object{object.StringObjectFromGoString("very")}}
+	result := sprintf([]interface{}{aObj, argsObj})
+
+	switch result.(type) {
+	case *GErrBlk:
+		t.Errorf("TestSprintf_1WithArgument: unexpected error: %s", result.(*GErrBlk).ErrMsg)
+	case *object.Object:
+		str := object.GoStringFromStringObject(result.(*object.Object))
+		if str != expected {
+			t.Errorf("TestSprintf_1WithArgument: expected: %s, observed: %s", expected, str)
+		}
+	default:
+		t.Errorf("TestSprintf_1WithArgument: result type %T makes no sense", result)
 	}
 }
 
@@ -1235,3 +1272,112 @@ func TestString_GetBytes_Empty(t *testing.T) {
 		t.Fatalf("expected empty byte array, got len=%d", len(gotBytes))
 	}
 }
+
+func TestString_GetBytes_CharsetName(t *testing.T) {
+	globals.InitStringPool()
+	strObj := object.StringObjectFromGoString("hé€")
+
+	out := getBytesFromStringCharsetName([]interface{}{strObj, object.StringObjectFromGoString("ISO-8859-1")})
+	if gotBytes := bytesFromByteArrayObject(out.(*object.Object)); string(gotBytes) != "h\xE9?" {
+		t.Errorf("expected the bytes 68 E9 3F, got % X", gotBytes)
+	}
+	out = getBytesFromStringCharsetName([]interface{}{strObj, object.StringObjectFromGoString("utf-16be")})
+	if gotBytes := bytesFromByteArrayObject(out.(*object.Object)); string(gotBytes) != "\x00h\x00\xE9\x20\xAC" {
+		t.Errorf("expected the bytes 00 68 00 E9 20 AC, got % X", gotBytes)
+	}
+
+	out = getBytesFromStringCharsetName([]interface{}{strObj, object.StringObjectFromGoString("no-such-charset")})
+	if gErr, ok := out.(*GErrBlk); !ok || gErr.ExceptionType != excNames.UnsupportedEncodingException {
+		t.Errorf("expected UnsupportedEncodingException, got %v", out)
+	}
+}
+
+func TestString_GetBytes_DefaultCharset(t *testing.T) {
+	globals.InitStringPool()
+	saved := charset.Default()
+	t.Cleanup(func() { charset.SetDefault(saved) })
+
+	charset.SetDefault(charset.USASCII)
+	out := getBytesFromString([]interface{}{object.StringObjectFromGoString("déjà")})
+	if gotBytes := bytesFromByteArrayObject(out.(*object.Object)); string(gotBytes) != "d?j?" {
+		t.Errorf("expected %q, got %q", "d?j?", gotBytes)
+	}
+}
+
+func TestString_NewFromBytes_Charset(t *testing.T) {
+	globals.InitStringPool()
+	saved := charset.Default()
+	t.Cleanup(func() { charset.SetDefault(saved) })
+	latin1 := Populator("[B", types.ByteArray, object.JavaByteArrayFromGoByteArray([]byte{'h', 0xE9, '!'}))
+
+	// decoded in the default charset, in which 0xE9 alone is malformed
+	charset.SetDefault(charset.UTF8)
+	str := object.MakeEmptyObject()
+	if res := newStringFromBytes([]interface{}{str, latin1}); res != nil {
+		t.Fatalf("newStringFromBytes returned error: %v", res)
+	}
+	if got := object.GoStringFromStringObject(str); got != "h�!" {
+		t.Errorf("expected %q, got %q", "h�!", got)
+	}
+
+	// decoded in the charset named
+	str = object.MakeEmptyObject()
+	if res := newStringFromBytesCharsetName([]interface{}{str, latin1, object.StringObjectFromGoString("latin1")}); res != nil {
+		t.Fatalf("newStringFromBytesCharsetName returned error: %v", res)
+	}
+	if got := object.GoStringFromStringObject(str); got != "hé!" {
+		t.Errorf("expected %q, got %q", "hé!", got)
+	}
+
+	str = object.MakeEmptyObject()
+	res := newStringFromBytesSubsetCharsetName([]interface{}{str, latin1, int64(1), int64(2),
+		object.StringObjectFromGoString("ISO-8859-1")})
+	if res != nil {
+		t.Fatalf("newStringFromBytesSubsetCharsetName returned error: %v", res)
+	}
+	if got := object.GoStringFromStringObject(str); got != "é!" {
+		t.Errorf("expected %q, got %q", "é!", got)
+	}
+
+	res = newStringFromBytesCharsetName([]interface{}{object.MakeEmptyObject(), latin1,
+		object.StringObjectFromGoString("no-such-charset")})
+	if gErr, ok := res.(*GErrBlk); !ok || gErr.ExceptionType != excNames.UnsupportedEncodingException {
+		t.Errorf("expected UnsupportedEncodingException, got %v", res)
+	}
+}
+
+// malformed calls return an error block rather than panicking
+func TestString_MalformedParams(t *testing.T) {
+	globals.InitStringPool()
+	str := object.StringObjectFromGoString("abc")
+
+	tests := []struct {
+		name          string
+		result        any
+		exceptionType int
+	}{
+		{"concat with null", stringConcat([]interface{}{str, object.Null}), excNames.NullPointerException},
+		{"concat with an int", stringConcat([]interface{}{str, int64(1)}), excNames.IllegalArgumentException},
+		{"charAt past the end", stringCharAt([]interface{}{str, int64(3)}), excNames.StringIndexOutOfBoundsException},
+		{"repeat with a missing count", stringRepeat([]interface{}{str}), excNames.IllegalArgumentException},
+		{"substring of a non-string", substringToTheEnd([]interface{}{object.MakeEmptyObject(), int64(1)}),
+			excNames.IllegalArgumentException},
+	}
+
+	for _, test := range tests {
+		errBlk, ok := test.result.(*GErrBlk)
+		if !ok {
+			t.Errorf("%s: expected an error block, got %T", test.name, test.result)
+			continue
+		}
+		if errBlk.ExceptionType != test.exceptionType {
+			t.Errorf("%s: expected %s, got %s (%s)", test.name, excNames.JVMexceptionNames[test.exceptionType],
+				excNames.JVMexceptionNames[errBlk.ExceptionType], errBlk.ErrMsg)
+		}
+	}
+
+	// a String equals neither null nor an object of another class
+	if stringEquals([]interface{}{str, object.Null}) != types.JavaBoolFalse {
+		t.Errorf("Expected \"abc\".equals(null) to be false")
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"jacobin/src/excNames"
 	"jacobin/src/globals"
 	"jacobin/src/object"
+	"jacobin/src/replay"
 	"jacobin/src/shutdown"
 	"jacobin/src/statics"
 	"jacobin/src/stringPool"
@@ -140,7 +141,7 @@ func Load_Lang_System() {
 	MethodSignatures["java/lang/System.identityHashCode(Ljava/lang/Object;)I"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  trapFunction,
+			GFunction:  systemIdentityHashCode,
 		}
 
 	MethodSignatures["java/lang/System.inheritedChannel()Ljava/nio/channels/Channel;"] =
@@ -157,20 +158,22 @@ func Load_Lang_System() {
 
 	MethodSignatures["java/lang/System.load(Ljava/lang/String;)V"] =
 		GMeth{
-			ParamSlots: 1,
-			GFunction:  trapFunction,
+			ParamSlots:   1,
+			GFunction:    systemLoad,
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/lang/System.loadLibrary(Ljava/lang/String;)V"] =
 		GMeth{
-			ParamSlots: 1,
-			GFunction:  trapFunction,
+			ParamSlots:   1,
+			GFunction:    systemLoadLibrary,
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/lang/System.mapLibraryName(Ljava/lang/String;)Ljava/lang/String;"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  trapFunction,
+			GFunction:  systemMapLibraryName,
 		}
 
 	MethodSignatures["java/lang/System.nanoTime()J"] = // get nanoseconds time, returned as long
@@ -194,19 +197,19 @@ func Load_Lang_System() {
 	MethodSignatures["java/lang/System.setErr(Ljava/io/PrintStream;)V"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  trapFunction,
+			GFunction:  systemSetErr,
 		}
 
 	MethodSignatures["java/lang/System.setIn(Ljava/io/InputStream;)V"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  trapFunction,
+			GFunction:  systemSetIn,
 		}
 
 	MethodSignatures["java/lang/System.setOut(Ljava/io/PrintStream;)V"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  trapFunction,
+			GFunction:  systemSetOut,
 		}
 
 	MethodSignatures["java/lang/System.setProperties(Ljava/util/Properties;)V"] =
@@ -224,7 +227,7 @@ func Load_Lang_System() {
 	MethodSignatures["java/lang/System.setSecurityManager(Ljava/lang/SecurityManager;)V"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  trapDeprecated,
+			GFunction:  systemSetSecurityManager,
 		}
 
 }
@@ -248,186 +251,214 @@ func systemClinit([]interface{}) interface{} {
 // systemArrayCopy copies an array or subarray from one array to another, both of which must exist.
 // It is a complex native function in the JDK. Javadoc here:
 // docs.oracle.com/en/java/javase/17/docs/api/java.base/java/lang/System.html#arraycopy(java.lang.Object,int,java.lang.Object,int,int)
+// The interpreter runs arraycopy() as an intrinsic, which calls ArrayCopy() directly; see jvm/intrinsics.go.
 func systemArrayCopy(params []interface{}) interface{} {
 	if len(params) != 5 {
 		errMsg := fmt.Sprintf("systemArrayCopy: Expected 5 parameters, got %d", len(params))
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
 
-	src := params[0].(*object.Object)
-	srcPos := params[1].(int64)
-	dest := params[2].(*object.Object)
-	destPos := params[3].(int64)
-	length := params[4].(int64)
+	src, _ := params[0].(*object.Object)
+	srcPos, _ := params[1].(int64)
+	dest, _ := params[2].(*object.Object)
+	destPos, _ := params[3].(int64)
+	length, _ := params[4].(int64)
+
+	if gErr := ArrayCopy(src, srcPos, dest, destPos, length); gErr != nil {
+		return gErr
+	}
+	return nil
+}
 
+// ArrayCopy copies length elements of the array src, starting at srcPos, to the array dest,
+// starting at destPos, as System.arraycopy() does. The object model keeps primitive arrays
+// in three kinds of Go slices (bytes and booleans, the integral types and char, and the
+// floating-point types), so primitive arrays can be copied to one another if they're of the
+// same kind. The copy is done as if through a temporary array, so the source and destination
+// can overlap. Reference arrays can be copied to one another if each element copied can be
+// stored in the destination; if one can't, the elements before it are copied and an
+// ArrayStoreException is thrown, as in the JDK.
+func ArrayCopy(src *object.Object, srcPos int64, dest *object.Object, destPos, length int64) *GErrBlk {
 	if object.IsNull(src) || object.IsNull(dest) {
 		errMsg := fmt.Sprintf("systemArrayCopy: null src or dest")
 		return getGErrBlk(excNames.NullPointerException, errMsg)
 	}
 
-	if srcPos < 0 || destPos < 0 || length < 0 {
-		errMsg := fmt.Sprintf(
-			"systemArrayCopy: Negative position in: srcPose=%d, destPos=%d, or length=%d", srcPos, destPos, length)
-		return getGErrBlk(excNames.ArrayIndexOutOfBoundsException, errMsg)
+	srcType := src.FieldTable["value"].Ftype
+	destType := dest.FieldTable["value"].Ftype
+	if !strings.HasPrefix(srcType, types.Array) || !strings.HasPrefix(destType, types.Array) {
+		errMsg := fmt.Sprintf("systemArrayCopy: invalid src (%s) or dest (%s) array",
+			*(stringPool.GetStringPointer(src.KlassName)), *(stringPool.GetStringPointer(dest.KlassName)))
+		return getGErrBlk(excNames.ArrayStoreException, errMsg)
 	}
-
-	srcType := *(stringPool.GetStringPointer(src.KlassName))
-	destType := *(stringPool.GetStringPointer(dest.KlassName))
-
-	if !strings.HasPrefix(srcType, types.Array) || !strings.HasPrefix(destType, types.Array) || srcType != destType {
-		errMsg := fmt.Sprintf("systemArrayCopy: invalid src (%s) or dest (%s) array", srcType, destType)
+	mismatch := func() *GErrBlk {
+		errMsg := fmt.Sprintf("systemArrayCopy: type mismatch: can not copy %s into %s", srcType, destType)
 		return getGErrBlk(excNames.ArrayStoreException, errMsg)
 	}
 
-	srcLen := object.ArrayLength(src)
-	destLen := object.ArrayLength(dest)
+	switch sArr := src.FieldTable["value"].Fvalue.(type) {
+	case []*object.Object:
+		dArr, ok := dest.FieldTable["value"].Fvalue.([]*object.Object)
+		if !ok {
+			return mismatch()
+		}
+		if gErr := checkArrayCopyBounds(int64(len(sArr)), srcPos, int64(len(dArr)), destPos, length); gErr != nil {
+			return gErr
+		}
+		return copyRefArray(sArr[srcPos:srcPos+length], dArr[destPos:destPos+length], srcType, destType)
 
-	if srcPos+length > srcLen || destPos+length > destLen {
-		errMsg := fmt.Sprintf("systemArrayCopy: Array position + length exceeds array size")
-		return getGErrBlk(excNames.ArrayIndexOutOfBoundsException, errMsg)
-	}
+	case []int64:
+		dArr, ok := dest.FieldTable["value"].Fvalue.([]int64)
+		if !ok {
+			return mismatch()
+		}
+		if gErr := checkArrayCopyBounds(int64(len(sArr)), srcPos, int64(len(dArr)), destPos, length); gErr != nil {
+			return gErr
+		}
+		copy(dArr[destPos:destPos+length], sArr[srcPos:srcPos+length])
 
-	s := srcPos
-	d := destPos
-
-	if (src != dest) || ((src == dest) && (srcPos+length < destPos)) {
-		// non-overlapping copy of identical items
-		switch srcType {
-		case types.ByteArray:
-			switch src.FieldTable["value"].Fvalue.(type) {
-			case []types.JavaByte:
-				sArr := src.FieldTable["value"].Fvalue.([]types.JavaByte)
-				dArr := dest.FieldTable["value"].Fvalue.([]types.JavaByte)
-				for i := int64(0); i < length; i++ {
-					dArr[d] = sArr[s]
-					d += 1
-					s += 1
-				}
-			case []byte:
-				sArr := src.FieldTable["value"].Fvalue.([]byte)
-				dArr := dest.FieldTable["value"].Fvalue.([]byte)
-				for i := int64(0); i < length; i++ {
-					dArr[d] = sArr[s]
-					d += 1
-					s += 1
-				}
-			}
+	case []float64:
+		dArr, ok := dest.FieldTable["value"].Fvalue.([]float64)
+		if !ok {
+			return mismatch()
+		}
+		if gErr := checkArrayCopyBounds(int64(len(sArr)), srcPos, int64(len(dArr)), destPos, length); gErr != nil {
+			return gErr
+		}
+		copy(dArr[destPos:destPos+length], sArr[srcPos:srcPos+length])
 
-		case types.FloatArray, types.DoubleArray:
-			sArr := src.FieldTable["value"].Fvalue.([]float64)
-			dArr := dest.FieldTable["value"].Fvalue.([]float64)
-			for i := int64(0); i < length; i++ {
-				dArr[d] = sArr[s]
-				d += 1
-				s += 1
-			}
-		case types.BoolArray, types.CharArray, types.IntArray, types.LongArray, types.ShortArray:
-			sArr := src.FieldTable["value"].Fvalue.([]int64)
-			dArr := dest.FieldTable["value"].Fvalue.([]int64)
+	case []types.JavaByte, []byte: // byte arrays made by some gfunctions hold Go bytes
+		sBytes := javaBytesOf(sArr)
+		dValue := dest.FieldTable["value"].Fvalue
+		dBytes := javaBytesOf(dValue)
+		if dBytes == nil {
+			return mismatch()
+		}
+		if gErr := checkArrayCopyBounds(int64(len(sBytes)), srcPos, int64(len(dBytes)), destPos, length); gErr != nil {
+			return gErr
+		}
+		if dArr, ok := dValue.([]byte); ok { // sBytes is a copy if src holds Go bytes, so they can't overlap
 			for i := int64(0); i < length; i++ {
-				dArr[d] = sArr[s]
-				d += 1
-				s += 1
-			}
-		default:
-			if strings.HasPrefix(srcType, types.RefArray) && strings.HasPrefix(destType, types.RefArray) {
-				sArr := src.FieldTable["value"].Fvalue.([]*object.Object)
-				dArr := dest.FieldTable["value"].Fvalue.([]*object.Object)
-				for i := int64(0); i < length; i++ {
-					dArr[d] = sArr[s]
-					d += 1
-					s += 1
-				}
-			} else {
-				errMsg := fmt.Sprintf("java/lang/System.arraycopy: src or dest type not an array")
-				return getGErrBlk(excNames.ArrayStoreException, errMsg)
+				dArr[destPos+i] = byte(sBytes[srcPos+i])
 			}
+			return nil
 		}
-	} else { // overlapping copy uses a temporary array
-		tempArray := make([]interface{}, length)
+		copy(dBytes[destPos:destPos+length], sBytes[srcPos:srcPos+length])
 
-		switch srcType {
-		case types.ByteArray:
-			sArr := src.FieldTable["value"].Fvalue.([]types.JavaByte)
-			dArr := dest.FieldTable["value"].Fvalue.([]types.JavaByte)
-			for i := int64(0); i < length; i++ {
-				tempArray[i] = sArr[s]
-				s += 1
-			}
-			for i := int64(0); i < length; i++ {
-				dArr[d] = tempArray[i].(types.JavaByte)
-				d += 1
-			}
+	default:
+		errMsg := fmt.Sprintf("systemArrayCopy: invalid src (%s) or dest (%s) array", srcType, destType)
+		return getGErrBlk(excNames.ArrayStoreException, errMsg)
+	}
+	return nil
+}
 
-		case types.FloatArray, types.DoubleArray:
-			sArr := src.FieldTable["value"].Fvalue.([]float64)
-			dArr := dest.FieldTable["value"].Fvalue.([]float64)
-			for i := int64(0); i < length; i++ {
-				tempArray[i] = sArr[s]
-				s += 1
-			}
-			for i := int64(0); i < length; i++ {
-				dArr[d] = tempArray[i].(float64)
-				d += 1
-			}
+// returns a byte array as Java bytes: a []types.JavaByte as is, and a []byte converted to a
+// copy. Returns nil if the value is neither.
+func javaBytesOf(value any) []types.JavaByte {
+	switch arr := value.(type) {
+	case []types.JavaByte:
+		return arr
+	case []byte:
+		return object.JavaByteArrayFromGoByteArray(arr)
+	}
+	return nil
+}
 
-		case types.BoolArray, types.CharArray, types.IntArray, types.LongArray, types.ShortArray:
-			sArr := src.FieldTable["value"].Fvalue.([]int64)
-			dArr := dest.FieldTable["value"].Fvalue.([]int64)
-			for i := int64(0); i < length; i++ {
-				tempArray[i] = sArr[s]
-				s += 1
-			}
-			for i := int64(0); i < length; i++ {
-				dArr[d] = tempArray[i].(int64)
-				d += 1
-			}
+// checks the positions and length passed to arraycopy() against the lengths of the arrays
+func checkArrayCopyBounds(srcLen, srcPos, destLen, destPos, length int64) *GErrBlk {
+	if srcPos < 0 || destPos < 0 || length < 0 {
+		errMsg := fmt.Sprintf(
+			"systemArrayCopy: Negative position in: srcPos=%d, destPos=%d, or length=%d", srcPos, destPos, length)
+		return getGErrBlk(excNames.ArrayIndexOutOfBoundsException, errMsg)
+	}
+	if srcPos+length > srcLen || destPos+length > destLen {
+		errMsg := fmt.Sprintf("systemArrayCopy: Array position + length exceeds array size: "+
+			"last source index %d of %d, last destination index %d of %d",
+			srcPos+length, srcLen, destPos+length, destLen)
+		return getGErrBlk(excNames.ArrayIndexOutOfBoundsException, errMsg)
+	}
+	return nil
+}
 
-		default:
-			if strings.HasPrefix(srcType, types.RefArray) && strings.HasPrefix(destType, types.RefArray) {
-				sArr := src.FieldTable["value"].Fvalue.([]*object.Object)
-				dArr := dest.FieldTable["value"].Fvalue.([]*object.Object)
-				for i := int64(0); i < length; i++ {
-					tempArray[i] = sArr[s]
-					s += 1
-				}
-				for i := int64(0); i < length; i++ {
-					dArr[d] = tempArray[i].(*object.Object)
-					d += 1
-				}
-			} else {
-				errMsg := fmt.Sprintf("java/lang/System.arraycopy: src or dest type not an array")
-				return getGErrBlk(excNames.ArrayStoreException, errMsg)
-			}
+// copies the elements of a reference array to another. Unless the elements of the source's
+// type are known to be storable in the destination, each element is checked before it's copied.
+func copyRefArray(src, dest []*object.Object, srcType, destType string) *GErrBlk {
+	srcComponent, destComponent := classloader.ArrayComponentType(srcType), classloader.ArrayComponentType(destType)
+	if srcType == destType || destComponent == "" || destComponent == types.ObjectClassName ||
+		(classloader.MethAreaFetch(srcComponent) != nil && classloader.IsAssignableToComponent(srcComponent, destComponent)) {
+		copy(dest, src)
+		return nil
+	}
+	for i, elem := range src {
+		if !object.IsNull(elem) && !classloader.IsAssignableToComponent(classloader.TypeNameOf(elem), destComponent) {
+			errMsg := fmt.Sprintf("systemArrayCopy: element type mismatch: can not cast one of the elements "+
+				"of %s to the type of the destination array, %s", srcType, destComponent)
+			return getGErrBlk(excNames.ArrayStoreException, errMsg)
 		}
+		dest[i] = elem
 	}
-
 	return nil
 }
 
 // Return the system input console as a *os.File.
 func systemConsole([]interface{}) interface{} {
-	return statics.GetStaticValue("java/lang/System", "in")
+	return consoleFile("in")
+}
+
+// "java/lang/System.setOut(Ljava/io/PrintStream;)V"
+func systemSetOut(params []interface{}) interface{} {
+	setStandardStream("out", "Ljava/io/PrintStream;", params[0])
+	return nil
+}
+
+// "java/lang/System.setErr(Ljava/io/PrintStream;)V"
+func systemSetErr(params []interface{}) interface{} {
+	setStandardStream("err", "Ljava/io/PrintStream;", params[0])
+	return nil
+}
+
+// "java/lang/System.setIn(Ljava/io/InputStream;)V"
+func systemSetIn(params []interface{}) interface{} {
+	setStandardStream("in", "Ljava/io/InputStream;", params[0])
+	return nil
+}
+
+// setStandardStream replaces System.in, out, or err with the stream, which may be null. A
+// Go handle, such as the *os.File the static held before, keeps the type "GS" that
+// systemClinit() gives it; a stream written in Java has the type of the static's field.
+func setStandardStream(name, fieldType string, stream interface{}) {
+	staticType := fieldType
+	if _, ok := stream.(*object.Object); !ok && stream != nil {
+		staticType = "GS"
+	}
+	_ = statics.AddStatic("java/lang/System."+name, statics.Static{Type: staticType, Value: stream})
 }
 
 // Return time in milliseconds, measured since midnight of Jan 1, 1970
 func systemCurrentTimeMillis([]interface{}) interface{} {
-	return time.Now().UnixMilli() // is int64
+	return replay.Int64(replay.KindMillis, func() int64 { return time.Now().UnixMilli() }) // see replay.go
 }
 
-// Return time in nanoseconds. Note that in golang this function has a lower (that is, less good)
-// resolution than Java: two successive calls often return the same value.
+// Return a time in nanoseconds. As in Java, this is for measuring intervals, not telling the
+// time: it's the wall-clock time at which Jacobin started plus the time elapsed since then on
+// Go's monotonic clock, so it never goes backward, even if the wall clock that
+// currentTimeMillis() reads is set back.
 func systemNanoTime([]interface{}) interface{} {
-	return time.Now().UnixNano() // is int64
+	return replay.Int64(replay.KindNanos, func() int64 { // see replay.go
+		return nanoTimeOrigin.UnixNano() + time.Since(nanoTimeOrigin).Nanoseconds()
+	})
 }
 
+// The origin of nanoTime(). time.Now() includes a monotonic clock reading, which time.Since uses.
+var nanoTimeOrigin = time.Now()
+
 // Exits the program directly, returning the passed in value
 // exit is a static function, so no object ref and exit value is in params[0]
 func systemExitI(params []interface{}) interface{} {
 	exitCode := params[0].(int64)
 	var exitStatus = int(exitCode)
 	shutdown.Exit(exitStatus)
-	return exitCode // this code is not executed as previous line ends Jacobin
+	return exitCode // reached only in tests and if the exit is intercepted (see globals.SetExitHandler)
 }
 
 // Force a garbage collection cycle.
@@ -547,15 +578,36 @@ func systemGetProperties([]interface{}) interface{} {
 
 }
 
-// systemGetSecurityManager
+// Return the identity hash code of an object, whether or not its class overrides hashCode().
+// The identity hash code of null is 0.
+func systemIdentityHashCode(params []interface{}) interface{} {
+	obj, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return int64(0)
+	}
+	return int64(object.IdentityHashCode(obj))
+}
+
+// systemGetSecurityManager: there is never a security manager, so this is always null,
+// which tells a library that probes for one that every operation is permitted
 func systemGetSecurityManager(params []interface{}) interface{} {
-	return object.MakeEmptyObjectWithClassName(&classNameSecurityManager)
+	return object.Null
+}
+
+// systemSetSecurityManager: as in the JDK when it doesn't allow a security manager, which
+// it doesn't by default since Java 18, setting one throws an UnsupportedOperationException,
+// and setting none does nothing
+func systemSetSecurityManager(params []interface{}) interface{} {
+	if object.IsNull(params[0]) {
+		return nil
+	}
+	errMsg := "The Security Manager is deprecated and will be removed in a future release"
+	return getGErrBlk(excNames.UnsupportedOperationException, errMsg)
 }
 
-// Get the system line separator.
+// Get the system line separator, which is set at startup.
 func systemLineSeparator([]interface{}) interface{} {
-	str := globals.GetSystemProperty("line.separator")
-	return object.StringObjectFromGoString(str)
+	return object.StringObjectFromGoString(globals.GetLineSeparator())
 }
 
 // Set a system property.
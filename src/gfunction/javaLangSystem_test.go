@@ -9,6 +9,7 @@ package gfunction
 import (
 	"fmt"
 	"jacobin/src/classloader"
+	"jacobin/src/excNames"
 	"jacobin/src/globals"
 	"jacobin/src/object"
 	"jacobin/src/statics"
@@ -266,6 +267,102 @@ func TestArrayCopyInvalidLength(t *testing.T) {
 	}
 }
 
+func TestArrayCopyOverlappingForward(t *testing.T) {
+	globals.InitGlobals("test")
+
+	arr := object.Make1DimArray(object.INT, 6)
+	raw := arr.FieldTable["value"].Fvalue.([]int64)
+	for i := range raw {
+		raw[i] = int64(i)
+	}
+
+	// copying to a later position in the same array must not overwrite elements before they're copied
+	if err := systemArrayCopy([]interface{}{arr, int64(0), arr, int64(2), int64(4)}); err != nil {
+		t.Errorf("Unexpected error in test of systemArrayCopy(): %v", err)
+	}
+	expected := []int64{0, 1, 0, 1, 2, 3}
+	for i := range expected {
+		if raw[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, raw)
+			break
+		}
+	}
+}
+
+func TestArrayCopyTypeMismatch(t *testing.T) {
+	globals.InitGlobals("test")
+
+	ints := object.Make1DimArray(object.INT, 4)
+	doubles := object.Make1DimArray(object.FLOAT, 4)
+	strs := object.Make1DimRefArray("java/lang/String;", 4)
+
+	for _, dest := range []*object.Object{doubles, strs} {
+		err := systemArrayCopy([]interface{}{ints, int64(0), dest, int64(0), int64(1)})
+		gErr, ok := err.(*GErrBlk)
+		if !ok || gErr.ExceptionType != excNames.ArrayStoreException || !strings.Contains(gErr.ErrMsg, "type mismatch") {
+			t.Errorf("Expected an ArrayStoreException re type mismatch, got %v", err)
+		}
+	}
+
+	// the positions are checked after the types
+	err := systemArrayCopy([]interface{}{ints, int64(-1), ints, int64(0), int64(1)})
+	if gErr, ok := err.(*GErrBlk); !ok || gErr.ExceptionType != excNames.ArrayIndexOutOfBoundsException {
+		t.Errorf("Expected an ArrayIndexOutOfBoundsException, got %v", err)
+	}
+}
+
+func TestArrayCopyByteArrays(t *testing.T) {
+	globals.InitGlobals("test")
+
+	goBytes := object.MakePrimitiveObject("[B", types.ByteArray, []byte("abcdef"))
+	javaBytes := object.Make1DimArray(object.BYTE, 4)
+
+	if err := systemArrayCopy([]interface{}{goBytes, int64(1), javaBytes, int64(0), int64(4)}); err != nil {
+		t.Errorf("Unexpected error in test of systemArrayCopy(): %v", err)
+	}
+	if got := object.GoStringFromJavaByteArray(javaBytes.FieldTable["value"].Fvalue.([]types.JavaByte)); got != "bcde" {
+		t.Errorf("Expected \"bcde\", got %q", got)
+	}
+
+	if err := systemArrayCopy([]interface{}{javaBytes, int64(0), goBytes, int64(0), int64(2)}); err != nil {
+		t.Errorf("Unexpected error in test of systemArrayCopy(): %v", err)
+	}
+	if got := string(goBytes.FieldTable["value"].Fvalue.([]byte)); got != "bccdef" {
+		t.Errorf("Expected \"bccdef\", got %q", got)
+	}
+}
+
+func TestArrayCopyReferenceArrays(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	// an Object[] holding a String and a 1-dim array, copied into a String[]: the String
+	// is copied, and then the array can't be stored
+	objs := object.Make1DimRefArray("java/lang/Object;", 3)
+	rawObjs := objs.FieldTable["value"].Fvalue.([]*object.Object)
+	rawObjs[0] = object.StringObjectFromGoString("copied")
+	rawObjs[1] = object.Make1DimArray(object.INT, 2)
+	strs := object.Make1DimRefArray("java/lang/String;", 3)
+
+	err := systemArrayCopy([]interface{}{objs, int64(0), strs, int64(0), int64(3)})
+	if gErr, ok := err.(*GErrBlk); !ok || gErr.ExceptionType != excNames.ArrayStoreException {
+		t.Errorf("Expected an ArrayStoreException, got %v", err)
+	}
+	rawStrs := strs.FieldTable["value"].Fvalue.([]*object.Object)
+	if object.GoStringFromStringObject(rawStrs[0]) != "copied" || rawStrs[1] != nil {
+		t.Errorf("Expected only the first element to be copied, got %v", rawStrs)
+	}
+
+	// the rows of a 2-dim array can be copied into an Object[]
+	matrix, _ := object.Make2DimArray(2, 3, object.INT)
+	if err = systemArrayCopy([]interface{}{matrix, int64(0), objs, int64(1), int64(2)}); err != nil {
+		t.Errorf("Unexpected error in test of systemArrayCopy(): %v", err)
+	}
+	if rawObjs[2] != matrix.FieldTable["value"].Fvalue.([]*object.Object)[1] {
+		t.Errorf("Expected the second row of the matrix in the Object[]")
+	}
+}
+
 func TestGetMilliTime(t *testing.T) {
 	globals.InitGlobals("test")
 	ret := systemCurrentTimeMillis(nil).(int64)
@@ -290,6 +387,18 @@ func TestExitI(t *testing.T) {
 	}
 }
 
+func TestExitIIntercepted(t *testing.T) {
+	globals.InitGlobals("test")
+	status := -1
+	previous := globals.SetExitHandler(func(s int) { status = s })
+	defer globals.SetExitHandler(previous)
+
+	systemExitI([]interface{}{int64(3)})
+	if status != 3 {
+		t.Errorf("Expected the exit handler to get the status 3, got %d", status)
+	}
+}
+
 func TestGetConsole(t *testing.T) {
 	globals.InitGlobals("test")
 
@@ -515,12 +624,15 @@ func TestGetProperty_LineSeparator(t *testing.T) {
 	result := systemGetProperty(params)
 	var expected string
 	if runtime.GOOS == "windows" {
-		expected = "\\r\\n"
+		expected = "\r\n"
 	} else {
-		expected = "\\n"
+		expected = "\n"
 	}
 	if object.GoStringFromStringObject(result.(*object.Object)) != expected {
-		t.Errorf("Expected %v, got %v", expected, result)
+		t.Errorf("Expected %q, got %q", expected, object.GoStringFromStringObject(result.(*object.Object)))
+	}
+	if sep := systemLineSeparator(nil).(*object.Object); object.GoStringFromStringObject(sep) != expected {
+		t.Errorf("Expected System.lineSeparator() to be %q, got %q", expected, object.GoStringFromStringObject(sep))
 	}
 }
 
@@ -574,7 +686,7 @@ func TestGetProperty_PathSeparator(t *testing.T) {
 	propObj := object.StringObjectFromGoString("path.separator")
 	params := []interface{}{propObj}
 	result := systemGetProperty(params)
-	expected := object.StringObjectFromGoString(string(os.PathSeparator))
+	expected := object.StringObjectFromGoString(string(os.PathListSeparator))
 	if object.GoStringFromStringObject(result.(*object.Object)) != object.GoStringFromStringObject(expected) {
 		t.Errorf("Expected %v, got %v", expected, result)
 	}
@@ -773,3 +885,20 @@ func TestRunGc(t *testing.T) {
 			initialGcCount, finalGcCount)
 	}
 }
+
+func TestNanoTimeIsMonotonic(t *testing.T) {
+	globals.InitGlobals("test")
+	previous := systemNanoTime(nil).(int64)
+	for i := 0; i < 1000; i++ {
+		now := systemNanoTime(nil).(int64)
+		if now < previous {
+			t.Fatalf("nanoTime went backward: %d, then %d", previous, now)
+		}
+		previous = now
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	if elapsed := systemNanoTime(nil).(int64) - previous; elapsed < int64(2*time.Millisecond) {
+		t.Errorf("nanoTime advanced %d ns across a 2 ms sleep", elapsed)
+	}
+}
@@ -7,10 +7,14 @@
 package gfunction
 
 import (
+	"container/list"
+	"fmt"
 	"jacobin/src/excNames"
+	"jacobin/src/globals"
 	"jacobin/src/object"
 	"jacobin/src/thread"
 	"jacobin/src/types"
+	"sync/atomic"
 	"time"
 )
 
@@ -79,16 +83,164 @@ func Load_Lang_Thread() {
 			GFunction:  threadCreateWithName,
 		}
 
+	// the constructors that take a Runnable, which the thread's run() runs
+	MethodSignatures["java/lang/Thread.<init>(Ljava/lang/Runnable;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  threadInitRunnable,
+		}
+
+	MethodSignatures["java/lang/Thread.<init>(Ljava/lang/Runnable;Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  threadInitRunnable,
+		}
+
+	MethodSignatures["java/lang/Thread.<init>(Ljava/lang/ThreadGroup;Ljava/lang/Runnable;)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  threadInitGroupRunnable,
+		}
+
+	MethodSignatures["java/lang/Thread.<init>(Ljava/lang/ThreadGroup;Ljava/lang/Runnable;Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  threadInitGroupRunnable,
+		}
+
+	MethodSignatures["java/lang/Thread.<init>(Ljava/lang/ThreadGroup;Ljava/lang/Runnable;Ljava/lang/String;J)V"] =
+		GMeth{
+			ParamSlots: 4,
+			GFunction:  threadInitGroupRunnable,
+		}
+
+	MethodSignatures["java/lang/Thread.<init>(Ljava/lang/ThreadGroup;Ljava/lang/Runnable;Ljava/lang/String;JZ)V"] =
+		GMeth{
+			ParamSlots: 5,
+			GFunction:  threadInitGroupRunnable,
+		}
+
 	MethodSignatures["java/lang/Thread.registerNatives()V"] =
 		GMeth{
 			ParamSlots: 0,
 			GFunction:  justReturn,
 		}
 
+	MethodSignatures["java/lang/Thread.currentThread()Ljava/lang/Thread;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    threadCurrentThread,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/Thread.getDefaultUncaughtExceptionHandler()Ljava/lang/Thread$UncaughtExceptionHandler;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  threadGetDefaultUncaughtExceptionHandler,
+		}
+
+	MethodSignatures["java/lang/Thread.getUncaughtExceptionHandler()Ljava/lang/Thread$UncaughtExceptionHandler;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  threadGetUncaughtExceptionHandler,
+		}
+
+	MethodSignatures["java/lang/Thread.interrupt()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  threadInterrupt,
+		}
+
+	MethodSignatures["java/lang/Thread.interrupted()Z"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    threadInterrupted,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/Thread.isInterrupted()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  threadIsInterrupted,
+		}
+
+	MethodSignatures["java/lang/Thread.isVirtual()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  threadIsVirtual,
+		}
+
+	MethodSignatures["java/lang/Thread.join()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    threadJoin,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/Thread.join(J)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    threadJoin,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/Thread.ofVirtual()Ljava/lang/Thread$Builder$OfVirtual;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  threadOfVirtual,
+		}
+
+	MethodSignatures["java/lang/Thread.setDefaultUncaughtExceptionHandler(Ljava/lang/Thread$UncaughtExceptionHandler;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  threadSetDefaultUncaughtExceptionHandler,
+		}
+
+	MethodSignatures["java/lang/Thread.setUncaughtExceptionHandler(Ljava/lang/Thread$UncaughtExceptionHandler;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  threadSetUncaughtExceptionHandler,
+		}
+
 	MethodSignatures["java/lang/Thread.sleep(J)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    threadSleep,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/Thread.start()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    threadStart,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/Thread.startVirtualThread(Ljava/lang/Runnable;)Ljava/lang/Thread;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    threadStartVirtualThread,
+			NeedsContext: true,
+		}
+
+	// the builder returned by Thread.ofVirtual()
+	MethodSignatures[virtualThreadBuilderClassName+".name(Ljava/lang/String;)Ljava/lang/Thread$Builder$OfVirtual;"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  threadSleep,
+			GFunction:  virtualThreadBuilderName,
+		}
+
+	MethodSignatures[virtualThreadBuilderClassName+".start(Ljava/lang/Runnable;)Ljava/lang/Thread;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    virtualThreadBuilderStart,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[virtualThreadBuilderClassName+".unstarted(Ljava/lang/Runnable;)Ljava/lang/Thread;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  virtualThreadBuilderUnstarted,
 		}
 
 	// various methods
@@ -101,10 +253,17 @@ func Load_Lang_Thread() {
 
 var classname = "java/lang/Thread"
 
-func threadCreateNoarg(params []interface{}) any {
+const virtualThreadBuilderClassName = "java/lang/ThreadBuilders$VirtualThreadBuilder"
 
+func threadCreateNoarg(params []interface{}) any {
 	t := object.MakeEmptyObjectWithClassName(&classname)
+	initThreadFields(t)
+	return t
+}
 
+// initThreadFields sets the fields of a new Thread object to those of a thread that
+// has not been started: no name, normal priority, and not a daemon
+func initThreadFields(t *object.Object) {
 	nameField := object.Field{Ftype: types.GolangString, Fvalue: ""}
 	t.FieldTable["name"] = nameField
 
@@ -126,8 +285,6 @@ func threadCreateNoarg(params []interface{}) any {
 	priority := object.Field{
 		Ftype: types.Int, Fvalue: int64(thread.NORM_PRIORITY)}
 	t.FieldTable["priority"] = priority
-
-	return &t
 }
 
 func threadCreateWithName(params []interface{}) any {
@@ -137,17 +294,345 @@ func threadCreateWithName(params []interface{}) any {
 	return t
 }
 
+// the number given to the next thread whose constructor is not passed a name, as in
+// the JDK's Thread-0, Thread-1, etc.
+var threadNameNumber atomic.Int64
+
+// "java/lang/Thread.<init>(Ljava/lang/Runnable;)V" and
+// "java/lang/Thread.<init>(Ljava/lang/Runnable;Ljava/lang/String;)V"
+func threadInitRunnable(params []interface{}) interface{} {
+	var name any
+	if len(params) > 2 {
+		name = params[2]
+	}
+	return initThread("threadInitRunnable", params[0].(*object.Object), object.Null, params[1], name, true)
+}
+
+// "java/lang/Thread.<init>(Ljava/lang/ThreadGroup;Ljava/lang/Runnable;)V" and the forms
+// that add a name, a stack size (which is ignored), and whether to inherit the values of
+// the InheritableThreadLocals
+func threadInitGroupRunnable(params []interface{}) interface{} {
+	var name any
+	if len(params) > 3 {
+		name = params[3]
+	}
+	inherit := true
+	if len(params) > 5 {
+		inherit = params[5].(int64) == types.JavaBoolTrue
+	}
+	return initThread("threadInitGroupRunnable", params[0].(*object.Object), params[1], params[2], name, inherit)
+}
+
+// initThread initializes a Thread object whose constructor was passed a Runnable,
+// which may be null if a subclass of Thread overrides run() instead. A nil name means
+// the constructor wasn't passed one, so the thread gets a generated name.
+func initThread(funcName string, t *object.Object, group, target, name any, inherit bool) interface{} {
+	threadName := fmt.Sprintf("Thread-%d", threadNameNumber.Add(1)-1)
+	if name != nil {
+		nameObj, ok := name.(*object.Object)
+		if !ok || object.IsNull(nameObj) {
+			return getGErrBlk(excNames.NullPointerException, funcName+": name cannot be null")
+		}
+		threadName = object.GoStringFromStringObject(nameObj)
+	}
+
+	initThreadFields(t)
+	t.FieldTable["name"] = object.Field{Ftype: types.GolangString, Fvalue: threadName}
+	if groupObj, ok := group.(*object.Object); ok && !object.IsNull(groupObj) {
+		t.FieldTable["threadgroup"] = object.Field{Ftype: types.Ref, Fvalue: groupObj}
+	}
+	if targetObj, ok := target.(*object.Object); ok && !object.IsNull(targetObj) {
+		t.FieldTable["target"] = object.Field{Ftype: types.Ref, Fvalue: targetObj}
+	}
+	t.FieldTable["inheritThreadLocals"] = object.Field{Ftype: types.Bool,
+		Fvalue: object.JavaBooleanFromGoBoolean(inherit)}
+	return nil
+}
+
+// "java/lang/Thread.currentThread()Ljava/lang/Thread;"
+// The Thread object is created on the first request and then reused, so that
+// repeated calls return the same object.
+func threadCurrentThread(params []interface{}) interface{} {
+	th := currentExecThread(params[0].(*list.List))
+	if th == nil {
+		errMsg := "threadCurrentThread: could not find the current thread"
+		return getGErrBlk(excNames.IllegalStateException, errMsg)
+	}
+
+	if t, ok := th.JavaThread.(*object.Object); ok {
+		return t
+	}
+	t := threadCreateNoarg(nil).(*object.Object)
+	t.FieldTable["ID"] = object.Field{Ftype: types.Int, Fvalue: int64(th.ID)}
+	t.FieldTable["state"] = object.Field{Ftype: types.Int, Fvalue: thread.State(thread.RUNNABLE)}
+	if th.ID == 1 {
+		t.FieldTable["name"] = object.Field{Ftype: types.GolangString, Fvalue: "main"}
+	}
+	th.JavaThread = t
+	return t
+}
+
+// the handler of the exceptions that threads with no handler of their own don't catch,
+// set by Thread.setDefaultUncaughtExceptionHandler()
+var defaultUncaughtHandler atomic.Pointer[object.Object]
+
+// "java/lang/Thread.getDefaultUncaughtExceptionHandler()Ljava/lang/Thread$UncaughtExceptionHandler;"
+func threadGetDefaultUncaughtExceptionHandler(_ []interface{}) interface{} {
+	if handler := defaultUncaughtHandler.Load(); handler != nil {
+		return handler
+	}
+	return object.Null
+}
+
+// "java/lang/Thread.getUncaughtExceptionHandler()Ljava/lang/Thread$UncaughtExceptionHandler;"
+// Returns the thread's own handler, or null if it has none. (In the JDK, a thread
+// with no handler returns its ThreadGroup, which Jacobin does not implement.)
+func threadGetUncaughtExceptionHandler(params []interface{}) interface{} {
+	t := params[0].(*object.Object)
+	if handler, ok := t.FieldTable["uncaughtExceptionHandler"].Fvalue.(*object.Object); ok {
+		return handler
+	}
+	return object.Null
+}
+
+// "java/lang/Thread.setDefaultUncaughtExceptionHandler(Ljava/lang/Thread$UncaughtExceptionHandler;)V"
+// A null handler removes the default handler.
+func threadSetDefaultUncaughtExceptionHandler(params []interface{}) interface{} {
+	handler, _ := params[0].(*object.Object)
+	if object.IsNull(handler) {
+		handler = nil
+	}
+	defaultUncaughtHandler.Store(handler)
+	return nil
+}
+
+// "java/lang/Thread.setUncaughtExceptionHandler(Ljava/lang/Thread$UncaughtExceptionHandler;)V"
+// A null handler removes the thread's handler, so the default handler applies.
+func threadSetUncaughtExceptionHandler(params []interface{}) interface{} {
+	t := params[0].(*object.Object)
+	handler, _ := params[1].(*object.Object)
+	if object.IsNull(handler) {
+		delete(t.FieldTable, "uncaughtExceptionHandler")
+		return nil
+	}
+	t.FieldTable["uncaughtExceptionHandler"] = object.Field{Ftype: types.Ref, Fvalue: handler}
+	return nil
+}
+
+// UncaughtExceptionHandlerOf returns the handler of the exceptions the thread whose
+// Thread object is given doesn't catch: its own handler if it has one, else the
+// default handler. Returns nil if there's neither, in which case the JVM prints the
+// exception's stack trace.
+func UncaughtExceptionHandlerOf(t *object.Object) *object.Object {
+	if !object.IsNull(t) {
+		if handler, ok := t.FieldTable["uncaughtExceptionHandler"].Fvalue.(*object.Object); ok {
+			return handler
+		}
+	}
+	return defaultUncaughtHandler.Load()
+}
+
+// ThreadObjectOf returns the Thread object of the thread that runs on the frame
+// stack fs, which is created if need be, as Thread.currentThread() does
+func ThreadObjectOf(fs *list.List) *object.Object {
+	t, _ := threadCurrentThread([]interface{}{fs}).(*object.Object)
+	return t
+}
+
+// "java/lang/Thread.interrupt()V"
+// Interrupting a thread that is not running has no effect.
+func threadInterrupt(params []interface{}) interface{} {
+	if th := execThreadOf(params[0].(*object.Object)); th != nil {
+		th.Interrupt()
+	}
+	return nil
+}
+
+// "java/lang/Thread.interrupted()Z"
+// Tests and clears the interrupt status of the current thread.
+func threadInterrupted(params []interface{}) interface{} {
+	th := currentExecThread(params[0].(*list.List))
+	if th == nil {
+		return types.JavaBoolFalse
+	}
+	return object.JavaBooleanFromGoBoolean(th.ClearInterrupt())
+}
+
+// "java/lang/Thread.isInterrupted()Z"
+func threadIsInterrupted(params []interface{}) interface{} {
+	th := execThreadOf(params[0].(*object.Object))
+	if th == nil {
+		return types.JavaBoolFalse
+	}
+	return object.JavaBooleanFromGoBoolean(th.IsInterrupted())
+}
+
+// "java/lang/Thread.join()V" and "java/lang/Thread.join(J)V"
+// Waits for the thread to end, for at most the given number of milliseconds
+// (0 = forever). Returns at once if the thread is not running. An interrupt
+// of the current thread ends the wait with an InterruptedException.
+func threadJoin(params []interface{}) interface{} {
+	var millis int64
+	if len(params) > 2 {
+		millis = params[2].(int64)
+	}
+	if millis < 0 {
+		errMsg := "threadJoin: timeout value is negative"
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+
+	current := currentExecThread(params[0].(*list.List))
+	target := execThreadOf(params[1].(*object.Object))
+	if current == nil || target == nil {
+		return nil
+	}
+	thread.SetBlockedOn(current.ID, thread.BlockedOnJoin, "", target.ID)
+	interrupted := current.WaitInterruptibly(target.Terminated, time.Duration(millis)*time.Millisecond)
+	thread.ClearBlockedOn(current.ID)
+	if interrupted {
+		return getGErrBlk(excNames.InterruptedException, "join interrupted")
+	}
+	return nil
+}
+
+// "java/lang/Thread.isVirtual()Z"
+func threadIsVirtual(params []interface{}) interface{} {
+	t := params[0].(*object.Object)
+	if virtual, ok := t.FieldTable["virtual"].Fvalue.(int64); ok {
+		return virtual
+	}
+	return types.JavaBoolFalse
+}
+
+// "java/lang/Thread.ofVirtual()Ljava/lang/Thread$Builder$OfVirtual;"
+// Returns a builder for virtual threads
+func threadOfVirtual(_ []interface{}) interface{} {
+	className := virtualThreadBuilderClassName
+	builder := object.MakeEmptyObjectWithClassName(&className)
+	builder.FieldTable["name"] = object.Field{Ftype: types.GolangString, Fvalue: ""}
+	return builder
+}
+
+// "java/lang/Thread.start()V"
+func threadStart(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	t := params[1].(*object.Object)
+
+	if started, _ := t.FieldTable["started"].Fvalue.(int64); started == types.JavaBoolTrue {
+		return getGErrBlk(excNames.IllegalThreadStateException, "threadStart: thread already started")
+	}
+	t.FieldTable["started"] = object.Field{Ftype: types.Bool, Fvalue: types.JavaBoolTrue}
+
+	glob := globals.GetGlobalRef()
+	if glob.FuncStartThread == nil {
+		return getGErrBlk(excNames.InternalException, "threadStart: no means of starting a thread")
+	}
+	if err := glob.FuncStartThread(fs, t); err != nil { // such as an exception in childValue()
+		return upcallErrBlk(err)
+	}
+	return nil
+}
+
+// "java/lang/Thread.startVirtualThread(Ljava/lang/Runnable;)Ljava/lang/Thread;"
+// Creates and starts a virtual thread that runs the Runnable
+func threadStartVirtualThread(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	t := virtualThreadBuilderUnstarted([]interface{}{threadOfVirtual(nil), params[1]})
+	if errBlk, ok := t.(*GErrBlk); ok {
+		return errBlk
+	}
+	if ret := threadStart([]interface{}{fs, t}); ret != nil {
+		return ret
+	}
+	return t
+}
+
+// "java/lang/ThreadBuilders$VirtualThreadBuilder.name(Ljava/lang/String;)Ljava/lang/Thread$Builder$OfVirtual;"
+// Sets the name of the threads the builder creates and returns the builder
+func virtualThreadBuilderName(params []interface{}) interface{} {
+	builder := params[0].(*object.Object)
+	name, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(name) {
+		return getGErrBlk(excNames.NullPointerException, "virtualThreadBuilderName: name is null")
+	}
+	builder.FieldTable["name"] = object.Field{Ftype: types.GolangString,
+		Fvalue: object.GoStringFromStringObject(name)}
+	return builder
+}
+
+// "java/lang/ThreadBuilders$VirtualThreadBuilder.start(Ljava/lang/Runnable;)Ljava/lang/Thread;"
+func virtualThreadBuilderStart(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	t := virtualThreadBuilderUnstarted(params[1:])
+	if errBlk, ok := t.(*GErrBlk); ok {
+		return errBlk
+	}
+	if ret := threadStart([]interface{}{fs, t}); ret != nil {
+		return ret
+	}
+	return t
+}
+
+// "java/lang/ThreadBuilders$VirtualThreadBuilder.unstarted(Ljava/lang/Runnable;)Ljava/lang/Thread;"
+// Creates a virtual thread that will run the Runnable once it is started
+func virtualThreadBuilderUnstarted(params []interface{}) interface{} {
+	builder := params[0].(*object.Object)
+	runnable, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(runnable) {
+		return getGErrBlk(excNames.NullPointerException, "virtualThreadBuilderUnstarted: task is null")
+	}
+
+	t := threadCreateNoarg(nil).(*object.Object)
+	t.FieldTable["name"] = builder.FieldTable["name"]
+	t.FieldTable["daemon"] = object.Field{Ftype: types.Int, Fvalue: types.JavaBoolTrue} // virtual threads are daemons
+	t.FieldTable["virtual"] = object.Field{Ftype: types.Bool, Fvalue: types.JavaBoolTrue}
+	t.FieldTable["target"] = object.Field{Ftype: types.Ref, Fvalue: runnable}
+	return t
+}
+
 // "java/lang/Thread.sleep(J)V"
+// An interrupt of the current thread, whether before or during the sleep, ends
+// the sleep with an InterruptedException and clears the interrupt status.
 func threadSleep(params []interface{}) interface{} {
-	sleepTime, ok := params[0].(int64)
+	sleepTime, ok := params[1].(int64)
 	if !ok {
 		errMsg := "threadSleep: Parameter must be an int64 (long)"
 		return getGErrBlk(excNames.IOException, errMsg)
 	}
-	time.Sleep(time.Duration(sleepTime) * time.Millisecond)
+	if sleepTime < 0 {
+		errMsg := "threadSleep: timeout value is negative"
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+
+	th := currentExecThread(params[0].(*list.List))
+	if th == nil { // no thread to interrupt, so just sleep
+		time.Sleep(time.Duration(sleepTime) * time.Millisecond)
+		return nil
+	}
+	if th.Sleep(time.Duration(sleepTime) * time.Millisecond) {
+		return getGErrBlk(excNames.InterruptedException, "sleep interrupted")
+	}
 	return nil
 }
 
+// execThreadOf returns the running ExecThread that corresponds to a Thread
+// object, or nil if the thread is not running
+func execThreadOf(t *object.Object) *thread.ExecThread {
+	if object.IsNull(t) {
+		return nil
+	}
+	id, ok := t.FieldTable["ID"].Fvalue.(int64)
+	if !ok {
+		return nil
+	}
+
+	glob := globals.GetGlobalRef()
+	glob.ThreadLock.Lock()
+	defer glob.ThreadLock.Unlock()
+	th, _ := glob.Threads[int(id)].(*thread.ExecThread)
+	return th
+}
+
 func cloneNotSupportedException(params []interface{}) interface{} {
 	errMsg := "cloneNotSupportedException: Not supported for threads"
 	return getGErrBlk(excNames.CloneNotSupportedException, errMsg)
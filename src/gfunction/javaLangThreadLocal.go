@@ -0,0 +1,262 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"jacobin/src/thread"
+	"jacobin/src/types"
+)
+
+// Implementation of java/lang/ThreadLocal and java/lang/InheritableThreadLocal.
+// The values are not held in the ThreadLocal object, but in the ExecThread of the
+// thread that calls get() and set(), in a map keyed by the ThreadLocal object. So,
+// each thread sees only the values that it set (or that it inherited from the thread
+// that started it, in the case of InheritableThreadLocals; see
+// thread.InheritThreadLocals, which runs their childValue() methods).
+//
+// The initial value of a ThreadLocal is obtained, on the first get() in a thread,
+// from the Supplier passed to withInitial() or, for subclasses of ThreadLocal, from
+// their initialValue() method. Both are Java methods, so they are called through
+// the upcall bridge.
+
+const (
+	threadLocalClassName            = "java/lang/ThreadLocal"
+	inheritableThreadLocalClassName = "java/lang/InheritableThreadLocal"
+	threadLocalSupplierField        = "supplier" // holds the Supplier passed to withInitial()
+)
+
+func Load_Lang_ThreadLocal() {
+
+	MethodSignatures["java/lang/ThreadLocal.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/lang/ThreadLocal.<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  justReturn,
+		}
+
+	MethodSignatures["java/lang/ThreadLocal.get()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    threadLocalGet,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/ThreadLocal.initialValue()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  threadLocalInitialValue,
+		}
+
+	MethodSignatures["java/lang/ThreadLocal.remove()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    threadLocalRemove,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/ThreadLocal.set(Ljava/lang/Object;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    threadLocalSet,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/ThreadLocal.withInitial(Ljava/util/function/Supplier;)Ljava/lang/ThreadLocal;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  threadLocalWithInitial,
+		}
+
+	// InheritableThreadLocal extends ThreadLocal. Its methods are listed here so
+	// that calls made through an InheritableThreadLocal reference find them.
+
+	MethodSignatures["java/lang/InheritableThreadLocal.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/lang/InheritableThreadLocal.<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  justReturn,
+		}
+
+	MethodSignatures["java/lang/InheritableThreadLocal.childValue(Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  inheritableThreadLocalChildValue,
+		}
+
+	MethodSignatures["java/lang/InheritableThreadLocal.get()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    threadLocalGet,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/InheritableThreadLocal.remove()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    threadLocalRemove,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/InheritableThreadLocal.set(Ljava/lang/Object;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    threadLocalSet,
+			NeedsContext: true,
+		}
+}
+
+// java/lang/ThreadLocal.get()Ljava/lang/Object;
+// If the current thread has no value for this ThreadLocal, the initial value
+// is computed, stored, and returned.
+func threadLocalGet(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	this := params[1].(*object.Object)
+
+	locals, errBlk := threadLocalMap(fs, this)
+	if errBlk != nil {
+		return errBlk
+	}
+	if value, ok := locals[this]; ok {
+		return value
+	}
+
+	value, errBlk := threadLocalComputeInitialValue(fs, this)
+	if errBlk != nil {
+		return errBlk
+	}
+	locals[this] = value
+	return value
+}
+
+// java/lang/ThreadLocal.set(Ljava/lang/Object;)V
+func threadLocalSet(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	this := params[1].(*object.Object)
+
+	locals, errBlk := threadLocalMap(fs, this)
+	if errBlk != nil {
+		return errBlk
+	}
+	locals[this] = params[2]
+	return nil
+}
+
+// java/lang/ThreadLocal.remove()V
+// A subsequent get() in the same thread computes the initial value anew.
+func threadLocalRemove(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	this := params[1].(*object.Object)
+
+	locals, errBlk := threadLocalMap(fs, this)
+	if errBlk != nil {
+		return errBlk
+	}
+	delete(locals, this)
+	return nil
+}
+
+// java/lang/ThreadLocal.initialValue()Ljava/lang/Object;
+// The default initial value is null. Subclasses override this method.
+func threadLocalInitialValue(_ []interface{}) interface{} {
+	return object.Null
+}
+
+// java/lang/ThreadLocal.withInitial(Ljava/util/function/Supplier;)Ljava/lang/ThreadLocal;
+// Returns a ThreadLocal whose initial value is obtained from the supplier.
+func threadLocalWithInitial(params []interface{}) interface{} {
+	supplier, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(supplier) {
+		errMsg := "ThreadLocal.withInitial(): supplier is null"
+		return getGErrBlk(excNames.NullPointerException, errMsg)
+	}
+
+	className := threadLocalClassName
+	tl := object.MakeEmptyObjectWithClassName(&className)
+	tl.FieldTable[threadLocalSupplierField] = object.Field{Ftype: types.Ref, Fvalue: supplier}
+	return tl
+}
+
+// java/lang/InheritableThreadLocal.childValue(Ljava/lang/Object;)Ljava/lang/Object;
+// The child thread's value is the parent's value.
+func inheritableThreadLocalChildValue(params []interface{}) interface{} {
+	return params[1]
+}
+
+// threadLocalMap returns the map that holds the values of ThreadLocals of the kind
+// of tl (inheritable or not) for the thread running on the frame stack fs,
+// creating the map if needed.
+func threadLocalMap(fs *list.List, tl *object.Object) (map[any]any, *GErrBlk) {
+	th := currentExecThread(fs)
+	if th == nil {
+		errMsg := "ThreadLocal: could not find the current thread"
+		return nil, getGErrBlk(excNames.IllegalStateException, errMsg)
+	}
+
+	className := *stringPool.GetStringPointer(tl.KlassName)
+	if classloader.IsSubclassOf(className, inheritableThreadLocalClassName) {
+		if th.InheritableLocals == nil {
+			th.InheritableLocals = make(map[any]any)
+		}
+		return th.InheritableLocals, nil
+	}
+
+	if th.ThreadLocals == nil {
+		th.ThreadLocals = make(map[any]any)
+	}
+	return th.ThreadLocals, nil
+}
+
+// threadLocalComputeInitialValue obtains the initial value of tl in the current
+// thread: from the supplier passed to withInitial(), if any; otherwise, from the
+// initialValue() method of a user subclass of ThreadLocal; otherwise, null.
+func threadLocalComputeInitialValue(fs *list.List, tl *object.Object) (any, *GErrBlk) {
+	if field, ok := tl.FieldTable[threadLocalSupplierField]; ok {
+		return invokeJavaMethod(fs, "java/util/function/Supplier", "get", "()Ljava/lang/Object;",
+			field.Fvalue, nil)
+	}
+
+	className := *stringPool.GetStringPointer(tl.KlassName)
+	if className == threadLocalClassName || className == inheritableThreadLocalClassName {
+		return object.Null, nil
+	}
+	return invokeJavaMethod(fs, className, "initialValue", "()Ljava/lang/Object;", tl, nil)
+}
+
+// currentExecThread returns the ExecThread running on the frame stack fs, or
+// nil if it cannot be found in the thread table.
+func currentExecThread(fs *list.List) *thread.ExecThread {
+	if fs == nil || fs.Len() == 0 {
+		return nil
+	}
+	threadID := fs.Front().Value.(*frames.Frame).Thread
+
+	glob := globals.GetGlobalRef()
+	glob.ThreadLock.Lock()
+	defer glob.ThreadLock.Unlock()
+	th, ok := glob.Threads[threadID].(*thread.ExecThread)
+	if !ok {
+		return nil
+	}
+	return th
+}
@@ -0,0 +1,156 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"errors"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/exceptions"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"testing"
+)
+
+func TestThreadLocalSetGetRemove(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	_, fs := addTestExecThread()
+
+	className := threadLocalClassName
+	tl := object.MakeEmptyObjectWithClassName(&className)
+
+	if ret := threadLocalGet([]interface{}{fs, tl}); !object.IsNull(ret) {
+		t.Errorf("Expected the initial value to be null, got: %v", ret)
+	}
+
+	threadLocalSet([]interface{}{fs, tl, int64(42)})
+	if ret := threadLocalGet([]interface{}{fs, tl}); ret != int64(42) {
+		t.Errorf("Expected 42, got: %v", ret)
+	}
+
+	threadLocalRemove([]interface{}{fs, tl})
+	if ret := threadLocalGet([]interface{}{fs, tl}); !object.IsNull(ret) {
+		t.Errorf("Expected null after remove(), got: %v", ret)
+	}
+}
+
+func TestThreadLocalIsPerThread(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	th1, fs1 := addTestExecThread()
+	_, fs2 := addTestExecThread()
+
+	className := threadLocalClassName
+	tl := object.MakeEmptyObjectWithClassName(&className)
+	threadLocalSet([]interface{}{fs1, tl, int64(1)})
+	threadLocalSet([]interface{}{fs2, tl, int64(2)})
+
+	if ret := threadLocalGet([]interface{}{fs1, tl}); ret != int64(1) {
+		t.Errorf("Expected 1 in the first thread, got: %v", ret)
+	}
+	if ret := threadLocalGet([]interface{}{fs2, tl}); ret != int64(2) {
+		t.Errorf("Expected 2 in the second thread, got: %v", ret)
+	}
+	if th1.ThreadLocals[tl] != int64(1) {
+		t.Error("Expected the value to be stored in the thread's ThreadLocals")
+	}
+}
+
+func TestInheritableThreadLocalIsInherited(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	parent, fs := addTestExecThread()
+
+	plainName := threadLocalClassName
+	inheritableName := inheritableThreadLocalClassName
+	plain := object.MakeEmptyObjectWithClassName(&plainName)
+	inheritable := object.MakeEmptyObjectWithClassName(&inheritableName)
+	threadLocalSet([]interface{}{fs, plain, int64(1)})
+	threadLocalSet([]interface{}{fs, inheritable, int64(2)})
+
+	child, childFs := addTestExecThread()
+	_ = child.InheritThreadLocals(parent, nil)
+
+	if ret := threadLocalGet([]interface{}{childFs, inheritable}); ret != int64(2) {
+		t.Errorf("Expected the child to inherit 2, got: %v", ret)
+	}
+	if ret := threadLocalGet([]interface{}{childFs, plain}); !object.IsNull(ret) {
+		t.Errorf("Expected the child not to inherit an ordinary ThreadLocal, got: %v", ret)
+	}
+}
+
+func TestThreadLocalWithInitial(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	_, fs := addTestExecThread()
+
+	supplierClass := "pkg/Counter"
+	supplier := object.MakeEmptyObjectWithClassName(&supplierClass)
+	calls := 0
+	globals.GetGlobalRef().FuncInvokeJavaMethod =
+		func(_ *list.List, className, methName, methType string, receiver any, _ []any) (any, error) {
+			if className != "java/util/function/Supplier" || methName != "get" || receiver != supplier {
+				t.Errorf("Unexpected upcall: %s.%s%s", className, methName, methType)
+			}
+			calls++
+			return int64(100 + calls), nil
+		}
+
+	tl := threadLocalWithInitial([]interface{}{supplier}).(*object.Object)
+	if ret := threadLocalGet([]interface{}{fs, tl}); ret != int64(101) {
+		t.Errorf("Expected 101 from the supplier, got: %v", ret)
+	}
+	if ret := threadLocalGet([]interface{}{fs, tl}); ret != int64(101) || calls != 1 {
+		t.Errorf("Expected the supplier to be called only once, got %v after %d calls", ret, calls)
+	}
+
+	threadLocalRemove([]interface{}{fs, tl})
+	if ret := threadLocalGet([]interface{}{fs, tl}); ret != int64(102) {
+		t.Errorf("Expected the supplier to be called again after remove(), got: %v", ret)
+	}
+
+	if errBlk, ok := threadLocalWithInitial([]interface{}{object.Null}).(*GErrBlk); !ok ||
+		errBlk.ExceptionType != excNames.NullPointerException {
+		t.Error("Expected a NullPointerException for a null supplier")
+	}
+}
+
+func TestThreadLocalSupplierException(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	_, fs := addTestExecThread()
+
+	supplierClass := "pkg/Failing"
+	supplier := object.MakeEmptyObjectWithClassName(&supplierClass)
+	globals.GetGlobalRef().FuncInvokeJavaMethod =
+		func(*list.List, string, string, string, any, []any) (any, error) {
+			return nil, &exceptions.UpcallError{Method: "pkg/Failing.get()Ljava/lang/Object;",
+				Cause: "java.lang.IllegalStateException: not ready"}
+		}
+
+	tl := threadLocalWithInitial([]interface{}{supplier}).(*object.Object)
+	errBlk, ok := threadLocalGet([]interface{}{fs, tl}).(*GErrBlk)
+	if !ok {
+		t.Fatal("Expected the supplier's exception to be returned as an error block")
+	}
+	if errBlk.ExceptionType != excNames.IllegalStateException || errBlk.ErrMsg != "not ready" {
+		t.Errorf("Expected IllegalStateException: not ready, got %s: %s",
+			excNames.JVMexceptionNames[errBlk.ExceptionType], errBlk.ErrMsg)
+	}
+
+	// the failed get() must not have stored a value
+	globals.GetGlobalRef().FuncInvokeJavaMethod =
+		func(*list.List, string, string, string, any, []any) (any, error) {
+			return nil, errors.New("bridge failure")
+		}
+	if errBlk, ok = threadLocalGet([]interface{}{fs, tl}).(*GErrBlk); !ok ||
+		errBlk.ExceptionType != excNames.InternalException {
+		t.Error("Expected a non-Java error from the bridge to be reported as an InternalException")
+	}
+}
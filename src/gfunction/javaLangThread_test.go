@@ -0,0 +1,245 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/excNames"
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/thread"
+	"jacobin/src/types"
+	"testing"
+	"time"
+)
+
+// creates a thread, adds it to the thread table, and returns it along with
+// a frame stack whose frame runs on that thread
+func addTestExecThread() (*thread.ExecThread, *list.List) {
+	th := thread.CreateThread()
+	th.AddThreadToTable(globals.GetGlobalRef())
+
+	fs := frames.CreateFrameStack()
+	f := frames.CreateFrame(2)
+	f.Thread = th.ID
+	_ = frames.PushFrame(fs, f)
+	return &th, fs
+}
+
+func TestThreadSleepInterrupted(t *testing.T) {
+	globals.InitGlobals("test")
+	th, fs := addTestExecThread()
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		th.Interrupt()
+	}()
+	start := time.Now()
+	ret := threadSleep([]interface{}{fs, int64(10000)})
+	errBlk, ok := ret.(*GErrBlk)
+	if !ok || errBlk.ExceptionType != excNames.InterruptedException {
+		t.Fatalf("Expected an InterruptedException, got: %v", ret)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("The interrupt did not end the sleep")
+	}
+	if th.IsInterrupted() {
+		t.Error("Expected the interrupt status to be cleared by the exception")
+	}
+
+	if ret = threadSleep([]interface{}{fs, int64(1)}); ret != nil {
+		t.Errorf("Expected an uninterrupted sleep to return nil, got: %v", ret)
+	}
+	if errBlk, ok = threadSleep([]interface{}{fs, int64(-1)}).(*GErrBlk); !ok ||
+		errBlk.ExceptionType != excNames.IllegalArgumentException {
+		t.Error("Expected an IllegalArgumentException for a negative sleep time")
+	}
+}
+
+func TestThreadInterruptAndInterrupted(t *testing.T) {
+	globals.InitGlobals("test")
+	th, fs := addTestExecThread()
+
+	current, ok := threadCurrentThread([]interface{}{fs}).(*object.Object)
+	if !ok {
+		t.Fatal("Expected currentThread() to return a Thread object")
+	}
+	if threadCurrentThread([]interface{}{fs}) != current {
+		t.Error("Expected currentThread() to return the same object each time")
+	}
+
+	threadInterrupt([]interface{}{current})
+	if !th.IsInterrupted() || threadIsInterrupted([]interface{}{current}) != types.JavaBoolTrue {
+		t.Error("Expected interrupt() to set the thread's interrupt status")
+	}
+	if threadInterrupted([]interface{}{fs}) != types.JavaBoolTrue {
+		t.Error("Expected interrupted() to return true")
+	}
+	if threadInterrupted([]interface{}{fs}) != types.JavaBoolFalse {
+		t.Error("Expected interrupted() to have cleared the interrupt status")
+	}
+}
+
+func TestThreadJoin(t *testing.T) {
+	globals.InitGlobals("test")
+	_, fs := addTestExecThread()
+	target, targetFs := addTestExecThread()
+	targetObj := threadCurrentThread([]interface{}{targetFs}).(*object.Object)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		target.Terminate()
+	}()
+	if ret := threadJoin([]interface{}{fs, targetObj}); ret != nil {
+		t.Errorf("Expected join() to return once the thread ended, got: %v", ret)
+	}
+
+	// joining a thread that was never started returns at once
+	notStarted := threadCreateNoarg(nil).(*object.Object)
+	if ret := threadJoin([]interface{}{fs, notStarted, int64(0)}); ret != nil {
+		t.Errorf("Expected join() of an unstarted thread to return nil, got: %v", ret)
+	}
+}
+
+func TestVirtualThreadBuilder(t *testing.T) {
+	globals.InitGlobals("test")
+	runnableClass := "pkg/Task"
+	runnable := object.MakeEmptyObjectWithClassName(&runnableClass)
+
+	builder := threadOfVirtual(nil)
+	builder = virtualThreadBuilderName([]interface{}{builder, object.StringObjectFromGoString("worker")})
+	th, ok := virtualThreadBuilderUnstarted([]interface{}{builder, runnable}).(*object.Object)
+	if !ok {
+		t.Fatal("Expected unstarted() to return a Thread")
+	}
+	if th.FieldTable["name"].Fvalue != "worker" {
+		t.Errorf("Expected the thread to be named worker, got: %v", th.FieldTable["name"].Fvalue)
+	}
+	if th.FieldTable["target"].Fvalue != runnable {
+		t.Error("Expected the thread's target to be the Runnable")
+	}
+	if threadIsVirtual([]interface{}{th}) != types.JavaBoolTrue {
+		t.Error("Expected isVirtual() to return true for a virtual thread")
+	}
+	if threadIsVirtual([]interface{}{threadCreateNoarg(nil)}) != types.JavaBoolFalse {
+		t.Error("Expected isVirtual() to return false for a platform thread")
+	}
+
+	if errBlk, ok := virtualThreadBuilderUnstarted([]interface{}{builder, object.Null}).(*GErrBlk); !ok ||
+		errBlk.ExceptionType != excNames.NullPointerException {
+		t.Error("Expected a NullPointerException for a null task")
+	}
+}
+
+func TestThreadStartTwice(t *testing.T) {
+	globals.InitGlobals("test")
+	_, fs := addTestExecThread()
+	var startedThreads []any
+	globals.GetGlobalRef().FuncStartThread = func(_ *list.List, threadObj any) error {
+		startedThreads = append(startedThreads, threadObj)
+		return nil
+	}
+
+	runnableClass := "pkg/Task"
+	runnable := object.MakeEmptyObjectWithClassName(&runnableClass)
+	th, ok := threadStartVirtualThread([]interface{}{fs, runnable}).(*object.Object)
+	if !ok {
+		t.Fatal("Expected startVirtualThread() to return a Thread")
+	}
+	if len(startedThreads) != 1 || startedThreads[0] != th {
+		t.Fatalf("Expected the thread to be started once, got %d starts", len(startedThreads))
+	}
+
+	errBlk, ok := threadStart([]interface{}{fs, th}).(*GErrBlk)
+	if !ok || errBlk.ExceptionType != excNames.IllegalThreadStateException {
+		t.Error("Expected an IllegalThreadStateException when starting a thread twice")
+	}
+	if len(startedThreads) != 1 {
+		t.Error("Expected the second start() not to start the thread again")
+	}
+}
+
+func TestUncaughtExceptionHandlers(t *testing.T) {
+	globals.InitGlobals("test")
+	defer defaultUncaughtHandler.Store(nil)
+	handlerClass := "pkg/Handler"
+	defaultHandler := object.MakeEmptyObjectWithClassName(&handlerClass)
+	ownHandler := object.MakeEmptyObjectWithClassName(&handlerClass)
+	th := threadCreateNoarg(nil).(*object.Object)
+
+	if UncaughtExceptionHandlerOf(th) != nil || threadGetDefaultUncaughtExceptionHandler(nil) != object.Null {
+		t.Errorf("Expected no handlers at first")
+	}
+
+	threadSetDefaultUncaughtExceptionHandler([]interface{}{defaultHandler})
+	if UncaughtExceptionHandlerOf(th) != defaultHandler || threadGetDefaultUncaughtExceptionHandler(nil) != defaultHandler {
+		t.Errorf("Expected the default handler to apply to a thread with no handler of its own")
+	}
+	if threadGetUncaughtExceptionHandler([]interface{}{th}) != object.Null {
+		t.Errorf("Expected the thread to have no handler of its own")
+	}
+
+	// the thread's own handler takes precedence, until it's removed
+	threadSetUncaughtExceptionHandler([]interface{}{th, ownHandler})
+	if UncaughtExceptionHandlerOf(th) != ownHandler || threadGetUncaughtExceptionHandler([]interface{}{th}) != ownHandler {
+		t.Errorf("Expected the thread's own handler to apply")
+	}
+	threadSetUncaughtExceptionHandler([]interface{}{th, object.Null})
+	if UncaughtExceptionHandlerOf(th) != defaultHandler {
+		t.Errorf("Expected the default handler to apply once the thread's handler is removed")
+	}
+
+	threadSetDefaultUncaughtExceptionHandler([]interface{}{object.Null})
+	if UncaughtExceptionHandlerOf(th) != nil {
+		t.Errorf("Expected no handler once the default handler is removed")
+	}
+}
+
+func TestThreadInitWithRunnable(t *testing.T) {
+	globals.InitGlobals("test")
+	runnableClass := "pkg/Task"
+	runnable := object.MakeEmptyObjectWithClassName(&runnableClass)
+
+	th := object.MakeEmptyObjectWithClassName(&classname)
+	if ret := threadInitRunnable([]interface{}{th, runnable}); ret != nil {
+		t.Fatalf("Unexpected error: %v", ret)
+	}
+	if th.FieldTable["target"].Fvalue != runnable {
+		t.Error("Expected Thread(Runnable) to set the thread's target")
+	}
+	if name, _ := th.FieldTable["name"].Fvalue.(string); len(name) < 8 || name[:7] != "Thread-" {
+		t.Errorf("Expected a generated name, got %q", name)
+	}
+
+	th = object.MakeEmptyObjectWithClassName(&classname)
+	name := object.StringObjectFromGoString("worker")
+	ret := threadInitGroupRunnable([]interface{}{th, object.Null, runnable, name, int64(0), types.JavaBoolFalse})
+	if ret != nil {
+		t.Fatalf("Unexpected error: %v", ret)
+	}
+	if th.FieldTable["target"].Fvalue != runnable || th.FieldTable["name"].Fvalue != "worker" {
+		t.Error("Expected Thread(ThreadGroup, Runnable, String, long, boolean) to set the target and name")
+	}
+	if th.FieldTable["inheritThreadLocals"].Fvalue != types.JavaBoolFalse {
+		t.Error("Expected the thread not to inherit the InheritableThreadLocals")
+	}
+
+	// a subclass of Thread that overrides run() passes a null Runnable
+	th = object.MakeEmptyObjectWithClassName(&classname)
+	if ret := threadInitRunnable([]interface{}{th, object.Null, name}); ret != nil {
+		t.Fatalf("Unexpected error: %v", ret)
+	}
+	if _, ok := th.FieldTable["target"]; ok {
+		t.Error("Expected no target for a null Runnable")
+	}
+
+	ret = threadInitRunnable([]interface{}{th, runnable, object.Null})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Error("Expected a NullPointerException for a null name")
+	}
+}
@@ -123,7 +123,7 @@ func Load_Math_Big_Decimal() {
 	MethodSignatures[classNameBigDecimal+".add(Ljava/math/BigDecimal;Ljava/math/MathContext;)Ljava/math/BigDecimal;"] =
 		GMeth{
 			ParamSlots: 2,
-			GFunction:  trapFunction,
+			GFunction:  bigdecimalAddMathContext,
 		}
 
 	MethodSignatures[classNameBigDecimal+".byteValueExact()B"] =
@@ -147,31 +147,31 @@ func Load_Math_Big_Decimal() {
 	MethodSignatures[classNameBigDecimal+".divide(Ljava/math/BigDecimal;I)Ljava/math/BigDecimal;"] = // Deprecated
 		GMeth{
 			ParamSlots: 2,
-			GFunction:  trapDeprecated,
+			GFunction:  bigdecimalDivideRounding,
 		}
 
 	MethodSignatures[classNameBigDecimal+".divide(Ljava/math/BigDecimal;II)Ljava/math/BigDecimal;"] = // Deprecated
 		GMeth{
 			ParamSlots: 3,
-			GFunction:  trapDeprecated,
+			GFunction:  bigdecimalDivideRounding,
 		}
 
-	MethodSignatures[classNameBigDecimal+".divide(Ljava/math/BigDecimal;ILjava/math/RoundingMode;)Ljava/math/BigDecimal;"] = // Deprecated
+	MethodSignatures[classNameBigDecimal+".divide(Ljava/math/BigDecimal;ILjava/math/RoundingMode;)Ljava/math/BigDecimal;"] =
 		GMeth{
 			ParamSlots: 3,
-			GFunction:  trapFunction,
+			GFunction:  bigdecimalDivideRounding,
 		}
 
 	MethodSignatures[classNameBigDecimal+".divide(Ljava/math/BigDecimal;Ljava/math/MathContext;)Ljava/math/BigDecimal;"] =
 		GMeth{
 			ParamSlots: 2,
-			GFunction:  trapFunction,
+			GFunction:  bigdecimalDivideMathContext,
 		}
 
 	MethodSignatures[classNameBigDecimal+".divide(Ljava/math/BigDecimal;Ljava/math/RoundingMode;)Ljava/math/BigDecimal;"] =
 		GMeth{
 			ParamSlots: 2,
-			GFunction:  trapFunction,
+			GFunction:  bigdecimalDivideRounding,
 		}
 
 	MethodSignatures[classNameBigDecimal+".divideAndRemainder(Ljava/math/BigDecimal;)[Ljava/math/BigDecimal;"] =
@@ -279,7 +279,7 @@ func Load_Math_Big_Decimal() {
 	MethodSignatures[classNameBigDecimal+".multiply(Ljava/math/BigDecimal;Ljava/math/MathContext;)Ljava/math/BigDecimal;"] =
 		GMeth{
 			ParamSlots: 2,
-			GFunction:  trapFunction,
+			GFunction:  bigdecimalMultiplyMathContext,
 		}
 
 	MethodSignatures[classNameBigDecimal+".negate()Ljava/math/BigDecimal;"] =
@@ -291,7 +291,7 @@ func Load_Math_Big_Decimal() {
 	MethodSignatures[classNameBigDecimal+".negate(Ljava/math/MathContext;)Ljava/math/BigDecimal;"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  trapFunction,
+			GFunction:  bigdecimalNegateMathContext,
 		}
 
 	MethodSignatures[classNameBigDecimal+".plus()Ljava/math/BigDecimal;"] =
@@ -303,7 +303,7 @@ func Load_Math_Big_Decimal() {
 	MethodSignatures[classNameBigDecimal+".plus(Ljava/math/MathContext;)Ljava/math/BigDecimal;"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  trapFunction,
+			GFunction:  bigdecimalRound,
 		}
 
 	MethodSignatures[classNameBigDecimal+".pow(I)Ljava/math/BigDecimal;"] =
@@ -339,7 +339,7 @@ func Load_Math_Big_Decimal() {
 	MethodSignatures[classNameBigDecimal+".round(Ljava/math/MathContext;)Ljava/math/BigDecimal;"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  trapFunction,
+			GFunction:  bigdecimalRound,
 		}
 
 	MethodSignatures[classNameBigDecimal+".scale()I"] =
@@ -363,7 +363,13 @@ func Load_Math_Big_Decimal() {
 	MethodSignatures[classNameBigDecimal+".setScale(II)Ljava/math/BigDecimal;"] =
 		GMeth{
 			ParamSlots: 2,
-			GFunction:  trapDeprecated,
+			GFunction:  bigdecimalSetScaleRounding,
+		}
+
+	MethodSignatures[classNameBigDecimal+".setScale(ILjava/math/RoundingMode;)Ljava/math/BigDecimal;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  bigdecimalSetScaleRounding,
 		}
 
 	MethodSignatures[classNameBigDecimal+".shortValueExact()S"] =
@@ -399,7 +405,7 @@ func Load_Math_Big_Decimal() {
 	MethodSignatures[classNameBigDecimal+".subtract(Ljava/math/BigDecimal;Ljava/math/MathContext;)Ljava/math/BigDecimal;"] =
 		GMeth{
 			ParamSlots: 2,
-			GFunction:  trapFunction,
+			GFunction:  bigdecimalSubtractMathContext,
 		}
 
 	MethodSignatures[classNameBigDecimal+".toBigInteger()Ljava/math/BigInteger;"] =
@@ -27,29 +27,25 @@ func bigdecimalAbs(params []interface{}) interface{} {
 	return bigDecimalObjectFromBigInt(absVal, int64(len(absVal.String())), bd.FieldTable["scale"].Fvalue.(int64))
 }
 
-// bigdecimalAdd returns the result of adding this BigDecimal to the specified one
+// bigdecimalAdd returns the result of adding this BigDecimal to the specified one.
+// The result's scale is the larger of the two scales.
 func bigdecimalAdd(params []interface{}) interface{} {
-	// Extract BigDecimal objects
-	bd1 := params[0].(*object.Object)
-	bd2 := params[1].(*object.Object)
+	val1, scale1 := bigDecimalComponents(params[0].(*object.Object))
+	val2, scale2 := bigDecimalComponents(params[1].(*object.Object))
 
-	// Extract BigInteger intVal fields
-	intVal1 := bd1.FieldTable["intVal"].Fvalue.(*object.Object)
-	intVal2 := bd2.FieldTable["intVal"].Fvalue.(*object.Object)
+	// Bring both values to the same scale and add them
+	aligned1, aligned2, scale := alignScales(val1, scale1, val2, scale2)
+	bigInt := new(big.Int).Add(aligned1, aligned2)
 
-	// Convert BigInteger objects to big.Int
-	val1 := intVal1.FieldTable["value"].Fvalue.(*big.Int)
-	val2 := intVal2.FieldTable["value"].Fvalue.(*big.Int)
-
-	// Perform the addition
-	bigInt := new(big.Int).Add(val1, val2)
-
-	// Calculate the precision and scale (same scale as the first BigDecimal)
-	precision := precisionFromBigInt(bigInt)
-	scale := bd1.FieldTable["scale"].Fvalue.(int64)
+	return makeBigDecimal(bigInt, scale)
+}
 
-	// Create a new BigDecimal object with the result
-	return bigDecimalObjectFromBigInt(bigInt, precision, scale)
+// bigdecimalAddMathContext adds the two BigDecimals and rounds the sum per the MathContext
+func bigdecimalAddMathContext(params []interface{}) interface{} {
+	val1, scale1 := bigDecimalComponents(params[0].(*object.Object))
+	val2, scale2 := bigDecimalComponents(params[1].(*object.Object))
+	aligned1, aligned2, scale := alignScales(val1, scale1, val2, scale2)
+	return roundBigDecimal("bigdecimalAddMathContext", new(big.Int).Add(aligned1, aligned2), scale, params[2])
 }
 
 // bigdecimalByteValueExact returns the exact byte value of this BigDecimal
@@ -77,47 +73,83 @@ func bigdecimalByteValueExact(params []interface{}) interface{} {
 // Returns a negative integer if this BigDecimal is less than the specified BigDecimal,
 // zero if they are equal, and a positive integer if this BigDecimal is greater.
 func bigdecimalCompareTo(params []interface{}) interface{} {
-	// Extract BigDecimal objects
-	bd1 := params[0].(*object.Object)
-	bd2 := params[1].(*object.Object)
+	val1, scale1 := bigDecimalComponents(params[0].(*object.Object))
+	val2, scale2 := bigDecimalComponents(params[1].(*object.Object))
 
-	// Extract BigInteger intVal fields
-	intVal1 := bd1.FieldTable["intVal"].Fvalue.(*object.Object)
-	intVal2 := bd2.FieldTable["intVal"].Fvalue.(*object.Object)
-
-	// Convert BigInteger objects to big.Int
-	val1 := intVal1.FieldTable["value"].Fvalue.(*big.Int)
-	val2 := intVal2.FieldTable["value"].Fvalue.(*big.Int)
-
-	// Compare the two values
-	return int64(val1.Cmp(val2))
+	// Compare the values at a common scale, so that 2.0 and 2.00 are equal
+	aligned1, aligned2, _ := alignScales(val1, scale1, val2, scale2)
+	return int64(aligned1.Cmp(aligned2))
 }
 
-// bigdecimalDivide returns the result of dividing this BigDecimal by the specified one
+// bigdecimalDivide returns the exact quotient of this BigDecimal and the specified one.
+// Its preferred scale is this.scale() - divisor.scale(); if the quotient has a
+// non-terminating decimal expansion, an ArithmeticException is thrown.
 func bigdecimalDivide(params []interface{}) interface{} {
-	dividend := params[0].(*object.Object)
-	divisor := params[1].(*object.Object)
+	dvBigInt, dvScale := bigDecimalComponents(params[0].(*object.Object))
+	drBigInt, drScale := bigDecimalComponents(params[1].(*object.Object))
 
-	// Extract BigInteger intVal fields
-	dv := dividend.FieldTable["intVal"].Fvalue.(*object.Object)
-	dr := divisor.FieldTable["intVal"].Fvalue.(*object.Object)
+	// Check for division by zero
+	if gerr := checkBigDecimalDivisor(dvBigInt, drBigInt); gerr != nil {
+		return gerr
+	}
 
-	// Convert BigInteger objects to big.Int
-	dvBigInt := dv.FieldTable["value"].Fvalue.(*big.Int)
-	drBigInt := dr.FieldTable["value"].Fvalue.(*big.Int)
+	quotient, scale, ok := divideExact(dvBigInt, dvScale, drBigInt, drScale)
+	if !ok {
+		return getGErrBlk(excNames.ArithmeticException,
+			"Non-terminating decimal expansion; no exact representable decimal result.")
+	}
+	return makeBigDecimal(quotient, scale)
+}
 
-	// Check for division by zero
-	if drBigInt.Sign() == 0 {
-		return getGErrBlk(excNames.ArithmeticException, "bigdecimalDivide: divide by zero")
+// bigdecimalDivideMathContext returns the quotient rounded per the MathContext.
+// A precision of 0 asks for the exact quotient.
+func bigdecimalDivideMathContext(params []interface{}) interface{} {
+	dvBigInt, dvScale := bigDecimalComponents(params[0].(*object.Object))
+	drBigInt, drScale := bigDecimalComponents(params[1].(*object.Object))
+	precision, mode, gerr := getMathContext("bigdecimalDivideMathContext", params[2])
+	if gerr != nil {
+		return gerr
+	}
+	if precision == 0 {
+		return bigdecimalDivide(params[:2])
 	}
 
-	// Do integer division
-	quotient := new(big.Int).Div(dvBigInt, drBigInt)
+	if gerr := checkBigDecimalDivisor(dvBigInt, drBigInt); gerr != nil {
+		return gerr
+	}
+	quotient, scale, ok := divideToPrecision(dvBigInt, dvScale, drBigInt, drScale, precision, mode)
+	if !ok {
+		return getGErrBlk(excNames.ArithmeticException, "Rounding necessary")
+	}
+	return makeBigDecimal(quotient, scale)
+}
 
-	// Create result BigDecimal object
-	result := bigDecimalObjectFromBigInt(quotient, int64(len(quotient.String())), int64(0))
+// bigdecimalDivideRounding implements the divide() variants that take a rounding mode, either
+// a RoundingMode or a legacy ROUND_* int, and optionally a scale. Without a scale, the
+// quotient has the scale of this BigDecimal.
+func bigdecimalDivideRounding(params []interface{}) interface{} {
+	dvBigInt, dvScale := bigDecimalComponents(params[0].(*object.Object))
+	drBigInt, drScale := bigDecimalComponents(params[1].(*object.Object))
+
+	scale := dvScale
+	modeParam := params[2]
+	if len(params) > 3 {
+		scale = params[2].(int64)
+		modeParam = params[3]
+	}
+	mode, gerr := roundingModeFromParam("bigdecimalDivideRounding", modeParam)
+	if gerr != nil {
+		return gerr
+	}
 
-	return result
+	if gerr := checkBigDecimalDivisor(dvBigInt, drBigInt); gerr != nil {
+		return gerr
+	}
+	quotient, ok := divideToScale(dvBigInt, dvScale, drBigInt, drScale, scale, mode)
+	if !ok {
+		return getGErrBlk(excNames.ArithmeticException, "Rounding necessary")
+	}
+	return makeBigDecimal(quotient, scale)
 }
 
 // bigdecimalDivideAndRemainder returns both the quotient and remainder of division
@@ -380,3 +412,10 @@ func bigdecimalMultiply(params []interface{}) interface{} {
 
 	return bigDecimalObjectFromBigInt(result, int64(len(result.String())), scale)
 }
+
+// bigdecimalMultiplyMathContext multiplies the two BigDecimals and rounds the product per the MathContext
+func bigdecimalMultiplyMathContext(params []interface{}) interface{} {
+	val1, scale1 := bigDecimalComponents(params[0].(*object.Object))
+	val2, scale2 := bigDecimalComponents(params[1].(*object.Object))
+	return roundBigDecimal("bigdecimalMultiplyMathContext", new(big.Int).Mul(val1, val2), scale1+scale2, params[2])
+}
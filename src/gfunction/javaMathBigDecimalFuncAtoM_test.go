@@ -201,18 +201,22 @@ func Test_gfunction_bigdecimal_all(t *testing.T) {
 	t.Run("bigdecimalDivide and divide by zero", func(t *testing.T) {
 		bdutInit()
 		dividend := bigDecimalObjectFromBigInt(big.NewInt(10), 2, 0)
-		divisor := bigDecimalObjectFromBigInt(big.NewInt(3), 1, 0)
+		divisor := bigDecimalObjectFromBigInt(big.NewInt(4), 1, 0)
 		res := bigdecimalDivide([]interface{}{dividend, divisor})
 		out, ok := res.(*object.Object)
 		if !ok {
 			t.Fatalf("expected *object.Object, got %T", res)
 		}
-		quot, _ := extractBigDecimalComponents(t, out)
-		// integer division 10/3 -> 3
-		if quot.Cmp(big.NewInt(3)) != 0 {
-			t.Fatalf("unexpected quotient %s", quot.String())
+		quot, scale := extractBigDecimalComponents(t, out)
+		// exact division 10/4 -> 2.5
+		if quot.Cmp(big.NewInt(25)) != 0 || scale != 1 {
+			t.Fatalf("unexpected quotient %s, scale %d", quot.String(), scale)
 		}
 
+		// 10/3 has no exact decimal representation
+		three := bigDecimalObjectFromBigInt(big.NewInt(3), 1, 0)
+		assertGErrBlk(t, bigdecimalDivide([]interface{}{dividend, three}), excNames.ArithmeticException)
+
 		// divide by zero
 		zero := bigDecimalObjectFromBigInt(big.NewInt(0), 1, 0)
 		res2 := bigdecimalDivide([]interface{}{dividend, zero})
@@ -469,3 +473,101 @@ func Test_gfunction_bigdecimal_all(t *testing.T) {
 		}
 	})
 }
+
+func TestBigDecimalScaledArithmetic(t *testing.T) {
+	bdutInit()
+
+	// 1.5 + 2.25 = 3.75: the operands are brought to the larger scale
+	sum := bigdecimalAdd([]interface{}{makeBigDecimalFromString(t, "1.5"), makeBigDecimalFromString(t, "2.25")})
+	assertBigDecimalUnscaledScale(t, sum.(*object.Object), "375", 2)
+
+	// 2.0 and 2.00 compare as equal
+	cmp := bigdecimalCompareTo([]interface{}{makeBigDecimalFromString(t, "2.0"), makeBigDecimalFromString(t, "2.00")})
+	if cmp.(int64) != 0 {
+		t.Errorf("compareTo(2.0, 2.00) = %d, want 0", cmp)
+	}
+
+	// 12.345 + 0.0006 with 4 digits of precision, HALF_UP = 12.35
+	sum = bigdecimalAddMathContext([]interface{}{makeBigDecimalFromString(t, "12.345"),
+		makeBigDecimalFromString(t, "0.0006"), makeMathContext(4, roundingModeHalfUp)})
+	assertBigDecimalUnscaledScale(t, sum.(*object.Object), "1235", 2)
+
+	// 1.25 * 1.25 = 1.5625, or 1.562 with 4 digits of precision, HALF_EVEN
+	product := bigdecimalMultiplyMathContext([]interface{}{makeBigDecimalFromString(t, "1.25"),
+		makeBigDecimalFromString(t, "1.25"), makeMathContext(4, roundingModeHalfEven)})
+	assertBigDecimalUnscaledScale(t, product.(*object.Object), "1562", 3)
+
+	// an unlimited MathContext doesn't round
+	product = bigdecimalMultiplyMathContext([]interface{}{makeBigDecimalFromString(t, "1.25"),
+		makeBigDecimalFromString(t, "1.25"), makeMathContext(0, roundingModeHalfUp)})
+	assertBigDecimalUnscaledScale(t, product.(*object.Object), "15625", 4)
+}
+
+func TestBigDecimalDivideRounding(t *testing.T) {
+	bdutInit()
+
+	one := makeBigDecimalFromString(t, "1.00")
+	three := makeBigDecimalFromString(t, "3")
+
+	// divide(BigDecimal, RoundingMode) keeps the dividend's scale
+	res := bigdecimalDivideRounding([]interface{}{one, three, roundingModeObject(roundingModeHalfUp)})
+	assertBigDecimalUnscaledScale(t, res.(*object.Object), "33", 2)
+
+	// divide(BigDecimal, int, RoundingMode)
+	res = bigdecimalDivideRounding([]interface{}{one, three, int64(5), roundingModeObject(roundingModeUp)})
+	assertBigDecimalUnscaledScale(t, res.(*object.Object), "33334", 5)
+
+	// the legacy divide(BigDecimal, int roundingMode): 10/4 with ROUND_HALF_UP is 3
+	res = bigdecimalDivideRounding([]interface{}{makeBigDecimalFromString(t, "10"),
+		makeBigDecimalFromString(t, "4"), int64(roundingModeHalfUp)})
+	assertBigDecimalUnscaledScale(t, res.(*object.Object), "3", 0)
+
+	// a negative scale rounds to tens, hundreds, ...
+	res = bigdecimalDivideRounding([]interface{}{makeBigDecimalFromString(t, "1000"), three,
+		int64(-1), roundingModeObject(roundingModeHalfEven)})
+	assertBigDecimalUnscaledScale(t, res.(*object.Object), "33", -1)
+
+	assertGErrBlk(t, bigdecimalDivideRounding([]interface{}{one, three, roundingModeObject(roundingModeUnnecessary)}),
+		excNames.ArithmeticException)
+	assertGErrBlk(t, bigdecimalDivideRounding([]interface{}{one, three, int64(99)}),
+		excNames.IllegalArgumentException)
+	assertGErrBlk(t, bigdecimalDivideRounding([]interface{}{one, makeBigDecimalFromString(t, "0"), int64(roundingModeUp)}),
+		excNames.ArithmeticException)
+}
+
+func TestBigDecimalDivideMathContext(t *testing.T) {
+	bdutInit()
+
+	tests := []struct {
+		dividend, divisor string
+		precision, mode   int64
+		unscaled          string
+		scale             int64
+	}{
+		{"1", "3", 7, roundingModeHalfEven, "3333333", 7},
+		{"2", "3", 5, roundingModeHalfEven, "66667", 5},
+		{"2", "3", 5, roundingModeDown, "66666", 5},
+		{"-2", "3", 3, roundingModeFloor, "-667", 3},
+		{"10", "4", 7, roundingModeHalfUp, "25", 1},   // exact quotients keep the preferred scale
+		{"100", "4", 7, roundingModeHalfUp, "25", 0},  // ... as in 25, not 25.00000
+		{"9.99", "1", 2, roundingModeHalfUp, "10", 0}, // rounding carries into another digit
+		{"1", "8", 0, roundingModeHalfUp, "125", 3},   // precision 0 is the exact quotient
+	}
+	for _, tt := range tests {
+		res := bigdecimalDivideMathContext([]interface{}{makeBigDecimalFromString(t, tt.dividend),
+			makeBigDecimalFromString(t, tt.divisor), makeMathContext(tt.precision, tt.mode)})
+		out, ok := res.(*object.Object)
+		if !ok {
+			t.Fatalf("%s/%s: expected a BigDecimal, got %v", tt.dividend, tt.divisor, res)
+		}
+		unscaled, scale := extractBigDecimalComponents(t, out)
+		if unscaled.String() != tt.unscaled || scale != tt.scale {
+			t.Errorf("%s/%s with precision %d, %s = %s scale %d, want %s scale %d", tt.dividend, tt.divisor,
+				tt.precision, roundingModeNames[tt.mode], unscaled, scale, tt.unscaled, tt.scale)
+		}
+	}
+
+	res := bigdecimalDivideMathContext([]interface{}{makeBigDecimalFromString(t, "1"),
+		makeBigDecimalFromString(t, "3"), makeMathContext(0, roundingModeHalfUp)})
+	assertGErrBlk(t, res, excNames.ArithmeticException)
+}
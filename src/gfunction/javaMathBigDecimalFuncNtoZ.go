@@ -17,7 +17,7 @@ import (
 
 // bigdecimalNegate returns a BigDecimal with value = -this
 // Extracts the internal unscaled BigInteger, negates it,
-// and creates a new BigDecimal with scale = 0 and recalculated precision.
+// and creates a new BigDecimal with the same scale and recalculated precision.
 func bigdecimalNegate(params []interface{}) interface{} {
 	// Implements BigDecimal.negate()
 	bd := params[0].(*object.Object)
@@ -32,11 +32,17 @@ func bigdecimalNegate(params []interface{}) interface{} {
 	negatedValue := new(big.Int).Neg(dvBigInt)
 
 	// Create result BigDecimal object for the negated value
-	result := bigDecimalObjectFromBigInt(negatedValue, int64(len(negatedValue.String())), int64(0))
+	result := bigDecimalObjectFromBigInt(negatedValue, precisionFromBigInt(negatedValue), bd.FieldTable["scale"].Fvalue.(int64))
 
 	return result
 }
 
+// bigdecimalNegateMathContext returns -this rounded per the MathContext
+func bigdecimalNegateMathContext(params []interface{}) interface{} {
+	unscaled, scale := bigDecimalComponents(params[0].(*object.Object))
+	return roundBigDecimal("bigdecimalNegateMathContext", new(big.Int).Neg(unscaled), scale, params[1])
+}
+
 // bigdecimalPlus returns a new BigDecimal identical to the input (unary plus).
 // This effectively clones the BigDecimal, preserving precision and scale.
 func bigdecimalPlus(params []interface{}) interface{} {
@@ -118,6 +124,12 @@ func bigdecimalRemainder(params []interface{}) interface{} {
 	return remObj
 }
 
+// bigdecimalRound returns this BigDecimal rounded per the MathContext. It also implements plus(MathContext).
+func bigdecimalRound(params []interface{}) interface{} {
+	unscaled, scale := bigDecimalComponents(params[0].(*object.Object))
+	return roundBigDecimal("bigdecimalRound", unscaled, scale, params[1])
+}
+
 // bigdecimalScale returns the current scale of the BigDecimal.
 // Scale represents the number of digits to the right of the decimal point.
 func bigdecimalScale(params []interface{}) interface{} {
@@ -187,6 +199,27 @@ func bigdecimalSetScale(params []interface{}) interface{} {
 	return bigDecimalObjectFromBigInt(newBigInt, precision, newScale)
 }
 
+// bigdecimalSetScaleRounding implements setScale(int, RoundingMode) and the legacy setScale(int, int).
+// When the scale is reduced, the dropped digits are rounded per the rounding mode.
+func bigdecimalSetScaleRounding(params []interface{}) interface{} {
+	bd := params[0].(*object.Object)
+	newScale := params[1].(int64)
+	mode, gerr := roundingModeFromParam("bigdecimalSetScaleRounding", params[2])
+	if gerr != nil {
+		return gerr
+	}
+
+	unscaled, scale := bigDecimalComponents(bd)
+	if newScale == scale {
+		return bd
+	}
+	newBigInt, ok := setScaleRounded(unscaled, scale, newScale, mode)
+	if !ok {
+		return getGErrBlk(excNames.ArithmeticException, "Rounding necessary")
+	}
+	return makeBigDecimal(newBigInt, newScale)
+}
+
 // bigdecimalShortValueExact converts the BigDecimal to an int16 exactly.
 // Returns ArithmeticException if the value is out of the int16 range.
 func bigdecimalShortValueExact(params []interface{}) interface{} {
@@ -286,7 +319,7 @@ func bigdecimalStripTrailingZeros(params []interface{}) interface{} {
 }
 
 // bigdecimalSubtract subtracts the specified BigDecimal from this one.
-// Operates on unscaled values directly and returns a new BigDecimal.
+// The result's scale is the larger of the two scales.
 func bigdecimalSubtract(params []interface{}) interface{} {
 	// Implements BigDecimal.subtract(BigDecimal subtrahend)
 	minuendBD := params[0].(*object.Object)
@@ -300,15 +333,25 @@ func bigdecimalSubtract(params []interface{}) interface{} {
 	dvBigInt := minuendBI.FieldTable["value"].Fvalue.(*big.Int)
 	drBigInt := subtrahendBI.FieldTable["value"].Fvalue.(*big.Int)
 
-	// Perform subtraction
-	resultBigInt := new(big.Int).Sub(dvBigInt, drBigInt)
+	// Bring both values to the same scale and subtract
+	aligned1, aligned2, scale := alignScales(dvBigInt, minuendBD.FieldTable["scale"].Fvalue.(int64),
+		drBigInt, subtrahendBD.FieldTable["scale"].Fvalue.(int64))
+	resultBigInt := new(big.Int).Sub(aligned1, aligned2)
 
 	// Create a new BigDecimal object with the result
-	result := bigDecimalObjectFromBigInt(resultBigInt, int64(len(resultBigInt.String())), minuendBD.FieldTable["scale"].Fvalue.(int64))
+	result := makeBigDecimal(resultBigInt, scale)
 
 	return result
 }
 
+// bigdecimalSubtractMathContext subtracts the specified BigDecimal and rounds the difference per the MathContext
+func bigdecimalSubtractMathContext(params []interface{}) interface{} {
+	val1, scale1 := bigDecimalComponents(params[0].(*object.Object))
+	val2, scale2 := bigDecimalComponents(params[1].(*object.Object))
+	aligned1, aligned2, scale := alignScales(val1, scale1, val2, scale2)
+	return roundBigDecimal("bigdecimalSubtractMathContext", new(big.Int).Sub(aligned1, aligned2), scale, params[2])
+}
+
 // bigdecimalToBigInteger returns the floor of this BigDecimal as a BigInteger.
 // Simply returns the unscaled BigInteger (ignoring scale).
 func bigdecimalToBigInteger(params []interface{}) interface{} {
@@ -1,6 +1,7 @@
 package gfunction
 
 import (
+	"jacobin/src/excNames"
 	"jacobin/src/object"
 	"math/big"
 	"strconv"
@@ -435,3 +436,54 @@ func TestBigDecimalNtoZFunctions_EdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestBigDecimalRoundingFunctions(t *testing.T) {
+	bdutInit()
+
+	// setScale(int, RoundingMode)
+	tests := []struct {
+		value    string
+		scale    int64
+		mode     int64
+		unscaled string
+	}{
+		{"2.35", 1, roundingModeHalfUp, "24"},
+		{"2.45", 1, roundingModeHalfEven, "24"},
+		{"2.45", 1, roundingModeHalfDown, "24"},
+		{"-2.35", 1, roundingModeHalfUp, "-24"},
+		{"-2.31", 1, roundingModeFloor, "-24"},
+		{"2.35", 4, roundingModeDown, "23500"},
+	}
+	for _, tt := range tests {
+		res := bigdecimalSetScaleRounding([]interface{}{makeBigDecimalFromString(t, tt.value), tt.scale,
+			roundingModeObject(tt.mode)})
+		assertBigDecimalUnscaledScale(t, res.(*object.Object), tt.unscaled, tt.scale)
+	}
+
+	// the legacy setScale(int, int) takes a ROUND_* constant
+	res := bigdecimalSetScaleRounding([]interface{}{makeBigDecimalFromString(t, "2.39"), int64(1), int64(roundingModeDown)})
+	assertBigDecimalUnscaledScale(t, res.(*object.Object), "23", 1)
+
+	res = bigdecimalSetScaleRounding([]interface{}{makeBigDecimalFromString(t, "2.35"), int64(1),
+		roundingModeObject(roundingModeUnnecessary)})
+	assertGErrBlk(t, res, excNames.ArithmeticException)
+
+	// round(MathContext)
+	res = bigdecimalRound([]interface{}{makeBigDecimalFromString(t, "123.456"), makeMathContext(4, roundingModeHalfUp)})
+	assertBigDecimalUnscaledScale(t, res.(*object.Object), "1235", 1)
+	res = bigdecimalRound([]interface{}{makeBigDecimalFromString(t, "999.9"), makeMathContext(3, roundingModeHalfUp)})
+	assertBigDecimalUnscaledScale(t, res.(*object.Object), "100", -1)
+
+	// negate keeps the scale
+	res = bigdecimalNegate([]interface{}{makeBigDecimalFromString(t, "1.50")})
+	assertBigDecimalUnscaledScale(t, res.(*object.Object), "-150", 2)
+	res = bigdecimalNegateMathContext([]interface{}{makeBigDecimalFromString(t, "1.55"), makeMathContext(2, roundingModeHalfEven)})
+	assertBigDecimalUnscaledScale(t, res.(*object.Object), "-16", 1)
+
+	// subtract brings the operands to the larger scale
+	res = bigdecimalSubtract([]interface{}{makeBigDecimalFromString(t, "1"), makeBigDecimalFromString(t, "0.25")})
+	assertBigDecimalUnscaledScale(t, res.(*object.Object), "75", 2)
+	res = bigdecimalSubtractMathContext([]interface{}{makeBigDecimalFromString(t, "1"),
+		makeBigDecimalFromString(t, "0.25"), makeMathContext(1, roundingModeCeiling)})
+	assertBigDecimalUnscaledScale(t, res.(*object.Object), "8", 1)
+}
@@ -518,3 +518,258 @@ func javaLikeRemainder(dividend, divisor *big.Int) *big.Int {
 	}
 	return result
 }
+
+/*
+Rounding helpers, shared by the BigDecimal functions that take a scale, a RoundingMode, or a MathContext.
+Values are passed as an unscaled *big.Int and a scale; the ok return is false when rounding
+would be necessary but the rounding mode is UNNECESSARY.
+*/
+
+// bigDecimalComponents returns the unscaled value and the scale of a BigDecimal object.
+func bigDecimalComponents(bd *object.Object) (*big.Int, int64) {
+	intVal := bd.FieldTable["intVal"].Fvalue.(*object.Object)
+	return intVal.FieldTable["value"].Fvalue.(*big.Int), bd.FieldTable["scale"].Fvalue.(int64)
+}
+
+// makeBigDecimal makes a BigDecimal object from an unscaled value and a scale.
+func makeBigDecimal(unscaled *big.Int, scale int64) *object.Object {
+	return bigDecimalObjectFromBigInt(unscaled, precisionFromBigInt(unscaled), scale)
+}
+
+// powerOfTen returns 10^n for n >= 0.
+func powerOfTen(n int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil)
+}
+
+// digitCount returns the number of decimal digits in the argument, with zero having none.
+func digitCount(arg *big.Int) int64 {
+	if arg.Sign() == 0 {
+		return 0
+	}
+	return precisionFromBigInt(arg)
+}
+
+// alignScales returns both unscaled values brought to the larger of the two scales.
+func alignScales(unscaled1 *big.Int, scale1 int64, unscaled2 *big.Int, scale2 int64) (*big.Int, *big.Int, int64) {
+	switch {
+	case scale1 < scale2:
+		return new(big.Int).Mul(unscaled1, powerOfTen(scale2-scale1)), unscaled2, scale2
+	case scale1 > scale2:
+		return unscaled1, new(big.Int).Mul(unscaled2, powerOfTen(scale1-scale2)), scale1
+	default:
+		return unscaled1, unscaled2, scale1
+	}
+}
+
+// roundingModeFromParam accepts either a RoundingMode object or one of the legacy
+// BigDecimal.ROUND_* int constants and returns the rounding mode's ordinal.
+func roundingModeFromParam(funcName string, param interface{}) (int64, *GErrBlk) {
+	if mode, ok := param.(int64); ok {
+		if mode < roundingModeUp || mode > roundingModeUnnecessary {
+			return 0, getGErrBlk(excNames.IllegalArgumentException, "Invalid rounding mode")
+		}
+		return mode, nil
+	}
+	return getRoundingModeOrdinal(funcName, param)
+}
+
+// divideAndRound returns numerator/denominator rounded to an integer using the rounding mode.
+func divideAndRound(numerator, denominator *big.Int, mode int64) (*big.Int, bool) {
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient, true
+	}
+
+	// the sign of the exact quotient; the truncated quotient may be zero
+	sign := numerator.Sign() * denominator.Sign()
+	var increment bool
+	switch mode {
+	case roundingModeUp:
+		increment = true
+	case roundingModeDown:
+		increment = false
+	case roundingModeCeiling:
+		increment = sign > 0
+	case roundingModeFloor:
+		increment = sign < 0
+	case roundingModeHalfUp, roundingModeHalfDown, roundingModeHalfEven:
+		twiceRemainder := new(big.Int).Lsh(new(big.Int).Abs(remainder), 1)
+		switch twiceRemainder.Cmp(new(big.Int).Abs(denominator)) {
+		case 1:
+			increment = true
+		case 0:
+			increment = mode == roundingModeHalfUp || (mode == roundingModeHalfEven && quotient.Bit(0) == 1)
+		}
+	default: // UNNECESSARY
+		return nil, false
+	}
+
+	if increment {
+		quotient.Add(quotient, big.NewInt(int64(sign)))
+	}
+	return quotient, true
+}
+
+// setScaleRounded returns the unscaled value of unscaled×10^-scale at newScale.
+func setScaleRounded(unscaled *big.Int, scale, newScale, mode int64) (*big.Int, bool) {
+	if newScale >= scale {
+		return new(big.Int).Mul(unscaled, powerOfTen(newScale-scale)), true
+	}
+	return divideAndRound(unscaled, powerOfTen(scale-newScale), mode)
+}
+
+// roundToPrecision rounds a value to at most precision significant digits; a precision of 0 means unlimited.
+func roundToPrecision(unscaled *big.Int, scale, precision, mode int64) (*big.Int, int64, bool) {
+	digits := precisionFromBigInt(unscaled)
+	if precision == 0 || digits <= precision {
+		return unscaled, scale, true
+	}
+
+	drop := digits - precision
+	rounded, ok := divideAndRound(unscaled, powerOfTen(drop), mode)
+	if !ok {
+		return nil, 0, false
+	}
+	scale -= drop
+
+	// rounding up can carry into a new digit, as when 999 becomes 1000
+	if precisionFromBigInt(rounded) > precision {
+		rounded.Quo(rounded, big.NewInt(10))
+		scale--
+	}
+	return rounded, scale, true
+}
+
+// stripZerosToScale removes trailing zeros from the unscaled value, but not below the preferred scale.
+func stripZerosToScale(unscaled *big.Int, scale, preferredScale int64) (*big.Int, int64) {
+	result := new(big.Int).Set(unscaled)
+	if result.Sign() == 0 {
+		return result, max(scale, preferredScale)
+	}
+	ten := big.NewInt(10)
+	quotient, remainder := new(big.Int), new(big.Int)
+	for scale > preferredScale {
+		quotient.QuoRem(result, ten, remainder)
+		if remainder.Sign() != 0 {
+			break
+		}
+		result.Set(quotient)
+		scale--
+	}
+	return result, scale
+}
+
+// divideToScale divides dividend×10^-dividendScale by divisor×10^-divisorScale and returns
+// the unscaled value of the quotient at the given scale.
+func divideToScale(dividend *big.Int, dividendScale int64, divisor *big.Int, divisorScale, scale, mode int64) (*big.Int, bool) {
+	numerator := new(big.Int).Set(dividend)
+	denominator := new(big.Int).Set(divisor)
+	shift := scale - dividendScale + divisorScale
+	if shift >= 0 {
+		numerator.Mul(numerator, powerOfTen(shift))
+	} else {
+		denominator.Mul(denominator, powerOfTen(-shift))
+	}
+	return divideAndRound(numerator, denominator, mode)
+}
+
+// divideExact returns the exact quotient at the smallest scale, no less than the preferred scale
+// of dividendScale-divisorScale, that holds it. If the quotient has a non-terminating decimal
+// expansion, ok is false.
+func divideExact(dividend *big.Int, dividendScale int64, divisor *big.Int, divisorScale int64) (*big.Int, int64, bool) {
+	preferredScale := dividendScale - divisorScale
+	if dividend.Sign() == 0 {
+		return new(big.Int), preferredScale, true
+	}
+
+	// the quotient terminates only if the reduced divisor has no prime factors other than 2 and 5
+	gcd := new(big.Int).GCD(nil, nil, new(big.Int).Abs(dividend), new(big.Int).Abs(divisor))
+	numerator := new(big.Int).Quo(dividend, gcd)
+	denominator := new(big.Int).Quo(divisor, gcd)
+	if denominator.Sign() < 0 {
+		numerator.Neg(numerator)
+		denominator.Neg(denominator)
+	}
+
+	rest := new(big.Int).Set(denominator)
+	var twos, fives int64
+	for rest.Bit(0) == 0 {
+		rest.Rsh(rest, 1)
+		twos++
+	}
+	five, remainder := big.NewInt(5), new(big.Int)
+	for {
+		quotient, _ := new(big.Int).QuoRem(rest, five, remainder)
+		if remainder.Sign() != 0 {
+			break
+		}
+		rest = quotient
+		fives++
+	}
+	if rest.Cmp(big.NewInt(1)) != 0 {
+		return nil, 0, false
+	}
+
+	// numerator/denominator = numerator×(10^digits/denominator)/10^digits
+	digits := max(twos, fives)
+	multiplier := new(big.Int).Quo(powerOfTen(digits), denominator)
+	return numerator.Mul(numerator, multiplier), preferredScale + digits, true
+}
+
+// divideToPrecision returns the quotient rounded to precision significant digits, with trailing
+// zeros removed down to the preferred scale of dividendScale-divisorScale.
+func divideToPrecision(dividend *big.Int, dividendScale int64, divisor *big.Int, divisorScale, precision, mode int64) (*big.Int, int64, bool) {
+	preferredScale := dividendScale - divisorScale
+	if dividend.Sign() == 0 {
+		return new(big.Int), preferredScale, true
+	}
+
+	// find the scale at which the truncated quotient has exactly precision digits
+	scale := precision - digitCount(dividend) + digitCount(divisor) + preferredScale
+	for {
+		truncated, _ := divideToScale(dividend, dividendScale, divisor, divisorScale, scale, roundingModeDown)
+		digits := digitCount(truncated)
+		if digits > precision {
+			scale--
+		} else if digits < precision {
+			scale++
+		} else {
+			break
+		}
+	}
+
+	quotient, ok := divideToScale(dividend, dividendScale, divisor, divisorScale, scale, mode)
+	if !ok {
+		return nil, 0, false
+	}
+	if precisionFromBigInt(quotient) > precision {
+		quotient.Quo(quotient, big.NewInt(10))
+		scale--
+	}
+	quotient, scale = stripZerosToScale(quotient, scale, preferredScale)
+	return quotient, scale, true
+}
+
+// checkBigDecimalDivisor returns the ArithmeticException for a zero divisor, or nil.
+func checkBigDecimalDivisor(dividend, divisor *big.Int) *GErrBlk {
+	if divisor.Sign() != 0 {
+		return nil
+	}
+	if dividend.Sign() == 0 {
+		return getGErrBlk(excNames.ArithmeticException, "Division undefined")
+	}
+	return getGErrBlk(excNames.ArithmeticException, "Division by zero")
+}
+
+// roundBigDecimal rounds a value per a MathContext object and returns the BigDecimal result.
+func roundBigDecimal(funcName string, unscaled *big.Int, scale int64, mc interface{}) interface{} {
+	precision, mode, gerr := getMathContext(funcName, mc)
+	if gerr != nil {
+		return gerr
+	}
+	rounded, newScale, ok := roundToPrecision(unscaled, scale, precision, mode)
+	if !ok {
+		return getGErrBlk(excNames.ArithmeticException, "Rounding necessary")
+	}
+	return makeBigDecimal(rounded, newScale)
+}
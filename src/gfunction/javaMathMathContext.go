@@ -0,0 +1,242 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"fmt"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/statics"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+	"strconv"
+	"strings"
+)
+
+// A MathContext holds a precision (the number of significant digits, 0 meaning
+// unlimited) and a RoundingMode. The BigDecimal G functions read both fields.
+
+var classNameMathContext = "java/math/MathContext"
+
+func Load_Math_Math_Context() {
+
+	MethodSignatures[classNameMathContext+".<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  mathContextClinit,
+		}
+
+	MethodSignatures[classNameMathContext+".<init>(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  mathContextInit,
+		}
+
+	MethodSignatures[classNameMathContext+".<init>(ILjava/math/RoundingMode;)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  mathContextInit,
+		}
+
+	MethodSignatures[classNameMathContext+".<init>(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  mathContextInitString,
+		}
+
+	MethodSignatures[classNameMathContext+".equals(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  mathContextEquals,
+		}
+
+	MethodSignatures[classNameMathContext+".getPrecision()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  mathContextGetPrecision,
+		}
+
+	MethodSignatures[classNameMathContext+".getRoundingMode()Ljava/math/RoundingMode;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  mathContextGetRoundingMode,
+		}
+
+	MethodSignatures[classNameMathContext+".hashCode()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  mathContextHashCode,
+		}
+
+	MethodSignatures[classNameMathContext+".toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  mathContextToString,
+		}
+}
+
+// "java/math/MathContext.<clinit>()V" -- create DECIMAL32, DECIMAL64, DECIMAL128, and UNLIMITED
+func mathContextClinit([]interface{}) interface{} {
+	klass := classloader.MethAreaFetch(classNameMathContext)
+	if klass == nil || klass.Data == nil {
+		errMsg := fmt.Sprintf("mathContextClinit: Expected %s to be in the MethodArea, but it was not", classNameMathContext)
+		trace.Error(errMsg)
+		return getGErrBlk(excNames.ClassNotLoadedException, errMsg)
+	}
+	if klass.Data.ClInit != types.ClInitRun {
+		addStaticMathContext("DECIMAL32", 7, roundingModeHalfEven)
+		addStaticMathContext("DECIMAL64", 16, roundingModeHalfEven)
+		addStaticMathContext("DECIMAL128", 34, roundingModeHalfEven)
+		addStaticMathContext("UNLIMITED", 0, roundingModeHalfUp)
+		klass.Data.ClInit = types.ClInitRun
+	}
+	return nil
+}
+
+func addStaticMathContext(name string, precision, roundingMode int64) {
+	_ = statics.AddStatic(classNameMathContext+"."+name,
+		statics.Static{Type: "Ljava/math/MathContext;", Value: makeMathContext(precision, roundingMode)})
+}
+
+// makeMathContext creates a MathContext object with the given precision and rounding mode ordinal
+func makeMathContext(precision, roundingMode int64) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&classNameMathContext)
+	setMathContextFields(obj, precision, roundingModeObject(roundingMode))
+	return obj
+}
+
+func setMathContextFields(obj *object.Object, precision int64, roundingMode *object.Object) {
+	obj.FieldTable["precision"] = object.Field{Ftype: types.Int, Fvalue: precision}
+	obj.FieldTable["roundingMode"] = object.Field{Ftype: "Ljava/math/RoundingMode;", Fvalue: roundingMode}
+}
+
+// getMathContext returns the precision and rounding mode ordinal of a MathContext object
+func getMathContext(funcName string, mc interface{}) (int64, int64, *GErrBlk) {
+	obj, ok := mc.(*object.Object)
+	if !ok || object.IsNull(obj) {
+		errMsg := fmt.Sprintf("%s: the MathContext is null", funcName)
+		return 0, 0, getGErrBlk(excNames.NullPointerException, errMsg)
+	}
+	precision, ok := obj.FieldTable["precision"].Fvalue.(int64)
+	if !ok {
+		errMsg := fmt.Sprintf("%s: the object is not a MathContext", funcName)
+		return 0, 0, getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	roundingMode, gerr := getRoundingModeOrdinal(funcName, obj.FieldTable["roundingMode"].Fvalue)
+	if gerr != nil {
+		return 0, 0, gerr
+	}
+	return precision, roundingMode, nil
+}
+
+// "java/math/MathContext.<init>(I)V" and <init>(ILjava/math/RoundingMode;)V.
+// Without a RoundingMode, HALF_UP is used.
+func mathContextInit(params []interface{}) interface{} {
+	obj := params[0].(*object.Object)
+	precision := params[1].(int64)
+	if precision < 0 {
+		return getGErrBlk(excNames.IllegalArgumentException, "Digits < 0")
+	}
+
+	roundingMode := roundingModeObject(roundingModeHalfUp)
+	if len(params) > 2 {
+		if _, gerr := getRoundingModeOrdinal("mathContextInit", params[2]); gerr != nil {
+			return gerr
+		}
+		roundingMode = params[2].(*object.Object)
+	}
+
+	setMathContextFields(obj, precision, roundingMode)
+	return nil
+}
+
+// "java/math/MathContext.<init>(Ljava/lang/String;)V" -- parses the format written by toString()
+func mathContextInitString(params []interface{}) interface{} {
+	obj := params[0].(*object.Object)
+	strObj, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(strObj) {
+		return getGErrBlk(excNames.NullPointerException, "mathContextInitString: null string")
+	}
+	str := object.GoStringFromStringObject(strObj)
+
+	badFormat := getGErrBlk(excNames.IllegalArgumentException, "bad string format")
+	precisionPart, modePart, found := strings.Cut(str, " ")
+	if !found || !strings.HasPrefix(precisionPart, "precision=") || !strings.HasPrefix(modePart, "roundingMode=") {
+		return badFormat
+	}
+	precision, err := strconv.ParseInt(strings.TrimPrefix(precisionPart, "precision="), 10, 32)
+	if err != nil {
+		return badFormat
+	}
+	if precision < 0 {
+		return getGErrBlk(excNames.IllegalArgumentException, "Digits < 0")
+	}
+
+	modeName := strings.TrimPrefix(modePart, "roundingMode=")
+	for ordinal, name := range roundingModeNames {
+		if name == modeName {
+			setMathContextFields(obj, precision, roundingModeObject(int64(ordinal)))
+			return nil
+		}
+	}
+	return badFormat
+}
+
+// "java/math/MathContext.equals(Ljava/lang/Object;)Z"
+func mathContextEquals(params []interface{}) interface{} {
+	that, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(that) || object.GoStringFromStringPoolIndex(that.KlassName) != classNameMathContext {
+		return types.JavaBoolFalse
+	}
+	precision1, mode1, gerr := getMathContext("mathContextEquals", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	precision2, mode2, gerr := getMathContext("mathContextEquals", that)
+	if gerr != nil {
+		return gerr
+	}
+	return object.JavaBooleanFromGoBoolean(precision1 == precision2 && mode1 == mode2)
+}
+
+// "java/math/MathContext.getPrecision()I"
+func mathContextGetPrecision(params []interface{}) interface{} {
+	precision, _, gerr := getMathContext("mathContextGetPrecision", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	return precision
+}
+
+// "java/math/MathContext.getRoundingMode()Ljava/math/RoundingMode;"
+func mathContextGetRoundingMode(params []interface{}) interface{} {
+	_, roundingMode, gerr := getMathContext("mathContextGetRoundingMode", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	return roundingModeObject(roundingMode)
+}
+
+// "java/math/MathContext.hashCode()I" -- the JDK uses the enum's identity hash code; the ordinal is used here
+func mathContextHashCode(params []interface{}) interface{} {
+	precision, roundingMode, gerr := getMathContext("mathContextHashCode", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	return int64(int32(roundingMode ^ (precision << 2)))
+}
+
+// "java/math/MathContext.toString()Ljava/lang/String;"
+func mathContextToString(params []interface{}) interface{} {
+	precision, roundingMode, gerr := getMathContext("mathContextToString", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	str := fmt.Sprintf("precision=%d roundingMode=%s", precision, roundingModeNames[roundingMode])
+	return object.StringObjectFromGoString(str)
+}
@@ -0,0 +1,58 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"testing"
+)
+
+func TestMathContextInitAndToString(t *testing.T) {
+	globals.InitStringPool()
+
+	mc := object.MakeEmptyObjectWithClassName(&classNameMathContext)
+	if ret := mathContextInit([]interface{}{mc, int64(5)}); ret != nil {
+		t.Fatalf("<init>(I): %v", ret)
+	}
+	str := object.GoStringFromStringObject(mathContextToString([]interface{}{mc}).(*object.Object))
+	if str != "precision=5 roundingMode=HALF_UP" {
+		t.Errorf("toString = %q", str)
+	}
+
+	parsed := object.MakeEmptyObjectWithClassName(&classNameMathContext)
+	ret := mathContextInitString([]interface{}{parsed, object.StringObjectFromGoString("precision=7 roundingMode=HALF_EVEN")})
+	if ret != nil {
+		t.Fatalf("<init>(String): %v", ret)
+	}
+	if mathContextEquals([]interface{}{parsed, makeMathContext(7, roundingModeHalfEven)}) != types.JavaBoolTrue {
+		t.Errorf("parsed MathContext is not equal to DECIMAL32")
+	}
+	if mathContextEquals([]interface{}{parsed, mc}) != types.JavaBoolFalse {
+		t.Errorf("different MathContexts are equal")
+	}
+	if precision := mathContextGetPrecision([]interface{}{parsed}); precision != int64(7) {
+		t.Errorf("getPrecision = %v, want 7", precision)
+	}
+	mode := mathContextGetRoundingMode([]interface{}{parsed})
+	if ordinal := roundingModeOrdinal([]interface{}{mode}); ordinal != int64(roundingModeHalfEven) {
+		t.Errorf("getRoundingMode().ordinal() = %v, want %d", ordinal, roundingModeHalfEven)
+	}
+
+	for _, bad := range []string{"precision=7", "precision=x roundingMode=UP", "precision=7 roundingMode=SIDEWAYS"} {
+		ret = mathContextInitString([]interface{}{parsed, object.StringObjectFromGoString(bad)})
+		if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalArgumentException {
+			t.Errorf("<init>(%q): expected IllegalArgumentException, got %v", bad, ret)
+		}
+	}
+	ret = mathContextInit([]interface{}{mc, int64(-1)})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("<init>(-1): expected IllegalArgumentException, got %v", ret)
+	}
+}
@@ -0,0 +1,190 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"fmt"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/statics"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+)
+
+// RoundingMode is an enum. Its constants are created by its <clinit> and held as statics,
+// so that Java code comparing them with == sees the same objects that the BigDecimal
+// G functions return. Each constant carries the name and ordinal fields of java/lang/Enum.
+
+var classNameRoundingMode = "java/math/RoundingMode"
+
+// the ordinals of the RoundingMode constants, in their declaration order
+const (
+	roundingModeUp = iota
+	roundingModeDown
+	roundingModeCeiling
+	roundingModeFloor
+	roundingModeHalfUp
+	roundingModeHalfDown
+	roundingModeHalfEven
+	roundingModeUnnecessary
+)
+
+var roundingModeNames = []string{
+	"UP", "DOWN", "CEILING", "FLOOR", "HALF_UP", "HALF_DOWN", "HALF_EVEN", "UNNECESSARY"}
+
+func Load_Math_Rounding_Mode() {
+
+	MethodSignatures[classNameRoundingMode+".<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  roundingModeClinit,
+		}
+
+	MethodSignatures[classNameRoundingMode+".name()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  roundingModeName,
+		}
+
+	MethodSignatures[classNameRoundingMode+".ordinal()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  roundingModeOrdinal,
+		}
+
+	MethodSignatures[classNameRoundingMode+".toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  roundingModeName,
+		}
+
+	MethodSignatures[classNameRoundingMode+".valueOf(I)Ljava/math/RoundingMode;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  roundingModeValueOfInt,
+		}
+
+	MethodSignatures[classNameRoundingMode+".valueOf(Ljava/lang/String;)Ljava/math/RoundingMode;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  roundingModeValueOfString,
+		}
+
+	MethodSignatures[classNameRoundingMode+".values()[Ljava/math/RoundingMode;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  roundingModeValues,
+		}
+}
+
+// "java/math/RoundingMode.<clinit>()V" -- create the enum constants
+func roundingModeClinit([]interface{}) interface{} {
+	klass := classloader.MethAreaFetch(classNameRoundingMode)
+	if klass == nil || klass.Data == nil {
+		errMsg := fmt.Sprintf("roundingModeClinit: Expected %s to be in the MethodArea, but it was not", classNameRoundingMode)
+		trace.Error(errMsg)
+		return getGErrBlk(excNames.ClassNotLoadedException, errMsg)
+	}
+	if klass.Data.ClInit != types.ClInitRun {
+		for ordinal, name := range roundingModeNames {
+			_ = statics.AddStatic(classNameRoundingMode+"."+name,
+				statics.Static{Type: "Ljava/math/RoundingMode;", Value: makeRoundingMode(int64(ordinal))})
+		}
+		klass.Data.ClInit = types.ClInitRun
+	}
+	return nil
+}
+
+// makeRoundingMode creates a RoundingMode object for the given ordinal
+func makeRoundingMode(ordinal int64) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&classNameRoundingMode)
+	obj.FieldTable["name"] = object.Field{
+		Ftype: types.StringClassRef, Fvalue: object.StringObjectFromGoString(roundingModeNames[ordinal])}
+	obj.FieldTable["ordinal"] = object.Field{Ftype: types.Int, Fvalue: ordinal}
+	return obj
+}
+
+// roundingModeObject returns the RoundingMode constant for the given ordinal. Once
+// <clinit> has run, this is the static; before then, a new object is made.
+func roundingModeObject(ordinal int64) *object.Object {
+	if static, ok := statics.Statics[classNameRoundingMode+"."+roundingModeNames[ordinal]]; ok {
+		if obj, ok := static.Value.(*object.Object); ok {
+			return obj
+		}
+	}
+	return makeRoundingMode(ordinal)
+}
+
+// getRoundingModeOrdinal returns the ordinal of a RoundingMode object
+func getRoundingModeOrdinal(funcName string, mode interface{}) (int64, *GErrBlk) {
+	obj, ok := mode.(*object.Object)
+	if !ok || object.IsNull(obj) {
+		errMsg := fmt.Sprintf("%s: the RoundingMode is null", funcName)
+		return 0, getGErrBlk(excNames.NullPointerException, errMsg)
+	}
+	ordinal, ok := obj.FieldTable["ordinal"].Fvalue.(int64)
+	if !ok || ordinal < 0 || ordinal >= int64(len(roundingModeNames)) {
+		errMsg := fmt.Sprintf("%s: the object is not a RoundingMode", funcName)
+		return 0, getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	return ordinal, nil
+}
+
+// "java/math/RoundingMode.name()Ljava/lang/String;" and toString()
+func roundingModeName(params []interface{}) interface{} {
+	ordinal, gerr := getRoundingModeOrdinal("roundingModeName", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	return object.StringObjectFromGoString(roundingModeNames[ordinal])
+}
+
+// "java/math/RoundingMode.ordinal()I"
+func roundingModeOrdinal(params []interface{}) interface{} {
+	ordinal, gerr := getRoundingModeOrdinal("roundingModeOrdinal", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	return ordinal
+}
+
+// "java/math/RoundingMode.valueOf(I)Ljava/math/RoundingMode;" -- maps the legacy
+// BigDecimal.ROUND_* constants, which share the ordinals, onto the enum
+func roundingModeValueOfInt(params []interface{}) interface{} {
+	ordinal := params[0].(int64)
+	if ordinal < 0 || ordinal >= int64(len(roundingModeNames)) {
+		return getGErrBlk(excNames.IllegalArgumentException, "argument out of range")
+	}
+	return roundingModeObject(ordinal)
+}
+
+// "java/math/RoundingMode.valueOf(Ljava/lang/String;)Ljava/math/RoundingMode;"
+func roundingModeValueOfString(params []interface{}) interface{} {
+	strObj, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(strObj) {
+		return getGErrBlk(excNames.NullPointerException, "Name is null")
+	}
+	name := object.GoStringFromStringObject(strObj)
+	for ordinal, modeName := range roundingModeNames {
+		if name == modeName {
+			return roundingModeObject(int64(ordinal))
+		}
+	}
+	errMsg := fmt.Sprintf("No enum constant java.math.RoundingMode.%s", name)
+	return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+}
+
+// "java/math/RoundingMode.values()[Ljava/math/RoundingMode;"
+func roundingModeValues([]interface{}) interface{} {
+	arr := object.Make1DimRefArray(classNameRoundingMode+";", int64(len(roundingModeNames)))
+	values := arr.FieldTable["value"].Fvalue.([]*object.Object)
+	for ordinal := range roundingModeNames {
+		values[ordinal] = roundingModeObject(int64(ordinal))
+	}
+	return arr
+}
@@ -0,0 +1,80 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"math/big"
+	"testing"
+)
+
+func TestRoundingModeValueOfAndValues(t *testing.T) {
+	globals.InitStringPool()
+
+	mode := roundingModeValueOfString([]interface{}{object.StringObjectFromGoString("HALF_EVEN")})
+	if ordinal := roundingModeOrdinal([]interface{}{mode}); ordinal != int64(roundingModeHalfEven) {
+		t.Errorf("valueOf(HALF_EVEN).ordinal() = %v, want %d", ordinal, roundingModeHalfEven)
+	}
+	name := roundingModeName([]interface{}{roundingModeValueOfInt([]interface{}{int64(roundingModeFloor)})})
+	if got := object.GoStringFromStringObject(name.(*object.Object)); got != "FLOOR" {
+		t.Errorf("valueOf(3).name() = %q, want FLOOR", got)
+	}
+
+	arr := roundingModeValues(nil).(*object.Object)
+	values := arr.FieldTable["value"].Fvalue.([]*object.Object)
+	if len(values) != len(roundingModeNames) {
+		t.Fatalf("values() returned %d modes, want %d", len(values), len(roundingModeNames))
+	}
+	for i, value := range values {
+		if ordinal := roundingModeOrdinal([]interface{}{value}); ordinal != int64(i) {
+			t.Errorf("values()[%d].ordinal() = %v", i, ordinal)
+		}
+	}
+
+	ret := roundingModeValueOfString([]interface{}{object.StringObjectFromGoString("HALF_ODD")})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("valueOf(HALF_ODD): expected IllegalArgumentException, got %v", ret)
+	}
+	ret = roundingModeValueOfInt([]interface{}{int64(8)})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("valueOf(8): expected IllegalArgumentException, got %v", ret)
+	}
+}
+
+func TestDivideAndRoundModes(t *testing.T) {
+	tests := []struct {
+		numerator, denominator int64
+		mode                   int64
+		want                   int64
+	}{
+		{-7, 2, roundingModeUp, -4},
+		{-7, 2, roundingModeDown, -3},
+		{-7, 2, roundingModeCeiling, -3},
+		{-7, 2, roundingModeFloor, -4},
+		{-7, 2, roundingModeHalfUp, -4},
+		{-7, 2, roundingModeHalfDown, -3},
+		{-7, 2, roundingModeHalfEven, -4},
+		{5, 2, roundingModeHalfEven, 2},
+		{1, 3, roundingModeCeiling, 1},
+		{-1, 3, roundingModeCeiling, 0},
+		{2, 3, roundingModeHalfDown, 1},
+		{8, 2, roundingModeUnnecessary, 4},
+	}
+	for _, tt := range tests {
+		got, ok := divideAndRound(big.NewInt(tt.numerator), big.NewInt(tt.denominator), tt.mode)
+		if !ok || got.Int64() != tt.want {
+			t.Errorf("%d/%d with %s = %v (ok=%v), want %d",
+				tt.numerator, tt.denominator, roundingModeNames[tt.mode], got, ok, tt.want)
+		}
+	}
+
+	if _, ok := divideAndRound(big.NewInt(7), big.NewInt(2), roundingModeUnnecessary); ok {
+		t.Errorf("7/2 with UNNECESSARY: expected rounding to be necessary")
+	}
+}
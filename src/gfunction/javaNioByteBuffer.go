@@ -0,0 +1,1325 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"encoding/binary"
+	"fmt"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
+	"jacobin/src/object"
+	"jacobin/src/statics"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+	"math"
+	"os"
+)
+
+// A ByteBuffer is a window, from its position to its limit, onto a Go slice of Java
+// bytes. The slice is that of a Java byte array, so a buffer made by wrap() shares the
+// bytes of the array it wraps, and a slice or a duplicate shares the bytes of its
+// buffer. Direct buffers are held the same way; they differ only in having no
+// accessible array. A buffer made by FileChannel.map() in READ_WRITE mode writes each
+// put through to its file.
+
+var classNameBuffer = "java/nio/Buffer"
+var classNameByteBuffer = "java/nio/ByteBuffer"
+var classNameMappedByteBuffer = "java/nio/MappedByteBuffer"
+var classNameByteOrder = "java/nio/ByteOrder"
+
+var fieldNameByteBuffer = "buffer"
+
+type byteBuffer struct {
+	array     *object.Object // the Java byte array that holds the bytes
+	offset    int64          // the index in array of the buffer's first byte
+	capacity  int64
+	limit     int64
+	position  int64
+	mark      int64 // -1 if there is no mark
+	bigEndian bool
+	readOnly  bool
+	direct    bool
+	mapFile   *os.File // for a READ_WRITE mapping, the file that puts are written to
+	mapStart  int64    // the position in mapFile of the buffer's first byte
+}
+
+func Load_Nio_ByteBuffer() {
+
+	MethodSignatures["java/nio/Buffer.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/nio/MappedByteBuffer.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.allocate(I)Ljava/nio/ByteBuffer;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  byteBufferAllocate,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.allocateDirect(I)Ljava/nio/ByteBuffer;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  byteBufferAllocateDirect,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.array()[B"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  byteBufferArray,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.asReadOnlyBuffer()Ljava/nio/ByteBuffer;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  byteBufferAsReadOnlyBuffer,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.compact()Ljava/nio/ByteBuffer;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  byteBufferCompact,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.compareTo(Ljava/nio/ByteBuffer;)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  byteBufferCompareTo,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.compareTo(Ljava/lang/Object;)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  byteBufferCompareTo,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.duplicate()Ljava/nio/ByteBuffer;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  byteBufferDuplicate,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.equals(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  byteBufferEquals,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.get()B"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  byteBufferGet,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.get(I)B"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  byteBufferGetAt,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.get([B)Ljava/nio/ByteBuffer;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  byteBufferGetArray,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.get([BII)Ljava/nio/ByteBuffer;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  byteBufferGetArrayOffset,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.hashCode()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  byteBufferHashCode,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.order()Ljava/nio/ByteOrder;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  byteBufferOrder,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.order(Ljava/nio/ByteOrder;)Ljava/nio/ByteBuffer;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  byteBufferSetOrder,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.put(B)Ljava/nio/ByteBuffer;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  byteBufferPut,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.put(IB)Ljava/nio/ByteBuffer;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  byteBufferPutAt,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.put([B)Ljava/nio/ByteBuffer;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  byteBufferPutArray,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.put([BII)Ljava/nio/ByteBuffer;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  byteBufferPutArrayOffset,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.put(Ljava/nio/ByteBuffer;)Ljava/nio/ByteBuffer;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  byteBufferPutBuffer,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.slice()Ljava/nio/ByteBuffer;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  byteBufferSlice,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.slice(II)Ljava/nio/ByteBuffer;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  byteBufferSliceRange,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  byteBufferToString,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.wrap([B)Ljava/nio/ByteBuffer;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  byteBufferWrap,
+		}
+
+	MethodSignatures["java/nio/ByteBuffer.wrap([BII)Ljava/nio/ByteBuffer;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  byteBufferWrapOffset,
+		}
+
+	MethodSignatures["java/nio/MappedByteBuffer.force()Ljava/nio/MappedByteBuffer;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  mappedByteBufferForce,
+		}
+
+	MethodSignatures["java/nio/MappedByteBuffer.isLoaded()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  returnTrue,
+		}
+
+	MethodSignatures["java/nio/MappedByteBuffer.load()Ljava/nio/MappedByteBuffer;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  byteBufferSelf,
+		}
+
+	// the methods of java/nio/Buffer, which ByteBuffer overrides to return a ByteBuffer
+	for _, class := range []string{classNameBuffer, classNameByteBuffer} {
+		returnType := "L" + class + ";"
+
+		MethodSignatures[class+".arrayOffset()I"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  byteBufferArrayOffset,
+			}
+
+		MethodSignatures[class+".capacity()I"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  byteBufferCapacity,
+			}
+
+		MethodSignatures[class+".clear()"+returnType] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  byteBufferClear,
+			}
+
+		MethodSignatures[class+".flip()"+returnType] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  byteBufferFlip,
+			}
+
+		MethodSignatures[class+".hasArray()Z"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  byteBufferHasArray,
+			}
+
+		MethodSignatures[class+".hasRemaining()Z"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  byteBufferHasRemaining,
+			}
+
+		MethodSignatures[class+".isDirect()Z"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  byteBufferIsDirect,
+			}
+
+		MethodSignatures[class+".isReadOnly()Z"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  byteBufferIsReadOnly,
+			}
+
+		MethodSignatures[class+".limit()I"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  byteBufferLimit,
+			}
+
+		MethodSignatures[class+".limit(I)"+returnType] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  byteBufferSetLimit,
+			}
+
+		MethodSignatures[class+".mark()"+returnType] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  byteBufferMark,
+			}
+
+		MethodSignatures[class+".position()I"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  byteBufferPosition,
+			}
+
+		MethodSignatures[class+".position(I)"+returnType] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  byteBufferSetPosition,
+			}
+
+		MethodSignatures[class+".remaining()I"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  byteBufferRemaining,
+			}
+
+		MethodSignatures[class+".reset()"+returnType] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  byteBufferReset,
+			}
+
+		MethodSignatures[class+".rewind()"+returnType] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  byteBufferRewind,
+			}
+	}
+
+	// the typed getters and putters, such as getInt()I, getInt(I)I, putInt(I)Ljava/nio/ByteBuffer;
+	// and putInt(II)Ljava/nio/ByteBuffer;
+	for _, primitive := range []struct {
+		name, descriptor string
+		size             int64
+	}{
+		{"Char", types.Char, 2}, {"Short", types.Short, 2}, {"Int", types.Int, 4},
+		{"Long", types.Long, 8}, {"Float", types.Float, 4}, {"Double", types.Double, 8},
+	} {
+		MethodSignatures["java/nio/ByteBuffer.get"+primitive.name+"()"+primitive.descriptor] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  byteBufferGetPrimitive(primitive.descriptor[0], primitive.size, false),
+			}
+
+		MethodSignatures["java/nio/ByteBuffer.get"+primitive.name+"(I)"+primitive.descriptor] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  byteBufferGetPrimitive(primitive.descriptor[0], primitive.size, true),
+			}
+
+		MethodSignatures["java/nio/ByteBuffer.put"+primitive.name+"("+primitive.descriptor+")Ljava/nio/ByteBuffer;"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  byteBufferPutPrimitive(primitive.descriptor[0], primitive.size, false),
+			}
+
+		MethodSignatures["java/nio/ByteBuffer.put"+primitive.name+"(I"+primitive.descriptor+")Ljava/nio/ByteBuffer;"] =
+			GMeth{
+				ParamSlots: 2,
+				GFunction:  byteBufferPutPrimitive(primitive.descriptor[0], primitive.size, true),
+			}
+	}
+
+	MethodSignatures["java/nio/ByteOrder.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  byteOrderClinit,
+		}
+
+	MethodSignatures["java/nio/ByteOrder.nativeOrder()Ljava/nio/ByteOrder;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  byteOrderNativeOrder,
+		}
+
+	MethodSignatures["java/nio/ByteOrder.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  byteOrderToString,
+		}
+}
+
+// makeByteBuffer returns a ByteBuffer object whose state is bb. As in the JDK, a direct
+// buffer is a MappedByteBuffer.
+func makeByteBuffer(bb *byteBuffer) *object.Object {
+	className := &classNameByteBuffer
+	if bb.direct {
+		className = &classNameMappedByteBuffer
+	}
+	obj := object.MakeEmptyObjectWithClassName(className)
+	obj.FieldTable[fieldNameByteBuffer] = object.Field{Ftype: types.BufferState, Fvalue: bb}
+	return obj
+}
+
+// newByteBuffer returns a ByteBuffer over the bytes of array from offset to offset+capacity,
+// with its position at 0 and its limit at its capacity
+func newByteBuffer(array *object.Object, offset, capacity int64) *byteBuffer {
+	return &byteBuffer{array: array, offset: offset, capacity: capacity, limit: capacity,
+		mark: -1, bigEndian: true}
+}
+
+// returns the state of the ByteBuffer in params[0]
+func getByteBuffer(funcName string, params []interface{}) (*byteBuffer, *GErrBlk) {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return nil, getArgsGErrBlk(funcName, err)
+	}
+	bb, ok := obj.FieldTable[fieldNameByteBuffer].Fvalue.(*byteBuffer)
+	if !ok {
+		return nil, getGErrBlk(excNames.IllegalStateException, funcName+": the ByteBuffer is not initialized")
+	}
+	return bb, nil
+}
+
+// bytes returns the bytes of the buffer, from index 0 to its capacity
+func (bb *byteBuffer) bytes() []types.JavaByte {
+	return bb.array.FieldTable["value"].Fvalue.([]types.JavaByte)[bb.offset : bb.offset+bb.capacity]
+}
+
+func (bb *byteBuffer) remaining() int64 {
+	return bb.limit - bb.position
+}
+
+// copyOf returns a buffer sharing the bytes of bb, with the same position, limit, and
+// mark, but in big-endian order, as duplicate() and asReadOnlyBuffer() return
+func (bb *byteBuffer) copyOf() *byteBuffer {
+	dup := *bb
+	dup.bigEndian = true
+	return &dup
+}
+
+// returns the index of the next n bytes of a relative get, and advances the position
+func (bb *byteBuffer) nextGetIndex(funcName string, n int64) (int64, *GErrBlk) {
+	if bb.remaining() < n {
+		return 0, getGErrBlk(excNames.BufferUnderflowException, funcName+": too few bytes remain in the buffer")
+	}
+	index := bb.position
+	bb.position += n
+	return index, nil
+}
+
+// returns the index of the next n bytes of a relative put, and advances the position
+func (bb *byteBuffer) nextPutIndex(funcName string, n int64) (int64, *GErrBlk) {
+	if bb.readOnly {
+		return 0, getGErrBlk(excNames.ReadOnlyBufferException, funcName+": the buffer is read-only")
+	}
+	if bb.remaining() < n {
+		return 0, getGErrBlk(excNames.BufferOverflowException, funcName+": too little room remains in the buffer")
+	}
+	index := bb.position
+	bb.position += n
+	return index, nil
+}
+
+// checks that the n bytes at index, for an absolute get or put, are below the limit
+func (bb *byteBuffer) checkIndex(funcName string, index, n int64) *GErrBlk {
+	if index < 0 || index > bb.limit-n {
+		errMsg := fmt.Sprintf("%s: Index %d out of bounds for length %d", funcName, index, bb.limit)
+		return getGErrBlk(excNames.IndexOutOfBoundsException, errMsg)
+	}
+	return nil
+}
+
+// stored is called after the n bytes at index have been put, so that a READ_WRITE
+// mapping writes them to its file
+func (bb *byteBuffer) stored(funcName string, index, n int64) *GErrBlk {
+	if bb.mapFile == nil || n == 0 {
+		return nil
+	}
+	data := object.GoByteArrayFromJavaByteArray(bb.bytes()[index : index+n])
+	if _, err := bb.mapFile.WriteAt(data, bb.mapStart+index); err != nil {
+		errMsg := fmt.Sprintf("%s: writing to the mapped file failed, reason: %s", funcName, err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	return nil
+}
+
+// "java/nio/ByteBuffer.allocate(I)Ljava/nio/ByteBuffer;"
+func byteBufferAllocate(params []interface{}) interface{} {
+	capacity, err := args.GetInt64(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("byteBufferAllocate", err)
+	}
+	if capacity < 0 {
+		errMsg := fmt.Sprintf("byteBufferAllocate: capacity < 0: (%d < 0)", capacity)
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	return makeByteBuffer(newByteBuffer(object.Make1DimArray(object.BYTE, capacity), 0, capacity))
+}
+
+// "java/nio/ByteBuffer.allocateDirect(I)Ljava/nio/ByteBuffer;"
+func byteBufferAllocateDirect(params []interface{}) interface{} {
+	capacity, err := args.GetInt64(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("byteBufferAllocateDirect", err)
+	}
+	if capacity < 0 {
+		errMsg := fmt.Sprintf("byteBufferAllocateDirect: capacity < 0: (%d < 0)", capacity)
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	bb := newByteBuffer(object.Make1DimArray(object.BYTE, capacity), 0, capacity)
+	bb.direct = true
+	return makeByteBuffer(bb)
+}
+
+// "java/nio/ByteBuffer.wrap([B)Ljava/nio/ByteBuffer;"
+func byteBufferWrap(params []interface{}) interface{} {
+	array, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("byteBufferWrap", err)
+	}
+	javaBytes, ok := array.FieldTable["value"].Fvalue.([]types.JavaByte)
+	if !ok {
+		return getGErrBlk(excNames.IllegalArgumentException, "byteBufferWrap: the parameter is not a byte array")
+	}
+	return makeByteBuffer(newByteBuffer(array, 0, int64(len(javaBytes))))
+}
+
+// "java/nio/ByteBuffer.wrap([BII)Ljava/nio/ByteBuffer;" -- the buffer's capacity is the
+// length of the array, its position is offset, and its limit is offset+length
+func byteBufferWrapOffset(params []interface{}) interface{} {
+	ret := byteBufferWrap(params[:1])
+	obj, ok := ret.(*object.Object)
+	if !ok {
+		return ret
+	}
+	offset, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("byteBufferWrapOffset", err)
+	}
+	length, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("byteBufferWrapOffset", err)
+	}
+	bb := obj.FieldTable[fieldNameByteBuffer].Fvalue.(*byteBuffer)
+	if offset < 0 || length < 0 || offset > bb.capacity-length {
+		errMsg := fmt.Sprintf("byteBufferWrapOffset: Range [%d, %d + %d) out of bounds for length %d",
+			offset, offset, length, bb.capacity)
+		return getGErrBlk(excNames.IndexOutOfBoundsException, errMsg)
+	}
+	bb.position = offset
+	bb.limit = offset + length
+	return obj
+}
+
+// returns the ByteBuffer object itself, as the methods that return this buffer do
+func byteBufferSelf(params []interface{}) interface{} {
+	return params[0]
+}
+
+// "java/nio/ByteBuffer.array()[B"
+func byteBufferArray(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferArray", params)
+	if gErr != nil {
+		return gErr
+	}
+	if bb.readOnly {
+		return getGErrBlk(excNames.ReadOnlyBufferException, "byteBufferArray: the buffer is read-only")
+	}
+	if bb.direct {
+		return getGErrBlk(excNames.UnsupportedOperationException, "byteBufferArray: a direct buffer has no array")
+	}
+	return bb.array
+}
+
+// "java/nio/ByteBuffer.arrayOffset()I"
+func byteBufferArrayOffset(params []interface{}) interface{} {
+	if ret := byteBufferArray(params); ret != nil {
+		if gErr, ok := ret.(*GErrBlk); ok {
+			gErr.ErrMsg = "byteBufferArrayOffset: the buffer has no accessible array"
+			return gErr
+		}
+	}
+	bb, _ := getByteBuffer("byteBufferArrayOffset", params)
+	return bb.offset
+}
+
+// "java/nio/ByteBuffer.hasArray()Z"
+func byteBufferHasArray(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferHasArray", params)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(!bb.readOnly && !bb.direct)
+}
+
+// "java/nio/ByteBuffer.capacity()I"
+func byteBufferCapacity(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferCapacity", params)
+	if gErr != nil {
+		return gErr
+	}
+	return bb.capacity
+}
+
+// "java/nio/ByteBuffer.clear()Ljava/nio/ByteBuffer;"
+func byteBufferClear(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferClear", params)
+	if gErr != nil {
+		return gErr
+	}
+	bb.position = 0
+	bb.limit = bb.capacity
+	bb.mark = -1
+	return params[0]
+}
+
+// "java/nio/ByteBuffer.flip()Ljava/nio/ByteBuffer;"
+func byteBufferFlip(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferFlip", params)
+	if gErr != nil {
+		return gErr
+	}
+	bb.limit = bb.position
+	bb.position = 0
+	bb.mark = -1
+	return params[0]
+}
+
+// "java/nio/ByteBuffer.rewind()Ljava/nio/ByteBuffer;"
+func byteBufferRewind(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferRewind", params)
+	if gErr != nil {
+		return gErr
+	}
+	bb.position = 0
+	bb.mark = -1
+	return params[0]
+}
+
+// "java/nio/ByteBuffer.hasRemaining()Z"
+func byteBufferHasRemaining(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferHasRemaining", params)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(bb.remaining() > 0)
+}
+
+// "java/nio/ByteBuffer.remaining()I"
+func byteBufferRemaining(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferRemaining", params)
+	if gErr != nil {
+		return gErr
+	}
+	return bb.remaining()
+}
+
+// "java/nio/ByteBuffer.isDirect()Z"
+func byteBufferIsDirect(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferIsDirect", params)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(bb.direct)
+}
+
+// "java/nio/ByteBuffer.isReadOnly()Z"
+func byteBufferIsReadOnly(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferIsReadOnly", params)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(bb.readOnly)
+}
+
+// "java/nio/ByteBuffer.limit()I"
+func byteBufferLimit(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferLimit", params)
+	if gErr != nil {
+		return gErr
+	}
+	return bb.limit
+}
+
+// "java/nio/ByteBuffer.limit(I)Ljava/nio/ByteBuffer;" -- the position and the mark are
+// pulled back if they are past the new limit
+func byteBufferSetLimit(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferSetLimit", params)
+	if gErr != nil {
+		return gErr
+	}
+	newLimit, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("byteBufferSetLimit", err)
+	}
+	if newLimit < 0 || newLimit > bb.capacity {
+		errMsg := fmt.Sprintf("byteBufferSetLimit: newLimit %d is not in [0, %d]", newLimit, bb.capacity)
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	bb.limit = newLimit
+	if bb.position > newLimit {
+		bb.position = newLimit
+	}
+	if bb.mark > newLimit {
+		bb.mark = -1
+	}
+	return params[0]
+}
+
+// "java/nio/ByteBuffer.mark()Ljava/nio/ByteBuffer;"
+func byteBufferMark(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferMark", params)
+	if gErr != nil {
+		return gErr
+	}
+	bb.mark = bb.position
+	return params[0]
+}
+
+// "java/nio/ByteBuffer.reset()Ljava/nio/ByteBuffer;"
+func byteBufferReset(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferReset", params)
+	if gErr != nil {
+		return gErr
+	}
+	if bb.mark < 0 {
+		return getGErrBlk(excNames.InvalidMarkException, "byteBufferReset: the mark is not set")
+	}
+	bb.position = bb.mark
+	return params[0]
+}
+
+// "java/nio/ByteBuffer.position()I"
+func byteBufferPosition(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferPosition", params)
+	if gErr != nil {
+		return gErr
+	}
+	return bb.position
+}
+
+// "java/nio/ByteBuffer.position(I)Ljava/nio/ByteBuffer;"
+func byteBufferSetPosition(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferSetPosition", params)
+	if gErr != nil {
+		return gErr
+	}
+	newPosition, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("byteBufferSetPosition", err)
+	}
+	if newPosition < 0 || newPosition > bb.limit {
+		errMsg := fmt.Sprintf("byteBufferSetPosition: newPosition %d is not in [0, %d]", newPosition, bb.limit)
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	bb.position = newPosition
+	if bb.mark > newPosition {
+		bb.mark = -1
+	}
+	return params[0]
+}
+
+// "java/nio/ByteBuffer.order()Ljava/nio/ByteOrder;"
+func byteBufferOrder(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferOrder", params)
+	if gErr != nil {
+		return gErr
+	}
+	return byteOrderObject(bb.bigEndian)
+}
+
+// "java/nio/ByteBuffer.order(Ljava/nio/ByteOrder;)Ljava/nio/ByteBuffer;" -- as in the JDK,
+// any order but BIG_ENDIAN, even null, is taken to be LITTLE_ENDIAN
+func byteBufferSetOrder(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferSetOrder", params)
+	if gErr != nil {
+		return gErr
+	}
+	order, err := args.GetObjectOrNull(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("byteBufferSetOrder", err)
+	}
+	bb.bigEndian = order != object.Null && byteOrderIsBigEndian(order)
+	return params[0]
+}
+
+// "java/nio/ByteBuffer.get()B"
+func byteBufferGet(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferGet", params)
+	if gErr != nil {
+		return gErr
+	}
+	index, gErr := bb.nextGetIndex("byteBufferGet", 1)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(bb.bytes()[index])
+}
+
+// "java/nio/ByteBuffer.get(I)B"
+func byteBufferGetAt(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferGetAt", params)
+	if gErr != nil {
+		return gErr
+	}
+	index, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("byteBufferGetAt", err)
+	}
+	if gErr = bb.checkIndex("byteBufferGetAt", index, 1); gErr != nil {
+		return gErr
+	}
+	return int64(bb.bytes()[index])
+}
+
+// "java/nio/ByteBuffer.get([B)Ljava/nio/ByteBuffer;"
+func byteBufferGetArray(params []interface{}) interface{} {
+	javaBytes, err := args.GetByteArray(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("byteBufferGetArray", err)
+	}
+	return byteBufferGetArrayOffset([]interface{}{params[0], params[1], int64(0), int64(len(javaBytes))})
+}
+
+// "java/nio/ByteBuffer.get([BII)Ljava/nio/ByteBuffer;" -- copies length bytes into the
+// array, at offset
+func byteBufferGetArrayOffset(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferGetArrayOffset", params)
+	if gErr != nil {
+		return gErr
+	}
+	javaBytes, offset, length, gErr := byteArrayRange("byteBufferGetArrayOffset", params)
+	if gErr != nil {
+		return gErr
+	}
+	index, gErr := bb.nextGetIndex("byteBufferGetArrayOffset", length)
+	if gErr != nil {
+		return gErr
+	}
+	copy(javaBytes[offset:offset+length], bb.bytes()[index:])
+	return params[0]
+}
+
+// "java/nio/ByteBuffer.put(B)Ljava/nio/ByteBuffer;"
+func byteBufferPut(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferPut", params)
+	if gErr != nil {
+		return gErr
+	}
+	value, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("byteBufferPut", err)
+	}
+	index, gErr := bb.nextPutIndex("byteBufferPut", 1)
+	if gErr != nil {
+		return gErr
+	}
+	bb.bytes()[index] = types.JavaByte(value)
+	if gErr = bb.stored("byteBufferPut", index, 1); gErr != nil {
+		return gErr
+	}
+	return params[0]
+}
+
+// "java/nio/ByteBuffer.put(IB)Ljava/nio/ByteBuffer;"
+func byteBufferPutAt(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferPutAt", params)
+	if gErr != nil {
+		return gErr
+	}
+	index, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("byteBufferPutAt", err)
+	}
+	value, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("byteBufferPutAt", err)
+	}
+	if bb.readOnly {
+		return getGErrBlk(excNames.ReadOnlyBufferException, "byteBufferPutAt: the buffer is read-only")
+	}
+	if gErr = bb.checkIndex("byteBufferPutAt", index, 1); gErr != nil {
+		return gErr
+	}
+	bb.bytes()[index] = types.JavaByte(value)
+	if gErr = bb.stored("byteBufferPutAt", index, 1); gErr != nil {
+		return gErr
+	}
+	return params[0]
+}
+
+// "java/nio/ByteBuffer.put([B)Ljava/nio/ByteBuffer;"
+func byteBufferPutArray(params []interface{}) interface{} {
+	javaBytes, err := args.GetByteArray(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("byteBufferPutArray", err)
+	}
+	return byteBufferPutArrayOffset([]interface{}{params[0], params[1], int64(0), int64(len(javaBytes))})
+}
+
+// "java/nio/ByteBuffer.put([BII)Ljava/nio/ByteBuffer;" -- copies length bytes from the
+// array, at offset
+func byteBufferPutArrayOffset(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferPutArrayOffset", params)
+	if gErr != nil {
+		return gErr
+	}
+	javaBytes, offset, length, gErr := byteArrayRange("byteBufferPutArrayOffset", params)
+	if gErr != nil {
+		return gErr
+	}
+	index, gErr := bb.nextPutIndex("byteBufferPutArrayOffset", length)
+	if gErr != nil {
+		return gErr
+	}
+	copy(bb.bytes()[index:], javaBytes[offset:offset+length])
+	if gErr = bb.stored("byteBufferPutArrayOffset", index, length); gErr != nil {
+		return gErr
+	}
+	return params[0]
+}
+
+// "java/nio/ByteBuffer.put(Ljava/nio/ByteBuffer;)Ljava/nio/ByteBuffer;" -- copies the
+// remaining bytes of the source buffer, advancing the positions of both buffers
+func byteBufferPutBuffer(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferPutBuffer", params)
+	if gErr != nil {
+		return gErr
+	}
+	src, gErr := getByteBuffer("byteBufferPutBuffer", params[1:])
+	if gErr != nil {
+		return gErr
+	}
+	if src == bb {
+		return getGErrBlk(excNames.IllegalArgumentException, "byteBufferPutBuffer: The source buffer is this buffer")
+	}
+	length := src.remaining()
+	index, gErr := bb.nextPutIndex("byteBufferPutBuffer", length)
+	if gErr != nil {
+		return gErr
+	}
+	copy(bb.bytes()[index:], src.bytes()[src.position:src.limit])
+	src.position = src.limit
+	if gErr = bb.stored("byteBufferPutBuffer", index, length); gErr != nil {
+		return gErr
+	}
+	return params[0]
+}
+
+// returns the byte array in params[1] and the offset and length in params[2] and
+// params[3], which must lie within the array
+func byteArrayRange(funcName string, params []interface{}) ([]types.JavaByte, int64, int64, *GErrBlk) {
+	javaBytes, err := args.GetByteArray(params, 1)
+	if err != nil {
+		return nil, 0, 0, getArgsGErrBlk(funcName, err)
+	}
+	offset, err := args.GetInt64(params, 2)
+	if err != nil {
+		return nil, 0, 0, getArgsGErrBlk(funcName, err)
+	}
+	length, err := args.GetInt64(params, 3)
+	if err != nil {
+		return nil, 0, 0, getArgsGErrBlk(funcName, err)
+	}
+	if offset < 0 || length < 0 || offset > int64(len(javaBytes))-length {
+		errMsg := fmt.Sprintf("%s: Range [%d, %d + %d) out of bounds for length %d",
+			funcName, offset, offset, length, len(javaBytes))
+		return nil, 0, 0, getGErrBlk(excNames.IndexOutOfBoundsException, errMsg)
+	}
+	return javaBytes, offset, length, nil
+}
+
+// returns the byte order of the buffer as a Go binary.ByteOrder
+func (bb *byteBuffer) byteOrder() binary.ByteOrder {
+	if bb.bigEndian {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}
+
+// byteBufferGetPrimitive returns the G function that reads a value of the given type and
+// size in bytes, either at the position (relative) or at the index in params[1] (absolute)
+func byteBufferGetPrimitive(primitive byte, size int64, absolute bool) func([]interface{}) interface{} {
+	return func(params []interface{}) interface{} {
+		funcName := "byteBufferGetPrimitive"
+		bb, gErr := getByteBuffer(funcName, params)
+		if gErr != nil {
+			return gErr
+		}
+		var index int64
+		if absolute {
+			var err error
+			if index, err = args.GetInt64(params, 1); err != nil {
+				return getArgsGErrBlk(funcName, err)
+			}
+			if gErr = bb.checkIndex(funcName, index, size); gErr != nil {
+				return gErr
+			}
+		} else if index, gErr = bb.nextGetIndex(funcName, size); gErr != nil {
+			return gErr
+		}
+
+		data := object.GoByteArrayFromJavaByteArray(bb.bytes()[index : index+size])
+		order := bb.byteOrder()
+		switch primitive {
+		case 'C':
+			return int64(order.Uint16(data))
+		case 'S':
+			return int64(int16(order.Uint16(data)))
+		case 'I':
+			return int64(int32(order.Uint32(data)))
+		case 'J':
+			return int64(order.Uint64(data))
+		case 'F':
+			return float64(math.Float32frombits(order.Uint32(data)))
+		default: // 'D'
+			return math.Float64frombits(order.Uint64(data))
+		}
+	}
+}
+
+// byteBufferPutPrimitive returns the G function that writes a value of the given type and
+// size in bytes, either at the position (relative) or at the index in params[1] (absolute)
+func byteBufferPutPrimitive(primitive byte, size int64, absolute bool) func([]interface{}) interface{} {
+	return func(params []interface{}) interface{} {
+		funcName := "byteBufferPutPrimitive"
+		bb, gErr := getByteBuffer(funcName, params)
+		if gErr != nil {
+			return gErr
+		}
+		valueIndex := 1
+		var index int64
+		if absolute {
+			var err error
+			if index, err = args.GetInt64(params, 1); err != nil {
+				return getArgsGErrBlk(funcName, err)
+			}
+			if bb.readOnly {
+				return getGErrBlk(excNames.ReadOnlyBufferException, funcName+": the buffer is read-only")
+			}
+			if gErr = bb.checkIndex(funcName, index, size); gErr != nil {
+				return gErr
+			}
+			valueIndex = 2
+		}
+
+		data := make([]byte, size)
+		order := bb.byteOrder()
+		if primitive == 'F' || primitive == 'D' {
+			value, err := args.GetFloat64(params, valueIndex)
+			if err != nil {
+				return getArgsGErrBlk(funcName, err)
+			}
+			if primitive == 'F' {
+				order.PutUint32(data, math.Float32bits(float32(value)))
+			} else {
+				order.PutUint64(data, math.Float64bits(value))
+			}
+		} else {
+			value, err := args.GetInt64(params, valueIndex)
+			if err != nil {
+				return getArgsGErrBlk(funcName, err)
+			}
+			switch size {
+			case 2:
+				order.PutUint16(data, uint16(value))
+			case 4:
+				order.PutUint32(data, uint32(value))
+			default:
+				order.PutUint64(data, uint64(value))
+			}
+		}
+
+		if !absolute {
+			if index, gErr = bb.nextPutIndex(funcName, size); gErr != nil {
+				return gErr
+			}
+		}
+		copy(bb.bytes()[index:], object.JavaByteArrayFromGoByteArray(data))
+		if gErr = bb.stored(funcName, index, size); gErr != nil {
+			return gErr
+		}
+		return params[0]
+	}
+}
+
+// "java/nio/ByteBuffer.slice()Ljava/nio/ByteBuffer;" -- a big-endian buffer over the
+// remaining bytes of this one
+func byteBufferSlice(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferSlice", params)
+	if gErr != nil {
+		return gErr
+	}
+	return makeByteBuffer(bb.sliceOf(bb.position, bb.remaining()))
+}
+
+// "java/nio/ByteBuffer.slice(II)Ljava/nio/ByteBuffer;"
+func byteBufferSliceRange(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferSliceRange", params)
+	if gErr != nil {
+		return gErr
+	}
+	index, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("byteBufferSliceRange", err)
+	}
+	length, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("byteBufferSliceRange", err)
+	}
+	if index < 0 || length < 0 || index > bb.limit-length {
+		errMsg := fmt.Sprintf("byteBufferSliceRange: Range [%d, %d + %d) out of bounds for length %d",
+			index, index, length, bb.limit)
+		return getGErrBlk(excNames.IndexOutOfBoundsException, errMsg)
+	}
+	return makeByteBuffer(bb.sliceOf(index, length))
+}
+
+// sliceOf returns a buffer over the length bytes of bb at index
+func (bb *byteBuffer) sliceOf(index, length int64) *byteBuffer {
+	slice := newByteBuffer(bb.array, bb.offset+index, length)
+	slice.readOnly = bb.readOnly
+	slice.direct = bb.direct
+	slice.mapFile = bb.mapFile
+	slice.mapStart = bb.mapStart + index
+	return slice
+}
+
+// "java/nio/ByteBuffer.duplicate()Ljava/nio/ByteBuffer;"
+func byteBufferDuplicate(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferDuplicate", params)
+	if gErr != nil {
+		return gErr
+	}
+	return makeByteBuffer(bb.copyOf())
+}
+
+// "java/nio/ByteBuffer.asReadOnlyBuffer()Ljava/nio/ByteBuffer;"
+func byteBufferAsReadOnlyBuffer(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferAsReadOnlyBuffer", params)
+	if gErr != nil {
+		return gErr
+	}
+	dup := bb.copyOf()
+	dup.readOnly = true
+	return makeByteBuffer(dup)
+}
+
+// "java/nio/ByteBuffer.compact()Ljava/nio/ByteBuffer;" -- moves the remaining bytes to
+// the start of the buffer, and positions the buffer after them
+func byteBufferCompact(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferCompact", params)
+	if gErr != nil {
+		return gErr
+	}
+	if bb.readOnly {
+		return getGErrBlk(excNames.ReadOnlyBufferException, "byteBufferCompact: the buffer is read-only")
+	}
+	remaining := bb.remaining()
+	copy(bb.bytes(), bb.bytes()[bb.position:bb.limit])
+	if gErr = bb.stored("byteBufferCompact", 0, remaining); gErr != nil {
+		return gErr
+	}
+	bb.position = remaining
+	bb.limit = bb.capacity
+	bb.mark = -1
+	return params[0]
+}
+
+// "java/nio/ByteBuffer.equals(Ljava/lang/Object;)Z" -- two buffers are equal if their
+// remaining bytes are
+func byteBufferEquals(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferEquals", params)
+	if gErr != nil {
+		return gErr
+	}
+	other, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(other) {
+		return types.JavaBoolFalse
+	}
+	that, ok := other.FieldTable[fieldNameByteBuffer].Fvalue.(*byteBuffer)
+	if !ok {
+		return types.JavaBoolFalse
+	}
+	return types.ConvertGoBoolToJavaBool(
+		object.JavaByteArrayEquals(bb.bytes()[bb.position:bb.limit], that.bytes()[that.position:that.limit]))
+}
+
+// "java/nio/ByteBuffer.compareTo(Ljava/nio/ByteBuffer;)I" -- compares the remaining bytes
+// of the two buffers, as signed bytes
+func byteBufferCompareTo(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferCompareTo", params)
+	if gErr != nil {
+		return gErr
+	}
+	that, gErr := getByteBuffer("byteBufferCompareTo", params[1:])
+	if gErr != nil {
+		return gErr
+	}
+	these, those := bb.bytes()[bb.position:bb.limit], that.bytes()[that.position:that.limit]
+	for i := 0; i < len(these) && i < len(those); i++ {
+		if these[i] != those[i] {
+			return int64(these[i]) - int64(those[i])
+		}
+	}
+	return int64(len(these) - len(those))
+}
+
+// "java/nio/ByteBuffer.hashCode()I" -- computed, as in the JDK, from the remaining bytes
+func byteBufferHashCode(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferHashCode", params)
+	if gErr != nil {
+		return gErr
+	}
+	hash := int32(1)
+	bytes := bb.bytes()
+	for i := bb.limit - 1; i >= bb.position; i-- {
+		hash = 31*hash + int32(bytes[i])
+	}
+	return int64(hash)
+}
+
+// "java/nio/ByteBuffer.toString()Ljava/lang/String;" -- names the JDK's implementation class
+func byteBufferToString(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("byteBufferToString", params)
+	if gErr != nil {
+		return gErr
+	}
+	className := "java.nio.HeapByteBuffer"
+	if bb.direct {
+		className = "java.nio.DirectByteBuffer"
+	}
+	if bb.readOnly {
+		className += "R"
+	}
+	str := fmt.Sprintf("%s[pos=%d lim=%d cap=%d]", className, bb.position, bb.limit, bb.capacity)
+	return object.StringObjectFromGoString(str)
+}
+
+// "java/nio/MappedByteBuffer.force()Ljava/nio/MappedByteBuffer;" -- the puts have been
+// written to the file already, so this only syncs it
+func mappedByteBufferForce(params []interface{}) interface{} {
+	bb, gErr := getByteBuffer("mappedByteBufferForce", params)
+	if gErr != nil {
+		return gErr
+	}
+	if bb.mapFile != nil {
+		if err := bb.mapFile.Sync(); err != nil {
+			errMsg := fmt.Sprintf("mappedByteBufferForce: Sync failed, reason: %s", err.Error())
+			return getGErrBlk(excNames.IOException, errMsg)
+		}
+	}
+	return params[0]
+}
+
+// ByteOrder has two constants, BIG_ENDIAN and LITTLE_ENDIAN, which its <clinit> creates
+// and holds as statics, so that Java code comparing them with == sees the same objects
+// that ByteBuffer.order() returns.
+
+var byteOrderNames = map[bool]string{true: "BIG_ENDIAN", false: "LITTLE_ENDIAN"}
+
+// "java/nio/ByteOrder.<clinit>()V" -- create the constants
+func byteOrderClinit([]interface{}) interface{} {
+	klass := classloader.MethAreaFetch(classNameByteOrder)
+	if klass == nil || klass.Data == nil {
+		errMsg := fmt.Sprintf("byteOrderClinit: Expected %s to be in the MethodArea, but it was not", classNameByteOrder)
+		trace.Error(errMsg)
+		return getGErrBlk(excNames.ClassNotLoadedException, errMsg)
+	}
+	if klass.Data.ClInit != types.ClInitRun {
+		for bigEndian, name := range byteOrderNames {
+			_ = statics.AddStatic(classNameByteOrder+"."+name,
+				statics.Static{Type: "Ljava/nio/ByteOrder;", Value: makeByteOrder(bigEndian)})
+		}
+		klass.Data.ClInit = types.ClInitRun
+	}
+	return nil
+}
+
+// makeByteOrder creates a ByteOrder object
+func makeByteOrder(bigEndian bool) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&classNameByteOrder)
+	obj.FieldTable["name"] = object.Field{
+		Ftype: types.StringClassRef, Fvalue: object.StringObjectFromGoString(byteOrderNames[bigEndian])}
+	return obj
+}
+
+// byteOrderObject returns the ByteOrder constant. Once <clinit> has run, this is the
+// static; before then, a new object is made.
+func byteOrderObject(bigEndian bool) *object.Object {
+	if static, ok := statics.Statics[classNameByteOrder+"."+byteOrderNames[bigEndian]]; ok {
+		if obj, ok := static.Value.(*object.Object); ok {
+			return obj
+		}
+	}
+	return makeByteOrder(bigEndian)
+}
+
+// byteOrderIsBigEndian reports whether the ByteOrder object is BIG_ENDIAN
+func byteOrderIsBigEndian(order *object.Object) bool {
+	name, ok := order.FieldTable["name"].Fvalue.(*object.Object)
+	return ok && object.GoStringFromStringObject(name) == byteOrderNames[true]
+}
+
+// "java/nio/ByteOrder.nativeOrder()Ljava/nio/ByteOrder;"
+func byteOrderNativeOrder([]interface{}) interface{} {
+	probe := []byte{0, 1}
+	return byteOrderObject(binary.NativeEndian.Uint16(probe) == binary.BigEndian.Uint16(probe))
+}
+
+// "java/nio/ByteOrder.toString()Ljava/lang/String;"
+func byteOrderToString(params []interface{}) interface{} {
+	order, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("byteOrderToString", err)
+	}
+	return object.StringObjectFromGoString(byteOrderNames[byteOrderIsBigEndian(order)])
+}
@@ -0,0 +1,164 @@
+package gfunction
+
+import (
+	"testing"
+
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+)
+
+// returns a ByteBuffer made by allocate(), failing the test if it can't be
+func allocateBuffer(t *testing.T, capacity int64) *object.Object {
+	t.Helper()
+	obj, ok := byteBufferAllocate([]interface{}{capacity}).(*object.Object)
+	if !ok {
+		t.Fatalf("byteBufferAllocate(%d) did not return a ByteBuffer", capacity)
+	}
+	return obj
+}
+
+// checks that ret is a GErrBlk for the given exception
+func expectException(t *testing.T, ret interface{}, exceptionType int, what string) {
+	t.Helper()
+	if gErr, ok := ret.(*GErrBlk); !ok || gErr.ExceptionType != exceptionType {
+		t.Errorf("%s: expected %s, got %v", what, excNames.JVMexceptionNames[exceptionType], ret)
+	}
+}
+
+func TestByteBuffer_PutFlipGet(t *testing.T) {
+	globals.InitStringPool()
+	buf := allocateBuffer(t, 8)
+
+	byteBufferPut([]interface{}{buf, int64(-2)})
+	byteBufferPut([]interface{}{buf, int64(7)})
+	if pos := byteBufferPosition([]interface{}{buf}); pos != int64(2) {
+		t.Errorf("Expected position 2 after two puts, got %v", pos)
+	}
+
+	byteBufferFlip([]interface{}{buf})
+	if lim := byteBufferLimit([]interface{}{buf}); lim != int64(2) {
+		t.Errorf("Expected limit 2 after flip, got %v", lim)
+	}
+	if b := byteBufferGet([]interface{}{buf}); b != int64(-2) {
+		t.Errorf("Expected -2, got %v", b)
+	}
+	if b := byteBufferGet([]interface{}{buf}); b != int64(7) {
+		t.Errorf("Expected 7, got %v", b)
+	}
+	if has := byteBufferHasRemaining([]interface{}{buf}); has != types.JavaBoolFalse {
+		t.Errorf("Expected no bytes to remain")
+	}
+	expectException(t, byteBufferGet([]interface{}{buf}), excNames.BufferUnderflowException, "get past the limit")
+	expectException(t, byteBufferPut([]interface{}{buf, int64(1)}), excNames.BufferOverflowException, "put past the limit")
+	expectException(t, byteBufferGetAt([]interface{}{buf, int64(2)}), excNames.IndexOutOfBoundsException, "get(2)")
+
+	str := object.GoStringFromStringObject(byteBufferToString([]interface{}{buf}).(*object.Object))
+	if str != "java.nio.HeapByteBuffer[pos=2 lim=2 cap=8]" {
+		t.Errorf("Unexpected toString(): %s", str)
+	}
+}
+
+func TestByteBuffer_PrimitivesAndOrder(t *testing.T) {
+	globals.InitStringPool()
+	buf := allocateBuffer(t, 16)
+
+	byteBufferPutPrimitive('I', 4, false)([]interface{}{buf, int64(0x01020304)})
+	if b := byteBufferGetAt([]interface{}{buf, int64(0)}); b != int64(1) {
+		t.Errorf("Expected big-endian order by default, got first byte %v", b)
+	}
+
+	byteBufferSetOrder([]interface{}{buf, byteOrderObject(false)})
+	if byteOrderIsBigEndian(byteBufferOrder([]interface{}{buf}).(*object.Object)) {
+		t.Errorf("Expected the order to be LITTLE_ENDIAN")
+	}
+	byteBufferPutPrimitive('S', 2, false)([]interface{}{buf, int64(-3)})
+	byteBufferPutPrimitive('D', 8, false)([]interface{}{buf, 2.5})
+	byteBufferPutPrimitive('C', 2, true)([]interface{}{buf, int64(14), int64(0xFFFE)})
+	if b := byteBufferGetAt([]interface{}{buf, int64(4)}); b != int64(-3) {
+		t.Errorf("Expected little-endian short, got first byte %v", b)
+	}
+	if v := byteBufferGetPrimitive('C', 2, true)([]interface{}{buf, int64(14)}); v != int64(0xFFFE) {
+		t.Errorf("Expected char 0xFFFE, got %v", v)
+	}
+
+	byteBufferFlip([]interface{}{buf})
+	if v := byteBufferGetPrimitive('I', 4, false)([]interface{}{buf}); v != int64(0x04030201) {
+		t.Errorf("Expected 0x04030201 read little-endian, got %#x", v)
+	}
+	if v := byteBufferGetPrimitive('S', 2, false)([]interface{}{buf}); v != int64(-3) {
+		t.Errorf("Expected -3, got %v", v)
+	}
+	if v := byteBufferGetPrimitive('D', 8, false)([]interface{}{buf}); v != 2.5 {
+		t.Errorf("Expected 2.5, got %v", v)
+	}
+	expectException(t, byteBufferGetPrimitive('J', 8, true)([]interface{}{buf, int64(8)}),
+		excNames.IndexOutOfBoundsException, "getLong(8) with a limit of 14")
+}
+
+func TestByteBuffer_WrapSliceAndReadOnly(t *testing.T) {
+	globals.InitStringPool()
+	array := object.Make1DimArray(object.BYTE, 6)
+	buf, ok := byteBufferWrapOffset([]interface{}{array, int64(1), int64(4)}).(*object.Object)
+	if !ok {
+		t.Fatalf("byteBufferWrapOffset failed")
+	}
+	if pos, lim := byteBufferPosition([]interface{}{buf}), byteBufferLimit([]interface{}{buf}); pos != int64(1) || lim != int64(5) {
+		t.Errorf("Expected position 1 and limit 5, got %v and %v", pos, lim)
+	}
+
+	slice := byteBufferSlice([]interface{}{buf}).(*object.Object)
+	byteBufferPut([]interface{}{slice, int64(9)})
+	if b := array.FieldTable["value"].Fvalue.([]types.JavaByte)[1]; b != 9 {
+		t.Errorf("Expected a put to the slice to be seen in the wrapped array, got %d", b)
+	}
+	if capacity := byteBufferCapacity([]interface{}{slice}); capacity != int64(4) {
+		t.Errorf("Expected the slice's capacity to be 4, got %v", capacity)
+	}
+
+	readOnly := byteBufferAsReadOnlyBuffer([]interface{}{buf}).(*object.Object)
+	if b := byteBufferGet([]interface{}{readOnly}); b != int64(9) {
+		t.Errorf("Expected the read-only buffer to share the bytes, got %v", b)
+	}
+	expectException(t, byteBufferPut([]interface{}{readOnly, int64(1)}), excNames.ReadOnlyBufferException, "put")
+	expectException(t, byteBufferArray([]interface{}{readOnly}), excNames.ReadOnlyBufferException, "array()")
+
+	expectException(t, byteBufferWrapOffset([]interface{}{array, int64(4), int64(3)}),
+		excNames.IndexOutOfBoundsException, "wrap past the end of the array")
+	expectException(t, byteBufferAllocate([]interface{}{int64(-1)}), excNames.IllegalArgumentException, "allocate(-1)")
+}
+
+func TestByteBuffer_CompactMarkAndEquals(t *testing.T) {
+	globals.InitStringPool()
+	buf := allocateBuffer(t, 4)
+	src := object.Make1DimArray(object.BYTE, 4)
+	copy(src.FieldTable["value"].Fvalue.([]types.JavaByte), []types.JavaByte{1, 2, 3, 4})
+	byteBufferPutArray([]interface{}{buf, src})
+	byteBufferFlip([]interface{}{buf})
+	byteBufferGet([]interface{}{buf})
+
+	byteBufferMark([]interface{}{buf})
+	byteBufferGet([]interface{}{buf})
+	byteBufferReset([]interface{}{buf})
+	if pos := byteBufferPosition([]interface{}{buf}); pos != int64(1) {
+		t.Errorf("Expected reset() to return to position 1, got %v", pos)
+	}
+
+	other, _ := byteBufferWrapOffset([]interface{}{src, int64(1), int64(3)}).(*object.Object)
+	if byteBufferEquals([]interface{}{buf, other}) != types.JavaBoolTrue {
+		t.Errorf("Expected buffers with the same remaining bytes to be equal")
+	}
+	if byteBufferHashCode([]interface{}{buf}) != byteBufferHashCode([]interface{}{other}) {
+		t.Errorf("Expected equal buffers to have equal hash codes")
+	}
+
+	byteBufferCompact([]interface{}{buf})
+	if pos, lim := byteBufferPosition([]interface{}{buf}), byteBufferLimit([]interface{}{buf}); pos != int64(3) || lim != int64(4) {
+		t.Errorf("Expected position 3 and limit 4 after compact(), got %v and %v", pos, lim)
+	}
+	if b := byteBufferGetAt([]interface{}{buf, int64(0)}); b != int64(2) {
+		t.Errorf("Expected compact() to move byte 2 to the start, got %v", b)
+	}
+	expectException(t, byteBufferReset([]interface{}{buf}), excNames.InvalidMarkException, "reset() after compact()")
+}
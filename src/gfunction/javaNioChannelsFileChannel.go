@@ -0,0 +1,573 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
+	"jacobin/src/object"
+	"jacobin/src/replay"
+	"jacobin/src/statics"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+	"os"
+)
+
+// A FileChannel is obtained from a FileInputStream, a FileOutputStream, or a
+// RandomAccessFile, and shares its os.File. So, as in the JDK, the channel's position
+// is the file position of the stream, and closing the one closes the other. Reads and
+// writes move bytes between the file and the remaining bytes of a ByteBuffer.
+
+var classNameFileChannel = "java/nio/channels/FileChannel"
+var classNameMapMode = "java/nio/channels/FileChannel$MapMode"
+
+var fieldNameFileChannel = "channel"
+
+type fileChannel struct {
+	file     *os.File
+	readable bool
+	writable bool
+	closed   bool
+}
+
+func Load_Nio_Channels_FileChannel() {
+
+	MethodSignatures["java/nio/channels/FileChannel.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/nio/channels/FileChannel.close()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  fileChannelClose,
+		}
+
+	MethodSignatures["java/nio/channels/FileChannel.force(Z)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  fileChannelForce,
+		}
+
+	MethodSignatures["java/nio/channels/FileChannel.isOpen()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  fileChannelIsOpen,
+		}
+
+	MethodSignatures["java/nio/channels/FileChannel.map(Ljava/nio/channels/FileChannel$MapMode;JJ)Ljava/nio/MappedByteBuffer;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  fileChannelMap,
+		}
+
+	MethodSignatures["java/nio/channels/FileChannel.position()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  fileChannelPosition,
+		}
+
+	MethodSignatures["java/nio/channels/FileChannel.position(J)Ljava/nio/channels/FileChannel;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  fileChannelSetPosition,
+		}
+
+	MethodSignatures["java/nio/channels/FileChannel.read(Ljava/nio/ByteBuffer;)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  fileChannelRead,
+		}
+
+	MethodSignatures["java/nio/channels/FileChannel.read(Ljava/nio/ByteBuffer;J)I"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  fileChannelReadAt,
+		}
+
+	MethodSignatures["java/nio/channels/FileChannel.size()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  fileChannelSize,
+		}
+
+	MethodSignatures["java/nio/channels/FileChannel.truncate(J)Ljava/nio/channels/FileChannel;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  fileChannelTruncate,
+		}
+
+	MethodSignatures["java/nio/channels/FileChannel.write(Ljava/nio/ByteBuffer;)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  fileChannelWrite,
+		}
+
+	MethodSignatures["java/nio/channels/FileChannel.write(Ljava/nio/ByteBuffer;J)I"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  fileChannelWriteAt,
+		}
+
+	MethodSignatures["java/nio/channels/FileChannel$MapMode.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  mapModeClinit,
+		}
+
+	MethodSignatures["java/nio/channels/FileChannel$MapMode.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  mapModeToString,
+		}
+}
+
+// "java/io/FileInputStream.getChannel()Ljava/nio/channels/FileChannel;"
+func fisGetChannel(params []interface{}) interface{} {
+	return getChannelOf("fisGetChannel", params, true, false)
+}
+
+// "java/io/FileOutputStream.getChannel()Ljava/nio/channels/FileChannel;"
+func fosGetChannel(params []interface{}) interface{} {
+	return getChannelOf("fosGetChannel", params, false, true)
+}
+
+// "java/io/RandomAccessFile.getChannel()Ljava/nio/channels/FileChannel;" -- the channel
+// may be written unless the file was opened in "r" mode
+func rafGetChannel(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("rafGetChannel", err)
+	}
+	mode, _ := obj.FieldTable[FileMode].Fvalue.(string)
+	return getChannelOf("rafGetChannel", params, true, mode != "r")
+}
+
+// getChannelOf returns the FileChannel of the stream or RandomAccessFile in params[0],
+// creating it on the first call
+func getChannelOf(funcName string, params []interface{}, readable, writable bool) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk(funcName, err)
+	}
+	if channel, ok := obj.FieldTable[FileChannel].Fvalue.(*object.Object); ok {
+		return channel
+	}
+	osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File)
+	if !ok {
+		return getGErrBlk(excNames.IOException, funcName+": the object lacks a FileHandle field")
+	}
+
+	channel := object.MakeEmptyObjectWithClassName(&classNameFileChannel)
+	channel.FieldTable[FileHandle] = object.Field{Ftype: types.FileHandle, Fvalue: osFile}
+	channel.FieldTable[fieldNameFileChannel] = object.Field{Ftype: types.ChannelState,
+		Fvalue: &fileChannel{file: osFile, readable: readable, writable: writable}}
+	obj.FieldTable[FileChannel] = object.Field{Ftype: types.Ref, Fvalue: channel}
+	return channel
+}
+
+// returns the state of the open FileChannel in params[0]
+func getFileChannel(funcName string, params []interface{}) (*fileChannel, *GErrBlk) {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return nil, getArgsGErrBlk(funcName, err)
+	}
+	fc, ok := obj.FieldTable[fieldNameFileChannel].Fvalue.(*fileChannel)
+	if !ok {
+		return nil, getGErrBlk(excNames.IllegalStateException, funcName+": the FileChannel is not initialized")
+	}
+	if fc.closed {
+		return nil, getGErrBlk(excNames.ClosedChannelException, funcName+": the channel is closed")
+	}
+	return fc, nil
+}
+
+// returns the exception for a failed operation on the channel's file. A file closed by
+// its stream is a closed channel.
+func fileChannelError(funcName string, err error) *GErrBlk {
+	if errors.Is(err, os.ErrClosed) {
+		return getGErrBlk(excNames.ClosedChannelException, funcName+": the channel is closed")
+	}
+	return getGErrBlk(excNames.IOException, fmt.Sprintf("%s: %s", funcName, err.Error()))
+}
+
+// returns an IllegalArgumentException if the position or size in params[index] is negative
+func nonNegativeParam(funcName string, params []interface{}, index int, what string) (int64, *GErrBlk) {
+	value, err := args.GetInt64(params, index)
+	if err != nil {
+		return 0, getArgsGErrBlk(funcName, err)
+	}
+	if value < 0 {
+		return 0, getGErrBlk(excNames.IllegalArgumentException, fmt.Sprintf("%s: Negative %s", funcName, what))
+	}
+	return value, nil
+}
+
+func (fc *fileChannel) checkReadable(funcName string) *GErrBlk {
+	if !fc.readable {
+		return getGErrBlk(excNames.NonReadableChannelException, funcName+": the channel was not opened for reading")
+	}
+	return nil
+}
+
+func (fc *fileChannel) checkWritable(funcName string) *GErrBlk {
+	if !fc.writable {
+		return getGErrBlk(excNames.NonWritableChannelException, funcName+": the channel was not opened for writing")
+	}
+	return nil
+}
+
+// "java/nio/channels/FileChannel.read(Ljava/nio/ByteBuffer;)I" -- reads into the remaining
+// bytes of the buffer at the channel's position, returning -1 at the end of the file
+func fileChannelRead(params []interface{}) interface{} {
+	return fileChannelReadInto("fileChannelRead", params, -1)
+}
+
+// "java/nio/channels/FileChannel.read(Ljava/nio/ByteBuffer;J)I" -- reads at the given
+// file position, leaving the channel's position unchanged
+func fileChannelReadAt(params []interface{}) interface{} {
+	position, gErr := nonNegativeParam("fileChannelReadAt", params, 2, "position")
+	if gErr != nil {
+		return gErr
+	}
+	return fileChannelReadInto("fileChannelReadAt", params, position)
+}
+
+// reads into the buffer in params[1] at position, or at the channel's position if
+// position is -1
+func fileChannelReadInto(funcName string, params []interface{}, position int64) interface{} {
+	fc, gErr := getFileChannel(funcName, params)
+	if gErr != nil {
+		return gErr
+	}
+	if gErr = fc.checkReadable(funcName); gErr != nil {
+		return gErr
+	}
+	dst, gErr := getByteBuffer(funcName, params[1:])
+	if gErr != nil {
+		return gErr
+	}
+	if dst.readOnly {
+		return getGErrBlk(excNames.IllegalArgumentException, funcName+": Read-only buffer")
+	}
+	if dst.remaining() == 0 {
+		return int64(0)
+	}
+
+	buffer := make([]byte, dst.remaining())
+	var nbytes int
+	var err error
+	if position < 0 {
+		nbytes, err = replay.Read(fc.file, buffer)
+	} else {
+		nbytes, err = fc.file.ReadAt(buffer, position)
+	}
+	if err != nil && err != io.EOF {
+		return fileChannelError(funcName, err)
+	}
+	if nbytes == 0 && err == io.EOF {
+		return int64(-1)
+	}
+
+	index := dst.position
+	copy(dst.bytes()[index:], object.JavaByteArrayFromGoByteArray(buffer[:nbytes]))
+	dst.position += int64(nbytes)
+	if gErr = dst.stored(funcName, index, int64(nbytes)); gErr != nil {
+		return gErr
+	}
+	return int64(nbytes)
+}
+
+// "java/nio/channels/FileChannel.write(Ljava/nio/ByteBuffer;)I" -- writes the remaining
+// bytes of the buffer at the channel's position
+func fileChannelWrite(params []interface{}) interface{} {
+	return fileChannelWriteFrom("fileChannelWrite", params, -1)
+}
+
+// "java/nio/channels/FileChannel.write(Ljava/nio/ByteBuffer;J)I" -- writes at the given
+// file position, leaving the channel's position unchanged
+func fileChannelWriteAt(params []interface{}) interface{} {
+	position, gErr := nonNegativeParam("fileChannelWriteAt", params, 2, "position")
+	if gErr != nil {
+		return gErr
+	}
+	return fileChannelWriteFrom("fileChannelWriteAt", params, position)
+}
+
+// writes the buffer in params[1] at position, or at the channel's position if position
+// is -1
+func fileChannelWriteFrom(funcName string, params []interface{}, position int64) interface{} {
+	fc, gErr := getFileChannel(funcName, params)
+	if gErr != nil {
+		return gErr
+	}
+	if gErr = fc.checkWritable(funcName); gErr != nil {
+		return gErr
+	}
+	src, gErr := getByteBuffer(funcName, params[1:])
+	if gErr != nil {
+		return gErr
+	}
+
+	data := object.GoByteArrayFromJavaByteArray(src.bytes()[src.position:src.limit])
+	var nbytes int
+	var err error
+	if position < 0 {
+		nbytes, err = fc.file.Write(data)
+	} else {
+		nbytes, err = fc.file.WriteAt(data, position)
+	}
+	src.position += int64(nbytes)
+	if err != nil {
+		return fileChannelError(funcName, err)
+	}
+	return int64(nbytes)
+}
+
+// "java/nio/channels/FileChannel.position()J"
+func fileChannelPosition(params []interface{}) interface{} {
+	fc, gErr := getFileChannel("fileChannelPosition", params)
+	if gErr != nil {
+		return gErr
+	}
+	position, err := fc.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fileChannelError("fileChannelPosition", err)
+	}
+	return position
+}
+
+// "java/nio/channels/FileChannel.position(J)Ljava/nio/channels/FileChannel;" -- a position
+// past the end of the file is allowed; a write there grows the file
+func fileChannelSetPosition(params []interface{}) interface{} {
+	fc, gErr := getFileChannel("fileChannelSetPosition", params)
+	if gErr != nil {
+		return gErr
+	}
+	position, gErr := nonNegativeParam("fileChannelSetPosition", params, 1, "position")
+	if gErr != nil {
+		return gErr
+	}
+	if _, err := fc.file.Seek(position, io.SeekStart); err != nil {
+		return fileChannelError("fileChannelSetPosition", err)
+	}
+	return params[0]
+}
+
+// "java/nio/channels/FileChannel.size()J"
+func fileChannelSize(params []interface{}) interface{} {
+	fc, gErr := getFileChannel("fileChannelSize", params)
+	if gErr != nil {
+		return gErr
+	}
+	info, err := fc.file.Stat()
+	if err != nil {
+		return fileChannelError("fileChannelSize", err)
+	}
+	return info.Size()
+}
+
+// "java/nio/channels/FileChannel.truncate(J)Ljava/nio/channels/FileChannel;" -- a size
+// larger than the file's leaves the file as is; the position is pulled back to the size
+// if it is past it
+func fileChannelTruncate(params []interface{}) interface{} {
+	fc, gErr := getFileChannel("fileChannelTruncate", params)
+	if gErr != nil {
+		return gErr
+	}
+	size, gErr := nonNegativeParam("fileChannelTruncate", params, 1, "size")
+	if gErr != nil {
+		return gErr
+	}
+	if gErr = fc.checkWritable("fileChannelTruncate"); gErr != nil {
+		return gErr
+	}
+
+	info, err := fc.file.Stat()
+	if err != nil {
+		return fileChannelError("fileChannelTruncate", err)
+	}
+	if size < info.Size() {
+		if err = fc.file.Truncate(size); err != nil {
+			return fileChannelError("fileChannelTruncate", err)
+		}
+	}
+	position, err := fc.file.Seek(0, io.SeekCurrent)
+	if err == nil && position > size {
+		_, err = fc.file.Seek(size, io.SeekStart)
+	}
+	if err != nil {
+		return fileChannelError("fileChannelTruncate", err)
+	}
+	return params[0]
+}
+
+// "java/nio/channels/FileChannel.force(Z)V"
+func fileChannelForce(params []interface{}) interface{} {
+	fc, gErr := getFileChannel("fileChannelForce", params)
+	if gErr != nil {
+		return gErr
+	}
+	if err := fc.file.Sync(); err != nil {
+		return fileChannelError("fileChannelForce", err)
+	}
+	return nil
+}
+
+// "java/nio/channels/FileChannel.isOpen()Z"
+func fileChannelIsOpen(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("fileChannelIsOpen", err)
+	}
+	fc, ok := obj.FieldTable[fieldNameFileChannel].Fvalue.(*fileChannel)
+	return types.ConvertGoBoolToJavaBool(ok && !fc.closed)
+}
+
+// "java/nio/channels/FileChannel.close()V" -- closes the file, and so the stream the
+// channel came from. Closing a closed channel does nothing.
+func fileChannelClose(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("fileChannelClose", err)
+	}
+	fc, ok := obj.FieldTable[fieldNameFileChannel].Fvalue.(*fileChannel)
+	if !ok || fc.closed {
+		return nil
+	}
+	fc.closed = true
+	if err = fc.file.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+		errMsg := fmt.Sprintf("fileChannelClose: osFile.Close() failed, reason: %s", err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	return nil
+}
+
+// "java/nio/channels/FileChannel.map(Ljava/nio/channels/FileChannel$MapMode;JJ)Ljava/nio/MappedByteBuffer;"
+// The region of the file is read into a direct buffer. A READ_ONLY buffer can't be put
+// to; a READ_WRITE buffer writes each put through to the file, which is grown to hold
+// the region if need be; and the puts to a PRIVATE buffer are never written.
+func fileChannelMap(params []interface{}) interface{} {
+	fc, gErr := getFileChannel("fileChannelMap", params)
+	if gErr != nil {
+		return gErr
+	}
+	modeObj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("fileChannelMap", err)
+	}
+	position, gErr := nonNegativeParam("fileChannelMap", params, 2, "position")
+	if gErr != nil {
+		return gErr
+	}
+	size, gErr := nonNegativeParam("fileChannelMap", params, 3, "size")
+	if gErr != nil {
+		return gErr
+	}
+	if size > MaxIntValue {
+		return getGErrBlk(excNames.IllegalArgumentException, "fileChannelMap: Size exceeds Integer.MAX_VALUE")
+	}
+
+	mode := mapModeName(modeObj)
+	if gErr = fc.checkReadable("fileChannelMap"); gErr != nil {
+		return gErr
+	}
+	if mode != mapModeNames[mapModeReadOnly] {
+		if gErr = fc.checkWritable("fileChannelMap"); gErr != nil {
+			return gErr
+		}
+	}
+
+	if mode == mapModeNames[mapModeReadWrite] {
+		info, err := fc.file.Stat()
+		if err != nil {
+			return fileChannelError("fileChannelMap", err)
+		}
+		if info.Size() < position+size {
+			if err = fc.file.Truncate(position + size); err != nil {
+				return fileChannelError("fileChannelMap", err)
+			}
+		}
+	}
+
+	// bytes past the end of the file are left zero
+	buffer := make([]byte, size)
+	if _, err = fc.file.ReadAt(buffer, position); err != nil && err != io.EOF {
+		return fileChannelError("fileChannelMap", err)
+	}
+
+	array := object.Make1DimArray(object.BYTE, size)
+	copy(array.FieldTable["value"].Fvalue.([]types.JavaByte), object.JavaByteArrayFromGoByteArray(buffer))
+	bb := newByteBuffer(array, 0, size)
+	bb.direct = true
+	switch mode {
+	case mapModeNames[mapModeReadOnly]:
+		bb.readOnly = true
+	case mapModeNames[mapModeReadWrite]:
+		bb.mapFile = fc.file
+		bb.mapStart = position
+	}
+	return makeByteBuffer(bb)
+}
+
+// FileChannel.MapMode has three constants, created by its <clinit> and held as statics
+
+const (
+	mapModeReadOnly = iota
+	mapModeReadWrite
+	mapModePrivate
+)
+
+var mapModeNames = []string{"READ_ONLY", "READ_WRITE", "PRIVATE"}
+
+// "java/nio/channels/FileChannel$MapMode.<clinit>()V" -- create the constants
+func mapModeClinit([]interface{}) interface{} {
+	klass := classloader.MethAreaFetch(classNameMapMode)
+	if klass == nil || klass.Data == nil {
+		errMsg := fmt.Sprintf("mapModeClinit: Expected %s to be in the MethodArea, but it was not", classNameMapMode)
+		trace.Error(errMsg)
+		return getGErrBlk(excNames.ClassNotLoadedException, errMsg)
+	}
+	if klass.Data.ClInit != types.ClInitRun {
+		for ordinal := range mapModeNames {
+			_ = statics.AddStatic(classNameMapMode+"."+mapModeNames[ordinal],
+				statics.Static{Type: "Ljava/nio/channels/FileChannel$MapMode;", Value: makeMapMode(ordinal)})
+		}
+		klass.Data.ClInit = types.ClInitRun
+	}
+	return nil
+}
+
+// makeMapMode creates a MapMode object for the given constant
+func makeMapMode(ordinal int) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&classNameMapMode)
+	obj.FieldTable["name"] = object.Field{
+		Ftype: types.StringClassRef, Fvalue: object.StringObjectFromGoString(mapModeNames[ordinal])}
+	return obj
+}
+
+// returns the name of the MapMode object
+func mapModeName(mode *object.Object) string {
+	if name, ok := mode.FieldTable["name"].Fvalue.(*object.Object); ok {
+		return object.GoStringFromStringObject(name)
+	}
+	return ""
+}
+
+// "java/nio/channels/FileChannel$MapMode.toString()Ljava/lang/String;"
+func mapModeToString(params []interface{}) interface{} {
+	mode, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("mapModeToString", err)
+	}
+	return object.StringObjectFromGoString(mapModeName(mode))
+}
@@ -0,0 +1,119 @@
+package gfunction
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+)
+
+// returns the FileChannel of a RandomAccessFile opened in the given mode on a file
+// holding content
+func openChannel(t *testing.T, mode string, content string) (*object.Object, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "channel.dat")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write the test file: %v", err)
+	}
+	raf := object.MakeEmptyObject()
+	if ret := rafOpen("openChannel", raf, path, mode); ret != nil {
+		t.Fatalf("rafOpen failed: %v", ret)
+	}
+	t.Cleanup(func() { _ = raf.FieldTable[FileHandle].Fvalue.(*os.File).Close() })
+	channel, ok := rafGetChannel([]interface{}{raf}).(*object.Object)
+	if !ok {
+		t.Fatalf("rafGetChannel did not return a FileChannel")
+	}
+	if again := rafGetChannel([]interface{}{raf}); again != channel {
+		t.Errorf("Expected getChannel() to return the same channel each time")
+	}
+	return channel, path
+}
+
+func TestFileChannel_ReadWritePosition(t *testing.T) {
+	globals.InitStringPool()
+	channel, path := openChannel(t, "rw", "hello")
+
+	buf := allocateBuffer(t, 3)
+	if n := fileChannelRead([]interface{}{channel, buf}); n != int64(3) {
+		t.Fatalf("Expected to read 3 bytes, got %v", n)
+	}
+	if pos := fileChannelPosition([]interface{}{channel}); pos != int64(3) {
+		t.Errorf("Expected the channel's position to be 3, got %v", pos)
+	}
+	byteBufferClear([]interface{}{buf})
+	if n := fileChannelRead([]interface{}{channel, buf}); n != int64(2) {
+		t.Errorf("Expected to read the last 2 bytes, got %v", n)
+	}
+	if n := fileChannelRead([]interface{}{channel, buf}); n != int64(-1) {
+		t.Errorf("Expected -1 at the end of the file, got %v", n)
+	}
+
+	src, _ := byteBufferWrap([]interface{}{object.MakeArrayFromRawArray([]byte("XY!"))}).(*object.Object)
+	if n := fileChannelWriteAt([]interface{}{channel, src, int64(1)}); n != int64(3) {
+		t.Errorf("Expected to write 3 bytes, got %v", n)
+	}
+	if pos := fileChannelPosition([]interface{}{channel}); pos != int64(5) {
+		t.Errorf("Expected a positional write to leave the position at 5, got %v", pos)
+	}
+	if size := fileChannelSize([]interface{}{channel}); size != int64(5) {
+		t.Errorf("Expected a size of 5, got %v", size)
+	}
+
+	fileChannelTruncate([]interface{}{channel, int64(2)})
+	if pos := fileChannelPosition([]interface{}{channel}); pos != int64(2) {
+		t.Errorf("Expected truncate() to pull the position back to 2, got %v", pos)
+	}
+	content, _ := os.ReadFile(path)
+	if string(content) != "hX" {
+		t.Errorf("Expected the file to hold %q, got %q", "hX", content)
+	}
+
+	fileChannelClose([]interface{}{channel})
+	if fileChannelIsOpen([]interface{}{channel}) != types.JavaBoolFalse {
+		t.Errorf("Expected the channel to be closed")
+	}
+	expectException(t, fileChannelSize([]interface{}{channel}), excNames.ClosedChannelException, "size() after close()")
+}
+
+func TestFileChannel_ReadOnlyChannel(t *testing.T) {
+	globals.InitStringPool()
+	channel, _ := openChannel(t, "r", "data")
+
+	buf := allocateBuffer(t, 4)
+	expectException(t, fileChannelWrite([]interface{}{channel, buf}), excNames.NonWritableChannelException, "write()")
+	expectException(t, fileChannelMap([]interface{}{channel, makeMapMode(mapModeReadWrite), int64(0), int64(4)}),
+		excNames.NonWritableChannelException, "map(READ_WRITE)")
+
+	mapped, ok := fileChannelMap([]interface{}{channel, makeMapMode(mapModeReadOnly), int64(1), int64(3)}).(*object.Object)
+	if !ok {
+		t.Fatalf("map(READ_ONLY) did not return a buffer")
+	}
+	if b := byteBufferGet([]interface{}{mapped}); b != int64('a') {
+		t.Errorf("Expected the mapping to start at the second byte, got %v", b)
+	}
+	expectException(t, byteBufferPut([]interface{}{mapped, int64(1)}), excNames.ReadOnlyBufferException, "put")
+}
+
+func TestFileChannel_MapReadWrite(t *testing.T) {
+	globals.InitStringPool()
+	channel, path := openChannel(t, "rw", "abc")
+
+	mapped, ok := fileChannelMap([]interface{}{channel, makeMapMode(mapModeReadWrite), int64(2), int64(4)}).(*object.Object)
+	if !ok {
+		t.Fatalf("map(READ_WRITE) did not return a buffer")
+	}
+	if isDirect := byteBufferIsDirect([]interface{}{mapped}); isDirect != types.JavaBoolTrue {
+		t.Errorf("Expected a mapped buffer to be direct")
+	}
+	byteBufferPutPrimitive('S', 2, true)([]interface{}{mapped, int64(1), int64(0x4142)})
+
+	content, _ := os.ReadFile(path)
+	if string(content) != "abcAB\x00" {
+		t.Errorf("Expected the file to be grown and written through, got %q", content)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"os"
+	"strings"
+)
+
+// The temporary file and directory methods of java/nio/file/Files. The entries are named
+// and created by createTempEntry() in javaIoFile.go, as for File.createTempFile(). The
+// FileAttribute arguments are ignored: a new file is readable and writable only by its
+// owner, and a new directory usable only by its owner, as the JDK makes them by default
+// on POSIX systems.
+
+func Load_Nio_File_Files() {
+
+	MethodSignatures["java/nio/file/Files.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/nio/file/Files.createTempDirectory(Ljava/lang/String;[Ljava/nio/file/attribute/FileAttribute;)Ljava/nio/file/Path;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  filesCreateTempDirectory,
+		}
+
+	MethodSignatures["java/nio/file/Files.createTempDirectory(Ljava/nio/file/Path;Ljava/lang/String;[Ljava/nio/file/attribute/FileAttribute;)Ljava/nio/file/Path;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  filesCreateTempDirectory,
+		}
+
+	MethodSignatures["java/nio/file/Files.createTempFile(Ljava/lang/String;Ljava/lang/String;[Ljava/nio/file/attribute/FileAttribute;)Ljava/nio/file/Path;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  filesCreateTempFile,
+		}
+
+	MethodSignatures["java/nio/file/Files.createTempFile(Ljava/nio/file/Path;Ljava/lang/String;Ljava/lang/String;[Ljava/nio/file/attribute/FileAttribute;)Ljava/nio/file/Path;"] =
+		GMeth{
+			ParamSlots: 4,
+			GFunction:  filesCreateTempFile,
+		}
+}
+
+// "java/nio/file/Files.createTempFile(Ljava/nio/file/Path;Ljava/lang/String;Ljava/lang/String;[Ljava/nio/file/attribute/FileAttribute;)Ljava/nio/file/Path;"
+// and the form without a directory, which uses the one named by java.io.tmpdir. A null
+// prefix is "" and a null suffix is ".tmp".
+func filesCreateTempFile(params []interface{}) interface{} {
+	dir, params, gErr := tempEntryDir("filesCreateTempFile", params, 4)
+	if gErr != nil {
+		return gErr
+	}
+	suffix := ".tmp"
+	if suffixObj, ok := params[1].(*object.Object); ok && !object.IsNull(suffixObj) {
+		suffix = object.GoStringFromStringObject(suffixObj)
+	}
+	return createTempPath("filesCreateTempFile", dir, optionalGoString(params[0]), suffix, false)
+}
+
+// "java/nio/file/Files.createTempDirectory(Ljava/nio/file/Path;Ljava/lang/String;[Ljava/nio/file/attribute/FileAttribute;)Ljava/nio/file/Path;"
+// and the form without a directory, which uses the one named by java.io.tmpdir. A null
+// prefix is "".
+func filesCreateTempDirectory(params []interface{}) interface{} {
+	dir, params, gErr := tempEntryDir("filesCreateTempDirectory", params, 3)
+	if gErr != nil {
+		return gErr
+	}
+	return createTempPath("filesCreateTempDirectory", dir, optionalGoString(params[0]), "", true)
+}
+
+// tempEntryDir returns the directory in which to create a temporary entry, which is the
+// Path in params[0] if there are slotsWithDir params, and java.io.tmpdir otherwise, and
+// the params that follow the directory
+func tempEntryDir(funcName string, params []interface{}, slotsWithDir int) (string, []interface{}, *GErrBlk) {
+	if len(params) < slotsWithDir {
+		return globals.GetSystemProperty("java.io.tmpdir"), params, nil
+	}
+	if dirObj, ok := params[0].(*object.Object); !ok || object.IsNull(dirObj) {
+		return "", nil, getGErrBlk(excNames.NullPointerException, funcName+": dir is null")
+	}
+	dir, gErr := pathString(funcName, params, 0)
+	return dir, params[1:], gErr
+}
+
+// returns the Go string of a String object, or "" if it's null
+func optionalGoString(param interface{}) string {
+	if strObj, ok := param.(*object.Object); ok && !object.IsNull(strObj) {
+		return object.GoStringFromStringObject(strObj)
+	}
+	return ""
+}
+
+// createTempPath creates a temporary file or directory and returns its Path
+func createTempPath(funcName, dir, prefix, suffix string, isDir bool) interface{} {
+	if strings.ContainsAny(prefix+suffix, "/"+string(os.PathSeparator)) {
+		return getGErrBlk(excNames.IllegalArgumentException, funcName+": Invalid prefix or suffix")
+	}
+	pathStr, err := createTempEntry(dir, prefix, suffix, isDir)
+	switch {
+	case os.IsNotExist(err):
+		return getGErrBlk(excNames.NoSuchFileException, dir)
+	case err != nil:
+		errMsg := fmt.Sprintf("%s: Unable to create a temporary entry in %s, reason: %s", funcName, dir, err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	return makePath(pathStr)
+}
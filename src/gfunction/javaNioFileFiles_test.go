@@ -0,0 +1,48 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFiles_CreateTempFileAndDirectory(t *testing.T) {
+	globals.InitStringPool()
+	parent := makePath(t.TempDir())
+	noAttrs := object.MakeArrayFromRawArray([]*object.Object{})
+
+	ret := filesCreateTempDirectory([]interface{}{parent, object.StringObjectFromGoString("build"), noAttrs})
+	dir := pathStringOf(t, ret)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() || info.Mode().Perm() != 0700 {
+		t.Fatalf("Expected a directory only its owner can use, got %v, %v", info, err)
+	}
+	if !strings.HasPrefix(filepath.Base(dir), "build") {
+		t.Errorf("Expected the directory name to start with the prefix, got %q", dir)
+	}
+
+	ret = filesCreateTempFile([]interface{}{makePath(dir), object.Null, object.StringObjectFromGoString(".json"), noAttrs})
+	file := pathStringOf(t, ret)
+	if filepath.Dir(file) != dir || !strings.HasSuffix(file, ".json") {
+		t.Errorf("Unexpected temp file path %q", file)
+	}
+	if info, err := os.Stat(file); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected a file only its owner can use, got %v, %v", info, err)
+	}
+
+	expectException(t, filesCreateTempFile([]interface{}{makePath(filepath.Join(dir, "missing")), object.Null, object.Null, noAttrs}),
+		excNames.NoSuchFileException, "createTempFile() in a missing directory")
+	expectException(t, filesCreateTempDirectory([]interface{}{makePath(dir), object.StringObjectFromGoString("a/b"), noAttrs}),
+		excNames.IllegalArgumentException, "createTempDirectory() with a separator in the prefix")
+	expectException(t, filesCreateTempFile([]interface{}{object.Null, object.Null, object.Null, noAttrs}),
+		excNames.NullPointerException, "createTempFile() in a null directory")
+}
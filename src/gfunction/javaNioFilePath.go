@@ -0,0 +1,358 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A Path holds its path string, as the platform writes it, in the FilePath field, as a
+// java/io/File does. Its class is the interface java/nio/file/Path itself, since the
+// JDK's implementations of it are specific to each platform. The default FileSystem
+// does no more than make Paths and WatchServices.
+
+var classNamePath = "java/nio/file/Path"
+var classNameFileSystem = "java/nio/file/FileSystem"
+
+func Load_Nio_File_Path() {
+
+	MethodSignatures["java/nio/file/FileSystems.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/nio/file/FileSystems.getDefault()Ljava/nio/file/FileSystem;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  fileSystemsGetDefault,
+		}
+
+	MethodSignatures["java/nio/file/FileSystem.getPath(Ljava/lang/String;[Ljava/lang/String;)Ljava/nio/file/Path;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  fileSystemGetPath,
+		}
+
+	MethodSignatures["java/nio/file/FileSystem.getSeparator()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  fileSystemGetSeparator,
+		}
+
+	MethodSignatures["java/nio/file/FileSystem.newWatchService()Ljava/nio/file/WatchService;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  fileSystemNewWatchService,
+		}
+
+	MethodSignatures["java/nio/file/Paths.get(Ljava/lang/String;[Ljava/lang/String;)Ljava/nio/file/Path;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  pathOf,
+		}
+
+	MethodSignatures["java/nio/file/Path.of(Ljava/lang/String;[Ljava/lang/String;)Ljava/nio/file/Path;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  pathOf,
+		}
+
+	MethodSignatures["java/nio/file/Path.equals(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  pathEquals,
+		}
+
+	MethodSignatures["java/nio/file/Path.getFileName()Ljava/nio/file/Path;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  pathGetFileName,
+		}
+
+	MethodSignatures["java/nio/file/Path.getParent()Ljava/nio/file/Path;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  pathGetParent,
+		}
+
+	MethodSignatures["java/nio/file/Path.hashCode()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  pathHashCode,
+		}
+
+	MethodSignatures["java/nio/file/Path.isAbsolute()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  pathIsAbsolute,
+		}
+
+	MethodSignatures["java/nio/file/Path.register(Ljava/nio/file/WatchService;[Ljava/nio/file/WatchEvent$Kind;)Ljava/nio/file/WatchKey;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  pathRegister,
+		}
+
+	MethodSignatures["java/nio/file/Path.register(Ljava/nio/file/WatchService;[Ljava/nio/file/WatchEvent$Kind;[Ljava/nio/file/WatchEvent$Modifier;)Ljava/nio/file/WatchKey;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  pathRegister,
+		}
+
+	MethodSignatures["java/nio/file/Path.resolve(Ljava/lang/String;)Ljava/nio/file/Path;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  pathResolve,
+		}
+
+	MethodSignatures["java/nio/file/Path.resolve(Ljava/nio/file/Path;)Ljava/nio/file/Path;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  pathResolve,
+		}
+
+	MethodSignatures["java/nio/file/Path.toAbsolutePath()Ljava/nio/file/Path;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  pathToAbsolutePath,
+		}
+
+	MethodSignatures["java/nio/file/Path.toFile()Ljava/io/File;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  pathToFile,
+		}
+
+	MethodSignatures["java/nio/file/Path.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  pathToString,
+		}
+}
+
+// makePath returns a Path object for the path string, which is cleaned of repeated and
+// trailing separators, as the JDK does, but not of "." and ".." elements
+func makePath(pathStr string) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&classNamePath)
+	obj.FieldTable[FilePath] = object.Field{Ftype: types.ByteArray,
+		Fvalue: object.JavaByteArrayFromGoString(normalizePathString(pathStr))}
+	return obj
+}
+
+// normalizePathString replaces '/' with the platform's separator, collapses repeated
+// separators, and drops a trailing separator unless it's the root
+func normalizePathString(pathStr string) string {
+	sep := string(os.PathSeparator)
+	pathStr = filepath.FromSlash(pathStr)
+	for strings.Contains(pathStr, sep+sep) {
+		pathStr = strings.ReplaceAll(pathStr, sep+sep, sep)
+	}
+	if len(pathStr) > 1 && strings.HasSuffix(pathStr, sep) && pathStr != filepath.VolumeName(pathStr)+sep {
+		pathStr = strings.TrimSuffix(pathStr, sep)
+	}
+	return pathStr
+}
+
+// pathString returns the path string of the Path or File in params[index]
+func pathString(funcName string, params []interface{}, index int) (string, *GErrBlk) {
+	obj, err := args.GetObject(params, index)
+	if err != nil {
+		return "", getArgsGErrBlk(funcName, err)
+	}
+	javaBytes, ok := obj.FieldTable[FilePath].Fvalue.([]types.JavaByte)
+	if !ok {
+		return "", getGErrBlk(excNames.IllegalArgumentException, funcName+": the object is not a Path")
+	}
+	return object.GoStringFromJavaByteArray(javaBytes), nil
+}
+
+// "java/nio/file/Path.of(Ljava/lang/String;[Ljava/lang/String;)Ljava/nio/file/Path;" and
+// Paths.get() -- the non-empty strings are joined with the separator
+func pathOf(params []interface{}) interface{} {
+	first, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("pathOf", err)
+	}
+	more, err := args.GetObjectArray(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("pathOf", err)
+	}
+
+	elements := []string{}
+	if first != "" {
+		elements = append(elements, first)
+	}
+	for _, strObj := range more {
+		if object.IsNull(strObj) {
+			return getGErrBlk(excNames.NullPointerException, "pathOf: a path element is null")
+		}
+		if str := object.GoStringFromStringObject(strObj); str != "" {
+			elements = append(elements, str)
+		}
+	}
+	return makePath(strings.Join(elements, string(os.PathSeparator)))
+}
+
+// "java/nio/file/FileSystems.getDefault()Ljava/nio/file/FileSystem;"
+func fileSystemsGetDefault([]interface{}) interface{} {
+	return object.MakeEmptyObjectWithClassName(&classNameFileSystem)
+}
+
+// "java/nio/file/FileSystem.getPath(Ljava/lang/String;[Ljava/lang/String;)Ljava/nio/file/Path;"
+func fileSystemGetPath(params []interface{}) interface{} {
+	return pathOf(params[1:])
+}
+
+// "java/nio/file/FileSystem.getSeparator()Ljava/lang/String;"
+func fileSystemGetSeparator([]interface{}) interface{} {
+	return object.StringObjectFromGoString(string(os.PathSeparator))
+}
+
+// "java/nio/file/Path.toString()Ljava/lang/String;"
+func pathToString(params []interface{}) interface{} {
+	pathStr, gErr := pathString("pathToString", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	return object.StringObjectFromGoString(pathStr)
+}
+
+// "java/nio/file/Path.equals(Ljava/lang/Object;)Z"
+func pathEquals(params []interface{}) interface{} {
+	pathStr, gErr := pathString("pathEquals", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	this := params[0].(*object.Object)
+	other, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(other) || other.KlassName != this.KlassName {
+		return types.JavaBoolFalse
+	}
+	otherStr, gErr := pathString("pathEquals", params, 1)
+	if gErr != nil {
+		return types.JavaBoolFalse
+	}
+	return types.ConvertGoBoolToJavaBool(pathStr == otherStr)
+}
+
+// "java/nio/file/Path.hashCode()I"
+func pathHashCode(params []interface{}) interface{} {
+	pathStr, gErr := pathString("pathHashCode", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(javaStringHashCode(pathStr))
+}
+
+// "java/nio/file/Path.getFileName()Ljava/nio/file/Path;" -- null for a root
+func pathGetFileName(params []interface{}) interface{} {
+	pathStr, gErr := pathString("pathGetFileName", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	if pathStr == "" {
+		return params[0]
+	}
+	if pathStr == filepath.Dir(pathStr) {
+		return object.Null
+	}
+	return makePath(filepath.Base(pathStr))
+}
+
+// "java/nio/file/Path.getParent()Ljava/nio/file/Path;" -- null for a root or a path of
+// one element
+func pathGetParent(params []interface{}) interface{} {
+	pathStr, gErr := pathString("pathGetParent", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	if !strings.ContainsRune(pathStr, os.PathSeparator) || pathStr == filepath.Dir(pathStr) {
+		return object.Null
+	}
+	return makePath(pathStr[:strings.LastIndexByte(pathStr, os.PathSeparator)+1])
+}
+
+// "java/nio/file/Path.isAbsolute()Z"
+func pathIsAbsolute(params []interface{}) interface{} {
+	pathStr, gErr := pathString("pathIsAbsolute", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(filepath.IsAbs(pathStr))
+}
+
+// "java/nio/file/Path.resolve(Ljava/nio/file/Path;)Ljava/nio/file/Path;" and
+// resolve(String) -- an absolute path resolves to itself, and an empty one to this path
+func pathResolve(params []interface{}) interface{} {
+	pathStr, gErr := pathString("pathResolve", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	var other string
+	if otherObj, ok := params[1].(*object.Object); ok && object.IsStringObject(otherObj) {
+		other = object.GoStringFromStringObject(otherObj)
+	} else if other, gErr = pathString("pathResolve", params, 1); gErr != nil {
+		return gErr
+	}
+
+	switch {
+	case filepath.IsAbs(other):
+		return makePath(other)
+	case other == "":
+		return params[0]
+	case pathStr == "":
+		return makePath(other)
+	}
+	return makePath(pathStr + string(os.PathSeparator) + other)
+}
+
+// "java/nio/file/Path.toAbsolutePath()Ljava/nio/file/Path;" -- resolves the path against
+// the working directory, without normalizing it
+func pathToAbsolutePath(params []interface{}) interface{} {
+	pathStr, gErr := pathString("pathToAbsolutePath", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	if filepath.IsAbs(pathStr) {
+		return params[0]
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		errMsg := fmt.Sprintf("pathToAbsolutePath: os.Getwd() failed, reason: %s", err.Error())
+		return getGErrBlk(excNames.IOError, errMsg)
+	}
+	if pathStr == "" {
+		return makePath(cwd)
+	}
+	return makePath(cwd + string(os.PathSeparator) + pathStr)
+}
+
+// "java/nio/file/Path.toFile()Ljava/io/File;"
+func pathToFile(params []interface{}) interface{} {
+	pathStr, gErr := pathString("pathToFile", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	return makeFileObject(pathStr)
+}
+
+// "java/io/File.toPath()Ljava/nio/file/Path;"
+func fileToPath(params []interface{}) interface{} {
+	pathStr, gErr := pathString("fileToPath", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	return makePath(pathStr)
+}
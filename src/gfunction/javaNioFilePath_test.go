@@ -0,0 +1,73 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// returns the string of the Path returned by a Path G function
+func pathStringOf(t *testing.T, ret interface{}) string {
+	t.Helper()
+	obj, ok := ret.(*object.Object)
+	if !ok {
+		t.Fatalf("Expected a Path, got %v", ret)
+	}
+	if object.IsNull(obj) {
+		return "null"
+	}
+	return object.GoStringFromStringObject(pathToString([]interface{}{obj}).(*object.Object))
+}
+
+func TestPath_OfAndElements(t *testing.T) {
+	globals.InitStringPool()
+	sep := string(os.PathSeparator)
+	more := object.MakeArrayFromRawArray([]*object.Object{
+		object.StringObjectFromGoString("b/"), object.StringObjectFromGoString(""), object.StringObjectFromGoString("c.txt")})
+	path := pathOf([]interface{}{object.StringObjectFromGoString("a//"), more}).(*object.Object)
+
+	if str := pathStringOf(t, path); str != "a"+sep+"b"+sep+"c.txt" {
+		t.Errorf("Expected the elements to be joined and cleaned, got %q", str)
+	}
+	if str := pathStringOf(t, pathGetFileName([]interface{}{path})); str != "c.txt" {
+		t.Errorf("Expected the file name c.txt, got %q", str)
+	}
+	if str := pathStringOf(t, pathGetParent([]interface{}{path})); str != "a"+sep+"b" {
+		t.Errorf("Expected the parent a/b, got %q", str)
+	}
+	if str := pathStringOf(t, pathGetParent([]interface{}{makePath("a")})); str != "null" {
+		t.Errorf("Expected a path of one element to have no parent, got %q", str)
+	}
+	if pathIsAbsolute([]interface{}{path}) != types.JavaBoolFalse {
+		t.Errorf("Expected a relative path")
+	}
+
+	abs := pathToAbsolutePath([]interface{}{path})
+	cwd, _ := os.Getwd()
+	if str := pathStringOf(t, abs); str != filepath.Join(cwd, "a", "b", "c.txt") {
+		t.Errorf("Expected the path to be resolved against the working directory, got %q", str)
+	}
+	root := string(os.PathSeparator)
+	if str := pathStringOf(t, pathResolve([]interface{}{path, makePath(root)})); str != root {
+		t.Errorf("Expected resolving an absolute path to return it, got %q", str)
+	}
+	if str := pathStringOf(t, pathResolve([]interface{}{makePath("a"), object.StringObjectFromGoString("b")})); str != "a"+sep+"b" {
+		t.Errorf("Expected a/b, got %q", str)
+	}
+
+	if pathEquals([]interface{}{path, makePath("a/b/c.txt")}) != types.JavaBoolTrue {
+		t.Errorf("Expected equal paths to be equal")
+	}
+	if pathHashCode([]interface{}{path}) != pathHashCode([]interface{}{makePath("a/b/c.txt")}) {
+		t.Errorf("Expected equal paths to have equal hash codes")
+	}
+}
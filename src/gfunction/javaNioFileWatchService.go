@@ -0,0 +1,709 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
+	"jacobin/src/object"
+	"jacobin/src/statics"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Implementation of java/nio/file/WatchService for the default file system. A goroutine
+// polls each registered directory every watchPollInterval, as the JDK's PollingWatchService
+// does, and compares what it finds with what it found the last time: a new name is an
+// ENTRY_CREATE, a name that's gone is an ENTRY_DELETE, and a name whose size or
+// modification time has changed is an ENTRY_MODIFY. Polling needs nothing from the OS
+// beyond reading directories, so it works on every platform Jacobin runs on.
+//
+// The service, its keys, and its events are objects of the JDK's abstract classes for
+// them, each holding its Go state in its "watch" field. All the state of a service and of
+// its keys is guarded by the mutex of the service's synchronizer, on which take() and
+// poll(long, TimeUnit) wait.
+
+var classNameWatchService = "sun/nio/fs/AbstractWatchService"
+var classNameWatchKey = "sun/nio/fs/AbstractWatchKey"
+var classNameWatchEvent = "sun/nio/fs/AbstractWatchKey$Event"
+var classNameWatchEventKinds = "java/nio/file/StandardWatchEventKinds"
+var classNameWatchEventKind = "java/nio/file/StandardWatchEventKinds$StdWatchEventKind"
+
+const fieldNameWatch = "watch"
+
+// the names of the standard event kinds
+const (
+	watchEntryCreate = "ENTRY_CREATE"
+	watchEntryDelete = "ENTRY_DELETE"
+	watchEntryModify = "ENTRY_MODIFY"
+	watchOverflow    = "OVERFLOW"
+)
+
+var watchEventKindNames = []string{watchOverflow, watchEntryCreate, watchEntryDelete, watchEntryModify}
+
+// how often the registered directories are polled
+var watchPollInterval = 2 * time.Second
+
+// the most events a key holds before further events are folded into an OVERFLOW event
+const watchMaxEvents = 512
+
+// watchService is the state of a WatchService
+type watchService struct {
+	sync   *synchronizer
+	keys   map[string]*watchKey // the valid keys, by the absolute path of their directories
+	queue  []*watchKey          // the signalled keys, in the order they were signalled
+	closed bool
+	stop   chan struct{} // closed by close() to end the polling goroutine
+}
+
+// watchKey is the state of a WatchKey
+type watchKey struct {
+	service   *watchService
+	dir       string         // the absolute path of the directory
+	path      *object.Object // the Path that was registered
+	obj       *object.Object // the WatchKey object
+	kinds     map[string]bool
+	stamps    map[string]watchStamp // what the last poll found in the directory, by name
+	events    []*watchEvent         // the events not yet retrieved by pollEvents()
+	signalled bool
+	valid     bool
+}
+
+// watchStamp is what a poll records of a directory entry
+type watchStamp struct {
+	modTime time.Time
+	size    int64
+	isDir   bool
+}
+
+// watchEvent is the state of a WatchEvent: an event of the kind on the named entry,
+// repeated count times. An OVERFLOW event has no name.
+type watchEvent struct {
+	kind  string
+	name  string
+	count int64
+}
+
+func Load_Nio_File_WatchService() {
+
+	MethodSignatures["java/nio/file/StandardWatchEventKinds.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  watchEventKindsClinit,
+		}
+
+	MethodSignatures[classNameWatchEventKind+".<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures[classNameWatchEventKind+".name()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  watchEventKindName,
+		}
+
+	MethodSignatures[classNameWatchEventKind+".toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  watchEventKindName,
+		}
+
+	// --- the WatchService ---
+
+	MethodSignatures[classNameWatchService+".<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures[classNameWatchService+".close()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  watchServiceClose,
+		}
+
+	MethodSignatures[classNameWatchService+".poll()Ljava/nio/file/WatchKey;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  watchServicePoll,
+		}
+
+	MethodSignatures[classNameWatchService+".poll(JLjava/util/concurrent/TimeUnit;)Ljava/nio/file/WatchKey;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    watchServiceTake,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[classNameWatchService+".take()Ljava/nio/file/WatchKey;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    watchServiceTake,
+			NeedsContext: true,
+		}
+
+	// --- its keys ---
+
+	MethodSignatures[classNameWatchKey+".<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures[classNameWatchKey+".cancel()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  watchKeyCancel,
+		}
+
+	MethodSignatures[classNameWatchKey+".isValid()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  watchKeyIsValid,
+		}
+
+	MethodSignatures[classNameWatchKey+".pollEvents()Ljava/util/List;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  watchKeyPollEvents,
+		}
+
+	MethodSignatures[classNameWatchKey+".reset()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  watchKeyReset,
+		}
+
+	MethodSignatures[classNameWatchKey+".watchable()Ljava/nio/file/Watchable;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  watchKeyWatchable,
+		}
+
+	// --- and their events ---
+
+	MethodSignatures[classNameWatchEvent+".<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures[classNameWatchEvent+".context()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  watchEventContext,
+		}
+
+	MethodSignatures[classNameWatchEvent+".count()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  watchEventCount,
+		}
+
+	MethodSignatures[classNameWatchEvent+".kind()Ljava/nio/file/WatchEvent$Kind;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  watchEventKind,
+		}
+}
+
+// "java/nio/file/StandardWatchEventKinds.<clinit>()V" -- create the event kinds
+func watchEventKindsClinit([]interface{}) interface{} {
+	klass := classloader.MethAreaFetch(classNameWatchEventKinds)
+	if klass == nil || klass.Data == nil {
+		errMsg := fmt.Sprintf("watchEventKindsClinit: Expected %s to be in the MethodArea, but it was not", classNameWatchEventKinds)
+		trace.Error(errMsg)
+		return getGErrBlk(excNames.ClassNotLoadedException, errMsg)
+	}
+	if klass.Data.ClInit != types.ClInitRun {
+		for _, name := range watchEventKindNames {
+			_ = statics.AddStatic(classNameWatchEventKinds+"."+name,
+				statics.Static{Type: "Ljava/nio/file/WatchEvent$Kind;", Value: makeWatchEventKind(name)})
+		}
+		klass.Data.ClInit = types.ClInitRun
+	}
+	return nil
+}
+
+// makes an event kind of the name
+func makeWatchEventKind(name string) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&classNameWatchEventKind)
+	obj.FieldTable["name"] = object.Field{
+		Ftype: types.StringClassRef, Fvalue: object.StringObjectFromGoString(name)}
+	return obj
+}
+
+// returns the event kind of the name: once StandardWatchEventKinds.<clinit> has run, this
+// is the static; before then, a new object is made.
+func watchEventKindObject(name string) *object.Object {
+	if static, ok := statics.Statics[classNameWatchEventKinds+"."+name]; ok {
+		if obj, ok := static.Value.(*object.Object); ok {
+			return obj
+		}
+	}
+	return makeWatchEventKind(name)
+}
+
+// returns the name of the event kind object, or "" if it isn't one
+func watchEventKindNameOf(kind *object.Object) string {
+	if name, ok := kind.FieldTable["name"].Fvalue.(*object.Object); ok {
+		return object.GoStringFromStringObject(name)
+	}
+	return ""
+}
+
+// "java/nio/file/StandardWatchEventKinds$StdWatchEventKind.name()Ljava/lang/String;"
+// and toString()
+func watchEventKindName(params []interface{}) interface{} {
+	kind, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("watchEventKindName", err)
+	}
+	return object.StringObjectFromGoString(watchEventKindNameOf(kind))
+}
+
+// "java/nio/file/FileSystem.newWatchService()Ljava/nio/file/WatchService;" -- starts the
+// goroutine that polls the service's directories until it's closed
+func fileSystemNewWatchService([]interface{}) interface{} {
+	ws := &watchService{
+		sync: newSynchronizer(false),
+		keys: make(map[string]*watchKey),
+		stop: make(chan struct{}),
+	}
+	ticker := time.NewTicker(watchPollInterval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ws.stop:
+				return
+			case <-ticker.C:
+				ws.scan()
+			}
+		}
+	}()
+	return object.MakeOneFieldObject(classNameWatchService, fieldNameWatch, types.WatchState, ws)
+}
+
+// returns the state of the WatchService in params[index]
+func getWatchService(funcName string, params []interface{}, index int) (*watchService, *GErrBlk) {
+	obj, err := args.GetObject(params, index)
+	if err != nil {
+		return nil, getArgsGErrBlk(funcName, err)
+	}
+	ws, ok := obj.FieldTable[fieldNameWatch].Fvalue.(*watchService)
+	if !ok {
+		return nil, getGErrBlk(excNames.IllegalArgumentException, funcName+": the object is not a WatchService")
+	}
+	return ws, nil
+}
+
+// returns the state of the WatchKey in params[0]
+func getWatchKey(funcName string, params []interface{}) (*watchKey, *GErrBlk) {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return nil, getArgsGErrBlk(funcName, err)
+	}
+	key, ok := obj.FieldTable[fieldNameWatch].Fvalue.(*watchKey)
+	if !ok {
+		return nil, getGErrBlk(excNames.IllegalArgumentException, funcName+": the object is not a WatchKey")
+	}
+	return key, nil
+}
+
+// returns the state of the WatchEvent in params[0]
+func getWatchEvent(funcName string, params []interface{}) (*watchEvent, *GErrBlk) {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return nil, getArgsGErrBlk(funcName, err)
+	}
+	event, ok := obj.FieldTable[fieldNameWatch].Fvalue.(*watchEvent)
+	if !ok {
+		return nil, getGErrBlk(excNames.IllegalArgumentException, funcName+": the object is not a WatchEvent")
+	}
+	return event, nil
+}
+
+// "java/nio/file/Path.register(Ljava/nio/file/WatchService;[Ljava/nio/file/WatchEvent$Kind;)Ljava/nio/file/WatchKey;"
+// and the form with modifiers, which are ignored. Registering a directory again returns
+// the same key, now watching for the new kinds.
+func pathRegister(params []interface{}) interface{} {
+	pathStr, gErr := pathString("pathRegister", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	ws, gErr := getWatchService("pathRegister", params, 1)
+	if gErr != nil {
+		return gErr
+	}
+	kindObjs, err := args.GetObjectArray(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("pathRegister", err)
+	}
+
+	kinds := make(map[string]bool)
+	for _, kindObj := range kindObjs {
+		if object.IsNull(kindObj) {
+			return getGErrBlk(excNames.NullPointerException, "pathRegister: an event kind is null")
+		}
+		switch name := watchEventKindNameOf(kindObj); name {
+		case watchEntryCreate, watchEntryDelete, watchEntryModify:
+			kinds[name] = true
+		case watchOverflow: // always delivered
+		default:
+			return getGErrBlk(excNames.UnsupportedOperationException, "pathRegister: unsupported event kind "+name)
+		}
+	}
+	if len(kinds) == 0 {
+		return getGErrBlk(excNames.IllegalArgumentException, "pathRegister: no events to register")
+	}
+
+	dir, err := filepath.Abs(pathStr)
+	if err != nil {
+		errMsg := fmt.Sprintf("pathRegister: filepath.Abs(%s) failed, reason: %s", pathStr, err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	info, err := os.Stat(dir)
+	switch {
+	case os.IsNotExist(err):
+		return getGErrBlk(excNames.NoSuchFileException, pathStr)
+	case err != nil:
+		return getGErrBlk(excNames.IOException, fmt.Sprintf("pathRegister: %s", err.Error()))
+	case !info.IsDir():
+		return getGErrBlk(excNames.NotDirectoryException, pathStr)
+	}
+
+	ws.sync.mutex.Lock()
+	defer ws.sync.mutex.Unlock()
+	if ws.closed {
+		return getGErrBlk(excNames.ClosedWatchServiceException, "pathRegister: the watch service is closed")
+	}
+	if key, ok := ws.keys[dir]; ok {
+		key.kinds = kinds
+		return key.obj
+	}
+
+	key := &watchKey{service: ws, dir: dir, path: params[0].(*object.Object), kinds: kinds, valid: true}
+	key.stamps, _ = readWatchStamps(dir)
+	key.obj = object.MakeOneFieldObject(classNameWatchKey, fieldNameWatch, types.WatchState, key)
+	ws.keys[dir] = key
+	return key.obj
+}
+
+// readWatchStamps returns the stamps of the entries in the directory
+func readWatchStamps(dir string) (map[string]watchStamp, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	stamps := make(map[string]watchStamp, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil { // removed since it was listed
+			continue
+		}
+		stamps[entry.Name()] = watchStamp{modTime: info.ModTime(), size: info.Size(), isDir: info.IsDir()}
+	}
+	return stamps, nil
+}
+
+// scan polls the directories of the service's keys, signalling and queueing each key that
+// has events and isn't already signalled
+func (ws *watchService) scan() {
+	ws.sync.mutex.Lock()
+	defer ws.sync.mutex.Unlock()
+	if ws.closed {
+		return
+	}
+
+	dirs := make([]string, 0, len(ws.keys))
+	for dir := range ws.keys {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	signalled := false
+	for _, dir := range dirs {
+		key := ws.keys[dir]
+		if key.rescan() && !key.signalled {
+			key.signalled = true
+			ws.queue = append(ws.queue, key)
+			signalled = true
+		}
+	}
+	if signalled {
+		ws.sync.notify()
+	}
+}
+
+// rescan compares the key's directory with what the last poll found, adding an event
+// for each difference, and returns whether the key has events or has just become
+// invalid. A key whose directory can no longer be read is cancelled, as in the JDK.
+func (key *watchKey) rescan() bool {
+	stamps, err := readWatchStamps(key.dir)
+	if err != nil {
+		key.cancel()
+		return true
+	}
+
+	names := make([]string, 0, len(stamps))
+	for name := range stamps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		old, found := key.stamps[name]
+		switch {
+		case !found:
+			key.addEvent(watchEntryCreate, name)
+		case !old.modTime.Equal(stamps[name].modTime) || old.size != stamps[name].size:
+			key.addEvent(watchEntryModify, name)
+		}
+	}
+
+	names = names[:0]
+	for name := range key.stamps {
+		if _, found := stamps[name]; !found {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		key.addEvent(watchEntryDelete, name)
+	}
+
+	key.stamps = stamps
+	return len(key.events) > 0
+}
+
+// addEvent adds an event of the kind, if the key is watching for it. A repeat of the last
+// event is counted rather than added, and once the key holds watchMaxEvents events, the
+// last of them is an OVERFLOW event that counts the ones discarded.
+func (key *watchKey) addEvent(kind, name string) {
+	if !key.kinds[kind] {
+		return
+	}
+	var last *watchEvent
+	if len(key.events) > 0 {
+		last = key.events[len(key.events)-1]
+	}
+	switch {
+	case last != nil && last.kind == kind && last.name == name:
+		last.count++
+	case len(key.events) < watchMaxEvents-1:
+		key.events = append(key.events, &watchEvent{kind: kind, name: name, count: 1})
+	case last.kind == watchOverflow:
+		last.count++
+	default:
+		key.events = append(key.events, &watchEvent{kind: watchOverflow, count: 1})
+	}
+}
+
+// cancel invalidates the key and stops its directory being watched
+func (key *watchKey) cancel() {
+	key.valid = false
+	if key.service.keys[key.dir] == key {
+		delete(key.service.keys, key.dir)
+	}
+}
+
+// "java/nio/file/WatchService.poll()Ljava/nio/file/WatchKey;" -- null if no key is signalled
+func watchServicePoll(params []interface{}) interface{} {
+	ws, gErr := getWatchService("watchServicePoll", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	ws.sync.mutex.Lock()
+	defer ws.sync.mutex.Unlock()
+	if ws.closed {
+		return getGErrBlk(excNames.ClosedWatchServiceException, "watchServicePoll: the watch service is closed")
+	}
+	if len(ws.queue) == 0 {
+		return object.Null
+	}
+	key := ws.queue[0]
+	ws.queue = ws.queue[1:]
+	return key.obj
+}
+
+// "java/nio/file/WatchService.take()Ljava/nio/file/WatchKey;", which waits for a key to be
+// signalled, and poll(long, TimeUnit), which returns null if the time runs out first
+func watchServiceTake(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	ws, gErr := getWatchService("watchServiceTake", params, 1)
+	if gErr != nil {
+		return gErr
+	}
+	timed := len(params) > 2
+	var timeout time.Duration
+	if timed {
+		if timeout, gErr = timeUnitDuration("watchServiceTake", params[2].(int64), params[3]); gErr != nil {
+			return gErr
+		}
+	}
+
+	var key *watchKey
+	try := func() bool {
+		if ws.closed {
+			return true
+		}
+		if len(ws.queue) > 0 {
+			key = ws.queue[0]
+			ws.queue = ws.queue[1:]
+			return true
+		}
+		return false
+	}
+	acquired, interrupted := ws.sync.await(fs, try, timed, timeout, true, "")
+
+	switch {
+	case interrupted:
+		return getGErrBlk(excNames.InterruptedException, "watchServiceTake: interrupted")
+	case !acquired:
+		return object.Null
+	case key == nil:
+		return getGErrBlk(excNames.ClosedWatchServiceException, "watchServiceTake: the watch service is closed")
+	}
+	return key.obj
+}
+
+// "java/nio/file/WatchService.close()V" -- cancels the keys and wakes any thread waiting
+// in take() or poll(long, TimeUnit)
+func watchServiceClose(params []interface{}) interface{} {
+	ws, gErr := getWatchService("watchServiceClose", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	ws.sync.mutex.Lock()
+	defer ws.sync.mutex.Unlock()
+	if ws.closed {
+		return nil
+	}
+	ws.closed = true
+	for _, key := range ws.keys {
+		key.valid = false
+	}
+	ws.keys = make(map[string]*watchKey)
+	ws.queue = nil
+	close(ws.stop)
+	ws.sync.notify()
+	return nil
+}
+
+// "java/nio/file/WatchKey.isValid()Z"
+func watchKeyIsValid(params []interface{}) interface{} {
+	key, gErr := getWatchKey("watchKeyIsValid", params)
+	if gErr != nil {
+		return gErr
+	}
+	key.service.sync.mutex.Lock()
+	defer key.service.sync.mutex.Unlock()
+	return types.ConvertGoBoolToJavaBool(key.valid)
+}
+
+// "java/nio/file/WatchKey.pollEvents()Ljava/util/List;" -- retrieves and removes the
+// key's events
+func watchKeyPollEvents(params []interface{}) interface{} {
+	key, gErr := getWatchKey("watchKeyPollEvents", params)
+	if gErr != nil {
+		return gErr
+	}
+	key.service.sync.mutex.Lock()
+	events := key.events
+	key.events = nil
+	key.service.sync.mutex.Unlock()
+
+	elems := make([]*object.Object, len(events))
+	for i, event := range events {
+		elems[i] = object.MakeOneFieldObject(classNameWatchEvent, fieldNameWatch, types.WatchState, event)
+	}
+	return newImmutableListObject(classNameImmutableList, elems)
+}
+
+// "java/nio/file/WatchKey.reset()Z" -- makes the key ready to be signalled again, queueing
+// it at once if it already has events. Returns whether the key is valid.
+func watchKeyReset(params []interface{}) interface{} {
+	key, gErr := getWatchKey("watchKeyReset", params)
+	if gErr != nil {
+		return gErr
+	}
+	ws := key.service
+	ws.sync.mutex.Lock()
+	defer ws.sync.mutex.Unlock()
+	if !key.valid {
+		return types.JavaBoolFalse
+	}
+	if key.signalled {
+		key.signalled = len(key.events) > 0
+		if key.signalled {
+			ws.queue = append(ws.queue, key)
+			ws.sync.notify()
+		}
+	}
+	return types.JavaBoolTrue
+}
+
+// "java/nio/file/WatchKey.cancel()V"
+func watchKeyCancel(params []interface{}) interface{} {
+	key, gErr := getWatchKey("watchKeyCancel", params)
+	if gErr != nil {
+		return gErr
+	}
+	key.service.sync.mutex.Lock()
+	defer key.service.sync.mutex.Unlock()
+	key.cancel()
+	return nil
+}
+
+// "java/nio/file/WatchKey.watchable()Ljava/nio/file/Watchable;" -- the registered Path
+func watchKeyWatchable(params []interface{}) interface{} {
+	key, gErr := getWatchKey("watchKeyWatchable", params)
+	if gErr != nil {
+		return gErr
+	}
+	return key.path
+}
+
+// "java/nio/file/WatchEvent.kind()Ljava/nio/file/WatchEvent$Kind;"
+func watchEventKind(params []interface{}) interface{} {
+	event, gErr := getWatchEvent("watchEventKind", params)
+	if gErr != nil {
+		return gErr
+	}
+	return watchEventKindObject(event.kind)
+}
+
+// "java/nio/file/WatchEvent.context()Ljava/lang/Object;" -- the Path of the entry,
+// relative to the watched directory, or null for an OVERFLOW event
+func watchEventContext(params []interface{}) interface{} {
+	event, gErr := getWatchEvent("watchEventContext", params)
+	if gErr != nil {
+		return gErr
+	}
+	if event.kind == watchOverflow {
+		return object.Null
+	}
+	return makePath(event.name)
+}
+
+// "java/nio/file/WatchEvent.count()I"
+func watchEventCount(params []interface{}) interface{} {
+	event, gErr := getWatchEvent("watchEventCount", params)
+	if gErr != nil {
+		return gErr
+	}
+	return event.count
+}
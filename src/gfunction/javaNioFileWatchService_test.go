@@ -0,0 +1,209 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// returns a new WatchService, closed when the test ends, and its state. The polling
+// goroutine is slowed so that the tests scan the directories themselves.
+func newTestWatchService(t *testing.T) (*object.Object, *watchService) {
+	t.Helper()
+	saved := watchPollInterval
+	watchPollInterval = time.Hour
+	t.Cleanup(func() { watchPollInterval = saved })
+
+	obj := fileSystemNewWatchService(nil).(*object.Object)
+	t.Cleanup(func() { watchServiceClose([]interface{}{obj}) })
+	ws, gErr := getWatchService("newTestWatchService", []interface{}{obj}, 0)
+	if gErr != nil {
+		t.Fatalf("newWatchService did not return a WatchService")
+	}
+	return obj, ws
+}
+
+// registers the directory with the service for the kinds, failing the test if it can't be
+func registerDir(t *testing.T, service *object.Object, dir string, kinds ...string) *object.Object {
+	t.Helper()
+	kindObjs := make([]*object.Object, len(kinds))
+	for i, kind := range kinds {
+		kindObjs[i] = watchEventKindObject(kind)
+	}
+	ret := pathRegister([]interface{}{makePath(dir), service, object.MakeArrayFromRawArray(kindObjs)})
+	key, ok := ret.(*object.Object)
+	if !ok {
+		t.Fatalf("register(%s) failed: %v", dir, ret)
+	}
+	return key
+}
+
+// returns the kinds, names, and counts of the key's events, as "KIND name count" strings
+func eventStrings(t *testing.T, key *object.Object) []string {
+	t.Helper()
+	events, gErr := getImmutableList("eventStrings", []interface{}{watchKeyPollEvents([]interface{}{key})}, 0)
+	if gErr != nil {
+		t.Fatalf("pollEvents() did not return a list")
+	}
+	strs := make([]string, len(events))
+	for i, event := range events {
+		kind := watchEventKindNameOf(watchEventKind([]interface{}{event}).(*object.Object))
+		name := "null"
+		if context := watchEventContext([]interface{}{event}).(*object.Object); !object.IsNull(context) {
+			name = object.GoStringFromStringObject(pathToString([]interface{}{context}).(*object.Object))
+		}
+		strs[i] = fmt.Sprintf("%s %s %d", kind, name, watchEventCount([]interface{}{event}))
+	}
+	return strs
+}
+
+func checkEvents(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("Expected events %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected events %v, got %v", want, got)
+			return
+		}
+	}
+}
+
+func TestWatchService_CreateModifyDelete(t *testing.T) {
+	globals.InitStringPool()
+	service, ws := newTestWatchService(t)
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.txt")
+	_ = os.WriteFile(filepath.Join(dir, "existing.txt"), []byte("x"), 0644)
+
+	key := registerDir(t, service, dir, watchEntryCreate, watchEntryModify, watchEntryDelete)
+	if again := registerDir(t, service, dir, watchEntryCreate, watchEntryDelete); again != key {
+		t.Errorf("Expected registering the directory again to return the same key")
+	}
+	key = registerDir(t, service, dir, watchEntryCreate, watchEntryModify, watchEntryDelete)
+
+	ws.scan()
+	if ret := watchServicePoll([]interface{}{service}); ret != object.Null {
+		t.Fatalf("Expected no key to be signalled before any change, got %v", ret)
+	}
+
+	_ = os.WriteFile(file, []byte("one"), 0644)
+	ws.scan()
+	if ret := watchServicePoll([]interface{}{service}); ret != key {
+		t.Fatalf("Expected the key to be signalled after a create, got %v", ret)
+	}
+	checkEvents(t, eventStrings(t, key), "ENTRY_CREATE a.txt 1")
+
+	// a signalled key isn't queued again until it's reset
+	_ = os.WriteFile(file, []byte("three"), 0644)
+	ws.scan()
+	if ret := watchServicePoll([]interface{}{service}); ret != object.Null {
+		t.Errorf("Expected a signalled key not to be queued again, got %v", ret)
+	}
+	if watchKeyReset([]interface{}{key}) != types.JavaBoolTrue {
+		t.Errorf("Expected reset() of a valid key to return true")
+	}
+	if ret := watchServicePoll([]interface{}{service}); ret != key {
+		t.Fatalf("Expected reset() to queue a key with pending events, got %v", ret)
+	}
+	checkEvents(t, eventStrings(t, key), "ENTRY_MODIFY a.txt 1")
+	watchKeyReset([]interface{}{key})
+
+	_ = os.Remove(file)
+	_ = os.Remove(filepath.Join(dir, "existing.txt"))
+	ws.scan()
+	if ret := watchServicePoll([]interface{}{service}); ret != key {
+		t.Fatalf("Expected the key to be signalled after the deletes, got %v", ret)
+	}
+	checkEvents(t, eventStrings(t, key), "ENTRY_DELETE a.txt 1", "ENTRY_DELETE existing.txt 1")
+
+	watchKeyCancel([]interface{}{key})
+	if watchKeyReset([]interface{}{key}) != types.JavaBoolFalse {
+		t.Errorf("Expected reset() of a cancelled key to return false")
+	}
+}
+
+func TestWatchService_TakeAndClose(t *testing.T) {
+	globals.InitGlobals("test")
+	_, fs := addTestExecThread()
+	service, ws := newTestWatchService(t)
+	dir := t.TempDir()
+	key := registerDir(t, service, dir, watchEntryCreate)
+
+	ret := watchServiceTake([]interface{}{fs, service, int64(20), timeUnitObject("MILLISECONDS")})
+	if ret != object.Null {
+		t.Errorf("Expected poll(20ms) to time out, got %v", ret)
+	}
+
+	done := make(chan interface{})
+	go func() { done <- watchServiceTake([]interface{}{fs, service}) }()
+	_ = os.Mkdir(filepath.Join(dir, "sub"), 0755)
+	ws.scan()
+	select {
+	case ret = <-done:
+		if ret != key {
+			t.Errorf("Expected take() to return the key, got %v", ret)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("take() did not return after a create")
+	}
+	checkEvents(t, eventStrings(t, key), "ENTRY_CREATE sub 1")
+
+	go func() { done <- watchServiceTake([]interface{}{fs, service}) }()
+	time.Sleep(10 * time.Millisecond)
+	watchServiceClose([]interface{}{service})
+	select {
+	case ret = <-done:
+		expectException(t, ret, excNames.ClosedWatchServiceException, "take() when closed")
+	case <-time.After(time.Second):
+		t.Fatalf("close() did not wake take()")
+	}
+	if watchKeyIsValid([]interface{}{key}) != types.JavaBoolFalse {
+		t.Errorf("Expected close() to invalidate the key")
+	}
+	expectException(t, watchServicePoll([]interface{}{service}), excNames.ClosedWatchServiceException, "poll() when closed")
+}
+
+func TestWatchService_RegisterErrors(t *testing.T) {
+	globals.InitStringPool()
+	service, _ := newTestWatchService(t)
+	dir := t.TempDir()
+	file := filepath.Join(dir, "plain.txt")
+	_ = os.WriteFile(file, nil, 0644)
+	kinds := object.MakeArrayFromRawArray([]*object.Object{watchEventKindObject(watchEntryCreate)})
+
+	expectException(t, pathRegister([]interface{}{makePath(file), service, kinds}),
+		excNames.NotDirectoryException, "register() of a file")
+	expectException(t, pathRegister([]interface{}{makePath(filepath.Join(dir, "missing")), service, kinds}),
+		excNames.NoSuchFileException, "register() of a missing directory")
+	overflowOnly := object.MakeArrayFromRawArray([]*object.Object{watchEventKindObject(watchOverflow)})
+	expectException(t, pathRegister([]interface{}{makePath(dir), service, overflowOnly}),
+		excNames.IllegalArgumentException, "register() for OVERFLOW alone")
+}
+
+func TestWatchKey_Overflow(t *testing.T) {
+	globals.InitStringPool()
+	key := &watchKey{kinds: map[string]bool{watchEntryCreate: true}}
+	for i := 0; i < watchMaxEvents+10; i++ {
+		key.addEvent(watchEntryCreate, fmt.Sprintf("file%d", i))
+	}
+	if len(key.events) != watchMaxEvents {
+		t.Fatalf("Expected %d events, got %d", watchMaxEvents, len(key.events))
+	}
+	if last := key.events[watchMaxEvents-1]; last.kind != watchOverflow || last.count != 11 {
+		t.Errorf("Expected an OVERFLOW event counting 11 events, got %s counting %d", last.kind, last.count)
+	}
+}
@@ -0,0 +1,203 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"strings"
+)
+
+// Implementation of java/security/AccessController and AccessControlContext for a VM that
+// runs without a security manager, as the JDK has by default since Java 18 (see
+// javaLangSecurityManager.go). There are no protection domains to check, so every
+// permission is granted, and doPrivileged() simply runs the action on the calling thread.
+// The contexts and permissions passed to the methods are ignored.
+//
+// As in the JDK, a checked exception thrown by a PrivilegedExceptionAction is wrapped in a
+// PrivilegedActionException, while an unchecked one passes through unchanged.
+
+var classNameAccessControlContext = "java/security/AccessControlContext"
+
+func Load_Security_AccessController() {
+
+	MethodSignatures["java/security/AccessController.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/security/AccessController.checkPermission(Ljava/security/Permission;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  accessControllerCheckPermission,
+		}
+
+	MethodSignatures["java/security/AccessController.doPrivileged(Ljava/security/PrivilegedAction;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    accessControllerDoPrivileged,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/security/AccessController.doPrivileged(Ljava/security/PrivilegedAction;Ljava/security/AccessControlContext;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    accessControllerDoPrivileged,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/security/AccessController.doPrivileged(Ljava/security/PrivilegedAction;Ljava/security/AccessControlContext;[Ljava/security/Permission;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   3,
+			GFunction:    accessControllerDoPrivileged,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/security/AccessController.doPrivileged(Ljava/security/PrivilegedExceptionAction;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    accessControllerDoPrivilegedException,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/security/AccessController.doPrivileged(Ljava/security/PrivilegedExceptionAction;Ljava/security/AccessControlContext;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    accessControllerDoPrivilegedException,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/security/AccessController.doPrivileged(Ljava/security/PrivilegedExceptionAction;Ljava/security/AccessControlContext;[Ljava/security/Permission;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   3,
+			GFunction:    accessControllerDoPrivilegedException,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/security/AccessController.doPrivilegedWithCombiner(Ljava/security/PrivilegedAction;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    accessControllerDoPrivileged,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/security/AccessController.doPrivilegedWithCombiner(Ljava/security/PrivilegedAction;Ljava/security/AccessControlContext;[Ljava/security/Permission;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   3,
+			GFunction:    accessControllerDoPrivileged,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/security/AccessController.doPrivilegedWithCombiner(Ljava/security/PrivilegedExceptionAction;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    accessControllerDoPrivilegedException,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/security/AccessController.doPrivilegedWithCombiner(Ljava/security/PrivilegedExceptionAction;Ljava/security/AccessControlContext;[Ljava/security/Permission;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   3,
+			GFunction:    accessControllerDoPrivilegedException,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/security/AccessController.getContext()Ljava/security/AccessControlContext;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  accessControllerGetContext,
+		}
+
+	// --- and its contexts ---
+
+	MethodSignatures[classNameAccessControlContext+".<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures[classNameAccessControlContext+".<init>([Ljava/security/ProtectionDomain;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  justReturn,
+		}
+
+	MethodSignatures[classNameAccessControlContext+".checkPermission(Ljava/security/Permission;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  accessControllerCheckPermission,
+		}
+}
+
+// "java/security/AccessController.checkPermission(Ljava/security/Permission;)V" and the
+// same method of AccessControlContext, which grant every permission
+func accessControllerCheckPermission(params []interface{}) interface{} {
+	if object.IsNull(params[len(params)-1]) {
+		return getGErrBlk(excNames.NullPointerException, "accessControllerCheckPermission: null permission")
+	}
+	return nil
+}
+
+// "java/security/AccessController.doPrivileged(Ljava/security/PrivilegedAction;)Ljava/lang/Object;"
+// and the other forms that take a PrivilegedAction: returns what the action's run() returns
+func accessControllerDoPrivileged(params []interface{}) interface{} {
+	ret, gErr := runPrivilegedAction("accessControllerDoPrivileged", params, "java/security/PrivilegedAction")
+	if gErr != nil {
+		return gErr
+	}
+	return ret
+}
+
+// "java/security/AccessController.doPrivileged(Ljava/security/PrivilegedExceptionAction;)Ljava/lang/Object;"
+// and the other forms that take a PrivilegedExceptionAction
+func accessControllerDoPrivilegedException(params []interface{}) interface{} {
+	ret, gErr := runPrivilegedAction("accessControllerDoPrivilegedException", params,
+		"java/security/PrivilegedExceptionAction")
+	if gErr != nil {
+		if !isUncheckedException(gErr) {
+			return getGErrBlk(excNames.PrivilegedActionException, describeGErrBlk(gErr))
+		}
+		return gErr
+	}
+	return ret
+}
+
+// runPrivilegedAction calls the run() method of the action in params[1]
+func runPrivilegedAction(funcName string, params []interface{}, iface string) (any, *GErrBlk) {
+	fs := params[0].(*list.List)
+	action, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(action) {
+		return nil, getGErrBlk(excNames.NullPointerException, funcName+": null action")
+	}
+	ret, gErr := invokeJavaMethod(fs, iface, "run", "()Ljava/lang/Object;", action, nil)
+	if gErr != nil {
+		return nil, gErr
+	}
+	if ret == nil {
+		return object.Null, nil
+	}
+	return ret, nil
+}
+
+// isUncheckedException determines whether the exception is a RuntimeException or an Error,
+// which a method may throw without declaring it
+func isUncheckedException(gErr *GErrBlk) bool {
+	if gErr.ExceptionType < 0 || gErr.ExceptionType >= len(excNames.JVMexceptionNames) {
+		return true // it's rethrown as a RuntimeException
+	}
+	className := strings.ReplaceAll(excNames.JVMexceptionNames[gErr.ExceptionType], ".", "/")
+	return classloader.IsSubclassOf(className, "java/lang/RuntimeException") ||
+		classloader.IsSubclassOf(className, "java/lang/Error")
+}
+
+// "java/security/AccessController.getContext()Ljava/security/AccessControlContext;"
+func accessControllerGetContext([]interface{}) interface{} {
+	return object.MakeEmptyObjectWithClassName(&classNameAccessControlContext)
+}
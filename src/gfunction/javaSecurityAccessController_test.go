@@ -0,0 +1,110 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/exceptions"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"testing"
+)
+
+// installs an upcall bridge that serves the run() upcall of a privileged action with result,
+// or throws the exception cause ("java.io.IOException: msg") if it isn't empty, and returns
+// the action
+func fakePrivilegedAction(t *testing.T, result any, cause string) *object.Object {
+	t.Helper()
+	className := "com/example/Action"
+	action := object.MakeEmptyObjectWithClassName(&className)
+
+	glob := globals.GetGlobalRef()
+	saved := glob.FuncInvokeJavaMethod
+	glob.FuncInvokeJavaMethod = func(_ *list.List, _, methName, methType string, receiver any, _ []any) (any, error) {
+		if receiver != action || methName+methType != "run()Ljava/lang/Object;" {
+			t.Fatalf("unexpected upcall of %s%s on %v", methName, methType, receiver)
+		}
+		if cause != "" {
+			return nil, &exceptions.UpcallError{Method: className + ".run", Cause: cause}
+		}
+		return result, nil
+	}
+	t.Cleanup(func() { glob.FuncInvokeJavaMethod = saved })
+	return action
+}
+
+// adds the classes to the method area, each a subclass of the next
+func addExceptionClasses(names ...string) {
+	for i, name := range names {
+		superIndex := types.ObjectPoolStringIndex
+		if i+1 < len(names) {
+			superIndex = stringPool.GetStringIndex(&names[i+1])
+		}
+		classloader.MethAreaInsert(name, &classloader.Klass{Status: 'F', Loader: "testloader",
+			Data: &classloader.ClData{Name: name, SuperclassIndex: superIndex}})
+	}
+}
+
+func TestDoPrivileged_ReturnsResult(t *testing.T) {
+	globals.InitGlobals("test")
+	result := object.StringObjectFromGoString("done")
+	action := fakePrivilegedAction(t, result, "")
+
+	if ret := accessControllerDoPrivileged([]interface{}{list.New(), action}); ret != result {
+		t.Errorf("Expected doPrivileged() to return the result of run(), got %v", ret)
+	}
+	context := accessControllerGetContext(nil)
+	if ret := accessControllerDoPrivilegedException([]interface{}{list.New(), action, context}); ret != result {
+		t.Errorf("Expected doPrivileged(action, context) to return the result of run(), got %v", ret)
+	}
+
+	ret := accessControllerDoPrivileged([]interface{}{list.New(), object.Null})
+	expectException(t, ret, excNames.NullPointerException, "doPrivileged(null)")
+}
+
+func TestDoPrivileged_Exceptions(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	addExceptionClasses("java/lang/IllegalStateException", "java/lang/RuntimeException",
+		"java/lang/Exception", "java/lang/Throwable")
+	addExceptionClasses("java/io/IOException", "java/lang/Exception", "java/lang/Throwable")
+
+	action := fakePrivilegedAction(t, nil, "java.io.IOException: no disk")
+	ret := accessControllerDoPrivilegedException([]interface{}{list.New(), action})
+	expectException(t, ret, excNames.PrivilegedActionException, "a checked exception from run()")
+	if gErr, ok := ret.(*GErrBlk); ok && gErr.ErrMsg != "java.io.IOException: no disk" {
+		t.Errorf("Expected the cause in the message, got %q", gErr.ErrMsg)
+	}
+
+	action = fakePrivilegedAction(t, nil, "java.lang.IllegalStateException: not now")
+	ret = accessControllerDoPrivilegedException([]interface{}{list.New(), action})
+	expectException(t, ret, excNames.IllegalStateException, "an unchecked exception from run()")
+
+	ret = accessControllerDoPrivileged([]interface{}{list.New(), action})
+	expectException(t, ret, excNames.IllegalStateException, "an exception from the run() of a PrivilegedAction")
+}
+
+func TestSecurityManager_NoneAllowed(t *testing.T) {
+	if ret := systemGetSecurityManager(nil); ret != object.Null {
+		t.Errorf("Expected no security manager, got %v", ret)
+	}
+	if ret := systemSetSecurityManager([]interface{}{object.Null}); ret != nil {
+		t.Errorf("Expected setSecurityManager(null) to do nothing, got %v", ret)
+	}
+	manager := object.MakeEmptyObjectWithClassName(&classNameSecurityManager)
+	ret := systemSetSecurityManager([]interface{}{manager})
+	expectException(t, ret, excNames.UnsupportedOperationException, "setSecurityManager()")
+
+	permission := object.StringObjectFromGoString("a stand-in for a Permission")
+	if ret := accessControllerCheckPermission([]interface{}{permission}); ret != nil {
+		t.Errorf("Expected every permission to be granted, got %v", ret)
+	}
+}
@@ -0,0 +1,336 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
+	"crypto/subtle"
+	"fmt"
+	"hash"
+	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"strings"
+)
+
+// MessageDigest computes digests with Go's crypto packages. The digest being computed
+// is held as a Golang hash.Hash in the MessageDigest object.
+
+func Load_Security_MessageDigest() {
+
+	MethodSignatures["java/security/MessageDigest.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/security/MessageDigest.digest()[B"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  messageDigestDigest,
+		}
+
+	MethodSignatures["java/security/MessageDigest.digest([B)[B"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  messageDigestDigest,
+		}
+
+	MethodSignatures["java/security/MessageDigest.digest([BII)I"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  messageDigestDigestInto,
+		}
+
+	MethodSignatures["java/security/MessageDigest.getAlgorithm()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  messageDigestGetAlgorithm,
+		}
+
+	MethodSignatures["java/security/MessageDigest.getDigestLength()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  messageDigestGetDigestLength,
+		}
+
+	MethodSignatures["java/security/MessageDigest.getInstance(Ljava/lang/String;)Ljava/security/MessageDigest;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  messageDigestGetInstance,
+		}
+
+	MethodSignatures["java/security/MessageDigest.isEqual([B[B)Z"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  messageDigestIsEqual,
+		}
+
+	MethodSignatures["java/security/MessageDigest.reset()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  messageDigestReset,
+		}
+
+	MethodSignatures["java/security/MessageDigest.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  messageDigestToString,
+		}
+
+	MethodSignatures["java/security/MessageDigest.update(B)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  messageDigestUpdateByte,
+		}
+
+	MethodSignatures["java/security/MessageDigest.update([B)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  messageDigestUpdate,
+		}
+
+	MethodSignatures["java/security/MessageDigest.update([BII)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  messageDigestUpdate,
+		}
+
+	// -----------------------------------------
+	// Traps that do nothing but return an error
+	// -----------------------------------------
+
+	MethodSignatures["java/security/MessageDigest.getInstance(Ljava/lang/String;Ljava/lang/String;)Ljava/security/MessageDigest;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["java/security/MessageDigest.getInstance(Ljava/lang/String;Ljava/security/Provider;)Ljava/security/MessageDigest;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["java/security/MessageDigest.getProvider()Ljava/security/Provider;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["java/security/MessageDigest.update(Ljava/nio/ByteBuffer;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  trapFunction,
+		}
+
+}
+
+var classNameMessageDigest = "java/security/MessageDigest"
+
+// The MessageDigest fields
+var fieldNameDigestAlgorithm = "algorithm"
+var fieldNameDigestHash = "hash"
+
+// The supported algorithms, keyed by their standard names in upper case
+var messageDigestAlgorithms = map[string]func() hash.Hash{
+	"MD5":         md5.New,
+	"SHA-1":       sha1.New,
+	"SHA-224":     sha256.New224,
+	"SHA-256":     sha256.New,
+	"SHA-384":     sha512.New384,
+	"SHA-512":     sha512.New,
+	"SHA-512/224": sha512.New512_224,
+	"SHA-512/256": sha512.New512_256,
+	"SHA3-224":    func() hash.Hash { return sha3.New224() },
+	"SHA3-256":    func() hash.Hash { return sha3.New256() },
+	"SHA3-384":    func() hash.Hash { return sha3.New384() },
+	"SHA3-512":    func() hash.Hash { return sha3.New512() },
+}
+
+// Other names the JDK accepts for the algorithms
+var messageDigestAliases = map[string]string{
+	"SHA":    "SHA-1",
+	"SHA1":   "SHA-1",
+	"SHA224": "SHA-224",
+	"SHA256": "SHA-256",
+	"SHA384": "SHA-384",
+	"SHA512": "SHA-512",
+}
+
+// returns the hash in the MessageDigest object params[0]
+func getMessageDigestHash(funcName string, params []interface{}) (hash.Hash, *GErrBlk) {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return nil, getArgsGErrBlk(funcName, err)
+	}
+	h, ok := obj.FieldTable[fieldNameDigestHash].Fvalue.(hash.Hash)
+	if !ok {
+		errMsg := fmt.Sprintf("%s: MessageDigest object lacks a hash field", funcName)
+		return nil, getGErrBlk(excNames.IllegalStateException, errMsg)
+	}
+	return h, nil
+}
+
+// "java/security/MessageDigest.getInstance(Ljava/lang/String;)Ljava/security/MessageDigest;"
+// The algorithm name isn't case-sensitive.
+func messageDigestGetInstance(params []interface{}) interface{} {
+	if object.IsNull(params[0]) {
+		return getGErrBlk(excNames.NullPointerException, "messageDigestGetInstance: null algorithm name")
+	}
+	algorithm, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("messageDigestGetInstance", err)
+	}
+
+	name := strings.ToUpper(algorithm)
+	if alias, ok := messageDigestAliases[name]; ok {
+		name = alias
+	}
+	newHash, ok := messageDigestAlgorithms[name]
+	if !ok {
+		errMsg := fmt.Sprintf("%s MessageDigest not available", algorithm)
+		return getGErrBlk(excNames.NoSuchAlgorithmException, errMsg)
+	}
+
+	obj := object.MakeEmptyObjectWithClassName(&classNameMessageDigest)
+	obj.FieldTable[fieldNameDigestAlgorithm] = object.Field{
+		Ftype: types.StringClassRef, Fvalue: object.StringObjectFromGoString(algorithm)}
+	obj.FieldTable[fieldNameDigestHash] = object.Field{Ftype: types.MessageDigest, Fvalue: newHash()}
+	return obj
+}
+
+// "java/security/MessageDigest.update(B)V"
+func messageDigestUpdateByte(params []interface{}) interface{} {
+	h, gerr := getMessageDigestHash("messageDigestUpdateByte", params)
+	if gerr != nil {
+		return gerr
+	}
+	b, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("messageDigestUpdateByte", err)
+	}
+	h.Write([]byte{byte(b)})
+	return nil
+}
+
+// "java/security/MessageDigest.update([B)V" and "java/security/MessageDigest.update([BII)V"
+func messageDigestUpdate(params []interface{}) interface{} {
+	h, gerr := getMessageDigestHash("messageDigestUpdate", params)
+	if gerr != nil {
+		return gerr
+	}
+	input, gerr := getByteArrayRange("messageDigestUpdate", params, 1)
+	if gerr != nil {
+		return gerr
+	}
+	h.Write(input)
+	return nil
+}
+
+// "java/security/MessageDigest.digest()[B" and "java/security/MessageDigest.digest([B)[B"
+// Completes the digest, after updating it with the bytes passed, if any, and resets it.
+func messageDigestDigest(params []interface{}) interface{} {
+	h, gerr := getMessageDigestHash("messageDigestDigest", params)
+	if gerr != nil {
+		return gerr
+	}
+	if len(params) > 1 {
+		input, gerr := getByteArrayRange("messageDigestDigest", params, 1)
+		if gerr != nil {
+			return gerr
+		}
+		h.Write(input)
+	}
+
+	digest := h.Sum(nil)
+	h.Reset()
+	return Populator("[B", types.ByteArray, object.JavaByteArrayFromGoByteArray(digest))
+}
+
+// "java/security/MessageDigest.digest([BII)I"
+// Completes the digest into the array at the offset and resets it. Returns the length
+// of the digest.
+func messageDigestDigestInto(params []interface{}) interface{} {
+	h, gerr := getMessageDigestHash("messageDigestDigestInto", params)
+	if gerr != nil {
+		return gerr
+	}
+	if byteArrayRangeLength(params, 1) < h.Size() {
+		errMsg := fmt.Sprintf("messageDigestDigestInto: partial digests not returned; buffer too short for %d bytes", h.Size())
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+
+	digest := h.Sum(nil)
+	ret := putByteArrayRange("messageDigestDigestInto", params, 1, digest)
+	if _, ok := ret.(int64); ok {
+		h.Reset()
+	}
+	return ret
+}
+
+// "java/security/MessageDigest.reset()V"
+func messageDigestReset(params []interface{}) interface{} {
+	h, gerr := getMessageDigestHash("messageDigestReset", params)
+	if gerr != nil {
+		return gerr
+	}
+	h.Reset()
+	return nil
+}
+
+// "java/security/MessageDigest.getAlgorithm()Ljava/lang/String;"
+func messageDigestGetAlgorithm(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("messageDigestGetAlgorithm", err)
+	}
+	return obj.FieldTable[fieldNameDigestAlgorithm].Fvalue
+}
+
+// "java/security/MessageDigest.getDigestLength()I"
+func messageDigestGetDigestLength(params []interface{}) interface{} {
+	h, gerr := getMessageDigestHash("messageDigestGetDigestLength", params)
+	if gerr != nil {
+		return gerr
+	}
+	return int64(h.Size())
+}
+
+// "java/security/MessageDigest.toString()Ljava/lang/String;"
+func messageDigestToString(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("messageDigestToString", err)
+	}
+	algorithm := object.GoStringFromStringObject(obj.FieldTable[fieldNameDigestAlgorithm].Fvalue.(*object.Object))
+	return object.StringObjectFromGoString(algorithm + " Message Digest from Jacobin")
+}
+
+// "java/security/MessageDigest.isEqual([B[B)Z"
+// Compares two digests in constant time. Two null digests are equal.
+func messageDigestIsEqual(params []interface{}) interface{} {
+	nullA, nullB := object.IsNull(params[0]), object.IsNull(params[1])
+	if nullA || nullB {
+		return object.JavaBooleanFromGoBoolean(nullA && nullB)
+	}
+
+	digestA, gerr := getByteArrayRange("messageDigestIsEqual", params, 0)
+	if gerr != nil {
+		return gerr
+	}
+	digestB, gerr := getByteArrayRange("messageDigestIsEqual", params[1:], 0)
+	if gerr != nil {
+		return gerr
+	}
+	return object.JavaBooleanFromGoBoolean(subtle.ConstantTimeCompare(digestA, digestB) == 1)
+}
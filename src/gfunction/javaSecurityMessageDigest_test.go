@@ -0,0 +1,139 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"testing"
+)
+
+// returns a MessageDigest for the algorithm, failing the test if there is none
+func newMessageDigest(t *testing.T, algorithm string) *object.Object {
+	t.Helper()
+	ret := messageDigestGetInstance([]interface{}{object.StringObjectFromGoString(algorithm)})
+	md, ok := ret.(*object.Object)
+	if !ok {
+		t.Fatalf("getInstance(%s): expected a MessageDigest, got %v", algorithm, ret)
+	}
+	return md
+}
+
+func TestMessageDigestAlgorithms(t *testing.T) {
+	globals.InitStringPool()
+
+	data := []byte("The quick brown fox jumps over the lazy dog")
+	md5Sum := md5.Sum(data)
+	sha1Sum := sha1.Sum(data)
+	sha256Sum := sha256.Sum256(data)
+	tests := []struct {
+		algorithm string
+		want      []byte
+	}{
+		{"MD5", md5Sum[:]},
+		{"sha-1", sha1Sum[:]},
+		{"SHA", sha1Sum[:]},
+		{"SHA-256", sha256Sum[:]},
+	}
+
+	for _, tt := range tests {
+		md := newMessageDigest(t, tt.algorithm)
+		if got := messageDigestGetDigestLength([]interface{}{md}).(int64); got != int64(len(tt.want)) {
+			t.Errorf("%s: getDigestLength() = %d, want %d", tt.algorithm, got, len(tt.want))
+		}
+
+		// update(byte), then update(byte[], off, len), then digest(byte[])
+		if ret := messageDigestUpdateByte([]interface{}{md, int64(data[0])}); ret != nil {
+			t.Fatalf("%s: update(B) returned %v", tt.algorithm, ret)
+		}
+		if ret := messageDigestUpdate([]interface{}{md, newZipByteArray(data), int64(1), int64(9)}); ret != nil {
+			t.Fatalf("%s: update([BII) returned %v", tt.algorithm, ret)
+		}
+		ret := messageDigestDigest([]interface{}{md, newZipByteArray(data[10:])})
+		got := zipByteArrayContents(ret.(*object.Object))
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("%s: digest = %x, want %x", tt.algorithm, got, tt.want)
+		}
+
+		// digest() resets the MessageDigest
+		ret = messageDigestDigest([]interface{}{md, newZipByteArray(data)})
+		if got = zipByteArrayContents(ret.(*object.Object)); !bytes.Equal(got, tt.want) {
+			t.Errorf("%s: digest after reset = %x, want %x", tt.algorithm, got, tt.want)
+		}
+	}
+}
+
+func TestMessageDigestDigestIntoAndReset(t *testing.T) {
+	globals.InitStringPool()
+
+	data := []byte("abc")
+	want := sha256.Sum256(data)
+	md := newMessageDigest(t, "SHA-256")
+
+	_ = messageDigestUpdate([]interface{}{md, newZipByteArray([]byte("discarded"))})
+	_ = messageDigestReset([]interface{}{md})
+	_ = messageDigestUpdate([]interface{}{md, newZipByteArray(data)})
+
+	buf := newZipByteArray(make([]byte, 40))
+	ret := messageDigestDigestInto([]interface{}{md, buf, int64(4), int64(32)})
+	if n, ok := ret.(int64); !ok || n != 32 {
+		t.Fatalf("digest([BII) = %v, want 32", ret)
+	}
+	if got := zipByteArrayContents(buf)[4:36]; !bytes.Equal(got, want[:]) {
+		t.Errorf("digest([BII) wrote %x, want %x", got, want)
+	}
+
+	ret = messageDigestDigestInto([]interface{}{md, buf, int64(0), int64(16)})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("digest([BII) into a short buffer: expected IllegalArgumentException, got %v", ret)
+	}
+}
+
+func TestMessageDigestGetInstanceErrors(t *testing.T) {
+	globals.InitStringPool()
+
+	ret := messageDigestGetInstance([]interface{}{object.StringObjectFromGoString("MD4")})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.NoSuchAlgorithmException {
+		t.Errorf("getInstance(MD4): expected NoSuchAlgorithmException, got %v", ret)
+	}
+
+	ret = messageDigestGetInstance([]interface{}{object.Null})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.NullPointerException {
+		t.Errorf("getInstance(null): expected NullPointerException, got %v", ret)
+	}
+
+	md := newMessageDigest(t, "sha-512")
+	algorithm := messageDigestGetAlgorithm([]interface{}{md}).(*object.Object)
+	if got := object.GoStringFromStringObject(algorithm); got != "sha-512" {
+		t.Errorf("getAlgorithm() = %q, want %q", got, "sha-512")
+	}
+}
+
+func TestMessageDigestIsEqual(t *testing.T) {
+	a := newZipByteArray([]byte{1, 2, 3})
+	b := newZipByteArray([]byte{1, 2, 3})
+	c := newZipByteArray([]byte{1, 2, 4})
+
+	if messageDigestIsEqual([]interface{}{a, b}) != types.JavaBoolTrue {
+		t.Errorf("isEqual of equal digests returned false")
+	}
+	if messageDigestIsEqual([]interface{}{a, c}) != types.JavaBoolFalse {
+		t.Errorf("isEqual of different digests returned true")
+	}
+	if messageDigestIsEqual([]interface{}{a, object.Null}) != types.JavaBoolFalse {
+		t.Errorf("isEqual of a digest and null returned true")
+	}
+	if messageDigestIsEqual([]interface{}{object.Null, object.Null}) != types.JavaBoolTrue {
+		t.Errorf("isEqual of two nulls returned false")
+	}
+}
@@ -0,0 +1,1160 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// NumberFormat and DecimalFormat. The NumberFormat factory methods return DecimalFormats,
+// so the instance methods are registered for both classes. A DecimalFormat is a pattern,
+// such as #,##0.00, applied with the decimal and grouping separators of a locale. The
+// patterns support the digits # and 0, grouping, the decimal separator, scientific
+// notation (0.###E0), percent and per mille, quoted literals in the prefix and suffix, and
+// a negative subpattern. Numbers are rounded by the exact value of the double, as Java
+// does, using the rounding mode, which is HALF_EVEN by default. The instances without a
+// locale use the default locale, as in Java.
+
+var classNameNumberFormat = "java/text/NumberFormat"
+var classNameDecimalFormat = "java/text/DecimalFormat"
+
+// The field of the DecimalFormat object that holds its state
+var fieldNameDecimalFormatState = "decimalFormatState"
+
+// the patterns of the NumberFormat factory methods
+const (
+	numberInstancePattern  = "#,##0.###"
+	integerInstancePattern = "#,##0"
+	percentInstancePattern = "#,##0%"
+)
+
+func Load_Text_DecimalFormat() {
+
+	MethodSignatures["java/text/NumberFormat.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/text/DecimalFormat.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/text/DecimalFormat.<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  decimalFormatInit,
+		}
+
+	MethodSignatures["java/text/DecimalFormat.<init>(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  decimalFormatInit,
+		}
+
+	MethodSignatures["java/text/DecimalFormat.applyPattern(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  decimalFormatApplyPattern,
+		}
+
+	MethodSignatures["java/text/DecimalFormat.getGroupingSize()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  decimalFormatGetGroupingSize,
+		}
+
+	MethodSignatures["java/text/DecimalFormat.getMultiplier()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  decimalFormatGetMultiplier,
+		}
+
+	MethodSignatures["java/text/DecimalFormat.isDecimalSeparatorAlwaysShown()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  decimalFormatIsDecimalSeparatorAlwaysShown,
+		}
+
+	MethodSignatures["java/text/DecimalFormat.setDecimalSeparatorAlwaysShown(Z)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  decimalFormatSetDecimalSeparatorAlwaysShown,
+		}
+
+	MethodSignatures["java/text/DecimalFormat.setGroupingSize(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  decimalFormatSetGroupingSize,
+		}
+
+	MethodSignatures["java/text/DecimalFormat.toPattern()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  decimalFormatToPattern,
+		}
+
+	// the factory methods
+	for _, name := range []string{"getInstance", "getNumberInstance"} {
+		MethodSignatures["java/text/NumberFormat."+name+"()Ljava/text/NumberFormat;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  numberFormatGetNumberInstance,
+			}
+
+		MethodSignatures["java/text/NumberFormat."+name+"(Ljava/util/Locale;)Ljava/text/NumberFormat;"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  numberFormatGetNumberInstance,
+			}
+	}
+
+	MethodSignatures["java/text/NumberFormat.getIntegerInstance()Ljava/text/NumberFormat;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  numberFormatGetIntegerInstance,
+		}
+
+	MethodSignatures["java/text/NumberFormat.getIntegerInstance(Ljava/util/Locale;)Ljava/text/NumberFormat;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  numberFormatGetIntegerInstance,
+		}
+
+	MethodSignatures["java/text/NumberFormat.getPercentInstance()Ljava/text/NumberFormat;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  numberFormatGetPercentInstance,
+		}
+
+	MethodSignatures["java/text/NumberFormat.getPercentInstance(Ljava/util/Locale;)Ljava/text/NumberFormat;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  numberFormatGetPercentInstance,
+		}
+
+	// the instance methods, which are those of NumberFormat and of DecimalFormat
+	for _, className := range []string{classNameNumberFormat, classNameDecimalFormat} {
+		MethodSignatures[className+".format(D)Ljava/lang/String;"] =
+			GMeth{
+				ParamSlots: 2,
+				GFunction:  decimalFormatFormat,
+			}
+
+		MethodSignatures[className+".format(J)Ljava/lang/String;"] =
+			GMeth{
+				ParamSlots: 2,
+				GFunction:  decimalFormatFormat,
+			}
+
+		MethodSignatures[className+".format(Ljava/lang/Object;)Ljava/lang/String;"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  decimalFormatFormatObject,
+			}
+
+		MethodSignatures[className+".getMaximumFractionDigits()I"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  decimalFormatGetMaximumFractionDigits,
+			}
+
+		MethodSignatures[className+".getMaximumIntegerDigits()I"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  decimalFormatGetMaximumIntegerDigits,
+			}
+
+		MethodSignatures[className+".getMinimumFractionDigits()I"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  decimalFormatGetMinimumFractionDigits,
+			}
+
+		MethodSignatures[className+".getMinimumIntegerDigits()I"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  decimalFormatGetMinimumIntegerDigits,
+			}
+
+		MethodSignatures[className+".getRoundingMode()Ljava/math/RoundingMode;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  decimalFormatGetRoundingMode,
+			}
+
+		MethodSignatures[className+".isGroupingUsed()Z"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  decimalFormatIsGroupingUsed,
+			}
+
+		MethodSignatures[className+".isParseIntegerOnly()Z"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  decimalFormatIsParseIntegerOnly,
+			}
+
+		MethodSignatures[className+".parse(Ljava/lang/String;)Ljava/lang/Number;"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  decimalFormatParse,
+			}
+
+		MethodSignatures[className+".setGroupingUsed(Z)V"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  decimalFormatSetGroupingUsed,
+			}
+
+		MethodSignatures[className+".setMaximumFractionDigits(I)V"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  decimalFormatSetMaximumFractionDigits,
+			}
+
+		MethodSignatures[className+".setMaximumIntegerDigits(I)V"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  decimalFormatSetMaximumIntegerDigits,
+			}
+
+		MethodSignatures[className+".setMinimumFractionDigits(I)V"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  decimalFormatSetMinimumFractionDigits,
+			}
+
+		MethodSignatures[className+".setMinimumIntegerDigits(I)V"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  decimalFormatSetMinimumIntegerDigits,
+			}
+
+		MethodSignatures[className+".setParseIntegerOnly(Z)V"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  decimalFormatSetParseIntegerOnly,
+			}
+
+		MethodSignatures[className+".setRoundingMode(Ljava/math/RoundingMode;)V"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  decimalFormatSetRoundingMode,
+			}
+	}
+
+}
+
+// decimalFormatState is the state of a DecimalFormat: its pattern, as parsed, with the
+// settings made since
+type decimalFormatState struct {
+	symbols              formatSymbols
+	posPrefix, posSuffix string // the affixes as they're written out
+	negPrefix, negSuffix string
+	posPrefixPattern     string // the affixes as they appear in the pattern, for toPattern()
+	posSuffixPattern     string
+	negPattern           string // the negative subpattern, if the pattern has one
+	multiplierExp        int    // the multiplier as a power of ten: 0, or 2 for percent, or 3 for per mille
+	groupingUsed         bool
+	groupingSize         int
+	minInt, maxInt       int
+	minFrac, maxFrac     int
+	minExp               int // the minimum exponent digits; 0 if not in scientific notation
+	decimalAlwaysShown   bool
+	roundingMode         int64
+	parseIntegerOnly     bool
+}
+
+// creates the state of a DecimalFormat for the pattern and the locale with the given name
+func newDecimalFormatState(pattern, locale string) (*decimalFormatState, *GErrBlk) {
+	df := &decimalFormatState{symbols: formatSymbolsForLocale(locale), roundingMode: roundingModeHalfEven}
+	if gErr := df.applyPattern(pattern); gErr != nil {
+		return nil, gErr
+	}
+	return df, nil
+}
+
+// parses the pattern and sets up the state accordingly
+func (df *decimalFormatState) applyPattern(pattern string) *GErrBlk {
+	malformed := func(reason string) *GErrBlk {
+		return getGErrBlk(excNames.IllegalArgumentException, fmt.Sprintf("%s in pattern \"%s\"", reason, pattern))
+	}
+
+	posPattern, negPattern, _ := cutUnquoted(pattern, ';')
+	posPrefix, number, posSuffix, err := splitDecimalSubpattern(posPattern)
+	if err != "" {
+		return malformed(err)
+	}
+
+	var intPart, fracPart, expPart string
+	mantissa, expPart, hasExp := strings.Cut(number, "E")
+	intPart, fracPart, hasPoint := strings.Cut(mantissa, ".")
+	if strings.Contains(fracPart, ".") {
+		return malformed("Multiple decimal separators")
+	}
+	if strings.Contains(fracPart, ",") {
+		return malformed("Malformed pattern: grouping separator after the decimal separator")
+	}
+	if hasExp && (expPart == "" || strings.Trim(expPart, "0") != "") {
+		return malformed("Malformed exponent")
+	}
+	if strings.Contains(strings.TrimLeft(strings.ReplaceAll(intPart, ",", ""), "#,"), "#") {
+		return malformed("Unexpected '0'")
+	}
+	if strings.Contains(strings.TrimRight(fracPart, "#"), "#") {
+		return malformed("Unexpected '0'")
+	}
+
+	digits := strings.ReplaceAll(intPart, ",", "")
+	df.minInt = strings.Count(digits, "0")
+	df.maxInt = math.MaxInt32
+	df.minFrac = strings.Count(fracPart, "0")
+	df.maxFrac = len(fracPart)
+	df.decimalAlwaysShown = hasPoint && fracPart == "" && !hasExp && number != ""
+	df.groupingUsed = false
+	df.groupingSize = 3
+	if lastComma := strings.LastIndexByte(intPart, ','); lastComma >= 0 {
+		df.groupingUsed = true
+		df.groupingSize = len(intPart) - lastComma - 1
+	}
+	df.minExp = 0
+	if hasExp {
+		df.minExp = len(expPart)
+		df.maxInt = len(digits)
+		if df.minInt == 0 && df.maxInt > 0 {
+			df.minInt = 1
+		}
+	}
+
+	df.multiplierExp = 0
+	df.posPrefix, df.posSuffix = df.expandAffix(posPrefix), df.expandAffix(posSuffix)
+	df.posPrefixPattern, df.posSuffixPattern, df.negPattern = posPrefix, posSuffix, negPattern
+	df.negPrefix, df.negSuffix = "-"+df.posPrefix, df.posSuffix
+	if negPattern != "" {
+		negPrefix, _, negSuffix, err := splitDecimalSubpattern(negPattern)
+		if err != "" {
+			return malformed(err)
+		}
+		df.negPrefix, df.negSuffix = df.expandAffix(negPrefix), df.expandAffix(negSuffix)
+	}
+	return nil
+}
+
+// the characters of the number part of a pattern
+const decimalPatternChars = "#0,.E"
+
+// splits a subpattern into its prefix, its number part, and its suffix. Returns the
+// reason, if the subpattern is malformed.
+func splitDecimalSubpattern(subpattern string) (string, string, string, string) {
+	start, end := -1, -1
+	quoted := false
+	for i := 0; i < len(subpattern); i++ {
+		ch := subpattern[i]
+		if ch == '\'' {
+			quoted = !quoted
+			continue
+		}
+		if quoted {
+			continue
+		}
+		isNumberChar := strings.IndexByte(decimalPatternChars, ch) >= 0
+		if ch == 'E' && start < 0 { // an E before the digits is a literal
+			isNumberChar = false
+		}
+		switch {
+		case isNumberChar && start < 0:
+			start, end = i, i+1
+		case isNumberChar && end == i:
+			end = i + 1
+		case isNumberChar:
+			return "", "", "", "Unquoted special character '" + string(ch) + "'"
+		}
+	}
+	if quoted {
+		return "", "", "", "Unterminated quote"
+	}
+	if start < 0 {
+		return subpattern, "", "", ""
+	}
+	return subpattern[:start], subpattern[start:end], subpattern[end:], ""
+}
+
+// cuts the pattern at the first occurrence of sep that is outside quotes
+func cutUnquoted(pattern string, sep byte) (string, string, bool) {
+	quoted := false
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case pattern[i] == '\'':
+			quoted = !quoted
+		case pattern[i] == sep && !quoted:
+			return pattern[:i], pattern[i+1:], true
+		}
+	}
+	return pattern, "", false
+}
+
+// returns the text of a prefix or suffix of the pattern: the quotes are removed (two
+// quotes in a row are a quote), and a percent or per mille sign sets the multiplier
+func (df *decimalFormatState) expandAffix(affix string) string {
+	var b strings.Builder
+	quoted := false
+	for i := 0; i < len(affix); i++ {
+		ch := affix[i]
+		switch {
+		case ch == '\'' && i+1 < len(affix) && affix[i+1] == '\'':
+			b.WriteByte('\'')
+			i++
+		case ch == '\'':
+			quoted = !quoted
+		case quoted:
+			b.WriteByte(ch)
+		case ch == '%':
+			df.multiplierExp = 2
+			b.WriteByte(ch)
+		case strings.HasPrefix(affix[i:], "‰"):
+			df.multiplierExp = 3
+			b.WriteString("‰")
+			i += len("‰") - 1
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
+
+// returns the pattern of the format, as DecimalFormat.toPattern() does
+func (df *decimalFormatState) toPattern() string {
+	var b strings.Builder
+	b.WriteString(df.posPrefixPattern)
+
+	intDigits := df.minInt
+	if df.minExp > 0 && df.maxInt > intDigits {
+		intDigits = df.maxInt
+	}
+	if df.groupingUsed && df.groupingSize > 0 && intDigits <= df.groupingSize {
+		intDigits = df.groupingSize + 1
+	}
+	if intDigits == 0 {
+		intDigits = 1
+	}
+	for pos := intDigits - 1; pos >= 0; pos-- {
+		if pos < df.minInt {
+			b.WriteByte('0')
+		} else {
+			b.WriteByte('#')
+		}
+		if df.groupingUsed && df.groupingSize > 0 && pos > 0 && pos%df.groupingSize == 0 {
+			b.WriteByte(',')
+		}
+	}
+	if df.maxFrac > 0 || df.decimalAlwaysShown {
+		b.WriteByte('.')
+	}
+	b.WriteString(strings.Repeat("0", df.minFrac))
+	b.WriteString(strings.Repeat("#", df.maxFrac-df.minFrac))
+	if df.minExp > 0 {
+		b.WriteString("E" + strings.Repeat("0", df.minExp))
+	}
+
+	b.WriteString(df.posSuffixPattern)
+	if df.negPattern != "" {
+		b.WriteString(";" + df.negPattern)
+	}
+	return b.String()
+}
+
+// formats the number, which is the decimal d, negative if neg is true
+func (df *decimalFormatState) format(neg bool, d formatDecimal) (string, *GErrBlk) {
+	if d.digits != "" && strings.Trim(d.digits, "0") != "" {
+		d.point += df.multiplierExp
+	}
+
+	var intDigits, fracDigits, exponent string
+	if df.minExp > 0 {
+		intDigits, fracDigits, exponent = df.scientificDigits(neg, d)
+	} else {
+		r, inexact := d.roundByMode(d.point+df.maxFrac, df.roundingMode, neg)
+		if inexact && df.roundingMode == roundingModeUnnecessary {
+			return "", getGErrBlk(excNames.ArithmeticException,
+				"Rounding needed with the rounding mode being set to RoundingMode.UNNECESSARY")
+		}
+		for i := 0; i < r.point; i++ {
+			intDigits += string(r.digitAt(i))
+		}
+		intDigits = strings.TrimLeft(intDigits, "0")
+		if len(intDigits) > df.maxInt {
+			intDigits = intDigits[len(intDigits)-df.maxInt:]
+		}
+		for i := 0; i < df.maxFrac; i++ {
+			fracDigits += string(r.digitAt(r.point + i))
+		}
+	}
+
+	if len(intDigits) < df.minInt {
+		intDigits = strings.Repeat("0", df.minInt-len(intDigits)) + intDigits
+	}
+	for len(fracDigits) > df.minFrac && strings.HasSuffix(fracDigits, "0") {
+		fracDigits = fracDigits[:len(fracDigits)-1]
+	}
+	if intDigits == "" && fracDigits == "" {
+		intDigits = "0"
+	}
+
+	var b strings.Builder
+	if neg {
+		b.WriteString(df.negPrefix)
+	} else {
+		b.WriteString(df.posPrefix)
+	}
+	for i, digit := range intDigits {
+		if i > 0 && df.groupingUsed && df.groupingSize > 0 && df.minExp == 0 &&
+			(len(intDigits)-i)%df.groupingSize == 0 {
+			b.WriteRune(df.symbols.grouping)
+		}
+		b.WriteRune(digit)
+	}
+	if fracDigits != "" || df.decimalAlwaysShown {
+		b.WriteRune(df.symbols.decimal)
+	}
+	b.WriteString(fracDigits)
+	b.WriteString(exponent)
+	if neg {
+		b.WriteString(df.negSuffix)
+	} else {
+		b.WriteString(df.posSuffix)
+	}
+	return b.String(), nil
+}
+
+// returns the integer digits, the fraction digits, and the exponent (as in E-3) of the
+// decimal d in scientific notation. The mantissa has the minimum number of integer digits.
+func (df *decimalFormatState) scientificDigits(neg bool, d formatDecimal) (string, string, string) {
+	intCount := df.minInt
+	if intCount == 0 {
+		intCount = 1
+	}
+	exp := 0
+	r := formatDecimal{}
+	if strings.Trim(d.digits, "0") != "" {
+		r, _ = d.roundByMode(intCount+df.maxFrac, df.roundingMode, neg)
+		exp = r.point - intCount
+	}
+	var intDigits, fracDigits string
+	for i := 0; i < intCount; i++ {
+		intDigits += string(r.digitAt(i))
+	}
+	for i := 0; i < df.maxFrac; i++ {
+		fracDigits += string(r.digitAt(intCount + i))
+	}
+
+	expDigits := strconv.Itoa(exp)
+	expSign := ""
+	if exp < 0 {
+		expSign = "-"
+		expDigits = expDigits[1:]
+	}
+	if len(expDigits) < df.minExp {
+		expDigits = strings.Repeat("0", df.minExp-len(expDigits)) + expDigits
+	}
+	return intDigits, fracDigits, "E" + expSign + expDigits
+}
+
+// returns the decimal rounded to n digits by the rounding mode, and whether any of the
+// digits dropped were nonzero. The decimal is the magnitude of a number that is negative
+// if neg is true; this matters to CEILING and FLOOR.
+func (d formatDecimal) roundByMode(n int, mode int64, neg bool) (formatDecimal, bool) {
+	if n >= len(d.digits) {
+		return d, false
+	}
+	var kept string
+	if n > 0 {
+		kept = d.digits[:n]
+	}
+	rest := d.digits
+	if n > 0 {
+		rest = d.digits[n:]
+	}
+	for len(rest) < len(d.digits)-n { // n < 0: the digits dropped include leading zeros
+		rest = "0" + rest
+	}
+	if strings.Trim(rest, "0") == "" {
+		return formatDecimal{digits: kept, point: d.point}, false
+	}
+
+	var up bool
+	switch mode {
+	case roundingModeUp:
+		up = true
+	case roundingModeCeiling:
+		up = !neg
+	case roundingModeFloor:
+		up = neg
+	case roundingModeHalfUp, roundingModeHalfDown, roundingModeHalfEven:
+		half := strings.Compare(rest, "5"+strings.Repeat("0", len(rest)-1))
+		switch {
+		case half > 0:
+			up = true
+		case half == 0 && mode == roundingModeHalfUp:
+			up = true
+		case half == 0 && mode == roundingModeHalfEven:
+			up = kept != "" && (kept[len(kept)-1]-'0')%2 == 1
+		}
+	}
+	if !up {
+		return formatDecimal{digits: kept, point: d.point}, true
+	}
+
+	if n < 0 { // the first digit kept is above all the digits of the number
+		return formatDecimal{digits: "1", point: d.point - n + 1}, true
+	}
+	incremented := []byte(kept)
+	for i := len(incremented) - 1; i >= 0; i-- {
+		if incremented[i] < '9' {
+			incremented[i]++
+			return formatDecimal{digits: string(incremented), point: d.point}, true
+		}
+		incremented[i] = '0'
+	}
+	return formatDecimal{digits: "1" + string(incremented), point: d.point + 1}, true
+}
+
+// returns the exact decimal value of the magnitude of a double
+func exactDecimal(value float64) formatDecimal {
+	text := new(big.Float).SetFloat64(math.Abs(value)).Text('f', 1100)
+	intPart, fracPart, _ := strings.Cut(text, ".")
+	fracPart = strings.TrimRight(fracPart, "0")
+	intPart = strings.TrimLeft(intPart, "0")
+	digits := intPart + fracPart
+	point := len(intPart)
+	trimmed := strings.TrimLeft(digits, "0")
+	point -= len(digits) - len(trimmed)
+	return formatDecimal{digits: trimmed, point: point}
+}
+
+// returns the decimal value of the magnitude of an integer
+func integerDecimal(value *big.Int) formatDecimal {
+	digits := new(big.Int).Abs(value).String()
+	if digits == "0" {
+		return formatDecimal{}
+	}
+	return formatDecimal{digits: digits, point: len(digits)}
+}
+
+// formats a double, as DecimalFormat does: NaN as NaN, and the infinities as ∞ with the
+// prefix and suffix
+func (df *decimalFormatState) formatDouble(value float64) (string, *GErrBlk) {
+	neg := math.Signbit(value)
+	switch {
+	case math.IsNaN(value):
+		return "NaN", nil
+	case math.IsInf(value, 1):
+		return df.posPrefix + "∞" + df.posSuffix, nil
+	case math.IsInf(value, -1):
+		return df.negPrefix + "∞" + df.negSuffix, nil
+	}
+	return df.format(neg, exactDecimal(value))
+}
+
+// returns the state of the DecimalFormat object params[0]
+func getDecimalFormatState(funcName string, params []interface{}) (*decimalFormatState, *GErrBlk) {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return nil, getArgsGErrBlk(funcName, err)
+	}
+	df, ok := obj.FieldTable[fieldNameDecimalFormatState].Fvalue.(*decimalFormatState)
+	if !ok {
+		return nil, getGErrBlk(excNames.IllegalStateException, funcName+": DecimalFormat not initialized")
+	}
+	return df, nil
+}
+
+// makes a DecimalFormat object for the pattern and for the locale in params[0], if any,
+// otherwise the default locale
+func makeDecimalFormat(funcName, pattern string, params []interface{}) (*object.Object, *decimalFormatState, *GErrBlk) {
+	locale := defaultLocaleName()
+	if len(params) > 0 {
+		localeObj, err := args.GetObject(params, 0)
+		if err != nil {
+			return nil, nil, getArgsGErrBlk(funcName, err)
+		}
+		locale = localeName(localeObj)
+	}
+	df, gErr := newDecimalFormatState(pattern, locale)
+	if gErr != nil {
+		return nil, nil, gErr
+	}
+	obj := object.MakeEmptyObjectWithClassName(&classNameDecimalFormat)
+	obj.FieldTable[fieldNameDecimalFormatState] = object.Field{Ftype: types.FormatState, Fvalue: df}
+	return obj, df, nil
+}
+
+// "java/text/NumberFormat.getInstance()Ljava/text/NumberFormat;" and getNumberInstance(),
+// with and without a Locale
+func numberFormatGetNumberInstance(params []interface{}) interface{} {
+	obj, _, gErr := makeDecimalFormat("numberFormatGetNumberInstance", numberInstancePattern, params)
+	if gErr != nil {
+		return gErr
+	}
+	return obj
+}
+
+// "java/text/NumberFormat.getIntegerInstance()Ljava/text/NumberFormat;", with and without
+// a Locale. The fraction is rounded off, and parsing stops at the decimal separator.
+func numberFormatGetIntegerInstance(params []interface{}) interface{} {
+	obj, df, gErr := makeDecimalFormat("numberFormatGetIntegerInstance", integerInstancePattern, params)
+	if gErr != nil {
+		return gErr
+	}
+	df.parseIntegerOnly = true
+	return obj
+}
+
+// "java/text/NumberFormat.getPercentInstance()Ljava/text/NumberFormat;", with and without a Locale
+func numberFormatGetPercentInstance(params []interface{}) interface{} {
+	obj, _, gErr := makeDecimalFormat("numberFormatGetPercentInstance", percentInstancePattern, params)
+	if gErr != nil {
+		return gErr
+	}
+	return obj
+}
+
+// "java/text/DecimalFormat.<init>()V" and "java/text/DecimalFormat.<init>(Ljava/lang/String;)V"
+func decimalFormatInit(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("decimalFormatInit", err)
+	}
+	pattern := numberInstancePattern
+	if len(params) > 1 {
+		if object.IsNull(params[1]) {
+			return getGErrBlk(excNames.NullPointerException, "decimalFormatInit: the pattern is null")
+		}
+		if pattern, err = args.GetGoString(params, 1); err != nil {
+			return getArgsGErrBlk("decimalFormatInit", err)
+		}
+	}
+	df, gErr := newDecimalFormatState(pattern, defaultLocaleName())
+	if gErr != nil {
+		return gErr
+	}
+	obj.FieldTable[fieldNameDecimalFormatState] = object.Field{Ftype: types.FormatState, Fvalue: df}
+	return nil
+}
+
+// "java/text/DecimalFormat.applyPattern(Ljava/lang/String;)V" -- the rounding mode and
+// the locale are kept; the other settings are replaced by those of the pattern
+func decimalFormatApplyPattern(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatApplyPattern", params)
+	if gErr != nil {
+		return gErr
+	}
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "decimalFormatApplyPattern: the pattern is null")
+	}
+	pattern, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("decimalFormatApplyPattern", err)
+	}
+	updated := *df
+	if gErr = updated.applyPattern(pattern); gErr != nil {
+		return gErr
+	}
+	*df = updated
+	return nil
+}
+
+// "java/text/DecimalFormat.toPattern()Ljava/lang/String;"
+func decimalFormatToPattern(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatToPattern", params)
+	if gErr != nil {
+		return gErr
+	}
+	return object.StringObjectFromGoString(df.toPattern())
+}
+
+// "java/text/NumberFormat.format(D)Ljava/lang/String;" and format(J), for NumberFormat
+// and DecimalFormat
+func decimalFormatFormat(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatFormat", params)
+	if gErr != nil {
+		return gErr
+	}
+	var str string
+	switch value := params[1].(type) {
+	case float64:
+		str, gErr = df.formatDouble(value)
+	case int64:
+		str, gErr = df.format(value < 0, integerDecimal(big.NewInt(value)))
+	default:
+		errMsg := fmt.Sprintf("decimalFormatFormat: Parameter type (%T) is illegal", params[1])
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	if gErr != nil {
+		return gErr
+	}
+	return object.StringObjectFromGoString(str)
+}
+
+// "java/text/NumberFormat.format(Ljava/lang/Object;)Ljava/lang/String;" -- the object is
+// a Byte, Short, Integer, Long, Float, Double, or BigInteger
+func decimalFormatFormatObject(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatFormatObject", params)
+	if gErr != nil {
+		return gErr
+	}
+	number, err := args.GetObject(params, 1)
+	if err != nil {
+		return getGErrBlk(excNames.IllegalArgumentException, "Cannot format given Object as a Number")
+	}
+	var str string
+	fld := number.FieldTable["value"]
+	switch value := fld.Fvalue.(type) {
+	case float64:
+		str, gErr = df.formatDouble(value)
+	case int64:
+		if fld.Ftype == types.Bool || fld.Ftype == types.Char {
+			return getGErrBlk(excNames.IllegalArgumentException, "Cannot format given Object as a Number")
+		}
+		str, gErr = df.format(value < 0, integerDecimal(big.NewInt(value)))
+	case *big.Int:
+		str, gErr = df.format(value.Sign() < 0, integerDecimal(value))
+	default:
+		return getGErrBlk(excNames.IllegalArgumentException, "Cannot format given Object as a Number")
+	}
+	if gErr != nil {
+		return gErr
+	}
+	return object.StringObjectFromGoString(str)
+}
+
+// "java/text/NumberFormat.parse(Ljava/lang/String;)Ljava/lang/Number;" -- parses the
+// number at the start of the string and ignores what follows it. The result is a Long if
+// the number is an integer that fits in one, otherwise a Double.
+func decimalFormatParse(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatParse", params)
+	if gErr != nil {
+		return gErr
+	}
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "decimalFormatParse: the string is null")
+	}
+	text, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("decimalFormatParse", err)
+	}
+
+	value, isLong, ok := df.parse(text)
+	if !ok {
+		return getGErrBlk(excNames.ParseException, fmt.Sprintf("Unparseable number: \"%s\"", text))
+	}
+	if isLong {
+		return Populator("java/lang/Long", types.Long, int64(value))
+	}
+	return Populator("java/lang/Double", types.Double, value)
+}
+
+// parses the number at the start of the text. Returns its value, whether it's an integer
+// that fits in a long, and whether a number was found.
+func (df *decimalFormatState) parse(text string) (float64, bool, bool) {
+	// the longer of the prefixes that match decides the sign, as in Java
+	neg := false
+	var rest string
+	posMatch := strings.HasPrefix(text, df.posPrefix)
+	negMatch := strings.HasPrefix(text, df.negPrefix)
+	switch {
+	case negMatch && (!posMatch || len(df.negPrefix) > len(df.posPrefix)):
+		neg, rest = true, text[len(df.negPrefix):]
+	case posMatch:
+		rest = text[len(df.posPrefix):]
+	default:
+		return 0, false, false
+	}
+
+	var digits strings.Builder
+	sawDigit, sawPoint := false, false
+	runes := []rune(rest)
+	i := 0
+	for ; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+			sawDigit = true
+		case r == df.symbols.grouping && df.groupingUsed && !sawPoint:
+		case r == df.symbols.decimal && !sawPoint && !df.parseIntegerOnly:
+			digits.WriteByte('.')
+			sawPoint = true
+		default:
+			goto done
+		}
+	}
+done:
+	if !sawDigit {
+		if strings.HasPrefix(string(runes[i:]), "∞") {
+			return math.Inf(map[bool]int{false: 1, true: -1}[neg]), false, true
+		}
+		return 0, false, false
+	}
+	if df.minExp > 0 && i < len(runes) && runes[i] == 'E' {
+		exp := "E"
+		j := i + 1
+		if j < len(runes) && runes[j] == '-' {
+			exp += "-"
+			j++
+		}
+		start := j
+		for j < len(runes) && runes[j] >= '0' && runes[j] <= '9' {
+			j++
+		}
+		if j > start {
+			digits.WriteString(exp + string(runes[start:j]))
+			i = j
+		}
+	}
+	suffix := df.posSuffix
+	if neg {
+		suffix = df.negSuffix
+	}
+	if !strings.HasPrefix(string(runes[i:]), suffix) {
+		return 0, false, false
+	}
+
+	exact, ok := new(big.Rat).SetString(digits.String())
+	if !ok {
+		return 0, false, false
+	}
+	if df.multiplierExp > 0 {
+		exact.Quo(exact, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(df.multiplierExp)), nil)))
+	}
+	if neg {
+		exact.Neg(exact)
+	}
+	if exact.IsInt() && exact.Num().IsInt64() && !(neg && exact.Sign() == 0) {
+		return float64(exact.Num().Int64()), true, true
+	}
+	value, _ := exact.Float64()
+	if neg && value == 0 {
+		value = math.Copysign(0, -1)
+	}
+	return value, false, true
+}
+
+// "java/text/DecimalFormat.getGroupingSize()I"
+func decimalFormatGetGroupingSize(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatGetGroupingSize", params)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(df.groupingSize)
+}
+
+// "java/text/DecimalFormat.setGroupingSize(I)V"
+func decimalFormatSetGroupingSize(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatSetGroupingSize", params)
+	if gErr != nil {
+		return gErr
+	}
+	size := params[1].(int64)
+	if size < 0 || size > 127 {
+		errMsg := "newValue is out of valid range. value: " + strconv.FormatInt(size, 10)
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	df.groupingSize = int(size)
+	return nil
+}
+
+// "java/text/DecimalFormat.getMultiplier()I" -- 100 for percent, 1000 for per mille, otherwise 1
+func decimalFormatGetMultiplier(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatGetMultiplier", params)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(math.Pow10(df.multiplierExp))
+}
+
+// "java/text/DecimalFormat.isDecimalSeparatorAlwaysShown()Z"
+func decimalFormatIsDecimalSeparatorAlwaysShown(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatIsDecimalSeparatorAlwaysShown", params)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(df.decimalAlwaysShown)
+}
+
+// "java/text/DecimalFormat.setDecimalSeparatorAlwaysShown(Z)V"
+func decimalFormatSetDecimalSeparatorAlwaysShown(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatSetDecimalSeparatorAlwaysShown", params)
+	if gErr != nil {
+		return gErr
+	}
+	df.decimalAlwaysShown = params[1].(int64) != types.JavaBoolFalse
+	return nil
+}
+
+// "java/text/NumberFormat.isGroupingUsed()Z"
+func decimalFormatIsGroupingUsed(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatIsGroupingUsed", params)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(df.groupingUsed)
+}
+
+// "java/text/NumberFormat.setGroupingUsed(Z)V"
+func decimalFormatSetGroupingUsed(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatSetGroupingUsed", params)
+	if gErr != nil {
+		return gErr
+	}
+	df.groupingUsed = params[1].(int64) != types.JavaBoolFalse
+	return nil
+}
+
+// "java/text/NumberFormat.isParseIntegerOnly()Z"
+func decimalFormatIsParseIntegerOnly(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatIsParseIntegerOnly", params)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(df.parseIntegerOnly)
+}
+
+// "java/text/NumberFormat.setParseIntegerOnly(Z)V"
+func decimalFormatSetParseIntegerOnly(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatSetParseIntegerOnly", params)
+	if gErr != nil {
+		return gErr
+	}
+	df.parseIntegerOnly = params[1].(int64) != types.JavaBoolFalse
+	return nil
+}
+
+// "java/text/NumberFormat.getMaximumFractionDigits()I"
+func decimalFormatGetMaximumFractionDigits(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatGetMaximumFractionDigits", params)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(df.maxFrac)
+}
+
+// "java/text/NumberFormat.getMinimumFractionDigits()I"
+func decimalFormatGetMinimumFractionDigits(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatGetMinimumFractionDigits", params)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(df.minFrac)
+}
+
+// "java/text/NumberFormat.getMaximumIntegerDigits()I"
+func decimalFormatGetMaximumIntegerDigits(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatGetMaximumIntegerDigits", params)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(df.maxInt)
+}
+
+// "java/text/NumberFormat.getMinimumIntegerDigits()I"
+func decimalFormatGetMinimumIntegerDigits(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatGetMinimumIntegerDigits", params)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(df.minInt)
+}
+
+// returns the digit count passed to one of the setters, which Java clamps at 0
+func digitCountParam(params []interface{}) int {
+	count := params[1].(int64)
+	if count < 0 {
+		return 0
+	}
+	if count > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int(count)
+}
+
+// "java/text/NumberFormat.setMaximumFractionDigits(I)V" -- lowers the minimum if need be
+func decimalFormatSetMaximumFractionDigits(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatSetMaximumFractionDigits", params)
+	if gErr != nil {
+		return gErr
+	}
+	df.maxFrac = digitCountParam(params)
+	df.minFrac = min(df.minFrac, df.maxFrac)
+	return nil
+}
+
+// "java/text/NumberFormat.setMinimumFractionDigits(I)V" -- raises the maximum if need be
+func decimalFormatSetMinimumFractionDigits(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatSetMinimumFractionDigits", params)
+	if gErr != nil {
+		return gErr
+	}
+	df.minFrac = digitCountParam(params)
+	df.maxFrac = max(df.minFrac, df.maxFrac)
+	return nil
+}
+
+// "java/text/NumberFormat.setMaximumIntegerDigits(I)V" -- lowers the minimum if need be
+func decimalFormatSetMaximumIntegerDigits(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatSetMaximumIntegerDigits", params)
+	if gErr != nil {
+		return gErr
+	}
+	df.maxInt = digitCountParam(params)
+	df.minInt = min(df.minInt, df.maxInt)
+	return nil
+}
+
+// "java/text/NumberFormat.setMinimumIntegerDigits(I)V" -- raises the maximum if need be
+func decimalFormatSetMinimumIntegerDigits(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatSetMinimumIntegerDigits", params)
+	if gErr != nil {
+		return gErr
+	}
+	df.minInt = digitCountParam(params)
+	df.maxInt = max(df.minInt, df.maxInt)
+	return nil
+}
+
+// "java/text/NumberFormat.getRoundingMode()Ljava/math/RoundingMode;"
+func decimalFormatGetRoundingMode(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatGetRoundingMode", params)
+	if gErr != nil {
+		return gErr
+	}
+	return roundingModeObject(df.roundingMode)
+}
+
+// "java/text/NumberFormat.setRoundingMode(Ljava/math/RoundingMode;)V"
+func decimalFormatSetRoundingMode(params []interface{}) interface{} {
+	df, gErr := getDecimalFormatState("decimalFormatSetRoundingMode", params)
+	if gErr != nil {
+		return gErr
+	}
+	mode, gErr := getRoundingModeOrdinal("decimalFormatSetRoundingMode", params[1])
+	if gErr != nil {
+		return gErr
+	}
+	df.roundingMode = mode
+	return nil
+}
@@ -0,0 +1,255 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"math"
+	"math/big"
+	"testing"
+)
+
+// makes a DecimalFormat object for the pattern, as new DecimalFormat(pattern) does, but
+// with the separators of the given locale rather than the default one
+func newTestDecimalFormat(t *testing.T, pattern, locale string) *object.Object {
+	df, gErr := newDecimalFormatState(pattern, locale)
+	if gErr != nil {
+		t.Fatalf("DecimalFormat(%q): unexpected error %s", pattern, gErr.ErrMsg)
+	}
+	obj := object.MakeEmptyObjectWithClassName(&classNameDecimalFormat)
+	obj.FieldTable[fieldNameDecimalFormatState] = object.Field{Ftype: types.FormatState, Fvalue: df}
+	return obj
+}
+
+func decimalFormatString(t *testing.T, df *object.Object, value interface{}) string {
+	ret := decimalFormatFormat([]interface{}{df, value})
+	str, ok := ret.(*object.Object)
+	if !ok {
+		t.Fatalf("format(%v): unexpected result %v", value, ret)
+	}
+	return object.GoStringFromStringObject(str)
+}
+
+func TestDecimalFormatPatterns(t *testing.T) {
+	globals.InitGlobals("test")
+
+	tests := []struct {
+		pattern  string
+		locale   string
+		value    interface{}
+		expected string
+	}{
+		{"#,##0.00", "", 1234567.891, "1,234,567.89"},
+		{"#,##0.00", "", -0.5, "-0.50"},
+		{"#,##0.00", "", 0.0, "0.00"},
+		{"#,##0.00", "de_DE", 1234567.891, "1.234.567,89"},
+		{"#,##0.###", "de_DE", int64(-1234567), "-1.234.567"},
+		{"#,##0.###", "fr_FR", 1234.5, "1 234,5"},
+		{"0.00", "", 2.675, "2.67"}, // 2.67499999999999982236431605997495353221893310546875
+		{"0.00", "", 0.125, "0.12"}, // an exact tie rounds to even
+		{"0.00", "", 0.135, "0.14"}, // 0.13500000000000000888178419700125232338905334472656250
+		{"#.##", "", 0.001, "0"},    // nothing left: a zero is written
+		{"0.0", "", math.Copysign(0, -1), "-0.0"},
+		{"000", "", int64(7), "007"},
+		{"#", "", 1e20, "100000000000000000000"},
+		{"#%", "", 0.256, "26%"},
+		{"#,##0.0‰", "", 0.01234, "12.3‰"},
+		{"#,##0.00;(#,##0.00)", "", -1234.5, "(1,234.50)"},
+		{"#,##0.00;(#,##0.00)", "", 1234.5, "1,234.50"},
+		{"'#'#", "", int64(5), "#5"},
+		{"# 'o''clock'", "", int64(5), "5 o'clock"},
+		{"0.###E0", "", 123456.0, "1.235E5"},
+		{"00.###E0", "", int64(12345), "12.345E3"},
+		{"0.00E00", "", 0.00012345, "1.23E-04"},
+		{"#,##0.00", "", math.NaN(), "NaN"},
+		{"#,##0.00", "", math.Inf(-1), "-∞"},
+		{"#.", "", int64(3), "3."},
+	}
+	for _, tt := range tests {
+		df := newTestDecimalFormat(t, tt.pattern, tt.locale)
+		if got := decimalFormatString(t, df, tt.value); got != tt.expected {
+			t.Errorf("DecimalFormat(%q).format(%v) in %q: got %q want %q",
+				tt.pattern, tt.value, tt.locale, got, tt.expected)
+		}
+	}
+}
+
+func TestDecimalFormatToPatternAndErrors(t *testing.T) {
+	globals.InitGlobals("test")
+
+	for _, pattern := range []string{"#,##0.00", "#,##0.###", "0.###E0", "00.##E00", "#%", "#,##0.00;(#,##0.00)", "0"} {
+		df := newTestDecimalFormat(t, pattern, "")
+		str := decimalFormatToPattern([]interface{}{df}).(*object.Object)
+		if got := object.GoStringFromStringObject(str); got != pattern {
+			t.Errorf("DecimalFormat(%q).toPattern(): got %q", pattern, got)
+		}
+	}
+
+	for _, pattern := range []string{"0.0.0", "#,##0.0,0", "0'x", "0E", "#0#"} {
+		if _, gErr := newDecimalFormatState(pattern, ""); gErr == nil ||
+			gErr.ExceptionType != excNames.IllegalArgumentException {
+			t.Errorf("DecimalFormat(%q): expected IllegalArgumentException, got %v", pattern, gErr)
+		}
+	}
+
+	df := newTestDecimalFormat(t, "0.0", "")
+	ret := decimalFormatApplyPattern([]interface{}{df, object.StringObjectFromGoString("0.0.0")})
+	if gErr, ok := ret.(*GErrBlk); !ok || gErr.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("applyPattern(0.0.0): expected IllegalArgumentException, got %v", ret)
+	}
+	if got := decimalFormatString(t, df, 1.25); got != "1.2" {
+		t.Errorf("a failed applyPattern() changed the format: got %q", got)
+	}
+
+	decimalFormatSetRoundingMode([]interface{}{df, roundingModeObject(roundingModeUnnecessary)})
+	ret = decimalFormatFormat([]interface{}{df, 1.25})
+	if gErr, ok := ret.(*GErrBlk); !ok || gErr.ExceptionType != excNames.ArithmeticException {
+		t.Errorf("format(1.25) with UNNECESSARY: expected ArithmeticException, got %v", ret)
+	}
+	if got := decimalFormatString(t, df, 1.5); got != "1.5" {
+		t.Errorf("format(1.5) with UNNECESSARY: got %q", got)
+	}
+
+	ret = decimalFormatFormatObject([]interface{}{df, object.StringObjectFromGoString("1")})
+	if gErr, ok := ret.(*GErrBlk); !ok || gErr.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("format(String): expected IllegalArgumentException, got %v", ret)
+	}
+}
+
+func TestDecimalFormatSettings(t *testing.T) {
+	globals.InitGlobals("test")
+
+	df := newTestDecimalFormat(t, numberInstancePattern, "")
+	decimalFormatSetMinimumFractionDigits([]interface{}{df, int64(5)})
+	if maxFrac := decimalFormatGetMaximumFractionDigits([]interface{}{df}); maxFrac != int64(5) {
+		t.Errorf("setMinimumFractionDigits(5) should raise the maximum to 5, got %v", maxFrac)
+	}
+	decimalFormatSetMaximumFractionDigits([]interface{}{df, int64(1)})
+	if minFrac := decimalFormatGetMinimumFractionDigits([]interface{}{df}); minFrac != int64(1) {
+		t.Errorf("setMaximumFractionDigits(1) should lower the minimum to 1, got %v", minFrac)
+	}
+	decimalFormatSetMinimumIntegerDigits([]interface{}{df, int64(3)})
+	decimalFormatSetGroupingUsed([]interface{}{df, types.JavaBoolFalse})
+	if got := decimalFormatString(t, df, 1234.56); got != "1234.6" {
+		t.Errorf("format(1234.56): got %q", got)
+	}
+	if got := decimalFormatString(t, df, 0.04); got != "000.0" {
+		t.Errorf("format(0.04): got %q", got)
+	}
+	decimalFormatSetMaximumIntegerDigits([]interface{}{df, int64(2)})
+	if got := decimalFormatString(t, df, 1234.56); got != "34.6" {
+		t.Errorf("format(1234.56) with 2 integer digits: got %q", got)
+	}
+
+	decimalFormatSetRoundingMode([]interface{}{df, roundingModeObject(roundingModeCeiling)})
+	if got := decimalFormatString(t, df, -1.26); got != "-01.2" {
+		t.Errorf("format(-1.26) with CEILING: got %q", got)
+	}
+	mode := decimalFormatGetRoundingMode([]interface{}{df}).(*object.Object)
+	if ordinal, _ := getRoundingModeOrdinal("test", mode); ordinal != roundingModeCeiling {
+		t.Errorf("getRoundingMode(): got %d", ordinal)
+	}
+
+	percent := numberFormatGetPercentInstance([]interface{}{makeLocale("en_US")}).(*object.Object)
+	if got := decimalFormatString(t, percent, 0.125); got != "12%" {
+		t.Errorf("getPercentInstance().format(0.125): got %q", got)
+	}
+	if multiplier := decimalFormatGetMultiplier([]interface{}{percent}); multiplier != int64(100) {
+		t.Errorf("getMultiplier(): got %v", multiplier)
+	}
+
+	integer := numberFormatGetIntegerInstance([]interface{}{makeLocale("de_DE")}).(*object.Object)
+	if got := decimalFormatString(t, integer, 12345.5); got != "12.346" {
+		t.Errorf("getIntegerInstance().format(12345.5): got %q", got)
+	}
+	big := Populator("java/math/BigInteger", types.Ref, new(big.Int).Lsh(big.NewInt(1), 70))
+	str := decimalFormatFormatObject([]interface{}{integer, big}).(*object.Object)
+	if got := object.GoStringFromStringObject(str); got != "1.180.591.620.717.411.303.424" {
+		t.Errorf("format(2^70): got %q", got)
+	}
+}
+
+func TestDecimalFormatParse(t *testing.T) {
+	globals.InitGlobals("test")
+
+	tests := []struct {
+		pattern  string
+		locale   string
+		text     string
+		expected interface{}
+	}{
+		{"#,##0.###", "", "1,234", int64(1234)},
+		{"#,##0.###", "", "-1,234.5xyz", -1234.5},
+		{"#,##0.###", "de_DE", "1.234,5", 1234.5},
+		{"#,##0.###", "", "-0", math.Copysign(0, -1)},
+		{"#%", "", "50%", 0.5},
+		{"#%", "", "200%", int64(2)},
+		{"#,##0.00;(#,##0.00)", "", "(12.50)", -12.5},
+		{"0.###E0", "", "1.5E3", int64(1500)},
+	}
+	for _, tt := range tests {
+		df := newTestDecimalFormat(t, tt.pattern, tt.locale)
+		ret := decimalFormatParse([]interface{}{df, object.StringObjectFromGoString(tt.text)})
+		number, ok := ret.(*object.Object)
+		if !ok {
+			t.Errorf("parse(%q): unexpected result %v", tt.text, ret)
+			continue
+		}
+		got := number.FieldTable["value"].Fvalue
+		if got != tt.expected || math.Signbit(toFloat(got)) != math.Signbit(toFloat(tt.expected)) {
+			t.Errorf("DecimalFormat(%q).parse(%q): got %v (%T) want %v (%T)",
+				tt.pattern, tt.text, got, got, tt.expected, tt.expected)
+		}
+	}
+
+	df := newTestDecimalFormat(t, "#,##0.###", "")
+	ret := decimalFormatParse([]interface{}{df, object.StringObjectFromGoString("abc")})
+	if gErr, ok := ret.(*GErrBlk); !ok || gErr.ExceptionType != excNames.ParseException ||
+		gErr.ErrMsg != "Unparseable number: \"abc\"" {
+		t.Errorf("parse(abc): expected ParseException, got %v", ret)
+	}
+	decimalFormatSetParseIntegerOnly([]interface{}{df, types.JavaBoolTrue})
+	ret = decimalFormatParse([]interface{}{df, object.StringObjectFromGoString("12.75")})
+	if got := ret.(*object.Object).FieldTable["value"].Fvalue; got != int64(12) {
+		t.Errorf("parse(12.75) with parseIntegerOnly: got %v", got)
+	}
+}
+
+func toFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+func TestDecimalFormatRegistrations(t *testing.T) {
+	globals.InitGlobals("test")
+	MethodSignatures = make(map[string]GMeth)
+	Load_Text_DecimalFormat()
+
+	for _, sig := range []string{
+		"java/text/NumberFormat.getInstance()Ljava/text/NumberFormat;",
+		"java/text/NumberFormat.getNumberInstance(Ljava/util/Locale;)Ljava/text/NumberFormat;",
+		"java/text/NumberFormat.format(D)Ljava/lang/String;",
+		"java/text/DecimalFormat.format(J)Ljava/lang/String;",
+		"java/text/DecimalFormat.<init>(Ljava/lang/String;)V",
+		"java/text/DecimalFormat.setRoundingMode(Ljava/math/RoundingMode;)V",
+	} {
+		if _, ok := MethodSignatures[sig]; !ok {
+			t.Errorf("%s is not registered", sig)
+		}
+	}
+	if MethodSignatures["java/text/DecimalFormat.format(D)Ljava/lang/String;"].ParamSlots != 2 {
+		t.Errorf("format(D) should take 2 slots")
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/object"
+	"time"
+)
+
+// Implementation of java/time/Clock for the system clock.
+// Strategy: Clock.systemUTC() and Clock.systemDefaultZone() return a Clock$SystemClock object
+// whose millis() and instant() read the wall clock, as System.currentTimeMillis() does. Like
+// the JDK's, this clock is not monotonic: it follows any change to the system time. Code that
+// measures intervals should use System.nanoTime(), which is.
+
+var classNameSystemClock = "java/time/Clock$SystemClock"
+
+func Load_Time_Clock() {
+
+	MethodSignatures["java/time/Clock.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/time/Clock.systemDefaultZone()Ljava/time/Clock;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clockSystemUTC, // the zone is not recorded
+		}
+
+	MethodSignatures["java/time/Clock.systemUTC()Ljava/time/Clock;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clockSystemUTC,
+		}
+
+	// The instance methods can be called through either class.
+	for _, className := range []string{"java/time/Clock", classNameSystemClock} {
+		MethodSignatures[className+".instant()Ljava/time/Instant;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  clockInstant,
+			}
+
+		MethodSignatures[className+".millis()J"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  clockMillis,
+			}
+	}
+}
+
+// "java/time/Clock.systemUTC()Ljava/time/Clock;" and systemDefaultZone()
+func clockSystemUTC([]interface{}) interface{} {
+	return object.MakeEmptyObjectWithClassName(&classNameSystemClock)
+}
+
+// "java/time/Clock.instant()Ljava/time/Instant;"
+func clockInstant([]interface{}) interface{} {
+	return makeInstantFromTime(time.Now())
+}
+
+// "java/time/Clock.millis()J" -- the same reading as System.currentTimeMillis()
+func clockMillis([]interface{}) interface{} {
+	return time.Now().UnixMilli()
+}
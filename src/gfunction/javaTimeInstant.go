@@ -0,0 +1,393 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"fmt"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/statics"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+	"math"
+	"time"
+)
+
+// Implementation of java/time/Instant, a point on the UTC time-line.
+// Strategy: as in the JDK, an Instant holds the seconds since the epoch, 1970-01-01T00:00:00Z,
+// in its seconds field and the nanoseconds within that second, 0 to 999,999,999, in its nanos field.
+
+var classNameInstant = "java/time/Instant"
+
+const nanosPerSecond = 1_000_000_000
+
+func Load_Time_Instant() {
+
+	MethodSignatures["java/time/Instant.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  instantClinit,
+		}
+
+	MethodSignatures["java/time/Instant.compareTo(Ljava/time/Instant;)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  instantCompareTo,
+		}
+
+	MethodSignatures["java/time/Instant.equals(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  instantEquals,
+		}
+
+	MethodSignatures["java/time/Instant.getEpochSecond()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  instantGetEpochSecond,
+		}
+
+	MethodSignatures["java/time/Instant.getNano()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  instantGetNano,
+		}
+
+	MethodSignatures["java/time/Instant.hashCode()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  instantHashCode,
+		}
+
+	MethodSignatures["java/time/Instant.isAfter(Ljava/time/Instant;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  instantIsAfter,
+		}
+
+	MethodSignatures["java/time/Instant.isBefore(Ljava/time/Instant;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  instantIsBefore,
+		}
+
+	MethodSignatures["java/time/Instant.now()Ljava/time/Instant;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  instantNow,
+		}
+
+	MethodSignatures["java/time/Instant.now(Ljava/time/Clock;)Ljava/time/Instant;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  instantNow, // every Clock is the system clock
+		}
+
+	MethodSignatures["java/time/Instant.ofEpochMilli(J)Ljava/time/Instant;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  instantOfEpochMilli,
+		}
+
+	MethodSignatures["java/time/Instant.ofEpochSecond(J)Ljava/time/Instant;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  instantOfEpochSecond,
+		}
+
+	MethodSignatures["java/time/Instant.ofEpochSecond(JJ)Ljava/time/Instant;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  instantOfEpochSecond,
+		}
+
+	MethodSignatures["java/time/Instant.plusMillis(J)Ljava/time/Instant;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  instantPlusMillis,
+		}
+
+	MethodSignatures["java/time/Instant.plusNanos(J)Ljava/time/Instant;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  instantPlusNanos,
+		}
+
+	MethodSignatures["java/time/Instant.plusSeconds(J)Ljava/time/Instant;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  instantPlusSeconds,
+		}
+
+	MethodSignatures["java/time/Instant.toEpochMilli()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  instantToEpochMilli,
+		}
+
+	MethodSignatures["java/time/Instant.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  instantToString,
+		}
+}
+
+// "java/time/Instant.<clinit>()V" -- create EPOCH
+func instantClinit([]interface{}) interface{} {
+	klass := classloader.MethAreaFetch(classNameInstant)
+	if klass == nil || klass.Data == nil {
+		errMsg := fmt.Sprintf("instantClinit: Expected %s to be in the MethodArea, but it was not", classNameInstant)
+		trace.Error(errMsg)
+		return getGErrBlk(excNames.ClassNotLoadedException, errMsg)
+	}
+	if klass.Data.ClInit != types.ClInitRun {
+		_ = statics.AddStatic(classNameInstant+".EPOCH",
+			statics.Static{Type: "Ljava/time/Instant;", Value: makeInstant(0, 0)})
+		klass.Data.ClInit = types.ClInitRun
+	}
+	return nil
+}
+
+// makeInstant creates an Instant; nanos must be from 0 to 999,999,999
+func makeInstant(seconds, nanos int64) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&classNameInstant)
+	obj.FieldTable["seconds"] = object.Field{Ftype: types.Long, Fvalue: seconds}
+	obj.FieldTable["nanos"] = object.Field{Ftype: types.Int, Fvalue: nanos}
+	return obj
+}
+
+// makeInstantFromTime creates an Instant for a Go time
+func makeInstantFromTime(t time.Time) *object.Object {
+	return makeInstant(t.Unix(), int64(t.Nanosecond()))
+}
+
+// getInstant returns the seconds and nanos of an Instant object
+func getInstant(funcName string, instant interface{}) (int64, int64, *GErrBlk) {
+	obj, ok := instant.(*object.Object)
+	if !ok || object.IsNull(obj) {
+		errMsg := fmt.Sprintf("%s: the Instant is null", funcName)
+		return 0, 0, getGErrBlk(excNames.NullPointerException, errMsg)
+	}
+	seconds, ok1 := obj.FieldTable["seconds"].Fvalue.(int64)
+	nanos, ok2 := obj.FieldTable["nanos"].Fvalue.(int64)
+	if !ok1 || !ok2 {
+		errMsg := fmt.Sprintf("%s: the object is not an Instant", funcName)
+		return 0, 0, getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	return seconds, nanos, nil
+}
+
+// addToInstant returns the Instant that is the given seconds and nanoseconds (which can be
+// any size, and either sign) after the given one
+func addToInstant(funcName string, seconds, nanos, addSeconds, addNanos int64) interface{} {
+	carrySeconds, addNanos := floorDivMod(addNanos, nanosPerSecond)
+	sum, overflow := addInt64(seconds, addSeconds)
+	if !overflow {
+		sum, overflow = addInt64(sum, carrySeconds)
+	}
+	nanos += addNanos
+	if nanos >= nanosPerSecond && !overflow {
+		sum, overflow = addInt64(sum, 1)
+		nanos -= nanosPerSecond
+	}
+	if overflow {
+		errMsg := fmt.Sprintf("%s: long overflow", funcName)
+		return getGErrBlk(excNames.ArithmeticException, errMsg)
+	}
+	return makeInstant(sum, nanos)
+}
+
+// floorDivMod returns the quotient rounded toward negative infinity, and the remainder, which
+// has the sign of the divisor, as Math.floorDiv and Math.floorMod do
+func floorDivMod(x, y int64) (int64, int64) {
+	quotient, remainder := x/y, x%y
+	if remainder != 0 && (remainder < 0) != (y < 0) {
+		quotient--
+		remainder += y
+	}
+	return quotient, remainder
+}
+
+// addInt64 adds two longs, reporting whether the sum overflowed
+func addInt64(x, y int64) (int64, bool) {
+	sum := x + y
+	return sum, (x >= 0) == (y >= 0) && (sum >= 0) != (x >= 0)
+}
+
+// "java/time/Instant.now()Ljava/time/Instant;" -- the current time from the system clock
+func instantNow([]interface{}) interface{} {
+	return makeInstantFromTime(time.Now())
+}
+
+// "java/time/Instant.ofEpochMilli(J)Ljava/time/Instant;"
+func instantOfEpochMilli(params []interface{}) interface{} {
+	seconds, millis := floorDivMod(params[0].(int64), 1000)
+	return makeInstant(seconds, millis*1_000_000)
+}
+
+// "java/time/Instant.ofEpochSecond(J)Ljava/time/Instant;" and ofEpochSecond(JJ), whose
+// nanosecond adjustment can be any size, and either sign
+func instantOfEpochSecond(params []interface{}) interface{} {
+	var adjustment int64
+	if len(params) > 1 {
+		adjustment = params[1].(int64)
+	}
+	return addToInstant("instantOfEpochSecond", params[0].(int64), 0, 0, adjustment)
+}
+
+// "java/time/Instant.getEpochSecond()J"
+func instantGetEpochSecond(params []interface{}) interface{} {
+	seconds, _, gerr := getInstant("instantGetEpochSecond", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	return seconds
+}
+
+// "java/time/Instant.getNano()I"
+func instantGetNano(params []interface{}) interface{} {
+	_, nanos, gerr := getInstant("instantGetNano", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	return nanos
+}
+
+// "java/time/Instant.toEpochMilli()J" -- throws ArithmeticException if the result doesn't fit in a long
+func instantToEpochMilli(params []interface{}) interface{} {
+	seconds, nanos, gerr := getInstant("instantToEpochMilli", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	if seconds > math.MaxInt64/1000 || seconds < math.MinInt64/1000 {
+		return getGErrBlk(excNames.ArithmeticException, "long overflow")
+	}
+	millis, overflow := addInt64(seconds*1000, nanos/1_000_000)
+	if overflow {
+		return getGErrBlk(excNames.ArithmeticException, "long overflow")
+	}
+	return millis
+}
+
+// "java/time/Instant.plusSeconds(J)Ljava/time/Instant;"
+func instantPlusSeconds(params []interface{}) interface{} {
+	seconds, nanos, gerr := getInstant("instantPlusSeconds", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	return addToInstant("instantPlusSeconds", seconds, nanos, params[1].(int64), 0)
+}
+
+// "java/time/Instant.plusMillis(J)Ljava/time/Instant;"
+func instantPlusMillis(params []interface{}) interface{} {
+	seconds, nanos, gerr := getInstant("instantPlusMillis", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	addSeconds, millis := floorDivMod(params[1].(int64), 1000)
+	return addToInstant("instantPlusMillis", seconds, nanos, addSeconds, millis*1_000_000)
+}
+
+// "java/time/Instant.plusNanos(J)Ljava/time/Instant;"
+func instantPlusNanos(params []interface{}) interface{} {
+	seconds, nanos, gerr := getInstant("instantPlusNanos", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	return addToInstant("instantPlusNanos", seconds, nanos, 0, params[1].(int64))
+}
+
+// compareInstants returns -1, 0, or 1 as the first Instant is before, the same as, or after the second
+func compareInstants(funcName string, params []interface{}) (int64, *GErrBlk) {
+	seconds1, nanos1, gerr := getInstant(funcName, params[0])
+	if gerr != nil {
+		return 0, gerr
+	}
+	seconds2, nanos2, gerr := getInstant(funcName, params[1])
+	if gerr != nil {
+		return 0, gerr
+	}
+	switch {
+	case seconds1 < seconds2 || (seconds1 == seconds2 && nanos1 < nanos2):
+		return -1, nil
+	case seconds1 == seconds2 && nanos1 == nanos2:
+		return 0, nil
+	default:
+		return 1, nil
+	}
+}
+
+// "java/time/Instant.compareTo(Ljava/time/Instant;)I"
+func instantCompareTo(params []interface{}) interface{} {
+	cmp, gerr := compareInstants("instantCompareTo", params)
+	if gerr != nil {
+		return gerr
+	}
+	return cmp
+}
+
+// "java/time/Instant.isAfter(Ljava/time/Instant;)Z"
+func instantIsAfter(params []interface{}) interface{} {
+	cmp, gerr := compareInstants("instantIsAfter", params)
+	if gerr != nil {
+		return gerr
+	}
+	return object.JavaBooleanFromGoBoolean(cmp > 0)
+}
+
+// "java/time/Instant.isBefore(Ljava/time/Instant;)Z"
+func instantIsBefore(params []interface{}) interface{} {
+	cmp, gerr := compareInstants("instantIsBefore", params)
+	if gerr != nil {
+		return gerr
+	}
+	return object.JavaBooleanFromGoBoolean(cmp < 0)
+}
+
+// "java/time/Instant.equals(Ljava/lang/Object;)Z"
+func instantEquals(params []interface{}) interface{} {
+	that, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(that) || object.GoStringFromStringPoolIndex(that.KlassName) != classNameInstant {
+		return types.JavaBoolFalse
+	}
+	cmp, gerr := compareInstants("instantEquals", params)
+	if gerr != nil {
+		return gerr
+	}
+	return object.JavaBooleanFromGoBoolean(cmp == 0)
+}
+
+// "java/time/Instant.hashCode()I"
+func instantHashCode(params []interface{}) interface{} {
+	seconds, nanos, gerr := getInstant("instantHashCode", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	return int64(int32(seconds^int64(uint64(seconds)>>32)) + 51*int32(nanos))
+}
+
+// "java/time/Instant.toString()Ljava/lang/String;" -- in the ISO-8601 format of
+// DateTimeFormatter.ISO_INSTANT, e.g. 2011-12-03T10:15:30.123Z, with the fraction of
+// the second in as many groups of three digits as it needs
+func instantToString(params []interface{}) interface{} {
+	seconds, nanos, gerr := getInstant("instantToString", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	str := time.Unix(seconds, 0).UTC().Format("2006-01-02T15:04:05")
+	switch {
+	case nanos == 0:
+	case nanos%1_000_000 == 0:
+		str += fmt.Sprintf(".%03d", nanos/1_000_000)
+	case nanos%1000 == 0:
+		str += fmt.Sprintf(".%06d", nanos/1000)
+	default:
+		str += fmt.Sprintf(".%09d", nanos)
+	}
+	return object.StringObjectFromGoString(str + "Z")
+}
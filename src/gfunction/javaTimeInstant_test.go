@@ -0,0 +1,107 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"math"
+	"testing"
+	"time"
+)
+
+func instantGoString(instant interface{}) string {
+	return object.GoStringFromStringObject(instantToString([]interface{}{instant}).(*object.Object))
+}
+
+func TestInstantConstructionAndToString(t *testing.T) {
+	globals.InitStringPool()
+
+	tests := []struct {
+		instant interface{}
+		want    string
+	}{
+		{instantOfEpochSecond([]interface{}{int64(0)}), "1970-01-01T00:00:00Z"},
+		{instantOfEpochMilli([]interface{}{int64(1_322_907_330_123)}), "2011-12-03T10:15:30.123Z"},
+		{instantOfEpochMilli([]interface{}{int64(-1)}), "1969-12-31T23:59:59.999Z"},
+		{instantOfEpochSecond([]interface{}{int64(10), int64(-1)}), "1970-01-01T00:00:09.999999999Z"},
+		{instantOfEpochSecond([]interface{}{int64(0), int64(2_000_001_000)}), "1970-01-01T00:00:02.000001Z"},
+	}
+	for _, tt := range tests {
+		if got := instantGoString(tt.instant); got != tt.want {
+			t.Errorf("toString = %q, want %q", got, tt.want)
+		}
+	}
+
+	instant := instantOfEpochMilli([]interface{}{int64(-1)})
+	if seconds := instantGetEpochSecond([]interface{}{instant}).(int64); seconds != -1 {
+		t.Errorf("getEpochSecond = %d, want -1", seconds)
+	}
+	if nanos := instantGetNano([]interface{}{instant}).(int64); nanos != 999_000_000 {
+		t.Errorf("getNano = %d, want 999000000", nanos)
+	}
+	if millis := instantToEpochMilli([]interface{}{instant}).(int64); millis != -1 {
+		t.Errorf("toEpochMilli = %d, want -1", millis)
+	}
+
+	ret := instantToEpochMilli([]interface{}{instantOfEpochSecond([]interface{}{int64(math.MaxInt64 / 10)})})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.ArithmeticException {
+		t.Errorf("toEpochMilli overflow: expected ArithmeticException, got %v", ret)
+	}
+	ret = instantPlusSeconds([]interface{}{instantOfEpochSecond([]interface{}{int64(math.MaxInt64)}), int64(1)})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.ArithmeticException {
+		t.Errorf("plusSeconds overflow: expected ArithmeticException, got %v", ret)
+	}
+}
+
+func TestInstantArithmeticAndComparison(t *testing.T) {
+	globals.InitStringPool()
+
+	start := instantOfEpochSecond([]interface{}{int64(100), int64(900_000_000)})
+	later := instantPlusMillis([]interface{}{start, int64(200)})
+	if got := instantGoString(later); got != "1970-01-01T00:01:41.100Z" {
+		t.Errorf("plusMillis(200) = %q", got)
+	}
+	earlier := instantPlusNanos([]interface{}{start, int64(-900_000_001)})
+	if got := instantGoString(earlier); got != "1970-01-01T00:01:39.999999999Z" {
+		t.Errorf("plusNanos(-900000001) = %q", got)
+	}
+
+	if instantIsBefore([]interface{}{earlier, later}) != types.JavaBoolTrue ||
+		instantIsAfter([]interface{}{earlier, later}) != types.JavaBoolFalse {
+		t.Errorf("isBefore/isAfter are wrong")
+	}
+	if cmp := instantCompareTo([]interface{}{later, start}).(int64); cmp != 1 {
+		t.Errorf("compareTo = %d, want 1", cmp)
+	}
+	same := instantOfEpochMilli([]interface{}{int64(100_900)})
+	if instantEquals([]interface{}{start, same}) != types.JavaBoolTrue {
+		t.Errorf("equal instants are not equal")
+	}
+	if instantHashCode([]interface{}{start}) != instantHashCode([]interface{}{same}) {
+		t.Errorf("equal instants have different hash codes")
+	}
+}
+
+func TestInstantNowAndClock(t *testing.T) {
+	globals.InitStringPool()
+
+	before := time.Now().UnixMilli()
+	clock := clockSystemUTC(nil).(*object.Object)
+	millis := clockMillis([]interface{}{clock}).(int64)
+	fromClock := instantToEpochMilli([]interface{}{clockInstant([]interface{}{clock})}).(int64)
+	fromNow := instantToEpochMilli([]interface{}{instantNow(nil)}).(int64)
+	after := time.Now().UnixMilli()
+
+	for _, got := range []int64{millis, fromClock, fromNow} {
+		if got < before || got > after {
+			t.Errorf("wall-clock reading %d is not between %d and %d", got, before, after)
+		}
+	}
+}
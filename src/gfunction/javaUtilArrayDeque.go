@@ -0,0 +1,815 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"strings"
+)
+
+// ArrayDeque keeps its elements in a circular buffer that doubles in size when it's full,
+// as Java's does, so elements are added and removed at either end in constant time. As in
+// Java, null elements aren't allowed, and iterators throw a ConcurrentModificationException
+// if the deque is changed other than by their remove().
+
+var classNameArrayDeque = "java/util/ArrayDeque"
+var classNameArrayDequeIterator = "java/util/ArrayDeque$DeqIterator"
+var classNameArrayDequeDescendingIterator = "java/util/ArrayDeque$DescendingIterator"
+
+// The field of ArrayDeque and its iterators that holds the Go state
+var fieldNameDeque = "deque"
+
+func Load_Util_ArrayDeque() {
+
+	object.RegisterInstantiationHook(classNameArrayDeque, newArrayDequeObject)
+
+	MethodSignatures["java/util/ArrayDeque.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequeInit,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.<init>(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  arraydequeInit,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.<init>(Ljava/util/Collection;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    arraydequeInitFromCollection,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.add(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  arraydequeOfferLast,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.addAll(Ljava/util/Collection;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    arraydequeAddAll,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.addFirst(Ljava/lang/Object;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  arraydequeAddFirst,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.addLast(Ljava/lang/Object;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  arraydequeAddLast,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.clear()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequeClear,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.clone()Ljava/util/ArrayDeque;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequeClone,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.contains(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    arraydequeContains,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.descendingIterator()Ljava/util/Iterator;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequeDescendingIterator,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.element()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequeGetFirst,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.getFirst()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequeGetFirst,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.getLast()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequeGetLast,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.isEmpty()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequeIsEmpty,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.iterator()Ljava/util/Iterator;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequeIterator,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.offer(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  arraydequeOfferLast,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.offerFirst(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  arraydequeOfferFirst,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.offerLast(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  arraydequeOfferLast,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.peek()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequePeekFirst,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.peekFirst()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequePeekFirst,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.peekLast()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequePeekLast,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.poll()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequePollFirst,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.pollFirst()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequePollFirst,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.pollLast()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequePollLast,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.pop()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequeRemoveFirst,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.push(Ljava/lang/Object;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  arraydequeAddFirst,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.remove()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequeRemoveFirst,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.remove(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    arraydequeRemoveFirstOccurrence,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.removeFirst()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequeRemoveFirst,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.removeFirstOccurrence(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    arraydequeRemoveFirstOccurrence,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.removeLast()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequeRemoveLast,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.removeLastOccurrence(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    arraydequeRemoveLastOccurrence,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.size()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequeSize,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.toArray()[Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arraydequeToArray,
+		}
+
+	MethodSignatures["java/util/ArrayDeque.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    arraydequeToString,
+			NeedsContext: true,
+		}
+
+	// the iterators
+
+	for _, className := range []string{classNameArrayDequeIterator, classNameArrayDequeDescendingIterator} {
+		MethodSignatures[className+".hasNext()Z"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  arraydequeIteratorHasNext,
+			}
+
+		MethodSignatures[className+".next()Ljava/lang/Object;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  arraydequeIteratorNext,
+			}
+
+		MethodSignatures[className+".remove()V"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  arraydequeIteratorRemove,
+			}
+	}
+}
+
+// the capacity of a new deque, which is the least capacity of a deque
+const dequeMinCapacity = 16
+
+// dequeState is the state of an ArrayDeque: its elements are buf[head], buf[head+1], ...,
+// wrapping around at the end of buf, for count elements
+type dequeState struct {
+	buf      []*object.Object
+	head     int
+	count    int
+	modCount int // the number of changes, which iterators check
+}
+
+// the state of an iterator: pos is the index, from the head, of the element it returns
+// next. The descending iterators go from the last element to the first.
+type dequeIterator struct {
+	deque       *dequeState
+	descending  bool
+	pos         int
+	last        int // the index of the element last returned, or -1 if there is none to remove
+	expectedMod int
+}
+
+func newDequeState() *dequeState {
+	return &dequeState{buf: make([]*object.Object, dequeMinCapacity)}
+}
+
+// the instantiation hook for ArrayDeque: a new object holding an empty deque
+func newArrayDequeObject(className string) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&className)
+	obj.FieldTable[fieldNameDeque] = object.Field{Ftype: types.DequeState, Fvalue: newDequeState()}
+	return obj
+}
+
+// returns the element at the index, counting from the head
+func (d *dequeState) at(index int) *object.Object {
+	return d.buf[(d.head+index)%len(d.buf)]
+}
+
+// doubles the capacity if the buffer is full, unwrapping the elements to its start
+func (d *dequeState) grow() {
+	if d.count < len(d.buf) {
+		return
+	}
+	buf := make([]*object.Object, 2*len(d.buf))
+	for i := 0; i < d.count; i++ {
+		buf[i] = d.at(i)
+	}
+	d.buf, d.head = buf, 0
+}
+
+func (d *dequeState) addFirst(elem *object.Object) {
+	d.grow()
+	d.head = (d.head - 1 + len(d.buf)) % len(d.buf)
+	d.buf[d.head] = elem
+	d.count++
+	d.modCount++
+}
+
+func (d *dequeState) addLast(elem *object.Object) {
+	d.grow()
+	d.buf[(d.head+d.count)%len(d.buf)] = elem
+	d.count++
+	d.modCount++
+}
+
+// removes and returns the first element, or nil if the deque is empty
+func (d *dequeState) removeFirst() *object.Object {
+	if d.count == 0 {
+		return nil
+	}
+	elem := d.buf[d.head]
+	d.buf[d.head] = nil
+	d.head = (d.head + 1) % len(d.buf)
+	d.count--
+	d.modCount++
+	return elem
+}
+
+// removes and returns the last element, or nil if the deque is empty
+func (d *dequeState) removeLast() *object.Object {
+	if d.count == 0 {
+		return nil
+	}
+	index := (d.head + d.count - 1) % len(d.buf)
+	elem := d.buf[index]
+	d.buf[index] = nil
+	d.count--
+	d.modCount++
+	return elem
+}
+
+// removes the element at the index, counting from the head, moving the later elements down
+func (d *dequeState) removeAt(index int) {
+	for i := index; i < d.count-1; i++ {
+		d.buf[(d.head+i)%len(d.buf)] = d.at(i + 1)
+	}
+	d.buf[(d.head+d.count-1)%len(d.buf)] = nil
+	d.count--
+	d.modCount++
+}
+
+// returns the elements in order, from the first to the last
+func (d *dequeState) elements() []*object.Object {
+	elems := make([]*object.Object, d.count)
+	for i := range elems {
+		elems[i] = d.at(i)
+	}
+	return elems
+}
+
+// returns the deque of the ArrayDeque in params[index]
+func getDeque(funcName string, params []interface{}, index int) (*dequeState, *GErrBlk) {
+	obj, ok := params[index].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return nil, getGErrBlk(excNames.NullPointerException, funcName+": the object is null")
+	}
+	deque, ok := obj.FieldTable[fieldNameDeque].Fvalue.(*dequeState)
+	if !ok {
+		errMsg := funcName + ": " + *stringPool.GetStringPointer(obj.KlassName) + " is not an ArrayDeque"
+		return nil, getGErrBlk(excNames.IllegalStateException, errMsg)
+	}
+	return deque, nil
+}
+
+// returns the deque of the ArrayDeque in params[0] and the element in params[1], which
+// must not be null
+func getDequeAndElement(funcName string, params []interface{}) (*dequeState, *object.Object, *GErrBlk) {
+	deque, gErr := getDeque(funcName, params, 0)
+	if gErr != nil {
+		return nil, nil, gErr
+	}
+	elem, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(elem) {
+		return nil, nil, getGErrBlk(excNames.NullPointerException, funcName+": the element is null")
+	}
+	return deque, elem, nil
+}
+
+// "java/util/ArrayDeque.<init>()V" and "java/util/ArrayDeque.<init>(I)V" -- the deque grows
+// as needed, so the capacity doesn't matter here
+func arraydequeInit(params []interface{}) interface{} {
+	obj, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return getGErrBlk(excNames.NullPointerException, "arraydequeInit: the object is null")
+	}
+	obj.FieldTable[fieldNameDeque] = object.Field{Ftype: types.DequeState, Fvalue: newDequeState()}
+	return nil
+}
+
+// "java/util/ArrayDeque.<init>(Ljava/util/Collection;)V"
+func arraydequeInitFromCollection(params []interface{}) interface{} {
+	if ret := arraydequeInit(params[1:]); ret != nil {
+		return ret
+	}
+	if ret := arraydequeAddAll(params); ret != types.JavaBoolTrue && ret != types.JavaBoolFalse {
+		return ret
+	}
+	return nil
+}
+
+// "java/util/ArrayDeque.addAll(Ljava/util/Collection;)Z" -- the elements are added at the
+// end, in the order of the collection
+func arraydequeAddAll(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	deque, gErr := getDeque("arraydequeAddAll", params, 1)
+	if gErr != nil {
+		return gErr
+	}
+	source, ok := params[2].(*object.Object)
+	if !ok || object.IsNull(source) {
+		return getGErrBlk(excNames.NullPointerException, "arraydequeAddAll: the collection is null")
+	}
+	elems, gErr := collectionElements(fs, source)
+	if gErr != nil {
+		return gErr
+	}
+	for _, elem := range elems {
+		if object.IsNull(elem) {
+			return getGErrBlk(excNames.NullPointerException, "arraydequeAddAll: the collection holds a null")
+		}
+		deque.addLast(elem)
+	}
+	return types.ConvertGoBoolToJavaBool(len(elems) > 0)
+}
+
+// "java/util/ArrayDeque.addFirst(Ljava/lang/Object;)V" and push(Object)
+func arraydequeAddFirst(params []interface{}) interface{} {
+	deque, elem, gErr := getDequeAndElement("arraydequeAddFirst", params)
+	if gErr != nil {
+		return gErr
+	}
+	deque.addFirst(elem)
+	return nil
+}
+
+// "java/util/ArrayDeque.addLast(Ljava/lang/Object;)V"
+func arraydequeAddLast(params []interface{}) interface{} {
+	deque, elem, gErr := getDequeAndElement("arraydequeAddLast", params)
+	if gErr != nil {
+		return gErr
+	}
+	deque.addLast(elem)
+	return nil
+}
+
+// "java/util/ArrayDeque.offerFirst(Ljava/lang/Object;)Z"
+func arraydequeOfferFirst(params []interface{}) interface{} {
+	if ret := arraydequeAddFirst(params); ret != nil {
+		return ret
+	}
+	return types.JavaBoolTrue
+}
+
+// "java/util/ArrayDeque.offerLast(Ljava/lang/Object;)Z", add(Object), and offer(Object)
+func arraydequeOfferLast(params []interface{}) interface{} {
+	if ret := arraydequeAddLast(params); ret != nil {
+		return ret
+	}
+	return types.JavaBoolTrue
+}
+
+// "java/util/ArrayDeque.clear()V"
+func arraydequeClear(params []interface{}) interface{} {
+	deque, gErr := getDeque("arraydequeClear", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	clear(deque.buf)
+	deque.head, deque.count = 0, 0
+	deque.modCount++
+	return nil
+}
+
+// "java/util/ArrayDeque.clone()Ljava/util/ArrayDeque;" -- the elements themselves aren't cloned
+func arraydequeClone(params []interface{}) interface{} {
+	deque, gErr := getDeque("arraydequeClone", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	obj := newArrayDequeObject(*stringPool.GetStringPointer(params[0].(*object.Object).KlassName))
+	cp := obj.FieldTable[fieldNameDeque].Fvalue.(*dequeState)
+	for _, elem := range deque.elements() {
+		cp.addLast(elem)
+	}
+	return obj
+}
+
+// returns the index, from the head, of the first (or, if last is true, the last) element
+// equal to the one in params[2], or -1 if there is none
+func arraydequeIndexOf(funcName string, params []interface{}, last bool) (*dequeState, int, *GErrBlk) {
+	fs := params[0].(*list.List)
+	deque, gErr := getDeque(funcName, params, 1)
+	if gErr != nil {
+		return nil, -1, gErr
+	}
+	target, ok := params[2].(*object.Object)
+	if !ok || object.IsNull(target) {
+		return deque, -1, nil
+	}
+	for i := 0; i < deque.count; i++ {
+		index := i
+		if last {
+			index = deque.count - 1 - i
+		}
+		equal, gErr := javaObjectsEqual(fs, target, deque.at(index))
+		if gErr != nil {
+			return nil, -1, gErr
+		}
+		if equal {
+			return deque, index, nil
+		}
+	}
+	return deque, -1, nil
+}
+
+// "java/util/ArrayDeque.contains(Ljava/lang/Object;)Z"
+func arraydequeContains(params []interface{}) interface{} {
+	_, index, gErr := arraydequeIndexOf("arraydequeContains", params, false)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(index >= 0)
+}
+
+// "java/util/ArrayDeque.removeFirstOccurrence(Ljava/lang/Object;)Z" and remove(Object)
+func arraydequeRemoveFirstOccurrence(params []interface{}) interface{} {
+	deque, index, gErr := arraydequeIndexOf("arraydequeRemoveFirstOccurrence", params, false)
+	if gErr != nil {
+		return gErr
+	}
+	if index < 0 {
+		return types.JavaBoolFalse
+	}
+	deque.removeAt(index)
+	return types.JavaBoolTrue
+}
+
+// "java/util/ArrayDeque.removeLastOccurrence(Ljava/lang/Object;)Z"
+func arraydequeRemoveLastOccurrence(params []interface{}) interface{} {
+	deque, index, gErr := arraydequeIndexOf("arraydequeRemoveLastOccurrence", params, true)
+	if gErr != nil {
+		return gErr
+	}
+	if index < 0 {
+		return types.JavaBoolFalse
+	}
+	deque.removeAt(index)
+	return types.JavaBoolTrue
+}
+
+// returns the first or the last element, without removing it. If the deque is empty, this
+// throws a NoSuchElementException if throwIfEmpty is true and otherwise returns null.
+func arraydequeEnd(funcName string, params []interface{}, last, throwIfEmpty bool) interface{} {
+	deque, gErr := getDeque(funcName, params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	if deque.count == 0 {
+		if throwIfEmpty {
+			return getGErrBlk(excNames.NoSuchElementException, funcName+": the deque is empty")
+		}
+		return object.Null
+	}
+	if last {
+		return deque.at(deque.count - 1)
+	}
+	return deque.at(0)
+}
+
+// "java/util/ArrayDeque.getFirst()Ljava/lang/Object;" and element()
+func arraydequeGetFirst(params []interface{}) interface{} {
+	return arraydequeEnd("arraydequeGetFirst", params, false, true)
+}
+
+// "java/util/ArrayDeque.getLast()Ljava/lang/Object;"
+func arraydequeGetLast(params []interface{}) interface{} {
+	return arraydequeEnd("arraydequeGetLast", params, true, true)
+}
+
+// "java/util/ArrayDeque.peekFirst()Ljava/lang/Object;" and peek()
+func arraydequePeekFirst(params []interface{}) interface{} {
+	return arraydequeEnd("arraydequePeekFirst", params, false, false)
+}
+
+// "java/util/ArrayDeque.peekLast()Ljava/lang/Object;"
+func arraydequePeekLast(params []interface{}) interface{} {
+	return arraydequeEnd("arraydequePeekLast", params, true, false)
+}
+
+// removes and returns the first or the last element. If the deque is empty, this throws a
+// NoSuchElementException if throwIfEmpty is true and otherwise returns null.
+func arraydequeTake(funcName string, params []interface{}, last, throwIfEmpty bool) interface{} {
+	deque, gErr := getDeque(funcName, params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	var elem *object.Object
+	if last {
+		elem = deque.removeLast()
+	} else {
+		elem = deque.removeFirst()
+	}
+	if elem == nil {
+		if throwIfEmpty {
+			return getGErrBlk(excNames.NoSuchElementException, funcName+": the deque is empty")
+		}
+		return object.Null
+	}
+	return elem
+}
+
+// "java/util/ArrayDeque.pollFirst()Ljava/lang/Object;" and poll()
+func arraydequePollFirst(params []interface{}) interface{} {
+	return arraydequeTake("arraydequePollFirst", params, false, false)
+}
+
+// "java/util/ArrayDeque.pollLast()Ljava/lang/Object;"
+func arraydequePollLast(params []interface{}) interface{} {
+	return arraydequeTake("arraydequePollLast", params, true, false)
+}
+
+// "java/util/ArrayDeque.removeFirst()Ljava/lang/Object;", remove(), and pop()
+func arraydequeRemoveFirst(params []interface{}) interface{} {
+	return arraydequeTake("arraydequeRemoveFirst", params, false, true)
+}
+
+// "java/util/ArrayDeque.removeLast()Ljava/lang/Object;"
+func arraydequeRemoveLast(params []interface{}) interface{} {
+	return arraydequeTake("arraydequeRemoveLast", params, true, true)
+}
+
+// "java/util/ArrayDeque.size()I"
+func arraydequeSize(params []interface{}) interface{} {
+	deque, gErr := getDeque("arraydequeSize", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(deque.count)
+}
+
+// "java/util/ArrayDeque.isEmpty()Z"
+func arraydequeIsEmpty(params []interface{}) interface{} {
+	deque, gErr := getDeque("arraydequeIsEmpty", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(deque.count == 0)
+}
+
+// "java/util/ArrayDeque.toArray()[Ljava/lang/Object;" -- the elements from first to last
+func arraydequeToArray(params []interface{}) interface{} {
+	deque, gErr := getDeque("arraydequeToArray", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	arr := object.Make1DimRefArray("java/lang/Object;", int64(deque.count))
+	copy(arr.FieldTable["value"].Fvalue.([]*object.Object), deque.elements())
+	return arr
+}
+
+// "java/util/ArrayDeque.toString()Ljava/lang/String;" -- [first, ..., last]
+func arraydequeToString(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	deque, gErr := getDeque("arraydequeToString", params, 1)
+	if gErr != nil {
+		return gErr
+	}
+	strs := make([]string, 0, deque.count)
+	for _, elem := range deque.elements() {
+		str, gErr := javaObjectString(fs, elem)
+		if gErr != nil {
+			return gErr
+		}
+		strs = append(strs, str)
+	}
+	return object.StringObjectFromGoString("[" + strings.Join(strs, ", ") + "]")
+}
+
+// makes an iterator over the deque in params[0]
+func newDequeIteratorObject(funcName string, params []interface{}, descending bool) interface{} {
+	deque, gErr := getDeque(funcName, params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	iterator := &dequeIterator{deque: deque, descending: descending, last: -1, expectedMod: deque.modCount}
+	className := classNameArrayDequeIterator
+	if descending {
+		iterator.pos = deque.count - 1
+		className = classNameArrayDequeDescendingIterator
+	}
+	obj := object.MakeEmptyObjectWithClassName(&className)
+	obj.FieldTable[fieldNameDeque] = object.Field{Ftype: types.DequeState, Fvalue: iterator}
+	return obj
+}
+
+// "java/util/ArrayDeque.iterator()Ljava/util/Iterator;" -- from the first element to the last
+func arraydequeIterator(params []interface{}) interface{} {
+	return newDequeIteratorObject("arraydequeIterator", params, false)
+}
+
+// "java/util/ArrayDeque.descendingIterator()Ljava/util/Iterator;" -- from the last element to the first
+func arraydequeDescendingIterator(params []interface{}) interface{} {
+	return newDequeIteratorObject("arraydequeDescendingIterator", params, true)
+}
+
+// returns the state of the iterator in params[0], which must not have been overtaken by
+// changes to the deque
+func getDequeIterator(funcName string, params []interface{}) (*dequeIterator, *GErrBlk) {
+	obj, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return nil, getGErrBlk(excNames.NullPointerException, funcName+": the iterator is null")
+	}
+	iterator, ok := obj.FieldTable[fieldNameDeque].Fvalue.(*dequeIterator)
+	if !ok {
+		return nil, getGErrBlk(excNames.IllegalStateException, funcName+": not an ArrayDeque iterator")
+	}
+	if iterator.expectedMod != iterator.deque.modCount {
+		return nil, getGErrBlk(excNames.ConcurrentModificationException, funcName+": the deque was changed")
+	}
+	return iterator, nil
+}
+
+// the hasNext() of the iterators of ArrayDeque
+func arraydequeIteratorHasNext(params []interface{}) interface{} {
+	obj, _ := params[0].(*object.Object)
+	if iterator, ok := obj.FieldTable[fieldNameDeque].Fvalue.(*dequeIterator); ok {
+		if iterator.descending {
+			return types.ConvertGoBoolToJavaBool(iterator.pos >= 0)
+		}
+		return types.ConvertGoBoolToJavaBool(iterator.pos < iterator.deque.count)
+	}
+	return types.JavaBoolFalse
+}
+
+// the next() of the iterators of ArrayDeque
+func arraydequeIteratorNext(params []interface{}) interface{} {
+	iterator, gErr := getDequeIterator("arraydequeIteratorNext", params)
+	if gErr != nil {
+		return gErr
+	}
+	if iterator.pos < 0 || iterator.pos >= iterator.deque.count {
+		return getGErrBlk(excNames.NoSuchElementException, "arraydequeIteratorNext: no more elements")
+	}
+	elem := iterator.deque.at(iterator.pos)
+	iterator.last = iterator.pos
+	if iterator.descending {
+		iterator.pos--
+	} else {
+		iterator.pos++
+	}
+	return elem
+}
+
+// the remove() of the iterators of ArrayDeque -- removes the element last returned
+func arraydequeIteratorRemove(params []interface{}) interface{} {
+	iterator, gErr := getDequeIterator("arraydequeIteratorRemove", params)
+	if gErr != nil {
+		return gErr
+	}
+	if iterator.last < 0 {
+		return getGErrBlk(excNames.IllegalStateException, "arraydequeIteratorRemove: next() has not been called")
+	}
+	iterator.deque.removeAt(iterator.last)
+	if !iterator.descending { // the elements after the one removed have moved down
+		iterator.pos--
+	}
+	iterator.expectedMod = iterator.deque.modCount
+	iterator.last = -1
+	return nil
+}
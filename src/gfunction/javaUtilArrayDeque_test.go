@@ -0,0 +1,122 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"testing"
+)
+
+func TestArrayDequeStackAndQueue(t *testing.T) {
+	globals.InitGlobals("test")
+	deque := newArrayDequeObject(classNameArrayDeque)
+
+	// as a stack
+	for _, value := range []int64{1, 2, 3} {
+		arraydequeAddFirst([]interface{}{deque, treeInt(value)})
+	}
+	checkTreeKey(t, "peek()", arraydequePeekFirst([]interface{}{deque}), 3)
+	checkTreeKey(t, "pop()", arraydequeRemoveFirst([]interface{}{deque}), 3)
+	checkTreeString(t, "toString()", treeCall(arraydequeToString, deque), "[2, 1]")
+
+	// as a queue
+	arraydequeOfferLast([]interface{}{deque, treeInt(4)})
+	checkTreeKey(t, "poll()", arraydequePollFirst([]interface{}{deque}), 2)
+	checkTreeKey(t, "pollLast()", arraydequePollLast([]interface{}{deque}), 4)
+	checkTreeKey(t, "getLast()", arraydequeGetLast([]interface{}{deque}), 1)
+	arraydequeRemoveFirst([]interface{}{deque})
+
+	if ret := arraydequeIsEmpty([]interface{}{deque}); ret != types.JavaBoolTrue {
+		t.Errorf("isEmpty(): expected true, got %v", ret)
+	}
+	if ret := arraydequePollFirst([]interface{}{deque}); !object.IsNull(ret) {
+		t.Errorf("poll() of an empty deque: expected null, got %v", ret)
+	}
+	checkTreeError(t, "pop() of an empty deque", arraydequeRemoveFirst([]interface{}{deque}),
+		excNames.NoSuchElementException)
+	checkTreeError(t, "element() of an empty deque", arraydequeGetFirst([]interface{}{deque}),
+		excNames.NoSuchElementException)
+	checkTreeError(t, "push(null)", arraydequeAddFirst([]interface{}{deque, object.Null}),
+		excNames.NullPointerException)
+}
+
+func TestArrayDequeGrowthAndSearch(t *testing.T) {
+	globals.InitGlobals("test")
+	deque := newArrayDequeObject(classNameArrayDeque)
+
+	// wrap the elements around the end of the buffer before it grows
+	for i := int64(0); i < 10; i++ {
+		arraydequeAddLast([]interface{}{deque, treeInt(i)})
+	}
+	for i := int64(0); i < 8; i++ {
+		arraydequeRemoveFirst([]interface{}{deque})
+	}
+	for i := int64(10); i < 40; i++ {
+		arraydequeAddLast([]interface{}{deque, treeInt(i % 20)})
+	}
+	arraydequeAddFirst([]interface{}{deque, treeInt(7)})
+	if ret := arraydequeSize([]interface{}{deque}); ret != int64(33) {
+		t.Fatalf("size(): expected 33, got %v", ret)
+	}
+	checkTreeKey(t, "getFirst()", arraydequeGetFirst([]interface{}{deque}), 7)
+	checkTreeKey(t, "getLast()", arraydequeGetLast([]interface{}{deque}), 19)
+
+	if ret := treeCall(arraydequeContains, deque, treeInt(15)); ret != types.JavaBoolTrue {
+		t.Errorf("contains(15): expected true, got %v", ret)
+	}
+	if ret := treeCall(arraydequeContains, deque, treeInt(25)); ret != types.JavaBoolFalse {
+		t.Errorf("contains(25): expected false, got %v", ret)
+	}
+	treeCall(arraydequeRemoveFirstOccurrence, deque, treeInt(12))
+	treeCall(arraydequeRemoveLastOccurrence, deque, treeInt(13))
+	arr := arraydequeToArray([]interface{}{deque}).(*object.Object)
+	elems := arr.FieldTable["value"].Fvalue.([]*object.Object)
+	if len(elems) != 31 {
+		t.Fatalf("toArray(): expected 31 elements, got %d", len(elems))
+	}
+	checkTreeKey(t, "toArray()[4]", elems[4], 11)
+	checkTreeKey(t, "toArray()[5]", elems[5], 13)
+	checkTreeKey(t, "toArray()[24]", elems[24], 12)
+	checkTreeKey(t, "toArray()[25]", elems[25], 14)
+
+	clone := arraydequeClone([]interface{}{deque}).(*object.Object)
+	arraydequeClear([]interface{}{deque})
+	if ret := arraydequeSize([]interface{}{clone}); ret != int64(31) {
+		t.Errorf("size() of the clone: expected 31, got %v", ret)
+	}
+}
+
+func TestArrayDequeIterators(t *testing.T) {
+	globals.InitGlobals("test")
+	deque := newArrayDequeObject(classNameArrayDeque)
+	for _, value := range []int64{1, 2, 3, 4} {
+		arraydequeAddLast([]interface{}{deque, treeInt(value)})
+	}
+
+	it := arraydequeIterator([]interface{}{deque}).(*object.Object)
+	var got []int64
+	for arraydequeIteratorHasNext([]interface{}{it}) == types.JavaBoolTrue {
+		value := arraydequeIteratorNext([]interface{}{it}).(*object.Object).FieldTable["value"].Fvalue.(int64)
+		if value == 2 {
+			arraydequeIteratorRemove([]interface{}{it})
+		}
+		got = append(got, value)
+	}
+	if len(got) != 4 || got[3] != 4 {
+		t.Errorf("iterator(): unexpected elements %v", got)
+	}
+	checkTreeString(t, "the deque after a removal by the iterator", treeCall(arraydequeToString, deque), "[1, 3, 4]")
+
+	it = arraydequeDescendingIterator([]interface{}{deque}).(*object.Object)
+	checkTreeKey(t, "descendingIterator().next()", arraydequeIteratorNext([]interface{}{it}), 4)
+	arraydequeAddFirst([]interface{}{deque, treeInt(0)})
+	checkTreeError(t, "next() after a change to the deque", arraydequeIteratorNext([]interface{}{it}),
+		excNames.ConcurrentModificationException)
+}
@@ -7,8 +7,13 @@
 package gfunction
 
 import (
+	"container/list"
+	"fmt"
+	"jacobin/src/classloader"
 	"jacobin/src/excNames"
 	"jacobin/src/object"
+	"jacobin/src/types"
+	"strings"
 )
 
 // A partial implementation of the java/util/Arrays class.
@@ -23,11 +28,26 @@ func Load_Util_Arrays() {
 	MethodSignatures["java/util/Arrays.copyOf([Ljava/lang/Object;ILjava/lang/Class;)[Ljava/lang/Object;"] =
 		GMeth{
 			ParamSlots: 3,
-			GFunction:  trapFunction,
+			GFunction:  copyOfObjectPointersAsType,
+		}
+
+	MethodSignatures["java/util/Arrays.sort([Ljava/lang/Object;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    sortObjectArray,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Arrays.sort([Ljava/lang/Object;Ljava/util/Comparator;)V"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    sortObjectArray,
+			NeedsContext: true,
 		}
 }
 
 // Copy the specified array of pointers, truncating or padding with nulls so the copy has the specified length.
+// The copy is of the same class as the original.
 func copyOfObjectPointers(params []interface{}) interface{} {
 	if len(params) < 2 {
 		return getGErrBlk(excNames.IllegalArgumentException, "copyOfObjectPointers: too few arguments")
@@ -54,7 +74,7 @@ func copyOfObjectPointers(params []interface{}) interface{} {
 	oldLen := len(rawArrayOld)
 
 	// Create a new array of the desired length.
-	newArrayObj := object.Make1DimRefArray("java/lang/Object;", int64(newLen))
+	newArrayObj := object.MakeMultiDimArray(arr.Ftype, []int64{int64(newLen)})
 	rawArrayNew := newArrayObj.FieldTable["value"].Fvalue.([]*object.Object)
 
 	// Copy the elements from the old array to the new array.
@@ -70,3 +90,54 @@ func copyOfObjectPointers(params []interface{}) interface{} {
 
 	return newArrayObj
 }
+
+// "java/util/Arrays.copyOf([Ljava/lang/Object;ILjava/lang/Class;)[Ljava/lang/Object;" -- like
+// copyOf() above, but the copy is of the array class passed. An element that the copy
+// can't hold causes an ArrayStoreException.
+func copyOfObjectPointersAsType(params []interface{}) interface{} {
+	if object.IsNull(params[0]) {
+		return getGErrBlk(excNames.NullPointerException, "copyOfObjectPointersAsType: null array argument")
+	}
+	if object.IsNull(params[2]) {
+		return getGErrBlk(excNames.NullPointerException, "copyOfObjectPointersAsType: null class argument")
+	}
+	original := params[0].(*object.Object)
+	newLen := params[1].(int64)
+	if newLen < 0 {
+		return getGErrBlk(excNames.NegativeArraySizeException, "copyOfObjectPointersAsType: negative array length")
+	}
+	newType := classloader.ClassNameFromClassObject(params[2].(*object.Object))
+	if !strings.HasPrefix(newType, types.RefArray) && !strings.HasPrefix(newType, types.MultiArray) {
+		errMsg := fmt.Sprintf("copyOfObjectPointersAsType: %s is not an array class of references", newType)
+		return getGErrBlk(excNames.ArrayStoreException, errMsg)
+	}
+
+	copied := object.MakeMultiDimArray(newType, []int64{newLen})
+	length := min(newLen, int64(len(original.FieldTable["value"].Fvalue.([]*object.Object))))
+	if gErr := ArrayCopy(original, 0, copied, 0, length); gErr != nil {
+		return gErr
+	}
+	return copied
+}
+
+// Sort the array of objects in place, by their natural ordering or with the comparator if
+// one is passed. The sort is stable. If a comparison throws, the array is left unchanged.
+func sortObjectArray(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	arrObj, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(arrObj) {
+		return getGErrBlk(excNames.NullPointerException, "sortObjectArray: null array argument")
+	}
+	var comparator *object.Object
+	if len(params) > 2 {
+		comparator, _ = params[2].(*object.Object)
+	}
+
+	elems, _ := arrObj.FieldTable["value"].Fvalue.([]*object.Object)
+	sorted, gErr := sortJavaObjects(fs, elems, comparator)
+	if gErr != nil {
+		return gErr
+	}
+	copy(elems, sorted)
+	return nil
+}
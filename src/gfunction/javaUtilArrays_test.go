@@ -7,10 +7,13 @@
 package gfunction
 
 import (
+	"container/list"
+	"jacobin/src/classloader"
 	"jacobin/src/excNames"
 	"jacobin/src/globals"
 	"jacobin/src/object"
 	"jacobin/src/stringPool"
+	"jacobin/src/types"
 	"testing"
 )
 
@@ -62,3 +65,64 @@ func TestCopyOfObjectPointers_CopyArray(t *testing.T) {
 		t.Errorf("Array elements not copied correctly")
 	}
 }
+
+func TestCopyOfObjectPointersAsType(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	oldArray := object.Make1DimRefArray("java/lang/Object;", 3)
+	rawOldArray := oldArray.FieldTable["value"].Fvalue.([]*object.Object)
+	rawOldArray[0] = object.StringObjectFromGoString("foo")
+	rawOldArray[1] = object.StringObjectFromGoString("bar")
+
+	name := "[Ljava/lang/String;"
+	newType := classloader.GetClassObject(stringPool.GetStringIndex(&name))
+	result := copyOfObjectPointersAsType([]interface{}{oldArray, int64(2), newType}).(*object.Object)
+	if result.FieldTable["value"].Ftype != name {
+		t.Errorf("Expected a copy of type %s, got %s", name, result.FieldTable["value"].Ftype)
+	}
+	newArray := result.FieldTable["value"].Fvalue.([]*object.Object)
+	if len(newArray) != 2 || object.GoStringFromStringObject(newArray[1]) != "bar" {
+		t.Errorf("Array elements not copied correctly")
+	}
+
+	// an Object[] holding an int[] can't be copied to a String[]
+	rawOldArray[2] = object.Make1DimArray(object.INT, 1)
+	ret := copyOfObjectPointersAsType([]interface{}{oldArray, int64(3), newType})
+	if gErr, ok := ret.(*GErrBlk); !ok || gErr.ExceptionType != excNames.ArrayStoreException {
+		t.Errorf("Expected ArrayStoreException, got %v", ret)
+	}
+}
+
+func TestSortObjectArray(t *testing.T) {
+	fakeSortUpcalls(t)
+
+	arr := object.Make1DimRefArray("Ljava/lang/Integer;", 4)
+	elems := arr.FieldTable["value"].Fvalue.([]*object.Object)
+	for i, value := range []int64{5, -2, 9, 0} {
+		elems[i] = Populator("java/lang/Integer", types.Int, value)
+	}
+
+	if ret := sortObjectArray([]interface{}{list.New(), arr}); ret != nil {
+		t.Fatalf("Arrays.sort(): unexpected result %v", ret)
+	}
+	var got []int64
+	for _, elem := range elems {
+		got = append(got, elem.FieldTable["value"].Fvalue.(int64))
+	}
+	if got[0] != -2 || got[1] != 0 || got[2] != 5 || got[3] != 9 {
+		t.Errorf("Arrays.sort(): got %v", got)
+	}
+
+	className := "pkg/Reverse"
+	comparator := object.MakeEmptyObjectWithClassName(&className)
+	sortObjectArray([]interface{}{list.New(), arr, comparator})
+	if first := elems[0].FieldTable["value"].Fvalue.(int64); first != 9 {
+		t.Errorf("Arrays.sort() with a reversing comparator: first element is %d", first)
+	}
+
+	ret := sortObjectArray([]interface{}{list.New(), object.Null})
+	if gErr, ok := ret.(*GErrBlk); !ok || gErr.ExceptionType != excNames.NullPointerException {
+		t.Errorf("Arrays.sort(null): expected NullPointerException, got %v", ret)
+	}
+}
@@ -66,7 +66,7 @@ func Load_Util_Base64() {
 	MethodSignatures["java/util/Base64.getMimeEncoder(I[B)Ljava/util/Base64$Encoder;"] =
 		GMeth{
 			ParamSlots: 2,
-			GFunction:  trapFunction,
+			GFunction:  base64GetMimeEncoderCustom,
 		}
 
 	MethodSignatures["java/util/Base64.getUrlDecoder()Ljava/util/Base64$Decoder;"] =
@@ -150,19 +150,26 @@ func Load_Util_Base64() {
 }
 
 // Classes:
-var classNameBase64Decoder = "java/util/Base64/Decoder"
-var classNameBase64Encoder = "java/util/Base64/Encoder"
+var classNameBase64Decoder = "java/util/Base64$Decoder"
+var classNameBase64Encoder = "java/util/Base64$Encoder"
 
 // Schemes:
 var base64SchemeStd = int64(1)
 var base64SchemeStdRaw = int64(101) // no padding
 var base64SchemeUrl = int64(2)
-var base64SchemeUrlRaw = int64(102) // no padding
-var base64SchemeMime = int64(3)     // essentially, the same as base64SchemeStd
+var base64SchemeUrlRaw = int64(102)  // no padding
+var base64SchemeMime = int64(3)      // the standard alphabet, in lines separated by CRLF
+var base64SchemeMimeRaw = int64(103) // no padding
+
+// MIME line length and line separator, as in RFC 2045:
+var base64MimeLineMax = int64(76)
+var base64MimeLineSeparator = []byte{'\r', '\n'}
 
 // Fields:
 var fieldNameScheme = "scheme"
 var fieldNameValue = "value"
+var fieldNameLineMax = "lineMax"             // MIME encoders only
+var fieldNameLineSeparator = "lineSeparator" // MIME encoders only
 
 // GetDecoder returns a standard Base64 decoder instance.
 func base64GetStdDecoder([]interface{}) interface{} {
@@ -181,7 +188,44 @@ func base64GetMimeDecoder([]interface{}) interface{} {
 
 // GetEncoder returns a Mime Base64 encoder instance.
 func base64GetMimeEncoder([]interface{}) interface{} {
-	return object.MakeOneFieldObject(classNameBase64Encoder, fieldNameScheme, types.Int, base64SchemeMime)
+	return makeBase64MimeEncoder(base64SchemeMime, base64MimeLineMax, base64MimeLineSeparator)
+}
+
+// getMimeEncoder(int lineLength, byte[] lineSeparator) returns a Mime Base64 encoder instance
+// that uses the line length, rounded down to a multiple of 4, and the line separator given.
+// If the rounded line length is not positive, the output is not separated into lines.
+func base64GetMimeEncoderCustom(params []interface{}) interface{} {
+	lineLength, ok := params[0].(int64)
+	if !ok {
+		errMsg := fmt.Sprintf("base64GetMimeEncoderCustom: Line length should be an int, observed: %T", params[0])
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "base64GetMimeEncoderCustom: Line separator is null")
+	}
+	separator, gerr := getByteArrayRange("base64GetMimeEncoderCustom", params, 1)
+	if gerr != nil {
+		return gerr
+	}
+
+	// The line separator must not contain Base64 alphabet characters, which would be decoded.
+	for _, b := range separator {
+		if isBase64AlphabetByte(b) || b == '=' {
+			errMsg := fmt.Sprintf("base64GetMimeEncoderCustom: Illegal base64 line separator character 0x%x", b)
+			return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+		}
+	}
+
+	return makeBase64MimeEncoder(base64SchemeMime, lineLength&^3, separator)
+}
+
+// makeBase64MimeEncoder returns a MIME encoder instance with the given scheme, line length, and line separator.
+func makeBase64MimeEncoder(scheme int64, lineMax int64, separator []byte) *object.Object {
+	obj := object.MakeOneFieldObject(classNameBase64Encoder, fieldNameScheme, types.Int, scheme)
+	obj.FieldTable[fieldNameLineMax] = object.Field{Ftype: types.Int, Fvalue: lineMax}
+	obj.FieldTable[fieldNameLineSeparator] = object.Field{
+		Ftype: types.ByteArray, Fvalue: object.JavaByteArrayFromGoByteArray(separator)}
+	return obj
 }
 
 // GetDecoder returns a URL Base64 decoder instance.
@@ -232,6 +276,12 @@ func base64WithoutPadding(params []interface{}) interface{} {
 		newScheme = base64SchemeStdRaw
 	case base64SchemeUrl:
 		newScheme = base64SchemeUrlRaw
+	case base64SchemeMime, base64SchemeMimeRaw:
+		// Keep the line length and line separator of this encoder.
+		newObj := object.MakeOneFieldObject(classNameBase64Encoder, fieldNameScheme, types.Int, base64SchemeMimeRaw)
+		newObj.FieldTable[fieldNameLineMax] = obj.FieldTable[fieldNameLineMax]
+		newObj.FieldTable[fieldNameLineSeparator] = obj.FieldTable[fieldNameLineSeparator]
+		return newObj
 	}
 
 	// Return the new Base 64 encoding object with the new scheme.
@@ -267,20 +317,15 @@ func base64EncodeBsrcBdst(params []interface{}) interface{} {
 		return getGErrBlk(excNames.VirtualMachineError, errMsg)
 	}
 
-	// Get field holding the destination array.
-	fld, ok := dstObject.FieldTable[fieldNameValue]
-	if !ok {
-		errMsg := fmt.Sprintf("base64EncodeBsrcBdst: Field missing in destination object: %s", fieldNameValue)
-		return getGErrBlk(excNames.VirtualMachineError, errMsg)
+	// The destination array must be large enough to hold all the output.
+	result := object.GoByteArrayFromJavaByteArray(jba.([]types.JavaByte))
+	if int64(len(result)) > byteArrayLength(dstObject) {
+		errMsg := "base64EncodeBsrcBdst: Output byte array is too small for encoding all input bytes"
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
 
-	// Put the encoded array in the destination object.
-	fld.Ftype = types.ByteArray
-	fld.Fvalue = jba.([]types.JavaByte)
-	dstObject.FieldTable[fieldNameValue] = fld
-
-	// Return array length to caller.
-	return int64(len(jba.([]types.JavaByte)))
+	// Write the output to the start of the destination array and return its length to caller.
+	return putByteArrayRange("base64EncodeBsrcBdst", []interface{}{dstObject}, 0, result)
 }
 
 // Base 64 encode all bytes from the specified byte array using the Base64 encoding scheme specified in params[0].
@@ -326,20 +371,15 @@ func base64DecodeBsrcBdst(params []interface{}) interface{} {
 		return getGErrBlk(excNames.VirtualMachineError, errMsg)
 	}
 
-	// Get field holding the destination array.
-	fld, ok := dstObject.FieldTable[fieldNameValue]
-	if !ok {
-		errMsg := fmt.Sprintf("base64DecodeBsrcBdst: Field missing in destination object: %s", fieldNameValue)
-		return getGErrBlk(excNames.VirtualMachineError, errMsg)
+	// The destination array must be large enough to hold all the output.
+	result := object.GoByteArrayFromJavaByteArray(jba.([]types.JavaByte))
+	if int64(len(result)) > byteArrayLength(dstObject) {
+		errMsg := "base64DecodeBsrcBdst: Output byte array is too small for decoding all input bytes"
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
 
-	// Put the encoded array in the destination object.
-	fld.Ftype = types.ByteArray
-	fld.Fvalue = jba.([]types.JavaByte)
-	dstObject.FieldTable[fieldNameValue] = fld
-
-	// Return array length to caller.
-	return int64(len(jba.([]types.JavaByte)))
+	// Write the output to the start of the destination array and return its length to caller.
+	return putByteArrayRange("base64DecodeBsrcBdst", []interface{}{dstObject}, 0, result)
 }
 
 /*
@@ -386,17 +426,11 @@ func _encodeBase64(params []interface{}) (interface{}, bool) {
 		return errMsg, false
 	}
 
-	// Get source JavaByte array converted to []byte.
-	srcObject, ok := params[1].(*object.Object)
-	if !ok {
-		return getGErrBlk(excNames.IllegalArgumentException, "_encodeBase64: source parameter is not an object"), false
-	}
-	srcJB, ok := srcObject.FieldTable[fieldNameValue].Fvalue.([]types.JavaByte)
-	if !ok {
-		errMsg := fmt.Sprintf("_encodeBase64: source parameter has no field %s or its the wrong type", fieldNameValue)
-		return getGErrBlk(excNames.IllegalArgumentException, errMsg), false
+	// Get source array (a byte array or a String) as []byte.
+	src, gerr := getByteArrayRange("_encodeBase64", params[:2], 1)
+	if gerr != nil {
+		return gerr, false
 	}
-	src := object.GoByteArrayFromJavaByteArray(srcJB)
 
 	// Base 64 encode, depending on scheme.
 	var dst []byte
@@ -417,15 +451,31 @@ func _encodeBase64(params []interface{}) (interface{}, bool) {
 		urlEnc := base64.URLEncoding.WithPadding(base64.NoPadding)
 		dst = make([]byte, urlEnc.EncodedLen(len(src)))
 		urlEnc.Encode(dst, src)
-	case base64SchemeMime:
+	case base64SchemeMime, base64SchemeMimeRaw:
+		mimeEnc := base64.StdEncoding
+		if scheme == base64SchemeMimeRaw {
+			mimeEnc = mimeEnc.WithPadding(base64.NoPadding)
+		}
+		encoded := make([]byte, mimeEnc.EncodedLen(len(src)))
+		mimeEnc.Encode(encoded, src)
+
+		// Separate the output into lines, with no separator after the last line.
+		lineMax, separator := base64MimeLineMax, base64MimeLineSeparator
+		if fld, ok := this.FieldTable[fieldNameLineMax]; ok {
+			lineMax = fld.Fvalue.(int64)
+			separator = object.GoByteArrayFromJavaByteArray(this.FieldTable[fieldNameLineSeparator].Fvalue.([]types.JavaByte))
+		}
+		if lineMax <= 0 {
+			dst = encoded
+			break
+		}
 		var encodedBuffer bytes.Buffer
-		mimeEncoder := base64.NewEncoder(base64.StdEncoding, &encodedBuffer)
-		_, err := mimeEncoder.Write(src)
-		if err != nil {
-			errMsg := fmt.Sprintf("_encodeBase64: mimeEncoder.Write failed, err: %v", err)
-			return getGErrBlk(excNames.IllegalArgumentException, errMsg), false
+		for len(encoded) > int(lineMax) {
+			encodedBuffer.Write(encoded[:lineMax])
+			encodedBuffer.Write(separator)
+			encoded = encoded[lineMax:]
 		}
-		_ = mimeEncoder.Close() // Ensure encoding is flushed
+		encodedBuffer.Write(encoded)
 		dst = encodedBuffer.Bytes()
 	default:
 		errMsg := fmt.Sprintf("_encodeBase64: Impossible %s field: %d", fieldNameScheme, scheme)
@@ -481,17 +531,11 @@ func _decodeBase64(params []interface{}) (interface{}, bool) {
 		return errMsg, false
 	}
 
-	// Get source JavaByte array converted to []byte.
-	srcObject, ok := params[1].(*object.Object)
-	if !ok {
-		return getGErrBlk(excNames.IllegalArgumentException, "_decodeBase64: source parameter is not an object"), false
-	}
-	srcJB, ok := srcObject.FieldTable[fieldNameValue].Fvalue.([]types.JavaByte)
-	if !ok {
-		errMsg := fmt.Sprintf("_decodeBase64: source parameter has no field %s or its the wrong type", fieldNameValue)
-		return getGErrBlk(excNames.IllegalArgumentException, errMsg), false
+	// Get source array (a byte array or a String) as []byte.
+	encoded, gerr := getByteArrayRange("_decodeBase64", params[:2], 1)
+	if gerr != nil {
+		return gerr, false
 	}
-	encoded := object.GoByteArrayFromJavaByteArray(srcJB)
 
 	// Base 64 encode, depending on scheme.
 	var decoded []byte
@@ -525,8 +569,19 @@ func _decodeBase64(params []interface{}) (interface{}, bool) {
 		}
 		decoded = decoded[:num]
 	case base64SchemeMime:
-		decoded = make([]byte, base64.StdEncoding.DecodedLen(len(encoded)))
-		num, err := base64.StdEncoding.Decode(decoded, encoded)
+		// Line separators and other characters outside the Base64 alphabet are ignored.
+		filtered := make([]byte, 0, len(encoded))
+		for _, b := range encoded {
+			if isBase64AlphabetByte(b) || b == '=' {
+				filtered = append(filtered, b)
+			}
+		}
+		decoder := base64.StdEncoding
+		if len(filtered)%4 != 0 {
+			decoder = base64.StdEncoding.WithPadding(base64.NoPadding)
+		}
+		decoded = make([]byte, decoder.DecodedLen(len(filtered)))
+		num, err := decoder.Decode(decoded, filtered)
 		if err != nil {
 			errMsg := fmt.Sprintf("_decodeBase64: Decoding/MIME error: %v", err)
 			return getGErrBlk(excNames.IllegalArgumentException, errMsg), false
@@ -541,3 +596,8 @@ func _decodeBase64(params []interface{}) (interface{}, bool) {
 	jbo := object.JavaByteArrayFromGoByteArray(decoded)
 	return jbo, true
 }
+
+// isBase64AlphabetByte reports whether b is in the standard Base64 alphabet.
+func isBase64AlphabetByte(b byte) bool {
+	return (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') || b == '+' || b == '/'
+}
@@ -127,8 +127,8 @@ func TestBase64_Std_BsrcBdst_Variants(t *testing.T) {
     input := []byte("hello") // aGVsbG8=
     srcObj := makeByteArrayObject(input)
 
-    // Destination objects must have a preexisting value field; initialize with empty
-    dstEnc := makeByteArrayObject([]byte{})
+    // Destination arrays must be large enough to hold the output
+    dstEnc := makeByteArrayObject(make([]byte, 8))
     nEnc := base64EncodeBsrcBdst([]interface{}{enc, srcObj, dstEnc}).(int64)
     encJB := dstEnc.FieldTable["value"].Fvalue.([]types.JavaByte)
     if int64(len(encJB)) != nEnc {
@@ -137,7 +137,7 @@ func TestBase64_Std_BsrcBdst_Variants(t *testing.T) {
 
     // Now decode into destination
     encBytesObj := makeByteArrayObject(object.GoByteArrayFromJavaByteArray(encJB))
-    dstDec := makeByteArrayObject([]byte{})
+    dstDec := makeByteArrayObject(make([]byte, len(input)))
     nDec := base64DecodeBsrcBdst([]interface{}{dec, encBytesObj, dstDec}).(int64)
     decJB := dstDec.FieldTable["value"].Fvalue.([]types.JavaByte)
     if int64(len(decJB)) != nDec {
@@ -210,3 +210,67 @@ func TestBase64_Decode_InvalidInput(t *testing.T) {
         t.Fatalf("expected error block for invalid base64 input, got %T", res)
     }
 }
+
+func TestBase64_BsrcBdst_DestinationTooSmall(t *testing.T) {
+    globals.InitStringPool()
+
+    enc := base64GetStdEncoder([]interface{}{}).(*object.Object)
+    dst := makeByteArrayObject(make([]byte, 4))
+    res := base64EncodeBsrcBdst([]interface{}{enc, makeByteArrayObject([]byte("hello")), dst})
+    if _, ok := res.(*GErrBlk); !ok {
+        t.Fatalf("expected error block for a destination array that is too small, got %T", res)
+    }
+
+    // A larger destination array keeps its length; the output is written at its start
+    dst = makeByteArrayObject([]byte("0123456789"))
+    n := base64EncodeBsrcBdst([]interface{}{enc, makeByteArrayObject([]byte("hi")), dst}).(int64)
+    got := string(object.GoByteArrayFromJavaByteArray(getJavaBytesFromArrayObject(dst)))
+    if n != 4 || got != "aGk=456789" {
+        t.Fatalf("encode([B[B)I into a larger array: n=%d, array=%q", n, got)
+    }
+}
+
+func TestBase64_Mime_LineSeparators(t *testing.T) {
+    globals.InitStringPool()
+
+    input := make([]byte, 100) // encodes to 136 characters
+    for i := range input {
+        input[i] = byte(i)
+    }
+    srcObj := makeByteArrayObject(input)
+
+    // The default MIME encoder writes lines of 76 characters separated by CRLF
+    mimeEnc := base64GetMimeEncoder([]interface{}{}).(*object.Object)
+    encStr := object.GoStringFromStringObject(base64EncodeBsrcToString([]interface{}{mimeEnc, srcObj}).(*object.Object))
+    if len(encStr) != 138 || encStr[76:78] != "\r\n" {
+        t.Fatalf("expected 136 characters in 2 lines separated by CRLF, got %q", encStr)
+    }
+
+    // A custom MIME encoder rounds the line length down to a multiple of 4
+    sep := makeByteArrayObject([]byte{'\n'})
+    customEnc := base64GetMimeEncoderCustom([]interface{}{int64(10), sep}).(*object.Object)
+    customStr := object.GoStringFromStringObject(base64EncodeBsrcToString([]interface{}{customEnc, srcObj}).(*object.Object))
+    if customStr[8] != '\n' || len(customStr) != 136+16 {
+        t.Fatalf("expected lines of 8 characters separated by LF, got %q", customStr)
+    }
+
+    // withoutPadding keeps the line separators
+    rawStr := object.GoStringFromStringObject(base64EncodeBsrcToString(
+        []interface{}{base64WithoutPadding([]interface{}{customEnc}), makeByteArrayObject([]byte("0123456789"))}).(*object.Object))
+    if rawStr != "MDEyMzQ1\nNjc4OQ" {
+        t.Fatalf("expected unpadded lines of 8 characters, got %q", rawStr)
+    }
+
+    // The MIME decoder ignores the separators and any other non-alphabet characters
+    mimeDec := base64GetMimeDecoder([]interface{}{}).(*object.Object)
+    decOut := base64Decode([]interface{}{mimeDec, object.StringObjectFromGoString(customStr + "\t*")}).(*object.Object)
+    if !bytesEqual(getJavaBytesFromArrayObject(decOut), object.JavaByteArrayFromGoByteArray(input)) {
+        t.Fatalf("MIME decode of custom-separated lines mismatch")
+    }
+
+    // A line separator can't contain Base64 characters
+    res := base64GetMimeEncoderCustom([]interface{}{int64(76), makeByteArrayObject([]byte("A"))})
+    if _, ok := res.(*GErrBlk); !ok {
+        t.Fatalf("expected error block for a line separator in the Base64 alphabet, got %T", res)
+    }
+}
@@ -0,0 +1,223 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+)
+
+// A partial implementation of the java/util/Collections class. The methods not here are
+// run from the JDK's bytecode. The empty and singleton collections are among the immutable
+// collections of javaUtilImmutableCollections.go, and the unmodifiable views are in
+// javaUtilCollectionsUnmodifiable.go.
+
+func Load_Util_Collections() {
+
+	MethodSignatures["java/util/Collections.emptyList()Ljava/util/List;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  collectionsEmptyList,
+		}
+
+	MethodSignatures["java/util/Collections.emptyMap()Ljava/util/Map;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  collectionsEmptyMap,
+		}
+
+	MethodSignatures["java/util/Collections.emptySet()Ljava/util/Set;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  collectionsEmptySet,
+		}
+
+	MethodSignatures["java/util/Collections.shuffle(Ljava/util/List;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    collectionsShuffle,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Collections.shuffle(Ljava/util/List;Ljava/util/Random;)V"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    collectionsShuffle,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Collections.singleton(Ljava/lang/Object;)Ljava/util/Set;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    collectionsSingleton,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Collections.singletonList(Ljava/lang/Object;)Ljava/util/List;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  collectionsSingletonList,
+		}
+
+	MethodSignatures["java/util/Collections.singletonMap(Ljava/lang/Object;Ljava/lang/Object;)Ljava/util/Map;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    collectionsSingletonMap,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Collections.sort(Ljava/util/List;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    collectionsSort,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Collections.sort(Ljava/util/List;Ljava/util/Comparator;)V"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    collectionsSort,
+			NeedsContext: true,
+		}
+}
+
+// java/util/Collections.sort(Ljava/util/List;)V and sort(Ljava/util/List;Ljava/util/Comparator;)V
+// As in the JDK's List.sort(), the elements are copied to an array by toArray(), sorted,
+// and put back in the list by set(). The list is left as it was if a comparison throws.
+func collectionsSort(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	lst, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(lst) {
+		return getGErrBlk(excNames.NullPointerException, "collectionsSort: the list is null")
+	}
+	var comparator *object.Object
+	if len(params) > 2 {
+		comparator, _ = params[2].(*object.Object)
+	}
+
+	ret, gErr := invokeJavaMethod(fs, "java/util/List", "toArray", "()[Ljava/lang/Object;", lst, nil)
+	if gErr != nil {
+		return gErr
+	}
+	arr, ok := ret.(*object.Object)
+	if !ok || object.IsNull(arr) {
+		return getGErrBlk(excNames.IllegalStateException, "collectionsSort: toArray() did not return an array")
+	}
+	elems, _ := arr.FieldTable["value"].Fvalue.([]*object.Object)
+
+	sorted, gErr := sortJavaObjects(fs, elems, comparator)
+	if gErr != nil {
+		return gErr
+	}
+	return setListElements(fs, lst, sorted)
+}
+
+// puts the elements in the list by its set(), in order from index 0
+func setListElements(fs *list.List, lst *object.Object, elems []*object.Object) interface{} {
+	for i, elem := range elems {
+		_, gErr := invokeJavaMethod(fs, "java/util/List", "set", "(ILjava/lang/Object;)Ljava/lang/Object;",
+			lst, []any{int64(i), elem})
+		if gErr != nil {
+			return gErr
+		}
+	}
+	return nil
+}
+
+// the Random of shuffle(List), made when it's first needed, as in the JDK
+var collectionsShuffleRandom *object.Object
+
+// java/util/Collections.shuffle(Ljava/util/List;)V and shuffle(Ljava/util/List;Ljava/util/Random;)V
+// The elements are shuffled as the JDK shuffles them: from the last position to the second,
+// each is swapped with the element at a random position up to it, drawn by the nextInt() of
+// the Random. So a Random with a given seed gives the same order each time.
+func collectionsShuffle(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	lst, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(lst) {
+		return getGErrBlk(excNames.NullPointerException, "collectionsShuffle: the list is null")
+	}
+	var rnd *object.Object
+	if len(params) > 2 {
+		rnd, _ = params[2].(*object.Object)
+		if object.IsNull(rnd) {
+			return getGErrBlk(excNames.NullPointerException, "collectionsShuffle: the Random is null")
+		}
+	} else {
+		if collectionsShuffleRandom == nil {
+			className := "java/util/Random"
+			collectionsShuffleRandom = object.MakeEmptyObjectWithClassName(&className)
+			randomInitVoid([]interface{}{collectionsShuffleRandom})
+		}
+		rnd = collectionsShuffleRandom
+	}
+
+	elems, gErr := collectionElements(fs, lst)
+	if gErr != nil {
+		return gErr
+	}
+	elems = append([]*object.Object(nil), elems...)
+	for i := len(elems); i > 1; i-- {
+		var ret any
+		if _, isGoRandom := rnd.FieldTable["value"].Fvalue.(Random); isGoRandom {
+			ret = randomNextIntBound([]interface{}{rnd, int64(i)})
+		} else { // a subclass of Random
+			ret, gErr = invokeJavaMethod(fs, "java/util/Random", "nextInt", "(I)I", rnd, []any{int64(i)})
+			if gErr != nil {
+				return gErr
+			}
+		}
+		j, _ := ret.(int64)
+		elems[i-1], elems[j] = elems[j], elems[i-1]
+	}
+	return setListElements(fs, lst, elems)
+}
+
+// java/util/Collections.emptyList()Ljava/util/List;
+func collectionsEmptyList([]interface{}) interface{} {
+	return newImmutableListObject(classNameEmptyList, nil)
+}
+
+// java/util/Collections.emptySet()Ljava/util/Set;
+func collectionsEmptySet([]interface{}) interface{} {
+	return newImmutableLinkedHashObject(classNameEmptySet, newLinkedHashView(false))
+}
+
+// java/util/Collections.emptyMap()Ljava/util/Map;
+func collectionsEmptyMap([]interface{}) interface{} {
+	return newImmutableLinkedHashObject(classNameEmptyMap, newLinkedHashView(true))
+}
+
+// java/util/Collections.singletonList(Ljava/lang/Object;)Ljava/util/List; -- unlike List.of(),
+// the singleton collections may hold null
+func collectionsSingletonList(params []interface{}) interface{} {
+	elem, _ := params[0].(*object.Object)
+	return newImmutableListObject(classNameSingletonList, []*object.Object{elem})
+}
+
+// java/util/Collections.singleton(Ljava/lang/Object;)Ljava/util/Set;
+func collectionsSingleton(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	elem, _ := params[1].(*object.Object)
+	view := newLinkedHashView(false)
+	if _, _, gErr := view.store.put(fs, elem, nil, false); gErr != nil {
+		return gErr
+	}
+	return newImmutableLinkedHashObject(classNameSingletonSet, view)
+}
+
+// java/util/Collections.singletonMap(Ljava/lang/Object;Ljava/lang/Object;)Ljava/util/Map;
+func collectionsSingletonMap(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	key, _ := params[1].(*object.Object)
+	view := newLinkedHashView(true)
+	if _, _, gErr := view.store.put(fs, key, params[2], false); gErr != nil {
+		return gErr
+	}
+	return newImmutableLinkedHashObject(classNameSingletonMap, view)
+}
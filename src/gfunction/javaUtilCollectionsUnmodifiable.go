@@ -0,0 +1,368 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"jacobin/src/util"
+	"strings"
+)
+
+// The unmodifiable views returned by Collections.unmodifiableCollection(), unmodifiableList(),
+// unmodifiableSet(), and unmodifiableMap(). A view passes the methods that read it to the
+// collection it wraps, in Java, so it works over any collection and shows the changes made
+// to it; the methods that would change it throw an UnsupportedOperationException. The
+// iterators, sublists, and collection views it returns are wrapped in turn, and so are the
+// entries of the entry set of a map, which are read-only snapshots.
+
+var classNameUnmodifiableCollection = "java/util/Collections$UnmodifiableCollection"
+var classNameUnmodifiableList = "java/util/Collections$UnmodifiableList"
+var classNameUnmodifiableSet = "java/util/Collections$UnmodifiableSet"
+var classNameUnmodifiableMap = "java/util/Collections$UnmodifiableMap"
+var classNameUnmodifiableEntrySet = "java/util/Collections$UnmodifiableMap$UnmodifiableEntrySet"
+var classNameUnmodifiableEntry = "java/util/Collections$UnmodifiableMap$UnmodifiableEntrySet$UnmodifiableEntry"
+var classNameUnmodifiableIterator = "java/util/Collections$UnmodifiableCollection$1"
+
+// The field of an unmodifiable view or of its iterator that holds the Go state
+var fieldNameUnmodifiable = "unmodifiable"
+
+// the methods of a collection, a list, a map, and an iterator that only read them
+var collectionReadMethods = []string{
+	"contains(Ljava/lang/Object;)Z",
+	"containsAll(Ljava/util/Collection;)Z",
+	"equals(Ljava/lang/Object;)Z",
+	"hashCode()I",
+	"isEmpty()Z",
+	"size()I",
+	"toArray()[Ljava/lang/Object;",
+	"toString()Ljava/lang/String;",
+}
+
+var listReadMethods = []string{
+	"get(I)Ljava/lang/Object;",
+	"indexOf(Ljava/lang/Object;)I",
+	"lastIndexOf(Ljava/lang/Object;)I",
+}
+
+var mapReadMethods = []string{
+	"containsKey(Ljava/lang/Object;)Z",
+	"containsValue(Ljava/lang/Object;)Z",
+	"equals(Ljava/lang/Object;)Z",
+	"get(Ljava/lang/Object;)Ljava/lang/Object;",
+	"getOrDefault(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;",
+	"hashCode()I",
+	"isEmpty()Z",
+	"size()I",
+	"toString()Ljava/lang/String;",
+}
+
+func Load_Util_Collections_Unmodifiable() {
+
+	MethodSignatures["java/util/Collections.unmodifiableCollection(Ljava/util/Collection;)Ljava/util/Collection;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  collectionsUnmodifiableCollection,
+		}
+
+	MethodSignatures["java/util/Collections.unmodifiableList(Ljava/util/List;)Ljava/util/List;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  collectionsUnmodifiableList,
+		}
+
+	MethodSignatures["java/util/Collections.unmodifiableMap(Ljava/util/Map;)Ljava/util/Map;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  collectionsUnmodifiableMap,
+		}
+
+	MethodSignatures["java/util/Collections.unmodifiableSet(Ljava/util/Set;)Ljava/util/Set;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  collectionsUnmodifiableSet,
+		}
+
+	// the collections
+
+	for _, className := range []string{classNameUnmodifiableCollection, classNameUnmodifiableList,
+		classNameUnmodifiableSet, classNameUnmodifiableEntrySet} {
+		registerPassThroughMethods(className, "java/util/Collection", collectionReadMethods)
+		registerUnsupportedMethods(className, collectionWriteMethods)
+
+		MethodSignatures[className+".iterator()Ljava/util/Iterator;"] =
+			GMeth{
+				ParamSlots:   0,
+				GFunction:    unmodifiableIterator,
+				NeedsContext: true,
+			}
+	}
+
+	registerPassThroughMethods(classNameUnmodifiableList, "java/util/List", listReadMethods)
+	registerUnsupportedMethods(classNameUnmodifiableList, listWriteMethods)
+
+	MethodSignatures[classNameUnmodifiableList+".subList(II)Ljava/util/List;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    unmodifiableSubList,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[classNameUnmodifiableEntrySet+".toArray()[Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    unmodifiableEntrySetToArray,
+			NeedsContext: true,
+		}
+
+	// the maps
+
+	registerPassThroughMethods(classNameUnmodifiableMap, "java/util/Map", mapReadMethods)
+	registerUnsupportedMethods(classNameUnmodifiableMap, mapWriteMethods)
+
+	MethodSignatures[classNameUnmodifiableMap+".entrySet()Ljava/util/Set;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    unmodifiableMapEntrySet,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[classNameUnmodifiableMap+".keySet()Ljava/util/Set;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    unmodifiableMapKeySet,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[classNameUnmodifiableMap+".values()Ljava/util/Collection;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    unmodifiableMapValues,
+			NeedsContext: true,
+		}
+
+	// the iterators and entries
+
+	MethodSignatures[classNameUnmodifiableIterator+".hasNext()Z"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    unmodifiablePassThrough("java/util/Iterator", "hasNext", "()Z"),
+			NeedsContext: true,
+		}
+
+	MethodSignatures[classNameUnmodifiableIterator+".next()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    unmodifiableIteratorNext,
+			NeedsContext: true,
+		}
+
+	registerUnsupportedMethods(classNameUnmodifiableIterator, []string{"remove()V"})
+
+	registerMapEntryMethods(classNameUnmodifiableEntry)
+	MethodSignatures[classNameUnmodifiableEntry+".setValue(Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  immutableUnsupported,
+		}
+}
+
+// unmodifiableView is the state of an unmodifiable view or of its iterator: the collection,
+// map, or iterator it wraps
+type unmodifiableView struct {
+	wrapped *object.Object
+	entries bool // it holds the entries of a map, which are wrapped in turn
+}
+
+// makes an unmodifiable view of the class over the collection, map, or iterator
+func newUnmodifiableObject(className string, wrapped *object.Object, entries bool) *object.Object {
+	return object.MakeOneFieldObject(className, fieldNameUnmodifiable, types.Unmodifiable,
+		&unmodifiableView{wrapped: wrapped, entries: entries})
+}
+
+// returns the state of the unmodifiable view in params[index]
+func getUnmodifiableView(funcName string, params []interface{}, index int) (*unmodifiableView, *GErrBlk) {
+	obj, ok := params[index].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return nil, getGErrBlk(excNames.NullPointerException, funcName+": the object is null")
+	}
+	view, ok := obj.FieldTable[fieldNameUnmodifiable].Fvalue.(*unmodifiableView)
+	if !ok {
+		return nil, getGErrBlk(excNames.IllegalStateException, funcName+": not an unmodifiable view")
+	}
+	return view, nil
+}
+
+// registers the methods, given by their names and descriptors, as ones passed to the
+// wrapped object through the interface
+func registerPassThroughMethods(className, iface string, methods []string) {
+	for _, method := range methods {
+		name, desc, _ := strings.Cut(method, "(")
+		desc = "(" + desc
+		MethodSignatures[className+"."+method] =
+			GMeth{
+				ParamSlots:   len(util.ParseIncomingParamsFromMethTypeString(desc)),
+				GFunction:    unmodifiablePassThrough(iface, name, desc),
+				NeedsContext: true,
+			}
+	}
+}
+
+// returns a gfunction that calls the method of the interface on the wrapped object, with
+// the same arguments, and returns what it returns
+func unmodifiablePassThrough(iface, name, desc string) func([]interface{}) interface{} {
+	return func(params []interface{}) interface{} {
+		view, gErr := getUnmodifiableView("unmodifiablePassThrough", params, 1)
+		if gErr != nil {
+			return gErr
+		}
+		ret, gErr := invokeJavaMethod(params[0].(*list.List), iface, name, desc, view.wrapped, params[2:])
+		if gErr != nil {
+			return gErr
+		}
+		return ret
+	}
+}
+
+// calls a method of the wrapped object that returns a collection, an iterator, or a list,
+// and returns an unmodifiable view of the class over what it returns
+func unmodifiableWrapResult(params []interface{}, iface, name, desc, className string, entries bool) interface{} {
+	view, gErr := getUnmodifiableView("unmodifiableWrapResult", params, 1)
+	if gErr != nil {
+		return gErr
+	}
+	ret, gErr := invokeJavaMethod(params[0].(*list.List), iface, name, desc, view.wrapped, params[2:])
+	if gErr != nil {
+		return gErr
+	}
+	obj, ok := ret.(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return getGErrBlk(excNames.NullPointerException, "unmodifiableWrapResult: "+name+"() returned null")
+	}
+	return newUnmodifiableObject(className, obj, entries || view.entries)
+}
+
+// makes an unmodifiable view of the class over the object in params[0], which must not be
+// null. A view of the same class is returned as it is.
+func collectionsUnmodifiable(funcName string, params []interface{}, className string) interface{} {
+	wrapped, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(wrapped) {
+		return getGErrBlk(excNames.NullPointerException, funcName+": the collection is null")
+	}
+	if *stringPool.GetStringPointer(wrapped.KlassName) == className {
+		return wrapped
+	}
+	return newUnmodifiableObject(className, wrapped, false)
+}
+
+// "java/util/Collections.unmodifiableCollection(Ljava/util/Collection;)Ljava/util/Collection;"
+func collectionsUnmodifiableCollection(params []interface{}) interface{} {
+	return collectionsUnmodifiable("collectionsUnmodifiableCollection", params, classNameUnmodifiableCollection)
+}
+
+// "java/util/Collections.unmodifiableList(Ljava/util/List;)Ljava/util/List;"
+func collectionsUnmodifiableList(params []interface{}) interface{} {
+	return collectionsUnmodifiable("collectionsUnmodifiableList", params, classNameUnmodifiableList)
+}
+
+// "java/util/Collections.unmodifiableMap(Ljava/util/Map;)Ljava/util/Map;"
+func collectionsUnmodifiableMap(params []interface{}) interface{} {
+	return collectionsUnmodifiable("collectionsUnmodifiableMap", params, classNameUnmodifiableMap)
+}
+
+// "java/util/Collections.unmodifiableSet(Ljava/util/Set;)Ljava/util/Set;"
+func collectionsUnmodifiableSet(params []interface{}) interface{} {
+	return collectionsUnmodifiable("collectionsUnmodifiableSet", params, classNameUnmodifiableSet)
+}
+
+// "java/util/Collections$UnmodifiableCollection.iterator()Ljava/util/Iterator;" and the
+// same method of the other views
+func unmodifiableIterator(params []interface{}) interface{} {
+	return unmodifiableWrapResult(params, "java/util/Collection", "iterator", "()Ljava/util/Iterator;",
+		classNameUnmodifiableIterator, false)
+}
+
+// "java/util/Collections$UnmodifiableList.subList(II)Ljava/util/List;"
+func unmodifiableSubList(params []interface{}) interface{} {
+	return unmodifiableWrapResult(params, "java/util/List", "subList", "(II)Ljava/util/List;",
+		classNameUnmodifiableList, false)
+}
+
+// "java/util/Collections$UnmodifiableMap.entrySet()Ljava/util/Set;"
+func unmodifiableMapEntrySet(params []interface{}) interface{} {
+	return unmodifiableWrapResult(params, "java/util/Map", "entrySet", "()Ljava/util/Set;",
+		classNameUnmodifiableEntrySet, true)
+}
+
+// "java/util/Collections$UnmodifiableMap.keySet()Ljava/util/Set;"
+func unmodifiableMapKeySet(params []interface{}) interface{} {
+	return unmodifiableWrapResult(params, "java/util/Map", "keySet", "()Ljava/util/Set;",
+		classNameUnmodifiableSet, false)
+}
+
+// "java/util/Collections$UnmodifiableMap.values()Ljava/util/Collection;"
+func unmodifiableMapValues(params []interface{}) interface{} {
+	return unmodifiableWrapResult(params, "java/util/Map", "values", "()Ljava/util/Collection;",
+		classNameUnmodifiableCollection, false)
+}
+
+// returns a read-only snapshot of the Map.Entry
+func unmodifiableEntry(fs *list.List, entryObj *object.Object) (*object.Object, *GErrBlk) {
+	if object.IsNull(entryObj) {
+		return entryObj, nil
+	}
+	entry, gErr := javaMapEntry(fs, entryObj)
+	if gErr != nil {
+		return nil, gErr
+	}
+	return newMapEntryObject(classNameUnmodifiableEntry, entry, true), nil
+}
+
+// "java/util/Collections$UnmodifiableCollection$1.next()Ljava/lang/Object;" -- the entries of
+// a map are wrapped, so they can't be changed through setValue()
+func unmodifiableIteratorNext(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	view, gErr := getUnmodifiableView("unmodifiableIteratorNext", params, 1)
+	if gErr != nil {
+		return gErr
+	}
+	ret, gErr := invokeJavaMethod(fs, "java/util/Iterator", "next", "()Ljava/lang/Object;", view.wrapped, nil)
+	if gErr != nil {
+		return gErr
+	}
+	if entryObj, ok := ret.(*object.Object); ok && view.entries {
+		if ret, gErr = unmodifiableEntry(fs, entryObj); gErr != nil {
+			return gErr
+		}
+	}
+	return ret
+}
+
+// "java/util/Collections$UnmodifiableMap$UnmodifiableEntrySet.toArray()[Ljava/lang/Object;"
+// -- the entries are wrapped, as the iterator wraps them
+func unmodifiableEntrySetToArray(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	view, gErr := getUnmodifiableView("unmodifiableEntrySetToArray", params, 1)
+	if gErr != nil {
+		return gErr
+	}
+	entryObjs, gErr := collectionElements(fs, view.wrapped)
+	if gErr != nil {
+		return gErr
+	}
+	arr := object.Make1DimRefArray("java/lang/Object;", int64(len(entryObjs)))
+	elems := arr.FieldTable["value"].Fvalue.([]*object.Object)
+	for i, entryObj := range entryObjs {
+		if elems[i], gErr = unmodifiableEntry(fs, entryObj); gErr != nil {
+			return gErr
+		}
+	}
+	return arr
+}
@@ -0,0 +1,124 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/excNames"
+	"jacobin/src/exceptions"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"strings"
+	"testing"
+)
+
+// replaces the upcall bridge with one that runs the gfunction registered for the method in
+// the class of the receiver, as the JVM does for the collections implemented in Go
+func fakeGfunctionUpcalls(t *testing.T) {
+	globals.InitGlobals("test")
+	Load_Util_ImmutableCollections()
+	Load_Util_Collections_Unmodifiable()
+	Load_Util_LinkedHashMap()
+	Load_Util_LinkedHashSet()
+
+	glob := globals.GetGlobalRef()
+	saved := glob.FuncInvokeJavaMethod
+	glob.FuncInvokeJavaMethod = func(fs *list.List, _, methName, methType string, receiver any, args []any) (any, error) {
+		fqn := *stringPool.GetStringPointer(receiver.(*object.Object).KlassName) + "." + methName + methType
+		gmeth, ok := MethodSignatures[fqn]
+		if !ok {
+			t.Fatalf("unexpected upcall of %s", fqn)
+		}
+		var params []interface{}
+		if gmeth.NeedsContext {
+			params = append(params, fs)
+		}
+		params = append(append(params, receiver), args...)
+		ret := gmeth.GFunction(params)
+		if gErr, ok := ret.(*GErrBlk); ok {
+			return nil, &exceptions.UpcallError{Method: fqn,
+				Cause: excNames.JVMexceptionNames[gErr.ExceptionType] + ": " + gErr.ErrMsg}
+		}
+		return ret, nil
+	}
+	t.Cleanup(func() { glob.FuncInvokeJavaMethod = saved })
+}
+
+// calls the method registered for the class of the object, as the JVM would
+func callRegistered(t *testing.T, obj *object.Object, method string, args ...interface{}) interface{} {
+	t.Helper()
+	name, desc, _ := strings.Cut(method, "(")
+	ret, gErr := invokeJavaMethod(list.New(), "", name, "("+desc, obj, args)
+	if gErr != nil {
+		return gErr
+	}
+	return ret
+}
+
+func TestUnmodifiableList(t *testing.T) {
+	fakeGfunctionUpcalls(t)
+	backing := newTestLinkedHashSet(1, 2)
+	view := collectionsUnmodifiableCollection([]interface{}{backing}).(*object.Object)
+
+	checkTreeString(t, "toString()", callRegistered(t, view, "toString()Ljava/lang/String;"), "[1, 2]")
+	treeCall(linkedhashsetAdd, backing, treeInt(3))
+	if ret := callRegistered(t, view, "size()I"); ret != int64(3) {
+		t.Errorf("size() after a change to the collection: expected 3, got %v", ret)
+	}
+	if ret := callRegistered(t, view, "contains(Ljava/lang/Object;)Z", treeInt(3)); ret != types.JavaBoolTrue {
+		t.Errorf("contains(3): expected true, got %v", ret)
+	}
+	checkTreeError(t, "add()", callRegistered(t, view, "add(Ljava/lang/Object;)Z", treeInt(4)),
+		excNames.UnsupportedOperationException)
+	if collectionsUnmodifiableCollection([]interface{}{view}) != view {
+		t.Errorf("unmodifiableCollection() of an unmodifiable collection did not return it")
+	}
+
+	it := callRegistered(t, view, "iterator()Ljava/util/Iterator;").(*object.Object)
+	checkTreeKey(t, "next()", callRegistered(t, it, "next()Ljava/lang/Object;"), 1)
+	checkTreeError(t, "remove() by the iterator", callRegistered(t, it, "remove()V"),
+		excNames.UnsupportedOperationException)
+
+	lst := collectionsUnmodifiableList([]interface{}{listOf([]interface{}{treeInt(7), treeInt(8)})}).(*object.Object)
+	checkTreeKey(t, "get(1)", callRegistered(t, lst, "get(I)Ljava/lang/Object;", int64(1)), 8)
+	checkTreeError(t, "set()", callRegistered(t, lst, "set(ILjava/lang/Object;)Ljava/lang/Object;", int64(0),
+		treeInt(1)), excNames.UnsupportedOperationException)
+	sub := callRegistered(t, lst, "subList(II)Ljava/util/List;", int64(1), int64(2)).(*object.Object)
+	checkTreeString(t, "subList(1, 2)", callRegistered(t, sub, "toString()Ljava/lang/String;"), "[8]")
+	checkTreeError(t, "subList().clear()", callRegistered(t, sub, "clear()V"), excNames.UnsupportedOperationException)
+}
+
+func TestUnmodifiableMap(t *testing.T) {
+	fakeGfunctionUpcalls(t)
+	backing := newTestLinkedHashMap(1, 2)
+	view := collectionsUnmodifiableMap([]interface{}{backing}).(*object.Object)
+
+	checkTreeString(t, "get(2)", callRegistered(t, view, "get(Ljava/lang/Object;)Ljava/lang/Object;", treeInt(2)),
+		"2.0")
+	checkTreeError(t, "put()", callRegistered(t, view, "put(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;",
+		treeInt(3), treeInt(3)), excNames.UnsupportedOperationException)
+
+	keys := callRegistered(t, view, "keySet()Ljava/util/Set;").(*object.Object)
+	checkTreeError(t, "keySet().remove()", callRegistered(t, keys, "remove(Ljava/lang/Object;)Z", treeInt(1)),
+		excNames.UnsupportedOperationException)
+	values := callRegistered(t, view, "values()Ljava/util/Collection;").(*object.Object)
+	checkTreeString(t, "values()", callRegistered(t, values, "toString()Ljava/lang/String;"), "[1.0, 2.0]")
+
+	entries := callRegistered(t, view, "entrySet()Ljava/util/Set;").(*object.Object)
+	it := callRegistered(t, entries, "iterator()Ljava/util/Iterator;").(*object.Object)
+	entry := callRegistered(t, it, "next()Ljava/lang/Object;").(*object.Object)
+	checkTreeKey(t, "the key of the first entry", callRegistered(t, entry, "getKey()Ljava/lang/Object;"), 1)
+	checkTreeError(t, "setValue() of an entry", callRegistered(t, entry, "setValue(Ljava/lang/Object;)Ljava/lang/Object;",
+		treeInt(0)), excNames.UnsupportedOperationException)
+	arr := callRegistered(t, entries, "toArray()[Ljava/lang/Object;").(*object.Object)
+	for _, elem := range arr.FieldTable["value"].Fvalue.([]*object.Object) {
+		checkTreeError(t, "setValue() of an entry of toArray()", mapEntrySetValue([]interface{}{elem, treeInt(0)}),
+			excNames.UnsupportedOperationException)
+	}
+}
@@ -0,0 +1,246 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/exceptions"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"math"
+	"testing"
+)
+
+// replaces the upcall bridge with one that runs the methods called by the sorts: toArray()
+// and set() of a list whose elements are in its "elems" field, compare() of a comparator
+// that orders Integers in reverse, and compareTo() of pkg/Item, which compares "key" fields.
+// Returns the number of upcalls made.
+func fakeSortUpcalls(t *testing.T) *int {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	comparable := classNameComparable
+	itemKlass := classloader.Klass{Status: 'F', Loader: "testloader", Data: &classloader.ClData{
+		Name:            "pkg/Item",
+		SuperclassIndex: types.ObjectPoolStringIndex,
+		Interfaces:      []uint16{uint16(stringPool.GetStringIndex(&comparable))},
+	}}
+	classloader.MethAreaInsert("pkg/Item", &itemKlass)
+
+	calls := 0
+	glob := globals.GetGlobalRef()
+	saved := glob.FuncInvokeJavaMethod
+	glob.FuncInvokeJavaMethod = func(_ *list.List, className, methName, _ string, receiver any, args []any) (any, error) {
+		calls++
+		obj := receiver.(*object.Object)
+		switch methName {
+		case "toArray":
+			elems := obj.FieldTable["elems"].Fvalue.([]*object.Object)
+			arr := object.Make1DimRefArray("Ljava/lang/Object;", int64(len(elems)))
+			copy(arr.FieldTable["value"].Fvalue.([]*object.Object), elems)
+			return arr, nil
+		case "set":
+			elems := obj.FieldTable["elems"].Fvalue.([]*object.Object)
+			index := args[0].(int64)
+			old := elems[index]
+			elems[index] = args[1].(*object.Object)
+			return old, nil
+		case "compare":
+			a := args[0].(*object.Object).FieldTable["value"].Fvalue.(int64)
+			b := args[1].(*object.Object).FieldTable["value"].Fvalue.(int64)
+			return b - a, nil
+		case "compareTo":
+			a := obj.FieldTable["key"].Fvalue.(int64)
+			b := args[0].(*object.Object).FieldTable["key"].Fvalue.(int64)
+			if a == 13 || b == 13 {
+				return nil, &exceptions.UpcallError{Method: className + ".compareTo",
+					Cause: "java.lang.IllegalStateException: unlucky"}
+			}
+			return a - b, nil
+		}
+		t.Fatalf("unexpected upcall of %s.%s", className, methName)
+		return nil, nil
+	}
+	t.Cleanup(func() { glob.FuncInvokeJavaMethod = saved })
+	return &calls
+}
+
+func newTestList(elems ...*object.Object) *object.Object {
+	className := "java/util/ArrayList"
+	lst := object.MakeEmptyObjectWithClassName(&className)
+	lst.FieldTable["elems"] = object.Field{Ftype: types.RefArray, Fvalue: elems}
+	return lst
+}
+
+func newTestItem(key int64, name string) *object.Object {
+	className := "pkg/Item"
+	item := object.MakeEmptyObjectWithClassName(&className)
+	item.FieldTable["key"] = object.Field{Ftype: types.Long, Fvalue: key}
+	item.FieldTable["name"] = object.Field{Ftype: types.GolangString, Fvalue: name}
+	return item
+}
+
+func TestCollectionsSortNaturalOrdering(t *testing.T) {
+	calls := fakeSortUpcalls(t)
+
+	words := []string{"pear", "Apple", "apple", "\U0001F600", "～", "banana"}
+	var elems []*object.Object
+	for _, word := range words {
+		elems = append(elems, object.StringObjectFromGoString(word))
+	}
+	lst := newTestList(elems...)
+	if ret := collectionsSort([]interface{}{list.New(), lst}); ret != nil {
+		t.Fatalf("Collections.sort(): unexpected result %v", ret)
+	}
+	// by UTF-16 code units, the surrogate pair of the emoji sorts before U+FF5E
+	expected := []string{"Apple", "apple", "banana", "pear", "\U0001F600", "～"}
+	for i, elem := range lst.FieldTable["elems"].Fvalue.([]*object.Object) {
+		if got := object.GoStringFromStringObject(elem); got != expected[i] {
+			t.Errorf("element %d: got %q want %q", i, got, expected[i])
+		}
+	}
+	if *calls != 1+len(words) { // toArray() and a set() per element, but no compareTo()
+		t.Errorf("expected the strings to be compared without upcalls, got %d upcalls", *calls)
+	}
+
+	// items with the same key keep their order
+	items := []*object.Object{newTestItem(3, "c"), newTestItem(1, "a1"), newTestItem(2, "b"), newTestItem(1, "a2")}
+	lst = newTestList(items...)
+	collectionsSort([]interface{}{list.New(), lst, object.Null})
+	var names string
+	for _, elem := range lst.FieldTable["elems"].Fvalue.([]*object.Object) {
+		names += elem.FieldTable["name"].Fvalue.(string) + " "
+	}
+	if names != "a1 a2 b c " {
+		t.Errorf("sorting by compareTo(): got %q", names)
+	}
+}
+
+func TestCollectionsSortWithComparator(t *testing.T) {
+	fakeSortUpcalls(t)
+
+	var elems []*object.Object
+	for _, value := range []int64{5, -2, 9, 0} {
+		elems = append(elems, Populator("java/lang/Integer", types.Int, value))
+	}
+	className := "pkg/Reverse"
+	comparator := object.MakeEmptyObjectWithClassName(&className)
+	lst := newTestList(elems...)
+	collectionsSort([]interface{}{list.New(), lst, comparator})
+	var got []int64
+	for _, elem := range lst.FieldTable["elems"].Fvalue.([]*object.Object) {
+		got = append(got, elem.FieldTable["value"].Fvalue.(int64))
+	}
+	if len(got) != 4 || got[0] != 9 || got[1] != 5 || got[2] != 0 || got[3] != -2 {
+		t.Errorf("sorting with a comparator: got %v", got)
+	}
+}
+
+func TestCollectionsSortErrors(t *testing.T) {
+	fakeSortUpcalls(t)
+
+	ret := collectionsSort([]interface{}{list.New(), object.Null})
+	if gErr, ok := ret.(*GErrBlk); !ok || gErr.ExceptionType != excNames.NullPointerException {
+		t.Errorf("sort(null): expected NullPointerException, got %v", ret)
+	}
+
+	// an exception thrown by compareTo() is rethrown, and the list is left as it was
+	items := []*object.Object{newTestItem(3, "c"), newTestItem(13, "x"), newTestItem(1, "a")}
+	lst := newTestList(items...)
+	ret = collectionsSort([]interface{}{list.New(), lst})
+	if gErr, ok := ret.(*GErrBlk); !ok || gErr.ExceptionType != excNames.IllegalStateException ||
+		gErr.ErrMsg != "unlucky" {
+		t.Errorf("sort() with a throwing compareTo(): expected IllegalStateException, got %v", ret)
+	}
+	if lst.FieldTable["elems"].Fvalue.([]*object.Object)[0] != items[0] {
+		t.Errorf("a failed sort changed the list")
+	}
+
+	className := "pkg/NotComparable"
+	other := object.MakeEmptyObjectWithClassName(&className)
+	ret = collectionsSort([]interface{}{list.New(), newTestList(other, other)})
+	if gErr, ok := ret.(*GErrBlk); !ok || gErr.ExceptionType != excNames.ClassCastException {
+		t.Errorf("sort() of an object that isn't Comparable: expected ClassCastException, got %v", ret)
+	}
+
+	ret = collectionsSort([]interface{}{list.New(),
+		newTestList(object.StringObjectFromGoString("a"), object.Null)})
+	if gErr, ok := ret.(*GErrBlk); !ok || gErr.ExceptionType != excNames.NullPointerException {
+		t.Errorf("sort() of a null element: expected NullPointerException, got %v", ret)
+	}
+}
+
+func TestCompareBuiltinObjects(t *testing.T) {
+	globals.InitGlobals("test")
+
+	tests := []struct {
+		a, b     *object.Object
+		expected int
+	}{
+		{Populator("java/lang/Double", types.Double, 1.5), Populator("java/lang/Double", types.Double, 1.5), 0},
+		{Populator("java/lang/Double", types.Double, math.Copysign(0, -1)), Populator("java/lang/Double", types.Double, 0.0), -1},
+		{Populator("java/lang/Double", types.Double, math.NaN()), Populator("java/lang/Double", types.Double, math.Inf(1)), 1},
+		{Populator("java/lang/Double", types.Double, math.NaN()), Populator("java/lang/Double", types.Double, math.NaN()), 0},
+		{Populator("java/lang/Long", types.Long, int64(-5)), Populator("java/lang/Long", types.Long, int64(3)), -1},
+		{Populator("java/lang/Boolean", types.Bool, types.JavaBoolTrue), Populator("java/lang/Boolean", types.Bool, types.JavaBoolFalse), 1},
+	}
+	for i, tt := range tests {
+		got, ok := compareBuiltinObjects(tt.a, tt.b)
+		if !ok || got != tt.expected {
+			t.Errorf("test %d: got %d, %v want %d", i, got, ok, tt.expected)
+		}
+	}
+	if _, ok := compareBuiltinObjects(Populator("java/lang/Long", types.Long, int64(1)),
+		Populator("java/lang/Integer", types.Int, int64(1))); ok {
+		t.Errorf("a Long and an Integer should not be compared as builtins")
+	}
+}
+
+func TestCollectionsShuffle(t *testing.T) {
+	fakeSortUpcalls(t)
+	shuffled := func(seed int64) []int64 {
+		className := "java/util/Random"
+		rnd := object.MakeEmptyObjectWithClassName(&className)
+		randomInitLong([]interface{}{rnd, seed})
+		var elems []*object.Object
+		for i := int64(0); i < 10; i++ {
+			elems = append(elems, Populator("java/lang/Integer", types.Int, i))
+		}
+		lst := newTestList(elems...)
+		if ret := collectionsShuffle([]interface{}{list.New(), lst, rnd}); ret != nil {
+			t.Fatalf("shuffle(): unexpected %v", ret)
+		}
+		var values []int64
+		for _, elem := range lst.FieldTable["elems"].Fvalue.([]*object.Object) {
+			values = append(values, elem.FieldTable["value"].Fvalue.(int64))
+		}
+		return values
+	}
+
+	first, second := shuffled(42), shuffled(42)
+	seen := make(map[int64]bool)
+	for i, value := range first {
+		if value != second[i] {
+			t.Errorf("shuffle() with the same seed gave %v and %v", first, second)
+			break
+		}
+		seen[value] = true
+	}
+	if len(seen) != 10 {
+		t.Errorf("shuffle() did not permute the elements: %v", first)
+	}
+
+	lst := newTestList(Populator("java/lang/Integer", types.Int, 1))
+	if ret := collectionsShuffle([]interface{}{list.New(), lst}); ret != nil {
+		t.Errorf("shuffle() with the default Random: unexpected %v", ret)
+	}
+	checkTreeError(t, "shuffle(null)", collectionsShuffle([]interface{}{list.New(), object.Null}),
+		excNames.NullPointerException)
+}
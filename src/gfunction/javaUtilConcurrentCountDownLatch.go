@@ -0,0 +1,146 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/types"
+)
+
+// Implementation of java/util/concurrent/CountDownLatch, on the synchronizer in
+// javaUtilConcurrentLocksReentrantLock.go, whose count is the latch's count.
+
+func Load_Util_Concurrent_CountDownLatch() {
+
+	MethodSignatures["java/util/concurrent/CountDownLatch.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/concurrent/CountDownLatch.<init>(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  countDownLatchInit,
+		}
+
+	MethodSignatures["java/util/concurrent/CountDownLatch.await()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    countDownLatchAwait,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/CountDownLatch.await(JLjava/util/concurrent/TimeUnit;)Z"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    countDownLatchAwait,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/CountDownLatch.countDown()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  countDownLatchCountDown,
+		}
+
+	MethodSignatures["java/util/concurrent/CountDownLatch.getCount()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  countDownLatchGetCount,
+		}
+
+	MethodSignatures["java/util/concurrent/CountDownLatch.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  countDownLatchToString,
+		}
+}
+
+// "java/util/concurrent/CountDownLatch.<init>(I)V"
+func countDownLatchInit(params []interface{}) interface{} {
+	this := params[0].(*object.Object)
+	count := params[1].(int64)
+	if count < 0 {
+		return getGErrBlk(excNames.IllegalArgumentException, "count < 0")
+	}
+	s := newSynchronizer(false)
+	s.count = count
+	this.FieldTable[fieldNameSyncState] = object.Field{Ftype: types.SyncState, Fvalue: s}
+	return nil
+}
+
+// "java/util/concurrent/CountDownLatch.await()V" and await(long, TimeUnit), which returns
+// false if the time runs out before the count reaches zero
+func countDownLatchAwait(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	s, gerr := getSynchronizer("countDownLatchAwait", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	timed := len(params) > 2
+	var acquired, interrupted bool
+	try := func() bool { return s.count == 0 }
+	if timed {
+		timeout, gerr := timeUnitDuration("countDownLatchAwait", params[2].(int64), params[3])
+		if gerr != nil {
+			return gerr
+		}
+		acquired, interrupted = s.await(fs, try, true, timeout, true, "")
+	} else {
+		acquired, interrupted = s.await(fs, try, false, 0, true, "")
+	}
+
+	if interrupted {
+		return getGErrBlk(excNames.InterruptedException, "countDownLatchAwait: interrupted")
+	}
+	if timed {
+		return object.JavaBooleanFromGoBoolean(acquired)
+	}
+	return nil
+}
+
+// "java/util/concurrent/CountDownLatch.countDown()V"
+func countDownLatchCountDown(params []interface{}) interface{} {
+	s, gerr := getSynchronizer("countDownLatchCountDown", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.count > 0 {
+		s.count--
+		if s.count == 0 {
+			s.notify()
+		}
+	}
+	return nil
+}
+
+// "java/util/concurrent/CountDownLatch.getCount()J"
+func countDownLatchGetCount(params []interface{}) interface{} {
+	s, gerr := getSynchronizer("countDownLatchGetCount", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.count
+}
+
+// "java/util/concurrent/CountDownLatch.toString()Ljava/lang/String;" -- e.g., java.util.concurrent.CountDownLatch@1b6d3586[Count = 2]
+func countDownLatchToString(params []interface{}) interface{} {
+	count := countDownLatchGetCount(params)
+	if gerr, ok := count.(*GErrBlk); ok {
+		return gerr
+	}
+	str := fmt.Sprintf("%s[Count = %d]", lockDescription(params[0]), count.(int64))
+	return object.StringObjectFromGoString(str)
+}
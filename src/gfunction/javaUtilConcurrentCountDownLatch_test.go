@@ -0,0 +1,60 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"testing"
+	"time"
+)
+
+func TestCountDownLatch(t *testing.T) {
+	globals.InitGlobals("test")
+	th, fs := addTestExecThread()
+	className := "java/util/concurrent/CountDownLatch"
+	latch := object.MakeEmptyObjectWithClassName(&className)
+
+	ret := countDownLatchInit([]interface{}{latch, int64(-1)})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("CountDownLatch(-1): expected IllegalArgumentException, got %v", ret)
+	}
+	countDownLatchInit([]interface{}{latch, int64(2)})
+
+	ret = countDownLatchAwait([]interface{}{fs, latch, int64(20), timeUnitObject("MILLISECONDS")})
+	if ret != types.JavaBoolFalse {
+		t.Errorf("await(20ms) on an open count returned %v", ret)
+	}
+
+	done := make(chan interface{})
+	go func() { done <- countDownLatchAwait([]interface{}{fs, latch}) }()
+	countDownLatchCountDown([]interface{}{latch})
+	select {
+	case <-done:
+		t.Fatalf("await() returned before the count reached zero")
+	case <-time.After(20 * time.Millisecond):
+	}
+	countDownLatchCountDown([]interface{}{latch})
+	if ret := <-done; ret != nil {
+		t.Errorf("await() returned %v", ret)
+	}
+
+	countDownLatchCountDown([]interface{}{latch})
+	if count := countDownLatchGetCount([]interface{}{latch}); count != int64(0) {
+		t.Errorf("getCount() = %v after counting down past zero", count)
+	}
+
+	// an interrupted thread doesn't wait, even on an open latch
+	th.Interrupt()
+	countDownLatchInit([]interface{}{latch, int64(1)})
+	ret = countDownLatchAwait([]interface{}{fs, latch})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.InterruptedException {
+		t.Errorf("await() on an interrupted thread: expected InterruptedException, got %v", ret)
+	}
+}
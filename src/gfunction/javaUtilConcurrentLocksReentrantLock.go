@@ -0,0 +1,575 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/frames"
+	"jacobin/src/object"
+	"jacobin/src/replay"
+	"jacobin/src/stringPool"
+	"jacobin/src/thread"
+	"jacobin/src/types"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Implementation of java/util/concurrent/locks/ReentrantLock and its Conditions, and of the
+// synchronizer that it shares with ReentrantReadWriteLock, CountDownLatch, and Semaphore.
+// Strategy: a synchronizer is a Go mutex that guards the state of the lock (or latch, or
+// semaphore) and a channel that is closed whenever that state changes. A thread that can't
+// acquire what it wants waits on the channel, interruptibly if the Java method is one that
+// throws InterruptedException, and then tries again. The thread that holds a lock is
+// identified by the ID of its VM thread, and a thread that waits for a lock held by another
+// records this for the deadlock detector. Fairness is not implemented: a fair lock behaves
+// as a nonfair one.
+
+const fieldNameSyncState = "syncState"
+
+var classNameReentrantLock = "java/util/concurrent/locks/ReentrantLock"
+var classNameConditionObject = "java/util/concurrent/locks/AbstractQueuedSynchronizer$ConditionObject"
+
+// synchronizer is the Go state of a lock, a CountDownLatch, or a Semaphore
+type synchronizer struct {
+	mutex   sync.Mutex
+	changed chan struct{} // closed, and replaced, whenever the state changes
+	owner   int           // the ID of the thread that holds the lock exclusively, if holds > 0
+	holds   int           // how many times the owner holds the lock
+	readers map[int]int   // for a read-write lock, how many times each thread holds the read lock
+	count   int64         // the count of a CountDownLatch or the permits of a Semaphore
+	fair    bool
+}
+
+func newSynchronizer(fair bool) *synchronizer {
+	return &synchronizer{
+		changed: make(chan struct{}),
+		readers: make(map[int]int),
+		fair:    fair,
+	}
+}
+
+// notify wakes the threads that are waiting for the state to change. Call with the mutex held.
+func (s *synchronizer) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// acquireExclusive takes the lock for the thread if it's free or the thread already holds
+// it. Call with the mutex held.
+func (s *synchronizer) acquireExclusive(threadID int) bool {
+	switch {
+	case s.holds == 0 && len(s.readers) == 0:
+		s.owner = threadID
+		s.holds = 1
+		return true
+	case s.holds > 0 && s.owner == threadID:
+		s.holds++
+		return true
+	}
+	return false
+}
+
+// await waits until try, which is called with the mutex held, succeeds. If timed is set, it
+// gives up after the timeout; if interruptible is set, an interrupt of the current thread ends
+// the wait. It returns whether try succeeded and whether the wait was interrupted. If
+// resource isn't empty, a wait for a lock held by another thread is recorded as being on
+// that resource, for the deadlock detector.
+func (s *synchronizer) await(fs *list.List, try func() bool, timed bool, timeout time.Duration,
+	interruptible bool, resource string) (acquired, interrupted bool) {
+
+	th := currentExecThread(fs)
+	if interruptible && th != nil && th.ClearInterrupt() {
+		return false, true
+	}
+	deadline := time.Now().Add(timeout)
+	threadID := currentThreadID(fs)
+
+	for {
+		replay.AwaitTurn(threadID) // when replaying, acquire in the recorded order
+		s.mutex.Lock()
+		if try() {
+			s.mutex.Unlock()
+			replay.Passed(threadID)
+			return true, false
+		}
+		changed := s.changed
+		owner, held := s.owner, s.holds > 0
+		s.mutex.Unlock()
+
+		var wait time.Duration // zero waits until the state changes
+		if timed {
+			if wait = time.Until(deadline); wait <= 0 {
+				return false, false
+			}
+		}
+		if th != nil && resource != "" && held {
+			thread.SetBlockedOn(th.ID, thread.BlockedOnLock, resource, owner)
+		}
+		if interruptible {
+			_, interrupted = waitForChannel(fs, changed, wait)
+		} else {
+			waitForChannel(nil, changed, wait)
+		}
+		if th != nil && resource != "" && held {
+			thread.ClearBlockedOn(th.ID)
+		}
+		if interrupted {
+			return false, true
+		}
+	}
+}
+
+// currentThreadID returns the ID of the VM thread whose frame stack is fs
+func currentThreadID(fs *list.List) int {
+	if fs == nil || fs.Len() == 0 {
+		return 0
+	}
+	return fs.Front().Value.(*frames.Frame).Thread
+}
+
+// getSynchronizer returns the synchronizer of a lock, a view of a lock, a latch, or a semaphore
+func getSynchronizer(funcName string, obj interface{}) (*synchronizer, *GErrBlk) {
+	this, ok := obj.(*object.Object)
+	if ok && !object.IsNull(this) {
+		if s, ok := this.FieldTable[fieldNameSyncState].Fvalue.(*synchronizer); ok {
+			return s, nil
+		}
+	}
+	errMsg := fmt.Sprintf("%s: the object has no synchronizer", funcName)
+	return nil, getGErrBlk(excNames.IllegalStateException, errMsg)
+}
+
+// lockDescription names a lock for the deadlock detector, as in "java.util.concurrent.locks.ReentrantLock@1b6d3586"
+func lockDescription(obj interface{}) string {
+	this := obj.(*object.Object)
+	className := strings.ReplaceAll(*stringPool.GetStringPointer(this.KlassName), "/", ".")
+	return fmt.Sprintf("%s@%x", className, object.IdentityHashCode(this))
+}
+
+func Load_Util_Concurrent_Locks_ReentrantLock() {
+
+	MethodSignatures["java/util/concurrent/locks/ReentrantLock.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/concurrent/locks/ReentrantLock.<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  reentrantLockInit,
+		}
+
+	MethodSignatures["java/util/concurrent/locks/ReentrantLock.<init>(Z)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  reentrantLockInit,
+		}
+
+	MethodSignatures["java/util/concurrent/locks/ReentrantLock.getHoldCount()I"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    reentrantLockGetHoldCount,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/locks/ReentrantLock.isFair()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  reentrantLockIsFair,
+		}
+
+	MethodSignatures["java/util/concurrent/locks/ReentrantLock.isHeldByCurrentThread()Z"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    reentrantLockIsHeldByCurrentThread,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/locks/ReentrantLock.isLocked()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  reentrantLockIsLocked,
+		}
+
+	loadLockMethods(classNameReentrantLock)
+
+	MethodSignatures[classNameConditionObject+".await()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    conditionAwait,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[classNameConditionObject+".await(JLjava/util/concurrent/TimeUnit;)Z"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    conditionAwaitTimed,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[classNameConditionObject+".awaitNanos(J)J"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    conditionAwaitNanos,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[classNameConditionObject+".awaitUninterruptibly()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    conditionAwaitUninterruptibly,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[classNameConditionObject+".signal()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    conditionSignal,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[classNameConditionObject+".signalAll()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    conditionSignalAll,
+			NeedsContext: true,
+		}
+}
+
+// loadLockMethods registers the methods of the Lock interface for a class of exclusive
+// locks: ReentrantLock and the write lock of a ReentrantReadWriteLock
+func loadLockMethods(className string) {
+
+	MethodSignatures[className+".lock()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    reentrantLockLock,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[className+".lockInterruptibly()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    reentrantLockLockInterruptibly,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[className+".newCondition()Ljava/util/concurrent/locks/Condition;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  reentrantLockNewCondition,
+		}
+
+	MethodSignatures[className+".tryLock()Z"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    reentrantLockTryLock,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[className+".tryLock(JLjava/util/concurrent/TimeUnit;)Z"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    reentrantLockTryLockTimed,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[className+".unlock()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    reentrantLockUnlock,
+			NeedsContext: true,
+		}
+}
+
+// "java/util/concurrent/locks/ReentrantLock.<init>()V" and <init>(boolean fair)
+func reentrantLockInit(params []interface{}) interface{} {
+	this := params[0].(*object.Object)
+	fair := len(params) > 1 && params[1].(int64) == types.JavaBoolTrue
+	this.FieldTable[fieldNameSyncState] = object.Field{Ftype: types.SyncState, Fvalue: newSynchronizer(fair)}
+	return nil
+}
+
+// "java/util/concurrent/locks/ReentrantLock.lock()V" -- an interrupt doesn't end the wait
+func reentrantLockLock(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	s, gerr := getSynchronizer("reentrantLockLock", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	threadID := currentThreadID(fs)
+	try := func() bool { return s.acquireExclusive(threadID) }
+	s.await(fs, try, false, 0, false, lockDescription(params[1]))
+	return nil
+}
+
+// "java/util/concurrent/locks/ReentrantLock.lockInterruptibly()V"
+func reentrantLockLockInterruptibly(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	s, gerr := getSynchronizer("reentrantLockLockInterruptibly", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	threadID := currentThreadID(fs)
+	try := func() bool { return s.acquireExclusive(threadID) }
+	if _, interrupted := s.await(fs, try, false, 0, true, lockDescription(params[1])); interrupted {
+		return getGErrBlk(excNames.InterruptedException, "reentrantLockLockInterruptibly: interrupted")
+	}
+	return nil
+}
+
+// "java/util/concurrent/locks/ReentrantLock.tryLock()Z" -- takes the lock only if it's free now
+func reentrantLockTryLock(params []interface{}) interface{} {
+	s, gerr := getSynchronizer("reentrantLockTryLock", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return object.JavaBooleanFromGoBoolean(s.acquireExclusive(currentThreadID(params[0].(*list.List))))
+}
+
+// "java/util/concurrent/locks/ReentrantLock.tryLock(JLjava/util/concurrent/TimeUnit;)Z"
+func reentrantLockTryLockTimed(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	s, gerr := getSynchronizer("reentrantLockTryLockTimed", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	timeout, gerr := timeUnitDuration("reentrantLockTryLockTimed", params[2].(int64), params[3])
+	if gerr != nil {
+		return gerr
+	}
+	threadID := currentThreadID(fs)
+	try := func() bool { return s.acquireExclusive(threadID) }
+	acquired, interrupted := s.await(fs, try, true, timeout, true, lockDescription(params[1]))
+	if interrupted {
+		return getGErrBlk(excNames.InterruptedException, "reentrantLockTryLockTimed: interrupted")
+	}
+	return object.JavaBooleanFromGoBoolean(acquired)
+}
+
+// "java/util/concurrent/locks/ReentrantLock.unlock()V"
+func reentrantLockUnlock(params []interface{}) interface{} {
+	s, gerr := getSynchronizer("reentrantLockUnlock", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.holds == 0 || s.owner != currentThreadID(params[0].(*list.List)) {
+		errMsg := "reentrantLockUnlock: the current thread does not hold the lock"
+		return getGErrBlk(excNames.IllegalMonitorStateException, errMsg)
+	}
+	s.holds--
+	if s.holds == 0 {
+		s.notify()
+	}
+	return nil
+}
+
+// "java/util/concurrent/locks/ReentrantLock.getHoldCount()I"
+func reentrantLockGetHoldCount(params []interface{}) interface{} {
+	s, gerr := getSynchronizer("reentrantLockGetHoldCount", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.holds > 0 && s.owner == currentThreadID(params[0].(*list.List)) {
+		return int64(s.holds)
+	}
+	return int64(0)
+}
+
+// "java/util/concurrent/locks/ReentrantLock.isFair()Z"
+func reentrantLockIsFair(params []interface{}) interface{} {
+	s, gerr := getSynchronizer("reentrantLockIsFair", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	return object.JavaBooleanFromGoBoolean(s.fair)
+}
+
+// "java/util/concurrent/locks/ReentrantLock.isHeldByCurrentThread()Z"
+func reentrantLockIsHeldByCurrentThread(params []interface{}) interface{} {
+	if reentrantLockGetHoldCount(params) == int64(0) {
+		return types.JavaBoolFalse
+	}
+	return types.JavaBoolTrue
+}
+
+// "java/util/concurrent/locks/ReentrantLock.isLocked()Z"
+func reentrantLockIsLocked(params []interface{}) interface{} {
+	s, gerr := getSynchronizer("reentrantLockIsLocked", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return object.JavaBooleanFromGoBoolean(s.holds > 0)
+}
+
+// lockCondition is the Go state of a Condition of a lock
+type lockCondition struct {
+	lock    *synchronizer
+	waiters []chan struct{} // guarded by the lock's mutex; a waiter's channel is closed to signal it
+}
+
+// "java/util/concurrent/locks/ReentrantLock.newCondition()Ljava/util/concurrent/locks/Condition;"
+func reentrantLockNewCondition(params []interface{}) interface{} {
+	s, gerr := getSynchronizer("reentrantLockNewCondition", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	condition := object.MakeEmptyObjectWithClassName(&classNameConditionObject)
+	condition.FieldTable[fieldNameSyncState] = object.Field{Ftype: types.SyncState, Fvalue: &lockCondition{lock: s}}
+	return condition
+}
+
+// conditionWait releases the lock, waits to be signalled, and takes the lock back as many
+// times as it was held, as Condition.await() and its variants do. A timeout of zero waits
+// until a signal. It returns whether the wait ended with a signal and whether it was
+// interrupted.
+func conditionWait(funcName string, params []interface{}, timeout time.Duration, interruptible bool) (signalled, interrupted bool, gerr *GErrBlk) {
+	fs := params[0].(*list.List)
+	c, ok := params[1].(*object.Object).FieldTable[fieldNameSyncState].Fvalue.(*lockCondition)
+	if !ok {
+		return false, false, getGErrBlk(excNames.IllegalStateException, funcName+": the object is not a Condition")
+	}
+	s := c.lock
+	threadID := currentThreadID(fs)
+
+	s.mutex.Lock()
+	if s.holds == 0 || s.owner != threadID {
+		s.mutex.Unlock()
+		errMsg := funcName + ": the current thread does not hold the lock"
+		return false, false, getGErrBlk(excNames.IllegalMonitorStateException, errMsg)
+	}
+	holds := s.holds
+	s.holds = 0
+	s.notify()
+	signal := make(chan struct{})
+	c.waiters = append(c.waiters, signal)
+	s.mutex.Unlock()
+
+	if interruptible {
+		signalled, interrupted = waitForChannel(fs, signal, timeout)
+	} else {
+		signalled, _ = waitForChannel(nil, signal, timeout)
+	}
+
+	// if not signalled, the thread is still among the waiters
+	s.mutex.Lock()
+	for i, w := range c.waiters {
+		if w == signal {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			break
+		}
+	}
+	s.mutex.Unlock()
+
+	try := func() bool {
+		if s.acquireExclusive(threadID) {
+			s.holds = holds
+			return true
+		}
+		return false
+	}
+	s.await(fs, try, false, 0, false, "")
+	return signalled, interrupted, nil
+}
+
+// "java/util/concurrent/locks/AbstractQueuedSynchronizer$ConditionObject.await()V"
+func conditionAwait(params []interface{}) interface{} {
+	_, interrupted, gerr := conditionWait("conditionAwait", params, 0, true)
+	if gerr != nil {
+		return gerr
+	}
+	if interrupted {
+		return getGErrBlk(excNames.InterruptedException, "conditionAwait: interrupted")
+	}
+	return nil
+}
+
+// "java/util/concurrent/locks/AbstractQueuedSynchronizer$ConditionObject.await(JLjava/util/concurrent/TimeUnit;)Z"
+// -- returns false if the time ran out before a signal
+func conditionAwaitTimed(params []interface{}) interface{} {
+	timeout, gerr := timeUnitDuration("conditionAwaitTimed", params[2].(int64), params[3])
+	if gerr != nil {
+		return gerr
+	}
+	timeout = max(timeout, time.Nanosecond) // a timeout of zero or less doesn't wait for a signal
+	signalled, interrupted, gerr := conditionWait("conditionAwaitTimed", params, timeout, true)
+	if gerr != nil {
+		return gerr
+	}
+	if interrupted {
+		return getGErrBlk(excNames.InterruptedException, "conditionAwaitTimed: interrupted")
+	}
+	return object.JavaBooleanFromGoBoolean(signalled)
+}
+
+// "java/util/concurrent/locks/AbstractQueuedSynchronizer$ConditionObject.awaitNanos(J)J"
+// -- returns an estimate of the nanoseconds left, which is zero or less if the time ran out
+func conditionAwaitNanos(params []interface{}) interface{} {
+	timeout := max(time.Duration(params[2].(int64)), time.Nanosecond)
+	deadline := time.Now().Add(timeout)
+	_, interrupted, gerr := conditionWait("conditionAwaitNanos", params, timeout, true)
+	if gerr != nil {
+		return gerr
+	}
+	if interrupted {
+		return getGErrBlk(excNames.InterruptedException, "conditionAwaitNanos: interrupted")
+	}
+	return int64(time.Until(deadline))
+}
+
+// "java/util/concurrent/locks/AbstractQueuedSynchronizer$ConditionObject.awaitUninterruptibly()V"
+func conditionAwaitUninterruptibly(params []interface{}) interface{} {
+	if _, _, gerr := conditionWait("conditionAwaitUninterruptibly", params, 0, false); gerr != nil {
+		return gerr
+	}
+	return nil
+}
+
+// "java/util/concurrent/locks/AbstractQueuedSynchronizer$ConditionObject.signal()V"
+func conditionSignal(params []interface{}) interface{} {
+	return conditionSignalWaiters("conditionSignal", params, false)
+}
+
+// "java/util/concurrent/locks/AbstractQueuedSynchronizer$ConditionObject.signalAll()V"
+func conditionSignalAll(params []interface{}) interface{} {
+	return conditionSignalWaiters("conditionSignalAll", params, true)
+}
+
+// conditionSignalWaiters wakes the longest-waiting thread, or all of them
+func conditionSignalWaiters(funcName string, params []interface{}, all bool) interface{} {
+	c, ok := params[1].(*object.Object).FieldTable[fieldNameSyncState].Fvalue.(*lockCondition)
+	if !ok {
+		return getGErrBlk(excNames.IllegalStateException, funcName+": the object is not a Condition")
+	}
+	s := c.lock
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.holds == 0 || s.owner != currentThreadID(params[0].(*list.List)) {
+		errMsg := funcName + ": the current thread does not hold the lock"
+		return getGErrBlk(excNames.IllegalMonitorStateException, errMsg)
+	}
+	for len(c.waiters) > 0 {
+		close(c.waiters[0])
+		c.waiters = c.waiters[1:]
+		if !all {
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"testing"
+	"time"
+)
+
+func newReentrantLock() *object.Object {
+	lock := object.MakeEmptyObjectWithClassName(&classNameReentrantLock)
+	reentrantLockInit([]interface{}{lock})
+	return lock
+}
+
+func TestReentrantLockReentrancy(t *testing.T) {
+	globals.InitGlobals("test")
+	_, fs := addTestExecThread()
+	_, otherFs := addTestExecThread()
+	lock := newReentrantLock()
+
+	reentrantLockLock([]interface{}{fs, lock})
+	reentrantLockLock([]interface{}{fs, lock})
+	if holds := reentrantLockGetHoldCount([]interface{}{fs, lock}); holds != int64(2) {
+		t.Errorf("getHoldCount() = %v, want 2", holds)
+	}
+	if reentrantLockIsHeldByCurrentThread([]interface{}{otherFs, lock}) != types.JavaBoolFalse {
+		t.Errorf("isHeldByCurrentThread() is true on a thread that doesn't hold the lock")
+	}
+	if reentrantLockTryLock([]interface{}{otherFs, lock}) != types.JavaBoolFalse {
+		t.Errorf("tryLock() took a lock that another thread holds")
+	}
+
+	ret := reentrantLockUnlock([]interface{}{otherFs, lock})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalMonitorStateException {
+		t.Errorf("unlock() by a thread that doesn't hold the lock: expected IllegalMonitorStateException, got %v", ret)
+	}
+
+	reentrantLockUnlock([]interface{}{fs, lock})
+	if reentrantLockIsLocked([]interface{}{lock}) != types.JavaBoolTrue {
+		t.Errorf("the lock was released while it was still held once")
+	}
+	reentrantLockUnlock([]interface{}{fs, lock})
+	if reentrantLockTryLock([]interface{}{otherFs, lock}) != types.JavaBoolTrue {
+		t.Errorf("tryLock() failed on a released lock")
+	}
+}
+
+func TestReentrantLockTimeoutAndInterrupt(t *testing.T) {
+	globals.InitGlobals("test")
+	_, fs := addTestExecThread()
+	other, otherFs := addTestExecThread()
+	lock := newReentrantLock()
+	reentrantLockLock([]interface{}{fs, lock})
+
+	start := time.Now()
+	ret := reentrantLockTryLockTimed([]interface{}{otherFs, lock, int64(30), timeUnitObject("MILLISECONDS")})
+	if ret != types.JavaBoolFalse {
+		t.Errorf("tryLock(30ms) took a held lock")
+	}
+	if time.Since(start) < 30*time.Millisecond {
+		t.Errorf("tryLock(30ms) returned after %v", time.Since(start))
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		other.Interrupt()
+	}()
+	ret = reentrantLockLockInterruptibly([]interface{}{otherFs, lock})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.InterruptedException {
+		t.Errorf("lockInterruptibly(): expected InterruptedException, got %v", ret)
+	}
+	if other.IsInterrupted() {
+		t.Errorf("the interrupt status was not cleared by the exception")
+	}
+
+	// a waiting thread gets the lock when it's released
+	done := make(chan interface{})
+	go func() {
+		done <- reentrantLockTryLockTimed([]interface{}{otherFs, lock, int64(5), timeUnitObject("SECONDS")})
+	}()
+	time.Sleep(20 * time.Millisecond)
+	reentrantLockUnlock([]interface{}{fs, lock})
+	if ret := <-done; ret != types.JavaBoolTrue {
+		t.Errorf("tryLock(5s) did not take the lock when it was released")
+	}
+}
+
+func TestReentrantLockCondition(t *testing.T) {
+	globals.InitGlobals("test")
+	_, fs := addTestExecThread()
+	_, otherFs := addTestExecThread()
+	lock := newReentrantLock()
+	condition := reentrantLockNewCondition([]interface{}{lock}).(*object.Object)
+
+	ret := conditionSignal([]interface{}{fs, condition})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalMonitorStateException {
+		t.Errorf("signal() without the lock: expected IllegalMonitorStateException, got %v", ret)
+	}
+
+	reentrantLockLock([]interface{}{fs, lock})
+	reentrantLockLock([]interface{}{fs, lock})
+	if ret := conditionAwaitTimed([]interface{}{fs, condition, int64(20), timeUnitObject("MILLISECONDS")}); ret != types.JavaBoolFalse {
+		t.Errorf("await(20ms) with no signal returned %v", ret)
+	}
+
+	done := make(chan interface{})
+	go func() { done <- conditionAwait([]interface{}{fs, condition}) }()
+	time.Sleep(20 * time.Millisecond)
+
+	// the waiting thread released the lock, so another thread can take it and signal
+	if reentrantLockTryLock([]interface{}{otherFs, lock}) != types.JavaBoolTrue {
+		t.Fatalf("await() did not release the lock")
+	}
+	conditionSignal([]interface{}{otherFs, condition})
+	reentrantLockUnlock([]interface{}{otherFs, lock})
+
+	select {
+	case ret := <-done:
+		if ret != nil {
+			t.Errorf("await() returned %v", ret)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("await() did not return after a signal")
+	}
+	if holds := reentrantLockGetHoldCount([]interface{}{fs, lock}); holds != int64(2) {
+		t.Errorf("after await(), getHoldCount() = %v, want 2", holds)
+	}
+}
@@ -0,0 +1,292 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/types"
+)
+
+// Implementation of java/util/concurrent/locks/ReentrantReadWriteLock, on the synchronizer in
+// javaUtilConcurrentLocksReentrantLock.go. The read lock and the write lock are objects that
+// share the synchronizer of the ReentrantReadWriteLock. The write lock is an exclusive lock
+// that can't be taken while any thread holds the read lock, so it has the methods of a
+// ReentrantLock; the read lock can be taken by any number of threads while no other thread
+// holds the write lock. As in the JDK, a thread that holds the write lock can take the read
+// lock (downgrading), but a thread that holds only the read lock waits forever for the write
+// lock. Writers are not given precedence over readers.
+
+var classNameReadLock = "java/util/concurrent/locks/ReentrantReadWriteLock$ReadLock"
+var classNameWriteLock = "java/util/concurrent/locks/ReentrantReadWriteLock$WriteLock"
+
+func Load_Util_Concurrent_Locks_ReentrantReadWriteLock() {
+
+	MethodSignatures["java/util/concurrent/locks/ReentrantReadWriteLock.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/concurrent/locks/ReentrantReadWriteLock.<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  readWriteLockInit,
+		}
+
+	MethodSignatures["java/util/concurrent/locks/ReentrantReadWriteLock.<init>(Z)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  readWriteLockInit,
+		}
+
+	MethodSignatures["java/util/concurrent/locks/ReentrantReadWriteLock.getReadHoldCount()I"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    readWriteLockGetReadHoldCount,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/locks/ReentrantReadWriteLock.getReadLockCount()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  readWriteLockGetReadLockCount,
+		}
+
+	MethodSignatures["java/util/concurrent/locks/ReentrantReadWriteLock.getWriteHoldCount()I"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    reentrantLockGetHoldCount,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/locks/ReentrantReadWriteLock.isFair()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  reentrantLockIsFair,
+		}
+
+	MethodSignatures["java/util/concurrent/locks/ReentrantReadWriteLock.isWriteLocked()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  reentrantLockIsLocked,
+		}
+
+	MethodSignatures["java/util/concurrent/locks/ReentrantReadWriteLock.isWriteLockedByCurrentThread()Z"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    reentrantLockIsHeldByCurrentThread,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/locks/ReentrantReadWriteLock.readLock()Ljava/util/concurrent/locks/ReentrantReadWriteLock$ReadLock;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  readWriteLockReadLock,
+		}
+
+	MethodSignatures["java/util/concurrent/locks/ReentrantReadWriteLock.writeLock()Ljava/util/concurrent/locks/ReentrantReadWriteLock$WriteLock;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  readWriteLockWriteLock,
+		}
+
+	// the read lock
+
+	MethodSignatures[classNameReadLock+".lock()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    readLockLock,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[classNameReadLock+".lockInterruptibly()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    readLockLockInterruptibly,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[classNameReadLock+".tryLock()Z"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    readLockTryLock,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[classNameReadLock+".tryLock(JLjava/util/concurrent/TimeUnit;)Z"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    readLockTryLockTimed,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[classNameReadLock+".unlock()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    readLockUnlock,
+			NeedsContext: true,
+		}
+
+	// the write lock
+
+	loadLockMethods(classNameWriteLock)
+
+	MethodSignatures[classNameWriteLock+".getHoldCount()I"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    reentrantLockGetHoldCount,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[classNameWriteLock+".isHeldByCurrentThread()Z"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    reentrantLockIsHeldByCurrentThread,
+			NeedsContext: true,
+		}
+}
+
+// "java/util/concurrent/locks/ReentrantReadWriteLock.<init>()V" and <init>(boolean fair) --
+// the read and write locks are made here, so that the same objects are returned each time
+func readWriteLockInit(params []interface{}) interface{} {
+	reentrantLockInit(params)
+	this := params[0].(*object.Object)
+	for _, className := range []string{classNameReadLock, classNameWriteLock} {
+		view := object.MakeEmptyObjectWithClassName(&className)
+		view.FieldTable[fieldNameSyncState] = this.FieldTable[fieldNameSyncState]
+		this.FieldTable[className] = object.Field{Ftype: types.Ref, Fvalue: view}
+	}
+	return nil
+}
+
+// "java/util/concurrent/locks/ReentrantReadWriteLock.readLock()Ljava/util/concurrent/locks/ReentrantReadWriteLock$ReadLock;"
+func readWriteLockReadLock(params []interface{}) interface{} {
+	return params[0].(*object.Object).FieldTable[classNameReadLock].Fvalue
+}
+
+// "java/util/concurrent/locks/ReentrantReadWriteLock.writeLock()Ljava/util/concurrent/locks/ReentrantReadWriteLock$WriteLock;"
+func readWriteLockWriteLock(params []interface{}) interface{} {
+	return params[0].(*object.Object).FieldTable[classNameWriteLock].Fvalue
+}
+
+// acquireShared takes the read lock for the thread unless another thread holds the write
+// lock. Call with the mutex held.
+func (s *synchronizer) acquireShared(threadID int) bool {
+	if s.holds > 0 && s.owner != threadID {
+		return false
+	}
+	s.readers[threadID]++
+	return true
+}
+
+// "java/util/concurrent/locks/ReentrantReadWriteLock$ReadLock.lock()V"
+func readLockLock(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	s, gerr := getSynchronizer("readLockLock", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	threadID := currentThreadID(fs)
+	try := func() bool { return s.acquireShared(threadID) }
+	s.await(fs, try, false, 0, false, lockDescription(params[1]))
+	return nil
+}
+
+// "java/util/concurrent/locks/ReentrantReadWriteLock$ReadLock.lockInterruptibly()V"
+func readLockLockInterruptibly(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	s, gerr := getSynchronizer("readLockLockInterruptibly", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	threadID := currentThreadID(fs)
+	try := func() bool { return s.acquireShared(threadID) }
+	if _, interrupted := s.await(fs, try, false, 0, true, lockDescription(params[1])); interrupted {
+		return getGErrBlk(excNames.InterruptedException, "readLockLockInterruptibly: interrupted")
+	}
+	return nil
+}
+
+// "java/util/concurrent/locks/ReentrantReadWriteLock$ReadLock.tryLock()Z"
+func readLockTryLock(params []interface{}) interface{} {
+	s, gerr := getSynchronizer("readLockTryLock", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return object.JavaBooleanFromGoBoolean(s.acquireShared(currentThreadID(params[0].(*list.List))))
+}
+
+// "java/util/concurrent/locks/ReentrantReadWriteLock$ReadLock.tryLock(JLjava/util/concurrent/TimeUnit;)Z"
+func readLockTryLockTimed(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	s, gerr := getSynchronizer("readLockTryLockTimed", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	timeout, gerr := timeUnitDuration("readLockTryLockTimed", params[2].(int64), params[3])
+	if gerr != nil {
+		return gerr
+	}
+	threadID := currentThreadID(fs)
+	try := func() bool { return s.acquireShared(threadID) }
+	acquired, interrupted := s.await(fs, try, true, timeout, true, lockDescription(params[1]))
+	if interrupted {
+		return getGErrBlk(excNames.InterruptedException, "readLockTryLockTimed: interrupted")
+	}
+	return object.JavaBooleanFromGoBoolean(acquired)
+}
+
+// "java/util/concurrent/locks/ReentrantReadWriteLock$ReadLock.unlock()V"
+func readLockUnlock(params []interface{}) interface{} {
+	s, gerr := getSynchronizer("readLockUnlock", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	threadID := currentThreadID(params[0].(*list.List))
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.readers[threadID] == 0 {
+		errMsg := "readLockUnlock: the current thread does not hold the read lock"
+		return getGErrBlk(excNames.IllegalMonitorStateException, errMsg)
+	}
+	s.readers[threadID]--
+	if s.readers[threadID] == 0 {
+		delete(s.readers, threadID)
+		s.notify()
+	}
+	return nil
+}
+
+// "java/util/concurrent/locks/ReentrantReadWriteLock.getReadHoldCount()I" -- the current thread's holds of the read lock
+func readWriteLockGetReadHoldCount(params []interface{}) interface{} {
+	s, gerr := getSynchronizer("readWriteLockGetReadHoldCount", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return int64(s.readers[currentThreadID(params[0].(*list.List))])
+}
+
+// "java/util/concurrent/locks/ReentrantReadWriteLock.getReadLockCount()I" -- all threads' holds of the read lock
+func readWriteLockGetReadLockCount(params []interface{}) interface{} {
+	s, gerr := getSynchronizer("readWriteLockGetReadLockCount", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var count int64
+	for _, holds := range s.readers {
+		count += int64(holds)
+	}
+	return count
+}
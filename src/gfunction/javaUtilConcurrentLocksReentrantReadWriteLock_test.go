@@ -0,0 +1,68 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"testing"
+	"time"
+)
+
+func TestReadWriteLock(t *testing.T) {
+	globals.InitGlobals("test")
+	_, fs := addTestExecThread()
+	_, otherFs := addTestExecThread()
+	className := "java/util/concurrent/locks/ReentrantReadWriteLock"
+	rwLock := object.MakeEmptyObjectWithClassName(&className)
+	readWriteLockInit([]interface{}{rwLock})
+	readLock := readWriteLockReadLock([]interface{}{rwLock}).(*object.Object)
+	writeLock := readWriteLockWriteLock([]interface{}{rwLock}).(*object.Object)
+	if readWriteLockReadLock([]interface{}{rwLock}) != readLock {
+		t.Errorf("readLock() returned a different object the second time")
+	}
+
+	// readers share the lock, and keep out a writer
+	readLockLock([]interface{}{fs, readLock})
+	if readLockTryLock([]interface{}{otherFs, readLock}) != types.JavaBoolTrue {
+		t.Errorf("a second reader could not take the read lock")
+	}
+	if count := readWriteLockGetReadLockCount([]interface{}{rwLock}); count != int64(2) {
+		t.Errorf("getReadLockCount() = %v, want 2", count)
+	}
+	if reentrantLockTryLock([]interface{}{otherFs, writeLock}) != types.JavaBoolFalse {
+		t.Errorf("the write lock was taken while there were readers")
+	}
+
+	done := make(chan interface{})
+	go func() {
+		done <- reentrantLockTryLockTimed([]interface{}{otherFs, writeLock, int64(5), timeUnitObject("SECONDS")})
+	}()
+	time.Sleep(20 * time.Millisecond)
+	readLockUnlock([]interface{}{otherFs, readLock})
+	readLockUnlock([]interface{}{fs, readLock})
+	if ret := <-done; ret != types.JavaBoolTrue {
+		t.Fatalf("the writer did not get the lock when the readers left")
+	}
+
+	// a writer keeps out readers, but can take the read lock itself
+	if readLockTryLock([]interface{}{fs, readLock}) != types.JavaBoolFalse {
+		t.Errorf("the read lock was taken while another thread held the write lock")
+	}
+	if readLockTryLock([]interface{}{otherFs, readLock}) != types.JavaBoolTrue {
+		t.Errorf("the writer could not take the read lock")
+	}
+	if readWriteLockGetReadHoldCount([]interface{}{otherFs, rwLock}) != int64(1) ||
+		reentrantLockIsLocked([]interface{}{rwLock}) != types.JavaBoolTrue {
+		t.Errorf("unexpected hold counts for a writer that took the read lock")
+	}
+	reentrantLockUnlock([]interface{}{otherFs, writeLock})
+	if readLockTryLock([]interface{}{fs, readLock}) != types.JavaBoolTrue {
+		t.Errorf("the read lock could not be taken after the writer unlocked")
+	}
+}
@@ -0,0 +1,187 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"math"
+	"time"
+)
+
+// Implementation of a subset of java/util/concurrent/ScheduledThreadPoolExecutor, built on
+// the scheduler in javaUtilTimer.go: schedule(), scheduleAtFixedRate() and
+// scheduleWithFixedDelay(), and the ScheduledFuture that they return. The methods that it
+// inherits from ThreadPoolExecutor are in javaUtilConcurrentThreadPoolExecutor.go.
+
+var classNameScheduledThreadPoolExecutor = "java/util/concurrent/ScheduledThreadPoolExecutor"
+var classNameScheduledFutureTask = "java/util/concurrent/ScheduledThreadPoolExecutor$ScheduledFutureTask"
+
+func Load_Util_Concurrent_ScheduledThreadPoolExecutor() {
+
+	MethodSignatures["java/util/concurrent/Executors.newScheduledThreadPool(I)Ljava/util/concurrent/ScheduledExecutorService;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  executorsNewScheduledThreadPool,
+		}
+
+	MethodSignatures["java/util/concurrent/Executors.newSingleThreadScheduledExecutor()Ljava/util/concurrent/ScheduledExecutorService;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  executorsNewSingleThreadScheduledExecutor,
+		}
+
+	MethodSignatures["java/util/concurrent/ScheduledThreadPoolExecutor.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/concurrent/ScheduledThreadPoolExecutor.<init>(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  scheduledExecutorInit,
+		}
+
+	MethodSignatures["java/util/concurrent/ScheduledThreadPoolExecutor.schedule(Ljava/lang/Runnable;JLjava/util/concurrent/TimeUnit;)Ljava/util/concurrent/ScheduledFuture;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  scheduledExecutorSchedule,
+		}
+
+	MethodSignatures["java/util/concurrent/ScheduledThreadPoolExecutor.schedule(Ljava/util/concurrent/Callable;JLjava/util/concurrent/TimeUnit;)Ljava/util/concurrent/ScheduledFuture;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  scheduledExecutorScheduleCallable,
+		}
+
+	MethodSignatures["java/util/concurrent/ScheduledThreadPoolExecutor.scheduleAtFixedRate(Ljava/lang/Runnable;JJLjava/util/concurrent/TimeUnit;)Ljava/util/concurrent/ScheduledFuture;"] =
+		GMeth{
+			ParamSlots: 4,
+			GFunction:  scheduledExecutorScheduleAtFixedRate,
+		}
+
+	MethodSignatures["java/util/concurrent/ScheduledThreadPoolExecutor.scheduleWithFixedDelay(Ljava/lang/Runnable;JJLjava/util/concurrent/TimeUnit;)Ljava/util/concurrent/ScheduledFuture;"] =
+		GMeth{
+			ParamSlots: 4,
+			GFunction:  scheduledExecutorScheduleWithFixedDelay,
+		}
+
+	loadExecutorMethods(classNameScheduledThreadPoolExecutor)
+
+	// the futures returned by the methods above
+	loadFutureMethods(classNameScheduledFutureTask)
+
+	MethodSignatures[classNameScheduledFutureTask+".getDelay(Ljava/util/concurrent/TimeUnit;)J"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  scheduledFutureGetDelay,
+		}
+}
+
+// "java/util/concurrent/Executors.newScheduledThreadPool(I)Ljava/util/concurrent/ScheduledExecutorService;"
+func executorsNewScheduledThreadPool(params []interface{}) interface{} {
+	executor := object.MakeEmptyObjectWithClassName(&classNameScheduledThreadPoolExecutor)
+	if ret := scheduledExecutorInit([]interface{}{executor, params[0]}); ret != nil {
+		return ret
+	}
+	return executor
+}
+
+// "java/util/concurrent/Executors.newSingleThreadScheduledExecutor()Ljava/util/concurrent/ScheduledExecutorService;"
+func executorsNewSingleThreadScheduledExecutor([]interface{}) interface{} {
+	return executorsNewScheduledThreadPool([]interface{}{int64(1)})
+}
+
+// "java/util/concurrent/ScheduledThreadPoolExecutor.<init>(I)V" -- a pool size of zero runs one task at a time
+func scheduledExecutorInit(params []interface{}) interface{} {
+	this := params[0].(*object.Object)
+	poolSize := params[1].(int64)
+	if poolSize < 0 || poolSize > math.MaxInt32 {
+		errMsg := fmt.Sprintf("scheduledExecutorInit: invalid pool size %d", poolSize)
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	executor := newExecutor(classNameScheduledThreadPoolExecutor, int(max(poolSize, 1)))
+	this.FieldTable[fieldNameSchedulerState] = executor.FieldTable[fieldNameSchedulerState]
+	return nil
+}
+
+// "java/util/concurrent/ScheduledThreadPoolExecutor.schedule(Ljava/lang/Runnable;JLjava/util/concurrent/TimeUnit;)Ljava/util/concurrent/ScheduledFuture;"
+func scheduledExecutorSchedule(params []interface{}) interface{} {
+	runnable, gerr := taskObject("scheduledExecutorSchedule", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	delay, gerr := timeUnitDuration("scheduledExecutorSchedule", params[2].(int64), params[3])
+	if gerr != nil {
+		return gerr
+	}
+	return scheduleOnExecutor("scheduledExecutorSchedule", params[0], runnableTask(runnable, false), nil,
+		classNameScheduledFutureTask, delay, 0, false)
+}
+
+// "java/util/concurrent/ScheduledThreadPoolExecutor.schedule(Ljava/util/concurrent/Callable;JLjava/util/concurrent/TimeUnit;)Ljava/util/concurrent/ScheduledFuture;"
+func scheduledExecutorScheduleCallable(params []interface{}) interface{} {
+	callable, gerr := taskObject("scheduledExecutorScheduleCallable", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	delay, gerr := timeUnitDuration("scheduledExecutorScheduleCallable", params[2].(int64), params[3])
+	if gerr != nil {
+		return gerr
+	}
+	return scheduleOnExecutor("scheduledExecutorScheduleCallable", params[0], callableTask(callable), nil,
+		classNameScheduledFutureTask, delay, 0, false)
+}
+
+// "java/util/concurrent/ScheduledThreadPoolExecutor.scheduleAtFixedRate(Ljava/lang/Runnable;JJLjava/util/concurrent/TimeUnit;)Ljava/util/concurrent/ScheduledFuture;"
+func scheduledExecutorScheduleAtFixedRate(params []interface{}) interface{} {
+	return schedulePeriodicOnExecutor("scheduledExecutorScheduleAtFixedRate", params, true)
+}
+
+// "java/util/concurrent/ScheduledThreadPoolExecutor.scheduleWithFixedDelay(Ljava/lang/Runnable;JJLjava/util/concurrent/TimeUnit;)Ljava/util/concurrent/ScheduledFuture;"
+func scheduledExecutorScheduleWithFixedDelay(params []interface{}) interface{} {
+	return schedulePeriodicOnExecutor("scheduledExecutorScheduleWithFixedDelay", params, false)
+}
+
+func schedulePeriodicOnExecutor(funcName string, params []interface{}, fixedRate bool) interface{} {
+	runnable, gerr := taskObject(funcName, params[1])
+	if gerr != nil {
+		return gerr
+	}
+	delay, gerr := timeUnitDuration(funcName, params[2].(int64), params[4])
+	if gerr != nil {
+		return gerr
+	}
+	period, gerr := timeUnitDuration(funcName, params[3].(int64), params[4])
+	if gerr != nil {
+		return gerr
+	}
+	if period <= 0 {
+		errMsg := fmt.Sprintf("%s: non-positive period", funcName)
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	return scheduleOnExecutor(funcName, params[0], runnableTask(runnable, false), nil,
+		classNameScheduledFutureTask, delay, period, fixedRate)
+}
+
+// "java/util/concurrent/ScheduledThreadPoolExecutor$ScheduledFutureTask.getDelay(Ljava/util/concurrent/TimeUnit;)J"
+func scheduledFutureGetDelay(params []interface{}) interface{} {
+	state, gerr := getTaskState("scheduledFutureGetDelay", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	per, gerr := timeUnitDuration("scheduledFutureGetDelay", 1, params[1])
+	if gerr != nil {
+		return gerr
+	}
+	state.scheduler.mutex.Lock()
+	next := state.task.nextRun
+	state.scheduler.mutex.Unlock()
+	return int64(time.Until(next) / per)
+}
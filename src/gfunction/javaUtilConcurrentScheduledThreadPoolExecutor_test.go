@@ -0,0 +1,123 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"testing"
+	"time"
+)
+
+// makes a TimeUnit enum constant as the JDK's class would
+func timeUnitObject(name string) *object.Object {
+	unit := newZipObject("java/util/concurrent/TimeUnit")
+	unit.FieldTable["name"] = object.Field{Ftype: types.StringClassRef, Fvalue: object.StringObjectFromGoString(name)}
+	return unit
+}
+
+func TestTimeUnitDuration(t *testing.T) {
+	fakeTaskRuns(t)
+
+	if d, _ := timeUnitDuration("test", 3, timeUnitObject("SECONDS")); d != 3*time.Second {
+		t.Errorf("3 SECONDS = %v", d)
+	}
+	unit := newZipObject("java/util/concurrent/TimeUnit")
+	unit.FieldTable["ordinal"] = object.Field{Ftype: types.Int, Fvalue: int64(2)}
+	if d, _ := timeUnitDuration("test", 5, unit); d != 5*time.Millisecond {
+		t.Errorf("5 of ordinal 2 = %v, want 5ms", d)
+	}
+	if d, _ := timeUnitDuration("test", 1<<62, timeUnitObject("DAYS")); d != time.Duration(1<<63-1) {
+		t.Errorf("a huge number of days did not saturate: %v", d)
+	}
+	if _, gerr := timeUnitDuration("test", 1, object.Null); gerr == nil || gerr.ExceptionType != excNames.NullPointerException {
+		t.Errorf("null TimeUnit: expected NullPointerException, got %v", gerr)
+	}
+}
+
+func TestScheduledExecutorRunsTasks(t *testing.T) {
+	runs := fakeTaskRuns(t)
+	executor := executorsNewScheduledThreadPool([]interface{}{int64(2)}).(*object.Object)
+	millis := timeUnitObject("MILLISECONDS")
+	fs := list.New()
+
+	once := newZipObject("java/lang/Runnable")
+	future := scheduledExecutorSchedule([]interface{}{executor, once, int64(10), millis}).(*object.Object)
+	expectRun(t, runs, once)
+	if ret := futureGet([]interface{}{fs, future}); ret != object.Null {
+		t.Errorf("get() of a finished task = %v, want null", ret)
+	}
+	if futureIsDone([]interface{}{future}) != types.JavaBoolTrue {
+		t.Errorf("isDone() of a finished task is false")
+	}
+
+	periodic := newZipObject("java/lang/Runnable")
+	future = scheduledExecutorScheduleWithFixedDelay([]interface{}{executor, periodic, int64(0), int64(10), millis}).(*object.Object)
+	expectRun(t, runs, periodic)
+	expectRun(t, runs, periodic)
+	ret := futureGet([]interface{}{fs, future, int64(1), millis})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.TimeoutException {
+		t.Errorf("get(1ms) of a periodic task: expected TimeoutException, got %v", ret)
+	}
+	if futureCancel([]interface{}{future, types.JavaBoolFalse}) != types.JavaBoolTrue {
+		t.Errorf("cancel() of a periodic task returned false")
+	}
+	ret = futureGet([]interface{}{fs, future})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.CancellationException {
+		t.Errorf("get() of a cancelled task: expected CancellationException, got %v", ret)
+	}
+	if futureIsCancelled([]interface{}{future}) != types.JavaBoolTrue {
+		t.Errorf("isCancelled() of a cancelled task is false")
+	}
+
+	ret = scheduledExecutorScheduleAtFixedRate([]interface{}{executor, periodic, int64(0), int64(0), millis})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("zero period: expected IllegalArgumentException, got %v", ret)
+	}
+	executorShutdownNow([]interface{}{executor})
+}
+
+func TestScheduledExecutorShutdown(t *testing.T) {
+	runs := fakeTaskRuns(t)
+	executor := executorsNewSingleThreadScheduledExecutor(nil).(*object.Object)
+	millis := timeUnitObject("MILLISECONDS")
+	fs := list.New()
+
+	delayed := newZipObject("java/lang/Runnable")
+	periodic := newZipObject("java/lang/Runnable")
+	scheduledExecutorSchedule([]interface{}{executor, delayed, int64(30), millis})
+	future := scheduledExecutorScheduleAtFixedRate([]interface{}{executor, periodic, int64(1000), int64(1000), millis}).(*object.Object)
+	executorShutdown([]interface{}{executor})
+
+	if executorIsShutdown([]interface{}{executor}) != types.JavaBoolTrue {
+		t.Errorf("isShutdown() is false after shutdown()")
+	}
+	ret := executorExecute([]interface{}{executor, newZipObject("java/lang/Runnable")})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.RejectedExecutionException {
+		t.Errorf("execute() after shutdown(): expected RejectedExecutionException, got %v", ret)
+	}
+
+	// the delayed task still runs, but the periodic one is dropped
+	expectRun(t, runs, delayed)
+	if executorAwaitTermination([]interface{}{fs, executor, int64(2), timeUnitObject("SECONDS")}) != types.JavaBoolTrue {
+		t.Errorf("awaitTermination() timed out")
+	}
+	if executorIsTerminated([]interface{}{executor}) != types.JavaBoolTrue {
+		t.Errorf("isTerminated() is false after the tasks finished")
+	}
+	if futureIsDone([]interface{}{future}) != types.JavaBoolTrue {
+		t.Errorf("the periodic task is not done after shutdown()")
+	}
+	expectNoRun(t, runs)
+
+	ret = scheduledExecutorInit([]interface{}{newZipObject(classNameScheduledThreadPoolExecutor), int64(-1)})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("negative pool size: expected IllegalArgumentException, got %v", ret)
+	}
+}
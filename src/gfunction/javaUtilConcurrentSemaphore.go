@@ -0,0 +1,272 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"math"
+)
+
+// Implementation of java/util/concurrent/Semaphore, on the synchronizer in
+// javaUtilConcurrentLocksReentrantLock.go, whose count is the number of permits available.
+// As in the JDK, the number of permits can be negative, and permits can be released by a
+// thread that never acquired them.
+
+func Load_Util_Concurrent_Semaphore() {
+
+	MethodSignatures["java/util/concurrent/Semaphore.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/concurrent/Semaphore.<init>(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  semaphoreInit,
+		}
+
+	MethodSignatures["java/util/concurrent/Semaphore.<init>(IZ)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  semaphoreInit,
+		}
+
+	MethodSignatures["java/util/concurrent/Semaphore.acquire()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    semaphoreAcquire,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/Semaphore.acquire(I)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    semaphoreAcquire,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/Semaphore.acquireUninterruptibly()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    semaphoreAcquireUninterruptibly,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/Semaphore.acquireUninterruptibly(I)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    semaphoreAcquireUninterruptibly,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/Semaphore.availablePermits()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  semaphoreAvailablePermits,
+		}
+
+	MethodSignatures["java/util/concurrent/Semaphore.drainPermits()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  semaphoreDrainPermits,
+		}
+
+	MethodSignatures["java/util/concurrent/Semaphore.isFair()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  reentrantLockIsFair,
+		}
+
+	MethodSignatures["java/util/concurrent/Semaphore.release()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  semaphoreRelease,
+		}
+
+	MethodSignatures["java/util/concurrent/Semaphore.release(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  semaphoreRelease,
+		}
+
+	MethodSignatures["java/util/concurrent/Semaphore.tryAcquire()Z"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    semaphoreTryAcquire,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/Semaphore.tryAcquire(I)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    semaphoreTryAcquire,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/Semaphore.tryAcquire(IJLjava/util/concurrent/TimeUnit;)Z"] =
+		GMeth{
+			ParamSlots:   3,
+			GFunction:    semaphoreTryAcquire,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/Semaphore.tryAcquire(JLjava/util/concurrent/TimeUnit;)Z"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    semaphoreTryAcquireTimed,
+			NeedsContext: true,
+		}
+}
+
+// "java/util/concurrent/Semaphore.<init>(I)V" and <init>(int permits, boolean fair)
+func semaphoreInit(params []interface{}) interface{} {
+	this := params[0].(*object.Object)
+	fair := len(params) > 2 && params[2].(int64) == types.JavaBoolTrue
+	s := newSynchronizer(fair)
+	s.count = params[1].(int64)
+	this.FieldTable[fieldNameSyncState] = object.Field{Ftype: types.SyncState, Fvalue: s}
+	return nil
+}
+
+// semaphorePermits returns the number of permits in params[index], or 1 if there's no such parameter
+func semaphorePermits(funcName string, params []interface{}, index int) (int64, *GErrBlk) {
+	if len(params) <= index {
+		return 1, nil
+	}
+	permits := params[index].(int64)
+	if permits < 0 {
+		return 0, getGErrBlk(excNames.IllegalArgumentException, fmt.Sprintf("%s: negative permits %d", funcName, permits))
+	}
+	return permits, nil
+}
+
+// takePermits returns a function that takes the permits if there are enough, for synchronizer.await()
+func (s *synchronizer) takePermits(permits int64) func() bool {
+	return func() bool {
+		if s.count < permits {
+			return false
+		}
+		s.count -= permits
+		return true
+	}
+}
+
+// "java/util/concurrent/Semaphore.acquire()V" and acquire(int permits)
+func semaphoreAcquire(params []interface{}) interface{} {
+	s, gerr := getSynchronizer("semaphoreAcquire", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	permits, gerr := semaphorePermits("semaphoreAcquire", params, 2)
+	if gerr != nil {
+		return gerr
+	}
+	if _, interrupted := s.await(params[0].(*list.List), s.takePermits(permits), false, 0, true, ""); interrupted {
+		return getGErrBlk(excNames.InterruptedException, "semaphoreAcquire: interrupted")
+	}
+	return nil
+}
+
+// "java/util/concurrent/Semaphore.acquireUninterruptibly()V" and acquireUninterruptibly(int permits)
+func semaphoreAcquireUninterruptibly(params []interface{}) interface{} {
+	s, gerr := getSynchronizer("semaphoreAcquireUninterruptibly", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	permits, gerr := semaphorePermits("semaphoreAcquireUninterruptibly", params, 2)
+	if gerr != nil {
+		return gerr
+	}
+	s.await(params[0].(*list.List), s.takePermits(permits), false, 0, false, "")
+	return nil
+}
+
+// "java/util/concurrent/Semaphore.tryAcquire()Z", tryAcquire(int permits), and
+// tryAcquire(int permits, long timeout, TimeUnit unit)
+func semaphoreTryAcquire(params []interface{}) interface{} {
+	s, gerr := getSynchronizer("semaphoreTryAcquire", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	permits, gerr := semaphorePermits("semaphoreTryAcquire", params, 2)
+	if gerr != nil {
+		return gerr
+	}
+	if len(params) <= 3 {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		return object.JavaBooleanFromGoBoolean(s.takePermits(permits)())
+	}
+
+	timeout, gerr := timeUnitDuration("semaphoreTryAcquire", params[3].(int64), params[4])
+	if gerr != nil {
+		return gerr
+	}
+	acquired, interrupted := s.await(params[0].(*list.List), s.takePermits(permits), true, timeout, true, "")
+	if interrupted {
+		return getGErrBlk(excNames.InterruptedException, "semaphoreTryAcquire: interrupted")
+	}
+	return object.JavaBooleanFromGoBoolean(acquired)
+}
+
+// "java/util/concurrent/Semaphore.tryAcquire(JLjava/util/concurrent/TimeUnit;)Z"
+func semaphoreTryAcquireTimed(params []interface{}) interface{} {
+	return semaphoreTryAcquire([]interface{}{params[0], params[1], int64(1), params[2], params[3]})
+}
+
+// "java/util/concurrent/Semaphore.release()V" and release(int permits)
+func semaphoreRelease(params []interface{}) interface{} {
+	s, gerr := getSynchronizer("semaphoreRelease", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	permits, gerr := semaphorePermits("semaphoreRelease", params, 1)
+	if gerr != nil {
+		return gerr
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.count > math.MaxInt32-permits {
+		return getGErrBlk(excNames.InternalError, "Maximum permit count exceeded")
+	}
+	s.count += permits
+	s.notify()
+	return nil
+}
+
+// "java/util/concurrent/Semaphore.availablePermits()I"
+func semaphoreAvailablePermits(params []interface{}) interface{} {
+	s, gerr := getSynchronizer("semaphoreAvailablePermits", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.count
+}
+
+// "java/util/concurrent/Semaphore.drainPermits()I" -- takes all the available permits and
+// returns how many there were. If the number of permits is negative, they're set to zero.
+func semaphoreDrainPermits(params []interface{}) interface{} {
+	s, gerr := getSynchronizer("semaphoreDrainPermits", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	drained := s.count
+	s.count = 0
+	if drained < 0 {
+		s.notify()
+	}
+	return drained
+}
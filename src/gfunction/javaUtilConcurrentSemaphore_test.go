@@ -0,0 +1,85 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"testing"
+	"time"
+)
+
+func TestSemaphorePermits(t *testing.T) {
+	globals.InitGlobals("test")
+	th, fs := addTestExecThread()
+	className := "java/util/concurrent/Semaphore"
+	semaphore := object.MakeEmptyObjectWithClassName(&className)
+	semaphoreInit([]interface{}{semaphore, int64(3)})
+
+	semaphoreAcquire([]interface{}{fs, semaphore, int64(2)})
+	if permits := semaphoreAvailablePermits([]interface{}{semaphore}); permits != int64(1) {
+		t.Errorf("availablePermits() = %v, want 1", permits)
+	}
+	if semaphoreTryAcquire([]interface{}{fs, semaphore, int64(2)}) != types.JavaBoolFalse {
+		t.Errorf("tryAcquire(2) succeeded with one permit")
+	}
+	ret := semaphoreTryAcquire([]interface{}{fs, semaphore, int64(2), int64(20), timeUnitObject("MILLISECONDS")})
+	if ret != types.JavaBoolFalse {
+		t.Errorf("tryAcquire(2, 20ms) succeeded with one permit")
+	}
+	ret = semaphoreAcquire([]interface{}{fs, semaphore, int64(-1)})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("acquire(-1): expected IllegalArgumentException, got %v", ret)
+	}
+
+	// a waiting acquire() goes ahead when enough permits are released
+	done := make(chan interface{})
+	go func() {
+		done <- semaphoreTryAcquire([]interface{}{fs, semaphore, int64(3), int64(5), timeUnitObject("SECONDS")})
+	}()
+	semaphoreRelease([]interface{}{semaphore})
+	time.Sleep(20 * time.Millisecond)
+	semaphoreRelease([]interface{}{semaphore})
+	if ret := <-done; ret != types.JavaBoolTrue {
+		t.Errorf("tryAcquire(3, 5s) did not get the released permits")
+	}
+
+	semaphoreRelease([]interface{}{semaphore, int64(4)})
+	if drained := semaphoreDrainPermits([]interface{}{semaphore}); drained != int64(4) {
+		t.Errorf("drainPermits() = %v, want 4", drained)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		th.Interrupt()
+	}()
+	ret = semaphoreAcquire([]interface{}{fs, semaphore})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.InterruptedException {
+		t.Errorf("acquire() interrupted: expected InterruptedException, got %v", ret)
+	}
+}
+
+func TestSemaphoreNegativePermits(t *testing.T) {
+	globals.InitGlobals("test")
+	_, fs := addTestExecThread()
+	className := "java/util/concurrent/Semaphore"
+	semaphore := object.MakeEmptyObjectWithClassName(&className)
+	semaphoreInit([]interface{}{semaphore, int64(-1), types.JavaBoolTrue})
+
+	if semaphoreTryAcquireTimed([]interface{}{fs, semaphore, int64(10), timeUnitObject("MILLISECONDS")}) != types.JavaBoolFalse {
+		t.Errorf("tryAcquire() succeeded with -1 permits")
+	}
+	semaphoreRelease([]interface{}{semaphore, int64(2)})
+	if semaphoreTryAcquire([]interface{}{fs, semaphore}) != types.JavaBoolTrue {
+		t.Errorf("tryAcquire() failed with 1 permit")
+	}
+	if reentrantLockIsFair([]interface{}{semaphore}) != types.JavaBoolTrue {
+		t.Errorf("isFair() is false for a fair semaphore")
+	}
+}
@@ -0,0 +1,533 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// Implementation of the thread pools of java/util/concurrent/Executors, as ThreadPoolExecutor
+// objects, and of the FutureTask objects that their submit() methods return. The methods that
+// ScheduledThreadPoolExecutor inherits are here too.
+// Strategy: an executor is a scheduler (see javaUtilTimer.go) whose number of slots is the
+// pool size. Each task runs on a new VM thread, named pool-N-thread-M as in the JDK, and
+// the tasks that find no free slot wait for one. A cached pool has no limit on the number of
+// tasks that run at once, and so no tasks wait.
+
+var classNameThreadPoolExecutor = "java/util/concurrent/ThreadPoolExecutor"
+var classNameFutureTask = "java/util/concurrent/FutureTask"
+
+// for the names of the pools' threads: pool-1-thread-1, pool-2-thread-1, and so on
+var poolNumber atomic.Int64
+
+// the units of java/util/concurrent/TimeUnit, in the order of their ordinals
+var timeUnits = []struct {
+	name     string
+	duration time.Duration
+}{
+	{"NANOSECONDS", time.Nanosecond},
+	{"MICROSECONDS", time.Microsecond},
+	{"MILLISECONDS", time.Millisecond},
+	{"SECONDS", time.Second},
+	{"MINUTES", time.Minute},
+	{"HOURS", time.Hour},
+	{"DAYS", 24 * time.Hour},
+}
+
+func Load_Util_Concurrent_ThreadPoolExecutor() {
+
+	MethodSignatures["java/util/concurrent/Executors.newCachedThreadPool()Ljava/util/concurrent/ExecutorService;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  executorsNewCachedThreadPool,
+		}
+
+	MethodSignatures["java/util/concurrent/Executors.newFixedThreadPool(I)Ljava/util/concurrent/ExecutorService;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  executorsNewFixedThreadPool,
+		}
+
+	MethodSignatures["java/util/concurrent/Executors.newSingleThreadExecutor()Ljava/util/concurrent/ExecutorService;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  executorsNewSingleThreadExecutor,
+		}
+
+	MethodSignatures["java/util/concurrent/ThreadPoolExecutor.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	loadExecutorMethods(classNameThreadPoolExecutor)
+	loadFutureMethods(classNameFutureTask)
+}
+
+// loadExecutorMethods registers the methods of ExecutorService for an executor class
+func loadExecutorMethods(className string) {
+
+	MethodSignatures[className+".awaitTermination(JLjava/util/concurrent/TimeUnit;)Z"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    executorAwaitTermination,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[className+".close()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    executorClose,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[className+".execute(Ljava/lang/Runnable;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  executorExecute,
+		}
+
+	MethodSignatures[className+".isShutdown()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  executorIsShutdown,
+		}
+
+	MethodSignatures[className+".isTerminated()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  executorIsTerminated,
+		}
+
+	MethodSignatures[className+".shutdown()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  executorShutdown,
+		}
+
+	MethodSignatures[className+".shutdownNow()Ljava/util/List;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  executorShutdownNow,
+		}
+
+	MethodSignatures[className+".submit(Ljava/lang/Runnable;)Ljava/util/concurrent/Future;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  executorSubmit,
+		}
+
+	MethodSignatures[className+".submit(Ljava/lang/Runnable;Ljava/lang/Object;)Ljava/util/concurrent/Future;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  executorSubmit,
+		}
+
+	MethodSignatures[className+".submit(Ljava/util/concurrent/Callable;)Ljava/util/concurrent/Future;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  executorSubmitCallable,
+		}
+}
+
+// loadFutureMethods registers the methods of Future for a class of the futures that executors return
+func loadFutureMethods(className string) {
+
+	MethodSignatures[className+".cancel(Z)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  futureCancel,
+		}
+
+	MethodSignatures[className+".get()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    futureGet,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[className+".get(JLjava/util/concurrent/TimeUnit;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    futureGet,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[className+".isCancelled()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  futureIsCancelled,
+		}
+
+	MethodSignatures[className+".isDone()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  futureIsDone,
+		}
+}
+
+// timeUnitDuration converts an amount of a TimeUnit to a Go duration, saturating at the
+// limits of a duration as the JDK's TimeUnit conversions do
+func timeUnitDuration(funcName string, amount int64, unitObj interface{}) (time.Duration, *GErrBlk) {
+	unit, ok := unitObj.(*object.Object)
+	if !ok || object.IsNull(unit) {
+		return 0, getGErrBlk(excNames.NullPointerException, funcName+": null TimeUnit")
+	}
+
+	index := -1
+	name := ""
+	if nameObj, ok := unit.FieldTable["name"].Fvalue.(*object.Object); ok && !object.IsNull(nameObj) {
+		name = object.GoStringFromStringObject(nameObj)
+	} else if object.IsStringObject(unit) {
+		name = object.GoStringFromStringObject(unit)
+	}
+	for i, u := range timeUnits {
+		if u.name == name {
+			index = i
+		}
+	}
+	if index < 0 {
+		if ordinal, ok := unit.FieldTable["ordinal"].Fvalue.(int64); ok && ordinal >= 0 && ordinal < int64(len(timeUnits)) {
+			index = int(ordinal)
+		}
+	}
+	if index < 0 {
+		errMsg := fmt.Sprintf("%s: unrecognized TimeUnit %s", funcName, object.StringifyAnythingGo(unit))
+		return 0, getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+
+	per := timeUnits[index].duration
+	switch {
+	case amount > int64(math.MaxInt64/per):
+		return math.MaxInt64, nil
+	case amount < int64(math.MinInt64/per):
+		return math.MinInt64, nil
+	}
+	return time.Duration(amount) * per, nil
+}
+
+// newExecutor makes an executor of the given class that runs at most poolSize tasks at once
+func newExecutor(className string, poolSize int) *object.Object {
+	executor := object.MakeEmptyObjectWithClassName(&className)
+	name := fmt.Sprintf("pool-%d-thread", poolNumber.Add(1))
+	executor.FieldTable[fieldNameSchedulerState] = object.Field{Ftype: types.SchedulerState,
+		Fvalue: newScheduler(name, false, poolSize, true)}
+	return executor
+}
+
+// "java/util/concurrent/Executors.newCachedThreadPool()Ljava/util/concurrent/ExecutorService;"
+func executorsNewCachedThreadPool([]interface{}) interface{} {
+	return newExecutor(classNameThreadPoolExecutor, math.MaxInt32)
+}
+
+// "java/util/concurrent/Executors.newFixedThreadPool(I)Ljava/util/concurrent/ExecutorService;"
+func executorsNewFixedThreadPool(params []interface{}) interface{} {
+	poolSize := params[0].(int64)
+	if poolSize <= 0 || poolSize > math.MaxInt32 {
+		errMsg := fmt.Sprintf("executorsNewFixedThreadPool: invalid pool size %d", poolSize)
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	return newExecutor(classNameThreadPoolExecutor, int(poolSize))
+}
+
+// "java/util/concurrent/Executors.newSingleThreadExecutor()Ljava/util/concurrent/ExecutorService;"
+func executorsNewSingleThreadExecutor([]interface{}) interface{} {
+	return newExecutor(classNameThreadPoolExecutor, 1)
+}
+
+// taskObject checks the Runnable or Callable passed to an executor
+func taskObject(funcName string, obj interface{}) (*object.Object, *GErrBlk) {
+	task, ok := obj.(*object.Object)
+	if !ok || object.IsNull(task) {
+		return nil, getGErrBlk(excNames.NullPointerException, funcName+": null task")
+	}
+	return task, nil
+}
+
+// callableTask returns a task that calls a Callable and returns its result
+func callableTask(callable *object.Object) taskFunc {
+	return func(threadName string, daemon bool) (any, *GErrBlk) {
+		return callOnJavaThread(threadName, daemon, callable, "java/util/concurrent/Callable",
+			"call", "()Ljava/lang/Object;")
+	}
+}
+
+// scheduleOnExecutor schedules a task and returns a future of the given class for it, or an error
+// block. queued is what shutdownNow() returns for the task if it hasn't run; if it's nil, the future is.
+func scheduleOnExecutor(funcName string, executor interface{}, call taskFunc, queued *object.Object,
+	futureClass string, delay, period time.Duration, fixedRate bool) interface{} {
+
+	s, gerr := getScheduler(funcName, executor)
+	if gerr != nil {
+		return gerr
+	}
+	future := object.MakeEmptyObjectWithClassName(&futureClass)
+	if queued == nil {
+		queued = future
+	}
+	task := s.schedule(call, queued, delay, period, fixedRate)
+	if task == nil {
+		errMsg := fmt.Sprintf("%s: the executor has been shut down", funcName)
+		return getGErrBlk(excNames.RejectedExecutionException, errMsg)
+	}
+	future.FieldTable[fieldNameSchedulerState] = object.Field{Ftype: types.SchedulerState,
+		Fvalue: &taskState{scheduler: s, task: task}}
+	return future
+}
+
+// "java/util/concurrent/ThreadPoolExecutor.execute(Ljava/lang/Runnable;)V" -- an exception
+// that the task throws is reported as uncaught. The Runnable itself is queued, not a future.
+func executorExecute(params []interface{}) interface{} {
+	runnable, gerr := taskObject("executorExecute", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	ret := scheduleOnExecutor("executorExecute", params[0], runnableTask(runnable, true), runnable,
+		classNameFutureTask, 0, 0, false)
+	if gerr, ok := ret.(*GErrBlk); ok {
+		return gerr
+	}
+	return nil
+}
+
+// "java/util/concurrent/ThreadPoolExecutor.submit(Ljava/lang/Runnable;)Ljava/util/concurrent/Future;"
+// and submit(Runnable, T), whose future's get() returns the given result
+func executorSubmit(params []interface{}) interface{} {
+	runnable, gerr := taskObject("executorSubmit", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	call := runnableTask(runnable, false)
+	if len(params) > 2 {
+		result := params[2]
+		run := call
+		call = func(threadName string, daemon bool) (any, *GErrBlk) {
+			if _, gerr := run(threadName, daemon); gerr != nil {
+				return nil, gerr
+			}
+			return result, nil
+		}
+	}
+	return scheduleOnExecutor("executorSubmit", params[0], call, nil, classNameFutureTask, 0, 0, false)
+}
+
+// "java/util/concurrent/ThreadPoolExecutor.submit(Ljava/util/concurrent/Callable;)Ljava/util/concurrent/Future;"
+func executorSubmitCallable(params []interface{}) interface{} {
+	callable, gerr := taskObject("executorSubmitCallable", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	return scheduleOnExecutor("executorSubmitCallable", params[0], callableTask(callable), nil,
+		classNameFutureTask, 0, 0, false)
+}
+
+// "java/util/concurrent/ThreadPoolExecutor.shutdown()V" -- the tasks already submitted still
+// run, except that a ScheduledThreadPoolExecutor drops its periodic tasks
+func executorShutdown(params []interface{}) interface{} {
+	s, gerr := getScheduler("executorShutdown", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	s.stop(false)
+	return nil
+}
+
+// "java/util/concurrent/ThreadPoolExecutor.shutdownNow()Ljava/util/List;" -- the tasks that
+// have not started are dropped and returned, in the order they were submitted: the Runnables
+// passed to execute() and the futures that submit() and the schedule methods returned
+func executorShutdownNow(params []interface{}) interface{} {
+	s, gerr := getScheduler("executorShutdownNow", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	unstarted := newLinkedListObject()
+	queue := unstarted.FieldTable["value"].Fvalue.(*list.List)
+	for _, item := range s.stop(true) {
+		queue.PushBack(item)
+	}
+	return unstarted
+}
+
+// "java/util/concurrent/ThreadPoolExecutor.isShutdown()Z"
+func executorIsShutdown(params []interface{}) interface{} {
+	s, gerr := getScheduler("executorIsShutdown", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return object.JavaBooleanFromGoBoolean(s.shutdown)
+}
+
+// "java/util/concurrent/ThreadPoolExecutor.isTerminated()Z"
+func executorIsTerminated(params []interface{}) interface{} {
+	s, gerr := getScheduler("executorIsTerminated", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	select {
+	case <-s.terminated:
+		return types.JavaBoolTrue
+	default:
+		return types.JavaBoolFalse
+	}
+}
+
+// "java/util/concurrent/ThreadPoolExecutor.awaitTermination(JLjava/util/concurrent/TimeUnit;)Z"
+// -- returns false if the timeout expires first
+func executorAwaitTermination(params []interface{}) interface{} {
+	s, gerr := getScheduler("executorAwaitTermination", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	timeout, gerr := timeUnitDuration("executorAwaitTermination", params[2].(int64), params[3])
+	if gerr != nil {
+		return gerr
+	}
+	if timeout <= 0 {
+		return executorIsTerminated([]interface{}{params[1]})
+	}
+	ended, interrupted := waitForChannel(params[0].(*list.List), s.terminated, timeout)
+	if interrupted {
+		return getGErrBlk(excNames.InterruptedException, "awaitTermination interrupted")
+	}
+	return object.JavaBooleanFromGoBoolean(ended)
+}
+
+// "java/util/concurrent/ThreadPoolExecutor.close()V" -- shuts down and waits for the tasks to
+// finish. If the wait is interrupted, the tasks that have not started are dropped.
+func executorClose(params []interface{}) interface{} {
+	s, gerr := getScheduler("executorClose", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	s.stop(false)
+	if _, interrupted := waitForChannel(params[0].(*list.List), s.terminated, 0); interrupted {
+		s.stop(true)
+	}
+	return nil
+}
+
+// waitForChannel waits for a channel to close, for at most the timeout (or forever, if it's
+// zero), interruptibly if the current thread can be found. It returns whether the channel
+// closed and whether the wait was interrupted.
+func waitForChannel(fs *list.List, ch <-chan struct{}, timeout time.Duration) (closed, interrupted bool) {
+	if th := currentExecThread(fs); th != nil {
+		interrupted = th.WaitInterruptibly(ch, timeout)
+	} else if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-ch:
+		case <-timer.C:
+		}
+	} else {
+		<-ch
+	}
+
+	select {
+	case <-ch:
+		return true, interrupted
+	default:
+		return false, interrupted
+	}
+}
+
+// getTaskState returns the task of a Future
+func getTaskState(funcName string, obj interface{}) (*taskState, *GErrBlk) {
+	this, ok := obj.(*object.Object)
+	if ok && !object.IsNull(this) {
+		if state, ok := this.FieldTable[fieldNameSchedulerState].Fvalue.(*taskState); ok && state.task != nil {
+			return state, nil
+		}
+	}
+	errMsg := fmt.Sprintf("%s: the object has no scheduled task", funcName)
+	return nil, getGErrBlk(excNames.IllegalStateException, errMsg)
+}
+
+// "java/util/concurrent/FutureTask.cancel(Z)Z" -- a run that has started is left to finish,
+// whether or not mayInterruptIfRunning is set
+func futureCancel(params []interface{}) interface{} {
+	state, gerr := getTaskState("futureCancel", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	return object.JavaBooleanFromGoBoolean(state.scheduler.cancelTask(state.task))
+}
+
+// "java/util/concurrent/FutureTask.get()Ljava/lang/Object;" and get(long, TimeUnit). An
+// exception that the task threw is wrapped in an ExecutionException, as its cause.
+func futureGet(params []interface{}) interface{} {
+	state, gerr := getTaskState("futureGet", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	if futureIsCancelled([]interface{}{params[1]}) == types.JavaBoolTrue { // don't wait for a run that's under way
+		return getGErrBlk(excNames.CancellationException, "futureGet: the task was cancelled")
+	}
+	var timeout time.Duration
+	if len(params) > 2 {
+		timeout, gerr = timeUnitDuration("futureGet", params[2].(int64), params[3])
+		if gerr != nil {
+			return gerr
+		}
+		if timeout <= 0 {
+			timeout = time.Nanosecond // don't wait, rather than wait forever
+		}
+	}
+
+	done, interrupted := waitForChannel(params[0].(*list.List), state.task.done, timeout)
+	if interrupted {
+		return getGErrBlk(excNames.InterruptedException, "futureGet: interrupted")
+	}
+	if !done {
+		return getGErrBlk(excNames.TimeoutException, "futureGet: timed out")
+	}
+
+	state.scheduler.mutex.Lock()
+	defer state.scheduler.mutex.Unlock()
+	switch {
+	case state.task.cancelled:
+		return getGErrBlk(excNames.CancellationException, "futureGet: the task was cancelled")
+	case state.task.failure != nil:
+		gerr = getGErrBlk(excNames.ExecutionException, describeGErrBlk(state.task.failure))
+		gerr.Cause = state.task.failure.Thrown // the exception that the task threw
+		return gerr
+	case state.task.result == nil:
+		return object.Null
+	}
+	return state.task.result
+}
+
+// "java/util/concurrent/FutureTask.isCancelled()Z"
+func futureIsCancelled(params []interface{}) interface{} {
+	state, gerr := getTaskState("futureIsCancelled", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	state.scheduler.mutex.Lock()
+	defer state.scheduler.mutex.Unlock()
+	return object.JavaBooleanFromGoBoolean(state.task.cancelled)
+}
+
+// "java/util/concurrent/FutureTask.isDone()Z" -- true once the task is cancelled or will run no more
+func futureIsDone(params []interface{}) interface{} {
+	state, gerr := getTaskState("futureIsDone", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	state.scheduler.mutex.Lock()
+	defer state.scheduler.mutex.Unlock()
+	return object.JavaBooleanFromGoBoolean(state.task.cancelled || state.task.finished)
+}
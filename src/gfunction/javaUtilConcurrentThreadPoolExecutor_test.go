@@ -0,0 +1,217 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/thread"
+	"jacobin/src/types"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFixedThreadPoolSubmit(t *testing.T) {
+	runs := fakeTaskRuns(t)
+	executor := executorsNewFixedThreadPool([]interface{}{int64(2)}).(*object.Object)
+	fs := list.New()
+
+	runnable := newZipObject("java/lang/Runnable")
+	future := executorSubmit([]interface{}{executor, runnable}).(*object.Object)
+	expectRun(t, runs, runnable)
+	if ret := futureGet([]interface{}{fs, future}); ret != object.Null {
+		t.Errorf("get() of a Runnable's future = %v, want null", ret)
+	}
+
+	result := object.StringObjectFromGoString("result")
+	future = executorSubmit([]interface{}{executor, runnable, result}).(*object.Object)
+	expectRun(t, runs, runnable)
+	if ret := futureGet([]interface{}{fs, future}); ret != result {
+		t.Errorf("get() of submit(Runnable, result) = %v, want the result", ret)
+	}
+
+	callable := newZipObject("java/util/concurrent/Callable")
+	future = executorSubmitCallable([]interface{}{executor, callable}).(*object.Object)
+	expectRun(t, runs, callable)
+	ret := futureGet([]interface{}{fs, future, int64(2), timeUnitObject("SECONDS")})
+	if s, ok := ret.(*object.Object); !ok || object.GoStringFromStringObject(s) != "called" {
+		t.Errorf("get() of a Callable's future = %v, want \"called\"", ret)
+	}
+	if futureIsDone([]interface{}{future}) != types.JavaBoolTrue || futureIsCancelled([]interface{}{future}) != types.JavaBoolFalse {
+		t.Errorf("a finished task is not done, or is cancelled")
+	}
+
+	// an exception is handed on by get(), wrapped in an ExecutionException
+	failing := newThrowingTask("java/util/concurrent/Callable", "java.lang.ArithmeticException: / by zero")
+	future = executorSubmitCallable([]interface{}{executor, failing}).(*object.Object)
+	expectRun(t, runs, failing)
+	ret = futureGet([]interface{}{fs, future})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.ExecutionException ||
+		gerr.ErrMsg != "java.lang.ArithmeticException: / by zero" {
+		t.Errorf("get() of a task that threw: expected ExecutionException, got %v", ret)
+	}
+
+	ret = executorSubmit([]interface{}{executor, object.Null})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.NullPointerException {
+		t.Errorf("submit(null): expected NullPointerException, got %v", ret)
+	}
+	ret = executorsNewFixedThreadPool([]interface{}{int64(0)})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("newFixedThreadPool(0): expected IllegalArgumentException, got %v", ret)
+	}
+
+	executorShutdown([]interface{}{executor})
+	if executorAwaitTermination([]interface{}{fs, executor, int64(2), timeUnitObject("SECONDS")}) != types.JavaBoolTrue {
+		t.Errorf("awaitTermination() timed out")
+	}
+}
+
+func TestThreadPoolSizeAndThreadNames(t *testing.T) {
+	fakeTaskRuns(t)
+	glob := globals.GetGlobalRef()
+
+	// each task waits until it's released, and records the name of its thread
+	var mutex sync.Mutex
+	names := map[string]bool{}
+	running, maxRunning := 0, 0
+	release := make(chan struct{})
+	glob.FuncInvokeJavaMethod = func(fs *list.List, _, _, _ string, _ any, _ []any) (any, error) {
+		th := currentExecThread(fs)
+		name := th.JavaThread.(*object.Object).FieldTable["name"].Fvalue.(string)
+		mutex.Lock()
+		names[name] = true
+		running++
+		maxRunning = max(maxRunning, running)
+		mutex.Unlock()
+		<-release
+		mutex.Lock()
+		running--
+		mutex.Unlock()
+		return nil, nil
+	}
+
+	executor := executorsNewFixedThreadPool([]interface{}{int64(2)}).(*object.Object)
+	for range 5 {
+		executorExecute([]interface{}{executor, newZipObject("java/lang/Runnable")})
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	executorShutdown([]interface{}{executor})
+	if executorAwaitTermination([]interface{}{list.New(), executor, int64(2), timeUnitObject("SECONDS")}) != types.JavaBoolTrue {
+		t.Fatalf("awaitTermination() timed out")
+	}
+
+	if maxRunning != 2 {
+		t.Errorf("a pool of 2 ran %d tasks at once", maxRunning)
+	}
+	if len(names) != 2 {
+		t.Errorf("the tasks ran on threads %v, want two names", names)
+	}
+	for name := range names {
+		if !strings.HasPrefix(name, "pool-") || !(strings.HasSuffix(name, "-thread-1") || strings.HasSuffix(name, "-thread-2")) {
+			t.Errorf("unexpected thread name %q", name)
+		}
+	}
+
+	glob.ThreadLock.Lock()
+	defer glob.ThreadLock.Unlock()
+	for _, th := range glob.Threads {
+		if th.(*thread.ExecThread).JavaThread != nil {
+			t.Errorf("a task's thread is still in the thread table")
+		}
+	}
+}
+
+func TestCachedThreadPoolShutdownNow(t *testing.T) {
+	runs := fakeTaskRuns(t)
+	executor := executorsNewCachedThreadPool(nil).(*object.Object)
+	fs := list.New()
+
+	for range 3 {
+		executorExecute([]interface{}{executor, newZipObject("java/lang/Runnable")})
+	}
+	for range 3 {
+		<-runs
+	}
+
+	executorShutdownNow([]interface{}{executor})
+	ret := executorSubmit([]interface{}{executor, newZipObject("java/lang/Runnable")})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.RejectedExecutionException {
+		t.Errorf("submit() after shutdownNow(): expected RejectedExecutionException, got %v", ret)
+	}
+	executorClose([]interface{}{fs, executor})
+	if executorIsTerminated([]interface{}{executor}) != types.JavaBoolTrue {
+		t.Errorf("isTerminated() is false after close()")
+	}
+}
+
+func TestFutureGetExecutionExceptionCause(t *testing.T) {
+	runs := fakeTaskRuns(t)
+	executor := executorsNewFixedThreadPool([]interface{}{int64(1)}).(*object.Object)
+
+	failing := newThrowingTask("java/util/concurrent/Callable", "java.lang.IllegalStateException: bad state")
+	future := executorSubmitCallable([]interface{}{executor, failing}).(*object.Object)
+	expectRun(t, runs, failing)
+	ret := futureGet([]interface{}{list.New(), future})
+	gerr, ok := ret.(*GErrBlk)
+	if !ok || gerr.ExceptionType != excNames.ExecutionException {
+		t.Fatalf("get() of a task that threw: expected ExecutionException, got %v", ret)
+	}
+	if gerr.ErrMsg != "java.lang.IllegalStateException: bad state" {
+		t.Errorf("ExecutionException message = %q, want the task's exception", gerr.ErrMsg)
+	}
+	if gerr.Cause == nil || gerr.Cause != failing.FieldTable["thrown"].Fvalue {
+		t.Errorf("the cause of the ExecutionException is not the task's Throwable: %v", gerr.Cause)
+	}
+
+	executorShutdown([]interface{}{executor})
+}
+
+func TestShutdownNowReturnsUnstartedTasks(t *testing.T) {
+	runs := fakeTaskRuns(t)
+	glob := globals.GetGlobalRef()
+	fakeRun := glob.FuncInvokeJavaMethod
+	release := make(chan struct{})
+	glob.FuncInvokeJavaMethod = func(fs *list.List, className, methName, methType string, receiver any, args []any) (any, error) {
+		ret, err := fakeRun(fs, className, methName, methType, receiver, args)
+		<-release // the first task holds the pool's only thread until it's released
+		return ret, err
+	}
+
+	executor := executorsNewFixedThreadPool([]interface{}{int64(1)}).(*object.Object)
+	first := newZipObject("java/lang/Runnable")
+	executorExecute([]interface{}{executor, first})
+	expectRun(t, runs, first)
+
+	second := newZipObject("java/lang/Runnable")
+	executorExecute([]interface{}{executor, second})
+	third := newZipObject("java/util/concurrent/Callable")
+	future := executorSubmitCallable([]interface{}{executor, third}).(*object.Object)
+
+	unstarted := executorShutdownNow([]interface{}{executor}).(*object.Object)
+	queue := unstarted.FieldTable["value"].Fvalue.(*list.List)
+	var got []any
+	for e := queue.Front(); e != nil; e = e.Next() {
+		got = append(got, e.Value)
+	}
+	if len(got) != 2 || got[0] != second || got[1] != future {
+		t.Errorf("shutdownNow() = %v, want the Runnable passed to execute() and then the future of submit()", got)
+	}
+
+	close(release)
+	expectNoRun(t, runs)
+	if executorAwaitTermination([]interface{}{list.New(), executor, int64(2), timeUnitObject("SECONDS")}) != types.JavaBoolTrue {
+		t.Errorf("awaitTermination() timed out")
+	}
+	if ret := executorShutdownNow([]interface{}{executor}).(*object.Object); ret.FieldTable["value"].Fvalue.(*list.List).Len() != 0 {
+		t.Errorf("a second shutdownNow() returned tasks")
+	}
+}
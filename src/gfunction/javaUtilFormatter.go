@@ -0,0 +1,283 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
+	"jacobin/src/object"
+	"jacobin/src/types"
+)
+
+// Formatter formats with the same engine as String.format() and appends the result to
+// its destination, which is a StringBuilder or a StringBuffer. The constructors that take
+// no Appendable create a StringBuilder, as Java does. A Formatter without a locale formats
+// as String.format() does, without localization; one with a locale uses that locale's
+// decimal and grouping separators. The destinations that are files, streams, and other
+// Appendables aren't supported.
+
+func Load_Util_Formatter() {
+
+	MethodSignatures["java/util/Formatter.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/Formatter.<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  formatterInit,
+		}
+
+	MethodSignatures["java/util/Formatter.<init>(Ljava/lang/Appendable;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  formatterInitAppendable,
+		}
+
+	MethodSignatures["java/util/Formatter.<init>(Ljava/lang/Appendable;Ljava/util/Locale;)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  formatterInitAppendable,
+		}
+
+	MethodSignatures["java/util/Formatter.<init>(Ljava/util/Locale;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  formatterInitLocale,
+		}
+
+	MethodSignatures["java/util/Formatter.close()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  formatterClose,
+		}
+
+	MethodSignatures["java/util/Formatter.flush()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  formatterFlush,
+		}
+
+	MethodSignatures["java/util/Formatter.format(Ljava/lang/String;[Ljava/lang/Object;)Ljava/util/Formatter;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  formatterFormat,
+		}
+
+	MethodSignatures["java/util/Formatter.format(Ljava/util/Locale;Ljava/lang/String;[Ljava/lang/Object;)Ljava/util/Formatter;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  formatterFormatLocale,
+		}
+
+	MethodSignatures["java/util/Formatter.ioException()Ljava/io/IOException;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  formatterIoException,
+		}
+
+	MethodSignatures["java/util/Formatter.locale()Ljava/util/Locale;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  formatterLocale,
+		}
+
+	MethodSignatures["java/util/Formatter.out()Ljava/lang/Appendable;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  formatterOut,
+		}
+
+	MethodSignatures["java/util/Formatter.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  formatterToString,
+		}
+
+}
+
+// The field of the Formatter object that holds its state
+var fieldNameFormatterState = "formatterState"
+
+// formatterState is the state of a Formatter
+type formatterState struct {
+	dest   *object.Object // the StringBuilder or StringBuffer appended to
+	locale string         // the language_COUNTRY_variant name of the locale, "" if there is none
+	hasLoc bool           // the Formatter was created with a locale
+	closed bool
+}
+
+// sets up the state of the Formatter object params[0]
+func initFormatter(params []interface{}, dest *object.Object, locale interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("formatterInit", err)
+	}
+	state := &formatterState{dest: dest}
+	if localeObj, ok := locale.(*object.Object); ok && !object.IsNull(localeObj) {
+		state.locale = localeName(localeObj)
+		state.hasLoc = true
+	}
+	obj.FieldTable[fieldNameFormatterState] = object.Field{Ftype: types.FormatState, Fvalue: state}
+	return nil
+}
+
+// returns a new, empty StringBuilder, the destination of a Formatter created without one
+func newFormatterStringBuilder() *object.Object {
+	sb := object.MakeEmptyObjectWithClassName(&classStringBuilder)
+	stringBuilderInit([]interface{}{sb})
+	return sb
+}
+
+// returns the state of the Formatter object params[0], which must not have been closed
+func getFormatterState(funcName string, params []interface{}) (*formatterState, *GErrBlk) {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return nil, getArgsGErrBlk(funcName, err)
+	}
+	state, ok := obj.FieldTable[fieldNameFormatterState].Fvalue.(*formatterState)
+	if !ok {
+		return nil, getGErrBlk(excNames.IllegalStateException, funcName+": Formatter not initialized")
+	}
+	if state.closed {
+		return nil, getGErrBlk(excNames.FormatterClosedException, funcName+": Formatter is closed")
+	}
+	return state, nil
+}
+
+// "java/util/Formatter.<init>()V"
+func formatterInit(params []interface{}) interface{} {
+	return initFormatter(params, newFormatterStringBuilder(), nil)
+}
+
+// "java/util/Formatter.<init>(Ljava/util/Locale;)V"
+func formatterInitLocale(params []interface{}) interface{} {
+	return initFormatter(params, newFormatterStringBuilder(), params[1])
+}
+
+// "java/util/Formatter.<init>(Ljava/lang/Appendable;)V" and the form that adds a locale.
+// A null Appendable is replaced by a new StringBuilder.
+func formatterInitAppendable(params []interface{}) interface{} {
+	dest, err := args.GetObjectOrNull(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("formatterInitAppendable", err)
+	}
+	if object.IsNull(dest) {
+		dest = newFormatterStringBuilder()
+	} else if _, ok := dest.FieldTable["value"].Fvalue.([]types.JavaByte); !ok {
+		errMsg := "formatterInitAppendable: only a StringBuilder or a StringBuffer is supported as the destination"
+		return getGErrBlk(excNames.UnsupportedOperationException, errMsg)
+	}
+	var locale interface{}
+	if len(params) > 2 {
+		locale = params[2]
+	}
+	return initFormatter(params, dest, locale)
+}
+
+// "java/util/Formatter.close()V" -- closing a closed Formatter has no effect
+func formatterClose(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("formatterClose", err)
+	}
+	if state, ok := obj.FieldTable[fieldNameFormatterState].Fvalue.(*formatterState); ok {
+		state.closed = true
+	}
+	return nil
+}
+
+// "java/util/Formatter.flush()V" -- the destinations aren't buffered, so there's nothing to flush
+func formatterFlush(params []interface{}) interface{} {
+	if _, gErr := getFormatterState("formatterFlush", params); gErr != nil {
+		return gErr
+	}
+	return nil
+}
+
+// "java/util/Formatter.format(Ljava/lang/String;[Ljava/lang/Object;)Ljava/util/Formatter;"
+func formatterFormat(params []interface{}) interface{} {
+	state, gErr := getFormatterState("formatterFormat", params)
+	if gErr != nil {
+		return gErr
+	}
+	symbols := rootFormatSymbols
+	if state.hasLoc {
+		symbols = formatSymbolsForLocale(state.locale)
+	}
+	return formatterAppend(params[0], state, params[1:], symbols)
+}
+
+// "java/util/Formatter.format(Ljava/util/Locale;Ljava/lang/String;[Ljava/lang/Object;)Ljava/util/Formatter;"
+// The locale is used for this call only; a null locale means no localization.
+func formatterFormatLocale(params []interface{}) interface{} {
+	state, gErr := getFormatterState("formatterFormatLocale", params)
+	if gErr != nil {
+		return gErr
+	}
+	symbols := rootFormatSymbols
+	if locale, ok := params[1].(*object.Object); ok && !object.IsNull(locale) {
+		symbols = formatSymbolsForLocale(localeName(locale))
+	}
+	return formatterAppend(params[0], state, params[2:], symbols)
+}
+
+// formats the format string and arguments in formatParams and appends the result to the
+// destination of the Formatter. Returns the Formatter.
+func formatterAppend(formatter interface{}, state *formatterState, formatParams []interface{},
+	symbols formatSymbols) interface{} {
+	ret := stringFormatterWithSymbols(formatParams, symbols)
+	str, ok := ret.(*object.Object)
+	if !ok {
+		return ret
+	}
+	if ret := stringBuilderAppend([]interface{}{state.dest, str}); ret != state.dest {
+		return ret
+	}
+	return formatter
+}
+
+// "java/util/Formatter.ioException()Ljava/io/IOException;" -- appending to a StringBuilder
+// or a StringBuffer never throws an IOException, so this is always null
+func formatterIoException(params []interface{}) interface{} {
+	if _, gErr := getFormatterState("formatterIoException", params); gErr != nil {
+		return gErr
+	}
+	return object.Null
+}
+
+// "java/util/Formatter.locale()Ljava/util/Locale;" -- null if the Formatter has no locale
+func formatterLocale(params []interface{}) interface{} {
+	state, gErr := getFormatterState("formatterLocale", params)
+	if gErr != nil {
+		return gErr
+	}
+	if !state.hasLoc {
+		return object.Null
+	}
+	return makeLocale(state.locale)
+}
+
+// "java/util/Formatter.out()Ljava/lang/Appendable;"
+func formatterOut(params []interface{}) interface{} {
+	state, gErr := getFormatterState("formatterOut", params)
+	if gErr != nil {
+		return gErr
+	}
+	return state.dest
+}
+
+// "java/util/Formatter.toString()Ljava/lang/String;" -- the contents of the destination
+func formatterToString(params []interface{}) interface{} {
+	state, gErr := getFormatterState("formatterToString", params)
+	if gErr != nil {
+		return gErr
+	}
+	return stringBuilderToString([]interface{}{state.dest})
+}
@@ -0,0 +1,118 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"testing"
+)
+
+func newTestFormatter(t *testing.T, params ...interface{}) *object.Object {
+	className := "java/util/Formatter"
+	formatter := object.MakeEmptyObjectWithClassName(&className)
+	var ret interface{}
+	switch len(params) {
+	case 0:
+		ret = formatterInit([]interface{}{formatter})
+	default:
+		ret = formatterInitAppendable(append([]interface{}{formatter}, params...))
+	}
+	if ret != nil {
+		t.Fatalf("Formatter.<init>: unexpected result %v", ret)
+	}
+	return formatter
+}
+
+func formatterString(t *testing.T, formatter *object.Object) string {
+	str, ok := formatterToString([]interface{}{formatter}).(*object.Object)
+	if !ok {
+		t.Fatalf("Formatter.toString() did not return a string")
+	}
+	return object.GoStringFromStringObject(str)
+}
+
+func TestFormatterAppendsToItsStringBuilder(t *testing.T) {
+	globals.InitGlobals("test")
+
+	formatter := newTestFormatter(t)
+	count := Populator("java/lang/Integer", types.Int, int64(5))
+	ret := formatterFormat([]interface{}{formatter, object.StringObjectFromGoString("%d items, "),
+		makeObjectRefArray(count)})
+	if ret != formatter {
+		t.Fatalf("format() should return the Formatter, got %v", ret)
+	}
+	pi := Populator("java/lang/Double", types.Double, 3.14159)
+	formatterFormat([]interface{}{formatter, object.StringObjectFromGoString("%,.2f"), makeObjectRefArray(pi)})
+	if got := formatterString(t, formatter); got != "5 items, 3.14" {
+		t.Errorf("toString(): got %q", got)
+	}
+	if locale := formatterLocale([]interface{}{formatter}); locale != object.Null {
+		t.Errorf("locale(): expected null, got %v", locale)
+	}
+	if ioErr := formatterIoException([]interface{}{formatter}); ioErr != object.Null {
+		t.Errorf("ioException(): expected null, got %v", ioErr)
+	}
+}
+
+func TestFormatterWithDestinationAndLocale(t *testing.T) {
+	globals.InitGlobals("test")
+
+	sb := object.MakeEmptyObjectWithClassName(&classStringBuilder)
+	stringBuilderInit([]interface{}{sb})
+	stringBuilderAppend([]interface{}{sb, object.StringObjectFromGoString("total: ")})
+	formatter := newTestFormatter(t, sb, makeLocale("de_DE"))
+	if out := formatterOut([]interface{}{formatter}); out != sb {
+		t.Fatalf("out(): expected the StringBuilder, got %v", out)
+	}
+
+	value := Populator("java/lang/Double", types.Double, 1234.567)
+	format := object.StringObjectFromGoString("%,.2f")
+	formatterFormat([]interface{}{formatter, format, makeObjectRefArray(value)})
+	formatterFormatLocale([]interface{}{formatter, object.Null, object.StringObjectFromGoString(" / "),
+		object.Null})
+	formatterFormatLocale([]interface{}{formatter, makeLocale("en_US"), format, makeObjectRefArray(value)})
+
+	got := object.GoStringFromStringObject(stringBuilderToString([]interface{}{sb}).(*object.Object))
+	if got != "total: 1.234,57 / 1,234.57" {
+		t.Errorf("StringBuilder contents: got %q", got)
+	}
+	if locale := formatterLocale([]interface{}{formatter}).(*object.Object); localeName(locale) != "de_DE" {
+		t.Errorf("locale(): got %q", localeName(locale))
+	}
+}
+
+func TestFormatterErrors(t *testing.T) {
+	globals.InitGlobals("test")
+
+	formatter := newTestFormatter(t)
+	ret := formatterFormat([]interface{}{formatter, object.StringObjectFromGoString("%d"),
+		makeObjectRefArray(object.StringObjectFromGoString("x"))})
+	if gErr, ok := ret.(*GErrBlk); !ok || gErr.ExceptionType != excNames.IllegalFormatConversionException {
+		t.Errorf("format(%%d, String): expected IllegalFormatConversionException, got %v", ret)
+	}
+
+	formatterClose([]interface{}{formatter})
+	formatterClose([]interface{}{formatter})
+	ret = formatterFormat([]interface{}{formatter, object.StringObjectFromGoString("x"), object.Null})
+	if gErr, ok := ret.(*GErrBlk); !ok || gErr.ExceptionType != excNames.FormatterClosedException {
+		t.Errorf("format() after close(): expected FormatterClosedException, got %v", ret)
+	}
+	ret = formatterToString([]interface{}{formatter})
+	if gErr, ok := ret.(*GErrBlk); !ok || gErr.ExceptionType != excNames.FormatterClosedException {
+		t.Errorf("toString() after close(): expected FormatterClosedException, got %v", ret)
+	}
+
+	className := "java/io/File"
+	file := object.MakeEmptyObjectWithClassName(&className)
+	ret = formatterInitAppendable([]interface{}{object.MakeEmptyObjectWithClassName(&className), file})
+	if gErr, ok := ret.(*GErrBlk); !ok || gErr.ExceptionType != excNames.UnsupportedOperationException {
+		t.Errorf("Formatter(File): expected UnsupportedOperationException, got %v", ret)
+	}
+}
@@ -21,6 +21,8 @@ var fieldNameMap = "map"
 
 func Load_Util_Hash_Map() {
 
+	object.RegisterInstantiationHook(classNameHashMap, newHashMapObject)
+
 	MethodSignatures["java/util/HashMap.<clinit>()V"] =
 		GMeth{
 			ParamSlots: 0,
@@ -163,6 +165,13 @@ func hashmapInit(params []interface{}) interface{} {
 	return nil
 }
 
+// The instantiation hook for HashMap and HashSet: a new object holding an empty map
+func newHashMapObject(className string) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&className)
+	obj.FieldTable[fieldNameMap] = object.Field{Ftype: types.HashMap, Fvalue: make(types.DefHashMap)}
+	return obj
+}
+
 // An internal function to extract the HashMap key field value.
 func _getKey(param interface{}) (interface{}, bool) {
 	keyObj, ok := param.(*object.Object)
@@ -182,3 +182,34 @@ func TestHashMap_ErrorPaths(t *testing.T) {
         if geb.ExceptionType != excNames.IllegalArgumentException { t.Fatalf("expected IllegalArgumentException") }
     }
 }
+
+func TestHashMap_InstantiationHook(t *testing.T) {
+    globals.InitStringPool()
+    saved := MethodSignatures
+    defer func() { MethodSignatures = saved }()
+    MethodSignatures = make(map[string]GMeth)
+    Load_Util_Hash_Map()
+    Load_Util_Hash_Set()
+
+    for _, className := range []string{classNameHashMap, "java/util/HashSet"} {
+        hook, ok := object.InstantiationHookFor(className)
+        if !ok {
+            t.Fatalf("expected an instantiation hook for %s", className)
+        }
+        obj := hook(className)
+        if name := object.GoStringFromStringPoolIndex(obj.KlassName); name != className {
+            t.Errorf("expected an object of class %s, got %s", className, name)
+        }
+        if _, ok = obj.FieldTable[fieldNameMap].Fvalue.(types.DefHashMap); !ok {
+            t.Errorf("expected %s to hold an empty map, got %T", className, obj.FieldTable[fieldNameMap].Fvalue)
+        }
+    }
+
+    // the map is usable before <init>() has run
+    hook, _ := object.InstantiationHookFor(classNameHashMap)
+    hm := hook(classNameHashMap)
+    hashmapPut([]interface{}{hm, strKey("k"), strKey("v")})
+    if sz := hashmapSize([]interface{}{hm}).(int64); sz != 1 {
+        t.Fatalf("expected size 1, got %d", sz)
+    }
+}
@@ -7,19 +7,25 @@
 package gfunction
 
 import (
+	"container/list"
 	"fmt"
 	"jacobin/src/excNames"
 	"jacobin/src/object"
 	"jacobin/src/stringPool"
 	"jacobin/src/types"
 	"jacobin/src/util"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 var classNameObject = "java/lang/Object"
+var classNameHashSetIterator = "java/util/HashMap$KeyIterator"
 
 func Load_Util_Hash_Set() {
 
+	object.RegisterInstantiationHook("java/util/HashSet", newHashMapObject)
+
 	MethodSignatures["java/util/HashSet.<clinit>()V"] =
 		GMeth{
 			ParamSlots: 0,
@@ -52,8 +58,9 @@ func Load_Util_Hash_Set() {
 
 	MethodSignatures["java/util/HashSet.addAll(Ljava/util/Collection;)Z"] =
 		GMeth{
-			ParamSlots: 1,
-			GFunction:  trapFunction,
+			ParamSlots:   1,
+			GFunction:    hashsetAddAll,
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/util/HashSet.clear()V"] =
@@ -65,7 +72,7 @@ func Load_Util_Hash_Set() {
 	MethodSignatures["java/util/HashSet.clone()Ljava/lang/Object;"] =
 		GMeth{
 			ParamSlots: 0,
-			GFunction:  hashmapInit,
+			GFunction:  hashsetClone,
 		}
 
 	MethodSignatures["java/util/HashSet.contains(Ljava/lang/Object;)Z"] =
@@ -76,8 +83,9 @@ func Load_Util_Hash_Set() {
 
 	MethodSignatures["java/util/HashSet.containsAll(Ljava/util/Collection;)Z"] =
 		GMeth{
-			ParamSlots: 1,
-			GFunction:  trapFunction,
+			ParamSlots:   1,
+			GFunction:    hashsetContainsAll,
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/util/HashSet.isEmpty()Z"] =
@@ -89,7 +97,7 @@ func Load_Util_Hash_Set() {
 	MethodSignatures["java/util/HashSet.iterator()Ljava/util/Iterator;"] =
 		GMeth{
 			ParamSlots: 0,
-			GFunction:  trapFunction,
+			GFunction:  hashsetIterator,
 		}
 
 	MethodSignatures["java/util/HashSet.newHashSet(I)Ljava/util/HashSet;"] =
@@ -106,14 +114,16 @@ func Load_Util_Hash_Set() {
 
 	MethodSignatures["java/util/HashSet.removeAll(Ljava/util/Collection;)Z"] =
 		GMeth{
-			ParamSlots: 1,
-			GFunction:  trapFunction,
+			ParamSlots:   1,
+			GFunction:    hashsetRemoveAll,
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/util/HashSet.retainAll(Ljava/util/Collection;)Z"] =
 		GMeth{
-			ParamSlots: 1,
-			GFunction:  trapFunction,
+			ParamSlots:   1,
+			GFunction:    hashsetRetainAll,
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/util/HashSet.size()I"] =
@@ -140,6 +150,33 @@ func Load_Util_Hash_Set() {
 			GFunction:  trapFunction,
 		}
 
+	MethodSignatures["java/util/HashSet.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    hashsetToString,
+			NeedsContext: true,
+		}
+
+	// the iterator
+
+	MethodSignatures[classNameHashSetIterator+".hasNext()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  hashsetIteratorHasNext,
+		}
+
+	MethodSignatures[classNameHashSetIterator+".next()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  hashsetIteratorNext,
+		}
+
+	MethodSignatures[classNameHashSetIterator+".remove()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  hashsetIteratorRemove,
+		}
+
 }
 
 // Compute the hash of the object being added to the HashSet.
@@ -344,12 +381,261 @@ func hashsetToArray(params []interface{}) interface{} {
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
 
-	// Create an array of objects.
-	objArray := make([]*object.Object, 0, len(hm))
-	for _, value := range hm {
-		objArray = append(objArray, value.(*object.Object))
-	}
+	// Create an array of the elements, in the order of iteration.
+	objArray := hashsetElements(hm)
 
 	return object.MakePrimitiveObject(classNameObject, types.RefArray, objArray)
 
 }
+
+// hashsetKey returns the key under which the element is held in the map of a HashSet: the
+// class name of the element and the hash of its value, as hashsetAdd() forms it.
+func hashsetKey(funcName string, that *object.Object) (string, *GErrBlk) {
+	fld, ok := that.FieldTable["value"]
+	if !ok {
+		errMsg := funcName + ": Argument parameter is missing the \"value\" field"
+		return "", getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	hashedUint64, err := util.HashAnything(fld.Fvalue)
+	if err != nil {
+		errMsg := fmt.Sprintf("%s: util.HashAnything failed, err: %v", funcName, err)
+		return "", getGErrBlk(excNames.VirtualMachineError, errMsg)
+	}
+	className := *stringPool.GetStringPointer(that.KlassName)
+	return className + ":" + strconv.FormatUint(hashedUint64, 10), nil
+}
+
+// hashsetElements returns the elements of the map of a HashSet. Go maps have no order, so
+// the elements are sorted by their keys, which gives each set a stable order of iteration.
+func hashsetElements(hm types.DefHashMap) []*object.Object {
+	keys := make([]string, 0, len(hm))
+	for key := range hm {
+		keys = append(keys, fmt.Sprint(key))
+	}
+	sort.Strings(keys)
+	elems := make([]*object.Object, 0, len(hm))
+	for _, key := range keys {
+		elems = append(elems, hm[key].(*object.Object))
+	}
+	return elems
+}
+
+// returns the map of the HashSet in params[index]
+func getHashSetMap(funcName string, params []interface{}, index int) (types.DefHashMap, *GErrBlk) {
+	this, ok := params[index].(*object.Object)
+	if !ok || object.IsNull(this) {
+		errMsg := funcName + ": HashSet parameter is nil or not an object"
+		return nil, getGErrBlk(excNames.ClassCastException, errMsg)
+	}
+	hm, ok := this.FieldTable[fieldNameMap].Fvalue.(types.DefHashMap)
+	if !ok {
+		errMsg := funcName + ": HashMap is not present"
+		return nil, getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	return hm, nil
+}
+
+// applies the HashSet function to the set in params[1] and each element of the collection in
+// params[2], returning whether the function returned true for any of the elements and for all
+// of them. params[0] is the frame stack.
+func hashsetForEach(funcName string, params []interface{},
+	function func([]interface{}) interface{}) (bool, bool, *GErrBlk) {
+	fs := params[0].(*list.List)
+	if _, gErr := getHashSetMap(funcName, params, 1); gErr != nil {
+		return false, false, gErr
+	}
+	source, ok := params[2].(*object.Object)
+	if !ok || object.IsNull(source) {
+		return false, false, getGErrBlk(excNames.NullPointerException, funcName+": the collection is null")
+	}
+	elems, gErr := collectionElements(fs, source)
+	if gErr != nil {
+		return false, false, gErr
+	}
+
+	anyTrue, allTrue := false, true
+	for _, elem := range elems {
+		ret := function([]interface{}{params[1], elem})
+		if gErr, ok := ret.(*GErrBlk); ok {
+			return false, false, gErr
+		}
+		anyTrue = anyTrue || ret == types.JavaBoolTrue
+		allTrue = allTrue && ret == types.JavaBoolTrue
+	}
+	return anyTrue, allTrue, nil
+}
+
+// "java/util/HashSet.addAll(Ljava/util/Collection;)Z" -- true if the set changed
+func hashsetAddAll(params []interface{}) interface{} {
+	changed, _, gErr := hashsetForEach("hashsetAddAll", params, hashsetAdd)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(changed)
+}
+
+// "java/util/HashSet.containsAll(Ljava/util/Collection;)Z"
+func hashsetContainsAll(params []interface{}) interface{} {
+	_, all, gErr := hashsetForEach("hashsetContainsAll", params, hashsetContains)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(all)
+}
+
+// "java/util/HashSet.removeAll(Ljava/util/Collection;)Z" -- true if the set changed
+func hashsetRemoveAll(params []interface{}) interface{} {
+	changed, _, gErr := hashsetForEach("hashsetRemoveAll", params, hashsetRemove)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(changed)
+}
+
+// "java/util/HashSet.retainAll(Ljava/util/Collection;)Z" -- removes the elements that aren't
+// in the collection and returns true if the set changed
+func hashsetRetainAll(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	hm, gErr := getHashSetMap("hashsetRetainAll", params, 1)
+	if gErr != nil {
+		return gErr
+	}
+	source, ok := params[2].(*object.Object)
+	if !ok || object.IsNull(source) {
+		return getGErrBlk(excNames.NullPointerException, "hashsetRetainAll: the collection is null")
+	}
+	elems, gErr := collectionElements(fs, source)
+	if gErr != nil {
+		return gErr
+	}
+
+	retained := make(map[string]bool, len(elems))
+	for _, elem := range elems {
+		if object.IsNull(elem) {
+			continue
+		}
+		key, gErr := hashsetKey("hashsetRetainAll", elem)
+		if gErr != nil {
+			return gErr
+		}
+		retained[key] = true
+	}
+
+	hashmapMutex.Lock()
+	defer hashmapMutex.Unlock()
+	changed := false
+	for key := range hm {
+		if !retained[fmt.Sprint(key)] {
+			delete(hm, key)
+			changed = true
+		}
+	}
+	return types.ConvertGoBoolToJavaBool(changed)
+}
+
+// "java/util/HashSet.clone()Ljava/lang/Object;" -- a new set holding the same elements,
+// which aren't themselves cloned
+func hashsetClone(params []interface{}) interface{} {
+	hm, gErr := getHashSetMap("hashsetClone", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	hashmapMutex.RLock()
+	defer hashmapMutex.RUnlock()
+	clone := newHashMapObject(classNameHashMap)
+	cloneMap := clone.FieldTable[fieldNameMap].Fvalue.(types.DefHashMap)
+	for key, value := range hm {
+		cloneMap[key] = value
+	}
+	return clone
+}
+
+// "java/util/HashSet.toString()Ljava/lang/String;" -- the elements in the order of iteration
+func hashsetToString(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	hm, gErr := getHashSetMap("hashsetToString", params, 1)
+	if gErr != nil {
+		return gErr
+	}
+	elems := hashsetElements(hm)
+	strs := make([]string, 0, len(elems))
+	for _, elem := range elems {
+		str, gErr := javaObjectString(fs, elem)
+		if gErr != nil {
+			return gErr
+		}
+		strs = append(strs, str)
+	}
+	return object.StringObjectFromGoString("[" + strings.Join(strs, ", ") + "]")
+}
+
+// hashsetIteratorState is the state of an iterator over a HashSet: the elements the set held when
+// the iterator was made, and the element last returned, which remove() removes from the set
+type hashsetIteratorState struct {
+	set   *object.Object
+	elems []*object.Object
+	pos   int
+	last  *object.Object
+}
+
+// "java/util/HashSet.iterator()Ljava/util/Iterator;"
+func hashsetIterator(params []interface{}) interface{} {
+	hm, gErr := getHashSetMap("hashsetIterator", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	iterator := &hashsetIteratorState{set: params[0].(*object.Object), elems: hashsetElements(hm)}
+	return object.MakeOneFieldObject(classNameHashSetIterator, fieldNameMap, types.HashMap, iterator)
+}
+
+// returns the state of the iterator in params[0]
+func getHashSetIterator(funcName string, params []interface{}) (*hashsetIteratorState, *GErrBlk) {
+	obj, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return nil, getGErrBlk(excNames.NullPointerException, funcName+": the iterator is null")
+	}
+	iterator, ok := obj.FieldTable[fieldNameMap].Fvalue.(*hashsetIteratorState)
+	if !ok {
+		return nil, getGErrBlk(excNames.IllegalStateException, funcName+": not a HashSet iterator")
+	}
+	return iterator, nil
+}
+
+// "java/util/HashMap$KeyIterator.hasNext()Z"
+func hashsetIteratorHasNext(params []interface{}) interface{} {
+	iterator, gErr := getHashSetIterator("hashsetIteratorHasNext", params)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(iterator.pos < len(iterator.elems))
+}
+
+// "java/util/HashMap$KeyIterator.next()Ljava/lang/Object;"
+func hashsetIteratorNext(params []interface{}) interface{} {
+	iterator, gErr := getHashSetIterator("hashsetIteratorNext", params)
+	if gErr != nil {
+		return gErr
+	}
+	if iterator.pos >= len(iterator.elems) {
+		return getGErrBlk(excNames.NoSuchElementException, "hashsetIteratorNext: no more elements")
+	}
+	iterator.last = iterator.elems[iterator.pos]
+	iterator.pos++
+	return iterator.last
+}
+
+// "java/util/HashMap$KeyIterator.remove()V" -- removes the element last returned from the set
+func hashsetIteratorRemove(params []interface{}) interface{} {
+	iterator, gErr := getHashSetIterator("hashsetIteratorRemove", params)
+	if gErr != nil {
+		return gErr
+	}
+	if iterator.last == nil {
+		return getGErrBlk(excNames.IllegalStateException, "hashsetIteratorRemove: next() has not been called")
+	}
+	if gErr, ok := hashsetRemove([]interface{}{iterator.set, iterator.last}).(*GErrBlk); ok {
+		return gErr
+	}
+	iterator.last = nil
+	return nil
+}
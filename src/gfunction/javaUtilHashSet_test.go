@@ -1,10 +1,12 @@
 package gfunction
 
 import (
+    "fmt"
     "jacobin/src/excNames"
     "jacobin/src/globals"
     "jacobin/src/object"
     "jacobin/src/types"
+    "strings"
     "testing"
 )
 
@@ -155,3 +157,47 @@ func TestHashSet_ErrorPaths(t *testing.T) {
         }
     }
 }
+
+func TestHashSet_BulkOperations_Iterator_Clone(t *testing.T) {
+    globals.InitGlobals("test")
+
+    hs := newHashSetObj(t)
+    for _, v := range []int64{1, 2, 3} {
+        hashsetAdd([]interface{}{hs, intObj(v)})
+    }
+    other := newTestTreeSet(3, 4, 5)
+
+    assertJavaBool(t, treeCall(hashsetAddAll, hs, other), types.JavaBoolTrue, "addAll")
+    assertJavaBool(t, treeCall(hashsetAddAll, hs, other), types.JavaBoolFalse, "addAll again")
+    assertJavaBool(t, treeCall(hashsetContainsAll, hs, other), types.JavaBoolTrue, "containsAll")
+    if size := hashmapSize([]interface{}{hs}); size != int64(5) {
+        t.Fatalf("size after addAll: expected 5, got %v", size)
+    }
+
+    clone := hashsetClone([]interface{}{hs}).(*object.Object)
+
+    assertJavaBool(t, treeCall(hashsetRemoveAll, hs, newTestTreeSet(1, 5, 9)), types.JavaBoolTrue, "removeAll")
+    assertJavaBool(t, treeCall(hashsetRetainAll, hs, newTestTreeSet(2, 3)), types.JavaBoolTrue, "retainAll")
+    assertJavaBool(t, treeCall(hashsetContainsAll, hs, other), types.JavaBoolFalse, "containsAll after retainAll")
+    if size := hashmapSize([]interface{}{hs}); size != int64(2) {
+        t.Fatalf("size after retainAll: expected 2, got %v", size)
+    }
+    if size := hashmapSize([]interface{}{clone}); size != int64(5) {
+        t.Fatalf("size of the clone: expected 5, got %v", size)
+    }
+
+    // the iterator and toString() agree on the order
+    expected := object.GoStringFromStringObject(treeCall(hashsetToString, clone).(*object.Object))
+    it := hashsetIterator([]interface{}{clone}).(*object.Object)
+    var strs []string
+    for hashsetIteratorHasNext([]interface{}{it}) == types.JavaBoolTrue {
+        elem := hashsetIteratorNext([]interface{}{it}).(*object.Object)
+        strs = append(strs, fmt.Sprint(elem.FieldTable["value"].Fvalue))
+        hashsetIteratorRemove([]interface{}{it})
+    }
+    if got := "[" + strings.Join(strs, ", ") + "]"; got != expected || len(strs) != 5 {
+        t.Errorf("iterator: expected %s, got %s", expected, got)
+    }
+    assertJavaBool(t, hashsetIsEmpty([]interface{}{clone}), types.JavaBoolTrue, "isEmpty after removals by the iterator")
+    checkTreeError(t, "next() past the end", hashsetIteratorNext([]interface{}{it}), excNames.NoSuchElementException)
+}
@@ -0,0 +1,1074 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"jacobin/src/util"
+	"strings"
+)
+
+// The immutable collections: those made by List.of(), Set.of(), Map.of(), and their
+// copyOf() and ofEntries() relatives, and the empty and singleton collections of
+// java/util/Collections. The lists hold their elements in a slice. The sets and maps are
+// held in the store of LinkedHashMap (see javaUtilLinkedHashMap.go), marked immutable, so
+// they iterate in the order in which their elements were given. Every method that would
+// change one of these collections throws an UnsupportedOperationException.
+
+var classNameImmutableList = "java/util/ImmutableCollections$ListN"
+var classNameImmutableListIterator = "java/util/ImmutableCollections$ListItr"
+var classNameImmutableSet = "java/util/ImmutableCollections$SetN"
+var classNameImmutableMap = "java/util/ImmutableCollections$MapN"
+var classNameKeyValueHolder = "java/util/KeyValueHolder" // the entries made by Map.entry()
+
+var classNameEmptyList = "java/util/Collections$EmptyList"
+var classNameEmptySet = "java/util/Collections$EmptySet"
+var classNameEmptyMap = "java/util/Collections$EmptyMap"
+var classNameSingletonList = "java/util/Collections$SingletonList"
+var classNameSingletonSet = "java/util/Collections$SingletonSet"
+var classNameSingletonMap = "java/util/Collections$SingletonMap"
+
+// The field of an immutable list or of its iterator that holds the Go state
+var fieldNameImmutableList = "immutableList"
+
+var immutableListClasses = []string{classNameImmutableList, classNameEmptyList, classNameSingletonList}
+var immutableSetClasses = []string{classNameImmutableSet, classNameEmptySet, classNameSingletonSet}
+var immutableMapClasses = []string{classNameImmutableMap, classNameEmptyMap, classNameSingletonMap}
+
+// the methods that change a collection, and those that change a list or a map. The
+// unmodifiable views of java/util/Collections refuse these too.
+var collectionWriteMethods = []string{
+	"add(Ljava/lang/Object;)Z",
+	"addAll(Ljava/util/Collection;)Z",
+	"clear()V",
+	"remove(Ljava/lang/Object;)Z",
+	"removeAll(Ljava/util/Collection;)Z",
+	"removeIf(Ljava/util/function/Predicate;)Z",
+	"retainAll(Ljava/util/Collection;)Z",
+}
+
+var listWriteMethods = []string{
+	"add(ILjava/lang/Object;)V",
+	"addAll(ILjava/util/Collection;)Z",
+	"addFirst(Ljava/lang/Object;)V",
+	"addLast(Ljava/lang/Object;)V",
+	"remove(I)Ljava/lang/Object;",
+	"removeFirst()Ljava/lang/Object;",
+	"removeLast()Ljava/lang/Object;",
+	"replaceAll(Ljava/util/function/UnaryOperator;)V",
+	"set(ILjava/lang/Object;)Ljava/lang/Object;",
+	"sort(Ljava/util/Comparator;)V",
+}
+
+var mapWriteMethods = []string{
+	"clear()V",
+	"compute(Ljava/lang/Object;Ljava/util/function/BiFunction;)Ljava/lang/Object;",
+	"computeIfAbsent(Ljava/lang/Object;Ljava/util/function/Function;)Ljava/lang/Object;",
+	"computeIfPresent(Ljava/lang/Object;Ljava/util/function/BiFunction;)Ljava/lang/Object;",
+	"merge(Ljava/lang/Object;Ljava/lang/Object;Ljava/util/function/BiFunction;)Ljava/lang/Object;",
+	"put(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;",
+	"putAll(Ljava/util/Map;)V",
+	"putIfAbsent(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;",
+	"remove(Ljava/lang/Object;)Ljava/lang/Object;",
+	"remove(Ljava/lang/Object;Ljava/lang/Object;)Z",
+	"replace(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;",
+	"replace(Ljava/lang/Object;Ljava/lang/Object;Ljava/lang/Object;)Z",
+	"replaceAll(Ljava/util/function/BiFunction;)V",
+}
+
+func Load_Util_ImmutableCollections() {
+
+	// the factory methods of List, Set, and Map, which have overloads for up to ten
+	// elements (or key-value pairs) before their varargs forms
+
+	for n := 0; n <= 10; n++ {
+		MethodSignatures["java/util/List.of("+strings.Repeat("Ljava/lang/Object;", n)+")Ljava/util/List;"] =
+			GMeth{
+				ParamSlots: n,
+				GFunction:  listOf,
+			}
+
+		MethodSignatures["java/util/Set.of("+strings.Repeat("Ljava/lang/Object;", n)+")Ljava/util/Set;"] =
+			GMeth{
+				ParamSlots:   n,
+				GFunction:    setOf,
+				NeedsContext: true,
+			}
+
+		MethodSignatures["java/util/Map.of("+strings.Repeat("Ljava/lang/Object;", 2*n)+")Ljava/util/Map;"] =
+			GMeth{
+				ParamSlots:   2 * n,
+				GFunction:    mapOf,
+				NeedsContext: true,
+			}
+	}
+
+	MethodSignatures["java/util/List.of([Ljava/lang/Object;)Ljava/util/List;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  listOfArray,
+		}
+
+	MethodSignatures["java/util/List.copyOf(Ljava/util/Collection;)Ljava/util/List;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    listCopyOf,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Set.of([Ljava/lang/Object;)Ljava/util/Set;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    setOfArray,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Set.copyOf(Ljava/util/Collection;)Ljava/util/Set;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    setCopyOf,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Map.copyOf(Ljava/util/Map;)Ljava/util/Map;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    mapCopyOf,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Map.entry(Ljava/lang/Object;Ljava/lang/Object;)Ljava/util/Map$Entry;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  mapEntryOf,
+		}
+
+	MethodSignatures["java/util/Map.ofEntries([Ljava/util/Map$Entry;)Ljava/util/Map;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    mapOfEntries,
+			NeedsContext: true,
+		}
+
+	// the lists
+
+	for _, className := range immutableListClasses {
+		MethodSignatures[className+".contains(Ljava/lang/Object;)Z"] =
+			GMeth{
+				ParamSlots:   1,
+				GFunction:    immutablelistContains,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".containsAll(Ljava/util/Collection;)Z"] =
+			GMeth{
+				ParamSlots:   1,
+				GFunction:    immutablelistContainsAll,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".equals(Ljava/lang/Object;)Z"] =
+			GMeth{
+				ParamSlots:   1,
+				GFunction:    immutablelistEquals,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".get(I)Ljava/lang/Object;"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  immutablelistGet,
+			}
+
+		MethodSignatures[className+".hashCode()I"] =
+			GMeth{
+				ParamSlots:   0,
+				GFunction:    immutablelistHashCode,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".indexOf(Ljava/lang/Object;)I"] =
+			GMeth{
+				ParamSlots:   1,
+				GFunction:    immutablelistIndexOf,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".isEmpty()Z"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  immutablelistIsEmpty,
+			}
+
+		MethodSignatures[className+".iterator()Ljava/util/Iterator;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  immutablelistIterator,
+			}
+
+		MethodSignatures[className+".lastIndexOf(Ljava/lang/Object;)I"] =
+			GMeth{
+				ParamSlots:   1,
+				GFunction:    immutablelistLastIndexOf,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".size()I"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  immutablelistSize,
+			}
+
+		MethodSignatures[className+".subList(II)Ljava/util/List;"] =
+			GMeth{
+				ParamSlots: 2,
+				GFunction:  immutablelistSubList,
+			}
+
+		MethodSignatures[className+".toArray()[Ljava/lang/Object;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  immutablelistToArray,
+			}
+
+		MethodSignatures[className+".toString()Ljava/lang/String;"] =
+			GMeth{
+				ParamSlots:   0,
+				GFunction:    immutablelistToString,
+				NeedsContext: true,
+			}
+
+		registerUnsupportedMethods(className, collectionWriteMethods)
+		registerUnsupportedMethods(className, listWriteMethods)
+	}
+
+	MethodSignatures[classNameImmutableListIterator+".hasNext()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  immutablelistIteratorHasNext,
+		}
+
+	MethodSignatures[classNameImmutableListIterator+".next()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  immutablelistIteratorNext,
+		}
+
+	registerUnsupportedMethods(classNameImmutableListIterator, []string{"remove()V"})
+
+	// the sets
+
+	for _, className := range immutableSetClasses {
+		MethodSignatures[className+".contains(Ljava/lang/Object;)Z"] =
+			GMeth{
+				ParamSlots:   1,
+				GFunction:    linkedhashContains,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".containsAll(Ljava/util/Collection;)Z"] =
+			GMeth{
+				ParamSlots:   1,
+				GFunction:    immutablesetContainsAll,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".equals(Ljava/lang/Object;)Z"] =
+			GMeth{
+				ParamSlots:   1,
+				GFunction:    immutablesetEquals,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".hashCode()I"] =
+			GMeth{
+				ParamSlots:   0,
+				GFunction:    immutableHashCode,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".isEmpty()Z"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  linkedhashIsEmpty,
+			}
+
+		MethodSignatures[className+".iterator()Ljava/util/Iterator;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  linkedhashIterator,
+			}
+
+		MethodSignatures[className+".size()I"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  linkedhashSize,
+			}
+
+		MethodSignatures[className+".toArray()[Ljava/lang/Object;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  linkedhashsetToArray,
+			}
+
+		MethodSignatures[className+".toString()Ljava/lang/String;"] =
+			GMeth{
+				ParamSlots:   0,
+				GFunction:    linkedhashToString,
+				NeedsContext: true,
+			}
+
+		registerUnsupportedMethods(className, collectionWriteMethods)
+	}
+
+	// the maps
+
+	for _, className := range immutableMapClasses {
+		MethodSignatures[className+".containsKey(Ljava/lang/Object;)Z"] =
+			GMeth{
+				ParamSlots:   1,
+				GFunction:    linkedhashContains,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".containsValue(Ljava/lang/Object;)Z"] =
+			GMeth{
+				ParamSlots:   1,
+				GFunction:    linkedhashmapContainsValue,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".entrySet()Ljava/util/Set;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  linkedhashmapEntrySet,
+			}
+
+		MethodSignatures[className+".equals(Ljava/lang/Object;)Z"] =
+			GMeth{
+				ParamSlots:   1,
+				GFunction:    immutablemapEquals,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".get(Ljava/lang/Object;)Ljava/lang/Object;"] =
+			GMeth{
+				ParamSlots:   1,
+				GFunction:    linkedhashmapGet,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".getOrDefault(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"] =
+			GMeth{
+				ParamSlots:   2,
+				GFunction:    linkedhashmapGet,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".hashCode()I"] =
+			GMeth{
+				ParamSlots:   0,
+				GFunction:    immutableHashCode,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".isEmpty()Z"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  linkedhashIsEmpty,
+			}
+
+		MethodSignatures[className+".keySet()Ljava/util/Set;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  linkedhashmapKeySet,
+			}
+
+		MethodSignatures[className+".size()I"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  linkedhashSize,
+			}
+
+		MethodSignatures[className+".toString()Ljava/lang/String;"] =
+			GMeth{
+				ParamSlots:   0,
+				GFunction:    linkedhashToString,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".values()Ljava/util/Collection;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  linkedhashmapValues,
+			}
+
+		registerUnsupportedMethods(className, mapWriteMethods)
+	}
+
+	registerMapEntryMethods(classNameKeyValueHolder)
+}
+
+// registers the methods, given by their names and descriptors, as ones that throw an
+// UnsupportedOperationException
+func registerUnsupportedMethods(className string, methods []string) {
+	for _, method := range methods {
+		desc := method[strings.Index(method, "("):]
+		MethodSignatures[className+"."+method] =
+			GMeth{
+				ParamSlots: len(util.ParseIncomingParamsFromMethTypeString(desc)),
+				GFunction:  immutableUnsupported,
+			}
+	}
+}
+
+// the methods that would change an immutable collection or an unmodifiable view
+func immutableUnsupported(params []interface{}) interface{} {
+	return getGErrBlk(excNames.UnsupportedOperationException, "immutableUnsupported: the collection can't be changed")
+}
+
+// immutableList is the state of an immutable list
+type immutableList struct {
+	elems []*object.Object
+}
+
+// the state of an iterator over an immutable list
+type immutableListIterator struct {
+	elems []*object.Object
+	pos   int
+}
+
+// makes an immutable list of the class holding the elements, which it takes over
+func newImmutableListObject(className string, elems []*object.Object) *object.Object {
+	return object.MakeOneFieldObject(className, fieldNameImmutableList, types.ImmutableList,
+		&immutableList{elems: elems})
+}
+
+// makes an immutable set or map of the class, holding the store
+func newImmutableLinkedHashObject(className string, view *linkedHashView) *object.Object {
+	view.store.immutable = true
+	return object.MakeOneFieldObject(className, fieldNameLinkedHash, types.LinkedHash, view)
+}
+
+// returns the elements of the immutable list in params[index]
+func getImmutableList(funcName string, params []interface{}, index int) ([]*object.Object, *GErrBlk) {
+	obj, ok := params[index].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return nil, getGErrBlk(excNames.NullPointerException, funcName+": the list is null")
+	}
+	lst, ok := obj.FieldTable[fieldNameImmutableList].Fvalue.(*immutableList)
+	if !ok {
+		return nil, getGErrBlk(excNames.IllegalStateException, funcName+": not an immutable list")
+	}
+	return lst.elems, nil
+}
+
+// returns the objects of the parameters, none of which may be null, as List.of() and the
+// like require
+func nonNullElements(funcName string, params []interface{}) ([]*object.Object, *GErrBlk) {
+	elems := make([]*object.Object, len(params))
+	for i, param := range params {
+		elem, ok := param.(*object.Object)
+		if !ok || object.IsNull(elem) {
+			return nil, getGErrBlk(excNames.NullPointerException, funcName+": an element is null")
+		}
+		elems[i] = elem
+	}
+	return elems, nil
+}
+
+// returns the elements of the array in params[index], none of which may be null
+func nonNullArrayElements(funcName string, params []interface{}, index int) ([]*object.Object, *GErrBlk) {
+	arr, ok := params[index].(*object.Object)
+	if !ok || object.IsNull(arr) {
+		return nil, getGErrBlk(excNames.NullPointerException, funcName+": the array is null")
+	}
+	elems, _ := arr.FieldTable["value"].Fvalue.([]*object.Object)
+	params = make([]interface{}, len(elems))
+	for i, elem := range elems {
+		params[i] = elem
+	}
+	return nonNullElements(funcName, params)
+}
+
+// "java/util/List.of()Ljava/util/List;" and the overloads with up to ten elements
+func listOf(params []interface{}) interface{} {
+	elems, gErr := nonNullElements("listOf", params)
+	if gErr != nil {
+		return gErr
+	}
+	return newImmutableListObject(classNameImmutableList, elems)
+}
+
+// "java/util/List.of([Ljava/lang/Object;)Ljava/util/List;"
+func listOfArray(params []interface{}) interface{} {
+	elems, gErr := nonNullArrayElements("listOfArray", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	return newImmutableListObject(classNameImmutableList, elems)
+}
+
+// "java/util/List.copyOf(Ljava/util/Collection;)Ljava/util/List;" -- an immutable list made
+// by List.of() is returned as it is
+func listCopyOf(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	source, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(source) {
+		return getGErrBlk(excNames.NullPointerException, "listCopyOf: the collection is null")
+	}
+	if *stringPool.GetStringPointer(source.KlassName) == classNameImmutableList {
+		return source
+	}
+	elems, gErr := collectionElements(fs, source)
+	if gErr != nil {
+		return gErr
+	}
+	params = make([]interface{}, len(elems))
+	for i, elem := range elems {
+		params[i] = elem
+	}
+	return listOf(params)
+}
+
+// makes an immutable set of the elements. Set.of() throws an IllegalArgumentException if an
+// element is given twice; Set.copyOf() keeps the first.
+func newImmutableSet(fs *list.List, funcName string, elems []*object.Object, duplicatesAllowed bool) interface{} {
+	view := newLinkedHashView(false)
+	for _, elem := range elems {
+		if object.IsNull(elem) {
+			return getGErrBlk(excNames.NullPointerException, funcName+": an element is null")
+		}
+		_, found, gErr := view.store.put(fs, elem, nil, false)
+		if gErr != nil {
+			return gErr
+		}
+		if found && !duplicatesAllowed {
+			str, _ := javaObjectString(fs, elem)
+			return getGErrBlk(excNames.IllegalArgumentException, funcName+": duplicate element: "+str)
+		}
+	}
+	return newImmutableLinkedHashObject(classNameImmutableSet, view)
+}
+
+// "java/util/Set.of()Ljava/util/Set;" and the overloads with up to ten elements
+func setOf(params []interface{}) interface{} {
+	elems, gErr := nonNullElements("setOf", params[1:])
+	if gErr != nil {
+		return gErr
+	}
+	return newImmutableSet(params[0].(*list.List), "setOf", elems, false)
+}
+
+// "java/util/Set.of([Ljava/lang/Object;)Ljava/util/Set;"
+func setOfArray(params []interface{}) interface{} {
+	elems, gErr := nonNullArrayElements("setOfArray", params, 1)
+	if gErr != nil {
+		return gErr
+	}
+	return newImmutableSet(params[0].(*list.List), "setOfArray", elems, false)
+}
+
+// "java/util/Set.copyOf(Ljava/util/Collection;)Ljava/util/Set;" -- an immutable set made by
+// Set.of() is returned as it is
+func setCopyOf(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	source, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(source) {
+		return getGErrBlk(excNames.NullPointerException, "setCopyOf: the collection is null")
+	}
+	if *stringPool.GetStringPointer(source.KlassName) == classNameImmutableSet {
+		return source
+	}
+	elems, gErr := collectionElements(fs, source)
+	if gErr != nil {
+		return gErr
+	}
+	return newImmutableSet(fs, "setCopyOf", elems, true)
+}
+
+// makes an immutable map of the entries, none of whose keys or values may be null, and
+// whose keys must be distinct
+func newImmutableMap(fs *list.List, funcName string, entries []*mapEntry) interface{} {
+	view := newLinkedHashView(true)
+	for _, entry := range entries {
+		if object.IsNull(entry.key) || object.IsNull(entry.value) {
+			return getGErrBlk(excNames.NullPointerException, funcName+": a key or value is null")
+		}
+		_, found, gErr := view.store.put(fs, entry.key, entry.value, false)
+		if gErr != nil {
+			return gErr
+		}
+		if found {
+			str, _ := javaObjectString(fs, entry.key)
+			return getGErrBlk(excNames.IllegalArgumentException, funcName+": duplicate key: "+str)
+		}
+	}
+	return newImmutableLinkedHashObject(classNameImmutableMap, view)
+}
+
+// "java/util/Map.of()Ljava/util/Map;" and the overloads with up to ten keys and values, which
+// alternate in the parameters
+func mapOf(params []interface{}) interface{} {
+	var entries []*mapEntry
+	for i := 1; i+1 < len(params); i += 2 {
+		key, _ := params[i].(*object.Object)
+		entries = append(entries, &mapEntry{key: key, value: params[i+1]})
+	}
+	return newImmutableMap(params[0].(*list.List), "mapOf", entries)
+}
+
+// "java/util/Map.entry(Ljava/lang/Object;Ljava/lang/Object;)Ljava/util/Map$Entry;" -- an
+// immutable entry, whose key and value may not be null
+func mapEntryOf(params []interface{}) interface{} {
+	key, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(key) || object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "mapEntryOf: the key or value is null")
+	}
+	return newMapEntryObject(classNameKeyValueHolder, &mapEntry{key: key, value: params[1]}, true)
+}
+
+// returns the key and value of a Map.Entry: directly, from the entries of the maps
+// implemented in Go, or from its getKey() and getValue() in Java
+func javaMapEntry(fs *list.List, entryObj *object.Object) (*mapEntry, *GErrBlk) {
+	if state, ok := entryObj.FieldTable[fieldNameMapEntry].Fvalue.(*mapEntryState); ok {
+		return state.entry, nil
+	}
+	key, gErr := invokeJavaMethod(fs, "java/util/Map$Entry", "getKey", "()Ljava/lang/Object;", entryObj, nil)
+	if gErr != nil {
+		return nil, gErr
+	}
+	value, gErr := invokeJavaMethod(fs, "java/util/Map$Entry", "getValue", "()Ljava/lang/Object;", entryObj, nil)
+	if gErr != nil {
+		return nil, gErr
+	}
+	keyObj, _ := key.(*object.Object)
+	return &mapEntry{key: keyObj, value: value}, nil
+}
+
+// "java/util/Map.ofEntries([Ljava/util/Map$Entry;)Ljava/util/Map;"
+func mapOfEntries(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	entryObjs, gErr := nonNullArrayElements("mapOfEntries", params, 1)
+	if gErr != nil {
+		return gErr
+	}
+	entries := make([]*mapEntry, len(entryObjs))
+	for i, entryObj := range entryObjs {
+		if entries[i], gErr = javaMapEntry(fs, entryObj); gErr != nil {
+			return gErr
+		}
+	}
+	return newImmutableMap(fs, "mapOfEntries", entries)
+}
+
+// "java/util/Map.copyOf(Ljava/util/Map;)Ljava/util/Map;" -- the entries are copied as
+// LinkedHashMap.putAll() copies them. An immutable map made by Map.of() is returned as it is.
+func mapCopyOf(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	source, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(source) {
+		return getGErrBlk(excNames.NullPointerException, "mapCopyOf: the map is null")
+	}
+	if *stringPool.GetStringPointer(source.KlassName) == classNameImmutableMap {
+		return source
+	}
+	cp := newLinkedHashMapObject(classNameLinkedHashMap)
+	if ret := linkedhashmapPutAll([]interface{}{fs, cp, source}); ret != nil {
+		return ret
+	}
+	view := cp.FieldTable[fieldNameLinkedHash].Fvalue.(*linkedHashView)
+	var entries []*mapEntry
+	for elem := view.store.order.Front(); elem != nil; elem = elem.Next() {
+		entries = append(entries, linkedHashEntry(elem))
+	}
+	return newImmutableMap(fs, "mapCopyOf", entries)
+}
+
+// returns the index of the first (or, if last is true, the last) element of the list in
+// params[1] that equals params[2], or -1 if there is none
+func immutablelistFind(funcName string, params []interface{}, last bool) (int, *GErrBlk) {
+	fs := params[0].(*list.List)
+	elems, gErr := getImmutableList(funcName, params, 1)
+	if gErr != nil {
+		return -1, gErr
+	}
+	for i := range elems {
+		index := i
+		if last {
+			index = len(elems) - 1 - i
+		}
+		equal, gErr := javaObjectsEqual(fs, params[2], elems[index])
+		if gErr != nil {
+			return -1, gErr
+		}
+		if equal {
+			return index, nil
+		}
+	}
+	return -1, nil
+}
+
+// "java/util/ImmutableCollections$ListN.contains(Ljava/lang/Object;)Z"
+func immutablelistContains(params []interface{}) interface{} {
+	index, gErr := immutablelistFind("immutablelistContains", params, false)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(index >= 0)
+}
+
+// "java/util/ImmutableCollections$ListN.indexOf(Ljava/lang/Object;)I"
+func immutablelistIndexOf(params []interface{}) interface{} {
+	index, gErr := immutablelistFind("immutablelistIndexOf", params, false)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(index)
+}
+
+// "java/util/ImmutableCollections$ListN.lastIndexOf(Ljava/lang/Object;)I"
+func immutablelistLastIndexOf(params []interface{}) interface{} {
+	index, gErr := immutablelistFind("immutablelistLastIndexOf", params, true)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(index)
+}
+
+// "java/util/ImmutableCollections$ListN.containsAll(Ljava/util/Collection;)Z"
+func immutablelistContainsAll(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	source, ok := params[2].(*object.Object)
+	if !ok || object.IsNull(source) {
+		return getGErrBlk(excNames.NullPointerException, "immutablelistContainsAll: the collection is null")
+	}
+	others, gErr := collectionElements(fs, source)
+	if gErr != nil {
+		return gErr
+	}
+	for _, other := range others {
+		index, gErr := immutablelistFind("immutablelistContainsAll", []interface{}{fs, params[1], other}, false)
+		if gErr != nil {
+			return gErr
+		}
+		if index < 0 {
+			return types.JavaBoolFalse
+		}
+	}
+	return types.JavaBoolTrue
+}
+
+// reports whether the object is an instance of the interface, which is java/util/List,
+// java/util/Set, or java/util/Map. The classes implemented in Go aren't in the method
+// area, so those that implement these interfaces are named here.
+func isCollectionInstance(obj *object.Object, iface string) bool {
+	className := *stringPool.GetStringPointer(obj.KlassName)
+	goClasses := map[string][]string{
+		"java/util/List": append([]string{classNameLinkedList, classNameUnmodifiableList}, immutableListClasses...),
+		"java/util/Set": append([]string{classNameLinkedHashSet, classNameTreeSet, classNameUnmodifiableSet,
+			classNameUnmodifiableEntrySet}, immutableSetClasses...),
+		"java/util/Map": append([]string{classNameHashMap, classNameLinkedHashMap, classNameTreeMap,
+			classNameUnmodifiableMap}, immutableMapClasses...),
+	}
+	for _, goClass := range goClasses[iface] {
+		if className == goClass {
+			return true
+		}
+	}
+	if iface == "java/util/Set" && strings.HasPrefix(className, classNameLinkedHashMap+"$") {
+		return className != linkedHashViewClasses[mapIterateValues] // the key and entry sets
+	}
+	return classloader.IsAssignableTo(className, iface)
+}
+
+// "java/util/ImmutableCollections$ListN.equals(Ljava/lang/Object;)Z" -- true if the other
+// object is a list of equal elements in the same order
+func immutablelistEquals(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	elems, gErr := getImmutableList("immutablelistEquals", params, 1)
+	if gErr != nil {
+		return gErr
+	}
+	other, ok := params[2].(*object.Object)
+	if !ok || object.IsNull(other) || !isCollectionInstance(other, "java/util/List") {
+		return types.JavaBoolFalse
+	}
+	others, gErr := collectionElements(fs, other)
+	if gErr != nil {
+		return gErr
+	}
+	if len(others) != len(elems) {
+		return types.JavaBoolFalse
+	}
+	for i := range elems {
+		equal, gErr := javaObjectsEqual(fs, elems[i], others[i])
+		if gErr != nil {
+			return gErr
+		}
+		if !equal {
+			return types.JavaBoolFalse
+		}
+	}
+	return types.JavaBoolTrue
+}
+
+// "java/util/ImmutableCollections$ListN.hashCode()I" -- computed from the hash codes of the
+// elements, as List.hashCode() specifies
+func immutablelistHashCode(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	elems, gErr := getImmutableList("immutablelistHashCode", params, 1)
+	if gErr != nil {
+		return gErr
+	}
+	hash := int32(1)
+	for _, elem := range elems {
+		elemHash, gErr := javaObjectHashCode(fs, elem)
+		if gErr != nil {
+			return gErr
+		}
+		hash = 31*hash + elemHash
+	}
+	return int64(hash)
+}
+
+// "java/util/ImmutableCollections$ListN.get(I)Ljava/lang/Object;"
+func immutablelistGet(params []interface{}) interface{} {
+	elems, gErr := getImmutableList("immutablelistGet", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	index := params[1].(int64)
+	if index < 0 || index >= int64(len(elems)) {
+		errMsg := fmt.Sprintf("immutablelistGet: index %d out of bounds for length %d", index, len(elems))
+		return getGErrBlk(excNames.IndexOutOfBoundsException, errMsg)
+	}
+	return elems[index]
+}
+
+// "java/util/ImmutableCollections$ListN.isEmpty()Z"
+func immutablelistIsEmpty(params []interface{}) interface{} {
+	elems, gErr := getImmutableList("immutablelistIsEmpty", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(len(elems) == 0)
+}
+
+// "java/util/ImmutableCollections$ListN.size()I"
+func immutablelistSize(params []interface{}) interface{} {
+	elems, gErr := getImmutableList("immutablelistSize", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(len(elems))
+}
+
+// "java/util/ImmutableCollections$ListN.subList(II)Ljava/util/List;" -- an immutable list of
+// the elements from the first index up to the second
+func immutablelistSubList(params []interface{}) interface{} {
+	elems, gErr := getImmutableList("immutablelistSubList", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	from, to := params[1].(int64), params[2].(int64)
+	if from < 0 || to > int64(len(elems)) || from > to {
+		errMsg := fmt.Sprintf("immutablelistSubList: fromIndex %d, toIndex %d, size %d", from, to, len(elems))
+		return getGErrBlk(excNames.IndexOutOfBoundsException, errMsg)
+	}
+	return newImmutableListObject(classNameImmutableList, elems[from:to:to])
+}
+
+// "java/util/ImmutableCollections$ListN.toArray()[Ljava/lang/Object;"
+func immutablelistToArray(params []interface{}) interface{} {
+	elems, gErr := getImmutableList("immutablelistToArray", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	arr := object.Make1DimRefArray("java/lang/Object;", int64(len(elems)))
+	copy(arr.FieldTable["value"].Fvalue.([]*object.Object), elems)
+	return arr
+}
+
+// "java/util/ImmutableCollections$ListN.toString()Ljava/lang/String;" -- [first, ..., last]
+func immutablelistToString(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	elems, gErr := getImmutableList("immutablelistToString", params, 1)
+	if gErr != nil {
+		return gErr
+	}
+	strs := make([]string, 0, len(elems))
+	for _, elem := range elems {
+		str, gErr := javaObjectString(fs, elem)
+		if gErr != nil {
+			return gErr
+		}
+		strs = append(strs, str)
+	}
+	return object.StringObjectFromGoString("[" + strings.Join(strs, ", ") + "]")
+}
+
+// "java/util/ImmutableCollections$ListN.iterator()Ljava/util/Iterator;"
+func immutablelistIterator(params []interface{}) interface{} {
+	elems, gErr := getImmutableList("immutablelistIterator", params, 0)
+	if gErr != nil {
+		return gErr
+	}
+	return object.MakeOneFieldObject(classNameImmutableListIterator, fieldNameImmutableList, types.ImmutableList,
+		&immutableListIterator{elems: elems})
+}
+
+// "java/util/ImmutableCollections$ListItr.hasNext()Z"
+func immutablelistIteratorHasNext(params []interface{}) interface{} {
+	obj, _ := params[0].(*object.Object)
+	if iterator, ok := obj.FieldTable[fieldNameImmutableList].Fvalue.(*immutableListIterator); ok {
+		return types.ConvertGoBoolToJavaBool(iterator.pos < len(iterator.elems))
+	}
+	return types.JavaBoolFalse
+}
+
+// "java/util/ImmutableCollections$ListItr.next()Ljava/lang/Object;"
+func immutablelistIteratorNext(params []interface{}) interface{} {
+	obj, _ := params[0].(*object.Object)
+	iterator, ok := obj.FieldTable[fieldNameImmutableList].Fvalue.(*immutableListIterator)
+	if !ok {
+		return getGErrBlk(excNames.IllegalStateException, "immutablelistIteratorNext: not an immutable list iterator")
+	}
+	if iterator.pos >= len(iterator.elems) {
+		return getGErrBlk(excNames.NoSuchElementException, "immutablelistIteratorNext: no more elements")
+	}
+	iterator.pos++
+	return iterator.elems[iterator.pos-1]
+}
+
+// "java/util/ImmutableCollections$SetN.containsAll(Ljava/util/Collection;)Z"
+func immutablesetContainsAll(params []interface{}) interface{} {
+	fs, view, gErr := getLinkedHashViewContext("immutablesetContainsAll", params)
+	if gErr != nil {
+		return gErr
+	}
+	source, ok := params[2].(*object.Object)
+	if !ok || object.IsNull(source) {
+		return getGErrBlk(excNames.NullPointerException, "immutablesetContainsAll: the collection is null")
+	}
+	others, gErr := collectionElements(fs, source)
+	if gErr != nil {
+		return gErr
+	}
+	for _, other := range others {
+		elem, _, gErr := view.store.find(fs, other)
+		if gErr != nil {
+			return gErr
+		}
+		if elem == nil {
+			return types.JavaBoolFalse
+		}
+	}
+	return types.JavaBoolTrue
+}
+
+// "java/util/ImmutableCollections$SetN.equals(Ljava/lang/Object;)Z" -- true if the other
+// object is a set of the same size, all of whose elements are in this one
+func immutablesetEquals(params []interface{}) interface{} {
+	fs, view, gErr := getLinkedHashViewContext("immutablesetEquals", params)
+	if gErr != nil {
+		return gErr
+	}
+	other, ok := params[2].(*object.Object)
+	if !ok || object.IsNull(other) || !isCollectionInstance(other, "java/util/Set") {
+		return types.JavaBoolFalse
+	}
+	others, gErr := collectionElements(fs, other)
+	if gErr != nil {
+		return gErr
+	}
+	if len(others) != view.store.order.Len() {
+		return types.JavaBoolFalse
+	}
+	return immutablesetContainsAll(params)
+}
+
+// "java/util/ImmutableCollections$MapN.equals(Ljava/lang/Object;)Z" -- true if the other
+// object is a map of the same size that maps each key of this one to an equal value
+func immutablemapEquals(params []interface{}) interface{} {
+	fs, view, gErr := getLinkedHashViewContext("immutablemapEquals", params)
+	if gErr != nil {
+		return gErr
+	}
+	other, ok := params[2].(*object.Object)
+	if !ok || object.IsNull(other) || !isCollectionInstance(other, "java/util/Map") {
+		return types.JavaBoolFalse
+	}
+
+	// the maps held in the store of LinkedHashMap are read directly; others, by their
+	// size() and get() in Java
+	otherView, isLinked := other.FieldTable[fieldNameLinkedHash].Fvalue.(*linkedHashView)
+	var size any
+	if isLinked && otherView.isMap {
+		size = int64(otherView.store.order.Len())
+	} else if size, gErr = invokeJavaMethod(fs, "java/util/Map", "size", "()I", other, nil); gErr != nil {
+		return gErr
+	}
+	if size != int64(view.store.order.Len()) {
+		return types.JavaBoolFalse
+	}
+
+	for elem := view.store.order.Front(); elem != nil; elem = elem.Next() {
+		entry := linkedHashEntry(elem)
+		var value any = object.Null
+		if isLinked && otherView.isMap {
+			otherElem, _, gErr := otherView.store.find(fs, entry.key)
+			if gErr != nil {
+				return gErr
+			}
+			if otherElem != nil {
+				value = linkedHashEntry(otherElem).value
+			}
+		} else if value, gErr = invokeJavaMethod(fs, "java/util/Map", "get", "(Ljava/lang/Object;)Ljava/lang/Object;",
+			other, []any{entry.key}); gErr != nil {
+			return gErr
+		}
+		equal, gErr := javaObjectsEqual(fs, entry.value, value)
+		if gErr != nil {
+			return gErr
+		}
+		if !equal {
+			return types.JavaBoolFalse
+		}
+	}
+	return types.JavaBoolTrue
+}
+
+// "java/util/ImmutableCollections$SetN.hashCode()I" and the same method of the immutable
+// maps -- the sum of the hash codes of the elements or of the entries, as Set.hashCode()
+// and Map.hashCode() specify
+func immutableHashCode(params []interface{}) interface{} {
+	fs, view, gErr := getLinkedHashViewContext("immutableHashCode", params)
+	if gErr != nil {
+		return gErr
+	}
+	var hash int32
+	for elem := view.store.order.Front(); elem != nil; elem = elem.Next() {
+		entry := linkedHashEntry(elem)
+		keyHash, gErr := javaObjectHashCode(fs, entry.key)
+		if gErr != nil {
+			return gErr
+		}
+		valueHash, gErr := javaObjectHashCode(fs, entry.value)
+		if gErr != nil {
+			return gErr
+		}
+		hash += keyHash ^ valueHash
+	}
+	return int64(hash)
+}
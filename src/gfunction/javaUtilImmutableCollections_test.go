@@ -0,0 +1,144 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"testing"
+)
+
+func TestListOf(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	lst := listOf([]interface{}{treeInt(3), treeInt(1), treeInt(3)}).(*object.Object)
+
+	checkTreeString(t, "toString()", treeCall(immutablelistToString, lst), "[3, 1, 3]")
+	checkTreeKey(t, "get(1)", immutablelistGet([]interface{}{lst, int64(1)}), 1)
+	checkTreeError(t, "get(3)", immutablelistGet([]interface{}{lst, int64(3)}), excNames.IndexOutOfBoundsException)
+	if ret := treeCall(immutablelistLastIndexOf, lst, treeInt(3)); ret != int64(2) {
+		t.Errorf("lastIndexOf(3): expected 2, got %v", ret)
+	}
+	if ret := treeCall(immutablelistContains, lst, treeInt(2)); ret != types.JavaBoolFalse {
+		t.Errorf("contains(2): expected false, got %v", ret)
+	}
+	if ret := treeCall(immutablelistHashCode, lst); ret != int64(((31+3)*31+1)*31+3) {
+		t.Errorf("hashCode(): unexpected %v", ret)
+	}
+	checkTreeError(t, "List.of() with a null", listOf([]interface{}{treeInt(1), object.Null}),
+		excNames.NullPointerException)
+	checkTreeError(t, "add()", immutableUnsupported([]interface{}{lst, treeInt(4)}),
+		excNames.UnsupportedOperationException)
+
+	arr := object.Make1DimRefArray("java/lang/Object;", 2)
+	copy(arr.FieldTable["value"].Fvalue.([]*object.Object), []*object.Object{treeInt(3), treeInt(1)})
+	prefix := immutablelistSubList([]interface{}{lst, int64(0), int64(2)}).(*object.Object)
+	if ret := treeCall(immutablelistEquals, prefix, listOfArray([]interface{}{arr})); ret != types.JavaBoolTrue {
+		t.Errorf("equals() of subList(0, 2) and List.of(3, 1): expected true, got %v", ret)
+	}
+	if ret := treeCall(immutablelistEquals, prefix, setOf([]interface{}{list.New(), treeInt(3), treeInt(1)})); ret != types.JavaBoolFalse {
+		t.Errorf("equals() of a list and a set: expected false, got %v", ret)
+	}
+
+	it := immutablelistIterator([]interface{}{lst}).(*object.Object)
+	count := 0
+	for immutablelistIteratorHasNext([]interface{}{it}) == types.JavaBoolTrue {
+		immutablelistIteratorNext([]interface{}{it})
+		count++
+	}
+	if count != 3 {
+		t.Errorf("iterator(): expected 3 elements, got %d", count)
+	}
+	checkTreeError(t, "next() past the end", immutablelistIteratorNext([]interface{}{it}),
+		excNames.NoSuchElementException)
+
+	copied := treeCall(listCopyOf, newTestTreeSet(9, 8)).(*object.Object)
+	checkTreeString(t, "List.copyOf(a TreeSet)", treeCall(immutablelistToString, copied), "[8, 9]")
+	if treeCall(listCopyOf, copied) != copied {
+		t.Errorf("List.copyOf() of an immutable list did not return it")
+	}
+}
+
+func TestSetOfAndMapOf(t *testing.T) {
+	globals.InitGlobals("test")
+	fs := list.New()
+	set := setOf([]interface{}{fs, treeInt(5), treeInt(7)}).(*object.Object)
+
+	checkTreeString(t, "toString()", treeCall(linkedhashToString, set), "[5, 7]")
+	checkTreeError(t, "Set.of() with a duplicate", setOf([]interface{}{fs, treeInt(5), treeInt(5)}),
+		excNames.IllegalArgumentException)
+	if ret := treeCall(immutablesetEquals, set, newTestTreeSet(7, 5)); ret != types.JavaBoolTrue {
+		t.Errorf("equals() of Set.of(5, 7) and a TreeSet of 7 and 5: expected true, got %v", ret)
+	}
+	if ret := treeCall(immutableHashCode, set); ret != int64(12) {
+		t.Errorf("hashCode(): expected 12, got %v", ret)
+	}
+	copied := treeCall(setCopyOf, listOf([]interface{}{treeInt(2), treeInt(2), treeInt(1)}).(*object.Object)).(*object.Object)
+	checkTreeString(t, "Set.copyOf() of a list with a duplicate", treeCall(linkedhashToString, copied), "[2, 1]")
+
+	it := linkedhashIterator([]interface{}{set}).(*object.Object)
+	linkedhashIteratorNext([]interface{}{it})
+	checkTreeError(t, "remove() by the iterator", linkedhashIteratorRemove([]interface{}{it}),
+		excNames.UnsupportedOperationException)
+
+	m := mapOf([]interface{}{fs, object.StringObjectFromGoString("a"), treeInt(1),
+		object.StringObjectFromGoString("b"), treeInt(2)}).(*object.Object)
+	checkTreeString(t, "toString()", treeCall(linkedhashToString, m), "{a=1, b=2}")
+	checkTreeKey(t, "get(b)", treeCall(linkedhashmapGet, m, object.StringObjectFromGoString("b")), 2)
+	checkTreeError(t, "Map.of() with a duplicate key", mapOf([]interface{}{fs, treeInt(1), treeInt(1),
+		treeInt(1), treeInt(2)}), excNames.IllegalArgumentException)
+	checkTreeError(t, "Map.of() with a null value", mapOf([]interface{}{fs, treeInt(1), object.Null}),
+		excNames.NullPointerException)
+
+	keys := linkedhashmapKeySet([]interface{}{m}).(*object.Object)
+	checkTreeError(t, "keySet().remove()", treeCall(linkedhashsetRemove, keys, object.StringObjectFromGoString("a")),
+		excNames.UnsupportedOperationException)
+	entries := linkedhashmapEntrySet([]interface{}{m}).(*object.Object)
+	entry := linkedhashIteratorNext([]interface{}{linkedhashIterator([]interface{}{entries})})
+	checkTreeError(t, "setValue() of an entry", mapEntrySetValue([]interface{}{entry, treeInt(9)}),
+		excNames.UnsupportedOperationException)
+
+	arr := object.Make1DimRefArray("java/util/Map$Entry;", 1)
+	arr.FieldTable["value"].Fvalue.([]*object.Object)[0] =
+		mapEntryOf([]interface{}{object.StringObjectFromGoString("b"), treeInt(2)}).(*object.Object)
+	single := mapOfEntries([]interface{}{fs, arr}).(*object.Object)
+	if ret := treeCall(immutablemapEquals, single, m); ret != types.JavaBoolFalse {
+		t.Errorf("equals() of maps of different sizes: expected false, got %v", ret)
+	}
+	lhm := newLinkedHashMapObject(classNameLinkedHashMap)
+	treeCall(linkedhashmapPut, lhm, object.StringObjectFromGoString("b"), treeInt(2))
+	if ret := treeCall(immutablemapEquals, single, lhm); ret != types.JavaBoolTrue {
+		t.Errorf("equals() of Map.ofEntries(entry(b, 2)) and a LinkedHashMap {b=2}: expected true, got %v", ret)
+	}
+	if ret := treeCall(immutableHashCode, m); ret != int64((97^1)+(98^2)) {
+		t.Errorf("hashCode(): unexpected %v", ret)
+	}
+}
+
+func TestCollectionsEmptyAndSingleton(t *testing.T) {
+	globals.InitGlobals("test")
+
+	empty := collectionsEmptyList(nil).(*object.Object)
+	if ret := immutablelistIsEmpty([]interface{}{empty}); ret != types.JavaBoolTrue {
+		t.Errorf("emptyList().isEmpty(): expected true, got %v", ret)
+	}
+	checkTreeString(t, "emptyMap()", treeCall(linkedhashToString, collectionsEmptyMap(nil).(*object.Object)), "{}")
+	checkTreeString(t, "emptySet()", treeCall(linkedhashToString, collectionsEmptySet(nil).(*object.Object)), "[]")
+
+	single := collectionsSingletonList([]interface{}{object.Null}).(*object.Object)
+	checkTreeString(t, "singletonList(null)", treeCall(immutablelistToString, single), "[null]")
+	set := collectionsSingleton([]interface{}{list.New(), treeInt(4)}).(*object.Object)
+	if ret := treeCall(linkedhashContains, set, treeInt(4)); ret != types.JavaBoolTrue {
+		t.Errorf("singleton(4).contains(4): expected true, got %v", ret)
+	}
+	m := collectionsSingletonMap([]interface{}{list.New(), treeInt(1), object.StringObjectFromGoString("one")}).(*object.Object)
+	checkTreeString(t, "singletonMap(1, one)", treeCall(linkedhashToString, m), "{1=one}")
+}
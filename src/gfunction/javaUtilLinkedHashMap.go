@@ -0,0 +1,830 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"strings"
+)
+
+// LinkedHashMap and LinkedHashSet keep their entries in a linked list, in the order in
+// which their keys were first put, or, for a map created with accessOrder true, in the
+// order in which they were last accessed. The entries are found by the hash codes and the
+// equals() methods of their keys, which are computed in Go for Strings and the boxed
+// primitives and by upcalls to Java for other keys, so any key can be used, as in Java.
+// As in Java, a subclass of LinkedHashMap whose removeEldestEntry() returns true has its
+// eldest entry removed after a new entry is put. The views returned by keySet(), values(),
+// and entrySet() share the entries of the map, and iterators throw a
+// ConcurrentModificationException if the map is changed other than by their remove().
+
+var classNameLinkedHashMap = "java/util/LinkedHashMap"
+var classNameLinkedHashMapEntry = "java/util/LinkedHashMap$Entry"
+
+// The field of LinkedHashMap, LinkedHashSet, and their views and iterators that holds the Go state
+var fieldNameLinkedHash = "linkedHash"
+
+// the classes of the collection views and of the iterators, by what they return
+var linkedHashViewClasses = map[int]string{
+	mapIterateKeys:    "java/util/LinkedHashMap$LinkedKeySet",
+	mapIterateValues:  "java/util/LinkedHashMap$LinkedValues",
+	mapIterateEntries: "java/util/LinkedHashMap$LinkedEntrySet",
+}
+
+var linkedHashIteratorClasses = map[int]string{
+	mapIterateKeys:    "java/util/LinkedHashMap$LinkedKeyIterator",
+	mapIterateValues:  "java/util/LinkedHashMap$LinkedValueIterator",
+	mapIterateEntries: "java/util/LinkedHashMap$LinkedEntryIterator",
+}
+
+func Load_Util_LinkedHashMap() {
+
+	object.RegisterInstantiationHook(classNameLinkedHashMap, newLinkedHashMapObject)
+
+	MethodSignatures["java/util/LinkedHashMap.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  linkedhashmapInit,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.<init>(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  linkedhashmapInit,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.<init>(IF)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  linkedhashmapInit,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.<init>(IFZ)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  linkedhashInitAccessOrder,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.<init>(Ljava/util/Map;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    linkedhashmapInitFromMap,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.clear()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  linkedhashClear,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.clone()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  linkedhashClone,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.containsKey(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    linkedhashContains,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.containsValue(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    linkedhashmapContainsValue,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.entrySet()Ljava/util/Set;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  linkedhashmapEntrySet,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.get(Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    linkedhashmapGet,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.getOrDefault(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    linkedhashmapGet,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.isEmpty()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  linkedhashIsEmpty,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.keySet()Ljava/util/Set;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  linkedhashmapKeySet,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.put(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    linkedhashmapPut,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.putAll(Ljava/util/Map;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    linkedhashmapPutAll,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.putIfAbsent(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    linkedhashmapPutIfAbsent,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.remove(Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    linkedhashmapRemove,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.removeEldestEntry(Ljava/util/Map$Entry;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  returnFalse,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.size()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  linkedhashSize,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    linkedhashToString,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap.values()Ljava/util/Collection;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  linkedhashmapValues,
+		}
+
+	// the collections returned by keySet(), values(), and entrySet()
+
+	for _, className := range mapValues(linkedHashViewClasses) {
+		MethodSignatures[className+".isEmpty()Z"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  linkedhashIsEmpty,
+			}
+
+		MethodSignatures[className+".iterator()Ljava/util/Iterator;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  linkedhashIterator,
+			}
+
+		MethodSignatures[className+".size()I"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  linkedhashSize,
+			}
+
+		MethodSignatures[className+".toString()Ljava/lang/String;"] =
+			GMeth{
+				ParamSlots:   0,
+				GFunction:    linkedhashToString,
+				NeedsContext: true,
+			}
+	}
+
+	MethodSignatures["java/util/LinkedHashMap$LinkedKeySet.contains(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    linkedhashContains,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap$LinkedKeySet.remove(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    linkedhashsetRemove,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/LinkedHashMap$LinkedValues.contains(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    linkedhashmapContainsValue,
+			NeedsContext: true,
+		}
+
+	// the iterators
+
+	for _, className := range mapValues(linkedHashIteratorClasses) {
+		MethodSignatures[className+".hasNext()Z"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  linkedhashIteratorHasNext,
+			}
+
+		MethodSignatures[className+".next()Ljava/lang/Object;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  linkedhashIteratorNext,
+			}
+
+		MethodSignatures[className+".remove()V"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  linkedhashIteratorRemove,
+			}
+	}
+
+	// the entries
+
+	registerMapEntryMethods(classNameLinkedHashMapEntry)
+}
+
+// linkedHashStore holds the entries of a LinkedHashMap or a LinkedHashSet, in order. It is
+// shared by the map or set and all of its views.
+type linkedHashStore struct {
+	order       *list.List                // the *linkedHashNode of each key, in iteration order
+	buckets     map[int32][]*list.Element // the elements of order, by the hash codes of their keys
+	accessOrder bool                      // entries move to the end of order when they're accessed
+	modCount    int                       // the number of changes to the keys, which iterators check
+	immutable   bool                      // the store of a Set or Map that can't be changed, such as Set.of() returns
+}
+
+// an entry of the store and the hash code of its key
+type linkedHashNode struct {
+	*mapEntry
+	hash int32
+}
+
+// the state of a LinkedHashMap, a LinkedHashSet, or one of their views
+type linkedHashView struct {
+	store *linkedHashStore
+	kind  int  // what the view holds: keys, values, or entries
+	isMap bool // the view is the map itself
+}
+
+// the state of an iterator
+type linkedHashIterator struct {
+	store       *linkedHashStore
+	kind        int
+	next        *list.Element // the element next returned, or nil at the end
+	last        *list.Element // the element last returned, or nil if there is none to remove
+	expectedMod int
+}
+
+// returns the entry of an element of the order of a store
+func linkedHashEntry(elem *list.Element) *mapEntry {
+	return elem.Value.(*linkedHashNode).mapEntry
+}
+
+// returns an empty LinkedHashMap, if isMap is true, or LinkedHashSet
+func newLinkedHashView(isMap bool) *linkedHashView {
+	view := &linkedHashView{store: &linkedHashStore{order: list.New(), buckets: make(map[int32][]*list.Element)},
+		kind: mapIterateKeys, isMap: isMap}
+	if isMap {
+		view.kind = mapIterateEntries
+	}
+	return view
+}
+
+// the instantiation hook for LinkedHashMap: a new object holding an empty map
+func newLinkedHashMapObject(className string) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&className)
+	obj.FieldTable[fieldNameLinkedHash] = object.Field{Ftype: types.LinkedHash, Fvalue: newLinkedHashView(true)}
+	return obj
+}
+
+// returns the view of the LinkedHashMap, LinkedHashSet, or collection view in params[index]
+func getLinkedHashView(funcName string, params []interface{}, index int) (*linkedHashView, *GErrBlk) {
+	obj, ok := params[index].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return nil, getGErrBlk(excNames.NullPointerException, funcName+": the object is null")
+	}
+	view, ok := obj.FieldTable[fieldNameLinkedHash].Fvalue.(*linkedHashView)
+	if !ok {
+		errMsg := fmt.Sprintf("%s: %s is not a linked hash map", funcName, *stringPool.GetStringPointer(obj.KlassName))
+		return nil, getGErrBlk(excNames.IllegalStateException, errMsg)
+	}
+	return view, nil
+}
+
+// returns the view of the object in params[0], for the methods that don't need the context
+func getLinkedHashViewNoContext(funcName string, params []interface{}) (*linkedHashView, *GErrBlk) {
+	return getLinkedHashView(funcName, params, 0)
+}
+
+// returns the frame stack and the view of the object in params[1], for the methods that
+// need the context
+func getLinkedHashViewContext(funcName string, params []interface{}) (*list.List, *linkedHashView, *GErrBlk) {
+	fs, _ := params[0].(*list.List)
+	view, gErr := getLinkedHashView(funcName, params, 1)
+	return fs, view, gErr
+}
+
+// returns the element of the key and the key's hash code. The element is nil if the key
+// isn't in the store.
+func (s *linkedHashStore) find(fs *list.List, key *object.Object) (*list.Element, int32, *GErrBlk) {
+	hash, gErr := javaObjectHashCode(fs, key)
+	if gErr != nil {
+		return nil, 0, gErr
+	}
+	for _, elem := range s.buckets[hash] {
+		equal, gErr := javaObjectsEqual(fs, key, linkedHashEntry(elem).key)
+		if gErr != nil {
+			return nil, 0, gErr
+		}
+		if equal {
+			return elem, hash, nil
+		}
+	}
+	return nil, hash, nil
+}
+
+// moves the element to the end of the order, if the store is in access order
+func (s *linkedHashStore) access(elem *list.Element) {
+	if s.accessOrder && elem != s.order.Back() {
+		s.order.MoveToBack(elem)
+		s.modCount++
+	}
+}
+
+// puts the key and value in the store. Returns the previous value of the key, and whether
+// there was one. If onlyIfAbsent is true, the value of a key already there isn't changed.
+func (s *linkedHashStore) put(fs *list.List, key *object.Object, value any, onlyIfAbsent bool) (any, bool, *GErrBlk) {
+	elem, hash, gErr := s.find(fs, key)
+	if gErr != nil {
+		return nil, false, gErr
+	}
+	if elem != nil {
+		entry := linkedHashEntry(elem)
+		previous := entry.value
+		if !onlyIfAbsent || object.IsNull(previous) {
+			entry.value = value
+		}
+		s.access(elem)
+		return previous, true, nil
+	}
+	elem = s.order.PushBack(&linkedHashNode{mapEntry: &mapEntry{key: key, value: value}, hash: hash})
+	s.buckets[hash] = append(s.buckets[hash], elem)
+	s.modCount++
+	return nil, false, nil
+}
+
+// removes the element from the store
+func (s *linkedHashStore) removeElement(elem *list.Element) {
+	hash := elem.Value.(*linkedHashNode).hash
+	bucket := s.buckets[hash]
+	for i, e := range bucket {
+		if e == elem {
+			bucket = append(bucket[:i:i], bucket[i+1:]...)
+			break
+		}
+	}
+	if len(bucket) == 0 {
+		delete(s.buckets, hash)
+	} else {
+		s.buckets[hash] = bucket
+	}
+	s.order.Remove(elem)
+	s.modCount++
+}
+
+// removes the key from the store. Returns the removed entry, or nil if the key wasn't there.
+func (s *linkedHashStore) remove(fs *list.List, key *object.Object) (*mapEntry, *GErrBlk) {
+	elem, _, gErr := s.find(fs, key)
+	if gErr != nil || elem == nil {
+		return nil, gErr
+	}
+	s.removeElement(elem)
+	return linkedHashEntry(elem), nil
+}
+
+// returns a copy of the store, with the same keys and values
+func (s *linkedHashStore) clone() *linkedHashStore {
+	cp := &linkedHashStore{order: list.New(), buckets: make(map[int32][]*list.Element), accessOrder: s.accessOrder}
+	index := make(map[*list.Element]*list.Element)
+	for elem := s.order.Front(); elem != nil; elem = elem.Next() {
+		node := elem.Value.(*linkedHashNode)
+		index[elem] = cp.order.PushBack(&linkedHashNode{mapEntry: &mapEntry{key: node.key, value: node.value},
+			hash: node.hash})
+	}
+	for hash, bucket := range s.buckets {
+		for _, elem := range bucket {
+			cp.buckets[hash] = append(cp.buckets[hash], index[elem])
+		}
+	}
+	return cp
+}
+
+// returns the keys, values, or entries (key=value) of the store, as strings separated by commas
+func (s *linkedHashStore) joinEntries(fs *list.List, kind int) (string, *GErrBlk) {
+	var strs []string
+	for elem := s.order.Front(); elem != nil; elem = elem.Next() {
+		str, gErr := linkedHashEntry(elem).toString(fs, kind)
+		if gErr != nil {
+			return "", gErr
+		}
+		strs = append(strs, str)
+	}
+	return strings.Join(strs, ", "), nil
+}
+
+// "java/util/LinkedHashMap.<init>()V", <init>(I), and <init>(IF) -- the capacity and load
+// factor don't matter here
+func linkedhashmapInit(params []interface{}) interface{} {
+	obj, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return getGErrBlk(excNames.NullPointerException, "linkedhashmapInit: the object is null")
+	}
+	obj.FieldTable[fieldNameLinkedHash] = object.Field{Ftype: types.LinkedHash, Fvalue: newLinkedHashView(true)}
+	return nil
+}
+
+// "java/util/LinkedHashMap.<init>(IFZ)V" -- the boolean is true for access order
+func linkedhashInitAccessOrder(params []interface{}) interface{} {
+	if ret := linkedhashmapInit(params); ret != nil {
+		return ret
+	}
+	view, _ := getLinkedHashViewNoContext("linkedhashInitAccessOrder", params)
+	view.store.accessOrder = params[3].(int64) != types.JavaBoolFalse
+	return nil
+}
+
+// "java/util/LinkedHashMap.<init>(Ljava/util/Map;)V"
+func linkedhashmapInitFromMap(params []interface{}) interface{} {
+	if ret := linkedhashmapInit(params[1:]); ret != nil {
+		return ret
+	}
+	return linkedhashmapPutAll(params)
+}
+
+// "java/util/LinkedHashMap.clear()V" and "java/util/LinkedHashSet.clear()V"
+func linkedhashClear(params []interface{}) interface{} {
+	view, gErr := getLinkedHashViewNoContext("linkedhashClear", params)
+	if gErr != nil {
+		return gErr
+	}
+	view.store.order.Init()
+	clear(view.store.buckets)
+	view.store.modCount++
+	return nil
+}
+
+// "java/util/LinkedHashMap.clone()Ljava/lang/Object;" and "java/util/LinkedHashSet.clone()Ljava/lang/Object;"
+// The keys and values themselves aren't cloned.
+func linkedhashClone(params []interface{}) interface{} {
+	view, gErr := getLinkedHashViewNoContext("linkedhashClone", params)
+	if gErr != nil {
+		return gErr
+	}
+	className := *stringPool.GetStringPointer(params[0].(*object.Object).KlassName)
+	obj := object.MakeEmptyObjectWithClassName(&className)
+	obj.FieldTable[fieldNameLinkedHash] = object.Field{Ftype: types.LinkedHash,
+		Fvalue: &linkedHashView{store: view.store.clone(), kind: view.kind, isMap: view.isMap}}
+	return obj
+}
+
+// "java/util/LinkedHashMap.containsKey(Ljava/lang/Object;)Z", "java/util/LinkedHashSet.contains(Ljava/lang/Object;)Z",
+// and the contains() of keySet()
+func linkedhashContains(params []interface{}) interface{} {
+	fs, view, gErr := getLinkedHashViewContext("linkedhashContains", params)
+	if gErr != nil {
+		return gErr
+	}
+	elem, _, gErr := view.store.find(fs, treeKeyParam(params, 2))
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(elem != nil)
+}
+
+// "java/util/LinkedHashMap.containsValue(Ljava/lang/Object;)Z" and the contains() of values()
+func linkedhashmapContainsValue(params []interface{}) interface{} {
+	fs, view, gErr := getLinkedHashViewContext("linkedhashmapContainsValue", params)
+	if gErr != nil {
+		return gErr
+	}
+	for elem := view.store.order.Front(); elem != nil; elem = elem.Next() {
+		equal, gErr := javaObjectsEqual(fs, params[2], linkedHashEntry(elem).value)
+		if gErr != nil {
+			return gErr
+		}
+		if equal {
+			return types.JavaBoolTrue
+		}
+	}
+	return types.JavaBoolFalse
+}
+
+// "java/util/LinkedHashMap.get(Ljava/lang/Object;)Ljava/lang/Object;" and
+// getOrDefault(Object, Object), which returns its second argument if the key isn't there
+func linkedhashmapGet(params []interface{}) interface{} {
+	fs, view, gErr := getLinkedHashViewContext("linkedhashmapGet", params)
+	if gErr != nil {
+		return gErr
+	}
+	elem, _, gErr := view.store.find(fs, treeKeyParam(params, 2))
+	if gErr != nil {
+		return gErr
+	}
+	if elem == nil {
+		if len(params) > 3 {
+			return params[3]
+		}
+		return object.Null
+	}
+	view.store.access(elem)
+	return linkedHashEntry(elem).value
+}
+
+// puts the key and value in the map in params[1], and removes the eldest entry if the map
+// is of a subclass whose removeEldestEntry() returns true. Returns the previous value of
+// the key or null.
+func linkedhashmapPutEntry(fs *list.List, params []interface{}, key *object.Object, value any,
+	onlyIfAbsent bool) interface{} {
+	view, gErr := getLinkedHashView("linkedhashmapPut", params, 1)
+	if gErr != nil {
+		return gErr
+	}
+	previous, found, gErr := view.store.put(fs, key, value, onlyIfAbsent)
+	if gErr != nil {
+		return gErr
+	}
+	if found {
+		return previous
+	}
+
+	obj := params[1].(*object.Object)
+	if *stringPool.GetStringPointer(obj.KlassName) != classNameLinkedHashMap {
+		eldest := newMapEntryObject(classNameLinkedHashMapEntry, linkedHashEntry(view.store.order.Front()), false)
+		ret, gErr := invokeJavaMethod(fs, classNameLinkedHashMap, "removeEldestEntry", "(Ljava/util/Map$Entry;)Z",
+			obj, []any{eldest})
+		if gErr != nil {
+			return gErr
+		}
+		if ret == types.JavaBoolTrue {
+			view.store.removeElement(view.store.order.Front())
+		}
+	}
+	return object.Null
+}
+
+// "java/util/LinkedHashMap.put(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"
+func linkedhashmapPut(params []interface{}) interface{} {
+	return linkedhashmapPutEntry(params[0].(*list.List), params, treeKeyParam(params, 2), params[3], false)
+}
+
+// "java/util/LinkedHashMap.putIfAbsent(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"
+// -- a key whose value is null counts as absent, as in Java
+func linkedhashmapPutIfAbsent(params []interface{}) interface{} {
+	return linkedhashmapPutEntry(params[0].(*list.List), params, treeKeyParam(params, 2), params[3], true)
+}
+
+// "java/util/LinkedHashMap.putAll(Ljava/util/Map;)V" -- the entries of the maps implemented
+// in Go are copied directly; those of other maps are obtained through their entrySet() in Java
+func linkedhashmapPutAll(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	source := treeKeyParam(params, 2)
+	if object.IsNull(source) {
+		return getGErrBlk(excNames.NullPointerException, "linkedhashmapPutAll: the map is null")
+	}
+
+	var entries []*mapEntry
+	if sourceView, ok := source.FieldTable[fieldNameLinkedHash].Fvalue.(*linkedHashView); ok {
+		for elem := sourceView.store.order.Front(); elem != nil; elem = elem.Next() {
+			entries = append(entries, linkedHashEntry(elem))
+		}
+	} else if sourceView, ok := source.FieldTable[fieldNameTreeState].Fvalue.(*treeView); ok {
+		from, to, gErr := sourceView.span(fs)
+		if gErr != nil {
+			return gErr
+		}
+		entries = append(entries, sourceView.store.entries[from:to]...)
+	} else {
+		entrySet, gErr := invokeJavaMethod(fs, "java/util/Map", "entrySet", "()Ljava/util/Set;", source, nil)
+		if gErr != nil {
+			return gErr
+		}
+		ret, gErr := invokeJavaMethod(fs, "java/util/Set", "toArray", "()[Ljava/lang/Object;", entrySet, nil)
+		if gErr != nil {
+			return gErr
+		}
+		entryObjs, _ := ret.(*object.Object).FieldTable["value"].Fvalue.([]*object.Object)
+		for _, entryObj := range entryObjs {
+			key, gErr := invokeJavaMethod(fs, "java/util/Map$Entry", "getKey", "()Ljava/lang/Object;", entryObj, nil)
+			if gErr != nil {
+				return gErr
+			}
+			value, gErr := invokeJavaMethod(fs, "java/util/Map$Entry", "getValue", "()Ljava/lang/Object;", entryObj, nil)
+			if gErr != nil {
+				return gErr
+			}
+			keyObj, _ := key.(*object.Object)
+			entries = append(entries, &mapEntry{key: keyObj, value: value})
+		}
+	}
+
+	for _, entry := range entries {
+		if ret := linkedhashmapPutEntry(fs, params, entry.key, entry.value, false); ret != nil {
+			if gErr, ok := ret.(*GErrBlk); ok {
+				return gErr
+			}
+		}
+	}
+	return nil
+}
+
+// "java/util/LinkedHashMap.remove(Ljava/lang/Object;)Ljava/lang/Object;"
+func linkedhashmapRemove(params []interface{}) interface{} {
+	fs, view, gErr := getLinkedHashViewContext("linkedhashmapRemove", params)
+	if gErr != nil {
+		return gErr
+	}
+	entry, gErr := view.store.remove(fs, treeKeyParam(params, 2))
+	if gErr != nil {
+		return gErr
+	}
+	if entry == nil {
+		return object.Null
+	}
+	return entry.value
+}
+
+// "java/util/LinkedHashMap.size()I", "java/util/LinkedHashSet.size()I", and the size() of the views
+func linkedhashSize(params []interface{}) interface{} {
+	view, gErr := getLinkedHashViewNoContext("linkedhashSize", params)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(view.store.order.Len())
+}
+
+// "java/util/LinkedHashMap.isEmpty()Z", "java/util/LinkedHashSet.isEmpty()Z", and the
+// isEmpty() of the views
+func linkedhashIsEmpty(params []interface{}) interface{} {
+	view, gErr := getLinkedHashViewNoContext("linkedhashIsEmpty", params)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(view.store.order.Len() == 0)
+}
+
+// "java/util/LinkedHashMap.toString()Ljava/lang/String;" -- {key1=value1, key2=value2} --
+// and the toString() of LinkedHashSet and of the views, [element1, element2]
+func linkedhashToString(params []interface{}) interface{} {
+	fs, view, gErr := getLinkedHashViewContext("linkedhashToString", params)
+	if gErr != nil {
+		return gErr
+	}
+	str, gErr := view.store.joinEntries(fs, view.kind)
+	if gErr != nil {
+		return gErr
+	}
+	if view.isMap {
+		return object.StringObjectFromGoString("{" + str + "}")
+	}
+	return object.StringObjectFromGoString("[" + str + "]")
+}
+
+// makes a view of the map in params[0] that holds the kind of element
+func linkedhashmapView(funcName string, params []interface{}, kind int) interface{} {
+	view, gErr := getLinkedHashViewNoContext(funcName, params)
+	if gErr != nil {
+		return gErr
+	}
+	className := linkedHashViewClasses[kind]
+	obj := object.MakeEmptyObjectWithClassName(&className)
+	obj.FieldTable[fieldNameLinkedHash] = object.Field{Ftype: types.LinkedHash,
+		Fvalue: &linkedHashView{store: view.store, kind: kind}}
+	return obj
+}
+
+// "java/util/LinkedHashMap.keySet()Ljava/util/Set;"
+func linkedhashmapKeySet(params []interface{}) interface{} {
+	return linkedhashmapView("linkedhashmapKeySet", params, mapIterateKeys)
+}
+
+// "java/util/LinkedHashMap.values()Ljava/util/Collection;"
+func linkedhashmapValues(params []interface{}) interface{} {
+	return linkedhashmapView("linkedhashmapValues", params, mapIterateValues)
+}
+
+// "java/util/LinkedHashMap.entrySet()Ljava/util/Set;"
+func linkedhashmapEntrySet(params []interface{}) interface{} {
+	return linkedhashmapView("linkedhashmapEntrySet", params, mapIterateEntries)
+}
+
+// "java/util/LinkedHashSet.iterator()Ljava/util/Iterator;" and the iterator() of the views of LinkedHashMap
+func linkedhashIterator(params []interface{}) interface{} {
+	view, gErr := getLinkedHashViewNoContext("linkedhashIterator", params)
+	if gErr != nil {
+		return gErr
+	}
+	className := linkedHashIteratorClasses[view.kind]
+	obj := object.MakeEmptyObjectWithClassName(&className)
+	obj.FieldTable[fieldNameLinkedHash] = object.Field{Ftype: types.LinkedHash,
+		Fvalue: &linkedHashIterator{store: view.store, kind: view.kind, next: view.store.order.Front(),
+			expectedMod: view.store.modCount}}
+	return obj
+}
+
+// returns the state of the iterator in params[0], which must not have been overtaken by
+// changes to the map
+func getLinkedHashIterator(funcName string, params []interface{}) (*linkedHashIterator, *GErrBlk) {
+	obj, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return nil, getGErrBlk(excNames.NullPointerException, funcName+": the iterator is null")
+	}
+	iterator, ok := obj.FieldTable[fieldNameLinkedHash].Fvalue.(*linkedHashIterator)
+	if !ok {
+		return nil, getGErrBlk(excNames.IllegalStateException, funcName+": not a linked hash iterator")
+	}
+	if iterator.expectedMod != iterator.store.modCount {
+		return nil, getGErrBlk(excNames.ConcurrentModificationException, funcName+": the map was changed")
+	}
+	return iterator, nil
+}
+
+// the hasNext() of the iterators of LinkedHashMap and LinkedHashSet
+func linkedhashIteratorHasNext(params []interface{}) interface{} {
+	obj, _ := params[0].(*object.Object)
+	if iterator, ok := obj.FieldTable[fieldNameLinkedHash].Fvalue.(*linkedHashIterator); ok {
+		return types.ConvertGoBoolToJavaBool(iterator.next != nil)
+	}
+	return types.JavaBoolFalse
+}
+
+// the next() of the iterators of LinkedHashMap and LinkedHashSet
+func linkedhashIteratorNext(params []interface{}) interface{} {
+	iterator, gErr := getLinkedHashIterator("linkedhashIteratorNext", params)
+	if gErr != nil {
+		return gErr
+	}
+	if iterator.next == nil {
+		return getGErrBlk(excNames.NoSuchElementException, "linkedhashIteratorNext: no more elements")
+	}
+	iterator.last = iterator.next
+	iterator.next = iterator.next.Next()
+
+	entry := linkedHashEntry(iterator.last)
+	switch iterator.kind {
+	case mapIterateValues:
+		return entry.value
+	case mapIterateEntries:
+		return newMapEntryObject(classNameLinkedHashMapEntry, entry, iterator.store.immutable)
+	}
+	return entry.key
+}
+
+// the remove() of the iterators of LinkedHashMap and LinkedHashSet -- removes the element
+// last returned
+func linkedhashIteratorRemove(params []interface{}) interface{} {
+	iterator, gErr := getLinkedHashIterator("linkedhashIteratorRemove", params)
+	if gErr != nil {
+		return gErr
+	}
+	if iterator.store.immutable {
+		return getGErrBlk(excNames.UnsupportedOperationException, "linkedhashIteratorRemove: the collection is immutable")
+	}
+	if iterator.last == nil {
+		return getGErrBlk(excNames.IllegalStateException, "linkedhashIteratorRemove: next() has not been called")
+	}
+	iterator.store.removeElement(iterator.last)
+	iterator.expectedMod = iterator.store.modCount
+	iterator.last = nil
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"testing"
+)
+
+func newTestLinkedHashMap(keys ...int64) *object.Object {
+	lhm := newLinkedHashMapObject(classNameLinkedHashMap)
+	for _, key := range keys {
+		treeCall(linkedhashmapPut, lhm, treeInt(key), object.StringObjectFromGoString(object.JavaDoubleString(float64(key))))
+	}
+	return lhm
+}
+
+func TestLinkedHashMapInsertionOrder(t *testing.T) {
+	globals.InitGlobals("test")
+	lhm := newTestLinkedHashMap(30, 10, 20)
+
+	checkTreeString(t, "toString()", treeCall(linkedhashToString, lhm), "{30=30.0, 10=10.0, 20=20.0}")
+	checkTreeString(t, "put() of an existing key", treeCall(linkedhashmapPut, lhm, treeInt(30),
+		object.StringObjectFromGoString("x")), "30.0")
+	checkTreeString(t, "toString() after replacing a value", treeCall(linkedhashToString, lhm),
+		"{30=x, 10=10.0, 20=20.0}")
+	checkTreeString(t, "get(10)", treeCall(linkedhashmapGet, lhm, treeInt(10)), "10.0")
+	if ret := treeCall(linkedhashmapGet, lhm, treeInt(99)); !object.IsNull(ret) {
+		t.Errorf("get(99): expected null, got %v", ret)
+	}
+	checkTreeString(t, "getOrDefault(99)", treeCall(linkedhashmapGet, lhm, treeInt(99),
+		object.StringObjectFromGoString("none")), "none")
+	if ret := treeCall(linkedhashmapContainsValue, lhm, object.StringObjectFromGoString("x")); ret != types.JavaBoolTrue {
+		t.Errorf("containsValue(x): expected true, got %v", ret)
+	}
+
+	checkTreeString(t, "remove(10)", treeCall(linkedhashmapRemove, lhm, treeInt(10)), "10.0")
+	treeCall(linkedhashmapPut, lhm, treeInt(10), object.StringObjectFromGoString("y"))
+	checkTreeString(t, "toString() after removing and putting again", treeCall(linkedhashToString, lhm),
+		"{30=x, 20=20.0, 10=y}")
+	if ret := linkedhashSize([]interface{}{lhm}); ret != int64(3) {
+		t.Errorf("size(): expected 3, got %v", ret)
+	}
+
+	// the same keys, as Strings, are distinct from the Integers
+	treeCall(linkedhashmapPut, lhm, object.StringObjectFromGoString("10"), object.StringObjectFromGoString("s"))
+	if ret := linkedhashSize([]interface{}{lhm}); ret != int64(4) {
+		t.Errorf("size() after putting a String key: expected 4, got %v", ret)
+	}
+
+	clone := linkedhashClone([]interface{}{lhm}).(*object.Object)
+	linkedhashClear([]interface{}{lhm})
+	if ret := linkedhashIsEmpty([]interface{}{lhm}); ret != types.JavaBoolTrue {
+		t.Errorf("isEmpty() after clear(): expected true, got %v", ret)
+	}
+	checkTreeString(t, "the clone", treeCall(linkedhashToString, clone), "{30=x, 20=20.0, 10=y, 10=s}")
+}
+
+func TestLinkedHashMapAccessOrder(t *testing.T) {
+	globals.InitGlobals("test")
+	lhm := newLinkedHashMapObject(classNameLinkedHashMap)
+	linkedhashInitAccessOrder([]interface{}{lhm, int64(16), 0.75, types.JavaBoolTrue})
+	for _, key := range []int64{1, 2, 3} {
+		treeCall(linkedhashmapPut, lhm, treeInt(key), treeInt(key*10))
+	}
+	treeCall(linkedhashmapGet, lhm, treeInt(1))
+	checkTreeString(t, "toString() after get(1)", treeCall(linkedhashToString, lhm), "{2=20, 3=30, 1=10}")
+}
+
+func TestLinkedHashMapViewsAndIterators(t *testing.T) {
+	globals.InitGlobals("test")
+	lhm := newTestLinkedHashMap(5, 3, 9)
+
+	keys := linkedhashmapKeySet([]interface{}{lhm}).(*object.Object)
+	checkTreeString(t, "keySet()", treeCall(linkedhashToString, keys), "[5, 3, 9]")
+	values := linkedhashmapValues([]interface{}{lhm}).(*object.Object)
+	checkTreeString(t, "values()", treeCall(linkedhashToString, values), "[5.0, 3.0, 9.0]")
+	entries := linkedhashmapEntrySet([]interface{}{lhm}).(*object.Object)
+	checkTreeString(t, "entrySet()", treeCall(linkedhashToString, entries), "[5=5.0, 3=3.0, 9=9.0]")
+
+	it := linkedhashIterator([]interface{}{entries}).(*object.Object)
+	entry := linkedhashIteratorNext([]interface{}{it}).(*object.Object)
+	mapEntrySetValue([]interface{}{entry, object.StringObjectFromGoString("five")})
+	checkTreeString(t, "get(5) after setValue()", treeCall(linkedhashmapGet, lhm, treeInt(5)), "five")
+	linkedhashIteratorNext([]interface{}{it})
+	linkedhashIteratorRemove([]interface{}{it})
+	checkTreeString(t, "the map after a removal by the iterator", treeCall(linkedhashToString, lhm),
+		"{5=five, 9=9.0}")
+	checkTreeError(t, "a second remove()", linkedhashIteratorRemove([]interface{}{it}),
+		excNames.IllegalStateException)
+
+	treeCall(linkedhashmapPut, lhm, treeInt(7), treeInt(7))
+	checkTreeError(t, "next() after a change to the map", linkedhashIteratorNext([]interface{}{it}),
+		excNames.ConcurrentModificationException)
+
+	treeCall(linkedhashsetRemove, keys, treeInt(9))
+	checkTreeString(t, "the map after a removal from keySet()", treeCall(linkedhashToString, lhm),
+		"{5=five, 7=7}")
+}
@@ -0,0 +1,326 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/types"
+)
+
+// LinkedHashSet is a LinkedHashMap whose entries have no values, iterated in the order in
+// which they were added; see javaUtilLinkedHashMap.go.
+
+var classNameLinkedHashSet = "java/util/LinkedHashSet"
+
+func Load_Util_LinkedHashSet() {
+
+	object.RegisterInstantiationHook(classNameLinkedHashSet, newLinkedHashSetObject)
+
+	MethodSignatures["java/util/LinkedHashSet.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/LinkedHashSet.<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  linkedhashsetInit,
+		}
+
+	MethodSignatures["java/util/LinkedHashSet.<init>(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  linkedhashsetInit,
+		}
+
+	MethodSignatures["java/util/LinkedHashSet.<init>(IF)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  linkedhashsetInit,
+		}
+
+	MethodSignatures["java/util/LinkedHashSet.<init>(Ljava/util/Collection;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    linkedhashsetInitFromCollection,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/LinkedHashSet.add(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    linkedhashsetAdd,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/LinkedHashSet.addAll(Ljava/util/Collection;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    linkedhashsetAddAll,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/LinkedHashSet.clear()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  linkedhashClear,
+		}
+
+	MethodSignatures["java/util/LinkedHashSet.clone()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  linkedhashClone,
+		}
+
+	MethodSignatures["java/util/LinkedHashSet.contains(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    linkedhashContains,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/LinkedHashSet.isEmpty()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  linkedhashIsEmpty,
+		}
+
+	MethodSignatures["java/util/LinkedHashSet.iterator()Ljava/util/Iterator;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  linkedhashIterator,
+		}
+
+	MethodSignatures["java/util/LinkedHashSet.remove(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    linkedhashsetRemove,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/LinkedHashSet.size()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  linkedhashSize,
+		}
+
+	MethodSignatures["java/util/LinkedHashSet.toArray()[Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  linkedhashsetToArray,
+		}
+
+	MethodSignatures["java/util/LinkedHashSet.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    linkedhashToString,
+			NeedsContext: true,
+		}
+}
+
+// the instantiation hook for LinkedHashSet: a new object holding an empty set
+func newLinkedHashSetObject(className string) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&className)
+	obj.FieldTable[fieldNameLinkedHash] = object.Field{Ftype: types.LinkedHash, Fvalue: newLinkedHashView(false)}
+	return obj
+}
+
+// "java/util/LinkedHashSet.<init>()V", <init>(I), and <init>(IF) -- the capacity and load
+// factor don't matter here
+func linkedhashsetInit(params []interface{}) interface{} {
+	obj, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return getGErrBlk(excNames.NullPointerException, "linkedhashsetInit: the object is null")
+	}
+	obj.FieldTable[fieldNameLinkedHash] = object.Field{Ftype: types.LinkedHash, Fvalue: newLinkedHashView(false)}
+	return nil
+}
+
+// "java/util/LinkedHashSet.<init>(Ljava/util/Collection;)V"
+func linkedhashsetInitFromCollection(params []interface{}) interface{} {
+	if ret := linkedhashsetInit(params[1:]); ret != nil {
+		return ret
+	}
+	if ret := linkedhashsetAddAll(params); ret != types.JavaBoolTrue && ret != types.JavaBoolFalse {
+		return ret
+	}
+	return nil
+}
+
+// "java/util/LinkedHashSet.add(Ljava/lang/Object;)Z" -- an element already in the set
+// keeps its place
+func linkedhashsetAdd(params []interface{}) interface{} {
+	fs, view, gErr := getLinkedHashViewContext("linkedhashsetAdd", params)
+	if gErr != nil {
+		return gErr
+	}
+	_, found, gErr := view.store.put(fs, treeKeyParam(params, 2), nil, false)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(!found)
+}
+
+// "java/util/LinkedHashSet.addAll(Ljava/util/Collection;)Z" -- the elements of the sets
+// implemented in Go are copied directly; those of other collections are obtained by their
+// toArray() in Java
+func linkedhashsetAddAll(params []interface{}) interface{} {
+	fs, view, gErr := getLinkedHashViewContext("linkedhashsetAddAll", params)
+	if gErr != nil {
+		return gErr
+	}
+	source := treeKeyParam(params, 2)
+	if object.IsNull(source) {
+		return getGErrBlk(excNames.NullPointerException, "linkedhashsetAddAll: the collection is null")
+	}
+	elems, gErr := collectionElements(fs, source)
+	if gErr != nil {
+		return gErr
+	}
+
+	changed := false
+	for _, elem := range elems {
+		_, found, gErr := view.store.put(fs, elem, nil, false)
+		if gErr != nil {
+			return gErr
+		}
+		changed = changed || !found
+	}
+	return types.ConvertGoBoolToJavaBool(changed)
+}
+
+// "java/util/LinkedHashSet.remove(Ljava/lang/Object;)Z" and the remove() of the key set of
+// a LinkedHashMap
+func linkedhashsetRemove(params []interface{}) interface{} {
+	fs, view, gErr := getLinkedHashViewContext("linkedhashsetRemove", params)
+	if gErr != nil {
+		return gErr
+	}
+	if view.store.immutable {
+		return getGErrBlk(excNames.UnsupportedOperationException, "linkedhashsetRemove: the collection is immutable")
+	}
+	entry, gErr := view.store.remove(fs, treeKeyParam(params, 2))
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(entry != nil)
+}
+
+// "java/util/LinkedHashSet.toArray()[Ljava/lang/Object;" -- the elements in order
+func linkedhashsetToArray(params []interface{}) interface{} {
+	view, gErr := getLinkedHashViewNoContext("linkedhashsetToArray", params)
+	if gErr != nil {
+		return gErr
+	}
+	arr := object.Make1DimRefArray("java/lang/Object;", int64(view.store.order.Len()))
+	elems := arr.FieldTable["value"].Fvalue.([]*object.Object)
+	i := 0
+	for elem := view.store.order.Front(); elem != nil; elem = elem.Next() {
+		elems[i] = linkedHashEntry(elem).key
+		i++
+	}
+	return arr
+}
+
+// collectionElements returns the elements of a collection. Those of the collections
+// implemented in Go are read directly; those of other collections are obtained by their
+// toArray() methods in Java.
+func collectionElements(fs *list.List, coll *object.Object) ([]*object.Object, *GErrBlk) {
+	if elems, ok, gErr := goCollectionElements(fs, coll); ok || gErr != nil {
+		return elems, gErr
+	}
+	ret, gErr := invokeJavaMethod(fs, "java/util/Collection", "toArray", "()[Ljava/lang/Object;", coll, nil)
+	if gErr != nil {
+		return nil, gErr
+	}
+	arr, ok := ret.(*object.Object)
+	if !ok || object.IsNull(arr) {
+		return nil, getGErrBlk(excNames.IllegalStateException, "collectionElements: toArray() did not return an array")
+	}
+	elems, _ := arr.FieldTable["value"].Fvalue.([]*object.Object)
+	return elems, nil
+}
+
+// iterableElements returns the elements of an Iterable. Those of the collections implemented
+// in Go are read directly; those of other Iterables are obtained by their iterators in Java.
+func iterableElements(fs *list.List, iterable *object.Object) ([]*object.Object, *GErrBlk) {
+	if elems, ok, gErr := goCollectionElements(fs, iterable); ok || gErr != nil {
+		return elems, gErr
+	}
+	ret, gErr := invokeJavaMethod(fs, "java/lang/Iterable", "iterator", "()Ljava/util/Iterator;", iterable, nil)
+	if gErr != nil {
+		return nil, gErr
+	}
+	iterator, ok := ret.(*object.Object)
+	if !ok || object.IsNull(iterator) {
+		return nil, getGErrBlk(excNames.NullPointerException, "iterableElements: iterator() returned null")
+	}
+	var elems []*object.Object
+	for {
+		hasNext, gErr := invokeJavaMethod(fs, "java/util/Iterator", "hasNext", "()Z", iterator, nil)
+		if gErr != nil {
+			return nil, gErr
+		}
+		if hasNext != types.JavaBoolTrue {
+			return elems, nil
+		}
+		next, gErr := invokeJavaMethod(fs, "java/util/Iterator", "next", "()Ljava/lang/Object;", iterator, nil)
+		if gErr != nil {
+			return nil, gErr
+		}
+		elem, _ := next.(*object.Object)
+		elems = append(elems, elem)
+	}
+}
+
+// returns the elements of a collection implemented in Go, and true, or false if the
+// collection isn't one of these
+func goCollectionElements(fs *list.List, coll *object.Object) ([]*object.Object, bool, *GErrBlk) {
+	var elems []*object.Object
+	if view, ok := coll.FieldTable[fieldNameLinkedHash].Fvalue.(*linkedHashView); ok && !view.isMap {
+		for elem := view.store.order.Front(); elem != nil; elem = elem.Next() {
+			entry := linkedHashEntry(elem)
+			switch view.kind {
+			case mapIterateKeys:
+				elems = append(elems, entry.key)
+			case mapIterateValues:
+				value, _ := entry.value.(*object.Object)
+				elems = append(elems, value)
+			default:
+				elems = append(elems, newMapEntryObject(classNameLinkedHashMapEntry, entry, view.store.immutable))
+			}
+		}
+		return elems, true, nil
+	}
+	if lst, ok := coll.FieldTable[fieldNameImmutableList].Fvalue.(*immutableList); ok {
+		return lst.elems, true, nil
+	}
+	if view, ok := coll.FieldTable[fieldNameUnmodifiable].Fvalue.(*unmodifiableView); ok && !view.entries {
+		elems, gErr := collectionElements(fs, view.wrapped)
+		return elems, true, gErr
+	}
+	if view, ok := coll.FieldTable[fieldNameTreeState].Fvalue.(*treeView); ok {
+		from, to, gErr := view.span(fs)
+		if gErr != nil {
+			return nil, true, gErr
+		}
+		for _, entry := range view.store.entries[from:to] {
+			elems = append(elems, entry.key)
+		}
+		return elems, true, nil
+	}
+	if deque, ok := coll.FieldTable[fieldNameDeque].Fvalue.(*dequeState); ok {
+		return deque.elements(), true, nil
+	}
+	if hm, ok := coll.FieldTable[fieldNameMap].Fvalue.(types.DefHashMap); ok { // a HashSet
+		return hashsetElements(hm), true, nil
+	}
+	return nil, false, nil
+}
@@ -0,0 +1,68 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"testing"
+)
+
+func newTestLinkedHashSet(keys ...int64) *object.Object {
+	lhs := newLinkedHashSetObject(classNameLinkedHashSet)
+	for _, key := range keys {
+		treeCall(linkedhashsetAdd, lhs, treeInt(key))
+	}
+	return lhs
+}
+
+func TestLinkedHashSetOrderAndCopies(t *testing.T) {
+	globals.InitGlobals("test")
+	lhs := newTestLinkedHashSet(8, 2, 5)
+
+	if ret := treeCall(linkedhashsetAdd, lhs, treeInt(8)); ret != types.JavaBoolFalse {
+		t.Errorf("add() of a duplicate: expected false, got %v", ret)
+	}
+	checkTreeString(t, "toString()", treeCall(linkedhashToString, lhs), "[8, 2, 5]")
+	if ret := treeCall(linkedhashContains, lhs, treeInt(2)); ret != types.JavaBoolTrue {
+		t.Errorf("contains(2): expected true, got %v", ret)
+	}
+	if ret := treeCall(linkedhashsetRemove, lhs, treeInt(2)); ret != types.JavaBoolTrue {
+		t.Errorf("remove(2): expected true, got %v", ret)
+	}
+
+	// a LinkedHashSet made from a TreeSet and a deque keeps their orders
+	copied := newLinkedHashSetObject(classNameLinkedHashSet)
+	treeCall(linkedhashsetInitFromCollection, copied, newTestTreeSet(3, 1, 2))
+	deque := newArrayDequeObject(classNameArrayDeque)
+	arraydequeAddLast([]interface{}{deque, treeInt(9)})
+	arraydequeAddLast([]interface{}{deque, treeInt(1)})
+	if ret := treeCall(linkedhashsetAddAll, copied, deque); ret != types.JavaBoolTrue {
+		t.Errorf("addAll() of a deque: expected true, got %v", ret)
+	}
+	if ret := treeCall(linkedhashsetAddAll, copied, lhs); ret != types.JavaBoolTrue {
+		t.Errorf("addAll() of a LinkedHashSet: expected true, got %v", ret)
+	}
+	checkTreeString(t, "the copy", treeCall(linkedhashToString, copied), "[1, 2, 3, 9, 8, 5]")
+
+	arr := linkedhashsetToArray([]interface{}{copied}).(*object.Object)
+	elems := arr.FieldTable["value"].Fvalue.([]*object.Object)
+	if len(elems) != 6 {
+		t.Fatalf("toArray(): expected 6 elements, got %d", len(elems))
+	}
+	checkTreeKey(t, "toArray()[3]", elems[3], 9)
+
+	it := linkedhashIterator([]interface{}{copied}).(*object.Object)
+	var got []int64
+	for linkedhashIteratorHasNext([]interface{}{it}) == types.JavaBoolTrue {
+		got = append(got, linkedhashIteratorNext([]interface{}{it}).(*object.Object).FieldTable["value"].Fvalue.(int64))
+	}
+	if len(got) != 6 || got[0] != 1 || got[5] != 5 {
+		t.Errorf("iterator(): unexpected elements %v", got)
+	}
+}
@@ -16,6 +16,9 @@ import (
 
 func Load_Util_LinkedList() {
 
+	object.RegisterInstantiationHook(classNameLinkedList,
+		func(string) *object.Object { return newLinkedListObject() })
+
 	MethodSignatures["java/util/LinkedList.<clinit>()V"] =
 		GMeth{
 			ParamSlots: 0,
@@ -7,13 +7,18 @@
 package gfunction
 
 import (
+	"jacobin/src/excNames"
 	"jacobin/src/object"
 	"jacobin/src/types"
 	"os"
+	"strings"
 )
 
 // Implementation of some of the functions in Java/util/Locale.
-// Strategy: Locale = jacobin Object wrapping a Go string.
+// Strategy: Locale = jacobin Object wrapping a Go string, which is the
+// locale's toString() form: language_COUNTRY_variant, e.g. "fr_CA".
+
+var classNameLocale = "java/util/Locale"
 
 func Load_Util_Locale() {
 
@@ -26,19 +31,37 @@ func Load_Util_Locale() {
 	MethodSignatures["java/util/Locale.<init>(Ljava/lang/String;)V"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  trapDeprecated,
+			GFunction:  localeInit,
 		}
 
 	MethodSignatures["java/util/Locale.<init>(Ljava/lang/String;Ljava/lang/String;)V"] =
 		GMeth{
 			ParamSlots: 2,
-			GFunction:  trapDeprecated,
+			GFunction:  localeInit,
 		}
 
 	MethodSignatures["java/util/Locale.<init>(Ljava/lang/String;Ljava/lang/String;Ljava/lang/String;)V"] =
 		GMeth{
 			ParamSlots: 3,
-			GFunction:  trapDeprecated,
+			GFunction:  localeInit,
+		}
+
+	MethodSignatures["java/util/Locale.equals(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  localeEquals,
+		}
+
+	MethodSignatures["java/util/Locale.forLanguageTag(Ljava/lang/String;)Ljava/util/Locale;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  localeForLanguageTag,
+		}
+
+	MethodSignatures["java/util/Locale.getCountry()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  localeGetCountry,
 		}
 
 	MethodSignatures["java/util/Locale.getDefault()Ljava/util/Locale;"] =
@@ -71,6 +94,54 @@ func Load_Util_Locale() {
 			GFunction:  getDefaultLocale, // ignore input
 		}
 
+	MethodSignatures["java/util/Locale.getLanguage()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  localeGetLanguage,
+		}
+
+	MethodSignatures["java/util/Locale.getVariant()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  localeGetVariant,
+		}
+
+	MethodSignatures["java/util/Locale.hashCode()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  localeHashCode,
+		}
+
+	MethodSignatures["java/util/Locale.of(Ljava/lang/String;)Ljava/util/Locale;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  localeOf,
+		}
+
+	MethodSignatures["java/util/Locale.of(Ljava/lang/String;Ljava/lang/String;)Ljava/util/Locale;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  localeOf,
+		}
+
+	MethodSignatures["java/util/Locale.of(Ljava/lang/String;Ljava/lang/String;Ljava/lang/String;)Ljava/util/Locale;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  localeOf,
+		}
+
+	MethodSignatures["java/util/Locale.toLanguageTag()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  localeToLanguageTag,
+		}
+
+	MethodSignatures["java/util/Locale.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  localeToString,
+		}
+
 }
 
 // "java/util/Locale.getDefault()Ljava/util/Locale;"
@@ -78,10 +149,165 @@ func Load_Util_Locale() {
 // "java/util/Locale.getInstance(Ljava/lang/String;Ljava/lang/String;Ljava/lang/String;Ljava/lang/String;Lsun/util/locale/LocaleExtensions;)Ljava/util/Locale;"
 func getDefaultLocale([]interface{}) interface{} {
 	// Ignore parameters.
-	langStr := os.Getenv("LANGUAGE")
-	classStr := "java/lang/Locale"
-	obj := object.MakeEmptyObjectWithClassName(&classStr)
-	fld := object.Field{Ftype: types.ByteArray, Fvalue: []byte(langStr)}
-	obj.FieldTable["value"] = fld
+	return makeLocale(defaultLocaleName())
+}
+
+// defaultLocaleName derives the default locale from the environment, as the JDK does on
+// Unix: the first of LANGUAGE, LC_ALL, and LANG that is set. A value such as
+// "en_US.UTF-8@euro" yields "en_US"; the C and POSIX locales yield the root locale, "".
+func defaultLocaleName() string {
+	var env string
+	for _, name := range []string{"LANGUAGE", "LC_ALL", "LANG"} {
+		if env = os.Getenv(name); env != "" {
+			break
+		}
+	}
+	env, _, _ = strings.Cut(env, ":") // LANGUAGE can be a list of preferences
+	env, _, _ = strings.Cut(env, "@")
+	env, _, _ = strings.Cut(env, ".")
+	if env == "C" || env == "POSIX" {
+		return ""
+	}
+	language, country, variant := splitLocaleName(env)
+	return joinLocaleName(language, country, variant)
+}
+
+// makeLocale creates a Locale object for a name of the form language_COUNTRY_variant
+func makeLocale(name string) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&classNameLocale)
+	obj.FieldTable["value"] = object.Field{Ftype: types.ByteArray, Fvalue: []byte(name)}
 	return obj
 }
+
+// localeName returns the language_COUNTRY_variant name of a Locale object
+func localeName(obj *object.Object) string {
+	name, _ := obj.FieldTable["value"].Fvalue.([]byte)
+	return string(name)
+}
+
+// splitLocaleName splits language_COUNTRY_variant into its parts
+func splitLocaleName(name string) (string, string, string) {
+	parts := strings.SplitN(name, "_", 3)
+	for len(parts) < 3 {
+		parts = append(parts, "")
+	}
+	return parts[0], parts[1], parts[2]
+}
+
+// joinLocaleName forms the toString() of a locale: the language is lower-cased and the
+// country upper-cased, and underscores are omitted only where nothing follows them.
+func joinLocaleName(language, country, variant string) string {
+	name := strings.ToLower(language)
+	if country != "" || variant != "" {
+		name += "_" + strings.ToUpper(country)
+	}
+	if variant != "" {
+		name += "_" + variant
+	}
+	return name
+}
+
+// localeNameFromParams forms a locale name from one, two, or three String parameters
+func localeNameFromParams(params []interface{}) (string, *GErrBlk) {
+	var parts [3]string
+	for ix, param := range params {
+		strObj, ok := param.(*object.Object)
+		if !ok || object.IsNull(strObj) {
+			return "", getGErrBlk(excNames.NullPointerException, "Locale: null language, country, or variant")
+		}
+		parts[ix] = object.GoStringFromStringObject(strObj)
+	}
+	return joinLocaleName(parts[0], parts[1], parts[2]), nil
+}
+
+// "java/util/Locale.<init>(Ljava/lang/String;)V" and the (language, country) and
+// (language, country, variant) forms
+func localeInit(params []interface{}) interface{} {
+	obj := params[0].(*object.Object)
+	name, gerr := localeNameFromParams(params[1:])
+	if gerr != nil {
+		return gerr
+	}
+	obj.FieldTable["value"] = object.Field{Ftype: types.ByteArray, Fvalue: []byte(name)}
+	return nil
+}
+
+// "java/util/Locale.of(Ljava/lang/String;)Ljava/util/Locale;" and the two- and three-String forms
+func localeOf(params []interface{}) interface{} {
+	name, gerr := localeNameFromParams(params)
+	if gerr != nil {
+		return gerr
+	}
+	return makeLocale(name)
+}
+
+// "java/util/Locale.forLanguageTag(Ljava/lang/String;)Ljava/util/Locale;" -- handles the
+// language-REGION-variant tags that map onto locale names, e.g. "fr-CA"; "und" is the root locale
+func localeForLanguageTag(params []interface{}) interface{} {
+	strObj, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(strObj) {
+		return getGErrBlk(excNames.NullPointerException, "localeForLanguageTag: null language tag")
+	}
+	tag := strings.ReplaceAll(object.GoStringFromStringObject(strObj), "-", "_")
+	language, country, variant := splitLocaleName(tag)
+	if strings.EqualFold(language, "und") {
+		language = ""
+	}
+	return makeLocale(joinLocaleName(language, country, variant))
+}
+
+// "java/util/Locale.toLanguageTag()Ljava/lang/String;"
+func localeToLanguageTag(params []interface{}) interface{} {
+	language, country, variant := splitLocaleName(localeName(params[0].(*object.Object)))
+	tag := language
+	if tag == "" {
+		tag = "und"
+	}
+	for _, part := range []string{country, variant} {
+		if part != "" {
+			tag += "-" + part
+		}
+	}
+	return object.StringObjectFromGoString(tag)
+}
+
+// "java/util/Locale.toString()Ljava/lang/String;"
+func localeToString(params []interface{}) interface{} {
+	return object.StringObjectFromGoString(localeName(params[0].(*object.Object)))
+}
+
+// "java/util/Locale.getLanguage()Ljava/lang/String;"
+func localeGetLanguage(params []interface{}) interface{} {
+	language, _, _ := splitLocaleName(localeName(params[0].(*object.Object)))
+	return object.StringObjectFromGoString(language)
+}
+
+// "java/util/Locale.getCountry()Ljava/lang/String;"
+func localeGetCountry(params []interface{}) interface{} {
+	_, country, _ := splitLocaleName(localeName(params[0].(*object.Object)))
+	return object.StringObjectFromGoString(country)
+}
+
+// "java/util/Locale.getVariant()Ljava/lang/String;"
+func localeGetVariant(params []interface{}) interface{} {
+	_, _, variant := splitLocaleName(localeName(params[0].(*object.Object)))
+	return object.StringObjectFromGoString(variant)
+}
+
+// "java/util/Locale.equals(Ljava/lang/Object;)Z"
+func localeEquals(params []interface{}) interface{} {
+	that, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(that) || object.GoStringFromStringPoolIndex(that.KlassName) != classNameLocale {
+		return types.JavaBoolFalse
+	}
+	return object.JavaBooleanFromGoBoolean(localeName(params[0].(*object.Object)) == localeName(that))
+}
+
+// "java/util/Locale.hashCode()I" -- the String hash code of the locale's name
+func localeHashCode(params []interface{}) interface{} {
+	var hash int32
+	for _, ch := range localeName(params[0].(*object.Object)) {
+		hash = 31*hash + int32(ch)
+	}
+	return int64(hash)
+}
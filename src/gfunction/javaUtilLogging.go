@@ -0,0 +1,839 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"fmt"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/statics"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A minimal java.util.logging: Logger, Level, ConsoleHandler, and SimpleFormatter.
+// Strategy: loggers are kept in a registry by name, and a logger's parent is its nearest
+// registered ancestor in the dotted namespace, else the root logger, "". The root logger
+// starts with one ConsoleHandler at level INFO. A record that a logger's effective level
+// lets through is published to the handlers of the logger and of its parents (unless
+// setUseParentHandlers(false) stops the climb), and each handler whose own level allows it
+// writes the record through the trace subsystem as "<logger name> <LEVEL>: <message>",
+// under the trace subsystem "java". Every handler is treated as a ConsoleHandler.
+
+var classNameLogger = "java/util/logging/Logger"
+var classNameLevel = "java/util/logging/Level"
+var classNameConsoleHandler = "java/util/logging/ConsoleHandler"
+
+// The standard levels, in the order of their values
+var loggingLevelNames = []string{"ALL", "FINEST", "FINER", "FINE", "CONFIG", "INFO", "WARNING", "SEVERE", "OFF"}
+var loggingLevelValues = map[string]int64{
+	"ALL":     math.MinInt32,
+	"FINEST":  300,
+	"FINER":   400,
+	"FINE":    500,
+	"CONFIG":  700,
+	"INFO":    800,
+	"WARNING": 900,
+	"SEVERE":  1000,
+	"OFF":     math.MaxInt32,
+}
+
+// the registry of named loggers, which also guards the fields of loggers and handlers
+var loggers = make(map[string]*object.Object)
+var loggingMutex = sync.Mutex{}
+
+func Load_Util_Logging() {
+
+	// --- Level ---
+
+	MethodSignatures["java/util/logging/Level.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  loggingLevelClinit,
+		}
+
+	MethodSignatures["java/util/logging/Level.getLocalizedName()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  loggingLevelGetName,
+		}
+
+	MethodSignatures["java/util/logging/Level.getName()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  loggingLevelGetName,
+		}
+
+	MethodSignatures["java/util/logging/Level.intValue()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  loggingLevelIntValue,
+		}
+
+	MethodSignatures["java/util/logging/Level.parse(Ljava/lang/String;)Ljava/util/logging/Level;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  loggingLevelParse,
+		}
+
+	MethodSignatures["java/util/logging/Level.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  loggingLevelGetName,
+		}
+
+	// --- Logger ---
+
+	MethodSignatures["java/util/logging/Logger.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/logging/Logger.addHandler(Ljava/util/logging/Handler;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  loggerAddHandler,
+		}
+
+	MethodSignatures["java/util/logging/Logger.config(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  loggerConfig,
+		}
+
+	MethodSignatures["java/util/logging/Logger.fine(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  loggerFine,
+		}
+
+	MethodSignatures["java/util/logging/Logger.finer(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  loggerFiner,
+		}
+
+	MethodSignatures["java/util/logging/Logger.finest(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  loggerFinest,
+		}
+
+	MethodSignatures["java/util/logging/Logger.getAnonymousLogger()Ljava/util/logging/Logger;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  loggerGetAnonymousLogger,
+		}
+
+	MethodSignatures["java/util/logging/Logger.getGlobal()Ljava/util/logging/Logger;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  loggerGetGlobal,
+		}
+
+	MethodSignatures["java/util/logging/Logger.getHandlers()[Ljava/util/logging/Handler;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  loggerGetHandlers,
+		}
+
+	MethodSignatures["java/util/logging/Logger.getLevel()Ljava/util/logging/Level;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  loggingGetLevel,
+		}
+
+	MethodSignatures["java/util/logging/Logger.getLogger(Ljava/lang/String;)Ljava/util/logging/Logger;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  loggerGetLogger,
+		}
+
+	MethodSignatures["java/util/logging/Logger.getLogger(Ljava/lang/String;Ljava/lang/String;)Ljava/util/logging/Logger;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  loggerGetLogger, // the resource bundle name is ignored
+		}
+
+	MethodSignatures["java/util/logging/Logger.getName()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  loggerGetName,
+		}
+
+	MethodSignatures["java/util/logging/Logger.getParent()Ljava/util/logging/Logger;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  loggerGetParent,
+		}
+
+	MethodSignatures["java/util/logging/Logger.getUseParentHandlers()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  loggerGetUseParentHandlers,
+		}
+
+	MethodSignatures["java/util/logging/Logger.info(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  loggerInfo,
+		}
+
+	MethodSignatures["java/util/logging/Logger.isLoggable(Ljava/util/logging/Level;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  loggerIsLoggable,
+		}
+
+	MethodSignatures["java/util/logging/Logger.log(Ljava/util/logging/Level;Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  loggerLog,
+		}
+
+	MethodSignatures["java/util/logging/Logger.log(Ljava/util/logging/Level;Ljava/lang/String;Ljava/lang/Object;)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  loggerLog,
+		}
+
+	MethodSignatures["java/util/logging/Logger.log(Ljava/util/logging/Level;Ljava/lang/String;[Ljava/lang/Object;)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  loggerLog,
+		}
+
+	MethodSignatures["java/util/logging/Logger.log(Ljava/util/logging/Level;Ljava/lang/String;Ljava/lang/Throwable;)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  loggerLogThrown,
+		}
+
+	MethodSignatures["java/util/logging/Logger.removeHandler(Ljava/util/logging/Handler;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  loggerRemoveHandler,
+		}
+
+	MethodSignatures["java/util/logging/Logger.setLevel(Ljava/util/logging/Level;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  loggingSetLevel,
+		}
+
+	MethodSignatures["java/util/logging/Logger.setUseParentHandlers(Z)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  loggerSetUseParentHandlers,
+		}
+
+	MethodSignatures["java/util/logging/Logger.severe(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  loggerSevere,
+		}
+
+	MethodSignatures["java/util/logging/Logger.warning(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  loggerWarning,
+		}
+
+	// --- ConsoleHandler and SimpleFormatter ---
+
+	MethodSignatures["java/util/logging/ConsoleHandler.<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  consoleHandlerInit,
+		}
+
+	// The inherited Handler methods can be called through either class.
+	for _, className := range []string{"java/util/logging/Handler", classNameConsoleHandler} {
+		MethodSignatures[className+".close()V"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  justReturn,
+			}
+
+		MethodSignatures[className+".flush()V"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  justReturn,
+			}
+
+		MethodSignatures[className+".getFormatter()Ljava/util/logging/Formatter;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  handlerGetFormatter,
+			}
+
+		MethodSignatures[className+".getLevel()Ljava/util/logging/Level;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  loggingGetLevel,
+			}
+
+		MethodSignatures[className+".setFormatter(Ljava/util/logging/Formatter;)V"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  handlerSetFormatter,
+			}
+
+		MethodSignatures[className+".setLevel(Ljava/util/logging/Level;)V"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  loggingSetLevel,
+			}
+	}
+
+	MethodSignatures["java/util/logging/SimpleFormatter.<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  justReturn,
+		}
+}
+
+// ---------------------------------------------------------------------------------
+// Level
+// ---------------------------------------------------------------------------------
+
+// "java/util/logging/Level.<clinit>()V" -- create the standard levels
+func loggingLevelClinit([]interface{}) interface{} {
+	klass := classloader.MethAreaFetch(classNameLevel)
+	if klass == nil || klass.Data == nil {
+		errMsg := fmt.Sprintf("loggingLevelClinit: Expected %s to be in the MethodArea, but it was not", classNameLevel)
+		trace.Error(errMsg)
+		return getGErrBlk(excNames.ClassNotLoadedException, errMsg)
+	}
+	if klass.Data.ClInit != types.ClInitRun {
+		for _, name := range loggingLevelNames {
+			_ = statics.AddStatic(classNameLevel+"."+name,
+				statics.Static{Type: "Ljava/util/logging/Level;", Value: makeLoggingLevel(name, loggingLevelValues[name])})
+		}
+		klass.Data.ClInit = types.ClInitRun
+	}
+	return nil
+}
+
+// makeLoggingLevel creates a Level object with the given name and value
+func makeLoggingLevel(name string, value int64) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&classNameLevel)
+	obj.FieldTable["name"] = object.Field{Ftype: types.StringClassRef, Fvalue: object.StringObjectFromGoString(name)}
+	obj.FieldTable["value"] = object.Field{Ftype: types.Int, Fvalue: value}
+	return obj
+}
+
+// loggingLevelObject returns the standard level with the given name. Once <clinit>
+// has run, this is the static; before then, a new object is made.
+func loggingLevelObject(name string) *object.Object {
+	if static, ok := statics.Statics[classNameLevel+"."+name]; ok {
+		if obj, ok := static.Value.(*object.Object); ok {
+			return obj
+		}
+	}
+	return makeLoggingLevel(name, loggingLevelValues[name])
+}
+
+// getLoggingLevel returns the name and value of a Level object
+func getLoggingLevel(funcName string, level interface{}) (string, int64, *GErrBlk) {
+	obj, ok := level.(*object.Object)
+	if !ok || object.IsNull(obj) {
+		errMsg := fmt.Sprintf("%s: the Level is null", funcName)
+		return "", 0, getGErrBlk(excNames.NullPointerException, errMsg)
+	}
+	value, ok := obj.FieldTable["value"].Fvalue.(int64)
+	nameObj, ok2 := obj.FieldTable["name"].Fvalue.(*object.Object)
+	if !ok || !ok2 {
+		errMsg := fmt.Sprintf("%s: the object is not a Level", funcName)
+		return "", 0, getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	return object.GoStringFromStringObject(nameObj), value, nil
+}
+
+// "java/util/logging/Level.getName()Ljava/lang/String;", getLocalizedName(), and toString()
+func loggingLevelGetName(params []interface{}) interface{} {
+	name, _, gerr := getLoggingLevel("loggingLevelGetName", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	return object.StringObjectFromGoString(name)
+}
+
+// "java/util/logging/Level.intValue()I"
+func loggingLevelIntValue(params []interface{}) interface{} {
+	_, value, gerr := getLoggingLevel("loggingLevelIntValue", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	return value
+}
+
+// "java/util/logging/Level.parse(Ljava/lang/String;)Ljava/util/logging/Level;" -- accepts the
+// name or the integer value of a level; an integer that isn't a standard level's makes a new level
+func loggingLevelParse(params []interface{}) interface{} {
+	strObj, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(strObj) {
+		return getGErrBlk(excNames.NullPointerException, "loggingLevelParse: null level name")
+	}
+	str := object.GoStringFromStringObject(strObj)
+	if _, ok := loggingLevelValues[str]; ok {
+		return loggingLevelObject(str)
+	}
+	if value, err := strconv.ParseInt(str, 10, 32); err == nil {
+		for _, name := range loggingLevelNames {
+			if loggingLevelValues[name] == value {
+				return loggingLevelObject(name)
+			}
+		}
+		return makeLoggingLevel(str, value)
+	}
+	errMsg := fmt.Sprintf("Bad level \"%s\"", str)
+	return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+}
+
+// loggingTraceLevel maps a level's value onto the trace level it's logged at
+func loggingTraceLevel(value int64) trace.Level {
+	switch {
+	case value >= loggingLevelValues["SEVERE"]:
+		return trace.LevelError
+	case value >= loggingLevelValues["WARNING"]:
+		return trace.LevelWarning
+	case value >= loggingLevelValues["CONFIG"]:
+		return trace.LevelInfo
+	default:
+		return trace.LevelTrace
+	}
+}
+
+// ---------------------------------------------------------------------------------
+// Logger
+// ---------------------------------------------------------------------------------
+
+// makeLogger creates a logger with no level of its own, so it inherits its parent's
+func makeLogger(name *object.Object) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&classNameLogger)
+	obj.FieldTable["name"] = object.Field{Ftype: types.StringClassRef, Fvalue: name}
+	obj.FieldTable["level"] = object.Field{Ftype: "Ljava/util/logging/Level;", Fvalue: object.Null}
+	obj.FieldTable["handlers"] = object.Field{Ftype: "[Ljava/util/logging/Handler;", Fvalue: []*object.Object{}}
+	obj.FieldTable["useParentHandlers"] = object.Field{Ftype: types.Bool, Fvalue: types.JavaBoolTrue}
+	return obj
+}
+
+// rootLogger returns the root logger, creating it if need be. Call with loggingMutex held.
+func rootLogger() *object.Object {
+	if root, ok := loggers[""]; ok {
+		return root
+	}
+	root := makeLogger(object.StringObjectFromGoString(""))
+	root.FieldTable["level"] = object.Field{Ftype: "Ljava/util/logging/Level;", Fvalue: loggingLevelObject("INFO")}
+	root.FieldTable["handlers"] = object.Field{Ftype: "[Ljava/util/logging/Handler;", Fvalue: []*object.Object{makeConsoleHandler()}}
+	loggers[""] = root
+	return root
+}
+
+// getLogger returns the named logger, creating and registering it if need be. Call with loggingMutex held.
+func getLogger(name string) *object.Object {
+	if name == "" {
+		return rootLogger()
+	}
+	if logger, ok := loggers[name]; ok {
+		return logger
+	}
+	logger := makeLogger(object.StringObjectFromGoString(name))
+	loggers[name] = logger
+	return logger
+}
+
+// loggerParent returns the nearest registered ancestor of a logger in the dotted namespace,
+// or the root logger, or nil for the root logger itself. Call with loggingMutex held.
+func loggerParent(logger *object.Object) *object.Object {
+	name := loggerNameOf(logger)
+	if logger == loggers[""] {
+		return nil
+	}
+	for ix := strings.LastIndex(name, "."); ix > 0; ix = strings.LastIndex(name, ".") {
+		name = name[:ix]
+		if parent, ok := loggers[name]; ok {
+			return parent
+		}
+	}
+	return rootLogger()
+}
+
+// loggerNameOf returns a logger's name, which is "" for an anonymous logger
+func loggerNameOf(logger *object.Object) string {
+	if nameObj, ok := logger.FieldTable["name"].Fvalue.(*object.Object); ok && !object.IsNull(nameObj) {
+		return object.GoStringFromStringObject(nameObj)
+	}
+	return ""
+}
+
+// effectiveLevel returns the value of the level of the logger or, if it has none, of the
+// nearest parent that has one. Call with loggingMutex held.
+func effectiveLevel(logger *object.Object) int64 {
+	for ; logger != nil; logger = loggerParent(logger) {
+		if level, ok := logger.FieldTable["level"].Fvalue.(*object.Object); ok && !object.IsNull(level) {
+			if value, ok := level.FieldTable["value"].Fvalue.(int64); ok {
+				return value
+			}
+		}
+	}
+	return loggingLevelValues["INFO"]
+}
+
+// getLoggerObject returns the logger in params[0]
+func getLoggerObject(funcName string, params []interface{}) (*object.Object, *GErrBlk) {
+	logger, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(logger) {
+		errMsg := fmt.Sprintf("%s: the Logger is null", funcName)
+		return nil, getGErrBlk(excNames.NullPointerException, errMsg)
+	}
+	return logger, nil
+}
+
+// "java/util/logging/Logger.getLogger(Ljava/lang/String;)Ljava/util/logging/Logger;"
+func loggerGetLogger(params []interface{}) interface{} {
+	nameObj, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(nameObj) {
+		return getGErrBlk(excNames.NullPointerException, "loggerGetLogger: null logger name")
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	return getLogger(object.GoStringFromStringObject(nameObj))
+}
+
+// "java/util/logging/Logger.getGlobal()Ljava/util/logging/Logger;"
+func loggerGetGlobal([]interface{}) interface{} {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	return getLogger("global")
+}
+
+// "java/util/logging/Logger.getAnonymousLogger()Ljava/util/logging/Logger;" -- an unregistered
+// logger, with a null name, whose parent is the root logger
+func loggerGetAnonymousLogger([]interface{}) interface{} {
+	return makeLogger(object.Null)
+}
+
+// "java/util/logging/Logger.getName()Ljava/lang/String;"
+func loggerGetName(params []interface{}) interface{} {
+	logger, gerr := getLoggerObject("loggerGetName", params)
+	if gerr != nil {
+		return gerr
+	}
+	return logger.FieldTable["name"].Fvalue
+}
+
+// "java/util/logging/Logger.getParent()Ljava/util/logging/Logger;" -- null for the root logger
+func loggerGetParent(params []interface{}) interface{} {
+	logger, gerr := getLoggerObject("loggerGetParent", params)
+	if gerr != nil {
+		return gerr
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	if parent := loggerParent(logger); parent != nil {
+		return parent
+	}
+	return object.Null
+}
+
+// "java/util/logging/Logger.getLevel()Ljava/util/logging/Level;" and the Handler method
+func loggingGetLevel(params []interface{}) interface{} {
+	obj, gerr := getLoggerObject("loggingGetLevel", params)
+	if gerr != nil {
+		return gerr
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	return obj.FieldTable["level"].Fvalue
+}
+
+// "java/util/logging/Logger.setLevel(Ljava/util/logging/Level;)V" and the Handler method. A
+// null level makes a logger inherit its parent's level; a handler's level can't be null.
+func loggingSetLevel(params []interface{}) interface{} {
+	obj, gerr := getLoggerObject("loggingSetLevel", params)
+	if gerr != nil {
+		return gerr
+	}
+	level, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(level) {
+		if object.GoStringFromStringPoolIndex(obj.KlassName) != classNameLogger {
+			return getGErrBlk(excNames.NullPointerException, "loggingSetLevel: null level")
+		}
+		level = object.Null
+	} else if _, _, gerr = getLoggingLevel("loggingSetLevel", level); gerr != nil {
+		return gerr
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	obj.FieldTable["level"] = object.Field{Ftype: "Ljava/util/logging/Level;", Fvalue: level}
+	return nil
+}
+
+// "java/util/logging/Logger.isLoggable(Ljava/util/logging/Level;)Z"
+func loggerIsLoggable(params []interface{}) interface{} {
+	logger, gerr := getLoggerObject("loggerIsLoggable", params)
+	if gerr != nil {
+		return gerr
+	}
+	_, value, gerr := getLoggingLevel("loggerIsLoggable", params[1])
+	if gerr != nil {
+		return gerr
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	threshold := effectiveLevel(logger)
+	return object.JavaBooleanFromGoBoolean(value >= threshold && threshold != loggingLevelValues["OFF"])
+}
+
+// "java/util/logging/Logger.addHandler(Ljava/util/logging/Handler;)V"
+func loggerAddHandler(params []interface{}) interface{} {
+	logger, gerr := getLoggerObject("loggerAddHandler", params)
+	if gerr != nil {
+		return gerr
+	}
+	handler, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(handler) {
+		return getGErrBlk(excNames.NullPointerException, "loggerAddHandler: null handler")
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	handlers := logger.FieldTable["handlers"].Fvalue.([]*object.Object)
+	logger.FieldTable["handlers"] = object.Field{Ftype: "[Ljava/util/logging/Handler;", Fvalue: append(handlers, handler)}
+	return nil
+}
+
+// "java/util/logging/Logger.removeHandler(Ljava/util/logging/Handler;)V" -- a null or absent handler is ignored
+func loggerRemoveHandler(params []interface{}) interface{} {
+	logger, gerr := getLoggerObject("loggerRemoveHandler", params)
+	if gerr != nil {
+		return gerr
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	handlers := logger.FieldTable["handlers"].Fvalue.([]*object.Object)
+	for ix, handler := range handlers {
+		if handler == params[1] {
+			remaining := append(append([]*object.Object{}, handlers[:ix]...), handlers[ix+1:]...)
+			logger.FieldTable["handlers"] = object.Field{Ftype: "[Ljava/util/logging/Handler;", Fvalue: remaining}
+			break
+		}
+	}
+	return nil
+}
+
+// "java/util/logging/Logger.getHandlers()[Ljava/util/logging/Handler;"
+func loggerGetHandlers(params []interface{}) interface{} {
+	logger, gerr := getLoggerObject("loggerGetHandlers", params)
+	if gerr != nil {
+		return gerr
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	handlers := logger.FieldTable["handlers"].Fvalue.([]*object.Object)
+	arr := object.Make1DimRefArray("java/util/logging/Handler;", int64(len(handlers)))
+	copy(arr.FieldTable["value"].Fvalue.([]*object.Object), handlers)
+	return arr
+}
+
+// "java/util/logging/Logger.setUseParentHandlers(Z)V"
+func loggerSetUseParentHandlers(params []interface{}) interface{} {
+	logger, gerr := getLoggerObject("loggerSetUseParentHandlers", params)
+	if gerr != nil {
+		return gerr
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	logger.FieldTable["useParentHandlers"] = object.Field{Ftype: types.Bool, Fvalue: params[1].(int64)}
+	return nil
+}
+
+// "java/util/logging/Logger.getUseParentHandlers()Z"
+func loggerGetUseParentHandlers(params []interface{}) interface{} {
+	logger, gerr := getLoggerObject("loggerGetUseParentHandlers", params)
+	if gerr != nil {
+		return gerr
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	return logger.FieldTable["useParentHandlers"].Fvalue
+}
+
+// "java/util/logging/Logger.log(Ljava/util/logging/Level;Ljava/lang/String;)V" and the forms
+// with a parameter or an array of parameters
+func loggerLog(params []interface{}) interface{} {
+	logger, levelName, value, msg, gerr := getLogRecord("loggerLog", params)
+	if gerr != nil {
+		return gerr
+	}
+	if len(params) > 3 {
+		if arg, ok := params[3].(*object.Object); ok && !object.IsNull(arg) {
+			if strings.HasPrefix(object.GoStringFromStringPoolIndex(arg.KlassName), types.RefArray) {
+				msg = formatLogMessage(msg, arg.FieldTable["value"].Fvalue.([]*object.Object))
+			} else {
+				msg = formatLogMessage(msg, []*object.Object{arg})
+			}
+		}
+	}
+	publishLogRecord(logger, levelName, value, msg)
+	return nil
+}
+
+// "java/util/logging/Logger.log(Ljava/util/logging/Level;Ljava/lang/String;Ljava/lang/Throwable;)V" --
+// the Throwable is described on the line after the message
+func loggerLogThrown(params []interface{}) interface{} {
+	logger, levelName, value, msg, gerr := getLogRecord("loggerLogThrown", params)
+	if gerr != nil {
+		return gerr
+	}
+	if thrown, ok := params[3].(*object.Object); ok && !object.IsNull(thrown) {
+		msg += "\n" + throwableDescription(thrown)
+	}
+	publishLogRecord(logger, levelName, value, msg)
+	return nil
+}
+
+// getLogRecord returns the logger, level name and value, and message in the parameters of log()
+func getLogRecord(funcName string, params []interface{}) (*object.Object, string, int64, string, *GErrBlk) {
+	logger, gerr := getLoggerObject(funcName, params)
+	if gerr != nil {
+		return nil, "", 0, "", gerr
+	}
+	levelName, value, gerr := getLoggingLevel(funcName, params[1])
+	if gerr != nil {
+		return nil, "", 0, "", gerr
+	}
+	msg := types.NullString
+	if msgObj, ok := params[2].(*object.Object); ok && !object.IsNull(msgObj) {
+		msg = object.GoStringFromStringObject(msgObj)
+	}
+	return logger, levelName, value, msg, nil
+}
+
+// formatLogMessage substitutes the parameters for {0}, {1}, and so on in a message, as
+// java.util.logging.Formatter does for messages that contain {0} through {3}
+func formatLogMessage(msg string, args []*object.Object) string {
+	if !strings.Contains(msg, "{0") && !strings.Contains(msg, "{1") &&
+		!strings.Contains(msg, "{2") && !strings.Contains(msg, "{3") {
+		return msg
+	}
+	for ix, arg := range args {
+		msg = strings.ReplaceAll(msg, fmt.Sprintf("{%d}", ix), object.StringifyAnythingGo(arg))
+	}
+	return msg
+}
+
+// throwableDescription returns what Throwable.toString() would: the class name and the message
+func throwableDescription(throwable *object.Object) string {
+	description := strings.ReplaceAll(object.GoStringFromStringPoolIndex(throwable.KlassName), "/", ".")
+	if msgObj, ok := throwable.FieldTable["detailMessage"].Fvalue.(*object.Object); ok && !object.IsNull(msgObj) {
+		description += ": " + object.GoStringFromStringObject(msgObj)
+	}
+	return description
+}
+
+// publishLogRecord writes a message through the handlers of the logger and its parents, if
+// the logger's effective level lets it through
+func publishLogRecord(logger *object.Object, levelName string, value int64, msg string) {
+	loggingMutex.Lock()
+	threshold := effectiveLevel(logger)
+	if value < threshold || threshold == loggingLevelValues["OFF"] {
+		loggingMutex.Unlock()
+		return
+	}
+
+	text := levelName + ": " + msg
+	if name := loggerNameOf(logger); name != "" {
+		text = name + " " + text
+	}
+
+	count := 0
+	for ; logger != nil; logger = loggerParent(logger) {
+		for _, handler := range logger.FieldTable["handlers"].Fvalue.([]*object.Object) {
+			handlerLevel := loggingLevelValues["ALL"]
+			if level, ok := handler.FieldTable["level"].Fvalue.(*object.Object); ok && !object.IsNull(level) {
+				handlerLevel, _ = level.FieldTable["value"].Fvalue.(int64)
+			}
+			if value >= handlerLevel && handlerLevel != loggingLevelValues["OFF"] {
+				count++
+			}
+		}
+		if logger.FieldTable["useParentHandlers"].Fvalue != types.JavaBoolTrue {
+			break
+		}
+	}
+	loggingMutex.Unlock()
+
+	// Each handler that accepts the record writes it.
+	for ; count > 0; count-- {
+		trace.Log(trace.Java, loggingTraceLevel(value), trace.NoThread, text)
+	}
+}
+
+// "java/util/logging/Logger.severe(Ljava/lang/String;)V" and the other convenience methods
+func loggerSevere(params []interface{}) interface{}  { return loggerLogAt("SEVERE", params) }
+func loggerWarning(params []interface{}) interface{} { return loggerLogAt("WARNING", params) }
+func loggerInfo(params []interface{}) interface{}    { return loggerLogAt("INFO", params) }
+func loggerConfig(params []interface{}) interface{}  { return loggerLogAt("CONFIG", params) }
+func loggerFine(params []interface{}) interface{}    { return loggerLogAt("FINE", params) }
+func loggerFiner(params []interface{}) interface{}   { return loggerLogAt("FINER", params) }
+func loggerFinest(params []interface{}) interface{}  { return loggerLogAt("FINEST", params) }
+
+func loggerLogAt(levelName string, params []interface{}) interface{} {
+	return loggerLog([]interface{}{params[0], loggingLevelObject(levelName), params[1]})
+}
+
+// ---------------------------------------------------------------------------------
+// ConsoleHandler
+// ---------------------------------------------------------------------------------
+
+// makeConsoleHandler creates a ConsoleHandler at level INFO, as the JDK's default configuration does
+func makeConsoleHandler() *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&classNameConsoleHandler)
+	consoleHandlerInit([]interface{}{obj})
+	return obj
+}
+
+// "java/util/logging/ConsoleHandler.<init>()V"
+func consoleHandlerInit(params []interface{}) interface{} {
+	obj := params[0].(*object.Object)
+	obj.FieldTable["level"] = object.Field{Ftype: "Ljava/util/logging/Level;", Fvalue: loggingLevelObject("INFO")}
+	obj.FieldTable["formatter"] = object.Field{Ftype: "Ljava/util/logging/Formatter;", Fvalue: object.Null}
+	return nil
+}
+
+// "java/util/logging/Handler.setFormatter(Ljava/util/logging/Formatter;)V" -- kept, but records
+// are always written in the one format
+func handlerSetFormatter(params []interface{}) interface{} {
+	obj := params[0].(*object.Object)
+	formatter, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(formatter) {
+		return getGErrBlk(excNames.NullPointerException, "handlerSetFormatter: null formatter")
+	}
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	obj.FieldTable["formatter"] = object.Field{Ftype: "Ljava/util/logging/Formatter;", Fvalue: formatter}
+	return nil
+}
+
+// "java/util/logging/Handler.getFormatter()Ljava/util/logging/Formatter;"
+func handlerGetFormatter(params []interface{}) interface{} {
+	obj := params[0].(*object.Object)
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	if formatter, ok := obj.FieldTable["formatter"].Fvalue.(*object.Object); ok {
+		return formatter
+	}
+	return object.Null
+}
@@ -0,0 +1,142 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"bytes"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+	"strings"
+	"testing"
+)
+
+// resets the logger registry and captures what the loggers write
+func setUpLogging(t *testing.T) *bytes.Buffer {
+	globals.InitGlobals("test")
+	trace.Init()
+	loggers = make(map[string]*object.Object)
+	var out bytes.Buffer
+	globals.SetTraceWriter(&out)
+	t.Cleanup(func() { globals.SetTraceWriter(nil) })
+	return &out
+}
+
+func TestLoggerLevelsAndOutput(t *testing.T) {
+	out := setUpLogging(t)
+
+	logger := loggerGetLogger([]interface{}{object.StringObjectFromGoString("com.example.App")}).(*object.Object)
+	same := loggerGetLogger([]interface{}{object.StringObjectFromGoString("com.example.App")})
+	if same != logger {
+		t.Errorf("getLogger returned a different logger for the same name")
+	}
+
+	loggerInfo([]interface{}{logger, object.StringObjectFromGoString("started")})
+	loggerWarning([]interface{}{logger, object.StringObjectFromGoString("low disk")})
+	loggerSevere([]interface{}{logger, object.StringObjectFromGoString("failed")})
+	loggerFine([]interface{}{logger, object.StringObjectFromGoString("not shown")})
+
+	got := out.String()
+	for _, want := range []string{"com.example.App INFO: started\n", "WARNING: com.example.App WARNING: low disk\n",
+		"ERROR: com.example.App SEVERE: failed\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output %q does not contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "not shown") {
+		t.Errorf("a FINE message was logged at the default level: %q", got)
+	}
+
+	// a logger at FINE passes the record, but the root's ConsoleHandler is still at INFO
+	loggingSetLevel([]interface{}{logger, loggingLevelObject("FINE")})
+	if loggerIsLoggable([]interface{}{logger, loggingLevelObject("FINE")}) != types.JavaBoolTrue {
+		t.Errorf("isLoggable(FINE) is false after setLevel(FINE)")
+	}
+	out.Reset()
+	loggerFine([]interface{}{logger, object.StringObjectFromGoString("detail")})
+	if out.Len() != 0 {
+		t.Errorf("FINE message got past the root handler: %q", out.String())
+	}
+
+	// a FINE handler on the logger writes it, and the parent handlers can be turned off
+	handler := newZipObject(classNameConsoleHandler)
+	consoleHandlerInit([]interface{}{handler})
+	loggingSetLevel([]interface{}{handler, loggingLevelObject("ALL")})
+	loggerAddHandler([]interface{}{logger, handler})
+	loggerSetUseParentHandlers([]interface{}{logger, types.JavaBoolFalse})
+	loggerFine([]interface{}{logger, object.StringObjectFromGoString("detail")})
+	loggerInfo([]interface{}{logger, object.StringObjectFromGoString("once")})
+	if got := out.String(); !strings.Contains(got, "com.example.App FINE: detail") || strings.Count(got, "once") != 1 {
+		t.Errorf("output with a FINE handler and no parent handlers = %q", got)
+	}
+
+	// a child inherits its level from its nearest registered ancestor
+	child := loggerGetLogger([]interface{}{object.StringObjectFromGoString("com.example.App.db")}).(*object.Object)
+	if parent := loggerGetParent([]interface{}{child}); parent != logger {
+		t.Errorf("getParent of com.example.App.db is not com.example.App")
+	}
+	if loggerIsLoggable([]interface{}{child, loggingLevelObject("FINE")}) != types.JavaBoolTrue {
+		t.Errorf("child did not inherit level FINE")
+	}
+
+	loggingSetLevel([]interface{}{logger, loggingLevelObject("OFF")})
+	if loggerIsLoggable([]interface{}{child, loggingLevelObject("SEVERE")}) != types.JavaBoolFalse {
+		t.Errorf("isLoggable(SEVERE) is true under level OFF")
+	}
+}
+
+func TestLoggerLogWithParameters(t *testing.T) {
+	out := setUpLogging(t)
+
+	logger := loggerGetGlobal(nil).(*object.Object)
+	level := loggingLevelObject("INFO")
+
+	loggerLog([]interface{}{logger, level, object.StringObjectFromGoString("user {0} logged in"),
+		object.StringObjectFromGoString("ada")})
+	args := object.Make1DimRefArray("java/lang/Object;", 2)
+	values := args.FieldTable["value"].Fvalue.([]*object.Object)
+	values[0] = object.StringObjectFromGoString("a")
+	values[1] = object.StringObjectFromGoString("b")
+	loggerLog([]interface{}{logger, level, object.StringObjectFromGoString("{1} then {0}"), args})
+	loggerLog([]interface{}{logger, level, object.StringObjectFromGoString("no {braces}"), args})
+
+	exceptionClass := "java/lang/IllegalStateException"
+	thrown := object.MakeEmptyObjectWithClassName(&exceptionClass)
+	thrown.FieldTable["detailMessage"] = object.Field{Ftype: types.StringClassRef, Fvalue: object.StringObjectFromGoString("bad state")}
+	loggerLogThrown([]interface{}{logger, loggingLevelObject("WARNING"), object.StringObjectFromGoString("oops"), thrown})
+
+	got := out.String()
+	for _, want := range []string{"global INFO: user ada logged in\n", "global INFO: b then a\n",
+		"global INFO: no {braces}\n", "global WARNING: oops\njava.lang.IllegalStateException: bad state\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output %q does not contain %q", got, want)
+		}
+	}
+}
+
+func TestLoggingLevelParse(t *testing.T) {
+	globals.InitStringPool()
+
+	level := loggingLevelParse([]interface{}{object.StringObjectFromGoString("WARNING")})
+	if name, value, _ := getLoggingLevel("test", level); name != "WARNING" || value != 900 {
+		t.Errorf("parse(WARNING) = %s %d", name, value)
+	}
+	level = loggingLevelParse([]interface{}{object.StringObjectFromGoString("500")})
+	if name, _, _ := getLoggingLevel("test", level); name != "FINE" {
+		t.Errorf("parse(500) = %s, want FINE", name)
+	}
+	level = loggingLevelParse([]interface{}{object.StringObjectFromGoString("850")})
+	if name, value, _ := getLoggingLevel("test", level); name != "850" || value != 850 {
+		t.Errorf("parse(850) = %s %d", name, value)
+	}
+	ret := loggingLevelParse([]interface{}{object.StringObjectFromGoString("LOUD")})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("parse(LOUD): expected IllegalArgumentException, got %v", ret)
+	}
+}
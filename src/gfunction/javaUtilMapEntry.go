@@ -0,0 +1,338 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"math"
+	"unicode/utf16"
+)
+
+// The Map.Entry objects of the maps implemented in Go (TreeMap and LinkedHashMap), and the
+// helpers these maps use to format and compare the keys and values they hold. Each map
+// has its own entry class, registered by registerMapEntryMethods().
+
+// The field of an entry object that holds its state
+var fieldNameMapEntry = "mapEntry"
+
+// what the iterators and the collection views of a map return
+const (
+	mapIterateKeys = iota
+	mapIterateValues
+	mapIterateEntries
+)
+
+// mapEntry is a key and its value. The entries of a set have no values.
+type mapEntry struct {
+	key   *object.Object
+	value any
+}
+
+// the state of an entry object: the entry itself, for the entries that write through to
+// the map, or a copy, for the snapshots returned by firstEntry() and the like
+type mapEntryState struct {
+	entry     *mapEntry
+	immutable bool
+}
+
+// registers the methods of the entry class of a map
+func registerMapEntryMethods(className string) {
+
+	MethodSignatures[className+".equals(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    mapEntryEquals,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[className+".getKey()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  mapEntryGetKey,
+		}
+
+	MethodSignatures[className+".getValue()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  mapEntryGetValue,
+		}
+
+	MethodSignatures[className+".hashCode()I"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    mapEntryHashCode,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[className+".setValue(Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  mapEntrySetValue,
+		}
+
+	MethodSignatures[className+".toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    mapEntryToString,
+			NeedsContext: true,
+		}
+}
+
+// returns the key, the value, or key=value, as Java's toString() methods give them
+func (e *mapEntry) toString(fs *list.List, kind int) (string, *GErrBlk) {
+	switch kind {
+	case mapIterateKeys:
+		return javaObjectString(fs, e.key)
+	case mapIterateValues:
+		return javaObjectString(fs, e.value)
+	}
+	key, gErr := javaObjectString(fs, e.key)
+	if gErr != nil {
+		return "", gErr
+	}
+	value, gErr := javaObjectString(fs, e.value)
+	return key + "=" + value, gErr
+}
+
+// makes a Map.Entry object of the class for the entry. An immutable entry is a snapshot of
+// the entry, whose setValue() throws an UnsupportedOperationException; otherwise,
+// setValue() changes the value in the map.
+func newMapEntryObject(className string, entry *mapEntry, immutable bool) *object.Object {
+	if immutable {
+		entry = &mapEntry{key: entry.key, value: entry.value}
+	}
+	obj := object.MakeEmptyObjectWithClassName(&className)
+	obj.FieldTable[fieldNameMapEntry] = object.Field{Ftype: types.MapEntry,
+		Fvalue: &mapEntryState{entry: entry, immutable: immutable}}
+	return obj
+}
+
+// returns the entry of the Map.Entry object in params[index]
+func getMapEntry(params []interface{}, index int) (*mapEntryState, bool) {
+	obj, ok := params[index].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return nil, false
+	}
+	state, ok := obj.FieldTable[fieldNameMapEntry].Fvalue.(*mapEntryState)
+	return state, ok
+}
+
+// "java/util/TreeMap$Entry.getKey()Ljava/lang/Object;" and the same method of the other entries
+func mapEntryGetKey(params []interface{}) interface{} {
+	state, ok := getMapEntry(params, 0)
+	if !ok {
+		return getGErrBlk(excNames.IllegalStateException, "mapEntryGetKey: not a map entry")
+	}
+	return state.entry.key
+}
+
+// "java/util/TreeMap$Entry.getValue()Ljava/lang/Object;" and the same method of the other entries
+func mapEntryGetValue(params []interface{}) interface{} {
+	state, ok := getMapEntry(params, 0)
+	if !ok {
+		return getGErrBlk(excNames.IllegalStateException, "mapEntryGetValue: not a map entry")
+	}
+	return state.entry.value
+}
+
+// "java/util/TreeMap$Entry.setValue(Ljava/lang/Object;)Ljava/lang/Object;" and the same
+// method of the other entries
+func mapEntrySetValue(params []interface{}) interface{} {
+	state, ok := getMapEntry(params, 0)
+	if !ok {
+		return getGErrBlk(excNames.IllegalStateException, "mapEntrySetValue: not a map entry")
+	}
+	if state.immutable {
+		return getGErrBlk(excNames.UnsupportedOperationException, "mapEntrySetValue: the entry is immutable")
+	}
+	previous := state.entry.value
+	state.entry.value = params[1]
+	return previous
+}
+
+// "java/util/TreeMap$Entry.toString()Ljava/lang/String;" and the same method of the other
+// entries -- key=value
+func mapEntryToString(params []interface{}) interface{} {
+	state, ok := getMapEntry(params, 1)
+	if !ok {
+		return getGErrBlk(excNames.IllegalStateException, "mapEntryToString: not a map entry")
+	}
+	str, gErr := state.entry.toString(params[0].(*list.List), mapIterateEntries)
+	if gErr != nil {
+		return gErr
+	}
+	return object.StringObjectFromGoString(str)
+}
+
+// "java/util/TreeMap$Entry.equals(Ljava/lang/Object;)Z" and the same method of the other
+// entries -- true if the other object is an entry with an equal key and value
+func mapEntryEquals(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	state, ok := getMapEntry(params, 1)
+	if !ok {
+		return getGErrBlk(excNames.IllegalStateException, "mapEntryEquals: not a map entry")
+	}
+	other, ok := getMapEntry(params, 2)
+	if !ok {
+		return types.JavaBoolFalse
+	}
+	for _, pair := range [][2]any{{state.entry.key, other.entry.key}, {state.entry.value, other.entry.value}} {
+		equal, gErr := javaObjectsEqual(fs, pair[0], pair[1])
+		if gErr != nil {
+			return gErr
+		}
+		if !equal {
+			return types.JavaBoolFalse
+		}
+	}
+	return types.JavaBoolTrue
+}
+
+// "java/util/TreeMap$Entry.hashCode()I" and the same method of the other entries -- the
+// hash code of the key XOR that of the value, as in Java
+func mapEntryHashCode(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	state, ok := getMapEntry(params, 1)
+	if !ok {
+		return getGErrBlk(excNames.IllegalStateException, "mapEntryHashCode: not a map entry")
+	}
+	keyHash, gErr := javaObjectHashCode(fs, state.entry.key)
+	if gErr != nil {
+		return gErr
+	}
+	valueHash, gErr := javaObjectHashCode(fs, state.entry.value)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(keyHash ^ valueHash)
+}
+
+// javaObjectString returns the string that String.valueOf() returns for the object. The
+// strings of Strings and of the boxed primitives are formed in Go; those of other objects
+// come from their toString() methods.
+func javaObjectString(fs *list.List, value any) (string, *GErrBlk) {
+	obj, ok := value.(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return "null", nil
+	}
+	fld := obj.FieldTable["value"]
+	switch *stringPool.GetStringPointer(obj.KlassName) {
+	case "java/lang/String":
+		return object.GoStringFromStringObject(obj), nil
+	case "java/lang/Byte", "java/lang/Integer", "java/lang/Long", "java/lang/Short":
+		if v, ok := fld.Fvalue.(int64); ok {
+			return fmt.Sprint(v), nil
+		}
+	case "java/lang/Character":
+		if v, ok := fld.Fvalue.(int64); ok {
+			return string(rune(v)), nil
+		}
+	case "java/lang/Boolean":
+		if v, ok := fld.Fvalue.(int64); ok {
+			return fmt.Sprint(v != types.JavaBoolFalse), nil
+		}
+	case "java/lang/Double":
+		if v, ok := fld.Fvalue.(float64); ok {
+			return object.JavaDoubleString(v), nil
+		}
+	case "java/lang/Float":
+		if v, ok := fld.Fvalue.(float64); ok {
+			return object.JavaFloatString(v), nil
+		}
+	}
+	ret, gErr := invokeJavaMethod(fs, types.ObjectClassName, "toString", "()Ljava/lang/String;", obj, nil)
+	if gErr != nil {
+		return "", gErr
+	}
+	str, ok := ret.(*object.Object)
+	if !ok || object.IsNull(str) {
+		return "null", nil
+	}
+	return object.GoStringFromStringObject(str), nil
+}
+
+// javaObjectHashCode returns the hashCode() of the object, or 0 for null. The hash codes of
+// Strings and of the boxed primitives are computed in Go, as Java computes them; those of
+// other objects come from their hashCode() methods.
+func javaObjectHashCode(fs *list.List, value any) (int32, *GErrBlk) {
+	obj, ok := value.(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return 0, nil
+	}
+	fld := obj.FieldTable["value"]
+	switch *stringPool.GetStringPointer(obj.KlassName) {
+	case "java/lang/String":
+		var hash int32
+		for _, unit := range utf16.Encode([]rune(object.GoStringFromStringObject(obj))) {
+			hash = 31*hash + int32(unit)
+		}
+		return hash, nil
+	case "java/lang/Byte", "java/lang/Character", "java/lang/Integer", "java/lang/Short":
+		if v, ok := fld.Fvalue.(int64); ok {
+			return int32(v), nil
+		}
+	case "java/lang/Long":
+		if v, ok := fld.Fvalue.(int64); ok {
+			return int32(v ^ int64(uint64(v)>>32)), nil
+		}
+	case "java/lang/Boolean":
+		if v, ok := fld.Fvalue.(int64); ok {
+			if v != types.JavaBoolFalse {
+				return 1231, nil
+			}
+			return 1237, nil
+		}
+	case "java/lang/Double":
+		if v, ok := fld.Fvalue.(float64); ok {
+			bits := math.Float64bits(v)
+			if math.IsNaN(v) {
+				bits = 0x7ff8000000000000 // the canonical NaN of Double.doubleToLongBits()
+			}
+			return int32(bits ^ bits>>32), nil
+		}
+	case "java/lang/Float":
+		if v, ok := fld.Fvalue.(float64); ok {
+			bits := math.Float32bits(float32(v))
+			if math.IsNaN(v) {
+				bits = 0x7fc00000 // the canonical NaN of Float.floatToIntBits()
+			}
+			return int32(bits), nil
+		}
+	}
+	ret, gErr := invokeJavaMethod(fs, types.ObjectClassName, "hashCode", "()I", obj, nil)
+	if gErr != nil {
+		return 0, gErr
+	}
+	hash, _ := ret.(int64)
+	return int32(hash), nil
+}
+
+// javaObjectsEqual reports whether a.equals(b), where a null equals only null. Strings and
+// boxed primitives are compared in Go; other objects, by their equals() methods.
+func javaObjectsEqual(fs *list.List, a, b any) (bool, *GErrBlk) {
+	aObj, _ := a.(*object.Object)
+	bObj, _ := b.(*object.Object)
+	if object.IsNull(aObj) || object.IsNull(bObj) {
+		return object.IsNull(aObj) && object.IsNull(bObj), nil
+	}
+	if aObj == bObj {
+		return true, nil
+	}
+	if result, ok := compareBuiltinObjects(aObj, bObj); ok {
+		return result == 0, nil
+	}
+	ret, gErr := invokeJavaMethod(fs, types.ObjectClassName, "equals", "(Ljava/lang/Object;)Z", aObj, []any{bObj})
+	if gErr != nil {
+		return false, gErr
+	}
+	return ret == types.JavaBoolTrue, nil
+}
@@ -7,16 +7,24 @@
 package gfunction
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"jacobin/src/excNames"
 	"jacobin/src/globals"
 	"jacobin/src/object"
 	"jacobin/src/types"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
+	"unicode/utf16"
 )
 
-// Implementation of some of the functions in Java/util/Locale.
-// Strategy: Locale = jacobin Object wrapping a Go string.
+// Implementation of some of the functions in java/util/Properties.
+// Strategy: Properties = jacobin Object wrapping a Go map of strings, plus an optional
+// Properties object of defaults that getProperty() falls back to.
 
 func Load_Util_Properties() {
 
@@ -41,13 +49,13 @@ func Load_Util_Properties() {
 	MethodSignatures["java/util/Properties.<init>(Ljava/util/Properties;)V"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  trapFunction,
+			GFunction:  propertiesInitDefaults,
 		}
 
 	MethodSignatures["java/util/Properties.clear()V"] =
 		GMeth{
 			ParamSlots: 0,
-			GFunction:  propertiesInit,
+			GFunction:  propertiesClear,
 		}
 
 	MethodSignatures["java/util/Properties.getProperty(Ljava/lang/String;)Ljava/lang/String;"] =
@@ -65,25 +73,25 @@ func Load_Util_Properties() {
 	MethodSignatures["java/util/Properties.list(Ljava/io/PrintStream;)V"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  trapFunction,
+			GFunction:  propertiesList,
 		}
 
 	MethodSignatures["java/util/Properties.list(Ljava/io/PrintWriter;)V"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  trapFunction,
+			GFunction:  propertiesList,
 		}
 
 	MethodSignatures["java/util/Properties.load(Ljava/io/InputStream;)V"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  trapFunction,
+			GFunction:  propertiesLoadInputStream,
 		}
 
 	MethodSignatures["java/util/Properties.load(Ljava/io/Reader;)V"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  trapFunction,
+			GFunction:  propertiesLoadReader,
 		}
 
 	MethodSignatures["java/util/Properties.loadFromXML(Ljava/io/InputStream;)V"] =
@@ -107,7 +115,7 @@ func Load_Util_Properties() {
 	MethodSignatures["java/util/Properties.save(Ljava/io/OutputStream;Ljava/lang/String;)V"] =
 		GMeth{
 			ParamSlots: 2,
-			GFunction:  trapFunction,
+			GFunction:  propertiesStoreOutputStream,
 		}
 
 	MethodSignatures["java/util/Properties.setProperty(Ljava/lang/String;Ljava/lang/String;)Ljava/lang/Object;"] =
@@ -125,13 +133,13 @@ func Load_Util_Properties() {
 	MethodSignatures["java/util/Properties.store(Ljava/io/OutputStream;Ljava/lang/String;)V"] =
 		GMeth{
 			ParamSlots: 2,
-			GFunction:  trapFunction,
+			GFunction:  propertiesStoreOutputStream,
 		}
 
 	MethodSignatures["java/util/Properties.store(Ljava/io/Writer;Ljava/lang/String;)V"] =
 		GMeth{
 			ParamSlots: 2,
-			GFunction:  trapFunction,
+			GFunction:  propertiesStoreWriter,
 		}
 
 	MethodSignatures["java/util/Properties.storeToXML(Ljava/io/OutputStream;Ljava/lang/String;)V"] =
@@ -168,6 +176,7 @@ func Load_Util_Properties() {
 
 var classNameProperties = "java/util/Properties"
 var fieldNameProperties = "map"
+var fieldNamePropertiesDefaults = "defaults"
 var propertiesMutex = sync.RWMutex{}
 
 func propertiesInit(params []interface{}) interface{} {
@@ -188,6 +197,31 @@ func propertiesInit(params []interface{}) interface{} {
 	return nil
 }
 
+// "java/util/Properties.<init>(Ljava/util/Properties;)V" -- an empty table whose getProperty()
+// falls back to the defaults table
+func propertiesInitDefaults(params []interface{}) interface{} {
+	if ret := propertiesInit(params[:1]); ret != nil {
+		return ret
+	}
+	obj := params[0].(*object.Object)
+	obj.FieldTable[fieldNamePropertiesDefaults] = object.Field{Ftype: "Ljava/util/Properties;", Fvalue: params[1]}
+	return nil
+}
+
+// "java/util/Properties.clear()V" -- empties the table, but keeps the defaults
+func propertiesClear(params []interface{}) interface{} {
+	propertiesMutex.Lock()
+	defer propertiesMutex.Unlock()
+
+	this, ok := params[0].(*object.Object)
+	if !ok {
+		errMsg := fmt.Sprintf("propertiesClear: Properties object is invalid: {type %T, value %v}", params[0], params[0])
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	this.FieldTable[fieldNameProperties] = object.Field{Ftype: types.Properties, Fvalue: make(types.DefProperties)}
+	return nil
+}
+
 // Given a properties table and a key, retrieve the associated value.
 func propertiesGetProperty(params []interface{}) interface{} {
 	// Get properties table.
@@ -237,6 +271,14 @@ func propertiesGetProperty(params []interface{}) interface{} {
 	}
 
 	// Value is not present.
+	// If there is a defaults table with the key, return its value.
+	if defaults, ok := this.FieldTable[fieldNamePropertiesDefaults].Fvalue.(*object.Object); ok && !object.IsNull(defaults) {
+		dfltProperty := propertiesGetProperty([]interface{}{defaults, keyObj})
+		if dfltObj, ok := dfltProperty.(*object.Object); ok && !object.IsNull(dfltObj) {
+			return dfltObj
+		}
+	}
+
 	// If default value supplied, return it.
 	// Otherwise, return null.
 	if flagDefault {
@@ -406,3 +448,405 @@ func propertiesToString(params []interface{}) interface{} {
 	// Return longString as a Java String.
 	return object.StringObjectFromGoString(longString)
 }
+
+// "java/util/Properties.load(Ljava/io/InputStream;)V" -- the stream is read as ISO 8859-1
+func propertiesLoadInputStream(params []interface{}) interface{} {
+	return propertiesLoad("propertiesLoadInputStream", params, true)
+}
+
+// "java/util/Properties.load(Ljava/io/Reader;)V" -- the reader is read as UTF-8
+func propertiesLoadReader(params []interface{}) interface{} {
+	return propertiesLoad("propertiesLoadReader", params, false)
+}
+
+// Read all of the input stream or reader in params[1] and add its key-value pairs to the properties table.
+func propertiesLoad(funcName string, params []interface{}, latin1 bool) interface{} {
+	this, ok := params[0].(*object.Object)
+	if !ok {
+		errMsg := fmt.Sprintf("%s: Properties object is invalid: {type %T, value %v}", funcName, params[0], params[0])
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	properties, ok := this.FieldTable[fieldNameProperties].Fvalue.(types.DefProperties)
+	if !ok {
+		errMsg := fmt.Sprintf("%s: Properties table is missing or invalid", funcName)
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+
+	// Get the whole of the input.
+	streamObj, ok := params[1].(*object.Object)
+	if !ok {
+		return getGErrBlk(excNames.NullPointerException, funcName+": null input stream")
+	}
+	reader, gerr := getSourceReader(funcName, streamObj)
+	if gerr != nil {
+		return gerr
+	}
+	contents, err := io.ReadAll(reader)
+	if err != nil {
+		errMsg := fmt.Sprintf("%s: %s", funcName, err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+
+	text := string(contents)
+	if latin1 {
+		text = latin1String(contents)
+	}
+
+	propertiesMutex.Lock()
+	defer propertiesMutex.Unlock()
+	if err = propertiesParse(text, properties); err != nil {
+		errMsg := fmt.Sprintf("%s: %s", funcName, err.Error())
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	return nil
+}
+
+// latin1String decodes ISO 8859-1 bytes, which are the first 256 Unicode characters
+func latin1String(contents []byte) string {
+	runes := make([]rune, len(contents))
+	for ix, b := range contents {
+		runes[ix] = rune(b)
+	}
+	return string(runes)
+}
+
+// Is this one of the whitespace characters of a .properties file?
+func isPropertiesWhitespace(ch rune) bool {
+	return ch == ' ' || ch == '\t' || ch == '\f'
+}
+
+// Parse the text of a .properties file into the properties table:
+//   - blank lines, and lines whose first non-whitespace character is # or !, are skipped;
+//   - a line ending in an odd number of backslashes continues on the next line, whose
+//     leading whitespace is skipped;
+//   - the key ends at the first unescaped =, :, or whitespace; whitespace and one = or :
+//     after the key are skipped, and the rest of the line is the value.
+func propertiesParse(text string, properties types.DefProperties) error {
+	chars := []rune(text)
+	ix := 0
+	for ix < len(chars) {
+		ch := chars[ix]
+		if isPropertiesWhitespace(ch) || ch == '\n' || ch == '\r' {
+			ix++
+			continue
+		}
+
+		// A comment runs to the end of the line and is never continued.
+		if ch == '#' || ch == '!' {
+			for ix < len(chars) && chars[ix] != '\n' && chars[ix] != '\r' {
+				ix++
+			}
+			continue
+		}
+
+		// Collect the logical line, joining continued lines.
+		var line []rune
+		for ix < len(chars) {
+			ch = chars[ix]
+			if ch != '\n' && ch != '\r' {
+				line = append(line, ch)
+				ix++
+				continue
+			}
+			if !endsInOddBackslashes(line) {
+				break
+			}
+			line = line[:len(line)-1]
+			if ch == '\r' && ix+1 < len(chars) && chars[ix+1] == '\n' {
+				ix++
+			}
+			ix++
+			for ix < len(chars) && isPropertiesWhitespace(chars[ix]) {
+				ix++
+			}
+		}
+		if endsInOddBackslashes(line) { // a backslash at the end of the input is dropped
+			line = line[:len(line)-1]
+		}
+
+		// Find the end of the key and the start of the value.
+		keyLen, valueStart := 0, len(line)
+		hasSeparator, precedingBackslash := false, false
+		for keyLen < len(line) {
+			ch = line[keyLen]
+			if (ch == '=' || ch == ':') && !precedingBackslash {
+				valueStart = keyLen + 1
+				hasSeparator = true
+				break
+			}
+			if isPropertiesWhitespace(ch) && !precedingBackslash {
+				valueStart = keyLen + 1
+				break
+			}
+			precedingBackslash = ch == '\\' && !precedingBackslash
+			keyLen++
+		}
+		for valueStart < len(line) {
+			ch = line[valueStart]
+			if !isPropertiesWhitespace(ch) {
+				if hasSeparator || (ch != '=' && ch != ':') {
+					break
+				}
+				hasSeparator = true
+			}
+			valueStart++
+		}
+
+		key, err := propertiesUnescape(line[:keyLen])
+		if err != nil {
+			return err
+		}
+		value, err := propertiesUnescape(line[valueStart:])
+		if err != nil {
+			return err
+		}
+		properties[key] = value
+	}
+	return nil
+}
+
+// Does the line end in an odd number of backslashes, that is, an unescaped backslash?
+func endsInOddBackslashes(line []rune) bool {
+	count := 0
+	for ix := len(line) - 1; ix >= 0 && line[ix] == '\\'; ix-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+// Convert the escapes \t, \n, \r, \f, and \uxxxx to the characters they stand for;
+// a backslash before any other character is dropped.
+func propertiesUnescape(chars []rune) (string, error) {
+	var sb strings.Builder
+	var utf16Units []uint16
+	flush := func() {
+		if len(utf16Units) > 0 {
+			sb.WriteString(string(utf16.Decode(utf16Units)))
+			utf16Units = utf16Units[:0]
+		}
+	}
+
+	for ix := 0; ix < len(chars); ix++ {
+		ch := chars[ix]
+		if ch != '\\' {
+			flush()
+			sb.WriteRune(ch)
+			continue
+		}
+		ix++
+		if ix >= len(chars) {
+			break
+		}
+		ch = chars[ix]
+		if ch == 'u' {
+			// \uxxxx escapes are UTF-16 code units; surrogate pairs are combined when flushed.
+			if ix+4 >= len(chars) {
+				return "", errors.New("Malformed \\uxxxx encoding.")
+			}
+			unit, err := strconv.ParseUint(string(chars[ix+1:ix+5]), 16, 16)
+			if err != nil {
+				return "", errors.New("Malformed \\uxxxx encoding.")
+			}
+			utf16Units = append(utf16Units, uint16(unit))
+			ix += 4
+			continue
+		}
+		flush()
+		switch ch {
+		case 't':
+			ch = '\t'
+		case 'r':
+			ch = '\r'
+		case 'n':
+			ch = '\n'
+		case 'f':
+			ch = '\f'
+		}
+		sb.WriteRune(ch)
+	}
+	flush()
+	return sb.String(), nil
+}
+
+// "java/util/Properties.store(Ljava/io/OutputStream;Ljava/lang/String;)V" -- written in ISO 8859-1,
+// with other characters as \uxxxx escapes. The deprecated save() is the same.
+func propertiesStoreOutputStream(params []interface{}) interface{} {
+	return propertiesStore("propertiesStoreOutputStream", params, true)
+}
+
+// "java/util/Properties.store(Ljava/io/Writer;Ljava/lang/String;)V" -- written in UTF-8
+func propertiesStoreWriter(params []interface{}) interface{} {
+	return propertiesStore("propertiesStoreWriter", params, false)
+}
+
+// Write the comments, if not null, a timestamp comment, and the entries sorted by key, in a form
+// that load() reads back.
+func propertiesStore(funcName string, params []interface{}, latin1 bool) interface{} {
+	this, ok := params[0].(*object.Object)
+	if !ok {
+		errMsg := fmt.Sprintf("%s: Properties object is invalid: {type %T, value %v}", funcName, params[0], params[0])
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	properties, ok := this.FieldTable[fieldNameProperties].Fvalue.(types.DefProperties)
+	if !ok {
+		errMsg := fmt.Sprintf("%s: Properties table is missing or invalid", funcName)
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	writer, gerr := getSinkWriter(funcName, params[1])
+	if gerr != nil {
+		return gerr
+	}
+	lineSeparator := globals.GetLineSeparator()
+
+	var sb strings.Builder
+	if commentsObj, ok := params[2].(*object.Object); ok && !object.IsNull(commentsObj) {
+		propertiesWriteComments(&sb, object.GoStringFromStringObject(commentsObj), lineSeparator)
+	}
+	sb.WriteString("#" + time.Now().Format("Mon Jan 02 15:04:05 MST 2006") + lineSeparator)
+
+	propertiesMutex.RLock()
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		sb.WriteString(propertiesEscape(key, true, latin1))
+		sb.WriteString("=")
+		sb.WriteString(propertiesEscape(properties[key], false, latin1))
+		sb.WriteString(lineSeparator)
+	}
+	propertiesMutex.RUnlock()
+
+	// After escaping, every character of the ISO 8859-1 form fits in a byte.
+	output := []byte(sb.String())
+	if latin1 {
+		output = output[:0]
+		for _, ch := range sb.String() {
+			output = append(output, byte(ch))
+		}
+	}
+	if _, err := writer.Write(output); err != nil {
+		errMsg := fmt.Sprintf("%s: %s", funcName, err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	return nil
+}
+
+// Write the comments as comment lines: each line break starts a new line, which gets a leading #
+// unless it already starts with # or !. Characters beyond ISO 8859-1 are written as \uxxxx.
+func propertiesWriteComments(sb *strings.Builder, comments, lineSeparator string) {
+	chars := []rune(comments)
+	sb.WriteString("#")
+	for ix := 0; ix < len(chars); ix++ {
+		ch := chars[ix]
+		switch {
+		case ch == '\n' || ch == '\r':
+			if ch == '\r' && ix+1 < len(chars) && chars[ix+1] == '\n' {
+				ix++
+			}
+			sb.WriteString(lineSeparator)
+			if ix+1 == len(chars) || (chars[ix+1] != '#' && chars[ix+1] != '!') {
+				sb.WriteString("#")
+			}
+		case ch > 0xff:
+			for _, unit := range utf16.Encode([]rune{ch}) {
+				fmt.Fprintf(sb, "\\u%04X", unit)
+			}
+		default:
+			sb.WriteRune(ch)
+		}
+	}
+	sb.WriteString(lineSeparator)
+}
+
+// Escape a key or value so that load() reads it back unchanged. Spaces are escaped throughout
+// keys, but only at the start of values. If escapeUnicode, characters outside printable
+// ASCII are written as \uxxxx.
+func propertiesEscape(str string, escapeSpace, escapeUnicode bool) string {
+	var sb strings.Builder
+	for ix, ch := range []rune(str) {
+		switch ch {
+		case '\\':
+			sb.WriteString("\\\\")
+		case ' ':
+			if ix == 0 || escapeSpace {
+				sb.WriteString("\\ ")
+			} else {
+				sb.WriteRune(ch)
+			}
+		case '\t':
+			sb.WriteString("\\t")
+		case '\n':
+			sb.WriteString("\\n")
+		case '\r':
+			sb.WriteString("\\r")
+		case '\f':
+			sb.WriteString("\\f")
+		case '=', ':', '#', '!':
+			sb.WriteRune('\\')
+			sb.WriteRune(ch)
+		default:
+			if (ch < 0x20 || ch > 0x7e) && escapeUnicode {
+				for _, unit := range utf16.Encode([]rune{ch}) {
+					fmt.Fprintf(&sb, "\\u%04X", unit)
+				}
+			} else {
+				sb.WriteRune(ch)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// "java/util/Properties.list(Ljava/io/PrintStream;)V" and list(Ljava/io/PrintWriter;)V --
+// a debugging listing of the entries and defaults, with values longer than 40 characters cut short
+func propertiesList(params []interface{}) interface{} {
+	this, ok := params[0].(*object.Object)
+	if !ok {
+		errMsg := fmt.Sprintf("propertiesList: Properties object is invalid: {type %T, value %v}", params[0], params[0])
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	writer, gerr := getSinkWriter("propertiesList", params[1])
+	if gerr != nil {
+		return gerr
+	}
+
+	// Gather the entries, starting with the defaults, which the table's own entries override.
+	entries := make(map[string]string)
+	var gather func(obj *object.Object)
+	gather = func(obj *object.Object) {
+		if defaults, ok := obj.FieldTable[fieldNamePropertiesDefaults].Fvalue.(*object.Object); ok && !object.IsNull(defaults) {
+			gather(defaults)
+		}
+		properties, _ := obj.FieldTable[fieldNameProperties].Fvalue.(types.DefProperties)
+		for key, value := range properties {
+			entries[key] = value
+		}
+	}
+	propertiesMutex.RLock()
+	gather(this)
+	propertiesMutex.RUnlock()
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lineSeparator := globals.GetLineSeparator()
+	var sb strings.Builder
+	sb.WriteString("-- listing properties --" + lineSeparator)
+	for _, key := range keys {
+		value := []rune(entries[key])
+		if len(value) > 40 {
+			value = append(value[:37], []rune("...")...)
+		}
+		sb.WriteString(key + "=" + string(value) + lineSeparator)
+	}
+	if _, err := io.WriteString(writer, sb.String()); err != nil {
+		errMsg := fmt.Sprintf("propertiesList: %s", err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	return nil
+}
@@ -4,6 +4,9 @@ import (
     "jacobin/src/excNames"
     "jacobin/src/globals"
     "jacobin/src/object"
+    "jacobin/src/types"
+    "reflect"
+    "strings"
     "testing"
 )
 
@@ -129,3 +132,154 @@ func TestProperties_Error_Paths(t *testing.T) {
         t.Fatalf("expected error for non-object key in remove")
     } else { expectErrType(t, err, excNames.IllegalArgumentException) }
 }
+
+// returns a java/io/StringReader holding str, as its constructor leaves it
+func newPropertiesStringReader(str string) *object.Object {
+    reader := newZipObject("java/io/StringReader")
+    reader.FieldTable["str"] = object.Field{Ftype: types.StringClassRef, Fvalue: s(str)}
+    reader.FieldTable["next"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+    return reader
+}
+
+// returns a java/io/ByteArrayInputStream or ByteArrayOutputStream over b
+func newPropertiesByteArrayStream(className string, b []byte) *object.Object {
+    stream := newZipObject(className)
+    stream.FieldTable["buf"] = object.Field{Ftype: types.ByteArray, Fvalue: newZipByteArray(b)}
+    stream.FieldTable["pos"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+    stream.FieldTable["count"] = object.Field{Ftype: types.Int, Fvalue: int64(len(b))}
+    return stream
+}
+
+func TestProperties_Load_EscapesAndContinuations(t *testing.T) {
+    globals.InitStringPool()
+
+    text := "# a comment\n" +
+        "! another comment \\\n" +
+        "key1 = value1\n" +
+        "key2:value2\r\n" +
+        "key3   value3\r" +
+        "  spaced\\ key = leading\n" +
+        "continued = first \\\n" +
+        "            second\n" +
+        "escapes = tab\\there\\nnew \\u0041\\u00e9\n" +
+        "empty=\n" +
+        "noseparator\n" +
+        "a\\=b = c\n" +
+        "trailing = x\\\\\n" +
+        "surrogates = \\ud83d\\ude00"
+    p := newPropertiesObj()
+    propInit(t, p)
+    if ret := propertiesLoadReader([]interface{}{p, newPropertiesStringReader(text)}); ret != nil {
+        t.Fatalf("load(Reader) failed: %v", ret)
+    }
+
+    want := types.DefProperties{
+        "key1": "value1", "key2": "value2", "key3": "value3", "spaced key": "leading",
+        "continued": "first second", "escapes": "tab\there\nnew Aé", "empty": "",
+        "noseparator": "", "a=b": "c", "trailing": `x\`, "surrogates": "😀",
+    }
+    got := p.FieldTable[fieldNameProperties].Fvalue.(types.DefProperties)
+    if !reflect.DeepEqual(got, want) {
+        t.Fatalf("load(Reader):\n got %q\nwant %q", got, want)
+    }
+
+    // load(InputStream) reads ISO 8859-1
+    p = newPropertiesObj()
+    propInit(t, p)
+    stream := newPropertiesByteArrayStream("java/io/ByteArrayInputStream", []byte("caf\xe9=cr\xe8me\n"))
+    if ret := propertiesLoadInputStream([]interface{}{p, stream}); ret != nil {
+        t.Fatalf("load(InputStream) failed: %v", ret)
+    }
+    if v := object.GoStringFromStringObject(propertiesGetProperty([]interface{}{p, s("café")}).(*object.Object)); v != "crème" {
+        t.Fatalf("load(InputStream): expected crème, got %q", v)
+    }
+
+    ret := propertiesLoadReader([]interface{}{p, newPropertiesStringReader("bad = \\u12")})
+    expectErrType(t, ret, excNames.IllegalArgumentException)
+}
+
+func TestProperties_Store_RoundTrip(t *testing.T) {
+    globals.InitStringPool()
+
+    p := newPropertiesObj()
+    propInit(t, p)
+    _ = propertiesSetProperty([]interface{}{p, s("b"), s(" x y")})
+    _ = propertiesSetProperty([]interface{}{p, s("a b"), s("=1#")})
+    _ = propertiesSetProperty([]interface{}{p, s("u"), s("é€")})
+
+    out := newPropertiesByteArrayStream("java/io/ByteArrayOutputStream", nil)
+    if ret := propertiesStoreOutputStream([]interface{}{p, out, s("hello\nworld")}); ret != nil {
+        t.Fatalf("store(OutputStream) failed: %v", ret)
+    }
+    written := string(zipByteArrayContents(out.FieldTable["buf"].Fvalue.(*object.Object)))
+    lines := strings.Split(written, globals.GetLineSeparator())
+    if len(lines) != 7 || lines[0] != "#hello" || lines[1] != "#world" || !strings.HasPrefix(lines[2], "#") {
+        t.Fatalf("store: unexpected comment lines in %q", written)
+    }
+    wantEntries := []string{`a\ b=\=1\#`, `b=\ x y`, `u=\u00E9\u20AC`}
+    if !reflect.DeepEqual(lines[3:6], wantEntries) {
+        t.Fatalf("store: entries %q, want %q", lines[3:6], wantEntries)
+    }
+
+    // what store() writes, load() reads back
+    loaded := newPropertiesObj()
+    propInit(t, loaded)
+    in := newPropertiesByteArrayStream("java/io/ByteArrayInputStream", []byte(written))
+    if ret := propertiesLoadInputStream([]interface{}{loaded, in}); ret != nil {
+        t.Fatalf("load of stored properties failed: %v", ret)
+    }
+    if !reflect.DeepEqual(loaded.FieldTable[fieldNameProperties].Fvalue, p.FieldTable[fieldNameProperties].Fvalue) {
+        t.Fatalf("round trip: got %q, want %q", loaded.FieldTable[fieldNameProperties].Fvalue,
+            p.FieldTable[fieldNameProperties].Fvalue)
+    }
+
+    // store(Writer) doesn't escape non-ASCII characters
+    out = newPropertiesByteArrayStream("java/io/ByteArrayOutputStream", nil)
+    if ret := propertiesStoreWriter([]interface{}{p, out, object.Null}); ret != nil {
+        t.Fatalf("store(Writer) failed: %v", ret)
+    }
+    written = string(zipByteArrayContents(out.FieldTable["buf"].Fvalue.(*object.Object)))
+    if !strings.Contains(written, "u=é€") || strings.Count(written, "#") != 2 {
+        t.Fatalf("store(Writer): unexpected output %q", written)
+    }
+}
+
+func TestProperties_Defaults_Clear_List(t *testing.T) {
+    globals.InitStringPool()
+
+    defaults := newPropertiesObj()
+    propInit(t, defaults)
+    _ = propertiesSetProperty([]interface{}{defaults, s("color"), s("blue")})
+    _ = propertiesSetProperty([]interface{}{defaults, s("long"), s(strings.Repeat("z", 50))})
+
+    p := newPropertiesObj()
+    if ret := propertiesInitDefaults([]interface{}{p, defaults}); ret != nil {
+        t.Fatalf("<init>(Properties) failed: %v", ret)
+    }
+    _ = propertiesSetProperty([]interface{}{p, s("size"), s("9")})
+    if v := object.GoStringFromStringObject(propertiesGetProperty([]interface{}{p, s("color")}).(*object.Object)); v != "blue" {
+        t.Fatalf("getProperty did not fall back to defaults: got %q", v)
+    }
+    if sz := propertiesSize([]interface{}{p}).(int64); sz != 1 {
+        t.Fatalf("defaults must not count toward size: got %d", sz)
+    }
+
+    out := newPropertiesByteArrayStream("java/io/ByteArrayOutputStream", nil)
+    if ret := propertiesList([]interface{}{p, out}); ret != nil {
+        t.Fatalf("list failed: %v", ret)
+    }
+    sep := globals.GetLineSeparator()
+    want := "-- listing properties --" + sep + "color=blue" + sep + "long=" + strings.Repeat("z", 37) + "..." + sep + "size=9" + sep
+    if got := string(zipByteArrayContents(out.FieldTable["buf"].Fvalue.(*object.Object))); got != want {
+        t.Fatalf("list: got %q, want %q", got, want)
+    }
+
+    // clear() empties the table, but the defaults remain
+    _ = propertiesClear([]interface{}{p})
+    if sz := propertiesSize([]interface{}{p}).(int64); sz != 0 {
+        t.Fatalf("size after clear expected 0, got %d", sz)
+    }
+    if v := propertiesGetProperty([]interface{}{p, s("color")}); v == object.Null {
+        t.Fatalf("clear removed the defaults")
+    }
+}
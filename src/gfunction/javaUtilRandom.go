@@ -5,6 +5,7 @@ import (
 	"jacobin/src/excNames"
 	"jacobin/src/globals"
 	"jacobin/src/object"
+	"jacobin/src/replay"
 	"jacobin/src/types"
 	"math"
 	"math/rand"
@@ -200,7 +201,8 @@ func GetStructFromRandomObject(objPtr *object.Object) Random {
 // NewRandom creates a new Random instance initialized with the current time as seed.
 // chatGPT generated: func NewRandom() *Random
 func randomInitVoid(params []interface{}) interface{} {
-	source := rand.NewSource(time.Now().UnixNano())
+	seed := replay.Int64(replay.KindSeed, func() int64 { return time.Now().UnixNano() }) // see replay.go
+	source := rand.NewSource(seed)
 	randStruct := Random{
 		rand:                 rand.New(source),
 		nextNextGaussian:     0.0,
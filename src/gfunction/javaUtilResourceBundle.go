@@ -0,0 +1,389 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Implementation of java/util/ResourceBundle for bundles kept in .properties files.
+// Strategy: every bundle is a java/util/PropertyResourceBundle object wrapping a Go map
+// of its own entries and a link to its parent bundle, which is searched for keys the
+// bundle doesn't have. getBundle("msgs.App", fr_CA) looks for the resources
+// msgs/App_fr_CA.properties, msgs/App_fr.properties, and msgs/App.properties on the
+// classpath, each being the parent of the one before. If none of the locale-specific
+// resources exists, the default locale's are tried before falling back to the base bundle.
+
+var classNameResourceBundle = "java/util/ResourceBundle"
+var classNamePropertyResourceBundle = "java/util/PropertyResourceBundle"
+
+const (
+	fieldNameBundleEntries  = "entries"
+	fieldNameBundleParent   = "parent"
+	fieldNameBundleBaseName = "baseName"
+	fieldNameBundleLocale   = "locale"
+)
+
+// Loaded bundles, keyed by resource name. A nil entry records a resource that doesn't exist.
+var resourceBundleCache = make(map[string]*object.Object)
+var resourceBundleMutex = sync.Mutex{}
+
+func Load_Util_ResourceBundle() {
+
+	MethodSignatures["java/util/ResourceBundle.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/ResourceBundle.clearCache()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  resourceBundleClearCache,
+		}
+
+	MethodSignatures["java/util/ResourceBundle.clearCache(Ljava/lang/ClassLoader;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  resourceBundleClearCache,
+		}
+
+	MethodSignatures["java/util/ResourceBundle.getBundle(Ljava/lang/String;)Ljava/util/ResourceBundle;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  resourceBundleGetBundle,
+		}
+
+	MethodSignatures["java/util/ResourceBundle.getBundle(Ljava/lang/String;Ljava/util/Locale;)Ljava/util/ResourceBundle;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  resourceBundleGetBundle,
+		}
+
+	MethodSignatures["java/util/ResourceBundle.getBundle(Ljava/lang/String;Ljava/util/Locale;Ljava/lang/ClassLoader;)Ljava/util/ResourceBundle;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  resourceBundleGetBundle, // the class loader is ignored
+		}
+
+	MethodSignatures["java/util/PropertyResourceBundle.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/PropertyResourceBundle.<init>(Ljava/io/InputStream;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  propertyResourceBundleInit,
+		}
+
+	MethodSignatures["java/util/PropertyResourceBundle.<init>(Ljava/io/Reader;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  propertyResourceBundleInit,
+		}
+
+	MethodSignatures["java/util/PropertyResourceBundle.handleGetObject(Ljava/lang/String;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  propertyResourceBundleHandleGetObject,
+		}
+
+	// The instance methods are final in ResourceBundle, but calls can name either class.
+	for _, className := range []string{classNameResourceBundle, classNamePropertyResourceBundle} {
+		MethodSignatures[className+".containsKey(Ljava/lang/String;)Z"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  resourceBundleContainsKey,
+			}
+
+		MethodSignatures[className+".getBaseBundleName()Ljava/lang/String;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  resourceBundleGetBaseBundleName,
+			}
+
+		MethodSignatures[className+".getLocale()Ljava/util/Locale;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  resourceBundleGetLocale,
+			}
+
+		MethodSignatures[className+".getObject(Ljava/lang/String;)Ljava/lang/Object;"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  resourceBundleGetString,
+			}
+
+		MethodSignatures[className+".getString(Ljava/lang/String;)Ljava/lang/String;"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  resourceBundleGetString,
+			}
+	}
+}
+
+// makeResourceBundle creates a bundle object holding the given entries
+func makeResourceBundle(entries types.DefProperties, baseName string, locale string, parent *object.Object) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&classNamePropertyResourceBundle)
+	setResourceBundleFields(obj, entries, baseName, locale, parent)
+	return obj
+}
+
+func setResourceBundleFields(obj *object.Object, entries types.DefProperties, baseName string, locale string, parent *object.Object) {
+	if parent == nil {
+		parent = object.Null
+	}
+	baseNameObj := object.Null
+	if baseName != "" {
+		baseNameObj = object.StringObjectFromGoString(baseName)
+	}
+	obj.FieldTable[fieldNameBundleEntries] = object.Field{Ftype: types.Properties, Fvalue: entries}
+	obj.FieldTable[fieldNameBundleParent] = object.Field{Ftype: "Ljava/util/ResourceBundle;", Fvalue: parent}
+	obj.FieldTable[fieldNameBundleBaseName] = object.Field{Ftype: types.StringClassRef, Fvalue: baseNameObj}
+	obj.FieldTable[fieldNameBundleLocale] = object.Field{Ftype: "Ljava/util/Locale;", Fvalue: makeLocale(locale)}
+}
+
+// bundleText decodes the contents of a .properties resource. As in Java 9 and later, the
+// bytes are read as UTF-8 unless they aren't valid UTF-8, in which case they're ISO 8859-1.
+func bundleText(contents []byte) string {
+	if utf8.Valid(contents) {
+		return string(contents)
+	}
+	return latin1String(contents)
+}
+
+// bundleResourceName forms the name of the resource holding a bundle: "msgs.App" with the
+// locale fr_CA is msgs/App_fr_CA.properties
+func bundleResourceName(baseName, locale string) string {
+	name := strings.ReplaceAll(baseName, ".", "/")
+	if locale != "" {
+		name += "_" + locale
+	}
+	return name + ".properties"
+}
+
+// parentLocaleName drops the last component of a locale name: fr_CA_x to fr_CA to fr to the root, "".
+// Components left empty, as in the country of "en__POSIX", are dropped too.
+func parentLocaleName(locale string) string {
+	ix := strings.LastIndex(locale, "_")
+	if ix < 0 {
+		return ""
+	}
+	return strings.TrimRight(locale[:ix], "_")
+}
+
+// findBundle returns the most specific bundle that exists for the locale, with its chain of
+// parents, or nil if there is none, not even the base bundle. Call with resourceBundleMutex held.
+func findBundle(baseName, locale string) (*object.Object, error) {
+	var parent *object.Object
+	if locale != "" {
+		var err error
+		if parent, err = findBundle(baseName, parentLocaleName(locale)); err != nil {
+			return nil, err
+		}
+	}
+
+	resourceName := bundleResourceName(baseName, locale)
+	bundle, cached := resourceBundleCache[resourceName]
+	if !cached {
+		contents, err := classloader.FindResource(resourceName)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		if err == nil {
+			entries := make(types.DefProperties)
+			if err = propertiesParse(bundleText(contents), entries); err != nil {
+				return nil, fmt.Errorf("%s: %s", resourceName, err.Error())
+			}
+			bundle = makeResourceBundle(entries, baseName, locale, parent)
+		}
+		resourceBundleCache[resourceName] = bundle
+	}
+
+	if bundle == nil {
+		return parent, nil
+	}
+	return bundle, nil
+}
+
+// "java/util/ResourceBundle.getBundle(Ljava/lang/String;)Ljava/util/ResourceBundle;", and the
+// forms with a Locale and a ClassLoader. Without a Locale, the default locale is used.
+func resourceBundleGetBundle(params []interface{}) interface{} {
+	baseNameObj, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(baseNameObj) {
+		return getGErrBlk(excNames.NullPointerException, "resourceBundleGetBundle: null base name")
+	}
+	baseName := object.GoStringFromStringObject(baseNameObj)
+
+	defaultLocale := defaultLocaleName()
+	locale := defaultLocale
+	if len(params) > 1 {
+		localeObj, ok := params[1].(*object.Object)
+		if !ok || object.IsNull(localeObj) {
+			return getGErrBlk(excNames.NullPointerException, "resourceBundleGetBundle: null locale")
+		}
+		locale = localeName(localeObj)
+	}
+
+	resourceBundleMutex.Lock()
+	defer resourceBundleMutex.Unlock()
+
+	bundle, err := findBundle(baseName, locale)
+	if err == nil && locale != defaultLocale && (bundle == nil || bundleLocaleName(bundle) == "") {
+		var fallback *object.Object
+		fallback, err = findBundle(baseName, defaultLocale)
+		if fallback != nil {
+			bundle = fallback
+		}
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("resourceBundleGetBundle: %s", err.Error())
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	if bundle == nil {
+		errMsg := fmt.Sprintf("Can't find bundle for base name %s, locale %s", baseName, locale)
+		return getGErrBlk(excNames.MissingResourceException, errMsg)
+	}
+	return bundle
+}
+
+// bundleLocaleName returns the name of the locale a bundle was loaded for
+func bundleLocaleName(bundle *object.Object) string {
+	localeObj, ok := bundle.FieldTable[fieldNameBundleLocale].Fvalue.(*object.Object)
+	if !ok || object.IsNull(localeObj) {
+		return ""
+	}
+	return localeName(localeObj)
+}
+
+// "java/util/ResourceBundle.clearCache()V" and clearCache(ClassLoader)
+func resourceBundleClearCache([]interface{}) interface{} {
+	resourceBundleMutex.Lock()
+	resourceBundleCache = make(map[string]*object.Object)
+	resourceBundleMutex.Unlock()
+	return nil
+}
+
+// "java/util/PropertyResourceBundle.<init>(Ljava/io/InputStream;)V" and <init>(Ljava/io/Reader;)V
+func propertyResourceBundleInit(params []interface{}) interface{} {
+	this := params[0].(*object.Object)
+	streamObj, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(streamObj) {
+		return getGErrBlk(excNames.NullPointerException, "propertyResourceBundleInit: null input")
+	}
+	reader, gerr := getSourceReader("propertyResourceBundleInit", streamObj)
+	if gerr != nil {
+		return gerr
+	}
+	contents, err := io.ReadAll(reader)
+	if err != nil {
+		errMsg := fmt.Sprintf("propertyResourceBundleInit: %s", err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+
+	entries := make(types.DefProperties)
+	if err = propertiesParse(bundleText(contents), entries); err != nil {
+		errMsg := fmt.Sprintf("propertyResourceBundleInit: %s", err.Error())
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	setResourceBundleFields(this, entries, "", "", nil)
+	return nil
+}
+
+// getBundleKey returns the key in params[1], or an error block if it is null
+func getBundleKey(funcName string, params []interface{}) (string, *GErrBlk) {
+	keyObj, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(keyObj) {
+		return "", getGErrBlk(excNames.NullPointerException, funcName+": null key")
+	}
+	return object.GoStringFromStringObject(keyObj), nil
+}
+
+// lookupBundleKey searches the bundle and then its parents for the key
+func lookupBundleKey(bundle *object.Object, key string) (string, bool) {
+	resourceBundleMutex.Lock()
+	defer resourceBundleMutex.Unlock()
+	for bundle != nil && !object.IsNull(bundle) {
+		entries, _ := bundle.FieldTable[fieldNameBundleEntries].Fvalue.(types.DefProperties)
+		if value, ok := entries[key]; ok {
+			return value, true
+		}
+		bundle, _ = bundle.FieldTable[fieldNameBundleParent].Fvalue.(*object.Object)
+	}
+	return "", false
+}
+
+// "java/util/ResourceBundle.getString(Ljava/lang/String;)Ljava/lang/String;" and getObject()
+func resourceBundleGetString(params []interface{}) interface{} {
+	key, gerr := getBundleKey("resourceBundleGetString", params)
+	if gerr != nil {
+		return gerr
+	}
+	value, ok := lookupBundleKey(params[0].(*object.Object), key)
+	if !ok {
+		errMsg := fmt.Sprintf("Can't find resource for bundle java.util.PropertyResourceBundle, key %s", key)
+		return getGErrBlk(excNames.MissingResourceException, errMsg)
+	}
+	return object.StringObjectFromGoString(value)
+}
+
+// "java/util/ResourceBundle.containsKey(Ljava/lang/String;)Z"
+func resourceBundleContainsKey(params []interface{}) interface{} {
+	key, gerr := getBundleKey("resourceBundleContainsKey", params)
+	if gerr != nil {
+		return gerr
+	}
+	_, ok := lookupBundleKey(params[0].(*object.Object), key)
+	return object.JavaBooleanFromGoBoolean(ok)
+}
+
+// "java/util/PropertyResourceBundle.handleGetObject(Ljava/lang/String;)Ljava/lang/Object;" --
+// looks in this bundle only, returning null if the key isn't there
+func propertyResourceBundleHandleGetObject(params []interface{}) interface{} {
+	key, gerr := getBundleKey("propertyResourceBundleHandleGetObject", params)
+	if gerr != nil {
+		return gerr
+	}
+	this := params[0].(*object.Object)
+	resourceBundleMutex.Lock()
+	defer resourceBundleMutex.Unlock()
+	entries, _ := this.FieldTable[fieldNameBundleEntries].Fvalue.(types.DefProperties)
+	if value, ok := entries[key]; ok {
+		return object.StringObjectFromGoString(value)
+	}
+	return object.Null
+}
+
+// "java/util/ResourceBundle.getBaseBundleName()Ljava/lang/String;" -- null for a bundle made by a constructor
+func resourceBundleGetBaseBundleName(params []interface{}) interface{} {
+	this := params[0].(*object.Object)
+	if baseName, ok := this.FieldTable[fieldNameBundleBaseName].Fvalue.(*object.Object); ok {
+		return baseName
+	}
+	return object.Null
+}
+
+// "java/util/ResourceBundle.getLocale()Ljava/util/Locale;" -- the locale the bundle was loaded for
+func resourceBundleGetLocale(params []interface{}) interface{} {
+	this := params[0].(*object.Object)
+	if locale, ok := this.FieldTable[fieldNameBundleLocale].Fvalue.(*object.Object); ok {
+		return locale
+	}
+	return makeLocale("")
+}
@@ -0,0 +1,201 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// puts a directory holding the given resources on the classpath
+func setUpBundleClasspath(t *testing.T, resources map[string]string) {
+	globals.InitGlobals("test")
+	dir := t.TempDir()
+	for name, contents := range resources {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	glob := globals.GetGlobalRef()
+	glob.StartingJar = ""
+	glob.Classpath = []string{dir}
+	resourceBundleClearCache(nil)
+}
+
+func bundleString(t *testing.T, bundle interface{}, key string) string {
+	t.Helper()
+	ret := resourceBundleGetString([]interface{}{bundle, object.StringObjectFromGoString(key)})
+	strObj, ok := ret.(*object.Object)
+	if !ok {
+		t.Fatalf("getString(%q): %v", key, ret)
+	}
+	return object.GoStringFromStringObject(strObj)
+}
+
+func TestResourceBundleLocaleFallback(t *testing.T) {
+	setUpBundleClasspath(t, map[string]string{
+		"msgs/App.properties":       "greeting=Hello\nfarewell=Goodbye\nonlyBase=base\n",
+		"msgs/App_fr.properties":    "greeting=Bonjour\nfarewell=Au revoir\n",
+		"msgs/App_fr_CA.properties": "greeting=Allo\n",
+		"msgs/App_de.properties":    "greeting=Hallo\n",
+	})
+	t.Setenv("LANGUAGE", "de_DE.UTF-8")
+
+	baseName := object.StringObjectFromGoString("msgs.App")
+	bundle := resourceBundleGetBundle([]interface{}{baseName, makeLocale("fr_CA")})
+	if _, ok := bundle.(*object.Object); !ok {
+		t.Fatalf("getBundle(fr_CA): %v", bundle)
+	}
+	if got := bundleString(t, bundle, "greeting"); got != "Allo" {
+		t.Errorf("greeting = %q, want Allo", got)
+	}
+	if got := bundleString(t, bundle, "farewell"); got != "Au revoir" {
+		t.Errorf("farewell = %q, want Au revoir", got)
+	}
+	if got := bundleString(t, bundle, "onlyBase"); got != "base" {
+		t.Errorf("onlyBase = %q, want base", got)
+	}
+	locale := resourceBundleGetLocale([]interface{}{bundle}).(*object.Object)
+	if localeName(locale) != "fr_CA" {
+		t.Errorf("getLocale = %q, want fr_CA", localeName(locale))
+	}
+	if resourceBundleContainsKey([]interface{}{bundle, object.StringObjectFromGoString("onlyBase")}) != types.JavaBoolTrue {
+		t.Errorf("containsKey(onlyBase) is false")
+	}
+
+	// no ja bundle, so the default locale's is used
+	bundle = resourceBundleGetBundle([]interface{}{baseName, makeLocale("ja_JP")})
+	if got := bundleString(t, bundle, "greeting"); got != "Hallo" {
+		t.Errorf("greeting for ja_JP = %q, want Hallo", got)
+	}
+
+	// the default locale with no country falls back to de
+	bundle = resourceBundleGetBundle([]interface{}{baseName})
+	if got := bundleString(t, bundle, "greeting"); got != "Hallo" {
+		t.Errorf("greeting for default locale = %q, want Hallo", got)
+	}
+
+	// nothing for es or the C locale, so the base bundle is used
+	t.Setenv("LANGUAGE", "C")
+	bundle = resourceBundleGetBundle([]interface{}{baseName, makeLocale("es")})
+	if got := bundleString(t, bundle, "greeting"); got != "Hello" {
+		t.Errorf("greeting for es = %q, want Hello", got)
+	}
+	locale = resourceBundleGetLocale([]interface{}{bundle}).(*object.Object)
+	if localeName(locale) != "" {
+		t.Errorf("getLocale of base bundle = %q, want the root locale", localeName(locale))
+	}
+}
+
+func TestResourceBundleMissing(t *testing.T) {
+	setUpBundleClasspath(t, map[string]string{
+		"Messages_en.properties": "key=value\n",
+	})
+	t.Setenv("LANGUAGE", "C")
+
+	ret := resourceBundleGetBundle([]interface{}{object.StringObjectFromGoString("NoSuchBundle")})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.MissingResourceException {
+		t.Errorf("getBundle of missing bundle: expected MissingResourceException, got %v", ret)
+	}
+
+	// there is no base bundle, so only the en locale finds one
+	baseName := object.StringObjectFromGoString("Messages")
+	ret = resourceBundleGetBundle([]interface{}{baseName, makeLocale("fr")})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.MissingResourceException {
+		t.Errorf("getBundle(fr) with no base bundle: expected MissingResourceException, got %v", ret)
+	}
+	bundle := resourceBundleGetBundle([]interface{}{baseName, makeLocale("en_US")})
+	if got := bundleString(t, bundle, "key"); got != "value" {
+		t.Errorf("key = %q, want value", got)
+	}
+
+	ret = resourceBundleGetString([]interface{}{bundle, object.StringObjectFromGoString("nokey")})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.MissingResourceException {
+		t.Errorf("getString of missing key: expected MissingResourceException, got %v", ret)
+	}
+	ret = resourceBundleGetString([]interface{}{bundle, object.Null})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.NullPointerException {
+		t.Errorf("getString(null): expected NullPointerException, got %v", ret)
+	}
+}
+
+func TestPropertyResourceBundleInit(t *testing.T) {
+	globals.InitStringPool()
+
+	// not valid UTF-8, so read as ISO 8859-1
+	bundle := newZipObject(classNamePropertyResourceBundle)
+	stream := newPropertiesByteArrayStream("java/io/ByteArrayInputStream", []byte("caf\xe9=ol\xe9\nescaped=\\u00e9\n"))
+	if ret := propertyResourceBundleInit([]interface{}{bundle, stream}); ret != nil {
+		t.Fatalf("<init>(InputStream): %v", ret)
+	}
+	if got := bundleString(t, bundle, "café"); got != "olé" {
+		t.Errorf("café = %q, want olé", got)
+	}
+	if got := bundleString(t, bundle, "escaped"); got != "é" {
+		t.Errorf("escaped = %q, want é", got)
+	}
+	if ret := resourceBundleGetBaseBundleName([]interface{}{bundle}); ret != object.Null {
+		t.Errorf("getBaseBundleName = %v, want null", ret)
+	}
+
+	bundle = newZipObject(classNamePropertyResourceBundle)
+	if ret := propertyResourceBundleInit([]interface{}{bundle, newPropertiesStringReader("k = \u20ac\n")}); ret != nil {
+		t.Fatalf("<init>(Reader): %v", ret)
+	}
+	ret := propertyResourceBundleHandleGetObject([]interface{}{bundle, object.StringObjectFromGoString("k")})
+	if strObj, ok := ret.(*object.Object); !ok || object.GoStringFromStringObject(strObj) != "\u20ac" {
+		t.Errorf("handleGetObject(k) = %v", ret)
+	}
+	if ret = propertyResourceBundleHandleGetObject([]interface{}{bundle, object.StringObjectFromGoString("x")}); ret != object.Null {
+		t.Errorf("handleGetObject(x) = %v, want null", ret)
+	}
+}
+
+func TestLocaleConstructionAndAccessors(t *testing.T) {
+	globals.InitStringPool()
+
+	locale := newZipObject(classNameLocale)
+	ret := localeInit([]interface{}{locale, object.StringObjectFromGoString("FR"), object.StringObjectFromGoString("ca")})
+	if ret != nil {
+		t.Fatalf("<init>: %v", ret)
+	}
+	if got := object.GoStringFromStringObject(localeToString([]interface{}{locale}).(*object.Object)); got != "fr_CA" {
+		t.Errorf("toString = %q, want fr_CA", got)
+	}
+	if got := object.GoStringFromStringObject(localeGetCountry([]interface{}{locale}).(*object.Object)); got != "CA" {
+		t.Errorf("getCountry = %q, want CA", got)
+	}
+	tagged := localeForLanguageTag([]interface{}{object.StringObjectFromGoString("fr-CA")})
+	if localeEquals([]interface{}{locale, tagged}) != types.JavaBoolTrue {
+		t.Errorf("forLanguageTag(fr-CA) = %q, want fr_CA", localeName(tagged.(*object.Object)))
+	}
+	if got := object.GoStringFromStringObject(localeToLanguageTag([]interface{}{locale}).(*object.Object)); got != "fr-CA" {
+		t.Errorf("toLanguageTag = %q, want fr-CA", got)
+	}
+
+	variant := localeOf([]interface{}{object.StringObjectFromGoString("en"), object.StringObjectFromGoString(""),
+		object.StringObjectFromGoString("POSIX")}).(*object.Object)
+	if localeName(variant) != "en__POSIX" {
+		t.Errorf("Locale.of(en, \"\", POSIX) = %q, want en__POSIX", localeName(variant))
+	}
+
+	t.Setenv("LANGUAGE", "")
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LANG", "pt_BR.UTF-8")
+	if got := localeName(getDefaultLocale(nil).(*object.Object)); got != "pt_BR" {
+		t.Errorf("default locale = %q, want pt_BR", got)
+	}
+}
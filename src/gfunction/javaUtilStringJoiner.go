@@ -0,0 +1,239 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"strings"
+	"unicode/utf16"
+)
+
+// StringJoiner collects the strings added to it and joins them with its delimiter between
+// its prefix and suffix. String.join() is implemented on top of it; see javaLangString.go.
+
+// The field of a StringJoiner that holds its Go state
+var fieldNameStringJoiner = "joiner"
+
+func Load_Util_StringJoiner() {
+
+	MethodSignatures["java/util/StringJoiner.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/StringJoiner.<init>(Ljava/lang/CharSequence;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    stringjoinerInit,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/StringJoiner.<init>(Ljava/lang/CharSequence;Ljava/lang/CharSequence;Ljava/lang/CharSequence;)V"] =
+		GMeth{
+			ParamSlots:   3,
+			GFunction:    stringjoinerInitWithAffixes,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/StringJoiner.add(Ljava/lang/CharSequence;)Ljava/util/StringJoiner;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    stringjoinerAdd,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/StringJoiner.length()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  stringjoinerLength,
+		}
+
+	MethodSignatures["java/util/StringJoiner.merge(Ljava/util/StringJoiner;)Ljava/util/StringJoiner;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringjoinerMerge,
+		}
+
+	MethodSignatures["java/util/StringJoiner.setEmptyValue(Ljava/lang/CharSequence;)Ljava/util/StringJoiner;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    stringjoinerSetEmptyValue,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/StringJoiner.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  stringjoinerToString,
+		}
+}
+
+// stringJoiner is the state of a StringJoiner. emptyValue is nil until setEmptyValue() is
+// called; until then, a joiner with no elements yields its prefix and suffix.
+type stringJoiner struct {
+	prefix     string
+	delimiter  string
+	suffix     string
+	elems      []string
+	emptyValue *string
+}
+
+// the elements joined by the delimiter, without the prefix and suffix
+func (sj *stringJoiner) joined() string {
+	return strings.Join(sj.elems, sj.delimiter)
+}
+
+func (sj *stringJoiner) String() string {
+	if len(sj.elems) == 0 && sj.emptyValue != nil {
+		return *sj.emptyValue
+	}
+	return sj.prefix + sj.joined() + sj.suffix
+}
+
+// charSequenceString returns the Go string of a CharSequence, or "null" for a null one.
+// Strings, StringBuilders, and StringBuffers are read directly; other CharSequences are
+// converted by their toString() methods in Java.
+func charSequenceString(fs *list.List, value any) (string, *GErrBlk) {
+	obj, ok := value.(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return "null", nil
+	}
+	switch v := obj.FieldTable["value"].Fvalue.(type) {
+	case []types.JavaByte:
+		return object.GoStringFromJavaByteArray(v), nil
+	case []byte:
+		return string(v), nil
+	case string:
+		return v, nil
+	}
+	return javaObjectString(fs, obj)
+}
+
+// returns the state of the StringJoiner in params[0]
+func getStringJoiner(funcName string, params []interface{}) (*stringJoiner, *GErrBlk) {
+	obj, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return nil, getGErrBlk(excNames.NullPointerException, funcName+": the StringJoiner is null")
+	}
+	sj, ok := obj.FieldTable[fieldNameStringJoiner].Fvalue.(*stringJoiner)
+	if !ok {
+		return nil, getGErrBlk(excNames.IllegalStateException, funcName+": the StringJoiner is not initialized")
+	}
+	return sj, nil
+}
+
+// returns the strings of the CharSequence params, throwing a NullPointerException if any
+// is null
+func charSequenceParams(fs *list.List, funcName string, params []interface{}) ([]string, *GErrBlk) {
+	strs := make([]string, len(params))
+	for i, param := range params {
+		if obj, ok := param.(*object.Object); !ok || object.IsNull(obj) {
+			return nil, getGErrBlk(excNames.NullPointerException, funcName+": a CharSequence argument is null")
+		}
+		str, gErr := charSequenceString(fs, param)
+		if gErr != nil {
+			return nil, gErr
+		}
+		strs[i] = str
+	}
+	return strs, nil
+}
+
+// "java/util/StringJoiner.<init>(Ljava/lang/CharSequence;)V" -- no prefix or suffix
+func stringjoinerInit(params []interface{}) interface{} {
+	empty := object.StringObjectFromGoString("")
+	return stringjoinerInitWithAffixes([]interface{}{params[0], params[1], params[2], empty, empty})
+}
+
+// "java/util/StringJoiner.<init>(Ljava/lang/CharSequence;Ljava/lang/CharSequence;Ljava/lang/CharSequence;)V"
+// -- the delimiter, prefix, and suffix
+func stringjoinerInitWithAffixes(params []interface{}) interface{} {
+	fs, _ := params[0].(*list.List)
+	obj, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return getGErrBlk(excNames.NullPointerException, "stringjoinerInit: the object is null")
+	}
+	strs, gErr := charSequenceParams(fs, "stringjoinerInit", params[2:5])
+	if gErr != nil {
+		return gErr
+	}
+	sj := &stringJoiner{delimiter: strs[0], prefix: strs[1], suffix: strs[2]}
+	obj.FieldTable[fieldNameStringJoiner] = object.Field{Ftype: types.JoinerState, Fvalue: sj}
+	return nil
+}
+
+// "java/util/StringJoiner.add(Ljava/lang/CharSequence;)Ljava/util/StringJoiner;" -- a null
+// element is added as "null"
+func stringjoinerAdd(params []interface{}) interface{} {
+	fs, _ := params[0].(*list.List)
+	sj, gErr := getStringJoiner("stringjoinerAdd", params[1:])
+	if gErr != nil {
+		return gErr
+	}
+	str, gErr := charSequenceString(fs, params[2])
+	if gErr != nil {
+		return gErr
+	}
+	sj.elems = append(sj.elems, str)
+	return params[1]
+}
+
+// "java/util/StringJoiner.length()I" -- the length in chars of what toString() returns
+func stringjoinerLength(params []interface{}) interface{} {
+	sj, gErr := getStringJoiner("stringjoinerLength", params)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(len(utf16.Encode([]rune(sj.String()))))
+}
+
+// "java/util/StringJoiner.merge(Ljava/util/StringJoiner;)Ljava/util/StringJoiner;" -- the
+// elements of the other joiner, joined by its delimiter but without its prefix and suffix,
+// are added as one element. Nothing is added if the other joiner has no elements.
+func stringjoinerMerge(params []interface{}) interface{} {
+	sj, gErr := getStringJoiner("stringjoinerMerge", params)
+	if gErr != nil {
+		return gErr
+	}
+	other, gErr := getStringJoiner("stringjoinerMerge", params[1:])
+	if gErr != nil {
+		return gErr
+	}
+	if len(other.elems) > 0 {
+		sj.elems = append(sj.elems, other.joined())
+	}
+	return params[0]
+}
+
+// "java/util/StringJoiner.setEmptyValue(Ljava/lang/CharSequence;)Ljava/util/StringJoiner;"
+// -- what toString() returns before any element is added
+func stringjoinerSetEmptyValue(params []interface{}) interface{} {
+	fs, _ := params[0].(*list.List)
+	sj, gErr := getStringJoiner("stringjoinerSetEmptyValue", params[1:])
+	if gErr != nil {
+		return gErr
+	}
+	strs, gErr := charSequenceParams(fs, "stringjoinerSetEmptyValue", params[2:3])
+	if gErr != nil {
+		return gErr
+	}
+	sj.emptyValue = &strs[0]
+	return params[1]
+}
+
+// "java/util/StringJoiner.toString()Ljava/lang/String;"
+func stringjoinerToString(params []interface{}) interface{} {
+	sj, gErr := getStringJoiner("stringjoinerToString", params)
+	if gErr != nil {
+		return gErr
+	}
+	return object.StringObjectFromGoString(sj.String())
+}
@@ -0,0 +1,94 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"testing"
+)
+
+func newTestStringJoiner(delimiter, prefix, suffix string) *object.Object {
+	className := "java/util/StringJoiner"
+	sj := object.MakeEmptyObjectWithClassName(&className)
+	stringjoinerInitWithAffixes([]interface{}{list.New(), sj, object.StringObjectFromGoString(delimiter),
+		object.StringObjectFromGoString(prefix), object.StringObjectFromGoString(suffix)})
+	return sj
+}
+
+func TestStringJoinerAddAndEmptyValue(t *testing.T) {
+	globals.InitGlobals("test")
+	sj := newTestStringJoiner(", ", "[", "]")
+
+	checkTreeString(t, "toString() with no elements", stringjoinerToString([]interface{}{sj}), "[]")
+	if ret := treeCall(stringjoinerSetEmptyValue, sj, object.StringObjectFromGoString("EMPTY")); ret != sj {
+		t.Errorf("setEmptyValue(): expected the joiner, got %v", ret)
+	}
+	checkTreeString(t, "toString() with the empty value", stringjoinerToString([]interface{}{sj}), "EMPTY")
+	checkTreeError(t, "setEmptyValue(null)", treeCall(stringjoinerSetEmptyValue, sj, object.Null),
+		excNames.NullPointerException)
+
+	treeCall(stringjoinerAdd, sj, object.StringObjectFromGoString("a"))
+	treeCall(stringjoinerAdd, sj, object.Null)
+	if ret := treeCall(stringjoinerAdd, sj, object.StringObjectFromGoString("é")); ret != sj {
+		t.Errorf("add(): expected the joiner, got %v", ret)
+	}
+	checkTreeString(t, "toString()", stringjoinerToString([]interface{}{sj}), "[a, null, é]")
+	if ret := stringjoinerLength([]interface{}{sj}); ret != int64(12) {
+		t.Errorf("length(): expected 12, got %v", ret)
+	}
+
+	// the no-affix constructor
+	className := "java/util/StringJoiner"
+	plain := object.MakeEmptyObjectWithClassName(&className)
+	treeCall(stringjoinerInit, plain, object.StringObjectFromGoString("-"))
+	treeCall(stringjoinerAdd, plain, object.StringObjectFromGoString(""))
+	checkTreeString(t, "toString() of an empty element", stringjoinerToString([]interface{}{plain}), "")
+	checkTreeError(t, "<init>(null)", treeCall(stringjoinerInit, plain, object.Null), excNames.NullPointerException)
+}
+
+func TestStringJoinerMerge(t *testing.T) {
+	globals.InitGlobals("test")
+	sj := newTestStringJoiner(",", "{", "}")
+	other := newTestStringJoiner("-", "(", ")")
+
+	stringjoinerMerge([]interface{}{sj, other})
+	checkTreeString(t, "merge() of an empty joiner", stringjoinerToString([]interface{}{sj}), "{}")
+
+	treeCall(stringjoinerAdd, sj, object.StringObjectFromGoString("x"))
+	treeCall(stringjoinerAdd, other, object.StringObjectFromGoString("y"))
+	treeCall(stringjoinerAdd, other, object.StringObjectFromGoString("z"))
+	if ret := stringjoinerMerge([]interface{}{sj, other}); ret != sj {
+		t.Errorf("merge(): expected the joiner, got %v", ret)
+	}
+	checkTreeString(t, "merge()", stringjoinerToString([]interface{}{sj}), "{x,y-z}")
+	checkTreeError(t, "merge(null)", stringjoinerMerge([]interface{}{sj, object.Null}), excNames.NullPointerException)
+}
+
+func TestStringJoin(t *testing.T) {
+	globals.InitGlobals("test")
+	arr := object.Make1DimRefArray("java/lang/CharSequence;", 3)
+	elems := arr.FieldTable["value"].Fvalue.([]*object.Object)
+	elems[0] = object.StringObjectFromGoString("id")
+	elems[1] = object.Null
+	elems[2] = object.StringObjectFromGoString("name")
+
+	delim := object.StringObjectFromGoString(",")
+	checkTreeString(t, "join(CharSequence[])", stringJoin([]interface{}{list.New(), delim, arr}), "id,null,name")
+	checkTreeError(t, "join(null, ...)", stringJoin([]interface{}{list.New(), object.Null, arr}),
+		excNames.NullPointerException)
+
+	deque := newArrayDequeObject(classNameArrayDeque)
+	for _, str := range []string{"1", "2", "3"} {
+		arraydequeOfferLast([]interface{}{deque, object.StringObjectFromGoString(str)})
+	}
+	checkTreeString(t, "join(Iterable)", stringJoinIterable([]interface{}{list.New(), delim, deque}), "1,2,3")
+	checkTreeError(t, "join(..., null)", stringJoinIterable([]interface{}{list.New(), delim, object.Null}),
+		excNames.NullPointerException)
+}
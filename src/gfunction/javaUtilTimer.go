@@ -0,0 +1,545 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"cmp"
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/thread"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Implementation of java/util/Timer and java/util/TimerTask, and the scheduler that they
+// share with the executors in java/util/concurrent.
+// Strategy: each scheduled task has a goroutine that waits on a Go timer until the task is
+// due, then runs the task on a new VM thread and waits for that thread to end before working
+// out when the task is next due. A scheduler has a fixed number of slots (one for a Timer,
+// the pool size for an executor), and a due task waits for a free slot, so a Timer runs its
+// tasks one at a time, as the JDK's single timer thread does. Note that, as with every thread
+// in Jacobin, the tasks stop when the main thread ends.
+
+const fieldNameSchedulerState = "schedulerState"
+
+// for the names of the threads that run the tasks: Timer-0, Timer-1, and so on
+var timerNumber atomic.Int64
+
+// scheduler is the Go state of a Timer or a ScheduledThreadPoolExecutor
+type scheduler struct {
+	mutex          sync.Mutex
+	threadName     string
+	daemon         bool
+	numbered       bool                    // whether the threads are named threadName-1, threadName-2, ...
+	busy           map[int]bool            // the numbers of the threads that are running
+	slots          chan struct{}           // holds a token for each task that is running
+	stopped        chan struct{}           // closed when no more tasks are to run
+	stoppedPeriods chan struct{}           // closed when no more periodic tasks are to run
+	terminated     chan struct{}           // closed when stopped and no task is left
+	shutdown       bool                    // no more tasks can be scheduled
+	pending        map[*scheduledTask]bool // the tasks scheduled and not yet finished
+	scheduled      int64                   // the number of tasks scheduled so far
+}
+
+// scheduledTask is the Go state of a task on a scheduler
+type scheduledTask struct {
+	call      taskFunc
+	item      any           // the Runnable or future that stands for the task in the executor's queue
+	number    int64         // the order in which the task was scheduled
+	period    time.Duration // zero for a task that runs once
+	fixedRate bool          // whether runs are a period apart from start to start (or else from end to start)
+	cancel    chan struct{} // closed when the task is cancelled
+	done      chan struct{} // closed when the task will run no more, whether cancelled or not
+	cancelled bool          // the fields from here on are guarded by the scheduler's mutex
+	finished  bool
+	queued    bool      // the task is waiting for its next run, rather than running
+	nextRun   time.Time // when the task is next due
+	lastDue   time.Time // when the task's latest run was due
+	result    any       // what the latest run returned
+	failure   *GErrBlk  // the exception that ended the task, if one did
+}
+
+// taskFunc runs a task once, on a new thread with the given name, and returns its result
+type taskFunc func(threadName string, daemon bool) (any, *GErrBlk)
+
+func newScheduler(threadName string, daemon bool, poolSize int, numbered bool) *scheduler {
+	return &scheduler{
+		threadName:     threadName,
+		daemon:         daemon,
+		numbered:       numbered,
+		busy:           make(map[int]bool),
+		slots:          make(chan struct{}, poolSize),
+		stopped:        make(chan struct{}),
+		stoppedPeriods: make(chan struct{}),
+		terminated:     make(chan struct{}),
+		pending:        make(map[*scheduledTask]bool),
+	}
+}
+
+// schedule starts a task that first runs after the delay, and then every period, if the
+// period isn't zero. item is the object that stop() returns for the task if the task is
+// waiting to run. It returns nil if the scheduler has been shut down.
+func (s *scheduler) schedule(call taskFunc, item any, delay, period time.Duration,
+	fixedRate bool) *scheduledTask {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.shutdown {
+		return nil
+	}
+	s.scheduled++
+	task := &scheduledTask{
+		call:      call,
+		item:      item,
+		number:    s.scheduled,
+		period:    period,
+		fixedRate: fixedRate,
+		cancel:    make(chan struct{}),
+		done:      make(chan struct{}),
+		nextRun:   time.Now().Add(max(delay, 0)),
+		queued:    true,
+	}
+	s.pending[task] = true
+	go s.run(task)
+	return task
+}
+
+// run is the goroutine that runs a task whenever it's due, until it's cancelled, the
+// scheduler stops, or (for a task that runs once) it has run
+func (s *scheduler) run(task *scheduledTask) {
+	defer s.finish(task)
+
+	var stopPeriodic <-chan struct{}
+	if task.period > 0 {
+		stopPeriodic = s.stoppedPeriods
+	}
+	for {
+		s.mutex.Lock()
+		next := task.nextRun
+		s.mutex.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+		case <-task.cancel:
+			timer.Stop()
+			return
+		case <-s.stopped:
+			timer.Stop()
+			return
+		case <-stopPeriodic:
+			timer.Stop()
+			return
+		}
+
+		select {
+		case s.slots <- struct{}{}:
+		case <-task.cancel:
+			return
+		case <-s.stopped:
+			return
+		}
+		s.mutex.Lock()
+		select {
+		case <-s.stopped: // the task was taken off the queue by stop()
+			s.mutex.Unlock()
+			<-s.slots
+			return
+		default:
+		}
+		task.queued = false
+		task.lastDue = next
+		name, number := s.takeThreadName()
+		s.mutex.Unlock()
+		result, gerr := task.call(name, s.daemon)
+		s.mutex.Lock()
+		delete(s.busy, number)
+		task.result = result
+		task.failure = gerr
+		s.mutex.Unlock()
+		<-s.slots
+
+		if task.period == 0 || gerr != nil { // an exception ends a periodic task, as in the JDK
+			return
+		}
+		s.mutex.Lock()
+		if task.fixedRate {
+			task.nextRun = task.nextRun.Add(task.period)
+		} else {
+			task.nextRun = time.Now().Add(task.period)
+		}
+		task.queued = true
+		s.mutex.Unlock()
+	}
+}
+
+// takeThreadName returns the name for the thread of a task that is starting, which for a
+// numbered scheduler is the lowest number not in use. Call with the mutex held.
+func (s *scheduler) takeThreadName() (string, int) {
+	if !s.numbered {
+		return s.threadName, 0
+	}
+	number := 1
+	for s.busy[number] {
+		number++
+	}
+	s.busy[number] = true
+	return fmt.Sprintf("%s-%d", s.threadName, number), number
+}
+
+// finish records that a task will run no more
+func (s *scheduler) finish(task *scheduledTask) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	task.finished = true
+	close(task.done)
+	delete(s.pending, task)
+	s.checkTerminated()
+}
+
+// checkTerminated closes the terminated channel if the scheduler is shut down and has no
+// tasks left. Call with the mutex held.
+func (s *scheduler) checkTerminated() {
+	if s.shutdown && len(s.pending) == 0 {
+		select {
+		case <-s.terminated:
+		default:
+			close(s.terminated)
+		}
+	}
+}
+
+// stop shuts the scheduler down. If all is false, the tasks that run once are still run
+// when they're due, as ScheduledThreadPoolExecutor.shutdown() does; otherwise, no more
+// tasks are started, and the items of the tasks that were waiting to run are returned in
+// the order the tasks were scheduled. Tasks that are running are left to finish.
+func (s *scheduler) stop(all bool) []any {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.shutdown {
+		s.shutdown = true
+		close(s.stoppedPeriods)
+	}
+	var drained []*scheduledTask
+	if all {
+		select {
+		case <-s.stopped:
+		default:
+			close(s.stopped)
+			for task := range s.pending {
+				if task.queued && !task.cancelled && !task.finished {
+					drained = append(drained, task)
+				}
+			}
+		}
+	}
+	s.checkTerminated()
+
+	slices.SortFunc(drained, func(a, b *scheduledTask) int { return cmp.Compare(a.number, b.number) })
+	items := make([]any, 0, len(drained))
+	for _, task := range drained {
+		items = append(items, task.item)
+	}
+	return items
+}
+
+// cancelTask cancels a task, returning true if this stopped it from running (again)
+func (s *scheduler) cancelTask(task *scheduledTask) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if task.cancelled || task.finished {
+		return false
+	}
+	task.cancelled = true
+	close(task.cancel)
+	return true
+}
+
+// callOnJavaThread calls a method that takes no arguments, such as Runnable.run() or
+// Callable.call(), on a new VM thread with the given name, and returns what the method
+// returns or the exception that it throws. The thread runs on the calling goroutine.
+func callOnJavaThread(name string, daemon bool, receiver *object.Object,
+	className, methName, methType string) (any, *GErrBlk) {
+
+	glob := globals.GetGlobalRef()
+	t := threadCreateNoarg(nil).(*object.Object)
+	t.FieldTable["name"] = object.Field{Ftype: types.GolangString, Fvalue: name}
+	t.FieldTable["daemon"] = object.Field{Ftype: types.Int, Fvalue: object.JavaBooleanFromGoBoolean(daemon)}
+	t.FieldTable["started"] = object.Field{Ftype: types.Bool, Fvalue: types.JavaBoolTrue}
+
+	th := thread.CreateThread()
+	execThread := &th
+	execThread.Stack = frames.CreateFrameStack()
+	execThread.JavaThread = t
+	t.FieldTable["ID"] = object.Field{Ftype: types.Int, Fvalue: int64(execThread.ID)}
+
+	// the upcall takes the thread's ID and pool of frames from the frame at the top of the stack
+	base := frames.CreateFrame(1)
+	base.Thread = execThread.ID
+	base.FrameStack = execThread.Stack
+	base.Pool = frames.NewFramePool()
+	if err := frames.PushFrame(execThread.Stack, base); err != nil {
+		return nil, getGErrBlk(excNames.InternalException, "callOnJavaThread: "+err.Error())
+	}
+
+	execThread.AddThreadToTable(glob)
+	defer func() {
+		execThread.Terminate() // release any threads that are joining this one
+		execThread.RemoveThreadFromTable(glob)
+	}()
+	return invokeJavaMethod(execThread.Stack, className, methName, methType, receiver, nil)
+}
+
+// runnableTask returns a task that runs a Runnable. If reportUncaught is set, exceptions that
+// the Runnable throws are reported as uncaught, for when no Future will hand them on.
+func runnableTask(runnable *object.Object, reportUncaught bool) taskFunc {
+	return func(threadName string, daemon bool) (any, *GErrBlk) {
+		_, gerr := callOnJavaThread(threadName, daemon, runnable, "java/lang/Runnable", "run", "()V")
+		if gerr != nil && reportUncaught {
+			trace.Error(fmt.Sprintf("Exception in thread \"%s\" %s", threadName, describeGErrBlk(gerr)))
+		}
+		return nil, gerr
+	}
+}
+
+// describeGErrBlk gives an exception in the form that Throwable.toString() does
+func describeGErrBlk(gerr *GErrBlk) string {
+	name := "java.lang.RuntimeException"
+	if gerr.ExceptionType >= 0 && gerr.ExceptionType < len(excNames.JVMexceptionNames) {
+		name = excNames.JVMexceptionNames[gerr.ExceptionType]
+	}
+	if gerr.ErrMsg == "" {
+		return name
+	}
+	return name + ": " + gerr.ErrMsg
+}
+
+func Load_Util_Timer() {
+
+	MethodSignatures["java/util/Timer.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/Timer.<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  timerInit,
+		}
+
+	MethodSignatures["java/util/Timer.<init>(Z)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  timerInitDaemon,
+		}
+
+	MethodSignatures["java/util/Timer.<init>(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  timerInit,
+		}
+
+	MethodSignatures["java/util/Timer.<init>(Ljava/lang/String;Z)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  timerInit,
+		}
+
+	MethodSignatures["java/util/Timer.cancel()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  timerCancel,
+		}
+
+	MethodSignatures["java/util/Timer.purge()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  timerPurge,
+		}
+
+	MethodSignatures["java/util/Timer.schedule(Ljava/util/TimerTask;J)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  timerSchedule,
+		}
+
+	MethodSignatures["java/util/Timer.schedule(Ljava/util/TimerTask;JJ)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  timerSchedule,
+		}
+
+	MethodSignatures["java/util/Timer.scheduleAtFixedRate(Ljava/util/TimerTask;JJ)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  timerScheduleAtFixedRate,
+		}
+
+	MethodSignatures["java/util/TimerTask.cancel()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  timerTaskCancel,
+		}
+
+	MethodSignatures["java/util/TimerTask.scheduledExecutionTime()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  timerTaskScheduledExecutionTime,
+		}
+}
+
+// "java/util/Timer.<init>()V" and the forms with a thread name and whether the thread is a daemon
+func timerInit(params []interface{}) interface{} {
+	this := params[0].(*object.Object)
+	name := fmt.Sprintf("Timer-%d", timerNumber.Add(1)-1)
+	daemon := false
+	if len(params) > 1 {
+		nameObj, ok := params[1].(*object.Object)
+		if !ok || object.IsNull(nameObj) {
+			return getGErrBlk(excNames.NullPointerException, "timerInit: null thread name")
+		}
+		name = object.GoStringFromStringObject(nameObj)
+	}
+	if len(params) > 2 {
+		daemon = params[2].(int64) == types.JavaBoolTrue
+	}
+	this.FieldTable[fieldNameSchedulerState] = object.Field{Ftype: types.SchedulerState, Fvalue: newScheduler(name, daemon, 1, false)}
+	return nil
+}
+
+// "java/util/Timer.<init>(Z)V"
+func timerInitDaemon(params []interface{}) interface{} {
+	name := object.StringObjectFromGoString(fmt.Sprintf("Timer-%d", timerNumber.Add(1)-1))
+	return timerInit([]interface{}{params[0], name, params[1]})
+}
+
+// getScheduler returns the scheduler of a Timer or an executor
+func getScheduler(funcName string, obj interface{}) (*scheduler, *GErrBlk) {
+	this, ok := obj.(*object.Object)
+	if ok && !object.IsNull(this) {
+		if s, ok := this.FieldTable[fieldNameSchedulerState].Fvalue.(*scheduler); ok {
+			return s, nil
+		}
+	}
+	errMsg := fmt.Sprintf("%s: the object has no scheduler", funcName)
+	return nil, getGErrBlk(excNames.IllegalStateException, errMsg)
+}
+
+// "java/util/Timer.schedule(Ljava/util/TimerTask;J)V" and schedule(TimerTask, long, long),
+// whose runs are the period apart from the end of one to the start of the next
+func timerSchedule(params []interface{}) interface{} {
+	return timerScheduleTask("timerSchedule", params, false)
+}
+
+// "java/util/Timer.scheduleAtFixedRate(Ljava/util/TimerTask;JJ)V" -- the runs are the period
+// apart from start to start
+func timerScheduleAtFixedRate(params []interface{}) interface{} {
+	return timerScheduleTask("timerScheduleAtFixedRate", params, true)
+}
+
+func timerScheduleTask(funcName string, params []interface{}, fixedRate bool) interface{} {
+	s, gerr := getScheduler(funcName, params[0])
+	if gerr != nil {
+		return gerr
+	}
+	timerTask, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(timerTask) {
+		return getGErrBlk(excNames.NullPointerException, funcName+": null task")
+	}
+	delay := params[2].(int64)
+	if delay < 0 {
+		return getGErrBlk(excNames.IllegalArgumentException, "Negative delay.")
+	}
+	var period int64
+	if len(params) > 3 {
+		period = params[3].(int64)
+		if period <= 0 {
+			return getGErrBlk(excNames.IllegalArgumentException, "Non-positive period.")
+		}
+	}
+
+	if _, scheduled := timerTask.FieldTable[fieldNameSchedulerState]; scheduled {
+		return getGErrBlk(excNames.IllegalStateException, "Task already scheduled or cancelled")
+	}
+	// an exception in a task ends the Timer's thread, and so all its tasks
+	run := runnableTask(timerTask, true)
+	call := func(threadName string, daemon bool) (any, *GErrBlk) {
+		ret, gerr := run(threadName, daemon)
+		if gerr != nil {
+			s.stop(true)
+		}
+		return ret, gerr
+	}
+	task := s.schedule(call, timerTask, time.Duration(delay)*time.Millisecond, time.Duration(period)*time.Millisecond, fixedRate)
+	if task == nil {
+		return getGErrBlk(excNames.IllegalStateException, "Timer already cancelled.")
+	}
+	timerTask.FieldTable[fieldNameSchedulerState] = object.Field{Ftype: types.SchedulerState,
+		Fvalue: &taskState{scheduler: s, task: task}}
+	return nil
+}
+
+// taskState links a TimerTask or a ScheduledFuture to its task on a scheduler
+type taskState struct {
+	scheduler *scheduler
+	task      *scheduledTask
+}
+
+// "java/util/Timer.cancel()V" -- the task that is running, if any, is left to finish
+func timerCancel(params []interface{}) interface{} {
+	s, gerr := getScheduler("timerCancel", params[0])
+	if gerr != nil {
+		return gerr
+	}
+	s.stop(true)
+	return nil
+}
+
+// "java/util/Timer.purge()I" -- the cancelled tasks are removed as they're cancelled, so there are none to purge
+func timerPurge(params []interface{}) interface{} {
+	if _, gerr := getScheduler("timerPurge", params[0]); gerr != nil {
+		return gerr
+	}
+	return int64(0)
+}
+
+// "java/util/TimerTask.cancel()Z" -- returns true if this stopped the task from running (again)
+func timerTaskCancel(params []interface{}) interface{} {
+	this := params[0].(*object.Object)
+	state, ok := this.FieldTable[fieldNameSchedulerState].Fvalue.(*taskState)
+	if !ok { // never scheduled; it can't be scheduled now
+		this.FieldTable[fieldNameSchedulerState] = object.Field{Ftype: types.SchedulerState, Fvalue: &taskState{}}
+		return types.JavaBoolFalse
+	}
+	if state.task == nil {
+		return types.JavaBoolFalse
+	}
+	return object.JavaBooleanFromGoBoolean(state.scheduler.cancelTask(state.task))
+}
+
+// "java/util/TimerTask.scheduledExecutionTime()J" -- when the latest run of the task was due,
+// in milliseconds since the epoch, or 0 if it hasn't run
+func timerTaskScheduledExecutionTime(params []interface{}) interface{} {
+	this := params[0].(*object.Object)
+	state, ok := this.FieldTable[fieldNameSchedulerState].Fvalue.(*taskState)
+	if !ok || state.task == nil {
+		return int64(0)
+	}
+	state.scheduler.mutex.Lock()
+	defer state.scheduler.mutex.Unlock()
+	if state.task.lastDue.IsZero() {
+		return int64(0)
+	}
+	return state.task.lastDue.UnixMilli()
+}
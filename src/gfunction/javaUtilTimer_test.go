@@ -0,0 +1,196 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"bytes"
+	"container/list"
+	"jacobin/src/excNames"
+	"jacobin/src/exceptions"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+	"strings"
+	"testing"
+	"time"
+)
+
+// replaces the upcalls that run tasks with a function that reports each task that would run.
+// A task with a "throws" field throws that exception, and a Callable returns "called".
+func fakeTaskRuns(t *testing.T) chan *object.Object {
+	globals.InitGlobals("test")
+	trace.Init()
+	glob := globals.GetGlobalRef()
+	saved := glob.FuncInvokeJavaMethod
+	runs := make(chan *object.Object, 100)
+	glob.FuncInvokeJavaMethod = func(_ *list.List, className, methName, _ string, receiver any, _ []any) (any, error) {
+		task := receiver.(*object.Object)
+		runs <- task
+		if cause, ok := task.FieldTable["throws"].Fvalue.(string); ok {
+			thrown, _ := task.FieldTable["thrown"].Fvalue.(*object.Object)
+			return nil, &exceptions.UpcallError{Method: className + "." + methName, Cause: cause, Throwable: thrown}
+		}
+		if methName == "call" {
+			return object.StringObjectFromGoString("called"), nil
+		}
+		return nil, nil
+	}
+	t.Cleanup(func() { glob.FuncInvokeJavaMethod = saved })
+	return runs
+}
+
+// makes a task whose run() or call() throws the given exception, whose Throwable is
+// in the task's "thrown" field
+func newThrowingTask(className, cause string) *object.Object {
+	task := newZipObject(className)
+	task.FieldTable["throws"] = object.Field{Ftype: types.GolangString, Fvalue: cause}
+	excName, _, _ := strings.Cut(cause, ":")
+	thrown := newZipObject(strings.ReplaceAll(excName, ".", "/"))
+	task.FieldTable["thrown"] = object.Field{Ftype: types.Ref, Fvalue: thrown}
+	return task
+}
+
+// waits for a run of the task, failing the test if none comes
+func expectRun(t *testing.T, runs chan *object.Object, task *object.Object) {
+	t.Helper()
+	select {
+	case ran := <-runs:
+		if ran != task {
+			t.Errorf("a different task ran")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("the task did not run")
+	}
+}
+
+// fails the test if a task runs in the next while
+func expectNoRun(t *testing.T, runs chan *object.Object) {
+	t.Helper()
+	select {
+	case <-runs:
+		t.Errorf("a task ran when none should have")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func newTimer(t *testing.T) *object.Object {
+	timer := newZipObject("java/util/Timer")
+	if ret := timerInit([]interface{}{timer}); ret != nil {
+		t.Fatalf("timerInit failed: %v", ret)
+	}
+	return timer
+}
+
+func TestTimerScheduleOnce(t *testing.T) {
+	runs := fakeTaskRuns(t)
+	timer := newTimer(t)
+	task := newZipObject("java/util/TimerTask")
+
+	start := time.Now()
+	if ret := timerSchedule([]interface{}{timer, task, int64(20)}); ret != nil {
+		t.Fatalf("schedule failed: %v", ret)
+	}
+	expectRun(t, runs, task)
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("the task ran after %v, before its delay", elapsed)
+	}
+	expectNoRun(t, runs)
+
+	if when := timerTaskScheduledExecutionTime([]interface{}{task}).(int64); when < start.UnixMilli() {
+		t.Errorf("scheduledExecutionTime = %d, before the task was scheduled", when)
+	}
+	if timerTaskCancel([]interface{}{task}) != types.JavaBoolFalse {
+		t.Errorf("cancel of a task that has run returned true")
+	}
+
+	// a task can be scheduled only once
+	ret := timerSchedule([]interface{}{timer, task, int64(0)})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalStateException {
+		t.Errorf("rescheduling a task: expected IllegalStateException, got %v", ret)
+	}
+}
+
+func TestTimerPeriodicAndCancel(t *testing.T) {
+	runs := fakeTaskRuns(t)
+	timer := newTimer(t)
+	task := newZipObject("java/util/TimerTask")
+
+	if ret := timerScheduleAtFixedRate([]interface{}{timer, task, int64(0), int64(10)}); ret != nil {
+		t.Fatalf("scheduleAtFixedRate failed: %v", ret)
+	}
+	for range 3 {
+		expectRun(t, runs, task)
+	}
+	if timerTaskCancel([]interface{}{task}) != types.JavaBoolTrue {
+		t.Errorf("cancel of a periodic task returned false")
+	}
+	time.Sleep(20 * time.Millisecond) // a run that was under way may still be reported
+	for len(runs) > 0 {
+		<-runs
+	}
+	expectNoRun(t, runs)
+
+	// after Timer.cancel(), delayed tasks don't run and no more can be scheduled
+	other := newZipObject("java/util/TimerTask")
+	timerSchedule([]interface{}{timer, other, int64(50), int64(50)})
+	timerCancel([]interface{}{timer})
+	expectNoRun(t, runs)
+	ret := timerSchedule([]interface{}{timer, newZipObject("java/util/TimerTask"), int64(0)})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalStateException {
+		t.Errorf("schedule on a cancelled Timer: expected IllegalStateException, got %v", ret)
+	}
+}
+
+func TestTimerScheduleErrors(t *testing.T) {
+	fakeTaskRuns(t)
+	timer := newTimer(t)
+
+	ret := timerSchedule([]interface{}{timer, newZipObject("java/util/TimerTask"), int64(-1)})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("negative delay: expected IllegalArgumentException, got %v", ret)
+	}
+	ret = timerSchedule([]interface{}{timer, newZipObject("java/util/TimerTask"), int64(0), int64(0)})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("zero period: expected IllegalArgumentException, got %v", ret)
+	}
+	ret = timerSchedule([]interface{}{timer, object.Null, int64(0)})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.NullPointerException {
+		t.Errorf("null task: expected NullPointerException, got %v", ret)
+	}
+
+	// a task that was cancelled before it was scheduled can't be scheduled
+	task := newZipObject("java/util/TimerTask")
+	timerTaskCancel([]interface{}{task})
+	ret = timerSchedule([]interface{}{timer, task, int64(0)})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalStateException {
+		t.Errorf("cancelled task: expected IllegalStateException, got %v", ret)
+	}
+}
+
+func TestTimerTaskException(t *testing.T) {
+	runs := fakeTaskRuns(t)
+	var out bytes.Buffer
+	globals.SetTraceWriter(&out)
+	t.Cleanup(func() { globals.SetTraceWriter(nil) })
+	timer := newTimer(t)
+
+	// an exception in a task is reported, and it cancels the Timer
+	failing := newThrowingTask("java/util/TimerTask", "java.lang.IllegalStateException: broken")
+	later := newZipObject("java/util/TimerTask")
+	timerSchedule([]interface{}{timer, failing, int64(0)})
+	timerSchedule([]interface{}{timer, later, int64(50)})
+	expectRun(t, runs, failing)
+	expectNoRun(t, runs)
+	if got := out.String(); !strings.Contains(got, "java.lang.IllegalStateException: broken") {
+		t.Errorf("the exception was not reported: %q", got)
+	}
+	ret := timerSchedule([]interface{}{timer, newZipObject("java/util/TimerTask"), int64(0)})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalStateException {
+		t.Errorf("schedule after a task failed: expected IllegalStateException, got %v", ret)
+	}
+}
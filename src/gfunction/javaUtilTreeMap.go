@@ -0,0 +1,1341 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"slices"
+	"strings"
+)
+
+// TreeMap and TreeSet keep their entries in a slice sorted by key, which is searched by
+// binary search. Keys are compared with the map's Comparator or by their natural ordering,
+// through the same bridge to Java code as the sorts (see sortObjects.go), so the methods
+// that compare keys need the frame stack. The views returned by headMap(), tailMap(),
+// subMap(), and keySet() -- and by headSet(), tailSet(), and subSet() of a TreeSet -- share
+// the entries of the map or set they come from, restricted to their range of keys, so
+// changes to one are seen in the other, as in Java. Iterators throw a
+// ConcurrentModificationException if the map is changed other than by the iterator's
+// remove(). The descending views aren't supported.
+
+var classNameTreeMap = "java/util/TreeMap"
+var classNameTreeMapEntry = "java/util/TreeMap$Entry"
+var classNameTreeMapEntrySet = "java/util/TreeMap$EntrySet"
+var classNameTreeMapValues = "java/util/TreeMap$Values"
+
+// The field of TreeMap, TreeSet, and their helper objects that holds the Go state
+var fieldNameTreeState = "treeState"
+
+// the classes of the iterators, by what they return
+var treeIteratorClasses = map[int]string{
+	mapIterateKeys:    "java/util/TreeMap$KeyIterator",
+	mapIterateValues:  "java/util/TreeMap$ValueIterator",
+	mapIterateEntries: "java/util/TreeMap$EntryIterator",
+}
+
+var classNameTreeDescendingIterator = "java/util/TreeMap$DescendingKeyIterator"
+
+func Load_Util_TreeMap() {
+
+	object.RegisterInstantiationHook(classNameTreeMap, newTreeObject)
+
+	MethodSignatures["java/util/TreeMap.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/TreeMap.<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  treeInit,
+		}
+
+	MethodSignatures["java/util/TreeMap.<init>(Ljava/util/Comparator;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  treeInit,
+		}
+
+	MethodSignatures["java/util/TreeMap.<init>(Ljava/util/Map;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treemapInitFromMap,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.<init>(Ljava/util/SortedMap;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treemapInitFromMap,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.ceilingEntry(Ljava/lang/Object;)Ljava/util/Map$Entry;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treemapCeilingEntry,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.ceilingKey(Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treemapCeilingKey,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.clear()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treeClear,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.clone()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treeClone,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.comparator()Ljava/util/Comparator;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treeComparator,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.containsKey(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treeContains,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.containsValue(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treemapContainsValue,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.descendingKeySet()Ljava/util/NavigableSet;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["java/util/TreeMap.descendingMap()Ljava/util/NavigableMap;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["java/util/TreeMap.entrySet()Ljava/util/Set;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treemapEntrySet,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.firstEntry()Ljava/util/Map$Entry;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treemapFirstEntry,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.firstKey()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treeFirstKey,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.floorEntry(Ljava/lang/Object;)Ljava/util/Map$Entry;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treemapFloorEntry,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.floorKey(Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treemapFloorKey,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.get(Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treemapGet,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.headMap(Ljava/lang/Object;)Ljava/util/SortedMap;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treeHead,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.headMap(Ljava/lang/Object;Z)Ljava/util/NavigableMap;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    treeHead,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.higherEntry(Ljava/lang/Object;)Ljava/util/Map$Entry;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treemapHigherEntry,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.higherKey(Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treemapHigherKey,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.isEmpty()Z"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treeIsEmpty,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.keySet()Ljava/util/Set;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treemapKeySet,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.lastEntry()Ljava/util/Map$Entry;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treemapLastEntry,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.lastKey()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treeLastKey,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.lowerEntry(Ljava/lang/Object;)Ljava/util/Map$Entry;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treemapLowerEntry,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.lowerKey(Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treemapLowerKey,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.navigableKeySet()Ljava/util/NavigableSet;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treemapKeySet,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.pollFirstEntry()Ljava/util/Map$Entry;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treemapPollFirstEntry,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.pollLastEntry()Ljava/util/Map$Entry;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treemapPollLastEntry,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.put(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    treemapPut,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.putAll(Ljava/util/Map;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treemapPutAll,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.remove(Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treemapRemove,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.size()I"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treeSize,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.subMap(Ljava/lang/Object;Ljava/lang/Object;)Ljava/util/SortedMap;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    treeSub,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.subMap(Ljava/lang/Object;ZLjava/lang/Object;Z)Ljava/util/NavigableMap;"] =
+		GMeth{
+			ParamSlots:   4,
+			GFunction:    treeSub,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.tailMap(Ljava/lang/Object;)Ljava/util/SortedMap;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treeTail,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.tailMap(Ljava/lang/Object;Z)Ljava/util/NavigableMap;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    treeTail,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treemapToString,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeMap.values()Ljava/util/Collection;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treemapValues,
+			NeedsContext: true,
+		}
+
+	// the collections returned by entrySet() and values()
+
+	for _, className := range []string{classNameTreeMapEntrySet, classNameTreeMapValues} {
+		MethodSignatures[className+".isEmpty()Z"] =
+			GMeth{
+				ParamSlots:   0,
+				GFunction:    treeIsEmpty,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".iterator()Ljava/util/Iterator;"] =
+			GMeth{
+				ParamSlots:   0,
+				GFunction:    treeCollectionIterator,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".size()I"] =
+			GMeth{
+				ParamSlots:   0,
+				GFunction:    treeSize,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".toString()Ljava/lang/String;"] =
+			GMeth{
+				ParamSlots:   0,
+				GFunction:    treeCollectionToString,
+				NeedsContext: true,
+			}
+	}
+
+	// the iterators
+
+	for _, className := range append(mapValues(treeIteratorClasses), classNameTreeDescendingIterator) {
+		MethodSignatures[className+".hasNext()Z"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  treeIteratorHasNext,
+			}
+
+		MethodSignatures[className+".next()Ljava/lang/Object;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  treeIteratorNext,
+			}
+
+		MethodSignatures[className+".remove()V"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  treeIteratorRemove,
+			}
+	}
+
+	// the entries
+
+	registerMapEntryMethods(classNameTreeMapEntry)
+}
+
+// treeStore holds the entries of a TreeMap or a TreeSet, sorted by key. It is shared by
+// the map or set and all of its views.
+type treeStore struct {
+	comparator *object.Object // null for the natural ordering
+	entries    []*mapEntry
+	modCount   int // the number of changes to the keys, which iterators check
+}
+
+// treeBound is the low or high end of the keys of a view
+type treeBound struct {
+	key       *object.Object
+	set       bool // false if there is no bound at this end
+	inclusive bool
+}
+
+// treeView is the state of a TreeMap or a TreeSet: the keys of its store within its bounds
+type treeView struct {
+	store    *treeStore
+	lo, hi   treeBound
+	keysOnly bool // a key set of a map, to which nothing can be added
+}
+
+// the state of an iterator. The ascending iterators return the entries from pos up to end;
+// the descending ones, from pos down to end.
+type treeIterator struct {
+	store       *treeStore
+	kind        int
+	descending  bool
+	pos, end    int
+	last        int // the index of the entry last returned, or -1 if there is none to remove
+	expectedMod int
+}
+
+// the instantiation hook for TreeMap and TreeSet: a new object holding an empty tree
+func newTreeObject(className string) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&className)
+	obj.FieldTable[fieldNameTreeState] = object.Field{Ftype: types.TreeState, Fvalue: &treeView{store: &treeStore{}}}
+	return obj
+}
+
+// makes an object of the named class, whose state is the view
+func newTreeViewObject(className string, view *treeView) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&className)
+	obj.FieldTable[fieldNameTreeState] = object.Field{Ftype: types.TreeState, Fvalue: view}
+	return obj
+}
+
+// returns the values of the map in the order of their keys
+func mapValues(m map[int]string) []string {
+	var values []string
+	for key := 0; key < len(m); key++ {
+		values = append(values, m[key])
+	}
+	return values
+}
+
+// returns the frame stack and the view of the TreeMap, TreeSet, or collection view in the
+// params of a method that needs the context
+func getTreeView(funcName string, params []interface{}) (*list.List, *treeView, *GErrBlk) {
+	fs, _ := params[0].(*list.List)
+	obj, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return nil, nil, getGErrBlk(excNames.NullPointerException, funcName+": the object is null")
+	}
+	view, ok := obj.FieldTable[fieldNameTreeState].Fvalue.(*treeView)
+	if !ok {
+		errMsg := fmt.Sprintf("%s: %s is not a tree", funcName, *stringPool.GetStringPointer(obj.KlassName))
+		return nil, nil, getGErrBlk(excNames.IllegalStateException, errMsg)
+	}
+	return fs, view, nil
+}
+
+// returns the object in params[index], which may be null
+func treeKeyParam(params []interface{}, index int) *object.Object {
+	obj, _ := params[index].(*object.Object)
+	return obj
+}
+
+// compares two keys as the store orders them
+func (s *treeStore) compare(fs *list.List, a, b *object.Object) (int, *GErrBlk) {
+	return compareJavaObjects(fs, s.comparator, a, b)
+}
+
+// checks that the key can be compared: null keys are allowed only by comparators
+func (s *treeStore) checkKey(funcName string, key *object.Object) *GErrBlk {
+	if object.IsNull(s.comparator) && object.IsNull(key) {
+		return getGErrBlk(excNames.NullPointerException, funcName+": null key in natural ordering")
+	}
+	return nil
+}
+
+// returns the index of the first entry whose key is at least the key or, if strict is
+// true, greater than the key
+func (s *treeStore) indexAbove(fs *list.List, key *object.Object, strict bool) (int, *GErrBlk) {
+	low, high := 0, len(s.entries)
+	for low < high {
+		mid := (low + high) / 2
+		result, gErr := s.compare(fs, key, s.entries[mid].key)
+		if gErr != nil {
+			return 0, gErr
+		}
+		if result > 0 || (strict && result == 0) {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return low, nil
+}
+
+// returns the index of the entry with the key, and whether there is one. If there isn't,
+// the index is where it would be inserted.
+func (s *treeStore) find(fs *list.List, key *object.Object) (int, bool, *GErrBlk) {
+	index, gErr := s.indexAbove(fs, key, false)
+	if gErr != nil || index == len(s.entries) {
+		return index, false, gErr
+	}
+	result, gErr := s.compare(fs, key, s.entries[index].key)
+	return index, result == 0, gErr
+}
+
+// returns the indexes of the entries in the view: from is the first, and to is one past the last
+func (v *treeView) span(fs *list.List) (int, int, *GErrBlk) {
+	from, to := 0, len(v.store.entries)
+	var gErr *GErrBlk
+	if v.lo.set {
+		if from, gErr = v.store.indexAbove(fs, v.lo.key, !v.lo.inclusive); gErr != nil {
+			return 0, 0, gErr
+		}
+	}
+	if v.hi.set {
+		if to, gErr = v.store.indexAbove(fs, v.hi.key, v.hi.inclusive); gErr != nil {
+			return 0, 0, gErr
+		}
+	}
+	return from, max(from, to), nil
+}
+
+// reports whether the key is above the low bound of the view, or at it if the bound is
+// inclusive or closed is true
+func (v *treeView) aboveLow(fs *list.List, key *object.Object, closed bool) (bool, *GErrBlk) {
+	if !v.lo.set {
+		return true, nil
+	}
+	result, gErr := v.store.compare(fs, key, v.lo.key)
+	return result > 0 || (result == 0 && (closed || v.lo.inclusive)), gErr
+}
+
+// reports whether the key is below the high bound of the view, or at it if the bound is
+// inclusive or closed is true
+func (v *treeView) belowHigh(fs *list.List, key *object.Object, closed bool) (bool, *GErrBlk) {
+	if !v.hi.set {
+		return true, nil
+	}
+	result, gErr := v.store.compare(fs, key, v.hi.key)
+	return result < 0 || (result == 0 && (closed || v.hi.inclusive)), gErr
+}
+
+// reports whether the key is in the range of the view. If closed is true, the bounds of the
+// range count as in it even if they're exclusive; this is how Java checks the bounds of a
+// view of a view.
+func (v *treeView) inRange(fs *list.List, key *object.Object, closed bool) (bool, *GErrBlk) {
+	ok, gErr := v.aboveLow(fs, key, closed)
+	if !ok || gErr != nil {
+		return false, gErr
+	}
+	return v.belowHigh(fs, key, closed)
+}
+
+// returns the index of the entry with the key, and whether the view has one
+func (v *treeView) find(fs *list.List, funcName string, key *object.Object) (int, bool, *GErrBlk) {
+	if gErr := v.store.checkKey(funcName, key); gErr != nil {
+		return 0, false, gErr
+	}
+	ok, gErr := v.inRange(fs, key, false)
+	if !ok || gErr != nil {
+		return 0, false, gErr
+	}
+	return v.store.find(fs, key)
+}
+
+// puts the key and value in the view. Returns the previous value of the key, and whether
+// there was one.
+func (v *treeView) put(fs *list.List, funcName string, key *object.Object, value any) (any, bool, *GErrBlk) {
+	if gErr := v.store.checkKey(funcName, key); gErr != nil {
+		return nil, false, gErr
+	}
+	ok, gErr := v.inRange(fs, key, false)
+	if gErr != nil {
+		return nil, false, gErr
+	}
+	if !ok {
+		return nil, false, getGErrBlk(excNames.IllegalArgumentException, "key out of range")
+	}
+	if len(v.store.entries) == 0 { // as in Java, the first key is compared to itself to check its type
+		if _, gErr = v.store.compare(fs, key, key); gErr != nil {
+			return nil, false, gErr
+		}
+	}
+	index, found, gErr := v.store.find(fs, key)
+	if gErr != nil {
+		return nil, false, gErr
+	}
+	if found {
+		previous := v.store.entries[index].value
+		v.store.entries[index].value = value
+		return previous, true, nil
+	}
+	v.store.entries = slices.Insert(v.store.entries, index, &mapEntry{key: key, value: value})
+	v.store.modCount++
+	return nil, false, nil
+}
+
+// removes the entry at the index
+func (v *treeView) removeAt(index int) *mapEntry {
+	entry := v.store.entries[index]
+	v.store.entries = slices.Delete(v.store.entries, index, index+1)
+	v.store.modCount++
+	return entry
+}
+
+// the relations of the keys searched for by ceiling(), floor(), higher(), and lower()
+const (
+	treeCeiling = iota
+	treeFloor
+	treeHigher
+	treeLower
+)
+
+// returns the index of the entry of the view whose key has the relation to the key, or -1
+// if there is none
+func (v *treeView) nearest(fs *list.List, funcName string, key *object.Object, relation int) (int, *GErrBlk) {
+	if gErr := v.store.checkKey(funcName, key); gErr != nil {
+		return -1, gErr
+	}
+	from, to, gErr := v.span(fs)
+	if gErr != nil {
+		return -1, gErr
+	}
+	strict := relation == treeHigher || relation == treeFloor
+	index, gErr := v.store.indexAbove(fs, key, strict)
+	if gErr != nil {
+		return -1, gErr
+	}
+	if relation == treeCeiling || relation == treeHigher {
+		index = max(index, from)
+		if index >= to {
+			return -1, nil
+		}
+		return index, nil
+	}
+	index = min(index-1, to-1)
+	if index < from {
+		return -1, nil
+	}
+	return index, nil
+}
+
+// returns the index of the first or the last entry of the view, or -1 if it's empty
+func (v *treeView) end(fs *list.List, last bool) (int, *GErrBlk) {
+	from, to, gErr := v.span(fs)
+	if gErr != nil || from == to {
+		return -1, gErr
+	}
+	if last {
+		return to - 1, nil
+	}
+	return from, nil
+}
+
+// returns the view of the keys of this view between the bounds. As in Java, the bounds must
+// be within this view, and the low bound can't be above the high one.
+func (v *treeView) subView(fs *list.List, lo, hi treeBound) (*treeView, *GErrBlk) {
+	for _, bound := range []treeBound{lo, hi} {
+		if !bound.set {
+			continue
+		}
+		if gErr := v.store.checkKey("subView", bound.key); gErr != nil {
+			return nil, gErr
+		}
+	}
+	if lo.set && hi.set {
+		result, gErr := v.store.compare(fs, lo.key, hi.key)
+		if gErr != nil {
+			return nil, gErr
+		}
+		if result > 0 {
+			return nil, getGErrBlk(excNames.IllegalArgumentException, "fromKey > toKey")
+		}
+	}
+	if lo.set {
+		ok, gErr := v.inRange(fs, lo.key, !lo.inclusive)
+		if gErr != nil {
+			return nil, gErr
+		}
+		if !ok {
+			return nil, getGErrBlk(excNames.IllegalArgumentException, "fromKey out of range")
+		}
+	} else {
+		lo = v.lo
+	}
+	if hi.set {
+		ok, gErr := v.inRange(fs, hi.key, !hi.inclusive)
+		if gErr != nil {
+			return nil, gErr
+		}
+		if !ok {
+			return nil, getGErrBlk(excNames.IllegalArgumentException, "toKey out of range")
+		}
+	} else {
+		hi = v.hi
+	}
+	return &treeView{store: v.store, lo: lo, hi: hi, keysOnly: v.keysOnly}, nil
+}
+
+// returns the bound of a view given by the key in params[index] and, if there is one, the
+// boolean after it. The bound is inclusive if there's no boolean and inclusive is true.
+func treeBoundParam(params []interface{}, index int, hasFlag, inclusive bool) treeBound {
+	bound := treeBound{key: treeKeyParam(params, index), set: true, inclusive: inclusive}
+	if hasFlag {
+		bound.inclusive = params[index+1].(int64) != types.JavaBoolFalse
+	}
+	return bound
+}
+
+// returns an object of the same class as obj for the view
+func sameClassTreeView(params []interface{}, view *treeView) *object.Object {
+	obj := params[1].(*object.Object)
+	return newTreeViewObject(*stringPool.GetStringPointer(obj.KlassName), view)
+}
+
+// "java/util/TreeMap.<init>()V" and "java/util/TreeMap.<init>(Ljava/util/Comparator;)V",
+// and the same constructors of TreeSet
+func treeInit(params []interface{}) interface{} {
+	obj, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return getGErrBlk(excNames.NullPointerException, "treeInit: the object is null")
+	}
+	store := &treeStore{}
+	if len(params) > 1 {
+		store.comparator = treeKeyParam(params, 1)
+	}
+	obj.FieldTable[fieldNameTreeState] = object.Field{Ftype: types.TreeState, Fvalue: &treeView{store: store}}
+	return nil
+}
+
+// "java/util/TreeMap.<init>(Ljava/util/Map;)V" and "java/util/TreeMap.<init>(Ljava/util/SortedMap;)V"
+// The comparator of a TreeMap that is copied is kept.
+func treemapInitFromMap(params []interface{}) interface{} {
+	obj, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return getGErrBlk(excNames.NullPointerException, "treemapInitFromMap: the object is null")
+	}
+	source := treeKeyParam(params, 2)
+	if object.IsNull(source) {
+		return getGErrBlk(excNames.NullPointerException, "treemapInitFromMap: the map is null")
+	}
+	store := &treeStore{}
+	if sourceView, ok := source.FieldTable[fieldNameTreeState].Fvalue.(*treeView); ok {
+		store.comparator = sourceView.store.comparator
+	}
+	obj.FieldTable[fieldNameTreeState] = object.Field{Ftype: types.TreeState, Fvalue: &treeView{store: store}}
+	return treemapPutAll(params)
+}
+
+// "java/util/TreeMap.clear()V" and "java/util/TreeSet.clear()V"
+func treeClear(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treeClear", params)
+	if gErr != nil {
+		return gErr
+	}
+	from, to, gErr := view.span(fs)
+	if gErr != nil {
+		return gErr
+	}
+	view.store.entries = slices.Delete(view.store.entries, from, to)
+	view.store.modCount++
+	return nil
+}
+
+// "java/util/TreeMap.clone()Ljava/lang/Object;" and "java/util/TreeSet.clone()Ljava/lang/Object;"
+// The keys and values themselves aren't cloned.
+func treeClone(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treeClone", params)
+	if gErr != nil {
+		return gErr
+	}
+	from, to, gErr := view.span(fs)
+	if gErr != nil {
+		return gErr
+	}
+	store := &treeStore{comparator: view.store.comparator}
+	for _, entry := range view.store.entries[from:to] {
+		store.entries = append(store.entries, &mapEntry{key: entry.key, value: entry.value})
+	}
+	return sameClassTreeView(params, &treeView{store: store})
+}
+
+// "java/util/TreeMap.comparator()Ljava/util/Comparator;" and the same method of TreeSet
+func treeComparator(params []interface{}) interface{} {
+	_, view, gErr := getTreeView("treeComparator", params)
+	if gErr != nil {
+		return gErr
+	}
+	if object.IsNull(view.store.comparator) {
+		return object.Null
+	}
+	return view.store.comparator
+}
+
+// "java/util/TreeMap.containsKey(Ljava/lang/Object;)Z" and "java/util/TreeSet.contains(Ljava/lang/Object;)Z"
+func treeContains(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treeContains", params)
+	if gErr != nil {
+		return gErr
+	}
+	_, found, gErr := view.find(fs, "treeContains", treeKeyParam(params, 2))
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(found)
+}
+
+// "java/util/TreeMap.containsValue(Ljava/lang/Object;)Z"
+func treemapContainsValue(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treemapContainsValue", params)
+	if gErr != nil {
+		return gErr
+	}
+	from, to, gErr := view.span(fs)
+	if gErr != nil {
+		return gErr
+	}
+	for _, entry := range view.store.entries[from:to] {
+		equal, gErr := javaObjectsEqual(fs, params[2], entry.value)
+		if gErr != nil {
+			return gErr
+		}
+		if equal {
+			return types.JavaBoolTrue
+		}
+	}
+	return types.JavaBoolFalse
+}
+
+// "java/util/TreeMap.get(Ljava/lang/Object;)Ljava/lang/Object;"
+func treemapGet(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treemapGet", params)
+	if gErr != nil {
+		return gErr
+	}
+	index, found, gErr := view.find(fs, "treemapGet", treeKeyParam(params, 2))
+	if gErr != nil {
+		return gErr
+	}
+	if !found {
+		return object.Null
+	}
+	return view.store.entries[index].value
+}
+
+// "java/util/TreeMap.put(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"
+func treemapPut(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treemapPut", params)
+	if gErr != nil {
+		return gErr
+	}
+	previous, found, gErr := view.put(fs, "treemapPut", treeKeyParam(params, 2), params[3])
+	if gErr != nil {
+		return gErr
+	}
+	if !found {
+		return object.Null
+	}
+	return previous
+}
+
+// "java/util/TreeMap.putAll(Ljava/util/Map;)V" -- the entries of a TreeMap are copied
+// directly; those of other maps are obtained through their entrySet() in Java
+func treemapPutAll(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treemapPutAll", params)
+	if gErr != nil {
+		return gErr
+	}
+	source := treeKeyParam(params, 2)
+	if object.IsNull(source) {
+		return getGErrBlk(excNames.NullPointerException, "treemapPutAll: the map is null")
+	}
+
+	if sourceView, ok := source.FieldTable[fieldNameTreeState].Fvalue.(*treeView); ok {
+		from, to, gErr := sourceView.span(fs)
+		if gErr != nil {
+			return gErr
+		}
+		for _, entry := range slices.Clone(sourceView.store.entries[from:to]) {
+			if _, _, gErr = view.put(fs, "treemapPutAll", entry.key, entry.value); gErr != nil {
+				return gErr
+			}
+		}
+		return nil
+	}
+
+	entrySet, gErr := invokeJavaMethod(fs, "java/util/Map", "entrySet", "()Ljava/util/Set;", source, nil)
+	if gErr != nil {
+		return gErr
+	}
+	ret, gErr := invokeJavaMethod(fs, "java/util/Set", "toArray", "()[Ljava/lang/Object;", entrySet, nil)
+	if gErr != nil {
+		return gErr
+	}
+	entries, _ := ret.(*object.Object).FieldTable["value"].Fvalue.([]*object.Object)
+	for _, entry := range entries {
+		key, gErr := invokeJavaMethod(fs, "java/util/Map$Entry", "getKey", "()Ljava/lang/Object;", entry, nil)
+		if gErr != nil {
+			return gErr
+		}
+		value, gErr := invokeJavaMethod(fs, "java/util/Map$Entry", "getValue", "()Ljava/lang/Object;", entry, nil)
+		if gErr != nil {
+			return gErr
+		}
+		keyObj, _ := key.(*object.Object)
+		if _, _, gErr = view.put(fs, "treemapPutAll", keyObj, value); gErr != nil {
+			return gErr
+		}
+	}
+	return nil
+}
+
+// "java/util/TreeMap.remove(Ljava/lang/Object;)Ljava/lang/Object;"
+func treemapRemove(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treemapRemove", params)
+	if gErr != nil {
+		return gErr
+	}
+	index, found, gErr := view.find(fs, "treemapRemove", treeKeyParam(params, 2))
+	if gErr != nil {
+		return gErr
+	}
+	if !found {
+		return object.Null
+	}
+	return view.removeAt(index).value
+}
+
+// "java/util/TreeMap.size()I", "java/util/TreeSet.size()I", and the size() of the
+// collections returned by entrySet() and values()
+func treeSize(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treeSize", params)
+	if gErr != nil {
+		return gErr
+	}
+	from, to, gErr := view.span(fs)
+	if gErr != nil {
+		return gErr
+	}
+	return int64(to - from)
+}
+
+// "java/util/TreeMap.isEmpty()Z", "java/util/TreeSet.isEmpty()Z", and the isEmpty() of
+// the collections returned by entrySet() and values()
+func treeIsEmpty(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treeIsEmpty", params)
+	if gErr != nil {
+		return gErr
+	}
+	from, to, gErr := view.span(fs)
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(from == to)
+}
+
+// returns the key of the first or the last entry, or throws a NoSuchElementException
+func treeEndKey(funcName string, params []interface{}, last bool) interface{} {
+	fs, view, gErr := getTreeView(funcName, params)
+	if gErr != nil {
+		return gErr
+	}
+	index, gErr := view.end(fs, last)
+	if gErr != nil {
+		return gErr
+	}
+	if index < 0 {
+		return getGErrBlk(excNames.NoSuchElementException, funcName+": the tree is empty")
+	}
+	return view.store.entries[index].key
+}
+
+// "java/util/TreeMap.firstKey()Ljava/lang/Object;" and "java/util/TreeSet.first()Ljava/lang/Object;"
+func treeFirstKey(params []interface{}) interface{} {
+	return treeEndKey("treeFirstKey", params, false)
+}
+
+// "java/util/TreeMap.lastKey()Ljava/lang/Object;" and "java/util/TreeSet.last()Ljava/lang/Object;"
+func treeLastKey(params []interface{}) interface{} {
+	return treeEndKey("treeLastKey", params, true)
+}
+
+// returns the first or the last entry as an immutable snapshot, or null if the map is
+// empty. If poll is true, the entry is removed.
+func treemapEndEntry(funcName string, params []interface{}, last, poll bool) interface{} {
+	fs, view, gErr := getTreeView(funcName, params)
+	if gErr != nil {
+		return gErr
+	}
+	index, gErr := view.end(fs, last)
+	if gErr != nil {
+		return gErr
+	}
+	if index < 0 {
+		return object.Null
+	}
+	entry := view.store.entries[index]
+	if poll {
+		view.removeAt(index)
+	}
+	return newMapEntryObject(classNameTreeMapEntry, entry, true)
+}
+
+// "java/util/TreeMap.firstEntry()Ljava/util/Map$Entry;"
+func treemapFirstEntry(params []interface{}) interface{} {
+	return treemapEndEntry("treemapFirstEntry", params, false, false)
+}
+
+// "java/util/TreeMap.lastEntry()Ljava/util/Map$Entry;"
+func treemapLastEntry(params []interface{}) interface{} {
+	return treemapEndEntry("treemapLastEntry", params, true, false)
+}
+
+// "java/util/TreeMap.pollFirstEntry()Ljava/util/Map$Entry;"
+func treemapPollFirstEntry(params []interface{}) interface{} {
+	return treemapEndEntry("treemapPollFirstEntry", params, false, true)
+}
+
+// "java/util/TreeMap.pollLastEntry()Ljava/util/Map$Entry;"
+func treemapPollLastEntry(params []interface{}) interface{} {
+	return treemapEndEntry("treemapPollLastEntry", params, true, true)
+}
+
+// returns the entry of the map whose key has the relation to the key in params[2], as an
+// immutable entry if wantEntry is true, otherwise its key. Returns null if there is none.
+func treeNearest(funcName string, params []interface{}, relation int, wantEntry bool) interface{} {
+	fs, view, gErr := getTreeView(funcName, params)
+	if gErr != nil {
+		return gErr
+	}
+	index, gErr := view.nearest(fs, funcName, treeKeyParam(params, 2), relation)
+	if gErr != nil {
+		return gErr
+	}
+	if index < 0 {
+		return object.Null
+	}
+	if wantEntry {
+		return newMapEntryObject(classNameTreeMapEntry, view.store.entries[index], true)
+	}
+	return view.store.entries[index].key
+}
+
+// "java/util/TreeMap.ceilingEntry(Ljava/lang/Object;)Ljava/util/Map$Entry;"
+func treemapCeilingEntry(params []interface{}) interface{} {
+	return treeNearest("treemapCeilingEntry", params, treeCeiling, true)
+}
+
+// "java/util/TreeMap.ceilingKey(Ljava/lang/Object;)Ljava/lang/Object;" and
+// "java/util/TreeSet.ceiling(Ljava/lang/Object;)Ljava/lang/Object;"
+func treemapCeilingKey(params []interface{}) interface{} {
+	return treeNearest("treemapCeilingKey", params, treeCeiling, false)
+}
+
+// "java/util/TreeMap.floorEntry(Ljava/lang/Object;)Ljava/util/Map$Entry;"
+func treemapFloorEntry(params []interface{}) interface{} {
+	return treeNearest("treemapFloorEntry", params, treeFloor, true)
+}
+
+// "java/util/TreeMap.floorKey(Ljava/lang/Object;)Ljava/lang/Object;" and
+// "java/util/TreeSet.floor(Ljava/lang/Object;)Ljava/lang/Object;"
+func treemapFloorKey(params []interface{}) interface{} {
+	return treeNearest("treemapFloorKey", params, treeFloor, false)
+}
+
+// "java/util/TreeMap.higherEntry(Ljava/lang/Object;)Ljava/util/Map$Entry;"
+func treemapHigherEntry(params []interface{}) interface{} {
+	return treeNearest("treemapHigherEntry", params, treeHigher, true)
+}
+
+// "java/util/TreeMap.higherKey(Ljava/lang/Object;)Ljava/lang/Object;" and
+// "java/util/TreeSet.higher(Ljava/lang/Object;)Ljava/lang/Object;"
+func treemapHigherKey(params []interface{}) interface{} {
+	return treeNearest("treemapHigherKey", params, treeHigher, false)
+}
+
+// "java/util/TreeMap.lowerEntry(Ljava/lang/Object;)Ljava/util/Map$Entry;"
+func treemapLowerEntry(params []interface{}) interface{} {
+	return treeNearest("treemapLowerEntry", params, treeLower, true)
+}
+
+// "java/util/TreeMap.lowerKey(Ljava/lang/Object;)Ljava/lang/Object;" and
+// "java/util/TreeSet.lower(Ljava/lang/Object;)Ljava/lang/Object;"
+func treemapLowerKey(params []interface{}) interface{} {
+	return treeNearest("treemapLowerKey", params, treeLower, false)
+}
+
+// "java/util/TreeMap.headMap(Ljava/lang/Object;)Ljava/util/SortedMap;", headMap(Object, boolean),
+// and the headSet() methods of TreeSet. Without the boolean, the high bound is exclusive.
+func treeHead(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treeHead", params)
+	if gErr != nil {
+		return gErr
+	}
+	sub, gErr := view.subView(fs, treeBound{}, treeBoundParam(params, 2, len(params) > 3, false))
+	if gErr != nil {
+		return gErr
+	}
+	return sameClassTreeView(params, sub)
+}
+
+// "java/util/TreeMap.tailMap(Ljava/lang/Object;)Ljava/util/SortedMap;", tailMap(Object, boolean),
+// and the tailSet() methods of TreeSet. Without the boolean, the low bound is inclusive.
+func treeTail(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treeTail", params)
+	if gErr != nil {
+		return gErr
+	}
+	sub, gErr := view.subView(fs, treeBoundParam(params, 2, len(params) > 3, true), treeBound{})
+	if gErr != nil {
+		return gErr
+	}
+	return sameClassTreeView(params, sub)
+}
+
+// "java/util/TreeMap.subMap(Ljava/lang/Object;Ljava/lang/Object;)Ljava/util/SortedMap;",
+// subMap(Object, boolean, Object, boolean), and the subSet() methods of TreeSet. Without the
+// booleans, the low bound is inclusive and the high one exclusive.
+func treeSub(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treeSub", params)
+	if gErr != nil {
+		return gErr
+	}
+	var lo, hi treeBound
+	if len(params) > 4 {
+		lo, hi = treeBoundParam(params, 2, true, true), treeBoundParam(params, 4, true, false)
+	} else {
+		lo, hi = treeBoundParam(params, 2, false, true), treeBoundParam(params, 3, false, false)
+	}
+	sub, gErr := view.subView(fs, lo, hi)
+	if gErr != nil {
+		return gErr
+	}
+	return sameClassTreeView(params, sub)
+}
+
+// "java/util/TreeMap.keySet()Ljava/util/Set;" and navigableKeySet() -- a TreeSet that shares
+// the keys of the map. Keys can be removed through it but not added.
+func treemapKeySet(params []interface{}) interface{} {
+	_, view, gErr := getTreeView("treemapKeySet", params)
+	if gErr != nil {
+		return gErr
+	}
+	return newTreeViewObject(classNameTreeSet, &treeView{store: view.store, lo: view.lo, hi: view.hi, keysOnly: true})
+}
+
+// "java/util/TreeMap.entrySet()Ljava/util/Set;"
+func treemapEntrySet(params []interface{}) interface{} {
+	_, view, gErr := getTreeView("treemapEntrySet", params)
+	if gErr != nil {
+		return gErr
+	}
+	return newTreeViewObject(classNameTreeMapEntrySet, view)
+}
+
+// "java/util/TreeMap.values()Ljava/util/Collection;"
+func treemapValues(params []interface{}) interface{} {
+	_, view, gErr := getTreeView("treemapValues", params)
+	if gErr != nil {
+		return gErr
+	}
+	return newTreeViewObject(classNameTreeMapValues, view)
+}
+
+// "java/util/TreeMap.toString()Ljava/lang/String;" -- as in Java, {key1=value1, key2=value2}
+func treemapToString(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treemapToString", params)
+	if gErr != nil {
+		return gErr
+	}
+	str, gErr := view.joinEntries(fs, mapIterateEntries)
+	if gErr != nil {
+		return gErr
+	}
+	return object.StringObjectFromGoString("{" + str + "}")
+}
+
+// returns the keys, values, or entries (key=value) of the view, as strings separated by commas
+func (v *treeView) joinEntries(fs *list.List, kind int) (string, *GErrBlk) {
+	from, to, gErr := v.span(fs)
+	if gErr != nil {
+		return "", gErr
+	}
+	var strs []string
+	for _, entry := range v.store.entries[from:to] {
+		str, gErr := entry.toString(fs, kind)
+		if gErr != nil {
+			return "", gErr
+		}
+		strs = append(strs, str)
+	}
+	return strings.Join(strs, ", "), nil
+}
+
+// the kind of element returned by the iterators of the collection in obj
+func treeCollectionKind(obj *object.Object) int {
+	switch *stringPool.GetStringPointer(obj.KlassName) {
+	case classNameTreeMapEntrySet:
+		return mapIterateEntries
+	case classNameTreeMapValues:
+		return mapIterateValues
+	}
+	return mapIterateKeys
+}
+
+// "java/util/TreeMap$EntrySet.toString()Ljava/lang/String;" and the same method of values()
+func treeCollectionToString(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treeCollectionToString", params)
+	if gErr != nil {
+		return gErr
+	}
+	str, gErr := view.joinEntries(fs, treeCollectionKind(params[1].(*object.Object)))
+	if gErr != nil {
+		return gErr
+	}
+	return object.StringObjectFromGoString("[" + str + "]")
+}
+
+// makes an iterator of the kind over the view
+func newTreeIterator(fs *list.List, view *treeView, kind int, descending bool) interface{} {
+	from, to, gErr := view.span(fs)
+	if gErr != nil {
+		return gErr
+	}
+	iterator := &treeIterator{store: view.store, kind: kind, descending: descending, pos: from, end: to,
+		last: -1, expectedMod: view.store.modCount}
+	className := treeIteratorClasses[kind]
+	if descending {
+		iterator.pos, iterator.end = to-1, from
+		className = classNameTreeDescendingIterator
+	}
+	obj := object.MakeEmptyObjectWithClassName(&className)
+	obj.FieldTable[fieldNameTreeState] = object.Field{Ftype: types.TreeState, Fvalue: iterator}
+	return obj
+}
+
+// "java/util/TreeMap$EntrySet.iterator()Ljava/util/Iterator;", the iterator() of values(),
+// and "java/util/TreeSet.iterator()Ljava/util/Iterator;"
+func treeCollectionIterator(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treeCollectionIterator", params)
+	if gErr != nil {
+		return gErr
+	}
+	return newTreeIterator(fs, view, treeCollectionKind(params[1].(*object.Object)), false)
+}
+
+// returns the state of the iterator in params[0], which must not have been overtaken by
+// changes to the map
+func getTreeIterator(funcName string, params []interface{}) (*treeIterator, *GErrBlk) {
+	obj, ok := params[0].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return nil, getGErrBlk(excNames.NullPointerException, funcName+": the iterator is null")
+	}
+	iterator, ok := obj.FieldTable[fieldNameTreeState].Fvalue.(*treeIterator)
+	if !ok {
+		return nil, getGErrBlk(excNames.IllegalStateException, funcName+": not a tree iterator")
+	}
+	if iterator.expectedMod != iterator.store.modCount {
+		return nil, getGErrBlk(excNames.ConcurrentModificationException, funcName+": the tree was changed")
+	}
+	return iterator, nil
+}
+
+// the hasNext() of the iterators of TreeMap and TreeSet
+func treeIteratorHasNext(params []interface{}) interface{} {
+	obj, _ := params[0].(*object.Object)
+	if iterator, ok := obj.FieldTable[fieldNameTreeState].Fvalue.(*treeIterator); ok {
+		if iterator.descending {
+			return types.ConvertGoBoolToJavaBool(iterator.pos >= iterator.end)
+		}
+		return types.ConvertGoBoolToJavaBool(iterator.pos < iterator.end)
+	}
+	return types.JavaBoolFalse
+}
+
+// the next() of the iterators of TreeMap and TreeSet
+func treeIteratorNext(params []interface{}) interface{} {
+	iterator, gErr := getTreeIterator("treeIteratorNext", params)
+	if gErr != nil {
+		return gErr
+	}
+	if (iterator.descending && iterator.pos < iterator.end) || (!iterator.descending && iterator.pos >= iterator.end) {
+		return getGErrBlk(excNames.NoSuchElementException, "treeIteratorNext: no more elements")
+	}
+	entry := iterator.store.entries[iterator.pos]
+	iterator.last = iterator.pos
+	if iterator.descending {
+		iterator.pos--
+	} else {
+		iterator.pos++
+	}
+
+	switch iterator.kind {
+	case mapIterateValues:
+		return entry.value
+	case mapIterateEntries:
+		return newMapEntryObject(classNameTreeMapEntry, entry, false)
+	}
+	return entry.key
+}
+
+// the remove() of the iterators of TreeMap and TreeSet -- removes the element last returned
+func treeIteratorRemove(params []interface{}) interface{} {
+	iterator, gErr := getTreeIterator("treeIteratorRemove", params)
+	if gErr != nil {
+		return gErr
+	}
+	if iterator.last < 0 {
+		return getGErrBlk(excNames.IllegalStateException, "treeIteratorRemove: next() has not been called")
+	}
+	iterator.store.entries = slices.Delete(iterator.store.entries, iterator.last, iterator.last+1)
+	iterator.store.modCount++
+	iterator.expectedMod = iterator.store.modCount
+	if !iterator.descending { // the entries after the one removed have moved down
+		iterator.pos--
+		iterator.end--
+	}
+	iterator.last = -1
+	return nil
+}
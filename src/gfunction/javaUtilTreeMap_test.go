@@ -0,0 +1,226 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"testing"
+)
+
+// the keys and values of the tests are Integers and Strings, which are compared and
+// formatted without upcalls
+func treeInt(value int64) *object.Object {
+	return Populator("java/lang/Integer", types.Int, value)
+}
+
+func newTestTree(className string, comparator *object.Object) *object.Object {
+	obj := newTreeObject(className)
+	if comparator != nil {
+		treeInit([]interface{}{obj, comparator})
+	}
+	return obj
+}
+
+// calls the gfunction on the tree with the frame stack and the args, as RunGfunction does
+func treeCall(gfunc func([]interface{}) interface{}, tree *object.Object, args ...interface{}) interface{} {
+	return gfunc(append([]interface{}{list.New(), tree}, args...))
+}
+
+func newTestTreeMap(keys ...int64) *object.Object {
+	tm := newTestTree(classNameTreeMap, nil)
+	for _, key := range keys {
+		treeCall(treemapPut, tm, treeInt(key), object.StringObjectFromGoString(object.JavaDoubleString(float64(key))))
+	}
+	return tm
+}
+
+func checkTreeString(t *testing.T, what string, ret interface{}, expected string) {
+	t.Helper()
+	str, ok := ret.(*object.Object)
+	if !ok {
+		t.Errorf("%s: expected %q, got %v", what, expected, ret)
+		return
+	}
+	if got := object.GoStringFromStringObject(str); got != expected {
+		t.Errorf("%s: expected %q, got %q", what, expected, got)
+	}
+}
+
+func checkTreeKey(t *testing.T, what string, ret interface{}, expected int64) {
+	t.Helper()
+	obj, ok := ret.(*object.Object)
+	if !ok || object.IsNull(obj) {
+		t.Errorf("%s: expected %d, got %v", what, expected, ret)
+		return
+	}
+	if got := obj.FieldTable["value"].Fvalue.(int64); got != expected {
+		t.Errorf("%s: expected %d, got %d", what, expected, got)
+	}
+}
+
+func checkTreeError(t *testing.T, what string, ret interface{}, excType int) {
+	t.Helper()
+	gErr, ok := ret.(*GErrBlk)
+	if !ok || gErr.ExceptionType != excType {
+		t.Errorf("%s: expected exception %d, got %v", what, excType, ret)
+	}
+}
+
+func TestTreeMapPutGetAndNavigation(t *testing.T) {
+	globals.InitGlobals("test")
+	tm := newTestTreeMap(5, 1, 9, 3)
+
+	checkTreeString(t, "toString()", treeCall(treemapToString, tm), "{1=1.0, 3=3.0, 5=5.0, 9=9.0}")
+	if ret := treeCall(treeSize, tm); ret != int64(4) {
+		t.Errorf("size(): expected 4, got %v", ret)
+	}
+	checkTreeString(t, "put(3)", treeCall(treemapPut, tm, treeInt(3), object.StringObjectFromGoString("three")), "3.0")
+	checkTreeString(t, "get(3)", treeCall(treemapGet, tm, treeInt(3)), "three")
+	if ret := treeCall(treemapGet, tm, treeInt(4)); !object.IsNull(ret) {
+		t.Errorf("get(4): expected null, got %v", ret)
+	}
+	if ret := treeCall(treemapContainsValue, tm, object.StringObjectFromGoString("9.0")); ret != types.JavaBoolTrue {
+		t.Errorf("containsValue(\"9.0\"): expected true, got %v", ret)
+	}
+
+	checkTreeKey(t, "firstKey()", treeCall(treeFirstKey, tm), 1)
+	checkTreeKey(t, "lastKey()", treeCall(treeLastKey, tm), 9)
+	checkTreeKey(t, "ceilingKey(4)", treeCall(treemapCeilingKey, tm, treeInt(4)), 5)
+	checkTreeKey(t, "ceilingKey(5)", treeCall(treemapCeilingKey, tm, treeInt(5)), 5)
+	checkTreeKey(t, "floorKey(4)", treeCall(treemapFloorKey, tm, treeInt(4)), 3)
+	checkTreeKey(t, "floorKey(5)", treeCall(treemapFloorKey, tm, treeInt(5)), 5)
+	checkTreeKey(t, "higherKey(5)", treeCall(treemapHigherKey, tm, treeInt(5)), 9)
+	checkTreeKey(t, "lowerKey(5)", treeCall(treemapLowerKey, tm, treeInt(5)), 3)
+	if ret := treeCall(treemapHigherKey, tm, treeInt(9)); !object.IsNull(ret) {
+		t.Errorf("higherKey(9): expected null, got %v", ret)
+	}
+	if ret := treeCall(treemapLowerKey, tm, treeInt(1)); !object.IsNull(ret) {
+		t.Errorf("lowerKey(1): expected null, got %v", ret)
+	}
+	checkTreeString(t, "floorEntry(8)", treeCall(mapEntryToString,
+		treeCall(treemapFloorEntry, tm, treeInt(8)).(*object.Object)), "5=5.0")
+
+	entry := treeCall(treemapPollFirstEntry, tm).(*object.Object)
+	checkTreeString(t, "pollFirstEntry()", treeCall(mapEntryToString, entry), "1=1.0")
+	checkTreeError(t, "setValue() of a snapshot", mapEntrySetValue([]interface{}{entry, object.Null}),
+		excNames.UnsupportedOperationException)
+	checkTreeString(t, "remove(9)", treeCall(treemapRemove, tm, treeInt(9)), "9.0")
+	checkTreeString(t, "toString() after removals", treeCall(treemapToString, tm), "{3=three, 5=5.0}")
+
+	treeCall(treeClear, tm)
+	checkTreeError(t, "firstKey() of an empty map", treeCall(treeFirstKey, tm), excNames.NoSuchElementException)
+	if ret := treeCall(treemapFirstEntry, tm); !object.IsNull(ret) {
+		t.Errorf("firstEntry() of an empty map: expected null, got %v", ret)
+	}
+	checkTreeError(t, "put(null)", treeCall(treemapPut, tm, object.Null, object.Null), excNames.NullPointerException)
+}
+
+func TestTreeMapRangeViews(t *testing.T) {
+	globals.InitGlobals("test")
+	tm := newTestTreeMap(1, 3, 5, 7, 9)
+
+	head := treeCall(treeHead, tm, treeInt(5)).(*object.Object)
+	checkTreeString(t, "headMap(5)", treeCall(treemapToString, head), "{1=1.0, 3=3.0}")
+	tail := treeCall(treeTail, tm, treeInt(5), types.JavaBoolFalse).(*object.Object)
+	checkTreeString(t, "tailMap(5, false)", treeCall(treemapToString, tail), "{7=7.0, 9=9.0}")
+	sub := treeCall(treeSub, tm, treeInt(3), types.JavaBoolFalse, treeInt(9), types.JavaBoolTrue).(*object.Object)
+	checkTreeString(t, "subMap(3, false, 9, true)", treeCall(treemapToString, sub), "{5=5.0, 7=7.0, 9=9.0}")
+	checkTreeKey(t, "firstKey() of the submap", treeCall(treeFirstKey, sub), 5)
+	checkTreeKey(t, "ceilingKey(4) of the tail map", treeCall(treemapCeilingKey, tail, treeInt(4)), 7)
+	if ret := treeCall(treemapFloorKey, tail, treeInt(6)); !object.IsNull(ret) {
+		t.Errorf("floorKey(6) of the tail map: expected null, got %v", ret)
+	}
+
+	// the views share the entries of the map
+	treeCall(treemapPut, tm, treeInt(2), object.StringObjectFromGoString("two"))
+	checkTreeString(t, "headMap(5) after put(2)", treeCall(treemapToString, head), "{1=1.0, 2=two, 3=3.0}")
+	treeCall(treemapPut, sub, treeInt(6), object.StringObjectFromGoString("six"))
+	checkTreeString(t, "get(6) after a put in the submap", treeCall(treemapGet, tm, treeInt(6)), "six")
+	treeCall(treeClear, head)
+	checkTreeString(t, "the map after clearing the head map", treeCall(treemapToString, tm),
+		"{5=5.0, 6=six, 7=7.0, 9=9.0}")
+
+	checkTreeError(t, "put() out of range", treeCall(treemapPut, sub, treeInt(3), object.Null),
+		excNames.IllegalArgumentException)
+	checkTreeError(t, "subMap(9, 1)", treeCall(treeSub, tm, treeInt(9), treeInt(1)), excNames.IllegalArgumentException)
+	checkTreeError(t, "headMap(10) of the submap", treeCall(treeHead, sub, treeInt(10)),
+		excNames.IllegalArgumentException)
+	if ret := treeCall(treeHead, sub, treeInt(9), types.JavaBoolTrue); ret == nil {
+		t.Errorf("headMap(9, true) of the submap: unexpected nil")
+	} else if _, ok := ret.(*GErrBlk); ok {
+		t.Errorf("headMap(9, true) of the submap: unexpected error %v", ret)
+	}
+}
+
+func TestTreeMapComparator(t *testing.T) {
+	calls := fakeSortUpcalls(t)
+	className := "pkg/Reverse"
+	reverse := object.MakeEmptyObjectWithClassName(&className)
+
+	tm := newTestTree(classNameTreeMap, reverse)
+	for _, key := range []int64{2, 8, 4} {
+		treeCall(treemapPut, tm, treeInt(key), treeInt(key*10))
+	}
+	checkTreeKey(t, "firstKey()", treeCall(treeFirstKey, tm), 8)
+	checkTreeKey(t, "ceilingKey(5)", treeCall(treemapCeilingKey, tm, treeInt(5)), 4)
+	if ret := treeCall(treeComparator, tm); ret != reverse {
+		t.Errorf("comparator(): expected the comparator, got %v", ret)
+	}
+	if *calls == 0 {
+		t.Errorf("expected the keys to be compared by the comparator")
+	}
+
+	// a copy of a TreeMap keeps its comparator
+	cp := newTestTree(classNameTreeMap, nil)
+	if ret := treeCall(treemapInitFromMap, cp, tm); ret != nil {
+		t.Fatalf("TreeMap(SortedMap): unexpected result %v", ret)
+	}
+	checkTreeString(t, "the copy", treeCall(treemapToString, cp), "{8=80, 4=40, 2=20}")
+}
+
+func TestTreeMapIterators(t *testing.T) {
+	globals.InitGlobals("test")
+	tm := newTestTreeMap(1, 2, 3)
+
+	entries := treeCall(treemapEntrySet, tm).(*object.Object)
+	checkTreeString(t, "entrySet()", treeCall(treeCollectionToString, entries), "[1=1.0, 2=2.0, 3=3.0]")
+	it := treeCall(treeCollectionIterator, entries).(*object.Object)
+	entry := treeIteratorNext([]interface{}{it}).(*object.Object)
+	mapEntrySetValue([]interface{}{entry, object.StringObjectFromGoString("one")})
+	checkTreeString(t, "get(1) after setValue()", treeCall(treemapGet, tm, treeInt(1)), "one")
+	treeIteratorNext([]interface{}{it})
+	if ret := treeIteratorRemove([]interface{}{it}); ret != nil {
+		t.Fatalf("remove(): unexpected result %v", ret)
+	}
+	checkTreeError(t, "a second remove()", treeIteratorRemove([]interface{}{it}), excNames.IllegalStateException)
+	if treeIteratorHasNext([]interface{}{it}) != types.JavaBoolTrue {
+		t.Fatalf("hasNext(): expected true after the removal")
+	}
+	checkTreeString(t, "the last value", mapEntryGetValue([]interface{}{treeIteratorNext([]interface{}{it})}), "3.0")
+	if treeIteratorHasNext([]interface{}{it}) != types.JavaBoolFalse {
+		t.Errorf("hasNext(): expected false at the end")
+	}
+	checkTreeError(t, "next() at the end", treeIteratorNext([]interface{}{it}), excNames.NoSuchElementException)
+
+	values := treeCall(treemapValues, tm).(*object.Object)
+	checkTreeString(t, "values()", treeCall(treeCollectionToString, values), "[one, 3.0]")
+	it = treeCall(treeCollectionIterator, values).(*object.Object)
+	treeCall(treemapPut, tm, treeInt(0), object.Null)
+	checkTreeError(t, "next() after a put()", treeIteratorNext([]interface{}{it}),
+		excNames.ConcurrentModificationException)
+
+	keys := treeCall(treemapKeySet, tm).(*object.Object)
+	checkTreeString(t, "keySet()", treeCall(treeCollectionToString, keys), "[0, 1, 3]")
+	checkTreeError(t, "add() to the key set", treeCall(treesetAdd, keys, treeInt(4)),
+		excNames.UnsupportedOperationException)
+	treeCall(treesetRemove, keys, treeInt(0))
+	checkTreeString(t, "the map after a removal from the key set", treeCall(treemapToString, tm), "{1=one, 3=3.0}")
+}
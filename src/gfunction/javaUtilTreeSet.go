@@ -0,0 +1,391 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/types"
+)
+
+// TreeSet is a TreeMap whose entries have no values; see javaUtilTreeMap.go. The key set
+// of a TreeMap is a TreeSet too, one to which keys can't be added.
+
+var classNameTreeSet = "java/util/TreeSet"
+
+func Load_Util_TreeSet() {
+
+	object.RegisterInstantiationHook(classNameTreeSet, newTreeObject)
+
+	MethodSignatures["java/util/TreeSet.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/TreeSet.<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  treeInit,
+		}
+
+	MethodSignatures["java/util/TreeSet.<init>(Ljava/util/Collection;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treesetInitFromCollection,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.<init>(Ljava/util/Comparator;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  treeInit,
+		}
+
+	MethodSignatures["java/util/TreeSet.<init>(Ljava/util/SortedSet;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treesetInitFromCollection,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.add(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treesetAdd,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.addAll(Ljava/util/Collection;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treesetAddAll,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.ceiling(Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treemapCeilingKey,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.clear()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treeClear,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.clone()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treeClone,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.comparator()Ljava/util/Comparator;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treeComparator,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.contains(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treeContains,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.descendingIterator()Ljava/util/Iterator;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treesetDescendingIterator,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.descendingSet()Ljava/util/NavigableSet;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["java/util/TreeSet.first()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treeFirstKey,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.floor(Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treemapFloorKey,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.headSet(Ljava/lang/Object;)Ljava/util/SortedSet;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treeHead,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.headSet(Ljava/lang/Object;Z)Ljava/util/NavigableSet;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    treeHead,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.higher(Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treemapHigherKey,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.isEmpty()Z"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treeIsEmpty,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.iterator()Ljava/util/Iterator;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treeCollectionIterator,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.last()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treeLastKey,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.lower(Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treemapLowerKey,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.pollFirst()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treesetPollFirst,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.pollLast()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treesetPollLast,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.remove(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treesetRemove,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.size()I"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treeSize,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.subSet(Ljava/lang/Object;Ljava/lang/Object;)Ljava/util/SortedSet;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    treeSub,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.subSet(Ljava/lang/Object;ZLjava/lang/Object;Z)Ljava/util/NavigableSet;"] =
+		GMeth{
+			ParamSlots:   4,
+			GFunction:    treeSub,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.tailSet(Ljava/lang/Object;)Ljava/util/SortedSet;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    treeTail,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.tailSet(Ljava/lang/Object;Z)Ljava/util/NavigableSet;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    treeTail,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.toArray()[Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treesetToArray,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/TreeSet.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    treeCollectionToString,
+			NeedsContext: true,
+		}
+}
+
+// "java/util/TreeSet.<init>(Ljava/util/Collection;)V" and "java/util/TreeSet.<init>(Ljava/util/SortedSet;)V"
+// The comparator of a TreeSet that is copied is kept.
+func treesetInitFromCollection(params []interface{}) interface{} {
+	obj, ok := params[1].(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return getGErrBlk(excNames.NullPointerException, "treesetInitFromCollection: the object is null")
+	}
+	source := treeKeyParam(params, 2)
+	if object.IsNull(source) {
+		return getGErrBlk(excNames.NullPointerException, "treesetInitFromCollection: the collection is null")
+	}
+	store := &treeStore{}
+	if sourceView, ok := source.FieldTable[fieldNameTreeState].Fvalue.(*treeView); ok {
+		store.comparator = sourceView.store.comparator
+	}
+	obj.FieldTable[fieldNameTreeState] = object.Field{Ftype: types.TreeState, Fvalue: &treeView{store: store}}
+	if ret := treesetAddAll(params); ret != types.JavaBoolTrue && ret != types.JavaBoolFalse {
+		return ret
+	}
+	return nil
+}
+
+// adds the key to the set. Returns whether it wasn't already there.
+func treesetAddKey(fs *list.List, view *treeView, key *object.Object) (bool, *GErrBlk) {
+	if view.keysOnly {
+		return false, getGErrBlk(excNames.UnsupportedOperationException, "treesetAdd: cannot add to the key set of a map")
+	}
+	_, found, gErr := view.put(fs, "treesetAdd", key, nil)
+	return !found, gErr
+}
+
+// "java/util/TreeSet.add(Ljava/lang/Object;)Z"
+func treesetAdd(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treesetAdd", params)
+	if gErr != nil {
+		return gErr
+	}
+	added, gErr := treesetAddKey(fs, view, treeKeyParam(params, 2))
+	if gErr != nil {
+		return gErr
+	}
+	return types.ConvertGoBoolToJavaBool(added)
+}
+
+// "java/util/TreeSet.addAll(Ljava/util/Collection;)Z"
+func treesetAddAll(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treesetAddAll", params)
+	if gErr != nil {
+		return gErr
+	}
+	source := treeKeyParam(params, 2)
+	if object.IsNull(source) {
+		return getGErrBlk(excNames.NullPointerException, "treesetAddAll: the collection is null")
+	}
+
+	keys, gErr := collectionElements(fs, source)
+	if gErr != nil {
+		return gErr
+	}
+
+	changed := false
+	for _, key := range keys {
+		added, gErr := treesetAddKey(fs, view, key)
+		if gErr != nil {
+			return gErr
+		}
+		changed = changed || added
+	}
+	return types.ConvertGoBoolToJavaBool(changed)
+}
+
+// "java/util/TreeSet.remove(Ljava/lang/Object;)Z"
+func treesetRemove(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treesetRemove", params)
+	if gErr != nil {
+		return gErr
+	}
+	index, found, gErr := view.find(fs, "treesetRemove", treeKeyParam(params, 2))
+	if gErr != nil {
+		return gErr
+	}
+	if found {
+		view.removeAt(index)
+	}
+	return types.ConvertGoBoolToJavaBool(found)
+}
+
+// removes and returns the first or the last key, or returns null if the set is empty
+func treesetPoll(funcName string, params []interface{}, last bool) interface{} {
+	fs, view, gErr := getTreeView(funcName, params)
+	if gErr != nil {
+		return gErr
+	}
+	index, gErr := view.end(fs, last)
+	if gErr != nil {
+		return gErr
+	}
+	if index < 0 {
+		return object.Null
+	}
+	return view.removeAt(index).key
+}
+
+// "java/util/TreeSet.pollFirst()Ljava/lang/Object;"
+func treesetPollFirst(params []interface{}) interface{} {
+	return treesetPoll("treesetPollFirst", params, false)
+}
+
+// "java/util/TreeSet.pollLast()Ljava/lang/Object;"
+func treesetPollLast(params []interface{}) interface{} {
+	return treesetPoll("treesetPollLast", params, true)
+}
+
+// "java/util/TreeSet.descendingIterator()Ljava/util/Iterator;"
+func treesetDescendingIterator(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treesetDescendingIterator", params)
+	if gErr != nil {
+		return gErr
+	}
+	return newTreeIterator(fs, view, mapIterateKeys, true)
+}
+
+// "java/util/TreeSet.toArray()[Ljava/lang/Object;" -- the keys in ascending order
+func treesetToArray(params []interface{}) interface{} {
+	fs, view, gErr := getTreeView("treesetToArray", params)
+	if gErr != nil {
+		return gErr
+	}
+	from, to, gErr := view.span(fs)
+	if gErr != nil {
+		return gErr
+	}
+	arr := object.Make1DimRefArray("java/lang/Object;", int64(to-from))
+	keys := arr.FieldTable["value"].Fvalue.([]*object.Object)
+	for i, entry := range view.store.entries[from:to] {
+		keys[i] = entry.key
+	}
+	return arr
+}
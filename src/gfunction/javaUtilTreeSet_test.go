@@ -0,0 +1,102 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"testing"
+)
+
+func newTestTreeSet(keys ...int64) *object.Object {
+	ts := newTestTree(classNameTreeSet, nil)
+	for _, key := range keys {
+		treeCall(treesetAdd, ts, treeInt(key))
+	}
+	return ts
+}
+
+func TestTreeSetAddAndNavigation(t *testing.T) {
+	globals.InitGlobals("test")
+	ts := newTestTreeSet(4, 2, 8, 6)
+
+	if ret := treeCall(treesetAdd, ts, treeInt(4)); ret != types.JavaBoolFalse {
+		t.Errorf("add() of a duplicate: expected false, got %v", ret)
+	}
+	checkTreeString(t, "toString()", treeCall(treeCollectionToString, ts), "[2, 4, 6, 8]")
+	checkTreeKey(t, "first()", treeCall(treeFirstKey, ts), 2)
+	checkTreeKey(t, "last()", treeCall(treeLastKey, ts), 8)
+	checkTreeKey(t, "ceiling(5)", treeCall(treemapCeilingKey, ts, treeInt(5)), 6)
+	checkTreeKey(t, "floor(5)", treeCall(treemapFloorKey, ts, treeInt(5)), 4)
+	checkTreeKey(t, "higher(6)", treeCall(treemapHigherKey, ts, treeInt(6)), 8)
+	checkTreeKey(t, "lower(3)", treeCall(treemapLowerKey, ts, treeInt(3)), 2)
+	if ret := treeCall(treemapCeilingKey, ts, treeInt(9)); !object.IsNull(ret) {
+		t.Errorf("ceiling(9): expected null, got %v", ret)
+	}
+
+	checkTreeKey(t, "pollFirst()", treeCall(treesetPollFirst, ts), 2)
+	checkTreeKey(t, "pollLast()", treeCall(treesetPollLast, ts), 8)
+	if ret := treeCall(treesetRemove, ts, treeInt(5)); ret != types.JavaBoolFalse {
+		t.Errorf("remove(5): expected false, got %v", ret)
+	}
+	if ret := treeCall(treeContains, ts, treeInt(6)); ret != types.JavaBoolTrue {
+		t.Errorf("contains(6): expected true, got %v", ret)
+	}
+	arr := treeCall(treesetToArray, ts).(*object.Object)
+	if elems := arr.FieldTable["value"].Fvalue.([]*object.Object); len(elems) != 2 {
+		t.Errorf("toArray(): expected 2 elements, got %d", len(elems))
+	}
+
+	treeCall(treeClear, ts)
+	checkTreeError(t, "first() of an empty set", treeCall(treeFirstKey, ts), excNames.NoSuchElementException)
+	if ret := treeCall(treesetPollFirst, ts); !object.IsNull(ret) {
+		t.Errorf("pollFirst() of an empty set: expected null, got %v", ret)
+	}
+	checkTreeError(t, "add(null)", treeCall(treesetAdd, ts, object.Null), excNames.NullPointerException)
+}
+
+func TestTreeSetViewsAndIterators(t *testing.T) {
+	globals.InitGlobals("test")
+	ts := newTestTreeSet(10, 20, 30, 40, 50)
+
+	head := treeCall(treeHead, ts, treeInt(30), types.JavaBoolTrue).(*object.Object)
+	checkTreeString(t, "headSet(30, true)", treeCall(treeCollectionToString, head), "[10, 20, 30]")
+	sub := treeCall(treeSub, ts, treeInt(20), treeInt(40)).(*object.Object)
+	checkTreeString(t, "subSet(20, 40)", treeCall(treeCollectionToString, sub), "[20, 30]")
+	tail := treeCall(treeTail, ts, treeInt(35)).(*object.Object)
+	checkTreeString(t, "tailSet(35)", treeCall(treeCollectionToString, tail), "[40, 50]")
+
+	treeCall(treesetAdd, sub, treeInt(25))
+	checkTreeString(t, "the set after an add() to the subset", treeCall(treeCollectionToString, ts),
+		"[10, 20, 25, 30, 40, 50]")
+	checkTreeError(t, "add() out of the subset", treeCall(treesetAdd, sub, treeInt(40)),
+		excNames.IllegalArgumentException)
+
+	clone := treeCall(treeClone, sub).(*object.Object)
+	treeCall(treesetAdd, clone, treeInt(99))
+	checkTreeString(t, "clone()", treeCall(treeCollectionToString, clone), "[20, 25, 30, 99]")
+	if ret := treeCall(treeContains, ts, treeInt(99)); ret != types.JavaBoolFalse {
+		t.Errorf("the clone shares the entries of the set")
+	}
+
+	it := treeCall(treesetDescendingIterator, ts).(*object.Object)
+	var got []int64
+	for treeIteratorHasNext([]interface{}{it}) == types.JavaBoolTrue {
+		key := treeIteratorNext([]interface{}{it}).(*object.Object).FieldTable["value"].Fvalue.(int64)
+		if key == 30 {
+			treeIteratorRemove([]interface{}{it})
+		}
+		got = append(got, key)
+	}
+	if len(got) != 6 || got[0] != 50 || got[5] != 10 {
+		t.Errorf("descendingIterator(): unexpected keys %v", got)
+	}
+	checkTreeString(t, "the set after a removal by the iterator", treeCall(treeCollectionToString, ts),
+		"[10, 20, 25, 40, 50]")
+}
@@ -0,0 +1,355 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"strconv"
+	"strings"
+)
+
+// A UUID is held, as in the JDK, as two longs: its most and least significant 64 bits.
+// Random UUIDs are generated with crypto/rand.
+
+func Load_Util_UUID() {
+
+	MethodSignatures["java/util/UUID.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/UUID.<init>(JJ)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  uuidInit,
+		}
+
+	MethodSignatures["java/util/UUID.compareTo(Ljava/lang/Object;)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  uuidCompareTo,
+		}
+
+	MethodSignatures["java/util/UUID.compareTo(Ljava/util/UUID;)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  uuidCompareTo,
+		}
+
+	MethodSignatures["java/util/UUID.equals(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  uuidEquals,
+		}
+
+	MethodSignatures["java/util/UUID.fromString(Ljava/lang/String;)Ljava/util/UUID;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  uuidFromString,
+		}
+
+	MethodSignatures["java/util/UUID.getLeastSignificantBits()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  uuidGetLeastSignificantBits,
+		}
+
+	MethodSignatures["java/util/UUID.getMostSignificantBits()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  uuidGetMostSignificantBits,
+		}
+
+	MethodSignatures["java/util/UUID.hashCode()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  uuidHashCode,
+		}
+
+	MethodSignatures["java/util/UUID.nameUUIDFromBytes([B)Ljava/util/UUID;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  uuidNameUUIDFromBytes,
+		}
+
+	MethodSignatures["java/util/UUID.randomUUID()Ljava/util/UUID;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  uuidRandomUUID,
+		}
+
+	MethodSignatures["java/util/UUID.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  uuidToString,
+		}
+
+	MethodSignatures["java/util/UUID.variant()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  uuidVariant,
+		}
+
+	MethodSignatures["java/util/UUID.version()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  uuidVersion,
+		}
+
+	// -----------------------------------------
+	// Traps that do nothing but return an error
+	// -----------------------------------------
+
+	MethodSignatures["java/util/UUID.clockSequence()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["java/util/UUID.node()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["java/util/UUID.timestamp()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  trapFunction,
+		}
+
+}
+
+var classNameUUID = "java/util/UUID"
+
+// The UUID fields, named as in the JDK
+var fieldNameUUIDMostSigBits = "mostSigBits"
+var fieldNameUUIDLeastSigBits = "leastSigBits"
+
+// makeUUID returns a UUID object holding the two halves of a UUID
+func makeUUID(mostSigBits, leastSigBits int64) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&classNameUUID)
+	obj.FieldTable[fieldNameUUIDMostSigBits] = object.Field{Ftype: types.Long, Fvalue: mostSigBits}
+	obj.FieldTable[fieldNameUUIDLeastSigBits] = object.Field{Ftype: types.Long, Fvalue: leastSigBits}
+	return obj
+}
+
+// makeUUIDFromBytes returns a UUID object from 16 bytes, after setting their version and
+// variant bits to the version given and to the IETF variant
+func makeUUIDFromBytes(b []byte, version byte) *object.Object {
+	b[6] = (b[6] & 0x0f) | (version << 4)
+	b[8] = (b[8] & 0x3f) | 0x80
+	return makeUUID(int64(binary.BigEndian.Uint64(b[:8])), int64(binary.BigEndian.Uint64(b[8:16])))
+}
+
+// getUUIDBits returns the two halves of the UUID object params[index]
+func getUUIDBits(funcName string, params []interface{}, index int) (int64, int64, *GErrBlk) {
+	obj, err := args.GetObject(params, index)
+	if err != nil {
+		return 0, 0, getArgsGErrBlk(funcName, err)
+	}
+	msb, ok1 := obj.FieldTable[fieldNameUUIDMostSigBits].Fvalue.(int64)
+	lsb, ok2 := obj.FieldTable[fieldNameUUIDLeastSigBits].Fvalue.(int64)
+	if !ok1 || !ok2 {
+		errMsg := fmt.Sprintf("%s: parameter %d is not a UUID", funcName, index)
+		return 0, 0, getGErrBlk(excNames.ClassCastException, errMsg)
+	}
+	return msb, lsb, nil
+}
+
+// "java/util/UUID.<init>(JJ)V"
+func uuidInit(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("uuidInit", err)
+	}
+	msb, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("uuidInit", err)
+	}
+	lsb, err := args.GetInt64(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("uuidInit", err)
+	}
+	obj.FieldTable[fieldNameUUIDMostSigBits] = object.Field{Ftype: types.Long, Fvalue: msb}
+	obj.FieldTable[fieldNameUUIDLeastSigBits] = object.Field{Ftype: types.Long, Fvalue: lsb}
+	return nil
+}
+
+// "java/util/UUID.randomUUID()Ljava/util/UUID;"
+// Returns a version 4 (random) UUID.
+func uuidRandomUUID(params []interface{}) interface{} {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		errMsg := fmt.Sprintf("uuidRandomUUID: %s", err.Error())
+		return getGErrBlk(excNames.InternalException, errMsg)
+	}
+	return makeUUIDFromBytes(b, 4)
+}
+
+// "java/util/UUID.nameUUIDFromBytes([B)Ljava/util/UUID;"
+// Returns a version 3 (name-based) UUID, which is the MD5 hash of the bytes.
+func uuidNameUUIDFromBytes(params []interface{}) interface{} {
+	if object.IsNull(params[0]) {
+		return getGErrBlk(excNames.NullPointerException, "uuidNameUUIDFromBytes: name is null")
+	}
+	name, gerr := getByteArrayRange("uuidNameUUIDFromBytes", params, 0)
+	if gerr != nil {
+		return gerr
+	}
+	hash := md5.Sum(name)
+	return makeUUIDFromBytes(hash[:], 3)
+}
+
+// "java/util/UUID.fromString(Ljava/lang/String;)Ljava/util/UUID;"
+// As in the JDK, each of the five hex components can be shorter than usual, so that
+// "1-2-3-4-5" is accepted, but the string can't be longer than 36 characters.
+func uuidFromString(params []interface{}) interface{} {
+	if object.IsNull(params[0]) {
+		return getGErrBlk(excNames.NullPointerException, "uuidFromString: name is null")
+	}
+	name, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("uuidFromString", err)
+	}
+	if len(name) > 36 {
+		return getGErrBlk(excNames.IllegalArgumentException, "UUID string too large")
+	}
+
+	components := strings.Split(name, "-")
+	if len(components) != 5 {
+		return getGErrBlk(excNames.IllegalArgumentException, "Invalid UUID string: "+name)
+	}
+	var values [5]int64
+	for i, component := range components {
+		value, err := strconv.ParseInt(component, 16, 64)
+		if err != nil {
+			errMsg := fmt.Sprintf("For input string: \"%s\" under radix 16", component)
+			return getGErrBlk(excNames.NumberFormatException, errMsg)
+		}
+		values[i] = value
+	}
+
+	msb := (values[0]&0xffffffff)<<32 | (values[1]&0xffff)<<16 | values[2]&0xffff
+	lsb := (values[3]&0xffff)<<48 | values[4]&0xffffffffffff
+	return makeUUID(msb, lsb)
+}
+
+// "java/util/UUID.toString()Ljava/lang/String;"
+// Returns the UUID in the canonical form, e.g. 123e4567-e89b-12d3-a456-426614174000
+func uuidToString(params []interface{}) interface{} {
+	msb, lsb, gerr := getUUIDBits("uuidToString", params, 0)
+	if gerr != nil {
+		return gerr
+	}
+	m, l := uint64(msb), uint64(lsb)
+	str := fmt.Sprintf("%08x-%04x-%04x-%04x-%012x", m>>32, (m>>16)&0xffff, m&0xffff, l>>48, l&0xffffffffffff)
+	return object.StringObjectFromGoString(str)
+}
+
+// "java/util/UUID.getMostSignificantBits()J"
+func uuidGetMostSignificantBits(params []interface{}) interface{} {
+	msb, _, gerr := getUUIDBits("uuidGetMostSignificantBits", params, 0)
+	if gerr != nil {
+		return gerr
+	}
+	return msb
+}
+
+// "java/util/UUID.getLeastSignificantBits()J"
+func uuidGetLeastSignificantBits(params []interface{}) interface{} {
+	_, lsb, gerr := getUUIDBits("uuidGetLeastSignificantBits", params, 0)
+	if gerr != nil {
+		return gerr
+	}
+	return lsb
+}
+
+// "java/util/UUID.version()I"
+func uuidVersion(params []interface{}) interface{} {
+	msb, _, gerr := getUUIDBits("uuidVersion", params, 0)
+	if gerr != nil {
+		return gerr
+	}
+	return (msb >> 12) & 0x0f
+}
+
+// "java/util/UUID.variant()I"
+// Returns 0 (NCS), 2 (IETF), 6 (Microsoft), or 7 (reserved), computed as in the JDK.
+func uuidVariant(params []interface{}) interface{} {
+	_, lsb, gerr := getUUIDBits("uuidVariant", params, 0)
+	if gerr != nil {
+		return gerr
+	}
+	return int64(uint64(lsb)>>(64-uint64(lsb)>>62)) & (lsb >> 63)
+}
+
+// "java/util/UUID.hashCode()I"
+func uuidHashCode(params []interface{}) interface{} {
+	msb, lsb, gerr := getUUIDBits("uuidHashCode", params, 0)
+	if gerr != nil {
+		return gerr
+	}
+	hilo := msb ^ lsb
+	return int64(int32(hilo>>32) ^ int32(hilo))
+}
+
+// "java/util/UUID.equals(Ljava/lang/Object;)Z"
+func uuidEquals(params []interface{}) interface{} {
+	msb, lsb, gerr := getUUIDBits("uuidEquals", params, 0)
+	if gerr != nil {
+		return gerr
+	}
+	if object.IsNull(params[1]) {
+		return types.JavaBoolFalse
+	}
+	that, ok := params[1].(*object.Object)
+	if !ok || object.GoStringFromStringPoolIndex(that.KlassName) != classNameUUID {
+		return types.JavaBoolFalse
+	}
+	thatMsb, thatLsb, gerr := getUUIDBits("uuidEquals", params, 1)
+	if gerr != nil {
+		return types.JavaBoolFalse
+	}
+	return object.JavaBooleanFromGoBoolean(msb == thatMsb && lsb == thatLsb)
+}
+
+// "java/util/UUID.compareTo(Ljava/util/UUID;)I"
+// As in the JDK, the halves are compared as signed values.
+func uuidCompareTo(params []interface{}) interface{} {
+	msb, lsb, gerr := getUUIDBits("uuidCompareTo", params, 0)
+	if gerr != nil {
+		return gerr
+	}
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "uuidCompareTo: UUID is null")
+	}
+	thatMsb, thatLsb, gerr := getUUIDBits("uuidCompareTo", params, 1)
+	if gerr != nil {
+		return gerr
+	}
+
+	switch {
+	case msb < thatMsb:
+		return int64(-1)
+	case msb > thatMsb:
+		return int64(1)
+	case lsb < thatLsb:
+		return int64(-1)
+	case lsb > thatLsb:
+		return int64(1)
+	}
+	return int64(0)
+}
@@ -0,0 +1,130 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"crypto/md5"
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"regexp"
+	"testing"
+)
+
+// returns the Go string of the UUID's toString()
+func uuidGoString(uuid interface{}) string {
+	return object.GoStringFromStringObject(uuidToString([]interface{}{uuid}).(*object.Object))
+}
+
+func TestUUIDFromStringAndToString(t *testing.T) {
+	globals.InitStringPool()
+
+	str := "123e4567-e89b-12d3-a456-426614174000"
+	uuid := uuidFromString([]interface{}{object.StringObjectFromGoString(str)})
+	if got := uuidGoString(uuid); got != str {
+		t.Errorf("toString = %q, want %q", got, str)
+	}
+	if msb := uuidGetMostSignificantBits([]interface{}{uuid}).(int64); msb != 0x123e4567e89b12d3 {
+		t.Errorf("getMostSignificantBits = %x", msb)
+	}
+	if lsb := uuidGetLeastSignificantBits([]interface{}{uuid}).(int64); uint64(lsb) != 0xa456426614174000 {
+		t.Errorf("getLeastSignificantBits = %x", uint64(lsb))
+	}
+	if version := uuidVersion([]interface{}{uuid}).(int64); version != 1 {
+		t.Errorf("version = %d, want 1", version)
+	}
+	if variant := uuidVariant([]interface{}{uuid}).(int64); variant != 2 {
+		t.Errorf("variant = %d, want 2", variant)
+	}
+
+	// short components are accepted, as in the JDK
+	uuid = uuidFromString([]interface{}{object.StringObjectFromGoString("1-2-3-4-5")})
+	if got := uuidGoString(uuid); got != "00000001-0002-0003-0004-000000000005" {
+		t.Errorf("toString of 1-2-3-4-5 = %q", got)
+	}
+
+	bad := []struct {
+		str  string
+		want int
+	}{
+		{"123e4567-e89b-12d3-a456", excNames.IllegalArgumentException},
+		{"123e4567-e89b-12d3-a456-4266141740000000", excNames.IllegalArgumentException},
+		{"123e4567-e89b-12d3-a456-42661417400z", excNames.NumberFormatException},
+	}
+	for _, tt := range bad {
+		ret := uuidFromString([]interface{}{object.StringObjectFromGoString(tt.str)})
+		if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != tt.want {
+			t.Errorf("fromString(%q): expected %s, got %v", tt.str, excNames.JVMexceptionNames[tt.want], ret)
+		}
+	}
+}
+
+func TestUUIDRandomAndNameBased(t *testing.T) {
+	globals.InitStringPool()
+
+	canonical := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	first := uuidRandomUUID(nil)
+	second := uuidRandomUUID(nil)
+	if str := uuidGoString(first); !canonical.MatchString(str) {
+		t.Errorf("randomUUID %q is not a version 4 UUID", str)
+	}
+	if uuidEquals([]interface{}{first, second}) != types.JavaBoolFalse {
+		t.Errorf("two random UUIDs are equal")
+	}
+
+	// the MD5 hash of the name, with the version and variant bits set
+	hash := md5.Sum([]byte("jacobin"))
+	hash[6] = hash[6]&0x0f | 0x30
+	hash[8] = hash[8]&0x3f | 0x80
+	want := fmt.Sprintf("%x-%x-%x-%x-%x", hash[0:4], hash[4:6], hash[6:8], hash[8:10], hash[10:16])
+	uuid := uuidNameUUIDFromBytes([]interface{}{newZipByteArray([]byte("jacobin"))})
+	if got := uuidGoString(uuid); got != want {
+		t.Errorf("nameUUIDFromBytes = %q, want %q", got, want)
+	}
+}
+
+func TestUUIDEqualsHashCodeCompareTo(t *testing.T) {
+	globals.InitStringPool()
+
+	a := makeUUID(1, 2)
+	b := newZipObject(classNameUUID)
+	if ret := uuidInit([]interface{}{b, int64(1), int64(2)}); ret != nil {
+		t.Fatalf("<init>(JJ): %v", ret)
+	}
+	c := makeUUID(-1, 2)
+
+	if uuidEquals([]interface{}{a, b}) != types.JavaBoolTrue {
+		t.Errorf("equal UUIDs are not equal")
+	}
+	if uuidEquals([]interface{}{a, c}) != types.JavaBoolFalse {
+		t.Errorf("different UUIDs are equal")
+	}
+	if uuidEquals([]interface{}{a, object.Null}) != types.JavaBoolFalse {
+		t.Errorf("a UUID equals null")
+	}
+	if uuidEquals([]interface{}{a, object.StringObjectFromGoString("x")}) != types.JavaBoolFalse {
+		t.Errorf("a UUID equals a String")
+	}
+
+	// (int)((msb ^ lsb) >> 32) ^ (int)(msb ^ lsb) = 0 ^ 3
+	if hash := uuidHashCode([]interface{}{a}).(int64); hash != 3 {
+		t.Errorf("hashCode = %d, want 3", hash)
+	}
+
+	// the halves are compared as signed longs
+	if cmp := uuidCompareTo([]interface{}{a, c}).(int64); cmp != 1 {
+		t.Errorf("compareTo = %d, want 1", cmp)
+	}
+	if cmp := uuidCompareTo([]interface{}{c, a}).(int64); cmp != -1 {
+		t.Errorf("compareTo = %d, want -1", cmp)
+	}
+	if cmp := uuidCompareTo([]interface{}{a, b}).(int64); cmp != 0 {
+		t.Errorf("compareTo = %d, want 0", cmp)
+	}
+}
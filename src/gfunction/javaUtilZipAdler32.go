@@ -37,6 +37,12 @@ func Load_Util_Zip_Adler32() {
 			GFunction:  adlerReset,
 		}
 
+	MethodSignatures["java/util/zip/Adler32.update([B)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  adlerUpdateFromArray,
+		}
+
 	MethodSignatures["java/util/zip/Adler32.update([BII)V"] =
 		GMeth{
 			ParamSlots: 3,
@@ -84,15 +90,15 @@ func adlerReset(params []interface{}) interface{} {
 	return nil
 }
 
-// Update the current Adler32 value from an array of bytes.
+// Update the current Adler32 value from an array of bytes, or from the range of them
+// given by an offset and a length.
 func adlerUpdateFromArray(params []interface{}) interface{} {
 	// Collect parameters.
 	obj := params[0].(*object.Object)
-	objBB := params[1].(*object.Object)
-	bbWhole := objBB.FieldTable["value"].Fvalue.([]types.JavaByte)
-	offset := params[2].(int64)
-	length := params[3].(int64)
-	bbSubset := bbWhole[offset:length]
+	bbSubset, gerr := getByteArrayRange("adlerUpdateFromArray", params, 1)
+	if gerr != nil {
+		return gerr
+	}
 
 	// Get current Adler32 value.
 	fld := obj.FieldTable["value"]
@@ -100,8 +106,7 @@ func adlerUpdateFromArray(params []interface{}) interface{} {
 	initialChecksum := uint32(value)
 
 	// Compute new checksum and store it back.
-	fld.Fvalue = int64(updateAdler32(initialChecksum,
-		object.GoByteArrayFromJavaByteArray(bbSubset)))
+	fld.Fvalue = int64(updateAdler32(initialChecksum, bbSubset))
 	obj.FieldTable["value"] = fld
 
 	return nil
@@ -68,6 +68,18 @@ func Load_Util_Zip_Crc32_Crc32c() {
 			GFunction:  crc32Reset,
 		}
 
+	MethodSignatures["java/util/zip/CRC32.update([B)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  crc32UpdateFromArray,
+		}
+
+	MethodSignatures["java/util/zip/CRC32C.update([B)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  crc32UpdateFromArray,
+		}
+
 	MethodSignatures["java/util/zip/CRC32.update([BII)V"] =
 		GMeth{
 			ParamSlots: 3,
@@ -146,15 +158,15 @@ func crc32Reset(params []interface{}) interface{} {
 	return nil
 }
 
-// Update the current CRC32 value from an array of bytes.
+// Update the current CRC32 value from an array of bytes, or from the range of them
+// given by an offset and a length.
 func crc32UpdateFromArray(params []interface{}) interface{} {
 	// Collect parameters.
 	obj := params[0].(*object.Object)
-	objBB := params[1].(*object.Object)
-	bbWhole := objBB.FieldTable["value"].Fvalue.([]types.JavaByte)
-	offset := params[2].(int64)
-	length := params[3].(int64)
-	bbSubset := bbWhole[offset:length]
+	bbSubset, gerr := getByteArrayRange("crc32UpdateFromArray", params, 1)
+	if gerr != nil {
+		return gerr
+	}
 
 	// Get current CRC32 value.
 	fldValue := obj.FieldTable["value"]
@@ -166,8 +178,7 @@ func crc32UpdateFromArray(params []interface{}) interface{} {
 	valuePoly := uint32(fldPoly.Fvalue.(int64))
 
 	// Compute new checksum and store it back.
-	fldValue.Fvalue = int64(updateCRC32(initialChecksum,
-		object.GoByteArrayFromJavaByteArray(bbSubset), valuePoly))
+	fldValue.Fvalue = int64(updateCRC32(initialChecksum, bbSubset, valuePoly))
 	obj.FieldTable["value"] = fldValue
 
 	return nil
@@ -87,10 +87,31 @@ func TestCRC32C_KnownVector_And_Subrange(t *testing.T) {
     all := []byte("abcdef")
     sub := all[1:4]
     arr2 := makeZipByteArray(all)
-    _ = crc32UpdateFromArray([]interface{}{c, arr2, int64(1), int64(4)})
+    _ = crc32UpdateFromArray([]interface{}{c, arr2, int64(1), int64(3)})
     got2 := uint32(crc32GetValue([]interface{}{c}).(int64))
     want2 := crc32.Update(0, tableC, sub)
     if got2 != want2 {
         t.Fatalf("CRC32C subrange mismatch: want 0x%08x got 0x%08x", want2, got2)
     }
 }
+
+func TestCRC32_UpdateWholeArray_And_BadRange(t *testing.T) {
+    globals.InitStringPool()
+
+    data := []byte("123456789")
+    c := newCRC32Obj()
+    _ = crc32InitIEEE([]interface{}{c})
+
+    // update([B) checksums the whole array
+    _ = crc32UpdateFromArray([]interface{}{c, makeZipByteArray(data)})
+    got := uint32(crc32GetValue([]interface{}{c}).(int64))
+    if want := crc32.ChecksumIEEE(data); got != want {
+        t.Fatalf("CRC32 update([B) mismatch: want 0x%08x got 0x%08x", want, got)
+    }
+
+    // update([BII) with a range past the end of the array
+    ret := crc32UpdateFromArray([]interface{}{c, makeZipByteArray(data), int64(5), int64(5)})
+    if _, ok := ret.(*GErrBlk); !ok {
+        t.Fatalf("expected an error for an out-of-bounds range, got %v", ret)
+    }
+}
@@ -0,0 +1,521 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
+	"jacobin/src/object"
+	"jacobin/src/types"
+)
+
+// Deflater compresses with Go's compress/flate: in the ZLIB format, or in the raw
+// DEFLATE format if the nowrap constructor parameter is true. The state of the
+// compression is kept in a deflater struct in the Deflater object.
+
+func Load_Util_Zip_Deflater() {
+
+	MethodSignatures["java/util/zip/Deflater.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  deflaterInit,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.<init>(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  deflaterInit,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.<init>(IZ)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  deflaterInit,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.deflate([B)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  deflaterDeflate,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.deflate([BII)I"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  deflaterDeflate,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.deflate([BIII)I"] =
+		GMeth{
+			ParamSlots: 4,
+			GFunction:  deflaterDeflate,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.end()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  zipEnd,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.finish()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  deflaterFinish,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.finished()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  deflaterFinished,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.getBytesRead()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  deflaterGetBytesRead,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.getBytesWritten()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  deflaterGetBytesWritten,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.getTotalIn()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  deflaterGetTotalIn,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.getTotalOut()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  deflaterGetTotalOut,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.needsInput()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  deflaterNeedsInput,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.reset()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  deflaterReset,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.setInput([B)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  deflaterSetInput,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.setInput([BII)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  deflaterSetInput,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.setLevel(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  deflaterSetLevel,
+		}
+
+	// -----------------------------------------
+	// Traps that do nothing but return an error
+	// -----------------------------------------
+
+	MethodSignatures["java/util/zip/Deflater.deflate(Ljava/nio/ByteBuffer;)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.setDictionary([B)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["java/util/zip/Deflater.setInput(Ljava/nio/ByteBuffer;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  trapFunction,
+		}
+
+}
+
+// The field of the java/util/zip objects that holds their Golang state
+var fieldNameZipState = "zipState"
+
+// Deflater constants
+const (
+	deflaterDefaultCompression = -1
+	deflaterNoFlush            = 0
+	deflaterSyncFlush          = 2
+	deflaterFullFlush          = 3
+)
+
+// compressor is what *flate.Writer and *zlib.Writer have in common
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// deflater is the state of a Deflater
+type deflater struct {
+	level        int
+	nowrap       bool
+	input        []byte       // input not yet passed to the compressor
+	output       bytes.Buffer // compressed bytes not yet returned by deflate()
+	compressor   compressor
+	finish       bool  // finish() has been called
+	closed       bool  // the compressor has written all its output
+	bytesRead    int64 // uncompressed bytes passed to the compressor
+	bytesWritten int64 // compressed bytes returned by deflate()
+}
+
+// passes the pending input to the compressor, which is created on first use, and
+// closes the compressor if finish() has been called
+func (d *deflater) compress(flush int64) error {
+	var err error
+	if d.compressor == nil {
+		if d.nowrap {
+			d.compressor, err = flate.NewWriter(&d.output, d.level)
+		} else {
+			d.compressor, err = zlib.NewWriterLevel(&d.output, d.level)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(d.input) > 0 {
+		if _, err = d.compressor.Write(d.input); err != nil {
+			return err
+		}
+		d.bytesRead += int64(len(d.input))
+		d.input = nil
+	}
+
+	switch {
+	case d.finish && !d.closed:
+		d.closed = true
+		return d.compressor.Close()
+	case flush == deflaterSyncFlush || flush == deflaterFullFlush:
+		return d.compressor.Flush()
+	}
+	return nil
+}
+
+// returns the deflater in the Deflater object params[0]
+func getDeflater(funcName string, params []interface{}) (*deflater, *GErrBlk) {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return nil, getArgsGErrBlk(funcName, err)
+	}
+	d, ok := obj.FieldTable[fieldNameZipState].Fvalue.(*deflater)
+	if !ok {
+		return nil, getGErrBlk(excNames.NullPointerException, funcName+": Deflater has been closed")
+	}
+	return d, nil
+}
+
+// "java/util/zip/Deflater.<init>()V", "java/util/zip/Deflater.<init>(I)V", and
+// "java/util/zip/Deflater.<init>(IZ)V"
+func deflaterInit(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("deflaterInit", err)
+	}
+
+	d := &deflater{level: deflaterDefaultCompression}
+	if len(params) > 1 {
+		level, err := args.GetInt64(params, 1)
+		if err != nil {
+			return getArgsGErrBlk("deflaterInit", err)
+		}
+		if level < deflaterDefaultCompression || level > flate.BestCompression {
+			return getGErrBlk(excNames.IllegalArgumentException, "deflaterInit: invalid compression level")
+		}
+		d.level = int(level)
+	}
+	if len(params) > 2 {
+		if d.nowrap, err = args.GetBoolean(params, 2); err != nil {
+			return getArgsGErrBlk("deflaterInit", err)
+		}
+	}
+
+	obj.FieldTable[fieldNameZipState] = object.Field{Ftype: types.ZipState, Fvalue: d}
+	return nil
+}
+
+// "java/util/zip/Deflater.setInput([B)V" and "java/util/zip/Deflater.setInput([BII)V"
+func deflaterSetInput(params []interface{}) interface{} {
+	d, gerr := getDeflater("deflaterSetInput", params)
+	if gerr != nil {
+		return gerr
+	}
+	input, gerr := getByteArrayRange("deflaterSetInput", params, 1)
+	if gerr != nil {
+		return gerr
+	}
+	d.input = append(d.input, input...)
+	return nil
+}
+
+// "java/util/zip/Deflater.setLevel(I)V"
+// Go can't change the level of a stream that's under way, so the level applies only
+// if it's set before any input is compressed.
+func deflaterSetLevel(params []interface{}) interface{} {
+	d, gerr := getDeflater("deflaterSetLevel", params)
+	if gerr != nil {
+		return gerr
+	}
+	level, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("deflaterSetLevel", err)
+	}
+	if level < deflaterDefaultCompression || level > flate.BestCompression {
+		return getGErrBlk(excNames.IllegalArgumentException, "deflaterSetLevel: invalid compression level")
+	}
+	if d.compressor == nil {
+		d.level = int(level)
+	}
+	return nil
+}
+
+// "java/util/zip/Deflater.finish()V"
+func deflaterFinish(params []interface{}) interface{} {
+	d, gerr := getDeflater("deflaterFinish", params)
+	if gerr != nil {
+		return gerr
+	}
+	d.finish = true
+	return nil
+}
+
+// "java/util/zip/Deflater.deflate([B)I", "java/util/zip/Deflater.deflate([BII)I", and
+// "java/util/zip/Deflater.deflate([BIII)I"
+// Returns the number of compressed bytes written to the array.
+func deflaterDeflate(params []interface{}) interface{} {
+	d, gerr := getDeflater("deflaterDeflate", params)
+	if gerr != nil {
+		return gerr
+	}
+
+	flush := int64(deflaterNoFlush)
+	if len(params) > 4 {
+		var err error
+		if flush, err = args.GetInt64(params, 4); err != nil {
+			return getArgsGErrBlk("deflaterDeflate", err)
+		}
+		if flush != deflaterNoFlush && flush != deflaterSyncFlush && flush != deflaterFullFlush {
+			return getGErrBlk(excNames.IllegalArgumentException, "deflaterDeflate: invalid flush mode")
+		}
+	}
+
+	if err := d.compress(flush); err != nil {
+		errMsg := fmt.Sprintf("deflaterDeflate: %s", err.Error())
+		return getGErrBlk(excNames.InternalException, errMsg)
+	}
+
+	buffer := make([]byte, byteArrayRangeLength(params, 1))
+	n, _ := d.output.Read(buffer)
+	d.bytesWritten += int64(n)
+	return putByteArrayRange("deflaterDeflate", params, 1, buffer[:n])
+}
+
+// "java/util/zip/Deflater.finished()Z"
+func deflaterFinished(params []interface{}) interface{} {
+	d, gerr := getDeflater("deflaterFinished", params)
+	if gerr != nil {
+		return gerr
+	}
+	return object.JavaBooleanFromGoBoolean(d.closed && d.output.Len() == 0)
+}
+
+// "java/util/zip/Deflater.needsInput()Z"
+func deflaterNeedsInput(params []interface{}) interface{} {
+	d, gerr := getDeflater("deflaterNeedsInput", params)
+	if gerr != nil {
+		return gerr
+	}
+	return object.JavaBooleanFromGoBoolean(len(d.input) == 0)
+}
+
+// "java/util/zip/Deflater.reset()V"
+func deflaterReset(params []interface{}) interface{} {
+	d, gerr := getDeflater("deflaterReset", params)
+	if gerr != nil {
+		return gerr
+	}
+	*d = deflater{level: d.level, nowrap: d.nowrap}
+	return nil
+}
+
+// "java/util/zip/Deflater.getBytesRead()J"
+func deflaterGetBytesRead(params []interface{}) interface{} {
+	d, gerr := getDeflater("deflaterGetBytesRead", params)
+	if gerr != nil {
+		return gerr
+	}
+	return d.bytesRead
+}
+
+// "java/util/zip/Deflater.getBytesWritten()J"
+func deflaterGetBytesWritten(params []interface{}) interface{} {
+	d, gerr := getDeflater("deflaterGetBytesWritten", params)
+	if gerr != nil {
+		return gerr
+	}
+	return d.bytesWritten
+}
+
+// "java/util/zip/Deflater.getTotalIn()I"
+func deflaterGetTotalIn(params []interface{}) interface{} {
+	d, gerr := getDeflater("deflaterGetTotalIn", params)
+	if gerr != nil {
+		return gerr
+	}
+	return int64(int32(d.bytesRead))
+}
+
+// "java/util/zip/Deflater.getTotalOut()I"
+func deflaterGetTotalOut(params []interface{}) interface{} {
+	d, gerr := getDeflater("deflaterGetTotalOut", params)
+	if gerr != nil {
+		return gerr
+	}
+	return int64(int32(d.bytesWritten))
+}
+
+// "java/util/zip/Deflater.end()V" and "java/util/zip/Inflater.end()V"
+// Discards the Golang state, after which the object can't be used.
+func zipEnd(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("zipEnd", err)
+	}
+	delete(obj.FieldTable, fieldNameZipState)
+	return nil
+}
+
+// The functions below handle the byte arrays passed to the java/util/zip methods,
+// either alone or followed by an offset and a length: (byte[] b) or (byte[] b, int off, int len).
+
+// returns the bytes of the array at params[index], or the range of them given by the
+// offset and length that follow it
+func getByteArrayRange(funcName string, params []interface{}, index int) ([]byte, *GErrBlk) {
+	arrayObj, err := args.GetObject(params, index)
+	if err != nil {
+		return nil, getArgsGErrBlk(funcName, err)
+	}
+
+	var contents []byte
+	switch value := arrayObj.FieldTable["value"].Fvalue.(type) {
+	case []types.JavaByte:
+		contents = object.GoByteArrayFromJavaByteArray(value)
+	case []byte:
+		contents = value
+	default:
+		errMsg := fmt.Sprintf("%s: parameter %d: expected a byte array, got %T", funcName, index, value)
+		return nil, getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+
+	if len(params) < index+3 {
+		return contents, nil
+	}
+	offset, err := args.GetInt64(params, index+1)
+	if err != nil {
+		return nil, getArgsGErrBlk(funcName, err)
+	}
+	length, err := args.GetInt64(params, index+2)
+	if err != nil {
+		return nil, getArgsGErrBlk(funcName, err)
+	}
+	if offset < 0 || length < 0 || offset+length > int64(len(contents)) {
+		errMsg := fmt.Sprintf("%s: offset=%d length=%d bytes.length=%d", funcName, offset, length, len(contents))
+		return nil, getGErrBlk(excNames.ArrayIndexOutOfBoundsException, errMsg)
+	}
+	return contents[offset : offset+length], nil
+}
+
+// returns the number of bytes that can be written to the array at params[index]: its
+// length, or the length that follows it and its offset. Returns 0 for invalid parameters,
+// which putByteArrayRange() reports.
+func byteArrayRangeLength(params []interface{}, index int) int {
+	if len(params) >= index+3 {
+		if length, ok := params[index+2].(int64); ok && length > 0 {
+			return int(length)
+		}
+		return 0
+	}
+	if arrayObj, ok := params[index].(*object.Object); ok && !object.IsNull(arrayObj) {
+		return int(byteArrayLength(arrayObj))
+	}
+	return 0
+}
+
+// writes data to the array at params[index], starting at the offset that follows it,
+// if any, and returns the number of bytes written
+func putByteArrayRange(funcName string, params []interface{}, index int, data []byte) interface{} {
+	arrayObj, err := args.GetObject(params, index)
+	if err != nil {
+		return getArgsGErrBlk(funcName, err)
+	}
+
+	offset := int64(0)
+	length := byteArrayLength(arrayObj)
+	if len(params) >= index+3 {
+		if offset, err = args.GetInt64(params, index+1); err != nil {
+			return getArgsGErrBlk(funcName, err)
+		}
+		if length, err = args.GetInt64(params, index+2); err != nil {
+			return getArgsGErrBlk(funcName, err)
+		}
+	}
+	if offset < 0 || length < 0 || offset+length > byteArrayLength(arrayObj) {
+		errMsg := fmt.Sprintf("%s: offset=%d length=%d bytes.length=%d",
+			funcName, offset, length, byteArrayLength(arrayObj))
+		return getGErrBlk(excNames.ArrayIndexOutOfBoundsException, errMsg)
+	}
+
+	switch value := arrayObj.FieldTable["value"].Fvalue.(type) {
+	case []types.JavaByte:
+		for i, b := range data {
+			value[offset+int64(i)] = types.JavaByte(b)
+		}
+	case []byte:
+		copy(value[offset:], data)
+	}
+	return int64(len(data))
+}
+
+// returns the length of a byte array, whether its bytes are Java bytes or Go bytes
+func byteArrayLength(arrayObj *object.Object) int64 {
+	switch value := arrayObj.FieldTable["value"].Fvalue.(type) {
+	case []types.JavaByte:
+		return int64(len(value))
+	case []byte:
+		return int64(len(value))
+	}
+	return 0
+}
@@ -0,0 +1,137 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"io"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"strings"
+	"testing"
+)
+
+func newZipObject(className string) *object.Object {
+	return object.MakeEmptyObjectWithClassName(&className)
+}
+
+// returns a Java byte array holding b
+func newZipByteArray(b []byte) *object.Object {
+	arr := object.Make1DimArray(object.BYTE, int64(len(b)))
+	arr.FieldTable["value"] = object.Field{Ftype: types.ByteArray, Fvalue: object.JavaByteArrayFromGoByteArray(b)}
+	return arr
+}
+
+// returns the contents of a Java byte array
+func zipByteArrayContents(arr *object.Object) []byte {
+	return object.GoByteArrayFromJavaByteArray(arr.FieldTable["value"].Fvalue.([]types.JavaByte))
+}
+
+// deflates all of input with the Deflater d, reading the output through a small buffer
+func deflateAll(t *testing.T, d *object.Object, input []byte) []byte {
+	if ret := deflaterSetInput([]interface{}{d, newZipByteArray(input)}); ret != nil {
+		t.Fatalf("deflaterSetInput failed: %v", ret)
+	}
+	_ = deflaterFinish([]interface{}{d})
+
+	var out []byte
+	buf := object.Make1DimArray(object.BYTE, 16)
+	for deflaterFinished([]interface{}{d}) != types.JavaBoolTrue {
+		n, ok := deflaterDeflate([]interface{}{d, buf}).(int64)
+		if !ok {
+			t.Fatalf("deflaterDeflate failed")
+		}
+		out = append(out, zipByteArrayContents(buf)[:n]...)
+	}
+	return out
+}
+
+func TestDeflaterZlibRoundTrip(t *testing.T) {
+	globals.InitStringPool()
+	input := []byte(strings.Repeat("Jacobin compresses this line. ", 40))
+
+	d := newZipObject("java/util/zip/Deflater")
+	if ret := deflaterInit([]interface{}{d}); ret != nil {
+		t.Fatalf("deflaterInit failed: %v", ret)
+	}
+	compressed := deflateAll(t, d, input)
+
+	r, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatalf("Output is not in ZLIB format: %v", err)
+	}
+	output, err := io.ReadAll(r)
+	if err != nil || !bytes.Equal(output, input) {
+		t.Fatalf("Round trip failed: err=%v, got %d bytes, expected %d", err, len(output), len(input))
+	}
+
+	if deflaterGetBytesRead([]interface{}{d}).(int64) != int64(len(input)) {
+		t.Errorf("Expected getBytesRead() = %d, got %d", len(input), deflaterGetBytesRead([]interface{}{d}))
+	}
+	if deflaterGetTotalOut([]interface{}{d}).(int64) != int64(len(compressed)) {
+		t.Errorf("Expected getTotalOut() = %d, got %d", len(compressed), deflaterGetTotalOut([]interface{}{d}))
+	}
+	if deflaterNeedsInput([]interface{}{d}) != types.JavaBoolTrue {
+		t.Error("Expected needsInput() to be true once the input is consumed")
+	}
+
+	// after reset(), the Deflater compresses a new stream
+	_ = deflaterReset([]interface{}{d})
+	if deflaterFinished([]interface{}{d}) != types.JavaBoolFalse {
+		t.Error("Expected finished() to be false after reset()")
+	}
+	if !bytes.Equal(deflateAll(t, d, input), compressed) {
+		t.Error("Expected the same output after reset()")
+	}
+}
+
+func TestDeflaterNowrapWithOffset(t *testing.T) {
+	globals.InitStringPool()
+	input := []byte("abcabcabcabcabcabcabcabc")
+
+	d := newZipObject("java/util/zip/Deflater")
+	_ = deflaterInit([]interface{}{d, int64(9), types.JavaBoolTrue})
+	_ = deflaterSetInput([]interface{}{d, newZipByteArray(input), int64(0), int64(len(input))})
+	_ = deflaterFinish([]interface{}{d})
+
+	buf := object.Make1DimArray(object.BYTE, 100)
+	n := deflaterDeflate([]interface{}{d, buf, int64(10), int64(90)}).(int64)
+	if deflaterFinished([]interface{}{d}) != types.JavaBoolTrue {
+		t.Fatal("Expected the output to fit in the buffer")
+	}
+
+	output, err := io.ReadAll(flate.NewReader(bytes.NewReader(zipByteArrayContents(buf)[10 : 10+n])))
+	if err != nil || !bytes.Equal(output, input) {
+		t.Fatalf("Raw DEFLATE round trip failed: err=%v, got %q", err, output)
+	}
+}
+
+func TestDeflaterErrors(t *testing.T) {
+	globals.InitStringPool()
+
+	d := newZipObject("java/util/zip/Deflater")
+	ret := deflaterInit([]interface{}{d, int64(10)})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("Expected IllegalArgumentException for level 10, got %v", ret)
+	}
+
+	_ = deflaterInit([]interface{}{d})
+	ret = deflaterSetInput([]interface{}{d, newZipByteArray([]byte("abc")), int64(2), int64(5)})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.ArrayIndexOutOfBoundsException {
+		t.Errorf("Expected ArrayIndexOutOfBoundsException for a bad range, got %v", ret)
+	}
+
+	_ = zipEnd([]interface{}{d})
+	ret = deflaterFinished([]interface{}{d})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("Expected NullPointerException after end(), got %v", ret)
+	}
+}
@@ -0,0 +1,339 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
+	"jacobin/src/object"
+	"jacobin/src/types"
+)
+
+// Inflater decompresses with Go's compress/flate: data in the ZLIB format, or in
+// the raw DEFLATE format if the nowrap constructor parameter is true. Input is
+// accumulated until it holds the whole compressed stream, which is then
+// decompressed at once; until then, inflate() returns 0 and needsInput() is true.
+
+func Load_Util_Zip_Inflater() {
+
+	MethodSignatures["java/util/zip/Inflater.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/zip/Inflater.<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  inflaterInit,
+		}
+
+	MethodSignatures["java/util/zip/Inflater.<init>(Z)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  inflaterInit,
+		}
+
+	MethodSignatures["java/util/zip/Inflater.end()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  zipEnd,
+		}
+
+	MethodSignatures["java/util/zip/Inflater.finished()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  inflaterFinished,
+		}
+
+	MethodSignatures["java/util/zip/Inflater.getBytesRead()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  inflaterGetBytesRead,
+		}
+
+	MethodSignatures["java/util/zip/Inflater.getBytesWritten()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  inflaterGetBytesWritten,
+		}
+
+	MethodSignatures["java/util/zip/Inflater.getRemaining()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  inflaterGetRemaining,
+		}
+
+	MethodSignatures["java/util/zip/Inflater.getTotalIn()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  inflaterGetTotalIn,
+		}
+
+	MethodSignatures["java/util/zip/Inflater.getTotalOut()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  inflaterGetTotalOut,
+		}
+
+	MethodSignatures["java/util/zip/Inflater.inflate([B)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  inflaterInflate,
+		}
+
+	MethodSignatures["java/util/zip/Inflater.inflate([BII)I"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  inflaterInflate,
+		}
+
+	MethodSignatures["java/util/zip/Inflater.needsDictionary()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  returnFalse,
+		}
+
+	MethodSignatures["java/util/zip/Inflater.needsInput()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  inflaterNeedsInput,
+		}
+
+	MethodSignatures["java/util/zip/Inflater.reset()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  inflaterReset,
+		}
+
+	MethodSignatures["java/util/zip/Inflater.setInput([B)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  inflaterSetInput,
+		}
+
+	MethodSignatures["java/util/zip/Inflater.setInput([BII)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  inflaterSetInput,
+		}
+
+	// -----------------------------------------
+	// Traps that do nothing but return an error
+	// -----------------------------------------
+
+	MethodSignatures["java/util/zip/Inflater.inflate(Ljava/nio/ByteBuffer;)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["java/util/zip/Inflater.setDictionary([B)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["java/util/zip/Inflater.setInput(Ljava/nio/ByteBuffer;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  trapFunction,
+		}
+
+}
+
+// inflater is the state of an Inflater
+type inflater struct {
+	nowrap       bool
+	input        []byte // compressed input not yet consumed
+	output       []byte // decompressed bytes not yet returned by inflate()
+	needInput    bool   // the input holds only part of the compressed stream
+	done         bool   // the compressed stream has been decompressed
+	bytesRead    int64  // compressed bytes consumed
+	bytesWritten int64  // decompressed bytes returned by inflate()
+}
+
+// decompresses the input, if it holds the whole compressed stream. Any input that
+// follows the stream remains in the input.
+func (i *inflater) decompress() error {
+	reader := bytes.NewReader(i.input)
+	var decompressor io.Reader
+	var err error
+	if i.nowrap {
+		decompressor = flate.NewReader(reader)
+	} else {
+		decompressor, err = zlib.NewReader(reader)
+	}
+
+	var data []byte
+	if err == nil {
+		data, err = io.ReadAll(decompressor)
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		i.needInput = true
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	consumed := len(i.input) - reader.Len()
+	i.bytesRead += int64(consumed)
+	i.input = i.input[consumed:]
+	i.output = data
+	i.done = true
+	return nil
+}
+
+// returns the inflater in the Inflater object params[0]
+func getInflater(funcName string, params []interface{}) (*inflater, *GErrBlk) {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return nil, getArgsGErrBlk(funcName, err)
+	}
+	i, ok := obj.FieldTable[fieldNameZipState].Fvalue.(*inflater)
+	if !ok {
+		return nil, getGErrBlk(excNames.NullPointerException, funcName+": Inflater has been closed")
+	}
+	return i, nil
+}
+
+// "java/util/zip/Inflater.<init>()V" and "java/util/zip/Inflater.<init>(Z)V"
+func inflaterInit(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("inflaterInit", err)
+	}
+
+	i := &inflater{}
+	if len(params) > 1 {
+		if i.nowrap, err = args.GetBoolean(params, 1); err != nil {
+			return getArgsGErrBlk("inflaterInit", err)
+		}
+	}
+
+	obj.FieldTable[fieldNameZipState] = object.Field{Ftype: types.ZipState, Fvalue: i}
+	return nil
+}
+
+// "java/util/zip/Inflater.setInput([B)V" and "java/util/zip/Inflater.setInput([BII)V"
+func inflaterSetInput(params []interface{}) interface{} {
+	i, gerr := getInflater("inflaterSetInput", params)
+	if gerr != nil {
+		return gerr
+	}
+	input, gerr := getByteArrayRange("inflaterSetInput", params, 1)
+	if gerr != nil {
+		return gerr
+	}
+	i.input = append(i.input, input...)
+	i.needInput = false
+	return nil
+}
+
+// "java/util/zip/Inflater.inflate([B)I" and "java/util/zip/Inflater.inflate([BII)I"
+// Returns the number of decompressed bytes written to the array.
+func inflaterInflate(params []interface{}) interface{} {
+	i, gerr := getInflater("inflaterInflate", params)
+	if gerr != nil {
+		return gerr
+	}
+
+	if !i.done && !i.needInput && len(i.input) > 0 {
+		if err := i.decompress(); err != nil {
+			errMsg := fmt.Sprintf("inflaterInflate: %s", err.Error())
+			return getGErrBlk(excNames.DataFormatException, errMsg)
+		}
+	}
+
+	n := min(len(i.output), byteArrayRangeLength(params, 1))
+	ret := putByteArrayRange("inflaterInflate", params, 1, i.output[:n])
+	if _, ok := ret.(int64); ok {
+		i.output = i.output[n:]
+		i.bytesWritten += int64(n)
+	}
+	return ret
+}
+
+// "java/util/zip/Inflater.finished()Z"
+func inflaterFinished(params []interface{}) interface{} {
+	i, gerr := getInflater("inflaterFinished", params)
+	if gerr != nil {
+		return gerr
+	}
+	return object.JavaBooleanFromGoBoolean(i.done && len(i.output) == 0)
+}
+
+// "java/util/zip/Inflater.needsInput()Z"
+func inflaterNeedsInput(params []interface{}) interface{} {
+	i, gerr := getInflater("inflaterNeedsInput", params)
+	if gerr != nil {
+		return gerr
+	}
+	return object.JavaBooleanFromGoBoolean(i.needInput || len(i.input) == 0)
+}
+
+// "java/util/zip/Inflater.getRemaining()I"
+func inflaterGetRemaining(params []interface{}) interface{} {
+	i, gerr := getInflater("inflaterGetRemaining", params)
+	if gerr != nil {
+		return gerr
+	}
+	return int64(len(i.input))
+}
+
+// "java/util/zip/Inflater.reset()V"
+func inflaterReset(params []interface{}) interface{} {
+	i, gerr := getInflater("inflaterReset", params)
+	if gerr != nil {
+		return gerr
+	}
+	*i = inflater{nowrap: i.nowrap}
+	return nil
+}
+
+// "java/util/zip/Inflater.getBytesRead()J"
+func inflaterGetBytesRead(params []interface{}) interface{} {
+	i, gerr := getInflater("inflaterGetBytesRead", params)
+	if gerr != nil {
+		return gerr
+	}
+	return i.bytesRead
+}
+
+// "java/util/zip/Inflater.getBytesWritten()J"
+func inflaterGetBytesWritten(params []interface{}) interface{} {
+	i, gerr := getInflater("inflaterGetBytesWritten", params)
+	if gerr != nil {
+		return gerr
+	}
+	return i.bytesWritten
+}
+
+// "java/util/zip/Inflater.getTotalIn()I"
+func inflaterGetTotalIn(params []interface{}) interface{} {
+	i, gerr := getInflater("inflaterGetTotalIn", params)
+	if gerr != nil {
+		return gerr
+	}
+	return int64(int32(i.bytesRead))
+}
+
+// "java/util/zip/Inflater.getTotalOut()I"
+func inflaterGetTotalOut(params []interface{}) interface{} {
+	i, gerr := getInflater("inflaterGetTotalOut", params)
+	if gerr != nil {
+		return gerr
+	}
+	return int64(int32(i.bytesWritten))
+}
@@ -0,0 +1,434 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"os"
+	"strings"
+)
+
+// InflaterInputStream reads data in the ZLIB format, and GZIPInputStream reads data in
+// the GZIP format, from an underlying input stream. The underlying stream can be a
+// FileInputStream, a ByteArrayInputStream, or another of these streams. Both classes
+// share the functions below, and ZipFile.getInputStream() returns an InflaterInputStream
+// over the contents of the entry.
+
+func Load_Util_Zip_Inflater_Input_Stream() {
+
+	for _, className := range []string{classNameInflaterInputStream, classNameGZIPInputStream} {
+
+		MethodSignatures[className+".<clinit>()V"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  clinitGeneric,
+			}
+
+		MethodSignatures[className+".available()I"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  zipStreamAvailable,
+			}
+
+		MethodSignatures[className+".close()V"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  zipStreamClose,
+			}
+
+		MethodSignatures[className+".markSupported()Z"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  returnFalse,
+			}
+
+		MethodSignatures[className+".read()I"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  zipStreamReadOne,
+			}
+
+		MethodSignatures[className+".read([B)I"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  zipStreamRead,
+			}
+
+		MethodSignatures[className+".read([BII)I"] =
+			GMeth{
+				ParamSlots: 3,
+				GFunction:  zipStreamRead,
+			}
+
+		MethodSignatures[className+".skip(J)J"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  zipStreamSkip,
+			}
+	}
+
+	MethodSignatures["java/util/zip/GZIPInputStream.<init>(Ljava/io/InputStream;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  gzipInputStreamInit,
+		}
+
+	MethodSignatures["java/util/zip/GZIPInputStream.<init>(Ljava/io/InputStream;I)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  gzipInputStreamInit,
+		}
+
+	MethodSignatures["java/util/zip/InflaterInputStream.<init>(Ljava/io/InputStream;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  inflaterInputStreamInit,
+		}
+
+	// -----------------------------------------
+	// Traps that do nothing but return an error
+	// -----------------------------------------
+
+	MethodSignatures["java/util/zip/InflaterInputStream.<init>(Ljava/io/InputStream;Ljava/util/zip/Inflater;)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["java/util/zip/InflaterInputStream.<init>(Ljava/io/InputStream;Ljava/util/zip/Inflater;I)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  trapFunction,
+		}
+
+}
+
+var classNameInflaterInputStream = "java/util/zip/InflaterInputStream"
+var classNameGZIPInputStream = "java/util/zip/GZIPInputStream"
+
+// zipInputStream is the state of an InflaterInputStream or a GZIPInputStream
+type zipInputStream struct {
+	source       io.Reader                          // the underlying stream
+	decompressor io.Reader                          // reads the decompressed data
+	open         func(io.Reader) (io.Reader, error) // creates the decompressor
+	closed       bool
+	eof          bool
+}
+
+// Read reads decompressed data, creating the decompressor on first use. It makes a
+// zipInputStream usable as the underlying stream of another one.
+func (s *zipInputStream) Read(p []byte) (int, error) {
+	if s.closed {
+		return 0, errStreamClosed
+	}
+	if s.decompressor == nil {
+		var err error
+		if s.decompressor, err = s.open(s.source); err != nil {
+			return 0, err
+		}
+	}
+	n, err := s.decompressor.Read(p)
+	if err == io.EOF {
+		s.eof = true
+	}
+	return n, err
+}
+
+var errStreamClosed = errors.New("Stream closed")
+
+// returns a Golang reader for the Java input stream obj
+func getSourceReader(funcName string, obj *object.Object) (io.Reader, *GErrBlk) {
+	if object.IsNull(obj) {
+		return nil, getGErrBlk(excNames.NullPointerException, funcName+": null input stream")
+	}
+
+	if osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File); ok {
+		return osFile, nil
+	}
+	if s, ok := obj.FieldTable[fieldNameZipState].Fvalue.(*zipInputStream); ok {
+		return s, nil
+	}
+
+	// a ByteArrayInputStream: the unread bytes of buf are those from pos up to count.
+	// They're all handed to the new reader, so the stream is left at its end.
+	if bufObj, ok := obj.FieldTable["buf"].Fvalue.(*object.Object); ok && !object.IsNull(bufObj) {
+		buf, gerr := getByteArrayRange(funcName, []interface{}{bufObj}, 0)
+		if gerr != nil {
+			return nil, gerr
+		}
+		pos, _ := obj.FieldTable["pos"].Fvalue.(int64)
+		count, ok := obj.FieldTable["count"].Fvalue.(int64)
+		if !ok || count > int64(len(buf)) {
+			count = int64(len(buf))
+		}
+		if pos > count {
+			pos = count
+		}
+		obj.FieldTable["pos"] = object.Field{Ftype: types.Int, Fvalue: count}
+		return bytes.NewReader(buf[pos:count]), nil
+	}
+
+	// a StringReader: the unread characters of str start at next. They're read as UTF-8.
+	if strObj, ok := obj.FieldTable["str"].Fvalue.(*object.Object); ok && !object.IsNull(strObj) {
+		chars := []rune(object.GoStringFromStringObject(strObj))
+		next, _ := obj.FieldTable["next"].Fvalue.(int64)
+		if next < 0 || next > int64(len(chars)) {
+			next = int64(len(chars))
+		}
+		obj.FieldTable["next"] = object.Field{Ftype: types.Int, Fvalue: int64(len(chars))}
+		return strings.NewReader(string(chars[next:])), nil
+	}
+
+	errMsg := fmt.Sprintf("%s: unsupported input stream: %s", funcName, object.GoStringFromStringPoolIndex(obj.KlassName))
+	return nil, getGErrBlk(excNames.IOException, errMsg)
+}
+
+// returns a Golang writer for the Java output stream or writer in param. System.out and
+// System.err are passed as Golang writers already.
+func getSinkWriter(funcName string, param interface{}) (io.Writer, *GErrBlk) {
+	if writer, ok := param.(io.Writer); ok {
+		return writer, nil
+	}
+	obj, ok := param.(*object.Object)
+	if !ok || object.IsNull(obj) {
+		return nil, getGErrBlk(excNames.NullPointerException, funcName+": null output stream")
+	}
+
+	if osFile, ok := obj.FieldTable[FileHandle].Fvalue.(*os.File); ok {
+		return osFile, nil
+	}
+	if bufObj, ok := obj.FieldTable["buf"].Fvalue.(*object.Object); ok && !object.IsNull(bufObj) {
+		if _, ok := obj.FieldTable["count"].Fvalue.(int64); ok {
+			return &byteArrayOutputStreamWriter{obj: obj}, nil
+		}
+	}
+
+	errMsg := fmt.Sprintf("%s: unsupported output stream: %s", funcName, object.GoStringFromStringPoolIndex(obj.KlassName))
+	return nil, getGErrBlk(excNames.IOException, errMsg)
+}
+
+// byteArrayOutputStreamWriter appends to a ByteArrayOutputStream: its buf holds count valid bytes
+type byteArrayOutputStreamWriter struct {
+	obj *object.Object
+}
+
+func (w *byteArrayOutputStreamWriter) Write(p []byte) (int, error) {
+	bufObj := w.obj.FieldTable["buf"].Fvalue.(*object.Object)
+	buf, gerr := getByteArrayRange("byteArrayOutputStreamWriter", []interface{}{bufObj}, 0)
+	if gerr != nil {
+		return 0, errors.New(gerr.ErrMsg)
+	}
+	count := w.obj.FieldTable["count"].Fvalue.(int64)
+	if count > int64(len(buf)) {
+		count = int64(len(buf))
+	}
+
+	contents := append(append([]byte{}, buf[:count]...), p...)
+	w.obj.FieldTable["buf"] = object.Field{Ftype: types.ByteArray,
+		Fvalue: Populator("[B", types.ByteArray, object.JavaByteArrayFromGoByteArray(contents))}
+	w.obj.FieldTable["count"] = object.Field{Ftype: types.Int, Fvalue: int64(len(contents))}
+	return len(p), nil
+}
+
+// returns the GErrBlk for an error reading a compressed stream
+func zipStreamError(funcName string, err error) *GErrBlk {
+	var corrupt flate.CorruptInputError
+	switch {
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		return getGErrBlk(excNames.IOException, funcName+": Unexpected end of ZLIB input stream")
+	case errors.Is(err, gzip.ErrHeader):
+		return getGErrBlk(excNames.ZipException, funcName+": Not in GZIP format")
+	case errors.Is(err, gzip.ErrChecksum) || errors.Is(err, zlib.ErrChecksum) ||
+		errors.Is(err, zlib.ErrHeader) || errors.As(err, &corrupt):
+		return getGErrBlk(excNames.ZipException, funcName+": "+err.Error())
+	default:
+		return getGErrBlk(excNames.IOException, funcName+": "+err.Error())
+	}
+}
+
+// returns the zipInputStream in the stream object params[0]
+func getZipInputStream(funcName string, params []interface{}) (*zipInputStream, *GErrBlk) {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return nil, getArgsGErrBlk(funcName, err)
+	}
+	s, ok := obj.FieldTable[fieldNameZipState].Fvalue.(*zipInputStream)
+	if !ok {
+		errMsg := fmt.Sprintf("%s: %s object lacks its stream state",
+			funcName, object.GoStringFromStringPoolIndex(obj.KlassName))
+		return nil, getGErrBlk(excNames.IOException, errMsg)
+	}
+	if s.closed {
+		return nil, getGErrBlk(excNames.IOException, funcName+": Stream closed")
+	}
+	return s, nil
+}
+
+// "java/util/zip/GZIPInputStream.<init>(Ljava/io/InputStream;)V" and
+// "java/util/zip/GZIPInputStream.<init>(Ljava/io/InputStream;I)V"
+// As in the JDK, the GZIP header is read here. The buffer size is ignored.
+func gzipInputStreamInit(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("gzipInputStreamInit", err)
+	}
+	sourceObj, err := args.GetObjectOrNull(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("gzipInputStreamInit", err)
+	}
+	source, gerr := getSourceReader("gzipInputStreamInit", sourceObj)
+	if gerr != nil {
+		return gerr
+	}
+
+	decompressor, err := gzip.NewReader(source)
+	if err != nil {
+		return zipStreamError("gzipInputStreamInit", err)
+	}
+	s := &zipInputStream{source: source, decompressor: decompressor}
+	obj.FieldTable[fieldNameZipState] = object.Field{Ftype: types.ZipState, Fvalue: s}
+	return nil
+}
+
+// "java/util/zip/InflaterInputStream.<init>(Ljava/io/InputStream;)V"
+// As in the JDK, nothing is read until the first read.
+func inflaterInputStreamInit(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("inflaterInputStreamInit", err)
+	}
+	sourceObj, err := args.GetObjectOrNull(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("inflaterInputStreamInit", err)
+	}
+	source, gerr := getSourceReader("inflaterInputStreamInit", sourceObj)
+	if gerr != nil {
+		return gerr
+	}
+
+	open := func(r io.Reader) (io.Reader, error) { return zlib.NewReader(r) }
+	s := &zipInputStream{source: source, open: open}
+	obj.FieldTable[fieldNameZipState] = object.Field{Ftype: types.ZipState, Fvalue: s}
+	return nil
+}
+
+// returns an InflaterInputStream over bytes that are already decompressed
+func newDecompressedInputStream(data []byte) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&classNameInflaterInputStream)
+	s := &zipInputStream{decompressor: bytes.NewReader(data)}
+	obj.FieldTable[fieldNameZipState] = object.Field{Ftype: types.ZipState, Fvalue: s}
+	return obj
+}
+
+// "java/util/zip/InflaterInputStream.read()I" and "java/util/zip/GZIPInputStream.read()I"
+func zipStreamReadOne(params []interface{}) interface{} {
+	s, gerr := getZipInputStream("zipStreamReadOne", params)
+	if gerr != nil {
+		return gerr
+	}
+
+	buffer := make([]byte, 1)
+	_, err := io.ReadFull(s, buffer)
+	if err == io.EOF {
+		return int64(-1) // return -1 on EOF
+	}
+	if err != nil {
+		return zipStreamError("zipStreamReadOne", err)
+	}
+	return int64(buffer[0])
+}
+
+// "java/util/zip/InflaterInputStream.read([B)I", "java/util/zip/InflaterInputStream.read([BII)I",
+// and the same methods of GZIPInputStream
+// Returns the number of bytes read, or -1 at the end of the stream.
+func zipStreamRead(params []interface{}) interface{} {
+	s, gerr := getZipInputStream("zipStreamRead", params)
+	if gerr != nil {
+		return gerr
+	}
+
+	length := byteArrayRangeLength(params, 1)
+	if length == 0 {
+		return putByteArrayRange("zipStreamRead", params, 1, nil) // checks the parameters
+	}
+
+	// read until the buffer is full or the stream ends, as the JDK's streams do
+	buffer := make([]byte, length)
+	n, err := io.ReadFull(s, buffer)
+	if err == io.EOF {
+		return int64(-1) // return -1 on EOF
+	}
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return zipStreamError("zipStreamRead", err)
+	}
+	return putByteArrayRange("zipStreamRead", params, 1, buffer[:n])
+}
+
+// "java/util/zip/InflaterInputStream.available()I" and "java/util/zip/GZIPInputStream.available()I"
+// Returns 0 at the end of the stream and 1 otherwise, as the JDK does.
+func zipStreamAvailable(params []interface{}) interface{} {
+	s, gerr := getZipInputStream("zipStreamAvailable", params)
+	if gerr != nil {
+		return gerr
+	}
+	if s.eof {
+		return int64(0)
+	}
+	return int64(1)
+}
+
+// "java/util/zip/InflaterInputStream.skip(J)J" and "java/util/zip/GZIPInputStream.skip(J)J"
+func zipStreamSkip(params []interface{}) interface{} {
+	s, gerr := getZipInputStream("zipStreamSkip", params)
+	if gerr != nil {
+		return gerr
+	}
+	count, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("zipStreamSkip", err)
+	}
+	if count < 0 {
+		return getGErrBlk(excNames.IllegalArgumentException, "zipStreamSkip: negative skip length")
+	}
+
+	skipped, err := io.CopyN(io.Discard, s, count)
+	if err != nil && err != io.EOF {
+		return zipStreamError("zipStreamSkip", err)
+	}
+	return skipped
+}
+
+// "java/util/zip/InflaterInputStream.close()V" and "java/util/zip/GZIPInputStream.close()V"
+// Closes the underlying stream, too. Closing a closed stream does nothing.
+func zipStreamClose(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("zipStreamClose", err)
+	}
+	s, ok := obj.FieldTable[fieldNameZipState].Fvalue.(*zipInputStream)
+	if !ok || s.closed {
+		return nil
+	}
+
+	s.closed = true
+	if closer, ok := s.source.(io.Closer); ok {
+		if err = closer.Close(); err != nil {
+			return getGErrBlk(excNames.IOException, "zipStreamClose: "+err.Error())
+		}
+	}
+	return nil
+}
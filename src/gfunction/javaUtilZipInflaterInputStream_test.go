@@ -0,0 +1,124 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"bytes"
+	"compress/gzip"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// returns a ByteArrayInputStream over data, with the fields the JDK's constructor sets
+func newByteArrayInputStream(data []byte) *object.Object {
+	bais := newZipObject("java/io/ByteArrayInputStream")
+	bais.FieldTable["buf"] = object.Field{Ftype: types.ByteArray, Fvalue: newZipByteArray(data)}
+	bais.FieldTable["pos"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+	bais.FieldTable["count"] = object.Field{Ftype: types.Int, Fvalue: int64(len(data))}
+	return bais
+}
+
+func gzipCompress(data []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, _ = w.Write(data)
+	_ = w.Close()
+	return buf.Bytes()
+}
+
+func TestGZIPInputStreamFromByteArray(t *testing.T) {
+	globals.InitStringPool()
+	input := []byte("Hello from a gzipped stream")
+
+	gz := newZipObject(classNameGZIPInputStream)
+	bais := newByteArrayInputStream(gzipCompress(input))
+	if ret := gzipInputStreamInit([]interface{}{gz, bais}); ret != nil {
+		t.Fatalf("gzipInputStreamInit failed: %v", ret)
+	}
+	if bais.FieldTable["pos"].Fvalue != bais.FieldTable["count"].Fvalue {
+		t.Error("Expected the ByteArrayInputStream to be left at its end")
+	}
+
+	if b := zipStreamReadOne([]interface{}{gz}); b != int64('H') {
+		t.Errorf("Expected read() to return 'H', got %v", b)
+	}
+
+	buf := object.Make1DimArray(object.BYTE, 100)
+	n := zipStreamRead([]interface{}{gz, buf}).(int64)
+	if string(zipByteArrayContents(buf)[:n]) != string(input[1:]) {
+		t.Errorf("Expected %q, got %q", input[1:], zipByteArrayContents(buf)[:n])
+	}
+
+	if n := zipStreamRead([]interface{}{gz, buf, int64(0), int64(10)}); n != int64(-1) {
+		t.Errorf("Expected -1 at the end of the stream, got %v", n)
+	}
+	if zipStreamAvailable([]interface{}{gz}) != int64(0) {
+		t.Error("Expected available() to be 0 at the end of the stream")
+	}
+
+	_ = zipStreamClose([]interface{}{gz})
+	ret := zipStreamReadOne([]interface{}{gz})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IOException {
+		t.Errorf("Expected IOException reading a closed stream, got %v", ret)
+	}
+}
+
+func TestGZIPInputStreamNotGzip(t *testing.T) {
+	globals.InitStringPool()
+
+	gz := newZipObject(classNameGZIPInputStream)
+	ret := gzipInputStreamInit([]interface{}{gz, newByteArrayInputStream([]byte("plain text, not gzip"))})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.ZipException {
+		t.Errorf("Expected ZipException, got %v", ret)
+	}
+
+	ret = gzipInputStreamInit([]interface{}{gz, object.Null})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("Expected NullPointerException for a null stream, got %v", ret)
+	}
+}
+
+// an InflaterInputStream over a FileInputStream, which it closes when it's closed
+func TestInflaterInputStreamFromFile(t *testing.T) {
+	globals.InitStringPool()
+	input := []byte("ZLIB data read from a file")
+
+	path := filepath.Join(t.TempDir(), "data.z")
+	if err := os.WriteFile(path, zlibCompress(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+	osFile, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fis := newZipObject("java/io/FileInputStream")
+	fis.FieldTable[FileHandle] = object.Field{Ftype: types.FileHandle, Fvalue: osFile}
+
+	iis := newZipObject(classNameInflaterInputStream)
+	if ret := inflaterInputStreamInit([]interface{}{iis, fis}); ret != nil {
+		t.Fatalf("inflaterInputStreamInit failed: %v", ret)
+	}
+
+	if skipped := zipStreamSkip([]interface{}{iis, int64(5)}); skipped != int64(5) {
+		t.Errorf("Expected to skip 5 bytes, skipped %v", skipped)
+	}
+	buf := object.Make1DimArray(object.BYTE, 100)
+	n := zipStreamRead([]interface{}{iis, buf}).(int64)
+	if string(zipByteArrayContents(buf)[:n]) != string(input[5:]) {
+		t.Errorf("Expected %q, got %q", input[5:], zipByteArrayContents(buf)[:n])
+	}
+
+	_ = zipStreamClose([]interface{}{iis})
+	if _, err = osFile.Read(make([]byte, 1)); err == nil {
+		t.Error("Expected the file to be closed")
+	}
+}
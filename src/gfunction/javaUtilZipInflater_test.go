@@ -0,0 +1,89 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"bytes"
+	"compress/zlib"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"testing"
+)
+
+func zlibCompress(data []byte) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	_, _ = w.Write(data)
+	_ = w.Close()
+	return buf.Bytes()
+}
+
+func TestInflaterInChunks(t *testing.T) {
+	globals.InitStringPool()
+	input := []byte("Inflated by Jacobin, one chunk at a time.")
+	compressed := append(zlibCompress(input), 'X', 'Y') // two bytes follow the stream
+
+	i := newZipObject("java/util/zip/Inflater")
+	if ret := inflaterInit([]interface{}{i}); ret != nil {
+		t.Fatalf("inflaterInit failed: %v", ret)
+	}
+	if inflaterNeedsInput([]interface{}{i}) != types.JavaBoolTrue {
+		t.Error("Expected needsInput() to be true before any input")
+	}
+
+	// the first half of the stream isn't enough to inflate anything
+	buf := object.Make1DimArray(object.BYTE, 100)
+	half := len(compressed) / 2
+	_ = inflaterSetInput([]interface{}{i, newZipByteArray(compressed[:half])})
+	if n := inflaterInflate([]interface{}{i, buf}); n != int64(0) {
+		t.Fatalf("Expected 0 bytes from half the stream, got %v", n)
+	}
+	if inflaterNeedsInput([]interface{}{i}) != types.JavaBoolTrue {
+		t.Error("Expected needsInput() to be true after inflating part of the stream")
+	}
+
+	_ = inflaterSetInput([]interface{}{i, newZipByteArray(compressed), int64(half), int64(len(compressed) - half)})
+	n := inflaterInflate([]interface{}{i, buf, int64(0), int64(10)}).(int64)
+	n += inflaterInflate([]interface{}{i, buf, int64(10), int64(90)}).(int64)
+	if !bytes.Equal(zipByteArrayContents(buf)[:n], input) {
+		t.Errorf("Expected %q, got %q", input, zipByteArrayContents(buf)[:n])
+	}
+
+	if inflaterFinished([]interface{}{i}) != types.JavaBoolTrue {
+		t.Error("Expected finished() to be true")
+	}
+	if inflaterGetRemaining([]interface{}{i}) != int64(2) {
+		t.Errorf("Expected 2 bytes to remain, got %v", inflaterGetRemaining([]interface{}{i}))
+	}
+	if inflaterGetBytesRead([]interface{}{i}) != int64(len(compressed)-2) {
+		t.Errorf("Expected getBytesRead() = %d, got %v", len(compressed)-2, inflaterGetBytesRead([]interface{}{i}))
+	}
+	if inflaterGetTotalOut([]interface{}{i}) != int64(len(input)) {
+		t.Errorf("Expected getTotalOut() = %d, got %v", len(input), inflaterGetTotalOut([]interface{}{i}))
+	}
+}
+
+func TestInflaterDataFormatException(t *testing.T) {
+	globals.InitStringPool()
+
+	i := newZipObject("java/util/zip/Inflater")
+	_ = inflaterInit([]interface{}{i})
+	_ = inflaterSetInput([]interface{}{i, newZipByteArray([]byte("this is not compressed"))})
+
+	ret := inflaterInflate([]interface{}{i, object.Make1DimArray(object.BYTE, 10)})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.DataFormatException {
+		t.Errorf("Expected DataFormatException, got %v", ret)
+	}
+
+	_ = zipEnd([]interface{}{i})
+	ret = inflaterNeedsInput([]interface{}{i})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("Expected NullPointerException after end(), got %v", ret)
+	}
+}
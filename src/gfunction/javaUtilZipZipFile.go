@@ -0,0 +1,460 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"os"
+	"strings"
+)
+
+// ZipFile reads zip files through the classloader's Archive, which is also what reads
+// JAR files. The ZipEntry objects it returns have the fields of the JDK's ZipEntry, so
+// that they're the same as the entries that programs create themselves, and the
+// ZipEntry getters are implemented here to read those fields.
+
+func Load_Util_Zip_Zip_File() {
+
+	MethodSignatures["java/util/zip/ZipFile.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["java/util/zip/ZipFile.<init>(Ljava/io/File;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  zipFileInitFile,
+		}
+
+	MethodSignatures["java/util/zip/ZipFile.<init>(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  zipFileInitString,
+		}
+
+	MethodSignatures["java/util/zip/ZipFile.close()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  zipFileClose,
+		}
+
+	MethodSignatures["java/util/zip/ZipFile.entries()Ljava/util/Enumeration;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  zipFileEntries,
+		}
+
+	MethodSignatures["java/util/zip/ZipFile.getEntry(Ljava/lang/String;)Ljava/util/zip/ZipEntry;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  zipFileGetEntry,
+		}
+
+	MethodSignatures["java/util/zip/ZipFile.getInputStream(Ljava/util/zip/ZipEntry;)Ljava/io/InputStream;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  zipFileGetInputStream,
+		}
+
+	MethodSignatures["java/util/zip/ZipFile.getName()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  zipFileGetName,
+		}
+
+	MethodSignatures["java/util/zip/ZipFile.size()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  zipFileSize,
+		}
+
+	// the enumeration returned by entries()
+
+	for _, methName := range []string{"hasMoreElements()Z", "hasNext()Z"} {
+		MethodSignatures[classNameZipEntryIterator+"."+methName] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  zipEntryIteratorHasNext,
+			}
+	}
+
+	for _, methName := range []string{
+		"nextElement()Ljava/lang/Object;", "nextElement()Ljava/util/zip/ZipEntry;",
+		"next()Ljava/lang/Object;", "next()Ljava/util/zip/ZipEntry;"} {
+		MethodSignatures[classNameZipEntryIterator+"."+methName] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  zipEntryIteratorNext,
+			}
+	}
+
+	// ZipEntry
+
+	MethodSignatures["java/util/zip/ZipEntry.getComment()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  zipEntryGetComment,
+		}
+
+	MethodSignatures["java/util/zip/ZipEntry.getCompressedSize()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  zipEntryGetCompressedSize,
+		}
+
+	MethodSignatures["java/util/zip/ZipEntry.getCrc()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  zipEntryGetCrc,
+		}
+
+	MethodSignatures["java/util/zip/ZipEntry.getMethod()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  zipEntryGetMethod,
+		}
+
+	MethodSignatures["java/util/zip/ZipEntry.getName()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  zipEntryGetName,
+		}
+
+	MethodSignatures["java/util/zip/ZipEntry.getSize()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  zipEntryGetSize,
+		}
+
+	MethodSignatures["java/util/zip/ZipEntry.getTime()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  zipEntryGetTime,
+		}
+
+	MethodSignatures["java/util/zip/ZipEntry.isDirectory()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  zipEntryIsDirectory,
+		}
+
+	MethodSignatures["java/util/zip/ZipEntry.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  zipEntryGetName,
+		}
+
+	// -----------------------------------------
+	// Traps that do nothing but return an error
+	// -----------------------------------------
+
+	MethodSignatures["java/util/zip/ZipFile.<init>(Ljava/io/File;I)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["java/util/zip/ZipFile.getComment()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["java/util/zip/ZipFile.stream()Ljava/util/stream/Stream;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  trapFunction,
+		}
+
+}
+
+var classNameZipEntry = "java/util/zip/ZipEntry"
+var classNameZipEntryIterator = "java/util/zip/ZipFile$ZipEntryIterator"
+
+// The ZipEntry field that holds the modification time in milliseconds. (The JDK's
+// ZipEntry holds it in MS-DOS format.)
+var fieldNameZipEntryTime = "mtimeMillis"
+
+// zipFile is the state of a ZipFile
+type zipFile struct {
+	name    string
+	archive *classloader.Archive
+	closed  bool
+}
+
+// zipEntryIterator is the state of the enumeration returned by ZipFile.entries()
+type zipEntryIterator struct {
+	entries []classloader.ArchiveEntry
+	next    int
+}
+
+// opens the zip file at path for the ZipFile object obj
+func openZipFile(funcName string, obj *object.Object, path string) interface{} {
+	if _, err := os.Stat(path); err != nil {
+		errMsg := fmt.Sprintf("%s: %s (No such file or directory)", funcName, path)
+		return getGErrBlk(excNames.FileNotFoundException, errMsg)
+	}
+
+	archive, err := classloader.NewJarFile(path)
+	if err != nil {
+		errMsg := fmt.Sprintf("%s: %s: zip END header not found", funcName, path)
+		return getGErrBlk(excNames.ZipException, errMsg)
+	}
+
+	zf := &zipFile{name: path, archive: archive}
+	obj.FieldTable[fieldNameZipState] = object.Field{Ftype: types.ZipState, Fvalue: zf}
+	return nil
+}
+
+// returns the zipFile in the ZipFile object params[0]
+func getZipFile(funcName string, params []interface{}) (*zipFile, *GErrBlk) {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return nil, getArgsGErrBlk(funcName, err)
+	}
+	zf, ok := obj.FieldTable[fieldNameZipState].Fvalue.(*zipFile)
+	if !ok || zf.closed {
+		return nil, getGErrBlk(excNames.IllegalStateException, funcName+": zip file closed")
+	}
+	return zf, nil
+}
+
+// returns a ZipEntry object for an entry in an archive
+func newZipEntry(entry classloader.ArchiveEntry) *object.Object {
+	obj := object.MakeEmptyObjectWithClassName(&classNameZipEntry)
+	obj.FieldTable["name"] = object.Field{Ftype: types.StringClassRef,
+		Fvalue: object.StringObjectFromGoString(entry.Name)}
+	obj.FieldTable["size"] = object.Field{Ftype: types.Long, Fvalue: entry.Size}
+	obj.FieldTable["csize"] = object.Field{Ftype: types.Long, Fvalue: entry.CompressedSize}
+	obj.FieldTable["crc"] = object.Field{Ftype: types.Long, Fvalue: int64(entry.CRC32)}
+	obj.FieldTable["method"] = object.Field{Ftype: types.Int, Fvalue: int64(entry.Method)}
+	obj.FieldTable[fieldNameZipEntryTime] = object.Field{Ftype: types.Long, Fvalue: entry.Modified.UnixMilli()}
+
+	comment := object.Null
+	if entry.Comment != "" {
+		comment = object.StringObjectFromGoString(entry.Comment)
+	}
+	obj.FieldTable["comment"] = object.Field{Ftype: types.StringClassRef, Fvalue: comment}
+	return obj
+}
+
+// "java/util/zip/ZipFile.<init>(Ljava/io/File;)V"
+func zipFileInitFile(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("zipFileInitFile", err)
+	}
+	fileObj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("zipFileInitFile", err)
+	}
+	fld, ok := fileObj.FieldTable[FilePath]
+	if !ok {
+		errMsg := "zipFileInitFile: File object argument lacks a FilePath field"
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	path := object.GoStringFromJavaByteArray(fld.Fvalue.([]types.JavaByte))
+	return openZipFile("zipFileInitFile", obj, path)
+}
+
+// "java/util/zip/ZipFile.<init>(Ljava/lang/String;)V"
+func zipFileInitString(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("zipFileInitString", err)
+	}
+	path, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("zipFileInitString", err)
+	}
+	return openZipFile("zipFileInitString", obj, path)
+}
+
+// "java/util/zip/ZipFile.close()V"
+func zipFileClose(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("zipFileClose", err)
+	}
+	if zf, ok := obj.FieldTable[fieldNameZipState].Fvalue.(*zipFile); ok {
+		zf.closed = true
+	}
+	return nil
+}
+
+// "java/util/zip/ZipFile.getName()Ljava/lang/String;"
+func zipFileGetName(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("zipFileGetName", err)
+	}
+	zf, ok := obj.FieldTable[fieldNameZipState].Fvalue.(*zipFile)
+	if !ok {
+		return getGErrBlk(excNames.IllegalStateException, "zipFileGetName: zip file not opened")
+	}
+	return object.StringObjectFromGoString(zf.name) // available after close, as in the JDK
+}
+
+// "java/util/zip/ZipFile.size()I"
+func zipFileSize(params []interface{}) interface{} {
+	zf, gerr := getZipFile("zipFileSize", params)
+	if gerr != nil {
+		return gerr
+	}
+	return int64(len(zf.archive.Entries()))
+}
+
+// "java/util/zip/ZipFile.getEntry(Ljava/lang/String;)Ljava/util/zip/ZipEntry;"
+// Returns null if there's no such entry. As in the JDK, a name without a trailing
+// slash also finds the directory of that name.
+func zipFileGetEntry(params []interface{}) interface{} {
+	zf, gerr := getZipFile("zipFileGetEntry", params)
+	if gerr != nil {
+		return gerr
+	}
+	name, err := args.GetGoString(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("zipFileGetEntry", err)
+	}
+
+	for _, entry := range zf.archive.Entries() {
+		if entry.Name == name || (!strings.HasSuffix(name, "/") && entry.Name == name+"/") {
+			return newZipEntry(entry)
+		}
+	}
+	return object.Null
+}
+
+// "java/util/zip/ZipFile.entries()Ljava/util/Enumeration;"
+func zipFileEntries(params []interface{}) interface{} {
+	zf, gerr := getZipFile("zipFileEntries", params)
+	if gerr != nil {
+		return gerr
+	}
+	iterator := &zipEntryIterator{entries: zf.archive.Entries()}
+	return object.MakeOneFieldObject(classNameZipEntryIterator, fieldNameZipState, types.ZipState, iterator)
+}
+
+// "java/util/zip/ZipFile.getInputStream(Ljava/util/zip/ZipEntry;)Ljava/io/InputStream;"
+// Returns null if there's no such entry in the zip file.
+func zipFileGetInputStream(params []interface{}) interface{} {
+	zf, gerr := getZipFile("zipFileGetInputStream", params)
+	if gerr != nil {
+		return gerr
+	}
+	entryObj, err := args.GetObject(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("zipFileGetInputStream", err)
+	}
+	nameObj, ok := entryObj.FieldTable["name"].Fvalue.(*object.Object)
+	if !ok || object.IsNull(nameObj) {
+		return getGErrBlk(excNames.NullPointerException, "zipFileGetInputStream: ZipEntry has no name")
+	}
+	name := object.GoStringFromStringObject(nameObj)
+
+	data, err := zf.archive.ReadEntry(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return object.Null
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("zipFileGetInputStream: %s: %s", name, err.Error())
+		return getGErrBlk(excNames.ZipException, errMsg)
+	}
+	return newDecompressedInputStream(data)
+}
+
+// "java/util/zip/ZipFile$ZipEntryIterator.hasMoreElements()Z" and
+// "java/util/zip/ZipFile$ZipEntryIterator.hasNext()Z"
+func zipEntryIteratorHasNext(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("zipEntryIteratorHasNext", err)
+	}
+	iterator := obj.FieldTable[fieldNameZipState].Fvalue.(*zipEntryIterator)
+	return object.JavaBooleanFromGoBoolean(iterator.next < len(iterator.entries))
+}
+
+// "java/util/zip/ZipFile$ZipEntryIterator.nextElement()Ljava/util/zip/ZipEntry;",
+// "java/util/zip/ZipFile$ZipEntryIterator.next()Ljava/util/zip/ZipEntry;", and their bridges
+func zipEntryIteratorNext(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("zipEntryIteratorNext", err)
+	}
+	iterator := obj.FieldTable[fieldNameZipState].Fvalue.(*zipEntryIterator)
+	if iterator.next >= len(iterator.entries) {
+		return getGErrBlk(excNames.NoSuchElementException, "zipEntryIteratorNext: no more entries")
+	}
+	iterator.next++
+	return newZipEntry(iterator.entries[iterator.next-1])
+}
+
+// "java/util/zip/ZipEntry.getName()Ljava/lang/String;" and "java/util/zip/ZipEntry.toString()Ljava/lang/String;"
+func zipEntryGetName(params []interface{}) interface{} {
+	return getZipEntryField("zipEntryGetName", params, "name", object.Null)
+}
+
+// "java/util/zip/ZipEntry.getComment()Ljava/lang/String;"
+func zipEntryGetComment(params []interface{}) interface{} {
+	return getZipEntryField("zipEntryGetComment", params, "comment", object.Null)
+}
+
+// "java/util/zip/ZipEntry.getSize()J"
+func zipEntryGetSize(params []interface{}) interface{} {
+	return getZipEntryField("zipEntryGetSize", params, "size", int64(-1))
+}
+
+// "java/util/zip/ZipEntry.getCompressedSize()J"
+func zipEntryGetCompressedSize(params []interface{}) interface{} {
+	return getZipEntryField("zipEntryGetCompressedSize", params, "csize", int64(-1))
+}
+
+// "java/util/zip/ZipEntry.getCrc()J"
+func zipEntryGetCrc(params []interface{}) interface{} {
+	return getZipEntryField("zipEntryGetCrc", params, "crc", int64(-1))
+}
+
+// "java/util/zip/ZipEntry.getMethod()I"
+func zipEntryGetMethod(params []interface{}) interface{} {
+	return getZipEntryField("zipEntryGetMethod", params, "method", int64(-1))
+}
+
+// "java/util/zip/ZipEntry.getTime()J"
+func zipEntryGetTime(params []interface{}) interface{} {
+	return getZipEntryField("zipEntryGetTime", params, fieldNameZipEntryTime, int64(-1))
+}
+
+// "java/util/zip/ZipEntry.isDirectory()Z"
+func zipEntryIsDirectory(params []interface{}) interface{} {
+	nameObj, ok := getZipEntryField("zipEntryIsDirectory", params, "name", object.Null).(*object.Object)
+	if !ok || object.IsNull(nameObj) {
+		return types.JavaBoolFalse
+	}
+	return object.JavaBooleanFromGoBoolean(strings.HasSuffix(object.GoStringFromStringObject(nameObj), "/"))
+}
+
+// returns the value of a field of the ZipEntry object params[0], or the default
+// value if the field is missing
+func getZipEntryField(funcName string, params []interface{}, fieldName string, defaultValue interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk(funcName, err)
+	}
+	fld, ok := obj.FieldTable[fieldName]
+	if !ok || fld.Fvalue == nil {
+		return defaultValue
+	}
+	return fld.Fvalue
+}
@@ -0,0 +1,134 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"archive/zip"
+	"io"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writes a zip file with a directory and two files, and returns its path
+func writeTestZip(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "test.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	_, _ = w.Create("docs/")
+	fw, _ := w.Create("docs/readme.txt")
+	_, _ = fw.Write([]byte("Read me from the zip"))
+	fw, _ = w.Create("hello.txt")
+	_, _ = fw.Write([]byte("Hello"))
+	_ = w.Close()
+	_ = f.Close()
+	return path
+}
+
+func openTestZip(t *testing.T) *object.Object {
+	zf := newZipObject("java/util/zip/ZipFile")
+	ret := zipFileInitString([]interface{}{zf, object.StringObjectFromGoString(writeTestZip(t))})
+	if ret != nil {
+		t.Fatalf("zipFileInitString failed: %v", ret)
+	}
+	return zf
+}
+
+func TestZipFileEntries(t *testing.T) {
+	globals.InitStringPool()
+	zf := openTestZip(t)
+
+	if size := zipFileSize([]interface{}{zf}); size != int64(3) {
+		t.Errorf("Expected 3 entries, got %v", size)
+	}
+
+	var names []string
+	en := zipFileEntries([]interface{}{zf}).(*object.Object)
+	for zipEntryIteratorHasNext([]interface{}{en}) == types.JavaBoolTrue {
+		entry := zipEntryIteratorNext([]interface{}{en}).(*object.Object)
+		names = append(names, object.GoStringFromStringObject(zipEntryGetName([]interface{}{entry}).(*object.Object)))
+	}
+	if len(names) != 3 || names[0] != "docs/" || names[2] != "hello.txt" {
+		t.Errorf("Got unexpected entries: %v", names)
+	}
+
+	ret := zipEntryIteratorNext([]interface{}{en})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NoSuchElementException {
+		t.Errorf("Expected NoSuchElementException after the last entry, got %v", ret)
+	}
+}
+
+func TestZipFileGetEntryAndInputStream(t *testing.T) {
+	globals.InitStringPool()
+	zf := openTestZip(t)
+
+	entry := zipFileGetEntry([]interface{}{zf, object.StringObjectFromGoString("docs/readme.txt")}).(*object.Object)
+	if size := zipEntryGetSize([]interface{}{entry}); size != int64(20) {
+		t.Errorf("Expected size 20, got %v", size)
+	}
+	if zipEntryGetMethod([]interface{}{entry}) != int64(zip.Deflate) {
+		t.Errorf("Expected the entry to be deflated, got method %v", zipEntryGetMethod([]interface{}{entry}))
+	}
+	if zipEntryIsDirectory([]interface{}{entry}) != types.JavaBoolFalse {
+		t.Error("Expected docs/readme.txt not to be a directory")
+	}
+
+	in := zipFileGetInputStream([]interface{}{zf, entry}).(*object.Object)
+	buf := object.Make1DimArray(object.BYTE, 64)
+	n := zipStreamRead([]interface{}{in, buf}).(int64)
+	if string(zipByteArrayContents(buf)[:n]) != "Read me from the zip" {
+		t.Errorf("Got unexpected contents: %q", zipByteArrayContents(buf)[:n])
+	}
+
+	// a name without a trailing slash finds the directory
+	dir := zipFileGetEntry([]interface{}{zf, object.StringObjectFromGoString("docs")})
+	if object.IsNull(dir) || zipEntryIsDirectory([]interface{}{dir}) != types.JavaBoolTrue {
+		t.Error("Expected getEntry(\"docs\") to return the directory")
+	}
+
+	if !object.IsNull(zipFileGetEntry([]interface{}{zf, object.StringObjectFromGoString("nope.txt")})) {
+		t.Error("Expected null for an entry that doesn't exist")
+	}
+
+	_ = zipFileClose([]interface{}{zf})
+	ret := zipFileSize([]interface{}{zf})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IllegalStateException {
+		t.Errorf("Expected IllegalStateException after close(), got %v", ret)
+	}
+	name := zipFileGetName([]interface{}{zf}).(*object.Object)
+	if filepath.Base(object.GoStringFromStringObject(name)) != "test.zip" {
+		t.Errorf("Got unexpected name: %s", object.GoStringFromStringObject(name))
+	}
+}
+
+func TestZipFileOpenErrors(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	globals.SetTraceWriter(io.Discard)
+
+	zf := newZipObject("java/util/zip/ZipFile")
+	missing := filepath.Join(t.TempDir(), "missing.zip")
+	ret := zipFileInitString([]interface{}{zf, object.StringObjectFromGoString(missing)})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.FileNotFoundException {
+		t.Errorf("Expected FileNotFoundException, got %v", ret)
+	}
+
+	notZip := filepath.Join(t.TempDir(), "notzip.zip")
+	_ = os.WriteFile(notZip, []byte("not a zip file"), 0644)
+	ret = zipFileInitString([]interface{}{zf, object.StringObjectFromGoString(notZip)})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.ZipException {
+		t.Errorf("Expected ZipException, got %v", ret)
+	}
+}
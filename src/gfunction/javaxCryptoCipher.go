@@ -0,0 +1,483 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"strings"
+)
+
+// Cipher supports the AES/GCM/NoPadding transformation with Go's crypto/aes and
+// crypto/cipher. GCM authenticates the whole message, so update() buffers its input
+// and returns no output; the work is done by doFinal(). The state of the cipher is
+// kept in a cipherState struct in the Cipher object.
+
+func Load_Crypto_Cipher() {
+
+	MethodSignatures["javax/crypto/Cipher.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.doFinal()[B"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  cipherDoFinal,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.doFinal([B)[B"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  cipherDoFinal,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.doFinal([BII)[B"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  cipherDoFinal,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.getAlgorithm()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  cipherGetAlgorithm,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.getBlockSize()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  cipherGetBlockSize,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.getIV()[B"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  cipherGetIV,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.getInstance(Ljava/lang/String;)Ljavax/crypto/Cipher;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  cipherGetInstance,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.getOutputSize(I)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  cipherGetOutputSize,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.init(ILjava/security/Key;)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  cipherInit,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.init(ILjava/security/Key;Ljava/security/SecureRandom;)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  cipherInit,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.init(ILjava/security/Key;Ljava/security/spec/AlgorithmParameterSpec;)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  cipherInit,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.init(ILjava/security/Key;Ljava/security/spec/AlgorithmParameterSpec;Ljava/security/SecureRandom;)V"] =
+		GMeth{
+			ParamSlots: 4,
+			GFunction:  cipherInit,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  cipherToString,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.update([B)[B"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  cipherUpdate,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.update([BII)[B"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  cipherUpdate,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.updateAAD([B)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  cipherUpdateAAD,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.updateAAD([BII)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  cipherUpdateAAD,
+		}
+
+	// -----------------------------------------
+	// Traps that do nothing but return an error
+	// -----------------------------------------
+
+	MethodSignatures["javax/crypto/Cipher.doFinal([BI)I"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.doFinal([BII[B)I"] =
+		GMeth{
+			ParamSlots: 4,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.getInstance(Ljava/lang/String;Ljava/lang/String;)Ljavax/crypto/Cipher;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.getInstance(Ljava/lang/String;Ljava/security/Provider;)Ljavax/crypto/Cipher;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.getParameters()Ljava/security/AlgorithmParameters;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.getProvider()Ljava/security/Provider;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.update([BII[B)I"] =
+		GMeth{
+			ParamSlots: 4,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["javax/crypto/Cipher.wrap(Ljava/security/Key;)[B"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  trapFunction,
+		}
+
+}
+
+var classNameCipher = "javax/crypto/Cipher"
+
+// The fields of the Cipher object
+var fieldNameCipherState = "cipherState"
+var fieldNameCipherTransformation = "transformation"
+
+// The Cipher operation modes
+const (
+	cipherEncryptMode = 1
+	cipherDecryptMode = 2
+)
+
+// The GCM parameters used when init() isn't passed a GCMParameterSpec
+const (
+	gcmDefaultIVLength  = 12
+	gcmDefaultTagLength = 16
+)
+
+// cipherState is the state of a Cipher
+type cipherState struct {
+	mode      int64
+	aead      cipher.AEAD
+	iv        []byte
+	tagLength int
+	aad       []byte // additional authenticated data passed to updateAAD()
+	input     []byte // input passed to update() and not yet processed
+	ivUsed    bool   // doFinal() has encrypted with the IV, which can't be reused
+}
+
+// returns the state of the Cipher object params[0], which must have been initialized
+func getCipherState(funcName string, params []interface{}) (*cipherState, *GErrBlk) {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return nil, getArgsGErrBlk(funcName, err)
+	}
+	cs, ok := obj.FieldTable[fieldNameCipherState].Fvalue.(*cipherState)
+	if !ok || cs.aead == nil {
+		return nil, getGErrBlk(excNames.IllegalStateException, funcName+": Cipher not initialized")
+	}
+	if cs.mode == cipherEncryptMode && cs.ivUsed {
+		errMsg := funcName + ": Cannot reuse iv for GCM encryption"
+		return nil, getGErrBlk(excNames.IllegalStateException, errMsg)
+	}
+	return cs, nil
+}
+
+// "javax/crypto/Cipher.getInstance(Ljava/lang/String;)Ljavax/crypto/Cipher;"
+// The transformation isn't case-sensitive.
+func cipherGetInstance(params []interface{}) interface{} {
+	if object.IsNull(params[0]) {
+		return getGErrBlk(excNames.NoSuchAlgorithmException, "cipherGetInstance: Null or empty transformation")
+	}
+	transformation, err := args.GetGoString(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("cipherGetInstance", err)
+	}
+
+	parts := strings.Split(strings.ToUpper(strings.TrimSpace(transformation)), "/")
+	if len(parts) != 3 || parts[0] != "AES" || parts[1] != "GCM" {
+		errMsg := fmt.Sprintf("Cannot find any provider supporting %s", transformation)
+		return getGErrBlk(excNames.NoSuchAlgorithmException, errMsg)
+	}
+	if parts[2] != "NOPADDING" {
+		errMsg := fmt.Sprintf("Unsupported padding %s", parts[2])
+		return getGErrBlk(excNames.NoSuchPaddingException, errMsg)
+	}
+
+	obj := object.MakeEmptyObjectWithClassName(&classNameCipher)
+	obj.FieldTable[fieldNameCipherTransformation] = object.Field{
+		Ftype: types.StringClassRef, Fvalue: object.StringObjectFromGoString(transformation)}
+	obj.FieldTable[fieldNameCipherState] = object.Field{Ftype: types.CipherState, Fvalue: &cipherState{}}
+	return obj
+}
+
+// "javax/crypto/Cipher.init(ILjava/security/Key;)V" and the init() methods that add an
+// AlgorithmParameterSpec, a SecureRandom, or both. When encrypting without a
+// GCMParameterSpec, a random IV is generated with crypto/rand. The SecureRandom
+// parameter is ignored.
+func cipherInit(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("cipherInit", err)
+	}
+	mode, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("cipherInit", err)
+	}
+	if mode != cipherEncryptMode && mode != cipherDecryptMode {
+		errMsg := fmt.Sprintf("cipherInit: unsupported opmode %d", mode)
+		return getGErrBlk(excNames.UnsupportedOperationException, errMsg)
+	}
+
+	// the key must be a SecretKeySpec holding an AES key
+	if object.IsNull(params[2]) {
+		return getGErrBlk(excNames.InvalidKeyException, "cipherInit: No installed provider supports this key: (null)")
+	}
+	keyObj, err := args.GetObject(params, 2)
+	if err != nil {
+		return getArgsGErrBlk("cipherInit", err)
+	}
+	key, ok := keyObj.FieldTable[fieldNameSpecKey].Fvalue.([]types.JavaByte)
+	if !ok {
+		return getGErrBlk(excNames.InvalidKeyException, "cipherInit: Key is not a SecretKeySpec")
+	}
+	block, err := aes.NewCipher(object.GoByteArrayFromJavaByteArray(key))
+	if err != nil {
+		errMsg := fmt.Sprintf("Invalid AES key length: %d bytes", len(key))
+		return getGErrBlk(excNames.InvalidKeyException, errMsg)
+	}
+
+	// the IV and tag length come from a GCMParameterSpec, if one was passed
+	var iv []byte
+	tagLength := gcmDefaultTagLength
+	var specObj *object.Object
+	if len(params) > 3 && !object.IsNull(params[3]) {
+		specObj, _ = params[3].(*object.Object)
+		if specObj != nil && object.GoStringFromStringPoolIndex(specObj.KlassName) == secureRandomClassName {
+			specObj = nil
+		}
+	}
+	if specObj != nil {
+		if _, isGCMSpec := specObj.FieldTable[fieldNameSpecTLen]; !isGCMSpec {
+			return getGErrBlk(excNames.InvalidAlgorithmParameterException, "Unsupported parameter: not a GCMParameterSpec")
+		}
+		tLen := specObj.FieldTable[fieldNameSpecTLen].Fvalue.(int64)
+		if tLen < 96 || tLen > 128 || tLen%8 != 0 {
+			errMsg := fmt.Sprintf("Unsupported TLen value.  Must be one of {128, 120, 112, 104, 96}: %d", tLen)
+			return getGErrBlk(excNames.InvalidAlgorithmParameterException, errMsg)
+		}
+		tagLength = int(tLen / 8)
+		iv = object.GoByteArrayFromJavaByteArray(specObj.FieldTable[fieldNameSpecIV].Fvalue.([]types.JavaByte))
+		if len(iv) == 0 {
+			return getGErrBlk(excNames.InvalidAlgorithmParameterException, "IV is empty")
+		}
+	} else {
+		if mode == cipherDecryptMode {
+			return getGErrBlk(excNames.InvalidKeyException, "Parameters missing")
+		}
+		iv = make([]byte, gcmDefaultIVLength)
+		if _, err = rand.Read(iv); err != nil {
+			return getGErrBlk(excNames.InternalException, "cipherInit: "+err.Error())
+		}
+	}
+
+	// Go supports a nonstandard IV length or a nonstandard tag length, but not both
+	var aead cipher.AEAD
+	switch {
+	case len(iv) == gcmDefaultIVLength:
+		aead, err = cipher.NewGCMWithTagSize(block, tagLength)
+	case tagLength == gcmDefaultTagLength:
+		aead, err = cipher.NewGCMWithNonceSize(block, len(iv))
+	default:
+		errMsg := fmt.Sprintf("cipherInit: a %d-byte IV requires a 128-bit tag", len(iv))
+		return getGErrBlk(excNames.InvalidAlgorithmParameterException, errMsg)
+	}
+	if err != nil {
+		return getGErrBlk(excNames.InvalidAlgorithmParameterException, "cipherInit: "+err.Error())
+	}
+
+	cs := &cipherState{mode: mode, aead: aead, iv: iv, tagLength: tagLength}
+	obj.FieldTable[fieldNameCipherState] = object.Field{Ftype: types.CipherState, Fvalue: cs}
+	return nil
+}
+
+// "javax/crypto/Cipher.updateAAD([B)V" and "javax/crypto/Cipher.updateAAD([BII)V"
+// The additional authenticated data must all be passed before the input.
+func cipherUpdateAAD(params []interface{}) interface{} {
+	cs, gerr := getCipherState("cipherUpdateAAD", params)
+	if gerr != nil {
+		return gerr
+	}
+	if len(cs.input) > 0 {
+		return getGErrBlk(excNames.IllegalStateException, "cipherUpdateAAD: Update has been called; no more AAD data")
+	}
+	aad, gerr := getByteArrayRange("cipherUpdateAAD", params, 1)
+	if gerr != nil {
+		return gerr
+	}
+	cs.aad = append(cs.aad, aad...)
+	return nil
+}
+
+// "javax/crypto/Cipher.update([B)[B" and "javax/crypto/Cipher.update([BII)[B"
+// Buffers the input for doFinal() and returns an empty array.
+func cipherUpdate(params []interface{}) interface{} {
+	cs, gerr := getCipherState("cipherUpdate", params)
+	if gerr != nil {
+		return gerr
+	}
+	input, gerr := getByteArrayRange("cipherUpdate", params, 1)
+	if gerr != nil {
+		return gerr
+	}
+	cs.input = append(cs.input, input...)
+	return Populator("[B", types.ByteArray, []types.JavaByte{})
+}
+
+// "javax/crypto/Cipher.doFinal()[B", "javax/crypto/Cipher.doFinal([B)[B", and
+// "javax/crypto/Cipher.doFinal([BII)[B"
+// Encrypts or decrypts the buffered input plus the bytes passed, if any. Encryption
+// appends the tag to the ciphertext; decryption expects to find it there. Afterwards,
+// a decrypting Cipher can be reused, while an encrypting one must be initialized again.
+func cipherDoFinal(params []interface{}) interface{} {
+	cs, gerr := getCipherState("cipherDoFinal", params)
+	if gerr != nil {
+		return gerr
+	}
+	if len(params) > 1 {
+		input, gerr := getByteArrayRange("cipherDoFinal", params, 1)
+		if gerr != nil {
+			return gerr
+		}
+		cs.input = append(cs.input, input...)
+	}
+
+	input, aad := cs.input, cs.aad
+	cs.input, cs.aad = nil, nil
+
+	var output []byte
+	if cs.mode == cipherEncryptMode {
+		output = cs.aead.Seal(nil, cs.iv, input, aad)
+		cs.ivUsed = true
+	} else {
+		if len(input) < cs.tagLength {
+			errMsg := fmt.Sprintf("Input data too short to contain an expected tag length of %dbytes", cs.tagLength)
+			return getGErrBlk(excNames.AEADBadTagException, errMsg)
+		}
+		var err error
+		if output, err = cs.aead.Open(nil, cs.iv, input, aad); err != nil {
+			return getGErrBlk(excNames.AEADBadTagException, "Tag mismatch")
+		}
+	}
+	return Populator("[B", types.ByteArray, object.JavaByteArrayFromGoByteArray(output))
+}
+
+// "javax/crypto/Cipher.getOutputSize(I)I"
+func cipherGetOutputSize(params []interface{}) interface{} {
+	cs, gerr := getCipherState("cipherGetOutputSize", params)
+	if gerr != nil {
+		return gerr
+	}
+	inputLen, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("cipherGetOutputSize", err)
+	}
+	if inputLen < 0 {
+		return getGErrBlk(excNames.IllegalArgumentException, "cipherGetOutputSize: Input size must be equal to or greater than zero")
+	}
+
+	total := int64(len(cs.input)) + inputLen
+	if cs.mode == cipherEncryptMode {
+		return total + int64(cs.tagLength)
+	}
+	return max(total-int64(cs.tagLength), 0)
+}
+
+// "javax/crypto/Cipher.getIV()[B"
+// Returns null if the Cipher hasn't been initialized.
+func cipherGetIV(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("cipherGetIV", err)
+	}
+	cs, ok := obj.FieldTable[fieldNameCipherState].Fvalue.(*cipherState)
+	if !ok || cs.iv == nil {
+		return object.Null
+	}
+	return Populator("[B", types.ByteArray, object.JavaByteArrayFromGoByteArray(cs.iv))
+}
+
+// "javax/crypto/Cipher.getAlgorithm()Ljava/lang/String;"
+// Returns the transformation passed to getInstance().
+func cipherGetAlgorithm(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("cipherGetAlgorithm", err)
+	}
+	return obj.FieldTable[fieldNameCipherTransformation].Fvalue
+}
+
+// "javax/crypto/Cipher.getBlockSize()I"
+func cipherGetBlockSize(params []interface{}) interface{} {
+	return int64(aes.BlockSize)
+}
+
+// "javax/crypto/Cipher.toString()Ljava/lang/String;"
+func cipherToString(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("cipherToString", err)
+	}
+	transformation := object.GoStringFromStringObject(obj.FieldTable[fieldNameCipherTransformation].Fvalue.(*object.Object))
+	return object.StringObjectFromGoString("Cipher." + transformation + ", from Jacobin")
+}
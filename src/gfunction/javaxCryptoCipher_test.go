@@ -0,0 +1,166 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"testing"
+)
+
+// returns an AES/GCM/NoPadding Cipher, failing the test if there is none
+func newGCMCipher(t *testing.T) *object.Object {
+	t.Helper()
+	ret := cipherGetInstance([]interface{}{object.StringObjectFromGoString("AES/GCM/NoPadding")})
+	c, ok := ret.(*object.Object)
+	if !ok {
+		t.Fatalf("getInstance: expected a Cipher, got %v", ret)
+	}
+	return c
+}
+
+// returns a SecretKeySpec holding an AES key
+func newAESKey(t *testing.T, key []byte) *object.Object {
+	t.Helper()
+	keySpec := newZipObject("javax/crypto/spec/SecretKeySpec")
+	ret := secretKeySpecInit([]interface{}{keySpec, newZipByteArray(key), object.StringObjectFromGoString("AES")})
+	if ret != nil {
+		t.Fatalf("SecretKeySpec.<init>: %v", ret)
+	}
+	return keySpec
+}
+
+// returns a GCMParameterSpec with the tag length in bits and the IV
+func newGCMSpec(t *testing.T, tLen int64, iv []byte) *object.Object {
+	t.Helper()
+	spec := newZipObject("javax/crypto/spec/GCMParameterSpec")
+	if ret := gcmParameterSpecInit([]interface{}{spec, tLen, newZipByteArray(iv)}); ret != nil {
+		t.Fatalf("GCMParameterSpec.<init>: %v", ret)
+	}
+	return spec
+}
+
+func TestCipherGCMRoundTrip(t *testing.T) {
+	globals.InitStringPool()
+
+	key := bytes.Repeat([]byte{0x42}, 16)
+	iv := []byte("123456789012")
+	aad := []byte("header")
+	plaintext := []byte("attack at dawn, bring snacks")
+
+	enc := newGCMCipher(t)
+	if ret := cipherInit([]interface{}{enc, int64(cipherEncryptMode), newAESKey(t, key), newGCMSpec(t, 128, iv)}); ret != nil {
+		t.Fatalf("init(ENCRYPT_MODE): %v", ret)
+	}
+	if ret := cipherUpdateAAD([]interface{}{enc, newZipByteArray(aad)}); ret != nil {
+		t.Fatalf("updateAAD: %v", ret)
+	}
+	ret := cipherUpdate([]interface{}{enc, newZipByteArray(plaintext), int64(0), int64(6)})
+	if got := zipByteArrayContents(ret.(*object.Object)); len(got) != 0 {
+		t.Errorf("update returned %d bytes, want 0", len(got))
+	}
+	if size := cipherGetOutputSize([]interface{}{enc, int64(len(plaintext) - 6)}).(int64); size != int64(len(plaintext)+16) {
+		t.Errorf("getOutputSize = %d, want %d", size, len(plaintext)+16)
+	}
+	ret = cipherDoFinal([]interface{}{enc, newZipByteArray(plaintext[6:])})
+	ciphertext := zipByteArrayContents(ret.(*object.Object))
+
+	// the ciphertext must match what Go's GCM produces
+	block, _ := aes.NewCipher(key)
+	aead, _ := cipher.NewGCM(block)
+	if want := aead.Seal(nil, iv, plaintext, aad); !bytes.Equal(ciphertext, want) {
+		t.Fatalf("ciphertext = %x, want %x", ciphertext, want)
+	}
+
+	// an encrypting Cipher can't reuse its IV
+	ret = cipherDoFinal([]interface{}{enc})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalStateException {
+		t.Errorf("second doFinal: expected IllegalStateException, got %v", ret)
+	}
+
+	dec := newGCMCipher(t)
+	if ret := cipherInit([]interface{}{dec, int64(cipherDecryptMode), newAESKey(t, key), newGCMSpec(t, 128, iv)}); ret != nil {
+		t.Fatalf("init(DECRYPT_MODE): %v", ret)
+	}
+	_ = cipherUpdateAAD([]interface{}{dec, newZipByteArray(aad)})
+	ret = cipherDoFinal([]interface{}{dec, newZipByteArray(ciphertext)})
+	if got := zipByteArrayContents(ret.(*object.Object)); !bytes.Equal(got, plaintext) {
+		t.Errorf("decrypted %q, want %q", got, plaintext)
+	}
+
+	// a tampered ciphertext fails authentication
+	ciphertext[0] ^= 1
+	_ = cipherUpdateAAD([]interface{}{dec, newZipByteArray(aad)})
+	ret = cipherDoFinal([]interface{}{dec, newZipByteArray(ciphertext)})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.AEADBadTagException {
+		t.Errorf("tampered ciphertext: expected AEADBadTagException, got %v", ret)
+	}
+}
+
+func TestCipherGCMRandomIV(t *testing.T) {
+	globals.InitStringPool()
+
+	key := newAESKey(t, bytes.Repeat([]byte{7}, 32))
+	enc := newGCMCipher(t)
+	if ret := cipherInit([]interface{}{enc, int64(cipherEncryptMode), key}); ret != nil {
+		t.Fatalf("init(ENCRYPT_MODE) without parameters: %v", ret)
+	}
+	iv := zipByteArrayContents(cipherGetIV([]interface{}{enc}).(*object.Object))
+	if len(iv) != gcmDefaultIVLength {
+		t.Fatalf("getIV returned %d bytes, want %d", len(iv), gcmDefaultIVLength)
+	}
+	ciphertext := zipByteArrayContents(cipherDoFinal([]interface{}{enc, newZipByteArray([]byte("hi"))}).(*object.Object))
+
+	dec := newGCMCipher(t)
+	if ret := cipherInit([]interface{}{dec, int64(cipherDecryptMode), key, newGCMSpec(t, 128, iv)}); ret != nil {
+		t.Fatalf("init(DECRYPT_MODE): %v", ret)
+	}
+	if got := zipByteArrayContents(cipherDoFinal([]interface{}{dec, newZipByteArray(ciphertext)}).(*object.Object)); string(got) != "hi" {
+		t.Errorf("decrypted %q, want %q", got, "hi")
+	}
+}
+
+func TestCipherErrors(t *testing.T) {
+	globals.InitStringPool()
+
+	expectException := func(what string, ret interface{}, want int) {
+		t.Helper()
+		if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != want {
+			t.Errorf("%s: expected %s, got %v", what, excNames.JVMexceptionNames[want], ret)
+		}
+	}
+
+	expectException("getInstance(DES/CBC/NoPadding)",
+		cipherGetInstance([]interface{}{object.StringObjectFromGoString("DES/CBC/NoPadding")}),
+		excNames.NoSuchAlgorithmException)
+	expectException("getInstance(AES/GCM/PKCS5Padding)",
+		cipherGetInstance([]interface{}{object.StringObjectFromGoString("AES/GCM/PKCS5Padding")}),
+		excNames.NoSuchPaddingException)
+
+	c := newGCMCipher(t)
+	expectException("doFinal before init", cipherDoFinal([]interface{}{c}), excNames.IllegalStateException)
+	expectException("init with a 15-byte key",
+		cipherInit([]interface{}{c, int64(cipherEncryptMode), newAESKey(t, make([]byte, 15))}),
+		excNames.InvalidKeyException)
+
+	key := newAESKey(t, make([]byte, 16))
+	expectException("decrypt without parameters",
+		cipherInit([]interface{}{c, int64(cipherDecryptMode), key}),
+		excNames.InvalidKeyException)
+	expectException("init with a 64-bit tag",
+		cipherInit([]interface{}{c, int64(cipherEncryptMode), key, newGCMSpec(t, 64, make([]byte, 12))}),
+		excNames.InvalidAlgorithmParameterException)
+
+	_ = cipherInit([]interface{}{c, int64(cipherDecryptMode), key, newGCMSpec(t, 128, make([]byte, 12))})
+	expectException("decrypt a short input",
+		cipherDoFinal([]interface{}{c, newZipByteArray(make([]byte, 10))}),
+		excNames.AEADBadTagException)
+}
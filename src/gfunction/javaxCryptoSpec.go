@@ -0,0 +1,206 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/excNames"
+	"jacobin/src/gfunction/args"
+	"jacobin/src/object"
+	"jacobin/src/types"
+)
+
+// SecretKeySpec and GCMParameterSpec hold the key and the parameters that are passed
+// to Cipher.init(). Each keeps a copy of the bytes passed to its constructor.
+
+func Load_Crypto_Spec() {
+
+	MethodSignatures["javax/crypto/spec/GCMParameterSpec.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["javax/crypto/spec/GCMParameterSpec.<init>(I[B)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  gcmParameterSpecInit,
+		}
+
+	MethodSignatures["javax/crypto/spec/GCMParameterSpec.<init>(I[BII)V"] =
+		GMeth{
+			ParamSlots: 4,
+			GFunction:  gcmParameterSpecInit,
+		}
+
+	MethodSignatures["javax/crypto/spec/GCMParameterSpec.getIV()[B"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  gcmParameterSpecGetIV,
+		}
+
+	MethodSignatures["javax/crypto/spec/GCMParameterSpec.getTLen()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  gcmParameterSpecGetTLen,
+		}
+
+	MethodSignatures["javax/crypto/spec/SecretKeySpec.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["javax/crypto/spec/SecretKeySpec.<init>([BLjava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  secretKeySpecInit,
+		}
+
+	MethodSignatures["javax/crypto/spec/SecretKeySpec.<init>([BIILjava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 4,
+			GFunction:  secretKeySpecInit,
+		}
+
+	MethodSignatures["javax/crypto/spec/SecretKeySpec.getAlgorithm()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  secretKeySpecGetAlgorithm,
+		}
+
+	MethodSignatures["javax/crypto/spec/SecretKeySpec.getEncoded()[B"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  secretKeySpecGetEncoded,
+		}
+
+	MethodSignatures["javax/crypto/spec/SecretKeySpec.getFormat()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  secretKeySpecGetFormat,
+		}
+
+}
+
+// The fields of the spec objects
+var fieldNameSpecAlgorithm = "algorithm"
+var fieldNameSpecIV = "iv"
+var fieldNameSpecKey = "key"
+var fieldNameSpecTLen = "tLen"
+
+// "javax/crypto/spec/SecretKeySpec.<init>([BLjava/lang/String;)V" and
+// "javax/crypto/spec/SecretKeySpec.<init>([BIILjava/lang/String;)V"
+func secretKeySpecInit(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("secretKeySpecInit", err)
+	}
+
+	last := len(params) - 1
+	if object.IsNull(params[1]) || object.IsNull(params[last]) {
+		return getGErrBlk(excNames.IllegalArgumentException, "secretKeySpecInit: Missing argument")
+	}
+	key, gerr := getByteArrayRange("secretKeySpecInit", params[:last], 1)
+	if gerr != nil {
+		return gerr
+	}
+	if len(key) == 0 {
+		return getGErrBlk(excNames.IllegalArgumentException, "secretKeySpecInit: Empty key")
+	}
+	algorithm, err := args.GetObject(params, last)
+	if err != nil {
+		return getArgsGErrBlk("secretKeySpecInit", err)
+	}
+
+	keyCopy := object.JavaByteArrayFromGoByteArray(key)
+	obj.FieldTable[fieldNameSpecKey] = object.Field{Ftype: types.ByteArray, Fvalue: keyCopy}
+	obj.FieldTable[fieldNameSpecAlgorithm] = object.Field{Ftype: types.StringClassRef, Fvalue: algorithm}
+	return nil
+}
+
+// "javax/crypto/spec/SecretKeySpec.getAlgorithm()Ljava/lang/String;"
+func secretKeySpecGetAlgorithm(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("secretKeySpecGetAlgorithm", err)
+	}
+	return obj.FieldTable[fieldNameSpecAlgorithm].Fvalue
+}
+
+// "javax/crypto/spec/SecretKeySpec.getEncoded()[B"
+// Returns a copy of the key.
+func secretKeySpecGetEncoded(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("secretKeySpecGetEncoded", err)
+	}
+	key, ok := obj.FieldTable[fieldNameSpecKey].Fvalue.([]types.JavaByte)
+	if !ok {
+		return object.Null
+	}
+	keyCopy := make([]types.JavaByte, len(key))
+	copy(keyCopy, key)
+	return Populator("[B", types.ByteArray, keyCopy)
+}
+
+// "javax/crypto/spec/SecretKeySpec.getFormat()Ljava/lang/String;"
+func secretKeySpecGetFormat(params []interface{}) interface{} {
+	return object.StringObjectFromGoString("RAW")
+}
+
+// "javax/crypto/spec/GCMParameterSpec.<init>(I[B)V" and
+// "javax/crypto/spec/GCMParameterSpec.<init>(I[BII)V"
+// The tag length is in bits.
+func gcmParameterSpecInit(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("gcmParameterSpecInit", err)
+	}
+	tLen, err := args.GetInt64(params, 1)
+	if err != nil {
+		return getArgsGErrBlk("gcmParameterSpecInit", err)
+	}
+	if tLen < 0 {
+		return getGErrBlk(excNames.IllegalArgumentException, "gcmParameterSpecInit: Length argument is negative")
+	}
+	if object.IsNull(params[2]) {
+		return getGErrBlk(excNames.IllegalArgumentException, "gcmParameterSpecInit: src array is null")
+	}
+	iv, gerr := getByteArrayRange("gcmParameterSpecInit", params, 2)
+	if gerr != nil {
+		return gerr
+	}
+
+	obj.FieldTable[fieldNameSpecTLen] = object.Field{Ftype: types.Int, Fvalue: tLen}
+	obj.FieldTable[fieldNameSpecIV] = object.Field{Ftype: types.ByteArray, Fvalue: object.JavaByteArrayFromGoByteArray(iv)}
+	return nil
+}
+
+// "javax/crypto/spec/GCMParameterSpec.getIV()[B"
+// Returns a copy of the IV.
+func gcmParameterSpecGetIV(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("gcmParameterSpecGetIV", err)
+	}
+	iv, ok := obj.FieldTable[fieldNameSpecIV].Fvalue.([]types.JavaByte)
+	if !ok {
+		return object.Null
+	}
+	ivCopy := make([]types.JavaByte, len(iv))
+	copy(ivCopy, iv)
+	return Populator("[B", types.ByteArray, ivCopy)
+}
+
+// "javax/crypto/spec/GCMParameterSpec.getTLen()I"
+func gcmParameterSpecGetTLen(params []interface{}) interface{} {
+	obj, err := args.GetObject(params, 0)
+	if err != nil {
+		return getArgsGErrBlk("gcmParameterSpecGetTLen", err)
+	}
+	return obj.FieldTable[fieldNameSpecTLen].Fvalue
+}
@@ -0,0 +1,69 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"bytes"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/types"
+	"testing"
+)
+
+func TestSecretKeySpec(t *testing.T) {
+	globals.InitStringPool()
+
+	// SecretKeySpec(byte[] key, int offset, int len, String algorithm)
+	keyBytes := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	keyArray := newZipByteArray(keyBytes)
+	keySpec := newZipObject("javax/crypto/spec/SecretKeySpec")
+	ret := secretKeySpecInit([]interface{}{keySpec, keyArray, int64(2), int64(4), object.StringObjectFromGoString("AES")})
+	if ret != nil {
+		t.Fatalf("SecretKeySpec.<init>: %v", ret)
+	}
+
+	// the key is a copy, so changing the array passed doesn't change it
+	keyArray.FieldTable["value"].Fvalue.([]types.JavaByte)[2] = 99
+	if got := zipByteArrayContents(secretKeySpecGetEncoded([]interface{}{keySpec}).(*object.Object)); !bytes.Equal(got, keyBytes[2:6]) {
+		t.Errorf("getEncoded = %v, want %v", got, keyBytes[2:6])
+	}
+	algorithm := secretKeySpecGetAlgorithm([]interface{}{keySpec}).(*object.Object)
+	if got := object.GoStringFromStringObject(algorithm); got != "AES" {
+		t.Errorf("getAlgorithm = %q, want %q", got, "AES")
+	}
+	format := secretKeySpecGetFormat([]interface{}{keySpec}).(*object.Object)
+	if got := object.GoStringFromStringObject(format); got != "RAW" {
+		t.Errorf("getFormat = %q, want %q", got, "RAW")
+	}
+
+	ret = secretKeySpecInit([]interface{}{keySpec, newZipByteArray(nil), object.StringObjectFromGoString("AES")})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("empty key: expected IllegalArgumentException, got %v", ret)
+	}
+}
+
+func TestGCMParameterSpec(t *testing.T) {
+	globals.InitStringPool()
+
+	iv := []byte("0123456789abcdef")
+	spec := newZipObject("javax/crypto/spec/GCMParameterSpec")
+	if ret := gcmParameterSpecInit([]interface{}{spec, int64(96), newZipByteArray(iv), int64(4), int64(12)}); ret != nil {
+		t.Fatalf("GCMParameterSpec.<init>: %v", ret)
+	}
+	if tLen := gcmParameterSpecGetTLen([]interface{}{spec}).(int64); tLen != 96 {
+		t.Errorf("getTLen = %d, want 96", tLen)
+	}
+	if got := zipByteArrayContents(gcmParameterSpecGetIV([]interface{}{spec}).(*object.Object)); !bytes.Equal(got, iv[4:]) {
+		t.Errorf("getIV = %q, want %q", got, iv[4:])
+	}
+
+	ret := gcmParameterSpecInit([]interface{}{spec, int64(-1), newZipByteArray(iv)})
+	if gerr, ok := ret.(*GErrBlk); !ok || gerr.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("negative tag length: expected IllegalArgumentException, got %v", ret)
+	}
+}
@@ -0,0 +1,387 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/frames"
+	"jacobin/src/object"
+	"jacobin/src/statics"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+	"strings"
+	"time"
+)
+
+// Shims for the JDK internals that java.base classes call as they load and initialize:
+// jdk/internal/misc/VM and CDS, jdk/internal/access/SharedSecrets, jdk/internal/util/
+// Preconditions, and jdk/internal/reflect/Reflection. Jacobin doesn't run the JDK's
+// initPhase1-3, so these answer as the JDK does once the system is fully booted, without
+// a class-data-sharing archive. Most are no-ops or constants; the ones that do real work,
+// such as the Preconditions checks, do it as the JDK does.
+
+var classNamePreconditions = "jdk/internal/util/Preconditions"
+var classNamePreconditionsFormatter = "jdk/internal/util/Preconditions$Formatter"
+
+// the init level that jdk/internal/misc/VM reports: SYSTEM_BOOTED, at which everything,
+// including the module system and the system class loader, is initialized
+const vmSystemBooted = 4
+
+// the standard exception formatters of Preconditions, by the names of their statics
+var preconditionsFormatters = map[string]int{
+	"AIOOBE_FORMATTER": excNames.ArrayIndexOutOfBoundsException,
+	"IOOBE_FORMATTER":  excNames.IndexOutOfBoundsException,
+	"SIOOBE_FORMATTER": excNames.StringIndexOutOfBoundsException,
+}
+
+func Load_Jdk_Internal_Shims() {
+
+	// --- jdk/internal/access/SharedSecrets ---
+
+	MethodSignatures["jdk/internal/access/SharedSecrets.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	// --- jdk/internal/misc/CDS: there's never an archive to share or dump ---
+
+	MethodSignatures["jdk/internal/misc/CDS.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["jdk/internal/misc/CDS.getRandomSeedForDumping()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  returnRandomLong,
+		}
+
+	MethodSignatures["jdk/internal/misc/CDS.initializeFromArchive(Ljava/lang/Class;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  justReturn,
+		}
+
+	MethodSignatures["jdk/internal/misc/CDS.isDumpingArchive()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  returnFalse,
+		}
+
+	MethodSignatures["jdk/internal/misc/CDS.isDumpingArchive0()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  returnFalse,
+		}
+
+	MethodSignatures["jdk/internal/misc/CDS.isDumpingClassList()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  returnFalse,
+		}
+
+	MethodSignatures["jdk/internal/misc/CDS.isDumpingClassList0()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  returnFalse,
+		}
+
+	MethodSignatures["jdk/internal/misc/CDS.isDumpingStaticArchive()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  returnFalse,
+		}
+
+	MethodSignatures["jdk/internal/misc/CDS.isSharingEnabled()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  returnFalse,
+		}
+
+	MethodSignatures["jdk/internal/misc/CDS.isSharingEnabled0()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  returnFalse,
+		}
+
+	MethodSignatures["jdk/internal/misc/CDS.logLambdaFormInvoker(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  justReturn,
+		}
+
+	// --- jdk/internal/misc/VM ---
+
+	MethodSignatures["jdk/internal/misc/VM.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	MethodSignatures["jdk/internal/misc/VM.awaitInitLevel(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  justReturn,
+		}
+
+	MethodSignatures["jdk/internal/misc/VM.getNanoTimeAdjustment(J)J"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  vmGetNanoTimeAdjustment,
+		}
+
+	MethodSignatures["jdk/internal/misc/VM.getSavedProperties()Ljava/util/Map;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  systemGetProperties,
+		}
+
+	MethodSignatures["jdk/internal/misc/VM.getSavedProperty(Ljava/lang/String;)Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  systemGetProperty,
+		}
+
+	MethodSignatures["jdk/internal/misc/VM.initLevel()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  vmInitLevel,
+		}
+
+	MethodSignatures["jdk/internal/misc/VM.initLevel(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  justReturn,
+		}
+
+	MethodSignatures["jdk/internal/misc/VM.initialize()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  justReturn,
+		}
+
+	MethodSignatures["jdk/internal/misc/VM.initializeOSEnvironment()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  justReturn,
+		}
+
+	MethodSignatures["jdk/internal/misc/VM.isBooted()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  returnTrue,
+		}
+
+	MethodSignatures["jdk/internal/misc/VM.isDirectMemoryPageAligned()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  returnFalse,
+		}
+
+	MethodSignatures["jdk/internal/misc/VM.isModuleSystemInited()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  returnTrue,
+		}
+
+	MethodSignatures["jdk/internal/misc/VM.isSetUID()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  returnFalse,
+		}
+
+	MethodSignatures["jdk/internal/misc/VM.isSystemDomainLoader(Ljava/lang/ClassLoader;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  vmIsSystemDomainLoader,
+		}
+
+	MethodSignatures["jdk/internal/misc/VM.latestUserDefinedLoader()Ljava/lang/ClassLoader;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  returnNull,
+		}
+
+	MethodSignatures["jdk/internal/misc/VM.maxDirectMemory()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  maxMemory, // the JDK's default limit
+		}
+
+	// --- jdk/internal/reflect/Reflection ---
+
+	MethodSignatures["jdk/internal/reflect/Reflection.getCallerClass()Ljava/lang/Class;"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    reflectionGetCallerClass,
+			NeedsContext: true,
+		}
+
+	// --- jdk/internal/util/ArraysSupport ---
+
+	MethodSignatures["jdk/internal/util/ArraysSupport.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  clinitGeneric,
+		}
+
+	// --- jdk/internal/util/Preconditions ---
+
+	MethodSignatures[classNamePreconditions+".<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  preconditionsClinit,
+		}
+
+	MethodSignatures[classNamePreconditions+".checkFromIndexSize(IIILjava/util/function/BiFunction;)I"] =
+		GMeth{
+			ParamSlots: 4,
+			GFunction:  preconditionsCheckFromIndexSize,
+		}
+
+	MethodSignatures[classNamePreconditions+".checkFromIndexSize(JJJLjava/util/function/BiFunction;)J"] =
+		GMeth{
+			ParamSlots: 4,
+			GFunction:  preconditionsCheckFromIndexSize,
+		}
+
+	MethodSignatures[classNamePreconditions+".checkFromToIndex(IIILjava/util/function/BiFunction;)I"] =
+		GMeth{
+			ParamSlots: 4,
+			GFunction:  preconditionsCheckFromToIndex,
+		}
+
+	MethodSignatures[classNamePreconditions+".checkFromToIndex(JJJLjava/util/function/BiFunction;)J"] =
+		GMeth{
+			ParamSlots: 4,
+			GFunction:  preconditionsCheckFromToIndex,
+		}
+
+	MethodSignatures[classNamePreconditions+".checkIndex(IILjava/util/function/BiFunction;)I"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  preconditionsCheckIndex,
+		}
+
+	MethodSignatures[classNamePreconditions+".checkIndex(JJLjava/util/function/BiFunction;)J"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  preconditionsCheckIndex,
+		}
+}
+
+// "jdk/internal/misc/VM.initLevel()I"
+func vmInitLevel([]interface{}) interface{} {
+	return int64(vmSystemBooted)
+}
+
+// "jdk/internal/misc/VM.isSystemDomainLoader(Ljava/lang/ClassLoader;)Z": Jacobin's class
+// loaders aren't ClassLoader objects, so only null, the bootstrap loader, is recognized
+func vmIsSystemDomainLoader(params []interface{}) interface{} {
+	return types.ConvertGoBoolToJavaBool(object.IsNull(params[0]))
+}
+
+// "jdk/internal/misc/VM.getNanoTimeAdjustment(J)J": the time now, in nanoseconds since
+// the given number of seconds after the epoch, or -1 if that's too far from now
+func vmGetNanoTimeAdjustment(params []interface{}) interface{} {
+	now := time.Now()
+	seconds := now.Unix() - params[0].(int64)
+	if seconds > 0xFFFFFFFF || seconds < -0xFFFFFFFF {
+		return int64(-1)
+	}
+	return seconds*int64(time.Second) + int64(now.Nanosecond())
+}
+
+// "jdk/internal/reflect/Reflection.getCallerClass()Ljava/lang/Class;": the class of the
+// method that called the method calling getCallerClass(), skipping the frames of
+// reflection, or null if there is no such method
+func reflectionGetCallerClass(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	if fs == nil || fs.Len() < 2 {
+		return object.Null
+	}
+	for e := fs.Front().Next(); e != nil; e = e.Next() {
+		className := e.Value.(*frames.Frame).ClName
+		if className == "java/lang/reflect/Method" || strings.HasPrefix(className, "jdk/internal/reflect/") {
+			continue
+		}
+		if cl := classloader.MethAreaClassObject(className); cl != nil {
+			return cl
+		}
+		break
+	}
+	return object.Null
+}
+
+// "jdk/internal/util/Preconditions.<clinit>()V": sets the standard exception formatters
+// to objects that tell the checks below which exception to throw
+func preconditionsClinit([]interface{}) interface{} {
+	klass := classloader.MethAreaFetch(classNamePreconditions)
+	if klass == nil || klass.Data == nil {
+		errMsg := fmt.Sprintf("preconditionsClinit: Expected %s to be in the MethodArea, but it was not", classNamePreconditions)
+		trace.Error(errMsg)
+		return getGErrBlk(excNames.ClassNotLoadedException, errMsg)
+	}
+	if klass.Data.ClInit != types.ClInitRun {
+		for name, excType := range preconditionsFormatters {
+			formatter := object.MakeOneFieldObject(classNamePreconditionsFormatter, "exception", types.Int, int64(excType))
+			_ = statics.AddStatic(classNamePreconditions+"."+name,
+				statics.Static{Type: "Ljava/util/function/BiFunction;", Value: formatter})
+		}
+		klass.Data.ClInit = types.ClInitRun
+	}
+	return nil
+}
+
+// outOfBounds returns the exception for a failed check: the one named by a standard
+// formatter, and an IndexOutOfBoundsException for no formatter or any other
+func outOfBounds(formatter interface{}, errMsg string) *GErrBlk {
+	excType := excNames.IndexOutOfBoundsException
+	if obj, ok := formatter.(*object.Object); ok && !object.IsNull(obj) {
+		if formatterType, ok := obj.FieldTable["exception"].Fvalue.(int64); ok {
+			excType = int(formatterType)
+		}
+	}
+	return getGErrBlk(excType, errMsg)
+}
+
+// "jdk/internal/util/Preconditions.checkIndex(IILjava/util/function/BiFunction;)I" and
+// the long form
+func preconditionsCheckIndex(params []interface{}) interface{} {
+	index, length := params[0].(int64), params[1].(int64)
+	if index < 0 || index >= length {
+		return outOfBounds(params[2], fmt.Sprintf("Index %d out of bounds for length %d", index, length))
+	}
+	return index
+}
+
+// "jdk/internal/util/Preconditions.checkFromToIndex(IIILjava/util/function/BiFunction;)I"
+// and the long form
+func preconditionsCheckFromToIndex(params []interface{}) interface{} {
+	fromIndex, toIndex, length := params[0].(int64), params[1].(int64), params[2].(int64)
+	if fromIndex < 0 || fromIndex > toIndex || toIndex > length {
+		errMsg := fmt.Sprintf("Range [%d, %d) out of bounds for length %d", fromIndex, toIndex, length)
+		return outOfBounds(params[3], errMsg)
+	}
+	return fromIndex
+}
+
+// "jdk/internal/util/Preconditions.checkFromIndexSize(IIILjava/util/function/BiFunction;)I"
+// and the long form
+func preconditionsCheckFromIndexSize(params []interface{}) interface{} {
+	fromIndex, size, length := params[0].(int64), params[1].(int64), params[2].(int64)
+	if (length|fromIndex|size) < 0 || size > length-fromIndex {
+		errMsg := fmt.Sprintf("Range [%d, %d + %d) out of bounds for length %d", fromIndex, fromIndex, size, length)
+		return outOfBounds(params[3], errMsg)
+	}
+	return fromIndex
+}
@@ -0,0 +1,111 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/statics"
+	"jacobin/src/types"
+	"testing"
+	"time"
+)
+
+func TestPreconditions_Checks(t *testing.T) {
+	globals.InitGlobals("test")
+
+	if ret := preconditionsCheckIndex([]interface{}{int64(2), int64(3), object.Null}); ret != int64(2) {
+		t.Errorf("Expected checkIndex(2, 3) to return 2, got %v", ret)
+	}
+	ret := preconditionsCheckIndex([]interface{}{int64(3), int64(3), object.Null})
+	expectException(t, ret, excNames.IndexOutOfBoundsException, "checkIndex(3, 3)")
+	if gErr, ok := ret.(*GErrBlk); ok && gErr.ErrMsg != "Index 3 out of bounds for length 3" {
+		t.Errorf("Unexpected message %q", gErr.ErrMsg)
+	}
+
+	if ret := preconditionsCheckFromToIndex([]interface{}{int64(1), int64(3), int64(3), object.Null}); ret != int64(1) {
+		t.Errorf("Expected checkFromToIndex(1, 3, 3) to return 1, got %v", ret)
+	}
+	ret = preconditionsCheckFromToIndex([]interface{}{int64(2), int64(1), int64(3), object.Null})
+	expectException(t, ret, excNames.IndexOutOfBoundsException, "checkFromToIndex(2, 1, 3)")
+
+	if ret := preconditionsCheckFromIndexSize([]interface{}{int64(1), int64(2), int64(3), object.Null}); ret != int64(1) {
+		t.Errorf("Expected checkFromIndexSize(1, 2, 3) to return 1, got %v", ret)
+	}
+	ret = preconditionsCheckFromIndexSize([]interface{}{int64(2), int64(2), int64(3), object.Null})
+	expectException(t, ret, excNames.IndexOutOfBoundsException, "checkFromIndexSize(2, 2, 3)")
+	if gErr, ok := ret.(*GErrBlk); ok && gErr.ErrMsg != "Range [2, 2 + 2) out of bounds for length 3" {
+		t.Errorf("Unexpected message %q", gErr.ErrMsg)
+	}
+}
+
+func TestPreconditions_Formatters(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	classloader.MethAreaInsert(classNamePreconditions, &classloader.Klass{Status: 'F', Loader: "testloader",
+		Data: &classloader.ClData{Name: classNamePreconditions, SuperclassIndex: types.ObjectPoolStringIndex}})
+
+	if ret := preconditionsClinit(nil); ret != nil {
+		t.Fatalf("Preconditions.<clinit> failed: %v", ret)
+	}
+	formatter := statics.GetStaticValue(classNamePreconditions, "SIOOBE_FORMATTER")
+	ret := preconditionsCheckIndex([]interface{}{int64(-1), int64(3), formatter})
+	expectException(t, ret, excNames.StringIndexOutOfBoundsException, "checkIndex() with SIOOBE_FORMATTER")
+
+	formatter = statics.GetStaticValue(classNamePreconditions, "AIOOBE_FORMATTER")
+	ret = preconditionsCheckFromToIndex([]interface{}{int64(0), int64(4), int64(3), formatter})
+	expectException(t, ret, excNames.ArrayIndexOutOfBoundsException, "checkFromToIndex() with AIOOBE_FORMATTER")
+}
+
+func TestVM_Answers(t *testing.T) {
+	if vmInitLevel(nil) != int64(vmSystemBooted) {
+		t.Errorf("Expected the VM to report that it has booted")
+	}
+	if vmIsSystemDomainLoader([]interface{}{object.Null}) != types.JavaBoolTrue {
+		t.Errorf("Expected the bootstrap loader to be a system domain loader")
+	}
+
+	offset := time.Now().Unix() - 10
+	adjustment := vmGetNanoTimeAdjustment([]interface{}{offset}).(int64)
+	if adjustment < 10*int64(time.Second) || adjustment > 20*int64(time.Second) {
+		t.Errorf("Expected an adjustment of about 10 seconds, got %d ns", adjustment)
+	}
+	if ret := vmGetNanoTimeAdjustment([]interface{}{int64(-1) << 40}); ret != int64(-1) {
+		t.Errorf("Expected -1 for an offset too far from now, got %v", ret)
+	}
+}
+
+func TestReflection_GetCallerClass(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	for _, name := range []string{"pkg/Caller", "pkg/Callee"} {
+		classloader.MethAreaInsert(name, &classloader.Klass{Status: 'F', Loader: "testloader",
+			Data: &classloader.ClData{Name: name, SuperclassIndex: types.ObjectPoolStringIndex}})
+	}
+
+	// pkg/Caller calls pkg/Callee by reflection, and pkg/Callee calls getCallerClass()
+	fs := list.New()
+	for _, name := range []string{"pkg/Callee", "jdk/internal/reflect/DirectMethodHandleAccessor",
+		"java/lang/reflect/Method", "pkg/Caller"} {
+		fs.PushBack(frames.CreateFrame(1))
+		fs.Back().Value.(*frames.Frame).ClName = name
+	}
+	cl, ok := reflectionGetCallerClass([]interface{}{fs}).(*object.Object)
+	if !ok || classloader.ClassNameFromClassObject(cl) != "pkg/Caller" {
+		t.Errorf("Expected the caller to be pkg/Caller, got %v", cl)
+	}
+
+	fs.Init()
+	fs.PushBack(frames.CreateFrame(1))
+	if ret := reflectionGetCallerClass([]interface{}{fs}); ret != object.Null {
+		t.Errorf("Expected no caller of the bottom frame, got %v", ret)
+	}
+}
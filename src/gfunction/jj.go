@@ -9,6 +9,7 @@ package gfunction
 import (
 	"bytes"
 	"fmt"
+	"jacobin/src/classloader"
 	"jacobin/src/excNames"
 	"jacobin/src/globals"
 	"jacobin/src/object"
@@ -62,6 +63,24 @@ func Load_jj() {
 			GFunction:  jjSubProcess,
 		}
 
+	MethodSignatures["jj._dumpClassHierarchy(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  jjDumpClassHierarchy,
+		}
+
+	MethodSignatures["jj._dumpMethodArea(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  jjDumpMethodArea,
+		}
+
+	MethodSignatures["jj._dumpMTable(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  jjDumpMTable,
+		}
+
 	MethodSignatures["jj._getProgramName()Ljava/lang/String;"] =
 		GMeth{
 			ParamSlots: 0,
@@ -232,6 +251,31 @@ func jjDumpStatics(params []interface{}) interface{} {
 	return nil
 }
 
+// prints the loaded subclasses of the named class to stderr, one per line, indented under
+// their superclasses. The class name can be in either java.lang.Object or java/lang/Object format.
+func jjDumpClassHierarchy(params []interface{}) interface{} {
+	classNameObj := params[0].(*object.Object)
+	className := strings.ReplaceAll(object.ObjectFieldToString(classNameObj, "value"), ".", "/")
+	_, _ = fmt.Fprint(os.Stderr, classloader.ClassHierarchy(className))
+	return nil
+}
+
+// prints the classes in the method area whose names begin with the given string to stderr,
+// with their loaders, status, method counts, and initialization states
+func jjDumpMethodArea(params []interface{}) interface{} {
+	filterObj := params[0].(*object.Object)
+	classloader.PrintMethArea(os.Stderr, object.ObjectFieldToString(filterObj, "value"))
+	return nil
+}
+
+// prints the entries of the MTable whose signatures begin with the given string to stderr,
+// with the class that defines each method and the gfunctions that shadow loaded bytecode
+func jjDumpMTable(params []interface{}) interface{} {
+	filterObj := params[0].(*object.Object)
+	classloader.PrintMTable(os.Stderr, object.ObjectFieldToString(filterObj, "value"))
+	return nil
+}
+
 func jjDumpObject(params []interface{}) interface{} {
 	this := params[0].(*object.Object)
 	objTitle := params[1].(*object.Object)
@@ -0,0 +1,147 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"fmt"
+	"io"
+	"jacobin/src/classloader"
+	"sort"
+	"strings"
+)
+
+// The listing of the gfunctions, for --list-gfunctions and --api-coverage. The
+// gfunctions are grouped by class, and each is marked as implemented or as a trap,
+// i.e., a method that Jacobin recognizes but does not support. For --api-coverage,
+// each class's gfunctions are also compared with the public and protected methods
+// of the class in the JDK, so that the methods not yet implemented can be seen.
+
+// gfuncEntry is a gfunction in the listing: its method name and descriptor, and
+// whether it's a trap
+type gfuncEntry struct {
+	method string
+	trap   bool
+}
+
+// groups the gfunctions in MethodSignatures by class. Returns the gfunctions of each
+// class, ordered by method, and the classes in alphabetical order.
+func groupGfunctions() (map[string][]gfuncEntry, []string) {
+	byClass := make(map[string][]gfuncEntry)
+	for fqn, gmeth := range MethodSignatures {
+		dot := strings.Index(fqn, ".") // class names use /, so the first dot ends the class
+		if dot < 0 {
+			continue
+		}
+		className := fqn[:dot]
+		byClass[className] = append(byClass[className], gfuncEntry{method: fqn[dot+1:], trap: isTrap(gmeth.GFunction)})
+	}
+
+	classes := make([]string, 0, len(byClass))
+	for className, entries := range byClass {
+		classes = append(classes, className)
+		sort.Slice(entries, func(i, j int) bool { return entries[i].method < entries[j].method })
+	}
+	sort.Strings(classes)
+	return byClass, classes
+}
+
+// PrintGfunctionList writes the gfunctions to out, grouped by class. If jdkMethods
+// is not nil, it's used to fetch the methods of each class in the JDK, and each class
+// also lists the JDK methods that have no gfunction and the gfunctions that aren't
+// JDK methods, followed by the totals of the coverage.
+func PrintGfunctionList(out io.Writer, jdkMethods func(className string) ([]classloader.JDKMethod, error)) {
+	byClass, classes := groupGfunctions()
+
+	var gfuncs, traps, jdkTotal, jdkCovered int
+	for _, className := range classes {
+		entries := byClass[className]
+		classTraps := 0
+		for _, entry := range entries {
+			if entry.trap {
+				classTraps++
+			}
+		}
+		gfuncs += len(entries)
+		traps += classTraps
+		_, _ = fmt.Fprintf(out, "%s (%d gfunctions, %d traps)\n", className, len(entries), classTraps)
+		for _, entry := range entries {
+			if entry.trap {
+				_, _ = fmt.Fprintf(out, "    %s [trap]\n", entry.method)
+			} else {
+				_, _ = fmt.Fprintf(out, "    %s\n", entry.method)
+			}
+		}
+
+		if jdkMethods == nil {
+			continue
+		}
+		methods, err := jdkMethods(className)
+		if err != nil {
+			_, _ = fmt.Fprintf(out, "  not compared with the JDK: %s\n", err.Error())
+			continue
+		}
+		total, covered, missing, extra := compareWithJDK(entries, methods)
+		jdkTotal += total
+		jdkCovered += covered
+		_, _ = fmt.Fprintf(out, "  %d of %d JDK methods implemented\n", covered, total)
+		if len(missing) > 0 {
+			_, _ = fmt.Fprintln(out, "  JDK methods without a gfunction:")
+			for _, method := range missing {
+				_, _ = fmt.Fprintf(out, "    %s\n", method)
+			}
+		}
+		if len(extra) > 0 {
+			_, _ = fmt.Fprintln(out, "  gfunctions that are not public or protected JDK methods:")
+			for _, method := range extra {
+				_, _ = fmt.Fprintf(out, "    %s\n", method)
+			}
+		}
+	}
+
+	_, _ = fmt.Fprintf(out, "%d gfunctions in %d classes: %d implemented, %d traps\n",
+		gfuncs, len(classes), gfuncs-traps, traps)
+	if jdkMethods != nil && jdkTotal > 0 {
+		_, _ = fmt.Fprintf(out, "%d of %d JDK methods of these classes implemented (%.1f%%)\n",
+			jdkCovered, jdkTotal, float64(jdkCovered)*100/float64(jdkTotal))
+	}
+}
+
+// compares a class's gfunctions with its methods in the JDK. Returns the number of JDK
+// methods, the number implemented by a gfunction that isn't a trap, the JDK methods
+// that have none (native methods are marked as such), and the gfunctions that aren't
+// public or protected JDK methods. Static initializers are not JDK API, so they are
+// left out of the last.
+func compareWithJDK(entries []gfuncEntry, methods []classloader.JDKMethod) (
+	total, covered int, missing, extra []string) {
+	gfuncs := make(map[string]bool, len(entries)) // method -> is it a trap?
+	for _, entry := range entries {
+		gfuncs[entry.method] = entry.trap
+	}
+
+	inJDK := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		inJDK[m.Method] = true
+		trap, ok := gfuncs[m.Method]
+		switch {
+		case ok && !trap:
+			covered++
+		case m.Native:
+			missing = append(missing, m.Method+" [native]")
+		case ok:
+			missing = append(missing, m.Method+" [trap]")
+		default:
+			missing = append(missing, m.Method)
+		}
+	}
+
+	for _, entry := range entries {
+		if !inJDK[entry.method] && !strings.HasPrefix(entry.method, "<clinit>") {
+			extra = append(extra, entry.method)
+		}
+	}
+	return len(methods), covered, missing, extra
+}
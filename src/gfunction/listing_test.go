@@ -0,0 +1,85 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"bytes"
+	"errors"
+	"jacobin/src/classloader"
+	"strings"
+	"testing"
+)
+
+// replaces the gfunctions with a few of a fake class, and a trap
+func loadListingTestGfunctions(t *testing.T) {
+	saved := MethodSignatures
+	t.Cleanup(func() { MethodSignatures = saved })
+	MethodSignatures = map[string]GMeth{
+		"java/lang/Fake.<clinit>()V":                     {GFunction: clinitGeneric},
+		"java/lang/Fake.size()I":                         {GFunction: justReturn},
+		"java/lang/Fake.clear()V":                        {GFunction: trapFunction},
+		"java/lang/Fake.helper()V":                       {GFunction: justReturn},
+		"java/util/Other.<init>()V":                      {GFunction: justReturn},
+		"java/util/Other$Inner.next()Ljava/lang/Object;": {GFunction: trapClass},
+	}
+}
+
+func TestPrintGfunctionList(t *testing.T) {
+	loadListingTestGfunctions(t)
+
+	var out bytes.Buffer
+	PrintGfunctionList(&out, nil)
+	expected := "java/lang/Fake (4 gfunctions, 1 traps)\n" +
+		"    <clinit>()V\n" +
+		"    clear()V [trap]\n" +
+		"    helper()V\n" +
+		"    size()I\n" +
+		"java/util/Other (1 gfunctions, 0 traps)\n" +
+		"    <init>()V\n" +
+		"java/util/Other$Inner (1 gfunctions, 1 traps)\n" +
+		"    next()Ljava/lang/Object; [trap]\n" +
+		"6 gfunctions in 3 classes: 4 implemented, 2 traps\n"
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestPrintGfunctionListWithCoverage(t *testing.T) {
+	loadListingTestGfunctions(t)
+
+	jdkMethods := func(className string) ([]classloader.JDKMethod, error) {
+		if className != "java/lang/Fake" {
+			return nil, errors.New(className + " is not in the JDK's jmod files")
+		}
+		return []classloader.JDKMethod{
+			{Method: "clear()V"},
+			{Method: "hashCode()I", Native: true},
+			{Method: "isEmpty()Z"},
+			{Method: "size()I"},
+		}, nil
+	}
+
+	var out bytes.Buffer
+	PrintGfunctionList(&out, jdkMethods)
+	report := out.String()
+	for _, expected := range []string{
+		"  1 of 4 JDK methods implemented\n" +
+			"  JDK methods without a gfunction:\n" +
+			"    clear()V [trap]\n" +
+			"    hashCode()I [native]\n" +
+			"    isEmpty()Z\n" +
+			"  gfunctions that are not public or protected JDK methods:\n" +
+			"    helper()V\n" +
+			"java/util/Other ",
+		"  not compared with the JDK: java/util/Other is not in the JDK's jmod files\n",
+		"1 of 4 JDK methods of these classes implemented (25.0%)\n",
+	} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("Expected the report to contain:\n%s\ngot:\n%s", expected, report)
+		}
+	}
+}
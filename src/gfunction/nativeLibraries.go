@@ -0,0 +1,210 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/util"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Jacobin can't load native code, so System.loadLibrary() and System.load() bind
+// instead to native libraries that are implemented in Go and registered here. When a
+// registered library is loaded, its methods (typically the native methods of the
+// classes that load it) are added to the MTable as G functions. Loading a library
+// that's not registered throws an UnsatisfiedLinkError, as the JDK does when it can't
+// find a library.
+//
+// As in the JDK, a library can be loaded by only one classloader; loading it again
+// from the same classloader does nothing.
+
+// NativeLibrary is a native library implemented in Go. Name is the name that's passed
+// to System.loadLibrary(), such as "zip" for libzip.so; Methods are the G functions
+// the library provides, keyed as in MethodSignatures.
+type NativeLibrary struct {
+	Name    string
+	Methods map[string]GMeth
+}
+
+var (
+	nativeLibraries      = make(map[string]NativeLibrary) // registered libraries, by name
+	loadedLibraries      = make(map[string]string)        // loaded libraries: name -> classloader
+	nativeLibrariesMutex sync.Mutex
+)
+
+// RegisterNativeLibrary makes a native library available for loading. Registering a
+// library again replaces the earlier registration.
+func RegisterNativeLibrary(lib NativeLibrary) {
+	nativeLibrariesMutex.Lock()
+	nativeLibraries[lib.Name] = lib
+	nativeLibrariesMutex.Unlock()
+}
+
+// LoadedLibraries returns the names, in sorted order, of the native libraries loaded
+// by the named classloader
+func LoadedLibraries(loader string) []string {
+	nativeLibrariesMutex.Lock()
+	defer nativeLibrariesMutex.Unlock()
+
+	var names []string
+	for name, ldr := range loadedLibraries {
+		if ldr == loader {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// mapLibraryName returns the platform-specific file name of a library,
+// such as libzip.so for zip
+func mapLibraryName(name string) string {
+	switch {
+	case util.GetPlatform() == util.Windows:
+		return name + ".dll"
+	case runtime.GOOS == "darwin":
+		return "lib" + name + ".dylib"
+	default:
+		return "lib" + name + ".so"
+	}
+}
+
+// libraryNameFromFile is the inverse of mapLibraryName(): it returns the library name
+// in the file name at the end of a path, such as zip for /usr/lib/libzip.so
+func libraryNameFromFile(path string) string {
+	sep := strings.LastIndexAny(path, "/\\")
+	name := path[sep+1:]
+	for _, ext := range []string{".dll", ".dylib", ".so"} {
+		if strings.HasSuffix(name, ext) {
+			name = strings.TrimSuffix(name, ext)
+			if ext != ".dll" {
+				name = strings.TrimPrefix(name, "lib")
+			}
+			break
+		}
+	}
+	return name
+}
+
+// callerLoader returns the name of the classloader that loaded the class whose
+// method called the G function
+func callerLoader(fs *list.List) string {
+	if fs != nil && fs.Len() > 0 {
+		f := fs.Front().Value.(*frames.Frame)
+		k := classloader.MethAreaFetch(f.ClName)
+		if k != nil && k.Loader != "" {
+			return k.Loader
+		}
+	}
+	return classloader.AppCL.Name
+}
+
+// loadNativeLibrary binds the named library for the loader and adds its methods to
+// the MTable. file is what the failure message reports as not found.
+func loadNativeLibrary(name, file, loader string) interface{} {
+	nativeLibrariesMutex.Lock()
+	defer nativeLibrariesMutex.Unlock()
+
+	lib, ok := nativeLibraries[name]
+	if !ok {
+		return getGErrBlk(excNames.UnsatisfiedLinkError, file)
+	}
+
+	if ldr, loaded := loadedLibraries[name]; loaded {
+		if ldr != loader {
+			errMsg := fmt.Sprintf("Native Library %s already loaded in another classloader", mapLibraryName(name))
+			return getGErrBlk(excNames.UnsatisfiedLinkError, errMsg)
+		}
+		return nil
+	}
+
+	loadlib(&classloader.MTable, lib.Methods)
+	loadedLibraries[name] = loader
+	return nil
+}
+
+// "java/lang/System.loadLibrary(Ljava/lang/String;)V"
+func systemLoadLibrary(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	return loadLibrary(params[1], fs)
+}
+
+// "java/lang/Runtime.loadLibrary(Ljava/lang/String;)V" and
+// "java/lang/Runtime.loadLibrary0(Ljava/lang/Class;Ljava/lang/String;)V"
+// The Runtime object and the Class are ignored.
+func runtimeLoadLibrary(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	return loadLibrary(params[len(params)-1], fs)
+}
+
+// loadLibrary() does the work for the loadLibrary() methods
+func loadLibrary(nameParam interface{}, fs *list.List) interface{} {
+	if object.IsNull(nameParam) {
+		return getGErrBlk(excNames.NullPointerException, "loadLibrary: null library name")
+	}
+
+	name := object.GoStringFromStringObject(nameParam.(*object.Object))
+	if strings.ContainsAny(name, "/\\") {
+		errMsg := fmt.Sprintf("Directory separator should not appear in library name: %s", name)
+		return getGErrBlk(excNames.UnsatisfiedLinkError, errMsg)
+	}
+
+	errMsg := fmt.Sprintf("no %s in java.library.path: %s",
+		name, globals.GetSystemProperty("java.library.path"))
+	return loadNativeLibrary(name, errMsg, callerLoader(fs))
+}
+
+// "java/lang/System.load(Ljava/lang/String;)V"
+func systemLoad(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	return load(params[1], fs)
+}
+
+// "java/lang/Runtime.load(Ljava/lang/String;)V" and
+// "java/lang/Runtime.load0(Ljava/lang/Class;Ljava/lang/String;)V"
+// The Runtime object and the Class are ignored.
+func runtimeLoad(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	return load(params[len(params)-1], fs)
+}
+
+// load() does the work for the load() methods, which take the absolute path of the
+// library file. The library is identified by the name of the file, so that loading
+// /usr/lib/libzip.so, for example, binds the library registered as zip.
+func load(pathParam interface{}, fs *list.List) interface{} {
+	if object.IsNull(pathParam) {
+		return getGErrBlk(excNames.NullPointerException, "load: null library path")
+	}
+
+	path := object.GoStringFromStringObject(pathParam.(*object.Object))
+	if !filepath.IsAbs(path) {
+		errMsg := fmt.Sprintf("Expecting an absolute path of the library: %s", path)
+		return getGErrBlk(excNames.UnsatisfiedLinkError, errMsg)
+	}
+
+	errMsg := fmt.Sprintf("Can't load library: %s", path)
+	return loadNativeLibrary(libraryNameFromFile(path), errMsg, callerLoader(fs))
+}
+
+// "java/lang/System.mapLibraryName(Ljava/lang/String;)Ljava/lang/String;"
+func systemMapLibraryName(params []interface{}) interface{} {
+	if object.IsNull(params[0]) {
+		return getGErrBlk(excNames.NullPointerException, "mapLibraryName: null library name")
+	}
+	name := object.GoStringFromStringObject(params[0].(*object.Object))
+	return object.StringObjectFromGoString(mapLibraryName(name))
+}
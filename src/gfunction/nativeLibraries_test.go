@@ -0,0 +1,175 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin authors. Consult jacobin.org.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0) All rights reserved.
+ */
+
+package gfunction
+
+import (
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/util"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func nativeAnswer([]interface{}) interface{} { return int64(42) }
+
+// registers a library with a single method, and resets the record of loaded libraries
+func registerTestLibrary(name string) string {
+	classloader.BootstrapCL.Name = "bootstrap"
+	classloader.AppCL.Name = "app"
+
+	nativeLibrariesMutex.Lock()
+	loadedLibraries = make(map[string]string)
+	nativeLibrariesMutex.Unlock()
+
+	key := "test/Native" + name + ".answer()I"
+	RegisterNativeLibrary(NativeLibrary{
+		Name:    name,
+		Methods: map[string]GMeth{key: {ParamSlots: 0, GFunction: nativeAnswer}},
+	})
+	return key
+}
+
+func TestLoadLibraryRegistered(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	key := registerTestLibrary("answers")
+
+	fs := makeFrameStack()
+	ret := systemLoadLibrary([]interface{}{fs, object.StringObjectFromGoString("answers")})
+	if ret != nil {
+		t.Fatalf("Expected the library to load, got: %v", ret)
+	}
+
+	mte, ok := classloader.MTable[key]
+	if !ok || mte.MType != 'G' {
+		t.Fatalf("Expected %s to be in the MTable as a G function", key)
+	}
+	if mte.Meth.(GMeth).GFunction(nil) != int64(42) {
+		t.Error("The loaded method did not run the library's G function")
+	}
+
+	libs := LoadedLibraries(classloader.AppCL.Name)
+	if len(libs) != 1 || libs[0] != "answers" {
+		t.Errorf("Expected the app classloader to have loaded [answers], got %v", libs)
+	}
+
+	// loading it again from the same classloader is not an error
+	ret = runtimeLoadLibrary([]interface{}{fs, object.Null, object.StringObjectFromGoString("answers")})
+	if ret != nil {
+		t.Errorf("Expected reloading the library to succeed, got: %v", ret)
+	}
+}
+
+func TestLoadLibraryNotRegistered(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	fs := makeFrameStack()
+	ret := systemLoadLibrary([]interface{}{fs, object.StringObjectFromGoString("nosuchlib")})
+	errBlk, ok := ret.(*GErrBlk)
+	if !ok {
+		t.Fatalf("Expected an error block, got %T", ret)
+	}
+	if errBlk.ExceptionType != excNames.UnsatisfiedLinkError {
+		t.Errorf("Expected UnsatisfiedLinkError, got %s", excNames.JVMexceptionNames[errBlk.ExceptionType])
+	}
+	if !strings.HasPrefix(errBlk.ErrMsg, "no nosuchlib in java.library.path") {
+		t.Errorf("Got unexpected message: %s", errBlk.ErrMsg)
+	}
+
+	ret = systemLoadLibrary([]interface{}{fs, object.Null})
+	if errBlk, ok = ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("Expected NullPointerException for a null library name, got %v", ret)
+	}
+}
+
+// a library loaded by one classloader can't be loaded by another
+func TestLoadLibraryInAnotherClassloader(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	registerTestLibrary("shared")
+
+	if ret := loadNativeLibrary("shared", "shared", classloader.BootstrapCL.Name); ret != nil {
+		t.Fatalf("Expected the library to load, got: %v", ret)
+	}
+
+	fs := makeFrameStack()
+	ret := systemLoadLibrary([]interface{}{fs, object.StringObjectFromGoString("shared")})
+	errBlk, ok := ret.(*GErrBlk)
+	if !ok || errBlk.ExceptionType != excNames.UnsatisfiedLinkError {
+		t.Fatalf("Expected UnsatisfiedLinkError, got %v", ret)
+	}
+	if !strings.Contains(errBlk.ErrMsg, "already loaded in another classloader") {
+		t.Errorf("Got unexpected message: %s", errBlk.ErrMsg)
+	}
+	if len(LoadedLibraries(classloader.AppCL.Name)) != 0 {
+		t.Error("Expected the app classloader to have loaded no libraries")
+	}
+}
+
+func TestLoadByPath(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	registerTestLibrary("bypath")
+	previous := util.SetPlatform(util.Posix)
+	defer util.SetPlatform(previous)
+
+	fs := makeFrameStack()
+	ret := systemLoad([]interface{}{fs, object.StringObjectFromGoString("libbypath.so")})
+	errBlk, ok := ret.(*GErrBlk)
+	if !ok || !strings.HasPrefix(errBlk.ErrMsg, "Expecting an absolute path") {
+		t.Errorf("Expected an error for a relative path, got %v", ret)
+	}
+
+	if runtime.GOOS == "windows" {
+		return // the absolute paths below are POSIX paths
+	}
+
+	ret = runtimeLoad([]interface{}{fs, object.Null, object.StringObjectFromGoString("/usr/lib/libbypath.so")})
+	if ret != nil {
+		t.Errorf("Expected the library to load, got: %v", ret)
+	}
+
+	ret = systemLoad([]interface{}{fs, object.StringObjectFromGoString("/usr/lib/libnosuchlib.so")})
+	errBlk, ok = ret.(*GErrBlk)
+	if !ok || errBlk.ErrMsg != "Can't load library: /usr/lib/libnosuchlib.so" {
+		t.Errorf("Expected UnsatisfiedLinkError for an unregistered library, got %v", ret)
+	}
+}
+
+func TestMapLibraryName(t *testing.T) {
+	previous := util.SetPlatform(util.Windows)
+	defer util.SetPlatform(previous)
+
+	ret := systemMapLibraryName([]interface{}{object.StringObjectFromGoString("zip")})
+	if object.GoStringFromStringObject(ret.(*object.Object)) != "zip.dll" {
+		t.Errorf("Expected zip.dll, got %s", object.GoStringFromStringObject(ret.(*object.Object)))
+	}
+
+	util.SetPlatform(util.Posix)
+	expected := "libzip.so"
+	if runtime.GOOS == "darwin" {
+		expected = "libzip.dylib"
+	}
+	if mapLibraryName("zip") != expected {
+		t.Errorf("Expected %s, got %s", expected, mapLibraryName("zip"))
+	}
+
+	for path, name := range map[string]string{
+		"/usr/lib/libzip.so":     "zip",
+		"C:\\libs\\zip.dll":      "zip",
+		"/opt/lib/libzip.dylib":  "zip",
+		"/opt/lib/libraries.txt": "libraries.txt",
+	} {
+		if libraryNameFromFile(path) != name {
+			t.Errorf("Expected library name %s for %s, got %s", name, path, libraryNameFromFile(path))
+		}
+	}
+}
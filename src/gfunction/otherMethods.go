@@ -56,30 +56,6 @@ func Load_Other_methods() {
 			GFunction:  clinitGeneric,
 		}
 
-	MethodSignatures["java/lang/ThreadLocal.<clinit>()V"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  clinitGeneric,
-		}
-
-	MethodSignatures["java/lang/ThreadLocal.<init>()V"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  justReturn,
-		}
-
-	MethodSignatures["java/math/MathContext.<clinit>()V"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  clinitGeneric,
-		}
-
-	MethodSignatures["java/math/RoundingMode.<clinit>()V"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  clinitGeneric,
-		}
-
 	MethodSignatures["java/nio/charset/Charset.<clinit>()V"] =
 		GMeth{
 			ParamSlots: 0,
@@ -122,54 +98,6 @@ func Load_Other_methods() {
 			GFunction:  clinitGeneric,
 		}
 
-	MethodSignatures["jdk/internal/access/SharedSecrets.<clinit>()V"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  clinitGeneric,
-		}
-
-	MethodSignatures["jdk/internal/misc/VM.initialize()V"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  justReturn,
-		}
-
-	MethodSignatures["jdk/internal/misc/CDS.getRandomSeedForDumping()J"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  returnRandomLong,
-		}
-
-	MethodSignatures["jdk/internal/misc/CDS.initializeFromArchive(Ljava/lang/Class;)V"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  justReturn,
-		}
-
-	MethodSignatures["jdk/internal/misc/CDS.isDumpingArchive0()Z"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  returnFalse,
-		}
-
-	MethodSignatures["jdk/internal/misc/CDS.isDumpingClassList0()Z"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  returnFalse,
-		}
-
-	MethodSignatures["jdk/internal/misc/CDS.isSharingEnabled0()Z"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  returnFalse,
-		}
-
-	MethodSignatures["jdk/internal/util/ArraysSupport.<clinit>()V"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  clinitGeneric,
-		}
-
 	MethodSignatures["sun/security/util/Debug.<clinit>()V"] =
 		GMeth{
 			ParamSlots: 0,
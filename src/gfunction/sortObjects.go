@@ -0,0 +1,140 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"cmp"
+	"container/list"
+	"fmt"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf16"
+)
+
+// The sorting of Java objects by the gfunctions that sort them, such as Collections.sort()
+// and Arrays.sort(Object[]). Objects are compared with the compare() method of a Comparator
+// or, if there is none, with their compareTo() methods, which run as Java code through the
+// upcall bridge (see invokeJavaMethod()). The compareTo() methods of strings and of the
+// boxed primitives are run here in Go, which is faster and covers the classes whose
+// compareTo(Object) bridge methods are replaced by gfunctions. The sort is stable, as
+// Java's is.
+
+const (
+	classNameComparable = "java/lang/Comparable"
+	classNameComparator = "java/util/Comparator"
+)
+
+// sortJavaObjects returns a sorted copy of elems. If the comparator is null, the elements
+// are sorted by their natural ordering. A comparison that throws an exception stops the
+// sort, and the exception is returned.
+func sortJavaObjects(fs *list.List, elems []*object.Object, comparator *object.Object) ([]*object.Object, *GErrBlk) {
+	sorted := slices.Clone(elems)
+	var gErr *GErrBlk
+	slices.SortStableFunc(sorted, func(a, b *object.Object) int {
+		if gErr != nil { // a comparison failed: finish quickly, as the result is discarded
+			return 0
+		}
+		var result int
+		result, gErr = compareJavaObjects(fs, comparator, a, b)
+		return result
+	})
+	if gErr != nil {
+		return nil, gErr
+	}
+	return sorted, nil
+}
+
+// compareJavaObjects compares a and b with the comparator or, if it is null, with the
+// compareTo() method of a. The result is negative, zero, or positive, as in Java.
+func compareJavaObjects(fs *list.List, comparator, a, b *object.Object) (int, *GErrBlk) {
+	if !object.IsNull(comparator) {
+		ret, gErr := invokeJavaMethod(fs, classNameComparator, "compare",
+			"(Ljava/lang/Object;Ljava/lang/Object;)I", comparator, []any{a, b})
+		if gErr != nil {
+			return 0, gErr
+		}
+		return javaCompareResult(ret), nil
+	}
+
+	if object.IsNull(a) || object.IsNull(b) {
+		return 0, getGErrBlk(excNames.NullPointerException, "compareJavaObjects: null element in natural ordering")
+	}
+	if result, ok := compareBuiltinObjects(a, b); ok {
+		return result, nil
+	}
+
+	className := *stringPool.GetStringPointer(a.KlassName)
+	if !classloader.ImplementsInterface(className, classNameComparable) {
+		errMsg := fmt.Sprintf("class %s cannot be cast to class java.lang.Comparable",
+			strings.ReplaceAll(className, "/", "."))
+		return 0, getGErrBlk(excNames.ClassCastException, errMsg)
+	}
+	ret, gErr := invokeJavaMethod(fs, className, "compareTo", "(Ljava/lang/Object;)I", a, []any{b})
+	if gErr != nil {
+		return 0, gErr
+	}
+	return javaCompareResult(ret), nil
+}
+
+// compareBuiltinObjects compares two strings, or two boxed primitives of the same class,
+// as their compareTo() methods do. The boolean is false for the other objects.
+func compareBuiltinObjects(a, b *object.Object) (int, bool) {
+	if a.KlassName != b.KlassName {
+		return 0, false
+	}
+	switch *stringPool.GetStringPointer(a.KlassName) {
+	case "java/lang/String":
+		return compareUTF16(object.GoStringFromStringObject(a), object.GoStringFromStringObject(b)), true
+	case "java/lang/Boolean", "java/lang/Byte", "java/lang/Character", "java/lang/Integer",
+		"java/lang/Long", "java/lang/Short":
+		aValue, aOK := a.FieldTable["value"].Fvalue.(int64)
+		bValue, bOK := b.FieldTable["value"].Fvalue.(int64)
+		return cmp.Compare(aValue, bValue), aOK && bOK
+	case "java/lang/Double", "java/lang/Float":
+		aValue, aOK := a.FieldTable["value"].Fvalue.(float64)
+		bValue, bOK := b.FieldTable["value"].Fvalue.(float64)
+		return compareJavaDoubles(aValue, bValue), aOK && bOK
+	}
+	return 0, false
+}
+
+// compareUTF16 compares two strings by their UTF-16 code units, as String.compareTo() does
+func compareUTF16(a, b string) int {
+	return slices.Compare(utf16.Encode([]rune(a)), utf16.Encode([]rune(b)))
+}
+
+// compareJavaDoubles compares two doubles as Double.compare() does: -0.0 is less than 0.0,
+// and NaN is equal to itself and greater than every other value
+func compareJavaDoubles(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	aBits, bBits := int64(math.Float64bits(a)), int64(math.Float64bits(b))
+	if math.IsNaN(a) {
+		aBits = int64(math.Float64bits(math.NaN()))
+	}
+	if math.IsNaN(b) {
+		bBits = int64(math.Float64bits(math.NaN()))
+	}
+	return cmp.Compare(aBits, bBits)
+}
+
+// javaCompareResult converts the int returned by compare() or compareTo() to a Go int
+func javaCompareResult(ret any) int {
+	if result, ok := ret.(int64); ok {
+		return cmp.Compare(result, 0)
+	}
+	return 0
+}
@@ -3,12 +3,14 @@ package gfunction
 import (
 	"fmt"
 	"jacobin/src/excNames"
+	"jacobin/src/globals"
 	"jacobin/src/object"
 	"jacobin/src/types"
 	"math"
-	"runtime"
+	"math/big"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // helper wrapper to keep Java integral bit-width for formatting
@@ -18,9 +20,21 @@ type intWithBits struct {
 	bits int
 }
 
+// helper wrapper to keep the Java floating-point type for formatting
+// bits=32 for float, bits=64 for double
+type floatWithBits struct {
+	v    float64
+	bits int
+}
+
 // String formatting given a format string and a slice of arguments.
 // Called by sprintf, javaIoConsole.go, and javaIoPrintStream.go.
 func StringFormatter(params []interface{}) interface{} {
+	return stringFormatterWithSymbols(params, rootFormatSymbols)
+}
+
+// StringFormatter for a locale: the numbers are formatted with the locale's separators
+func stringFormatterWithSymbols(params []interface{}, symbols formatSymbols) interface{} {
 	// params[0]: format string
 	// params[1]: argument slice (array of object pointers)
 
@@ -30,10 +44,6 @@ func StringFormatter(params []interface{}) interface{} {
 		errMsg := fmt.Sprintf("StringFormatter: Invalid parameter count: %d", lenParams)
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
-	if lenParams == 1 { // No parameters beyond the format string
-		formatStringObj := params[0].(*object.Object)
-		return formatStringObj
-	}
 
 	// Check the format string.
 	var formatString string
@@ -56,16 +66,20 @@ func StringFormatter(params []interface{}) interface{} {
 		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
 	}
 
-	// Make sure that the argument slice is a reference array.
-	field := params[1].(*object.Object).FieldTable["value"]
-	if !strings.HasPrefix(field.Ftype, types.RefArray) {
-		errMsg := fmt.Sprintf("StringFormatter: Expected Ftype=%s for params[1]: fld.Ftype=%s, fld.Fvalue=%v",
-			types.RefArray, field.Ftype, field.Fvalue)
-		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
-	}
+	// No argument array (or a null one) means there are no arguments
+	var valuesIn []*object.Object
+	if lenParams == 2 && !object.IsNull(params[1]) {
+		// Make sure that the argument slice is a reference array.
+		field := params[1].(*object.Object).FieldTable["value"]
+		if !strings.HasPrefix(field.Ftype, types.RefArray) {
+			errMsg := fmt.Sprintf("StringFormatter: Expected Ftype=%s for params[1]: fld.Ftype=%s, fld.Fvalue=%v",
+				types.RefArray, field.Ftype, field.Fvalue)
+			return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+		}
 
-	// valuesIn = the reference array
-	valuesIn := field.Fvalue.([]*object.Object)
+		// valuesIn = the reference array
+		valuesIn = field.Fvalue.([]*object.Object)
+	}
 
 	// Convert input arguments but keep unknown refs for later handling
 	rawArgs := make([]interface{}, 0, len(valuesIn))
@@ -109,9 +123,9 @@ func StringFormatter(params []interface{}) interface{} {
 			rooney := rune(fld.Fvalue.(int64))
 			rawArgs = append(rawArgs, rooney)
 		case types.Double:
-			rawArgs = append(rawArgs, fld.Fvalue.(float64))
+			rawArgs = append(rawArgs, floatWithBits{v: fld.Fvalue.(float64), bits: 64})
 		case types.Float:
-			rawArgs = append(rawArgs, fld.Fvalue.(float64))
+			rawArgs = append(rawArgs, floatWithBits{v: fld.Fvalue.(float64), bits: 32})
 		case types.Int:
 			rawArgs = append(rawArgs, intWithBits{v: fld.Fvalue.(int64), bits: 32})
 		case types.Long:
@@ -120,294 +134,664 @@ func StringFormatter(params []interface{}) interface{} {
 			rawArgs = append(rawArgs, intWithBits{v: fld.Fvalue.(int64), bits: 16})
 		case types.Byte:
 			rawArgs = append(rawArgs, intWithBits{v: fld.Fvalue.(int64), bits: 8})
+		case types.BigInteger:
+			rawArgs = append(rawArgs, fld.Fvalue.(*big.Int))
 		default:
 			// keep the full object for later processing (e.g., %s/%b/%h)
 			rawArgs = append(rawArgs, obj)
 		}
 	}
 
-	// Transform Java format string and arguments to Go compatible ones
-	newFmt, newArgs := translateJavaFormat(formatString, rawArgs)
-
-	str := fmt.Sprintf(newFmt, newArgs...)
+	str, gErr := javaFormatWithSymbols(formatString, rawArgs, symbols)
+	if gErr != nil {
+		return gErr
+	}
 	return object.StringObjectFromGoString(str)
 }
 
-// translateJavaFormat parses a Java String.format-style format and maps it to a Go fmt format,
-// returning the rewritten format string and adjusted argument slice.
-func translateJavaFormat(fmtJava string, rawArgs []interface{}) (string, []interface{}) {
-	var b strings.Builder
-	outArgs := make([]interface{}, 0)
+// a format specifier, %[argument_index$][flags][width][.precision]conversion
+type formatSpec struct {
+	text      string // the specifier as it appears in the format string
+	argIndex  int    // the explicit argument index (0-based), or -1 if there is none
+	reusePrev bool   // the < flag: use the argument of the previous specifier
+	flags     string // the flags other than <
+	width     int    // -1 if not specified
+	precision int    // -1 if not specified
+	conv      byte   // the conversion, in lowercase
+	upper     bool   // the conversion was given in uppercase, as in %X
+}
 
-	nextIndex := 0
-	lastIndex := -1
-	newline := "\n"
-	if runtime.GOOS == "windows" {
-		newline = "\r\n"
+func (spec *formatSpec) has(flag byte) bool {
+	return strings.IndexByte(spec.flags, flag) >= 0
+}
+
+// the flags that Java rejects for each conversion with a FormatFlagsConversionMismatchException,
+// in the order Java checks them. The hex and octal conversions accept + ( and space only for
+// BigIntegers; this is checked when the argument is known.
+var badFormatFlags = map[byte]string{
+	'b': "#+ 0,(",
+	'h': "#+ 0,(",
+	's': "#+ 0,(",
+	'c': "#+ 0,(",
+	'd': "#",
+	'o': ",",
+	'x': ",",
+	'e': ",",
+	'f': "",
+	'g': "#",
+	'a': ",(",
+}
+
+// the separators used in formatting numbers for a locale
+type formatSymbols struct {
+	decimal  rune
+	grouping rune
+}
+
+// the separators of the root locale, which String.format() uses
+var rootFormatSymbols = formatSymbols{decimal: '.', grouping: ','}
+
+// the separators of the locales whose separators differ from the root locale's, by
+// language_COUNTRY and then by language, as in the JDK's (CLDR) locale data
+var localeFormatSymbols = map[string]formatSymbols{
+	"de": {',', '.'}, "es": {',', '.'}, "it": {',', '.'}, "nl": {',', '.'}, "pt": {',', '.'},
+	"da": {',', '.'}, "el": {',', '.'}, "id": {',', '.'}, "ro": {',', '.'}, "tr": {',', '.'},
+	"hr": {',', '.'}, "sl": {',', '.'}, "sr": {',', '.'},
+	"fr": {',', '\u202F'},
+	"bg": {',', '\u00A0'}, "cs": {',', '\u00A0'}, "et": {',', '\u00A0'}, "fi": {',', '\u00A0'},
+	"hu": {',', '\u00A0'}, "lt": {',', '\u00A0'}, "lv": {',', '\u00A0'}, "nb": {',', '\u00A0'},
+	"no": {',', '\u00A0'}, "pl": {',', '\u00A0'}, "ru": {',', '\u00A0'}, "sk": {',', '\u00A0'},
+	"sv": {',', '\u00A0'}, "uk": {',', '\u00A0'}, "pt_PT": {',', '\u00A0'},
+	"de_CH": {'.', '\u2019'}, "it_CH": {'.', '\u2019'}, "es_MX": {'.', ','}, "es_US": {'.', ','},
+}
+
+// returns the separators for the locale with the given language_COUNTRY_variant name
+func formatSymbolsForLocale(name string) formatSymbols {
+	language, country, _ := splitLocaleName(name)
+	if symbols, ok := localeFormatSymbols[language+"_"+country]; ok {
+		return symbols
 	}
+	if symbols, ok := localeFormatSymbols[language]; ok {
+		return symbols
+	}
+	return rootFormatSymbols
+}
 
-	for i := 0; i < len(fmtJava); i++ {
-		ch := fmtJava[i]
-		if ch != '%' {
-			b.WriteByte(ch)
-			continue
+// replaces the root locale's separators in a formatted number with those of the locale
+func localizeNumber(str string, symbols formatSymbols) string {
+	if symbols == rootFormatSymbols {
+		return str
+	}
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.':
+			return symbols.decimal
+		case ',':
+			return symbols.grouping
 		}
-		// handle %% literal
-		if i+1 < len(fmtJava) && fmtJava[i+1] == '%' {
-			b.WriteString("%%")
-			i++
+		return r
+	}, str)
+}
+
+// javaFormat formats the arguments as java.util.Formatter does, in the root locale: the
+// grouping separator is a comma, the decimal separator is a period, and %n is the line
+// separator of System.lineSeparator(). Floating-point values are rounded HALF_UP from their
+// shortest decimal representation, as in Java (so %.1f formats 0.25 as 0.3). The date/time
+// conversions (%t and %T) are not supported: the argument is formatted as by %s. Returns the
+// exception that Java throws if the format string is invalid or doesn't fit the arguments.
+func javaFormat(format string, rawArgs []interface{}) (string, *GErrBlk) {
+	return javaFormatWithSymbols(format, rawArgs, rootFormatSymbols)
+}
+
+// javaFormat for a locale: the decimal integers and the floating-point values in decimal
+// (%d, %e, %f, and %g) are written with the locale's separators
+func javaFormatWithSymbols(format string, rawArgs []interface{}, symbols formatSymbols) (string, *GErrBlk) {
+	var b strings.Builder
+	nextIndex := 0
+	lastIndex := -1
+	newline := globals.GetLineSeparator()
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			b.WriteByte(format[i])
 			continue
 		}
-		j := i + 1
-		// parse argument_index (digits+$) but only accept if a trailing '$' is present
-		argIndex := -1
-		// tentatively scan digits
-		tmp := j
-		for tmp < len(fmtJava) && fmtJava[tmp] >= '0' && fmtJava[tmp] <= '9' {
-			tmp++
-		}
-		if tmp < len(fmtJava) && fmtJava[tmp] == '$' && tmp > i+1 {
-			idxStr := fmtJava[i+1 : tmp]
-			if v, err := strconv.Atoi(idxStr); err == nil && v > 0 {
-				argIndex = v - 1
-			}
-			j = tmp + 1
-		} else {
-			// no argument index; keep j right after '%'
-			j = i + 1
-		}
-		// parse flags (we will collect but drop '<')
-		flagsStart := j
-		reusePrev := false
-		for j < len(fmtJava) {
-			c := fmtJava[j]
-			if strings.ContainsRune("-#+ 0,(<", rune(c)) {
-				if c == '<' {
-					reusePrev = true
-				}
-				j++
-			} else {
-				break
-			}
-		}
-		flags := fmtJava[flagsStart:j]
-		flags = strings.ReplaceAll(flags, "<", "") // we'll resolve it ourselves
-
-		// width
-		widthStart := j
-		for j < len(fmtJava) && fmtJava[j] >= '0' && fmtJava[j] <= '9' {
-			j++
-		}
-		width := fmtJava[widthStart:j]
-		// precision
-		precision := ""
-		if j < len(fmtJava) && fmtJava[j] == '.' {
-			k := j + 1
-			for k < len(fmtJava) && fmtJava[k] >= '0' && fmtJava[k] <= '9' {
-				k++
-			}
-			precision = fmtJava[j:k]
-			j = k
-		}
-		if j >= len(fmtJava) {
-			// malformed, copy as-is
-			b.WriteString(fmtJava[i:])
-			break
+		spec, end, gErr := parseFormatSpec(format, i)
+		if gErr != nil {
+			return "", gErr
 		}
-		conv := fmtJava[j]
+		i = end - 1
 
-		// %n newline (no arg consumed)
-		if conv == 'n' {
+		switch spec.conv {
+		case 'n':
 			b.WriteString(newline)
-			i = j
+			continue
+		case '%':
+			b.WriteString(justifyFormatted(spec, "%"))
 			continue
 		}
 
-		// Resolve which argument index to use
-		useIndex := -1
-		if argIndex >= 0 {
-			useIndex = argIndex
-		} else if reusePrev {
+		// Resolve which argument to use
+		var useIndex int
+		switch {
+		case spec.argIndex >= 0:
+			useIndex = spec.argIndex
+		case spec.reusePrev:
 			useIndex = lastIndex
-		} else {
+		default:
 			useIndex = nextIndex
 			nextIndex++
 		}
+		if useIndex < 0 || useIndex >= len(rawArgs) {
+			errMsg := fmt.Sprintf("Format specifier '%s'", spec.text)
+			return "", getGErrBlk(excNames.MissingFormatArgumentException, errMsg)
+		}
 		lastIndex = useIndex
 
-		// Prepare Go conv and value
-		goConv := string(conv)
-		switch conv {
-		case 'b', 'B':
-			goConv = "t"
-			val := coerceJavaBoolean(rawArgs, useIndex)
-			outArgs = append(outArgs, val)
-		case 's', 'S':
-			goConv = "s"
-			val := coerceJavaString(rawArgs, useIndex)
-			if conv == 'S' {
-				val = strings.ToUpper(val)
-			}
-			outArgs = append(outArgs, val)
-		case 'h', 'H':
-			// Java %h/%H produces hex hash of arg (null -> "null")
-			if rawArgs == nil || useIndex < 0 || useIndex >= len(rawArgs) || rawArgs[useIndex] == nil {
-				goConv = "s"
-				outArgs = append(outArgs, "null")
-			} else {
-				goConv = string(conv) // x or X
-				if conv == 'h' {
-					goConv = "x"
-				} else {
-					goConv = "X"
-				}
-				outArgs = append(outArgs, javaHashValue(rawArgs[useIndex]))
-			}
-		case 't', 'T':
-			// Not fully supported: degrade to %s on placeholder
-			goConv = "s"
-			val := coerceJavaString(rawArgs, useIndex)
-			outArgs = append(outArgs, val)
-		case 'x', 'X', 'o':
-			// For hex/octal, Java uses two's complement unsigned representation of the primitive width
-			v := rawArgs[useIndex]
-			switch iv := v.(type) {
-			case intWithBits:
-				var u uint64
-				switch iv.bits {
-				case 64:
-					u = uint64(iv.v)
-				case 32:
-					u = uint64(uint32(iv.v))
-				case 16:
-					u = uint64(uint16(iv.v))
-				case 8:
-					u = uint64(uint8(iv.v))
-				default:
-					u = uint64(uint32(iv.v))
-				}
-				outArgs = append(outArgs, u)
-			default:
-				outArgs = append(outArgs, normalizeForGo(rawArgs, useIndex))
-			}
-		case 'f':
-			// Ensure Java-like zero-padding works identically
-			val := normalizeForGo(rawArgs, useIndex)
-			switch val.(type) {
-			case float64, int64:
-				var f64 float64
-				if vv, ok := val.(float64); ok {
-					f64 = vv
-				} else {
-					f64 = float64(val.(int64))
-				}
-				// Base formatting with precision only
-				base := "%" + precision + "f"
-				res := fmt.Sprintf(base, f64)
-				// sign handling for + or space flags
-				if strings.Contains(flags, "+") && f64 >= 0.0 {
-					res = "+" + res
-				} else if strings.Contains(flags, " ") && f64 >= 0.0 {
-					res = " " + res
-				}
-				// zero-padding to width (if requested and not left-justified)
-				if strings.Contains(flags, "0") && !strings.Contains(flags, "-") && len(width) > 0 {
-					if w, err := strconv.Atoi(width); err == nil {
-						if lw := len(res); lw < w {
-							pad := w - lw
-							zeros := strings.Repeat("0", pad)
-							if strings.HasPrefix(res, "+") || strings.HasPrefix(res, " ") || strings.HasPrefix(res, "-") {
-								res = res[:1] + zeros + res[1:]
-							} else {
-								res = zeros + res
-							}
-						}
-					}
-				}
-				goConv = "s"
-				flags, width, precision = "", "", ""
-				outArgs = append(outArgs, res)
-			default:
-				// Fallback: let Go handle with original spec
-				goConv = "f"
-				outArgs = append(outArgs, val)
-			}
-		default:
-			// Pass-through, but ensure we supply the raw argument in Go-native type
-			outArgs = append(outArgs, normalizeForGo(rawArgs, useIndex))
+		str, gErr := formatArgument(spec, rawArgs[useIndex])
+		if gErr != nil {
+			return "", gErr
+		}
+		switch spec.conv {
+		case 'd', 'e', 'f', 'g':
+			str = localizeNumber(str, symbols)
 		}
+		if spec.upper {
+			str = strings.ToUpper(str)
+		}
+		b.WriteString(justifyFormatted(spec, str))
+	}
+	return b.String(), nil
+}
 
-		// Rebuild the format specifier without index or '<'
-		b.WriteByte('%')
-		b.WriteString(flags)
-		b.WriteString(width)
-		b.WriteString(precision)
-		b.WriteString(goConv)
+// parses the format specifier that starts at format[start], which is a %. Returns the
+// specifier and the index just past it.
+func parseFormatSpec(format string, start int) (*formatSpec, int, *GErrBlk) {
+	spec := &formatSpec{argIndex: -1, width: -1, precision: -1}
+	j := start + 1
 
-		i = j
+	// parse argument_index (digits+$) but only accept if a trailing '$' is present
+	tmp := j
+	for tmp < len(format) && isFormatDigit(format[tmp]) {
+		tmp++
+	}
+	if tmp < len(format) && format[tmp] == '$' && tmp > j {
+		if v, err := strconv.Atoi(format[j:tmp]); err == nil && v > 0 {
+			spec.argIndex = v - 1
+		}
+		j = tmp + 1
 	}
-	return b.String(), outArgs
-}
 
-func coerceJavaBoolean(args []interface{}, idx int) bool {
-	if args == nil || idx < 0 || idx >= len(args) {
-		return false
+	// flags
+	flagsStart := j
+	for j < len(format) && strings.IndexByte("-#+ 0,(<", format[j]) >= 0 {
+		j++
 	}
-	v := args[idx]
-	switch vv := v.(type) {
-	case bool:
-		return vv
-	case *object.Object:
-		if vv == nil || object.IsNull(vv) {
-			return false
+	spec.flags = format[flagsStart:j]
+	if strings.Contains(spec.flags, "<") {
+		spec.reusePrev = true
+		spec.flags = strings.ReplaceAll(spec.flags, "<", "")
+	}
+
+	// width
+	widthStart := j
+	for j < len(format) && isFormatDigit(format[j]) {
+		j++
+	}
+	if j > widthStart {
+		spec.width, _ = strconv.Atoi(format[widthStart:j])
+	}
+
+	// precision
+	if j+1 < len(format) && format[j] == '.' && isFormatDigit(format[j+1]) {
+		k := j + 1
+		for k < len(format) && isFormatDigit(format[k]) {
+			k++
 		}
-		return true
+		spec.precision, _ = strconv.Atoi(format[j+1 : k])
+		j = k
+	}
+
+	if j >= len(format) {
+		return nil, j, getGErrBlk(excNames.UnknownFormatConversionException, "Conversion = '%'")
+	}
+	conv := format[j]
+	j++
+	if conv == 't' || conv == 'T' { // the date/time conversions have a suffix, as in %tY
+		if j >= len(format) {
+			errMsg := fmt.Sprintf("Conversion = '%c'", conv)
+			return nil, j, getGErrBlk(excNames.UnknownFormatConversionException, errMsg)
+		}
+		j++
+	}
+	spec.text = format[start:j]
+
+	switch conv {
+	case 'B', 'H', 'S', 'C', 'X', 'E', 'G', 'A', 'T':
+		spec.upper = true
+		conv += 'a' - 'A'
+	case 'b', 'h', 's', 'c', 'd', 'o', 'x', 'e', 'f', 'g', 'a', 't', 'n', '%':
 	default:
-		// primitives and strings are considered non-null in Java formatting
-		return true
+		errMsg := fmt.Sprintf("Conversion = '%c'", conv)
+		return nil, j, getGErrBlk(excNames.UnknownFormatConversionException, errMsg)
 	}
+	spec.conv = conv
+
+	for _, flag := range []byte(badFormatFlags[conv]) {
+		if spec.has(flag) {
+			errMsg := fmt.Sprintf("Conversion = %c, Flags = %c", conv, flag)
+			return nil, j, getGErrBlk(excNames.FormatFlagsConversionMismatchException, errMsg)
+		}
+	}
+	return spec, j, nil
 }
 
-func coerceJavaString(args []interface{}, idx int) string {
-	if args == nil || idx < 0 || idx >= len(args) {
-		return "null"
+func isFormatDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+// pads the formatted argument with spaces to the width of the specifier, on the right
+// if the - flag is present, otherwise on the left
+func justifyFormatted(spec *formatSpec, str string) string {
+	pad := spec.width - utf8.RuneCountInString(str)
+	if pad <= 0 {
+		return str
 	}
-	v := args[idx]
-	switch vv := v.(type) {
+	if spec.has('-') {
+		return str + strings.Repeat(" ", pad)
+	}
+	return strings.Repeat(" ", pad) + str
+}
+
+// formats a single argument by the conversion of the specifier, except for the case of
+// the uppercase conversions and the justification to the width
+func formatArgument(spec *formatSpec, arg interface{}) (string, *GErrBlk) {
+	switch spec.conv {
+	case 'b':
+		var str string
+		switch vv := arg.(type) {
+		case nil:
+			str = "false"
+		case bool:
+			str = strconv.FormatBool(vv)
+		default:
+			str = "true"
+		}
+		return truncateFormatted(spec, str), nil
+	case 'h':
+		if arg == nil {
+			return truncateFormatted(spec, "null"), nil
+		}
+		return truncateFormatted(spec, strconv.FormatUint(uint64(uint32(javaHashValue(arg))), 16)), nil
+	case 's', 't':
+		return truncateFormatted(spec, javaFormatString(arg)), nil
+	}
+
+	if arg == nil { // Java formats null as "null" for the other conversions
+		return "null", nil
+	}
+
+	switch spec.conv {
+	case 'c':
+		var cp int64
+		switch vv := arg.(type) {
+		case rune:
+			cp = int64(vv)
+		case intWithBits:
+			if vv.bits == 64 {
+				return "", illegalFormatConversion(spec, arg)
+			}
+			cp = vv.v
+		default:
+			return "", illegalFormatConversion(spec, arg)
+		}
+		if cp < 0 || cp > utf8.MaxRune {
+			errMsg := fmt.Sprintf("Code point = 0x%x", uint32(cp))
+			return "", getGErrBlk(excNames.IllegalFormatCodePointException, errMsg)
+		}
+		return string(rune(cp)), nil
+	case 'd', 'o', 'x':
+		return formatIntegral(spec, arg)
+	default: // 'e', 'f', 'g', 'a'
+		vv, ok := arg.(floatWithBits)
+		if !ok {
+			return "", illegalFormatConversion(spec, arg)
+		}
+		return formatFloating(spec, vv.v), nil
+	}
+}
+
+// the string of %s: the Java toString() of the argument
+func javaFormatString(arg interface{}) string {
+	switch vv := arg.(type) {
+	case nil:
+		return "null"
 	case string:
 		return vv
-	case *object.Object:
-		if vv == nil || object.IsNull(vv) {
-			return "null"
-		}
-		// For non-String ref, mimic minimal Object.toString(): ClassName@hex
-		klass := object.GetClassNameSuffix(vv, true)
-		addr := fmt.Sprintf("%p", vv) // e.g., 0x1234abcd
-		if strings.HasPrefix(addr, "0x") {
-			addr = addr[2:]
+	case bool:
+		return strconv.FormatBool(vv)
+	case rune:
+		return string(vv)
+	case intWithBits:
+		return strconv.FormatInt(vv.v, 10)
+	case floatWithBits:
+		if vv.bits == 32 {
+			return object.JavaFloatString(vv.v)
 		}
-		return fmt.Sprintf("%s@%s", klass, addr)
+		return object.JavaDoubleString(vv.v)
+	case *big.Int:
+		return vv.String()
+	case *object.Object:
+		// Object.toString(): the class name and the identity hash code in hex
+		return fmt.Sprintf("%s@%x", javaFormatClassName(arg), object.IdentityHashCode(vv))
 	default:
 		return fmt.Sprintf("%v", vv)
 	}
 }
 
-func normalizeForGo(args []interface{}, idx int) interface{} {
-	if args == nil || idx < 0 || idx >= len(args) {
-		return nil
+// truncates the formatted argument to the precision of the specifier, if there is one
+func truncateFormatted(spec *formatSpec, str string) string {
+	if spec.precision < 0 || utf8.RuneCountInString(str) <= spec.precision {
+		return str
 	}
-	v := args[idx]
-	switch vv := v.(type) {
+	return string([]rune(str)[:spec.precision])
+}
+
+// returns the Java class name of an argument, as in the message of an exception
+func javaFormatClassName(arg interface{}) string {
+	switch vv := arg.(type) {
+	case string:
+		return "java.lang.String"
+	case bool:
+		return "java.lang.Boolean"
+	case rune:
+		return "java.lang.Character"
+	case intWithBits:
+		switch vv.bits {
+		case 8:
+			return "java.lang.Byte"
+		case 16:
+			return "java.lang.Short"
+		case 32:
+			return "java.lang.Integer"
+		}
+		return "java.lang.Long"
+	case floatWithBits:
+		if vv.bits == 32 {
+			return "java.lang.Float"
+		}
+		return "java.lang.Double"
+	case *big.Int:
+		return "java.math.BigInteger"
 	case *object.Object:
-		// For unknown object refs, format as their toString-like string
-		return coerceJavaString(args, idx)
+		return strings.ReplaceAll(object.GoStringFromStringPoolIndex(vv.KlassName), "/", ".")
+	}
+	return fmt.Sprintf("%T", arg)
+}
+
+func illegalFormatConversion(spec *formatSpec, arg interface{}) *GErrBlk {
+	errMsg := fmt.Sprintf("%c != %s", spec.conv, javaFormatClassName(arg))
+	return getGErrBlk(excNames.IllegalFormatConversionException, errMsg)
+}
+
+// formats an integral argument (byte, short, int, long, or BigInteger) for %d, %o, or %x
+func formatIntegral(spec *formatSpec, arg interface{}) (string, *GErrBlk) {
+	var value *big.Int
+	switch vv := arg.(type) {
 	case intWithBits:
-		return vv.v
+		value = big.NewInt(vv.v)
+		// For hex/octal, Java uses two's complement unsigned representation of the primitive width
+		if spec.conv != 'd' && vv.v < 0 {
+			value = new(big.Int).SetUint64(uint64(vv.v) & (math.MaxUint64 >> (64 - vv.bits)))
+		}
+		if spec.conv != 'd' {
+			for _, flag := range []byte("+ (") {
+				if spec.has(flag) {
+					errMsg := fmt.Sprintf("Conversion = %c, Flags = %c", spec.conv, flag)
+					return "", getGErrBlk(excNames.FormatFlagsConversionMismatchException, errMsg)
+				}
+			}
+		}
+	case *big.Int:
+		value = vv
 	default:
-		return vv
+		return "", illegalFormatConversion(spec, arg)
+	}
+
+	neg := value.Sign() < 0
+	magnitude := new(big.Int).Abs(value)
+	prefix := ""
+	var digits string
+	switch spec.conv {
+	case 'd':
+		digits = magnitude.String()
+		if spec.has(',') {
+			digits = groupDigits(digits)
+		}
+	case 'o':
+		digits = magnitude.Text(8)
+		if spec.has('#') {
+			prefix = "0"
+		}
+	case 'x':
+		digits = magnitude.Text(16)
+		if spec.has('#') {
+			prefix = "0x"
+		}
+	}
+	return signAndPad(spec, neg, prefix, digits), nil
+}
+
+// adds the sign, the prefix (such as 0x), and, for the 0 flag, the leading zeros to the
+// digits of a number
+func signAndPad(spec *formatSpec, neg bool, prefix, digits string) string {
+	sign, suffix := "", ""
+	switch {
+	case neg && spec.has('('):
+		sign, suffix = "(", ")"
+	case neg:
+		sign = "-"
+	case spec.has('+'):
+		sign = "+"
+	case spec.has(' '):
+		sign = " "
+	}
+	if spec.has('0') {
+		if pad := spec.width - len(sign) - len(prefix) - len(digits) - len(suffix); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+	}
+	return sign + prefix + digits + suffix
+}
+
+// inserts a comma between each group of three digits of the integer part of a number
+func groupDigits(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	lead := len(digits) % 3
+	if lead > 0 {
+		b.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(digits[i : i+3])
 	}
+	return b.String()
 }
 
+// formats a floating-point argument (float or double) for %e, %f, %g, or %a
+func formatFloating(spec *formatSpec, value float64) string {
+	if math.IsNaN(value) {
+		return "NaN"
+	}
+	neg := math.Signbit(value)
+	if math.IsInf(value, 0) { // the sign flags apply, but not the 0 flag
+		switch {
+		case neg && spec.has('('):
+			return "(Infinity)"
+		case neg:
+			return "-Infinity"
+		case spec.has('+'):
+			return "+Infinity"
+		case spec.has(' '):
+			return " Infinity"
+		}
+		return "Infinity"
+	}
+	value = math.Abs(value)
+
+	precision := spec.precision
+	if precision < 0 {
+		precision = 6
+	}
+	dec := shortestDecimal(value)
+
+	var digits string
+	switch spec.conv {
+	case 'e':
+		digits = dec.scientific(precision, spec.has('#'))
+	case 'f':
+		digits = dec.fixed(precision, spec.has(','), spec.has('#'))
+	case 'g':
+		if precision == 0 {
+			precision = 1
+		}
+		rounded := dec.round(precision)
+		if value == 0 {
+			digits = dec.fixed(precision-1, spec.has(','), false)
+		} else if rounded.point >= -3 && rounded.point <= precision { // 10^-4 <= rounded value < 10^precision
+			digits = dec.fixed(precision-rounded.point, spec.has(','), false)
+		} else {
+			digits = dec.scientific(precision-1, false)
+		}
+	case 'a':
+		return signAndPad(spec, neg, "0x", hexSignificand(value))
+	}
+	return signAndPad(spec, neg, "", digits)
+}
+
+// a non-negative decimal number, 0.digits x 10^point. An empty digits string is zero.
+type formatDecimal struct {
+	digits string
+	point  int
+}
+
+// returns the shortest decimal that uniquely distinguishes the non-negative value, which
+// are the digits that Java rounds from for formatting
+func shortestDecimal(value float64) formatDecimal {
+	if value == 0 {
+		return formatDecimal{digits: "0", point: 1}
+	}
+	mantissa, exp, _ := strings.Cut(strconv.FormatFloat(value, 'e', -1, 64), "e")
+	point, _ := strconv.Atoi(exp)
+	return formatDecimal{digits: strings.Replace(mantissa, ".", "", 1), point: point + 1}
+}
+
+// returns the decimal rounded HALF_UP to n digits
+func (d formatDecimal) round(n int) formatDecimal {
+	if n >= len(d.digits) {
+		return d
+	}
+	if n < 0 || (n == 0 && d.digits[0] < '5') {
+		return formatDecimal{point: d.point}
+	}
+	kept := []byte(d.digits[:n])
+	if d.digits[n] < '5' {
+		return formatDecimal{digits: string(kept), point: d.point}
+	}
+	for i := n - 1; i >= 0; i-- {
+		if kept[i] < '9' {
+			kept[i]++
+			return formatDecimal{digits: string(kept), point: d.point}
+		}
+		kept[i] = '0'
+	}
+	return formatDecimal{digits: "1" + string(kept), point: d.point + 1} // all nines: 99.9 -> 100
+}
+
+// returns the digit at the given position, counting from the first digit, which is 0
+func (d formatDecimal) digitAt(pos int) byte {
+	if pos < 0 || pos >= len(d.digits) {
+		return '0'
+	}
+	return d.digits[pos]
+}
+
+// formats the decimal as %f does, with the given number of digits after the decimal point
+func (d formatDecimal) fixed(precision int, group, forcePoint bool) string {
+	r := d.round(d.point + precision)
+	intPart := "0"
+	if r.point > 0 {
+		digits := make([]byte, r.point)
+		for i := range digits {
+			digits[i] = r.digitAt(i)
+		}
+		intPart = strings.TrimLeft(string(digits), "0")
+		if intPart == "" {
+			intPart = "0"
+		}
+	}
+	if group {
+		intPart = groupDigits(intPart)
+	}
+	if precision == 0 {
+		if forcePoint {
+			return intPart + "."
+		}
+		return intPart
+	}
+	frac := make([]byte, precision)
+	for i := range frac {
+		frac[i] = r.digitAt(r.point + i)
+	}
+	return intPart + "." + string(frac)
+}
+
+// formats the decimal as %e does, with the given number of digits after the decimal point
+func (d formatDecimal) scientific(precision int, forcePoint bool) string {
+	exp := 0
+	r := formatDecimal{}
+	if d.digits != "0" {
+		r = d.round(precision + 1)
+		exp = r.point - 1
+	}
+	mantissa := string(r.digitAt(0))
+	if precision > 0 || forcePoint {
+		mantissa += "."
+	}
+	for i := 1; i <= precision; i++ {
+		mantissa += string(r.digitAt(i))
+	}
+	expSign := "+"
+	if exp < 0 {
+		expSign = "-"
+		exp = -exp
+	}
+	return fmt.Sprintf("%se%s%02d", mantissa, expSign, exp)
+}
+
+// formats the non-negative value as Double.toHexString() does, without the 0x prefix
+func hexSignificand(value float64) string {
+	if value == 0 {
+		return "0.0p0"
+	}
+	bits := math.Float64bits(value)
+	exp := int(bits>>52) & 0x7FF
+	fraction := strings.TrimRight(fmt.Sprintf("%013x", bits&(1<<52-1)), "0")
+	if fraction == "" {
+		fraction = "0"
+	}
+	if exp == 0 { // subnormal
+		return "0." + fraction + "p-1022"
+	}
+	return "1." + fraction + "p" + strconv.Itoa(exp-1023)
+}
+
+// returns the Java hashCode() of the argument, as used by %h
 func javaHashValue(v interface{}) uint64 {
 	switch vv := v.(type) {
 	case nil:
@@ -417,26 +801,31 @@ func javaHashValue(v interface{}) uint64 {
 			return 1231
 		}
 		return 1237
+	case intWithBits:
+		if vv.bits == 64 {
+			return uint64(uint32(vv.v ^ int64(uint64(vv.v)>>32)))
+		}
+		return uint64(uint32(vv.v))
 	case int64:
 		return uint64(vv)
+	case floatWithBits:
+		if vv.bits == 32 {
+			return uint64(math.Float32bits(float32(vv.v)))
+		}
+		bits := mathFloat64bits(vv.v)
+		return uint64(uint32(bits ^ (bits >> 32)))
 	case float64:
 		// use IEEE bits as basis
 		return uint64(mathFloat64bits(vv))
 	case rune:
 		return uint64(vv)
 	case string:
-		return uint64(javaStringHashCode(vv))
+		return uint64(uint32(javaStringHashCode(vv)))
 	case *object.Object:
 		if vv == nil || object.IsNull(vv) {
 			return 0
 		}
-		// use pointer address as identity hash surrogate
-		addrStr := fmt.Sprintf("%p", vv)
-		if strings.HasPrefix(addrStr, "0x") {
-			addrStr = addrStr[2:]
-		}
-		ui, _ := strconv.ParseUint(addrStr, 16, 64)
-		return ui
+		return uint64(object.IdentityHashCode(vv))
 	default:
 		return 0
 	}
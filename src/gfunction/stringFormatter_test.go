@@ -1,10 +1,14 @@
 package gfunction
 
 import (
+    "fmt"
+    "jacobin/src/excNames"
+    "jacobin/src/globals"
     "jacobin/src/object"
     "jacobin/src/types"
+    "math"
+    "math/big"
     "runtime"
-    "strings"
     "testing"
 )
 
@@ -37,6 +41,18 @@ func TestStringFormatter_Newline_And_Boolean(t *testing.T) {
     }
 }
 
+func TestStringFormatter_Newline_LineSeparator(t *testing.T) {
+    saved := globals.GetLineSeparator()
+    t.Cleanup(func() { globals.SetLineSeparator(saved) })
+    globals.SetLineSeparator("\r\n")
+
+    fmtObj := object.StringObjectFromGoString("a%nb")
+    out := StringFormatter([]interface{}{fmtObj, makeObjectRefArray()})
+    if s := object.GoStringFromStringObject(out.(*object.Object)); s != "a\r\nb" {
+        t.Fatalf("unexpected output: %q want %q", s, "a\r\nb")
+    }
+}
+
 func TestStringFormatter_String_And_Uppercase(t *testing.T) {
     fmtObj := object.StringObjectFromGoString("%s %S")
     s1 := object.StringObjectFromGoString("hello")
@@ -71,8 +87,10 @@ func TestStringFormatter_Object_ToString_Like(t *testing.T) {
 
     out := StringFormatter([]interface{}{fmtObj, argsArr})
     got := object.GoStringFromStringObject(out.(*object.Object))
-    if !strings.HasPrefix(got, "obj=Dummy@") {
-        t.Fatalf("expected Dummy@..., got %q", got)
+    // as Object.toString(): the class name and the identity hash code
+    expected := fmt.Sprintf("obj=com.example.Dummy@%x", object.IdentityHashCode(o))
+    if got != expected {
+        t.Fatalf("got %q want %q", got, expected)
     }
 }
 
@@ -124,3 +142,158 @@ func TestStringFormatter_HexByte_Negative_TwoDigits(t *testing.T) {
         t.Fatalf("got %q want %q", got, expected)
     }
 }
+
+func TestJavaFormat_MatchesJDK(t *testing.T) {
+    globals.InitGlobals("test")
+
+    i32 := func(v int64) intWithBits { return intWithBits{v: v, bits: 32} }
+    i64 := func(v int64) intWithBits { return intWithBits{v: v, bits: 64} }
+    f64 := func(v float64) floatWithBits { return floatWithBits{v: v, bits: 64} }
+    bigValue, _ := new(big.Int).SetString("12345678901234567890", 10)
+
+    tests := []struct {
+        format   string
+        args     []interface{}
+        expected string
+    }{
+        {"%d", []interface{}{i32(42)}, "42"},
+        {"%,d", []interface{}{i32(1234567)}, "1,234,567"},
+        {"%,d", []interface{}{i32(-1234567)}, "-1,234,567"},
+        {"%(,d", []interface{}{i32(-1234)}, "(1,234)"},
+        {"%+d % d", []interface{}{i32(5), i32(5)}, "+5  5"},
+        {"%08d", []interface{}{i32(-42)}, "-0000042"},
+        {"%,010d", []interface{}{i32(1234)}, "000001,234"},
+        {"%-6d|", []interface{}{i32(42)}, "42    |"},
+        {"%d", []interface{}{i64(math.MinInt64)}, "-9223372036854775808"},
+        {"%x", []interface{}{i32(-1)}, "ffffffff"},
+        {"%x", []interface{}{i64(-1)}, "ffffffffffffffff"},
+        {"%x", []interface{}{intWithBits{v: -1, bits: 8}}, "ff"},
+        {"%X %#x %#o", []interface{}{i32(255), i32(255), i32(8)}, "FF 0xff 010"},
+        {"%#010x", []interface{}{i32(255)}, "0x000000ff"},
+        {"%,d", []interface{}{bigValue}, "12,345,678,901,234,567,890"},
+        {"%x", []interface{}{big.NewInt(-255)}, "-ff"},
+        {"%2$s %1$s", []interface{}{"a", "b"}, "b a"},
+        {"%s %<s %s", []interface{}{"a", "b"}, "a a b"},
+        {"%2$s %s %s", []interface{}{"a", "b"}, "b a b"},
+        {"%b %b %B", []interface{}{nil, "x", false}, "false true FALSE"},
+        {"%-5b|", []interface{}{true}, "true |"},
+        {"%s %s %s", []interface{}{nil, 'c', floatWithBits{v: 0.1, bits: 32}}, "null c 0.1"},
+        {"%S", []interface{}{f64(1e10)}, "1.0E10"},
+        {"%10.3s|", []interface{}{"abcdef"}, "       abc|"},
+        {"%c%c", []interface{}{'A', i32(0x1F600)}, "A\U0001F600"},
+        {"%d %x", []interface{}{nil, nil}, "null null"},
+        {"%h", []interface{}{"abc"}, "17862"},
+        {"%h %h", []interface{}{i64(-1), f64(1.0)}, "0 3ff00000"},
+        {"100%% %5%|", nil, "100%     %|"},
+        {"%e", []interface{}{f64(12345.678)}, "1.234568e+04"},
+        {"%.2e", []interface{}{f64(0.000123456)}, "1.23e-04"},
+        {"%E", []interface{}{f64(1e100)}, "1.000000E+100"},
+        {"%e", []interface{}{f64(0)}, "0.000000e+00"},
+        {"%f", []interface{}{f64(3.14159)}, "3.141590"},
+        {"%.1f %.1f", []interface{}{f64(0.25), f64(0.15)}, "0.3 0.2"},
+        {"%.2f", []interface{}{f64(1.005)}, "1.01"},
+        {"%.0f %#.0f", []interface{}{f64(2.5), f64(2.5)}, "3 3."},
+        {"%.2f", []interface{}{f64(99.999)}, "100.00"},
+        {"%.20f", []interface{}{f64(0.1)}, "0.10000000000000000000"},
+        {"%.3f", []interface{}{floatWithBits{v: float64(float32(0.1)), bits: 32}}, "0.100"},
+        {"%.10f", []interface{}{floatWithBits{v: float64(float32(0.1)), bits: 32}}, "0.1000000015"},
+        {"%,.2f", []interface{}{f64(1234567.891)}, "1,234,567.89"},
+        {"%+.1f|%08.2f", []interface{}{f64(1.25), f64(-3.5)}, "+1.3|-0003.50"},
+        {"%.1f", []interface{}{f64(math.Copysign(0, -1))}, "-0.0"},
+        {"%f %f %(f %+f", []interface{}{f64(math.NaN()), f64(math.Inf(-1)), f64(math.Inf(-1)), f64(math.Inf(1))},
+            "NaN -Infinity (Infinity) +Infinity"},
+        {"%g", []interface{}{f64(123.456)}, "123.456"},
+        {"%g", []interface{}{f64(0.0001)}, "0.000100000"},
+        {"%g", []interface{}{f64(0.00001)}, "1.00000e-05"},
+        {"%g", []interface{}{f64(1234567)}, "1.23457e+06"},
+        {"%.3g", []interface{}{f64(1234)}, "1.23e+03"},
+        {"%g", []interface{}{f64(0)}, "0.00000"},
+        {"%a %a %a", []interface{}{f64(1), f64(0.5), f64(-2.5)}, "0x1.0p0 0x1.0p-1 -0x1.4p1"},
+        {"%A", []interface{}{f64(math.SmallestNonzeroFloat64)}, "0X0.0000000000001P-1022"},
+    }
+
+    for _, test := range tests {
+        got, gErr := javaFormat(test.format, test.args)
+        if gErr != nil {
+            t.Errorf("format %q: unexpected exception: %s", test.format, gErr.ErrMsg)
+        } else if got != test.expected {
+            t.Errorf("format %q: got %q want %q", test.format, got, test.expected)
+        }
+    }
+}
+
+func TestJavaFormat_Exceptions(t *testing.T) {
+    globals.InitGlobals("test")
+
+    tests := []struct {
+        format  string
+        args    []interface{}
+        excType int
+        errMsg  string
+    }{
+        {"%d", []interface{}{"x"}, excNames.IllegalFormatConversionException, "d != java.lang.String"},
+        {"%f", []interface{}{intWithBits{v: 1, bits: 32}}, excNames.IllegalFormatConversionException,
+            "f != java.lang.Integer"},
+        {"%c", []interface{}{intWithBits{v: 65, bits: 64}}, excNames.IllegalFormatConversionException,
+            "c != java.lang.Long"},
+        {"%s %s", []interface{}{"x"}, excNames.MissingFormatArgumentException, "Format specifier '%s'"},
+        {"%3$s", []interface{}{"x"}, excNames.MissingFormatArgumentException, "Format specifier '%3$s'"},
+        {"%<s", []interface{}{"x"}, excNames.MissingFormatArgumentException, "Format specifier '%<s'"},
+        {"%q", nil, excNames.UnknownFormatConversionException, "Conversion = 'q'"},
+        {"abc%", nil, excNames.UnknownFormatConversionException, "Conversion = '%'"},
+        {"%,x", []interface{}{intWithBits{v: 1, bits: 32}}, excNames.FormatFlagsConversionMismatchException,
+            "Conversion = x, Flags = ,"},
+        {"%+x", []interface{}{intWithBits{v: 1, bits: 32}}, excNames.FormatFlagsConversionMismatchException,
+            "Conversion = x, Flags = +"},
+        {"%#s", []interface{}{"x"}, excNames.FormatFlagsConversionMismatchException, "Conversion = s, Flags = #"},
+        {"%c", []interface{}{intWithBits{v: 0x110000, bits: 32}}, excNames.IllegalFormatCodePointException,
+            "Code point = 0x110000"},
+    }
+
+    for _, test := range tests {
+        got, gErr := javaFormat(test.format, test.args)
+        if gErr == nil {
+            t.Errorf("format %q: expected an exception, got %q", test.format, got)
+            continue
+        }
+        if gErr.ExceptionType != test.excType || gErr.ErrMsg != test.errMsg {
+            t.Errorf("format %q: got %s: %s, want %s: %s", test.format,
+                excNames.JVMexceptionNames[gErr.ExceptionType], gErr.ErrMsg,
+                excNames.JVMexceptionNames[test.excType], test.errMsg)
+        }
+    }
+}
+
+func TestStringFormatter_NullArgumentArray(t *testing.T) {
+    globals.InitGlobals("test")
+
+    fmtObj := object.StringObjectFromGoString("100%%")
+    out := StringFormatter([]interface{}{fmtObj, object.Null})
+    got := object.GoStringFromStringObject(out.(*object.Object))
+    if got != "100%" {
+        t.Fatalf("got %q want %q", got, "100%")
+    }
+}
+
+func TestStringFormatter_LocaleSeparators(t *testing.T) {
+    globals.InitGlobals("test")
+
+    value := Populator("java/lang/Double", types.Double, 1234567.891)
+    tests := []struct {
+        locale   interface{}
+        expected string
+    }{
+        {makeLocale("de_DE"), "1.234.567,89 1,2e+06"},
+        {makeLocale("fr_FR"), "1\u202f234\u202f567,89 1,2e+06"},
+        {makeLocale("en_US"), "1,234,567.89 1.2e+06"},
+        {object.Null, "1,234,567.89 1.2e+06"},
+    }
+    for _, tt := range tests {
+        fmtObj := object.StringObjectFromGoString("%,.2f %.1e")
+        out := sprintfLocale([]interface{}{tt.locale, fmtObj, makeObjectRefArray(value, value)})
+        got := object.GoStringFromStringObject(out.(*object.Object))
+        if got != tt.expected {
+            t.Errorf("String.format(%v): got %q want %q", tt.locale, got, tt.expected)
+        }
+    }
+}
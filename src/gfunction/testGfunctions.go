@@ -159,7 +159,7 @@ func ld(params []any) any {
 }
 
 func ie(params []any) any {
-	geb := GErrBlk{excNames.InternalException, "intended return of test error"}
+	geb := GErrBlk{ExceptionType: excNames.InternalException, ErrMsg: "intended return of test error"}
 	return &geb
 }
 
@@ -0,0 +1,87 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// The gfunction usage report, enabled by -XX:+PrintGfunctionUsageAtExit, counts the
+// calls to each gfunction and the calls that hit a trap (see Traps.go), and prints
+// them when the program ends. Run over real workloads, it shows maintainers which
+// JDK methods programs use and which unsupported ones to implement next.
+
+// GfunctionUsage is the count of calls to one gfunction
+type GfunctionUsage struct {
+	FQN   string // the fully qualified name of the method, e.g., java/lang/String.length()I
+	Calls int64
+	Traps int64 // the calls that hit a trap, which is all of them or none
+}
+
+type usageCounts struct {
+	calls atomic.Int64
+	traps atomic.Int64
+}
+
+var gfunctionUsage sync.Map // FQN -> *usageCounts
+
+// records a call to the named gfunction. Called by RunGfunction() when the report is enabled.
+func recordGfunctionCall(methFQN string, trapped bool) {
+	value, ok := gfunctionUsage.Load(methFQN)
+	if !ok {
+		value, _ = gfunctionUsage.LoadOrStore(methFQN, &usageCounts{})
+	}
+	counts := value.(*usageCounts)
+	counts.calls.Add(1)
+	if trapped {
+		counts.traps.Add(1)
+	}
+}
+
+// GfunctionUsageReport returns the counts of the gfunctions called so far: the trapped
+// ones first, as they are the candidates for implementation, then the others, each
+// ordered by the number of calls and then by name.
+func GfunctionUsageReport() []GfunctionUsage {
+	var report []GfunctionUsage
+	gfunctionUsage.Range(func(key, value any) bool {
+		counts := value.(*usageCounts)
+		report = append(report, GfunctionUsage{
+			FQN: key.(string), Calls: counts.calls.Load(), Traps: counts.traps.Load()})
+		return true
+	})
+
+	sort.Slice(report, func(i, j int) bool {
+		if (report[i].Traps > 0) != (report[j].Traps > 0) {
+			return report[i].Traps > 0
+		}
+		if report[i].Calls != report[j].Calls {
+			return report[i].Calls > report[j].Calls
+		}
+		return report[i].FQN < report[j].FQN
+	})
+	return report
+}
+
+// PrintGfunctionUsage writes the gfunction usage report to out, one gfunction per
+// line, followed by the totals. It's used by -XX:+PrintGfunctionUsageAtExit.
+func PrintGfunctionUsage(out io.Writer) {
+	report := GfunctionUsageReport()
+	var calls, traps int64
+	_, _ = fmt.Fprintln(out, "---- start of gfunction usage report ----")
+	_, _ = fmt.Fprintf(out, "%10s %10s  %s\n", "calls", "traps", "gfunction")
+	for _, usage := range report {
+		_, _ = fmt.Fprintf(out, "%10d %10d  %s\n", usage.Calls, usage.Traps, usage.FQN)
+		calls += usage.Calls
+		traps += usage.Traps
+	}
+	_, _ = fmt.Fprintf(out, "---- end of gfunction usage report: %d gfunctions, %d calls, %d traps ----\n",
+		len(report), calls, traps)
+}
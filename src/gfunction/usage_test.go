@@ -0,0 +1,46 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGfunctionUsageReport(t *testing.T) {
+	gfunctionUsage = sync.Map{}
+	defer func() { gfunctionUsage = sync.Map{} }()
+
+	for i := 0; i < 3; i++ {
+		recordGfunctionCall("java/lang/String.length()I", false)
+	}
+	recordGfunctionCall("java/lang/Math.abs(I)I", false)
+	recordGfunctionCall("java/rmi/RMISecurityManager.<init>()V", true)
+
+	report := GfunctionUsageReport()
+	expected := []GfunctionUsage{
+		{FQN: "java/rmi/RMISecurityManager.<init>()V", Calls: 1, Traps: 1},
+		{FQN: "java/lang/String.length()I", Calls: 3},
+		{FQN: "java/lang/Math.abs(I)I", Calls: 1},
+	}
+	if len(report) != len(expected) {
+		t.Fatalf("Expected %d gfunctions in the report, got: %v", len(expected), report)
+	}
+	for i := range expected {
+		if report[i] != expected[i] {
+			t.Errorf("Expected entry %d to be %v, got: %v", i, expected[i], report[i])
+		}
+	}
+
+	var out bytes.Buffer
+	PrintGfunctionUsage(&out)
+	if !strings.Contains(out.String(), "3 gfunctions, 5 calls, 1 traps") {
+		t.Errorf("Expected the totals in the report, got: %s", out.String())
+	}
+}
@@ -0,0 +1,100 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package globals
+
+import (
+	"jacobin/src/util"
+	"strings"
+	"sync"
+)
+
+// The assertion status of classes, as set by the command-line options:
+//
+//	-ea, -da                 enable or disable assertions in all but the system classes
+//	-ea:<package>...         ... in the package and its subpackages (-ea:... is the unnamed package)
+//	-ea:<class>              ... in the class
+//	-esa, -dsa               enable or disable assertions in the system (JDK) classes
+//
+// As in HotSpot, a class's status is that of its class rule, if there is one, else of
+// the rule for its most specific package, else the default for system or other classes.
+// A later option for the same class or package overrides an earlier one. The status
+// is what Class.desiredAssertionStatus() returns, from which the static initializers
+// that javac generates set each class's $assertionsDisabled.
+
+type assertionRules struct {
+	lock          sync.Mutex
+	enabled       bool            // the default for classes other than system classes
+	systemEnabled bool            // the default for system classes
+	classes       map[string]bool // by class name, in internal form (java/lang/String)
+	packages      map[string]bool // by package name, in internal form; "" is the unnamed package
+}
+
+var assertions = assertionRules{classes: make(map[string]bool), packages: make(map[string]bool)}
+
+// SetAssertionStatus applies an -ea (enabled) or -da option, whose argument--after
+// the colon, if any--is given in the form of the command line: empty, a class name,
+// or a package name followed by "..."
+func SetAssertionStatus(arg string, enabled bool) {
+	assertions.lock.Lock()
+	defer assertions.lock.Unlock()
+	name := strings.ReplaceAll(arg, ".", "/")
+	switch {
+	case arg == "":
+		assertions.enabled = enabled
+	case strings.HasSuffix(arg, "..."):
+		assertions.packages[strings.TrimSuffix(name, "///")] = enabled
+	default:
+		assertions.classes[name] = enabled
+	}
+}
+
+// SetSystemAssertionStatus applies an -esa (enabled) or -dsa option
+func SetSystemAssertionStatus(enabled bool) {
+	assertions.lock.Lock()
+	assertions.systemEnabled = enabled
+	assertions.lock.Unlock()
+}
+
+// DesiredAssertionStatus returns whether assertions are enabled in the class, whose
+// name is in internal form
+func DesiredAssertionStatus(className string) bool {
+	assertions.lock.Lock()
+	defer assertions.lock.Unlock()
+	if enabled, ok := assertions.classes[className]; ok {
+		return enabled
+	}
+
+	pkg := ""
+	if slash := strings.LastIndex(className, "/"); slash >= 0 {
+		pkg = className[:slash]
+	}
+	for {
+		if enabled, ok := assertions.packages[pkg]; ok {
+			return enabled
+		}
+		slash := strings.LastIndex(pkg, "/")
+		if slash < 0 {
+			break // the unnamed package applies only to its own classes
+		}
+		pkg = pkg[:slash]
+	}
+
+	if util.IsFilePartOfJDK(&className) {
+		return assertions.systemEnabled
+	}
+	return assertions.enabled
+}
+
+// ResetAssertionStatus disables assertions in all classes, as they are by default
+func ResetAssertionStatus() {
+	assertions.lock.Lock()
+	assertions.enabled = false
+	assertions.systemEnabled = false
+	assertions.classes = make(map[string]bool)
+	assertions.packages = make(map[string]bool)
+	assertions.lock.Unlock()
+}
@@ -0,0 +1,63 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package globals
+
+import "testing"
+
+func TestAssertionsDisabledByDefault(t *testing.T) {
+	ResetAssertionStatus()
+	if DesiredAssertionStatus("Hello") || DesiredAssertionStatus("java/lang/String") {
+		t.Errorf("Expected assertions to be disabled by default")
+	}
+}
+
+func TestAssertionStatusRules(t *testing.T) {
+	ResetAssertionStatus()
+	defer ResetAssertionStatus()
+
+	SetAssertionStatus("", true)                     // -ea
+	SetAssertionStatus("com.acme...", false)         // -da:com.acme...
+	SetAssertionStatus("com.acme.core...", true)     // -ea:com.acme.core...
+	SetAssertionStatus("com.acme.core.Quiet", false) // -da:com.acme.core.Quiet
+	SetAssertionStatus("...", false)                 // -da:...
+
+	for className, expected := range map[string]bool{
+		"org/other/Main":            true,  // the default
+		"com/acme/Tool":             false, // the package
+		"com/acme/util/Strings":     false, // a subpackage
+		"com/acme/core/Engine":      true,  // the more specific package
+		"com/acme/core/sub/Part":    true,
+		"com/acme/core/Quiet":       false, // the class
+		"Hello":                     false, // the unnamed package
+		"java/lang/String":          false, // system classes are not affected by -ea
+		"com/acmeother/NotAPackage": true,
+	} {
+		if DesiredAssertionStatus(className) != expected {
+			t.Errorf("Expected the assertion status of %s to be %v", className, expected)
+		}
+	}
+
+	// a later option for the same package overrides an earlier one
+	SetAssertionStatus("com.acme...", true)
+	if !DesiredAssertionStatus("com/acme/Tool") {
+		t.Errorf("Expected the later -ea:com.acme... to override -da:com.acme...")
+	}
+}
+
+func TestSystemAssertionStatus(t *testing.T) {
+	ResetAssertionStatus()
+	defer ResetAssertionStatus()
+
+	SetSystemAssertionStatus(true) // -esa
+	if !DesiredAssertionStatus("java/util/HashMap") || DesiredAssertionStatus("Hello") {
+		t.Errorf("Expected -esa to enable assertions in the system classes only")
+	}
+	SetAssertionStatus("java.util...", false) // -da:java.util...
+	if DesiredAssertionStatus("java/util/HashMap") || !DesiredAssertionStatus("java/lang/String") {
+		t.Errorf("Expected -da:java.util... to disable assertions in java.util only")
+	}
+}
@@ -0,0 +1,38 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package globals
+
+import "sync"
+
+// The exit handler lets Go code that embeds Jacobin, and tests, intercept the end of
+// the program--by System.exit(), Runtime.exit(), an uncaught exception, or an error in
+// the JVM--rather than have the process end. shutdown.Exit() runs Jacobin's exit hooks
+// as usual, then calls the handler with the exit status in place of os.Exit(). If the
+// handler returns, so does Exit(), as it does in tests, and the code that called it
+// carries on; a handler that must stop the caller can end its goroutine instead, with
+// runtime.Goexit().
+
+var exitHandler func(status int)
+var exitHandlerLock sync.Mutex
+
+// SetExitHandler installs the handler that shutdown.Exit() calls in place of ending
+// the process. A nil handler restores the normal exit. Returns the previous handler,
+// so that a test can restore it when it's done.
+func SetExitHandler(handler func(status int)) (previous func(status int)) {
+	exitHandlerLock.Lock()
+	defer exitHandlerLock.Unlock()
+	previous = exitHandler
+	exitHandler = handler
+	return previous
+}
+
+// GetExitHandler returns the handler installed by SetExitHandler(), or nil if none is
+func GetExitHandler() func(status int) {
+	exitHandlerLock.Lock()
+	defer exitHandlerLock.Unlock()
+	return exitHandler
+}
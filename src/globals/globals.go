@@ -11,16 +11,19 @@ import (
 	"container/list"
 	"errors"
 	"fmt"
+	"io"
+	"jacobin/src/charset"
 	"jacobin/src/config"
 	"jacobin/src/types"
+	"jacobin/src/util"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"runtime"
 	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unicode"
 )
@@ -48,6 +51,12 @@ type Globals struct {
 
 	StartingClass string
 	StartingJar   string
+	SelfTestDir   string // the directory of the conformance suite run by -selftest
+	RecordFile    string // the log of nondeterministic inputs written by -record (see replay/replay.go)
+	ReplayFile    string // the log of nondeterministic inputs read by -replay
+	ValidateOnly  bool   // check that the program can run without running it; set by --validate-only (see jvm/validate.go)
+	ListGfuncs    bool   // list the gfunctions by class rather than run a program; set by --list-gfunctions
+	APICoverage   bool   // list the gfunctions and compare them with the JDK's methods; set by --api-coverage
 	AppArgs       []string
 	Options       map[string]Option
 
@@ -72,12 +81,38 @@ type Globals struct {
 	Threads    map[int]interface{} // in reality the interface is a threads.ExecThread, but
 	// due to circularity has to be described this way here.
 	ThreadNumber int
+	// the most frames a thread's frame stack may hold, set by -Xss. 0 means no limit.
+	MaxFrameDepth int
+
+	// ---- heap management, see object/heap.go ----
+	MaxHeapSize   int64 // the limit on the size of the Java heap in bytes, set by -Xmx. 0 means no limit.
+	HeapDumpOnOOM bool  // dump the heap on the first OutOfMemoryError; enabled by -XX:+HeapDumpOnOutOfMemoryError
+	UseNursery    bool  // reuse objects that never leave their methods; enabled by -XX:+UseAllocationNursery
 
 	// ---- execution context ----
 	JacobinBuildData map[string]string
 
 	// ---- special switches ----
-	StrictJDK bool // hew closely to actions and error messages of the JDK
+	StrictJDK       bool   // hew closely to actions and error messages of the JDK
+	ActiveCPUs      int    // the processors Jacobin uses, or -1 for all of them; set by -XX:ActiveProcessorCount
+	CompactCPs      bool   // share the UTF-8 strings of loaded classes' CPs; enabled by -XX:+CompactConstantPools
+	CountBytecodes  bool   // count the instructions executed for each opcode and print them at exit; enabled by -XX:+CountBytecodes
+	EnablePreview   bool   // run classes that use the preview features of MaxJavaVersion; set by --enable-preview
+	EnforceAccess   bool   // perform JVMS 5.4.4 access checks; disabled by -XX:-EnforceAccess
+	GreenThreads    bool   // run virtual threads on a bounded pool of goroutines; enabled by -XX:+GreenThreads
+	InterpretOnly   bool   // execute bytecode only in the interpreter, never compiling it (see jvm/jit.go); set by -Xint
+	JitThreshold    int    // the invocations of a method after which it's compiled; set by -XX:CompileThreshold
+	LintDeprecation bool   // warn at startup of calls to deprecated JDK methods; enabled by -Xlint:deprecation
+	OSRThreshold    int    // the backward branches in a method after which it's compiled; set by -XX:BackEdgeThreshold
+	PinThreads      bool   // run each Java thread on its own OS thread; enabled by -XX:+PinInterpreterThreads
+	PrintFlags      bool   // print the final values of the -XX flags; enabled by -XX:+PrintFlagsFinal
+	PrintGfuncUsage bool   // count the calls to each gfunction and print them at exit; enabled by -XX:+PrintGfunctionUsageAtExit
+	PrintMethArea   bool   // print the method area when the program ends; enabled by -XX:+PrintMethodAreaAtExit
+	PrintMTable     bool   // print the MTable when the program ends; enabled by -XX:+PrintMTableAtExit
+	PreferBytecode  string // the classes and methods whose JDK bytecode runs in place of their gfunctions; set by -XX:PreferBytecode
+	SafepointDelay  int    // the milliseconds to wait for threads to reach a safepoint; set by -XX:SafepointTimeoutDelay
+	TrapPolicy      string // what a call to a trapped gfunction does; set by -XX:TrapPolicy (see TrapThrow, etc.)
+	UseContainer    bool   // size the heap and CPUs to the container's cgroup limits; disabled by -XX:-UseContainerSupport
 
 	// ---- list of addresses of arrays, see jvm/arrays.go for info ----
 	ArrayAddressList *list.List
@@ -91,6 +126,7 @@ type Globals struct {
 	PanicCauseShown    bool
 	JvmFrameStackShown bool
 	GoStackShown       bool
+	TraceWriter        io.Writer // where trace and log messages go; nil means stderr. See SetTraceWriter
 
 	// Random object mutex
 	RandomLock sync.Mutex
@@ -109,6 +145,9 @@ type Globals struct {
 	FuncMinimalAbort     func(int, string)
 	FuncThrowException   func(int, string) bool
 	FuncFillInStackTrace func([]any) any
+	FuncInvokeJavaMethod func(*list.List, string, string, string, any, []any) (any, error)
+	FuncStartThread      func(*list.List, any) error
+	FuncUncaughtHandler  func(*list.List, any) bool
 }
 
 // ---- JJ options
@@ -122,17 +161,66 @@ var TraceClass bool
 var TraceVerbose bool
 
 // ----- String Pool
-var StringPoolTable map[string]uint32
-var StringPoolList []string
-var StringPoolNext uint32
+// The map of strings to their indices is split into shards by the hash of the string,
+// each with its own lock, so that threads loading classes in parallel seldom wait on
+// one another. The list of strings is replaced, never modified in place, when it has to
+// grow, so it can be read without a lock; StringPoolLock serializes additions to it.
+const StringPoolShardCount = 64
+
+type StringPoolShard struct {
+	Lock  sync.RWMutex
+	Table map[string]uint32
+}
+
+var StringPoolShards [StringPoolShardCount]StringPoolShard
+var StringPoolList atomic.Pointer[[]string]
 var StringPoolLock sync.Mutex
 
+// StringPoolShardOf returns the shard of the string pool's map that holds the string.
+// The hash is FNV-1a.
+func StringPoolShardOf(str string) *StringPoolShard {
+	hash := uint32(2166136261)
+	for i := 0; i < len(str); i++ {
+		hash ^= uint32(str[i])
+		hash *= 16777619
+	}
+	return &StringPoolShards[hash%StringPoolShardCount]
+}
+
 // LoaderWg is a wait group for various channels used for parallel loading of classes.
 var LoaderWg sync.WaitGroup
 
 // Standard Sleep amount in milliseconds used in various places.
 var SleepMsecs time.Duration = 5
 
+// DefaultThreadStackSize is the stack size of a thread if -Xss is not specified, as in
+// HotSpot. Jacobin's frames are not kept on a native stack, so a stack size is converted
+// to a maximum frame depth by taking every frame to use ApproxFrameSize bytes.
+const DefaultThreadStackSize = 1024 * 1024
+const ApproxFrameSize = 64
+
+// DefaultCompileThreshold is the number of times a method is invoked before the JIT
+// compiles it, if -XX:CompileThreshold is not specified, as in HotSpot without tiers
+const DefaultCompileThreshold = 10000
+
+// DefaultBackEdgeThreshold is the number of backward branches a method takes before the
+// JIT compiles it and the frames running it switch to the compiled code (on-stack
+// replacement), if -XX:BackEdgeThreshold is not specified, as in HotSpot without tiers
+const DefaultBackEdgeThreshold = 100000
+
+// DefaultSafepointTimeoutDelay is the number of milliseconds the VM waits for the threads
+// to reach a safepoint before it abandons an operation, if -XX:SafepointTimeoutDelay is
+// not specified, as in HotSpot
+const DefaultSafepointTimeoutDelay = 10000
+
+// the values of -XX:TrapPolicy, which determines what happens when a program calls
+// a gfunction that traps a JDK class or method Jacobin does not support
+const (
+	TrapThrow = "throw" // throw an UnsupportedOperationException (the default)
+	TrapWarn  = "warn"  // the same, but first log a warning the first time each method is trapped
+	TrapAbort = "abort" // end the program with an error message
+)
+
 // the Globals struct.
 var global Globals
 
@@ -140,9 +228,11 @@ var global Globals
 func InitGlobals(progName string) Globals {
 
 	global = Globals{ // in alpha order
+		ActiveCPUs:           -1, // as in HotSpot, -1 means all the processors the OS reports
 		ArrayAddressList:     InitArrayAddressList(),
 		Classpath:            make([]string, 1), // at least one element, the current directory
 		ClasspathRaw:         "",
+		EnforceAccess:        true,
 		ErrorGoStack:         "",
 		ExitNow:              false,
 		FileEncoding:         "UTF-8", // default encoding for file contents
@@ -155,17 +245,23 @@ func InitGlobals(progName string) Globals {
 		JacobinHome:          "",
 		JacobinName:          progName,
 		JavaHome:             "",
+		JitThreshold:         DefaultCompileThreshold,
 		JmodBaseBytes:        nil,
 		JVMframeStack:        nil,
 		JvmFrameStackShown:   false,
+		MaxFrameDepth:        DefaultThreadStackSize / ApproxFrameSize,
 		MaxJavaVersion:       21, // this value and MaxJavaVersionRaw must *always* be in sync
 		MaxJavaVersionRaw:    65, // this value and MaxJavaVersion must *always* be in sync
+		OSRThreshold:         DefaultBackEdgeThreshold,
 		Options:              make(map[string]Option),
 		PanicCauseShown:      false,
+		SafepointDelay:       DefaultSafepointTimeoutDelay,
 		StartingClass:        "",
 		StartingJar:          "",
 		StrictJDK:            false,
-		ThreadNumber:         0,                          // first thread will be numbered 1, as increment occurs prior
+		ThreadNumber:         0, // first thread will be numbered 1, as increment occurs prior
+		TrapPolicy:           TrapThrow,
+		UseContainer:         true,
 		Version:              config.GetJacobinVersion(), // gets version and build #
 		VmModel:              "server",
 	}
@@ -173,6 +269,9 @@ func InitGlobals(progName string) Globals {
 	// ----- G function alternative processing flag
 	Galt = false
 
+	// ----- assertions are disabled until -ea enables them (see assertions.go)
+	ResetAssertionStatus()
+
 	// ----- Tracing flags
 	TraceInit = false
 	TraceCloadi = false
@@ -202,15 +301,16 @@ func InitGlobals(progName string) Globals {
 	}
 	InitArrayAddressList()
 
-	if runtime.GOOS == "windows" {
-		global.FileEncoding = "windows-1252"
-	} else {
-		global.FileEncoding = "UTF-8"
-	}
+	// The default charset is that of the OS and locale, unless -Dfile.encoding overrides it.
+	charset.SetDefault(charset.Native())
+	global.FileEncoding = charset.Default().Name
 
 	// Make the encoding for filesystem names be the same as for file contents.
 	global.FileNameEncoding = global.FileEncoding
 
+	// The line separator is that of the platform, unless -Dline.separator overrides it.
+	lineSeparator = platformLineSeparator()
+
 	// Set up headlass mode for AWT from enviromment varialbe. May eventually be removed as we don't support AWT.
 	strHeadless := os.Getenv(StringEnvVarHeadless)
 	global.Headless = false
@@ -239,7 +339,7 @@ func InitClasspath() {
 		cp = strings.TrimSpace(cp)
 		cp = cleanupPath(cp) // convert slashes to current platform's path separator
 		global.ClasspathRaw = cp
-		global.Classpath = strings.Split(cp, string(os.PathListSeparator))
+		global.Classpath = util.GetPlatform().SplitPathList(cp)
 	} else {
 		global.ClasspathRaw, _ = os.Getwd()
 		global.Classpath[0] = global.ClasspathRaw
@@ -251,6 +351,23 @@ func GetGlobalRef() *Globals {
 	return &global
 }
 
+// SetTraceWriter directs trace and log messages (see the trace package) to w rather
+// than to stderr. This lets tests capture the messages without redirecting the
+// process's stderr. Passing nil restores stderr. InitGlobals also restores stderr.
+func SetTraceWriter(w io.Writer) {
+	global.TraceWriter = w
+}
+
+// GetTraceWriter returns the writer that trace and log messages go to. Note that
+// stderr is looked up on each call, rather than saved, so that replacing os.Stderr
+// still redirects the messages.
+func GetTraceWriter() io.Writer {
+	if global.TraceWriter == nil {
+		return os.Stderr
+	}
+	return global.TraceWriter
+}
+
 // Option is the value portion of the globals.options table. This table is described in
 // more detail in option_table_loader.go introductory comments
 type Option struct {
@@ -274,7 +391,7 @@ func InitJacobinHome() {
 			_, _ = fmt.Fprintf(os.Stderr, "%s\n", err.Error())
 			return
 		}
-		jacobinHome = userHomeDir + string(os.PathSeparator) + "jacobin_data"
+		jacobinHome = util.GetPlatform().JoinPath(userHomeDir, "jacobin_data")
 	}
 	// 0755 (Unix octal): user(owner) can do anything, group and other can read and visit directory ("execute").
 	// Ref: https://opensource.com/article/19/8/linux-permissions-101
@@ -306,7 +423,7 @@ func InitJavaHome() {
 	global.JavaHome = javaHome
 
 	// Check if JAVA_HOME is a valid directory by looking for the release file.
-	releasePath := javaHome + string(os.PathSeparator) + "release"
+	releasePath := util.GetPlatform().JoinPath(javaHome, "release")
 	handle, err := os.Open(releasePath)
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "InitJavaHome: Cannot find the specified path: %s. Exiting.\n", releasePath)
@@ -333,7 +450,9 @@ func JavaVersion() string { return global.JavaVersion }
 
 // Normalize a file path. Slashes are converted to the current platform's path separator if necessary.
 func cleanupPath(path string) string {
-	path = filepath.FromSlash(path)
+	if util.GetPlatform().PathSeparator != '/' {
+		path = strings.ReplaceAll(path, "/", util.GetPlatform().Separator())
+	}
 	return path
 }
 
@@ -375,27 +494,24 @@ func InitStringPool() {
 	StringPoolLock.Lock()
 
 	// create the string pool
-	StringPoolTable = make(map[string]uint32)
-	StringPoolList = nil
+	for i := range StringPoolShards {
+		StringPoolShards[i].Lock.Lock()
+		StringPoolShards[i].Table = make(map[string]uint32)
+		StringPoolShards[i].Lock.Unlock()
+	}
 
 	// Changed on 9-Apr-2024: 0 = nil, 1 = String, 2 = Object
-	// Preload two values. java/lang/Object is always 0
-	// and java/lang/String is always 1.
-
-	// Add empty string (for when an index field has not been use, and so = 0
-	StringPoolTable[""] = 0
-	StringPoolList = append(StringPoolList, types.EmptyString)
-
-	// Add "java/lang/String"
-	StringPoolTable[types.StringClassName] = types.StringPoolStringIndex
-	StringPoolList = append(StringPoolList, types.StringClassName)
-
-	// Add "java/lang/Object"
-	StringPoolTable[types.ObjectClassName] = types.ObjectPoolStringIndex
-	StringPoolList = append(StringPoolList, types.ObjectClassName)
-
-	// Set up next available index
-	StringPoolNext = uint32(3)
+	// Preload three values: the empty string (for when an index field has not
+	// been used, and so = 0) is always 0, java/lang/String is always 1, and
+	// java/lang/Object is always 2. The next available index is 3.
+	list := []string{types.EmptyString, types.StringClassName, types.ObjectClassName}
+	for index, str := range list {
+		shard := StringPoolShardOf(str)
+		shard.Lock.Lock()
+		shard.Table[str] = uint32(index)
+		shard.Lock.Unlock()
+	}
+	StringPoolList.Store(&list)
 
 	StringPoolLock.Unlock()
 }
@@ -405,6 +521,27 @@ func GetCharsetName() string {
 	return global.FileEncoding
 }
 
+// the line separator that println(), BufferedWriter.newLine(), and %n write
+var lineSeparator = platformLineSeparator()
+
+func platformLineSeparator() string {
+	if runtime.GOOS == "windows" {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// GetLineSeparator returns the line separator, as System.lineSeparator() does. Like the
+// JDK, it is fixed at startup: setting the line.separator property later doesn't change it.
+func GetLineSeparator() string {
+	return lineSeparator
+}
+
+// SetLineSeparator sets the line separator, as -Dline.separator does
+func SetLineSeparator(separator string) {
+	lineSeparator = separator
+}
+
 // Case-insensitive sort.
 // Golang should have provided this!
 func SortCaseInsensitive(ptrSlice *[]string) {
@@ -443,7 +580,7 @@ func getOSVersion() string {
 
 // Reads the JDK release file and returns the major version number and the full version string.
 func GetJDKmajorVersion() (int, string) {
-	releaseFilePath := global.JavaHome + string(os.PathSeparator) + "release"
+	releaseFilePath := util.GetPlatform().JoinPath(global.JavaHome, "release")
 	file, err := os.Open(releaseFilePath)
 	if err != nil {
 		if TraceVerbose {
@@ -320,3 +320,19 @@ func TestGetJDKversionScannerError(t *testing.T) {
 		t.Errorf("Expected empty JAVA_VERSION for scanner error, got '%s'", version)
 	}
 }
+
+func TestSetExitHandler(t *testing.T) {
+	first := func(int) {}
+	if previous := SetExitHandler(first); previous != nil {
+		t.Errorf("Expected no exit handler at first")
+	}
+	if GetExitHandler() == nil {
+		t.Errorf("Expected the exit handler to be installed")
+	}
+	if previous := SetExitHandler(nil); previous == nil {
+		t.Errorf("Expected the previous exit handler to be returned")
+	}
+	if GetExitHandler() != nil {
+		t.Errorf("Expected a nil handler to restore the normal exit")
+	}
+}
@@ -8,7 +8,9 @@ package globals
 
 import (
 	"fmt"
+	"jacobin/src/charset"
 	"jacobin/src/types"
+	"jacobin/src/util"
 	"os"
 	"os/user"
 	"runtime"
@@ -46,7 +48,7 @@ func getOsProperty(arg string) string {
 	case "file.encoding":
 		value = global.FileEncoding
 	case "file.separator":
-		value = string(os.PathSeparator)
+		value = util.GetPlatform().Separator()
 	case "java.class.path":
 		value = global.ClasspathRaw
 	case "java.compiler": // the name of the JIT compiler (we don't have a JIT)
@@ -85,13 +87,9 @@ func getOsProperty(arg string) string {
 		_, ver := GetJDKmajorVersion() // "" if not found
 		value = ver
 	case "line.separator":
-		if operSys == "windows" {
-			value = "\\r\\n"
-		} else {
-			value = "\\n"
-		}
+		value = lineSeparator
 	case "native.encoding", "stdout.encoding", "stderr.encoding":
-		value = GetCharsetName()
+		value = charset.Native().Name
 	case "os.arch":
 		value = runtime.GOARCH
 	case "os.name":
@@ -99,7 +97,7 @@ func getOsProperty(arg string) string {
 	case "os.version":
 		value = getOSVersion()
 	case "path.separator":
-		value = string(os.PathSeparator)
+		value = util.GetPlatform().ListSeparator()
 	case "sun.jnu.encoding":
 		value = "UTF-8" // this is the default encoding for file names in Java
 	case "user.dir": // present working directory
@@ -134,7 +134,7 @@ func TestHexIDIVException(t *testing.T) {
 		t.Errorf("Should have received error message but got none\n")
 	}
 
-	msgExpected := "IDIV or LDIV: division by zero"
+	msgExpected := "/ by zero"
 	if !strings.Contains(string(msgStderr), msgExpected) {
 		t.Errorf("Error expected error message to contain \"%s\", got: \"%s\"\n",
 			msgExpected, string(msgStderr))
@@ -0,0 +1,137 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package jvm
+
+import (
+	"fmt"
+	"io"
+	"jacobin/src/opcodes"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// The bytecode statistics, enabled by -XX:+CountBytecodes, count the instructions
+// executed by the interpreter (and the JIT's compiled code) for each opcode, and the
+// total time spent executing them, and print them by category when the program ends.
+// They show which instructions are worth optimizing first. Note that the time of an
+// instruction that invokes a method includes the time of the method, if it's a
+// gfunction or runs before the instruction returns.
+
+// the counts and times, indexed by opcode
+var bytecodeCounts [256]atomic.Int64
+var bytecodeNanos [256]atomic.Int64
+
+// records the execution of an instruction that began at start. Called by interpret()
+// when the statistics are enabled.
+func recordBytecode(opcode byte, start time.Time) {
+	bytecodeCounts[opcode].Add(1)
+	bytecodeNanos[opcode].Add(int64(time.Since(start)))
+}
+
+// the categories of the opcodes, as in JVMS chapter 7, by the first opcode in each
+var bytecodeCategories = []struct {
+	first byte
+	name  string
+}{
+	{opcodes.NOP, "constants"},
+	{opcodes.ILOAD, "loads"},
+	{opcodes.ISTORE, "stores"},
+	{opcodes.POP, "stack"},
+	{opcodes.IADD, "math"},
+	{opcodes.I2L, "conversions"},
+	{opcodes.LCMP, "comparisons"},
+	{opcodes.GOTO, "control"},
+	{opcodes.GETSTATIC, "references"},
+	{opcodes.WIDE, "extended"},
+	{0xCA, "reserved"}, // BREAKPOINT, IMPDEP1, IMPDEP2, and invalid opcodes
+}
+
+// returns the category of an opcode
+func bytecodeCategory(opcode byte) string {
+	i := sort.Search(len(bytecodeCategories), func(i int) bool {
+		return bytecodeCategories[i].first > opcode
+	})
+	return bytecodeCategories[i-1].name
+}
+
+// BytecodeStat is the count and total time of the instructions executed for one
+// opcode, or for a category of them
+type BytecodeStat struct {
+	Name  string // the name of the opcode or category
+	Count int64
+	Time  time.Duration
+}
+
+// BytecodeStatsReport returns the statistics of the opcodes executed so far, ordered
+// by count, and the totals for their categories, in the order of the categories.
+func BytecodeStatsReport() (byOpcode []BytecodeStat, byCategory []BytecodeStat) {
+	categories := make(map[string]*BytecodeStat)
+	for _, category := range bytecodeCategories {
+		byCategory = append(byCategory, BytecodeStat{Name: category.name})
+	}
+	for i := range byCategory {
+		categories[byCategory[i].Name] = &byCategory[i]
+	}
+
+	for opcode := range bytecodeCounts {
+		count := bytecodeCounts[opcode].Load()
+		if count == 0 {
+			continue
+		}
+		name := fmt.Sprintf("0x%02X", opcode)
+		if opcode < len(opcodes.BytecodeNames) {
+			name = opcodes.BytecodeNames[opcode]
+		}
+		stat := BytecodeStat{Name: name, Count: count, Time: time.Duration(bytecodeNanos[opcode].Load())}
+		byOpcode = append(byOpcode, stat)
+
+		category := categories[bytecodeCategory(byte(opcode))]
+		category.Count += stat.Count
+		category.Time += stat.Time
+	}
+
+	sort.SliceStable(byOpcode, func(i, j int) bool { return byOpcode[i].Count > byOpcode[j].Count })
+	return byOpcode, byCategory
+}
+
+// PrintBytecodeStats writes the bytecode statistics to out: the categories, then
+// each opcode executed, followed by the totals. It's used by -XX:+CountBytecodes.
+func PrintBytecodeStats(out io.Writer) {
+	byOpcode, byCategory := BytecodeStatsReport()
+	var count int64
+	var total time.Duration
+	for _, stat := range byCategory {
+		count += stat.Count
+		total += stat.Time
+	}
+
+	printStat := func(stat BytecodeStat) {
+		percent, average := 0.0, time.Duration(0)
+		if count > 0 {
+			percent = float64(stat.Count) * 100 / float64(count)
+		}
+		if stat.Count > 0 {
+			average = stat.Time / time.Duration(stat.Count)
+		}
+		_, _ = fmt.Fprintf(out, "%14d %6.2f%% %14v %10v  %s\n", stat.Count, percent, stat.Time, average, stat.Name)
+	}
+
+	_, _ = fmt.Fprintln(out, "---- start of bytecode statistics ----")
+	_, _ = fmt.Fprintf(out, "%14s %7s %14s %10s  %s\n", "count", "%", "time", "average", "category")
+	for _, stat := range byCategory {
+		if stat.Count > 0 {
+			printStat(stat)
+		}
+	}
+	_, _ = fmt.Fprintf(out, "\n%14s %7s %14s %10s  %s\n", "count", "%", "time", "average", "opcode")
+	for _, stat := range byOpcode {
+		printStat(stat)
+	}
+	_, _ = fmt.Fprintf(out, "---- end of bytecode statistics: %d opcodes, %d instructions, %v ----\n",
+		len(byOpcode), count, total)
+}
@@ -0,0 +1,89 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package jvm
+
+import (
+	"bytes"
+	"jacobin/src/globals"
+	"jacobin/src/opcodes"
+	"jacobin/src/trace"
+	"strings"
+	"testing"
+)
+
+// clears the bytecode statistics
+func resetBytecodeStats() {
+	for opcode := range bytecodeCounts {
+		bytecodeCounts[opcode].Store(0)
+		bytecodeNanos[opcode].Store(0)
+	}
+}
+
+func TestBytecodeCategory(t *testing.T) {
+	tests := map[byte]string{
+		opcodes.NOP: "constants", opcodes.SIPUSH: "constants", opcodes.LDC2_W: "constants",
+		opcodes.ILOAD: "loads", opcodes.ALOAD_3: "loads", opcodes.SALOAD: "loads",
+		opcodes.ISTORE: "stores", opcodes.SASTORE: "stores",
+		opcodes.POP: "stack", opcodes.SWAP: "stack",
+		opcodes.IADD: "math", opcodes.IINC: "math",
+		opcodes.I2L: "conversions", opcodes.I2S: "conversions",
+		opcodes.LCMP: "comparisons", opcodes.IF_ACMPNE: "comparisons",
+		opcodes.GOTO: "control", opcodes.RETURN: "control",
+		opcodes.GETSTATIC: "references", opcodes.MONITOREXIT: "references",
+		opcodes.WIDE: "extended", opcodes.JSR_W: "extended",
+		0xCA: "reserved", 0xFF: "reserved",
+	}
+	for opcode, expected := range tests {
+		if category := bytecodeCategory(opcode); category != expected {
+			t.Errorf("Expected %s to be in category %s, got: %s", opcodes.BytecodeNames[opcode], expected, category)
+		}
+	}
+}
+
+func TestCountBytecodes(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	resetBytecodeStats()
+	defer resetBytecodeStats()
+	globals.GetGlobalRef().CountBytecodes = true
+
+	runJitTestCode(jitSumLoop, 10, false) // 10 iterations of the loop
+	byOpcode, byCategory := BytecodeStatsReport()
+
+	counts := make(map[string]int64)
+	for _, stat := range byOpcode {
+		counts[stat.Name] = stat.Count
+	}
+	if counts["IINC"] != 10 || counts["GOTO"] != 10 || counts["IF_ICMPGE"] != 11 || counts["ILOAD"] != 42 {
+		t.Errorf("Expected 10 IINCs and GOTOs, 11 IF_ICMPGEs, and 42 ILOADs, got: %v", counts)
+	}
+	if byOpcode[0].Name != "ILOAD" {
+		t.Errorf("Expected ILOAD to be the most frequent opcode, got: %s", byOpcode[0].Name)
+	}
+	for _, category := range byCategory {
+		if category.Name == "control" && category.Count != 10 {
+			t.Errorf("Expected 10 instructions in the control category, got: %d", category.Count)
+		}
+	}
+
+	var out bytes.Buffer
+	PrintBytecodeStats(&out)
+	if !strings.Contains(out.String(), "9 opcodes, 97 instructions") {
+		t.Errorf("Expected the totals in the report, got: %s", out.String())
+	}
+}
+
+func TestCountBytecodesOffByDefault(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	resetBytecodeStats()
+
+	runJitTestCode(jitSumLoop, 10, false)
+	if byOpcode, _ := BytecodeStatsReport(); len(byOpcode) != 0 {
+		t.Errorf("Did not expect bytecodes to be counted without -XX:+CountBytecodes, got: %v", byOpcode)
+	}
+}
@@ -13,7 +13,9 @@ import (
 	"jacobin/src/globals"
 	"jacobin/src/trace"
 	"os"
+	"slices"
 	"strings"
+	"unicode"
 )
 
 // HandleCli handles all args from the command line, including those from environment
@@ -28,25 +30,27 @@ func HandleCli(osArgs []string, Global *globals.Globals) (err error) {
 	// JAVA_HOME and JACOBIN_HOME were obtained in the init of globals.go. Here we just log them.
 	showJavaHomeArgs(Global)
 
-	// add command-line args to those extracted from the environment (if any)
-	cliArgs := javaEnvOptions + " "
-	for _, v := range osArgs[1:] {
-		cliArgs += v + " "
+	// break the environment variables into their options. Note that an option with spaces
+	// but within quotes is treated as a single option
+	envOptions, err := getEnvOptions(Global.Options)
+	if err != nil {
+		trace.Error("HandleCli: " + err.Error())
+		return err
 	}
-	Global.CommandLine = strings.TrimSpace(cliArgs)
+
+	// add command-line args to those extracted from the environment (if any)
+	args := mergeEnvOptions(envOptions, osArgs[1:], Global.Options)
+	Global.CommandLine = strings.Join(args, " ")
 	if globals.TraceInit {
 		trace.Trace("HandleCli: Commandline: " + Global.CommandLine)
 	}
-
-	// pull out all the arguments into an array of strings. Note that an arg with spaces but
-	// within quotes is treated as a single arg
-	args := strings.Fields(javaEnvOptions)
-	for _, v := range osArgs[1:] {
-		args = append(args, v)
-	}
 	Global.Args = args
 	showCopyright(Global)
 
+	// in strict mode, an unrecognized option is an error, as in the JDK. Otherwise, it's
+	// only a warning. -strictJDK can appear anywhere on the command line, so check for it first.
+	strict := Global.StrictJDK || slices.Contains(args, "-strictJDK")
+
 	for i := 0; i < len(args); i++ {
 		var option, arg string
 		// if it's a JVM option (so, it begins with a hyphen)
@@ -84,15 +88,26 @@ func HandleCli(osArgs []string, Global *globals.Globals) (err error) {
 			// if the option is a JAR file, then all remaining args have been captureed
 			// in the optAction function, so we can exit here
 			if option == "-jar" {
-				return nil
+				break
 			}
 			i = newPos // advance the index by the number of args consumed by this option
+		} else if strings.HasPrefix(args[i], "-") {
+			if strict {
+				errMsg := fmt.Sprintf("Unrecognized option: %s", args[i])
+				trace.Error(errMsg)
+				return errors.New(errMsg)
+			}
+			trace.Warning(fmt.Sprintf("HandleCli: Parameter %s is not a recognized option. Ignoring it.", args[i]))
 		} else {
 			errMsg := fmt.Sprintf("HandleCli: Parameter %s is not a recognized option. Exiting.\n", args[i])
 			trace.Error(errMsg)
 			return err
 		}
 	}
+
+	if Global.PrintFlags {
+		printFlagsFinal(os.Stdout, Global)
+	}
 	return nil
 }
 
@@ -117,6 +132,13 @@ func getOptionRootAndArgs(option string) (string, string, error) {
 		return "", "", errors.New("empty option error")
 	}
 
+	// options such as -Xss512k, whose value follows the option name without a colon
+	for _, root := range xSizeOptions {
+		if strings.HasPrefix(option, root) && len(option) > len(root) {
+			return root, option[len(root):], nil
+		}
+	}
+
 	// if the option has an embedded arg value, it'll come after the first colon (:).
 	argMarker := strings.Index(option, ":")
 
@@ -129,13 +151,21 @@ func getOptionRootAndArgs(option string) (string, string, error) {
 
 }
 
-// you can set JVM options using the three environment variables that are
-// inspected in this function. Note: order is important because later options
-// can override earlier ones. These are checked before any of the command-line
-// options are processed.
+// the -X options whose value, a memory size, is appended directly to the option name
+var xSizeOptions = attachedValueOptions()
+
+// you can set JVM options using three environment variables. They're listed here
+// in the order in which their options are processed, which is important because
+// later options can override earlier ones:
+//
+//	JAVA_TOOL_OPTIONS - processed before the command-line options
+//	JDK_JAVA_OPTIONS  - prepended to the command-line options (by the launcher, in the JDK)
+//	_JAVA_OPTIONS     - processed after the command-line options, so they override them
+var javaEnvKeys = [3]string{"JAVA_TOOL_OPTIONS", "JDK_JAVA_OPTIONS", "_JAVA_OPTIONS"}
+
+// returns the contents of the three environment variables, in the order of javaEnvKeys.
 func getEnvArgs() string {
 	envArgs := ""
-	javaEnvKeys := [3]string{"JAVA_TOOL_OPTIONS", "_JAVA_OPTIONS", "JDK_JAVA_OPTIONS"}
 
 	for i := 0; i < 3; i++ { // if a string is found copy it and a trailing space
 		envString := os.Getenv(javaEnvKeys[i])
@@ -149,6 +179,115 @@ func getEnvArgs() string {
 	return strings.TrimSpace(envArgs)
 }
 
+// getEnvOptions returns the options in each of the environment variables, indexed
+// as javaEnvKeys. As in the JDK, the options are separated by whitespace, except
+// within a pair of single or double quotes, which are removed. JDK_JAVA_OPTIONS
+// may not hold -jar or the main class. A note is written to stderr for each
+// variable that's used, as the JDK does.
+func getEnvOptions(options map[string]globals.Option) ([3][]string, error) {
+	var envOptions [3][]string
+	for i, key := range javaEnvKeys {
+		value := os.Getenv(key)
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+
+		if key == "JDK_JAVA_OPTIONS" {
+			_, _ = fmt.Fprintf(os.Stderr, "NOTE: Picked up %s: %s\n", key, value)
+		} else {
+			_, _ = fmt.Fprintf(os.Stderr, "Picked up %s: %s\n", key, value)
+		}
+
+		tokens, err := tokenizeEnvOptions(key, value)
+		if err != nil {
+			return envOptions, err
+		}
+
+		if key == "JDK_JAVA_OPTIONS" {
+			if end := endOfOptions(tokens, options); end < len(tokens) {
+				if tokens[end] == "-jar" {
+					return envOptions, fmt.Errorf("Option -jar is not allowed in environment variable %s", key)
+				}
+				return envOptions, fmt.Errorf("Cannot specify main class in environment variable %s", key)
+			}
+		}
+		envOptions[i] = tokens
+	}
+	return envOptions, nil
+}
+
+// breaks the value of an environment variable into options, which are separated by
+// whitespace. Whitespace between single or double quotes is part of the option, and
+// the quotes are removed: JDK_JAVA_OPTIONS="-cp 'my dir'" holds -cp and my dir.
+func tokenizeEnvOptions(key string, value string) ([]string, error) {
+	var tokens []string
+	var token strings.Builder
+	inToken := false
+	var quote rune // the quote that opened the present quoted string, if any
+
+	for _, c := range value {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				token.WriteRune(c)
+			}
+		case c == '"' || c == '\'':
+			quote = c
+			inToken = true // so that an empty quoted string is an option
+		case unicode.IsSpace(c):
+			if inToken {
+				tokens = append(tokens, token.String())
+				token.Reset()
+				inToken = false
+			}
+		default:
+			token.WriteRune(c)
+			inToken = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("Unmatched quote in environment variable %s", key)
+	}
+	if inToken {
+		tokens = append(tokens, token.String())
+	}
+	return tokens, nil
+}
+
+// returns the index of the first arg that's not a JVM option: the main class, or -jar
+// (which is followed by the JAR file and the app's args), or len(args) if there's none.
+// The values of options that take the next arg as their value, such as -cp, are skipped.
+func endOfOptions(args []string, options map[string]globals.Option) int {
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-jar" || !strings.HasPrefix(args[i], "-") {
+			return i
+		}
+		if opt, ok := options[args[i]]; ok && opt.ArgStyle == 4 {
+			i++
+		}
+	}
+	return len(args)
+}
+
+// combines the options from the environment variables with the command-line args, in
+// the order in which they're processed (see javaEnvKeys). The options of _JAVA_OPTIONS
+// are placed after the command-line options, but before the main class or -jar, so
+// that they're not taken for the app's args.
+func mergeEnvOptions(envOptions [3][]string, cliArgs []string, options map[string]globals.Option) []string {
+	end := endOfOptions(cliArgs, options)
+
+	var args []string
+	args = append(args, envOptions[0]...) // JAVA_TOOL_OPTIONS
+	args = append(args, envOptions[1]...) // JDK_JAVA_OPTIONS
+	args = append(args, cliArgs[:end]...)
+	args = append(args, envOptions[2]...) // _JAVA_OPTIONS
+	args = append(args, cliArgs[end:]...)
+	return args
+}
+
 // log the two environmental variables from which we'll load base classes.
 func showJavaHomeArgs(Global *globals.Globals) {
 	if globals.TraceVerbose {
@@ -180,14 +319,41 @@ are passed as the arguments to main class.
 
 where options include:
 	-client         to select the "client" VM
+	-D<name>=<value>
+	                set a system property
 	-? -h -help     print this help message to the error stream
 	--help          print this help message to the output stream
 	-version        print product version to the error stream and exit
 	--version       print product version to the output stream and exit
 	-showversion    print product version to the error stream and continue
 	--show-version  print product version to the output stream and continue
+	--enable-preview
+	                allow classes to depend on preview features of this release
+	-ea[:<packagename>...|:<classname>]
+	-enableassertions[:<packagename>...|:<classname>]
+	                enable assertions with specified granularity
+	-da[:<packagename>...|:<classname>]
+	-disableassertions[:<packagename>...|:<classname>]
+	                disable assertions with specified granularity
+	-esa | -enablesystemassertions
+	                enable system assertions
+	-dsa | -disablesystemassertions
+	                disable system assertions
 
 Jacobin-specific options:
+    --api-coverage        as --list-gfunctions, and also list the public and protected methods of each class
+                          in the JDK that have no gfunction, with the totals of the coverage
+    --dry-run, --validate-only
+                          load and check the main class and the classes it uses, transitively, without running
+                          the program: report every class that fails to load or verify and every call to a
+                          method Jacobin does not support
+    --list-gfunctions     list the Go functions (gfunctions) that implement JDK methods, by class, marking
+                          the traps: methods that Jacobin recognizes but does not support
+    -record <file>        log the program's nondeterministic inputs to <file>: the clocks, random seeds, reads,
+                          and the order in which threads pass monitor operations
+    -replay <file>        run the program with the inputs logged by -record, to reproduce a failure
+    -selftest <dir>       run the conformance suite in <dir>: each Name.class there is run and its output
+                          compared with the golden files Name.out (stdout), Name.err (stderr), and Name.exit
     -strictJDK            make user messages conform closely to the JDK's format
     -trace=<selections>   display selected tracing to the console
                           where the <selections> are one or more of the following separated by commas (,):
@@ -196,6 +362,56 @@ Jacobin-specific options:
                           * inst - bytecode interpreter trace
                           * class - class & method support for the interpreter
                           * verbose - inst, class, and more details of the interpreter
+    -Xint                 execute bytecode only in the interpreter, never compiling hot methods
+    -Xlint:deprecation    warn at startup of calls in the main class to deprecated methods Jacobin does not support
+    -Xmx<size>            set the maximum size of the Java heap, e.g., -Xmx512m (default: no limit)
+    -Xss<size>            set the maximum stack size of each thread, e.g., -Xss2m (default: 1m)
+    -XX:ActiveProcessorCount=<n>
+                          use <n> processors: set GOMAXPROCS and the value of Runtime.availableProcessors()
+                          (default: all the processors the OS reports)
+    -XX:BackEdgeThreshold=<n>
+                          compile a method once it has taken <n> backward branches, switching the
+                          running loop to the compiled code (default: 100000)
+    -XX:+CompactConstantPools
+                          share identical constant pool strings between loaded classes, to save memory
+    -XX:CompileThreshold=<n>
+                          compile a method to Go closures once it has been invoked <n> times (default: 10000)
+    -XX:+CountBytecodes   count the instructions executed and the time spent on each opcode, and print
+                          them by category at exit
+    -XX:-EnforceAccess    disable the access checks on classes, fields, and methods (for compatibility)
+    -XX:+GreenThreads     run virtual threads on a pool of goroutines sized to GOMAXPROCS
+    -XX:+HeapDumpOnOutOfMemoryError
+                          write a Go heap profile to jacobin_pid<pid>.pprof on the first OutOfMemoryError
+    -XX:+PinInterpreterThreads
+                          run each Java thread on its own OS thread, so the interpreter's hot threads are
+                          not moved between OS threads and CPU affinity set by the OS applies to each
+    -XX:PreferBytecode=<patterns>
+                          run the JDK's own bytecode instead of Jacobin's Go implementation of the methods
+                          matched by the <patterns>, separated by commas: a package (java/util/*), a class
+                          (java/util/ArrayList), a method (java/lang/Math.abs or java/lang/Math.abs(I)I),
+                          or any of these prefixed with - to exclude it. The last pattern to match decides.
+    -XX:+PrintFlagsFinal  print the final values of all the -XX flags
+    -XX:+PrintGfunctionUsageAtExit
+                          print the number of calls to each gfunction, and the traps hit, at exit
+    -XX:+PrintMethodAreaAtExit
+                          print the loaded classes, their loaders, method counts, and initialization states at exit
+    -XX:+PrintMTableAtExit
+                          print the methods the program resolved at exit: whether each runs as a gfunction (G)
+                          or bytecode (J), the class that defines it, and its parameter count, followed by
+                          the gfunctions that are run in place of the bytecode of a loaded class
+    -XX:SafepointTimeoutDelay=<ms>
+                          how long to wait for the threads to stop for a thread or heap dump before
+                          giving up and listing the threads that did not stop (default: 10000)
+    -XX:TrapPolicy=<policy>
+                          what happens when the program calls a JDK method Jacobin does not support:
+                          throw an UnsupportedOperationException (throw, the default), warn once per method
+                          and throw (warn), or end the program (abort)
+    -XX:+UseAllocationNursery
+                          reuse the small objects that a method creates and never lets out of its local variables
+    -XX:-UseContainerSupport
+                          ignore the memory and CPU limits of the container (cgroup) Jacobin runs in, which
+                          otherwise set the default -Xmx to 1/4 of the memory limit and cap the processors used
+    -XX:<flag>=<value>    set a -XX flag that takes a value, e.g., -XX:MaxHeapSize=512m
     -JJ:galt              Do not use this unless you are a Jacobin developer! `
 
 	_, _ = fmt.Fprintln(outStream, userMessage)
@@ -10,6 +10,7 @@ import (
 	"io"
 	"jacobin/src/globals"
 	"os"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -27,15 +28,15 @@ func TestGetJVMenvVariablesWhenAbsent(t *testing.T) {
 	}
 }
 
-// set two of the JVM environment variables and make sure
-// they are fetched correctly and a space is inserted between them
+// set two of the JVM environment variables and make sure they are fetched
+// correctly, in the order they're processed, and a space is inserted between them
 func TestGetJVMenvVariablesWhenTwoArePresent(t *testing.T) {
 	_ = os.Unsetenv("JAVA_TOOL_OPTIONS")
 	_ = os.Setenv("_JAVA_OPTIONS", "Hello,")
 	_ = os.Setenv("JDK_JAVA_OPTIONS", "Jacobin!")
 
 	javaEnvVars := getEnvArgs()
-	if javaEnvVars != "Hello, Jacobin!" {
+	if javaEnvVars != "Jacobin! Hello," {
 		t.Error("getting two set Java environment options failed: " + javaEnvVars)
 	}
 
@@ -271,7 +272,7 @@ func TestSpecifyValidButUnsupportedOption(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stderr = w
 
-	args := []string{"jacobin", "--dry-run", " class"}
+	args := []string{"jacobin", "-Xshare:auto", " class"}
 	_ = HandleCli(args, &global)
 
 	// restore stderr to what it was before
@@ -466,3 +467,142 @@ func TestEmptyOptionForEmbeddedArg(t *testing.T) {
 		t.Error("Empty option should fail test for embedded args, but did not.")
 	}
 }
+
+func TestSizeOptionWithoutColon(t *testing.T) {
+	option, arg, err := getOptionRootAndArgs("-Xss512k")
+	if err != nil || option != "-Xss" || arg != "512k" {
+		t.Errorf("Expected -Xss and 512k, got: %s, %s, %v", option, arg, err)
+	}
+
+	// an option name that merely begins with a size option is left alone
+	option, arg, _ = getOptionRootAndArgs("-Xss")
+	if option != "-Xss" || arg != "" {
+		t.Errorf("Expected -Xss with no argument, got: %s, %s", option, arg)
+	}
+}
+
+// an unrecognized option is only a warning, unless -strictJDK is specified
+func TestUnrecognizedOption(t *testing.T) {
+	global := globals.InitGlobals("test")
+	LoadOptionsTable(globals.Globals{Options: global.Options}) // loads global's table
+	var errOut strings.Builder
+	globals.SetTraceWriter(&errOut)
+
+	args := []string{"jacobin", "-nosuchoption", "-client"}
+	if err := HandleCli(args, &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(errOut.String(), "WARNING: HandleCli: Parameter -nosuchoption is not a recognized option") {
+		t.Errorf("Expected a warning about the unrecognized option, got: %s", errOut.String())
+	}
+	if global.VmModel != "client" {
+		t.Error("Expected the options after the unrecognized option to be processed")
+	}
+
+	global = globals.InitGlobals("test")
+	LoadOptionsTable(globals.Globals{Options: global.Options}) // loads global's table
+	errOut.Reset()
+	globals.SetTraceWriter(&errOut)
+
+	args = []string{"jacobin", "-nosuchoption", "-strictJDK"}
+	err := HandleCli(args, &global)
+	if err == nil || err.Error() != "Unrecognized option: -nosuchoption" {
+		t.Errorf("Expected an error for the unrecognized option in strict mode, got: %v", err)
+	}
+}
+
+func TestPrintFlagsFinalOption(t *testing.T) {
+	global := globals.InitGlobals("test")
+	LoadOptionsTable(globals.Globals{Options: global.Options}) // loads global's table
+
+	normalStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	args := []string{"jacobin", "-XX:+PrintFlagsFinal", "-XX:-EnforceAccess"}
+	err := HandleCli(args, &global)
+
+	_ = w.Close()
+	os.Stdout = normalStdout
+	out, _ := io.ReadAll(r)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(string(out), "bool EnforceAccess                            = false") {
+		t.Errorf("Expected the final value of EnforceAccess to be printed, got: %s", string(out))
+	}
+}
+
+func TestTokenizeEnvOptions(t *testing.T) {
+	tokens, err := tokenizeEnvOptions("JDK_JAVA_OPTIONS", `  -cp "my classes:lib" -Dname='a "b"'  -client '' `)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{"-cp", "my classes:lib", `-Dname=a "b"`, "-client", ""}
+	if !slices.Equal(tokens, expected) {
+		t.Errorf("Expected %q, got %q", expected, tokens)
+	}
+
+	_, err = tokenizeEnvOptions("JDK_JAVA_OPTIONS", `-cp "unterminated`)
+	if err == nil || !strings.Contains(err.Error(), "Unmatched quote in environment variable JDK_JAVA_OPTIONS") {
+		t.Errorf("Expected an error for the unmatched quote, got: %v", err)
+	}
+}
+
+// JDK_JAVA_OPTIONS may hold only options: not -jar or the main class
+func TestJDKJavaOptionsRejectsJarAndMainClass(t *testing.T) {
+	global := globals.InitGlobals("test")
+	LoadOptionsTable(globals.Globals{Options: global.Options}) // loads global's table
+	_ = os.Unsetenv("JAVA_TOOL_OPTIONS")
+	_ = os.Unsetenv("_JAVA_OPTIONS")
+	defer os.Unsetenv("JDK_JAVA_OPTIONS")
+
+	normalStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stderr = w
+	defer func() { _ = w.Close(); os.Stderr = normalStderr }()
+
+	_ = os.Setenv("JDK_JAVA_OPTIONS", "-client -jar app.jar")
+	_, err := getEnvOptions(global.Options)
+	if err == nil || err.Error() != "Option -jar is not allowed in environment variable JDK_JAVA_OPTIONS" {
+		t.Errorf("Expected an error for -jar, got: %v", err)
+	}
+
+	_ = os.Setenv("JDK_JAVA_OPTIONS", "-cp classes Main.class")
+	_, err = getEnvOptions(global.Options)
+	if err == nil || err.Error() != "Cannot specify main class in environment variable JDK_JAVA_OPTIONS" {
+		t.Errorf("Expected an error for the main class, got: %v", err)
+	}
+
+	_ = os.Setenv("JDK_JAVA_OPTIONS", "-cp classes -client")
+	envOptions, err := getEnvOptions(global.Options)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !slices.Equal(envOptions[1], []string{"-cp", "classes", "-client"}) {
+		t.Errorf("Unexpected options from JDK_JAVA_OPTIONS: %q", envOptions[1])
+	}
+}
+
+// the options from the environment are ordered so that JAVA_TOOL_OPTIONS can be overridden
+// by the command line, which can be overridden by _JAVA_OPTIONS
+func TestMergeEnvOptions(t *testing.T) {
+	global := globals.InitGlobals("test")
+	LoadOptionsTable(globals.Globals{Options: global.Options}) // loads global's table
+
+	envOptions := [3][]string{{"-Xss1m"}, {"-client"}, {"-Xss4m"}}
+	cliArgs := []string{"-cp", "lib", "-Xss2m", "Main.class", "-Xss8m"}
+	args := mergeEnvOptions(envOptions, cliArgs, global.Options)
+	expected := []string{"-Xss1m", "-client", "-cp", "lib", "-Xss2m", "-Xss4m", "Main.class", "-Xss8m"}
+	if !slices.Equal(args, expected) {
+		t.Errorf("Expected %q, got %q", expected, args)
+	}
+
+	cliArgs = []string{"-jar", "app.jar", "arg"}
+	args = mergeEnvOptions(envOptions, cliArgs, global.Options)
+	expected = []string{"-Xss1m", "-client", "-Xss4m", "-jar", "app.jar", "arg"}
+	if !slices.Equal(args, expected) {
+		t.Errorf("Expected %q, got %q", expected, args)
+	}
+}
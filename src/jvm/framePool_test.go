@@ -0,0 +1,184 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"container/list"
+	"errors"
+	"jacobin/src/classloader"
+	"jacobin/src/exceptions"
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/opcodes"
+	"jacobin/src/stringPool"
+	"jacobin/src/thread"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+	"os"
+	"strings"
+	"testing"
+)
+
+// adds a static method of pkg/Recursive whose constant pool entry 1 refers to the method itself
+func addRecursiveMethod(methName, methType string, code []byte) {
+	className := "pkg/Recursive"
+	CP := classloader.CPool{}
+	CP.CpIndex = make([]classloader.CpEntry, 6)
+	CP.CpIndex[1] = classloader.CpEntry{Type: classloader.MethodRef, Slot: 0}
+	CP.MethodRefs = []classloader.MethodRefEntry{{ClassIndex: 2, NameAndType: 3}}
+	CP.CpIndex[2] = classloader.CpEntry{Type: classloader.ClassRef, Slot: 0}
+	CP.ClassRefs = []uint32{stringPool.GetStringIndex(&className)}
+	CP.CpIndex[3] = classloader.CpEntry{Type: classloader.NameAndType, Slot: 0}
+	CP.NameAndTypes = []classloader.NameAndTypeEntry{{NameIndex: 4, DescIndex: 5}}
+	CP.CpIndex[4] = classloader.CpEntry{Type: classloader.UTF8, Slot: 0}
+	CP.CpIndex[5] = classloader.CpEntry{Type: classloader.UTF8, Slot: 1}
+	CP.Utf8Refs = []string{methName, methType}
+	classloader.ResolveCPmethRefs(&CP)
+
+	if classloader.MethAreaFetch(className) == nil {
+		addInitTestClass(className, types.ObjectClassName, false, nil)
+	}
+	classloader.MTable[className+"."+methName+methType] = classloader.MTentry{
+		MType: 'J',
+		Meth: classloader.JmEntry{
+			MaxStack:    4,
+			MaxLocals:   1,
+			AccessFlags: classloader.AccPublic | classloader.AccStatic,
+			Cp:          &CP,
+			Code:        code,
+		},
+	}
+}
+
+// sets up these methods of pkg/Recursive:
+//
+//	static int fib(int n) { return n < 2 ? n : fib(n-1) + fib(n-2); } // call-heavy
+//	static int sum(int n) { return n == 0 ? 0 : n + sum(n-1); }        // deeply recursive
+//
+// and returns a frame stack with a caller frame that uses the given frame pool
+func setUpRecursion(pool *frames.FramePool) *list.List {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+	addRecursiveMethod("fib", "(I)I", []byte{
+		opcodes.ILOAD_0, opcodes.ICONST_2,
+		opcodes.IF_ICMPGE, 0x00, 0x05, // if n >= 2 go to the recursive case
+		opcodes.ILOAD_0, opcodes.IRETURN,
+		opcodes.ILOAD_0, opcodes.ICONST_1, opcodes.ISUB,
+		opcodes.INVOKESTATIC, 0x00, 0x01, // fib(n-1)
+		opcodes.ILOAD_0, opcodes.ICONST_2, opcodes.ISUB,
+		opcodes.INVOKESTATIC, 0x00, 0x01, // fib(n-2)
+		opcodes.IADD, opcodes.IRETURN})
+	addRecursiveMethod("sum", "(I)I", []byte{
+		opcodes.ILOAD_0,
+		opcodes.IFNE, 0x00, 0x05, // if n != 0 go to the recursive case
+		opcodes.ICONST_0, opcodes.IRETURN,
+		opcodes.ILOAD_0,
+		opcodes.ILOAD_0, opcodes.ICONST_1, opcodes.ISUB,
+		opcodes.INVOKESTATIC, 0x00, 0x01, // sum(n-1)
+		opcodes.IADD, opcodes.IRETURN})
+
+	fs := frames.CreateFrameStack()
+	caller := frames.CreateFrame(2)
+	caller.Pool = pool
+	_ = frames.PushFrame(fs, caller)
+	return fs
+}
+
+func TestRecursionWithFramePool(t *testing.T) {
+	pool := frames.NewFramePool()
+	fs := setUpRecursion(pool)
+
+	ret, err := InvokeJavaMethod(fs, "pkg/Recursive", "fib", "(I)I", nil, []any{int64(15)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ret != int64(610) {
+		t.Errorf("Expected fib(15) = 610, got: %v", ret)
+	}
+
+	// the frames of the calls have been returned to the pool, which never held
+	// more frames than the depth of the recursion
+	if pool.Len() == 0 || pool.Len() > 15 {
+		t.Errorf("Expected between 1 and 15 frames in the pool, got: %d", pool.Len())
+	}
+
+	ret, err = InvokeJavaMethod(fs, "pkg/Recursive", "sum", "(I)I", nil, []any{int64(1000)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ret != int64(500500) {
+		t.Errorf("Expected sum(1000) = 500500, got: %v", ret)
+	}
+	if fs.Len() != 1 {
+		t.Errorf("Expected only the caller's frame to remain, got %d frames", fs.Len())
+	}
+}
+
+// compare:  go test ./jvm -run XXX -bench 'Fibonacci|DeepRecursion' -benchmem
+func benchmarkRecursion(b *testing.B, pool *frames.FramePool, methName string, n int64) {
+	fs := setUpRecursion(pool)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := InvokeJavaMethod(fs, "pkg/Recursive", methName, "(I)I", nil, []any{n}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFibonacciPooledFrames(b *testing.B) {
+	benchmarkRecursion(b, frames.NewFramePool(), "fib", 20)
+}
+
+func BenchmarkFibonacciUnpooledFrames(b *testing.B) {
+	benchmarkRecursion(b, nil, "fib", 20)
+}
+
+// deep recursion: each call's frame stays on the stack until the deepest call returns
+func BenchmarkDeepRecursionPooledFrames(b *testing.B) {
+	benchmarkRecursion(b, frames.NewFramePool(), "sum", 2000)
+}
+
+func BenchmarkDeepRecursionUnpooledFrames(b *testing.B) {
+	benchmarkRecursion(b, nil, "sum", 2000)
+}
+
+func TestRunawayRecursionThrowsStackOverflowError(t *testing.T) {
+	fs := setUpRecursion(nil)
+	glob := globals.GetGlobalRef()
+	glob.JacobinName = "testWithoutShutdown" // so that ThrowEx looks for a handler
+	glob.MaxFrameDepth = 50
+
+	// ThrowEx finds the frame stack through the thread table
+	th := thread.CreateThread()
+	th.Stack = fs
+	th.AddThreadToTable(glob)
+	fs.Front().Value.(*frames.Frame).Thread = th.ID
+
+	// suppress the stack trace that ThrowEx prints
+	normalStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stderr = w
+	_, err := InvokeJavaMethod(fs, "pkg/Recursive", "sum", "(I)I", nil, []any{int64(1000)})
+	_ = w.Close()
+	os.Stderr = normalStderr
+
+	var upcallErr *exceptions.UpcallError
+	if !errors.As(err, &upcallErr) || !strings.Contains(upcallErr.Cause, "StackOverflowError") {
+		t.Fatalf("Expected a StackOverflowError, got: %v", err)
+	}
+	if fs.Len() != 1 {
+		t.Errorf("Expected only the caller's frame to remain, got %d frames", fs.Len())
+	}
+
+	// within the limit, the same call succeeds
+	ret, err := InvokeJavaMethod(fs, "pkg/Recursive", "sum", "(I)I", nil, []any{int64(40)})
+	if err != nil || ret != int64(820) {
+		t.Errorf("Expected sum(40) = 820, got: %v (err: %v)", ret, err)
+	}
+}
@@ -219,3 +219,57 @@ func TestGfuncINVOKEVIRTUALwith1stringArgtemplate(t *testing.T) {
 		t.Errorf("gfunctionExec: Did not get expected msg, got: %s", outMsg)
 	}
 }
+
+// invoking a gfunction on a null object should throw a NullPointerException that
+// names the method, rather than calling the gfunction with a null receiver
+func TestGfuncINVOKEVIRTUALonNullObject(t *testing.T) {
+	objClassName := "java/io/PrintStream"
+	methName := "println"
+	methType := "(Ljava/lang/String;)V"
+
+	globals.InitGlobals("test")
+	trace.Init()
+
+	normalStderr := os.Stderr
+	rerr, werr, _ := os.Pipe()
+	os.Stderr = werr
+
+	classloader.MTable = make(map[string]classloader.MTentry)
+	gfunction.MTableLoadGFunctions(&classloader.MTable)
+
+	CP := classloader.CPool{}
+	CP.CpIndex = make([]classloader.CpEntry, 8)
+	CP.CpIndex[1] = classloader.CpEntry{Type: classloader.MethodRef, Slot: 0}
+	CP.CpIndex[2] = classloader.CpEntry{Type: classloader.ClassRef, Slot: 0}
+	CP.CpIndex[3] = classloader.CpEntry{Type: classloader.NameAndType, Slot: 0}
+	CP.CpIndex[4] = classloader.CpEntry{Type: classloader.UTF8, Slot: 0}
+	CP.CpIndex[5] = classloader.CpEntry{Type: classloader.UTF8, Slot: 1}
+	CP.CpIndex[6] = classloader.CpEntry{Type: classloader.StringConst, Slot: 7}
+	CP.CpIndex[7] = classloader.CpEntry{Type: classloader.UTF8, Slot: 2}
+	CP.MethodRefs = []classloader.MethodRefEntry{{ClassIndex: 2, NameAndType: 3}}
+	CP.ClassRefs = []uint32{stringPool.GetStringIndex(&objClassName)}
+	CP.Utf8Refs = []string{methName, methType, "never printed"}
+	CP.NameAndTypes = []classloader.NameAndTypeEntry{{NameIndex: 4, DescIndex: 5}}
+	classloader.ResolveCPmethRefs(&CP)
+
+	f := newFrame(opcodes.ACONST_NULL)
+	f.Meth = append(f.Meth, opcodes.LDC, 6, opcodes.INVOKEVIRTUAL, 0x00, 0x01, opcodes.RETURN)
+	f.CP = &CP
+	for j := 0; j < 10; j++ {
+		f.OpStack = append(f.OpStack, 0)
+	}
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f)
+	interpret(fs)
+
+	_ = werr.Close()
+	rawStderrMsg, _ := io.ReadAll(rerr)
+	os.Stderr = normalStderr
+
+	errMsg := string(rawStderrMsg)
+	if !strings.Contains(errMsg, "NullPointerException") ||
+		!strings.Contains(errMsg, `Cannot invoke "java.io.PrintStream.println(String)" because "null" is null`) {
+		t.Errorf("INVOKEVIRTUAL: Did not get expected NullPointerException, got: %s", errMsg)
+	}
+}
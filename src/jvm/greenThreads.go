@@ -0,0 +1,163 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"fmt"
+	"jacobin/src/exceptions"
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/shutdown"
+	"jacobin/src/thread"
+	"jacobin/src/trace"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// Green threads. When -XX:+GreenThreads is specified, virtual threads are not
+// given a goroutine each. Rather, they are placed on a run queue, which is served
+// by a fixed pool of worker goroutines, one per GOMAXPROCS. A worker runs a thread
+// until the thread ends or yields, and then takes the next thread from the queue.
+// Threads yield at backward branches (that is, at the bottom of loops): after
+// greenYieldInterval backward branches, the interpreter returns to the worker,
+// which puts the thread at the back of the run queue. Because a thread's state is
+// entirely in its frame stack, it resumes where it left off the next time a worker
+// takes it from the queue.
+//
+// A green thread that blocks in a gfunction (e.g., in Thread.sleep()) blocks its
+// worker, so threads that spend most of their time blocked gain little from this
+// mode.
+
+// greenYieldInterval is the number of backward branches a green thread takes
+// before it yields to the other threads on the run queue
+var greenYieldInterval = 1000
+
+// greenThreadsActive is set once the first green thread is submitted, so that
+// the interpreter checks for yields only when green threads might be running
+var greenThreadsActive atomic.Bool
+
+// greenThreadStates holds a *greenThreadState for every green thread, keyed by thread ID
+var greenThreadStates sync.Map
+
+type greenThreadState struct {
+	branches int  // backward branches taken since the thread last yielded
+	yielded  bool // set by the interpreter when the thread must yield
+}
+
+// the run queue and the pool of workers that serve it
+var greenScheduler struct {
+	lock      sync.Mutex
+	ready     *sync.Cond
+	queue     []*thread.ExecThread
+	startOnce sync.Once
+	workers   int
+}
+
+// submitGreenThread places a new green thread on the run queue, starting the
+// pool of workers if they are not already running
+func submitGreenThread(th *thread.ExecThread) {
+	greenScheduler.startOnce.Do(startGreenWorkers)
+	greenThreadStates.Store(th.ID, &greenThreadState{})
+	greenThreadsActive.Store(true)
+	enqueueGreenThread(th)
+}
+
+func startGreenWorkers() {
+	greenScheduler.ready = sync.NewCond(&greenScheduler.lock)
+	greenScheduler.workers = runtime.GOMAXPROCS(0)
+	if globals.TraceVerbose {
+		trace.Trace(fmt.Sprintf("startGreenWorkers: starting %d workers", greenScheduler.workers))
+	}
+	for i := 0; i < greenScheduler.workers; i++ {
+		go greenWorker()
+	}
+}
+
+// puts a thread at the back of the run queue
+func enqueueGreenThread(th *thread.ExecThread) {
+	greenScheduler.lock.Lock()
+	greenScheduler.queue = append(greenScheduler.queue, th)
+	greenScheduler.lock.Unlock()
+	greenScheduler.ready.Signal()
+}
+
+// takes the thread at the front of the run queue, waiting for one if need be
+func dequeueGreenThread() *thread.ExecThread {
+	greenScheduler.lock.Lock()
+	defer greenScheduler.lock.Unlock()
+	for len(greenScheduler.queue) == 0 {
+		greenScheduler.ready.Wait()
+	}
+	th := greenScheduler.queue[0]
+	greenScheduler.queue[0] = nil
+	greenScheduler.queue = greenScheduler.queue[1:]
+	return th
+}
+
+// greenWorker runs threads from the run queue, for the life of the program
+func greenWorker() {
+	for {
+		th := dequeueGreenThread()
+		if runGreenThreadSlice(th) {
+			th.Terminate()
+			greenThreadStates.Delete(th.ID)
+			th.RemoveThreadFromTable(globals.GetGlobalRef())
+		} else {
+			enqueueGreenThread(th)
+		}
+	}
+}
+
+// runGreenThreadSlice runs the thread until it ends or yields. Returns true if
+// the thread ended.
+func runGreenThreadSlice(th *thread.ExecThread) bool {
+	defer func() {
+		// only an untrapped panic gets us here
+		if r := recover(); r != nil {
+			glob := globals.GetGlobalRef()
+			glob.ErrorGoStack = string(debug.Stack())
+			exceptions.ShowPanicCause(r)
+			exceptions.ShowFrameStack(th)
+			exceptions.ShowGoStackTrace(nil)
+			shutdown.Exit(shutdown.APP_EXCEPTION)
+		}
+	}()
+
+	v, _ := greenThreadStates.Load(th.ID)
+	state := v.(*greenThreadState)
+	state.yielded = false
+	thread.RegisterForSafepoints(th.ID) // a green thread that has yielded is not running Java code
+	defer thread.UnregisterFromSafepoints(th.ID)
+	for th.Stack.Len() > 0 {
+		interpret(th.Stack)
+		if state.yielded {
+			return false
+		}
+	}
+	return true
+}
+
+// greenThreadShouldYield is called by the interpreter after a backward branch.
+// It returns true if the frame runs on a green thread that has used up its
+// allotment of backward branches, in which case the interpreter returns to the
+// worker so that the thread can yield.
+func greenThreadShouldYield(fr *frames.Frame) bool {
+	v, ok := greenThreadStates.Load(fr.Thread)
+	if !ok {
+		return false
+	}
+	state := v.(*greenThreadState)
+	state.branches++
+	if state.branches < greenYieldInterval {
+		return false
+	}
+	state.branches = 0
+	state.yielded = true
+	return true
+}
@@ -0,0 +1,307 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"errors"
+	"jacobin/src/classloader"
+	"jacobin/src/exceptions"
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/opcodes"
+	"jacobin/src/thread"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// creates a Runnable class whose run() method counts down from 2000 in a loop,
+// and returns a virtual Thread object that will run an instance of it
+func makeSpinnerThread(t *testing.T) *object.Object {
+	t.Helper()
+	addUpcallTestMethod("pkg/Spinner", "run()V", []byte{
+		opcodes.SIPUSH, 0x07, 0xD0, // 2000
+		opcodes.ISTORE_1,
+		opcodes.IINC, 0x01, 0xFF, // i--
+		opcodes.ILOAD_1,
+		opcodes.IFGT, 0xFF, 0xFC, // back to IINC while i > 0
+		opcodes.RETURN})
+
+	className := "pkg/Spinner"
+	runnable := object.MakeEmptyObjectWithClassName(&className)
+	threadClass := "java/lang/Thread"
+	th := object.MakeEmptyObjectWithClassName(&threadClass)
+	th.FieldTable["target"] = object.Field{Ftype: types.Ref, Fvalue: runnable}
+	th.FieldTable["virtual"] = object.Field{Ftype: types.Bool, Fvalue: types.JavaBoolTrue}
+	return th
+}
+
+// waits for the thread whose ID is in the Thread object to end
+func waitForJavaThread(t *testing.T, threadObj *object.Object, started map[int]*thread.ExecThread) {
+	t.Helper()
+	id := int(threadObj.FieldTable["ID"].Fvalue.(int64))
+	th, ok := started[id]
+	if !ok {
+		t.Fatalf("Thread %d was not found", id)
+	}
+	select {
+	case <-th.Terminated:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("Thread %d did not end", id)
+	}
+}
+
+func TestGreenThreadsRunToCompletionOnBoundedPool(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+	glob := globals.GetGlobalRef()
+	glob.GreenThreads = true
+
+	savedInterval := greenYieldInterval
+	greenYieldInterval = 10 // make the threads yield often
+	defer func() { greenYieldInterval = savedInterval }()
+
+	const threadCount = 50
+	var threadObjs []*object.Object
+	started := make(map[int]*thread.ExecThread)
+	for i := 0; i < threadCount; i++ {
+		threadObj := makeSpinnerThread(t)
+		if err := StartJavaThread(nil, threadObj); err != nil {
+			t.Fatalf("Unexpected error starting thread: %v", err)
+		}
+		// grab the ExecThread before it ends and leaves the thread table
+		id := int(threadObj.FieldTable["ID"].Fvalue.(int64))
+		glob.ThreadLock.Lock()
+		if th, ok := glob.Threads[id].(*thread.ExecThread); ok {
+			started[id] = th
+		}
+		glob.ThreadLock.Unlock()
+		threadObjs = append(threadObjs, threadObj)
+	}
+
+	if greenScheduler.workers != runtime.GOMAXPROCS(0) {
+		t.Errorf("Expected %d workers, got %d", runtime.GOMAXPROCS(0), greenScheduler.workers)
+	}
+
+	for _, threadObj := range threadObjs {
+		id := int(threadObj.FieldTable["ID"].Fvalue.(int64))
+		if _, ok := started[id]; !ok {
+			continue // the thread ended before we could look it up
+		}
+		waitForJavaThread(t, threadObj, started)
+	}
+
+	// once ended, the threads leave the thread table
+	time.Sleep(10 * time.Millisecond)
+	glob.ThreadLock.Lock()
+	defer glob.ThreadLock.Unlock()
+	for id := range started {
+		if _, ok := glob.Threads[id]; ok {
+			t.Errorf("Expected thread %d to be removed from the thread table", id)
+		}
+	}
+}
+
+func TestGreenThreadShouldYield(t *testing.T) {
+	savedInterval := greenYieldInterval
+	greenYieldInterval = 3
+	defer func() { greenYieldInterval = savedInterval }()
+
+	fr := frames.CreateFrame(1)
+	fr.Thread = 9999
+	if greenThreadShouldYield(fr) {
+		t.Error("A thread that is not a green thread should never yield")
+	}
+
+	state := &greenThreadState{}
+	greenThreadStates.Store(fr.Thread, state)
+	defer greenThreadStates.Delete(fr.Thread)
+	for i := 1; i < greenYieldInterval; i++ {
+		if greenThreadShouldYield(fr) {
+			t.Fatalf("Did not expect a yield after %d branches", i)
+		}
+	}
+	if !greenThreadShouldYield(fr) || !state.yielded || state.branches != 0 {
+		t.Error("Expected a yield once the interval was reached")
+	}
+}
+
+func TestStartJavaThreadWithoutRunnable(t *testing.T) {
+	globals.InitGlobals("test")
+	threadClass := "java/lang/Thread"
+	threadObj := object.MakeEmptyObjectWithClassName(&threadClass)
+
+	// a thread with nothing to run ends at once
+	if err := StartJavaThread(nil, threadObj); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, ok := threadObj.FieldTable["ID"].Fvalue.(int64); !ok {
+		t.Error("Expected the thread to be given an ID")
+	}
+}
+
+// creates the class, a subclass of superclass, whose run() method sets the int field
+// "ran" of the object it's called on to 1
+func addFieldSettingRun(className, superclass string) {
+	addInitTestClass(className, superclass, false, nil)
+	cp := classloader.CPool{}
+	cp.CpIndex = []classloader.CpEntry{{Type: 0, Slot: 0}, {Type: classloader.FieldRef, Slot: 0}}
+	cp.FieldRefs = []classloader.ResolvedFieldEntry{{ClName: className, FldName: "ran", FldType: types.Int}}
+	classloader.MTable[className+".run()V"] = classloader.MTentry{
+		MType: 'J',
+		Meth: classloader.JmEntry{
+			MaxStack:  2,
+			MaxLocals: 1,
+			Code:      []byte{opcodes.ALOAD_0, opcodes.ICONST_1, opcodes.PUTFIELD, 0x00, 0x01, opcodes.RETURN},
+			Cp:        &cp,
+		},
+	}
+}
+
+// starts the thread and waits for it to end
+func runJavaThread(t *testing.T, threadObj *object.Object) {
+	t.Helper()
+	if err := StartJavaThread(nil, threadObj); err != nil {
+		t.Fatalf("Unexpected error starting thread: %v", err)
+	}
+	glob := globals.GetGlobalRef()
+	started := make(map[int]*thread.ExecThread)
+	id := int(threadObj.FieldTable["ID"].Fvalue.(int64))
+	glob.ThreadLock.Lock()
+	th, ok := glob.Threads[id].(*thread.ExecThread)
+	glob.ThreadLock.Unlock()
+	if ok {
+		started[id] = th
+		waitForJavaThread(t, threadObj, started)
+	}
+}
+
+func TestStartJavaThreadRunsRunnable(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+	addFieldSettingRun("pkg/Task", types.ObjectClassName)
+
+	className := "pkg/Task"
+	runnable := object.MakeEmptyObjectWithClassName(&className)
+	runnable.FieldTable["ran"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+	threadClass := "java/lang/Thread"
+	threadObj := object.MakeEmptyObjectWithClassName(&threadClass)
+	threadObj.FieldTable["target"] = object.Field{Ftype: types.Ref, Fvalue: runnable}
+
+	runJavaThread(t, threadObj)
+	if ran := runnable.FieldTable["ran"].Fvalue; ran != int64(1) {
+		t.Errorf("Expected the thread to run the Runnable's run(), got ran=%v", ran)
+	}
+}
+
+func TestStartJavaThreadRunsOverriddenRun(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+	addFieldSettingRun("pkg/Worker", "java/lang/Thread")
+
+	// a subclass of Thread that overrides run() is given no Runnable
+	className := "pkg/Worker"
+	threadObj := object.MakeEmptyObjectWithClassName(&className)
+	threadObj.FieldTable["ran"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+
+	runJavaThread(t, threadObj)
+	if ran := threadObj.FieldTable["ran"].Fvalue; ran != int64(1) {
+		t.Errorf("Expected the thread to run its own run(), got ran=%v", ran)
+	}
+}
+
+// adds a subclass of InheritableThreadLocal whose childValue() method runs the code
+func addChildValueClass(className string, code []byte) {
+	addInitTestClass(className, "java/lang/InheritableThreadLocal", false, nil)
+	addUpcallTestMethod(className, "childValue(Ljava/lang/Object;)Ljava/lang/Object;", code)
+}
+
+// returns a thread, registered in the thread table, whose frame stack holds a frame
+func addParentThread() *thread.ExecThread {
+	th := thread.CreateThread()
+	th.Stack = frames.CreateFrameStack()
+	th.AddThreadToTable(globals.GetGlobalRef())
+	f := frames.CreateFrame(2)
+	f.Thread = th.ID
+	_ = frames.PushFrame(th.Stack, f)
+	return &th
+}
+
+func TestInheritedThreadLocalsUseChildValue(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+	addChildValueClass("pkg/SelfValued", []byte{opcodes.ALOAD_0, opcodes.ARETURN}) // returns the local itself
+
+	overriding := "pkg/SelfValued"
+	plain := "java/lang/InheritableThreadLocal"
+	overrider := object.MakeEmptyObjectWithClassName(&overriding)
+	inheritable := object.MakeEmptyObjectWithClassName(&plain)
+
+	parent := addParentThread()
+	defer parent.RemoveThreadFromTable(globals.GetGlobalRef())
+	parentValue := object.StringObjectFromGoString("parent")
+	parent.InheritableLocals = map[any]any{overrider: parentValue, inheritable: parentValue}
+
+	child := thread.CreateThread()
+	if err := child.InheritThreadLocals(parent, childThreadLocalValue(parent.Stack)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if child.InheritableLocals[overrider] != overrider {
+		t.Errorf("Expected the value returned by the overriding childValue(), got %v",
+			child.InheritableLocals[overrider])
+	}
+	if child.InheritableLocals[inheritable] != parentValue {
+		t.Errorf("Expected the parent's value, got %v", child.InheritableLocals[inheritable])
+	}
+	if parent.InheritableLocals[overrider] != parentValue {
+		t.Error("Expected the parent's value to be unchanged")
+	}
+	if parent.Stack.Len() != 1 {
+		t.Errorf("Expected the parent's frame stack to be left as it was, got %d frames", parent.Stack.Len())
+	}
+}
+
+// an exception in childValue() is thrown by start() and the thread doesn't run
+func TestStartJavaThreadChildValueException(t *testing.T) {
+	globals.InitGlobals("test")
+	globals.GetGlobalRef().JacobinName = "testWithoutShutdown" // so that ThrowEx looks for a handler
+	trace.Init()
+	classloader.InitMethodArea()
+	addChildValueClass("pkg/Failing", []byte{opcodes.ICONST_1, opcodes.ICONST_0, opcodes.IDIV, opcodes.ARETURN})
+	addFieldSettingRun("pkg/Task", types.ObjectClassName)
+
+	failing := "pkg/Failing"
+	local := object.MakeEmptyObjectWithClassName(&failing)
+	parent := addParentThread()
+	defer parent.RemoveThreadFromTable(globals.GetGlobalRef())
+	parent.InheritableLocals = map[any]any{local: int64(1)}
+
+	className := "pkg/Task"
+	runnable := object.MakeEmptyObjectWithClassName(&className)
+	runnable.FieldTable["ran"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+	threadClass := "java/lang/Thread"
+	threadObj := object.MakeEmptyObjectWithClassName(&threadClass)
+	threadObj.FieldTable["target"] = object.Field{Ftype: types.Ref, Fvalue: runnable}
+
+	err := StartJavaThread(parent.Stack, threadObj)
+	var upcallErr *exceptions.UpcallError
+	if !errors.As(err, &upcallErr) || !strings.Contains(upcallErr.Cause, "ArithmeticException") {
+		t.Fatalf("Expected the ArithmeticException from childValue(), got: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if ran := runnable.FieldTable["ran"].Fvalue; ran != int64(0) {
+		t.Error("Expected the thread not to run")
+	}
+}
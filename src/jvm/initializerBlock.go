@@ -11,88 +11,274 @@ import (
 	"errors"
 	"fmt"
 	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/exceptions"
 	"jacobin/src/frames"
 	"jacobin/src/gfunction"
 	"jacobin/src/globals"
+	"jacobin/src/object"
 	"jacobin/src/stringPool"
+	jvmThread "jacobin/src/thread"
 	"jacobin/src/trace"
 	"jacobin/src/types"
+	"runtime/debug"
+	"strings"
+	"sync"
 )
 
 // Initialization blocks are code blocks that for all intents are methods. They're gathered up by the
-// Java compiler into a method called <clinit>, which must be run at class instantiation--that is,
-// before any constructor. Because that code might well call other methods, it will need to be run
-// just like a regular method with stack frames and depending on the interpreter in run.go
-// In addition, we have to make sure that the initialization blocks of superclasses have been
-// previously executed.
-func runInitializationBlock(k *classloader.Klass, superClasses []string, fs *list.List) error {
-	if superClasses == nil || len(superClasses) == 0 {
-		// show we're running <clinit>. This prevents circularity errors.
-		k.Data.ClInit = types.ClInitInProgress
-
-		// if no superclasses were previously looked up
-		// get list of the superclasses up to but not including java.lang.Object
-		var superclasses []string
-
-		// put the present class at the bottom of the list of superclasses,
-		// because we'll need to run its clinit() code, if any
-		superclasses = append(superclasses, k.Data.Name)
-
-		superclass := *stringPool.GetStringPointer(k.Data.SuperclassIndex)
-		for {
-			if superclass == types.ObjectClassName {
-				break
-			}
+// Java compiler into a method called <clinit>, which must be run at class initialization--that is,
+// before any constructor or static method is run and before any static field is used. Because that
+// code might well call other methods, it will need to be run just like a regular method with stack
+// frames and depending on the interpreter in run.go
+//
+// Initialization follows the procedure in JVMS 5.5
+// (https://docs.oracle.com/javase/specs/jvms/se21/html/jvms-5.html#jvms-5.5):
+// before a class is initialized, its superclass is initialized, followed by those of its
+// superinterfaces that declare non-abstract, non-static methods. Interfaces do not initialize
+// their superinterfaces. Each class has an initialization lock, so that if two threads
+// try to initialize the same class, one of them waits for the other to finish. A thread
+// that requests the initialization of a class it is already initializing (a recursive
+// request, such as a <clinit> that calls a static method of its own class) proceeds
+// immediately. If <clinit> throws an exception that it does not catch, the class is marked
+// as erroneous: the code that triggered the initialization gets an ExceptionInInitializerError,
+// and any later attempt to initialize the class gets a NoClassDefFoundError.
 
-			err := loadThisClass(superclass) // load the superclass
-			if err != nil {                  // error message will have been displayed
-				return err
-			}
+// classInitLock is the per-class initialization lock described in JVMS 5.5
+type classInitLock struct {
+	mutex  sync.Mutex
+	cond   *sync.Cond
+	thread int // the thread initializing the class, valid while ClInit is ClInitInProgress
+}
 
-			// load only superclasses that have a clInit block that has not been run
-			loadedSuperclass := classloader.MethAreaFetch(superclass)
-			if loadedSuperclass.Data.ClInit == types.ClInitNotRun {
-				superclasses = append(superclasses, superclass)
-			}
+var classInitLocks sync.Map // class name -> *classInitLock
+
+// classInitError is the error returned when a class cannot be initialized. If the
+// class was marked as erroneous by an earlier failed attempt, erroneous is true.
+type classInitError struct {
+	className string
+	cause     string         // the exception thrown by <clinit>, e.g. "java.lang.ArithmeticException: / by zero"
+	thrown    *object.Object // the Throwable of that exception, if it was created
+	erroneous bool
+}
+
+func (e *classInitError) Error() string {
+	if e.erroneous {
+		return fmt.Sprintf("Could not initialize class %s", strings.ReplaceAll(e.className, "/", "."))
+	}
+	return fmt.Sprintf("exception in initializer of class %s: %s",
+		strings.ReplaceAll(e.className, "/", "."), e.cause)
+}
+
+// throwClassInitError throws the exception corresponding to an error returned by
+// runInitializationBlock(): NoClassDefFoundError if the class is erroneous, otherwise
+// ExceptionInInitializerError, whose cause is the exception that <clinit> threw. The
+// return value is the one the bytecode should return.
+func throwClassInitError(bytecode string, err error, fr *frames.Frame) int {
+	globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+	which := excNames.ExceptionInInitializerError
+	var cause *object.Object
+	var initErr *classInitError
+	if errors.As(err, &initErr) {
+		if initErr.erroneous {
+			which = excNames.NoClassDefFoundError
+		} else {
+			cause = initErr.thrown
+		}
+	}
+	errMsg := fmt.Sprintf("%s: %s", bytecode, err.Error())
+	status := exceptions.ThrowExWithCause(which, errMsg, cause, fr)
+	if status != exceptions.Caught {
+		return exceptions.ERROR_OCCURRED // applies only if in test
+	}
+	return exceptions.RESUME_HERE // caught
+}
+
+// gets the initialization lock for a class, creating it if need be
+func getClassInitLock(className string) *classInitLock {
+	if lock, ok := classInitLocks.Load(className); ok {
+		return lock.(*classInitLock)
+	}
+	newLock := &classInitLock{}
+	newLock.cond = sync.NewCond(&newLock.mutex)
+	lock, _ := classInitLocks.LoadOrStore(className, newLock)
+	return lock.(*classInitLock)
+}
+
+// runInitializationBlock initializes the class k, per the procedure described above, if it
+// has not already been initialized. An error means that an initializer failed or that the
+// class is erroneous; the caller should throw the exception using throwClassInitError().
+func runInitializationBlock(k *classloader.Klass, fs *list.List) error {
+	if k == nil || k.Data == nil || k.Data.Name == types.ObjectClassName {
+		return nil
+	}
+	return initializeClass(k, fs, initializingThread(fs))
+}
 
-			// now loop to see whether this superclass has a superclass
-			superclass = *stringPool.GetStringPointer(loadedSuperclass.Data.SuperclassIndex)
+// initializingThread returns the ID of the thread that runs on the frame stack fs: the
+// thread of the frame at the top of the stack or, if the stack is empty, the ExecThread
+// whose stack it is. A stack that belongs to no thread, such as one a gfunction creates,
+// is given an ID of its own, so that no two such requests are taken to come from the
+// same thread. The ID is passed on to the frames of the initializers that are run, so
+// that their recursive requests are recognized.
+func initializingThread(fs *list.List) int {
+	if fs != nil && fs.Front() != nil {
+		if thread := fs.Front().Value.(*frames.Frame).Thread; thread != 0 {
+			return thread
 		}
-		superClasses = superclasses
-	}
-
-	// now execute any encountered <clinit> code in this class
-	for i := len(superClasses) - 1; i >= 0; i-- {
-		className := superClasses[i]
-		me, err := classloader.FetchMethodAndCP(className, "<clinit>", "()V")
-		if err == nil {
-			switch me.MType {
-			case 'J': // it's a Java initializer (the most common case)
-				err = runJavaInitializer(me.Meth, k, fs)
-			case 'G': // it's a golang implementation of the initializer
-				err = runNativeInitializer(me, k, fs)
+	}
+	if fs != nil {
+		glob := globals.GetGlobalRef()
+		glob.ThreadLock.Lock()
+		for _, t := range glob.Threads {
+			if th, ok := t.(*jvmThread.ExecThread); ok && th.Stack == fs {
+				glob.ThreadLock.Unlock()
+				return th.ID
 			}
-			if err != nil {
+		}
+		glob.ThreadLock.Unlock()
+	}
+	return jvmThread.IncrementThreadNumber()
+}
+
+// initializeClass initializes the class k on behalf of the thread whose ID is given
+func initializeClass(k *classloader.Klass, fs *list.List, thread int) error {
+	// steps 1-4: acquire the lock, waiting if another thread is initializing the class
+	lock := getClassInitLock(k.Data.Name)
+	lock.mutex.Lock()
+	for k.Data.ClInit == types.ClInitInProgress && lock.thread != thread {
+		// record the wait for the deadlock detector
+		jvmThread.SetBlockedOn(thread, jvmThread.BlockedOnLock, "the initialization lock of class "+
+			strings.ReplaceAll(k.Data.Name, "/", "."), lock.thread)
+		lock.cond.Wait()
+		jvmThread.ClearBlockedOn(thread)
+	}
+	if k.Data.ClInit == types.ClInitInProgress || k.Data.ClInit == types.ClInitRun {
+		lock.mutex.Unlock() // a recursive request or an already-initialized class
+		return nil
+	}
+	if k.Data.ClInit == types.ClInitError { // step 5: a previous attempt failed
+		lock.mutex.Unlock()
+		return &classInitError{className: k.Data.Name, erroneous: true}
+	}
+	hasClinit := k.Data.ClInit == types.ClInitNotRun
+	k.Data.ClInit = types.ClInitInProgress
+	lock.thread = thread
+	lock.mutex.Unlock()
+
+	// step 7: initialize the superclass and superinterfaces, then (step 9) run <clinit>
+	err := initializeSupertypes(k, fs, thread)
+	if err == nil && hasClinit {
+		err = runClinit(k, fs, thread)
+	}
+
+	// steps 10-11: record the outcome and notify any waiting threads
+	lock.mutex.Lock()
+	if err == nil {
+		k.Data.ClInit = types.ClInitRun
+	} else {
+		k.Data.ClInit = types.ClInitError
+	}
+	lock.thread = 0
+	lock.cond.Broadcast()
+	lock.mutex.Unlock()
+	return err
+}
+
+// initializeIfNeeded initializes the named class if it is loaded and has a <clinit>
+// that has not yet been run. It is used when a static field of the class is first
+// accessed, which for interfaces is the principal trigger of initialization. An
+// error is also returned if the class is erroneous.
+func initializeIfNeeded(className string, fs *list.List) error {
+	k := classloader.MethAreaFetch(className)
+	if k == nil || k.Data == nil ||
+		(k.Data.ClInit != types.ClInitNotRun && k.Data.ClInit != types.ClInitError) {
+		return nil
+	}
+	return runInitializationBlock(k, fs)
+}
+
+// initializes the superclass of k and, if k is a class, those superinterfaces of k
+// that declare non-abstract, non-static (that is, default) methods
+func initializeSupertypes(k *classloader.Klass, fs *list.List, thread int) error {
+	if k.Data.Access.ClassIsInterface {
+		return nil // interfaces don't initialize their superinterfaces
+	}
+
+	superclass := *stringPool.GetStringPointer(k.Data.SuperclassIndex)
+	if superclass != types.ObjectClassName {
+		if err := loadThisClass(superclass); err != nil { // error message will have been displayed
+			return err
+		}
+		if err := initializeClass(classloader.MethAreaFetch(superclass), fs, thread); err != nil {
+			return err
+		}
+	}
+
+	for _, ifaceIndex := range k.Data.Interfaces {
+		iface := *stringPool.GetStringPointer(uint32(ifaceIndex))
+		if err := loadThisClass(iface); err != nil {
+			return err
+		}
+		ik := classloader.MethAreaFetch(iface)
+		if declaresDefaultMethods(ik) {
+			if err := initializeClass(ik, fs, thread); err != nil {
 				return err
 			}
-		} // if no <clinit> method, then skip that superclass
+		}
 	}
 	return nil
 }
 
-// Run the <clinit>() initializer code as a Java method. This effectively duplicates
-// the code in run.go that creates a new frame and runs the method. Note that this
-// code creates its own frame stack, which is distinct from the applications frame
-// stack. The reason is that this is computing that's in most ways apart from the
-// bytecode of the app. (This design might be revised at a later point and the two
-// frame stacks combined into one.)
-func runJavaInitializer(m classloader.MData, k *classloader.Klass, fs *list.List) error {
+// determines whether an interface declares any non-abstract, non-static methods
+func declaresDefaultMethods(k *classloader.Klass) bool {
+	if k == nil || k.Data == nil {
+		return false
+	}
+	for _, m := range k.Data.MethodTable {
+		if m.AccessFlags&(classloader.AccAbstract|classloader.AccStatic) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// runs the <clinit> method of k, which can be a Java or a native (golang) method
+func runClinit(k *classloader.Klass, fs *list.List, thread int) error {
+	me, err := classloader.FetchMethodAndCP(k.Data.Name, "<clinit>", "()V")
+	if err != nil {
+		return nil // if no <clinit> method, then there's nothing to run
+	}
+	switch me.MType {
+	case 'J': // it's a Java initializer (the most common case)
+		err = runJavaInitializer(me.Meth, k, fs, thread)
+	case 'G': // it's a golang implementation of the initializer
+		err = runNativeInitializer(me, k, fs)
+	}
+	return err
+}
+
+// Run the <clinit>() initializer code as a Java method. Its frame is pushed onto the
+// frame stack of the thread that triggered the initialization, directly above the frame
+// of the triggering code, and the methods that <clinit> calls are pushed above it as for
+// any other method. So stack traces taken during initialization show how the class came
+// to be initialized, and -Xss limits the depth of initializers as it does other code.
+// The <clinit> frame is a boundary for exceptions: one that escapes <clinit> is not
+// propagated to the triggering code (see exceptions.AbortToBoundary()), but returned
+// here as a classInitError, which the caller throws as an ExceptionInInitializerError.
+func runJavaInitializer(m classloader.MData, k *classloader.Klass, fs *list.List, thread int) error {
+	if fs == nil { // only from gfunctions that have no access to the thread's frame stack
+		fs = frames.CreateFrameStack()
+	}
+
 	meth := m.(classloader.JmEntry)
 	f := frames.CreateFrame(meth.MaxStack + types.StackInflator) // Experimental expansion, see JACOBIN-494
+	f.Thread = thread
 	if fs.Front() != nil {
-		parentFrame := *(fs.Front().Value.(*frames.Frame))
-		f.Thread = parentFrame.Thread
+		parentFrame := fs.Front().Value.(*frames.Frame)
+		f.Pool = parentFrame.Pool // so the methods <clinit> calls use the thread's frame pool
 	}
+	f.FrameStack = fs
 	f.MethName = "<clinit>"
 	f.MethType = "()V"
 	f.ClName = k.Data.Name
@@ -104,10 +290,11 @@ func runJavaInitializer(m classloader.MData, k *classloader.Klass, fs *list.List
 		f.Locals = append(f.Locals, 0)
 	}
 
-	k.Data.ClInit = types.ClInitInProgress
-
 	currJvmStackSize := fs.Len()
-	if frames.PushFrame(fs, f) != nil {
+	if err := frames.PushFrame(fs, f); err != nil {
+		if errors.Is(err, frames.ErrStackOverflow) { // thrown in <clinit>, like any other exception
+			return &classInitError{className: k.Data.Name, cause: "java.lang.StackOverflowError"}
+		}
 		errMsg := "memory exception allocating frame in runJavaInitializer()"
 		trace.Error(errMsg)
 		return errors.New(errMsg)
@@ -122,16 +309,19 @@ func runJavaInitializer(m classloader.MData, k *classloader.Klass, fs *list.List
 	// the <clinit> method might call other methods, so we can't just determine that
 	// it's completed by the return from interpret(). See JACOBIN-665
 	for fs.Len() > currJvmStackSize { // loop until the frame stack is back to its pre-<clinit>() size
-		interpret(fs) // if an error occurs, ThrowEx() will break us out of here
+		interpret(fs)
+		if f.Uncaught != "" { // an exception escaped <clinit>, see exceptions.AbortToBoundary()
+			fs.Remove(fs.Front()) // AbortToBoundary() left the <clinit> frame at the top
+			return &classInitError{className: k.Data.Name, cause: f.Uncaught, thrown: f.UncaughtObj}
+		}
 	}
-	k.Data.ClInit = types.ClInitRun // flag showing we've run this class's <clinit>
-
-	// frames.PopFrame(fs)
 	return nil
 }
 
 func runNativeInitializer(mt classloader.MTentry, k *classloader.Klass, fs *list.List) error {
-	_ = gfunction.RunGfunction(mt, fs, k.Data.Name, "<clinit>", "()V", nil, false, false)
-	k.Data.ClInit = types.ClInitRun // flag showing we've run this class's <clinit>
+	ret := gfunction.RunGfunction(mt, fs, k.Data.Name, "<clinit>", "()V", nil, false, false)
+	if err, ok := ret.(error); ok && !errors.Is(err, gfunction.CaughtGfunctionException) {
+		return err
+	}
 	return nil
 }
@@ -0,0 +1,310 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"container/list"
+	"errors"
+	"jacobin/src/classloader"
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/opcodes"
+	"jacobin/src/statics"
+	"jacobin/src/stringPool"
+	"jacobin/src/thread"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+	"testing"
+	"time"
+)
+
+// adds a skeletal class to the method area, with the given superclass and interfaces
+func addInitTestClass(name, superclass string, isInterface bool, methods map[string]*classloader.Method,
+	interfaces ...string) *classloader.Klass {
+	k := classloader.Klass{Status: 'F', Loader: "testloader", Data: &classloader.ClData{
+		Name:            name,
+		SuperclassIndex: stringPool.GetStringIndex(&superclass),
+		MethodTable:     methods,
+		ClInit:          types.NoClInit,
+	}}
+	k.Data.Access.ClassIsInterface = isInterface
+	for _, iface := range interfaces {
+		k.Data.Interfaces = append(k.Data.Interfaces, uint16(stringPool.GetStringIndex(&iface)))
+	}
+	classloader.MethAreaInsert(name, &k)
+	return &k
+}
+
+func TestRunInitializationBlockInitializesSupertypes(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+
+	withDefault := map[string]*classloader.Method{"run()V": {AccessFlags: classloader.AccPublic}}
+	abstractOnly := map[string]*classloader.Method{"run()V": {AccessFlags: classloader.AccPublic | classloader.AccAbstract}}
+
+	defIface := addInitTestClass("pkg/DefaultIface", types.ObjectClassName, true, withDefault)
+	plainIface := addInitTestClass("pkg/PlainIface", types.ObjectClassName, true, abstractOnly)
+	super := addInitTestClass("pkg/Super", types.ObjectClassName, false, nil)
+	sub := addInitTestClass("pkg/Sub", "pkg/Super", false, nil, "pkg/DefaultIface", "pkg/PlainIface")
+
+	if err := runInitializationBlock(sub, list.New()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if sub.Data.ClInit != types.ClInitRun || super.Data.ClInit != types.ClInitRun {
+		t.Errorf("Expected class and superclass to be initialized, got: %d, %d",
+			sub.Data.ClInit, super.Data.ClInit)
+	}
+	if defIface.Data.ClInit != types.ClInitRun {
+		t.Error("Expected superinterface with a default method to be initialized")
+	}
+	if plainIface.Data.ClInit == types.ClInitRun {
+		t.Error("Did not expect superinterface with only abstract methods to be initialized")
+	}
+}
+
+func TestRunInitializationBlockRecursiveRequest(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+
+	k := addInitTestClass("pkg/InProgress", types.ObjectClassName, false, nil)
+	k.Data.ClInit = types.ClInitInProgress
+	lock := getClassInitLock("pkg/InProgress")
+	lock.thread = 7
+
+	fs := frames.CreateFrameStack()
+	f := frames.CreateFrame(1)
+	f.Thread = 7
+	_ = frames.PushFrame(fs, f)
+
+	// a request by the thread that is initializing the class returns immediately
+	if err := runInitializationBlock(k, fs); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if k.Data.ClInit != types.ClInitInProgress {
+		t.Errorf("Expected initialization to still be in progress, got: %d", k.Data.ClInit)
+	}
+}
+
+// requests that come on empty frame stacks are not taken to be recursive requests,
+// unless the stacks belong to the thread that is initializing the class
+func TestRunInitializationBlockEmptyStacksAreDifferentThreads(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+
+	k := addInitTestClass("pkg/InProgressElsewhere", types.ObjectClassName, false, nil)
+	k.Data.ClInit = types.ClInitInProgress
+	lock := getClassInitLock("pkg/InProgressElsewhere")
+	lock.mutex.Lock()
+	lock.thread = initializingThread(list.New())
+	lock.mutex.Unlock()
+
+	done := make(chan error)
+	go func() { done <- runInitializationBlock(k, list.New()) }()
+	select {
+	case <-done:
+		t.Fatal("Expected a request on another empty frame stack to wait for the initialization")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	lock.mutex.Lock()
+	k.Data.ClInit = types.ClInitRun
+	lock.thread = 0
+	lock.cond.Broadcast()
+	lock.mutex.Unlock()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the waiting request to return once the class was initialized")
+	}
+}
+
+func TestInitializingThread(t *testing.T) {
+	globals.InitGlobals("test")
+	glob := globals.GetGlobalRef()
+
+	th := thread.CreateThread()
+	th.Stack = frames.CreateFrameStack()
+	th.AddThreadToTable(glob)
+	defer th.RemoveThreadFromTable(glob)
+
+	if id := initializingThread(th.Stack); id != th.ID {
+		t.Errorf("Expected the ID of the thread that owns the empty stack, %d, got %d", th.ID, id)
+	}
+
+	f := frames.CreateFrame(1)
+	f.Thread = th.ID
+	_ = frames.PushFrame(th.Stack, f)
+	if id := initializingThread(th.Stack); id != th.ID {
+		t.Errorf("Expected the thread of the frame at the top of the stack, %d, got %d", th.ID, id)
+	}
+
+	id1, id2 := initializingThread(list.New()), initializingThread(nil)
+	if id1 == 0 || id2 == 0 || id1 == id2 || id1 == th.ID || id2 == th.ID {
+		t.Errorf("Expected unowned frame stacks to get distinct, nonzero IDs, got %d and %d", id1, id2)
+	}
+}
+
+func TestInitializeIfNeededSkipsClassesWithoutClinit(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+
+	k := addInitTestClass("pkg/NoClinit", types.ObjectClassName, false, nil)
+	if err := initializeIfNeeded("pkg/NoClinit", list.New()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if k.Data.ClInit != types.NoClInit {
+		t.Errorf("Expected class without <clinit> to be left alone, got: %d", k.Data.ClInit)
+	}
+
+	if err := initializeIfNeeded("pkg/NotLoaded", list.New()); err != nil {
+		t.Errorf("Unexpected error for a class that is not loaded: %v", err)
+	}
+}
+
+func TestRunInitializationBlockErroneousClass(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+
+	k := addInitTestClass("pkg/Broken", types.ObjectClassName, false, nil)
+	k.Data.ClInit = types.ClInitError
+
+	// a class whose <clinit> previously failed cannot be initialized again
+	err := runInitializationBlock(k, list.New())
+	var initErr *classInitError
+	if !errors.As(err, &initErr) || !initErr.erroneous {
+		t.Fatalf("Expected an erroneous-class error, got: %v", err)
+	}
+	if err.Error() != "Could not initialize class pkg.Broken" {
+		t.Errorf("Unexpected error message: %s", err.Error())
+	}
+	if err = initializeIfNeeded("pkg/Broken", list.New()); err == nil {
+		t.Error("Expected initializeIfNeeded to report the erroneous class")
+	}
+}
+
+func TestSubclassOfErroneousClassIsErroneous(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+
+	super := addInitTestClass("pkg/BrokenSuper", types.ObjectClassName, false, nil)
+	super.Data.ClInit = types.ClInitError
+	sub := addInitTestClass("pkg/SubOfBroken", "pkg/BrokenSuper", false, nil)
+
+	if err := runInitializationBlock(sub, list.New()); err == nil {
+		t.Fatal("Expected initialization of a subclass of an erroneous class to fail")
+	}
+	if sub.Data.ClInit != types.ClInitError {
+		t.Errorf("Expected subclass to be marked erroneous, got: %d", sub.Data.ClInit)
+	}
+}
+
+func TestClassInitErrorMessage(t *testing.T) {
+	err := &classInitError{className: "pkg/Broken", cause: "java.lang.ArithmeticException: / by zero"}
+	expected := "exception in initializer of class pkg.Broken: java.lang.ArithmeticException: / by zero"
+	if err.Error() != expected {
+		t.Errorf("Expected: %s, got: %s", expected, err.Error())
+	}
+}
+
+// <clinit> runs on the frame stack of the thread that triggers it, above the triggering frame
+func TestRunJavaInitializerUsesCallersFrameStack(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+
+	k := addInitTestClass("pkg/WithClinit", types.ObjectClassName, false, nil)
+	meth := classloader.JmEntry{MaxStack: 1, Code: []byte{opcodes.RETURN}, Cp: &classloader.CPool{}}
+
+	fs := frames.CreateFrameStack()
+	caller := frames.CreateFrame(2)
+	caller.Thread = 7
+	caller.ClName = "pkg/Caller"
+	caller.MethName = "main"
+	_ = frames.PushFrame(fs, caller)
+
+	if err := runJavaInitializer(meth, k, fs, 7); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fs.Len() != 1 || fs.Front().Value.(*frames.Frame) != caller {
+		t.Errorf("Expected the frame stack to hold only the triggering frame, got %d frames", fs.Len())
+	}
+
+	// gfunctions that have no frame stack get one of their own
+	if err := runJavaInitializer(meth, k, nil, 1); err != nil {
+		t.Errorf("Unexpected error with no frame stack: %v", err)
+	}
+}
+
+// the exception that escapes <clinit> is kept, to be the cause of the ExceptionInInitializerError
+func TestRunJavaInitializerKeepsThrownException(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+
+	k := addInitTestClass("pkg/Thrower", types.ObjectClassName, false, nil)
+	excClass := "java/lang/IllegalStateException"
+	thrown := object.MakeEmptyObjectWithClassName(&excClass)
+	_ = statics.AddStatic("pkg/Thrower.exc", statics.Static{Type: "Ljava/lang/Throwable;", Value: thrown})
+
+	// static { throw exc; }
+	cp := classloader.CPool{}
+	cp.CpIndex = []classloader.CpEntry{{Type: 0, Slot: 0}, {Type: classloader.FieldRef, Slot: 0}}
+	cp.FieldRefs = []classloader.ResolvedFieldEntry{
+		{ClName: "pkg/Thrower", FldName: "exc", FldType: "Ljava/lang/Throwable;"}}
+	meth := classloader.JmEntry{MaxStack: 1, Code: []byte{opcodes.GETSTATIC, 0x00, 0x01, opcodes.ATHROW}, Cp: &cp}
+
+	fs := frames.CreateFrameStack()
+	_ = frames.PushFrame(fs, frames.CreateFrame(2))
+	err := runJavaInitializer(meth, k, fs, 7)
+	var initErr *classInitError
+	if !errors.As(err, &initErr) {
+		t.Fatalf("Expected an exception in the initializer, got: %v", err)
+	}
+	if initErr.thrown != thrown {
+		t.Errorf("Expected the thrown exception to be kept as the cause, got: %v", initErr.thrown)
+	}
+	if initErr.cause != "java.lang.IllegalStateException" {
+		t.Errorf("Unexpected description of the exception: %s", initErr.cause)
+	}
+	if fs.Len() != 1 {
+		t.Errorf("Expected the frame stack to be unchanged, got %d frames", fs.Len())
+	}
+}
+
+func TestRunJavaInitializerStackOverflow(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+
+	k := addInitTestClass("pkg/DeepClinit", types.ObjectClassName, false, nil)
+	meth := classloader.JmEntry{MaxStack: 1, Code: []byte{opcodes.RETURN}, Cp: &classloader.CPool{}}
+
+	fs := frames.CreateFrameStack()
+	_ = frames.PushFrame(fs, frames.CreateFrame(2))
+	globals.GetGlobalRef().MaxFrameDepth = fs.Len()
+	defer func() { globals.GetGlobalRef().MaxFrameDepth = 0 }()
+
+	err := runJavaInitializer(meth, k, fs, 7)
+	var initErr *classInitError
+	if !errors.As(err, &initErr) || initErr.cause != "java.lang.StackOverflowError" {
+		t.Fatalf("Expected a StackOverflowError in the initializer, got: %v", err)
+	}
+	if fs.Len() != 1 {
+		t.Errorf("Expected the frame stack to be unchanged, got %d frames", fs.Len())
+	}
+}
@@ -22,7 +22,6 @@ import (
 	"jacobin/src/types"
 	"jacobin/src/util"
 	"strings"
-	"unsafe"
 )
 
 // instantiating an object is a two-part process (except for arrays, which are handled
@@ -37,6 +36,16 @@ import (
 //     This is being done to avoid a golang circularity error when the caller
 //     is one of the native 'G' functions.
 func InstantiateClass(classname string, frameStack *list.List) (any, error) {
+	obj, err := instantiateClass(classname, frameStack, nil)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// instantiateClass does the work of InstantiateClass(). If nursery is not nil, the
+// object is taken from it (see nursery.go), rather than newly allocated.
+func instantiateClass(classname string, frameStack *list.List, nursery *object.Nursery) (*object.Object, error) {
 
 	if !strings.HasPrefix(classname, "[") { // do this only for classes, not arrays
 		err := loadThisClass(classname)
@@ -45,16 +54,20 @@ func InstantiateClass(classname string, frameStack *list.List) (any, error) {
 		}
 	}
 
-	// strings are handled separately
-	if classname == types.StringClassName {
-		return object.NewStringObject(), nil
+	// classes implemented by gfunctions, such as String, have a representation of
+	// their own, which their instantiation hook creates. See object/instantiation.go
+	if hook, ok := object.InstantiationHookFor(classname); ok {
+		return hook(classname), nil
 	}
 
 	// At this point, classname is ready
 	k := classloader.MethAreaFetch(classname)
-	obj := object.Object{
+	obj := &object.Object{
 		KlassName: stringPool.GetStringIndex(&classname),
 	}
+	if nursery != nil {
+		obj = nursery.Alloc(&classname)
+	}
 
 	if k == nil {
 		errMsg := "InstantiateClass: Class is nil after loading, class: " + classname
@@ -91,68 +104,28 @@ func InstantiateClass(classname string, frameStack *list.List) (any, error) {
 		superclassNamePtr = stringPool.GetStringPointer(loadedSuperclass.Data.SuperclassIndex)
 	}
 
-	// the object's mark field contains the lower 32-bits of the object's
-	// address, which serves as the hash code for the object
-	uintp := uintptr(unsafe.Pointer(&obj))
-	obj.Mark.Hash = uint32(uintp)
-
-	// handle the fields. If the object has no superclass other than Object,
-	// the fields are in an array in the order they're declared in the CP.
-	// If the object has a non-Object superclass, then the superclasses' fields
-	// and the present object's field are stored in a map--indexed by the
-	// field name. Eventually, we might coalesce on a single approach for
-	// both kinds of objects.
+	// handle the fields. The instance fields of the class and of all its superclasses
+	// are allocated in the object's field table, starting with the topmost superclass
+	// and working down to the present class, so that a field declared in a subclass
+	// hides a field of the same name declared in a superclass. Static fields are not
+	// part of the instance: they're placed in the Statics table under the name of the
+	// class that declares them, with their ConstantValue, if any, or the default value.
+	// See (https://docs.oracle.com/javase/specs/jvms/se21/html/jvms-5.html#jvms-5.4.2)
 	if len(superclasses) == 0 && len(k.Data.Fields) == 0 {
 		goto runInitializer // check to see if any static initializers
 	}
 
-	// initialize the map of this object's fields
-	obj.FieldTable = make(map[string]object.Field)
-
-	if len(superclasses) == 0 {
-		for i := 0; i < len(k.Data.Fields); i++ {
-			fld := k.Data.Fields[i]
-			fldName := k.Data.CP.Utf8Refs[fld.Name]
+	// initialize the map of this object's fields, unless it came from the nursery with one
+	if obj.FieldTable == nil {
+		obj.FieldTable = make(map[string]object.Field)
+	}
 
-			fieldToAdd, err := createField(fld, k, classname)
-			if err != nil {
-				return nil, err
-			}
-			obj.FieldTable[fldName] = *fieldToAdd
-
-			// prepare the static fields, by inserting them w/ default values in Statics table
-			// See (https://docs.oracle.com/javase/specs/jvms/se21/html/jvms-5.html#jvms-5.4.2)
-			if fld.IsStatic {
-				staticName := classname + "." + fldName
-				_, ok := statics.Statics[staticName]
-				if !ok {
-					var fldValue any
-					fldType := []byte(k.Data.CP.Utf8Refs[fld.Desc])
-					switch fldType[0] {
-					case 'B', 'C', 'S', 'I', 'J', 'Z':
-						fldValue = int64(0)
-					case 'F', 'D':
-						fldValue = float64(0.00)
-					case 'L', '[':
-						fldValue = object.Null
-					}
-					statics.AddStatic(staticName, statics.Static{Type: string(fldType[0]), Value: fldValue})
-				}
-			}
-		} // loop through the fields if any
-		goto runInitializer
-	} // end of handling fields for objects w/ no superclasses other than java/lang/Object
-
-	// in the case of superclasses, we start at the topmost superclass
-	// and work our way down to the present class, adding fields to FieldTable.
-	// so we add the present class into position[0] and then loop through
-	// the slice of class names
 	superclasses = append([]string{classname}, superclasses...)
 	for j := len(superclasses) - 1; j >= 0; j-- {
-		superclassName := superclasses[j]
-		c := classloader.MethAreaFetch(superclassName)
+		declaringClass := superclasses[j]
+		c := classloader.MethAreaFetch(declaringClass)
 		if c == nil {
-			errMsg := fmt.Sprintf("InstantiateClass: MethAreaFetch(superclass: %s) failed", superclassName)
+			errMsg := fmt.Sprintf("InstantiateClass: MethAreaFetch(superclass: %s) failed", declaringClass)
 			trace.Error(errMsg)
 			return nil, errors.New(errMsg)
 		}
@@ -160,15 +133,16 @@ func InstantiateClass(classname string, frameStack *list.List) (any, error) {
 			f := c.Data.Fields[i]
 			name := c.Data.CP.Utf8Refs[f.Name]
 
-			fieldToAdd, err := createField(f, c, classname)
+			fieldToAdd, err := createField(f, c, declaringClass) // also adds statics to the Statics table
 			if err != nil {
 				return nil, err
 			}
 
-			// add the field to the field table for this object
-			obj.FieldTable[name] = *fieldToAdd
-		} // end of handling fields for one  class or superclass
-	} // end of handling fields for classes with superclasses other than Object
+			if !f.IsStatic {
+				obj.FieldTable[name] = *fieldToAdd
+			}
+		} // end of handling fields for one class or superclass
+	}
 
 runInitializer:
 
@@ -227,10 +201,9 @@ runInitializer:
 		}
 	}
 
-	// run intialization blocks
-	_, ok := k.Data.MethodTable["<clinit>()V"]
-	if ok && k.Data.ClInit == types.ClInitNotRun {
-		err := runInitializationBlock(k, superclasses, frameStack)
+	// run intialization blocks, including those of superclasses, if not already run
+	if k.Data.ClInit != types.ClInitRun {
+		err := runInitializationBlock(k, frameStack)
 		if err != nil {
 			errMsg := fmt.Sprintf("InstantiateClass: runInitializationBlock failed with %s.<clinit>()V", classname)
 			trace.Error(errMsg)
@@ -238,10 +211,11 @@ runInitializer:
 		}
 	}
 
-	return &obj, nil
+	return obj, nil
 }
 
-// creates a field for insertion into the object representation
+// creates a field for insertion into the object representation. Static fields
+// are added to the Statics table under the name of the declaring class, classname.
 func createField(f classloader.Field, k *classloader.Klass, classname string) (*object.Field, error) {
 	desc := k.Data.CP.Utf8Refs[f.Desc]
 
@@ -349,7 +323,7 @@ func loadThisClass(className string) error {
 	err = classloader.WaitForClassStatus(className)
 	if err != nil {
 		errMsg := fmt.Sprintf("loadThisClass: WaitForClassStatus(%s) failed, err: %v", className, err)
-		trace.Error(errMsg)
+		globals.GetGlobalRef().FuncThrowException(excNames.ClassNotLoadedException, errMsg)
 		return errors.New(errMsg) // needed for testing, which does not shutdown on failure
 	}
 	return nil
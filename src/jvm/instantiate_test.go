@@ -194,3 +194,58 @@ func TestLoadClassJavaLangObject(t *testing.T) {
 		t.Errorf("Got unexpected error from loadThisClass: %s", err.Error())
 	}
 }
+
+// a subclass object should hold the instance fields of its superclass, while the
+// static fields of both classes go to the statics table under the declaring class
+func TestInstantiateClassWithSuperclassFields(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+	statics.Statics = make(map[string]statics.Static)
+
+	superName := "pkg/FieldSuper"
+	cp := classloader.CPool{Utf8Refs: []string{"", "a", "I", "COUNT", "b", "J"}}
+	superKlass := classloader.Klass{Status: 'F', Loader: "testloader", CodeChecked: true, Data: &classloader.ClData{
+		Name:            superName,
+		SuperclassIndex: types.ObjectPoolStringIndex,
+		CP:              cp,
+		Fields: []classloader.Field{
+			{Name: 1, Desc: 2},
+			{Name: 3, Desc: 2, IsStatic: true, ConstValue: int64(7)},
+		},
+	}}
+	classloader.MethAreaInsert(superName, &superKlass)
+
+	subName := "pkg/FieldSub"
+	subKlass := classloader.Klass{Status: 'F', Loader: "testloader", CodeChecked: true, Data: &classloader.ClData{
+		Name:            subName,
+		SuperclassIndex: stringPool.GetStringIndex(&superName),
+		CP:              cp,
+		Fields:          []classloader.Field{{Name: 4, Desc: 5}},
+	}}
+	classloader.MethAreaInsert(subName, &subKlass)
+
+	anything, err := InstantiateClass(subName, nil)
+	if err != nil {
+		t.Fatalf("Got unexpected error from InstantiateClass: %s", err.Error())
+	}
+	obj := anything.(*object.Object)
+
+	if _, ok := obj.FieldTable["a"]; !ok {
+		t.Error("Expected inherited field a in the object")
+	}
+	if fld, ok := obj.FieldTable["b"]; !ok || fld.Fvalue != int64(0) {
+		t.Errorf("Expected field b with default value 0, got: %v", fld)
+	}
+	if _, ok := obj.FieldTable["COUNT"]; ok {
+		t.Error("Did not expect static field COUNT in the object")
+	}
+
+	static, ok := statics.Statics["pkg/FieldSuper.COUNT"]
+	if !ok || static.Value != int64(7) {
+		t.Errorf("Expected static pkg/FieldSuper.COUNT with ConstantValue 7, got: %v", static)
+	}
+	if _, ok = statics.Statics["pkg/FieldSub.COUNT"]; ok {
+		t.Error("Did not expect the static field to be recorded under the subclass")
+	}
+}
@@ -23,9 +23,11 @@ import (
 	"jacobin/src/globals"
 	"jacobin/src/object"
 	"jacobin/src/opcodes"
+	"jacobin/src/replay"
 	"jacobin/src/shutdown"
 	"jacobin/src/statics"
 	"jacobin/src/stringPool"
+	"jacobin/src/thread"
 	"jacobin/src/trace"
 	"jacobin/src/types"
 	"jacobin/src/util"
@@ -33,6 +35,7 @@ import (
 	"reflect"
 	"runtime/debug"
 	"strings"
+	"time"
 )
 
 // set up a DispatchTable with 203 slots that correspond to the bytecodes
@@ -182,10 +185,10 @@ var DispatchTable = [203]BytecodeFunc{
 	doL2f,             // L2F             0x89
 	doL2f,             // L2D             0x8A
 	doF2i,             // F2I             0x8B
-	doF2i,             // F2L             0x8C
+	doF2l,             // F2L             0x8C
 	doNothing,         // F2D             0x8D
-	doD2i,             // D2I             0x8E
-	doD2i,             // D2L             0x8F
+	doF2i,             // D2I             0x8E
+	doF2l,             // D2L             0x8F
 	doNothing,         // D2F             0x90
 	doI2b,             // I2B             0x91
 	doI2c,             // I2C             0x92
@@ -236,7 +239,7 @@ var DispatchTable = [203]BytecodeFunc{
 	doAthrow,          // ATHROW          0xBF
 	doCheckcast,       // CHECKCAST       0xC0
 	doInstanceof,      // INSTANCEOF      0xC1
-	doPop,             // MONITORENTER    0xC2 not implemented but won't throw exception
+	doMonitorenter,    // MONITORENTER    0xC2 not implemented but won't throw exception
 	doPop,             // MONITOREXIT     0xC3  "       "       "    "     "      '
 	doWide,            // WIDE            0xC4
 	doMultinewarray,   // MULTIANEWARRAY  0xC5
@@ -280,6 +283,13 @@ func interpret(fs *list.List) {
 		initializeDispatchTable()
 	}
 
+	// only an untrapped panic gets us here; it becomes an InternalError on this thread
+	defer func() {
+		if r := recover(); r != nil {
+			containPanic(fs, r)
+		}
+	}()
+
 	fr := fs.Front().Value.(*frames.Frame)
 	if fr.FrameStack == nil { // make sure we can reference the frame stack
 		fr.FrameStack = fs
@@ -295,32 +305,26 @@ func interpret(fs *list.List) {
 		}
 	}
 
+	pollSafepoint(fr) // method entry is a safepoint, see safepoint.go
+
+	countBytecodes := globals.GetGlobalRef().CountBytecodes // see bytecodeStats.go
 	for fr.PC < len(fr.Meth) {
 		if globals.TraceInst {
 			traceInfo := EmitTraceData(fr)
-			trace.Trace(traceInfo)
+			trace.Log(trace.JVM, trace.LevelTrace, fr.Thread, traceInfo)
 		}
 
+		var start time.Time
+		if countBytecodes {
+			start = time.Now()
+		}
+
+		var ret int
 		opcode := fr.Meth[fr.PC]
-		if opcode <= maxBytecode {
-			ret := DispatchTable[opcode](fr, 0)
-			switch ret {
-			case 0:
-				// exiting will either end program or call this function
-				// again for the frame at the top of the frame stack
-				return
-			case exceptions.ERROR_OCCURRED: // occurs only in tests
-				fs.Remove(fs.Front()) // pop the frame off, else we loop endlessly
-				return
-			case exceptions.RESUME_HERE: // continue processing from the present fr.PC
-				// This primarily occurs when an exception is caught. The catch resets
-				// the PC to the catch code to execute. So, we don't need any update to
-				// the PC. However, we have to refresh the current frame b/c the
-				// exception will refresh the topmost frame with any exception handling
-				fr = fs.Front().Value.(*frames.Frame)
-			default:
-				fr.PC += ret
-			}
+		if fr.Compiled != nil && fr.Compiled[fr.PC] != nil { // the method has been compiled, see jit.go
+			ret = fr.Compiled[fr.PC](fr, 0)
+		} else if opcode <= maxBytecode {
+			ret = DispatchTable[opcode](fr, 0)
 		} else {
 			errMsg := fmt.Sprintf("Invalid bytecode: %d", opcode)
 			status := exceptions.ThrowEx(excNames.ClassFormatError, errMsg, fr)
@@ -328,21 +332,43 @@ func interpret(fs *list.List) {
 				globals.InitGlobals("test")
 				return
 			}
+			continue
 		}
 
-		defer func() int {
-			// only an untrapped panic gets us here
-			if r := recover(); r != nil {
-				stack := string(debug.Stack())
-				glob := globals.GetGlobalRef()
-				glob.ErrorGoStack = stack
-				exceptions.ShowPanicCause(r)
-				exceptions.ShowFrameStack(fs)
-				exceptions.ShowGoStackTrace(nil)
-				return shutdown.Exit(shutdown.APP_EXCEPTION)
+		if countBytecodes {
+			recordBytecode(opcode, start)
+		}
+
+		switch ret {
+		case 0:
+			// exiting will either end program or call this function
+			// again for the frame at the top of the frame stack
+			return
+		case exceptions.ERROR_OCCURRED: // occurs only in tests
+			fs.Remove(fs.Front()) // pop the frame off, else we loop endlessly
+			return
+		case exceptions.RESUME_HERE: // continue processing from the present fr.PC
+			// This primarily occurs when an exception is caught. The catch resets
+			// the PC to the catch code to execute. So, we don't need any update to
+			// the PC. However, we have to refresh the current frame b/c the
+			// exception will refresh the topmost frame with any exception handling
+			if fs.Len() == 0 { // the exception ended the thread
+				return
+			}
+			fr = fs.Front().Value.(*frames.Frame)
+		default:
+			fr.PC += ret
+			if ret >= 0 {
+				continue
 			}
-			return shutdown.OK
-		}()
+			if fr.Compiled == nil && fr.JitMethod != nil {
+				fr.Compiled = jitBackEdge(fr) // on-stack replacement, see jit.go
+			}
+			pollSafepoint(fr) // a backward branch is a safepoint
+			if greenThreadsActive.Load() && greenThreadShouldYield(fr) {
+				return // a backward branch is a yield point for green threads, see greenThreads.go
+			}
+		}
 	}
 }
 
@@ -474,14 +500,9 @@ func doIaload(fr *frames.Frame, _ int64) int {
 	case *object.Object:
 		obj := ref.(*object.Object)
 		if object.IsNull(obj) {
-			globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
 			errMsg := fmt.Sprintf("in %s.%s, I/C/S/LALOAD: Invalid null reference to an array",
 				util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName)
-			status := exceptions.ThrowEx(excNames.NullPointerException, errMsg, fr)
-			if status != exceptions.Caught {
-				return exceptions.ERROR_OCCURRED // applies only if in test
-			}
-			return exceptions.RESUME_HERE // caught
+			return exceptions.ThrowNPE(fr, errMsg)
 		}
 		array = obj.FieldTable["value"].Fvalue.([]int64)
 	case []int64:
@@ -524,14 +545,9 @@ func doFaload(fr *frames.Frame, _ int64) int {
 	case *object.Object:
 		obj := ref.(*object.Object)
 		if object.IsNull(obj) {
-			globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
 			errMsg := fmt.Sprintf("in %s.%s, D/FALOAD: Invalid object pointer (nil)",
 				util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName)
-			status := exceptions.ThrowEx(excNames.NullPointerException, errMsg, fr)
-			if status != exceptions.Caught {
-				return exceptions.ERROR_OCCURRED // applies only if in test
-			}
-			return exceptions.RESUME_HERE // caught
+			return exceptions.ThrowNPE(fr, errMsg)
 		}
 		array = (*obj).FieldTable["value"].Fvalue.([]float64)
 	default:
@@ -565,28 +581,33 @@ func doFaload(fr *frames.Frame, _ int64) int {
 func doAaload(fr *frames.Frame, _ int64) int {
 	index := pop(fr).(int64)
 	rAref := pop(fr) // the array object. Can't be cast to *Object b/c might be nil
-	if rAref == nil {
+	if object.IsNull(rAref) {
 		errMsg := fmt.Sprintf("in %s.%s, AALOAD: Invalid (null) reference to an array",
 			util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName)
-		status := exceptions.ThrowEx(excNames.NullPointerException, errMsg, fr)
+		return exceptions.ThrowNPE(fr, errMsg)
+	}
+
+	array, ok := (rAref.(*object.Object)).FieldTable["value"].Fvalue.([]*object.Object)
+	if !ok {
+		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+		errMsg := fmt.Sprintf("in %s.%s, AALOAD: not an array of references",
+			util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName)
+		status := exceptions.ThrowEx(excNames.VerifyError, errMsg, fr)
 		if status != exceptions.Caught {
 			return exceptions.ERROR_OCCURRED // applies only if in test
 		}
 		return exceptions.RESUME_HERE // caught
 	}
 
-	fvalue := (rAref.(*object.Object)).FieldTable["value"].Fvalue
-	array := fvalue.([]*object.Object)
-
 	size := int64(len(array))
-	if index >= size {
-		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+	if index < 0 || index >= size {
 		errMsg := fmt.Sprintf("in %s.%s, AALOAD: Invalid array subscript: %d",
 			util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName, index)
 		status := exceptions.ThrowEx(excNames.ArrayIndexOutOfBoundsException, errMsg, fr)
 		if status != exceptions.Caught {
 			return exceptions.ERROR_OCCURRED // applies only if in test
 		}
+		return exceptions.RESUME_HERE // caught
 	}
 
 	var value = array[index]
@@ -599,14 +620,9 @@ func doBaload(fr *frames.Frame, _ int64) int {
 	index := pop(fr).(int64)
 	ref := pop(fr) // the array object
 	if ref == nil || ref == object.Null {
-		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
 		errMsg := fmt.Sprintf("in %s.%s, BALOAD: Invalid (null) reference to an array",
 			util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName)
-		status := exceptions.ThrowEx(excNames.NullPointerException, errMsg, fr)
-		if status != exceptions.Caught {
-			return exceptions.ERROR_OCCURRED // applies only if in test
-		}
-		return exceptions.RESUME_HERE // caught
+		return exceptions.ThrowNPE(fr, errMsg)
 	}
 
 	var bAref *object.Object
@@ -633,7 +649,6 @@ func doBaload(fr *frames.Frame, _ int64) int {
 		pushValue = int64(val)
 		pushValueReady = true
 	default:
-		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
 		errMsg := fmt.Sprintf("in %s.%s, BALOAD: Invalid  type of object ref: %T",
 			util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName, ref)
 		status := exceptions.ThrowEx(excNames.InvalidTypeException, errMsg, fr)
@@ -747,14 +762,9 @@ func doIastore(fr *frames.Frame, _ int64) int {
 	case *object.Object:
 		obj := ref.(*object.Object)
 		if object.IsNull(obj) {
-			globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
 			errMsg := fmt.Sprintf("in %s.%s, I/C/S/LASTORE: Invalid (null) reference to an array",
 				util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName)
-			status := exceptions.ThrowEx(excNames.NullPointerException, errMsg, fr)
-			if status != exceptions.Caught {
-				return exceptions.ERROR_OCCURRED // applies only if in test
-			}
-			return exceptions.RESUME_HERE // caught
+			return exceptions.ThrowNPE(fr, errMsg)
 		}
 		fld := obj.FieldTable["value"]
 		if fld.Ftype != types.IntArray && fld.Ftype != types.LongArray && fld.Ftype != types.CharArray && fld.Ftype != types.ShortArray {
@@ -806,14 +816,9 @@ func doFastore(fr *frames.Frame, _ int64) int {
 	case *object.Object:
 		obj := ref.(*object.Object)
 		if object.IsNull(obj) {
-			globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
 			errMsg := fmt.Sprintf("in %s.%s, F/DASTORE: Invalid (null) reference to an array",
 				util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName)
-			status := exceptions.ThrowEx(excNames.NullPointerException, errMsg, fr)
-			if status != exceptions.Caught {
-				return exceptions.ERROR_OCCURRED // applies only if in test
-			}
-			return exceptions.RESUME_HERE // caught
+			return exceptions.ThrowNPE(fr, errMsg)
 		}
 		fld := obj.FieldTable["value"]
 		if fld.Ftype != types.FloatArray && fld.Ftype != types.DoubleArray {
@@ -862,15 +867,10 @@ func doAastore(fr *frames.Frame, _ int64) int {
 	index := pop(fr).(int64)             // index into the array
 	arrayRef := pop(fr).(*object.Object) // ptr to the array object
 
-	if arrayRef == nil {
-		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+	if object.IsNull(arrayRef) {
 		errMsg := fmt.Sprintf("in %s.%s, AASTORE: Invalid (null) reference to an array",
 			util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName)
-		status := exceptions.ThrowEx(excNames.NullPointerException, errMsg, fr)
-		if status != exceptions.Caught {
-			return exceptions.ERROR_OCCURRED // applies only if in test
-		}
-		return exceptions.RESUME_HERE // caught
+		return exceptions.ThrowNPE(fr, errMsg)
 	}
 
 	arrayObj := *arrayRef
@@ -878,7 +878,6 @@ func doAastore(fr *frames.Frame, _ int64) int {
 
 	if !strings.HasPrefix(rawArrayObj.Ftype, types.RefArray) &&
 		!strings.HasPrefix(rawArrayObj.Ftype, types.MultiArray) {
-		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
 		errMsg := fmt.Sprintf("in %s.%s, AASTORE: field type must start with '[L', got %s",
 			util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName, rawArrayObj.Ftype)
 		status := exceptions.ThrowEx(excNames.ArrayStoreException, errMsg, fr)
@@ -891,7 +890,7 @@ func doAastore(fr *frames.Frame, _ int64) int {
 	// get pointer to the actual array
 	rawArray := rawArrayObj.Fvalue.([]*object.Object)
 	size := int64(len(rawArray))
-	if index >= size {
+	if index < 0 || index >= size {
 		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
 		errMsg := fmt.Sprintf("in %s.%s, AASTORE: array size is %d but array index is %d",
 			util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName, size, index)
@@ -902,6 +901,24 @@ func doAastore(fr *frames.Frame, _ int64) int {
 		return exceptions.RESUME_HERE // caught
 	}
 
+	// arrays are covariant, so the array's type is checked at run time: a String[]
+	// passed as an Object[] can't hold an Integer
+	if !object.IsNull(value) {
+		component := classloader.ArrayComponentType(rawArrayObj.Ftype)
+		if !classloader.IsAssignableToComponent(classloader.TypeNameOf(value), component) {
+			globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+			errMsg := fmt.Sprintf("in %s.%s, AASTORE: %s cannot be stored in an array of %s",
+				util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName,
+				util.ConvertInternalClassNameToUserFormat(classloader.TypeNameOf(value)),
+				util.ConvertInternalClassNameToUserFormat(component))
+			status := exceptions.ThrowEx(excNames.ArrayStoreException, errMsg, fr)
+			if status != exceptions.Caught {
+				return exceptions.ERROR_OCCURRED // applies only if in test
+			}
+			return exceptions.RESUME_HERE // caught
+		}
+	}
+
 	rawArray[index] = value
 	return 1
 }
@@ -916,14 +933,9 @@ func doBastore(fr *frames.Frame, _ int64) int {
 	case *object.Object:
 		obj := arrayRef.(*object.Object)
 		if object.IsNull(obj) {
-			globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
 			errMsg := fmt.Sprintf("in %s.%s, BASTORE: Invalid (null) reference to an array",
 				util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName)
-			status := exceptions.ThrowEx(excNames.NullPointerException, errMsg, fr)
-			if status != exceptions.Caught {
-				return exceptions.ERROR_OCCURRED // applies only if in test
-			}
-			return exceptions.RESUME_HERE // caught
+			return exceptions.ThrowNPE(fr, errMsg)
 		}
 		fld := obj.FieldTable["value"]
 		if fld.Ftype != types.ByteArray {
@@ -1165,28 +1177,34 @@ func doIdiv(fr *frames.Frame, _ int64) int {
 	val1 := pop(fr).(int64) // divisor
 	val2 := pop(fr).(int64) // dividend
 	if val1 == 0 {
-		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
-		errInfo := fmt.Sprintf("IDIV or LDIV: division by zero -- %d/0", val2)
-		if globals.GetGlobalRef().StrictJDK { // use the HotSpot JDK's error message instead of ours
-			errInfo = "/ by zero"
-		}
-		errMsg := fmt.Sprintf("in %s.%s %s",
-			util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName, errInfo)
-		status := exceptions.ThrowEx(excNames.ArithmeticException, errMsg, fr)
-		if status != exceptions.Caught {
-			return exceptions.ERROR_OCCURRED // applies only if in test
-		} else {
-			// Make the current frame the caught exception frame.
-			fs := fr.FrameStack
-			fr = fs.Front().Value.(*frames.Frame)
-			return 0 // PC is already set up so indicate that to caller.
-		}
+		return throwDivisionByZero(fr, val2)
+	}
+	if fr.Meth[fr.PC] == opcodes.IDIV {
+		// Integer.MIN_VALUE / -1 overflows, so it wraps around to Integer.MIN_VALUE.
+		// (Go's int64 division does the same for Long.MIN_VALUE / -1.)
+		push(fr, int64(int32(val2/val1)))
 	} else {
 		push(fr, val2/val1)
 	}
 	return 1
 }
 
+// throws the ArithmeticException of IDIV, LDIV, IREM, and LREM when the divisor is zero
+func throwDivisionByZero(fr *frames.Frame, dividend int64) int {
+	globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+	errMsg := fmt.Sprintf("/ by zero in %s.%s (%s: %d/0)",
+		util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName,
+		opcodes.BytecodeNames[fr.Meth[fr.PC]], dividend)
+	if globals.GetGlobalRef().StrictJDK { // use the HotSpot JDK's error message instead of ours
+		errMsg = "/ by zero"
+	}
+	status := exceptions.ThrowEx(excNames.ArithmeticException, errMsg, fr)
+	if status != exceptions.Caught {
+		return exceptions.ERROR_OCCURRED // applies only if in test
+	}
+	return exceptions.RESUME_HERE // caught
+}
+
 // 0x6E, 0x6F FDIV, DDIV floating-point division
 func doFdiv(fr *frames.Frame, _ int64) int {
 	val1 := pop(fr).(float64)
@@ -1205,31 +1223,15 @@ func doFdiv(fr *frames.Frame, _ int64) int {
 	return 1
 }
 
-// 0x70, 0x71 IREM, LREM get remainder of integer division
+// 0x70, 0x71 IREM, LREM get remainder of integer division. The remainder of MIN_VALUE
+// divided by -1 is 0 for both ints and longs.
 func doIrem(fr *frames.Frame, _ int64) int {
 	val2 := pop(fr).(int64)
 	val1 := pop(fr).(int64)
 	if val2 == 0 {
-		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
-		errInfo := fmt.Sprintf("IREM or LREM: division by zero -- %d/0", val2)
-		if globals.GetGlobalRef().StrictJDK { // use the HotSpot JDK's error message instead of ours
-			errInfo = "/ by zero"
-		}
-		errMsg := fmt.Sprintf("in %s.%s %s",
-			util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName, errInfo)
-		status := exceptions.ThrowEx(excNames.ArithmeticException, errMsg, fr)
-		if status != exceptions.Caught {
-			return exceptions.ERROR_OCCURRED // applies only if in test
-		} else {
-			// Make the current frame the caught exception frame.
-			fs := fr.FrameStack
-			fr = fs.Front().Value.(*frames.Frame)
-			return 0 // PC is already set up so indicate that to caller.
-		}
-	} else {
-		res := val1 % val2
-		push(fr, res)
+		return throwDivisionByZero(fr, val1)
 	}
+	push(fr, val1%val2)
 	return 1
 }
 
@@ -1344,7 +1346,7 @@ func doIinc(fr *frames.Frame, _ int64) int {
 	var PCtoSkip int
 	if fr.WideInEffect { // if wide is in effect, index  and increment are two bytes wide, otherwise one byte each
 		index = (int(fr.Meth[fr.PC+1]) * 256) + int(fr.Meth[fr.PC+2])
-		increment = int64(fr.Meth[fr.PC+3])*256 + int64(fr.Meth[fr.PC+4])
+		increment = int64(int16(uint16(fr.Meth[fr.PC+3])<<8 | uint16(fr.Meth[fr.PC+4]))) // signed, like the 1-byte form
 		PCtoSkip = 4
 		fr.WideInEffect = false
 	} else {
@@ -1352,19 +1354,15 @@ func doIinc(fr *frames.Frame, _ int64) int {
 		increment = byteToInt64(fr.Meth[fr.PC+2])
 		PCtoSkip = 2
 	}
+	iinc(fr, index, increment)
+	return PCtoSkip + 1
+}
 
-	// shoehorn the result into Java's 32-bit int
+// adds increment to the int in local index
+func iinc(fr *frames.Frame, index int, increment int64) {
+	// shoehorn the result into Java's 32-bit int, which wraps around on overflow
 	orig := fr.Locals[index].(int64)
-	chkInt32 := orig + increment
-	if chkInt32 > math.MaxInt32 {
-		chkInt32 = math.MinInt32 + (increment - 1)
-	} else {
-		if chkInt32 < math.MinInt32 {
-			chkInt32 = math.MaxInt32 + (increment + 1)
-		}
-	}
-	fr.Locals[index] = chkInt32
-	return PCtoSkip + 1
+	fr.Locals[index] = int64(int32(orig + increment))
 }
 
 // 0x86, 0x87 I2L, I2F convert int to float/double
@@ -1381,17 +1379,37 @@ func doL2f(fr *frames.Frame, _ int64) int {
 	return 1
 }
 
-// 0x8B, 0x8C F2I, F2L convert float to int/long
+// 0x8B, 0x8E F2I, D2I convert float/double to int. Per the JVMS, NaN converts to 0 and
+// values beyond the range of int convert to the nearest int.
 func doF2i(fr *frames.Frame, _ int64) int {
 	floatVal := pop(fr).(float64)
-	push(fr, int64(math.Trunc(floatVal)))
+	switch {
+	case math.IsNaN(floatVal):
+		push(fr, int64(0))
+	case floatVal >= math.MaxInt32:
+		push(fr, int64(math.MaxInt32))
+	case floatVal <= math.MinInt32:
+		push(fr, int64(math.MinInt32))
+	default:
+		push(fr, int64(math.Trunc(floatVal)))
+	}
 	return 1
 }
 
-// 0x8E, 0x8F D2I, D2L double to int/long
-func doD2i(fr *frames.Frame, _ int64) int {
-	doubleVal := pop(fr).(float64)
-	push(fr, int64(math.Trunc(doubleVal)))
+// 0x8C, 0x8F F2L, D2L convert float/double to long, which like F2I, converts NaN to 0
+// and values beyond the range of long to the nearest long
+func doF2l(fr *frames.Frame, _ int64) int {
+	floatVal := pop(fr).(float64)
+	switch {
+	case math.IsNaN(floatVal):
+		push(fr, int64(0))
+	case floatVal >= math.MaxInt64: // 2^63, as MaxInt64 can't be represented exactly
+		push(fr, int64(math.MaxInt64))
+	case floatVal <= math.MinInt64:
+		push(fr, int64(math.MinInt64))
+	default:
+		push(fr, int64(math.Trunc(floatVal)))
+	}
 	return 1
 }
 
@@ -1444,7 +1462,7 @@ func doFcmpl(fr *frames.Frame, _ int64) int {
 	value1 := pop(fr).(float64)
 	if math.IsNaN(value1) || math.IsNaN(value2) {
 		if fr.Meth[fr.PC] == opcodes.FCMPG ||
-			fr.Meth[fr.PC] == opcodes.DCMPG { // TODO: check this points to right byecode
+			fr.Meth[fr.PC] == opcodes.DCMPG {
 			push(fr, int64(1))
 		} else {
 			push(fr, int64(-1))
@@ -1769,12 +1787,14 @@ func doIreturn(fr *frames.Frame, _ int64) int {
 	f := fr.FrameStack.Front().Next().Value.(*frames.Frame)
 	push(f, valToReturn)
 	fr.FrameStack.Remove(fr.FrameStack.Front())
+	frames.ReleaseFrame(fr)
 	return 0
 }
 
 // 0xB1 RETURN return from void method
 func doReturn(fr *frames.Frame, _ int64) int {
 	fr.FrameStack.Remove(fr.FrameStack.Front())
+	frames.ReleaseFrame(fr)
 	return 0
 }
 
@@ -1804,6 +1824,12 @@ func doGetStatic(fr *frames.Frame, _ int64) int {
 		EmitTraceFieldID("GETSTATIC", fieldName)
 	}
 
+	// a static final constant that was previously resolved can be pushed directly
+	if field.ConstValue != nil {
+		push(fr, field.ConstValue)
+		return 3 // 2 for the CP slot + 1 for the next bytecode
+	}
+
 	// was this static field previously loaded? Is so, get its location and move on.
 	prevLoaded, ok := statics.Statics[fieldName]
 	if !ok { // if field is not already loaded, then
@@ -1835,6 +1861,27 @@ func doGetStatic(fr *frames.Frame, _ int64) int {
 		return exceptions.RESUME_HERE // caught
 	}
 
+	// the first use of a static field initializes its class (JVMS 5.5), if that
+	// was not already done when the class was loaded above
+	if err := initializeIfNeeded(className, fr.FrameStack); err != nil {
+		return throwClassInitError("GETSTATIC", err, fr)
+	}
+	prevLoaded = statics.Statics[fieldName] // <clinit> may have set the field's value
+
+	// now that the class is loaded, make sure this class may access the field
+	if err := classloader.CheckFieldRefAccess(fr.ClName, CP, CPentry.Slot); err != nil {
+		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+		errMsg := "GETSTATIC: " + err.Error()
+		status := exceptions.ThrowEx(excNames.IllegalAccessError, errMsg, fr)
+		if status != exceptions.Caught {
+			return exceptions.ERROR_OCCURRED // applies only if in test
+		}
+		return exceptions.RESUME_HERE // caught
+	}
+
+	// if the field is a constant, inline its value into the field ref for next time
+	classloader.CacheConstantStatic(CP, CPentry.Slot)
+
 	switch prevLoaded.Value.(type) {
 	case bool:
 		// a boolean, which might
@@ -1918,6 +1965,22 @@ func doPutStatic(fr *frames.Frame, _ int64) int {
 		return exceptions.ERROR_OCCURRED
 	}
 
+	// the first use of a static field initializes its class (JVMS 5.5)
+	if err := initializeIfNeeded(className, fr.FrameStack); err != nil {
+		return throwClassInitError("PUTSTATIC", err, fr)
+	}
+
+	// now that the class is loaded, make sure this class may access the field
+	if err := classloader.CheckFieldRefAccess(fr.ClName, CP, CPentry.Slot); err != nil {
+		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+		errMsg := "PUTSTATIC: " + err.Error()
+		status := exceptions.ThrowEx(excNames.IllegalAccessError, errMsg, fr)
+		if status != exceptions.Caught {
+			return exceptions.ERROR_OCCURRED // applies only if in test
+		}
+		return exceptions.RESUME_HERE // caught
+	}
+
 	var value interface{}
 	switch prevLoaded.Type {
 	case types.Bool:
@@ -2030,7 +2093,13 @@ func doGetfield(fr *frames.Frame, _ int64) int {
 	// Check reference for a nil pointer.
 	if object.IsNull(ref) {
 		errMsg := fmt.Sprintf("GETFIELD: Null object reference, fieldName: %s.%s", fr.ClName, fieldName)
-		status := exceptions.ThrowEx(excNames.NullPointerException, errMsg, fr)
+		return exceptions.ThrowNPE(fr, errMsg)
+	}
+
+	// make sure this class may access the field
+	if err := classloader.CheckFieldRefAccess(fr.ClName, CP, fieldEntry.Slot); err != nil {
+		errMsg := "GETFIELD: " + err.Error()
+		status := exceptions.ThrowEx(excNames.IllegalAccessError, errMsg, fr)
 		if status != exceptions.Caught {
 			return exceptions.ERROR_OCCURRED // applies only if in test
 		}
@@ -2134,6 +2203,12 @@ func doPutfield(fr *frames.Frame, _ int64) int {
 		}
 	}
 
+	if object.IsNull(ref) {
+		errMsg := fmt.Sprintf("PUTFIELD: Null object reference, fieldName: %s.%s",
+			fr.ClName, CP.FieldRefs[fieldEntry.Slot].FldName)
+		return exceptions.ThrowNPE(fr, errMsg)
+	}
+
 	// Get Object struct.
 	obj := *(ref.(*object.Object))
 
@@ -2158,6 +2233,17 @@ func doPutfield(fr *frames.Frame, _ int64) int {
 			EmitTraceFieldID("PUTFIELD", fieldName)
 		}
 
+		// make sure this class may access the field
+		if err := classloader.CheckFieldRefAccess(fr.ClName, CP, fieldEntry.Slot); err != nil {
+			globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+			errMsg := "PUTFIELD: " + err.Error()
+			status := exceptions.ThrowEx(excNames.IllegalAccessError, errMsg, fr)
+			if status != exceptions.Caught {
+				return exceptions.ERROR_OCCURRED // applies only if in test
+			}
+			return exceptions.RESUME_HERE // caught
+		}
+
 		objField, ok := obj.FieldTable[fieldName]
 		if !ok {
 			errMsg := fmt.Sprintf("PUTFIELD: In trying for a superclass field, %s is not present in object of class %s",
@@ -2188,12 +2274,14 @@ func doPutfield(fr *frames.Frame, _ int64) int {
 
 // 0xB6 INVOKEVIRTUAL
 func doInvokeVirtual(fr *frames.Frame, _ int64) int {
-	var err error
 	CPslot := (int(fr.Meth[fr.PC+1]) * 256) + int(fr.Meth[fr.PC+2]) // next 2 bytes point to CP entry
 	CP := fr.CP.(*classloader.CPool)
 
 	className, methodName, methodType, fqn :=
 		classloader.GetMethInfoFromCPmethref(CP, CPslot)
+	if strings.HasPrefix(className, types.Array) { // arrays inherit their methods, such as clone(), from Object
+		className = types.ObjectClassName
+	}
 	/* // JACOBIN-575 reactivate this code when ready to complete this task
 	k := classloader.MethAreaFetch(className) // we know the class is already loaded
 	methListEntry, ok := k.Data.MethodList[methodName+methodType]
@@ -2228,10 +2316,9 @@ func doInvokeVirtual(fr *frames.Frame, _ int64) int {
 	}
 	*/
 
-	mtEntry := classloader.MTable[className+"."+methodName+methodType]
-	if mtEntry.Meth == nil { // if the method is not in the method table, search classes or superclasses
-		mtEntry, err = classloader.FetchMethodAndCP(className, methodName, methodType)
-	}
+	// the method is looked up in the MTable, and if need be in the superclasses, and
+	// the result is memoized in the CP so subsequent calls skip these lookups
+	mtEntry, err := classloader.ResolveMethodRef(CP, CPslot)
 
 	if err != nil || mtEntry.Meth == nil { // the method is not in the superclasses, so check interfaces
 		klass := classloader.MethAreaFetch(className)
@@ -2258,7 +2345,13 @@ func doInvokeVirtual(fr *frames.Frame, _ int64) int {
 		}
 	}
 
-	// if we got here, we have a method to call in mtEntry.Meth
+	// if we got here, we have a method to call in mtEntry.Meth, but it can't be
+	// invoked on a null object. The objectRef is below the arguments on the op stack.
+	argCount := len(util.ParseIncomingParamsFromMethTypeString(methodType))
+	if fr.TOS >= argCount && object.IsNull(fr.OpStack[fr.TOS-argCount]) {
+		errMsg := "INVOKEVIRTUAL: object whose method is invoked is null: " + fqn
+		return exceptions.ThrowNPE(fr, errMsg)
+	}
 
 	// if we have a native function (here, one implemented in golang, rather than Java),
 	// then follow the JVM spec and push the objectRef and the parameters to the function
@@ -2280,7 +2373,7 @@ func doInvokeVirtual(fr *frames.Frame, _ int64) int {
 
 		if globals.TraceInst {
 			infoMsg := fmt.Sprintf("G-function: class=%s, meth=%s%s", className, methodName, methodType)
-			trace.Trace(infoMsg)
+			trace.Log(trace.JVM, trace.LevelTrace, fr.Thread, infoMsg)
 		}
 
 		ret := gfunction.RunGfunction(
@@ -2348,6 +2441,16 @@ func doInvokeVirtual(fr *frames.Frame, _ int64) int {
 			}
 		}
 
+		// make sure this class may invoke the method
+		if err := classloader.CheckMethodRefAccess(fr.ClName, CP, CPslot, m.AccessFlags); err != nil {
+			globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+			errMsg := "INVOKEVIRTUAL: " + err.Error()
+			status := exceptions.ThrowEx(excNames.IllegalAccessError, errMsg, fr)
+			if status != exceptions.Caught {
+				return exceptions.ERROR_OCCURRED // applies only if in test
+			}
+			return exceptions.RESUME_HERE // caught
+		}
 		fram, err := createAndInitNewFrame(
 			className, methodName, methodType, &m, true, fr)
 		if err != nil {
@@ -2360,13 +2463,34 @@ func doInvokeVirtual(fr *frames.Frame, _ int64) int {
 			return exceptions.RESUME_HERE // caught
 		}
 
-		fr.PC += 3                    // 2 for PC slot, move to next bytecode before exiting
-		fr.FrameStack.PushFront(fram) // push the new frame
-		return 0
+		return pushInvokedFrame(fr, fram, 3) // 3 moves the PC past this bytecode for when we return
 	}
 	return exceptions.ERROR_OCCURRED // in theory, unreachable
 }
 
+// pushes the frame of a Java method invoked by the bytecode at fr.PC, which is
+// bytecodeLen bytes long, and advances the PC past the bytecode. If the frame stack
+// is at its maximum depth, a StackOverflowError is thrown instead, at the present PC.
+// Returns the value to return from the invoke bytecode.
+func pushInvokedFrame(fr *frames.Frame, fram *frames.Frame, bytecodeLen int) int {
+	if err := frames.PushFrame(fr.FrameStack, fram); err != nil {
+		frames.ReleaseFrame(fram)
+		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+		errMsg := fmt.Sprintf("in %s.%s, frame stack depth exceeds %d frames (see -Xss)",
+			util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName, fr.FrameStack.Len())
+		if globals.GetGlobalRef().StrictJDK { // the JDK's StackOverflowError has no message
+			errMsg = ""
+		}
+		status := exceptions.ThrowEx(excNames.StackOverflowError, errMsg, fr)
+		if status != exceptions.Caught {
+			return exceptions.ERROR_OCCURRED // applies only if in test
+		}
+		return exceptions.RESUME_HERE // caught
+	}
+	fr.PC += bytecodeLen
+	return 0
+}
+
 // OxB7 INVOKESPECIAL
 func doInvokespecial(fr *frames.Frame, _ int64) int {
 	var className, methodName, methodType, fqn string
@@ -2390,7 +2514,7 @@ func doInvokespecial(fr *frames.Frame, _ int64) int {
 		return 3 // 2 for the CPslot + 1 for next bytecode
 	}
 
-	mtEntry, err := classloader.FetchMethodAndCP(className, methodName, methodType)
+	mtEntry, err := classloader.ResolveMethodRef(CP, CPslot)
 	if err != nil || mtEntry.Meth == nil {
 		// TODO: search the classpath and retry
 		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
@@ -2419,7 +2543,7 @@ func doInvokespecial(fr *frames.Frame, _ int64) int {
 
 		if globals.TraceInst {
 			infoMsg := fmt.Sprintf("G-function: class=%s, meth=%s%s", className, methodName, methodType)
-			trace.Trace(infoMsg)
+			trace.Log(trace.JVM, trace.LevelTrace, fr.Thread, infoMsg)
 		}
 
 		ret := gfunction.RunGfunction(
@@ -2455,6 +2579,16 @@ func doInvokespecial(fr *frames.Frame, _ int64) int {
 			}
 			return exceptions.RESUME_HERE // caught
 		}
+		// make sure this class may invoke the method
+		if err := classloader.CheckMethodRefAccess(fr.ClName, CP, CPslot, m.AccessFlags); err != nil {
+			globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+			errMsg := "INVOKESPECIAL: " + err.Error()
+			status := exceptions.ThrowEx(excNames.IllegalAccessError, errMsg, fr)
+			if status != exceptions.Caught {
+				return exceptions.ERROR_OCCURRED // applies only if in test
+			}
+			return exceptions.RESUME_HERE // caught
+		}
 		fram, err := createAndInitNewFrame(className, methodName, methodType, &m, true, fr)
 		if err != nil {
 			globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
@@ -2466,9 +2600,7 @@ func doInvokespecial(fr *frames.Frame, _ int64) int {
 			return exceptions.RESUME_HERE // caught
 		}
 
-		fr.PC += 3                    // point to the next bytecode for when we return from the invoked method.
-		fr.FrameStack.PushFront(fram) // push the new frame
-		return 0
+		return pushInvokedFrame(fr, fram, 3) // 3 moves the PC past this bytecode for when we return
 	}
 	return exceptions.ERROR_OCCURRED // in theory, unreachable
 }
@@ -2487,8 +2619,11 @@ func doInvokestatic(fr *frames.Frame, _ int64) int {
 	} else {
 		className, methodName, methodType, fqn = // fqn is the fully qualified name of the method
 			classloader.GetMethInfoFromCPmethref(CP, CPslot)
+		if intrinsic, ok := intrinsics[fqn]; ok { // see intrinsics.go
+			return intrinsic(fr)
+		}
 	}
-	mtEntry, err := classloader.FetchMethodAndCP(className, methodName, methodType)
+	mtEntry, err := classloader.ResolveMethodRef(CP, CPslot)
 	if err != nil || mtEntry.Meth == nil {
 		// TODO: search the classpath and retry
 		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
@@ -2504,16 +2639,10 @@ func doInvokestatic(fr *frames.Frame, _ int64) int {
 	// make sure that its static intializer block (if any) has been run. At this point,
 	// all we know is that the class exists and has been loaded.
 	k := classloader.MethAreaFetch(className)
-	if k.Data.ClInit == types.ClInitNotRun {
-		err = runInitializationBlock(k, nil, fr.FrameStack)
+	if k.Data.ClInit != types.ClInitRun {
+		err = runInitializationBlock(k, fr.FrameStack)
 		if err != nil {
-			globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
-			errMsg := fmt.Sprintf("INVOKESTATIC: error running initializer block in %s", fqn)
-			status := exceptions.ThrowEx(excNames.ClassNotLoadedException, errMsg, fr)
-			if status != exceptions.Caught {
-				return exceptions.ERROR_OCCURRED // applies only if in test
-			}
-			return exceptions.RESUME_HERE // caught
+			return throwClassInitError("INVOKESTATIC", err, fr)
 		}
 	}
 
@@ -2527,7 +2656,7 @@ func doInvokestatic(fr *frames.Frame, _ int64) int {
 
 		if globals.TraceInst {
 			infoMsg := fmt.Sprintf("G-function: class=%s, meth=%s%s", className, methodName, methodType)
-			trace.Trace(infoMsg)
+			trace.Log(trace.JVM, trace.LevelTrace, fr.Thread, infoMsg)
 		}
 
 		ret := gfunction.RunGfunction(mtEntry, fr.FrameStack, className, methodName, methodType, &params, false, MainThread.Trace)
@@ -2557,6 +2686,16 @@ func doInvokestatic(fr *frames.Frame, _ int64) int {
 			}
 			return exceptions.RESUME_HERE // caught
 		}
+		// make sure this class may invoke the method
+		if err := classloader.CheckMethodRefAccess(fr.ClName, CP, CPslot, m.AccessFlags); err != nil {
+			globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+			errMsg := "INVOKESTATIC: " + err.Error()
+			status := exceptions.ThrowEx(excNames.IllegalAccessError, errMsg, fr)
+			if status != exceptions.Caught {
+				return exceptions.ERROR_OCCURRED // applies only if in test
+			}
+			return exceptions.RESUME_HERE // caught
+		}
 		fram, err := createAndInitNewFrame(
 			className, methodName, methodType, &m, false, fr)
 		if err != nil {
@@ -2569,9 +2708,7 @@ func doInvokestatic(fr *frames.Frame, _ int64) int {
 			return exceptions.RESUME_HERE // caught
 		}
 
-		fr.PC += 3                    // 2 == initial PC advance in this bytecode + 1 for next bytecode
-		fr.FrameStack.PushFront(fram) // push the new frame
-		return 0
+		return pushInvokedFrame(fr, fram, 3) // 3 moves the PC past this bytecode for when we return
 	}
 	return exceptions.ERROR_OCCURRED // in theory, unreachable code
 }
@@ -2621,22 +2758,21 @@ func doInvokeinterface(fr *frames.Frame, _ int64) int {
 	// be passed to the method.
 	// The objRef object has previously been instantiated and its constructor called.
 	objRef := fr.OpStack[fr.TOS-int(count)+1]
-	if objRef == nil {
+	if object.IsNull(objRef) {
 		errMsg := fmt.Sprintf("INVOKEINTERFACE: object whose method, %s, is invoked is null",
 			interfaceName+interfaceMethodName+interfaceMethodType)
-		status := exceptions.ThrowEx(excNames.NullPointerException, errMsg, fr)
-		if status != exceptions.Caught {
-			return exceptions.ERROR_OCCURRED // applies only if in test
-		}
-		return exceptions.RESUME_HERE // caught
+		return exceptions.ThrowNPE(fr, errMsg)
 	}
 
-	// get the name of the objectRef's class, and make sure it's loaded
+	// get the name of the objectRef's class, and make sure it's loaded. (A proxy class
+	// is never loaded: it's put in the method area when it's created.)
 	objRefClassName := *(stringPool.GetStringPointer(objRef.(*object.Object).KlassName))
-	if err := classloader.LoadClassFromNameOnly(objRefClassName); err != nil {
-		// in this case, LoadClassFromNameOnly() will have already thrown the exception
-		if globals.JacobinHome() == "test" {
-			return exceptions.ERROR_OCCURRED // applies only if in test
+	if classloader.MethAreaFetch(objRefClassName) == nil {
+		if err := classloader.LoadClassFromNameOnly(objRefClassName); err != nil {
+			// in this case, LoadClassFromNameOnly() will have already thrown the exception
+			if globals.JacobinHome() == "test" {
+				return exceptions.ERROR_OCCURRED // applies only if in test
+			}
 		}
 	}
 
@@ -2673,9 +2809,8 @@ func doInvokeinterface(fr *frames.Frame, _ int64) int {
 				return exceptions.ERROR_OCCURRED // applies only if in test
 			}
 		}
-		fr.PC += 5                    // 2 for CP slot, 1 for count, 1 for zero byte, 1 for next bytecode
-		fr.FrameStack.PushFront(fram) // push the new frame
-		return 0                      // forcing execution of the new frame
+		// 5: 2 for CP slot, 1 for count, 1 for zero byte, 1 for next bytecode
+		return pushInvokedFrame(fr, fram, 5) // forcing execution of the new frame
 	} else if mtEntry.MType == 'G' { // it's a gfunction (i.e., a native function implemented in golang)
 		gmethData := mtEntry.Meth.(gfunction.GMeth)
 		paramCount := gmethData.ParamSlots
@@ -2683,10 +2818,11 @@ func doInvokeinterface(fr *frames.Frame, _ int64) int {
 		for i := 0; i < paramCount; i++ {
 			params = append(params, pop(fr))
 		}
+		params = append(params, pop(fr)) // the objRef, which the gfunction gets as its first parameter
 
 		if globals.TraceInst {
 			infoMsg := fmt.Sprintf("G-function: interface=%s, meth=%s%s", interfaceName, interfaceName, interfaceMethodType)
-			trace.Trace(infoMsg)
+			trace.Log(trace.JVM, trace.LevelTrace, fr.Thread, infoMsg)
 		}
 		ret := gfunction.RunGfunction(
 			mtEntry, fr.FrameStack, interfaceName, interfaceMethodName, interfaceMethodType, &params, true,
@@ -2697,14 +2833,14 @@ func doInvokeinterface(fr *frames.Frame, _ int64) int {
 				if globals.GetGlobalRef().JacobinName == "test" {
 					return exceptions.ERROR_OCCURRED
 				} else if errors.Is(ret.(error), gfunction.CaughtGfunctionException) {
-					return exceptions.ERROR_OCCURRED
+					return exceptions.RESUME_HERE // caught
 				}
 			default: // if it's not an error, then it's a legitimate return value, which we simply push
 				push(fr, ret)
-				return 5 // 2 for CP slot + 1 for count, 1 for zero byte, and 1 for next bytecode
 			}
 		}
 		// any exception will already have been handled.
+		return 5 // 2 for CP slot + 1 for count, 1 for zero byte, and 1 for next bytecode
 	}
 	return notImplemented(fr, 0) // in theory, unreachable code
 }
@@ -2731,7 +2867,21 @@ func doNew(fr *frames.Frame, _ int64) int {
 		className = *stringPool.GetStringPointer(nameStringPoolIndex)
 	}
 
-	ref, err := InstantiateClass(className, fr.FrameStack)
+	if object.CheckHeapSpace(object.ObjectBytes(0)) != nil {
+		return throwOutOfMemoryError("NEW", fr)
+	}
+
+	var ref any
+	var err error
+	if slot, ok := nurserySiteAt(fr); ok { // an object that never leaves the frame, see nursery.go
+		ref, err = nurseryAlloc(fr, slot, className)
+	} else {
+		ref, err = InstantiateClass(className, fr.FrameStack)
+	}
+	var initErr *classInitError
+	if errors.As(err, &initErr) {
+		return throwClassInitError("NEW", err, fr)
+	}
 	if err != nil {
 		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
 		errMsg := fmt.Sprintf("NEW: could not load class %s", className)
@@ -2741,10 +2891,44 @@ func doNew(fr *frames.Frame, _ int64) int {
 		}
 		return exceptions.RESUME_HERE // caught
 	}
+
+	// the class is now loaded, so its access flags can be checked
+	if err = classloader.CheckClassAccess(fr.ClName, className); err != nil {
+		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+		errMsg := "NEW: " + err.Error()
+		status := exceptions.ThrowEx(excNames.IllegalAccessError, errMsg, fr)
+		if status != exceptions.Caught {
+			return exceptions.ERROR_OCCURRED // applies only if in test
+		}
+		return exceptions.RESUME_HERE // caught
+	}
+
 	push(fr, ref.(*object.Object))
 	return 3 // 2 for CPslot + 1 for next bytecode
 }
 
+// throws an OutOfMemoryError for a bytecode whose allocation would exceed the heap
+// limit set by -Xmx, after writing a heap dump if -XX:+HeapDumpOnOutOfMemoryError
+// was specified. Returns the value the bytecode should return.
+func throwOutOfMemoryError(bytecode string, fr *frames.Frame) int {
+	if globals.GetGlobalRef().HeapDumpOnOOM { // the heap is dumped with the other threads stopped
+		if err := atSafepoint(fr.Thread, "a heap dump", object.DumpHeapOnce); err != nil {
+			trace.Error("heap dump: " + err.Error())
+			object.DumpHeapOnce() // dump it anyway, with the other threads running
+		}
+	}
+	globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+	errMsg := bytecode + ": " + object.ErrHeapExhausted.Error()
+	if globals.GetGlobalRef().StrictJDK { // use the HotSpot JDK's error message instead of ours
+		errMsg = object.ErrHeapExhausted.Error()
+	}
+	status := exceptions.ThrowEx(excNames.OutOfMemoryError, errMsg, fr)
+	if status != exceptions.Caught {
+		return exceptions.ERROR_OCCURRED // applies only if in test
+	}
+	return exceptions.RESUME_HERE // caught
+}
+
 // 0xBC NEWARRAY create a new array of primitives
 func doNewarray(fr *frames.Frame, _ int64) int {
 	size := pop(fr).(int64)
@@ -2771,6 +2955,10 @@ func doNewarray(fr *frames.Frame, _ int64) int {
 		return exceptions.RESUME_HERE // caught
 	}
 
+	if object.CheckHeapSpace(object.ArrayBytes(uint8(actualType), size)) != nil {
+		return throwOutOfMemoryError("NEWARRAY", fr)
+	}
+
 	arrayPtr := object.Make1DimArray(uint8(actualType), size)
 	g := globals.GetGlobalRef()
 	g.ArrayAddressList.PushFront(arrayPtr)
@@ -2810,7 +2998,30 @@ func doAnewarray(fr *frames.Frame, _ int64) int {
 		refTypeName = *stringPool.GetStringPointer(refNameStringPoolIndex)
 	}
 
-	arrayPtr := object.Make1DimRefArray(refTypeName, size)
+	if err := classloader.CheckClassAccess(fr.ClName, refTypeName); err != nil {
+		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+		errMsg := "ANEWARRAY: " + err.Error()
+		status := exceptions.ThrowEx(excNames.IllegalAccessError, errMsg, fr)
+		if status != exceptions.Caught {
+			return exceptions.ERROR_OCCURRED // applies only if in test
+		}
+		return exceptions.RESUME_HERE // caught
+	}
+
+	if object.CheckHeapSpace(object.ArrayBytes(object.REF, size)) != nil {
+		return throwOutOfMemoryError("ANEWARRAY", fr)
+	}
+
+	// the array of a class or interface is typed "[Lname;", and an array of arrays, such as
+	// the outer array of a jagged int[][], is "[" followed by the descriptor of its elements
+	var arrayPtr *object.Object
+	if strings.HasPrefix(refTypeName, types.Array) {
+		arrayPtr = object.MakeMultiDimArray(types.Array+refTypeName, []int64{size})
+	} else if refTypeName != "" {
+		arrayPtr = object.Make1DimRefArray(refTypeName+";", size)
+	} else {
+		arrayPtr = object.Make1DimRefArray(refTypeName, size)
+	}
 	g := globals.GetGlobalRef()
 	g.ArrayAddressList.PushFront(arrayPtr)
 	push(fr, arrayPtr)
@@ -2820,13 +3031,9 @@ func doAnewarray(fr *frames.Frame, _ int64) int {
 // 0xBE ARRAYLENGTH get size of an array
 func doArraylength(fr *frames.Frame, _ int64) int {
 	ref := pop(fr) // pointer to the array
-	if ref == nil {
-		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+	if object.IsNull(ref) {
 		errMsg := "ARRAYLENGTH: Invalid (null) reference to an array"
-		status := exceptions.ThrowEx(excNames.NullPointerException, errMsg, fr)
-		if status != exceptions.Caught {
-			return exceptions.ERROR_OCCURRED // applies only if in test
-		}
+		return exceptions.ThrowNPE(fr, errMsg)
 	}
 
 	var size int64
@@ -2861,17 +3068,11 @@ func doArraylength(fr *frames.Frame, _ int64) int {
 	case *object.Object:
 		r := ref.(*object.Object)
 		if object.IsNull(r) {
-			globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
 			errMsg := "ARRAYLENGTH: Invalid (null) value for *object.Object"
-			status := exceptions.ThrowEx(excNames.NullPointerException, errMsg, fr)
-			if status != exceptions.Caught {
-				return exceptions.ERROR_OCCURRED // applies only if in test
-			}
-			return exceptions.RESUME_HERE // caught
+			return exceptions.ThrowNPE(fr, errMsg)
 		}
 		size = object.ArrayLength(r)
 	default:
-		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
 		errMsg := fmt.Sprintf("ARRAYLENGTH: Invalid ref.(type): %T", ref)
 		status := exceptions.ThrowEx(excNames.IllegalArgumentException, errMsg, fr)
 		if status != exceptions.Caught {
@@ -2889,10 +3090,7 @@ func doAthrow(fr *frames.Frame, _ int64) int {
 	objectRef := pop(fr).(*object.Object)
 	if object.IsNull(objectRef) {
 		errMsg := "ATHROW: Invalid (null) reference to an exception/error class to throw"
-		status := exceptions.ThrowEx(excNames.NullPointerException, errMsg, fr)
-		if status != exceptions.Caught {
-			return exceptions.ERROR_OCCURRED // applies only if in test
-		}
+		return exceptions.ThrowNPE(fr, errMsg)
 	}
 
 	// capture the golang stack
@@ -2917,87 +3115,27 @@ func doAthrow(fr *frames.Frame, _ int64) int {
 	// with whether we want the standard JDK info as elected with the -strictJDK
 	// command-line option)
 	if catchFrame == nil {
-		// if the exception is not caught, then print the data from the stackTraceElements (STEs)
-		// in the Throwable object or subclass (which is generally the specific exception class).
-
-		// start by printing out the name of the exception/error and the thread it occurred on
-		errMsg := ""
-		if fr.Thread == 1 { // if it's thread #1, use its name, "main"
-			errMsg = fmt.Sprintf("Exception in thread \"main\" %s", exceptionName)
-		} else {
-			errMsg = fmt.Sprintf("Exception in thread %d %s", fr.Thread, exceptionName)
+		// an exception that escapes a static initializer or an upcall ends it, but not the program
+		if exceptions.AbortToBoundary(fr.FrameStack, exceptionName+exceptionDetailMessage(objectRef), objectRef) {
+			return exceptions.RESUME_HERE
 		}
 
-		appMsg := objectRef.FieldTable["detailMessage"].Fvalue
-		if appMsg != object.Null && appMsg != nil {
-			switch appMsg.(type) {
-			case []types.JavaByte:
-				jbarray := appMsg.([]types.JavaByte)
-				errMsg += fmt.Sprintf(": %s", object.GoStringFromJavaByteArray(jbarray))
-			case *object.Object:
-				var value any
-				obj := appMsg.(*object.Object)
-				fld, ok := obj.FieldTable["value"]
-				if !ok {
-					value = "<missing>"
-				} else {
-					value = fld.Fvalue
-				}
-				switch value.(type) {
-				case []byte:
-					errMsg += fmt.Sprintf(": %s", string(obj.FieldTable["value"].Fvalue.([]byte)))
-				case uint32:
-					str := stringPool.GetStringPointer(value.(uint32))
-					errMsg += fmt.Sprintf(": %s", *str)
-				default:
-					str := fmt.Sprintf(": %v", value)
-					errMsg += fmt.Sprintf(": %s", str)
-				}
-			default:
-				errMsg += ": objectRef.FieldTable[\"detailMessage\"] is object.Null"
-			}
-		}
-		trace.Error(errMsg)
-
-		steArrayPtr := objectRef.FieldTable["stackTrace"].Fvalue.(*object.Object)
-		rawSteArray := steArrayPtr.FieldTable["value"].Fvalue.([]*object.Object) // []*object.Object (each of which is an STE)
-		for i := 0; i < len(rawSteArray); i++ {
-			ste := rawSteArray[i]
-			methodName := ste.FieldTable["methodName"].Fvalue.(string)
-			if methodName == "<init>" { // don't show constructors
-				continue
-			}
-			rawClassName := ste.FieldTable["declaringClass"].Fvalue.(string)
-			if rawClassName == "java/lang/Throwable" { // don't show Throwable methods
-				continue
-			}
-			className := strings.Replace(rawClassName, "/", ".", -1)
-
-			sourceLine := ste.FieldTable["sourceLine"].Fvalue.(string)
-
-			var errMsg string
-			if sourceLine != "" {
-				errMsg = fmt.Sprintf("\tat %s.%s(%s:%s)", className,
-					methodName, ste.FieldTable["fileName"].Fvalue, sourceLine)
-			} else {
-				errMsg = fmt.Sprintf("\tat %s.%s(%s)", className,
-					methodName, ste.FieldTable["fileName"].Fvalue)
-			}
-			trace.Error(errMsg)
+		// the thread's uncaught exception handler, if it has one, deals with the exception
+		// (see uncaught.go); if not, its stack trace is printed, as the JDK's default does
+		glob := globals.GetGlobalRef()
+		if glob.FuncUncaughtHandler == nil || !glob.FuncUncaughtHandler(fr.FrameStack, objectRef) {
+			printUncaughtException(fr, exceptionName, objectRef)
 		}
 
-		// show Jacobin's JVM stack info if -strictJDK is not set
-		if globals.GetGlobalRef().StrictJDK == false {
-			trace.Trace(" ")
-			for _, frameData := range *globals.GetGlobalRef().JVMframeStack {
-				colon := strings.Index(frameData, ":")
-				shortenedFrameData := frameData[colon+1:]
-				trace.Trace("\tat" + shortenedFrameData)
+		// the exception ends the thread. On the main thread, that ends the program, with
+		// the exit status the JDK gives an uncaught exception; other threads keep running.
+		if fr.Thread != MainThread.ID {
+			for fr.FrameStack.Len() > 0 {
+				fr.FrameStack.Remove(fr.FrameStack.Front())
 			}
+			return exceptions.RESUME_HERE
 		}
-
-		// all exceptions that got this far are untrapped, so shutdown with an error code
-		shutdown.Exit(shutdown.APP_EXCEPTION)
+		shutdown.Exit(shutdown.UNCAUGHT_EXCEPTION)
 
 	} else { // perform the catch operation. We know the frame and the starting bytecode for the handler
 		for f := fr.FrameStack.Front(); fr != nil; f = f.Next() {
@@ -3018,6 +3156,97 @@ func doAthrow(fr *frames.Frame, _ int64) int {
 	return 1 // should not be reached, in theory
 }
 
+// prints an exception that a thread didn't catch and the stack trace in it, for
+// a thread with no uncaught exception handler
+func printUncaughtException(fr *frames.Frame, exceptionName string, objectRef *object.Object) {
+	// print the data from the stackTraceElements (STEs) in the Throwable object or
+	// subclass (which is generally the specific exception class).
+
+	// start by printing out the name of the exception/error and the thread it occurred on
+	errMsg := ""
+	if fr.Thread == 1 { // if it's thread #1, use its name, "main"
+		errMsg = fmt.Sprintf("Exception in thread \"main\" %s", exceptionName)
+	} else {
+		errMsg = fmt.Sprintf("Exception in thread \"%s\" %s", thread.ThreadName(fr.Thread), exceptionName)
+	}
+
+	errMsg += exceptionDetailMessage(objectRef)
+	trace.Error(errMsg)
+
+	steArrayPtr := objectRef.FieldTable["stackTrace"].Fvalue.(*object.Object)
+	rawSteArray := steArrayPtr.FieldTable["value"].Fvalue.([]*object.Object) // []*object.Object (each of which is an STE)
+	for i := 0; i < len(rawSteArray); i++ {
+		ste := rawSteArray[i]
+		methodName := ste.FieldTable["methodName"].Fvalue.(string)
+		if methodName == "<init>" { // don't show constructors
+			continue
+		}
+		rawClassName := ste.FieldTable["declaringClass"].Fvalue.(string)
+		if rawClassName == "java/lang/Throwable" { // don't show Throwable methods
+			continue
+		}
+		className := strings.Replace(rawClassName, "/", ".", -1)
+
+		sourceLine := ste.FieldTable["sourceLine"].Fvalue.(string)
+
+		var errMsg string
+		if sourceLine != "" {
+			errMsg = fmt.Sprintf("\tat %s.%s(%s:%s)", className,
+				methodName, ste.FieldTable["fileName"].Fvalue, sourceLine)
+		} else {
+			errMsg = fmt.Sprintf("\tat %s.%s(%s)", className,
+				methodName, ste.FieldTable["fileName"].Fvalue)
+		}
+		trace.Error(errMsg)
+	}
+
+	// show Jacobin's JVM stack info if -strictJDK is not set
+	if globals.GetGlobalRef().StrictJDK == false {
+		trace.Trace(" ")
+		for _, frameData := range *globals.GetGlobalRef().JVMframeStack {
+			colon := strings.Index(frameData, ":")
+			shortenedFrameData := frameData[colon+1:]
+			trace.Trace("\tat" + shortenedFrameData)
+		}
+	}
+}
+
+// returns the detail message of a Throwable, formatted as a suffix (": message")
+// to the exception name, or an empty string if the Throwable has no message
+func exceptionDetailMessage(objectRef *object.Object) string {
+	errMsg := ""
+	appMsg := objectRef.FieldTable["detailMessage"].Fvalue
+	if appMsg != object.Null && appMsg != nil {
+		switch appMsg.(type) {
+		case []types.JavaByte:
+			jbarray := appMsg.([]types.JavaByte)
+			errMsg = fmt.Sprintf(": %s", object.GoStringFromJavaByteArray(jbarray))
+		case *object.Object:
+			var value any
+			obj := appMsg.(*object.Object)
+			fld, ok := obj.FieldTable["value"]
+			if !ok {
+				value = "<missing>"
+			} else {
+				value = fld.Fvalue
+			}
+			switch value.(type) {
+			case []byte:
+				errMsg = fmt.Sprintf(": %s", string(obj.FieldTable["value"].Fvalue.([]byte)))
+			case uint32:
+				str := stringPool.GetStringPointer(value.(uint32))
+				errMsg = fmt.Sprintf(": %s", *str)
+			default:
+				str := fmt.Sprintf(": %v", value)
+				errMsg = fmt.Sprintf(": %s", str)
+			}
+		default:
+			errMsg = ": objectRef.FieldTable[\"detailMessage\"] is object.Null"
+		}
+	}
+	return errMsg
+}
+
 // 0xC0 CHECKCAST
 func doCheckcast(fr *frames.Frame, _ int64) int {
 	// same as INSTANCEOF but does nothing on null;
@@ -3142,20 +3371,20 @@ func doInstanceof(fr *frames.Frame, _ int64) int {
 						trace.Trace(traceInfo)
 					}
 				}
-				classPtr := classloader.MethAreaFetch(className)
-				if classPtr == nil { // class wasn't loaded, so load it now
-					if classloader.LoadClassFromNameOnly(className) != nil {
-						globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
-						errMsg := "INSTANCEOF: Could not load class: " + className
-						status := exceptions.ThrowEx(excNames.ClassNotLoadedException, errMsg, fr)
-						if status != exceptions.Caught {
-							return exceptions.ERROR_OCCURRED // applies only if in test
-						}
-						return exceptions.RESUME_HERE // caught
+				// loads the class, if need be, and memoizes it in the CP
+				classPtr, err := classloader.ResolveClassRef(CP, CPslot)
+				if err != nil {
+					globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+					errMsg := "INSTANCEOF: Could not load class: " + className
+					status := exceptions.ThrowEx(excNames.ClassNotLoadedException, errMsg, fr)
+					if status != exceptions.Caught {
+						return exceptions.ERROR_OCCURRED // applies only if in test
 					}
-					classPtr = classloader.MethAreaFetch(className)
+					return exceptions.RESUME_HERE // caught
 				}
-				if classPtr == classloader.MethAreaFetch(*(stringPool.GetStringPointer(obj.KlassName))) {
+				objClassName := *(stringPool.GetStringPointer(obj.KlassName))
+				if classPtr == classloader.MethAreaFetch(objClassName) ||
+					classloader.IsAssignableTo(objClassName, className) {
 					push(fr, int64(1))
 				} else {
 					push(fr, int64(0))
@@ -3166,6 +3395,18 @@ func doInstanceof(fr *frames.Frame, _ int64) int {
 	return 3 // 2 for CP slot + 1 for next bytecode
 }
 
+// 0xC2 MONITORENTER pops the object whose monitor is to be entered. Monitors are not
+// yet implemented, but MONITORENTER is where threads synchronize, so it's a point at
+// which -record logs the order of the threads and -replay enforces it (see replay.go)
+func doMonitorenter(fr *frames.Frame, _ int64) int {
+	pop(fr)
+	if replay.Active() {
+		replay.AwaitTurn(fr.Thread)
+		replay.Passed(fr.Thread)
+	}
+	return 1
+}
+
 // 0xC4 WIDE use wide versions of bytecode arguments
 func doWide(fr *frames.Frame, _ int64) int {
 	fr.WideInEffect = true
@@ -3174,102 +3415,67 @@ func doWide(fr *frames.Frame, _ int64) int {
 
 // 0xC5 MULTIANEWARRAY create a multi-dimensional array
 func doMultinewarray(fr *frames.Frame, _ int64) int {
-	var arrayDesc string
-	var arrayType uint8
-
 	// The first two bytes after the bytecode point to a classref entry in the CP.
 	// In turn, it points to a string describing the array of the form [[L or
-	// similar, in which one [ is present for every array dimension, followed by a
-	// single letter describing the type of primitive in the leaf dimension of the array.
+	// similar, in which one [ is present for every array dimension, followed by the
+	// descriptor of the type of the elements of the innermost arrays.
 	// The letters are the usual ones used in the JVM for primitives, etc.
 	// as in: https://docs.oracle.com/javase/specs/jvms/se21/html/jvms-4.html#jvms-4.3.2-200
 	CPslot := (int(fr.Meth[fr.PC+1]) * 256) + int(fr.Meth[fr.PC+2]) // point to CP entry
 	CP := fr.CP.(*classloader.CPool)
 	CPentry := CP.CpIndex[CPslot]
 	arrayDescStringPoolIndex := CP.ClassRefs[CPentry.Slot]
-	arrayDesc = *stringPool.GetStringPointer(arrayDescStringPoolIndex)
-
-	var rawArrayType uint8
-	for i := 0; i < len(arrayDesc); i++ {
-		if arrayDesc[i] != '[' {
-			rawArrayType = arrayDesc[i]
-			break
-		}
-	}
+	arrayDesc := *stringPool.GetStringPointer(arrayDescStringPoolIndex)
 
-	switch rawArrayType {
-	case 'B', 'Z':
-		arrayType = object.BYTE
-	case 'F', 'D':
-		arrayType = object.FLOAT
-	case 'L':
-		arrayType = object.REF
-	default:
-		arrayType = object.INT
-	}
-
-	// get the number of dimensions, then pop off the operand
-	// stack an int for every dimension, giving the size of that
-	// dimension and put them into a slice that starts with
-	// the highest dimension first. So a two-dimensional array
-	// such as x[4][3], would have entries of 4 and 3 respectively
-	// in the dimsizes slice.
+	// get the number of dimensions to create, which can be fewer than the dimensions
+	// of the array (as in new int[3][][]), then pop off the operand stack an int for
+	// every dimension, giving the size of that dimension, and put them into a slice that
+	// starts with the outermost dimension. So a two-dimensional array such as x[4][3]
+	// would have entries of 4 and 3 respectively in the dimSizes slice.
 	dimensionCount := int(fr.Meth[fr.PC+3])
-
-	if dimensionCount > 3 { // TODO: explore arrays of > 5-255 dimensions
+	arrayDims := len(arrayDesc) - len(object.GetArrayType(arrayDesc))
+	if dimensionCount < 1 || dimensionCount > arrayDims {
 		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
-		errMsg := "MULTIANEWARRAY: Jacobin supports arrays only up to three dimensions"
-		status := exceptions.ThrowEx(excNames.InternalException, errMsg, fr)
+		errMsg := fmt.Sprintf("MULTIANEWARRAY: invalid count of %d dimensions for array type %s",
+			dimensionCount, arrayDesc)
+		status := exceptions.ThrowEx(excNames.VerifyError, errMsg, fr)
 		if status != exceptions.Caught {
 			return exceptions.ERROR_OCCURRED // applies only if in test
 		}
 		return exceptions.RESUME_HERE // caught
 	}
 
+	// the values on the operand stack give the last dimension first when popped off
+	// the stack, so they're stored here in reverse order, so that dimSizes[0] will hold
+	// the first dimension. As in the JDK, all the sizes are checked before any array
+	// is created.
 	dimSizes := make([]int64, dimensionCount)
-
-	// the values on the operand stack give the last dimension
-	// first when popped off the stack, so, they're stored here
-	// in reverse order, so that dimSizes[0] will hold the first
-	// dimenion.
 	for i := dimensionCount - 1; i >= 0; i-- {
 		dimSizes[i] = pop(fr).(int64)
 	}
-
-	// A dimension of zero ends the dimensions, so we check
-	// and cut off the dimensions below and includingthe 0-sized
-	// one. Because this is almost certainly an error, we also
-	// issue a warning.
-	for i := range dimSizes {
-		if dimSizes[i] == 0 {
-			dimSizes = dimSizes[i+1:] // lop off the prev dims
-			trace.Error("MULTIANEWARRAY: Multidimensional array with one dimension of size 0 encountered.")
-			break
+	for _, size := range dimSizes {
+		if size < 0 {
+			globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+			errMsg := fmt.Sprintf("MULTIANEWARRAY: Invalid size for array: %d", size)
+			status := exceptions.ThrowEx(excNames.NegativeArraySizeException, errMsg, fr)
+			if status != exceptions.Caught {
+				return exceptions.ERROR_OCCURRED // applies only if in test
+			}
+			return exceptions.RESUME_HERE // caught
 		}
 	}
 
-	// Because of the possibility of a zero-sized dimension
-	// affecting the valid number of dimensions, dimensionCount
-	// can no longer be considered reliable. Use len(dimSizes).
-	if len(dimSizes) == 3 {
-		multiArr := object.Make1DimArray(object.REF, dimSizes[0])
-		actualArray := multiArr.FieldTable["value"].Fvalue.([]*object.Object)
-		for i := 0; i < len(actualArray); i++ {
-			actualArray[i], _ = object.Make2DimArray(dimSizes[1],
-				dimSizes[2], arrayType)
-		}
-		push(fr, multiArr)
-
-	} else if len(dimSizes) == 2 { // 2-dim array is a special, trivial case
-		multiArr, _ := object.Make2DimArray(dimSizes[0], dimSizes[1], arrayType)
-		push(fr, multiArr)
-		// It's possible due to a zero-length dimension, that we
-		// need to create a single-dimension array.
-	} else if len(dimSizes) == 1 {
-		oneDimArr := object.Make1DimArray(arrayType, dimSizes[0])
-		push(fr, oneDimArr)
-
+	// the innermost arrays created hold references unless they're the arrays of the
+	// array type's innermost dimension
+	leafType := uint8(object.REF)
+	if dimensionCount == arrayDims {
+		leafType = object.ArrayKind(object.GetArrayType(arrayDesc))
+	}
+	if object.CheckHeapSpace(object.MultiArrayBytes(leafType, dimSizes)) != nil {
+		return throwOutOfMemoryError("MULTIANEWARRAY", fr)
 	}
+
+	push(fr, object.MakeMultiDimArray(arrayDesc, dimSizes))
 	return 4 // 2 for CPslot + 1 for dimensions + 1 for next bytecode
 }
 
@@ -3358,24 +3564,105 @@ func ldc(fr *frames.Frame, width int) int {
 		}
 		return exceptions.RESUME_HERE // caught
 	}
+	// class literals (Foo.class), method types, and method handles push objects
+	switch CPe.EntryType {
+	case classloader.ClassRef:
+		return ldcClass(fr, *CPe.StringVal, width)
+	case classloader.MethodType, classloader.MethodHandle:
+		return ldcMethodTypeOrHandle(fr, idx, width)
+	}
+
 	// if no error
 	switch CPe.RetType {
 	case classloader.IS_INT64:
 		push(fr, CPe.IntVal)
 	case classloader.IS_FLOAT64:
 		push(fr, CPe.FloatVal)
-	case classloader.IS_STRUCT_ADDR:
-		push(fr, CPe.AddrVal)
+	case classloader.IS_STRUCT_ADDR: // no other CP entry that's a struct can be loaded
+		globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+		errMsg := fmt.Sprintf("in %s.%s, LDC: Invalid type for bytecode operand: %d",
+			util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName, CPe.EntryType)
+		status := exceptions.ThrowEx(excNames.ClassFormatError, errMsg, fr)
+		if status != exceptions.Caught {
+			return exceptions.ERROR_OCCURRED // applies only if in test
+		}
+		return exceptions.RESUME_HERE // caught
 	case classloader.IS_STRING_ADDR: // returns a string object whose "value" field is a byte array
 		stringAddr := object.StringObjectFromGoString(*CPe.StringVal)
 		push(fr, stringAddr)
 	}
 
-	if width == 1 {
-		return 2 // 1 for the index + 1 for the next bytecode
+	return width + 1 // width of the index + 1 for the next bytecode
+}
+
+// LDC or LDC_W of a ClassRef: loads the class, if need be, and pushes its java/lang/Class object
+func ldcClass(fr *frames.Frame, className string, width int) int {
+	// for arrays, the class to load is the element type, if it's a reference
+	elementName := strings.TrimLeft(className, types.Array)
+	if elementName != className {
+		if !strings.HasPrefix(elementName, types.Ref) {
+			elementName = "" // an array of primitives requires no loading
+		} else {
+			elementName = strings.TrimSuffix(elementName[1:], ";")
+		}
+	}
+
+	if elementName != "" && classloader.MethAreaFetch(elementName) == nil {
+		if err := classloader.LoadClassFromNameOnly(elementName); err != nil {
+			globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+			errMsg := fmt.Sprintf("in %s.%s, LDC: could not load class %s",
+				util.ConvertInternalClassNameToUserFormat(fr.ClName), fr.MethName,
+				util.ConvertInternalClassNameToUserFormat(elementName))
+			status := exceptions.ThrowEx(excNames.NoClassDefFoundError, errMsg, fr)
+			if status != exceptions.Caught {
+				return exceptions.ERROR_OCCURRED // applies only if in test
+			}
+			return exceptions.RESUME_HERE // caught
+		}
+	}
+
+	push(fr, classloader.GetClassObject(stringPool.GetStringIndex(&className)))
+	return width + 1 // width of the index + 1 for the next bytecode
+}
+
+// LDC or LDC_W of a MethodType or MethodHandle. Jacobin does not implement
+// java/lang/invoke, so these push placeholder objects that record the
+// descriptor (for MethodType) or the kind and the referenced member (for
+// MethodHandle). This is enough for code that passes these constants along
+// or prints them.
+func ldcMethodTypeOrHandle(fr *frames.Frame, idx int, width int) int {
+	CP := fr.CP.(*classloader.CPool)
+	entry := CP.CpIndex[idx]
+
+	var obj *object.Object
+	if entry.Type == classloader.MethodType {
+		descIndex := CP.MethodTypes[entry.Slot]
+		desc := classloader.FetchUTF8stringFromCPEntryNumber(CP, descIndex)
+		obj = object.MakeOneFieldObject("java/lang/invoke/MethodType", "descriptor",
+			types.Ref, object.StringObjectFromGoString(desc))
 	} else {
-		return 3 // 2 for the index + 1 for the next bytecode
+		mh := CP.MethodHandles[entry.Slot]
+		member := ""
+		if int(mh.RefIndex) < len(CP.CpIndex) {
+			refEntry := CP.CpIndex[mh.RefIndex]
+			switch refEntry.Type {
+			case classloader.MethodRef:
+				_, _, _, member = classloader.GetMethInfoFromCPmethref(CP, int(mh.RefIndex))
+			case classloader.Interface:
+				clName, methName, methSig := classloader.GetMethInfoFromCPinterfaceRef(CP, int(mh.RefIndex))
+				member = clName + "." + methName + methSig
+			case classloader.FieldRef:
+				fld := CP.FieldRefs[refEntry.Slot]
+				member = fld.ClName + "." + fld.FldName + ":" + fld.FldType
+			}
+		}
+		obj = object.MakeOneFieldObject("java/lang/invoke/MethodHandle", "member",
+			types.Ref, object.StringObjectFromGoString(member))
+		obj.FieldTable["refKind"] = object.Field{Ftype: types.Int, Fvalue: int64(mh.RefKind)}
 	}
+
+	push(fr, obj)
+	return width + 1 // width of the index + 1 for the next bytecode
 }
 
 func pushInt(fr *frames.Frame, intToPush int64) int {
@@ -552,6 +552,41 @@ func TestNewD2lNegative(t *testing.T) {
 	}
 }
 
+// D2I, D2L: NaN converts to 0 and values out of range saturate, per the JVMS
+func TestNewD2iD2lSaturate(t *testing.T) {
+	tests := []struct {
+		opcode   byte
+		value    float64
+		expected int64
+	}{
+		{opcodes.D2I, math.NaN(), 0},
+		{opcodes.D2I, math.Inf(1), math.MaxInt32},
+		{opcodes.D2I, math.Inf(-1), math.MinInt32},
+		{opcodes.D2I, 3e9, math.MaxInt32},
+		{opcodes.D2I, -3e9, math.MinInt32},
+		{opcodes.D2I, math.Copysign(0, -1), 0},
+		{opcodes.D2L, math.NaN(), 0},
+		{opcodes.D2L, math.Inf(1), math.MaxInt64},
+		{opcodes.D2L, math.Inf(-1), math.MinInt64},
+		{opcodes.D2L, 1e19, math.MaxInt64},
+		{opcodes.D2L, -1e19, math.MinInt64},
+		{opcodes.D2L, 3e9, 3000000000},
+	}
+	for _, test := range tests {
+		f := newFrame(test.opcode)
+		push(&f, test.value)
+
+		fs := frames.CreateFrameStack()
+		fs.PushFront(&f) // push the new frame
+		interpret(fs)
+
+		val := pop(&f).(int64)
+		if val != test.expected {
+			t.Errorf("0x%X of %v: expected a result of %d, but got: %d", test.opcode, test.value, test.expected, val)
+		}
+	}
+}
+
 // DADD: test add two doubles
 func TestNewDadd(t *testing.T) {
 	f := newFrame(opcodes.DADD)
@@ -1268,3 +1303,71 @@ func TestNewDup2X2(t *testing.T) {
 			"DUP2_X2: popped values are incorrect. Expecting value of 1, got: %X and %X", a, e)
 	}
 }
+
+// The stack-manipulation bytecodes on longs and doubles, which take up two stack entries on
+// HotSpot and one on Jacobin. The code checker converts them to the bytecodes that do the
+// same on Jacobin, so these check that the converted bytecodes leave the expected stack.
+func TestStackBytecodesOnLongsAndDoubles(t *testing.T) {
+	globals.InitGlobals("test")
+
+	tests := []struct {
+		name     string
+		locals   []any
+		code     []byte
+		expected []any // the stack afterward, bottom first
+	}{
+		{"DUP2 of a long", []any{int64(7), int64(0)},
+			[]byte{opcodes.LLOAD_0, opcodes.DUP2},
+			[]any{int64(7), int64(7)}},
+		{"DUP2 of two ints", []any{int64(1), int64(2)},
+			[]byte{opcodes.ILOAD_0, opcodes.ILOAD_1, opcodes.DUP2},
+			[]any{int64(1), int64(2), int64(1), int64(2)}},
+		{"DUP_X2 over a long", []any{int64(9), int64(0), int64(3)},
+			[]byte{opcodes.LLOAD_0, opcodes.ILOAD_2, opcodes.DUP_X2},
+			[]any{int64(3), int64(9), int64(3)}},
+		{"DUP2_X1 of a double", []any{int64(1), 2.5, float64(0)},
+			[]byte{opcodes.ILOAD_0, opcodes.DLOAD_1, opcodes.DUP2_X1},
+			[]any{2.5, int64(1), 2.5}},
+		{"DUP2_X2 of a long over two ints", []any{int64(1), int64(2), int64(5), int64(0)},
+			[]byte{opcodes.ILOAD_0, opcodes.ILOAD_1, opcodes.LLOAD_2, opcodes.DUP2_X2},
+			[]any{int64(5), int64(1), int64(2), int64(5)}},
+		{"DUP2_X2 of two ints over a long", []any{int64(5), int64(0), int64(1), int64(2)},
+			[]byte{opcodes.LLOAD_0, opcodes.ILOAD_2, opcodes.ILOAD_3, opcodes.DUP2_X2},
+			[]any{int64(1), int64(2), int64(5), int64(1), int64(2)}},
+		{"DUP2_X2 of a double over a long", []any{int64(5), int64(0), 2.5, float64(0)},
+			[]byte{opcodes.LLOAD_0, opcodes.DLOAD_2, opcodes.DUP2_X2},
+			[]any{2.5, int64(5), 2.5}},
+		{"POP2 of a long", []any{int64(5), int64(0), int64(1)},
+			[]byte{opcodes.ILOAD_2, opcodes.LLOAD_0, opcodes.POP2},
+			[]any{int64(1)}},
+		{"SWAP", []any{int64(1), int64(2)},
+			[]byte{opcodes.ILOAD_0, opcodes.ILOAD_1, opcodes.SWAP},
+			[]any{int64(2), int64(1)}},
+	}
+
+	for _, test := range tests {
+		code := append([]byte{}, test.code...)
+		cp := classloader.CPool{CpIndex: make([]classloader.CpEntry, 1)}
+		if err := classloader.CheckCodeValidity(&code, &cp, 6, classloader.AccessFlags{}); err != nil {
+			t.Errorf("%s: CheckCodeValidity failed: %v", test.name, err)
+			continue
+		}
+
+		f := newFrame(code[0])
+		f.Meth = code
+		f.Locals = test.locals
+		fs := frames.CreateFrameStack()
+		fs.PushFront(&f) // push the new frame
+		interpret(fs)
+
+		if f.TOS != len(test.expected)-1 {
+			t.Errorf("%s: expected a stack of %d values, got: %d", test.name, len(test.expected), f.TOS+1)
+			continue
+		}
+		for i, expected := range test.expected {
+			if f.OpStack[i] != expected {
+				t.Errorf("%s: expected %v at stack entry %d, got: %v", test.name, expected, i, f.OpStack[i])
+			}
+		}
+	}
+}
@@ -178,6 +178,54 @@ func TestNewFcmpg0(t *testing.T) {
 	}
 }
 
+// F2I, F2L: NaN converts to 0 and values out of range saturate, per the JVMS
+func TestNewF2iF2lSaturate(t *testing.T) {
+	tests := []struct {
+		opcode   byte
+		value    float64
+		expected int64
+	}{
+		{opcodes.F2I, math.NaN(), 0},
+		{opcodes.F2I, float64(float32(3e9)), math.MaxInt32},
+		{opcodes.F2I, math.Inf(-1), math.MinInt32},
+		{opcodes.F2I, -2.5, -2},
+		{opcodes.F2L, math.NaN(), 0},
+		{opcodes.F2L, float64(float32(1e19)), math.MaxInt64},
+		{opcodes.F2L, math.Inf(-1), math.MinInt64},
+	}
+	for _, test := range tests {
+		f := newFrame(test.opcode)
+		push(&f, test.value)
+
+		fs := frames.CreateFrameStack()
+		fs.PushFront(&f) // push the new frame
+		interpret(fs)
+
+		val := pop(&f).(int64)
+		if val != test.expected {
+			t.Errorf("0x%X of %v: expected a result of %d, but got: %d", test.opcode, test.value, test.expected, val)
+		}
+	}
+}
+
+// FCMPG, FCMPL: -0.0 and 0.0 are equal
+func TestNewFcmpNegativeZero(t *testing.T) {
+	for _, opcode := range []byte{opcodes.FCMPG, opcodes.FCMPL} {
+		f := newFrame(opcode)
+		push(&f, math.Copysign(0, -1))
+		push(&f, 0.0)
+
+		fs := frames.CreateFrameStack()
+		fs.PushFront(&f) // push the new frame
+		interpret(fs)
+
+		val := pop(&f).(int64)
+		if val != 0 {
+			t.Errorf("0x%X: expected -0.0 and 0.0 to compare as 0, but got: %d", opcode, val)
+		}
+	}
+}
+
 // FCMPG
 func TestNewFcmpgNan(t *testing.T) {
 	f := newFrame(opcodes.FCMPG)
@@ -1126,6 +1174,84 @@ func TestNewIdiv(t *testing.T) {
 
 // IDIV: Testing the exception is done in TestHexIDIVexception.go
 
+// IDIV, LDIV, IREM, LREM: the edge cases of integer division, which truncates toward zero
+// and wraps around when MIN_VALUE is divided by -1
+func TestIntegerDivisionEdgeCases(t *testing.T) {
+	tests := []struct {
+		opcode   byte
+		dividend int64
+		divisor  int64
+		expected int64
+	}{
+		{opcodes.IDIV, math.MinInt32, -1, math.MinInt32},
+		{opcodes.IDIV, math.MinInt32, 1, math.MinInt32},
+		{opcodes.IDIV, -7, 2, -3},
+		{opcodes.IDIV, 7, -2, -3},
+		{opcodes.IDIV, 0, -5, 0},
+		{opcodes.LDIV, math.MinInt64, -1, math.MinInt64},
+		{opcodes.LDIV, -7, 2, -3},
+		{opcodes.LDIV, math.MaxInt64, -1, -math.MaxInt64},
+		{opcodes.IREM, math.MinInt32, -1, 0},
+		{opcodes.IREM, -7, 2, -1},
+		{opcodes.IREM, 7, -2, 1},
+		{opcodes.LREM, math.MinInt64, -1, 0},
+		{opcodes.LREM, -7, 2, -1},
+		{opcodes.LREM, 7, -2, 1},
+	}
+	for _, test := range tests {
+		f := newFrame(test.opcode)
+		push(&f, test.dividend)
+		push(&f, test.divisor)
+		fs := frames.CreateFrameStack()
+		fs.PushFront(&f) // push the new frame
+		interpret(fs)
+
+		value := pop(&f).(int64)
+		if value != test.expected {
+			t.Errorf("%s of %d by %d: expected a result of %d, but got: %d", opcodes.BytecodeNames[test.opcode],
+				test.dividend, test.divisor, test.expected, value)
+		}
+	}
+}
+
+// IDIV, LDIV, IREM, LREM: dividing by zero throws an ArithmeticException whose message,
+// with -strictJDK, is the JDK's "/ by zero"
+func TestIntegerDivisionByZero(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		for _, opcode := range []byte{opcodes.IDIV, opcodes.LDIV, opcodes.IREM, opcodes.LREM} {
+			globals.InitGlobals("test")
+			globals.GetGlobalRef().StrictJDK = strict
+
+			// hide the error message to stderr
+			normalStderr := os.Stderr
+			r, w, _ := os.Pipe()
+			os.Stderr = w
+
+			f := newFrame(opcode)
+			push(&f, int64(6))
+			push(&f, int64(0))
+			fs := frames.CreateFrameStack()
+			fs.PushFront(&f) // push the new frame
+			interpret(fs)
+
+			_ = w.Close()
+			msg, _ := io.ReadAll(r)
+			os.Stderr = normalStderr
+
+			errMsg := string(msg)
+			if !strings.Contains(errMsg, "ArithmeticException") || !strings.Contains(errMsg, "/ by zero") {
+				t.Errorf("%s: expected an ArithmeticException for / by zero, got: %s",
+					opcodes.BytecodeNames[opcode], errMsg)
+			}
+			if strict == strings.Contains(errMsg, opcodes.BytecodeNames[opcode]+": 6/0") {
+				t.Errorf("%s: expected the details of the division only without -strictJDK, got: %s",
+					opcodes.BytecodeNames[opcode], errMsg)
+			}
+		}
+	}
+	globals.GetGlobalRef().StrictJDK = false
+}
+
 // IF_ACMPEQ: jump if two addresses are equal
 func TestNewIfAcmpEq(t *testing.T) {
 	f := newFrame(opcodes.IF_ACMPEQ)
@@ -85,6 +85,37 @@ func TestIincOverflow(t *testing.T) {
 	}
 }
 
+// IINC: the int wraps around on overflow by more than 1
+func TestIincOverflowWraps(t *testing.T) {
+	f := newFrame(opcodes.IINC)
+	f.Locals = append(f.Locals, int64(0x7FFFFFFE)) // initialize local variable[0] to max int - 1
+	f.Meth = append(f.Meth, 0)                     // increment local variable[0]
+	f.Meth = append(f.Meth, 5)                     // increment it by 5
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	interpret(fs)
+
+	if f.Locals[0] != int64(-2147483645) {
+		t.Errorf("IINC: Expected value to be -2147483645, got: %d", f.Locals[0])
+	}
+}
+
+// IINC: the int wraps around on underflow
+func TestIincUnderflowWraps(t *testing.T) {
+	f := newFrame(opcodes.IINC)
+	f.Locals = append(f.Locals, int64(-2147483647)) // initialize local variable[0] to min int + 1
+	f.Meth = append(f.Meth, 0)                      // increment local variable[0]
+	val := -3
+	f.Meth = append(f.Meth, byte(val)) // "increment" it by -3
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	interpret(fs)
+
+	if f.Locals[0] != int64(2147483646) {
+		t.Errorf("IINC: Expected value to be 2147483646, got: %d", f.Locals[0])
+	}
+}
+
 // ILOAD: test load of int in locals[index] on to stack
 func TestNewIload(t *testing.T) {
 	f := newFrame(opcodes.ILOAD)
@@ -1040,6 +1071,72 @@ func TestNewLdcInvalidDouble(t *testing.T) {
 	}
 }
 
+// LDC of a ClassRef (a class literal, such as int[].class) pushes the class's
+// java/lang/Class object, which must be the same object every time.
+func TestNewLdcClassRef(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+
+	CP := classloader.CPool{}
+	CP.CpIndex = []classloader.CpEntry{{}, {Type: classloader.ClassRef, Slot: 0}}
+	className := "[I"
+	CP.ClassRefs = append(CP.ClassRefs, stringPool.GetStringIndex(&className))
+
+	var pushed [2]*object.Object
+	for i := 0; i < 2; i++ {
+		f := newFrame(opcodes.LDC)
+		f.Meth = append(f.Meth, 0x01)
+		f.CP = &CP
+
+		fs := frames.CreateFrameStack()
+		fs.PushFront(&f) // push the new frame
+		interpret(fs)
+		if f.TOS != 0 {
+			t.Fatalf("Top of stack, expected 0, got: %d", f.TOS)
+		}
+		pushed[i] = pop(&f).(*object.Object)
+	}
+
+	if object.GoStringFromStringPoolIndex(pushed[0].KlassName) != types.ClassClassName {
+		t.Errorf("LDC: Expected a java/lang/Class object, got: %s",
+			object.GoStringFromStringPoolIndex(pushed[0].KlassName))
+	}
+	if classloader.ClassNameFromClassObject(pushed[0]) != "[I" {
+		t.Errorf("LDC: Expected Class object for [I, got: %s", classloader.ClassNameFromClassObject(pushed[0]))
+	}
+	if pushed[0] != pushed[1] {
+		t.Error("LDC: Expected the same Class object on each execution")
+	}
+}
+
+// LDC of a MethodType pushes an object holding the method descriptor
+func TestNewLdcMethodType(t *testing.T) {
+	globals.InitGlobals("test")
+	f := newFrame(opcodes.LDC)
+	f.Meth = append(f.Meth, 0x01)
+
+	CP := classloader.CPool{}
+	CP.CpIndex = []classloader.CpEntry{{},
+		{Type: classloader.MethodType, Slot: 0},
+		{Type: classloader.UTF8, Slot: 0}}
+	CP.MethodTypes = []uint16{2}
+	CP.Utf8Refs = []string{"(I)V"}
+	f.CP = &CP
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	interpret(fs)
+
+	obj := pop(&f).(*object.Object)
+	if object.GoStringFromStringPoolIndex(obj.KlassName) != "java/lang/invoke/MethodType" {
+		t.Errorf("LDC: Expected a MethodType object, got: %s", object.GoStringFromStringPoolIndex(obj.KlassName))
+	}
+	desc := object.GoStringFromStringObject(obj.FieldTable["descriptor"].Fvalue.(*object.Object))
+	if desc != "(I)V" {
+		t.Errorf("LDC: Expected MethodType descriptor (I)V, got: %s", desc)
+	}
+}
+
 // Test LDC_W: get int64 CP entry indexed by two bytes
 func TestNewLdcw(t *testing.T) {
 	f := newFrame(opcodes.LDC_W)
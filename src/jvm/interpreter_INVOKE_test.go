@@ -595,3 +595,64 @@ func TestNewInvokeStaticGmethodErrorReturn(t *testing.T) {
 		}
 	}
 }
+
+// INVOKESTATIC: System.arraycopy() runs as an intrinsic, without the System class in the method area
+func TestNewInvokeStaticArraycopyIntrinsic(t *testing.T) {
+	globals.InitGlobals("test")
+
+	f := newFrame(opcodes.INVOKESTATIC)
+	f.Meth = append(f.Meth, 0x00)
+	f.Meth = append(f.Meth, 0x01) // Go to slot 0x0001 in the CP
+
+	CP := classloader.CPool{}
+	CP.CpIndex = make([]classloader.CpEntry, 10)
+	CP.CpIndex[0] = classloader.CpEntry{Type: 0, Slot: 0}
+	CP.CpIndex[1] = classloader.CpEntry{Type: classloader.MethodRef, Slot: 0}
+
+	CP.MethodRefs = make([]classloader.MethodRefEntry, 1)
+	CP.MethodRefs[0] = classloader.MethodRefEntry{ClassIndex: 2, NameAndType: 3}
+
+	CP.CpIndex[2] = classloader.CpEntry{Type: classloader.ClassRef, Slot: 0}
+	CP.ClassRefs = make([]uint32, 4)
+	classname := "java/lang/System"
+	CP.ClassRefs[0] = stringPool.GetStringIndex(&classname)
+
+	CP.CpIndex[3] = classloader.CpEntry{Type: classloader.NameAndType, Slot: 0}
+	CP.NameAndTypes = make([]classloader.NameAndTypeEntry, 4)
+	CP.NameAndTypes[0] = classloader.NameAndTypeEntry{
+		NameIndex: 4,
+		DescIndex: 5,
+	}
+	CP.CpIndex[4] = classloader.CpEntry{Type: classloader.UTF8, Slot: 0} // method name
+	CP.Utf8Refs = make([]string, 4)
+	CP.Utf8Refs[0] = "arraycopy"
+
+	CP.CpIndex[5] = classloader.CpEntry{Type: classloader.UTF8, Slot: 1} // method type
+	CP.Utf8Refs[1] = "(Ljava/lang/Object;ILjava/lang/Object;II)V"
+
+	f.CP = &CP
+	classloader.ResolveCPmethRefs(&CP)
+
+	src := object.Make1DimArray(object.INT, 5)
+	dest := object.Make1DimArray(object.INT, 5)
+	rawSrc := src.FieldTable["value"].Fvalue.([]int64)
+	for i := range rawSrc {
+		rawSrc[i] = int64(i + 1)
+	}
+	push(&f, src)
+	push(&f, int64(1))
+	push(&f, dest)
+	push(&f, int64(0))
+	push(&f, int64(3))
+
+	if ret := doInvokestatic(&f, 0); ret != 3 {
+		t.Errorf("INVOKESTATIC: Expected a return of 3, got %d", ret)
+	}
+	if f.TOS != -1 {
+		t.Errorf("INVOKESTATIC: Expecting an empty stack, got TOS %d", f.TOS)
+	}
+	rawDest := dest.FieldTable["value"].Fvalue.([]int64)
+	if rawDest[0] != 2 || rawDest[1] != 3 || rawDest[2] != 4 || rawDest[3] != 0 {
+		t.Errorf("INVOKESTATIC: Expected [2 3 4 0 0], got %v", rawDest)
+	}
+}
@@ -241,8 +241,8 @@ func TestLremDivideByZero(t *testing.T) {
 	os.Stderr = normalStderr
 
 	errMsg := string(msg)
-	if !strings.Contains(errMsg, "division by zero") {
-		t.Errorf("LREM: Expected division by zero error msg, got: %s", errMsg)
+	if !strings.Contains(errMsg, "/ by zero") {
+		t.Errorf("LREM: Expected \"/ by zero\" error msg, got: %s", errMsg)
 	}
 }
 
@@ -1058,6 +1058,43 @@ func TestPutFieldSimpleInt(t *testing.T) {
 	}
 }
 
+// PUTFIELD: updating a field of a null object throws a NullPointerException
+func TestPutFieldNullObject(t *testing.T) {
+	globals.InitGlobals("test")
+
+	normalStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	f := newFrame(opcodes.PUTFIELD)
+	f.Meth = append(f.Meth, 0x00)
+	f.Meth = append(f.Meth, 0x01) // Go to slot 0x0001 in the CP
+
+	CP := classloader.CPool{}
+	CP.CpIndex = make([]classloader.CpEntry, 10, 10)
+	CP.CpIndex[0] = classloader.CpEntry{Type: 0, Slot: 0}
+	CP.CpIndex[1] = classloader.CpEntry{Type: classloader.FieldRef, Slot: 0}
+	CP.FieldRefs = []classloader.ResolvedFieldEntry{{FldName: "value", FldType: types.Int}}
+	f.CP = &CP
+
+	push(&f, object.Null)
+	push(&f, int64(26))
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	interpret(fs)
+
+	_ = w.Close()
+	msg, _ := io.ReadAll(r)
+	os.Stderr = normalStderr
+
+	errMsg := string(msg)
+	if !strings.Contains(errMsg, "java.lang.NullPointerException") ||
+		!strings.Contains(errMsg, `Cannot assign field "value"`) {
+		t.Errorf("PUTFIELD: Expected a NullPointerException, got: %s", errMsg)
+	}
+}
+
 // PUTFIELD for a double
 func TestPutFieldDouble(t *testing.T) {
 	globals.InitGlobals("test")
@@ -1871,6 +1908,27 @@ func TestWideIINC(t *testing.T) {
 	}
 }
 
+// WIDE version of IINC with a negative increment, which is a signed 2-byte value
+func TestWideIINCNegative(t *testing.T) {
+	globals.InitGlobals("test")
+
+	f := newFrame(opcodes.WIDE)
+	f.Meth = append(f.Meth, opcodes.IINC)
+	f.Meth = append(f.Meth, 0x00) // index = 2, i.e. locals[2]
+	f.Meth = append(f.Meth, 0x02)
+
+	f.Meth = append(f.Meth, 0xFE) // amount of increment, 0xFE00 = -512
+	f.Meth = append(f.Meth, 0x00)
+	fs := frames.CreateFrameStack()
+	f.Locals = append(f.Locals, int64(10), int64(20), int64(30))
+	fs.PushFront(&f) // push the new frame
+	interpret(fs)
+
+	if f.Locals[2] != int64(-482) {
+		t.Errorf("WIDE,IINC: expected result of -482, got: %d", f.Locals[2])
+	}
+}
+
 // WIDE version of ILOAD (covers FLOAD AND ALOAD as well b/c they use the same logic)
 func TestWideILOAD(t *testing.T) {
 	globals.InitGlobals("test")
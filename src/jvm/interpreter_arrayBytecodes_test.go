@@ -441,8 +441,8 @@ func TestNewAnewrrayKlassField(t *testing.T) {
 		t.Errorf("ANEWARRAY: Expecting class to start with '[L', got %s", *klassString)
 	}
 
-	if !strings.HasSuffix(*klassString, types.StringClassName) {
-		t.Errorf("ANEWARRAY: Expecting class to end with 'java/lang/String', got %s", *klassString)
+	if !strings.HasSuffix(*klassString, types.StringClassName+";") {
+		t.Errorf("ANEWARRAY: Expecting class to end with 'java/lang/String;', got %s", *klassString)
 	}
 }
 
@@ -475,6 +475,33 @@ func TestNewAnewrrayInvalidSize(t *testing.T) {
 	}
 }
 
+// NEWARRAY: an array larger than the heap set by -Xmx throws an OutOfMemoryError
+func TestNewNewarrayExceedsMaxHeap(t *testing.T) {
+	f := newFrame(opcodes.NEWARRAY)
+	push(&f, int64(math.MaxInt32))
+	f.Meth = append(f.Meth, object.T_LONG)
+
+	globals.InitGlobals("test")
+	globals.GetGlobalRef().MaxHeapSize = 16 * 1024 * 1024
+
+	normalStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	interpret(fs)
+
+	_ = w.Close()
+	msg, _ := io.ReadAll(r)
+	os.Stderr = normalStderr
+
+	if !strings.Contains(string(msg), "java.lang.OutOfMemoryError") ||
+		!strings.Contains(string(msg), "Java heap space") {
+		t.Errorf("NEWARRAY: Expecting an OutOfMemoryError, got: %s", msg)
+	}
+}
+
 // ARRAYLENGTH: Test length of byte array
 // First, we create the array of 13 elements, then we push the reference
 // to it and execute the ARRAYLENGTH bytecode using the address stored
@@ -2410,8 +2437,8 @@ func TestNew3DimArray1(t *testing.T) {
 	}
 
 	topLevelArray := *(arrayPtr.(*object.Object))
-	if topLevelArray.FieldTable["value"].Ftype != "[L" {
-		t.Errorf("MULTIANEWARRAY: Expected 1st dim to be type '[L', got %s",
+	if topLevelArray.FieldTable["value"].Ftype != "[[[I" {
+		t.Errorf("MULTIANEWARRAY: Expected 1st dim to be type '[[[I', got %s",
 			topLevelArray.FieldTable["value"].Ftype)
 	}
 
@@ -2452,8 +2479,8 @@ func TestNew3DimArray1(t *testing.T) {
 }
 
 // MULTINEWARRAY: Test an array 4x0x3 array of int64's. The zero
-// size of the second dimension should result in an single-dimension
-// array of int64s
+// size of the second dimension should result in an array of four
+// empty int[][] arrays, and no third dimension
 func TestNew3DimArray2(t *testing.T) {
 	g := globals.InitGlobals("test")
 	g.JacobinName = "test" // prevents a shutdown when the exception hits.
@@ -2478,9 +2505,9 @@ func TestNew3DimArray2(t *testing.T) {
 	f.Meth = append(f.Meth, 0x00) // this byte and next form index into CP
 	f.Meth = append(f.Meth, 0x02)
 	f.Meth = append(f.Meth, 0x03) // the number of dimensions
-	push(&f, int64(0x03))         // size of the three dimensions: 4x0x3
+	push(&f, int64(0x04))         // size of the three dimensions: 4x0x3
 	push(&f, int64(0x00))
-	push(&f, int64(0x04))
+	push(&f, int64(0x03))
 	f.CP = &CP
 
 	fs := frames.CreateFrameStack()
@@ -2500,16 +2527,23 @@ func TestNew3DimArray2(t *testing.T) {
 	}
 
 	topLevelArray := *(arrayPtr.(*object.Object))
-	if topLevelArray.FieldTable["value"].Ftype != "[I" {
-		t.Errorf("MULTIANEWARRAY: Expected 1st dim to be type '[I', got %s",
+	if topLevelArray.FieldTable["value"].Ftype != "[[[I" {
+		t.Errorf("MULTIANEWARRAY: Expected 1st dim to be type '[[[I', got %s",
 			topLevelArray.FieldTable["value"].Ftype)
 	}
 
-	dim1 := topLevelArray.FieldTable["value"].Fvalue.([]int64)
+	dim1 := topLevelArray.FieldTable["value"].Fvalue.([]*object.Object)
 	if len(dim1) != 4 {
 		t.Errorf("MULTINEWARRAY: Expected 1st dim to have 4 elements, got: %d",
 			len(dim1))
 	}
+
+	// as in the JDK, each element is an empty int[][]
+	dim2 := dim1[3].FieldTable["value"]
+	if dim2.Ftype != "[[I" || len(dim2.Fvalue.([]*object.Object)) != 0 {
+		t.Errorf("MULTIANEWARRAY: Expected 2nd dim to be an empty '[[I', got %s of %d elements",
+			dim2.Ftype, len(dim2.Fvalue.([]*object.Object)))
+	}
 }
 
 // NEWARRAY: creation of array for primitive values
@@ -2765,3 +2799,202 @@ func TestNewSastore(t *testing.T) {
 		t.Errorf("SASTORE: Expected sum of array entries to be 100, got: %d", sum)
 	}
 }
+
+// creates a frame that executes MULTIANEWARRAY on an array of arrayType, with the sizes
+// of the dimensions to create, outermost first, on the operand stack
+func multianewarrayFrame(arrayType string, dimSizes ...int64) frames.Frame {
+	CP := classloader.CPool{}
+	CP.CpIndex = make([]classloader.CpEntry, 10, 10)
+	CP.CpIndex[0] = classloader.CpEntry{Type: 0, Slot: 0}
+	CP.CpIndex[1] = classloader.CpEntry{Type: classloader.UTF8, Slot: 0}
+	CP.CpIndex[2] = classloader.CpEntry{Type: classloader.ClassRef, Slot: 0}
+	CP.ClassRefs = append(CP.ClassRefs, stringPool.GetStringIndex(&arrayType))
+	CP.Utf8Refs = append(CP.Utf8Refs, arrayType)
+
+	f := newFrame(opcodes.MULTIANEWARRAY)
+	f.Meth = append(f.Meth, 0x00) // this byte and next form index into CP
+	f.Meth = append(f.Meth, 0x02)
+	f.Meth = append(f.Meth, byte(len(dimSizes))) // the number of dimensions
+	for _, size := range dimSizes {
+		push(&f, size)
+	}
+	f.CP = &CP
+	return f
+}
+
+// runs the frame with stderr captured, returning what was written to it
+func interpretCapturingStderr(f *frames.Frame) string {
+	normalStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(f)
+	interpret(fs)
+
+	_ = w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stderr = normalStderr
+	return string(out)
+}
+
+// MULTIANEWARRAY: a 2x3 String[][]. Each row is a String[] of nulls.
+func TestMultianewarrayStrings(t *testing.T) {
+	globals.InitGlobals("test")
+
+	f := multianewarrayFrame("[[Ljava/lang/String;", 2, 3)
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f)
+	interpret(fs)
+
+	arr := pop(&f).(*object.Object)
+	if arr.FieldTable["value"].Ftype != "[[Ljava/lang/String;" {
+		t.Errorf("MULTIANEWARRAY: Expected type '[[Ljava/lang/String;', got %s", arr.FieldTable["value"].Ftype)
+	}
+	rows := arr.FieldTable["value"].Fvalue.([]*object.Object)
+	if len(rows) != 2 {
+		t.Fatalf("MULTIANEWARRAY: Expected 2 rows, got %d", len(rows))
+	}
+	for _, row := range rows {
+		if row.FieldTable["value"].Ftype != "[Ljava/lang/String;" {
+			t.Errorf("MULTIANEWARRAY: Expected row type '[Ljava/lang/String;', got %s", row.FieldTable["value"].Ftype)
+		}
+		if klass := stringPool.GetStringPointer(row.KlassName); *klass != "[Ljava/lang/String;" {
+			t.Errorf("MULTIANEWARRAY: Expected row class '[Ljava/lang/String;', got %s", *klass)
+		}
+		elems := row.FieldTable["value"].Fvalue.([]*object.Object)
+		if len(elems) != 3 || !object.IsNull(elems[0]) {
+			t.Errorf("MULTIANEWARRAY: Expected a row of 3 nulls, got %d elements", len(elems))
+		}
+	}
+}
+
+// MULTIANEWARRAY: arrays of more than three dimensions are created all the way down
+func TestMultianewarrayFiveDims(t *testing.T) {
+	globals.InitGlobals("test")
+
+	f := multianewarrayFrame("[[[[[J", 2, 2, 2, 2, 3)
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f)
+	interpret(fs)
+
+	arr := pop(&f).(*object.Object)
+	for _, expected := range []string{"[[[[[J", "[[[[J", "[[[J", "[[J"} {
+		if arr.FieldTable["value"].Ftype != expected {
+			t.Fatalf("MULTIANEWARRAY: Expected type %s, got %s", expected, arr.FieldTable["value"].Ftype)
+		}
+		arr = arr.FieldTable["value"].Fvalue.([]*object.Object)[1]
+	}
+	leaf, ok := arr.FieldTable["value"].Fvalue.([]int64)
+	if !ok || len(leaf) != 3 {
+		t.Errorf("MULTIANEWARRAY: Expected a leaf array of 3 longs, got %T", arr.FieldTable["value"].Fvalue)
+	}
+}
+
+// MULTIANEWARRAY: a negative size in any dimension throws NegativeArraySizeException
+func TestMultianewarrayNegativeSize(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+
+	f := multianewarrayFrame("[[I", 2, -1)
+	errMsg := interpretCapturingStderr(&f)
+	if !strings.Contains(errMsg, "NegativeArraySizeException") {
+		t.Errorf("MULTIANEWARRAY: Expected NegativeArraySizeException, got: %s", errMsg)
+	}
+}
+
+// MULTIANEWARRAY: new int[3][] creates only the first dimension, whose elements are
+// null until rows of any length are stored in them. AASTORE accepts an int[] but not
+// a String[].
+func TestMultianewarrayJagged(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+
+	f := multianewarrayFrame("[[I", 3)
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f)
+	interpret(fs)
+
+	arr := pop(&f).(*object.Object)
+	rows := arr.FieldTable["value"].Fvalue.([]*object.Object)
+	if len(rows) != 3 || !object.IsNull(rows[0]) || !object.IsNull(rows[2]) {
+		t.Fatalf("MULTIANEWARRAY: Expected 3 null rows, got %v", rows)
+	}
+
+	for i, size := range []int64{1, 5} {
+		f = newFrame(opcodes.AASTORE)
+		push(&f, arr)
+		push(&f, int64(i))
+		push(&f, object.Make1DimArray(object.INT, size))
+		if ret := doAastore(&f, 0); ret != 1 {
+			t.Errorf("AASTORE: Expected an int[] to be stored in an int[][], got return %d", ret)
+		}
+	}
+	if len(rows[1].FieldTable["value"].Fvalue.([]int64)) != 5 {
+		t.Errorf("AASTORE: Expected row 1 to have 5 elements")
+	}
+
+	f = newFrame(opcodes.AASTORE)
+	push(&f, arr)
+	push(&f, int64(2))
+	push(&f, object.Make1DimRefArray("java/lang/String;", 2))
+	errMsg := interpretCapturingStderr(&f)
+	if !strings.Contains(errMsg, "ArrayStoreException") {
+		t.Errorf("AASTORE: Expected ArrayStoreException, got: %s", errMsg)
+	}
+	if !object.IsNull(rows[2]) {
+		t.Errorf("AASTORE: Expected row 2 to remain null")
+	}
+}
+
+// AASTORE: an Integer can't be stored in a String[], even one passed as an Object[]
+func TestAastoreCovariance(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+	for _, name := range []string{types.StringClassName, "java/lang/Integer"} {
+		k := classloader.Klass{Status: 'F', Loader: "testloader", Data: &classloader.ClData{
+			Name:            name,
+			SuperclassIndex: types.ObjectPoolStringIndex,
+		}}
+		classloader.MethAreaInsert(name, &k)
+	}
+
+	arr := object.Make1DimRefArray("java/lang/String;", 2)
+	className := "java/lang/Integer"
+	integer := object.MakeEmptyObjectWithClassName(&className)
+
+	f := newFrame(opcodes.AASTORE)
+	push(&f, arr)
+	push(&f, int64(0))
+	push(&f, object.StringObjectFromGoString("ok"))
+	if ret := doAastore(&f, 0); ret != 1 {
+		t.Errorf("AASTORE: Expected a String to be stored in a String[], got return %d", ret)
+	}
+
+	f = newFrame(opcodes.AASTORE)
+	push(&f, arr)
+	push(&f, int64(1))
+	push(&f, integer)
+	errMsg := interpretCapturingStderr(&f)
+	if !strings.Contains(errMsg, "java.lang.Integer cannot be stored in an array of java.lang.String") {
+		t.Errorf("AASTORE: Expected ArrayStoreException for an Integer, got: %s", errMsg)
+	}
+	if !object.IsNull(arr.FieldTable["value"].Fvalue.([]*object.Object)[1]) {
+		t.Errorf("AASTORE: Expected the Integer not to be stored")
+	}
+}
+
+// AALOAD: a negative index throws ArrayIndexOutOfBoundsException
+func TestAaloadNegativeSubscript(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+
+	f := newFrame(opcodes.AALOAD)
+	push(&f, object.Make1DimRefArray(types.ObjectClassName, 10))
+	push(&f, int64(-1))
+	errMsg := interpretCapturingStderr(&f)
+	if !strings.Contains(errMsg, "ArrayIndexOutOfBoundsException") {
+		t.Errorf("AALOAD: Expected ArrayIndexOutOfBoundsException, got: %s", errMsg)
+	}
+}
@@ -0,0 +1,53 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package jvm
+
+import (
+	"fmt"
+	"jacobin/src/exceptions"
+	"jacobin/src/frames"
+	"jacobin/src/gfunction"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/trace"
+)
+
+// Intrinsics. A few static methods are called so often, and do so little work per call,
+// that the cost of invoking them through INVOKESTATIC dominates: resolving the method
+// reference, checking that the class has been initialized, and passing the arguments to
+// RunGfunction() in a slice. INVOKESTATIC runs these methods itself: the intrinsic pops
+// the arguments from the operand stack and does the work of the gfunction that implements
+// the method, which remains registered for the other ways of calling it (reflection,
+// method handles, and upcalls). An intrinsic returns what a bytecode function returns.
+
+var intrinsics = map[string]func(fr *frames.Frame) int{
+	"java/lang/System.arraycopy(Ljava/lang/Object;ILjava/lang/Object;II)V": intrinsicArraycopy,
+}
+
+// System.arraycopy(Object src, int srcPos, Object dest, int destPos, int length)
+func intrinsicArraycopy(fr *frames.Frame) int {
+	length, _ := pop(fr).(int64)
+	destPos, _ := pop(fr).(int64)
+	dest, _ := pop(fr).(*object.Object)
+	srcPos, _ := pop(fr).(int64)
+	src, _ := pop(fr).(*object.Object)
+
+	if globals.TraceInst {
+		infoMsg := fmt.Sprintf("intrinsic: java/lang/System.arraycopy, srcPos=%d, destPos=%d, length=%d",
+			srcPos, destPos, length)
+		trace.Log(trace.JVM, trace.LevelTrace, fr.Thread, infoMsg)
+	}
+
+	if gErr := gfunction.ArrayCopy(src, srcPos, dest, destPos, length); gErr != nil {
+		status := exceptions.ThrowEx(gErr.ExceptionType, gErr.ErrMsg, fr)
+		if status != exceptions.Caught {
+			return exceptions.ERROR_OCCURRED // applies only if in test
+		}
+		return exceptions.RESUME_HERE // caught
+	}
+	return 3 // the length of INVOKESTATIC and its operand
+}
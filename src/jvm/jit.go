@@ -0,0 +1,269 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package jvm
+
+import (
+	"fmt"
+	"jacobin/src/classloader"
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/opcodes"
+	"jacobin/src/trace"
+	"sync"
+	"sync/atomic"
+)
+
+// The JIT. Jacobin first runs a method in the interpreter, which, for every instruction,
+// fetches the opcode, looks up its function in the dispatch table, and then has the
+// function decode its operands from the bytecode. Once a method has been invoked
+// -XX:CompileThreshold times (see globals.DefaultCompileThreshold), it's queued for
+// compilation by a background goroutine, which translates its bytecode into a slice
+// of functions, one per instruction, indexed by PC. The instructions whose operands
+// are worth decoding ahead of time (local variable loads and stores, IINC, the
+// pushes of constants, and the branches) become closures with their operands bound;
+// the others use their function in the dispatch table. Frames created for the method
+// after it has been compiled execute the compiled code (see interpret()), which has
+// the same effect as the interpreter's, including exception handling, since each
+// function advances the PC or throws exactly as the interpreter does.
+//
+// A method that is invoked only once but loops for a long time, such as main(), would
+// never reach the compile threshold, so the interpreter also counts the backward
+// branches a method takes. Once they reach -XX:BackEdgeThreshold, the method is
+// queued for compilation, and when it has been compiled, the frames that are running
+// it switch to the compiled code at their next backward branch: on-stack replacement.
+// Since the compiled code works on the same frame as the interpreter, with the same
+// PCs, locals, and operand stack, nothing needs to be translated when it switches.
+//
+// Methods that contain the WIDE bytecode are compiled without bound operands, since
+// WIDE changes the width of the operands of the instruction that follows it.
+//
+// -Xint disables the JIT, as does tracing of instructions (-trace:inst).
+
+// the invocation and backward branch counts and compiled code of a method
+type jitMethod struct {
+	fqn         string
+	bytecode    []byte
+	invocations atomic.Int64
+	backEdges   atomic.Int64
+	queued      atomic.Bool // the method is queued for compilation or compiled
+	compiled    atomic.Pointer[[]func(*frames.Frame, int64) int]
+}
+
+// the methods that have been invoked, keyed by the address of their bytecode, which
+// identifies a method without the cost of building its FQN on every invocation
+var jitMethods sync.Map
+
+// the methods waiting to be compiled
+var jitQueue = make(chan *jitMethod, 256)
+var jitCompilerStarted sync.Once
+
+// the compiler, which is compileMethod(). It's assigned in init() because compileMethod()
+// refers to DispatchTable, whose functions create frames, which calls jitCodeFor(): an
+// initialization cycle that Go reports, although it can never occur. (See the comments
+// on initializeDispatchTable() for the same issue.)
+var jitCompile func(code []byte) []func(*frames.Frame, int64) int
+
+func init() {
+	jitCompile = compileMethod
+}
+
+// jitCodeFor counts an invocation of a method and returns its compiled code, or nil
+// if it has not been compiled (yet), along with the JIT's record of the method, which
+// is nil if the JIT is disabled. It queues the method for compilation when the count
+// reaches the compile threshold.
+func jitCodeFor(className, methName, methType string, code []byte) ([]func(*frames.Frame, int64) int, *jitMethod) {
+	glob := globals.GetGlobalRef()
+	if glob.InterpretOnly || globals.TraceInst || len(code) == 0 {
+		return nil, nil
+	}
+
+	entry, ok := jitMethods.Load(&code[0])
+	if !ok {
+		entry, _ = jitMethods.LoadOrStore(&code[0],
+			&jitMethod{fqn: className + "." + methName + methType, bytecode: code})
+	}
+	jm := entry.(*jitMethod)
+	if compiled := jm.compiled.Load(); compiled != nil {
+		return *compiled, jm
+	}
+
+	if jm.invocations.Add(1) >= int64(glob.JitThreshold) {
+		jm.enqueue()
+	}
+	return nil, jm
+}
+
+// jitBackEdge counts a backward branch taken by a frame that is running a method in
+// the interpreter, and returns the method's compiled code if it has been compiled, so
+// the frame can switch to it. It queues the method for compilation when the count
+// reaches the back-edge threshold.
+func jitBackEdge(fr *frames.Frame) []func(*frames.Frame, int64) int {
+	jm, ok := fr.JitMethod.(*jitMethod)
+	if !ok {
+		return nil
+	}
+	if compiled := jm.compiled.Load(); compiled != nil {
+		if globals.TraceVerbose {
+			trace.Trace(fmt.Sprintf("JIT: on-stack replacement of %s at PC %d", jm.fqn, fr.PC))
+		}
+		return *compiled
+	}
+
+	if jm.backEdges.Add(1) >= int64(globals.GetGlobalRef().OSRThreshold) {
+		jm.enqueue()
+	}
+	return nil
+}
+
+// queues the method for compilation, unless it's already queued
+func (jm *jitMethod) enqueue() {
+	if !jm.queued.CompareAndSwap(false, true) {
+		return
+	}
+	jitCompilerStarted.Do(func() { go runJitCompiler() })
+	select {
+	case jitQueue <- jm:
+	default: // the queue is full, so start counting again
+		jm.invocations.Store(0)
+		jm.backEdges.Store(0)
+		jm.queued.Store(false)
+	}
+}
+
+// the compiler goroutine, which compiles the methods in the queue, one at a time
+func runJitCompiler() {
+	for jm := range jitQueue {
+		compiled := jitCompile(jm.bytecode)
+		if compiled == nil {
+			if globals.TraceVerbose {
+				trace.Trace("JIT: cannot compile " + jm.fqn)
+			}
+			continue
+		}
+		jm.compiled.Store(&compiled)
+		if globals.TraceVerbose {
+			trace.Trace(fmt.Sprintf("JIT: compiled %s (%d bytes of bytecode)", jm.fqn, len(jm.bytecode)))
+		}
+	}
+}
+
+// compiles the bytecode of a method into a function for each instruction, indexed by
+// the PC of the instruction. Returns nil if the bytecode is truncated or invalid.
+func compileMethod(code []byte) []func(*frames.Frame, int64) int {
+	hasWide := false
+	for pc := 0; pc < len(code); {
+		length := classloader.BytecodeLength(code, pc)
+		if length == 0 || int(code[pc]) >= len(DispatchTable) || DispatchTable[code[pc]] == nil {
+			return nil
+		}
+		hasWide = hasWide || code[pc] == opcodes.WIDE
+		pc += length
+	}
+
+	compiled := make([]func(*frames.Frame, int64) int, len(code))
+	for pc := 0; pc < len(code); pc += classloader.BytecodeLength(code, pc) {
+		compiled[pc] = DispatchTable[code[pc]]
+		if code[pc] == opcodes.WIDE { // doWide() advances the PC to the instruction it widens
+			compiled[pc+1] = DispatchTable[code[pc+1]]
+			continue
+		}
+		if !hasWide {
+			if bound := compileWithOperands(code, pc); bound != nil {
+				compiled[pc] = bound
+			}
+		}
+	}
+	return compiled
+}
+
+// returns a closure for the instruction at pc with its operands bound, or nil if the
+// instruction should use its function in the dispatch table. The closures must do
+// exactly what the dispatch table's functions do.
+func compileWithOperands(code []byte, pc int) func(*frames.Frame, int64) int {
+	op := code[pc]
+	switch {
+	case op == opcodes.BIPUSH:
+		value := byteToInt64(code[pc+1])
+		return func(fr *frames.Frame, _ int64) int { push(fr, value); return 2 }
+
+	case op == opcodes.SIPUSH:
+		value := int64(int16(uint16(code[pc+1])<<8 | uint16(code[pc+2])))
+		return func(fr *frames.Frame, _ int64) int { push(fr, value); return 3 }
+
+	case op >= opcodes.ILOAD && op <= opcodes.ALOAD: // see doLoad()
+		index := int(code[pc+1])
+		return func(fr *frames.Frame, _ int64) int { push(fr, fr.Locals[index]); return 2 }
+
+	case op == opcodes.ISTORE || op == opcodes.LSTORE: // see doIstore()
+		index := int(code[pc+1])
+		return func(fr *frames.Frame, _ int64) int {
+			fr.Locals[index] = convertInterfaceToInt64(pop(fr))
+			return 2
+		}
+
+	case op == opcodes.FSTORE || op == opcodes.DSTORE: // see doFstore()
+		index := int(code[pc+1])
+		return func(fr *frames.Frame, _ int64) int { fr.Locals[index] = pop(fr).(float64); return 2 }
+
+	case op == opcodes.ASTORE:
+		index := int(code[pc+1])
+		return func(fr *frames.Frame, _ int64) int { fr.Locals[index] = pop(fr); return 2 }
+
+	case op == opcodes.IINC:
+		index, increment := int(code[pc+1]), byteToInt64(code[pc+2])
+		return func(fr *frames.Frame, _ int64) int { iinc(fr, index, increment); return 3 }
+
+	case op == opcodes.GOTO:
+		jump := jumpOffset(code, pc)
+		return func(_ *frames.Frame, _ int64) int { return jump }
+
+	case op >= opcodes.IFEQ && op <= opcodes.IFLE: // see doIfeq(), etc.
+		test, jump := intComparison(op-opcodes.IFEQ), jumpOffset(code, pc)
+		return func(fr *frames.Frame, _ int64) int {
+			if test(convertInterfaceToInt64(pop(fr)), 0) {
+				return jump
+			}
+			return 3
+		}
+
+	case op >= opcodes.IF_ICMPEQ && op <= opcodes.IF_ICMPLE: // see doIficmpeq(), etc.
+		test, jump := intComparison(op-opcodes.IF_ICMPEQ), jumpOffset(code, pc)
+		return func(fr *frames.Frame, _ int64) int {
+			val2 := convertInterfaceToInt64(pop(fr))
+			val1 := convertInterfaceToInt64(pop(fr))
+			if test(val1, val2) {
+				return jump
+			}
+			return 3
+		}
+	}
+	return nil
+}
+
+// returns the offset of the target of the branch at pc, as decoded by doGoto(), etc.
+func jumpOffset(code []byte, pc int) int {
+	return int((int16(code[pc+1]) * 256) + int16(code[pc+2]))
+}
+
+// returns the comparison of the IF and IF_ICMP bytecodes, given the bytecode's offset
+// from IFEQ or IF_ICMPEQ: 0 = eq, 1 = ne, 2 = lt, 3 = ge, 4 = gt, 5 = le
+func intComparison(offset byte) func(a, b int64) bool {
+	switch offset {
+	case 0:
+		return func(a, b int64) bool { return a == b }
+	case 1:
+		return func(a, b int64) bool { return a != b }
+	case 2:
+		return func(a, b int64) bool { return a < b }
+	case 3:
+		return func(a, b int64) bool { return a >= b }
+	case 4:
+		return func(a, b int64) bool { return a > b }
+	default:
+		return func(a, b int64) bool { return a <= b }
+	}
+}
@@ -0,0 +1,243 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package jvm
+
+import (
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/opcodes"
+	"jacobin/src/trace"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// static void sum(int n) { int sum = 0; for (int i = 0; i < n; i++) { sum += i; } }
+// with the loads and stores in their long forms, so they are compiled with bound operands
+var jitSumLoop = []byte{
+	opcodes.ICONST_0, opcodes.ISTORE_1, // sum = 0
+	opcodes.ICONST_0, opcodes.ISTORE_2, // i = 0
+	opcodes.ILOAD, 2, // 4: loop test
+	opcodes.ILOAD, 0,
+	opcodes.IF_ICMPGE, 0, 16, // to 24, the end
+	opcodes.ILOAD, 1, // 11: sum += i
+	opcodes.ILOAD, 2,
+	opcodes.IADD,
+	opcodes.ISTORE, 1,
+	opcodes.IINC, 2, 1, // 18: i++
+	opcodes.GOTO, 0xFF, 0xEF, // 21: back to 4
+}
+
+// runs the code in a new frame, compiled if compiled is true, with n in local 0,
+// and returns the frame
+func runJitTestCode(code []byte, n int64, compiled bool) *frames.Frame {
+	fr := frames.CreateFrame(4)
+	fr.Meth = append(fr.Meth, code...)
+	fr.Locals = []any{n, int64(0), int64(0)}
+	if compiled {
+		fr.Compiled = compileMethod(code)
+	}
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(fr)
+	interpret(fs)
+	return fr
+}
+
+// the function at a PC of compiled code, or in the dispatch table, for comparisons
+func funcAddress(f func(*frames.Frame, int64) int) uintptr {
+	return reflect.ValueOf(f).Pointer()
+}
+
+func TestJitCompileMethodBindsOperands(t *testing.T) {
+	globals.InitGlobals("test")
+	initializeDispatchTable()
+
+	compiled := compileMethod(jitSumLoop)
+	if len(compiled) != len(jitSumLoop) {
+		t.Fatalf("Expected a function for each byte of bytecode, got %d of %d", len(compiled), len(jitSumLoop))
+	}
+	for pc, want := range map[int]bool{0: false, 1: false, 4: true, 8: true, 15: false, 16: true, 18: true, 21: true} {
+		bound := funcAddress(compiled[pc]) != funcAddress(DispatchTable[jitSumLoop[pc]])
+		if bound != want {
+			t.Errorf("At PC %d (%s), expected bound operands to be %v", pc, opcodes.BytecodeNames[jitSumLoop[pc]], want)
+		}
+	}
+	if compiled[5] != nil || compiled[9] != nil {
+		t.Error("Expected no functions at the PCs of operands")
+	}
+}
+
+func TestJitCompiledCodeMatchesInterpreter(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+
+	for _, n := range []int64{0, 1, 10, 100} {
+		interpreted := runJitTestCode(jitSumLoop, n, false)
+		compiled := runJitTestCode(jitSumLoop, n, true)
+		if compiled.Locals[1] != interpreted.Locals[1] || compiled.Locals[2] != interpreted.Locals[2] {
+			t.Errorf("For n = %d, expected sum and i of %v and %v, got: %v and %v", n,
+				interpreted.Locals[1], interpreted.Locals[2], compiled.Locals[1], compiled.Locals[2])
+		}
+		if compiled.Locals[1] != n*(n-1)/2 && n > 0 {
+			t.Errorf("For n = %d, expected a sum of %d, got: %v", n, n*(n-1)/2, compiled.Locals[1])
+		}
+	}
+}
+
+func TestJitCompiledConstants(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+
+	// BIPUSH -5, SIPUSH -300, SIPUSH 1000, IFLT +5 (taken), ICONST_1, ISTORE_2
+	code := []byte{opcodes.BIPUSH, 0xFB, opcodes.SIPUSH, 0xFE, 0xD4, opcodes.SIPUSH, 0x03, 0xE8,
+		opcodes.ISTORE_1, opcodes.ISTORE_2, opcodes.ILOAD_2, opcodes.IFLT, 0, 5, opcodes.ICONST_1, opcodes.ISTORE_2}
+	fr := runJitTestCode(code, 0, true)
+	if fr.Locals[1] != int64(1000) || fr.Locals[2] != int64(-300) {
+		t.Errorf("Expected locals 1 and 2 to be 1000 and -300, got: %v and %v", fr.Locals[1], fr.Locals[2])
+	}
+	if fr.TOS != 0 || fr.OpStack[0] != int64(-5) {
+		t.Errorf("Expected -5 on the op stack, got a TOS of %d and %v", fr.TOS, fr.OpStack[0])
+	}
+}
+
+func TestJitWideMethodUsesDispatchTable(t *testing.T) {
+	globals.InitGlobals("test")
+	initializeDispatchTable()
+
+	code := []byte{opcodes.WIDE, opcodes.ILOAD, 0, 1, opcodes.ILOAD, 0, opcodes.IADD, opcodes.IRETURN}
+	compiled := compileMethod(code)
+	if compiled == nil {
+		t.Fatal("Expected a method with WIDE to be compiled")
+	}
+	for _, pc := range []int{0, 1, 4, 6, 7} {
+		if funcAddress(compiled[pc]) != funcAddress(DispatchTable[code[pc]]) {
+			t.Errorf("At PC %d, expected the function in the dispatch table", pc)
+		}
+	}
+}
+
+func TestJitInvalidBytecodeIsNotCompiled(t *testing.T) {
+	globals.InitGlobals("test")
+
+	if compileMethod([]byte{opcodes.ICONST_0, opcodes.BIPUSH}) != nil {
+		t.Error("Expected truncated bytecode not to be compiled")
+	}
+	if compileMethod([]byte{opcodes.ICONST_0, 0xFE}) != nil {
+		t.Error("Expected an invalid bytecode not to be compiled")
+	}
+}
+
+func TestJitCompilesAtThreshold(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	glob := globals.GetGlobalRef()
+	jitMethods = sync.Map{}
+	glob.JitThreshold = 3
+
+	code := append([]byte{}, jitSumLoop...)
+	for i := 0; i < glob.JitThreshold; i++ {
+		if compiled, _ := jitCodeFor("jit/Test", "sum", "(I)V", code); compiled != nil {
+			t.Fatalf("Did not expect compiled code before the threshold, at invocation %d", i+1)
+		}
+	}
+
+	_, jm := jitCodeFor("jit/Test", "sum", "(I)V", code)
+	waitForJit(t, jm)
+	if compiled, _ := jitCodeFor("jit/Test", "sum", "(I)V", code); compiled == nil {
+		t.Error("Expected the compiled code for invocations after the method is compiled")
+	}
+}
+
+func TestJitDisabledByXint(t *testing.T) {
+	globals.InitGlobals("test")
+	glob := globals.GetGlobalRef()
+	jitMethods = sync.Map{}
+	glob.JitThreshold = 1
+
+	if _, err := interpretOnly(0, "-Xint", glob); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	code := append([]byte{}, jitSumLoop...)
+	for i := 0; i < 3; i++ {
+		if compiled, jm := jitCodeFor("jit/Test", "sum", "(I)V", code); compiled != nil || jm != nil {
+			t.Fatal("Did not expect compiled code with -Xint")
+		}
+	}
+	if _, ok := jitMethods.Load(&code[0]); ok {
+		t.Error("Did not expect the invocations of a method to be counted with -Xint")
+	}
+}
+
+// waits for the compiler to compile a method, and returns its compiled code
+func waitForJit(t *testing.T, jm *jitMethod) []func(*frames.Frame, int64) int {
+	deadline := time.Now().Add(5 * time.Second)
+	for jm.compiled.Load() == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the method to be compiled")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return *jm.compiled.Load()
+}
+
+func TestJitBackEdgesTriggerCompilation(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	glob := globals.GetGlobalRef()
+	jitMethods = sync.Map{}
+	glob.OSRThreshold = 5
+
+	code := append([]byte{}, jitSumLoop...)
+	_, jm := jitCodeFor("jit/Test", "main", "(I)V", code)
+	fr := frames.CreateFrame(4)
+	fr.JitMethod = jm
+	for i := 0; i < glob.OSRThreshold-1; i++ {
+		if jitBackEdge(fr) != nil || jm.queued.Load() {
+			t.Fatalf("Did not expect the method to be queued before the threshold, at back edge %d", i+1)
+		}
+	}
+	jitBackEdge(fr)
+	if !jm.queued.Load() {
+		t.Fatal("Expected the method to be queued at the back-edge threshold")
+	}
+	waitForJit(t, jm)
+	if jitBackEdge(fr) == nil {
+		t.Error("Expected a back edge to return the compiled code once the method is compiled")
+	}
+}
+
+func TestJitOnStackReplacement(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	glob := globals.GetGlobalRef()
+	jitMethods = sync.Map{}
+	glob.OSRThreshold = 1
+
+	code := append([]byte{}, jitSumLoop...)
+	_, jm := jitCodeFor("jit/Test", "main", "(I)V", code)
+	jitBackEdge(&frames.Frame{JitMethod: jm})
+	waitForJit(t, jm)
+
+	// the frame starts in the interpreter, then switches to the compiled code at the
+	// first backward branch, the GOTO at the end of the first iteration
+	fr := frames.CreateFrame(4)
+	fr.Meth = append(fr.Meth, code...)
+	fr.Locals = []any{int64(10), int64(0), int64(0)}
+	fr.JitMethod = jm
+	fs := frames.CreateFrameStack()
+	fs.PushFront(fr)
+	interpret(fs)
+
+	if fr.Compiled == nil {
+		t.Error("Expected the frame to switch to the compiled code")
+	}
+	if fr.Locals[1] != int64(45) || fr.Locals[2] != int64(10) {
+		t.Errorf("Expected sum and i of 45 and 10, got: %v and %v", fr.Locals[1], fr.Locals[2])
+	}
+}
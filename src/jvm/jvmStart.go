@@ -7,18 +7,22 @@
 package jvm
 
 import (
+	"errors"
 	"fmt"
 	"jacobin/src/classloader"
+	"jacobin/src/container"
 	"jacobin/src/exceptions"
 	"jacobin/src/gfunction"
 	"jacobin/src/globals"
+	"jacobin/src/replay"
+	"jacobin/src/selftest"
 	"jacobin/src/shutdown"
 	"jacobin/src/statics"
 	"jacobin/src/stringPool"
 	"jacobin/src/thread"
 	"jacobin/src/trace"
-	"jacobin/src/types"
 	"os"
+	"runtime"
 )
 
 var globPtr *globals.Globals
@@ -58,6 +62,7 @@ func JVMrun() int {
 		// Not a test!
 		_ = globals.InitGlobals(os.Args[0])
 		stringPool.PreloadArrayClassesToStringPool()
+		stringPool.PreloadDescriptorsToStringPool()
 	}
 	globPtr = globals.GetGlobalRef()
 
@@ -87,6 +92,30 @@ func JVMrun() int {
 		return shutdown.Exit(shutdown.OK)
 	}
 
+	// -selftest runs the conformance suite, each test in its own Jacobin process
+	if globPtr.SelfTestDir != "" {
+		return runSelfTest(globPtr.SelfTestDir)
+	}
+
+	// -record and -replay log the program's nondeterministic inputs, or repeat them
+	if globPtr.RecordFile != "" || globPtr.ReplayFile != "" {
+		if err = startRecordOrReplay(globPtr); err != nil {
+			trace.Error(err.Error())
+			return shutdown.Exit(shutdown.JVM_EXCEPTION)
+		}
+		shutdown.AddExitHook(func() { _ = replay.Close() })
+	}
+
+	// in a container, the default heap size and processor count respect its limits
+	if globPtr.UseContainer {
+		applyContainerLimits(globPtr, container.Detect())
+	}
+
+	// -XX:ActiveProcessorCount limits the processors Jacobin uses
+	if globPtr.ActiveCPUs > 0 {
+		runtime.GOMAXPROCS(globPtr.ActiveCPUs)
+	}
+
 	// Initialize classloaders and method area
 	err = classloader.Init()
 	if err != nil {
@@ -94,6 +123,18 @@ func JVMrun() int {
 	}
 	classloader.LoadBaseClasses() // must follow classloader.Init()
 
+	// --list-gfunctions and --api-coverage report on the gfunctions and run no program
+	if globPtr.ListGfuncs {
+		classloader.MTable = make(map[string]classloader.MTentry)
+		gfunction.MTableLoadGFunctions(&classloader.MTable)
+		if globPtr.APICoverage {
+			gfunction.PrintGfunctionList(os.Stdout, classloader.JDKClassMethods)
+		} else {
+			gfunction.PrintGfunctionList(os.Stdout, nil)
+		}
+		return shutdown.Exit(shutdown.OK)
+	}
+
 	var mainClassNameIndex uint32
 	if globPtr.StartingJar != "" { // if a jar file was specified, then load the main class from it
 		manifestClass, err := classloader.GetMainClassFromJar(classloader.BootstrapCL, globPtr.StartingJar)
@@ -124,14 +165,6 @@ func JVMrun() int {
 		return shutdown.Exit(shutdown.APP_EXCEPTION)
 	}
 
-	// if assertions were enabled on the command line for the program, then make sure
-	// that the assertion status is set in the Statics table w/ an entry corresponding
-	// to the main class. 	// Otherwise, it was previously initialized to "disabled".
-	if globPtr.Options["-ea"].Set {
-		_ = statics.AddStatic("main.$assertionsDisabled",
-			statics.Static{Type: types.Int, Value: types.JavaBoolFalse})
-	}
-
 	// the following was commented out per JACOBIN-327. Likely to be reinstated at some later point.
 	// Preload the main class and its dependencies.
 	// classloader.LoadReferencedClasses(mainClass)
@@ -140,15 +173,44 @@ func JVMrun() int {
 	classloader.MTable = make(map[string]classloader.MTentry)
 	gfunction.MTableLoadGFunctions(&classloader.MTable)
 
+	// with -XX:+PrintGfunctionUsageAtExit, print the report however the program ends
+	if globPtr.PrintGfuncUsage {
+		shutdown.AddExitHook(func() { gfunction.PrintGfunctionUsage(os.Stderr) })
+	}
+
+	// with -XX:+PrintMTableAtExit, likewise print the methods that were resolved
+	if globPtr.PrintMTable {
+		shutdown.AddExitHook(func() { classloader.PrintMTable(os.Stderr, "") })
+	}
+
+	// with -XX:+CountBytecodes, likewise print the bytecode statistics
+	if globPtr.CountBytecodes {
+		shutdown.AddExitHook(func() { PrintBytecodeStats(os.Stderr) })
+	}
+
+	// with --validate-only, check the program's classes, but run nothing
+	if globPtr.ValidateOnly {
+		if validateProgram(*stringPool.GetStringPointer(mainClassNameIndex), os.Stdout) > 0 {
+			return shutdown.Exit(shutdown.APP_EXCEPTION)
+		}
+		return shutdown.Exit(shutdown.OK)
+	}
+
 	// create the main thread
 	MainThread = thread.CreateThread()
 	MainThread.AddThreadToTable(globPtr)
+	startThreadDumpOnSignal() // as in HotSpot, SIGQUIT prints a thread dump (see safepoint.go)
 
 	mainClass := stringPool.GetStringPointer(mainClassNameIndex)
 	if globals.TraceInit {
 		trace.Trace("Starting execution with: " + *mainClass)
 	}
 
+	// with -Xlint:deprecation, warn of calls to deprecated methods that will be trapped
+	if globPtr.LintDeprecation {
+		warnDeprecatedCalls(*mainClass)
+	}
+
 	// StartExec() runs the main thread. It does not return an error because all errors
 	// will be handled one of three ways: 1) trapped in an exception, which shuts down the
 	// JVM after processing the error; 2) a deferred catch of a go panic, which also shuts
@@ -158,6 +220,63 @@ func JVMrun() int {
 	// To test for errors, trap stderr, as many of the unit tests do.
 	StartExec(*mainClass, &MainThread, globPtr)
 
+	if globPtr.PrintMethArea {
+		classloader.PrintMethArea(os.Stderr, "")
+	}
+	return shutdown.Exit(shutdown.OK)
+}
+
+// starts recording the inputs of the program to the file named by -record, or
+// replaying them from the file named by -replay
+func startRecordOrReplay(globPtr *globals.Globals) error {
+	switch {
+	case globPtr.RecordFile != "" && globPtr.ReplayFile != "":
+		return errors.New("-record and -replay cannot be used together")
+	case globPtr.RecordFile != "":
+		return replay.Record(globPtr.RecordFile)
+	default:
+		return replay.Replay(globPtr.ReplayFile)
+	}
+}
+
+// sizes Jacobin to the limits of the container it runs in, unless the options set
+// the sizes explicitly. As in the JDK, the default maximum heap is a quarter of the
+// memory limit, and the processors are capped at the CPU quota.
+func applyContainerLimits(globPtr *globals.Globals, limits container.Limits) {
+	if globPtr.MaxHeapSize == 0 && limits.Memory > 0 {
+		globPtr.MaxHeapSize = max(limits.Memory/containerHeapFraction, minHeapSize)
+	}
+	if globPtr.ActiveCPUs <= 0 && limits.CPUs > 0 {
+		globPtr.ActiveCPUs = min(limits.CPUs, runtime.NumCPU())
+	}
+	if globals.TraceInit && (limits.Memory > 0 || limits.CPUs > 0) {
+		trace.Trace(fmt.Sprintf("container limits: %d bytes of memory, %d CPUs; max heap: %d bytes, processors: %d",
+			limits.Memory, limits.CPUs, globPtr.MaxHeapSize, globPtr.ActiveCPUs))
+	}
+}
+
+// the default maximum heap in a container is 1/containerHeapFraction of its memory
+// limit, as with the JDK's default -XX:MaxRAMPercentage of 25
+const containerHeapFraction = 4
+
+// runs the conformance suite in dir with the present Jacobin executable and exits
+// with an error status if any test fails
+func runSelfTest(dir string) int {
+	exe, err := os.Executable()
+	if err != nil {
+		trace.Error("runSelfTest: cannot locate the Jacobin executable: " + err.Error())
+		return shutdown.Exit(shutdown.JVM_EXCEPTION)
+	}
+	results, err := selftest.Run(dir, []string{exe}, os.Stdout)
+	if err != nil {
+		trace.Error(err.Error())
+		return shutdown.Exit(shutdown.JVM_EXCEPTION)
+	}
+	for _, result := range results {
+		if !result.Passed() {
+			return shutdown.Exit(shutdown.APP_EXCEPTION)
+		}
+	}
 	return shutdown.Exit(shutdown.OK)
 }
 
@@ -173,4 +292,7 @@ func InitGlobalFunctionPointers(globalPtr *globals.Globals) {
 	globalPtr.FuncMinimalAbort = exceptions.MinimalAbort
 	globalPtr.FuncThrowException = exceptions.ThrowExNil
 	globalPtr.FuncFillInStackTrace = gfunction.FillInStackTrace
+	globalPtr.FuncInvokeJavaMethod = InvokeJavaMethod
+	globalPtr.FuncStartThread = StartJavaThread
+	globalPtr.FuncUncaughtHandler = dispatchUncaughtException
 }
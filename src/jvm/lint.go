@@ -0,0 +1,85 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"fmt"
+	"jacobin/src/classloader"
+	"jacobin/src/gfunction"
+	"jacobin/src/opcodes"
+	"jacobin/src/trace"
+	"sort"
+)
+
+// warnDeprecatedCalls implements -Xlint:deprecation. It scans the bytecode of the
+// methods of the main class for invocations of deprecated JDK methods that Jacobin
+// traps (with trapDeprecated in gfunction/Traps.go), so that the user learns of them
+// at startup rather than by an UnsupportedOperationException partway through the run.
+// Each deprecated method is reported once, at its first call site. Returns the number
+// of warnings issued.
+func warnDeprecatedCalls(className string) int {
+	k := classloader.MethAreaFetch(className)
+	if k == nil || k.Data == nil {
+		return 0
+	}
+
+	// walk the methods in a fixed order, so the warnings are the same on every run
+	methKeys := make([]string, 0, len(k.Data.MethodTable))
+	for key := range k.Data.MethodTable {
+		methKeys = append(methKeys, key)
+	}
+	sort.Strings(methKeys)
+
+	cp := &k.Data.CP
+	reported := make(map[string]bool)
+	for _, methKey := range methKeys {
+		code := k.Data.MethodTable[methKey].CodeAttr.Code
+		for pc := 0; pc < len(code); {
+			length := classloader.BytecodeLength(code, pc)
+			if length == 0 { // a truncated or invalid instruction; the verifier reports these
+				break
+			}
+
+			switch code[pc] {
+			case opcodes.INVOKEVIRTUAL, opcodes.INVOKESPECIAL, opcodes.INVOKESTATIC, opcodes.INVOKEINTERFACE:
+				cpIndex := int(code[pc+1])<<8 | int(code[pc+2])
+				fqn := invokedMethodFQN(cp, cpIndex)
+				if fqn != "" && !reported[fqn] && gfunction.IsTrapDeprecated(fqn) {
+					reported[fqn] = true
+					trace.Warning(fmt.Sprintf("[deprecation] %s.%s calls %s, which is deprecated and not supported",
+						className, methKey, fqn))
+				}
+			}
+			pc += length
+		}
+	}
+	return len(reported)
+}
+
+// returns the FQN of the method referred to by the CP entry at cpIndex, or an empty
+// string if the entry is not a method or interface method reference
+func invokedMethodFQN(cp *classloader.CPool, cpIndex int) string {
+	if cpIndex < 1 || cpIndex >= len(cp.CpIndex) {
+		return ""
+	}
+	entry := cp.CpIndex[cpIndex]
+	switch entry.Type {
+	case classloader.MethodRef:
+		if int(entry.Slot) >= len(cp.ResolvedMethodRefs) {
+			return ""
+		}
+		_, _, _, fqn := classloader.GetMethInfoFromCPmethref(cp, cpIndex)
+		return fqn
+	case classloader.Interface:
+		_, _, _, fqn, err := classloader.GetIfaceMethInfoFromCPref(cp, cpIndex)
+		if err != nil {
+			return ""
+		}
+		return fqn
+	}
+	return ""
+}
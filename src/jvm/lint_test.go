@@ -0,0 +1,75 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"jacobin/src/classloader"
+	"jacobin/src/gfunction"
+	"jacobin/src/globals"
+	"jacobin/src/opcodes"
+	"jacobin/src/stringPool"
+	"jacobin/src/trace"
+	"testing"
+)
+
+// loads a main class whose two methods each call the deprecated (and trapped)
+// RMISecurityManager.<init>()V as well as a method that is not deprecated
+func loadLintTestClass(className string) {
+	cp := classloader.CPool{}
+	cp.CpIndex = []classloader.CpEntry{
+		{Type: 0, Slot: 0}, // mandatory dummy entry
+		{Type: classloader.MethodRef, Slot: 0},
+		{Type: classloader.MethodRef, Slot: 1},
+	}
+	cp.MethodRefs = []classloader.MethodRefEntry{{}, {}}
+	for _, ref := range [][3]string{
+		{"java/rmi/RMISecurityManager", "<init>", "()V"},
+		{className, "helper", "()V"},
+	} {
+		fqn := ref[0] + "." + ref[1] + ref[2]
+		cp.ResolvedMethodRefs = append(cp.ResolvedMethodRefs, classloader.ResolvedMethodRefEntry{
+			ClassIndex:  stringPool.GetStringIndex(&ref[0]),
+			NameIndex:   stringPool.GetStringIndex(&ref[1]),
+			TypeIndex:   stringPool.GetStringIndex(&ref[2]),
+			FQNameIndex: stringPool.GetStringIndex(&fqn),
+		})
+	}
+
+	code := []byte{
+		opcodes.NEW, 0x00, 0x03, // the class ref isn't resolved by the scan
+		opcodes.INVOKESPECIAL, 0x00, 0x01,
+		opcodes.INVOKESTATIC, 0x00, 0x02,
+		opcodes.RETURN,
+	}
+	classloader.MethAreaInsert(className, &classloader.Klass{Status: 'F', Data: &classloader.ClData{
+		Name: className,
+		CP:   cp,
+		MethodTable: map[string]*classloader.Method{
+			"main([Ljava/lang/String;)V": {CodeAttr: classloader.CodeAttrib{Code: code}},
+			"helper()V":                  {CodeAttr: classloader.CodeAttrib{Code: code}},
+		},
+	}})
+}
+
+func TestWarnDeprecatedCalls(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	gfunction.Load_Traps()
+
+	className := "lint/Main"
+	loadLintTestClass(className)
+
+	// the deprecated method is called by both methods, but is reported once
+	if count := warnDeprecatedCalls(className); count != 1 {
+		t.Errorf("Expected 1 deprecation warning, got %d", count)
+	}
+	if count := warnDeprecatedCalls("lint/NotLoaded"); count != 0 {
+		t.Errorf("Expected no warnings for a class that is not loaded, got %d", count)
+	}
+}
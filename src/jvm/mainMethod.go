@@ -0,0 +1,156 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"fmt"
+	"jacobin/src/classloader"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"sort"
+	"strings"
+)
+
+// The resolution of the main() method that starts the program. As in the JDK, the
+// main method is public static void main(String[] args), which may be declared as
+// main(String... args), and which may be inherited from a superclass. With
+// --enable-preview, the launch protocol of JEP 445 also applies: main may be any
+// non-private method, may omit its parameter, and may be an instance method, in
+// which case the class is instantiated with its no-argument constructor. The
+// unnamed classes of JEP 445 are ordinary classes to the JVM, so they need no more.
+
+const (
+	mainArgsType = "([Ljava/lang/String;)V"
+	mainNoArgs   = "()V"
+
+	accPublic  = 0x0001
+	accPrivate = 0x0002
+	accStatic  = 0x0008
+)
+
+// mainMethod is the main() method that starts the program
+type mainMethod struct {
+	className string // the class that declares the method, possibly a superclass of the main class
+	methType  string // mainArgsType or mainNoArgs
+	static    bool   // if false, main() is invoked on a new instance of the main class
+}
+
+// resolveMainMethod finds the main() method of the program's main class, or returns
+// an error whose message explains why there is none and lists the methods named main
+func resolveMainMethod(mainClass string, preview bool) (mainMethod, error) {
+	// the candidates, in order of preference. A static main with parameters is
+	// always preferred, so it's the only one searched for without preview.
+	type candidate struct {
+		methType string
+		static   bool
+	}
+	candidates := []candidate{{mainArgsType, true}}
+	if preview {
+		candidates = append(candidates,
+			candidate{mainNoArgs, true}, candidate{mainArgsType, false}, candidate{mainNoArgs, false})
+	}
+
+	for _, c := range candidates {
+		declarer, m := findInheritedMethod(mainClass, "main"+c.methType)
+		if m == nil || (m.AccessFlags&accStatic != 0) != c.static {
+			continue
+		}
+		if preview && m.AccessFlags&accPrivate != 0 { // JEP 445 allows any non-private main()
+			continue
+		}
+		if !preview && m.AccessFlags&accPublic == 0 {
+			continue
+		}
+		if !c.static {
+			k := classloader.MethAreaFetch(mainClass)
+			ctor, ok := k.Data.MethodTable["<init>()V"]
+			if !ok || ctor.AccessFlags&accPrivate != 0 {
+				return mainMethod{}, fmt.Errorf(
+					"Error: the instance main() method of class %s requires a non-private constructor with no parameters",
+					mainClass)
+			}
+		}
+		return mainMethod{className: declarer, methType: c.methType, static: c.static}, nil
+	}
+
+	// no main method qualifies, so explain why
+	var msg string
+	if _, m := findInheritedMethod(mainClass, "main"+mainArgsType); m != nil && !preview &&
+		m.AccessFlags&accStatic == 0 {
+		msg = fmt.Sprintf("Error: main() method is not static in class %s\n", mainClass)
+	} else {
+		msg = fmt.Sprintf("Error: main() method not found in class %s\n", mainClass)
+	}
+	msg += "Please define the main method as:\n" +
+		"   public static void main(String[] args)"
+	if preview {
+		msg += "\nor as a non-private method void main() or void main(String[] args), which if it's\n" +
+			"not static requires a non-private constructor with no parameters"
+	}
+	if found := methodsNamedMain(mainClass); len(found) > 0 {
+		msg += "\nThe methods named main are:\n   " + strings.Join(found, "\n   ")
+	}
+	return mainMethod{}, fmt.Errorf("%s", msg)
+}
+
+// finds the method with the given name and type in the class or its superclasses.
+// Returns the class that declares it, and the method, which is nil if not found.
+func findInheritedMethod(className, nameAndType string) (string, *classloader.Method) {
+	for className != "" {
+		k := classloader.MethAreaFetch(className)
+		if k == nil {
+			if classloader.LoadClassFromNameOnly(className) != nil {
+				return "", nil
+			}
+			k = classloader.MethAreaFetch(className)
+		}
+		if k == nil || k.Data == nil {
+			return "", nil
+		}
+		if m, ok := k.Data.MethodTable[nameAndType]; ok {
+			return className, m
+		}
+		if className == types.ObjectClassName {
+			break
+		}
+		className = *stringPool.GetStringPointer(k.Data.SuperclassIndex)
+	}
+	return "", nil
+}
+
+// lists the methods named main in the class and its superclasses, with their
+// modifiers, such as "public static main(I)V in Hello"
+func methodsNamedMain(className string) []string {
+	var found []string
+	for className != "" && className != types.ObjectClassName {
+		k := classloader.MethAreaFetch(className)
+		if k == nil || k.Data == nil {
+			break
+		}
+		var inClass []string
+		for key, m := range k.Data.MethodTable {
+			if !strings.HasPrefix(key, "main(") {
+				continue
+			}
+			var modifiers string
+			switch {
+			case m.AccessFlags&accPublic != 0:
+				modifiers = "public "
+			case m.AccessFlags&accPrivate != 0:
+				modifiers = "private "
+			}
+			if m.AccessFlags&accStatic != 0 {
+				modifiers += "static "
+			}
+			inClass = append(inClass, modifiers+key+" in "+className)
+		}
+		sort.Strings(inClass)
+		found = append(found, inClass...)
+		className = *stringPool.GetStringPointer(k.Data.SuperclassIndex)
+	}
+	return found
+}
@@ -0,0 +1,118 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"jacobin/src/classloader"
+	"jacobin/src/globals"
+	"jacobin/src/stringPool"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+	"strings"
+	"testing"
+)
+
+// puts a class with the given superclass and methods, by name and type and with
+// their access flags, in the method area
+func loadMainTestClass(className, superclass string, methods map[string]int) {
+	methodTable := make(map[string]*classloader.Method)
+	for key, access := range methods {
+		methodTable[key] = &classloader.Method{AccessFlags: access}
+	}
+	classloader.MethAreaInsert(className, &classloader.Klass{Status: 'F', Data: &classloader.ClData{
+		Name:            className,
+		SuperclassIndex: stringPool.GetStringIndex(&superclass),
+		MethodTable:     methodTable,
+	}})
+}
+
+func initMainTest(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+	t.Cleanup(classloader.InitMethodArea)
+	loadMainTestClass(types.ObjectClassName, "", map[string]int{"<init>()V": accPublic})
+}
+
+func TestResolveMainMethod(t *testing.T) {
+	initMainTest(t)
+	loadMainTestClass("main/Super", types.ObjectClassName, map[string]int{
+		"main([Ljava/lang/String;)V": accPublic | accStatic, // declared as main(String... args), it's the same
+	})
+	loadMainTestClass("main/Sub", "main/Super", map[string]int{"<init>()V": accPublic})
+	loadMainTestClass("main/Own", "main/Super", map[string]int{
+		"main([Ljava/lang/String;)V": accPublic | accStatic,
+	})
+
+	if mm, err := resolveMainMethod("main/Sub", false); err != nil ||
+		mm != (mainMethod{className: "main/Super", methType: mainArgsType, static: true}) {
+		t.Errorf("Expected main() to be inherited from main/Super, got: %+v, %v", mm, err)
+	}
+	if mm, err := resolveMainMethod("main/Own", false); err != nil || mm.className != "main/Own" {
+		t.Errorf("Expected the class's own main() to hide its superclass's, got: %+v, %v", mm, err)
+	}
+}
+
+func TestResolveInstanceMainMethod(t *testing.T) {
+	initMainTest(t)
+	loadMainTestClass("main/Instance", types.ObjectClassName, map[string]int{
+		"<init>()V": 0, // package access
+		"main()V":   0,
+	})
+
+	if _, err := resolveMainMethod("main/Instance", false); err == nil {
+		t.Errorf("Expected an instance main() to be rejected without --enable-preview")
+	}
+	if mm, err := resolveMainMethod("main/Instance", true); err != nil ||
+		mm != (mainMethod{className: "main/Instance", methType: mainNoArgs, static: false}) {
+		t.Errorf("Expected the instance main() to be used with --enable-preview, got: %+v, %v", mm, err)
+	}
+
+	// a static main() is preferred to an instance main(), whatever its parameters
+	loadMainTestClass("main/Both", types.ObjectClassName, map[string]int{
+		"<init>()V":                  accPublic,
+		"main([Ljava/lang/String;)V": accPublic,
+		"main()V":                    accStatic,
+	})
+	if mm, err := resolveMainMethod("main/Both", true); err != nil || mm.methType != mainNoArgs || !mm.static {
+		t.Errorf("Expected the static main() to be preferred, got: %+v, %v", mm, err)
+	}
+
+	// an instance main() needs a constructor to create the instance
+	loadMainTestClass("main/NoCtor", types.ObjectClassName, map[string]int{
+		"<init>()V": accPrivate,
+		"main()V":   accPublic,
+	})
+	if _, err := resolveMainMethod("main/NoCtor", true); err == nil ||
+		!strings.Contains(err.Error(), "requires a non-private constructor with no parameters") {
+		t.Errorf("Expected an error for the missing constructor, got: %v", err)
+	}
+}
+
+func TestResolveMainMethodErrors(t *testing.T) {
+	initMainTest(t)
+	loadMainTestClass("main/NotStatic", types.ObjectClassName, map[string]int{
+		"main([Ljava/lang/String;)V": accPublic,
+	})
+	loadMainTestClass("main/Wrong", types.ObjectClassName, map[string]int{
+		"main(I)V":                   accPublic | accStatic,
+		"main([Ljava/lang/String;)V": accPrivate | accStatic,
+	})
+
+	_, err := resolveMainMethod("main/NotStatic", false)
+	if err == nil || !strings.HasPrefix(err.Error(), "Error: main() method is not static in class main/NotStatic") {
+		t.Errorf("Expected an error for the instance main(), got: %v", err)
+	}
+
+	_, err = resolveMainMethod("main/Wrong", false)
+	if err == nil || !strings.HasPrefix(err.Error(), "Error: main() method not found in class main/Wrong") ||
+		!strings.HasSuffix(err.Error(), "The methods named main are:\n"+
+			"   private static main([Ljava/lang/String;)V in main/Wrong\n"+
+			"   public static main(I)V in main/Wrong") {
+		t.Errorf("Expected an error listing the methods named main, got: %v", err)
+	}
+}
@@ -0,0 +1,393 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package jvm
+
+import (
+	"jacobin/src/classloader"
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/opcodes"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"jacobin/src/util"
+	"sync"
+)
+
+// Escape analysis for the allocation nursery (-XX:+UseAllocationNursery, see
+// object/nursery.go). A NEW bytecode is a nursery site if the object it creates
+// provably never leaves the local variable it's stored in. The analysis is
+// deliberately simple and conservative: it recognizes only the code javac generates
+// for a statement such as
+//
+//	Point p = new Point(x, y + 1);
+//
+// that is, NEW, DUP, instructions that push the constructor's arguments, the
+// INVOKESPECIAL of the constructor, and an ASTORE to a local. For the site to
+// qualify:
+//
+//   - the arguments must be computed from constants, primitive locals, arithmetic,
+//     and field reads, and must not refer to the local the object is stored in
+//   - the class must extend Object directly, and its constructor must use this only
+//     to read and write its fields and to call Object.<init>()
+//   - every other use of the local in the method must read one of the object's
+//     fields or write a value computed as above to one of them
+//   - no branch may land, and no exception handler may cover, the middle of these
+//     instruction sequences
+//
+// Methods that contain WIDE, JSR, RET, or the switch bytecodes are not analyzed.
+// So an object from a nursery site can be referenced only from its local, and it
+// becomes garbage when the local is overwritten or the method returns. At those
+// points, the interpreter returns it to the nursery (see nurseryAlloc() and
+// frames.ReleaseFrame()).
+
+// the nursery sites of each method that has been analyzed, by FQN. Each entry is a
+// map from the PC of a NEW bytecode to the local its object is stored in.
+var nurserySites sync.Map
+
+// the result of the analysis of each constructor, by the FQN of the constructor
+var nurseryConstructors sync.Map
+
+// returns the nursery sites of the method running in fr, analyzing it the first
+// time it's called for
+func nurserySitesOf(fr *frames.Frame) map[int]int {
+	fqn := fr.ClName + "." + fr.MethName + fr.MethType
+	if sites, ok := nurserySites.Load(fqn); ok {
+		return sites.(map[int]int)
+	}
+
+	var sites map[int]int
+	if k := classloader.MethAreaFetch(fr.ClName); k != nil && k.Data != nil {
+		if meth, ok := k.Data.MethodTable[fr.MethName+fr.MethType]; ok {
+			sites = findNurserySites(meth.CodeAttr.Code, meth.CodeAttr.Exceptions, &k.Data.CP)
+		}
+	}
+	nurserySites.Store(fqn, sites)
+	return sites
+}
+
+// returns the local in which the object created by the NEW at fr.PC is stored, if
+// the allocation nursery is enabled and the NEW is a nursery site
+func nurserySiteAt(fr *frames.Frame) (int, bool) {
+	if !globals.GetGlobalRef().UseNursery || fr.Pool == nil {
+		return 0, false
+	}
+	slot, ok := nurserySitesOf(fr)[fr.PC]
+	return slot, ok
+}
+
+// nurseryAlloc returns a new object of the named class, from the thread's nursery, for
+// the nursery site at fr.PC whose object is stored in the given local. The object
+// previously stored in the local by this site (or another that stores to the same
+// local) is garbage once the new one replaces it, so it's released to the nursery first.
+func nurseryAlloc(fr *frames.Frame, slot int, className string) (*object.Object, error) {
+	if fr.Pool.Nursery == nil {
+		fr.Pool.Nursery = object.NewNursery()
+	}
+	if fr.NurseryObjs == nil {
+		fr.NurseryObjs = make(map[int]*object.Object)
+	}
+
+	if prev, ok := fr.NurseryObjs[slot]; ok {
+		delete(fr.NurseryObjs, slot)
+		if slot < len(fr.Locals) && fr.Locals[slot] == any(prev) { // else it was overwritten, and is garbage
+			fr.Pool.Nursery.Release(prev)
+		}
+	}
+
+	obj, err := instantiateClass(className, fr.FrameStack, fr.Pool.Nursery)
+	if err != nil {
+		return nil, err
+	}
+	fr.NurseryObjs[slot] = obj
+	return obj, nil
+}
+
+// returns the nursery sites in a method's bytecode: a map from the PC of each NEW
+// whose object never escapes to the local the object is stored in
+func findNurserySites(code []byte, handlers []classloader.CodeException, cp *classloader.CPool) map[int]int {
+	targets, ok := branchTargets(code, handlers)
+	if !ok {
+		return nil
+	}
+
+	sites := make(map[int]int)
+	for pc := 0; pc < len(code); pc += classloader.BytecodeLength(code, pc) {
+		if code[pc] != opcodes.NEW {
+			continue
+		}
+		slot, storePC, ok := matchNewSequence(code, pc, cp, targets)
+		if !ok || coveredByHandler(handlers, pc, storePC) {
+			continue
+		}
+		sites[pc] = slot
+	}
+
+	// drop the sites whose locals are used in any other way than to access fields
+	for pc, slot := range sites {
+		if !localStaysInFrame(code, slot, targets, cp) {
+			delete(sites, pc)
+		}
+	}
+	if len(sites) == 0 {
+		return nil
+	}
+	return sites
+}
+
+// returns the set of PCs that are the targets of branches or the starts of exception
+// handlers. Returns false if the code has instructions that the analysis doesn't handle.
+func branchTargets(code []byte, handlers []classloader.CodeException) (map[int]bool, bool) {
+	targets := make(map[int]bool)
+	for _, handler := range handlers {
+		targets[handler.HandlerPc] = true
+	}
+
+	for pc := 0; pc < len(code); {
+		length := classloader.BytecodeLength(code, pc)
+		if length == 0 {
+			return nil, false
+		}
+		switch op := code[pc]; {
+		case op == opcodes.WIDE || op == opcodes.JSR || op == opcodes.JSR_W || op == opcodes.RET ||
+			op == opcodes.TABLESWITCH || op == opcodes.LOOKUPSWITCH:
+			return nil, false
+		case op >= opcodes.IFEQ && op <= opcodes.GOTO, op == opcodes.IFNULL, op == opcodes.IFNONNULL:
+			targets[pc+int(int16(uint16(code[pc+1])<<8|uint16(code[pc+2])))] = true
+		case op == opcodes.GOTO_W:
+			targets[pc+int(int32(uint32(code[pc+1])<<24|uint32(code[pc+2])<<16|
+				uint32(code[pc+3])<<8|uint32(code[pc+4])))] = true
+		}
+		pc += length
+	}
+	return targets, true
+}
+
+// reports whether an exception handler covers any of the instructions from start to end
+func coveredByHandler(handlers []classloader.CodeException, start, end int) bool {
+	for _, handler := range handlers {
+		if handler.StartPc <= end && handler.EndPc > start {
+			return true
+		}
+	}
+	return false
+}
+
+// matches NEW, DUP, <arguments>, INVOKESPECIAL <init>, ASTORE at pc. Returns the local
+// the object is stored in and the PC of the ASTORE.
+func matchNewSequence(code []byte, pc int, cp *classloader.CPool, targets map[int]bool) (int, int, bool) {
+	if pc+3 >= len(code) || code[pc+3] != opcodes.DUP || targets[pc+3] {
+		return 0, 0, false
+	}
+	className, ok := classRefName(cp, int(code[pc+1])<<8|int(code[pc+2]))
+	if !ok {
+		return 0, 0, false
+	}
+
+	argsPC := pc + 4
+	invokePC, depth, ok := valueSequence(code, argsPC, 0, -1, targets)
+	if !ok || invokePC+3 > len(code) || code[invokePC] != opcodes.INVOKESPECIAL || targets[invokePC] {
+		return 0, 0, false
+	}
+	initClass, initName, initType, ok := methodRefInfo(cp, int(code[invokePC+1])<<8|int(code[invokePC+2]))
+	if !ok || initClass != className || initName != "<init>" ||
+		depth != len(util.ParseIncomingParamsFromMethTypeString(initType)) {
+		return 0, 0, false
+	}
+
+	storePC := invokePC + 3
+	slot, ok := astoreSlot(code, storePC)
+	if !ok || targets[storePC] || !constructorKeepsThis(className, initType) {
+		return 0, 0, false
+	}
+
+	// the arguments must not read the object previously stored in the local, as it
+	// is released to the nursery before they're computed
+	for argPC := argsPC; argPC < invokePC; argPC += classloader.BytecodeLength(code, argPC) {
+		if loaded, ok := aloadSlot(code, argPC); ok && loaded == slot {
+			return 0, 0, false
+		}
+	}
+	return slot, storePC, true
+}
+
+// reports whether the constructor of a class uses this only to access the object's
+// fields and to call Object.<init>(), so that the object doesn't escape from it
+func constructorKeepsThis(className, initType string) bool {
+	fqn := className + ".<init>" + initType
+	if result, ok := nurseryConstructors.Load(fqn); ok {
+		return result.(bool)
+	}
+
+	result := false
+	k := classloader.MethAreaFetch(className)
+	if k != nil && k.Data != nil && !k.Data.Access.ClassIsInterface {
+		_, hooked := object.InstantiationHookFor(className)
+		superclass := *stringPool.GetStringPointer(k.Data.SuperclassIndex)
+		if meth, ok := k.Data.MethodTable["<init>"+initType]; ok && !hooked && superclass == types.ObjectClassName {
+			code := meth.CodeAttr.Code
+			if targets, ok := branchTargets(code, meth.CodeAttr.Exceptions); ok {
+				result = localStaysInFrame(code, 0, targets, &k.Data.CP)
+			}
+		}
+	}
+	nurseryConstructors.Store(fqn, result)
+	return result
+}
+
+// reports whether every use of the object in local slot of the code only reads or
+// writes one of its fields. In a constructor (slot 0), the call to Object.<init>()
+// is also permitted.
+func localStaysInFrame(code []byte, slot int, targets map[int]bool, cp *classloader.CPool) bool {
+	for pc := 0; pc < len(code); pc += classloader.BytecodeLength(code, pc) {
+		if stored, ok := astoreSlot(code, pc); ok && stored == slot && slot == 0 {
+			return false // a constructor that reuses the local of this
+		}
+		loaded, ok := aloadSlot(code, pc)
+		if !ok || loaded != slot {
+			continue
+		}
+
+		next := pc + classloader.BytecodeLength(code, pc)
+		if next >= len(code) || targets[next] {
+			return false
+		}
+		valuePC, depth := next, 0
+		switch code[next] {
+		case opcodes.GETFIELD:
+			continue
+		case opcodes.INVOKESPECIAL:
+			if slot == 0 && isObjectInit(code, next, cp) {
+				continue
+			}
+			return false
+		case opcodes.DUP: // as in p.x++, which reads the field and writes the new value to it
+			getPC := next + 1
+			if getPC >= len(code) || code[getPC] != opcodes.GETFIELD || targets[getPC] {
+				return false
+			}
+			valuePC, depth = getPC+classloader.BytecodeLength(code, getPC), 1
+			if targets[valuePC] {
+				return false
+			}
+		}
+
+		putPC, depth, ok := valueSequence(code, valuePC, depth, slot, targets)
+		if !ok || depth != 1 || putPC >= len(code) || code[putPC] != opcodes.PUTFIELD || targets[putPC] {
+			return false
+		}
+	}
+	return true
+}
+
+// reports whether the INVOKESPECIAL at pc calls java/lang/Object.<init>()V
+func isObjectInit(code []byte, pc int, cp *classloader.CPool) bool {
+	if pc+3 > len(code) {
+		return false
+	}
+	className, methName, methType, ok := methodRefInfo(cp, int(code[pc+1])<<8|int(code[pc+2]))
+	return ok && className == types.ObjectClassName && methName == "<init>" && methType == "()V"
+}
+
+// walks a sequence of instructions that compute values without letting any object
+// escape: constants, loads of locals (but not of the local slot, except to read
+// one of its fields), field reads, and arithmetic. depth is the number of values
+// the sequence may use that were pushed before it. Returns the PC of the first
+// instruction that is not part of the sequence and the number of values then on
+// the operand stack. Returns false if the sequence consumes values it doesn't
+// have or a branch lands in its midst.
+func valueSequence(code []byte, pc, depth, slot int, targets map[int]bool) (int, int, bool) {
+	for start := pc; pc < len(code); {
+		if pc != start && targets[pc] {
+			return 0, 0, false
+		}
+		op := code[pc]
+		length := classloader.BytecodeLength(code, pc)
+		if length == 0 {
+			return 0, 0, false
+		}
+
+		if loaded, ok := aloadSlot(code, pc); ok {
+			next := pc + length
+			if next < len(code) && code[next] == opcodes.GETFIELD && !targets[next] {
+				length += classloader.BytecodeLength(code, next) // the field read leaves one value
+			} else if loaded == slot {
+				return 0, 0, false // the object itself is pushed
+			}
+			depth++
+			pc += length
+			continue
+		}
+
+		switch {
+		case op >= opcodes.ACONST_NULL && op <= opcodes.LDC2_W, // constants
+			op >= opcodes.ILOAD && op <= opcodes.DLOAD,
+			op >= opcodes.ILOAD_0 && op <= opcodes.DLOAD_3:
+			depth++
+		case op >= opcodes.IADD && op < opcodes.INEG, // binary arithmetic
+			op >= opcodes.ISHL && op <= opcodes.LXOR,
+			op >= opcodes.LCMP && op <= opcodes.DCMPG:
+			if depth < 2 {
+				return 0, 0, false
+			}
+			depth--
+		case op >= opcodes.INEG && op <= opcodes.DNEG, // unary arithmetic and conversions
+			op >= opcodes.I2L && op <= opcodes.I2S:
+			if depth < 1 {
+				return 0, 0, false
+			}
+		case op == opcodes.IINC:
+		default:
+			return pc, depth, true
+		}
+		pc += length
+	}
+	return pc, depth, true
+}
+
+// returns the local that the ALOAD instruction at pc loads, if it is one
+func aloadSlot(code []byte, pc int) (int, bool) {
+	switch op := code[pc]; {
+	case op == opcodes.ALOAD && pc+1 < len(code):
+		return int(code[pc+1]), true
+	case op >= opcodes.ALOAD_0 && op <= opcodes.ALOAD_3:
+		return int(op - opcodes.ALOAD_0), true
+	}
+	return 0, false
+}
+
+// returns the local that the ASTORE instruction at pc stores to, if it is one
+func astoreSlot(code []byte, pc int) (int, bool) {
+	if pc >= len(code) {
+		return 0, false
+	}
+	switch op := code[pc]; {
+	case op == opcodes.ASTORE && pc+1 < len(code):
+		return int(code[pc+1]), true
+	case op >= opcodes.ASTORE_0 && op <= opcodes.ASTORE_3:
+		return int(op - opcodes.ASTORE_0), true
+	}
+	return 0, false
+}
+
+// returns the class, name, and type of the method referred to by a MethodRef entry in the CP
+func methodRefInfo(cp *classloader.CPool, cpIndex int) (string, string, string, bool) {
+	if cpIndex < 1 || cpIndex >= len(cp.CpIndex) || cp.CpIndex[cpIndex].Type != classloader.MethodRef ||
+		int(cp.CpIndex[cpIndex].Slot) >= len(cp.ResolvedMethodRefs) {
+		return "", "", "", false
+	}
+	className, methName, methType, _ := classloader.GetMethInfoFromCPmethref(cp, cpIndex)
+	return className, methName, methType, true
+}
+
+// returns the name of the class referred to by a ClassRef entry in the CP
+func classRefName(cp *classloader.CPool, cpIndex int) (string, bool) {
+	if cpIndex < 1 || cpIndex >= len(cp.CpIndex) || cp.CpIndex[cpIndex].Type != classloader.ClassRef {
+		return "", false
+	}
+	return *stringPool.GetStringPointer(cp.ClassRefs[cp.CpIndex[cpIndex].Slot]), true
+}
@@ -0,0 +1,211 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package jvm
+
+import (
+	"jacobin/src/classloader"
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/opcodes"
+	"jacobin/src/stringPool"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+	"sync"
+	"testing"
+)
+
+const nurseryPoint = "nursery/Point"
+
+// returns a CP entry for a MethodRef to the given method
+func nurseryMethodRef(className, methName, methType string) classloader.ResolvedMethodRefEntry {
+	fqn := className + "." + methName + methType
+	return classloader.ResolvedMethodRefEntry{
+		ClassIndex:  stringPool.GetStringIndex(&className),
+		NameIndex:   stringPool.GetStringIndex(&methName),
+		TypeIndex:   stringPool.GetStringIndex(&methType),
+		FQNameIndex: stringPool.GetStringIndex(&fqn),
+	}
+}
+
+// loads nursery/Point, which has an int field x set by its constructor, <init>(I)V, and
+// returns a CP in which 1 is a ClassRef to it, 2 is a MethodRef to its constructor,
+// and 3 is a FieldRef to x
+func setupNurseryTest(ctorCode []byte) *classloader.CPool {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+	nurserySites = sync.Map{}
+	nurseryConstructors = sync.Map{}
+
+	pointCP := classloader.CPool{
+		CpIndex: []classloader.CpEntry{{}, {Type: classloader.MethodRef, Slot: 0}, {Type: classloader.FieldRef, Slot: 0}},
+		ResolvedMethodRefs: []classloader.ResolvedMethodRefEntry{
+			nurseryMethodRef(types.ObjectClassName, "<init>", "()V")},
+		Utf8Refs: []string{"x", types.Int},
+	}
+	objectClass := types.ObjectClassName
+	classloader.MethAreaInsert(nurseryPoint, &classloader.Klass{Status: 'F', Loader: "testloader", CodeChecked: true,
+		Data: &classloader.ClData{
+			Name:            nurseryPoint,
+			SuperclassIndex: stringPool.GetStringIndex(&objectClass),
+			CP:              pointCP,
+			Fields:          []classloader.Field{{Name: 0, Desc: 1}},
+			MethodTable: map[string]*classloader.Method{
+				"<init>(I)V": {CodeAttr: classloader.CodeAttrib{Code: ctorCode}}},
+			ClInit: types.NoClInit,
+		}})
+
+	point := nurseryPoint
+	return &classloader.CPool{
+		CpIndex: []classloader.CpEntry{{}, {Type: classloader.ClassRef, Slot: 0},
+			{Type: classloader.MethodRef, Slot: 0}, {Type: classloader.FieldRef, Slot: 0}},
+		ClassRefs:          []uint32{stringPool.GetStringIndex(&point)},
+		ResolvedMethodRefs: []classloader.ResolvedMethodRefEntry{nurseryMethodRef(nurseryPoint, "<init>", "(I)V")},
+	}
+}
+
+// the constructor of Point: super(); this.x = x;
+var pointCtor = []byte{
+	opcodes.ALOAD_0, opcodes.INVOKESPECIAL, 0, 1,
+	opcodes.ALOAD_0, opcodes.ILOAD_1, opcodes.PUTFIELD, 0, 2,
+	opcodes.RETURN,
+}
+
+// static int sum(int n) { int sum = 0; Point p = new Point(n); sum += p.x; p.x = n * 2; return sum; }
+// The NEW is at PC 0, and the code after the store to p begins at PC 9.
+var sumPoint = []byte{
+	opcodes.NEW, 0, 1, opcodes.DUP, opcodes.ILOAD_0, opcodes.INVOKESPECIAL, 0, 2, opcodes.ASTORE_2,
+	opcodes.ILOAD_1, opcodes.ALOAD_2, opcodes.GETFIELD, 0, 3, opcodes.IADD, opcodes.ISTORE_1,
+	opcodes.ALOAD_2, opcodes.ILOAD_0, opcodes.ICONST_2, opcodes.IMUL, opcodes.PUTFIELD, 0, 3,
+	opcodes.ILOAD_1, opcodes.IRETURN,
+}
+
+func TestFindNurserySites(t *testing.T) {
+	cp := setupNurseryTest(pointCtor)
+
+	sites := findNurserySites(sumPoint, nil, cp)
+	if len(sites) != 1 || sites[0] != 2 {
+		t.Errorf("Expected a nursery site at PC 0 storing to local 2, got: %v", sites)
+	}
+
+	// the object escapes if it's returned
+	escapes := append(append([]byte{}, sumPoint[:23]...), opcodes.ALOAD_2, opcodes.ARETURN)
+	if sites = findNurserySites(escapes, nil, cp); len(sites) != 0 {
+		t.Errorf("Expected no nursery site for a returned object, got: %v", sites)
+	}
+
+	// or if an exception handler covers its creation
+	handlers := []classloader.CodeException{{StartPc: 0, EndPc: 9, HandlerPc: 23}}
+	if sites = findNurserySites(sumPoint, handlers, cp); len(sites) != 0 {
+		t.Errorf("Expected no nursery site within a try block, got: %v", sites)
+	}
+
+	// it's not a site if the constructor's arguments read the previous object
+	readsPrev := []byte{
+		opcodes.NEW, 0, 1, opcodes.DUP, opcodes.ALOAD_2, opcodes.GETFIELD, 0, 3,
+		opcodes.INVOKESPECIAL, 0, 2, opcodes.ASTORE_2, opcodes.RETURN,
+	}
+	if sites = findNurserySites(readsPrev, nil, cp); len(sites) != 0 {
+		t.Errorf("Expected no nursery site when the arguments read the local, got: %v", sites)
+	}
+}
+
+func TestFindNurserySitesLeakyConstructor(t *testing.T) {
+	// Point(int x) { super(); Registry.last = this; }
+	leaky := []byte{
+		opcodes.ALOAD_0, opcodes.INVOKESPECIAL, 0, 1,
+		opcodes.ALOAD_0, opcodes.PUTSTATIC, 0, 2,
+		opcodes.RETURN,
+	}
+	cp := setupNurseryTest(leaky)
+	if sites := findNurserySites(sumPoint, nil, cp); len(sites) != 0 {
+		t.Errorf("Expected no nursery site when the constructor lets this escape, got: %v", sites)
+	}
+}
+
+func TestNurseryAllocReusesObjects(t *testing.T) {
+	cp := setupNurseryTest(pointCtor)
+	globals.GetGlobalRef().UseNursery = true
+	caller := "nursery/Caller"
+	classloader.MethAreaInsert(caller, &classloader.Klass{Status: 'F', Loader: "testloader", Data: &classloader.ClData{
+		Name: caller,
+		CP:   *cp,
+		MethodTable: map[string]*classloader.Method{
+			"sum(I)I": {CodeAttr: classloader.CodeAttrib{Code: sumPoint}}},
+	}})
+
+	pool := frames.NewFramePool()
+	fr := pool.CreateFrame(4)
+	fr.ClName, fr.MethName, fr.MethType = caller, "sum", "(I)I"
+	fr.Locals = []any{int64(5), int64(0), nil}
+
+	slot, ok := nurserySiteAt(fr)
+	if !ok || slot != 2 {
+		t.Fatalf("Expected a nursery site storing to local 2, got: %d, %v", slot, ok)
+	}
+	first, err := nurseryAlloc(fr, slot, nurseryPoint)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first.FieldTable["x"].Fvalue != int64(0) {
+		t.Errorf("Expected field x to be 0, got: %v", first.FieldTable["x"])
+	}
+	first.FieldTable["x"] = object.Field{Ftype: types.Int, Fvalue: int64(5)}
+	fr.Locals[2] = first // the ASTORE
+
+	// the next iteration gets the same object back, reset
+	second, _ := nurseryAlloc(fr, slot, nurseryPoint)
+	if second != first || pool.Nursery.Reused() != 1 {
+		t.Fatalf("Expected the object in local 2 to be reused, got %p and %p", first, second)
+	}
+	if second.FieldTable["x"].Fvalue != int64(0) {
+		t.Errorf("Expected the reused object's field x to be reset, got: %v", second.FieldTable["x"])
+	}
+
+	// an object no longer in its local was overwritten, so it is not released
+	fr.Locals[2] = nil
+	if third, _ := nurseryAlloc(fr, slot, nurseryPoint); third == second || pool.Nursery.Reused() != 1 {
+		t.Error("Did not expect an object that left its local to be reused")
+	}
+
+	frames.ReleaseFrame(fr)
+	if pool.Nursery.Len() != 1 {
+		t.Errorf("Expected the frame's object to be released when it's popped, got %d", pool.Nursery.Len())
+	}
+
+	globals.GetGlobalRef().UseNursery = false
+	if _, ok = nurserySiteAt(pool.CreateFrame(4)); ok {
+		t.Error("Expected no nursery sites without -XX:+UseAllocationNursery")
+	}
+}
+
+// an allocation-heavy loop: a new Point on every iteration, with and without the nursery
+func benchmarkPointLoop(b *testing.B, useNursery bool) {
+	setupNurseryTest(pointCtor)
+	var nursery *object.Nursery
+	if useNursery {
+		nursery = object.NewNursery()
+	}
+	var prev *object.Object
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if nursery != nil && prev != nil {
+			nursery.Release(prev)
+		}
+		obj, err := instantiateClass(nurseryPoint, nil, nursery)
+		if err != nil {
+			b.Fatal(err)
+		}
+		obj.FieldTable["x"] = object.Field{Ftype: types.Int, Fvalue: int64(i)}
+		prev = obj
+	}
+}
+
+func BenchmarkPointLoopWithoutNursery(b *testing.B) { benchmarkPointLoop(b, false) }
+
+func BenchmarkPointLoopWithNursery(b *testing.B) { benchmarkPointLoop(b, true) }
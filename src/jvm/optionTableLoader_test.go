@@ -7,10 +7,15 @@
 package jvm
 
 import (
+	"jacobin/src/charset"
+	"jacobin/src/container"
 	"jacobin/src/globals"
 	"jacobin/src/statics"
+	"jacobin/src/trace"
 	"jacobin/src/types"
+	"jacobin/src/util"
 	"os"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -36,6 +41,33 @@ func TestEnableAssertions(t *testing.T) {
 	}
 }
 
+func TestAssertionOptions(t *testing.T) {
+	global := globals.InitGlobals("test")
+	statics.LoadProgramStatics()
+	defer globals.ResetAssertionStatus()
+
+	// -ea:com.acme... -da:com.acme.Legacy -esa
+	_, _ = enableAssertions(0, "com.acme...", &global)
+	_, _ = disableAssertions(0, "com.acme.Legacy", &global)
+	_, _ = enableSystemAssertions(0, "", &global)
+	if !globals.DesiredAssertionStatus("com/acme/Tool") || globals.DesiredAssertionStatus("com/acme/Legacy") ||
+		globals.DesiredAssertionStatus("org/other/Main") || !globals.DesiredAssertionStatus("java/lang/String") {
+		t.Errorf("Expected assertions in com.acme (but not com.acme.Legacy) and in the system classes only")
+	}
+	if statics.GetStaticValue("main", "$assertionsDisabled").(int64) != types.JavaBoolTrue {
+		t.Errorf("Expected -ea with an argument to leave the default status disabled")
+	}
+
+	// a bare -da after a bare -ea disables them again
+	_, _ = enableAssertions(0, "", &global)
+	_, _ = disableAssertions(0, "", &global)
+	_, _ = disableSystemAssertions(0, "", &global)
+	if globals.DesiredAssertionStatus("org/other/Main") || globals.DesiredAssertionStatus("java/lang/String") ||
+		statics.GetStaticValue("main", "$assertionsDisabled").(int64) != types.JavaBoolTrue {
+		t.Errorf("Expected -da and -dsa to disable assertions")
+	}
+}
+
 func TestExpandClasspathWithJarFile(t *testing.T) {
 	globals.InitGlobals("test")
 
@@ -192,6 +224,101 @@ func TestGetJarFilenameMissingArgument(t *testing.T) {
 	}
 }
 
+func TestSetXXflagEnforceAccess(t *testing.T) {
+	global := globals.InitGlobals("test")
+
+	if !global.EnforceAccess {
+		t.Error("Expected access checks to be enforced by default")
+	}
+
+	if _, err := setXXflag(0, "-EnforceAccess", &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if global.EnforceAccess {
+		t.Error("Expected -XX:-EnforceAccess to disable access checks")
+	}
+
+	if _, err := setXXflag(0, "+EnforceAccess", &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !global.EnforceAccess {
+		t.Error("Expected -XX:+EnforceAccess to enable access checks")
+	}
+
+	if !global.Options["-XX"].Set {
+		t.Error("Expected -XX option to be marked as set")
+	}
+}
+
+func TestSetXXflagGreenThreads(t *testing.T) {
+	global := globals.InitGlobals("test")
+
+	if global.GreenThreads {
+		t.Error("Expected green threads to be off by default")
+	}
+	if _, err := setXXflag(0, "+GreenThreads", &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !global.GreenThreads {
+		t.Error("Expected -XX:+GreenThreads to enable green threads")
+	}
+}
+
+func TestSetXXflagInvalid(t *testing.T) {
+	global := globals.InitGlobals("test")
+
+	if _, err := setXXflag(0, "EnforceAccess", &global); err == nil {
+		t.Error("Expected an error for a -XX option without + or -")
+	}
+
+	if _, err := setXXflag(0, "+NoSuchFlag", &global); err == nil {
+		t.Error("Expected an error for an unknown -XX option")
+	}
+}
+
+func TestSetThreadStackSize(t *testing.T) {
+	global := globals.InitGlobals("test")
+
+	if global.MaxFrameDepth != globals.DefaultThreadStackSize/globals.ApproxFrameSize {
+		t.Errorf("Unexpected default maximum frame depth: %d", global.MaxFrameDepth)
+	}
+	if _, err := setThreadStackSize(0, "2m", &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if global.MaxFrameDepth != 2*1024*1024/globals.ApproxFrameSize {
+		t.Errorf("Expected -Xss2m to double the maximum frame depth, got: %d", global.MaxFrameDepth)
+	}
+	if !global.Options["-Xss"].Set {
+		t.Error("Expected -Xss to be marked as set")
+	}
+
+	for _, bad := range []string{"", "abc", "-1k", "12q", "10"} {
+		if _, err := setThreadStackSize(0, bad, &global); err == nil {
+			t.Errorf("Expected an error for -Xss%s", bad)
+		}
+	}
+}
+
+func TestParseMemorySize(t *testing.T) {
+	tests := []struct {
+		size     string
+		expected int64
+	}{{"4096", 4096}, {"512k", 512 * 1024}, {"512K", 512 * 1024}, {"16m", 16 * 1024 * 1024},
+		{"1g", 1024 * 1024 * 1024}, {"2G", 2 * 1024 * 1024 * 1024}}
+	for _, test := range tests {
+		size, err := parseMemorySize(test.size)
+		if err != nil || size != test.expected {
+			t.Errorf("%s: expected %d, got %d (err: %v)", test.size, test.expected, size, err)
+		}
+	}
+
+	for _, bad := range []string{"", "m", "1.5m", "99999999999999g"} {
+		if _, err := parseMemorySize(bad); err == nil {
+			t.Errorf("Expected an error for %q", bad)
+		}
+	}
+}
+
 // Helper function to compare slices
 func equalSlices(a, b []string) bool {
 	if len(a) != len(b) {
@@ -204,3 +331,556 @@ func equalSlices(a, b []string) bool {
 	}
 	return true
 }
+
+func TestSetMaxHeapSize(t *testing.T) {
+	global := globals.InitGlobals("test")
+
+	if global.MaxHeapSize != 0 {
+		t.Errorf("Expected no heap limit by default, got: %d", global.MaxHeapSize)
+	}
+	if _, err := setMaxHeapSize(0, "64m", &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if global.MaxHeapSize != 64*1024*1024 {
+		t.Errorf("Expected -Xmx64m to set a 64 MB heap, got: %d", global.MaxHeapSize)
+	}
+	if !global.Options["-Xmx"].Set {
+		t.Error("Expected -Xmx to be marked as set")
+	}
+
+	for _, bad := range []string{"", "lots", "-64m", "512k"} {
+		if _, err := setMaxHeapSize(0, bad, &global); err == nil {
+			t.Errorf("Expected an error for -Xmx%s", bad)
+		}
+	}
+}
+
+func TestSetSystemProperty(t *testing.T) {
+	global := globals.InitGlobals("test")
+	saved := charset.Default()
+	t.Cleanup(func() { charset.SetDefault(saved) })
+
+	if _, err := setSystemProperty(0, "app.mode=fast=yes", &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if globals.GetSystemProperty("app.mode") != "fast=yes" {
+		t.Errorf("Expected app.mode to be fast=yes, got: %s", globals.GetSystemProperty("app.mode"))
+	}
+	if !global.Options["-D"].Set {
+		t.Error("Expected -D to be marked as set")
+	}
+
+	// the charset is looked up by its alias and reported by its canonical name
+	if _, err := setSystemProperty(0, "file.encoding=latin1", &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if charset.Default() != charset.ISO88591 || global.FileEncoding != "ISO-8859-1" ||
+		globals.GetSystemProperty("file.encoding") != "ISO-8859-1" {
+		t.Errorf("Expected -Dfile.encoding=latin1 to make ISO-8859-1 the default, got: %s", charset.Default().Name)
+	}
+
+	if _, err := setSystemProperty(0, "file.encoding=COMPAT", &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if charset.Default() != charset.Native() {
+		t.Errorf("Expected COMPAT to select the native charset, got: %s", charset.Default().Name)
+	}
+
+	// an unsupported charset falls back to UTF-8, with a warning
+	if _, err := setSystemProperty(0, "file.encoding=EBCDIC-XYZ", &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if charset.Default() != charset.UTF8 || globals.GetSystemProperty("file.encoding") != "UTF-8" {
+		t.Errorf("Expected an unsupported charset to fall back to UTF-8, got: %s", charset.Default().Name)
+	}
+
+	if _, err := setSystemProperty(0, "=value", &global); err == nil {
+		t.Error("Expected an error for a property without a name")
+	}
+}
+
+func TestSetSystemPropertyLineSeparator(t *testing.T) {
+	global := globals.InitGlobals("test")
+	t.Cleanup(func() { globals.InitGlobals("test") })
+
+	if _, err := setSystemProperty(0, "line.separator=\r\n", &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if globals.GetLineSeparator() != "\r\n" || globals.GetSystemProperty("line.separator") != "\r\n" {
+		t.Errorf("Expected -Dline.separator to set the line separator, got: %q", globals.GetLineSeparator())
+	}
+}
+
+func TestSetXXflagHeapDumpOnOutOfMemoryError(t *testing.T) {
+	global := globals.InitGlobals("test")
+
+	if global.HeapDumpOnOOM {
+		t.Error("Expected heap dumps to be off by default")
+	}
+	if _, err := setXXflag(0, "+HeapDumpOnOutOfMemoryError", &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !global.HeapDumpOnOOM {
+		t.Error("Expected -XX:+HeapDumpOnOutOfMemoryError to enable heap dumps")
+	}
+}
+
+func TestSetXXflagTrapPolicy(t *testing.T) {
+	global := globals.InitGlobals("test")
+
+	if global.TrapPolicy != globals.TrapThrow {
+		t.Errorf("Expected the default trap policy to be throw, got: %s", global.TrapPolicy)
+	}
+	for _, policy := range []string{globals.TrapWarn, globals.TrapAbort, globals.TrapThrow} {
+		if _, err := setXXflag(0, "TrapPolicy="+policy, &global); err != nil {
+			t.Errorf("Unexpected error for -XX:TrapPolicy=%s: %v", policy, err)
+		}
+		if global.TrapPolicy != policy {
+			t.Errorf("Expected -XX:TrapPolicy=%s to set the policy, got: %s", policy, global.TrapPolicy)
+		}
+	}
+
+	if _, err := setXXflag(0, "TrapPolicy=ignore", &global); err == nil {
+		t.Error("Expected an error for -XX:TrapPolicy=ignore")
+	}
+	if _, err := setXXflag(0, "+TrapPolicy", &global); err == nil {
+		t.Error("Expected an error for -XX:+TrapPolicy")
+	}
+}
+
+func TestSetXXflagPreferBytecode(t *testing.T) {
+	global := globals.InitGlobals("test")
+
+	if global.PreferBytecode != "" {
+		t.Errorf("Expected no classes to prefer bytecode by default, got: %s", global.PreferBytecode)
+	}
+	if _, err := setXXflag(0, "PreferBytecode=java/util/*,-java/util/HashMap", &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if global.PreferBytecode != "java/util/*,-java/util/HashMap" {
+		t.Errorf("Expected -XX:PreferBytecode to set the patterns, got: %s", global.PreferBytecode)
+	}
+	if _, err := setXXflag(0, "PreferBytecode=java/util/*,", &global); err == nil {
+		t.Error("Expected an error for an empty pattern")
+	}
+}
+
+func TestSetXXflagPrintMTableAtExit(t *testing.T) {
+	global := globals.InitGlobals("test")
+
+	if _, err := setXXflag(0, "+PrintMTableAtExit", &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !global.PrintMTable {
+		t.Error("Expected -XX:+PrintMTableAtExit to enable the dump of the MTable")
+	}
+}
+
+func TestSetLogging(t *testing.T) {
+	global := globals.InitGlobals("test")
+	trace.Init()
+	defer trace.Init()
+
+	if _, err := setLogging(0, "level=warning,classloader=trace,gfunction=off", &global); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !trace.Enabled(trace.Classloader, trace.LevelTrace) {
+		t.Error("Expected trace messages to be enabled for the classloader")
+	}
+	if trace.Enabled(trace.JVM, trace.LevelInfo) || !trace.Enabled(trace.JVM, trace.LevelWarning) {
+		t.Error("Expected the jvm subsystem to have the default level, warning")
+	}
+	if trace.Enabled(trace.Gfunction, trace.LevelError) {
+		t.Error("Expected logging to be off for gfunctions")
+	}
+	if !global.Options["-log"].Set {
+		t.Error("Expected -log option to be marked as set")
+	}
+
+	for _, bad := range []string{"verbose", "jvm=loud", "=info"} {
+		if _, err := setLogging(0, bad, &global); err == nil {
+			t.Errorf("Expected an error for -log:%s", bad)
+		}
+	}
+}
+
+func TestSetXXvalueFlags(t *testing.T) {
+	global := globals.InitGlobals("test")
+
+	if _, err := setXXflag(0, "MaxHeapSize=64m", &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if global.MaxHeapSize != 64*1024*1024 {
+		t.Errorf("Expected -XX:MaxHeapSize=64m to set a 64 MB heap, got: %d", global.MaxHeapSize)
+	}
+	if _, err := setXXflag(0, "ThreadStackSize=2048", &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if global.MaxFrameDepth != 2*1024*1024/globals.ApproxFrameSize {
+		t.Errorf("Expected -XX:ThreadStackSize=2048 to set the maximum frame depth, got: %d", global.MaxFrameDepth)
+	}
+
+	for _, bad := range []string{"MaxHeapSize=lots", "MaxHeapSize=1k", "+MaxHeapSize", "GreenThreads=true", "NoSuchFlag=1"} {
+		if _, err := setXXflag(0, bad, &global); err == nil {
+			t.Errorf("Expected an error for -XX:%s", bad)
+		}
+	}
+}
+
+func TestSetXXflagThreadStackSizeInKB(t *testing.T) {
+	for _, kb := range []string{"1", "512", "1024", "4096"} {
+		xss := globals.InitGlobals("test")
+		if _, err := setThreadStackSize(0, kb+"k", &xss); err != nil {
+			t.Fatalf("Unexpected error for -Xss%sk: %v", kb, err)
+		}
+		global := globals.InitGlobals("test")
+		if _, err := setXXflag(0, "ThreadStackSize="+kb, &global); err != nil {
+			t.Fatalf("Unexpected error for -XX:ThreadStackSize=%s: %v", kb, err)
+		}
+		if global.MaxFrameDepth != xss.MaxFrameDepth {
+			t.Errorf("Expected -XX:ThreadStackSize=%s to give the depth of -Xss%sk, %d, got: %d",
+				kb, kb, xss.MaxFrameDepth, global.MaxFrameDepth)
+		}
+		if value := xxFlagValue(t, "ThreadStackSize", &global); value != kb {
+			t.Errorf("Expected -XX:ThreadStackSize to print as %s, got: %s", kb, value)
+		}
+	}
+
+	global := globals.InitGlobals("test")
+	_, _ = setXXflag(0, "ThreadStackSize=64", &global)
+	if _, err := setXXflag(0, "ThreadStackSize=0", &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if global.MaxFrameDepth != globals.DefaultThreadStackSize/globals.ApproxFrameSize {
+		t.Errorf("Expected -XX:ThreadStackSize=0 to restore the default depth, got: %d", global.MaxFrameDepth)
+	}
+}
+
+// returns the value of the -XX flag as -XX:+PrintFlagsFinal shows it
+func xxFlagValue(t *testing.T, name string, gl *globals.Globals) string {
+	t.Helper()
+	for _, flag := range xxFlags {
+		if flag.name == name {
+			return flag.value(gl)
+		}
+	}
+	t.Fatalf("No -XX flag %s", name)
+	return ""
+}
+
+func TestInterpretOnly(t *testing.T) {
+	global := globals.InitGlobals("test")
+	LoadOptionsTable(globals.Globals{Options: global.Options}) // loads global's table
+
+	if _, err := interpretOnly(0, "", &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !global.InterpretOnly || !global.Options["-Xint"].Set {
+		t.Error("Expected -Xint to restrict execution to the interpreter")
+	}
+}
+
+func TestPrintFlagsFinal(t *testing.T) {
+	global := globals.InitGlobals("test")
+	if _, err := setXXflag(0, "+GreenThreads", &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	var out strings.Builder
+	printFlagsFinal(&out, &global)
+	flags := out.String()
+
+	if !strings.HasPrefix(flags, "[Global flags]\n") {
+		t.Errorf("Expected the flags to be headed by [Global flags], got: %s", flags)
+	}
+	for _, line := range []string{
+		"     bool EnforceAccess                            = true\n",
+		"     bool GreenThreads                             = true\n",
+		"   size_t MaxHeapSize                              = 0\n",
+	} {
+		if !strings.Contains(flags, line) {
+			t.Errorf("Expected %q in the flags, got: %s", line, flags)
+		}
+	}
+	if strings.Count(flags, "\n") != len(xxFlags)+1 {
+		t.Errorf("Expected one line per -XX flag, got: %s", flags)
+	}
+}
+
+// every option in the registry is loaded under each of its keys
+func TestLoadOptionsTableFromRegistry(t *testing.T) {
+	global := globals.InitGlobals("test")
+	LoadOptionsTable(globals.Globals{Options: global.Options}) // loads global's table
+
+	for _, spec := range optionRegistry {
+		for _, key := range spec.keys {
+			if _, ok := global.Options[key]; !ok {
+				t.Errorf("Expected option %s in the options table", key)
+			}
+		}
+	}
+	if !slices.Equal(xSizeOptions, []string{"-Xss", "-Xmx"}) {
+		t.Errorf("Unexpected options with attached values: %v", xSizeOptions)
+	}
+}
+
+// the classpath is split and its directories completed with the separators of the platform
+func TestExpandClasspathOnEachPlatform(t *testing.T) {
+	globals.InitGlobals("test")
+	gl := globals.GetGlobalRef()
+
+	tests := []struct {
+		platform  util.Platform
+		classpath string
+		expected  []string
+	}{
+		{util.Windows, `C:\lib\app.jar;C:\classes`, []string{`C:\lib\app.jar`, `C:\classes\`}},
+		{util.Posix, "/lib/app.jar:/classes", []string{"/lib/app.jar", "/classes/"}},
+	}
+	for _, test := range tests {
+		previous := util.SetPlatform(test.platform)
+		gl.Classpath = make([]string, 0)
+		gl.ClasspathRaw = test.classpath
+		expandClasspth(gl)
+		util.SetPlatform(previous)
+
+		if len(gl.Classpath) < len(test.expected) ||
+			!slices.Equal(gl.Classpath[:len(test.expected)], test.expected) {
+			t.Errorf("On %s, expected classpath %q, got %q", test.platform.Name, test.expected, gl.Classpath)
+		}
+	}
+}
+
+func TestSetXlint(t *testing.T) {
+	global := globals.InitGlobals("test")
+
+	if global.LintDeprecation {
+		t.Error("Expected -Xlint:deprecation to be off by default")
+	}
+	for _, arg := range []string{"", "all", "deprecation", "none,deprecation"} {
+		global.LintDeprecation = false
+		if _, err := setXlint(0, arg, &global); err != nil {
+			t.Errorf("Unexpected error for -Xlint:%s: %v", arg, err)
+		}
+		if !global.LintDeprecation {
+			t.Errorf("Expected -Xlint:%s to enable the deprecation warnings", arg)
+		}
+	}
+	if !global.Options["-Xlint"].Set {
+		t.Error("Expected -Xlint to be marked as set")
+	}
+
+	for _, arg := range []string{"none", "all,-deprecation"} {
+		global.LintDeprecation = true
+		if _, err := setXlint(0, arg, &global); err != nil {
+			t.Errorf("Unexpected error for -Xlint:%s: %v", arg, err)
+		}
+		if global.LintDeprecation {
+			t.Errorf("Expected -Xlint:%s to disable the deprecation warnings", arg)
+		}
+	}
+
+	if _, err := setXlint(0, "unchecked", &global); err == nil {
+		t.Error("Expected an error for the unsupported -Xlint:unchecked")
+	}
+}
+
+func TestEnablePreview(t *testing.T) {
+	global := globals.InitGlobals("test")
+	for _, spec := range optionRegistry { // load only the option under test, without copying the globals
+		for _, key := range spec.keys {
+			if key == "--enable-preview" {
+				global.Options[key] = spec.option
+			}
+		}
+	}
+
+	if global.EnablePreview {
+		t.Error("Expected preview features to be disabled by default")
+	}
+	option, ok := global.Options["--enable-preview"]
+	if !ok || !option.Supported {
+		t.Fatal("Expected --enable-preview to be a supported option")
+	}
+	if _, err := option.Action(0, "", &global); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !global.EnablePreview || !global.Options["--enable-preview"].Set {
+		t.Error("Expected --enable-preview to enable preview features and be marked as set")
+	}
+}
+
+func TestSetXXflagCompileThreshold(t *testing.T) {
+	global := globals.InitGlobals("test")
+
+	if global.JitThreshold != globals.DefaultCompileThreshold {
+		t.Errorf("Expected the default compile threshold to be %d, got: %d",
+			globals.DefaultCompileThreshold, global.JitThreshold)
+	}
+	if _, err := setXXflag(0, "CompileThreshold=500", &global); err != nil {
+		t.Errorf("Unexpected error for -XX:CompileThreshold=500: %v", err)
+	}
+	if global.JitThreshold != 500 {
+		t.Errorf("Expected -XX:CompileThreshold=500 to set the threshold, got: %d", global.JitThreshold)
+	}
+
+	for _, value := range []string{"0", "-1", "many"} {
+		if _, err := setXXflag(0, "CompileThreshold="+value, &global); err == nil {
+			t.Errorf("Expected an error for -XX:CompileThreshold=%s", value)
+		}
+	}
+}
+
+func TestSetXXflagBackEdgeThreshold(t *testing.T) {
+	global := globals.InitGlobals("test")
+
+	if global.OSRThreshold != globals.DefaultBackEdgeThreshold {
+		t.Errorf("Expected the default back-edge threshold to be %d, got: %d",
+			globals.DefaultBackEdgeThreshold, global.OSRThreshold)
+	}
+	if _, err := setXXflag(0, "BackEdgeThreshold=2000", &global); err != nil {
+		t.Errorf("Unexpected error for -XX:BackEdgeThreshold=2000: %v", err)
+	}
+	if global.OSRThreshold != 2000 {
+		t.Errorf("Expected -XX:BackEdgeThreshold=2000 to set the threshold, got: %d", global.OSRThreshold)
+	}
+	if _, err := setXXflag(0, "BackEdgeThreshold=0", &global); err == nil {
+		t.Error("Expected an error for -XX:BackEdgeThreshold=0")
+	}
+}
+
+func TestSetXXflagCountBytecodes(t *testing.T) {
+	global := globals.InitGlobals("test")
+
+	if global.CountBytecodes {
+		t.Error("Expected CountBytecodes to be off by default")
+	}
+	if _, err := setXXflag(0, "+CountBytecodes", &global); err != nil {
+		t.Errorf("Unexpected error for -XX:+CountBytecodes: %v", err)
+	}
+	if !global.CountBytecodes {
+		t.Error("Expected -XX:+CountBytecodes to enable the bytecode statistics")
+	}
+}
+
+func TestGetRecordAndReplayFiles(t *testing.T) {
+	global := globals.InitGlobals("test")
+	global.Args = []string{"-record", "run.log", "-replay", "other.log", "-replay"}
+
+	if pos, err := getRecordFile(0, "-record", &global); err != nil || pos != 1 || global.RecordFile != "run.log" {
+		t.Errorf("Expected -record to set the file to run.log at position 1, got: %s, %d, %v",
+			global.RecordFile, pos, err)
+	}
+	if pos, err := getReplayFile(2, "-replay", &global); err != nil || pos != 3 || global.ReplayFile != "other.log" {
+		t.Errorf("Expected -replay to set the file to other.log at position 3, got: %s, %d, %v",
+			global.ReplayFile, pos, err)
+	}
+	if _, err := getReplayFile(4, "-replay", &global); err == nil {
+		t.Error("Expected an error for -replay without a file name")
+	}
+	if err := startRecordOrReplay(&global); err == nil {
+		t.Error("Expected an error for -record and -replay together")
+	}
+}
+
+func TestSetXXflagSafepointTimeoutDelay(t *testing.T) {
+	global := globals.InitGlobals("test")
+
+	if global.SafepointDelay != globals.DefaultSafepointTimeoutDelay {
+		t.Errorf("Expected the default safepoint timeout to be %d, got: %d",
+			globals.DefaultSafepointTimeoutDelay, global.SafepointDelay)
+	}
+	if _, err := setXXflag(0, "SafepointTimeoutDelay=500", &global); err != nil {
+		t.Errorf("Unexpected error for -XX:SafepointTimeoutDelay=500: %v", err)
+	}
+	if global.SafepointDelay != 500 {
+		t.Errorf("Expected -XX:SafepointTimeoutDelay=500 to set the timeout, got: %d", global.SafepointDelay)
+	}
+	if _, err := setXXflag(0, "SafepointTimeoutDelay=-1", &global); err == nil {
+		t.Error("Expected an error for -XX:SafepointTimeoutDelay=-1")
+	}
+}
+
+func TestSetXXflagActiveProcessorCount(t *testing.T) {
+	global := globals.InitGlobals("test")
+
+	if global.ActiveCPUs != -1 {
+		t.Errorf("Expected the default processor count to be -1, got: %d", global.ActiveCPUs)
+	}
+	if _, err := setXXflag(0, "ActiveProcessorCount=2", &global); err != nil {
+		t.Errorf("Unexpected error for -XX:ActiveProcessorCount=2: %v", err)
+	}
+	if global.ActiveCPUs != 2 {
+		t.Errorf("Expected -XX:ActiveProcessorCount=2 to set the processor count, got: %d", global.ActiveCPUs)
+	}
+	if _, err := setXXflag(0, "ActiveProcessorCount=0", &global); err == nil {
+		t.Error("Expected an error for -XX:ActiveProcessorCount=0")
+	}
+}
+
+func TestSetXXflagPinInterpreterThreads(t *testing.T) {
+	global := globals.InitGlobals("test")
+
+	if global.PinThreads {
+		t.Error("Expected PinInterpreterThreads to be off by default")
+	}
+	if _, err := setXXflag(0, "+PinInterpreterThreads", &global); err != nil {
+		t.Errorf("Unexpected error for -XX:+PinInterpreterThreads: %v", err)
+	}
+	if !global.PinThreads {
+		t.Error("Expected -XX:+PinInterpreterThreads to pin the Java threads")
+	}
+}
+
+func TestSetXXflagUseContainerSupport(t *testing.T) {
+	global := globals.InitGlobals("test")
+
+	if !global.UseContainer {
+		t.Error("Expected UseContainerSupport to be on by default")
+	}
+	if _, err := setXXflag(0, "-UseContainerSupport", &global); err != nil {
+		t.Errorf("Unexpected error for -XX:-UseContainerSupport: %v", err)
+	}
+	if global.UseContainer {
+		t.Error("Expected -XX:-UseContainerSupport to ignore the container's limits")
+	}
+}
+
+func TestApplyContainerLimits(t *testing.T) {
+	global := globals.InitGlobals("test")
+	applyContainerLimits(&global, container.Limits{Memory: 2 * 1024 * 1024 * 1024, CPUs: 1})
+	if global.MaxHeapSize != 512*1024*1024 {
+		t.Errorf("Expected a default max heap of a quarter of the memory limit, got: %d", global.MaxHeapSize)
+	}
+	if global.ActiveCPUs != 1 {
+		t.Errorf("Expected the processors to be capped at the CPU quota, got: %d", global.ActiveCPUs)
+	}
+
+	// the options take precedence over the limits
+	global = globals.InitGlobals("test")
+	global.MaxHeapSize = 64 * 1024 * 1024
+	global.ActiveCPUs = 3
+	applyContainerLimits(&global, container.Limits{Memory: 2 * 1024 * 1024 * 1024, CPUs: 1})
+	if global.MaxHeapSize != 64*1024*1024 || global.ActiveCPUs != 3 {
+		t.Errorf("Expected -Xmx and -XX:ActiveProcessorCount to override the limits, got: %d and %d",
+			global.MaxHeapSize, global.ActiveCPUs)
+	}
+
+	global = globals.InitGlobals("test")
+	applyContainerLimits(&global, container.Limits{})
+	if global.MaxHeapSize != 0 || global.ActiveCPUs != -1 {
+		t.Errorf("Expected no limits outside a container, got: %d and %d", global.MaxHeapSize, global.ActiveCPUs)
+	}
+}
+
+func TestListGfunctionsOptions(t *testing.T) {
+	global := globals.InitGlobals("test")
+	if _, err := listGfunctions(0, "--list-gfunctions", &global); err != nil || !global.ListGfuncs || global.APICoverage {
+		t.Errorf("Expected --list-gfunctions to list the gfunctions without coverage, got error: %v", err)
+	}
+
+	global = globals.InitGlobals("test")
+	if _, err := listGfunctions(0, "--api-coverage", &global); err != nil || !global.ListGfuncs || !global.APICoverage {
+		t.Errorf("Expected --api-coverage to list the gfunctions with coverage, got error: %v", err)
+	}
+}
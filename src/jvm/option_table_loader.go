@@ -7,14 +7,20 @@
 package jvm
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"jacobin/src/charset"
+	"jacobin/src/classloader"
 	"jacobin/src/globals"
 	"jacobin/src/statics"
 	"jacobin/src/trace"
 	"jacobin/src/types"
 	"jacobin/src/util"
+	"math"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -41,84 +47,167 @@ import (
 // 		-h, -help, --help, and -?
 // because these have been handled prior to the use of this table.
 //
+// The table is loaded from two declarative registries: optionRegistry, which holds the
+// options themselves, and xxFlags, which holds the flags set by the -XX option.
+//
 // ==== How to add new options to Jacobin:
-// 1) Create an entry in LoadOptionsTable:
-//    * x := globalOptions {
-//             where param1 = is a boolean: is the option supported? s/be true
-//							  Setting it to false avoids an error message to the
-//							  user that the option is unrecognized while still
-//							  having it be unsupported
-//                   param2 = boolean: has the options been set yet? s/be false
-//					 param3 = integer as explained in the previous paragraphs
-//                   param3 = the function to perform
-//  2) Add x to the GlobalOptions table, using the string of the option as the key
-//     Note that in options with parameters after an : or an = (types 1 or 2 in
-//     param3 in step 1), you enter only the root as the key. For example, see
-//     the -verbose entry below.
-//  3) create the function referred to in param 3 in step 1. This function accepts
-//     the position in the command line where the present option is located (first
-//     option is at position zero), a string which contains any parameters (if it has
-//     no parameters an empty string is passed in), and finally a pointer to the
-//     globals data structure, which contains the Options table. The function returns
-//     an int showing the last arg processed, and an error if any.
+// 1) Add an entry to optionRegistry, consisting of:
+//    * the keys: the option as typed on the command line and any aliases. Note that in
+//      options with parameters after an : or an = (types 1 or 2 of argStyle), you enter
+//      only the root as the key. For example, see the -version entry below.
+//    * the Option: Supported is a boolean: is the option supported? s/be true. Setting
+//      it to false avoids an error message to the user that the option is unrecognized
+//      while still having it be unsupported. Set is false. ArgStyle is explained in the
+//      previous paragraphs, and Action is the function to perform.
+//    * attached: true for -X options whose value follows the option name directly, such
+//      as -Xss512k
+// 2) create the function referred to as the Action. This function accepts the position
+//    in the command line where the present option is located (first option is at position
+//    zero), a string which contains any parameters (if it has no parameters an empty string
+//    is passed in), and finally a pointer to the globals data structure, which contains
+//    the Options table. The function returns an int showing the last arg processed, and
+//    an error if any.
 //
+// ==== How to add new -XX flags: add an entry to xxFlags (see setXXflag below).
 
-// LoadOptionsTable loads the table with all the options Jacobin recognizes.
-func LoadOptionsTable(Global globals.Globals) {
+// an option in the registry, with the keys under which it's entered in the Options table
+type optionSpec struct {
+	keys     []string
+	option   globals.Option
+	attached bool // the value follows the option name directly, as in -Xss512k
+}
+
+// optionRegistry holds all the options Jacobin recognizes.
+var optionRegistry = []optionSpec{
+	{keys: []string{"-classpath", "--class-path", "-cp"},
+		option: globals.Option{Supported: true, ArgStyle: 4, Action: getClasspath}},
+
+	{keys: []string{"-client"},
+		option: globals.Option{Supported: true, ArgStyle: 0, Action: clientVM}},
+
+	// -D<name>=<value>, set a system property
+	{keys: []string{"-D"}, attached: true,
+		option: globals.Option{Supported: true, ArgStyle: 2, Action: setSystemProperty}},
+
+	// -ea[:<package>...|:<class>] and -da, enable or disable assertions, and -esa and -dsa,
+	// enable or disable them in the system classes (see globals/assertions.go)
+	{keys: []string{"-da", "-disableassertions"},
+		option: globals.Option{Supported: true, ArgStyle: 10, Action: disableAssertions}},
+
+	{keys: []string{"-dsa", "-disablesystemassertions"},
+		option: globals.Option{Supported: true, ArgStyle: 0, Action: disableSystemAssertions}},
+
+	{keys: []string{"-ea", "-enableassertions"},
+		option: globals.Option{Supported: true, ArgStyle: 10, Action: enableAssertions}},
+
+	{keys: []string{"-esa", "-enablesystemassertions"},
+		option: globals.Option{Supported: true, ArgStyle: 0, Action: enableSystemAssertions}},
+
+	// --enable-preview, allow classes that use the preview features of the latest supported Java version
+	{keys: []string{"--enable-preview"},
+		option: globals.Option{Supported: true, ArgStyle: 0, Action: enablePreview}},
+
+	{keys: []string{"-h", "-help", "-?"},
+		option: globals.Option{Supported: true, ArgStyle: 0, Action: showHelpStderrAndExit}},
+
+	{keys: []string{"--help"},
+		option: globals.Option{Supported: true, ArgStyle: 0, Action: showHelpStdoutAndExit}},
+
+	// --dry-run or --validate-only, check that the program can run on Jacobin without
+	// running it (see validate.go)
+	{keys: []string{"--dry-run", "--validate-only"},
+		option: globals.Option{Supported: true, ArgStyle: 0, Action: validateOnly}},
+
+	// --list-gfunctions and --api-coverage, list the gfunctions rather than run a program,
+	// the latter also comparing them with the methods of the JDK (see gfunction/listing.go)
+	{keys: []string{"--list-gfunctions", "--api-coverage"},
+		option: globals.Option{Supported: true, ArgStyle: 0, Action: listGfunctions}},
+
+	{keys: []string{"-jar"},
+		option: globals.Option{Supported: true, ArgStyle: 4, Action: getJarFilename}},
+
+	// -record <file> and -replay <file>, log the program's nondeterministic inputs to the
+	// file, or repeat them from it (see replay/replay.go)
+	{keys: []string{"-record"},
+		option: globals.Option{Supported: true, ArgStyle: 4, Action: getRecordFile}},
+
+	{keys: []string{"-replay"},
+		option: globals.Option{Supported: true, ArgStyle: 4, Action: getReplayFile}},
 
-	classpath := globals.Option{true, false, 4, getClasspath}
-	Global.Options["-classpath"] = classpath
-	Global.Options["--class-path"] = classpath
-	Global.Options["-cp"] = classpath
-	classpath.Set = true
+	// -selftest <dir>, run the conformance suite in the directory (see selftest/selftest.go)
+	{keys: []string{"-selftest"},
+		option: globals.Option{Supported: true, ArgStyle: 4, Action: getSelfTestDir}},
 
-	client := globals.Option{true, false, 0, clientVM}
-	Global.Options["-client"] = client
-	client.Set = true
+	{keys: []string{"-showversion"},
+		option: globals.Option{Supported: true, ArgStyle: 0, Action: showVersionStderr}},
 
-	// --dry-run option is a valid HotSpot option, but not supported in Jacobin.
-	// including it here so that we can test the unsupported option.
-	// in Hotpot, it is used to run the VM without actually running the main method.
-	dryRun := globals.Option{false, false, 0, notSupported}
-	Global.Options["--dry-run"] = dryRun
-	dryRun.Set = true
+	{keys: []string{"--show-version"},
+		option: globals.Option{Supported: true, ArgStyle: 0, Action: showVersionStdout}},
 
-	ea := globals.Option{false, false, 0, enableAssertions}
-	Global.Options["-ea"] = ea
-	Global.Options["-enableassertions"] = ea
+	{keys: []string{"-strictJDK"},
+		option: globals.Option{Supported: true, ArgStyle: 0, Action: strictJDK}},
 
-	help := globals.Option{true, false, 0, showHelpStderrAndExit}
-	Global.Options["-h"] = help
-	Global.Options["-help"] = help
-	Global.Options["-?"] = help
+	{keys: []string{"-trace"},
+		option: globals.Option{Supported: true, ArgStyle: 10, Action: enableTrace}},
 
-	helpp := globals.Option{true, false, 0, showHelpStdoutAndExit}
-	Global.Options["--help"] = helpp
+	// -log:<settings>, the format of log messages and the level of each subsystem
+	{keys: []string{"-log"},
+		option: globals.Option{Supported: true, ArgStyle: 10, Action: setLogging}},
 
-	jarFile := globals.Option{true, false, 4, getJarFilename}
-	Global.Options["-jar"] = jarFile
-	jarFile.Set = true
+	{keys: []string{"-JJ"},
+		option: globals.Option{Supported: true, ArgStyle: 10, Action: enableJJ}},
 
-	showversion := globals.Option{true, false, 0, showVersionStderr}
-	Global.Options["-showversion"] = showversion
+	{keys: []string{"-version"},
+		option: globals.Option{Supported: true, ArgStyle: 1, Action: versionStderrThenExit}},
 
-	show_Version := globals.Option{true, false, 0, showVersionStdout}
-	Global.Options["--show-version"] = show_Version
+	{keys: []string{"--version"},
+		option: globals.Option{Supported: true, ArgStyle: 1, Action: versionStdoutThenExit}},
 
-	strictJdk := globals.Option{true, false, 0, strictJDK}
-	Global.Options["-strictJDK"] = strictJdk
+	// -Xint, execute bytecode only in the interpreter
+	{keys: []string{"-Xint"},
+		option: globals.Option{Supported: true, ArgStyle: 0, Action: interpretOnly}},
 
-	traceInstruction := globals.Option{true, false, 10, enableTrace}
-	Global.Options["-trace"] = traceInstruction
+	// -Xlint:<keys>, warnings about the main class issued at startup
+	{keys: []string{"-Xlint"},
+		option: globals.Option{Supported: true, ArgStyle: 10, Action: setXlint}},
 
-	JJ := globals.Option{true, false, 10, enableJJ}
-	Global.Options["-JJ"] = JJ
+	// -Xshare:<mode> is a valid HotSpot option, but Jacobin has no class data sharing.
+	// It's accepted, with a notice, so that launch scripts that specify it still work.
+	{keys: []string{"-Xshare"},
+		option: globals.Option{Supported: false, ArgStyle: 10, Action: notSupported}},
 
-	version := globals.Option{true, false, 1, versionStderrThenExit}
-	Global.Options["-version"] = version
+	// -Xss<size>, the thread stack size
+	{keys: []string{"-Xss"}, attached: true,
+		option: globals.Option{Supported: true, ArgStyle: 1, Action: setThreadStackSize}},
 
-	vversion := globals.Option{true, false, 1, versionStdoutThenExit}
-	Global.Options["--version"] = vversion
+	// -Xmx<size>, the maximum heap size
+	{keys: []string{"-Xmx"}, attached: true,
+		option: globals.Option{Supported: true, ArgStyle: 1, Action: setMaxHeapSize}},
+
+	// -XX:+<flag>, -XX:-<flag>, and -XX:<flag>=<value> options. The key is the root, -XX,
+	// and the rest is passed to the action as the argument.
+	{keys: []string{"-XX"},
+		option: globals.Option{Supported: true, ArgStyle: 1, Action: setXXflag}},
+}
+
+// LoadOptionsTable loads the table with all the options Jacobin recognizes.
+func LoadOptionsTable(Global globals.Globals) {
+	for _, spec := range optionRegistry {
+		for _, key := range spec.keys {
+			Global.Options[key] = spec.option
+		}
+	}
+}
+
+// returns the options in the registry whose value follows the option name directly
+func attachedValueOptions() []string {
+	var roots []string
+	for _, spec := range optionRegistry {
+		if spec.attached {
+			roots = append(roots, spec.keys...)
+		}
+	}
+	return roots
 }
 
 // ---- the functions for the supported CLI options, in alphabetic order ----
@@ -161,7 +250,8 @@ func expandClasspth(gl *globals.Globals) {
 	}
 
 	// if the classpath is set by env variable or CLI, then split it into its components and expand them
-	classpaths := strings.Split(gl.ClasspathRaw, string(os.PathListSeparator))
+	platform := util.GetPlatform()
+	classpaths := platform.SplitPathList(gl.ClasspathRaw)
 
 	jarFiles := make([]string, 0, 10) // for the JAR files, if any, specified in the classpath or via wildcard
 	for _, path := range classpaths {
@@ -178,7 +268,7 @@ func expandClasspth(gl *globals.Globals) {
 
 		// expand paths that end with a wildcard
 		// (per JVM spec, only the * wildcard is allowed and it must be at end)
-		wildcard := string(os.PathSeparator) + "*"
+		wildcard := platform.Separator() + "*"
 		if strings.HasSuffix(path, wildcard) {
 			// if the path ends with a wildcard, then we need to expand it
 			// to all files in that directory
@@ -196,8 +286,8 @@ func expandClasspth(gl *globals.Globals) {
 		if strings.HasSuffix(path, ".jar") || strings.HasSuffix(path, ".JAR") {
 			gl.Classpath = append(gl.Classpath, path)
 			continue
-		} else if !strings.HasSuffix(path, string(os.PathSeparator)) { // make sure each path ends w/ a path separator
-			entry = path + string(os.PathSeparator)
+		} else if !strings.HasSuffix(path, platform.Separator()) { // make sure each path ends w/ a path separator
+			entry = path + platform.Separator()
 			gl.Classpath = append(gl.Classpath, entry)
 			continue
 		}
@@ -216,10 +306,10 @@ func checkForPreJDK9(gl *globals.Globals) {
 
 	// if JDK is pre-JDK9, then we need to add the JRE lib directory to the classpath
 	if globals.GetGlobalRef().JDKmajorVersion != 0 || globals.GetGlobalRef().JDKmajorVersion < 9 {
-		jreLibExt := "jre" + string(os.PathSeparator) + "lib" + string(os.PathSeparator) +
-			"ext" + string(os.PathSeparator)
-		if !strings.HasSuffix(gl.JavaHome, string(os.PathListSeparator)) {
-			jreLibExt += string(os.PathListSeparator)
+		platform := util.GetPlatform()
+		jreLibExt := platform.JoinPath("jre", "lib", "ext") + platform.Separator()
+		if !strings.HasSuffix(gl.JavaHome, platform.ListSeparator()) {
+			jreLibExt += platform.ListSeparator()
 		}
 		jreLibExtPath := filepath.Join(gl.JavaHome, jreLibExt) // full path to the JDK's jre/lib/ext directory
 		jars, err := util.ListJarFiles(jreLibExtPath)
@@ -233,7 +323,7 @@ func checkForPreJDK9(gl *globals.Globals) {
 				}
 				gl.Classpath = append(gl.Classpath, jar)
 			}
-			gl.ClasspathRaw = gl.ClasspathRaw + string(os.PathListSeparator) + jreLibExtPath
+			gl.ClasspathRaw = gl.ClasspathRaw + platform.ListSeparator() + jreLibExtPath
 		}
 	}
 }
@@ -256,6 +346,53 @@ func getJarFilename(pos int, name string, gl *globals.Globals) (int, error) {
 	}
 }
 
+// handles -record <file>, which logs the program's nondeterministic inputs to the file
+func getRecordFile(pos int, name string, gl *globals.Globals) (int, error) {
+	setOptionToSeen("-record", gl)
+	if len(gl.Args) > pos+1 {
+		gl.RecordFile = gl.Args[pos+1]
+		return pos + 1, nil
+	}
+	return pos, fmt.Errorf("missing file name after -record option")
+}
+
+// handles -replay <file>, which runs the program with the inputs recorded in the file
+func getReplayFile(pos int, name string, gl *globals.Globals) (int, error) {
+	setOptionToSeen("-replay", gl)
+	if len(gl.Args) > pos+1 {
+		gl.ReplayFile = gl.Args[pos+1]
+		return pos + 1, nil
+	}
+	return pos, fmt.Errorf("missing file name after -replay option")
+}
+
+// handles --validate-only and --dry-run, which load and check the program's classes
+// rather than running it
+func validateOnly(pos int, name string, gl *globals.Globals) (int, error) {
+	setOptionToSeen(name, gl)
+	gl.ValidateOnly = true
+	return pos, nil
+}
+
+// handles --list-gfunctions and --api-coverage, which list the gfunctions by class
+// rather than running a program
+func listGfunctions(pos int, name string, gl *globals.Globals) (int, error) {
+	setOptionToSeen(name, gl)
+	gl.ListGfuncs = true
+	gl.APICoverage = name == "--api-coverage"
+	return pos, nil
+}
+
+// handles -selftest <dir>, which runs the conformance suite in dir rather than a program
+func getSelfTestDir(pos int, name string, gl *globals.Globals) (int, error) {
+	setOptionToSeen("-selftest", gl)
+	if len(gl.Args) > pos+1 {
+		gl.SelfTestDir = gl.Args[pos+1]
+		return pos + 1, nil
+	}
+	return pos, fmt.Errorf("missing directory after -selftest option")
+}
+
 // generic notification function that an option is not supported
 func notSupported(pos int, arg string, gl *globals.Globals) (int, error) {
 	name := gl.Args[pos]
@@ -332,10 +469,79 @@ func enableTrace(pos int, argValue string, gl *globals.Globals) (int, error) {
 	return pos, nil
 }
 
-func enableAssertions(pos int, name string, gl *globals.Globals) (int, error) {
+// handles -log:<settings>, where the settings are separated by commas. Each is one of:
+//
+//	json                - write log messages as JSON lines, for ingestion by log pipelines
+//	text                - write log messages as text (the default)
+//	level=<level>       - set the level of all subsystems
+//	<subsystem>=<level> - set the level of one subsystem, such as classloader, jvm, gfunction, or java
+//
+// where <level> is one of trace, info, warning, error, or off. A subsystem logs only
+// the messages at or above its level. Example: -log:json,level=warning,classloader=trace
+func setLogging(pos int, argValue string, gl *globals.Globals) (int, error) {
+	setOptionToSeen("-log", gl)
+	for _, setting := range strings.Split(argValue, TraceSep) {
+		switch setting {
+		case "json":
+			trace.SetJSON(true)
+			continue
+		case "text":
+			trace.SetJSON(false)
+			continue
+		}
+
+		subsystem, levelName, found := strings.Cut(setting, "=")
+		if !found || subsystem == "" {
+			return pos, fmt.Errorf("invalid -log setting: %s", setting)
+		}
+		level, err := trace.ParseLevel(levelName)
+		if err != nil {
+			return pos, err
+		}
+		if subsystem == "level" {
+			subsystem = "" // all subsystems
+		}
+		trace.SetLevel(subsystem, level)
+	}
+	return pos, nil
+}
+
+// handles -ea and -enableassertions, with an optional argument after a colon: a
+// package name followed by ..., or a class name. Without one, they apply to all the
+// classes but the system classes, whose default status is also kept in the statics
+// as main.$assertionsDisabled.
+func enableAssertions(pos int, argValue string, gl *globals.Globals) (int, error) {
 	setOptionToSeen("-ea", gl)
-	statics.AddStatic("main.$assertionsDisabled",
-		statics.Static{Type: types.Int, Value: types.JavaBoolFalse})
+	globals.SetAssertionStatus(argValue, true)
+	if argValue == "" {
+		statics.AddStatic("main.$assertionsDisabled",
+			statics.Static{Type: types.Int, Value: types.JavaBoolFalse})
+	}
+	return pos, nil
+}
+
+// handles -da and -disableassertions, with the same arguments as -ea
+func disableAssertions(pos int, argValue string, gl *globals.Globals) (int, error) {
+	setOptionToSeen("-da", gl)
+	globals.SetAssertionStatus(argValue, false)
+	if argValue == "" {
+		statics.AddStatic("main.$assertionsDisabled",
+			statics.Static{Type: types.Int, Value: types.JavaBoolTrue})
+	}
+	return pos, nil
+}
+
+// handles -esa and -enablesystemassertions
+func enableSystemAssertions(pos int, _ string, gl *globals.Globals) (int, error) {
+	setOptionToSeen("-esa", gl)
+	globals.SetSystemAssertionStatus(true)
+	return pos, nil
+}
+
+// handles -dsa and -disablesystemassertions
+func disableSystemAssertions(pos int, _ string, gl *globals.Globals) (int, error) {
+	setOptionToSeen("-dsa", gl)
+	globals.SetSystemAssertionStatus(false)
 	return pos, nil
 }
 
@@ -353,6 +559,367 @@ func enableJJ(pos int, argValue string, gl *globals.Globals) (int, error) {
 	return pos, nil
 }
 
+// handles -Xss<size>, which sets the maximum size of each thread's stack. The size
+// is in bytes, or in kilobytes, megabytes, or gigabytes if followed by k, m, or g,
+// as in HotSpot. Because Jacobin's frames are not on a native stack, the size is
+// converted into a maximum number of frames (see globals.ApproxFrameSize). A thread
+// that exceeds it gets a StackOverflowError.
+func setThreadStackSize(pos int, argValue string, gl *globals.Globals) (int, error) {
+	setOptionToSeen("-Xss", gl)
+	return pos, applyThreadStackSize("-Xss"+argValue, argValue, gl)
+}
+
+// sets the thread stack size for -Xss and -XX:ThreadStackSize. The option is used
+// in the error messages.
+func applyThreadStackSize(option string, argValue string, gl *globals.Globals) error {
+	size, err := parseMemorySize(argValue)
+	if err != nil {
+		return fmt.Errorf("invalid thread stack size: %s", option)
+	}
+	if size < globals.ApproxFrameSize {
+		return fmt.Errorf("the thread stack size specified is too small: %s", option)
+	}
+	gl.MaxFrameDepth = int(size / globals.ApproxFrameSize)
+	return nil
+}
+
+// sets the thread stack size for -XX:ThreadStackSize, whose value is in KB rather than
+// bytes. As in HotSpot, 0 means the default size.
+func applyThreadStackSizeKB(option string, argValue string, gl *globals.Globals) error {
+	size, err := parseMemorySize(argValue)
+	if err != nil || size > math.MaxInt64/1024 {
+		return fmt.Errorf("invalid thread stack size: %s", option)
+	}
+	if size == 0 {
+		gl.MaxFrameDepth = globals.DefaultThreadStackSize / globals.ApproxFrameSize
+		return nil
+	}
+	return applyThreadStackSize(option, strconv.FormatInt(size, 10)+"k", gl)
+}
+
+// handles -Xmx<size>, which limits the size of the Java heap. The size is given as
+// for -Xss. An allocation that would exceed the limit throws an OutOfMemoryError
+// (see object/heap.go).
+func setMaxHeapSize(pos int, argValue string, gl *globals.Globals) (int, error) {
+	setOptionToSeen("-Xmx", gl)
+	return pos, applyMaxHeapSize("-Xmx"+argValue, argValue, gl)
+}
+
+// sets the maximum heap size for -Xmx and -XX:MaxHeapSize. The option is used
+// in the error messages.
+func applyMaxHeapSize(option string, argValue string, gl *globals.Globals) error {
+	size, err := parseMemorySize(argValue)
+	if err != nil {
+		return fmt.Errorf("invalid maximum heap size: %s", option)
+	}
+	if size < minHeapSize {
+		return fmt.Errorf("too small maximum heap: %s", option)
+	}
+	gl.MaxHeapSize = size
+	return nil
+}
+
+// the smallest heap that -Xmx accepts
+const minHeapSize = 1024 * 1024
+
+// parses a memory size such as 512k, 16m, or 1g, in the format HotSpot accepts
+// for -Xss, -Xmx, and similar options. Returns the size in bytes.
+func parseMemorySize(size string) (int64, error) {
+	if size == "" {
+		return 0, errors.New("missing size")
+	}
+
+	multiplier := int64(1)
+	switch size[len(size)-1] {
+	case 'k', 'K':
+		multiplier = 1024
+	case 'm', 'M':
+		multiplier = 1024 * 1024
+	case 'g', 'G':
+		multiplier = 1024 * 1024 * 1024
+	}
+	if multiplier != 1 {
+		size = size[:len(size)-1]
+	}
+
+	value, err := strconv.ParseInt(size, 10, 64)
+	if err != nil || value < 0 || value > math.MaxInt64/multiplier {
+		return 0, fmt.Errorf("invalid size: %s", size)
+	}
+	return value * multiplier, nil
+}
+
+// handles -D<name>=<value>, which sets a system property. -Dfile.encoding also sets the
+// default charset: it names a supported charset, or is COMPAT for the charset of the OS
+// and locale. As in the JDK, an unsupported charset is replaced by UTF-8. -Dline.separator
+// sets the line separator that println() and the like write.
+func setSystemProperty(pos int, argValue string, gl *globals.Globals) (int, error) {
+	setOptionToSeen("-D", gl)
+	name, value, _ := strings.Cut(argValue, "=")
+	if name == "" {
+		return pos, fmt.Errorf("missing system property name: -D%s", argValue)
+	}
+
+	switch name {
+	case "file.encoding":
+		cs := charset.Lookup(value)
+		switch {
+		case value == "COMPAT":
+			cs = charset.Native()
+		case cs == nil:
+			fmt.Fprintf(os.Stderr, "Warning: unsupported file.encoding %s, using UTF-8\n", value)
+			cs = charset.UTF8
+		}
+		charset.SetDefault(cs)
+		gl.FileEncoding = cs.Name
+		value = cs.Name
+	case "line.separator":
+		globals.SetLineSeparator(value)
+	}
+	globals.SetSystemProperty(name, value)
+	return pos, nil
+}
+
+func enablePreview(pos int, name string, gl *globals.Globals) (int, error) {
+	setOptionToSeen("--enable-preview", gl)
+	gl.EnablePreview = true
+	return pos, nil
+}
+
+// handles -Xint, which restricts execution to the interpreter, disabling the JIT
+func interpretOnly(pos int, name string, gl *globals.Globals) (int, error) {
+	setOptionToSeen("-Xint", gl)
+	gl.InterpretOnly = true
+	return pos, nil
+}
+
+// handles -Xlint:<keys>, where the keys are separated by commas, as with javac. The
+// only category presently checked is deprecation, which warns at startup of calls in
+// the main class to deprecated JDK methods that Jacobin does not support. The keys:
+//
+//	all, deprecation - enable the warnings (as does a bare -Xlint)
+//	none, -deprecation - disable them
+func setXlint(pos int, argValue string, gl *globals.Globals) (int, error) {
+	setOptionToSeen("-Xlint", gl)
+	if argValue == "" {
+		gl.LintDeprecation = true
+		return pos, nil
+	}
+	for _, key := range strings.Split(argValue, TraceSep) {
+		switch key {
+		case "all", "deprecation":
+			gl.LintDeprecation = true
+		case "none", "-deprecation":
+			gl.LintDeprecation = false
+		default:
+			return pos, fmt.Errorf("unsupported -Xlint option: %s", key)
+		}
+	}
+	return pos, nil
+}
+
+// an -XX flag. A boolean flag is enabled by -XX:+<name> and disabled by -XX:-<name>.
+// A value flag is set by -XX:<name>=<value>.
+type xxFlag struct {
+	name     string
+	typeName string                                        // the type shown by -XX:+PrintFlagsFinal
+	boolean  func(gl *globals.Globals) *bool               // for boolean flags, the setting
+	set      func(gl *globals.Globals, value string) error // for value flags, validates and applies the value
+	value    func(gl *globals.Globals) string              // for value flags, the effective value
+}
+
+// xxFlags is the registry of the -XX flags, in alphabetic order
+var xxFlags = []xxFlag{
+	// the number of processors Jacobin uses, which sets GOMAXPROCS and is the value of
+	// Runtime.availableProcessors() (-1 = all of them)
+	{name: "ActiveProcessorCount", typeName: "intx",
+		set: func(gl *globals.Globals, value string) error {
+			return applyPositiveInt("ActiveProcessorCount", value, &gl.ActiveCPUs)
+		},
+		value: func(gl *globals.Globals) string { return strconv.Itoa(gl.ActiveCPUs) }},
+
+	// the number of backward branches after which a method is compiled by the JIT and
+	// the frames running it switch to the compiled code (see jit.go)
+	{name: "BackEdgeThreshold", typeName: "intx",
+		set: func(gl *globals.Globals, value string) error {
+			return applyPositiveInt("BackEdgeThreshold", value, &gl.OSRThreshold)
+		},
+		value: func(gl *globals.Globals) string { return strconv.Itoa(gl.OSRThreshold) }},
+
+	// share the UTF-8 strings of the constant pools of loaded classes through the string pool (off by default)
+	{name: "CompactConstantPools", typeName: "bool",
+		boolean: func(gl *globals.Globals) *bool { return &gl.CompactCPs }},
+
+	// the number of invocations after which a method is compiled by the JIT (see jit.go)
+	{name: "CompileThreshold", typeName: "intx",
+		set: func(gl *globals.Globals, value string) error {
+			return applyPositiveInt("CompileThreshold", value, &gl.JitThreshold)
+		},
+		value: func(gl *globals.Globals) string { return strconv.Itoa(gl.JitThreshold) }},
+
+	// count the instructions executed for each opcode, and print them when the program ends (off by default)
+	{name: "CountBytecodes", typeName: "bool",
+		boolean: func(gl *globals.Globals) *bool { return &gl.CountBytecodes }},
+
+	// perform the access checks of JVMS 5.4.4 (on by default)
+	{name: "EnforceAccess", typeName: "bool",
+		boolean: func(gl *globals.Globals) *bool { return &gl.EnforceAccess }},
+
+	// run virtual threads on a bounded pool of goroutines (off by default)
+	{name: "GreenThreads", typeName: "bool",
+		boolean: func(gl *globals.Globals) *bool { return &gl.GreenThreads }},
+
+	// dump the heap on the first OutOfMemoryError (off by default)
+	{name: "HeapDumpOnOutOfMemoryError", typeName: "bool",
+		boolean: func(gl *globals.Globals) *bool { return &gl.HeapDumpOnOOM }},
+
+	// the maximum size of the heap in bytes, the same as -Xmx (0 = no limit)
+	{name: "MaxHeapSize", typeName: "size_t",
+		set: func(gl *globals.Globals, value string) error {
+			return applyMaxHeapSize("-XX:MaxHeapSize="+value, value, gl)
+		},
+		value: func(gl *globals.Globals) string { return strconv.FormatInt(gl.MaxHeapSize, 10) }},
+
+	// run each Java thread on its own OS thread (off by default)
+	{name: "PinInterpreterThreads", typeName: "bool",
+		boolean: func(gl *globals.Globals) *bool { return &gl.PinThreads }},
+
+	// the classes and methods whose JDK bytecode is run in place of their gfunctions (see
+	// classloader/bytecodePolicy.go); by default, none
+	{name: "PreferBytecode", typeName: "ccstr",
+		set: func(gl *globals.Globals, value string) error {
+			if err := classloader.CheckBytecodePolicy(value); err != nil {
+				return fmt.Errorf("invalid -XX:PreferBytecode=%s: %v", value, err)
+			}
+			gl.PreferBytecode = value
+			return nil
+		},
+		value: func(gl *globals.Globals) string { return gl.PreferBytecode }},
+
+	// print the final values of all the -XX flags once the command line is processed
+	{name: "PrintFlagsFinal", typeName: "bool",
+		boolean: func(gl *globals.Globals) *bool { return &gl.PrintFlags }},
+
+	// count the calls to each gfunction, and the traps hit, and print them when the program ends (off by default)
+	{name: "PrintGfunctionUsageAtExit", typeName: "bool",
+		boolean: func(gl *globals.Globals) *bool { return &gl.PrintGfuncUsage }},
+
+	// print the classes in the method area when the program ends (off by default)
+	{name: "PrintMethodAreaAtExit", typeName: "bool",
+		boolean: func(gl *globals.Globals) *bool { return &gl.PrintMethArea }},
+
+	// print the MTable, and the gfunctions that shadow loaded bytecode, when the program ends (off by default)
+	{name: "PrintMTableAtExit", typeName: "bool",
+		boolean: func(gl *globals.Globals) *bool { return &gl.PrintMTable }},
+
+	// the milliseconds to wait for the threads to reach a safepoint before a VM
+	// operation, such as a thread dump, is abandoned (see safepoint.go)
+	{name: "SafepointTimeoutDelay", typeName: "intx",
+		set: func(gl *globals.Globals, value string) error {
+			return applyPositiveInt("SafepointTimeoutDelay", value, &gl.SafepointDelay)
+		},
+		value: func(gl *globals.Globals) string { return strconv.Itoa(gl.SafepointDelay) }},
+
+	// what happens when a trapped gfunction is called: throw (the default), warn, or abort
+	{name: "TrapPolicy", typeName: "ccstr",
+		set: func(gl *globals.Globals, value string) error {
+			switch value {
+			case globals.TrapThrow, globals.TrapWarn, globals.TrapAbort:
+				gl.TrapPolicy = value
+				return nil
+			}
+			return fmt.Errorf("invalid -XX:TrapPolicy=%s: must be throw, warn, or abort", value)
+		},
+		value: func(gl *globals.Globals) string { return gl.TrapPolicy }},
+
+	// the maximum size of each thread's stack in KB, as in HotSpot, so that N is the same
+	// as -XssNk (0 = the default size)
+	{name: "ThreadStackSize", typeName: "intx",
+		set: func(gl *globals.Globals, value string) error {
+			return applyThreadStackSizeKB("-XX:ThreadStackSize="+value, value, gl)
+		},
+		value: func(gl *globals.Globals) string {
+			return strconv.Itoa(gl.MaxFrameDepth * globals.ApproxFrameSize / 1024)
+		}},
+
+	// size the heap and the processors to the limits of the container (on by default)
+	{name: "UseContainerSupport", typeName: "bool",
+		boolean: func(gl *globals.Globals) *bool { return &gl.UseContainer }},
+
+	// reuse the objects that never leave the methods that create them (off by default)
+	{name: "UseAllocationNursery", typeName: "bool",
+		boolean: func(gl *globals.Globals) *bool { return &gl.UseNursery }},
+}
+
+// sets a flag that must be a positive integer, such as one of the JIT's thresholds
+func applyPositiveInt(flag, value string, setting *int) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return fmt.Errorf("invalid -XX:%s=%s: must be a positive integer", flag, value)
+	}
+	*setting = n
+	return nil
+}
+
+// returns the -XX flag with the given name, or nil if there is none
+func findXXflag(name string) *xxFlag {
+	for i := range xxFlags {
+		if xxFlags[i].name == name {
+			return &xxFlags[i]
+		}
+	}
+	return nil
+}
+
+// handles the -XX options: -XX:+<flag> and -XX:-<flag> for boolean flags, where
+// + enables the flag and - disables it, and -XX:<flag>=<value> for value flags.
+// The flags are listed in xxFlags.
+func setXXflag(pos int, argValue string, gl *globals.Globals) (int, error) {
+	setOptionToSeen("-XX", gl)
+	if len(argValue) < 2 {
+		return pos, fmt.Errorf("invalid -XX option: %s", argValue)
+	}
+
+	if argValue[0] == '+' || argValue[0] == '-' {
+		flag := findXXflag(argValue[1:])
+		if flag == nil {
+			return pos, fmt.Errorf("unknown -XX option: %s", argValue[1:])
+		}
+		if flag.boolean == nil {
+			return pos, fmt.Errorf("-XX:%s requires a value, as in -XX:%s=<value>", flag.name, flag.name)
+		}
+		*flag.boolean(gl) = argValue[0] == '+'
+		return pos, nil
+	}
+
+	name, value, found := strings.Cut(argValue, "=")
+	if !found {
+		return pos, fmt.Errorf("invalid -XX option: %s", argValue)
+	}
+	flag := findXXflag(name)
+	if flag == nil {
+		return pos, fmt.Errorf("unknown -XX option: %s", name)
+	}
+	if flag.set == nil {
+		return pos, fmt.Errorf("-XX:%s is a boolean flag: use -XX:+%s or -XX:-%s", name, name, name)
+	}
+	return pos, flag.set(gl, value)
+}
+
+// prints the values of all the -XX flags, in the format of HotSpot's -XX:+PrintFlagsFinal
+func printFlagsFinal(out io.Writer, gl *globals.Globals) {
+	_, _ = fmt.Fprintln(out, "[Global flags]")
+	for _, flag := range xxFlags {
+		var value string
+		if flag.boolean != nil {
+			value = strconv.FormatBool(*flag.boolean(gl))
+		} else {
+			value = flag.value(gl)
+		}
+		_, _ = fmt.Fprintf(out, "%9s %-40s = %s\n", flag.typeName, flag.name, value)
+	}
+}
+
 // Marks the given option as having been 'set' that is, specified on the command line
 func setOptionToSeen(optionKey string, gl *globals.Globals) {
 	o := gl.Options[optionKey]
@@ -0,0 +1,69 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/src/excNames"
+	"jacobin/src/exceptions"
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/shutdown"
+	"jacobin/src/thread"
+	"runtime/debug"
+)
+
+// containPanic handles a Go panic that escaped the execution of a bytecode in
+// interpret(). Rather than bringing down the JVM, the panic becomes a
+// java/lang/InternalError thrown on the Java thread that was running, so that
+// the application can catch it like any other error. If the error is not caught,
+// it ends the program when thrown on the main thread, as any uncaught exception
+// does; on any other thread, it ends only that thread, so the other threads
+// keep running.
+//
+// If the panic can't be attributed to a Java thread (there is no frame, or the
+// frame's thread is not in the thread table), we fall back to showing the panic
+// and the stacks, and shutting down.
+func containPanic(fs *list.List, r any) {
+	glob := globals.GetGlobalRef()
+	glob.ErrorGoStack = string(debug.Stack())
+
+	var fr *frames.Frame
+	if fs.Len() > 0 {
+		fr = fs.Front().Value.(*frames.Frame)
+	}
+	if fr == nil || !isRegisteredThread(fr.Thread) {
+		exceptions.ShowPanicCause(r)
+		exceptions.ShowFrameStack(fs)
+		exceptions.ShowGoStackTrace(nil)
+		_ = shutdown.Exit(shutdown.APP_EXCEPTION)
+		return
+	}
+
+	errMsg := fmt.Sprintf("Go panic in %s: %v", frames.FormatFQN(fr), r)
+	var status bool
+	if fr.Thread == MainThread.ID {
+		status = exceptions.ThrowEx(excNames.InternalError, errMsg, fr)
+	} else {
+		status = exceptions.ThrowExEndingThread(excNames.InternalError, errMsg, fr)
+	}
+
+	// in tests, the exception is neither caught nor fatal, so pop the frame, else we loop endlessly
+	if status != exceptions.Caught && fs.Len() > 0 && fs.Front().Value.(*frames.Frame) == fr {
+		fs.Remove(fs.Front())
+	}
+}
+
+// isRegisteredThread reports whether the thread with the given ID is in the thread table
+func isRegisteredThread(threadID int) bool {
+	glob := globals.GetGlobalRef()
+	glob.ThreadLock.Lock()
+	defer glob.ThreadLock.Unlock()
+	_, ok := glob.Threads[threadID].(*thread.ExecThread)
+	return ok
+}
@@ -0,0 +1,106 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"errors"
+	"io"
+	"jacobin/src/classloader"
+	"jacobin/src/exceptions"
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/opcodes"
+	"jacobin/src/thread"
+	"jacobin/src/trace"
+	"os"
+	"strings"
+	"testing"
+)
+
+// makes NOP panic for the duration of a test
+func makeNopPanic(t *testing.T) {
+	if DispatchTable[opcodes.NEW] == nil {
+		initializeDispatchTable()
+	}
+	saved := DispatchTable[opcodes.NOP]
+	DispatchTable[opcodes.NOP] = func(_ *frames.Frame, _ int64) int { panic("simulated fault") }
+	t.Cleanup(func() { DispatchTable[opcodes.NOP] = saved })
+}
+
+// a panic during an upcall becomes an InternalError, which is returned to the Go caller
+func TestPanicInInterpreterBecomesInternalError(t *testing.T) {
+	globals.InitGlobals("test")
+	globals.GetGlobalRef().JacobinName = "testWithoutShutdown" // so that ThrowEx looks for a handler
+	trace.Init()
+	classloader.InitMethodArea()
+	makeNopPanic(t)
+	addUpcallTestMethod("pkg/Faulty", "run()I", []byte{
+		opcodes.NOP, opcodes.ICONST_0, opcodes.IRETURN})
+
+	th := thread.CreateThread()
+	th.Stack = frames.CreateFrameStack()
+	th.AddThreadToTable(globals.GetGlobalRef())
+	caller := frames.CreateFrame(2)
+	caller.Thread = th.ID
+	_ = frames.PushFrame(th.Stack, caller)
+
+	_, err := InvokeJavaMethod(th.Stack, "pkg/Faulty", "run", "()I", nil, nil)
+	var upcallErr *exceptions.UpcallError
+	if !errors.As(err, &upcallErr) {
+		t.Fatalf("Expected an UpcallError, got: %v", err)
+	}
+	if !strings.Contains(upcallErr.Cause, "InternalError") ||
+		!strings.Contains(upcallErr.Cause, "Go panic in pkg.Faulty.run()I: simulated fault") {
+		t.Errorf("Expected an InternalError describing the panic, got: %s", upcallErr.Cause)
+	}
+	if th.Stack.Len() != 1 || th.Stack.Front().Value.(*frames.Frame) != caller {
+		t.Errorf("Expected only the caller's frame to remain, got %d frames", th.Stack.Len())
+	}
+}
+
+// an uncaught panic on a thread other than the main thread ends only that thread
+func TestPanicEndsOnlyItsThread(t *testing.T) {
+	globals.InitGlobals("test")
+	glob := globals.GetGlobalRef()
+	glob.JacobinName = "testWithoutShutdown"
+	glob.FuncInstantiateClass = InstantiateClass
+	trace.Init()
+	classloader.InitMethodArea()
+	makeNopPanic(t)
+	addUpcallTestMethod("pkg/Worker", "run()V", []byte{opcodes.NOP, opcodes.RETURN})
+
+	savedMain := MainThread
+	MainThread = thread.CreateThread()
+	MainThread.AddThreadToTable(glob)
+	t.Cleanup(func() { MainThread = savedMain })
+
+	worker := thread.CreateThread()
+	worker.Stack = frames.CreateFrameStack()
+	worker.AddThreadToTable(glob)
+	f := frames.CreateFrame(2)
+	f.Thread = worker.ID
+	f.ClName, f.MethName, f.MethType = "pkg/Worker", "run", "()V"
+	f.Meth = []byte{opcodes.NOP, opcodes.RETURN}
+	_ = frames.PushFrame(worker.Stack, f)
+
+	normalStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	interpret(worker.Stack)
+
+	_ = w.Close()
+	os.Stderr = normalStderr
+	out, _ := io.ReadAll(r)
+
+	if worker.Stack.Len() != 0 {
+		t.Errorf("Expected the worker thread's frames to be removed, got %d frames", worker.Stack.Len())
+	}
+	if !strings.Contains(string(out), "InternalError") {
+		t.Errorf("Expected the InternalError to be reported, got: %s", string(out))
+	}
+}
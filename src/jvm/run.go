@@ -7,6 +7,8 @@
 package jvm
 
 import (
+	"container/list"
+	"errors"
 	"fmt"
 	"jacobin/src/classloader"
 	"jacobin/src/config"
@@ -22,6 +24,7 @@ import (
 	"jacobin/src/types"
 	"jacobin/src/util"
 	"os"
+	"runtime"
 	"runtime/debug"
 	"strconv"
 )
@@ -37,7 +40,15 @@ func StartExec(className string, mainThread *thread.ExecThread, globalStruct *gl
 
 	MainThread = *mainThread
 
-	me, err := classloader.FetchMethodAndCP(className, "main", "([Ljava/lang/String;)V")
+	// find main(), which may be inherited or, with --enable-preview, an instance method
+	mainMeth, err := resolveMainMethod(className, globalStruct.EnablePreview)
+	if err != nil {
+		trace.Error(err.Error())
+		shutdown.Exit(shutdown.APP_EXCEPTION)
+		return // needed for tests, in which Exit() doesn't exit
+	}
+
+	me, err := classloader.FetchMethodAndCP(mainMeth.className, "main", mainMeth.methType)
 	if err != nil {
 		errMsg := "Class not found: " + className + ".main()"
 		exceptions.ThrowEx(excNames.ClassNotFoundException, errMsg, nil)
@@ -46,9 +57,10 @@ func StartExec(className string, mainThread *thread.ExecThread, globalStruct *gl
 	m := me.Meth.(classloader.JmEntry)
 	f := frames.CreateFrame(m.MaxStack + types.StackInflator) // experiment with stack size. See JACOBIN-494
 	f.Thread = MainThread.ID
+	f.Pool = frames.NewFramePool() // the frames of the methods main() calls come from this pool
 	f.MethName = "main"
-	f.MethType = "([Ljava/lang/String;)V"
-	f.ClName = className
+	f.MethType = mainMeth.methType
+	f.ClName = mainMeth.className
 	f.CP = m.Cp                        // add its pointer to the class CP
 	f.Meth = append(f.Meth, m.Code...) // copy the bytecodes over
 
@@ -57,14 +69,20 @@ func StartExec(className string, mainThread *thread.ExecThread, globalStruct *gl
 		f.Locals = append(f.Locals, 0)
 	}
 
-	// Create an array of string objects in locals[0].
-	var objArray []*object.Object
-	for _, str := range globalStruct.AppArgs {
-		// sobj := object.NewStringFromGoString(str) // deprecated by JACOBIN-480
-		sobj := object.StringObjectFromGoString(str)
-		objArray = append(objArray, sobj)
+	// Create an array of string objects for the args, which follow the instance, if any.
+	argsLocal := 0
+	if !mainMeth.static {
+		argsLocal = 1
+	}
+	if mainMeth.methType == mainArgsType {
+		var objArray []*object.Object
+		for _, str := range globalStruct.AppArgs {
+			// sobj := object.NewStringFromGoString(str) // deprecated by JACOBIN-480
+			sobj := object.StringObjectFromGoString(str)
+			objArray = append(objArray, sobj)
+		}
+		f.Locals[argsLocal] = object.MakePrimitiveObject("[Ljava/lang/String;", types.RefArray, objArray)
 	}
-	f.Locals[0] = object.MakePrimitiveObject("[Ljava/lang/String", types.RefArray, objArray)
 
 	// create the first thread and place its first frame on it
 	MainThread.Stack = frames.CreateFrameStack()
@@ -77,10 +95,24 @@ func StartExec(className string, mainThread *thread.ExecThread, globalStruct *gl
 	}
 
 	// must first instantiate the class, so that any static initializers are run
-	_, instantiateError := InstantiateClass(className, MainThread.Stack)
+	instance, instantiateError := InstantiateClass(className, MainThread.Stack)
 	if instantiateError != nil {
-		errMsg := "Error instantiating: " + className + ".main()"
-		exceptions.ThrowEx(excNames.InstantiationException, errMsg, nil)
+		var initErr *classInitError
+		if errors.As(instantiateError, &initErr) { // the main class's <clinit> threw an exception
+			exceptions.ThrowEx(excNames.ExceptionInInitializerError, initErr.Error(), nil)
+		} else {
+			errMsg := "Error instantiating: " + className + ".main()"
+			exceptions.ThrowEx(excNames.InstantiationException, errMsg, nil)
+		}
+	}
+
+	// an instance main() is invoked on the instance, after its constructor has run. The
+	// constructor's frame goes above main()'s, so that it runs first.
+	if !mainMeth.static {
+		f.Locals[0] = instance
+		if err = pushMainConstructor(className, instance.(*object.Object), MainThread.Stack); err != nil {
+			exceptions.ThrowEx(excNames.InstantiationException, err.Error(), nil)
+		}
 	}
 
 	if globals.TraceInst {
@@ -89,6 +121,7 @@ func StartExec(className string, mainThread *thread.ExecThread, globalStruct *gl
 		trace.Trace(traceInfo)
 	}
 
+	object.SetHeapBaseline() // from here on, growth in the heap is taken to be Java objects
 	err = runThread(&MainThread)
 
 	if globals.TraceVerbose {
@@ -97,6 +130,33 @@ func StartExec(className string, mainThread *thread.ExecThread, globalStruct *gl
 	}
 }
 
+// pushes the frame of the main class's no-argument constructor, to run on the instance
+// whose instance main() is the program's, onto the frame stack
+func pushMainConstructor(className string, instance *object.Object, fs *list.List) error {
+	me, err := classloader.FetchMethodAndCP(className, "<init>", "()V")
+	if err != nil {
+		return err
+	}
+	m, ok := me.Meth.(classloader.JmEntry)
+	if !ok {
+		return fmt.Errorf("the constructor of %s is not a Java method", className)
+	}
+
+	f := frames.CreateFrame(m.MaxStack + types.StackInflator)
+	f.Thread = MainThread.ID
+	f.Pool = frames.NewFramePool()
+	f.MethName = "<init>"
+	f.MethType = "()V"
+	f.ClName = className
+	f.CP = m.Cp
+	f.Meth = append(f.Meth, m.Code...)
+	for k := 0; k < m.MaxLocals; k++ {
+		f.Locals = append(f.Locals, 0)
+	}
+	f.Locals[0] = instance
+	return frames.PushFrame(fs, f)
+}
+
 // Point the thread to the top of the frame stack and tell it to run from there.
 func runThread(t *thread.ExecThread) error {
 
@@ -114,9 +174,17 @@ func runThread(t *thread.ExecThread) error {
 		return shutdown.OK
 	}()
 
+	if globals.GetGlobalRef().PinThreads { // -XX:+PinInterpreterThreads
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+	}
+
+	thread.RegisterForSafepoints(t.ID) // see safepoint.go
 	for t.Stack.Len() > 0 {
 		interpret(t.Stack)
 	}
+	thread.UnregisterFromSafepoints(t.ID)
+	t.Terminate() // release any threads that are joining this one
 
 	if t.Stack.Len() == 0 { // true when the last executed frame was main()
 		return nil
@@ -530,7 +598,7 @@ func createAndInitNewFrame(
 		stackSize = 2
 	}
 
-	fram := frames.CreateFrame(stackSize)
+	fram := currFrame.Pool.CreateFrame(stackSize) // reuses a frame if the thread has a frame pool
 	fram.Thread = currFrame.Thread
 	fram.FrameStack = currFrame.FrameStack
 	fram.ClName = className
@@ -538,6 +606,9 @@ func createAndInitNewFrame(
 	fram.MethType = methodType
 	fram.CP = m.Cp                           // add its pointer to the class CP
 	fram.Meth = append(fram.Meth, m.Code...) // copy the method's bytecodes over
+	if compiled, jm := jitCodeFor(className, methodName, methodType, m.Code); jm != nil {
+		fram.Compiled, fram.JitMethod = compiled, jm
+	}
 
 	// pop the parameters off the present stack and put them in
 	// the new frame's locals. This is done in reverse order so
@@ -666,5 +737,9 @@ func createAndInitNewFrame(
 
 	fram.TOS = -1
 
+	if globals.TraceVerbose {
+		LogTraceLocals(fram)
+	}
+
 	return fram, nil
 }
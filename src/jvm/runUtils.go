@@ -365,13 +365,12 @@ func checkcastNonArrayObject(obj *object.Object, className string) bool {
 		classPtr = classloader.MethAreaFetch(className)
 	}
 
-	// if classPtr does not point to the entry for the same class, then examine superclasses
+	// if classPtr does not point to the entry for the same class, then examine the
+	// superclasses and, if the class is an interface, the implemented interfaces
 	if classPtr == classloader.MethAreaFetch(*(stringPool.GetStringPointer(obj.KlassName))) {
 		return true
-	} else if isClassAaSublclassOfB(obj.KlassName, stringPool.GetStringIndex(&className)) {
-		return true
 	}
-	return false
+	return classloader.IsAssignableTo(*(stringPool.GetStringPointer(obj.KlassName)), className)
 }
 
 // do the checkcast logic for an array. The rules are:
@@ -688,6 +687,32 @@ func LogTraceStack(f *frames.Frame) {
 	}
 }
 
+// Log the local variables of a frame, named from the method's LocalVariableTable
+// if the class was compiled with -g, and otherwise by slot number
+func LogTraceLocals(f *frames.Frame) {
+	localVars := classloader.FetchLocalVariables(f.ClName, f.MethName, f.MethType)
+	for slot, value := range f.Locals {
+		name := fmt.Sprintf("<local%d>", slot)
+		if lv, ok := classloader.LocalVariableAt(localVars, slot, f.PC); ok {
+			name = lv.Name
+		}
+
+		var output string
+		switch v := value.(type) {
+		case *object.Object:
+			if object.IsNull(v) {
+				output = "<null>"
+			} else {
+				output = v.FormatField("")
+			}
+		default:
+			output = fmt.Sprintf("%T %v ", value, value)
+		}
+		traceInfo := fmt.Sprintf("%55s %s.%s local [%d] %s: %s", "", f.ClName, f.MethName, slot, name, output)
+		trace.Trace(traceInfo)
+	}
+}
+
 // TraceObject : Used by push, pop, and peek in tracing an object.
 func TraceObject(f *frames.Frame, opStr string, obj *object.Object) {
 	var traceInfo string
@@ -0,0 +1,91 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package jvm
+
+import (
+	"fmt"
+	"io"
+	"jacobin/src/exceptions"
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/thread"
+	"jacobin/src/trace"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+)
+
+// Safepoints (see thread/safepoint.go for the protocol). The interpreter polls for a
+// safepoint at method entry and at every backward branch, so a thread running Java
+// code stops within a bounded number of instructions. The operations that need the
+// threads stopped use atSafepoint(): the heap dump on an OutOfMemoryError and the
+// thread dump, which is printed when Jacobin receives SIGQUIT (Ctrl-\ on the console),
+// as in HotSpot.
+
+// polls for a safepoint, parking the thread if the VM has requested one
+func pollSafepoint(fr *frames.Frame) {
+	if thread.SafepointRequested.Load() {
+		thread.SafepointPoll(fr.Thread, fmt.Sprintf("%s.%s%s at PC %d", fr.ClName, fr.MethName, fr.MethType, fr.PC))
+	}
+}
+
+// runs op with all the Java threads other than the requester stopped at a safepoint.
+// If they can't all be stopped within -XX:SafepointTimeoutDelay, op is not run and
+// the error describes the threads that did not stop.
+func atSafepoint(requester int, operation string, op func()) error {
+	timeout := time.Duration(globals.GetGlobalRef().SafepointDelay) * time.Millisecond
+	if err := thread.StopTheWorld(requester, operation, timeout); err != nil {
+		return err
+	}
+	defer thread.ResumeTheWorld()
+	op()
+	return nil
+}
+
+// DumpThreads writes the frame stack of each Java thread to out, followed by any
+// deadlocks among them, with the threads stopped at a safepoint. requester is the
+// thread asking for the dump, or 0 if it's not a Java thread.
+func DumpThreads(out io.Writer, requester int) error {
+	return atSafepoint(requester, "a thread dump", func() {
+		glob := globals.GetGlobalRef()
+		glob.ThreadLock.Lock()
+		var threads []*thread.ExecThread
+		for _, t := range glob.Threads {
+			if th, ok := t.(*thread.ExecThread); ok {
+				threads = append(threads, th)
+			}
+		}
+		glob.ThreadLock.Unlock()
+		sort.Slice(threads, func(i, j int) bool { return threads[i].ID < threads[j].ID })
+
+		_, _ = fmt.Fprintf(out, "Full thread dump Jacobin VM (%s):\n", glob.Version)
+		for _, th := range threads {
+			_, _ = fmt.Fprintf(out, "\n\"%s\" (thread %d):\n", thread.ThreadName(th.ID), th.ID)
+			for _, entry := range *exceptions.GrabFrameStack(th.Stack) {
+				_, _ = fmt.Fprintf(out, "\t%s\n", entry)
+			}
+		}
+		if report := thread.DeadlockReport(thread.FindDeadlocks()); report != "" {
+			_, _ = fmt.Fprintf(out, "\n%s\n", report)
+		}
+	})
+}
+
+// prints a thread dump to stderr whenever Jacobin receives SIGQUIT
+func startThreadDumpOnSignal() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGQUIT)
+	go func() {
+		for range signals {
+			if err := DumpThreads(os.Stderr, 0); err != nil {
+				trace.Error("thread dump: " + err.Error())
+			}
+		}
+	}()
+}
@@ -0,0 +1,65 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package jvm
+
+import (
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/thread"
+	"jacobin/src/trace"
+	"testing"
+	"time"
+)
+
+func TestInterpreterStopsAtSafepoint(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+
+	// jitSumLoop with a bound it won't reach, so the loop runs until the test ends it
+	fr := frames.CreateFrame(4)
+	fr.Meth = append(fr.Meth, jitSumLoop...)
+	fr.Locals = []any{int64(1) << 40, int64(0), int64(0)}
+	fr.Thread = 7
+	fs := frames.CreateFrameStack()
+	fs.PushFront(fr)
+
+	thread.RegisterForSafepoints(7)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer thread.UnregisterFromSafepoints(7)
+		interpret(fs)
+	}()
+
+	// the thread stops first at method entry (PC 0) or at the backward branch to PC 4,
+	// and, once it's running the loop, only at the branch. While the thread is stopped,
+	// its frame can safely be changed: end the loop.
+	for attempt := 0; ; attempt++ {
+		var pc int
+		err := atSafepoint(0, "a test", func() {
+			if pc = fr.PC; pc == 4 {
+				fr.Locals[0] = int64(0)
+			}
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if pc == 4 {
+			break
+		}
+		if pc != 0 || attempt == 100 {
+			t.Fatalf("Expected the thread to stop at PC 0 or 4, got PC %d at attempt %d", pc, attempt)
+		}
+		time.Sleep(time.Millisecond) // let the thread run
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the loop to end once the thread was resumed")
+	}
+}
@@ -0,0 +1,167 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"jacobin/src/classloader"
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/opcodes"
+	"jacobin/src/stringPool"
+	"jacobin/src/thread"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+	"sync"
+	"time"
+)
+
+// how often the deadlock detector checks the waits-for graph (see thread/deadlock.go)
+const deadlockCheckInterval = time.Second
+
+var deadlockDetectorOnce sync.Once
+
+const threadClassName = "java/lang/Thread"
+
+// StartJavaThread starts running the java/lang/Thread object threadObj, whose
+// "target" field holds the Runnable to run. If it has none, the thread runs its own
+// run() method, as a subclass of Thread does. The new thread gets its own ExecThread
+// and frame stack, which begins with a frame for the Runnable's run() method, and
+// it inherits the InheritableThreadLocals of the thread whose frame stack is
+// parentFs, each with the value the local's childValue() returns. The thread's ID
+// is stored in the "ID" field of threadObj, which is how the Thread object is later
+// matched to the running thread.
+//
+// Virtual threads are run on the green-thread scheduler if -XX:+GreenThreads is
+// in effect (see greenThreads.go); all other threads get their own goroutine.
+func StartJavaThread(parentFs *list.List, threadObj any) error {
+	t, ok := threadObj.(*object.Object)
+	if !ok || object.IsNull(t) {
+		return errors.New("StartJavaThread: invalid Thread object")
+	}
+	glob := globals.GetGlobalRef()
+
+	if DispatchTable[opcodes.NEW] == nil { // make sure threads don't race to initialize it
+		initializeDispatchTable()
+	}
+
+	// deadlocks require at least two threads, so the detector starts with the first new thread
+	deadlockDetectorOnce.Do(func() {
+		thread.StartDeadlockDetector(deadlockCheckInterval, func(report string) {
+			trace.Error("deadlock detected\n" + report)
+		})
+	})
+
+	th := thread.CreateThread()
+	execThread := &th
+	execThread.Stack = frames.CreateFrameStack()
+	execThread.JavaThread = t
+	t.FieldTable["ID"] = object.Field{Ftype: types.Int, Fvalue: int64(execThread.ID)}
+
+	inherit, ok := t.FieldTable["inheritThreadLocals"].Fvalue.(int64)
+	if (!ok || inherit == types.JavaBoolTrue) && parentFs != nil && parentFs.Len() > 0 {
+		parentID := parentFs.Front().Value.(*frames.Frame).Thread
+		glob.ThreadLock.Lock()
+		parent, _ := glob.Threads[parentID].(*thread.ExecThread)
+		glob.ThreadLock.Unlock()
+		if err := execThread.InheritThreadLocals(parent, childThreadLocalValue(parentFs)); err != nil {
+			return err
+		}
+	}
+
+	// a thread without a Runnable runs its own run(), which a subclass of Thread overrides
+	runnable, _ := t.FieldTable["target"].Fvalue.(*object.Object)
+	if object.IsNull(runnable) {
+		runnable = t
+	}
+
+	fram, err := createRunFrame(execThread, runnable)
+	if errors.Is(err, errNothingToRun) { // Thread's own run() does nothing without a Runnable
+		execThread.Terminate()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err = frames.PushFrame(execThread.Stack, fram); err != nil {
+		return err
+	}
+	execThread.AddThreadToTable(glob)
+
+	virtual, _ := t.FieldTable["virtual"].Fvalue.(int64)
+	if globals.TraceVerbose {
+		trace.Trace(fmt.Sprintf("StartJavaThread: thread %d, runnable: %s, virtual: %v",
+			execThread.ID, fram.ClName, virtual == types.JavaBoolTrue))
+	}
+
+	if virtual == types.JavaBoolTrue && glob.GreenThreads {
+		submitGreenThread(execThread)
+	} else {
+		go func() {
+			_ = runThread(execThread)
+			execThread.RemoveThreadFromTable(glob)
+		}()
+	}
+	return nil
+}
+
+// childThreadLocalValue returns the function that gives a new thread its value of an
+// InheritableThreadLocal: the value that the local's childValue() method returns for
+// the parent's value. childValue() is called on the parent's frame stack, parentFs,
+// unless it's the gfunction of InheritableThreadLocal, which returns the parent's value.
+func childThreadLocalValue(parentFs *list.List) func(local, parentValue any) (any, error) {
+	return func(local, parentValue any) (any, error) {
+		tl, ok := local.(*object.Object)
+		if !ok || object.IsNull(tl) {
+			return parentValue, nil
+		}
+		className := *stringPool.GetStringPointer(tl.KlassName)
+		mtEntry, err := classloader.FetchMethodAndCP(className, "childValue", "(Ljava/lang/Object;)Ljava/lang/Object;")
+		if err != nil || mtEntry.MType != 'J' {
+			return parentValue, nil
+		}
+		return InvokeJavaMethod(parentFs, className, "childValue", "(Ljava/lang/Object;)Ljava/lang/Object;",
+			tl, []any{parentValue})
+	}
+}
+
+// returned by createRunFrame() for a Thread whose run() is that of java/lang/Thread,
+// which has nothing to run if the thread wasn't given a Runnable
+var errNothingToRun = errors.New("StartJavaThread: the thread has no run() method to run")
+
+// createRunFrame creates the frame for the run() method that a new thread executes:
+// that of its Runnable, or that of the Thread object itself
+func createRunFrame(th *thread.ExecThread, runnable *object.Object) (*frames.Frame, error) {
+	className := *stringPool.GetStringPointer(runnable.KlassName)
+	if className == threadClassName {
+		return nil, errNothingToRun
+	}
+	mtEntry, err := classloader.FetchMethodAndCP(className, "run", "()V")
+	if err != nil || mtEntry.Meth == nil {
+		return nil, fmt.Errorf("StartJavaThread: run() method not found in %s", className)
+	}
+	if mtEntry.MType != 'J' {
+		return nil, fmt.Errorf("StartJavaThread: run() method of %s is not a Java method", className)
+	}
+	if mtEntry.Meth.(classloader.JmEntry).Class == threadClassName {
+		return nil, errNothingToRun // a subclass of Thread that doesn't override run()
+	}
+
+	// createAndInitNewFrame() takes the objectRef from the invoking frame, so we
+	// use a placeholder frame for the new thread to hold it
+	launcher := frames.CreateFrame(1)
+	launcher.Thread = th.ID
+	launcher.FrameStack = th.Stack
+	launcher.Pool = frames.NewFramePool() // each thread has its own pool of frames
+	push(launcher, runnable)
+
+	m := mtEntry.Meth.(classloader.JmEntry)
+	return createAndInitNewFrame(className, "run", "()V", &m, true, launcher)
+}
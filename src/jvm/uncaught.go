@@ -0,0 +1,64 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"jacobin/src/exceptions"
+	"jacobin/src/frames"
+	"jacobin/src/gfunction"
+	"jacobin/src/object"
+	"jacobin/src/thread"
+	"jacobin/src/trace"
+)
+
+// As in the JDK, an exception that a thread doesn't catch is passed to the thread's
+// uncaught exception handler, set by Thread.setUncaughtExceptionHandler(), or if it
+// has none, to the default handler, set by Thread.setDefaultUncaughtExceptionHandler().
+// The handler is Java code, which runs on the thread, on top of the frames the
+// exception is escaping. Only if there's no handler is the exception's stack trace
+// printed. Either way, the exception then ends the thread--and if it's the main
+// thread, the program, whose exit status is 1.
+
+const (
+	uncaughtHandlerClass = "java/lang/Thread$UncaughtExceptionHandler"
+	uncaughtHandlerType  = "(Ljava/lang/Thread;Ljava/lang/Throwable;)V"
+)
+
+// dispatchUncaughtException passes the throwable, which escaped the code on the frame
+// stack fs, to the thread's uncaught exception handler. Returns false if the thread
+// has no handler, in which case the caller prints the exception. It's called through
+// globals.FuncUncaughtHandler, both by ATHROW and by the exceptions package.
+func dispatchUncaughtException(fs *list.List, throwable any) bool {
+	if fs.Len() == 0 {
+		return false
+	}
+	threadID := fs.Front().Value.(*frames.Frame).Thread
+	t := gfunction.ThreadObjectOf(fs)
+	handler := gfunction.UncaughtExceptionHandlerOf(t)
+	if handler == nil {
+		return false
+	}
+	if t == nil {
+		t = object.Null
+	}
+
+	_, err := InvokeJavaMethod(fs, uncaughtHandlerClass, "uncaughtException", uncaughtHandlerType,
+		handler, []any{t, throwable})
+	if err != nil { // as in the JDK, an exception thrown by the handler is reported, then ignored
+		var upcallErr *exceptions.UpcallError
+		cause := err.Error()
+		if errors.As(err, &upcallErr) {
+			cause = upcallErr.Cause
+		}
+		trace.Error(fmt.Sprintf("Exception: %s thrown from the UncaughtExceptionHandler in thread \"%s\"",
+			cause, thread.ThreadName(threadID)))
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"container/list"
+	"io"
+	"jacobin/src/classloader"
+	"jacobin/src/frames"
+	"jacobin/src/gfunction"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/opcodes"
+	"jacobin/src/thread"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+	"os"
+	"strings"
+	"testing"
+)
+
+// creates a thread with a frame, and returns its frame stack
+func uncaughtTestThread() *list.List {
+	th := thread.CreateThread()
+	th.Stack = frames.CreateFrameStack()
+	th.AddThreadToTable(globals.GetGlobalRef())
+	f := frames.CreateFrame(2)
+	f.Thread = th.ID
+	_ = frames.PushFrame(th.Stack, f)
+	return th.Stack
+}
+
+func TestDispatchUncaughtExceptionWithoutHandler(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	className := "java/lang/RuntimeException"
+	throwable := object.MakeEmptyObjectWithClassName(&className)
+	if dispatchUncaughtException(uncaughtTestThread(), throwable) {
+		t.Errorf("Expected the exception not to be handled by a thread with no handler")
+	}
+}
+
+func TestDispatchUncaughtExceptionToHandler(t *testing.T) {
+	globals.InitGlobals("test")
+	globals.GetGlobalRef().JacobinName = "testWithoutShutdown" // so that exceptions look for a handler
+	trace.Init()
+	classloader.InitMethodArea()
+	addInitTestClass("pkg/Handler", types.ObjectClassName, false, nil)
+
+	// the handler rethrows the exception it's given, which shows that it ran and got the
+	// exception, and is reported as thrown from the handler
+	classloader.MTable["pkg/Handler.uncaughtException"+uncaughtHandlerType] = classloader.MTentry{
+		MType: 'J',
+		Meth: classloader.JmEntry{
+			MaxStack:  2,
+			MaxLocals: 3,
+			Code:      []byte{opcodes.ALOAD_2, opcodes.ATHROW},
+			Cp:        &classloader.CPool{},
+		},
+	}
+	handlerClass := "pkg/Handler"
+	handler := object.MakeEmptyObjectWithClassName(&handlerClass)
+	exceptionClass := "java/lang/IllegalStateException"
+	throwable := object.MakeEmptyObjectWithClassName(&exceptionClass)
+
+	fs := uncaughtTestThread()
+	th := gfunction.ThreadObjectOf(fs)
+	th.FieldTable["uncaughtExceptionHandler"] = object.Field{Ftype: types.Ref, Fvalue: handler}
+
+	normalStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+	handled := dispatchUncaughtException(fs, throwable)
+	_ = w.Close()
+	os.Stderr = normalStderr
+	out, _ := io.ReadAll(r)
+
+	if !handled {
+		t.Fatalf("Expected the exception to be passed to the handler")
+	}
+	if !strings.Contains(string(out), "Exception: java.lang.IllegalStateException thrown from the UncaughtExceptionHandler in thread") {
+		t.Errorf("Expected the handler's exception to be reported, got: %s", string(out))
+	}
+	if fs.Len() != 1 {
+		t.Errorf("Expected the frame stack to be restored, got %d frames", fs.Len())
+	}
+}
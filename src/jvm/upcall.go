@@ -0,0 +1,151 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"jacobin/src/classloader"
+	"jacobin/src/exceptions"
+	"jacobin/src/frames"
+	"jacobin/src/gfunction"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"jacobin/src/stringPool"
+	"jacobin/src/trace"
+	"strings"
+)
+
+// This file contains the bridge that lets Go code, principally gfunctions, call
+// a Java method and obtain its result. This is needed by library methods that take
+// Java objects whose methods must be run, such as the Supplier passed to
+// ThreadLocal.withInitial().
+//
+// The bridge pushes an upcall frame (see frames.UpcallMethName) onto the frame
+// stack. The receiver and arguments are pushed onto the upcall frame's operand
+// stack, from which the method's frame is created just as if the upcall frame had
+// executed an invoke bytecode. The interpreter then runs until the frame stack is
+// back to the upcall frame, where the method's return value (if any) is found. An
+// exception that is not caught by the called method (or the methods it calls)
+// stops at the upcall frame and is returned to the caller as an error.
+
+// InvokeJavaMethod runs the named method on the frame stack fs and returns its
+// return value, which is nil for void methods. If receiver is nil, the method is
+// static; otherwise, it is an instance method, which is looked up starting from
+// the runtime class of the receiver, with className as a fallback. Args holds
+// the arguments in the form they take on the operand stack (e.g., int64 for ints,
+// float64 for doubles).
+func InvokeJavaMethod(fs *list.List, className, methName, methType string,
+	receiver any, args []any) (any, error) {
+
+	fqn := className + "." + methName + methType
+	lookupClass := className
+	if obj, ok := receiver.(*object.Object); ok && !object.IsNull(obj) {
+		runtimeClass := *stringPool.GetStringPointer(obj.KlassName)
+		if !strings.HasPrefix(runtimeClass, "[") {
+			lookupClass = runtimeClass
+		}
+	}
+
+	mtEntry, err := classloader.FetchMethodAndCP(lookupClass, methName, methType)
+	if (err != nil || mtEntry.Meth == nil) && lookupClass != className {
+		mtEntry, err = classloader.FetchMethodAndCP(className, methName, methType)
+	}
+	if err != nil || mtEntry.Meth == nil {
+		return nil, fmt.Errorf("InvokeJavaMethod: method not found: %s", fqn)
+	}
+
+	// push the upcall frame, which holds the receiver and arguments
+	upcall := frames.CreateFrame(len(args) + 2)
+	upcall.MethName = frames.UpcallMethName
+	upcall.ClName = className
+	upcall.MethType = methType
+	upcall.FrameStack = fs
+	if fs.Len() > 0 {
+		upcall.Thread = fs.Front().Value.(*frames.Frame).Thread
+		upcall.Pool = fs.Front().Value.(*frames.Frame).Pool
+	}
+	if receiver != nil {
+		push(upcall, receiver)
+	}
+	for _, arg := range args {
+		push(upcall, arg)
+	}
+	if err = frames.PushFrame(fs, upcall); err != nil {
+		return nil, upcallPushError(fqn, err)
+	}
+	upcallDepth := fs.Len()
+	defer func() { // whatever happens, leave the frame stack as we found it
+		for fs.Len() >= upcallDepth {
+			fs.Remove(fs.Front())
+		}
+	}()
+
+	if globals.TraceInst {
+		trace.Trace(fmt.Sprintf("InvokeJavaMethod: %s (resolved in %s)", fqn, lookupClass))
+	}
+
+	switch mtEntry.MType {
+	case 'G':
+		// gfunctions take their parameters in the order the invoke bytecodes build them
+		var params []any
+		for i := len(args) - 1; i >= 0; i-- {
+			params = append(params, args[i])
+		}
+		if receiver != nil {
+			params = append(params, receiver)
+		}
+		ret := gfunction.RunGfunction(mtEntry, fs, className, methName, methType, &params,
+			receiver != nil, MainThread.Trace)
+		if upcall.Uncaught != "" {
+			return nil, &exceptions.UpcallError{Method: fqn, Cause: upcall.Uncaught, Throwable: upcall.UncaughtObj}
+		}
+		if err, ok := ret.(error); ok {
+			if errors.Is(err, gfunction.CaughtGfunctionException) {
+				return nil, &exceptions.UpcallError{Method: fqn, Cause: err.Error()}
+			}
+			return nil, err
+		}
+		return ret, nil
+
+	case 'J':
+		m := mtEntry.Meth.(classloader.JmEntry)
+		if m.AccessFlags&0x0100 > 0 {
+			return nil, fmt.Errorf("InvokeJavaMethod: native method requested: %s", fqn)
+		}
+		fram, err := createAndInitNewFrame(lookupClass, methName, methType, &m, receiver != nil, upcall)
+		if err != nil {
+			return nil, err
+		}
+		if err = frames.PushFrame(fs, fram); err != nil {
+			return nil, upcallPushError(fqn, err)
+		}
+
+		for fs.Len() > upcallDepth {
+			interpret(fs)
+		}
+		if upcall.Uncaught != "" {
+			return nil, &exceptions.UpcallError{Method: fqn, Cause: upcall.Uncaught, Throwable: upcall.UncaughtObj}
+		}
+		if strings.HasSuffix(methType, ")V") || upcall.TOS < 0 {
+			return nil, nil
+		}
+		return pop(upcall), nil
+	}
+
+	return nil, fmt.Errorf("InvokeJavaMethod: unsupported method type '%c': %s", mtEntry.MType, fqn)
+}
+
+// a frame stack that is too deep to take another frame is reported to the Go
+// caller as a StackOverflowError in the called method
+func upcallPushError(fqn string, err error) error {
+	if errors.Is(err, frames.ErrStackOverflow) {
+		return &exceptions.UpcallError{Method: fqn, Cause: "java.lang.StackOverflowError"}
+	}
+	return err
+}
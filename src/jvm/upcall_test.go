@@ -0,0 +1,89 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"errors"
+	"jacobin/src/classloader"
+	"jacobin/src/exceptions"
+	"jacobin/src/frames"
+	"jacobin/src/globals"
+	"jacobin/src/opcodes"
+	"jacobin/src/thread"
+	"jacobin/src/trace"
+	"jacobin/src/types"
+	"strings"
+	"testing"
+)
+
+// adds a static Java method with the given bytecode to the MTable, and its
+// class to the method area
+func addUpcallTestMethod(className, methNameAndType string, code []byte) {
+	if classloader.MethAreaFetch(className) == nil {
+		addInitTestClass(className, types.ObjectClassName, false, nil)
+	}
+	classloader.MTable[className+"."+methNameAndType] = classloader.MTentry{
+		MType: 'J',
+		Meth: classloader.JmEntry{
+			MaxStack:  4,
+			MaxLocals: 2,
+			Code:      code,
+			Cp:        &classloader.CPool{},
+		},
+	}
+}
+
+func TestInvokeJavaMethodReturnsValue(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+	addUpcallTestMethod("pkg/Calc", "add(II)I", []byte{
+		opcodes.ILOAD_0, opcodes.ILOAD_1, opcodes.IADD, opcodes.IRETURN})
+
+	fs := frames.CreateFrameStack()
+	_ = frames.PushFrame(fs, frames.CreateFrame(2))
+
+	ret, err := InvokeJavaMethod(fs, "pkg/Calc", "add", "(II)I", nil, []any{int64(2), int64(3)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ret != int64(5) {
+		t.Errorf("Expected 5, got: %v", ret)
+	}
+	if fs.Len() != 1 {
+		t.Errorf("Expected the frame stack to be restored to 1 frame, got: %d", fs.Len())
+	}
+}
+
+func TestInvokeJavaMethodUncaughtException(t *testing.T) {
+	globals.InitGlobals("test")
+	globals.GetGlobalRef().JacobinName = "testWithoutShutdown" // so that ThrowEx looks for a handler
+	trace.Init()
+	classloader.InitMethodArea()
+	addUpcallTestMethod("pkg/Calc", "divByZero()I", []byte{
+		opcodes.ICONST_1, opcodes.ICONST_0, opcodes.IDIV, opcodes.IRETURN})
+
+	th := thread.CreateThread()
+	th.Stack = frames.CreateFrameStack()
+	th.AddThreadToTable(globals.GetGlobalRef())
+	caller := frames.CreateFrame(2)
+	caller.Thread = th.ID
+	_ = frames.PushFrame(th.Stack, caller)
+
+	// the exception stops at the upcall frame and is returned as an error
+	_, err := InvokeJavaMethod(th.Stack, "pkg/Calc", "divByZero", "()I", nil, nil)
+	var upcallErr *exceptions.UpcallError
+	if !errors.As(err, &upcallErr) {
+		t.Fatalf("Expected an UpcallError, got: %v", err)
+	}
+	if !strings.Contains(upcallErr.Cause, "ArithmeticException") {
+		t.Errorf("Expected an ArithmeticException, got: %s", upcallErr.Cause)
+	}
+	if th.Stack.Len() != 1 || th.Stack.Front().Value.(*frames.Frame) != caller {
+		t.Errorf("Expected only the caller's frame to remain, got %d frames", th.Stack.Len())
+	}
+}
@@ -0,0 +1,155 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"fmt"
+	"io"
+	"jacobin/src/classloader"
+	"jacobin/src/excNames"
+	"jacobin/src/gfunction"
+	"jacobin/src/globals"
+	"jacobin/src/opcodes"
+	"jacobin/src/util"
+	"sort"
+	"strings"
+)
+
+// The validation mode, --validate-only (or --dry-run), checks whether a program can
+// run on Jacobin without running any of it. Starting from the main class, it loads
+// the program's classes--the main class and every class it refers to, transitively--
+// which parses and format-checks them, verifies the code of their methods, and checks
+// the methods they call against the gfunctions that Jacobin traps. The JDK classes
+// the program refers to are loaded, to check that they exist, but not followed
+// further, since their closure is most of the JDK. Every problem is reported, not
+// just the first, so one run shows all that stands in the way of running the program.
+
+// validateProgram validates the program whose main class is given, writes the
+// report to out, and returns the number of problems found
+func validateProgram(mainClass string, out io.Writer) int {
+	classes, problems := validateClosure(mainClass)
+	for _, problem := range problems {
+		_, _ = fmt.Fprintln(out, problem)
+	}
+	_, _ = fmt.Fprintf(out, "Validated %d classes: %d problems found\n", classes, len(problems))
+	return len(problems)
+}
+
+// loads and checks the main class and the classes it refers to, transitively.
+// Returns the number of classes checked and the problems found, in order of class.
+func validateClosure(mainClass string) (int, []string) {
+	// the loader reports a class that can't be loaded by throwing an exception, which
+	// would end the program, so the exceptions are collected instead
+	glob := globals.GetGlobalRef()
+	var thrown []string
+	savedThrow := glob.FuncThrowException
+	glob.FuncThrowException = func(which int, msg string) bool {
+		thrown = append(thrown, excNames.JVMexceptionNames[which]+": "+msg)
+		return false
+	}
+	defer func() { glob.FuncThrowException = savedThrow }()
+
+	var problems []string
+	seen := map[string]bool{mainClass: true}
+	queue := []string{mainClass}
+	for len(queue) > 0 {
+		className := queue[0]
+		queue = queue[1:]
+
+		k := classloader.MethAreaFetch(className)
+		if k == nil {
+			thrown = nil
+			err := classloader.LoadClassFromNameOnly(className)
+			if k = classloader.MethAreaFetch(className); err != nil || k == nil || k.Data == nil {
+				reason := "it cannot be found"
+				switch {
+				case len(thrown) > 0:
+					reason = strings.Join(thrown, "; ")
+				case err != nil:
+					reason = err.Error()
+				}
+				problems = append(problems, fmt.Sprintf("%s cannot be loaded: %s", className, reason))
+				continue
+			}
+		}
+		if util.IsFilePartOfJDK(&className) {
+			continue // JDK classes are loaded, but their references are not followed
+		}
+
+		classProblems, references := validateClass(className, k)
+		problems = append(problems, classProblems...)
+		for _, ref := range references {
+			if !seen[ref] {
+				seen[ref] = true
+				queue = append(queue, ref)
+			}
+		}
+	}
+	return len(seen), problems
+}
+
+// verifies the code of a loaded class's methods and checks the methods they call for
+// trapped gfunctions. Returns the problems found and the classes the class refers to.
+func validateClass(className string, k *classloader.Klass) (problems []string, references []string) {
+	cp := &k.Data.CP
+
+	// the classes the class refers to, taking the element class of an array class
+	for i, entry := range cp.CpIndex {
+		if entry.Type != classloader.ClassRef {
+			continue
+		}
+		name := classloader.GetClassNameFromCPclassref(cp, uint16(i))
+		if strings.HasPrefix(name, "[") {
+			name = strings.TrimLeft(name, "[")
+			if !strings.HasPrefix(name, "L") || !strings.HasSuffix(name, ";") {
+				continue // an array of primitives
+			}
+			name = name[1 : len(name)-1]
+		}
+		if name != "" {
+			references = append(references, name)
+		}
+	}
+
+	// walk the methods in a fixed order, so the report is the same on every run
+	methKeys := make([]string, 0, len(k.Data.MethodTable))
+	for key := range k.Data.MethodTable {
+		methKeys = append(methKeys, key)
+	}
+	sort.Strings(methKeys)
+
+	reported := make(map[string]bool)
+	for _, methKey := range methKeys {
+		m := k.Data.MethodTable[methKey]
+		code := m.CodeAttr.Code
+		if len(code) == 0 { // abstract and native methods
+			continue
+		}
+		if err := classloader.CheckCodeValidity(&code, cp, m.CodeAttr.MaxStack, k.Data.Access); err != nil {
+			problems = append(problems, fmt.Sprintf("%s.%s fails verification: %s", className, methKey, err.Error()))
+			continue
+		}
+
+		for pc := 0; pc < len(code); {
+			length := classloader.BytecodeLength(code, pc)
+			if length == 0 {
+				break
+			}
+			switch code[pc] {
+			case opcodes.INVOKEVIRTUAL, opcodes.INVOKESPECIAL, opcodes.INVOKESTATIC, opcodes.INVOKEINTERFACE:
+				fqn := invokedMethodFQN(cp, int(code[pc+1])<<8|int(code[pc+2]))
+				if fqn != "" && !reported[fqn] && gfunction.IsTrapped(fqn) {
+					reported[fqn] = true
+					problems = append(problems, fmt.Sprintf("%s.%s calls %s, which Jacobin does not support",
+						className, methKey, fqn))
+				}
+			}
+			pc += length
+		}
+	}
+	return problems, references
+}
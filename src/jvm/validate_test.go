@@ -0,0 +1,94 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"bytes"
+	"jacobin/src/classloader"
+	"jacobin/src/gfunction"
+	"jacobin/src/globals"
+	"jacobin/src/opcodes"
+	"jacobin/src/stringPool"
+	"jacobin/src/trace"
+	"strings"
+	"testing"
+)
+
+// puts a class in the method area whose constant pool refers to the given classes,
+// and whose main() has the given code
+func loadValidateTestClass(className string, refs []string, code []byte) {
+	cp := classloader.CPool{CpIndex: []classloader.CpEntry{{Type: 0, Slot: 0}}} // the mandatory dummy entry
+	for i, ref := range refs {
+		cp.CpIndex = append(cp.CpIndex, classloader.CpEntry{Type: classloader.ClassRef, Slot: uint16(i)})
+		cp.ClassRefs = append(cp.ClassRefs, stringPool.GetStringIndex(&ref))
+	}
+	classloader.MethAreaInsert(className, &classloader.Klass{Status: 'F', Data: &classloader.ClData{
+		Name: className,
+		CP:   cp,
+		MethodTable: map[string]*classloader.Method{
+			"main([Ljava/lang/String;)V": {CodeAttr: classloader.CodeAttrib{Code: code, MaxStack: 2}},
+		},
+	}})
+}
+
+func TestValidateClosure(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	gfunction.Load_Traps()
+
+	// the main class refers to a helper, an array of itself, an array of ints, and a
+	// class that doesn't exist; the helper calls a trapped method
+	loadValidateTestClass("validate/Main",
+		[]string{"validate/Helper", "[Lvalidate/Main;", "[I", "validate/Missing"},
+		[]byte{opcodes.RETURN})
+	loadLintTestClass("validate/Helper")
+
+	classes, problems := validateClosure("validate/Main")
+	if classes != 3 {
+		t.Errorf("Expected 3 classes to be checked (Main, Helper, and Missing), got %d", classes)
+	}
+	if len(problems) != 2 {
+		t.Fatalf("Expected 2 problems, got %d: %v", len(problems), problems)
+	}
+	if !strings.HasPrefix(problems[0], "validate/Helper.helper()V calls java/rmi/RMISecurityManager.<init>()V") {
+		t.Errorf("Expected the trapped call to be reported, got: %s", problems[0])
+	}
+	if !strings.HasPrefix(problems[1], "validate/Missing cannot be loaded: ") {
+		t.Errorf("Expected the missing class to be reported, got: %s", problems[1])
+	}
+}
+
+func TestValidateProgramReportsVerificationFailures(t *testing.T) {
+	globals.InitGlobals("test")
+	trace.Init()
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+
+	loadValidateTestClass("validate/Bad", nil, []byte{opcodes.ICONST_0, 0xCB}) // an undefined opcode
+
+	var out bytes.Buffer
+	if count := validateProgram("validate/Bad", &out); count != 1 {
+		t.Fatalf("Expected 1 problem, got %d:\n%s", count, out.String())
+	}
+	report := out.String()
+	if !strings.Contains(report, "validate/Bad.main([Ljava/lang/String;)V fails verification") ||
+		!strings.HasSuffix(report, "Validated 1 classes: 1 problems found\n") {
+		t.Errorf("Unexpected report:\n%s", report)
+	}
+}
+
+func TestValidateOnlyOption(t *testing.T) {
+	global := globals.InitGlobals("test")
+	for _, option := range []string{"--dry-run", "--validate-only"} {
+		global.ValidateOnly = false
+		if _, err := validateOnly(0, option, &global); err != nil || !global.ValidateOnly {
+			t.Errorf("Expected %s to select the validation mode, got error: %v", option, err)
+		}
+	}
+}
@@ -151,6 +151,47 @@ func TestMake2DimByteArray(t *testing.T) {
 	}
 }
 
+func TestMakeMultiDimArray(t *testing.T) {
+	globals.InitGlobals("test")
+
+	// new int[2][3][4]: every array is typed with its own descriptor
+	arr := MakeMultiDimArray("[[[I", []int64{2, 3, 4})
+	if got := *(stringPool.GetStringPointer(arr.KlassName)); got != "[[[I" {
+		t.Errorf("Expecting class [[[I, got %s", got)
+	}
+	middle := arr.FieldTable["value"].Fvalue.([]*Object)[1]
+	if middle.FieldTable["value"].Ftype != "[[I" || ArrayLength(middle) != 3 {
+		t.Errorf("Expecting a [[I array of 3, got %s of %d", middle.FieldTable["value"].Ftype, ArrayLength(middle))
+	}
+	leaf := middle.FieldTable["value"].Fvalue.([]*Object)[2]
+	if len(leaf.FieldTable["value"].Fvalue.([]int64)) != 4 {
+		t.Errorf("Expecting a leaf array of 4 ints")
+	}
+
+	// new String[2][]: the arrays of the dimension not created are null
+	strs := MakeMultiDimArray("[[Ljava/lang/String;", []int64{2})
+	for _, elem := range strs.FieldTable["value"].Fvalue.([]*Object) {
+		if elem != nil {
+			t.Errorf("Expecting null elements, got %v", elem)
+		}
+	}
+
+	// new String[2][0][5]: a dimension of size 0 ends the arrays created
+	strs = MakeMultiDimArray("[[[Ljava/lang/String;", []int64{2, 0, 5})
+	inner := strs.FieldTable["value"].Fvalue.([]*Object)[0]
+	if *(stringPool.GetStringPointer(inner.KlassName)) != "[[Ljava/lang/String;" || ArrayLength(inner) != 0 {
+		t.Errorf("Expecting an empty [[Ljava/lang/String; array, got %s of %d",
+			*(stringPool.GetStringPointer(inner.KlassName)), ArrayLength(inner))
+	}
+
+	// the leaf arrays of references
+	strs = MakeMultiDimArray("[[Ljava/lang/String;", []int64{1, 2})
+	leaf = strs.FieldTable["value"].Fvalue.([]*Object)[0]
+	if got := *(stringPool.GetStringPointer(leaf.KlassName)); got != "[Ljava/lang/String;" {
+		t.Errorf("Expecting class [Ljava/lang/String;, got %s", got)
+	}
+}
+
 func TestMakeArrayFromRawArray(t *testing.T) {
 	globals.InitGlobals("test")
 	rawArray := make([]types.JavaByte, 10)
@@ -243,3 +284,24 @@ func TestArrayLength(t *testing.T) {
 		t.Errorf("Expecting 256 elements in ref array, got %d", length)
 	}
 }
+
+func TestCopyArray(t *testing.T) {
+	globals.InitGlobals("test")
+
+	arr := Make1DimRefArray("Ljava/lang/String;", 2)
+	str := StringObjectFromGoString("hello")
+	arr.FieldTable["value"].Fvalue.([]*Object)[0] = str
+
+	copied := CopyArray(arr)
+	if copied == nil || copied.KlassName != arr.KlassName {
+		t.Fatalf("Expected a copy of the array with the same type, got: %v", copied)
+	}
+	elements := copied.FieldTable["value"].Fvalue.([]*Object)
+	if len(elements) != 2 || elements[0] != str {
+		t.Errorf("Expected a shallow copy holding the same elements, got: %v", elements)
+	}
+
+	if CopyArray(MakeEmptyObject()) != nil {
+		t.Error("Expected nil when copying an object that is not an array")
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"jacobin/src/stringPool"
 	"jacobin/src/types"
 	"reflect"
+	"strings"
 )
 
 /*  This file contains some data structures and some primitive
@@ -115,6 +116,51 @@ func Make2DimArray(ptrArrSize, leafArrSize int64, arrType uint8) (*Object, error
 	return ptrArr, nil
 }
 
+// MakeMultiDimArray creates a multidimensional array, as MULTIANEWARRAY does. arrayType
+// is the descriptor of the array, such as "[[[I" or "[[Ljava/lang/String;", and dimSizes
+// holds the sizes of the dimensions to create, outermost first. There may be fewer sizes
+// than the array has dimensions, as in new int[3][][], in which case the innermost arrays
+// created hold nulls. A dimension of size 0 yields empty arrays, so the dimensions after it
+// are not created. Every array is typed with its own descriptor, so that its class and
+// component type are correct; the arrays of primitives are made as Make1DimArray() makes them.
+func MakeMultiDimArray(arrayType string, dimSizes []int64) *Object {
+	componentType := arrayType[1:]
+	if !strings.HasPrefix(componentType, types.Array) {
+		if strings.HasPrefix(componentType, types.Ref) {
+			return Make1DimRefArray(componentType[1:], dimSizes[0])
+		}
+		return Make1DimArray(ArrayKind(componentType), dimSizes[0])
+	}
+
+	o := MakeEmptyObject()
+	elements := make([]*Object, dimSizes[0])
+	if len(dimSizes) > 1 {
+		for i := range elements {
+			elements[i] = MakeMultiDimArray(componentType, dimSizes[1:])
+		}
+	}
+	of := Field{Ftype: arrayType, Fvalue: elements}
+	o.FieldTable["value"] = of
+	o.KlassName = stringPool.GetStringIndex(&of.Ftype)
+	recordArrayElements(REF, dimSizes[0])
+	return o
+}
+
+// ArrayKind returns the Jacobin array type (BYTE, FLOAT, INT, or REF) of the arrays whose
+// elements have the descriptor passed, such as "I" or "Ljava/lang/String;"
+func ArrayKind(elementType string) uint8 {
+	switch elementType[0] {
+	case 'B', 'Z':
+		return BYTE
+	case 'F', 'D':
+		return FLOAT
+	case 'L', '[':
+		return REF
+	default:
+		return INT
+	}
+}
+
 // Make1DimArray creates and 1-dimensional Jacobin-style array
 // of the specified type (passed as a byte) and size.
 func Make1DimArray(arrType uint8, size int64) *Object {
@@ -143,6 +189,7 @@ func Make1DimArray(arrType uint8, size int64) *Object {
 	}
 	value := o.FieldTable["value"]
 	o.KlassName = stringPool.GetStringIndex(&value.Ftype) // in arrays, Klass field is a pointer to the array type string
+	recordArrayElements(arrType, size)
 	return o
 }
 
@@ -157,6 +204,7 @@ func Make1DimRefArray(objType string, size int64) *Object {
 	o.FieldTable["value"] = of
 	o.KlassName = stringPool.GetStringIndex(&of.Ftype)
 	// o.Klass = &of.Ftype
+	recordArrayElements(REF, size)
 	return o
 }
 
@@ -246,3 +294,45 @@ func ArrayLength(arrayRef *Object) int64 {
 	}
 	return size
 }
+
+// CopyArray makes a shallow copy of an array object, as done by clone() on Java arrays:
+// the copy has the same type and length, and holds the same values (for reference
+// arrays, pointers to the same objects) as the original. Returns nil if the object
+// is not an array.
+func CopyArray(arrayRef *Object) *Object {
+	o, ok := arrayRef.FieldTable["value"]
+	if !ok || !strings.HasPrefix(o.Ftype, types.Array) {
+		return nil
+	}
+
+	var newValue any
+	switch array := o.Fvalue.(type) {
+	case []types.JavaByte:
+		newArray := make([]types.JavaByte, len(array))
+		copy(newArray, array)
+		newValue = newArray
+	case []byte:
+		newArray := make([]byte, len(array))
+		copy(newArray, array)
+		newValue = newArray
+	case []int64:
+		newArray := make([]int64, len(array))
+		copy(newArray, array)
+		newValue = newArray
+	case []float64:
+		newArray := make([]float64, len(array))
+		copy(newArray, array)
+		newValue = newArray
+	case []*Object:
+		newArray := make([]*Object, len(array))
+		copy(newArray, array)
+		newValue = newArray
+	default:
+		return nil
+	}
+
+	newObj := MakeEmptyObject()
+	newObj.KlassName = arrayRef.KlassName
+	newObj.FieldTable["value"] = Field{Ftype: o.Ftype, Fvalue: newValue}
+	return newObj
+}
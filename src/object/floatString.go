@@ -0,0 +1,79 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package object
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The string representations of doubles and floats, as produced by Double.toString() and
+// Float.toString() in Java. These use the shortest decimal that uniquely distinguishes the
+// value from its neighbors (as Go does), but unlike Go, they write NaN and the infinities
+// as Java does, always have at least one digit after the decimal point, and use Java's
+// computerized scientific notation (1.0E10, 1.5E-5) for magnitudes below 10^-3 or at or
+// above 10^7.
+
+// JavaDoubleString returns the string that Java's Double.toString() returns for d
+func JavaDoubleString(d float64) string {
+	return javaFloatingString(d, 64)
+}
+
+// JavaFloatString returns the string that Java's Float.toString() returns for f. Floats
+// are held in float64s in Jacobin, so f is rounded to a float32 first.
+func JavaFloatString(f float64) string {
+	return javaFloatingString(float64(float32(f)), 32)
+}
+
+// formats the value, which is of the given bit size, as Java does
+func javaFloatingString(value float64, bitSize int) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "Infinity"
+	case math.IsInf(value, -1):
+		return "-Infinity"
+	case value == 0:
+		if math.Signbit(value) {
+			return "-0.0"
+		}
+		return "0.0"
+	}
+
+	// the shortest digits, as d.dddde±xx, give the digits and the exponent. When one digit
+	// is enough, Java uses the 2-digit decimal closest to the value, as in 4.9E-324.
+	sci := strconv.FormatFloat(value, 'e', -1, bitSize)
+	if !strings.Contains(sci, ".") {
+		sci = strconv.FormatFloat(value, 'e', 1, bitSize)
+	}
+	sign := ""
+	if sci[0] == '-' {
+		sign = "-"
+		sci = sci[1:]
+	}
+	mantissa, expStr, _ := strings.Cut(sci, "e")
+	exp, _ := strconv.Atoi(expStr)
+	digits := strings.TrimRight(strings.Replace(mantissa, ".", "", 1), "0")
+
+	if exp < -3 || exp >= 7 { // computerized scientific notation
+		fraction := digits[1:]
+		if fraction == "" {
+			fraction = "0"
+		}
+		return sign + digits[:1] + "." + fraction + "E" + strconv.Itoa(exp)
+	}
+
+	if exp < 0 {
+		return sign + "0." + strings.Repeat("0", -exp-1) + digits
+	}
+	if len(digits) <= exp+1 {
+		return sign + digits + strings.Repeat("0", exp+1-len(digits)) + ".0"
+	}
+	return sign + digits[:exp+1] + "." + digits[exp+1:]
+}
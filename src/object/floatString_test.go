@@ -0,0 +1,69 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package object
+
+import (
+	"math"
+	"testing"
+)
+
+func TestJavaDoubleString(t *testing.T) {
+	tenth, fifth := 0.1, 0.2
+	tests := []struct {
+		value    float64
+		expected string
+	}{
+		{0, "0.0"},
+		{math.Copysign(0, -1), "-0.0"},
+		{1, "1.0"},
+		{-1.5, "-1.5"},
+		{100, "100.0"},
+		{3.14, "3.14"},
+		{0.1, "0.1"},
+		{0.001, "0.001"},
+		{0.0001, "1.0E-4"},
+		{1.25e-5, "1.25E-5"},
+		{1234567, "1234567.0"},
+		{9999999.5, "9999999.5"},
+		{1e7, "1.0E7"},
+		{12345678.9, "1.23456789E7"},
+		{1e100, "1.0E100"},
+		{math.MaxFloat64, "1.7976931348623157E308"},
+		{math.SmallestNonzeroFloat64, "4.9E-324"},
+		{tenth + fifth, "0.30000000000000004"},
+		{math.NaN(), "NaN"},
+		{math.Inf(1), "Infinity"},
+		{math.Inf(-1), "-Infinity"},
+	}
+	for _, test := range tests {
+		if str := JavaDoubleString(test.value); str != test.expected {
+			t.Errorf("JavaDoubleString(%v): expected %s, got: %s", test.value, test.expected, str)
+		}
+	}
+}
+
+func TestJavaFloatString(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected string
+	}{
+		{float64(float32(0.1)), "0.1"},
+		{float64(float32(3.14)), "3.14"},
+		{1.0 / 3.0, "0.33333334"},
+		{1e10, "1.0E10"},
+		{float64(math.MaxFloat32), "3.4028235E38"},
+		{float64(float32(1e-5)), "1.0E-5"},
+		{math.Copysign(0, -1), "-0.0"},
+		{math.NaN(), "NaN"},
+		{math.Inf(-1), "-Infinity"},
+	}
+	for _, test := range tests {
+		if str := JavaFloatString(test.value); str != test.expected {
+			t.Errorf("JavaFloatString(%v): expected %s, got: %s", test.value, test.expected, str)
+		}
+	}
+}
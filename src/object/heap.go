@@ -0,0 +1,218 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package object
+
+import (
+	"errors"
+	"fmt"
+	"jacobin/src/globals"
+	"jacobin/src/trace"
+	"math"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"runtime/pprof"
+	"sync/atomic"
+)
+
+// Heap accounting. Jacobin's objects live on the Go heap and are freed by the Go
+// garbage collector, so Jacobin does not know exactly how much memory Java objects
+// use at any moment. Instead, it keeps two approximations:
+//
+//   - a running count of the objects and bytes allocated by the functions in this
+//     package, which tells us how much allocation a program does (see HeapUsage())
+//   - the size of the live Go heap, less the size it had when the program's main()
+//     was about to begin (see SetHeapBaseline()), which approximates the live Java heap
+//
+// When -Xmx is specified, allocations are checked against the second figure by
+// CheckHeapSpace(), so that an exhausted heap produces an OutOfMemoryError, which
+// Java code can catch, rather than an ever-growing process that the OS eventually
+// kills. The limit is also given to the Go runtime as its soft memory limit, so
+// that the garbage collector works harder as the heap approaches the limit.
+
+// ErrHeapExhausted is returned by CheckHeapSpace when an allocation would exceed
+// the heap limit. The caller should throw an OutOfMemoryError.
+var ErrHeapExhausted = errors.New("Java heap space")
+
+// approximate sizes of the data structures of objects and arrays
+const (
+	objectHeaderBytes = 48 // an Object struct and its empty field table
+	fieldBytes        = 64 // an entry in the field table, including its name and value
+	refBytes          = 8  // an element of a reference array
+	wordBytes         = 8  // an element of an int64 or float64 array
+)
+
+// an allocation of at least 1/heapCheckFraction of the heap limit, or that much
+// allocation since the last check, causes the heap to be measured again
+const heapCheckFraction = 64
+
+var heapStats struct {
+	objects    atomic.Int64 // objects and arrays allocated since start-up
+	bytes      atomic.Int64 // approximate bytes allocated since start-up
+	sinceCheck atomic.Int64 // bytes allocated since the heap was last measured
+	baseline   atomic.Int64 // size of the live Go heap when main() began
+	dumped     atomic.Bool  // whether a heap dump has already been written
+}
+
+// recordAllocation adds an allocation of the given size to the heap statistics
+func recordAllocation(bytes int64) {
+	heapStats.objects.Add(1)
+	heapStats.bytes.Add(bytes)
+}
+
+// adds the elements of a newly allocated array to the heap statistics. (The array
+// object itself is recorded when it is created.)
+func recordArrayElements(arrType uint8, length int64) {
+	heapStats.bytes.Add(ArrayBytes(arrType, length) - objectHeaderBytes)
+}
+
+// HeapUsage returns the number of objects (including arrays) allocated since
+// start-up, and the approximate number of bytes allocated for them
+func HeapUsage() (objects int64, bytes int64) {
+	return heapStats.objects.Load(), heapStats.bytes.Load()
+}
+
+// ObjectBytes returns the approximate size of an object with the given number of fields
+func ObjectBytes(fieldCount int) int64 {
+	return objectHeaderBytes + int64(fieldCount)*fieldBytes
+}
+
+// ArrayBytes returns the approximate size of an array of the given Jacobin array
+// type (BYTE, INT, FLOAT, REF) and length. An array too large to allocate returns
+// math.MaxInt64.
+func ArrayBytes(arrType uint8, length int64) int64 {
+	elementBytes := int64(wordBytes)
+	switch arrType {
+	case BYTE:
+		elementBytes = 1
+	case REF:
+		elementBytes = refBytes
+	}
+	if length < 0 || length > (math.MaxInt64-objectHeaderBytes-fieldBytes)/elementBytes {
+		return math.MaxInt64
+	}
+	return ObjectBytes(1) + length*elementBytes
+}
+
+// MultiArrayBytes returns the approximate size of a multidimensional array with the
+// given dimensions, the first being the outermost, whose innermost arrays are of the
+// given Jacobin array type. An array too large to allocate returns math.MaxInt64.
+func MultiArrayBytes(leafType uint8, dimSizes []int64) int64 {
+	total := int64(0)
+	arrayCount := int64(1) // the number of arrays in the present dimension
+	for i, size := range dimSizes {
+		arrType := uint8(REF)
+		if i == len(dimSizes)-1 {
+			arrType = leafType
+		}
+		each := ArrayBytes(arrType, size)
+		if each == math.MaxInt64 || arrayCount > (math.MaxInt64-total)/each {
+			return math.MaxInt64
+		}
+		total += arrayCount * each
+		if size > 0 && arrayCount > math.MaxInt64/size {
+			return math.MaxInt64
+		}
+		arrayCount *= size
+	}
+	return total
+}
+
+// SetHeapBaseline records the present size of the live Go heap, which consists of
+// Jacobin's own data, as the baseline from which the size of the Java heap is
+// measured. It's called just before the program's main() begins. If there is a heap
+// limit, it also sets the Go runtime's soft memory limit accordingly.
+func SetHeapBaseline() {
+	limit := globals.GetGlobalRef().MaxHeapSize
+	if limit <= 0 {
+		return
+	}
+	runtime.GC()
+	baseline := liveGoHeapBytes()
+	heapStats.baseline.Store(baseline)
+	if baseline <= math.MaxInt64-limit {
+		debug.SetMemoryLimit(baseline + limit)
+	}
+}
+
+// JavaHeapBytes returns the approximate size of the live Java heap
+func JavaHeapBytes() int64 {
+	live := liveGoHeapBytes() - heapStats.baseline.Load()
+	if live < 0 {
+		return 0
+	}
+	return live
+}
+
+// reads the size of the live Go heap from the runtime, including objects that
+// are no longer referenced but have not yet been collected
+func liveGoHeapBytes() int64 {
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return int64(sample[0].Value.Uint64())
+}
+
+// CheckHeapSpace checks that an allocation of the given number of bytes leaves the
+// Java heap within the limit set by -Xmx, if any. To keep the check inexpensive, the
+// heap is measured only after a sizable amount of allocation. If the heap appears to
+// be full, a garbage collection is run and the heap measured again before
+// ErrHeapExhausted is returned.
+func CheckHeapSpace(bytes int64) error {
+	limit := globals.GetGlobalRef().MaxHeapSize
+	if limit <= 0 {
+		return nil
+	}
+	if bytes > limit {
+		return ErrHeapExhausted
+	}
+
+	threshold := limit / heapCheckFraction
+	if heapStats.sinceCheck.Add(bytes) < threshold && bytes < threshold {
+		return nil
+	}
+	heapStats.sinceCheck.Store(0)
+
+	if JavaHeapBytes() <= limit-bytes {
+		return nil
+	}
+	runtime.GC() // much of the heap might be garbage
+	if JavaHeapBytes() <= limit-bytes {
+		return nil
+	}
+	return ErrHeapExhausted
+}
+
+// DumpHeapOnce writes a profile of the Go heap to jacobin_pid<pid>.pprof in the
+// current directory, if -XX:+HeapDumpOnOutOfMemoryError was specified and no heap
+// dump has yet been written. As in HotSpot, only the first OutOfMemoryError causes
+// a dump. The profile can be examined with 'go tool pprof'.
+func DumpHeapOnce() {
+	if !globals.GetGlobalRef().HeapDumpOnOOM || !heapStats.dumped.CompareAndSwap(false, true) {
+		return
+	}
+
+	fileName := fmt.Sprintf("jacobin_pid%d.pprof", os.Getpid())
+	_, _ = fmt.Fprintf(os.Stderr, "Dumping heap to %s ...\n", fileName)
+	file, err := os.Create(fileName)
+	if err != nil {
+		trace.Error("DumpHeapOnce: could not create heap dump file: " + err.Error())
+		return
+	}
+	defer file.Close()
+
+	if err = pprof.WriteHeapProfile(file); err != nil {
+		trace.Error("DumpHeapOnce: could not write heap dump: " + err.Error())
+		return
+	}
+	objects, bytes := HeapUsage()
+	_, _ = fmt.Fprintf(os.Stderr, "Heap dump file created. Java heap: about %d bytes live; "+
+		"%d objects (%d bytes) allocated since start-up\n", JavaHeapBytes(), objects, bytes)
+}
@@ -0,0 +1,121 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package object
+
+import (
+	"errors"
+	"jacobin/src/globals"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"testing"
+)
+
+func TestArrayBytes(t *testing.T) {
+	if ArrayBytes(BYTE, 1000) != ObjectBytes(1)+1000 {
+		t.Errorf("Unexpected size of a byte array: %d", ArrayBytes(BYTE, 1000))
+	}
+	if ArrayBytes(INT, 1000) != ObjectBytes(1)+8000 || ArrayBytes(REF, 1000) != ObjectBytes(1)+8000 {
+		t.Error("Expected int and reference arrays to take 8 bytes per element")
+	}
+	if ArrayBytes(INT, math.MaxInt64/2) != math.MaxInt64 {
+		t.Error("Expected an impossibly large array to have size MaxInt64")
+	}
+
+	// int[3][4]: one array of 3 references and three arrays of 4 ints
+	expected := ArrayBytes(REF, 3) + 3*ArrayBytes(INT, 4)
+	if size := MultiArrayBytes(INT, []int64{3, 4}); size != expected {
+		t.Errorf("Expected int[3][4] to take %d bytes, got %d", expected, size)
+	}
+	if MultiArrayBytes(INT, []int64{1 << 40, 1 << 40}) != math.MaxInt64 {
+		t.Error("Expected an impossibly large multidimensional array to have size MaxInt64")
+	}
+}
+
+func TestHeapUsageCountsAllocations(t *testing.T) {
+	globals.InitGlobals("test")
+	objects, bytes := HeapUsage()
+	MakeEmptyObject()
+	Make1DimArray(BYTE, 100)
+	newObjects, newBytes := HeapUsage()
+	if newObjects-objects != 2 {
+		t.Errorf("Expected 2 more objects, got %d", newObjects-objects)
+	}
+	if newBytes-bytes != objectHeaderBytes+ArrayBytes(BYTE, 100) {
+		t.Errorf("Expected %d more bytes, got %d", objectHeaderBytes+ArrayBytes(BYTE, 100), newBytes-bytes)
+	}
+}
+
+func TestCheckHeapSpace(t *testing.T) {
+	globals.InitGlobals("test")
+	defer debug.SetMemoryLimit(math.MaxInt64)
+	if CheckHeapSpace(1<<40) != nil {
+		t.Error("Expected no limit on the heap unless -Xmx is specified")
+	}
+
+	glob := globals.GetGlobalRef()
+	glob.MaxHeapSize = 8 * 1024 * 1024
+	SetHeapBaseline()
+
+	if err := CheckHeapSpace(16 * 1024 * 1024); !errors.Is(err, ErrHeapExhausted) {
+		t.Errorf("Expected an allocation larger than the heap to fail, got: %v", err)
+	}
+	if err := CheckHeapSpace(1024 * 1024); err != nil {
+		t.Errorf("Expected an allocation within the limit to succeed, got: %v", err)
+	}
+
+	// fill most of the heap, so that the next large allocation doesn't fit
+	retained := make([]byte, 6*1024*1024)
+	for i := range retained {
+		retained[i] = 1
+	}
+	if err := CheckHeapSpace(4 * 1024 * 1024); !errors.Is(err, ErrHeapExhausted) {
+		t.Errorf("Expected an allocation beyond the limit to fail, got: %v", err)
+	}
+	runtime.KeepAlive(retained)
+
+	// once the memory is garbage, the allocation fits
+	retained = nil
+	if err := CheckHeapSpace(4 * 1024 * 1024); err != nil {
+		t.Errorf("Expected the allocation to succeed after garbage collection, got: %v", err)
+	}
+}
+
+func TestDumpHeapOnce(t *testing.T) {
+	globals.InitGlobals("test")
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	_ = os.Chdir(dir)
+	defer func() { _ = os.Chdir(wd) }()
+
+	// suppress the messages about the dump
+	normalStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stderr = w
+	defer func() { _ = w.Close(); os.Stderr = normalStderr }()
+
+	heapStats.dumped.Store(false)
+	DumpHeapOnce()
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.pprof")); len(files) != 0 {
+		t.Error("Expected no heap dump without -XX:+HeapDumpOnOutOfMemoryError")
+	}
+
+	globals.GetGlobalRef().HeapDumpOnOOM = true
+	DumpHeapOnce()
+	files, _ := filepath.Glob(filepath.Join(dir, "jacobin_pid*.pprof"))
+	if len(files) != 1 {
+		t.Fatalf("Expected a heap dump file, got: %v", files)
+	}
+
+	_ = os.Remove(files[0])
+	DumpHeapOnce()
+	if files, _ = filepath.Glob(filepath.Join(dir, "*.pprof")); len(files) != 0 {
+		t.Error("Expected the heap to be dumped only once")
+	}
+}
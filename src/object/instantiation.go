@@ -0,0 +1,73 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package object
+
+import (
+	"jacobin/src/types"
+	"sync"
+)
+
+// Some classes are implemented by gfunctions that expect their objects to have a
+// representation of their own, rather than the fields declared in the class file:
+// a String holds its characters in a JavaByte array in its value field, an Integer
+// holds an int64 in its value field, a HashMap holds a Go map, and so on. An
+// instantiation hook constructs a new, uninitialized object of such a class, and
+// the new bytecode (via jvm.InstantiateClass) calls it in place of allocating the
+// fields of the class. The object is then set up by the class's <init>() as usual.
+
+// InstantiationHook returns a new object of the named class in its native representation
+type InstantiationHook func(className string) *Object
+
+var instantiationHooks = struct {
+	sync.RWMutex
+	hooks map[string]InstantiationHook
+}{hooks: make(map[string]InstantiationHook)}
+
+// RegisterInstantiationHook makes hook the means of creating new objects of the named
+// class, replacing any hook previously registered for the class. A nil hook removes
+// the registration, so that the class's objects are again created from its fields.
+func RegisterInstantiationHook(className string, hook InstantiationHook) {
+	instantiationHooks.Lock()
+	defer instantiationHooks.Unlock()
+	if hook == nil {
+		delete(instantiationHooks.hooks, className)
+		return
+	}
+	instantiationHooks.hooks[className] = hook
+}
+
+// InstantiationHookFor returns the instantiation hook registered for the named class,
+// if there is one
+func InstantiationHookFor(className string) (InstantiationHook, bool) {
+	instantiationHooks.RLock()
+	defer instantiationHooks.RUnlock()
+	hook, ok := instantiationHooks.hooks[className]
+	return hook, ok
+}
+
+// the hooks for String and the wrappers of the primitive types, whose representations
+// are defined in this package. Hooks for other classes, such as the collections, are
+// registered by the gfunctions that implement them.
+func init() {
+	RegisterInstantiationHook(types.StringClassName, func(string) *Object { return NewStringObject() })
+
+	wrappers := map[string]Field{
+		"java/lang/Boolean":   {Ftype: types.Bool, Fvalue: types.JavaBoolFalse},
+		"java/lang/Byte":      {Ftype: types.Byte, Fvalue: int64(0)},
+		"java/lang/Character": {Ftype: types.Char, Fvalue: int64(0)},
+		"java/lang/Short":     {Ftype: types.Short, Fvalue: int64(0)},
+		"java/lang/Integer":   {Ftype: types.Int, Fvalue: int64(0)},
+		"java/lang/Long":      {Ftype: types.Long, Fvalue: int64(0)},
+		"java/lang/Float":     {Ftype: types.Float, Fvalue: float64(0)},
+		"java/lang/Double":    {Ftype: types.Double, Fvalue: float64(0)},
+	}
+	for className, value := range wrappers {
+		RegisterInstantiationHook(className, func(className string) *Object {
+			return MakePrimitiveObject(className, value.Ftype, value.Fvalue)
+		})
+	}
+}
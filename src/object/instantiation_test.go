@@ -0,0 +1,69 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package object
+
+import (
+	"jacobin/src/globals"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"testing"
+)
+
+func TestInstantiationHooksForStringAndWrappers(t *testing.T) {
+	globals.InitGlobals("test")
+
+	hook, ok := InstantiationHookFor(types.StringClassName)
+	if !ok {
+		t.Fatal("Expected an instantiation hook for String")
+	}
+	str := hook(types.StringClassName)
+	if str.KlassName != types.StringPoolStringIndex || str.FieldTable["value"].Ftype != types.StringClassRef {
+		t.Errorf("Expected an empty String object, got: %v", str)
+	}
+
+	hook, ok = InstantiationHookFor("java/lang/Integer")
+	if !ok {
+		t.Fatal("Expected an instantiation hook for Integer")
+	}
+	integer := hook("java/lang/Integer")
+	if *stringPool.GetStringPointer(integer.KlassName) != "java/lang/Integer" {
+		t.Errorf("Expected an Integer, got: %s", *stringPool.GetStringPointer(integer.KlassName))
+	}
+	if value := integer.FieldTable["value"]; value.Ftype != types.Int || value.Fvalue != int64(0) {
+		t.Errorf("Expected an int value of 0, got: %v", value)
+	}
+
+	hook, _ = InstantiationHookFor("java/lang/Double")
+	if value := hook("java/lang/Double").FieldTable["value"]; value.Ftype != types.Double || value.Fvalue != float64(0) {
+		t.Errorf("Expected a double value of 0, got: %v", value)
+	}
+
+	if _, ok = InstantiationHookFor("java/lang/Object"); ok {
+		t.Error("Did not expect an instantiation hook for Object")
+	}
+}
+
+func TestRegisterInstantiationHook(t *testing.T) {
+	globals.InitGlobals("test")
+
+	className := "pkg/Native"
+	RegisterInstantiationHook(className, func(name string) *Object {
+		return MakeOneFieldObject(name, "handle", types.Long, int64(42))
+	})
+	hook, ok := InstantiationHookFor(className)
+	if !ok {
+		t.Fatal("Expected the registered hook to be found")
+	}
+	if obj := hook(className); obj.FieldTable["handle"].Fvalue != int64(42) {
+		t.Errorf("Expected the object created by the hook, got: %v", obj)
+	}
+
+	RegisterInstantiationHook(className, nil)
+	if _, ok = InstantiationHookFor(className); ok {
+		t.Error("Expected a nil hook to remove the registration")
+	}
+}
@@ -24,8 +24,8 @@ func GoStringFromJavaByteArray(jbarr []types.JavaByte) string {
 
 func JavaByteArrayFromGoString(str string) []types.JavaByte {
 	jbarr := make([]types.JavaByte, len(str))
-	for i, b := range str {
-		jbarr[i] = types.JavaByte(b)
+	for i := 0; i < len(str); i++ {
+		jbarr[i] = types.JavaByte(str[i])
 	}
 	return jbarr
 }
@@ -0,0 +1,79 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package object
+
+import "jacobin/src/stringPool"
+
+// The allocation nursery. Loops that create a small object on every iteration, use
+// it, and drop it (a Point to hold a pair of coordinates, say) produce a great deal
+// of garbage, and an object's field table is its most expensive part to allocate.
+// When -XX:+UseAllocationNursery is specified, the interpreter looks for such objects:
+// ones that, by an analysis of the method's bytecode, never leave the local variable
+// they are stored in (see jvm/nursery.go). When such an object is overwritten by
+// the next one created at the same place, it's returned to the thread's nursery,
+// and the next object is made from it. The field table of a released object is
+// cleared but keeps its storage, so that the Field entries are reused as well.
+//
+// A nursery belongs to a single thread, like its frame pool (which holds it), so
+// it needs no locking.
+
+const maxNurseryObjects = 256 // the most objects a nursery holds
+
+// Nursery holds the objects of a thread that are available for reuse
+type Nursery struct {
+	free   []*Object
+	reused int64 // the number of objects allocated from the free list
+}
+
+// NewNursery creates an empty nursery, for use by a single thread
+func NewNursery() *Nursery {
+	return &Nursery{}
+}
+
+// Alloc returns an object of the named class with an empty field table, reusing
+// a released object if one is available. If n is nil, a new object is created.
+func (n *Nursery) Alloc(className *string) *Object {
+	if n == nil || len(n.free) == 0 {
+		return MakeEmptyObjectWithClassName(className)
+	}
+
+	last := len(n.free) - 1
+	obj := n.free[last]
+	n.free[last] = nil
+	n.free = n.free[:last]
+	n.reused++
+
+	obj.KlassName = stringPool.GetStringIndex(className)
+	return obj
+}
+
+// Release returns an object that is no longer referenced to the nursery for reuse.
+// The caller must be certain that nothing refers to the object any longer.
+func (n *Nursery) Release(obj *Object) {
+	if n == nil || obj == nil || len(n.free) >= maxNurseryObjects {
+		return
+	}
+	clear(obj.FieldTable) // so it doesn't keep objects alive
+	obj.Mark = MarkWord{} // a reused object is a new object, with a new identity hash
+	n.free = append(n.free, obj)
+}
+
+// Len returns the number of objects in the nursery, for diagnostics and testing
+func (n *Nursery) Len() int {
+	if n == nil {
+		return 0
+	}
+	return len(n.free)
+}
+
+// Reused returns the number of objects the nursery has supplied from released ones
+func (n *Nursery) Reused() int64 {
+	if n == nil {
+		return 0
+	}
+	return n.reused
+}
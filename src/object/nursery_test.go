@@ -0,0 +1,64 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package object
+
+import (
+	"jacobin/src/globals"
+	"jacobin/src/stringPool"
+	"jacobin/src/types"
+	"testing"
+)
+
+func TestNurseryReusesObjects(t *testing.T) {
+	globals.InitGlobals("test")
+	className := "pkg/Point"
+	n := NewNursery()
+
+	obj := n.Alloc(&className)
+	obj.FieldTable["x"] = Field{Ftype: types.Int, Fvalue: int64(3)}
+	obj.Mark.Hash = 1234
+	n.Release(obj)
+	if n.Len() != 1 {
+		t.Fatalf("Expected 1 object in the nursery, got %d", n.Len())
+	}
+
+	otherClass := "pkg/Other"
+	reused := n.Alloc(&otherClass)
+	if reused != obj || n.Reused() != 1 || n.Len() != 0 {
+		t.Fatalf("Expected the released object to be reused, got %p (reused: %d)", reused, n.Reused())
+	}
+	if len(reused.FieldTable) != 0 || reused.Mark.Hash != 0 {
+		t.Errorf("Expected the reused object to be reset, got: %+v", reused)
+	}
+	if *stringPool.GetStringPointer(reused.KlassName) != otherClass {
+		t.Errorf("Expected class %s, got %s", otherClass, *stringPool.GetStringPointer(reused.KlassName))
+	}
+}
+
+func TestNurseryIsBounded(t *testing.T) {
+	globals.InitGlobals("test")
+	n := NewNursery()
+	for i := 0; i < maxNurseryObjects+10; i++ {
+		n.Release(MakeEmptyObject())
+	}
+	if n.Len() != maxNurseryObjects {
+		t.Errorf("Expected the nursery to hold %d objects, got %d", maxNurseryObjects, n.Len())
+	}
+}
+
+func TestNilNursery(t *testing.T) {
+	globals.InitGlobals("test")
+	className := "pkg/Point"
+	var n *Nursery
+	if obj := n.Alloc(&className); obj == nil || obj.FieldTable == nil {
+		t.Errorf("Expected a new object from a nil nursery, got: %v", obj)
+	}
+	n.Release(MakeEmptyObject()) // must not panic
+	if n.Len() != 0 || n.Reused() != 0 {
+		t.Error("Expected a nil nursery to be empty")
+	}
+}
@@ -13,7 +13,7 @@ import (
 	"jacobin/src/types"
 	"path"
 	"strings"
-	"unsafe"
+	"sync/atomic"
 )
 
 // This file contains basic functions of object creation. (Array objects
@@ -30,14 +30,45 @@ type Object struct {
 }
 
 // These mark word contains values for different purposes. Here,
-// we use the first four bytes for a hash value, which is taken
-// from the address of the object. The 'misc' field will eventually
-// contain other values, such as locking and monitoring items.
+// we use the first four bytes for the object's identity hash code, which
+// is assigned the first time it's requested (see IdentityHashCode()).
+// The 'misc' field will eventually contain other values, such as locking
+// and monitoring items.
 type MarkWord struct {
-	Hash uint32 // contains hash code which is the lower 32 bits of the address
+	Hash uint32 // the identity hash code; 0 = not yet assigned
 	Misc uint32 // at present unused
 }
 
+// the state of the generator of identity hash codes
+var identityHashSeed uint64
+
+// IdentityHashCode returns the identity hash code of an object, which is the value
+// returned by System.identityHashCode() and by Object.hashCode() when it is not
+// overridden. The hash is assigned the first time it's requested and is then stored
+// in the object's header, so it's stable for the life of the object, regardless of
+// where the object resides in memory. Like HotSpot's, the hash is a non-zero 31-bit value.
+func IdentityHashCode(obj *Object) uint32 {
+	if hash := atomic.LoadUint32(&obj.Mark.Hash); hash != 0 {
+		return hash
+	}
+
+	// a splitmix64 step on a shared counter gives well-distributed values across threads
+	z := atomic.AddUint64(&identityHashSeed, 0x9E3779B97F4A7C15)
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	z ^= z >> 31
+	hash := uint32(z) & 0x7FFFFFFF
+	if hash == 0 {
+		hash = 1
+	}
+
+	// if another thread assigned the hash in the meantime, use its value
+	if !atomic.CompareAndSwapUint32(&obj.Mark.Hash, 0, hash) {
+		return atomic.LoadUint32(&obj.Mark.Hash)
+	}
+	return hash
+}
+
 // We need to know the type of the field only to tell whether
 // it occupies one or two slots on the stack when getfield and
 // putfield bytecodes are executed. The type also flags static
@@ -52,24 +83,22 @@ type Field struct {
 // code will fill in the Klass header field and the data fields.
 func MakeEmptyObject() *Object {
 	o := Object{}
-	h := uintptr(unsafe.Pointer(&o))
-	o.Mark.Hash = uint32(h)
 	o.KlassName = types.InvalidStringIndex // s/be filled in later, when class is filled in.
 
 	// initialize the map of this object's fields
 	o.FieldTable = make(map[string]Field)
+	recordAllocation(objectHeaderBytes) // see heap.go
 	return &o
 }
 
 // MakeEmptyObjectWithClassName() creates an empty Object using the passed-in class name
 func MakeEmptyObjectWithClassName(className *string) *Object {
 	o := Object{}
-	h := uintptr(unsafe.Pointer(&o))
-	o.Mark.Hash = uint32(h)
 	o.KlassName = stringPool.GetStringIndex(className)
 
 	// initialize the map of this object's fields
 	o.FieldTable = make(map[string]Field)
+	recordAllocation(objectHeaderBytes) // see heap.go
 	return &o
 }
 
@@ -87,7 +87,7 @@ func TestCloneObject_1(t *testing.T) {
 	}
 
 	// Make sure that their hashes are different.
-	if obj2.Mark.Hash == obj1.Mark.Hash {
+	if IdentityHashCode(obj2) == IdentityHashCode(obj1) {
 		t.Errorf("Mark.Hash should be different. obj1: %v, obj2: %v", obj1.Mark.Hash, obj2.Mark.Hash)
 	}
 
@@ -132,7 +132,7 @@ func TestCloneObject_2(t *testing.T) {
 	}
 
 	// Make sure that their hashes are different.
-	if obj2.Mark.Hash == obj1.Mark.Hash {
+	if IdentityHashCode(obj2) == IdentityHashCode(obj1) {
 		t.Errorf("Mark.Hash should be different. obj1: %v, obj2: %v", obj1.Mark.Hash, obj2.Mark.Hash)
 	}
 
@@ -193,8 +193,11 @@ func TestMakeEmptyObject(t *testing.T) {
 		t.Errorf("MakeEmptyObject() should not return nil")
 	}
 
-	// Verify hash is set and non-zero
-	if obj.Mark.Hash == 0 {
+	// Verify hash is not assigned until requested, and is then non-zero
+	if obj.Mark.Hash != 0 {
+		t.Errorf("Expected hash to be unassigned, got %d", obj.Mark.Hash)
+	}
+	if IdentityHashCode(obj) == 0 {
 		t.Errorf("Expected non-zero hash, got %d", obj.Mark.Hash)
 	}
 
@@ -213,7 +216,7 @@ func TestMakeEmptyObject(t *testing.T) {
 
 	// Test hash uniqueness with multiple objects
 	obj2 := MakeEmptyObject()
-	if obj.Mark.Hash == obj2.Mark.Hash {
+	if IdentityHashCode(obj) == IdentityHashCode(obj2) {
 		t.Errorf("Expected different hash values for different objects, both got %d", obj.Mark.Hash)
 	}
 }
@@ -625,7 +628,7 @@ func TestMakeEmptyObjectHashUniqueness(t *testing.T) {
 	// Create multiple objects and collect their hashes
 	for i := 0; i < numObjects; i++ {
 		objects[i] = MakeEmptyObject()
-		hash := objects[i].Mark.Hash
+		hash := IdentityHashCode(objects[i])
 
 		if hashes[hash] {
 			t.Errorf("Hash collision detected: hash %d appears multiple times", hash)
@@ -640,3 +643,17 @@ func TestMakeEmptyObjectHashUniqueness(t *testing.T) {
 }
 
 // === end of generated tests ===
+
+func TestIdentityHashCodeIsStable(t *testing.T) {
+	obj := MakeEmptyObject()
+	hash := IdentityHashCode(obj)
+	if hash == 0 || hash > 0x7FFFFFFF {
+		t.Errorf("Expected a non-zero 31-bit hash, got %d", hash)
+	}
+	if obj.Mark.Hash != hash {
+		t.Errorf("Expected the hash to be stored in the mark word, got %d", obj.Mark.Hash)
+	}
+	if IdentityHashCode(obj) != hash {
+		t.Error("Expected the identity hash code not to change between calls")
+	}
+}
@@ -0,0 +1,319 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+// Package replay records the nondeterministic inputs of a run of a program and replays
+// them, so that a failure that depends on them, such as a concurrency bug, can be
+// reproduced. With -record <file>, the inputs are logged to the file as the program
+// runs; with -replay <file>, the program gets the logged inputs in place of the live
+// ones. The inputs are:
+//
+//   - the values of the clocks (System.currentTimeMillis() and nanoTime()) and the
+//     random numbers that are not reproducible from a seed (the seed of new Random()
+//     and Math.random())
+//   - the results of reads from files and the console
+//   - the order in which threads pass the monitor operations: MONITORENTER and the
+//     acquisitions of locks, semaphores, and latches. When replaying, a thread that
+//     reaches a monitor operation out of turn waits until the threads recorded before
+//     it have passed theirs.
+//
+// Values are replayed in the order they were recorded, for each kind of input. If the
+// replayed program asks for more inputs than were recorded, or a thread waits too long
+// for its turn at a monitor operation, the run has diverged from the recording: a
+// warning is shown and the program continues with live inputs and scheduling.
+//
+// The log is a text file, with a line for each input: its kind and its value, e.g.
+// "millis 1729080000000". The value of a monitor operation is the thread's ID.
+package replay
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// the kinds of inputs, as they appear in the log
+const (
+	KindMillis  = "millis"  // System.currentTimeMillis()
+	KindNanos   = "nanos"   // System.nanoTime()
+	KindSeed    = "seed"    // the seed of a Random created without one
+	KindRandom  = "random"  // Math.random(), as the bits of the double
+	KindRead    = "read"    // the result of a read
+	KindMonitor = "monitor" // a thread passing a monitor operation
+)
+
+const header = "jacobin-replay 1"
+
+// DivergenceTimeout is how long a thread waits for its turn at a monitor operation
+// before the replay is considered to have diverged from the recording
+var DivergenceTimeout = 5 * time.Second
+
+const (
+	off = iota
+	recording
+	replaying
+)
+
+var mode atomic.Int32
+
+// the log being recorded
+var recorder struct {
+	sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+}
+
+// the log being replayed: the values of each kind, and the order of the threads at
+// monitor operations
+var player struct {
+	sync.Mutex
+	values   map[string][]string
+	schedule []int
+	next     int           // the index in schedule of the thread whose turn it is
+	changed  chan struct{} // closed when next advances
+	diverged bool
+}
+
+// Record starts recording the inputs of the program to the named file
+func Record(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("cannot create the replay log %s: %w", path, err)
+	}
+	recorder.Lock()
+	recorder.file = file
+	recorder.writer = bufio.NewWriter(file)
+	_, _ = fmt.Fprintln(recorder.writer, header)
+	recorder.Unlock()
+	mode.Store(recording)
+	return nil
+}
+
+// Replay starts replaying the inputs recorded in the named file
+func Replay(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("cannot open the replay log %s: %w", path, err)
+	}
+	defer file.Close()
+
+	values := make(map[string][]string)
+	var schedule []int
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024) // a read can log a large buffer
+	if !scanner.Scan() || scanner.Text() != header {
+		return fmt.Errorf("%s is not a Jacobin replay log", path)
+	}
+	for line := 2; scanner.Scan(); line++ {
+		kind, value, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			return fmt.Errorf("invalid entry at line %d of the replay log %s", line, path)
+		}
+		if kind == KindMonitor {
+			thread, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid thread at line %d of the replay log %s", line, path)
+			}
+			schedule = append(schedule, thread)
+			continue
+		}
+		values[kind] = append(values[kind], value)
+	}
+	if err = scanner.Err(); err != nil {
+		return fmt.Errorf("cannot read the replay log %s: %w", path, err)
+	}
+
+	player.Lock()
+	player.values, player.schedule, player.next = values, schedule, 0
+	player.changed = make(chan struct{})
+	player.diverged = false
+	player.Unlock()
+	mode.Store(replaying)
+	return nil
+}
+
+// Close ends the recording or replay, writing any of the log that is buffered
+func Close() error {
+	previous := mode.Swap(off)
+	if previous != recording {
+		return nil
+	}
+	recorder.Lock()
+	defer recorder.Unlock()
+	err := recorder.writer.Flush()
+	if closeErr := recorder.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Active reports whether inputs are being recorded or replayed
+func Active() bool {
+	return mode.Load() != off
+}
+
+// writes an input to the log being recorded
+func record(kind string, value string) {
+	recorder.Lock()
+	defer recorder.Unlock()
+	if recorder.writer != nil {
+		_, _ = fmt.Fprintf(recorder.writer, "%s %s\n", kind, value)
+	}
+}
+
+// returns the next recorded value of a kind, or false if the replay has diverged
+func nextValue(kind string) (string, bool) {
+	player.Lock()
+	defer player.Unlock()
+	if player.diverged {
+		return "", false
+	}
+	values := player.values[kind]
+	if len(values) == 0 {
+		divergeLocked(fmt.Sprintf("the program asked for more %s inputs than were recorded", kind))
+		return "", false
+	}
+	player.values[kind] = values[1:]
+	return values[0], true
+}
+
+// ends the replay of the recorded inputs, which the program no longer follows.
+// Call with player locked.
+func divergeLocked(reason string) {
+	if player.diverged {
+		return
+	}
+	player.diverged = true
+	close(player.changed) // release the threads waiting for their turn
+	_, _ = fmt.Fprintf(os.Stderr, "Warning: the replay has diverged from the recording (%s); "+
+		"continuing with live inputs\n", reason)
+}
+
+// Int64 returns a nondeterministic int64 input of the given kind, such as the time,
+// which live gets. When recording, the value is logged; when replaying, the recorded
+// value is returned in place of the live one.
+func Int64(kind string, live func() int64) int64 {
+	switch mode.Load() {
+	case recording:
+		value := live()
+		record(kind, strconv.FormatInt(value, 10))
+		return value
+	case replaying:
+		if recorded, ok := nextValue(kind); ok {
+			if value, err := strconv.ParseInt(recorded, 10, 64); err == nil {
+				return value
+			}
+		}
+	}
+	return live()
+}
+
+// Read reads from r into buf, as r.Read() does. When recording, the result is logged;
+// when replaying, the recorded result is returned and r is not read.
+func Read(r io.Reader, buf []byte) (int, error) {
+	switch mode.Load() {
+	case recording:
+		n, err := r.Read(buf)
+		value := strconv.Itoa(n) + " " + hex.EncodeToString(buf[:n])
+		switch {
+		case err == io.EOF:
+			value += " EOF"
+		case err != nil:
+			value += " " + err.Error()
+		}
+		record(KindRead, value)
+		return n, err
+	case replaying:
+		if recorded, ok := nextValue(KindRead); ok {
+			if n, err, ok := parseRead(recorded, buf); ok {
+				return n, err
+			}
+		}
+	}
+	return r.Read(buf)
+}
+
+// parses a recorded read into buf, returning false if it's invalid or the data doesn't fit
+func parseRead(recorded string, buf []byte) (int, error, bool) {
+	fields := strings.SplitN(recorded, " ", 3)
+	n, err := strconv.Atoi(fields[0])
+	if err != nil || len(fields) < 2 || n > len(buf) {
+		return 0, nil, false
+	}
+	data, err := hex.DecodeString(fields[1])
+	if err != nil || len(data) != n {
+		return 0, nil, false
+	}
+	copy(buf, data)
+
+	switch {
+	case len(fields) < 3:
+		return n, nil, true
+	case fields[2] == "EOF":
+		return n, io.EOF, true
+	default:
+		return n, errors.New(fields[2]), true
+	}
+}
+
+// AwaitTurn is called by a thread before it attempts a monitor operation. When
+// replaying, it waits until the threads recorded before it have passed theirs.
+func AwaitTurn(thread int) {
+	if mode.Load() != replaying {
+		return
+	}
+	deadline := time.Now().Add(DivergenceTimeout)
+	for {
+		player.Lock()
+		if player.diverged || player.next >= len(player.schedule) || player.schedule[player.next] == thread {
+			player.Unlock()
+			return
+		}
+		changed := player.changed
+		player.Unlock()
+
+		timer := time.NewTimer(time.Until(deadline))
+		select {
+		case <-changed:
+			timer.Stop()
+		case <-timer.C:
+			player.Lock()
+			divergeLocked(fmt.Sprintf("thread %d waited too long for its turn at a monitor operation", thread))
+			player.Unlock()
+			return
+		}
+	}
+}
+
+// Passed is called by a thread when it has completed a monitor operation, such as
+// acquiring a lock. When recording, the thread's turn is logged; when replaying, the
+// turn passes to the next thread in the recording.
+func Passed(thread int) {
+	switch mode.Load() {
+	case recording:
+		record(KindMonitor, strconv.Itoa(thread))
+	case replaying:
+		player.Lock()
+		defer player.Unlock()
+		if player.diverged || player.next >= len(player.schedule) {
+			return
+		}
+		if player.schedule[player.next] != thread {
+			divergeLocked(fmt.Sprintf("thread %d passed a monitor operation out of turn", thread))
+			return
+		}
+		player.next++
+		close(player.changed)
+		player.changed = make(chan struct{})
+	}
+}
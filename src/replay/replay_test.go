@@ -0,0 +1,203 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package replay
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// returns a function that returns the given values in turn, as a live input
+func liveValues(values ...int64) func() int64 {
+	return func() int64 {
+		value := values[0]
+		values = values[1:]
+		return value
+	}
+}
+
+// redirects stderr, where divergence is reported, for the duration of a test
+func captureStderr(t *testing.T) func() string {
+	normalStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+	t.Cleanup(func() { os.Stderr = normalStderr })
+	return func() string {
+		_ = w.Close()
+		os.Stderr = normalStderr
+		out, _ := io.ReadAll(r)
+		return string(out)
+	}
+}
+
+func TestRecordAndReplayValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.log")
+	if err := Record(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	live := liveValues(1729080000000, 42, 1729080000005)
+	for i := 0; i < 2; i++ {
+		Int64(KindMillis, live)
+	}
+	Int64(KindSeed, live)
+	if err := Close(); err != nil {
+		t.Fatalf("Unexpected error closing the recording: %v", err)
+	}
+
+	if err := Replay(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer Close()
+	stderr := captureStderr(t)
+	live = liveValues(7, 8)
+	if seed := Int64(KindSeed, live); seed != 1729080000005 {
+		t.Errorf("Expected the recorded seed, got: %d", seed)
+	}
+	if first, second := Int64(KindMillis, live), Int64(KindMillis, live); first != 1729080000000 || second != 42 {
+		t.Errorf("Expected the recorded times in order, got: %d and %d", first, second)
+	}
+
+	// a further input wasn't recorded, so the replay has diverged and the live value is used
+	if millis := Int64(KindMillis, live); millis != 7 {
+		t.Errorf("Expected the live time after the recorded ones, got: %d", millis)
+	}
+	if !strings.Contains(stderr(), "diverged") {
+		t.Error("Expected a warning that the replay diverged")
+	}
+}
+
+func TestRecordAndReplayReads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.log")
+	if err := Record(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	input := strings.NewReader("hello")
+	buf := make([]byte, 3)
+	var recorded []string
+	for {
+		n, err := Read(input, buf)
+		recorded = append(recorded, string(buf[:n]))
+		if err != nil {
+			break
+		}
+	}
+	_ = Close()
+
+	if err := Replay(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer Close()
+	input = strings.NewReader("other input")
+	for i, expected := range recorded {
+		n, err := Read(input, buf)
+		if string(buf[:n]) != expected {
+			t.Errorf("Expected read %d to return %q, got: %q", i, expected, buf[:n])
+		}
+		if (err == io.EOF) != (i == len(recorded)-1) {
+			t.Errorf("Expected EOF only at the last read, got: %v at read %d", err, i)
+		}
+	}
+	if input.Len() != len("other input") {
+		t.Error("Did not expect the live input to be read during the replay")
+	}
+}
+
+func TestReplayMonitorSchedule(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.log")
+	if err := Record(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, thread := range []int{3, 2, 3, 1} {
+		Passed(thread)
+	}
+	_ = Close()
+
+	if err := Replay(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer Close()
+
+	// the threads arrive in the opposite order, but pass in the recorded one
+	var order []int
+	var orderLock sync.Mutex
+	var wg sync.WaitGroup
+	for _, thread := range []int{1, 2, 3} {
+		wg.Add(1)
+		go func(thread int) {
+			defer wg.Done()
+			passes := 1
+			if thread == 3 {
+				passes = 2
+			}
+			for i := 0; i < passes; i++ {
+				AwaitTurn(thread)
+				orderLock.Lock()
+				order = append(order, thread)
+				orderLock.Unlock()
+				Passed(thread)
+			}
+		}(thread)
+		time.Sleep(10 * time.Millisecond)
+	}
+	wg.Wait()
+
+	if len(order) != 4 || order[0] != 3 || order[1] != 2 || order[2] != 3 || order[3] != 1 {
+		t.Errorf("Expected the threads to pass in the order 3, 2, 3, 1, got: %v", order)
+	}
+}
+
+func TestReplayMonitorDivergence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.log")
+	if err := os.WriteFile(path, []byte(header+"\nmonitor 2\n"), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := Replay(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer Close()
+	stderr := captureStderr(t)
+
+	saved := DivergenceTimeout
+	DivergenceTimeout = 20 * time.Millisecond
+	defer func() { DivergenceTimeout = saved }()
+
+	AwaitTurn(1) // thread 2 never arrives
+	if !strings.Contains(stderr(), "thread 1 waited too long") {
+		t.Error("Expected a warning that the replay diverged")
+	}
+	AwaitTurn(1) // once diverged, threads no longer wait
+}
+
+func TestReplayInvalidLog(t *testing.T) {
+	dir := t.TempDir()
+	if err := Replay(filepath.Join(dir, "missing.log")); err == nil {
+		t.Error("Expected an error for a missing log")
+	}
+
+	path := filepath.Join(dir, "bad.log")
+	_ = os.WriteFile(path, []byte("not a log\n"), 0644)
+	if err := Replay(path); err == nil {
+		t.Error("Expected an error for a file that is not a replay log")
+	}
+	if Active() {
+		t.Error("Did not expect a replay to be active after an error")
+	}
+}
+
+func TestInactiveUsesLiveInputs(t *testing.T) {
+	_ = Close()
+	if Int64(KindNanos, liveValues(5)) != 5 {
+		t.Error("Expected the live value when not recording or replaying")
+	}
+	AwaitTurn(1)
+	Passed(1)
+}
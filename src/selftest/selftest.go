@@ -0,0 +1,222 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+// Package selftest runs Jacobin's conformance suite: a directory of small Java
+// programs, each with golden files that give the output and exit code it's expected
+// to produce, in the manner of jtreg. It's run by jacobin -selftest <dir> and by the
+// Go test harness in wholeClassTests.
+//
+// A test case is a class, Name.class, for which at least one golden file exists:
+//
+//	Name.out  - the expected stdout
+//	Name.err  - the expected stderr
+//	Name.exit - the expected exit code (0 if there is no such file)
+//
+// Output for which there is no golden file is not checked. If Name.java is present,
+// it's compiled with javac (if javac is on the path) when Name.class is missing or
+// older than the source. Each case is run in a separate Jacobin process, with the
+// suite's directory as the classpath.
+package selftest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timeout is the longest a test case may run before it's stopped and counted as failed
+var Timeout = 60 * time.Second
+
+// Result is the outcome of one test case
+type Result struct {
+	Name  string
+	Diffs []string // how the actual results differ from the golden files; empty if the case passed
+}
+
+// Passed reports whether the case produced the expected output and exit code
+func (r Result) Passed() bool { return len(r.Diffs) == 0 }
+
+// Run runs the test cases in dir with the JVM command jvm (the executable and any
+// options that precede the classpath), writes a PASS or FAIL line for each to out,
+// followed by the totals, and returns the results. An error is returned only if the
+// suite can't be read.
+func Run(dir string, jvm []string, out io.Writer) ([]Result, error) {
+	if len(jvm) == 0 {
+		return nil, errors.New("selftest: no JVM to run the tests with")
+	}
+	names, err := findCases(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	failed := 0
+	for _, name := range names {
+		result := runCase(dir, name, jvm)
+		results = append(results, result)
+		if result.Passed() {
+			_, _ = fmt.Fprintf(out, "PASS %s\n", name)
+			continue
+		}
+		failed++
+		_, _ = fmt.Fprintf(out, "FAIL %s\n", name)
+		for _, diff := range result.Diffs {
+			_, _ = fmt.Fprintf(out, "    %s\n", diff)
+		}
+	}
+	_, _ = fmt.Fprintf(out, "selftest: %d passed, %d failed\n", len(results)-failed, failed)
+	return results, nil
+}
+
+// returns the names of the test cases in dir, in alphabetic order
+func findCases(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("selftest: cannot read the test directory %s: %w", dir, err)
+	}
+
+	cases := make(map[string]bool)
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".out" && ext != ".err" && ext != ".exit") {
+			continue
+		}
+		cases[strings.TrimSuffix(entry.Name(), ext)] = true
+	}
+
+	names := make([]string, 0, len(cases))
+	for name := range cases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// runs one test case and compares the results to its golden files
+func runCase(dir, name string, jvm []string) Result {
+	result := Result{Name: name}
+	if err := CompileIfNeeded(dir, name); err != nil {
+		result.Diffs = append(result.Diffs, err.Error())
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	args := append(append(append([]string{}, jvm[1:]...), "-cp", dir), name)
+	cmd := exec.CommandContext(ctx, jvm[0], args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	exitCode := 0
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if ctx.Err() != nil {
+			result.Diffs = append(result.Diffs, fmt.Sprintf("timed out after %v", Timeout))
+			return result
+		} else if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		} else {
+			result.Diffs = append(result.Diffs, fmt.Sprintf("cannot run %s: %v", jvm[0], err))
+			return result
+		}
+	}
+
+	base := filepath.Join(dir, name)
+	if expected, ok := readGolden(base + ".out"); ok {
+		result.Diffs = append(result.Diffs, diffOutput("stdout", expected, stdout.String())...)
+	}
+	if expected, ok := readGolden(base + ".err"); ok {
+		result.Diffs = append(result.Diffs, diffOutput("stderr", expected, stderr.String())...)
+	}
+	expectedExit := 0
+	if golden, ok := readGolden(base + ".exit"); ok {
+		code, err := strconv.Atoi(strings.TrimSpace(golden))
+		if err != nil {
+			result.Diffs = append(result.Diffs, fmt.Sprintf("invalid exit code in %s.exit: %q", name, golden))
+			return result
+		}
+		expectedExit = code
+	}
+	if exitCode != expectedExit {
+		result.Diffs = append(result.Diffs, fmt.Sprintf("exit code: expected %d, got %d", expectedExit, exitCode))
+	}
+	return result
+}
+
+// CompileIfNeeded compiles Name.java in dir with javac if it's present and Name.class
+// is missing or out of date
+func CompileIfNeeded(dir, name string) error {
+	source := filepath.Join(dir, name+".java")
+	sourceInfo, err := os.Stat(source)
+	if err != nil {
+		if _, err = os.Stat(filepath.Join(dir, name+".class")); err != nil {
+			return fmt.Errorf("missing %s.class", name)
+		}
+		return nil // there's no source, only the class
+	}
+	if classInfo, err := os.Stat(filepath.Join(dir, name+".class")); err == nil &&
+		!classInfo.ModTime().Before(sourceInfo.ModTime()) {
+		return nil
+	}
+
+	javac, err := exec.LookPath("javac")
+	if err != nil {
+		return fmt.Errorf("%s.class is missing or out of date, and there's no javac to compile %s.java", name, name)
+	}
+	if output, err := exec.Command(javac, "-d", dir, source).CombinedOutput(); err != nil {
+		return fmt.Errorf("cannot compile %s.java: %v\n%s", name, err, output)
+	}
+	return nil
+}
+
+// returns the contents of a golden file, with Windows line endings converted to \n
+func readGolden(path string) (string, bool) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return strings.ReplaceAll(string(contents), "\r\n", "\n"), true
+}
+
+// compares the output of a stream with its golden file, and describes the first
+// line that differs. Returns nil if they're the same.
+func diffOutput(stream, expected, actual string) []string {
+	actual = strings.ReplaceAll(actual, "\r\n", "\n")
+	if expected == actual {
+		return nil
+	}
+
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+	for i := 0; i < len(expectedLines) || i < len(actualLines); i++ {
+		var want, got string
+		if i < len(expectedLines) {
+			want = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			got = actualLines[i]
+		}
+		switch {
+		case i >= len(actualLines):
+			return []string{fmt.Sprintf("%s ends before line %d: expected %q", stream, i+1, want)}
+		case i >= len(expectedLines):
+			return []string{fmt.Sprintf("%s has unexpected output at line %d: %q", stream, i+1, got)}
+		case want != got:
+			return []string{fmt.Sprintf("%s differs at line %d: expected %q, got %q", stream, i+1, want, got)}
+		}
+	}
+	return nil // not reached, as expected != actual
+}
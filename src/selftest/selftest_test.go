@@ -0,0 +1,101 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package selftest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestHelperJVM is not a real test: it stands in for Jacobin when the test binary
+// is run as the JVM by the tests below. The last arg is the name of the class.
+func TestHelperJVM(t *testing.T) {
+	if os.Getenv("JACOBIN_SELFTEST_HELPER") != "1" {
+		return
+	}
+	switch os.Args[len(os.Args)-1] {
+	case "Hello":
+		fmt.Println("Hello")
+		os.Exit(0)
+	case "Bad":
+		fmt.Println("Goodbye")
+		fmt.Fprintln(os.Stderr, "Exception in thread \"main\" java.lang.ArithmeticException")
+		os.Exit(3)
+	}
+	os.Exit(99)
+}
+
+// writes the files, given as name and contents, to dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatalf("Cannot write %s: %v", name, err)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Setenv("JACOBIN_SELFTEST_HELPER", "1")
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"Hello.class": "", "Hello.out": "Hello\r\n", "Hello.exit": "0\n",
+		"Bad.class": "", "Bad.out": "Hello\n", "Bad.err": "",
+		"Missing.out":  "", // no class for this case
+		"Helper.class": "", // a class with no golden files is not a case
+	})
+
+	var out bytes.Buffer
+	jvm := []string{os.Args[0], "-test.run=^TestHelperJVM$", "--"}
+	results, err := Run(dir, jvm, &out)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(results) != 3 || results[0].Name != "Bad" || results[1].Name != "Hello" || results[2].Name != "Missing" {
+		t.Fatalf("Expected the cases Bad, Hello, and Missing, got: %v", results)
+	}
+	if !results[1].Passed() {
+		t.Errorf("Expected Hello to pass, got: %v", results[1].Diffs)
+	}
+	if len(results[0].Diffs) != 3 {
+		t.Errorf("Expected Bad to differ in stdout, stderr, and exit code, got: %v", results[0].Diffs)
+	}
+	if results[2].Passed() || !strings.Contains(results[2].Diffs[0], "missing Missing.class") {
+		t.Errorf("Expected Missing to fail for want of its class, got: %v", results[2].Diffs)
+	}
+	if !strings.Contains(out.String(), "PASS Hello") || !strings.Contains(out.String(), "selftest: 1 passed, 2 failed") {
+		t.Errorf("Unexpected report: %s", out.String())
+	}
+}
+
+func TestRunWithMissingDirectory(t *testing.T) {
+	if _, err := Run(filepath.Join(t.TempDir(), "none"), []string{"jacobin"}, &bytes.Buffer{}); err == nil {
+		t.Error("Expected an error for a directory that does not exist")
+	}
+}
+
+func TestDiffOutput(t *testing.T) {
+	tests := []struct {
+		expected, actual, diff string
+	}{
+		{"a\nb\n", "a\r\nb\r\n", ""},
+		{"a\nb\n", "a\nc\n", `stdout differs at line 2: expected "b", got "c"`},
+		{"a\nb\n", "a\n", `stdout differs at line 2: expected "b", got ""`},
+		{"a", "a\nb", `stdout has unexpected output at line 2: "b"`},
+		{"a\nb", "a", `stdout ends before line 2: expected "b"`},
+	}
+	for _, test := range tests {
+		diffs := diffOutput("stdout", test.expected, test.actual)
+		if (test.diff == "" && diffs != nil) || (test.diff != "" && (len(diffs) != 1 || diffs[0] != test.diff)) {
+			t.Errorf("diffOutput(%q, %q): expected %q, got %v", test.expected, test.actual, test.diff, diffs)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"jacobin/src/statics"
 	"jacobin/src/trace"
 	"os"
+	"sync"
 )
 
 // The various flags that can be passed to the exit() function, reflecting
@@ -28,10 +29,50 @@ const (
 	UNKNOWN_ERROR
 )
 
-// This is the exit-to-O/S function.
+// UNCAUGHT_EXCEPTION is the exit status of a program whose main thread ends with an
+// uncaught exception, which is 1, as in the JDK
+const UNCAUGHT_EXCEPTION = JVM_EXCEPTION
+
+// the functions run by Exit() before Jacobin ends, in the order they were added
+var exitHooks []func()
+var exitHooksLock sync.Mutex
+
+// AddExitHook adds a function to run when Jacobin exits, for reports that are printed
+// at exit, however the program ends, such as -XX:+PrintGfunctionUsageAtExit. These are
+// Jacobin's own hooks, not the Java program's (see Runtime.addShutdownHook()).
+func AddExitHook(hook func()) {
+	exitHooksLock.Lock()
+	exitHooks = append(exitHooks, hook)
+	exitHooksLock.Unlock()
+}
+
+// runs the exit hooks. Each runs only once, even if Exit() is called again.
+func runExitHooks() {
+	exitHooksLock.Lock()
+	hooks := exitHooks
+	exitHooks = nil
+	exitHooksLock.Unlock()
+	for _, hook := range hooks {
+		hook()
+	}
+}
+
+// This is the exit-to-O/S function. If an exit handler is installed (see
+// globals.SetExitHandler), it gets the exit status instead, and Exit() returns.
 // TODO: Check a list of JVM Shutdown hooks before closing down in order to have an orderly exit.
 func Exit(errorCondition ExitStatus) int {
 	globals.LoaderWg.Wait()
+	runExitHooks()
+
+	// an embedder or test that installed an exit handler gets the status instead
+	if handler := globals.GetExitHandler(); handler != nil {
+		if globals.TraceVerbose {
+			trace.Trace(fmt.Sprintf("shutdown.Exit(%d) intercepted by the exit handler", errorCondition))
+		}
+		handler(errorCondition)
+		return errorCondition
+	}
+
 	g := globals.GetGlobalRef()
 	if g.JacobinName == "test" || g.JacobinName == "testWithoutShutdown" {
 		if errorCondition == OK {
@@ -73,3 +73,39 @@ func TestShutdownReturn(t *testing.T) {
 		t.Errorf("Expecting exit() return value of 0, but got %d", ret)
 	}
 }
+
+func TestExitRunsExitHooksOnce(t *testing.T) {
+	globals.InitGlobals("test")
+
+	var calls []string
+	AddExitHook(func() { calls = append(calls, "first") })
+	AddExitHook(func() { calls = append(calls, "second") })
+
+	Exit(OK)
+	Exit(OK)
+
+	if len(calls) != 2 || calls[0] != "first" || calls[1] != "second" {
+		t.Errorf("Expected the exit hooks to run once, in order, got: %v", calls)
+	}
+}
+
+func TestExitHandlerInterceptsExit(t *testing.T) {
+	globals.InitGlobals("test")
+	var statuses []int
+	previous := globals.SetExitHandler(func(status int) { statuses = append(statuses, status) })
+	defer globals.SetExitHandler(previous)
+
+	hookRan := false
+	AddExitHook(func() { hookRan = true })
+
+	// the handler gets the status itself, not the one that tests are given
+	if ret := Exit(APP_EXCEPTION); ret != APP_EXCEPTION {
+		t.Errorf("Expected Exit() to return the status %d, got: %d", APP_EXCEPTION, ret)
+	}
+	if len(statuses) != 1 || statuses[0] != APP_EXCEPTION {
+		t.Errorf("Expected the handler to get the status %d, got: %v", APP_EXCEPTION, statuses)
+	}
+	if !hookRan {
+		t.Errorf("Expected the exit hooks to run before the handler")
+	}
+}
@@ -18,10 +18,12 @@ pre-existing or is new to the Pool.
 String Pool components, in the globals package, common across all frames and threads:
 -------------------------------------------------------------------------------------
 
-stringTable = map[string] --> uint32, an index to stringList (initially empty)
-stringList []string - the array of unique strings (initially empty)
-stringNext uint32 - the index of the next available stringList entry (initially 0)
-stringLock sync.Mutex - control modifications to this Pool (initially unlocked)
+StringPoolShards - the map of string --> uint32, an index into StringPoolList, split into
+  shards by the hash of the string. Each shard has its own RWMutex, so lookups of strings
+  in different shards (and all lookups of strings already in the pool) do not contend.
+StringPoolList atomic.Pointer[[]string] - the array of unique strings. Entries are never
+  changed once added; when the array must grow, a new one replaces it, so readers need no lock.
+StringPoolLock sync.Mutex - serializes the additions to StringPoolList (initially unlocked)
 
 Mid-level Functions:
 --------------------
@@ -48,6 +50,9 @@ GetStringIndex(arg *string) uint32 -
   - Given a pointer to a Go string, add the string to the pool if the string is not already present.
   - Whether new or existing, return the index for the string for subsequent direct retrievals using stringList.
 
+GetStringIndexBatch(args []string) []uint32 -
+  - Like GetStringIndex, for many strings at once, as when a class's constant pool is parsed.
+
 GetStringPoolSize() uint32
 
 	Get the current string Pool size.
@@ -79,32 +84,78 @@ func GetStringIndex(arg *string) uint32 {
 		arg = &nilString
 	}
 
-	index, ok := globals.StringPoolTable[*arg]
-	if ok {
+	if index, ok := lookUpString(*arg); ok {
 		return index
 	}
+	return addString(*arg)
+}
+
+// GetStringIndexBatch returns the string pool indices of the strings, adding any that are
+// not yet in the pool. The strings already in the pool are looked up without waiting for
+// the additions of other threads, and the new strings are added in a single pass.
+func GetStringIndexBatch(args []string) []uint32 {
+	indices := make([]uint32, len(args))
+	var missing []int
+	for i := range args {
+		index, ok := lookUpString(args[i])
+		if ok {
+			indices[i] = index
+		} else {
+			missing = append(missing, i)
+		}
+	}
+	for _, i := range missing {
+		indices[i] = addString(args[i])
+	}
+	return indices
+}
+
+// lookUpString returns the index of the string, if it's in the pool
+func lookUpString(str string) (uint32, bool) {
+	shard := globals.StringPoolShardOf(str)
+	shard.Lock.RLock()
+	index, ok := shard.Table[str]
+	shard.Lock.RUnlock()
+	return index, ok
+}
+
+// addString adds the string to the pool, unless another thread added it first,
+// and returns its index. The lock of the string's shard is held while the string
+// is appended, so that no other thread can add it as well.
+func addString(str string) uint32 {
+	shard := globals.StringPoolShardOf(str)
+	shard.Lock.Lock()
+	defer shard.Lock.Unlock()
+	if index, ok := shard.Table[str]; ok {
+		return index
+	}
+
 	globals.StringPoolLock.Lock()
-	index = globals.StringPoolNext
-	globals.StringPoolTable[*arg] = index
-	globals.StringPoolList = append(globals.StringPoolList, *arg)
-	globals.StringPoolNext++
+	list := *globals.StringPoolList.Load()
+	index := uint32(len(list))
+	// append() either writes past the end of the slice that readers have, or copies the
+	// strings to a new array, so no reader sees an entry change
+	list = append(list, str)
+	globals.StringPoolList.Store(&list)
 	globals.StringPoolLock.Unlock()
+
+	shard.Table[str] = index
 	return index
 }
 
 // GetStringPointer retrieves a pointer to the string at the index into the string pool slice
 // Returns nil on index out of range (which is the only possible error)
 func GetStringPointer(index uint32) *string {
-	if index < uint32(len(globals.StringPoolList)) {
-		return &globals.StringPoolList[index]
+	list := *globals.StringPoolList.Load()
+	if index < uint32(len(list)) {
+		return &list[index]
 	} else {
 		return nil
 	}
 }
 
 func GetStringPoolSize() uint32 {
-	// glob := globals.GetGlobalRef()
-	return globals.StringPoolNext
+	return uint32(len(*globals.StringPoolList.Load()))
 }
 
 // EmptyStringPool is used exclusively for testing. If used in production, remove this comment.
@@ -119,9 +170,18 @@ func DumpStringPool(context string) {
 	} else {
 		_, _ = fmt.Fprintln(os.Stdout, "\n===== DumpStringPool BEGIN")
 	}
-	// Create an array of keys.
-	keys := make([]string, 0, len(globals.StringPoolTable))
-	for key := range globals.StringPoolTable {
+	// Create an array of keys, from all the shards.
+	table := make(map[string]uint32)
+	for i := range globals.StringPoolShards {
+		shard := &globals.StringPoolShards[i]
+		shard.Lock.RLock()
+		for key, index := range shard.Table {
+			table[key] = index
+		}
+		shard.Lock.RUnlock()
+	}
+	keys := make([]string, 0, len(table))
+	for key := range table {
 		keys = append(keys, key)
 	}
 	// Sort the keys.
@@ -129,12 +189,28 @@ func DumpStringPool(context string) {
 	sort.Strings(keys)
 	// In key sequence order, display the key and its value.
 	for _, key := range keys {
-		_, _ = fmt.Fprintf(os.Stdout, "%d\t%s\n", globals.StringPoolTable[key], key)
+		_, _ = fmt.Fprintf(os.Stdout, "%d\t%s\n", table[key], key)
 	}
 	_, _ = fmt.Fprintln(os.Stdout, "===== DumpStringPool END")
 	globals.StringPoolLock.Unlock()
 }
 
+// PreloadDescriptorsToStringPool adds the class names and method descriptors that nearly
+// every class refers to, so that the threads loading classes find them already in the pool
+func PreloadDescriptorsToStringPool() {
+	descriptors := []string{
+		"()V", "(Ljava/lang/Object;)Z", "()I", "()Ljava/lang/String;",
+		"([Ljava/lang/String;)V", "(Ljava/lang/String;)V",
+		"<init>", "<clinit>", "main", "toString", "hashCode", "equals",
+		"Z", "B", "C", "S", "I", "J", "F", "D", "V",
+		"Ljava/lang/Object;", "Ljava/lang/String;",
+		"java/lang/Class", "java/lang/System", "java/lang/Throwable", "java/lang/Exception",
+		"java/lang/StringBuilder", "java/lang/Integer", "java/lang/Long", "java/lang/Math",
+		"java/io/PrintStream", "java/util/Objects",
+	}
+	_ = GetStringIndexBatch(descriptors)
+}
+
 func PreloadArrayClassesToStringPool() {
 	arrayClassesToPreload := []string{
 		types.BoolArray,
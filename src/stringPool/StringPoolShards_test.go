@@ -0,0 +1,123 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by  the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package stringPool
+
+import (
+	"fmt"
+	"jacobin/src/globals"
+	"sync"
+	"testing"
+)
+
+func TestGetStringIndexBatch(t *testing.T) {
+	globals.InitGlobals("test")
+	existing := "java/lang/Object"
+	str := "batch/First"
+	first := GetStringIndex(&str)
+
+	indices := GetStringIndexBatch([]string{"batch/First", "batch/Second", existing, "batch/Second"})
+	if indices[0] != first || indices[2] != 2 {
+		t.Errorf("strings already in the pool got new indices: %v", indices)
+	}
+	if indices[1] != 4 || indices[3] != 4 {
+		t.Errorf("expected batch/Second to be added once, at index 4, got %v", indices)
+	}
+	if *GetStringPointer(indices[1]) != "batch/Second" {
+		t.Errorf("index %d holds %q", indices[1], *GetStringPointer(indices[1]))
+	}
+	if GetStringPoolSize() != 5 {
+		t.Errorf("expected a pool of 5 strings, got %d", GetStringPoolSize())
+	}
+}
+
+// many goroutines add the same strings at once: each string must get exactly one index
+func TestGetStringIndexConcurrently(t *testing.T) {
+	globals.InitGlobals("test")
+	initialSize := GetStringPoolSize()
+	const goroutines, strings = 8, 2000
+
+	results := make([][]uint32, goroutines)
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[g] = make([]uint32, strings)
+			for i := range strings {
+				str := fmt.Sprintf("concurrent/Class%d", i)
+				results[g][i] = GetStringIndex(&str)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := range strings {
+		for g := 1; g < goroutines; g++ {
+			if results[g][i] != results[0][i] {
+				t.Fatalf("string %d got indices %d and %d", i, results[0][i], results[g][i])
+			}
+		}
+		if want := fmt.Sprintf("concurrent/Class%d", i); *GetStringPointer(results[0][i]) != want {
+			t.Fatalf("index %d holds %q, want %q", results[0][i], *GetStringPointer(results[0][i]), want)
+		}
+	}
+	if GetStringPoolSize() != initialSize+strings {
+		t.Errorf("expected %d strings in the pool, got %d", initialSize+strings, GetStringPoolSize())
+	}
+}
+
+func TestPreloadDescriptorsToStringPool(t *testing.T) {
+	globals.InitGlobals("test")
+	PreloadDescriptorsToStringPool()
+	size := GetStringPoolSize()
+
+	descriptor := "()V"
+	if index := GetStringIndex(&descriptor); *GetStringPointer(index) != "()V" || GetStringPoolSize() != size {
+		t.Errorf("()V was not preloaded")
+	}
+	PreloadDescriptorsToStringPool()
+	if GetStringPoolSize() != size {
+		t.Errorf("preloading twice added %d strings", GetStringPoolSize()-size)
+	}
+}
+
+// lookups of strings already in the pool, from all the available threads
+func BenchmarkGetStringIndexParallel(b *testing.B) {
+	globals.InitGlobals("test")
+	names := make([]string, 4096)
+	for i := range names {
+		names[i] = fmt.Sprintf("bench/Class%d", i)
+	}
+	GetStringIndexBatch(names)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			_ = GetStringIndex(&names[i%len(names)])
+			i++
+		}
+	})
+}
+
+// additions of new strings from all the available threads
+func BenchmarkAddStringParallel(b *testing.B) {
+	globals.InitGlobals("test")
+	var counter sync.Mutex
+	next := 0
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			counter.Lock()
+			next++
+			str := fmt.Sprintf("bench/New%d", next)
+			counter.Unlock()
+			_ = GetStringIndex(&str)
+		}
+	})
+}
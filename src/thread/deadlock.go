@@ -0,0 +1,206 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package thread
+
+import (
+	"fmt"
+	"jacobin/src/globals"
+	"jacobin/src/object"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Deadlock detection. A thread that blocks until another thread does something--
+// releases a lock or ends--records what it is waiting for by calling SetBlockedOn()
+// and erases the record with ClearBlockedOn() once it resumes. These records are the
+// edges of the waits-for graph: thread A -> thread B when A waits for B. Because a
+// blocked thread waits for only one thing, every thread has at most one outgoing
+// edge, so a deadlock is simply a cycle found by following the edges from a thread.
+// The detector checks the graph periodically and reports each deadlock it finds in
+// the manner of HotSpot's "Found one Java-level deadlock" thread-dump output.
+
+// the kinds of things a thread can be blocked on
+const (
+	BlockedOnLock = iota // waiting to acquire a lock held by another thread
+	BlockedOnJoin        // waiting in Thread.join() for another thread to end
+)
+
+// BlockedOn describes what a blocked thread is waiting for
+type BlockedOn struct {
+	Kind     int    // BlockedOnLock or BlockedOnJoin
+	Resource string // the lock being waited for, e.g., "the initialization lock of class pkg.Foo"
+	Owner    int    // ID of the thread being waited for
+}
+
+// the waits-for graph, keyed by the ID of the blocked thread
+var waitsFor = struct {
+	sync.Mutex
+	edges map[int]BlockedOn
+}{edges: make(map[int]BlockedOn)}
+
+// SetBlockedOn records that the thread threadID is about to block until the thread
+// owner releases the resource or, for BlockedOnJoin, ends
+func SetBlockedOn(threadID int, kind int, resource string, owner int) {
+	waitsFor.Lock()
+	waitsFor.edges[threadID] = BlockedOn{Kind: kind, Resource: resource, Owner: owner}
+	waitsFor.Unlock()
+}
+
+// ClearBlockedOn records that the thread threadID is no longer blocked
+func ClearBlockedOn(threadID int) {
+	waitsFor.Lock()
+	delete(waitsFor.edges, threadID)
+	waitsFor.Unlock()
+}
+
+// FindDeadlocks returns the cycles in the waits-for graph. Each cycle is a list of
+// thread IDs, in which each thread waits for the next one and the last waits for
+// the first. Each cycle begins with its lowest thread ID, and the cycles are sorted,
+// so that the same deadlock is always reported the same way.
+func FindDeadlocks() [][]int {
+	waitsFor.Lock()
+	edges := make(map[int]BlockedOn, len(waitsFor.edges))
+	for id, b := range waitsFor.edges {
+		edges[id] = b
+	}
+	waitsFor.Unlock()
+
+	var cycles [][]int
+	done := make(map[int]bool) // threads whose path has already been followed
+	for start := range edges {
+		if done[start] {
+			continue
+		}
+		// follow the edges until we reach a thread that isn't blocked, a thread
+		// whose path was followed earlier, or a thread on this path (a cycle)
+		position := make(map[int]int)
+		var path []int
+		id := start
+		for {
+			if done[id] {
+				break
+			}
+			if pos, onPath := position[id]; onPath {
+				cycles = append(cycles, rotateToLowest(path[pos:]))
+				break
+			}
+			b, blocked := edges[id]
+			if !blocked {
+				break
+			}
+			position[id] = len(path)
+			path = append(path, id)
+			id = b.Owner
+		}
+		for _, p := range path {
+			done[p] = true
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	return cycles
+}
+
+// returns a copy of the cycle that begins with its lowest thread ID
+func rotateToLowest(cycle []int) []int {
+	lowest := 0
+	for i, id := range cycle {
+		if id < cycle[lowest] {
+			lowest = i
+		}
+	}
+	return append(append([]int{}, cycle[lowest:]...), cycle[:lowest]...)
+}
+
+// DeadlockReport formats the deadlocks returned by FindDeadlocks() for the thread
+// dump. It returns an empty string if there are no deadlocks.
+func DeadlockReport(cycles [][]int) string {
+	if len(cycles) == 0 {
+		return ""
+	}
+
+	waitsFor.Lock()
+	defer waitsFor.Unlock()
+
+	var sb strings.Builder
+	for _, cycle := range cycles {
+		sb.WriteString("Found one Java-level deadlock:\n")
+		sb.WriteString("=============================\n")
+		for _, id := range cycle {
+			b := waitsFor.edges[id]
+			sb.WriteString(fmt.Sprintf("\"%s\":\n", ThreadName(id)))
+			if b.Kind == BlockedOnJoin {
+				sb.WriteString(fmt.Sprintf("  waiting in Thread.join() for \"%s\" to end\n", ThreadName(b.Owner)))
+			} else {
+				sb.WriteString(fmt.Sprintf("  waiting to lock %s,\n", b.Resource))
+				sb.WriteString(fmt.Sprintf("  which is held by \"%s\"\n", ThreadName(b.Owner)))
+			}
+		}
+		sb.WriteString("\n")
+	}
+	if len(cycles) == 1 {
+		sb.WriteString("Found 1 deadlock.\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("Found %d deadlocks.\n", len(cycles)))
+	}
+	return sb.String()
+}
+
+// ThreadName returns the name of the Java thread with the given ID, as shown in a thread dump
+func ThreadName(id int) string {
+	glob := globals.GetGlobalRef()
+	glob.ThreadLock.Lock()
+	th, _ := glob.Threads[id].(*ExecThread)
+	glob.ThreadLock.Unlock()
+
+	if th != nil {
+		if t, ok := th.JavaThread.(*object.Object); ok && !object.IsNull(t) {
+			if name, ok := t.FieldTable["name"].Fvalue.(string); ok && name != "" {
+				return name
+			}
+		}
+	}
+	if id == 1 { // the thread that runs main()
+		return "main"
+	}
+	return fmt.Sprintf("Thread-%d", id)
+}
+
+// StartDeadlockDetector checks for deadlocks every interval and passes the report
+// of any deadlock not previously found to report(). The returned function stops
+// the detector.
+func StartDeadlockDetector(interval time.Duration, report func(string)) (stop func()) {
+	ticker := time.NewTicker(interval)
+	quit := make(chan struct{})
+	go func() {
+		reported := make(map[string]bool)
+		for {
+			select {
+			case <-ticker.C:
+				var newCycles [][]int
+				for _, cycle := range FindDeadlocks() {
+					key := fmt.Sprint(cycle)
+					if !reported[key] {
+						reported[key] = true
+						newCycles = append(newCycles, cycle)
+					}
+				}
+				if len(newCycles) > 0 {
+					report(DeadlockReport(newCycles))
+				}
+			case <-quit:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(quit) }) }
+}
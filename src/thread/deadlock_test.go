@@ -0,0 +1,100 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package thread
+
+import (
+	"jacobin/src/globals"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clears the waits-for graph, so that tests don't see each other's edges
+func resetWaitsFor() {
+	waitsFor.Lock()
+	waitsFor.edges = make(map[int]BlockedOn)
+	waitsFor.Unlock()
+}
+
+func TestFindDeadlocks(t *testing.T) {
+	resetWaitsFor()
+	defer resetWaitsFor()
+
+	// 3 -> 4 -> 5 -> 3 is a deadlock; 6 -> 3 waits on it but is not part of it;
+	// 7 -> 8 is an ordinary wait
+	SetBlockedOn(4, BlockedOnLock, "the initialization lock of class pkg.B", 5)
+	SetBlockedOn(5, BlockedOnJoin, "", 3)
+	SetBlockedOn(3, BlockedOnLock, "the initialization lock of class pkg.A", 4)
+	SetBlockedOn(6, BlockedOnJoin, "", 3)
+	SetBlockedOn(7, BlockedOnJoin, "", 8)
+
+	cycles := FindDeadlocks()
+	if !reflect.DeepEqual(cycles, [][]int{{3, 4, 5}}) {
+		t.Fatalf("Expected the cycle [3 4 5], got: %v", cycles)
+	}
+
+	ClearBlockedOn(5)
+	if cycles = FindDeadlocks(); len(cycles) != 0 {
+		t.Errorf("Expected no deadlock once thread 5 resumed, got: %v", cycles)
+	}
+}
+
+func TestDeadlockReport(t *testing.T) {
+	globals.InitGlobals("test")
+	resetWaitsFor()
+	defer resetWaitsFor()
+
+	SetBlockedOn(1, BlockedOnLock, "the initialization lock of class pkg.A", 2)
+	SetBlockedOn(2, BlockedOnJoin, "", 1)
+
+	report := DeadlockReport(FindDeadlocks())
+	expected := []string{
+		"Found one Java-level deadlock:",
+		"\"main\":\n  waiting to lock the initialization lock of class pkg.A,\n  which is held by \"Thread-2\"",
+		"\"Thread-2\":\n  waiting in Thread.join() for \"main\" to end",
+		"Found 1 deadlock.",
+	}
+	for _, s := range expected {
+		if !strings.Contains(report, s) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", s, report)
+		}
+	}
+
+	if DeadlockReport(nil) != "" {
+		t.Error("Expected an empty report when there are no deadlocks")
+	}
+}
+
+func TestDeadlockDetectorReportsOnce(t *testing.T) {
+	globals.InitGlobals("test")
+	resetWaitsFor()
+	defer resetWaitsFor()
+
+	reports := make(chan string, 10)
+	stop := StartDeadlockDetector(5*time.Millisecond, func(r string) { reports <- r })
+	defer stop()
+
+	SetBlockedOn(11, BlockedOnJoin, "", 12)
+	SetBlockedOn(12, BlockedOnJoin, "", 11)
+
+	select {
+	case r := <-reports:
+		if !strings.Contains(r, "Thread-11") || !strings.Contains(r, "Thread-12") {
+			t.Errorf("Expected the report to name both threads, got:\n%s", r)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("The detector did not report the deadlock")
+	}
+
+	// the same deadlock is not reported again
+	select {
+	case r := <-reports:
+		t.Errorf("Expected the deadlock to be reported only once, got:\n%s", r)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package thread
+
+import "time"
+
+// Thread interruption. A thread's interrupt status is represented by a token in
+// its Interrupts channel, which has a capacity of one. Setting the status sends
+// the token (if it's not already there); clearing the status takes it out. This
+// lets a thread that is sleeping, joining, or waiting select on the channel, so
+// that an interrupt ends the wait at once. Because taking the token out of the
+// channel is what ends the wait, the interrupt status is cleared whenever a wait
+// is interrupted, as the Java spec requires for methods that throw
+// InterruptedException.
+
+// Interrupt sets the thread's interrupt status, waking it if it is waiting
+func (t *ExecThread) Interrupt() {
+	select {
+	case t.Interrupts <- struct{}{}:
+	default: // the status is already set
+	}
+}
+
+// IsInterrupted reports the thread's interrupt status without changing it
+func (t *ExecThread) IsInterrupted() bool {
+	return len(t.Interrupts) > 0
+}
+
+// ClearInterrupt clears the thread's interrupt status and returns its prior value
+func (t *ExecThread) ClearInterrupt() bool {
+	select {
+	case <-t.Interrupts:
+		return true
+	default:
+		return false
+	}
+}
+
+// WaitInterruptibly blocks the thread until done is closed (or receives a value),
+// the timeout expires, or the thread is interrupted. A timeout of zero or less
+// means no timeout, and a nil done channel is never ready. Returns true if the
+// wait ended because of an interrupt, in which case the interrupt status has
+// been cleared. If the status is already set when this is called, it returns
+// true at once.
+func (t *ExecThread) WaitInterruptibly(done <-chan struct{}, timeout time.Duration) bool {
+	if t.ClearInterrupt() {
+		return true
+	}
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	BeginBlocking(t.ID) // a blocked thread is at a safepoint, see safepoint.go
+	defer EndBlocking(t.ID)
+	select {
+	case <-t.Interrupts:
+		return true
+	case <-done:
+		return false
+	case <-expired:
+		return false
+	}
+}
+
+// Sleep pauses the thread for the given duration unless it is interrupted.
+// Returns true if it was interrupted (which clears the interrupt status).
+func (t *ExecThread) Sleep(d time.Duration) bool {
+	if d <= 0 {
+		return t.ClearInterrupt()
+	}
+	return t.WaitInterruptibly(nil, d)
+}
+
+// Terminate marks the thread as ended, releasing any threads that are joining it
+func (t *ExecThread) Terminate() {
+	if t.Terminated == nil {
+		return
+	}
+	select {
+	case <-t.Terminated: // already closed
+	default:
+		close(t.Terminated)
+	}
+}
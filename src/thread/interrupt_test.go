@@ -0,0 +1,78 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package thread
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInterruptStatus(t *testing.T) {
+	th := CreateThread()
+	if th.IsInterrupted() {
+		t.Error("A new thread should not be interrupted")
+	}
+
+	th.Interrupt()
+	th.Interrupt() // a second interrupt must not block
+	if !th.IsInterrupted() {
+		t.Error("Expected the thread to be interrupted")
+	}
+	if !th.ClearInterrupt() || th.IsInterrupted() {
+		t.Error("Expected ClearInterrupt to return true and clear the status")
+	}
+	if th.ClearInterrupt() {
+		t.Error("Expected ClearInterrupt to return false when the status is clear")
+	}
+}
+
+func TestSleepIsInterrupted(t *testing.T) {
+	th := CreateThread()
+
+	// an interrupt that is pending before the sleep ends it at once
+	th.Interrupt()
+	start := time.Now()
+	if !th.Sleep(10 * time.Second) {
+		t.Error("Expected the sleep to be interrupted")
+	}
+	if time.Since(start) > time.Second || th.IsInterrupted() {
+		t.Error("Expected an immediate return with the interrupt status cleared")
+	}
+
+	// an interrupt during the sleep ends it
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		th.Interrupt()
+	}()
+	start = time.Now()
+	if !th.Sleep(10 * time.Second) {
+		t.Error("Expected the sleep to be interrupted")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("The interrupt did not end the sleep")
+	}
+
+	// an uninterrupted sleep runs its course
+	if th.Sleep(time.Millisecond) {
+		t.Error("Did not expect the sleep to be interrupted")
+	}
+}
+
+func TestWaitInterruptiblyForTermination(t *testing.T) {
+	waiter := CreateThread()
+	target := CreateThread()
+
+	if waiter.WaitInterruptibly(target.Terminated, 10*time.Millisecond) {
+		t.Error("Did not expect a timed-out wait to report an interrupt")
+	}
+
+	target.Terminate()
+	target.Terminate() // must be safe to call twice
+	if waiter.WaitInterruptibly(target.Terminated, 0) {
+		t.Error("Did not expect the wait for a terminated thread to report an interrupt")
+	}
+}
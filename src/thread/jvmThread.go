@@ -16,9 +16,14 @@ import (
 // They begin execution; they exit when execution ends.
 
 type ExecThread struct {
-	ID    int        // the thread ID
-	Stack *list.List // the JVM Stack (frame stack, that is) for this thread
-	Trace bool       // do we trace instructions?
+	ID                int           // the thread ID
+	Stack             *list.List    // the JVM Stack (frame stack, that is) for this thread
+	Trace             bool          // do we trace instructions?
+	ThreadLocals      map[any]any   // values of ThreadLocals for this thread, keyed by the ThreadLocal object
+	InheritableLocals map[any]any   // same, for InheritableThreadLocals
+	Interrupts        chan struct{} // holds a token while the thread's interrupt status is set, see interrupt.go
+	Terminated        chan struct{} // closed when the thread ends, which releases threads that join() it
+	JavaThread        any           // the java/lang/Thread object for this thread, once one is requested
 }
 
 // CreateThread creates an execution thread and initializes it with default values
@@ -28,6 +33,8 @@ func CreateThread() ExecThread {
 	t.ID = IncrementThreadNumber()
 	t.Stack = nil
 	t.Trace = false
+	t.Interrupts = make(chan struct{}, 1)
+	t.Terminated = make(chan struct{})
 	return t
 }
 
@@ -39,6 +46,37 @@ func (t *ExecThread) AddThreadToTable(glob *globals.Globals) {
 	glob.ThreadLock.Unlock()
 }
 
+// Removes a thread that has ended from the global thread table
+func (t *ExecThread) RemoveThreadFromTable(glob *globals.Globals) {
+	glob.ThreadLock.Lock()
+	delete(glob.Threads, t.ID)
+	glob.ThreadLock.Unlock()
+}
+
+// InheritThreadLocals gives a thread that is being started the values of its
+// parent's InheritableThreadLocals. The child's value of each is the one that
+// childValue returns for the InheritableThreadLocal and the parent's value--which
+// runs the local's childValue() method--or, if childValue is nil, the parent's value
+// as it stands. The two threads don't share the map: later calls to set() in either
+// thread are not seen by the other. An error from childValue is returned at once.
+func (t *ExecThread) InheritThreadLocals(parent *ExecThread,
+	childValue func(local, parentValue any) (any, error)) error {
+	if parent == nil || len(parent.InheritableLocals) == 0 {
+		return nil
+	}
+	t.InheritableLocals = make(map[any]any, len(parent.InheritableLocals))
+	for k, v := range parent.InheritableLocals {
+		if childValue != nil {
+			var err error
+			if v, err = childValue(k, v); err != nil {
+				return err
+			}
+		}
+		t.InheritableLocals[k] = v
+	}
+	return nil
+}
+
 // threads are assigned a monotonically incrementing integer ID. This function
 // increments the counter and returns its value as the integer ID to use
 func IncrementThreadNumber() int {
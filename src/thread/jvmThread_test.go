@@ -7,6 +7,7 @@
 package thread
 
 import (
+	"errors"
 	"jacobin/src/globals"
 	"sync"
 	"testing"
@@ -91,3 +92,50 @@ func addThreadsToTable(numThreads int) {
 		th.AddThreadToTable(glob)
 	}
 }
+
+func TestInheritThreadLocals(t *testing.T) {
+	parent := CreateThread()
+	child := CreateThread()
+	_ = child.InheritThreadLocals(&parent, nil)
+	if child.InheritableLocals != nil {
+		t.Error("Expected no inheritable locals when the parent has none")
+	}
+
+	key := "tl"
+	parent.InheritableLocals = map[any]any{key: int64(42)}
+	parent.ThreadLocals = map[any]any{key: int64(7)}
+	_ = child.InheritThreadLocals(&parent, nil)
+	if child.InheritableLocals[key] != int64(42) {
+		t.Errorf("Expected inherited value 42, got %v", child.InheritableLocals[key])
+	}
+	if child.ThreadLocals != nil {
+		t.Error("Ordinary ThreadLocals must not be inherited")
+	}
+
+	child.InheritableLocals[key] = int64(43)
+	if parent.InheritableLocals[key] != int64(42) {
+		t.Error("A set() in the child should not be visible in the parent")
+	}
+}
+
+func TestInheritThreadLocalsChildValue(t *testing.T) {
+	parent := CreateThread()
+	child := CreateThread()
+	parent.InheritableLocals = map[any]any{"tl": int64(42)}
+
+	double := func(_, v any) (any, error) { return v.(int64) * 2, nil }
+	if err := child.InheritThreadLocals(&parent, double); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if child.InheritableLocals["tl"] != int64(84) {
+		t.Errorf("Expected the child value 84, got %v", child.InheritableLocals["tl"])
+	}
+	if parent.InheritableLocals["tl"] != int64(42) {
+		t.Errorf("Expected the parent to keep 42, got %v", parent.InheritableLocals["tl"])
+	}
+
+	fail := func(_, _ any) (any, error) { return nil, errors.New("childValue failed") }
+	if err := child.InheritThreadLocals(&parent, fail); err == nil {
+		t.Error("Expected the error from childValue to be returned")
+	}
+}
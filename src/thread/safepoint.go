@@ -0,0 +1,207 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package thread
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Safepoints. Some operations of the VM--a thread dump, a heap dump, and in future
+// the redefinition of classes or the moving of objects--need all the Java threads to
+// be stopped where their frame stacks are consistent. The VM gets them there
+// cooperatively: StopTheWorld() sets SafepointRequested, which the interpreter polls
+// at method entry and at backward branches, and each thread that sees it calls
+// SafepointPoll(), which parks it until the operation is done. A thread that is
+// blocked (in WaitInterruptibly(), and so in sleep(), join(), lock waits, and the
+// like) is already at a safepoint, as it can't change its frame stack; if it wakes
+// while an operation is under way, it parks before returning to Java code.
+//
+// Threads are registered while they run Java code (see jvm.runThread()). If one of
+// them fails to reach a safepoint within the timeout--because it's stuck in a long
+// gfunction, say--the operation is abandoned and the error describes the threads
+// that did not stop.
+
+// SafepointRequested is set while the VM is stopping the threads for an operation.
+// The interpreter polls it and calls SafepointPoll() when it's set.
+var SafepointRequested atomic.Bool
+
+// the states of a thread with respect to safepoints
+const (
+	safepointRunning = iota // running Java code
+	safepointBlocked        // blocked, and so at a safepoint
+	safepointStopped        // parked in SafepointPoll()
+)
+
+type safepointThread struct {
+	state int
+	where string // where the thread last stopped at a safepoint, for the diagnostics
+}
+
+// the registered threads and the state of the present operation
+var safepoints = struct {
+	sync.Mutex
+	changed   *sync.Cond
+	threads   map[int]*safepointThread
+	operation string // the operation the threads are stopped for, if any
+	requester int    // the thread that requested the operation, which doesn't stop
+}{threads: make(map[int]*safepointThread)}
+
+func init() {
+	safepoints.changed = sync.NewCond(&safepoints.Mutex)
+}
+
+// RegisterForSafepoints records that the thread is running Java code and must reach
+// a safepoint before a VM operation proceeds
+func RegisterForSafepoints(threadID int) {
+	safepoints.Lock()
+	safepoints.threads[threadID] = &safepointThread{state: safepointRunning}
+	safepoints.Unlock()
+}
+
+// UnregisterFromSafepoints records that the thread no longer runs Java code, because
+// it has ended or, for a green thread, yielded its worker
+func UnregisterFromSafepoints(threadID int) {
+	safepoints.Lock()
+	delete(safepoints.threads, threadID)
+	safepoints.changed.Broadcast()
+	safepoints.Unlock()
+}
+
+// SafepointPoll parks the thread until the VM operation under way is done. where
+// describes the thread's position, e.g. the method and PC, for the diagnostics.
+func SafepointPoll(threadID int, where string) {
+	safepoints.Lock()
+	defer safepoints.Unlock()
+	t := safepoints.threads[threadID]
+	if t == nil || safepoints.operation == "" || threadID == safepoints.requester {
+		return
+	}
+	t.state, t.where = safepointStopped, where
+	safepoints.changed.Broadcast()
+	for safepoints.operation != "" {
+		safepoints.changed.Wait()
+	}
+	t.state = safepointRunning
+}
+
+// BeginBlocking records that the thread is about to block, during which it's at a
+// safepoint. It must be followed by EndBlocking().
+func BeginBlocking(threadID int) {
+	safepoints.Lock()
+	if t := safepoints.threads[threadID]; t != nil {
+		t.state = safepointBlocked
+		safepoints.changed.Broadcast()
+	}
+	safepoints.Unlock()
+}
+
+// EndBlocking records that the thread has stopped blocking. If a VM operation is
+// under way, the thread waits until it's done.
+func EndBlocking(threadID int) {
+	safepoints.Lock()
+	defer safepoints.Unlock()
+	t := safepoints.threads[threadID]
+	if t == nil {
+		return
+	}
+	for safepoints.operation != "" && threadID != safepoints.requester {
+		safepoints.changed.Wait()
+	}
+	t.state = safepointRunning
+}
+
+// StopTheWorld brings all the registered threads other than the requester (which
+// is 0 if the requester isn't a Java thread) to a safepoint for the named operation.
+// If they are all stopped within the timeout, it returns nil, and the caller must
+// call ResumeTheWorld() when the operation is done. Otherwise, the threads are
+// released and the error describes those that did not reach a safepoint. Only one
+// operation runs at a time; a second waits for the first to finish.
+func StopTheWorld(requester int, operation string, timeout time.Duration) error {
+	safepoints.Lock()
+	defer safepoints.Unlock()
+	self := safepoints.threads[requester]
+	for safepoints.operation != "" { // the requester is at a safepoint while it waits
+		if self != nil {
+			self.state = safepointStopped
+			safepoints.changed.Broadcast()
+		}
+		safepoints.changed.Wait()
+	}
+	if self != nil {
+		self.state = safepointRunning
+	}
+	safepoints.operation, safepoints.requester = operation, requester
+	SafepointRequested.Store(true)
+
+	timedOut := false
+	timer := time.AfterFunc(timeout, func() {
+		safepoints.Lock()
+		timedOut = true
+		safepoints.changed.Broadcast()
+		safepoints.Unlock()
+	})
+	defer timer.Stop()
+
+	for {
+		running := runningThreads()
+		if len(running) == 0 {
+			return nil
+		}
+		if timedOut {
+			err := safepointTimeoutError(operation, timeout, running)
+			releaseLocked()
+			return err
+		}
+		safepoints.changed.Wait()
+	}
+}
+
+// ResumeTheWorld ends the VM operation begun by StopTheWorld(), releasing the threads
+func ResumeTheWorld() {
+	safepoints.Lock()
+	releaseLocked()
+	safepoints.Unlock()
+}
+
+// releases the stopped threads. Call with safepoints locked.
+func releaseLocked() {
+	safepoints.operation, safepoints.requester = "", 0
+	SafepointRequested.Store(false)
+	safepoints.changed.Broadcast()
+}
+
+// returns the IDs of the registered threads, other than the requester, that are not
+// at a safepoint, in order. Call with safepoints locked.
+func runningThreads() []int {
+	var running []int
+	for id, t := range safepoints.threads {
+		if t.state == safepointRunning && id != safepoints.requester {
+			running = append(running, id)
+		}
+	}
+	sort.Ints(running)
+	return running
+}
+
+// describes the threads that did not reach a safepoint. Call with safepoints locked.
+func safepointTimeoutError(operation string, timeout time.Duration, running []int) error {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("timed out after %v waiting for threads to reach a safepoint for %s:",
+		timeout, operation))
+	for _, id := range running {
+		sb.WriteString(fmt.Sprintf("\n  \"%s\" (thread %d) is still running", ThreadName(id), id))
+		if where := safepoints.threads[id].where; where != "" {
+			sb.WriteString("; it last stopped at a safepoint in " + where)
+		}
+	}
+	return fmt.Errorf("%s", sb.String())
+}
@@ -0,0 +1,159 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package thread
+
+import (
+	"jacobin/src/globals"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// clears the registered threads, so that tests don't see each other's threads
+func resetSafepoints() {
+	safepoints.Lock()
+	safepoints.threads = make(map[int]*safepointThread)
+	safepoints.Unlock()
+}
+
+// starts a goroutine that runs "Java code" for the thread, polling for safepoints as
+// the interpreter does, until stop is closed. It counts its iterations in progress.
+func startPolling(id int, progress *atomic.Int64, stop chan struct{}) {
+	RegisterForSafepoints(id)
+	go func() {
+		defer UnregisterFromSafepoints(id)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if SafepointRequested.Load() {
+				SafepointPoll(id, "Test.loop()V at PC 4")
+			}
+			progress.Add(1)
+		}
+	}()
+}
+
+func TestStopAndResumeTheWorld(t *testing.T) {
+	resetSafepoints()
+	defer resetSafepoints()
+
+	var progress atomic.Int64
+	stop := make(chan struct{})
+	defer close(stop)
+	startPolling(2, &progress, stop)
+	startPolling(3, &progress, stop)
+
+	if err := StopTheWorld(0, "a test", time.Second); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	stopped := progress.Load()
+	time.Sleep(20 * time.Millisecond)
+	if progress.Load() != stopped {
+		t.Error("Expected the threads to make no progress while they are stopped")
+	}
+	ResumeTheWorld()
+
+	deadline := time.Now().Add(time.Second)
+	for progress.Load() == stopped {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the threads to run again once the world was resumed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if SafepointRequested.Load() {
+		t.Error("Expected no safepoint to be requested once the world was resumed")
+	}
+}
+
+func TestBlockedThreadIsAtSafepoint(t *testing.T) {
+	resetSafepoints()
+	defer resetSafepoints()
+
+	RegisterForSafepoints(2)
+	BeginBlocking(2)
+	if err := StopTheWorld(0, "a test", time.Second); err != nil {
+		t.Fatalf("Expected a blocked thread to be at a safepoint, got: %v", err)
+	}
+
+	// the thread wakes during the operation, so it must wait until it's done
+	woke := make(chan struct{})
+	go func() {
+		EndBlocking(2)
+		close(woke)
+	}()
+	select {
+	case <-woke:
+		t.Fatal("Expected a thread that stops blocking to wait for the operation")
+	case <-time.After(20 * time.Millisecond):
+	}
+	ResumeTheWorld()
+	select {
+	case <-woke:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the thread to continue once the world was resumed")
+	}
+}
+
+func TestStopTheWorldTimesOut(t *testing.T) {
+	globals.InitGlobals("test")
+	resetSafepoints()
+	defer resetSafepoints()
+
+	RegisterForSafepoints(1) // never polls, as if stuck in a gfunction
+	RegisterForSafepoints(2) // the requester
+	err := StopTheWorld(2, "a thread dump", 20*time.Millisecond)
+	if err == nil {
+		t.Fatal("Expected a timeout, as thread 1 never reached a safepoint")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "safepoint for a thread dump") || !strings.Contains(msg, "\"main\" (thread 1) is still running") {
+		t.Errorf("Expected the diagnostics to name the operation and thread 1, got: %s", msg)
+	}
+	if strings.Contains(msg, "thread 2") {
+		t.Errorf("Did not expect the requester among the threads that did not stop, got: %s", msg)
+	}
+	if SafepointRequested.Load() {
+		t.Error("Expected the safepoint request to be withdrawn after a timeout")
+	}
+}
+
+func TestSecondOperationWaitsForFirst(t *testing.T) {
+	resetSafepoints()
+	defer resetSafepoints()
+
+	RegisterForSafepoints(2)
+	RegisterForSafepoints(3)
+	BeginBlocking(3)
+	if err := StopTheWorld(2, "the first operation", time.Second); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// thread 3 wakes and requests an operation of its own while the first is under way
+	second := make(chan error)
+	go func() { second <- StopTheWorld(3, "the second operation", time.Second) }()
+	select {
+	case <-second:
+		t.Fatal("Expected the second operation to wait for the first")
+	case <-time.After(20 * time.Millisecond):
+	}
+	ResumeTheWorld()
+	BeginBlocking(2) // the requester of the first operation goes on to block
+
+	select {
+	case err := <-second:
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		ResumeTheWorld()
+	case <-time.After(time.Second):
+		t.Fatal("Expected the second operation to proceed once the first was done")
+	}
+}
@@ -6,13 +6,15 @@
 
 package trace
 
-// The principal logging function. Note it currently logs to stderr.
-// At some future point, might allow the user to specify where logging should go.
+// The principal logging function. It logs to stderr, unless another writer was
+// set by globals.SetTraceWriter (which tests do to capture the messages).
 import (
+	"encoding/json"
 	"fmt"
 	"jacobin/src/excNames"
 	"jacobin/src/globals"
-	"os"
+	"runtime"
+	"strings"
 	"sync"
 	"time"
 )
@@ -28,10 +30,66 @@ const UNKNOWN_ERROR = 5
 
 var disabled = false
 
+// Level is the severity of a log message. A subsystem logs only the messages
+// at or above its level (see SetLevel).
+type Level int
+
+const (
+	LevelTrace Level = iota
+	LevelInfo
+	LevelWarning
+	LevelError
+	LevelOff
+)
+
+var levelNames = []string{"trace", "info", "warning", "error", "off"}
+
+func (l Level) String() string {
+	if l < LevelTrace || l > LevelOff {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel returns the Level with the given name, which is not case sensitive.
+func ParseLevel(name string) (Level, error) {
+	for i, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return Level(i), nil
+		}
+	}
+	return LevelOff, fmt.Errorf("unknown log level: %s", name)
+}
+
+// The subsystems that log messages. A message's subsystem is the Jacobin package
+// it's logged from (so the messages logged in jacobin/src/classloader are from
+// the classloader subsystem), unless the caller of Log specifies it. Any package
+// name can be used as a subsystem; these are the ones most often configured.
+const (
+	Classloader = "classloader"
+	JVM         = "jvm"
+	Gfunction   = "gfunction"
+	Java        = "java"    // messages the Java program logs through java.util.logging
+	General     = "jacobin" // messages logged outside the Jacobin packages
+)
+
+// NoThread is passed to Log for messages that aren't about a particular thread.
+const NoThread = -1
+
+// the logging configuration, set from the -log option
+var (
+	jsonOutput   bool
+	defaultLevel = LevelTrace
+	levels       = map[string]Level{}
+)
+
 // Initialize the trace frame.
 func Init() {
 	StartTime = time.Now()
 	disabled = false
+	jsonOutput = false
+	defaultLevel = LevelTrace
+	levels = map[string]Level{}
 }
 
 // Disable the trace function. This is useful primarily in testing.
@@ -39,63 +97,154 @@ func Disable() {
 	disabled = true
 }
 
-// Trace is the principal tracing function. Note that it currently
-// writes to stderr. At some future point, this might become an option.
-func Trace(argMsg string) {
-	var err error
+// SetJSON selects the output format: JSON lines (one JSON object per message) if
+// enabled, otherwise the traditional text format.
+func SetJSON(enabled bool) {
+	mutex.Lock()
+	jsonOutput = enabled
+	mutex.Unlock()
+}
 
-	if disabled {
-		return
+// SetLevel sets the minimum level of the messages logged by a subsystem. An empty
+// subsystem sets the level of all the subsystems that don't have one of their own.
+func SetLevel(subsystem string, level Level) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if subsystem == "" {
+		defaultLevel = level
+	} else {
+		levels[subsystem] = level
 	}
-	// if the message is more low-level than a WARNING,
-	// prefix it with the elapsed time in millisecs.
-	// check duration accuracy: time.Sleep(100 * time.Millisecond)
-	duration := time.Since(StartTime)
-	var millis = duration.Milliseconds()
+}
 
-	// Lock access to the logging stream to prevent inter-thread overwrite issues
+// Enabled reports whether a message of the given level from the given subsystem
+// would be logged. Code that builds expensive messages can test this first.
+func Enabled(subsystem string, level Level) bool {
 	mutex.Lock()
-	_, err = fmt.Fprintf(os.Stderr, "[%3d.%03ds] %s\n", millis/1000, millis%1000, argMsg)
-	mutex.Unlock()
-	if err != nil {
-		errMsg := fmt.Sprintf("Trace: *** stderr failed, err: %v", err)
-		rawAbort(excNames.IOError, errMsg)
+	defer mutex.Unlock()
+	return !disabled && level >= levelFor(subsystem)
+}
+
+// levelFor returns the level of a subsystem. The mutex must be held.
+func levelFor(subsystem string) Level {
+	if level, ok := levels[subsystem]; ok {
+		return level
 	}
+	return defaultLevel
+}
+
+// Trace is the principal tracing function. It writes to stderr, unless another
+// writer was set by globals.SetTraceWriter.
+func Trace(argMsg string) {
+	emit("", LevelTrace, NoThread, argMsg)
 }
 
 // An error message is a prefix-decorated message that has no time-stamp.
 func Error(argMsg string) {
-	if disabled {
-		return
-	}
-
-	var err error
-	errMsg := "ERROR: " + argMsg
-	mutex.Lock()
-	_, err = fmt.Fprintf(os.Stderr, "%s\n", errMsg)
-	mutex.Unlock()
-	if err != nil {
-		errMsg = fmt.Sprintf("Error: *** stderr failed, err: %v", err)
-		rawAbort(excNames.IOError, errMsg)
-	}
+	emit("", LevelError, NoThread, argMsg)
 }
 
 // Similar to Error, except it's a warning, not an error.
 func Warning(argMsg string) {
+	emit("", LevelWarning, NoThread, argMsg)
+}
+
+// Log logs a message of the given level for the given subsystem. If the message
+// concerns a particular Java thread, threadID is its ID, else NoThread. An empty
+// subsystem means the package of the calling function.
+func Log(subsystem string, level Level, threadID int, msg string) {
+	emit(subsystem, level, threadID, msg)
+}
+
+// the fields of a message in JSON lines format
+type jsonEntry struct {
+	Time      string `json:"time"`
+	ElapsedMs int64  `json:"elapsedMs"`
+	Level     string `json:"level"`
+	Subsystem string `json:"subsystem"`
+	Thread    *int   `json:"thread,omitempty"`
+	Msg       string `json:"msg"`
+}
+
+// emit writes a message in the configured format to the trace writer (stderr, unless
+// globals.SetTraceWriter was called), if its level is enabled for its subsystem. It's
+// called only by the exported logging functions, which is what allows it to find the
+// package of the code that logged the message.
+func emit(subsystem string, level Level, threadID int, msg string) {
 	if disabled {
 		return
 	}
 
-	errMsg := "WARNING: " + argMsg
+	now := time.Now()
+	millis := now.Sub(StartTime).Milliseconds()
+
+	// Lock access to the logging stream to prevent inter-thread overwrite issues
 	mutex.Lock()
-	_, err := fmt.Fprintf(os.Stderr, "%s\n", errMsg)
+	if subsystem == "" && (jsonOutput || len(levels) > 0) {
+		subsystem = callerSubsystem()
+	}
+	if level < levelFor(subsystem) {
+		mutex.Unlock()
+		return
+	}
+
+	var err error
+	out := globals.GetTraceWriter()
+	if jsonOutput {
+		entry := jsonEntry{
+			Time:      now.Format(time.RFC3339Nano),
+			ElapsedMs: millis,
+			Level:     level.String(),
+			Subsystem: subsystem,
+			Msg:       msg,
+		}
+		if threadID != NoThread {
+			entry.Thread = &threadID
+		}
+		line, _ := json.Marshal(entry) // marshaling strings and ints can't fail
+		_, err = fmt.Fprintf(out, "%s\n", line)
+	} else {
+		if threadID != NoThread {
+			msg = fmt.Sprintf("[thread %d] %s", threadID, msg)
+		}
+		switch level {
+		case LevelError:
+			_, err = fmt.Fprintf(out, "ERROR: %s\n", msg)
+		case LevelWarning:
+			_, err = fmt.Fprintf(out, "WARNING: %s\n", msg)
+		default: // lower-level messages are prefixed with the elapsed time in millisecs.
+			_, err = fmt.Fprintf(out, "[%3d.%03ds] %s\n", millis/1000, millis%1000, msg)
+		}
+	}
 	mutex.Unlock()
+
 	if err != nil {
-		errMsg = fmt.Sprintf("Error: *** stderr failed, err: %v", err)
+		errMsg := fmt.Sprintf("Trace: *** write failed, err: %v", err)
 		rawAbort(excNames.IOError, errMsg)
 	}
 }
 
+// callerSubsystem returns the name of the Jacobin package that called the
+// exported logging function, e.g. "classloader" for jacobin/src/classloader.
+func callerSubsystem() string {
+	pc, _, _, ok := runtime.Caller(3) // callerSubsystem <- emit <- Trace et al. <- caller
+	if !ok {
+		return General
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return General
+	}
+	pkg, found := strings.CutPrefix(fn.Name(), "jacobin/src/")
+	if !found {
+		return General
+	}
+	if end := strings.IndexAny(pkg, "./"); end != -1 {
+		pkg = pkg[:end]
+	}
+	return pkg
+}
+
 // Perform a minimal abort, which is a direct call to the global minimal abort function.
 // Clearly, if trace is not working, then something is grievously wrong and the abort
 // must be immediate.
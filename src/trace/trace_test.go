@@ -7,12 +7,14 @@
 package trace
 
 import (
+	"encoding/json"
 	"io"
 	"jacobin/src/globals"
 	"os"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 )
 
 func initialize() {
@@ -150,3 +152,104 @@ func TestTracePrefixContainsTimestamp(t *testing.T) {
 		}
 	}
 }
+
+func TestParseLevel(t *testing.T) {
+	for _, name := range []string{"trace", "INFO", "Warning", "error", "off"} {
+		level, err := ParseLevel(name)
+		if err != nil || !strings.EqualFold(level.String(), name) {
+			t.Errorf("ParseLevel(%q) returned %v, %v", name, level, err)
+		}
+	}
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
+
+func TestSubsystemLevels(t *testing.T) {
+	initialize()
+	defer Init()
+
+	SetLevel("", LevelWarning)
+	SetLevel(Classloader, LevelTrace)
+
+	saved := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	Log(Classloader, LevelTrace, NoThread, "loading class")
+	Log(JVM, LevelInfo, NoThread, "dropped")
+	Trace("dropped too") // logged from the trace package, which has the default level
+	Log(JVM, LevelWarning, 1, "low on frames")
+	_ = w.Close()
+	os.Stderr = saved
+
+	out, _ := io.ReadAll(r)
+	outString := string(out)
+	if !strings.Contains(outString, "loading class") {
+		t.Errorf("Expected the classloader message, got: %q", outString)
+	}
+	if strings.Contains(outString, "dropped") {
+		t.Errorf("Expected messages below the level to be dropped, got: %q", outString)
+	}
+	if !strings.Contains(outString, "WARNING: [thread 1] low on frames") {
+		t.Errorf("Expected the warning with its thread ID, got: %q", outString)
+	}
+}
+
+func TestJSONOutput(t *testing.T) {
+	initialize()
+	defer Init()
+	SetJSON(true)
+
+	saved := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	Log(Gfunction, LevelInfo, 3, `say "hi"`)
+	Warning("no thread")
+	_ = w.Close()
+	os.Stderr = saved
+
+	out, _ := io.ReadAll(r)
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 JSON lines, got: %q", string(out))
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Invalid JSON line %q: %v", lines[0], err)
+	}
+	if entry["level"] != "info" || entry["subsystem"] != Gfunction ||
+		entry["thread"] != float64(3) || entry["msg"] != `say "hi"` {
+		t.Errorf("Unexpected JSON entry: %v", entry)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, entry["time"].(string)); err != nil {
+		t.Errorf("Expected an RFC 3339 timestamp, got: %v", entry["time"])
+	}
+
+	entry = nil
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatalf("Invalid JSON line %q: %v", lines[1], err)
+	}
+	if _, hasThread := entry["thread"]; hasThread || entry["subsystem"] != "trace" || entry["level"] != "warning" {
+		t.Errorf("Unexpected JSON entry: %v", entry)
+	}
+}
+
+func TestTraceWriter(t *testing.T) {
+	initialize()
+
+	var out strings.Builder
+	globals.SetTraceWriter(&out)
+	Error("captured")
+	Trace("also captured")
+	globals.SetTraceWriter(nil)
+
+	if !strings.Contains(out.String(), "ERROR: captured\n") || !strings.Contains(out.String(), "] also captured\n") {
+		t.Errorf("Expected the messages in the trace writer, got: %q", out.String())
+	}
+	if globals.GetTraceWriter() != os.Stderr {
+		t.Error("Expected SetTraceWriter(nil) to restore stderr")
+	}
+}
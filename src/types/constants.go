@@ -13,6 +13,7 @@ const NoClInit byte = 0x00
 const ClInitNotRun byte = 0x01
 const ClInitInProgress byte = 0x02
 const ClInitRun byte = 0x03
+const ClInitError byte = 0x04 // <clinit> failed, so the class is erroneous
 
 // ---- invalid index into string pool ----
 const InvalidStringIndex uint32 = 0xffffffff
@@ -30,5 +31,8 @@ var StringPoolStringIndex = uint32(1) // points to the string pool slice for "ja
 var EmptyString = ""
 var NullString = "null"
 
+// Constants related to "java/lang/Class":
+var ClassClassName = "java/lang/Class"
+
 // ---- experimental values ----
 var StackInflator = 2 // for toying with whether to increase # of stack entries
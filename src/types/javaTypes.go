@@ -60,10 +60,27 @@ const GolangString = "G"
 // Field types created and used in gfunctions
 const BigInteger = "*BI" // The related Fvalue is a Golang *big.Int
 const BigDecimal = "*BD"
-const FileHandle = "*FH" // The related Fvalue is a Golang *os.File
-const HashMap = "*HM"    // The related Fvalue is a Golang map[interface{}]interface{}
-const LinkedList = "*LL" // The related Fvalue is a Golang *list.List
-const Properties = "*PT" // The related Fvalue is a Golang map[interface{}]interface{}
+const BufferState = "*BB"    // The related Fvalue is the Golang state of a java/nio/ByteBuffer
+const ChannelState = "*CH"   // The related Fvalue is the Golang state of a java/nio/channels/FileChannel
+const CipherState = "*CS"    // The related Fvalue is the Golang state of a javax/crypto/Cipher
+const DequeState = "*DQ"     // The related Fvalue is the Golang state of a java/util/ArrayDeque or of one of its iterators
+const FileHandle = "*FH"     // The related Fvalue is a Golang *os.File
+const FormatState = "*FS"    // The related Fvalue is the Golang state of a java/util/Formatter or a java/text/DecimalFormat
+const HashMap = "*HM"        // The related Fvalue is a Golang map[interface{}]interface{}
+const ImmutableList = "*IL"  // The related Fvalue is the Golang state of an immutable List, such as List.of() returns, or of its iterator
+const JoinerState = "*SJ"    // The related Fvalue is the Golang state of a java/util/StringJoiner
+const LinkedHash = "*LH"     // The related Fvalue is the Golang state of a java/util/LinkedHashMap or LinkedHashSet, or of one of their views or iterators
+const LinkedList = "*LL"     // The related Fvalue is a Golang *list.List
+const MapEntry = "*ME"       // The related Fvalue is the Golang state of a Map.Entry of a map implemented in Go
+const MessageDigest = "*MD"  // The related Fvalue is a Golang hash.Hash
+const ProcessState = "*PR"   // The related Fvalue is the Golang state of a java/lang/Process
+const Properties = "*PT"     // The related Fvalue is a Golang map[interface{}]interface{}
+const SchedulerState = "*SS" // The related Fvalue is the Golang state of a Timer, an executor, or one of their tasks
+const SyncState = "*SY"      // The related Fvalue is the Golang state of a lock, a Condition, a CountDownLatch, or a Semaphore
+const TreeState = "*TR"      // The related Fvalue is the Golang state of a java/util/TreeMap or TreeSet, or of one of their views or iterators
+const Unmodifiable = "*UM"   // The related Fvalue is the Golang state of an unmodifiable view from java/util/Collections, or of its iterator
+const WatchState = "*WS"     // The related Fvalue is the Golang state of a java/nio/file WatchService, WatchKey, or WatchEvent
+const ZipState = "*ZS"       // The related Fvalue is the Golang state of a java/util/zip object
 
 func IsIntegral(t string) bool {
 	if t == Byte || t == Char || t == Int ||
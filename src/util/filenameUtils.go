@@ -37,9 +37,9 @@ func ConvertInternalClassNameToUserFormat(fName string) string {
 
 // ConvertToPlatformPathSeparators accepts a file path and,
 // if necessary, converts the filepath separator characters
-// to those used on the runtime platform
+// to those used on the runtime platform (see GetPlatform)
 func ConvertToPlatformPathSeparators(pathIn string) string {
-	osps := os.PathSeparator
+	osps := GetPlatform().PathSeparator
 	if strings.ContainsRune(pathIn, '/') && osps != '/' {
 		return strings.ReplaceAll(pathIn, "/", string(osps))
 	}
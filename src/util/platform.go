@@ -0,0 +1,84 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package util
+
+import (
+	"os"
+	"strings"
+)
+
+// The path conventions of the platform Jacobin runs on: the character that separates
+// the directories in a path and the one that separates the paths in a list, such as
+// a classpath. Code that builds or splits paths consults the platform here, rather
+// than os.PathSeparator and os.PathListSeparator, so that Jacobin handles paths the
+// same way everywhere, and so that tests can run under either the Windows or the
+// POSIX conventions, whatever the OS they run on (see SetPlatform).
+
+// Platform holds the path conventions of a family of operating systems
+type Platform struct {
+	Name              string
+	PathSeparator     byte // separates the directories in a path
+	PathListSeparator byte // separates the paths in a list, such as a classpath
+}
+
+var (
+	Windows = Platform{Name: "windows", PathSeparator: '\\', PathListSeparator: ';'}
+	Posix   = Platform{Name: "posix", PathSeparator: '/', PathListSeparator: ':'}
+)
+
+var platform = hostPlatform()
+
+// returns the platform of the OS Jacobin is running on
+func hostPlatform() Platform {
+	if os.PathSeparator == '\\' {
+		return Windows
+	}
+	return Posix
+}
+
+// GetPlatform returns the platform whose path conventions are in use
+func GetPlatform() Platform {
+	return platform
+}
+
+// SetPlatform puts the path conventions of p in use and returns the platform
+// that was in use. It's intended for tests, which restore the previous platform
+// when they're done.
+func SetPlatform(p Platform) Platform {
+	previous := platform
+	platform = p
+	return previous
+}
+
+// Separator returns the path separator as a string
+func (p Platform) Separator() string {
+	return string(p.PathSeparator)
+}
+
+// ListSeparator returns the path-list separator as a string
+func (p Platform) ListSeparator() string {
+	return string(p.PathListSeparator)
+}
+
+// JoinPath joins the elements of a path with the path separator, without doubling
+// the separator when an element already ends with it. Unlike filepath.Join, it
+// doesn't otherwise clean the path.
+func (p Platform) JoinPath(elem ...string) string {
+	var path strings.Builder
+	for i, e := range elem {
+		if i > 0 && !strings.HasSuffix(path.String(), p.Separator()) {
+			path.WriteByte(p.PathSeparator)
+		}
+		path.WriteString(e)
+	}
+	return path.String()
+}
+
+// SplitPathList splits a list of paths, such as a classpath, into its paths
+func (p Platform) SplitPathList(list string) []string {
+	return strings.Split(list, p.ListSeparator())
+}
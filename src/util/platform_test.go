@@ -0,0 +1,63 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package util
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestHostPlatform(t *testing.T) {
+	host := GetPlatform()
+	if host.PathSeparator != os.PathSeparator || host.PathListSeparator != os.PathListSeparator {
+		t.Errorf("Expected the host's separators, got: %c and %c", host.PathSeparator, host.PathListSeparator)
+	}
+}
+
+func TestPlatformPathsOnWindows(t *testing.T) {
+	defer SetPlatform(SetPlatform(Windows))
+
+	if path := GetPlatform().JoinPath(`C:\jdk`, "jmods", "java.base.jmod"); path != `C:\jdk\jmods\java.base.jmod` {
+		t.Errorf("Unexpected joined path: %s", path)
+	}
+	if path := GetPlatform().JoinPath(`C:\jdk\`, "release"); path != `C:\jdk\release` {
+		t.Errorf("Expected no doubled separator, got: %s", path)
+	}
+
+	paths := GetPlatform().SplitPathList(`C:\lib\a.jar;D:\classes`)
+	if !slices.Equal(paths, []string{`C:\lib\a.jar`, `D:\classes`}) {
+		t.Errorf("Unexpected classpath elements: %q", paths)
+	}
+
+	if path := ConvertToPlatformPathSeparators("java/lang/String.class"); path != `java\lang\String.class` {
+		t.Errorf("Unexpected converted path: %s", path)
+	}
+	if name := ConvertInternalClassNameToFilename("java/lang/String"); name != `java\lang\String.class` {
+		t.Errorf("Unexpected filename: %s", name)
+	}
+}
+
+func TestPlatformPathsOnPosix(t *testing.T) {
+	defer SetPlatform(SetPlatform(Posix))
+
+	if path := GetPlatform().JoinPath("/usr/jdk", "jmods", "java.base.jmod"); path != "/usr/jdk/jmods/java.base.jmod" {
+		t.Errorf("Unexpected joined path: %s", path)
+	}
+	if path := GetPlatform().JoinPath("/usr/jdk/", "release"); path != "/usr/jdk/release" {
+		t.Errorf("Expected no doubled separator, got: %s", path)
+	}
+
+	paths := GetPlatform().SplitPathList("/lib/a.jar:/classes")
+	if !slices.Equal(paths, []string{"/lib/a.jar", "/classes"}) {
+		t.Errorf("Unexpected classpath elements: %q", paths)
+	}
+
+	if path := ConvertToPlatformPathSeparators(`java\lang\String.class`); path != "java/lang/String.class" {
+		t.Errorf("Unexpected converted path: %s", path)
+	}
+}
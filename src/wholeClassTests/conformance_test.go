@@ -0,0 +1,45 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2025 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package wholeClassTests
+
+import (
+	"jacobin/src/selftest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Runs the conformance suite (see selftest/selftest.go) with the Jacobin executable
+// given in JACOBIN_EXE. The suite is in testdata/conformance unless another directory
+// is given in JACOBIN_CONFORMANCE.
+func TestConformanceSuite(t *testing.T) {
+	if testing.Short() { // don't run if running quick tests only. (Used primarily so GitHub doesn't run and bork)
+		t.Skip()
+	}
+
+	jacobin := os.Getenv("JACOBIN_EXE")
+	if jacobin == "" {
+		t.Skip("the conformance suite requires the Jacobin executable to be specified in JACOBIN_EXE")
+	}
+	dir := os.Getenv("JACOBIN_CONFORMANCE")
+	if dir == "" {
+		dir = filepath.Join("..", "..", "testdata", "conformance")
+	}
+
+	var report strings.Builder
+	results, err := selftest.Run(dir, []string{jacobin}, &report)
+	if err != nil {
+		t.Fatalf("Cannot run the conformance suite: %v", err)
+	}
+	for _, result := range results {
+		if !result.Passed() {
+			t.Errorf("%s failed:\n    %s", result.Name, strings.Join(result.Diffs, "\n    "))
+		}
+	}
+	t.Log(report.String())
+}
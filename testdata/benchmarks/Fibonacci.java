@@ -0,0 +1,18 @@
+/*
+ * Jacobin VM benchmark: recursive method invocation and integer arithmetic.
+ * One operation computes fib(20) recursively. The argument is the number of operations.
+ */
+public class Fibonacci {
+    static int fib(int n) {
+        return n < 2 ? n : fib(n - 1) + fib(n - 2);
+    }
+
+    public static void main(String[] args) {
+        int ops = Integer.parseInt(args[0]);
+        long check = 0;
+        for (int i = 0; i < ops; i++) {
+            check += fib(20);
+        }
+        System.out.println(check);
+    }
+}
@@ -0,0 +1,32 @@
+import java.util.HashMap;
+
+/*
+ * Jacobin VM benchmark: collections, boxing, and allocation.
+ * One operation puts 100 entries in a HashMap, looks each up, and removes every other one.
+ * The argument is the number of operations.
+ */
+public class MapChurn {
+    static int churn() {
+        HashMap<Integer, Integer> map = new HashMap<>();
+        for (int i = 0; i < 100; i++) {
+            map.put(i, i * 2);
+        }
+        int sum = 0;
+        for (int i = 0; i < 100; i++) {
+            sum += map.get(i);
+        }
+        for (int i = 0; i < 100; i += 2) {
+            map.remove(i);
+        }
+        return sum + map.size();
+    }
+
+    public static void main(String[] args) {
+        int ops = Integer.parseInt(args[0]);
+        long check = 0;
+        for (int i = 0; i < ops; i++) {
+            check += churn();
+        }
+        System.out.println(check);
+    }
+}
@@ -0,0 +1,46 @@
+/*
+ * Jacobin VM benchmark: virtual and interface method dispatch.
+ * One operation makes 1,000 calls through an interface to three implementations.
+ * The argument is the number of operations.
+ */
+public class MethodDispatch {
+    interface Shape {
+        int area();
+    }
+
+    static class Square implements Shape {
+        final int side;
+        Square(int side) { this.side = side; }
+        public int area() { return side * side; }
+    }
+
+    static class Rectangle implements Shape {
+        final int width, height;
+        Rectangle(int width, int height) { this.width = width; this.height = height; }
+        public int area() { return width * height; }
+    }
+
+    static class Triangle implements Shape {
+        final int base, height;
+        Triangle(int base, int height) { this.base = base; this.height = height; }
+        public int area() { return base * height / 2; }
+    }
+
+    static int dispatch(Shape[] shapes) {
+        int total = 0;
+        for (int i = 0; i < 1000; i++) {
+            total += shapes[i % shapes.length].area();
+        }
+        return total;
+    }
+
+    public static void main(String[] args) {
+        int ops = Integer.parseInt(args[0]);
+        Shape[] shapes = { new Square(3), new Rectangle(2, 5), new Triangle(4, 6) };
+        long check = 0;
+        for (int i = 0; i < ops; i++) {
+            check += dispatch(shapes);
+        }
+        System.out.println(check);
+    }
+}
@@ -0,0 +1,29 @@
+/*
+ * Jacobin VM benchmark: array access and loops.
+ * One operation finds the primes below 10,000 with the sieve of Eratosthenes.
+ * The argument is the number of operations.
+ */
+public class Sieve {
+    static int sieve(int limit) {
+        boolean[] composite = new boolean[limit];
+        int count = 0;
+        for (int i = 2; i < limit; i++) {
+            if (!composite[i]) {
+                count++;
+                for (int j = i * i; j < limit; j += i) {
+                    composite[j] = true;
+                }
+            }
+        }
+        return count;
+    }
+
+    public static void main(String[] args) {
+        int ops = Integer.parseInt(args[0]);
+        long check = 0;
+        for (int i = 0; i < ops; i++) {
+            check += sieve(10000);
+        }
+        System.out.println(check);
+    }
+}
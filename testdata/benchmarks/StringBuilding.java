@@ -0,0 +1,23 @@
+/*
+ * Jacobin VM benchmark: string building and object allocation.
+ * One operation appends 100 numbers and separators to a StringBuilder and makes a String of it.
+ * The argument is the number of operations.
+ */
+public class StringBuilding {
+    static int build() {
+        StringBuilder sb = new StringBuilder();
+        for (int i = 0; i < 100; i++) {
+            sb.append(i).append(',');
+        }
+        return sb.toString().length();
+    }
+
+    public static void main(String[] args) {
+        int ops = Integer.parseInt(args[0]);
+        long check = 0;
+        for (int i = 0; i < ops; i++) {
+            check += build();
+        }
+        System.out.println(check);
+    }
+}